@@ -0,0 +1,259 @@
+// Command admin handles support tasks on user accounts against DATABASE_URL, so they
+// don't need SQL access
+//
+// Usage:
+//
+//	admin <command> [flags] [user]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juan-cantero/fitapi/config"
+	"github.com/juan-cantero/fitapi/internal/database"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+const usage = `Usage: admin <command> [flags] [user]
+
+Users are given by ID or email. The database is DATABASE_URL (.env is loaded).
+
+Commands:
+  users [-email S] [-limit N] [-json]   List the newest users, optionally matching an email
+  show <user>                           Show a user
+  promote <user>                        Grant admin access, from the user's next token
+  demote <user>                         Revoke admin access, from the user's next token
+  suspend [-for DURATION] <user>        Block sign-in and end every session; indefinite by
+                                        default. Issued access tokens last until they expire.
+  unsuspend <user>                      Lift a suspension
+  export <user>                         Queue an account export; the API's job workers build it
+  recalc <user>                         Rebuild the user's trend snapshots and refresh the
+                                        analytics views; cached reads may lag 10 minutes
+`
+
+// app holds the services the commands use
+type app struct {
+	users     *services.UserService
+	exports   *services.ExportService
+	trends    *services.TrendService
+	analytics *services.AnalyticsService
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	commands := map[string]func(ctx context.Context, a *app, args []string) error{
+		"users":     listUsers,
+		"show":      showUser,
+		"promote":   func(ctx context.Context, a *app, args []string) error { return setAdmin(ctx, a, "promote", args, true) },
+		"demote":    func(ctx context.Context, a *app, args []string) error { return setAdmin(ctx, a, "demote", args, false) },
+		"suspend":   suspendUser,
+		"unsuspend": unsuspendUser,
+		"export":    exportUser,
+		"recalc":    recalcUser,
+	}
+
+	name := os.Args[1]
+	if name == "-h" || name == "--help" || name == "help" {
+		fmt.Print(usage)
+		return
+	}
+	command, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", name, usage)
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	cfg := config.Load()
+	if cfg.DatabaseURL == "" {
+		fmt.Fprintln(os.Stderr, "DATABASE_URL not set")
+		os.Exit(1)
+	}
+	db, err := database.New(cfg.DatabaseURL, database.PoolConfig{
+		MaxConns:      2,
+		QueryExecMode: cfg.DBQueryExecMode,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to connect to database: %v\n", err)
+		os.Exit(1)
+	}
+	defer db.Close()
+
+	profiles := repositories.NewPostgresProfileRepository(db.Pool)
+	hydration := services.NewHydrationService(repositories.NewPostgresHydrationRepository(db.Pool), profiles)
+
+	// Without the API's read cache, trends cached there expire on their own
+	a := &app{
+		users:     services.NewUserService(repositories.NewPostgresUserRepository(db.Pool)),
+		exports:   services.NewExportService(repositories.NewPostgresExportRepository(db.Pool), jobs.NewQueue(repositories.NewPostgresJobRepository(db.Pool)), services.NewAuditLogService(repositories.NewPostgresAuditLogRepository(db.Pool))),
+		trends:    services.NewTrendService(repositories.NewPostgresTrendRepository(db.Pool), profiles, nil),
+		analytics: services.NewAnalyticsService(repositories.NewPostgresAnalyticsRepository(db.Pool), profiles, hydration),
+	}
+
+	if err := command(ctx, a, os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "%s: %v\n", name, err)
+		os.Exit(1)
+	}
+}
+
+// userArg parses a command's flags and finds the single user argument
+func userArg(ctx context.Context, a *app, flags *flag.FlagSet, args []string) (*models.User, error) {
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return nil, errors.New("expected one user, by ID or email")
+	}
+	return a.users.Find(ctx, flags.Arg(0))
+}
+
+func listUsers(ctx context.Context, a *app, args []string) error {
+	flags := flag.NewFlagSet("users", flag.ExitOnError)
+	email := flags.String("email", "", "Only users whose email contains this, ignoring case")
+	limit := flags.Int("limit", 50, "Maximum users to list (at most 1000)")
+	asJSON := flags.Bool("json", false, "Print JSON instead of a table")
+	flags.Parse(args)
+
+	users, err := a.users.List(ctx, models.UserFilter{Email: *email, Limit: *limit})
+	if err != nil {
+		return err
+	}
+	if *asJSON {
+		return printJSON(users)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tEMAIL\tCREATED\tLAST SIGN-IN\tFLAGS")
+	for _, u := range users {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", u.ID, u.Email, u.CreatedAt.UTC().Format(time.DateOnly), formatTime(u.LastSignInAt), userFlags(u))
+	}
+	return w.Flush()
+}
+
+func showUser(ctx context.Context, a *app, args []string) error {
+	user, err := userArg(ctx, a, flag.NewFlagSet("show", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+	return printJSON(user)
+}
+
+func setAdmin(ctx context.Context, a *app, name string, args []string, admin bool) error {
+	user, err := userArg(ctx, a, flag.NewFlagSet(name, flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := a.users.SetAdmin(ctx, user.ID, admin); err != nil {
+		return err
+	}
+
+	if admin {
+		fmt.Printf("%s is now an admin, once they sign in again or their token is refreshed\n", user.Email)
+	} else {
+		fmt.Printf("%s is no longer an admin, once their current token expires\n", user.Email)
+	}
+	return nil
+}
+
+func suspendUser(ctx context.Context, a *app, args []string) error {
+	flags := flag.NewFlagSet("suspend", flag.ExitOnError)
+	duration := flags.Duration("for", 0, "How long the suspension lasts, e.g. 72h; 0 is indefinitely")
+	user, err := userArg(ctx, a, flags, args)
+	if err != nil {
+		return err
+	}
+
+	until, err := a.users.Suspend(ctx, user.ID, *duration)
+	if err != nil {
+		return err
+	}
+	if *duration == 0 {
+		fmt.Printf("%s is suspended indefinitely and signed out everywhere\n", user.Email)
+	} else {
+		fmt.Printf("%s is suspended until %s and signed out everywhere\n", user.Email, until.Format(time.RFC3339))
+	}
+	return nil
+}
+
+func unsuspendUser(ctx context.Context, a *app, args []string) error {
+	user, err := userArg(ctx, a, flag.NewFlagSet("unsuspend", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := a.users.Unsuspend(ctx, user.ID); err != nil {
+		return err
+	}
+	fmt.Printf("%s can sign in again\n", user.Email)
+	return nil
+}
+
+func exportUser(ctx context.Context, a *app, args []string) error {
+	user, err := userArg(ctx, a, flag.NewFlagSet("export", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+	export, err := a.exports.RequestAccountExport(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Export %s of %s is %s; the user can download it from /api/export/%s/download once ready\n", export.ID, user.Email, export.Status, export.ID)
+	return nil
+}
+
+func recalcUser(ctx context.Context, a *app, args []string) error {
+	user, err := userArg(ctx, a, flag.NewFlagSet("recalc", flag.ExitOnError), args)
+	if err != nil {
+		return err
+	}
+	if err := a.trends.RefreshUser(ctx, user.ID); err != nil {
+		return err
+	}
+	fmt.Printf("Rebuilt the trend snapshots of %s\n", user.Email)
+
+	// The views hold every user's data and are refreshed as a whole
+	if err := a.analytics.RefreshViews(ctx); err != nil {
+		return err
+	}
+	fmt.Println("Refreshed the analytics views")
+	return nil
+}
+
+func userFlags(u *models.User) string {
+	var flags []string
+	if u.IsAdmin {
+		flags = append(flags, "admin")
+	}
+	if u.SuspendedUntil != nil {
+		flags = append(flags, "suspended")
+	}
+	return strings.Join(flags, ",")
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return "-"
+	}
+	return t.UTC().Format(time.DateOnly)
+}
+
+func printJSON(v any) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
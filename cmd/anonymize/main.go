@@ -0,0 +1,100 @@
+// Command anonymize scrambles personal data in a copy of the production database, for
+// staging refreshes
+//
+// Usage:
+//
+//	anonymize -url <database url> [-password <password>] [-dry-run] [-yes]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"text/tabwriter"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/anonymize"
+)
+
+func main() {
+	log.SetFlags(0)
+	url := flag.String("url", "", "Database to anonymize; required, DATABASE_URL is never used so .env can't point this at production")
+	password := flag.String("password", "", "Password set for every user, so testers can sign in as anyone; empty disables password sign-in")
+	dryRun := flag.Bool("dry-run", false, "Run every step and report the rows, then roll back")
+	yes := flag.Bool("yes", false, "Don't ask for confirmation")
+	list := flag.Bool("list", false, "List the steps and exit")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: anonymize -url <database url> [flags]\n\nScrambles emails, names and notes and deletes credentials and queued work, in one\ntransaction. Run it on a copy of production, never on production itself.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *list {
+		printSteps()
+		return
+	}
+	if *url == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	conn, err := pgx.Connect(ctx, *url)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer conn.Close(context.Background())
+
+	target := fmt.Sprintf("%s on %s", conn.Config().Database, conn.Config().Host)
+	if !*dryRun && !*yes && !confirm(target) {
+		log.Fatal("Aborted")
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		log.Fatalf("Failed to begin: %v", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	results, err := anonymize.Run(ctx, tx, anonymize.Options{Password: *password})
+	printResults(results)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if *dryRun {
+		fmt.Printf("Dry run on %s, rolled back\n", target)
+		return
+	}
+	if err := tx.Commit(ctx); err != nil {
+		log.Fatalf("Failed to commit: %v", err)
+	}
+	fmt.Printf("Anonymized %s\n", target)
+}
+
+// confirm asks before changing the database
+func confirm(target string) bool {
+	fmt.Printf("This scrambles %s for good. Type yes to continue: ", target)
+	var answer string
+	fmt.Scanln(&answer)
+	return answer == "yes"
+}
+
+func printSteps() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, step := range anonymize.Steps() {
+		fmt.Fprintf(w, "%s\t%s\n", step.Table, step.Description)
+	}
+	w.Flush()
+}
+
+func printResults(results []*anonymize.StepResult) {
+	for _, r := range results {
+		fmt.Printf("%10d  %s\n", r.Rows, r.Step.Table)
+	}
+}
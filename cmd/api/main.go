@@ -1,14 +1,39 @@
 package main
 
+//go:generate go run ../openapi -root ../.. -o ../../api/openapi.yaml
+
 import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/juan-cantero/fitapi/config"
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/cron"
 	"github.com/juan-cantero/fitapi/internal/database"
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/featureflags"
+	"github.com/juan-cantero/fitapi/internal/googlefit"
 	"github.com/juan-cantero/fitapi/internal/handlers"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/mailer"
 	"github.com/juan-cantero/fitapi/internal/middleware"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/push"
+	"github.com/juan-cantero/fitapi/internal/recommend"
 	"github.com/juan-cantero/fitapi/internal/repositories"
 	"github.com/juan-cantero/fitapi/internal/services"
+	"github.com/juan-cantero/fitapi/internal/slo"
+	"github.com/juan-cantero/fitapi/internal/strava"
+	"github.com/juan-cantero/fitapi/internal/webhooks"
+	"github.com/juan-cantero/fitapi/internal/webhookverify"
 
 	"github.com/gin-gonic/gin"
 	supa "github.com/supabase-community/supabase-go"
@@ -21,8 +46,23 @@ func main() {
 	// Set Gin mode
 	gin.SetMode(cfg.GinMode)
 
-	// Initialize database connection
-	db, err := database.New(cfg.DatabaseURL)
+	// Cancelled on SIGTERM/SIGINT; stops background workers and triggers shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	// Initialize database connection; the tracer logs slow queries and times every statement
+	queryTracer := database.NewQueryTracer(cfg.DBSlowQueryThreshold)
+	db, err := database.New(cfg.DatabaseURL, database.PoolConfig{
+		MaxConns:               int32(cfg.DBMaxConns),
+		MinConns:               int32(cfg.DBMinConns),
+		MaxConnLifetime:        cfg.DBMaxConnLifetime,
+		MaxConnIdleTime:        cfg.DBMaxConnIdleTime,
+		HealthCheckPeriod:      cfg.DBHealthCheckPeriod,
+		QueryExecMode:          cfg.DBQueryExecMode,
+		StatementCacheCapacity: cfg.DBStatementCacheCapacity,
+		StatementTimeout:       cfg.DBStatementTimeout,
+		Tracer:                 queryTracer,
+	})
 	if err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
@@ -36,52 +76,462 @@ func main() {
 
 	// Initialize repositories
 	equipmentRepo := repositories.NewPostgresEquipmentRepository(db.Pool)
+	exerciseRepo := repositories.NewPostgresExerciseRepository(db.Pool)
+	coachClientRepo := repositories.NewPostgresCoachClientRepository(db.Pool)
+	organizationRepo := repositories.NewPostgresOrganizationRepository(db.Pool)
+	revokedTokenRepo := repositories.NewPostgresRevokedTokenRepository(db.Pool)
+	storageRepo := repositories.NewPostgresStorageRepository(db.Pool)
+	challengeRepo := repositories.NewPostgresChallengeRepository(db.Pool)
+	trainingMaxRepo := repositories.NewPostgresTrainingMaxRepository(db.Pool)
+	exportRepo := repositories.NewPostgresExportRepository(db.Pool)
+	measurementRepo := repositories.NewPostgresMeasurementRepository(db.Pool)
+	importRepo := repositories.NewPostgresImportRepository(db.Pool)
+	sessionMediaRepo := repositories.NewPostgresSessionMediaRepository(db.Pool)
+	restTimerRepo := repositories.NewPostgresRestTimerRepository(db.Pool)
+	exerciseSwapRepo := repositories.NewPostgresExerciseSwapRepository(db.Pool)
+	integrationRepo := repositories.NewPostgresIntegrationRepository(db.Pool)
+	sessionLapRepo := repositories.NewPostgresSessionLapRepository(db.Pool)
+	trendRepo := repositories.NewPostgresTrendRepository(db.Pool)
+	analyticsRepo := repositories.NewPostgresAnalyticsRepository(db.Pool)
+	sessionTypeRepo := repositories.NewPostgresSessionTypeRepository(db.Pool)
+	webhookRepo := repositories.NewPostgresWebhookRepository(db.Pool)
+	sessionLiveRepo := repositories.NewPostgresSessionLiveRepository(db.Pool)
+	userEventRepo := repositories.NewPostgresUserEventRepository(db.Pool)
+	pushRepo := repositories.NewPostgresPushRepository(db.Pool)
+	emailRepo := repositories.NewPostgresEmailRepository(db.Pool)
+	notificationRepo := repositories.NewPostgresNotificationRepository(db.Pool)
+	reminderRepo := repositories.NewPostgresReminderRepository(db.Pool)
+	profileRepo := repositories.NewPostgresProfileRepository(db.Pool)
+	followRepo := repositories.NewPostgresFollowRepository(db.Pool)
+	feedRepo := repositories.NewPostgresFeedRepository(db.Pool)
+	commentRepo := repositories.NewPostgresCommentRepository(db.Pool)
+	publicProfileRepo := repositories.NewPostgresPublicProfileRepository(db.Pool)
+	exerciseLeaderboardRepo := repositories.NewPostgresExerciseLeaderboardRepository(db.Pool)
+	shareCardRepo := repositories.NewPostgresShareCardRepository(db.Pool)
+	classSessionRepo := repositories.NewPostgresClassSessionRepository(db.Pool)
+	coachProgramRepo := repositories.NewPostgresCoachProgramRepository(db.Pool)
+	moderationRepo := repositories.NewPostgresModerationRepository(db.Pool)
+	auditLogRepo := repositories.NewPostgresAuditLogRepository(db.Pool)
+	featureFlagRepo := repositories.NewPostgresFeatureFlagRepository(db.Pool)
+	hydrationRepo := repositories.NewPostgresHydrationRepository(db.Pool)
+	sleepRepo := repositories.NewPostgresSleepRepository(db.Pool)
+	goalRepo := repositories.NewPostgresGoalRepository(db.Pool)
+	injuryRepo := repositories.NewPostgresInjuryRepository(db.Pool)
+	progressionRepo := repositories.NewPostgresProgressionRepository(db.Pool)
+	recommendationRepo := repositories.NewPostgresRecommendationRepository(db.Pool)
+	jobRepo := repositories.NewPostgresJobRepository(db.Pool)
+	reportRepo := repositories.NewPostgresReportRepository(db.Pool)
+	cacheRepo := repositories.NewPostgresCacheRepository(db.Pool)
+	workoutRepo := repositories.NewPostgresWorkoutRepository(db.Pool)
+	sessionRepo := repositories.NewPostgresSessionRepository(db.Pool)
+	idempotencyRepo := repositories.NewPostgresIdempotencyRepository(db.Pool)
 
-	// Initialize services
-	equipmentService := services.NewEquipmentService(equipmentRepo)
+	// Services that write through several repositories at once run them in a unit of work
+	unitOfWork := repositories.NewPostgresUnitOfWork(db.Pool)
 
-	// Initialize handlers
-	equipmentHandler := handlers.NewEquipmentHandler(equipmentService)
+	// Initialize the job queue; services register their job kinds with it
+	jobQueue := jobs.NewQueue(jobRepo)
 
-	// Initialize Gin router
-	router := gin.Default()
+	// Initialize the read cache; reads go to the database every time without one
+	readCache, err := newCache(cfg)
+	if err != nil {
+		log.Fatalf("Invalid cache configuration: %v", err)
+	}
 
-	// Public routes (no authentication required)
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status":   "ok",
-			"database": "connected",
-			"supabase": supabaseClient != nil,
-		})
+	// Initialize services
+	cacheService := services.NewCacheService(cacheRepo, readCache)
+	queryStatsService := services.NewQueryStatsService(queryTracer)
+	accessPolicy := services.NewAccessPolicy(coachClientRepo, organizationRepo)
+	auditLogService := services.NewAuditLogService(auditLogRepo)
+	flagDefaults, err := featureflags.ParseDefaults(cfg.FeatureFlags)
+	if err != nil {
+		log.Fatalf("Invalid FEATURE_FLAGS: %v", err)
+	}
+	flags := featureflags.New(featureFlagRepo, flagDefaults)
+	featureFlagService := services.NewFeatureFlagService(featureFlagRepo, flags, auditLogService)
+	userEventService := services.NewUserEventService(userEventRepo, events.NewBus())
+	equipmentService := services.NewEquipmentService(equipmentRepo, accessPolicy, auditLogService)
+	exerciseService := services.NewExerciseService(exerciseRepo, injuryRepo, accessPolicy, readCache)
+	emailMailer, err := mailer.New(mailerConfig(cfg))
+	if err != nil {
+		log.Fatalf("Invalid email configuration: %v", err)
+	}
+	notificationService := services.NewNotificationService(notificationRepo)
+	emailService := services.NewEmailService(emailRepo, jobQueue, emailMailer, notificationService)
+	coachService := services.NewCoachService(coachClientRepo, emailService, auditLogService)
+	organizationService := services.NewOrganizationService(organizationRepo, auditLogService)
+	tokenRevocationService := services.NewTokenRevocationService(revokedTokenRepo)
+	idempotencyService := services.NewIdempotencyService(idempotencyRepo)
+	adminService := services.NewAdminService(storageRepo)
+	challengeService := services.NewChallengeService(challengeRepo, profileRepo, unitOfWork)
+	trainingMaxService := services.NewTrainingMaxService(trainingMaxRepo)
+	exportService := services.NewExportService(exportRepo, jobQueue, auditLogService)
+	measurementService := services.NewMeasurementService(measurementRepo)
+	importService := services.NewImportService(importRepo, userEventService, jobQueue)
+	sessionMediaService := services.NewSessionMediaService(sessionMediaRepo, sessionRepo, auditLogService)
+	restTimerService := services.NewRestTimerService(restTimerRepo)
+	exerciseSwapService := services.NewExerciseSwapService(exerciseSwapRepo, sessionRepo, exerciseRepo, profileRepo, accessPolicy)
+	sessionLapService := services.NewSessionLapService(sessionLapRepo, sessionRepo, accessPolicy)
+	trendService := services.NewTrendService(trendRepo, profileRepo, readCache)
+	hydrationService := services.NewHydrationService(hydrationRepo, profileRepo)
+	sleepService := services.NewSleepService(sleepRepo, profileRepo)
+	goalService := services.NewGoalService(goalRepo, exerciseRepo, auditLogService)
+	injuryService := services.NewInjuryService(injuryRepo, profileRepo, auditLogService)
+	recommendationService := services.NewRecommendationService(recommendationRepo, goalRepo, injuryRepo, recommend.NewHeuristic(), flags)
+	analyticsService := services.NewAnalyticsService(analyticsRepo, profileRepo, hydrationService)
+	profileService := services.NewProfileService(profileRepo, readCache)
+	followService := services.NewFollowService(followRepo, profileRepo)
+	publicProfileService := services.NewPublicProfileService(publicProfileRepo, followRepo)
+	exerciseLeaderboardService := services.NewExerciseLeaderboardService(exerciseLeaderboardRepo, exerciseService)
+	shareCardService := services.NewShareCardService(shareCardRepo, jobQueue, shareCardKey(cfg))
+	classSessionService := services.NewClassSessionService(classSessionRepo, workoutRepo, trainingMaxService, accessPolicy)
+	coachProgramService := services.NewCoachProgramService(coachProgramRepo, workoutRepo, profileRepo, accessPolicy)
+	moderationService := services.NewModerationService(moderationRepo, exerciseRepo, workoutRepo, accessPolicy, readCache, auditLogService)
+	feedService := services.NewFeedService(feedRepo, followRepo)
+	commentService := services.NewCommentService(commentRepo, accessPolicy, followRepo)
+	sessionTypeService := services.NewSessionTypeService(sessionTypeRepo, sessionRepo, accessPolicy, readCache, auditLogService)
+	sessionLiveService := services.NewSessionLiveService(sessionLiveRepo, accessPolicy)
+	workoutService := services.NewWorkoutService(workoutRepo, injuryRepo, accessPolicy, auditLogService)
+	progressionService := services.NewProgressionService(workoutService, progressionRepo, trainingMaxService)
+	sessionService := services.NewSessionService(sessionRepo, accessPolicy)
+	webhookService := services.NewWebhookService(webhookRepo, webhooks.NewSender(webhooks.SafeClient(15*time.Second)), jobQueue, auditLogService)
+	pushSenders, err := newPushSenders(cfg)
+	if err != nil {
+		log.Fatalf("Invalid push notification configuration: %v", err)
+	}
+	pushService := services.NewPushService(pushRepo, pushSenders, notificationService, auditLogService)
+	jobService := services.NewJobService(jobRepo)
+	reminderService := services.NewReminderService(reminderRepo, pushService, emailService)
+	reportService := services.NewReportService(reportRepo, emailService)
+	sloTargets, err := slo.ParseTargets(cfg.SLOTargets)
+	if err != nil {
+		log.Fatalf("Invalid SLO_TARGETS: %v", err)
+	}
+	sloTracker := slo.NewTracker(sloTargets)
+	// The alert webhook is operator configuration, so it may point at internal hosts
+	sloService := services.NewSLOService(sloTracker, webhooks.NewSender(&http.Client{Timeout: 15 * time.Second}), services.SLOAlerting{
+		WebhookURL:    cfg.SLOAlertWebhookURL,
+		WebhookSecret: cfg.SLOAlertWebhookSecret,
 	})
+	googleFitClient := googlefit.NewClient(googlefit.Config{
+		ClientID:     cfg.GoogleFitClientID,
+		ClientSecret: cfg.GoogleFitClientSecret,
+		RedirectURL:  cfg.GoogleFitRedirectURL,
+	}, nil)
+	googleFitService := services.NewGoogleFitService(integrationRepo, measurementRepo, googleFitClient, []byte(cfg.GoogleFitClientSecret))
+	stravaClient := strava.NewClient(strava.Config{
+		ClientID:     cfg.StravaClientID,
+		ClientSecret: cfg.StravaClientSecret,
+		RedirectURL:  cfg.StravaRedirectURL,
+	}, nil)
+	stravaService := services.NewStravaService(integrationRepo, stravaClient, []byte(cfg.StravaClientSecret), services.StravaWebhook{
+		VerifyToken:    cfg.StravaWebhookVerifyToken,
+		SubscriptionID: int64(cfg.StravaWebhookSubscriptionID),
+	}, userEventService, jobQueue)
+	// Strava does not sign its events, so the verifier only checks they name our
+	// subscription, are recent and were not seen before; an hour leaves room for Strava's
+	// retries. The nonces are kept per instance.
+	stravaWebhookVerifier := webhookverify.NewVerifier(webhookverify.StravaScheme{
+		SubscriptionID: int64(cfg.StravaWebhookSubscriptionID),
+	}, webhookverify.NewMemoryNonceStore(), time.Hour)
+
+	// Load revoked tokens; the scheduler keeps the cache in sync with other instances
+	if err := tokenRevocationService.Refresh(ctx); err != nil {
+		log.Fatalf("Failed to load revoked tokens: %v", err)
+	}
+
+	// Pull connected Google Fit accounts in the background
+	if googleFitClient.Configured() {
+		googleFitService.Start(ctx, cfg.GoogleFitSyncInterval)
+	}
+
+	// Pull connected Strava accounts in the background
+	if stravaClient.Configured() {
+		stravaService.Start(ctx, cfg.StravaSyncInterval)
+	}
+
+	// Keep monthly trend snapshots up to date with changed history
+	trendService.Start(ctx, time.Hour)
+
+	// Run queued emails, account exports, imports, webhook deliveries and Strava jobs
+	jobQueue.Start(ctx, cfg.JobWorkers, cfg.JobPollInterval)
+
+	// Turn recorded events into deliveries to users' webhook endpoints
+	webhookService.Start(ctx, cfg.WebhookDeliveryInterval)
+
+	// Alert when route groups burn their response time error budget too fast
+	sloService.Start(ctx, time.Minute)
+
+	// Forward session activity to clients watching sessions live
+	sessionLiveService.Start(ctx)
+
+	// Push events to users' connected clients
+	userEventService.Start(ctx)
+
+	// Drop cached reads as the data behind them changes
+	cacheService.Start(ctx)
+
+	// Send queued push notifications to users' devices
+	if len(pushSenders) > 0 {
+		pushService.Start(ctx, cfg.PushDeliveryInterval)
+	}
+
+	// Fire users' workout reminder rules
+	reminderService.Start(ctx, time.Minute)
+
+	// Forget notifications read long ago
+	notificationService.Start(ctx, time.Hour)
+
+	// Forget idempotency keys once retries can no longer replay them
+	idempotencyService.Start(ctx, time.Hour)
+
+	// Schedule the reminders users opted into
+	if emailMailer != nil {
+		emailService.Start(ctx, time.Minute)
+	}
+
+	// Run recurring tasks
+	scheduler := cron.New(time.UTC)
+	if cfg.CronTokenRefresh {
+		if err := scheduler.Add("token-cache-refresh", "@every 1m", tokenRevocationService.Refresh); err != nil {
+			log.Fatalf("Failed to schedule token cache refresh: %v", err)
+		}
+	}
+	if cfg.CronWeeklyReports {
+		if err := scheduler.Add("weekly-reports", "0 * * * 1", reportService.GenerateWeeklyReports); err != nil {
+			log.Fatalf("Failed to schedule weekly reports: %v", err)
+		}
+	}
+	if cfg.CronAnalyticsRefresh {
+		if err := scheduler.Add("analytics-refresh", "*/15 * * * *", analyticsService.RefreshViews); err != nil {
+			log.Fatalf("Failed to schedule analytics refresh: %v", err)
+		}
+	}
+	scheduler.Start(ctx)
+
+	rateLimits, err := newRateLimits(cfg)
+	if err != nil {
+		log.Fatalf("Invalid rate limit configuration: %v", err)
+	}
+
+	// Initialize handlers and the router
+	router, err := newRouter(&routeDeps{
+		db:                     db,
+		supabaseClient:         supabaseClient,
+		sloTracker:             sloTracker,
+		requestTimeout:         cfg.RequestTimeout,
+		trustedProxies:         cfg.TrustedProxies,
+		tokenRevocationService: tokenRevocationService,
+		organizationService:    organizationService,
+		idempotencyService:     idempotencyService,
+		rateLimits:             rateLimits,
+		stravaWebhookVerifier:  stravaWebhookVerifier,
 
-	// Protected routes (authentication required)
-	api := router.Group("/api")
-	api.Use(middleware.AuthRequired())
-	{
-		// Test endpoint to verify auth is working
-		api.GET("/me", func(c *gin.Context) {
-			userID, _ := c.Get("user_id")
-			userEmail, _ := c.Get("user_email")
-
-			c.JSON(200, gin.H{
-				"user_id": userID,
-				"email":   userEmail,
-				"message": "Authentication successful!",
-			})
-		})
-
-		// Equipment endpoints
-		api.POST("/equipment", equipmentHandler.Create)
-		api.GET("/equipment", equipmentHandler.List)
-		api.GET("/equipment/:id", equipmentHandler.GetByID)
-		api.PUT("/equipment/:id", equipmentHandler.Update)
-		api.DELETE("/equipment/:id", equipmentHandler.Delete)
+		equipmentHandler:           handlers.NewEquipmentHandler(equipmentService),
+		exerciseHandler:            handlers.NewExerciseHandler(exerciseService),
+		coachHandler:               handlers.NewCoachHandler(coachService),
+		organizationHandler:        handlers.NewOrganizationHandler(organizationService),
+		authHandler:                handlers.NewAuthHandler(tokenRevocationService),
+		adminHandler:               handlers.NewAdminHandler(adminService),
+		challengeHandler:           handlers.NewChallengeHandler(challengeService),
+		trainingMaxHandler:         handlers.NewTrainingMaxHandler(trainingMaxService),
+		exportHandler:              handlers.NewExportHandler(exportService),
+		measurementHandler:         handlers.NewMeasurementHandler(measurementService),
+		hydrationHandler:           handlers.NewHydrationHandler(hydrationService),
+		sleepHandler:               handlers.NewSleepHandler(sleepService),
+		goalHandler:                handlers.NewGoalHandler(goalService),
+		injuryHandler:              handlers.NewInjuryHandler(injuryService),
+		recommendationHandler:      handlers.NewRecommendationHandler(recommendationService),
+		importHandler:              handlers.NewImportHandler(importService),
+		sessionMediaHandler:        handlers.NewSessionMediaHandler(sessionMediaService),
+		restTimerHandler:           handlers.NewRestTimerHandler(restTimerService),
+		exerciseSwapHandler:        handlers.NewExerciseSwapHandler(exerciseSwapService),
+		googleFitHandler:           handlers.NewGoogleFitHandler(googleFitService),
+		stravaHandler:              handlers.NewStravaHandler(stravaService),
+		sessionLapHandler:          handlers.NewSessionLapHandler(sessionLapService),
+		trendHandler:               handlers.NewTrendHandler(trendService),
+		analyticsHandler:           handlers.NewAnalyticsHandler(analyticsService),
+		sessionTypeHandler:         handlers.NewSessionTypeHandler(sessionTypeService),
+		webhookHandler:             handlers.NewWebhookHandler(webhookService),
+		sloHandler:                 handlers.NewSLOHandler(sloService),
+		cacheHandler:               handlers.NewCacheHandler(cacheService),
+		queryStatsHandler:          handlers.NewQueryStatsHandler(queryStatsService),
+		workoutHandler:             handlers.NewWorkoutHandler(workoutService),
+		progressionHandler:         handlers.NewProgressionHandler(progressionService),
+		sessionHandler:             handlers.NewSessionHandler(sessionService),
+		sessionLiveHandler:         handlers.NewSessionLiveHandler(sessionLiveService),
+		userEventHandler:           handlers.NewUserEventHandler(userEventService),
+		pushHandler:                handlers.NewPushHandler(pushService),
+		emailHandler:               handlers.NewEmailHandler(emailService),
+		profileHandler:             handlers.NewProfileHandler(profileService),
+		followHandler:              handlers.NewFollowHandler(followService),
+		publicProfileHandler:       handlers.NewPublicProfileHandler(publicProfileService),
+		exerciseLeaderboardHandler: handlers.NewExerciseLeaderboardHandler(exerciseLeaderboardService),
+		shareCardHandler:           handlers.NewShareCardHandler(shareCardService),
+		classSessionHandler:        handlers.NewClassSessionHandler(classSessionService),
+		coachProgramHandler:        handlers.NewCoachProgramHandler(coachProgramService),
+		moderationHandler:          handlers.NewModerationHandler(moderationService),
+		auditLogHandler:            handlers.NewAuditLogHandler(auditLogService),
+		featureFlagHandler:         handlers.NewFeatureFlagHandler(featureFlagService),
+		feedHandler:                handlers.NewFeedHandler(feedService),
+		commentHandler:             handlers.NewCommentHandler(commentService),
+		notificationHandler:        handlers.NewNotificationHandler(notificationService),
+		reminderHandler:            handlers.NewReminderHandler(reminderService),
+		jobHandler:                 handlers.NewJobHandler(jobService),
+		reportHandler:              handlers.NewReportHandler(reportService),
+	})
+	if err != nil {
+		log.Fatalf("Invalid router configuration: %v", err)
 	}
 
 	// Start server
-	log.Printf("Server starting on port %s", cfg.Port)
-	if err := router.Run(":" + cfg.Port); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	srv := &http.Server{
+		Addr:    ":" + cfg.Port,
+		Handler: router,
+	}
+	// Event streams last until their client goes away; end them when shutdown starts so
+	// draining does not wait out the timeout while they still hold the database
+	srv.RegisterOnShutdown(userEventService.Close)
+	srv.RegisterOnShutdown(restTimerService.Close)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Printf("Server starting on port %s", cfg.Port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+		close(serverErr)
+	}()
+
+	// Wait for a shutdown signal or a listener failure
+	select {
+	case err := <-serverErr:
+		if err != nil {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	case <-ctx.Done():
+	}
+	stop()
+
+	// Drain in-flight requests; the deferred db.Close runs after this returns
+	log.Printf("Shutting down server (timeout %s)", cfg.ShutdownTimeout)
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Server shutdown did not complete cleanly: %v", err)
+	}
+
+	log.Println("Server stopped")
+}
+
+// newPushSenders creates the push senders of the platforms whose credentials are configured
+func newPushSenders(cfg *config.Config) (map[string]services.PushSender, error) {
+	senders := make(map[string]services.PushSender)
+
+	if cfg.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(cfg.FCMCredentialsFile)
+		if err != nil {
+			return nil, err
+		}
+		fcm, err := push.NewFCM(credentials, nil)
+		if err != nil {
+			return nil, err
+		}
+		senders[models.DevicePlatformAndroid] = fcm
+	}
+
+	if cfg.APNsKeyFile != "" {
+		key, err := os.ReadFile(cfg.APNsKeyFile)
+		if err != nil {
+			return nil, err
+		}
+		apns, err := push.NewAPNs(push.APNsConfig{
+			KeyID:      cfg.APNsKeyID,
+			TeamID:     cfg.APNsTeamID,
+			Topic:      cfg.APNsTopic,
+			PrivateKey: key,
+			Sandbox:    cfg.APNsSandbox,
+		}, nil)
+		if err != nil {
+			return nil, err
+		}
+		senders[models.DevicePlatformIOS] = apns
+	}
+
+	return senders, nil
+}
+
+// shareCardKey returns the configured key signing share card links, or a random one
+func shareCardKey(cfg *config.Config) []byte {
+	if cfg.ShareCardSecret != "" {
+		return []byte(cfg.ShareCardSecret)
+	}
+
+	log.Println("SHARE_CARD_SECRET is not set; share card links will stop working on restart")
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		log.Fatalf("Failed to generate share card key: %v", err)
+	}
+	return key
+}
+
+// mailerConfig picks the email settings out of the configuration
+func mailerConfig(cfg *config.Config) mailer.Config {
+	return mailer.Config{
+		Provider: cfg.MailProvider,
+		From:     cfg.MailFrom,
+		SMTP: mailer.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+		},
+		ResendAPIKey:   cfg.ResendAPIKey,
+		SendGridAPIKey: cfg.SendGridAPIKey,
+	}
+}
+
+// newCache creates the read cache of the configured backend, or nil when there is none
+func newCache(cfg *config.Config) (*cache.Cache, error) {
+	var store cache.Store
+	switch cfg.CacheBackend {
+	case "":
+		return nil, nil
+	case "memory":
+		store = cache.NewMemory(cfg.CacheMaxEntries)
+	case "redis":
+		redis, err := cache.NewRedis(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		store = redis
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q, expected memory or redis", cfg.CacheBackend)
+	}
+	return cache.New(store, "fitapi:"), nil
+}
+
+// newRateLimits creates the rate limiters of every route group in the configured backend
+func newRateLimits(cfg *config.Config) (*middleware.RateLimits, error) {
+	rules, err := middleware.ParseRateLimitRules(cfg.RateLimits)
+	if err != nil {
+		return nil, err
+	}
+
+	newLimiter := middleware.NewTokenBucketLimiter
+	switch cfg.RateLimitBackend {
+	case "memory":
+	case "redis":
+		redis, err := cache.NewRedis(cfg.RedisURL)
+		if err != nil {
+			return nil, err
+		}
+		newLimiter = func(perMinute int) middleware.RateLimiter {
+			return middleware.NewRedisTokenBucketLimiter(redis, "fitapi:ratelimit:", perMinute)
+		}
+	default:
+		return nil, fmt.Errorf("unknown rate limit backend %q, expected memory or redis", cfg.RateLimitBackend)
 	}
+	return middleware.NewRateLimits(cfg.RateLimitUser, cfg.RateLimitAnonymous, rules, newLimiter), nil
 }
@@ -0,0 +1,464 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/database"
+	"github.com/juan-cantero/fitapi/internal/handlers"
+	"github.com/juan-cantero/fitapi/internal/middleware"
+	"github.com/juan-cantero/fitapi/internal/services"
+	"github.com/juan-cantero/fitapi/internal/slo"
+	"github.com/juan-cantero/fitapi/internal/webhookverify"
+
+	"github.com/gin-gonic/gin"
+	supa "github.com/supabase-community/supabase-go"
+)
+
+// routeDeps are the handlers and middleware dependencies the router is built from
+type routeDeps struct {
+	db                     *database.DB
+	supabaseClient         *supa.Client
+	sloTracker             *slo.Tracker
+	requestTimeout         time.Duration
+	trustedProxies         []string
+	tokenRevocationService *services.TokenRevocationService
+	organizationService    *services.OrganizationService
+	idempotencyService     *services.IdempotencyService
+	rateLimits             *middleware.RateLimits
+	stravaWebhookVerifier  *webhookverify.Verifier
+
+	equipmentHandler           *handlers.EquipmentHandler
+	exerciseHandler            *handlers.ExerciseHandler
+	coachHandler               *handlers.CoachHandler
+	organizationHandler        *handlers.OrganizationHandler
+	authHandler                *handlers.AuthHandler
+	adminHandler               *handlers.AdminHandler
+	challengeHandler           *handlers.ChallengeHandler
+	trainingMaxHandler         *handlers.TrainingMaxHandler
+	exportHandler              *handlers.ExportHandler
+	measurementHandler         *handlers.MeasurementHandler
+	hydrationHandler           *handlers.HydrationHandler
+	sleepHandler               *handlers.SleepHandler
+	goalHandler                *handlers.GoalHandler
+	injuryHandler              *handlers.InjuryHandler
+	recommendationHandler      *handlers.RecommendationHandler
+	importHandler              *handlers.ImportHandler
+	sessionMediaHandler        *handlers.SessionMediaHandler
+	restTimerHandler           *handlers.RestTimerHandler
+	exerciseSwapHandler        *handlers.ExerciseSwapHandler
+	googleFitHandler           *handlers.GoogleFitHandler
+	stravaHandler              *handlers.StravaHandler
+	sessionLapHandler          *handlers.SessionLapHandler
+	trendHandler               *handlers.TrendHandler
+	analyticsHandler           *handlers.AnalyticsHandler
+	sessionTypeHandler         *handlers.SessionTypeHandler
+	webhookHandler             *handlers.WebhookHandler
+	sloHandler                 *handlers.SLOHandler
+	cacheHandler               *handlers.CacheHandler
+	queryStatsHandler          *handlers.QueryStatsHandler
+	workoutHandler             *handlers.WorkoutHandler
+	progressionHandler         *handlers.ProgressionHandler
+	sessionHandler             *handlers.SessionHandler
+	sessionLiveHandler         *handlers.SessionLiveHandler
+	userEventHandler           *handlers.UserEventHandler
+	pushHandler                *handlers.PushHandler
+	emailHandler               *handlers.EmailHandler
+	profileHandler             *handlers.ProfileHandler
+	followHandler              *handlers.FollowHandler
+	publicProfileHandler       *handlers.PublicProfileHandler
+	exerciseLeaderboardHandler *handlers.ExerciseLeaderboardHandler
+	shareCardHandler           *handlers.ShareCardHandler
+	classSessionHandler        *handlers.ClassSessionHandler
+	coachProgramHandler        *handlers.CoachProgramHandler
+	moderationHandler          *handlers.ModerationHandler
+	auditLogHandler            *handlers.AuditLogHandler
+	featureFlagHandler         *handlers.FeatureFlagHandler
+	feedHandler                *handlers.FeedHandler
+	commentHandler             *handlers.CommentHandler
+	notificationHandler        *handlers.NotificationHandler
+	reminderHandler            *handlers.ReminderHandler
+	jobHandler                 *handlers.JobHandler
+	reportHandler              *handlers.ReportHandler
+}
+
+// newRouter registers every route of the API
+// Gin panics on routes it cannot tell apart, e.g. two wildcard names in one path segment,
+// so this runs in tests as well as at startup.
+func newRouter(h *routeDeps) (*gin.Engine, error) {
+	router := gin.Default()
+	// Client IPs, which anonymous requests are rate limited by, come from X-Forwarded-For
+	// only when a trusted proxy sent it; with none the connection's address is used
+	if err := router.SetTrustedProxies(h.trustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+	router.Use(slo.Middleware(h.sloTracker))
+	// Downloads stream for as long as the client takes to read them
+	router.Use(middleware.Timeout(h.requestTimeout, "/api/export/logs.csv", "/api/export/:id/download"))
+
+	// Public routes (no authentication required)
+	// ?verbose=true adds the database pool's effective settings and connection counts
+	router.GET("/health", func(c *gin.Context) {
+		health := gin.H{
+			"status":   "ok",
+			"database": "connected",
+			"supabase": h.supabaseClient != nil,
+		}
+		if verbose, _ := strconv.ParseBool(c.Query("verbose")); verbose {
+			health["database_pool"] = h.db.Settings()
+		}
+		c.JSON(200, health)
+	})
+
+	// Provider webhooks (authenticated by the provider's own checks)
+	router.GET("/webhooks/strava", h.stravaHandler.VerifyWebhook)
+	router.POST("/webhooks/strava", webhookverify.Middleware(h.stravaWebhookVerifier), h.stravaHandler.Webhook)
+
+	// Optionally authenticated routes (anonymous visitors get public data only)
+	optional := router.Group("/api")
+	optional.Use(middleware.RateLimitAnonymous(h.rateLimits))
+	optional.Use(middleware.OptionalAuth(h.tokenRevocationService))
+	optional.Use(middleware.RateLimit(h.rateLimits))
+	optional.Use(middleware.UUIDParams("id"))
+	{
+		// Exercise library endpoints
+		exercises := optional.Group("/exercises", middleware.RequireScopes("exercises"))
+		exercises.GET("", h.exerciseHandler.List)
+		exercises.GET("/search", h.exerciseHandler.Search)
+		exercises.GET("/:id", h.exerciseHandler.GetByID)
+
+		// Share card images, authorized by the signature of their download link
+		optional.GET("/share-cards/:id/image", h.shareCardHandler.Image)
+	}
+
+	// WebSocket routes (browsers may pass the token as a subprotocol)
+	ws := router.Group("/api/ws")
+	ws.Use(middleware.WebSocketToken())
+	ws.Use(middleware.RateLimitAnonymous(h.rateLimits))
+	ws.Use(middleware.AuthRequired(h.tokenRevocationService))
+	ws.Use(middleware.RateLimit(h.rateLimits))
+	ws.Use(middleware.UUIDParams("id"))
+	{
+		ws.GET("/sessions/:id", middleware.RequireScopes("sessions"), h.sessionLiveHandler.Watch)
+	}
+
+	// Public profiles, looked up by the username in the :id segment the users routes share,
+	// so they are left out of the UUID check of the protected routes
+	profiles := router.Group("/api/users")
+	profiles.Use(middleware.RateLimitAnonymous(h.rateLimits))
+	profiles.Use(middleware.AuthRequired(h.tokenRevocationService))
+	profiles.Use(middleware.RateLimit(h.rateLimits))
+	{
+		profiles.GET("/:id", middleware.RequireScopes("social"), h.publicProfileHandler.Get)
+	}
+
+	// Protected routes (authentication required)
+	api := router.Group("/api")
+	api.Use(middleware.RateLimitAnonymous(h.rateLimits))
+	api.Use(middleware.AuthRequired(h.tokenRevocationService))
+	api.Use(middleware.RateLimit(h.rateLimits))
+	api.Use(middleware.OrgContext(h.organizationService))
+	api.Use(middleware.UUIDParams("id", "user_id", "media_id", "comment_id"))
+	api.Use(middleware.Idempotency(h.idempotencyService))
+	{
+		// Test endpoint to verify auth is working
+		api.GET("/me", func(c *gin.Context) {
+			userID, _ := c.Get("user_id")
+			userEmail, _ := c.Get("user_email")
+
+			c.JSON(200, gin.H{
+				"user_id": userID,
+				"email":   userEmail,
+				"message": "Authentication successful!",
+			})
+		})
+
+		// Session endpoints
+		api.POST("/auth/logout", h.authHandler.Logout)
+
+		// Activity events for the user's connected clients (Server-Sent Events)
+		api.GET("/events", middleware.RequireScopes("events"), h.userEventHandler.Stream)
+
+		// Exercise library maintenance (signed-in users only)
+		ownExercises := api.Group("/exercises", middleware.RequireScopes("exercises"))
+		ownExercises.GET("/duplicates", h.exerciseHandler.Duplicates)
+		ownExercises.POST("/merge", h.exerciseHandler.Merge)
+		ownExercises.GET("/:id/leaderboard", middleware.RequireScopes("social"), h.exerciseLeaderboardHandler.Get)
+		ownExercises.POST("/:id/report", h.moderationHandler.ReportExercise)
+
+		// Equipment endpoints
+		equipment := api.Group("/equipment", middleware.RequireScopes("equipment"))
+		equipment.POST("", h.equipmentHandler.Create)
+		equipment.GET("", h.equipmentHandler.List)
+		equipment.GET("/:id", h.equipmentHandler.GetByID)
+		equipment.PUT("/:id", h.equipmentHandler.Update)
+		equipment.DELETE("/:id", h.equipmentHandler.Delete)
+
+		// Coach-client endpoints
+		coach := api.Group("/coach", middleware.RequireScopes("coaching"))
+		coach.POST("/invitations", h.coachHandler.Invite)
+		coach.GET("/invitations", h.coachHandler.ListInvitations)
+		coach.POST("/invitations/:id/accept", h.coachHandler.Accept)
+		coach.POST("/invitations/:id/decline", h.coachHandler.Decline)
+		coach.GET("/clients", h.coachHandler.ListClients)
+		coach.GET("/coaches", h.coachHandler.ListCoaches)
+		coach.PUT("/relationships/:id/permissions", h.coachHandler.UpdatePermissions)
+		coach.DELETE("/relationships/:id", h.coachHandler.Revoke)
+		coach.POST("/clients/:user_id/programs", h.coachProgramHandler.Assign)
+		coach.GET("/clients/:user_id/programs", h.coachProgramHandler.ListForClient)
+		coach.GET("/clients/:user_id/compliance", h.coachProgramHandler.Compliance)
+		coach.GET("/programs", h.coachProgramHandler.ListMine)
+		coach.POST("/program-workouts/:id/skip", h.coachProgramHandler.Skip)
+
+		// Organization endpoints
+		orgs := api.Group("/orgs", middleware.RequireScopes("organizations"))
+		orgs.POST("", h.organizationHandler.Create)
+		orgs.GET("", h.organizationHandler.List)
+		orgs.GET("/:id", h.organizationHandler.GetByID)
+		orgs.GET("/:id/members", h.organizationHandler.ListMembers)
+		orgs.POST("/:id/members", h.organizationHandler.AddMember)
+		orgs.PUT("/:id/members/:user_id", h.organizationHandler.UpdateMember)
+		orgs.DELETE("/:id/members/:user_id", h.organizationHandler.RemoveMember)
+		orgs.POST("/:id/classes", h.classSessionHandler.Create)
+		orgs.GET("/:id/classes", h.classSessionHandler.List)
+		orgs.POST("/:id/workouts", h.workoutHandler.Share)
+		orgs.GET("/:id/workouts", h.workoutHandler.ListOrg)
+
+		// Organization classes, which members join with their own session of the class workout
+		classes := api.Group("/classes", middleware.RequireScopes("organizations"))
+		classes.GET("/:id", h.classSessionHandler.GetByID)
+		classes.POST("/:id/join", h.classSessionHandler.Join)
+		classes.GET("/:id/attendance", h.classSessionHandler.Attendance)
+
+		// Workout template endpoints
+		api.GET("/workouts/:id", middleware.RequireScopes("workouts"), h.workoutHandler.GetByID)
+		api.POST("/workouts/:id/report", middleware.RequireScopes("workouts"), h.moderationHandler.ReportWorkout)
+		api.GET("/workouts/:id/next-prescription", middleware.RequireScopes("workouts"), h.progressionHandler.NextPrescription)
+
+		// Training max endpoints (referenced by percentage-based templates)
+		trainingMaxes := api.Group("/training-maxes", middleware.RequireScopes("workouts"))
+		trainingMaxes.GET("", h.trainingMaxHandler.List)
+		trainingMaxes.PUT("/:name", h.trainingMaxHandler.Set)
+		trainingMaxes.DELETE("/:name", h.trainingMaxHandler.Delete)
+
+		// Challenge endpoints
+		challenges := api.Group("/challenges", middleware.RequireScopes("challenges"))
+		challenges.POST("", h.challengeHandler.Create)
+		challenges.GET("", h.challengeHandler.List)
+		challenges.GET("/:id", h.challengeHandler.GetByID)
+		challenges.POST("/:id/join", h.challengeHandler.Join)
+		challenges.DELETE("/:id/join", h.challengeHandler.Leave)
+		challenges.POST("/:id/completions", h.challengeHandler.CompleteDay)
+		challenges.GET("/:id/leaderboard", h.challengeHandler.Leaderboard)
+
+		// Body measurement endpoints
+		measurements := api.Group("/measurements", middleware.RequireScopes("measurements"))
+		measurements.GET("", h.measurementHandler.List)
+		measurements.POST("/import", h.measurementHandler.Import)
+
+		// Water intake against a daily goal
+		hydration := api.Group("/hydration", middleware.RequireScopes("hydration"))
+		hydration.GET("/logs", h.hydrationHandler.ListLogs)
+		hydration.POST("/logs", h.hydrationHandler.LogWater)
+		hydration.DELETE("/logs/:id", h.hydrationHandler.DeleteLog)
+		hydration.GET("/goal", h.hydrationHandler.GetGoal)
+		hydration.PUT("/goal", h.hydrationHandler.UpdateGoal)
+		hydration.GET("/progress", h.hydrationHandler.Progress)
+
+		// Sleep logs, entered or imported from sleep trackers
+		sleep := api.Group("/sleep", middleware.RequireScopes("sleep"))
+		sleep.GET("", h.sleepHandler.List)
+		sleep.POST("", h.sleepHandler.Create)
+		sleep.POST("/import", h.sleepHandler.Import)
+		sleep.DELETE("/:id", h.sleepHandler.Delete)
+
+		// Goals, with progress from measurements and logs
+		goals := api.Group("/goals", middleware.RequireScopes("goals"))
+		goals.GET("", h.goalHandler.List)
+		goals.POST("", h.goalHandler.Create)
+		goals.GET("/:id", h.goalHandler.Get)
+		goals.PUT("/:id", h.goalHandler.Update)
+		goals.DELETE("/:id", h.goalHandler.Delete)
+
+		// Injuries, which flag the exercises they contraindicate
+		injuries := api.Group("/injuries", middleware.RequireScopes("injuries"))
+		injuries.GET("", h.injuryHandler.List)
+		injuries.POST("", h.injuryHandler.Create)
+		injuries.PUT("/:id", h.injuryHandler.Update)
+		injuries.DELETE("/:id", h.injuryHandler.Delete)
+
+		// Workout recommendations from training history, equipment and goals
+		api.GET("/recommendations/workout", middleware.RequireScopes("workouts"), h.recommendationHandler.Workout)
+
+		// Which features are rolled out to the caller
+		api.GET("/feature-flags", h.featureFlagHandler.Mine)
+
+		// Session endpoints (media, highlights reel, shared rest timer, exercise swaps and skips,
+		// cardio activity files and laps, share cards)
+		sessions := api.Group("/sessions", middleware.RequireScopes("sessions"))
+		sessions.GET("", h.sessionHandler.List)
+		sessions.POST("/import-fit", h.importHandler.ImportActivityFile)
+		sessions.GET("/:id", h.sessionHandler.GetByID)
+		sessions.GET("/:id/laps", h.sessionLapHandler.List)
+		sessions.GET("/:id/media", h.sessionMediaHandler.List)
+		sessions.POST("/:id/media", h.sessionMediaHandler.Create)
+		sessions.PUT("/:id/media/order", h.sessionMediaHandler.Reorder)
+		sessions.PUT("/:id/media/:media_id", h.sessionMediaHandler.Update)
+		sessions.DELETE("/:id/media/:media_id", h.sessionMediaHandler.Delete)
+		sessions.GET("/:id/highlights", h.sessionMediaHandler.Highlights)
+		sessions.GET("/:id/rest-timer", h.restTimerHandler.Get)
+		sessions.POST("/:id/rest-timer", h.restTimerHandler.Update)
+		sessions.GET("/:id/rest-timer/events", h.restTimerHandler.Events)
+		sessions.POST("/:id/swap", h.exerciseSwapHandler.Swap)
+		sessions.GET("/:id/swaps", h.exerciseSwapHandler.List)
+		sessions.POST("/:id/skip", h.exerciseSwapHandler.Skip)
+		sessions.PUT("/:id/type", h.sessionTypeHandler.SetType)
+		sessions.POST("/:id/share-card", h.shareCardHandler.Create)
+		api.GET("/share-cards/:id", middleware.RequireScopes("sessions"), h.shareCardHandler.Get)
+
+		// Session type registry (sports and the payload their sessions carry)
+		api.GET("/session-types", middleware.RequireScopes("sessions"), h.sessionTypeHandler.List)
+
+		// Analytics endpoints
+		analytics := api.Group("/analytics", middleware.RequireScopes("sessions"))
+		analytics.GET("/skipped-volume", h.exerciseSwapHandler.SkippedVolume)
+		analytics.GET("/trends/:metric", h.trendHandler.Get)
+		analytics.GET("/muscle-volume", h.analyticsHandler.MuscleVolume)
+		analytics.GET("/e1rm-bests", h.analyticsHandler.E1RMBests)
+		analytics.GET("/daily-summary", h.analyticsHandler.DailySummary)
+		analytics.GET("/sleep-performance", h.analyticsHandler.SleepPerformance)
+		analytics.GET("/session-types", h.sessionTypeHandler.Summaries)
+
+		// Report endpoints
+		api.GET("/reports/weekly", middleware.RequireScopes("sessions"), h.reportHandler.Weekly)
+
+		// Import endpoints (workout history from other apps)
+		imports := api.Group("/import", middleware.RequireScopes("sessions"))
+		imports.POST("/:source", h.importHandler.Import)
+		imports.GET("/:id", h.importHandler.GetImport)
+
+		// Integration endpoints (third-party fitness platforms)
+		integrations := api.Group("/integrations", middleware.RequireScopes("integrations"))
+		integrations.GET("/googlefit", h.googleFitHandler.Get)
+		integrations.DELETE("/googlefit", h.googleFitHandler.Disconnect)
+		integrations.GET("/googlefit/connect", h.googleFitHandler.Authorize)
+		integrations.POST("/googlefit/connect", h.googleFitHandler.Connect)
+		integrations.POST("/googlefit/sync", h.googleFitHandler.Sync)
+		integrations.GET("/strava", h.stravaHandler.Get)
+		integrations.PATCH("/strava", h.stravaHandler.UpdateSettings)
+		integrations.DELETE("/strava", h.stravaHandler.Disconnect)
+		integrations.GET("/strava/connect", h.stravaHandler.Authorize)
+		integrations.POST("/strava/connect", h.stravaHandler.Connect)
+		integrations.POST("/strava/sync", h.stravaHandler.Sync)
+
+		// Devices receiving push notifications
+		devices := api.Group("/devices", middleware.RequireScopes("devices"))
+		devices.GET("", h.pushHandler.List)
+		devices.POST("", h.pushHandler.Register)
+		devices.DELETE("/:id", h.pushHandler.Unregister)
+
+		// In-app notification inbox
+		notifications := api.Group("/notifications", middleware.RequireScopes("notifications"))
+		notifications.GET("", h.notificationHandler.List)
+		notifications.GET("/unread-count", h.notificationHandler.UnreadCount)
+		notifications.POST("/read", h.notificationHandler.MarkAllRead)
+		notifications.POST("/:id/read", h.notificationHandler.MarkRead)
+
+		// Channels and quiet hours of every notification the user gets
+		notificationPreferences := api.Group("/notification-preferences", middleware.RequireScopes("notifications"))
+		notificationPreferences.GET("", h.notificationHandler.GetPreferences)
+		notificationPreferences.PUT("", h.notificationHandler.UpdatePreferences)
+
+		// Workout reminder rules
+		reminders := api.Group("/reminders", middleware.RequireScopes("reminders"))
+		reminders.GET("", h.reminderHandler.List)
+		reminders.POST("", h.reminderHandler.Create)
+		reminders.PUT("/:id", h.reminderHandler.Update)
+		reminders.DELETE("/:id", h.reminderHandler.Delete)
+		reminders.GET("/:id/preview", h.reminderHandler.Preview)
+
+		// Recurring emails the user opted into
+		emailPreferences := api.Group("/email-preferences", middleware.RequireScopes("email"))
+		emailPreferences.GET("", h.emailHandler.GetPreferences)
+		emailPreferences.PUT("", h.emailHandler.UpdatePreferences)
+
+		// Account-wide settings, e.g. the timezone days and weeks are counted in
+		profile := api.Group("/profile", middleware.RequireScopes("profile"))
+		profile.GET("", h.profileHandler.Get)
+		profile.PUT("", h.profileHandler.Update)
+		profile.GET("/public", h.publicProfileHandler.GetSettings)
+		profile.PUT("/public", h.publicProfileHandler.UpdateSettings)
+
+		// Following users, under each profile's privacy, and their activity feed and likes
+		users := api.Group("/users", middleware.RequireScopes("social"))
+		users.POST("/:id/follow", h.followHandler.Follow)
+		users.DELETE("/:id/follow", h.followHandler.Unfollow)
+		users.GET("/:id/followers", h.followHandler.ListFollowers)
+		users.GET("/:id/following", h.followHandler.ListFollowing)
+		followRequests := api.Group("/follow-requests", middleware.RequireScopes("social"))
+		followRequests.GET("", h.followHandler.ListRequests)
+		followRequests.POST("/:id/accept", h.followHandler.AcceptRequest)
+		api.DELETE("/followers/:id", middleware.RequireScopes("social"), h.followHandler.RemoveFollower)
+		api.GET("/feed", middleware.RequireScopes("social"), h.feedHandler.Get)
+		api.POST("/feed/:id/reaction", middleware.RequireScopes("social"), h.feedHandler.React)
+		api.DELETE("/feed/:id/reaction", middleware.RequireScopes("social"), h.feedHandler.Unreact)
+
+		// Comments on workouts and sessions
+		api.GET("/workouts/:id/comments", middleware.RequireScopes("social"), h.commentHandler.ListWorkoutComments)
+		api.POST("/workouts/:id/comments", middleware.RequireScopes("social"), h.commentHandler.CreateWorkoutComment)
+		api.DELETE("/workouts/:id/comments/:comment_id", middleware.RequireScopes("social"), h.commentHandler.DeleteWorkoutComment)
+		api.GET("/sessions/:id/comments", middleware.RequireScopes("social"), h.commentHandler.ListSessionComments)
+		api.POST("/sessions/:id/comments", middleware.RequireScopes("social"), h.commentHandler.CreateSessionComment)
+		api.DELETE("/sessions/:id/comments/:comment_id", middleware.RequireScopes("social"), h.commentHandler.DeleteSessionComment)
+
+		// Status of the user's background jobs, e.g. account exports
+		userJobs := api.Group("/jobs", middleware.RequireScopes("jobs"))
+		userJobs.GET("", h.jobHandler.List)
+		userJobs.GET("/:id", h.jobHandler.Get)
+
+		// Outgoing webhook endpoints
+		webhookEndpoints := api.Group("/webhooks", middleware.RequireScopes("webhooks"))
+		webhookEndpoints.GET("", h.webhookHandler.List)
+		webhookEndpoints.POST("", h.webhookHandler.Create)
+		webhookEndpoints.DELETE("/:id", h.webhookHandler.Delete)
+		webhookEndpoints.GET("/:id/deliveries", h.webhookHandler.Deliveries)
+
+		// Data export endpoints
+		export := api.Group("/export", middleware.RequireScopes("export"))
+		export.GET("/logs.csv", h.exportHandler.ExerciseLogsCSV)
+		export.POST("", h.exportHandler.RequestAccountExport)
+		export.GET("/:id", h.exportHandler.GetAccountExport)
+		export.GET("/:id/download", h.exportHandler.DownloadAccountExport)
+
+		// Admin endpoints (app_metadata.role = admin or service tokens)
+		admin := api.Group("/admin", middleware.AdminRequired())
+		admin.GET("/storage", h.adminHandler.Storage)
+		admin.POST("/session-types", h.sessionTypeHandler.Create)
+		admin.GET("/slo", h.sloHandler.Get)
+		admin.GET("/cache", h.cacheHandler.Stats)
+		admin.GET("/queries", h.queryStatsHandler.Get)
+		admin.GET("/jobs", h.jobHandler.AdminList)
+		admin.GET("/jobs/counts", h.jobHandler.AdminCounts)
+		admin.POST("/jobs/:id/retry", h.jobHandler.AdminRetry)
+		admin.GET("/audit-logs", h.auditLogHandler.List)
+
+		// Feature flags, applied on every instance within featureflags.RefreshInterval
+		admin.GET("/feature-flags", h.featureFlagHandler.List)
+		admin.PUT("/feature-flags/:name", h.featureFlagHandler.Update)
+		admin.PUT("/feature-flags/:name/users/:user_id", h.featureFlagHandler.SetOverride)
+		admin.DELETE("/feature-flags/:name/users/:user_id", h.featureFlagHandler.DeleteOverride)
+
+		// Public library moderation: public exercises and organization-shared workouts
+		admin.GET("/reports", h.moderationHandler.Queue)
+		admin.POST("/library/exercises/merge", h.moderationHandler.MergeExercises)
+		admin.PUT("/library/exercises/:id", h.moderationHandler.UpdateExercise)
+		admin.POST("/library/exercises/:id/approve", h.moderationHandler.ApproveExercise)
+		admin.POST("/library/exercises/:id/unpublish", h.moderationHandler.UnpublishExercise)
+		admin.PUT("/library/workouts/:id", h.moderationHandler.UpdateWorkout)
+		admin.POST("/library/workouts/:id/approve", h.moderationHandler.ApproveWorkout)
+		admin.POST("/library/workouts/:id/unpublish", h.moderationHandler.UnpublishWorkout)
+	}
+
+	return router, nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestNewRouter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("SUPABASE_JWT_SECRET", "test-secret")
+
+	// Gin panics while registering conflicting routes, which fails the test
+	router, err := newRouter(&routeDeps{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	registered := make(map[string]bool)
+	for _, route := range router.Routes() {
+		registered[route.Method+" "+route.Path] = true
+	}
+	for _, route := range []string{
+		"GET /api/users/:id",
+		"POST /api/users/:id/follow",
+		"GET /api/users/:id/followers",
+	} {
+		if !registered[route] {
+			t.Errorf("%s is not registered", route)
+		}
+	}
+}
+
+func TestNewRouter_IgnoresUntrustedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	t.Setenv("SUPABASE_JWT_SECRET", "test-secret")
+
+	for _, tt := range []struct {
+		trusted []string
+		want    string
+	}{
+		{nil, "192.0.2.1"},
+		{[]string{"192.0.2.0/24"}, "203.0.113.7"},
+	} {
+		router, err := newRouter(&routeDeps{trustedProxies: tt.trusted})
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		var clientIP string
+		router.GET("/test/ip", func(c *gin.Context) { clientIP = c.ClientIP() })
+
+		req := httptest.NewRequest(http.MethodGet, "/test/ip", nil)
+		req.RemoteAddr = "192.0.2.1:4321"
+		req.Header.Set("X-Forwarded-For", "203.0.113.7")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+
+		if clientIP != tt.want {
+			t.Errorf("Trusting %v: expected client IP %s, got %s", tt.trusted, tt.want, clientIP)
+		}
+	}
+
+	if _, err := newRouter(&routeDeps{trustedProxies: []string{"not-an-ip"}}); err == nil {
+		t.Error("Expected an invalid trusted proxy to be rejected")
+	}
+}
@@ -0,0 +1,120 @@
+// Command backup writes users' data to a portable archive, for account migrations
+// between Supabase projects and recovery drills; cmd/restore loads it back
+//
+// Usage:
+//
+//	backup [-url <database url>] -o <file> (-all | <user>...)
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/juan-cantero/fitapi/config"
+	"github.com/juan-cantero/fitapi/internal/backup"
+	"github.com/juan-cantero/fitapi/internal/database"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+func main() {
+	log.SetFlags(0)
+	url := flag.String("url", "", "Database to back up; defaults to DATABASE_URL (.env is loaded)")
+	output := flag.String("o", "", "Archive to write; required")
+	all := flag.Bool("all", false, "Back up every user")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: backup -o <file> (-all | <user>...)\n\nWrites users' workouts, sessions, measurements and settings to a gzipped archive.\nUsers are given by ID or email. Each user is read from a consistent snapshot.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *output == "" || *all == (flag.NArg() > 0) {
+		flag.Usage()
+		os.Exit(2)
+	}
+	cfg := config.Load()
+	if *url == "" {
+		*url = cfg.DatabaseURL
+	}
+	if *url == "" {
+		log.Fatal("DATABASE_URL not set")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := database.New(*url, database.PoolConfig{MaxConns: 2, QueryExecMode: cfg.DBQueryExecMode})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	userIDs, err := resolveUsers(ctx, db, *all, flag.Args())
+	if err != nil {
+		log.Fatal(err)
+	}
+	schemaVersion, err := backup.SchemaVersion(ctx, db.Pool)
+	if err != nil {
+		log.Fatalf("Failed to read the schema version: %v", err)
+	}
+
+	// Written next to the output and renamed once complete, so a failed run leaves no
+	// archive that looks whole
+	partial := *output + ".partial"
+	f, err := os.OpenFile(partial, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.Remove(partial)
+	defer f.Close()
+
+	w, err := backup.NewWriter(f, backup.Header{CreatedAt: time.Now().UTC(), SchemaVersion: schemaVersion})
+	if err != nil {
+		log.Fatal(err)
+	}
+	rows := 0
+	for _, id := range userIDs {
+		user, err := backup.Backup(ctx, db.Pool, id)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if err := w.Write(user); err != nil {
+			log.Fatal(err)
+		}
+		rows += user.Rows()
+		fmt.Printf("%8d rows  %s\n", user.Rows(), user.Email)
+	}
+	if err := w.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		log.Fatal(err)
+	}
+	if err := os.Rename(partial, *output); err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Backed up %d users, %d rows, at schema version %d to %s\n", len(userIDs), rows, schemaVersion, *output)
+}
+
+// resolveUsers finds the users to back up, by ID or email
+func resolveUsers(ctx context.Context, db *database.DB, all bool, args []string) ([]string, error) {
+	if all {
+		return backup.UserIDs(ctx, db.Pool)
+	}
+
+	users := services.NewUserService(repositories.NewPostgresUserRepository(db.Pool))
+	ids := make([]string, 0, len(args))
+	for _, arg := range args {
+		user, err := users.Find(ctx, arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", arg, err)
+		}
+		ids = append(ids, user.ID)
+	}
+	return ids, nil
+}
@@ -0,0 +1,441 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/juan-cantero/fitapi/config"
+	"github.com/juan-cantero/fitapi/internal/featureflags"
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/middleware"
+	"github.com/juan-cantero/fitapi/internal/push"
+	"github.com/juan-cantero/fitapi/internal/slo"
+)
+
+// Check outcomes
+const (
+	statusPass = "PASS"
+	statusFail = "FAIL"
+	statusSkip = "SKIP"
+)
+
+// checkResult is one line of the doctor report
+type checkResult struct {
+	name   string
+	status string
+	detail string
+}
+
+// doctorEnv is what the checks need, loaded once from the environment
+type doctorEnv struct {
+	cfg           *config.Config
+	jwtSecret     string
+	token         string
+	migrationsDir string
+	httpClient    *http.Client
+	pool          *pgxpool.Pool
+}
+
+// doctor runs every check, prints a pass/fail report and returns the exit code: 0 when
+// nothing failed, 1 otherwise
+func doctor(args []string) int {
+	flags := flag.NewFlagSet("doctor", flag.ExitOnError)
+	token := flags.String("token", "", "Access token to validate against SUPABASE_JWT_SECRET (defaults to SUPABASE_KEY when it is a JWT)")
+	migrationsDir := flags.String("migrations", "migrations", "Directory holding the migration files")
+	timeout := flags.Duration("timeout", 10*time.Second, "Timeout for each network check")
+	flags.Parse(args)
+
+	env := &doctorEnv{
+		cfg:           config.Load(),
+		jwtSecret:     os.Getenv("SUPABASE_JWT_SECRET"),
+		token:         *token,
+		migrationsDir: *migrationsDir,
+		httpClient:    &http.Client{Timeout: *timeout},
+	}
+	defer func() {
+		if env.pool != nil {
+			env.pool.Close()
+		}
+	}()
+
+	checks := []struct {
+		name string
+		run  func(ctx context.Context, env *doctorEnv) (string, string)
+	}{
+		{"environment", checkEnvironment},
+		{"database", checkDatabase},
+		{"migrations", checkMigrations},
+		{"supabase api", checkSupabase},
+		{"jwt secret", checkJWTSecret},
+		{"google fit", checkGoogleFit},
+		{"strava", checkStrava},
+		{"push", checkPush},
+		{"email", checkEmail},
+		{"slo targets", checkSLOTargets},
+		{"rate limits", checkRateLimits},
+		{"feature flags", checkFeatureFlags},
+	}
+
+	var results []checkResult
+	failed := 0
+	for _, check := range checks {
+		ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+		status, detail := check.run(ctx, env)
+		cancel()
+
+		if status == statusFail {
+			failed++
+		}
+		results = append(results, checkResult{name: check.name, status: status, detail: detail})
+	}
+
+	for _, result := range results {
+		fmt.Printf("%-4s  %-13s %s\n", result.status, result.name, result.detail)
+	}
+	if failed > 0 {
+		fmt.Printf("\n%d of %d checks failed\n", failed, len(results))
+		return 1
+	}
+	fmt.Printf("\nAll %d checks passed or were skipped\n", len(results))
+	return 0
+}
+
+// checkEnvironment reports required variables that are not set
+func checkEnvironment(ctx context.Context, env *doctorEnv) (string, string) {
+	required := map[string]string{
+		"SUPABASE_URL":        env.cfg.SupabaseURL,
+		"SUPABASE_KEY":        env.cfg.SupabaseKey,
+		"SUPABASE_JWT_SECRET": env.jwtSecret,
+		"DATABASE_URL":        env.cfg.DatabaseURL,
+	}
+
+	var missing []string
+	for _, key := range []string{"SUPABASE_URL", "SUPABASE_KEY", "SUPABASE_JWT_SECRET", "DATABASE_URL"} {
+		if required[key] == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return statusFail, "missing " + strings.Join(missing, ", ")
+	}
+	if os.Getenv("SKIP_AUTH") == "true" {
+		return statusFail, "SKIP_AUTH is true; every request is served as a test user"
+	}
+	return statusPass, "required variables are set"
+}
+
+// checkDatabase connects to DATABASE_URL and keeps the pool for the migration check
+func checkDatabase(ctx context.Context, env *doctorEnv) (string, string) {
+	if env.cfg.DatabaseURL == "" {
+		return statusSkip, "DATABASE_URL is not set"
+	}
+
+	pool, err := pgxpool.New(ctx, env.cfg.DatabaseURL)
+	if err != nil {
+		return statusFail, fmt.Sprintf("invalid DATABASE_URL: %v", err)
+	}
+	var version string
+	if err := pool.QueryRow(ctx, `SHOW server_version`).Scan(&version); err != nil {
+		pool.Close()
+		return statusFail, fmt.Sprintf("cannot connect: %v", err)
+	}
+
+	env.pool = pool
+	return statusPass, "connected to PostgreSQL " + version
+}
+
+// checkMigrations compares the database's migration version with the newest migration file
+func checkMigrations(ctx context.Context, env *doctorEnv) (string, string) {
+	if env.pool == nil {
+		return statusSkip, "no database connection"
+	}
+
+	latest, err := latestMigration(env.migrationsDir)
+	if err != nil {
+		return statusFail, err.Error()
+	}
+
+	var version int64
+	var dirty bool
+	err = env.pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return statusFail, fmt.Sprintf("no migrations applied, latest is %d; run cmd/migrate", latest)
+	}
+	if err != nil {
+		return statusFail, fmt.Sprintf("cannot read schema_migrations (run cmd/migrate): %v", err)
+	}
+
+	switch {
+	case dirty:
+		return statusFail, fmt.Sprintf("version %d is dirty; a migration failed halfway and needs fixing by hand", version)
+	case version < latest:
+		return statusFail, fmt.Sprintf("version %d is behind %d; run cmd/migrate", version, latest)
+	case version > latest:
+		return statusFail, fmt.Sprintf("version %d is ahead of %d; this build is older than the database", version, latest)
+	}
+	return statusPass, fmt.Sprintf("at version %d", version)
+}
+
+// latestMigration returns the highest version among the up migrations in dir
+func latestMigration(dir string) (int64, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.up.sql"))
+	if err != nil || len(files) == 0 {
+		return 0, fmt.Errorf("no migrations found in %s (set -migrations)", dir)
+	}
+
+	var latest int64
+	for _, file := range files {
+		prefix, _, _ := strings.Cut(filepath.Base(file), "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("migration %s has no numeric version", filepath.Base(file))
+		}
+		latest = max(latest, version)
+	}
+	return latest, nil
+}
+
+// checkSupabase calls the auth settings endpoint, which needs a valid project URL and API key
+func checkSupabase(ctx context.Context, env *doctorEnv) (string, string) {
+	if env.cfg.SupabaseURL == "" || env.cfg.SupabaseKey == "" {
+		return statusSkip, "SUPABASE_URL or SUPABASE_KEY is not set"
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(env.cfg.SupabaseURL, "/")+"/auth/v1/settings", nil)
+	if err != nil {
+		return statusFail, fmt.Sprintf("invalid SUPABASE_URL: %v", err)
+	}
+	req.Header.Set("apikey", env.cfg.SupabaseKey)
+
+	resp, err := env.httpClient.Do(req)
+	if err != nil {
+		return statusFail, fmt.Sprintf("cannot reach SUPABASE_URL: %v", err)
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return statusFail, "SUPABASE_KEY was rejected"
+	case resp.StatusCode != http.StatusOK:
+		return statusFail, fmt.Sprintf("unexpected status %d from the auth API; check SUPABASE_URL", resp.StatusCode)
+	}
+	return statusPass, "auth API accepted the key"
+}
+
+// checkJWTSecret validates a token the way the auth middleware does. Legacy Supabase API keys
+// are JWTs signed with the project's secret, so SUPABASE_KEY serves as the test token unless
+// -token is given.
+func checkJWTSecret(ctx context.Context, env *doctorEnv) (string, string) {
+	if env.jwtSecret == "" {
+		return statusSkip, "SUPABASE_JWT_SECRET is not set"
+	}
+
+	token, source := env.token, "-token"
+	if token == "" {
+		token, source = env.cfg.SupabaseKey, "SUPABASE_KEY"
+	}
+	if strings.Count(token, ".") != 2 {
+		return statusSkip, "SUPABASE_KEY is not a JWT; pass an access token with -token (see cmd/gettoken)"
+	}
+
+	_, err := jwt.Parse(token, func(token *jwt.Token) (any, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(env.jwtSecret), nil
+	})
+	switch {
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return statusFail, "signature of " + source + " does not match SUPABASE_JWT_SECRET"
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return statusFail, source + " has expired; pass a fresh token with -token"
+	case err != nil:
+		return statusFail, fmt.Sprintf("cannot validate %s: %v", source, err)
+	}
+	return statusPass, source + " validates against SUPABASE_JWT_SECRET"
+}
+
+// checkGoogleFit reports a partially configured Google Fit integration
+func checkGoogleFit(ctx context.Context, env *doctorEnv) (string, string) {
+	return checkIntegration(map[string]string{
+		"GOOGLE_FIT_CLIENT_ID":     env.cfg.GoogleFitClientID,
+		"GOOGLE_FIT_CLIENT_SECRET": env.cfg.GoogleFitClientSecret,
+		"GOOGLE_FIT_REDIRECT_URL":  env.cfg.GoogleFitRedirectURL,
+	})
+}
+
+// checkStrava reports a partially configured Strava integration
+func checkStrava(ctx context.Context, env *doctorEnv) (string, string) {
+	status, detail := checkIntegration(map[string]string{
+		"STRAVA_CLIENT_ID":     env.cfg.StravaClientID,
+		"STRAVA_CLIENT_SECRET": env.cfg.StravaClientSecret,
+		"STRAVA_REDIRECT_URL":  env.cfg.StravaRedirectURL,
+	})
+	if status == statusPass && (env.cfg.StravaWebhookVerifyToken == "" || env.cfg.StravaWebhookSubscriptionID == 0) {
+		return statusPass, "enabled, but webhooks are off (STRAVA_WEBHOOK_* not set); only the periodic sync runs"
+	}
+	return status, detail
+}
+
+// checkPush loads the push notification credentials, which the API refuses to start with
+// when unreadable; a platform without credentials gets no notifications
+func checkPush(ctx context.Context, env *doctorEnv) (string, string) {
+	var enabled []string
+
+	if env.cfg.FCMCredentialsFile != "" {
+		credentials, err := os.ReadFile(env.cfg.FCMCredentialsFile)
+		if err != nil {
+			return statusFail, err.Error()
+		}
+		if _, err := push.NewFCM(credentials, nil); err != nil {
+			return statusFail, "FCM_CREDENTIALS_FILE: " + err.Error()
+		}
+		enabled = append(enabled, "android")
+	}
+
+	status, detail := checkIntegration(map[string]string{
+		"APNS_KEY_FILE": env.cfg.APNsKeyFile,
+		"APNS_KEY_ID":   env.cfg.APNsKeyID,
+		"APNS_TEAM_ID":  env.cfg.APNsTeamID,
+		"APNS_TOPIC":    env.cfg.APNsTopic,
+	})
+	if status == statusFail {
+		return status, "ios " + detail
+	}
+	if status == statusPass {
+		key, err := os.ReadFile(env.cfg.APNsKeyFile)
+		if err != nil {
+			return statusFail, err.Error()
+		}
+		_, err = push.NewAPNs(push.APNsConfig{
+			KeyID:      env.cfg.APNsKeyID,
+			TeamID:     env.cfg.APNsTeamID,
+			Topic:      env.cfg.APNsTopic,
+			PrivateKey: key,
+		}, nil)
+		if err != nil {
+			return statusFail, "APNS_KEY_FILE: " + err.Error()
+		}
+		enabled = append(enabled, "ios")
+	}
+
+	if len(enabled) == 0 {
+		return statusSkip, "disabled"
+	}
+	return statusPass, "enabled for " + strings.Join(enabled, " and ")
+}
+
+// checkEmail validates the email provider settings, which the API refuses to start with
+// when invalid, and that an SMTP relay accepts connections
+func checkEmail(ctx context.Context, env *doctorEnv) (string, string) {
+	m, err := mailer.New(mailer.Config{
+		Provider: env.cfg.MailProvider,
+		From:     env.cfg.MailFrom,
+		SMTP: mailer.SMTPConfig{
+			Host:     env.cfg.SMTPHost,
+			Port:     env.cfg.SMTPPort,
+			Username: env.cfg.SMTPUsername,
+			Password: env.cfg.SMTPPassword,
+		},
+		ResendAPIKey:   env.cfg.ResendAPIKey,
+		SendGridAPIKey: env.cfg.SendGridAPIKey,
+	})
+	if err != nil {
+		return statusFail, err.Error()
+	}
+	if m == nil {
+		return statusSkip, "disabled (MAIL_PROVIDER not set)"
+	}
+	if env.cfg.MailProvider != mailer.ProviderSMTP {
+		return statusPass, "enabled via " + env.cfg.MailProvider
+	}
+
+	addr := net.JoinHostPort(env.cfg.SMTPHost, strconv.Itoa(env.cfg.SMTPPort))
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return statusFail, err.Error()
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+	if env.cfg.SMTPPort == 465 {
+		conn = tls.Client(conn, &tls.Config{ServerName: env.cfg.SMTPHost})
+	}
+	if _, _, err := textproto.NewConn(conn).ReadResponse(220); err != nil {
+		return statusFail, addr + " did not greet: " + err.Error()
+	}
+	return statusPass, "enabled via smtp (" + addr + ")"
+}
+
+// checkSLOTargets parses SLO_TARGETS, which the API refuses to start with when invalid
+func checkSLOTargets(ctx context.Context, env *doctorEnv) (string, string) {
+	targets, err := slo.ParseTargets(env.cfg.SLOTargets)
+	if err != nil {
+		return statusFail, err.Error()
+	}
+	if len(targets) == 0 {
+		return statusSkip, "no targets; response times are not tracked"
+	}
+	return statusPass, fmt.Sprintf("%d targets", len(targets))
+}
+
+// checkRateLimits parses RATE_LIMITS and RATE_LIMIT_BACKEND, which the API refuses to start
+// with when invalid
+func checkRateLimits(ctx context.Context, env *doctorEnv) (string, string) {
+	rules, err := middleware.ParseRateLimitRules(env.cfg.RateLimits)
+	if err != nil {
+		return statusFail, err.Error()
+	}
+	if env.cfg.RateLimitBackend != "memory" && env.cfg.RateLimitBackend != "redis" {
+		return statusFail, fmt.Sprintf("unknown backend %q, expected memory or redis", env.cfg.RateLimitBackend)
+	}
+	return statusPass, fmt.Sprintf("%d group rules in %s", len(rules), env.cfg.RateLimitBackend)
+}
+
+// checkFeatureFlags parses FEATURE_FLAGS, which the API refuses to start with when invalid
+func checkFeatureFlags(ctx context.Context, env *doctorEnv) (string, string) {
+	flags, err := featureflags.ParseDefaults(env.cfg.FeatureFlags)
+	if err != nil {
+		return statusFail, err.Error()
+	}
+	if len(flags) == 0 {
+		return statusSkip, "no defaults; flags not edited at runtime use the builtin ones"
+	}
+	return statusPass, fmt.Sprintf("%d defaults", len(flags))
+}
+
+// checkIntegration passes when all or none of an integration's variables are set; the
+// integration stays disabled otherwise, which is rarely intended
+func checkIntegration(vars map[string]string) (string, string) {
+	var missing []string
+	for key, value := range vars {
+		if value == "" {
+			missing = append(missing, key)
+		}
+	}
+
+	switch len(missing) {
+	case len(vars):
+		return statusSkip, "disabled"
+	case 0:
+		return statusPass, "enabled"
+	}
+	slices.Sort(missing)
+	return statusFail, "disabled because " + strings.Join(missing, ", ") + " not set"
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/config"
+	"github.com/juan-cantero/fitapi/internal/schemadrift"
+)
+
+// schemaSnapshot writes a database's schema as JSON, the expected state for drift when
+// taken from a database freshly migrated to the latest version
+func schemaSnapshot(args []string) int {
+	flags := flag.NewFlagSet("schema-snapshot", flag.ExitOnError)
+	url := flags.String("url", "", "Database to snapshot; defaults to DATABASE_URL")
+	output := flags.String("o", "", "File to write; defaults to standard output")
+	timeout := flags.Duration("timeout", time.Minute, "Timeout for reading the schema")
+	flags.Parse(args)
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	schema, err := inspectSchema(ctx, *url)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	w := os.Stdout
+	if *output != "" {
+		f, err := os.Create(*output)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer f.Close()
+		w = f
+	}
+	if err := schema.Write(w); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	if *output != "" {
+		fmt.Printf("Wrote %d tables at migration %d to %s\n", len(schema.Tables), schema.Version, *output)
+	}
+	return 0
+}
+
+// drift compares a database's schema with the expected one, from a snapshot or a
+// freshly migrated reference database, prints every difference and returns the exit
+// code: 0 when the schemas match, 1 otherwise
+func drift(args []string) int {
+	flags := flag.NewFlagSet("drift", flag.ExitOnError)
+	url := flags.String("url", "", "Database to check; defaults to DATABASE_URL")
+	snapshot := flags.String("snapshot", "", "Expected schema, written by schema-snapshot")
+	reference := flags.String("reference", "", "Database freshly migrated to the latest version, instead of a snapshot")
+	migrationsDir := flags.String("migrations", "migrations", "Directory holding the migration files, to check the expected schema is current")
+	timeout := flags.Duration("timeout", time.Minute, "Timeout for reading the schemas")
+	flags.Parse(args)
+
+	if (*snapshot == "") == (*reference == "") {
+		fmt.Fprintln(os.Stderr, "Expected one of -snapshot and -reference")
+		flags.Usage()
+		return 2
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), *timeout)
+	defer cancel()
+
+	var expected *schemadrift.Schema
+	var err error
+	if *snapshot != "" {
+		expected, err = readSnapshot(*snapshot)
+	} else {
+		expected, err = inspectSchema(ctx, *reference)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	actual, err := inspectSchema(ctx, *url)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+
+	// A stale expected schema reports every later migration as drift
+	if latest, err := latestMigration(*migrationsDir); err == nil && expected.Version != latest {
+		fmt.Printf("Warning: the expected schema is at migration %d but the latest is %d; take a new snapshot\n", expected.Version, latest)
+	}
+	if actual.Version != expected.Version {
+		fmt.Printf("Warning: the database is at migration %d, the expected schema at %d; differences include those migrations\n", actual.Version, expected.Version)
+	}
+
+	drifts := schemadrift.Diff(expected, actual)
+	for _, d := range drifts {
+		fmt.Println(d)
+	}
+	if len(drifts) > 0 {
+		fmt.Printf("\n%d differences from the schema at migration %d\n", len(drifts), expected.Version)
+		return 1
+	}
+	fmt.Printf("No drift: %d tables match the schema at migration %d\n", len(expected.Tables), expected.Version)
+	return 0
+}
+
+// inspectSchema reads the schema of url, or DATABASE_URL when empty
+func inspectSchema(ctx context.Context, url string) (*schemadrift.Schema, error) {
+	if url == "" {
+		url = config.Load().DatabaseURL
+	}
+	if url == "" {
+		return nil, fmt.Errorf("DATABASE_URL is not set")
+	}
+
+	conn, err := pgx.Connect(ctx, url)
+	if err != nil {
+		return nil, fmt.Errorf("cannot connect: %w", err)
+	}
+	defer conn.Close(context.Background())
+	return schemadrift.Inspect(ctx, conn)
+}
+
+func readSnapshot(path string) (*schemadrift.Schema, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return schemadrift.Read(f)
+}
@@ -0,0 +1,45 @@
+// Command fitctl holds operator tasks for a fitapi deployment
+//
+// Usage:
+//
+//	fitctl doctor [-token <jwt>] [-migrations <dir>] [-timeout <duration>]
+//	fitctl schema-snapshot [-url <database url>] [-o <file>]
+//	fitctl drift (-snapshot <file> | -reference <database url>) [-url <database url>]
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+const usage = `Usage: fitctl <command> [flags]
+
+Commands:
+  doctor             Check the environment configuration and the services it points to
+  schema-snapshot    Write a database's schema as JSON; take it from a database freshly
+                     migrated to the latest version, as the expected schema for drift
+  drift              Compare DATABASE_URL's schema with a snapshot or a freshly migrated
+                     reference database and list tables, columns, indexes and constraints
+                     that differ
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "doctor":
+		os.Exit(doctor(os.Args[2:]))
+	case "schema-snapshot":
+		os.Exit(schemaSnapshot(os.Args[2:]))
+	case "drift":
+		os.Exit(drift(os.Args[2:]))
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+}
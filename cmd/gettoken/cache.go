@@ -0,0 +1,100 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// refreshMargin is how long before its expiry a cached token is refreshed, so it doesn't
+// expire mid test run
+const refreshMargin = 5 * time.Minute
+
+// tokenCache is ~/.fitapi/token.json: tokens by Supabase project and email
+type tokenCache struct {
+	Tokens map[string]*cachedToken `json:"tokens"`
+}
+
+type cachedToken struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+	UserID       string    `json:"user_id"`
+	Email        string    `json:"email"`
+}
+
+func cacheKey(supabaseURL, email string) string {
+	return supabaseURL + " " + email
+}
+
+// cachePath is where tokens are cached, in the user's home directory
+func cachePath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fitapi", "token.json"), nil
+}
+
+// loadCache reads the cache; a missing file is an empty cache
+func loadCache(path string) (*tokenCache, error) {
+	cache := &tokenCache{Tokens: make(map[string]*cachedToken)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, err
+	}
+	if cache.Tokens == nil {
+		cache.Tokens = make(map[string]*cachedToken)
+	}
+	return cache, nil
+}
+
+// save writes the cache readable by the user only, as it holds credentials. The file is
+// replaced by a rename so concurrent runs never read half of it.
+func (c *tokenCache) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "token-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// fresh reports whether the access token stays valid for a while yet
+func (t *cachedToken) fresh(now time.Time) bool {
+	return t.AccessToken != "" && now.Add(refreshMargin).Before(t.ExpiresAt)
+}
+
+func newCachedToken(token *SignInResponse, now time.Time) *cachedToken {
+	return &cachedToken{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		ExpiresAt:    now.Add(time.Duration(token.ExpiresIn) * time.Second).UTC(),
+		UserID:       token.User.ID,
+		Email:        token.User.Email,
+	}
+}
@@ -3,11 +3,13 @@ package main
 import (
 	"bytes"
 	"encoding/json"
+	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -29,62 +31,60 @@ type SignInResponse struct {
 	} `json:"user"`
 }
 
-func main() {
-	// Load .env
-	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found")
-	}
+const usage = `Usage: gettoken [--profile NAME] [--json] [--print-curl] [--no-cache] [email [password]]
 
-	supabaseURL := os.Getenv("SUPABASE_URL")
-	supabaseKey := os.Getenv("SUPABASE_KEY")
+Signs in to Supabase and prints an access token. Without a profile, the environment is
+SUPABASE_URL and SUPABASE_KEY (.env is loaded), the default user is test@example.com /
+test123456, and users who don't exist are signed up.
 
-	if supabaseURL == "" || supabaseKey == "" {
-		log.Fatal("SUPABASE_URL and SUPABASE_KEY must be set")
-	}
+--profile targets an environment defined in ~/.fitapi/profiles.json, e.g.
 
-	// Check for --json flag for machine-readable output
-	jsonOutput := false
-	if len(os.Args) > 1 && os.Args[1] == "--json" {
-		jsonOutput = true
-	}
+  {"staging": {"supabase_url": "https://xyz.supabase.co", "supabase_key": "eyJ...",
+               "api_url": "https://staging.fitapi.dev", "email": "qa@fitapi.dev"}}
+
+Profiles only sign in, never sign up. A missing password is asked for on the terminal.
 
-	// Get email and password from args or use defaults
-	email := "test@example.com"
-	password := "test123456"
+Tokens are cached in ~/.fitapi/token.json and reused until shortly before they expire,
+then renewed with the refresh token, so the password is only needed now and then.
 
-	if len(os.Args) > 2 && !jsonOutput {
-		email = os.Args[1]
-		password = os.Args[2]
-	} else if len(os.Args) > 3 && jsonOutput {
-		email = os.Args[2]
-		password = os.Args[3]
+`
+
+func main() {
+	profileName := flag.String("profile", "", "Environment from ~/.fitapi/profiles.json, e.g. staging")
+	jsonOutput := flag.Bool("json", false, "Print machine-readable JSON")
+	printCurl := flag.Bool("print-curl", false, "Print only a curl command with the token")
+	noCache := flag.Bool("no-cache", false, "Sign in again, ignoring the cached token (the new one is still cached)")
+	flag.Usage = func() {
+		fmt.Fprint(os.Stderr, usage)
+		flag.PrintDefaults()
 	}
+	flag.Parse()
 
-	// Try to sign in (if user exists)
-	token, err := signIn(supabaseURL, supabaseKey, email, password)
+	if flag.NArg() > 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	t, err := resolveTarget(*profileName, flag.Args())
 	if err != nil {
-		if !jsonOutput {
-			fmt.Fprintln(os.Stderr, "Sign in failed, trying to sign up...")
-		}
-		// If sign in fails, try to sign up (create user)
-		token, err = signUp(supabaseURL, supabaseKey, email, password)
-		if err != nil {
-			log.Fatalf("Sign up failed: %v", err)
-		}
-		if !jsonOutput {
-			fmt.Fprintln(os.Stderr, "✅ User created successfully!")
-		}
+		log.Fatal(err)
 	}
 
+	quiet := *jsonOutput || *printCurl
+	token := getToken(t, !*noCache, quiet)
+
 	// Output format
-	if jsonOutput {
+	if *printCurl {
+		fmt.Printf("curl -H 'Authorization: Bearer %s' %s/api/exercises\n", token.AccessToken, t.APIURL)
+	} else if *jsonOutput {
 		// Machine-readable JSON output
 		output := map[string]interface{}{
 			"access_token": token.AccessToken,
-			"expires_in":   token.ExpiresIn,
-			"expires_at":   calculateExpiresAt(token.ExpiresIn),
-			"user_id":      token.User.ID,
-			"email":        token.User.Email,
+			"expires_in":   int(time.Until(token.ExpiresAt).Seconds()),
+			"expires_at":   token.ExpiresAt.Unix(),
+			"user_id":      token.UserID,
+			"email":        token.Email,
+			"api_url":      t.APIURL,
 		}
 		jsonData, _ := json.Marshal(output)
 		fmt.Println(string(jsonData))
@@ -95,17 +95,164 @@ func main() {
 		fmt.Println("─────────────────────────────────────────────────────────")
 		fmt.Println(token.AccessToken)
 		fmt.Println("─────────────────────────────────────────────────────────")
-		fmt.Printf("\n👤 User ID: %s\n", token.User.ID)
-		fmt.Printf("📧 Email: %s\n", token.User.Email)
-		fmt.Printf("⏰ Expires in: %d seconds\n", token.ExpiresIn)
+		fmt.Printf("\n👤 User ID: %s\n", token.UserID)
+		fmt.Printf("📧 Email: %s\n", token.Email)
+		if t.Profile != "" {
+			fmt.Printf("🌍 Profile: %s (%s)\n", t.Profile, t.APIURL)
+		}
+		fmt.Printf("⏰ Expires in: %d seconds\n", int(time.Until(token.ExpiresAt).Seconds()))
 		fmt.Println("\n💡 Usage:")
-		fmt.Println("curl http://localhost:8080/api/exercises \\")
+		fmt.Printf("curl %s/api/exercises \\\n", t.APIURL)
 		fmt.Printf("  -H 'Authorization: Bearer %s'\n", token.AccessToken)
 	}
 }
 
-func calculateExpiresAt(expiresIn int) int64 {
-	return time.Now().Unix() + int64(expiresIn)
+// resolveTarget picks the environment from the profile, or .env without one, and the
+// user from the arguments, falling back to the profile's or the default test user
+func resolveTarget(profileName string, args []string) (*target, error) {
+	var t *target
+	if profileName != "" {
+		p, err := loadProfile(profileName)
+		if err != nil {
+			return nil, err
+		}
+		t = &target{
+			Profile:     profileName,
+			SupabaseURL: p.SupabaseURL,
+			SupabaseKey: p.SupabaseKey,
+			APIURL:      strings.TrimSuffix(p.APIURL, "/"),
+			Email:       p.Email,
+			Password:    p.Password,
+		}
+	} else {
+		// Load .env
+		if err := godotenv.Load(); err != nil {
+			log.Println("No .env file found")
+		}
+		t = &target{
+			SupabaseURL: os.Getenv("SUPABASE_URL"),
+			SupabaseKey: os.Getenv("SUPABASE_KEY"),
+			APIURL:      apiURL(),
+			Email:       "test@example.com",
+			Password:    "test123456",
+		}
+		if t.SupabaseURL == "" || t.SupabaseKey == "" {
+			return nil, fmt.Errorf("SUPABASE_URL and SUPABASE_KEY must be set")
+		}
+	}
+
+	// An email without a password asks for it
+	if len(args) >= 1 {
+		t.Email = args[0]
+		t.Password = ""
+	}
+	if len(args) == 2 {
+		t.Password = args[1]
+	}
+	return t, nil
+}
+
+// getToken returns a cached token while it is fresh, refreshes it once it is about to
+// expire, and otherwise signs in or up; new tokens are cached. Cache failures only warn,
+// as a token can always be had by signing in.
+func getToken(t *target, useCache bool, quiet bool) *cachedToken {
+	now := time.Now()
+	key := cacheKey(t.SupabaseURL, t.Email)
+
+	path, err := cachePath()
+	var cache *tokenCache
+	if err == nil {
+		cache, err = loadCache(path)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Token cache unavailable: %v\n", err)
+		cache = nil
+	}
+
+	if cached := cacheEntry(cache, key); useCache && cached != nil {
+		if cached.fresh(now) {
+			if !quiet {
+				fmt.Fprintln(os.Stderr, "Using the cached token")
+			}
+			return cached
+		}
+		if cached.RefreshToken != "" {
+			refreshed, err := refresh(t.SupabaseURL, t.SupabaseKey, cached.RefreshToken)
+			if err == nil {
+				if !quiet {
+					fmt.Fprintln(os.Stderr, "Refreshed the cached token")
+				}
+				return storeToken(cache, path, key, newCachedToken(refreshed, now))
+			}
+			if !quiet {
+				fmt.Fprintln(os.Stderr, "Refreshing the cached token failed, signing in...")
+			}
+		}
+	}
+
+	password := t.Password
+	if password == "" {
+		label := t.Email
+		if t.Profile != "" {
+			label += " on " + t.Profile
+		}
+		if password, err = promptPassword(label); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	// Try to sign in (if user exists)
+	token, err := signIn(t.SupabaseURL, t.SupabaseKey, t.Email, password)
+	if err != nil && t.Profile != "" {
+		// Shared environments only sign in existing users
+		log.Fatalf("Sign in failed: %v", err)
+	}
+	if err != nil {
+		if !quiet {
+			fmt.Fprintln(os.Stderr, "Sign in failed, trying to sign up...")
+		}
+		// If sign in fails, try to sign up (create user)
+		token, err = signUp(t.SupabaseURL, t.SupabaseKey, t.Email, password)
+		if err != nil {
+			log.Fatalf("Sign up failed: %v", err)
+		}
+		if !quiet {
+			fmt.Fprintln(os.Stderr, "✅ User created successfully!")
+		}
+	}
+
+	return storeToken(cache, path, key, newCachedToken(token, now))
+}
+
+func cacheEntry(cache *tokenCache, key string) *cachedToken {
+	if cache == nil {
+		return nil
+	}
+	return cache.Tokens[key]
+}
+
+// storeToken caches the token, if the cache is available, and returns it
+func storeToken(cache *tokenCache, path, key string, token *cachedToken) *cachedToken {
+	if cache == nil {
+		return token
+	}
+	cache.Tokens[key] = token
+	if err := cache.save(path); err != nil {
+		fmt.Fprintf(os.Stderr, "⚠️  Failed to cache the token: %v\n", err)
+	}
+	return token
+}
+
+// apiURL is the local API's base URL, from API_URL or PORT
+func apiURL() string {
+	if url := os.Getenv("API_URL"); url != "" {
+		return strings.TrimSuffix(url, "/")
+	}
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	return "http://localhost:" + port
 }
 
 func signIn(supabaseURL, apiKey, email, password string) (*SignInResponse, error) {
@@ -130,7 +277,14 @@ func signUp(supabaseURL, apiKey, email, password string) (*SignInResponse, error
 	return makeAuthRequest(url, apiKey, reqBody)
 }
 
-func makeAuthRequest(url, apiKey string, reqBody SignInRequest) (*SignInResponse, error) {
+// refresh exchanges a refresh token for a new access token (and refresh token)
+func refresh(supabaseURL, apiKey, refreshToken string) (*SignInResponse, error) {
+	url := fmt.Sprintf("%s/auth/v1/token?grant_type=refresh_token", supabaseURL)
+
+	return makeAuthRequest(url, apiKey, map[string]string{"refresh_token": refreshToken})
+}
+
+func makeAuthRequest(url, apiKey string, reqBody any) (*SignInResponse, error) {
 	jsonData, err := json.Marshal(reqBody)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// target is the environment to get a token for and the user to sign in as
+type target struct {
+	Profile     string // Empty for the .env environment
+	SupabaseURL string
+	SupabaseKey string
+	APIURL      string
+	Email       string
+	Password    string // Empty to prompt, when signing in is needed
+}
+
+// profile is an entry of ~/.fitapi/profiles.json, e.g.
+//
+//	{"staging": {"supabase_url": "https://xyz.supabase.co", "supabase_key": "eyJ...",
+//	             "api_url": "https://staging.fitapi.dev", "email": "qa@fitapi.dev"}}
+//
+// The password is better left out, to be prompted for.
+type profile struct {
+	SupabaseURL string `json:"supabase_url"`
+	SupabaseKey string `json:"supabase_key"`
+	APIURL      string `json:"api_url"`
+	Email       string `json:"email"`
+	Password    string `json:"password"`
+}
+
+// profilesPath is where profiles are defined, next to the token cache
+func profilesPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".fitapi", "profiles.json"), nil
+}
+
+// loadProfile reads the named profile; every field but the password is required
+func loadProfile(name string) (*profile, error) {
+	path, err := profilesPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("no profiles defined, create %s", path)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var profiles map[string]*profile
+	if err := json.Unmarshal(data, &profiles); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	p, ok := profiles[name]
+	if !ok {
+		names := make([]string, 0, len(profiles))
+		for n := range profiles {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+
+	var missing []string
+	for field, value := range map[string]string{"supabase_url": p.SupabaseURL, "supabase_key": p.SupabaseKey, "api_url": p.APIURL, "email": p.Email} {
+		if value == "" {
+			missing = append(missing, field)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return nil, fmt.Errorf("profile %q is missing %s", name, strings.Join(missing, ", "))
+	}
+	return p, nil
+}
+
+// promptPassword asks for a password on the terminal without echoing it, where stty can
+// turn echo off
+func promptPassword(label string) (string, error) {
+	tty, err := os.OpenFile("/dev/tty", os.O_RDWR, 0)
+	if err != nil {
+		return "", errors.New("no terminal to ask for the password on, pass it as an argument")
+	}
+	defer tty.Close()
+
+	fmt.Fprintf(tty, "Password for %s: ", label)
+	stty := func(args ...string) error {
+		cmd := exec.Command("stty", args...)
+		cmd.Stdin = tty
+		return cmd.Run()
+	}
+	if err := stty("-echo"); err == nil {
+		defer func() {
+			stty("echo")
+			fmt.Fprintln(tty)
+		}()
+	}
+
+	line, err := bufio.NewReader(tty).ReadString('\n')
+	if err != nil && line == "" {
+		return "", err
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("no password given")
+	}
+	return password, nil
+}
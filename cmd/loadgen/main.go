@@ -0,0 +1,80 @@
+// Command loadgen creates users with multi-year training histories in the database at
+// DATABASE_URL, for testing pagination, analytics and indexes at scale
+//
+// Usage:
+//
+//	loadgen [-users N] [-years N] [-seed N] [-workers N]
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/juan-cantero/fitapi/internal/seed"
+)
+
+func main() {
+	log.SetFlags(0)
+	users := flag.Int("users", 1000, "Users to generate: loadgen-000000@fitapi.dev and on")
+	years := flag.Int("years", 3, "Years of history per user, ending last week")
+	seedValue := flag.Uint64("seed", 1, "Varies the histories; users keep their IDs")
+	workers := flag.Int("workers", 4, "Users written at once")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: loadgen [flags]\n\nCreates users with years of sessions, sets, personal records and weigh-ins in\nDATABASE_URL (.env is loaded). Users who already have sessions are skipped, so an\ninterrupted run can be resumed.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if *users < 1 || *years < 1 || *workers < 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL not set")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		log.Fatalf("Invalid DATABASE_URL: %v", err)
+	}
+	poolConfig.MaxConns = int32(*workers + 1)
+	db, err := pgxpool.NewWithConfig(ctx, poolConfig)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	started := time.Now()
+	reports, err := seed.Generate(ctx, db, seed.GenerateOptions{
+		Users:   *users,
+		Years:   *years,
+		Seed:    *seedValue,
+		Workers: *workers,
+		Progress: func(done, total int) {
+			if done%100 == 0 || done == total {
+				fmt.Printf("%d/%d users (%s)\n", done, total, time.Since(started).Round(time.Second))
+			}
+		},
+	})
+	for _, report := range reports {
+		fmt.Println(report)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
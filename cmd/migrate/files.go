@@ -0,0 +1,140 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-migrate/migrate/v4"
+)
+
+// migrationFilePattern matches migration files: version_name.up.sql or .down.sql
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// migrationFile is a migration in the directory, by its up file
+type migrationFile struct {
+	version uint64
+	name    string // version_name, without the suffix
+}
+
+// migrationFiles lists the migrations in dir, oldest first
+func migrationFiles(dir string) ([]migrationFile, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []migrationFile
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil || match[3] != "up" {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version", entry.Name())
+		}
+		files = append(files, migrationFile{version: version, name: match[1] + "_" + match[2]})
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].version < files[j].version })
+	return files, nil
+}
+
+// status prints every migration in dir, marking those the database has applied
+func status(m *migrate.Migrate, dir string) error {
+	files, err := migrationFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	version, dirty, err := m.Version()
+	applied := err == nil
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return err
+	}
+
+	pending := 0
+	for _, file := range files {
+		state := "pending"
+		switch {
+		case applied && file.version == uint64(version) && dirty:
+			state = "DIRTY"
+		case applied && file.version <= uint64(version):
+			state = "applied"
+		default:
+			pending++
+		}
+		fmt.Printf("%-8s %s\n", state, file.name)
+	}
+
+	fmt.Println()
+	if !applied {
+		fmt.Printf("No migrations applied, %d pending\n", pending)
+		return nil
+	}
+	fmt.Printf("Current version: %d, Dirty: %v, %d pending\n", version, dirty, pending)
+	if dirty {
+		fmt.Println("A migration failed halfway: fix the schema by hand, then force the last clean version")
+	}
+	return nil
+}
+
+// migrationNameCleaner turns a migration name into lowercase words joined by underscores
+var migrationNameCleaner = regexp.MustCompile(`[^a-z0-9]+`)
+
+// create writes an empty up and down migration pair
+func create(dir string, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	seq := flags.Bool("seq", false, "Version by the next number instead of the current time")
+	flags.Parse(args)
+	if flags.NArg() != 1 {
+		return errors.New("expected one argument, the migration name, e.g. create_goals_table")
+	}
+
+	name := strings.Trim(migrationNameCleaner.ReplaceAllString(strings.ToLower(flags.Arg(0)), "_"), "_")
+	if name == "" {
+		return fmt.Errorf("invalid migration name %q", flags.Arg(0))
+	}
+
+	files, err := migrationFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	version := time.Now().UTC().Format("20060102150405")
+	if *seq {
+		var latest uint64
+		if len(files) > 0 {
+			latest = files[len(files)-1].version
+		}
+		version = fmt.Sprintf("%03d", latest+1)
+	}
+	number, _ := strconv.ParseUint(version, 10, 64)
+	for _, file := range files {
+		if file.version == number {
+			return fmt.Errorf("version %s already exists: %s", version, file.name)
+		}
+	}
+
+	// Headers follow the existing migrations: what the up migration does, and what rolling back undoes
+	title := strings.ReplaceAll(name, "_", " ")
+	title = strings.ToUpper(title[:1]) + title[1:]
+	base := filepath.Join(dir, version+"_"+name)
+	if err := os.WriteFile(base+".up.sql", []byte("-- "+title+"\n"), 0o644); err != nil {
+		return err
+	}
+	if err := os.WriteFile(base+".down.sql", []byte("-- Rollback: "+title+"\n"), 0o644); err != nil {
+		return err
+	}
+
+	fmt.Printf("Created %s.up.sql and %s.down.sql\n", base, base)
+	return nil
+}
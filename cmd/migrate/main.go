@@ -1,10 +1,20 @@
+// Command migrate applies and manages the database migrations in migrations/
+//
+// Usage:
+//
+//	migrate [-path <dir>] <command> [arguments]
 package main
 
 import (
 	"database/sql"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
@@ -13,49 +23,185 @@ import (
 	_ "github.com/lib/pq"
 )
 
+const usage = `Usage: migrate [-path <dir>] <command> [arguments]
+
+Commands:
+  up [N]           Apply every pending migration, or the next N
+  down N           Roll back the last N migrations
+  goto VERSION     Migrate up or down to VERSION
+  force VERSION    Record VERSION as applied and clean without running anything, after
+                   fixing a failed migration by hand (-1 records none)
+  status           Show the database's version and which migrations are applied
+  create [-seq] NAME
+                   Create empty up and down files versioned by the current UTC time, or
+                   with -seq the next number. Versions only go up: once a timestamp
+                   version is applied, numbered migrations created after it never run.
+
+Commands other than create connect to DATABASE_URL (.env is loaded).
+`
+
 func main() {
-	// Load .env
+	log.SetFlags(0)
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	dir := flag.String("path", "migrations", "Directory holding the migration files")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	command, args := args[0], args[1:]
+	switch command {
+	case "create":
+		if err := create(*dir, args); err != nil {
+			log.Fatal(err)
+		}
+		return
+	case "-h", "--help", "help":
+		fmt.Print(usage)
+		return
+	case "up", "down", "goto", "force", "status":
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
+	}
+
+	m, err := open(*dir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer m.Close()
+
+	// Finish the running migration on Ctrl-C rather than leave the version dirty
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-interrupt
+		log.Println("Stopping after the current migration...")
+		m.GracefulStop <- true
+	}()
+
+	if err := run(m, *dir, command, args); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// open connects to DATABASE_URL with the migrations in dir
+func open(dir string) (*migrate.Migrate, error) {
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found")
 	}
 
 	databaseURL := os.Getenv("DATABASE_URL")
 	if databaseURL == "" {
-		log.Fatal("DATABASE_URL not set")
+		return nil, errors.New("DATABASE_URL not set")
 	}
 
-	// Open database connection
 	db, err := sql.Open("postgres", databaseURL)
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
-	defer db.Close()
 
-	// Create driver instance
 	driver, err := postgres.WithInstance(db, &postgres.Config{})
 	if err != nil {
-		log.Fatalf("Failed to create driver: %v", err)
+		return nil, fmt.Errorf("failed to create driver: %w", err)
 	}
 
-	// Create migrate instance
-	m, err := migrate.NewWithDatabaseInstance(
-		"file://migrations",
-		"postgres",
-		driver,
-	)
+	m, err := migrate.NewWithDatabaseInstance("file://"+dir, "postgres", driver)
 	if err != nil {
-		log.Fatalf("Failed to create migrate instance: %v", err)
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
 	}
+	m.Log = logger{}
+	return m, nil
+}
 
-	// Run migrations
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		log.Fatalf("Failed to run migrations: %v", err)
+// run executes a database command
+func run(m *migrate.Migrate, dir string, command string, args []string) error {
+	var err error
+	switch command {
+	case "up":
+		if len(args) == 0 {
+			err = m.Up()
+			break
+		}
+		var n int
+		if n, err = countArg(args); err == nil {
+			err = m.Steps(n)
+		}
+	case "down":
+		// down needs a count, so a forgotten argument can't roll back the whole schema
+		var n int
+		if n, err = countArg(args); err == nil {
+			err = m.Steps(-n)
+		}
+	case "goto":
+		var version int64
+		if version, err = versionArg(args); err == nil {
+			if version < 0 {
+				return errors.New("VERSION must not be negative")
+			}
+			err = m.Migrate(uint(version))
+		}
+	case "force":
+		var version int64
+		if version, err = versionArg(args); err == nil {
+			err = m.Force(int(version))
+		}
+	case "status":
+		return status(m, dir)
 	}
 
+	if errors.Is(err, migrate.ErrNoChange) {
+		log.Println("No change")
+		err = nil
+	}
+	if err != nil {
+		return err
+	}
+	return printVersion(m)
+}
+
+// countArg parses the single positive count argument of up and down
+func countArg(args []string) (int, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected one argument, the number of migrations")
+	}
+	n, err := strconv.Atoi(args[0])
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("invalid number of migrations %q", args[0])
+	}
+	return n, nil
+}
+
+// versionArg parses the single version argument of goto and force
+func versionArg(args []string) (int64, error) {
+	if len(args) != 1 {
+		return 0, errors.New("expected one argument, the version")
+	}
+	version, err := strconv.ParseInt(args[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q", args[0])
+	}
+	return version, nil
+}
+
+func printVersion(m *migrate.Migrate) error {
 	version, dirty, err := m.Version()
+	if errors.Is(err, migrate.ErrNilVersion) {
+		fmt.Println("No migrations applied")
+		return nil
+	}
 	if err != nil {
-		log.Printf("Migration completed successfully")
-	} else {
-		fmt.Printf("Migration completed successfully. Current version: %d, Dirty: %v\n", version, dirty)
+		return err
 	}
+	fmt.Printf("Current version: %d, Dirty: %v\n", version, dirty)
+	return nil
 }
+
+// logger prints what migrate applies
+type logger struct{}
+
+func (logger) Printf(format string, v ...any) { log.Printf(format, v...) }
+func (logger) Verbose() bool                  { return false }
@@ -0,0 +1,52 @@
+// Command openapi generates the API's OpenAPI document from the router and handlers
+//
+// Usage:
+//
+//	openapi [-root <module directory>] [-o <file>] [-check]
+//
+// go generate ./cmd/api runs it to refresh api/openapi.yaml.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/juan-cantero/fitapi/internal/openapi"
+)
+
+func main() {
+	log.SetFlags(0)
+	root := flag.String("root", ".", "Module directory, the one with go.mod")
+	output := flag.String("o", "api/openapi.yaml", "Document to write, - for stdout")
+	check := flag.Bool("check", false, "Don't write; exit 1 if the document is out of date")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: openapi [flags]\n\nGenerates the OpenAPI 3.1 document from the routes in cmd/api and their handlers.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	var document bytes.Buffer
+	if err := openapi.Generate(*root, &document); err != nil {
+		log.Fatalf("Failed to generate the OpenAPI document: %v", err)
+	}
+
+	switch {
+	case *check:
+		current, err := os.ReadFile(*output)
+		if err != nil {
+			log.Fatalf("Failed to read %s: %v", *output, err)
+		}
+		if !bytes.Equal(current, document.Bytes()) {
+			log.Fatalf("%s is out of date, run go generate ./cmd/api", *output)
+		}
+	case *output == "-":
+		os.Stdout.Write(document.Bytes())
+	default:
+		if err := os.WriteFile(*output, document.Bytes(), 0o644); err != nil {
+			log.Fatalf("Failed to write %s: %v", *output, err)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Command restore loads an archive written by cmd/backup, giving every row a new ID
+//
+// Usage:
+//
+//	restore [-url <database url>] [-to <user>] [-create-users] [-dry-run] <file>
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+
+	"github.com/juan-cantero/fitapi/config"
+	"github.com/juan-cantero/fitapi/internal/backup"
+	"github.com/juan-cantero/fitapi/internal/database"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+func main() {
+	log.SetFlags(0)
+	url := flag.String("url", "", "Database to restore into; defaults to DATABASE_URL (.env is loaded)")
+	to := flag.String("to", "", "Restore a single-user archive into this user, by ID or email, instead of matching emails")
+	createUsers := flag.Bool("create-users", false, "Create users missing from the database, without a password; they sign in by resetting it")
+	dryRun := flag.Bool("dry-run", false, "Read the archive and report what it holds, without connecting")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: restore [flags] <file>\n\nLoads an archive written by cmd/backup. Users are matched by email, each in one\ntransaction; users who already have workouts or sessions are skipped, so a restore\ncan be run again after fixing a failure.\n\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer f.Close()
+	r, err := backup.NewReader(f)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("Archive from %s at schema version %d\n", r.Header.CreatedAt.Format("2006-01-02 15:04 MST"), r.Header.SchemaVersion)
+
+	if *dryRun {
+		if err := summarize(r); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg := config.Load()
+	if *url == "" {
+		*url = cfg.DatabaseURL
+	}
+	if *url == "" {
+		log.Fatal("DATABASE_URL not set")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	db, err := database.New(*url, database.PoolConfig{MaxConns: 2, QueryExecMode: cfg.DBQueryExecMode})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	// Columns are matched by name, so nearby versions restore; missing columns take
+	// their defaults and unknown ones are left out
+	schemaVersion, err := backup.SchemaVersion(ctx, db.Pool)
+	if err != nil {
+		log.Fatalf("Failed to read the schema version: %v", err)
+	}
+	if schemaVersion != r.Header.SchemaVersion {
+		fmt.Printf("Warning: the database is at schema version %d; columns the versions don't share are left out\n", schemaVersion)
+	}
+
+	opts := backup.RestoreOptions{CreateUsers: *createUsers}
+	if *to != "" {
+		user, err := services.NewUserService(repositories.NewPostgresUserRepository(db.Pool)).Find(ctx, *to)
+		if err != nil {
+			log.Fatalf("%s: %v", *to, err)
+		}
+		opts.UserID = user.ID
+	}
+
+	restorer := backup.NewRestorer(db.Pool)
+	restored, skipped := 0, 0
+	for n := 0; ; n++ {
+		archive, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if opts.UserID != "" && n > 0 {
+			log.Fatal("-to takes a single-user archive")
+		}
+
+		result, err := restorer.Restore(ctx, archive, opts)
+		if err != nil {
+			log.Fatalf("%s: %v", archive.Email, err)
+		}
+		if result.Skipped != "" {
+			skipped++
+			fmt.Printf("Skipped %s: %s\n", archive.Email, result.Skipped)
+			continue
+		}
+		restored++
+		fmt.Printf("Restored %s as %s\n", archive.Email, result.UserID)
+		printCounts("inserted", result.Inserted)
+		printCounts("left out, missing what they belong to", result.Orphaned)
+	}
+	fmt.Printf("Restored %d users, skipped %d\n", restored, skipped)
+}
+
+// summarize prints the archive's users and row counts
+func summarize(r *backup.Reader) error {
+	for {
+		archive, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%8d rows  %s\n", archive.Rows(), archive.Email)
+	}
+}
+
+func printCounts(label string, counts map[string]int64) {
+	tables := make([]string, 0, len(counts))
+	for table, n := range counts {
+		if n > 0 {
+			tables = append(tables, table)
+		}
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		fmt.Printf("  %8d %s (%s)\n", counts[table], table, label)
+	}
+}
@@ -0,0 +1,67 @@
+// Command seed loads fixture bundles into the database at DATABASE_URL
+//
+// Usage:
+//
+//	seed [-list] <bundle>...
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/joho/godotenv"
+	"github.com/juan-cantero/fitapi/internal/seed"
+)
+
+func main() {
+	log.SetFlags(0)
+	list := flag.Bool("list", false, "List the bundles")
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "Usage: seed [-list] <bundle>...\n\nLoads fixture bundles and the bundles they require into DATABASE_URL (.env is loaded).\nLoading a bundle again only inserts what is missing.\n\n")
+		printBundles(os.Stderr)
+	}
+	flag.Parse()
+
+	if *list {
+		printBundles(os.Stdout)
+		return
+	}
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	if err := godotenv.Load(); err != nil {
+		log.Println("No .env file found")
+	}
+	databaseURL := os.Getenv("DATABASE_URL")
+	if databaseURL == "" {
+		log.Fatal("DATABASE_URL not set")
+	}
+
+	ctx := context.Background()
+	db, err := pgxpool.New(ctx, databaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+	defer db.Close()
+
+	reports, err := seed.Load(ctx, db, flag.Args()...)
+	for _, report := range reports {
+		fmt.Println(report)
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func printBundles(w *os.File) {
+	fmt.Fprintln(w, "Bundles:")
+	for _, b := range seed.Bundles() {
+		fmt.Fprintf(w, "  %-18s %s\n", b.Name, b.Description)
+	}
+}
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -13,6 +16,122 @@ type Config struct {
 	DatabaseURL string
 	Port        string
 	GinMode     string
+
+	// TrustedProxies are the addresses or CIDR ranges of the load balancers in front of the
+	// API; X-Forwarded-For is only believed when one of them sent it
+	TrustedProxies []string
+
+	// Database connection pool; see database.PoolConfig
+	DBMaxConns               int
+	DBMinConns               int
+	DBMaxConnLifetime        time.Duration
+	DBMaxConnIdleTime        time.Duration
+	DBHealthCheckPeriod      time.Duration
+	DBQueryExecMode          string
+	DBStatementCacheCapacity int
+	DBSlowQueryThreshold     time.Duration
+	DBStatementTimeout       time.Duration
+
+	// RequestTimeout bounds how long an API request may run; event streams and downloads are exempt
+	RequestTimeout time.Duration
+
+	// ShutdownTimeout bounds how long in-flight requests may drain on SIGTERM/SIGINT
+	ShutdownTimeout time.Duration
+
+	// Requests per minute allowed per authenticated user and per anonymous client IP
+	RateLimitUser      int
+	RateLimitAnonymous int
+	// RateLimits are the limits of route groups with their own budget ("group=user:anonymous",
+	// comma separated); other groups share the limits above
+	RateLimits string
+	// RateLimitBackend keeps the rate limit buckets: memory (per instance) or redis (shared
+	// by every instance, at RedisURL)
+	RateLimitBackend string
+
+	// Google Fit OAuth client; the integration is disabled unless all three are set
+	GoogleFitClientID     string
+	GoogleFitClientSecret string
+	GoogleFitRedirectURL  string
+	// GoogleFitSyncInterval is how often connected accounts are synced in the background
+	GoogleFitSyncInterval time.Duration
+
+	// Strava API application; the integration is disabled unless all three are set
+	StravaClientID     string
+	StravaClientSecret string
+	StravaRedirectURL  string
+	// Webhook subscription: the verify token chosen when subscribing and the ID Strava returned;
+	// webhook events are rejected until the ID is set
+	StravaWebhookVerifyToken    string
+	StravaWebhookSubscriptionID int
+	// StravaSyncInterval is how often connected accounts are synced in the background
+	StravaSyncInterval time.Duration
+
+	// WebhookDeliveryInterval is how often recorded events are queued for users' webhook endpoints
+	WebhookDeliveryInterval time.Duration
+
+	// JobWorkers is how many background jobs (emails, account exports, imports, share cards,
+	// webhook deliveries, Strava events and pushes) run at once
+	JobWorkers int
+	// JobPollInterval is how often the job queue is checked for due jobs
+	JobPollInterval time.Duration
+
+	// CronWeeklyReports enables compiling last week's reports on Mondays (UTC), and
+	// emailing them to users who turned weekly summaries on
+	CronWeeklyReports bool
+	// CronTokenRefresh enables reloading the revoked token cache every minute; turn it off
+	// only on single-instance deployments
+	CronTokenRefresh bool
+	// CronAnalyticsRefresh enables refreshing the analytics views every 15 minutes
+	CronAnalyticsRefresh bool
+
+	// FCMCredentialsFile is the Firebase service account key (JSON) for Android push
+	// notifications; Android devices get none without it
+	FCMCredentialsFile string
+	// APNs authentication key for iOS push notifications; disabled unless all four are set
+	APNsKeyFile string // The .p8 key file
+	APNsKeyID   string
+	APNsTeamID  string
+	APNsTopic   string // The iOS app's bundle ID
+	// APNsSandbox sends to the development environment, which development builds register with
+	APNsSandbox bool
+	// PushDeliveryInterval is how often queued push notifications are sent
+	PushDeliveryInterval time.Duration
+
+	// MailProvider sends the API's emails: smtp, resend or sendgrid; no email is sent when empty
+	MailProvider string
+	// MailFrom is the sender address, e.g. "FitAPI <hello@example.com>"
+	MailFrom string
+	// SMTP relay for the smtp provider; port 465 uses TLS, others STARTTLS
+	SMTPHost     string
+	SMTPPort     int
+	SMTPUsername string
+	SMTPPassword string
+	// API keys for the resend and sendgrid providers
+	ResendAPIKey   string
+	SendGridAPIKey string
+
+	// SLOTargets are response time objectives per route group ("group=threshold:percent", comma
+	// separated; "default" covers the other groups)
+	SLOTargets string
+	// Alert webhook for SLO burn rate alerts; alerts are only logged without a URL
+	SLOAlertWebhookURL    string
+	SLOAlertWebhookSecret string
+
+	// FeatureFlags are the defaults of flags not edited at runtime ("name=on|off|percent",
+	// comma separated)
+	FeatureFlags string
+
+	// CacheBackend caches exercise library and analytics reads: memory or redis; reads go
+	// to the database every time when empty
+	CacheBackend string
+	// RedisURL is the server of the redis cache and rate limit backends, e.g. redis://:password@localhost:6379/0
+	RedisURL string
+	// CacheMaxEntries bounds the memory backend
+	CacheMaxEntries int
+
+	// ShareCardSecret signs the download links of session share cards; when empty a random
+	// secret is used, so links break on restart and only work on the instance that issued them
+	ShareCardSecret string
 }
 
 func Load() *Config {
@@ -26,7 +145,78 @@ func Load() *Config {
 		SupabaseKey: getEnv("SUPABASE_KEY", ""),
 		DatabaseURL: getEnv("DATABASE_URL", ""),
 		Port:        getEnv("PORT", "8080"),
-		GinMode:     getEnv("GIN_MODE", "debug"),
+
+		TrustedProxies: getEnvList("TRUSTED_PROXIES"),
+		GinMode:        getEnv("GIN_MODE", "debug"),
+
+		DBMaxConns:               getEnvInt("DB_MAX_CONNS", 10),
+		DBMinConns:               getEnvInt("DB_MIN_CONNS", 2),
+		DBMaxConnLifetime:        getEnvDuration("DB_MAX_CONN_LIFETIME", 0),
+		DBMaxConnIdleTime:        getEnvDuration("DB_MAX_CONN_IDLE_TIME", 30*time.Minute),
+		DBHealthCheckPeriod:      getEnvDuration("DB_HEALTH_CHECK_PERIOD", time.Minute),
+		DBQueryExecMode:          getEnv("DB_QUERY_EXEC_MODE", ""),
+		DBStatementCacheCapacity: getEnvInt("DB_STATEMENT_CACHE_CAPACITY", 0),
+		DBSlowQueryThreshold:     getEnvDuration("DB_SLOW_QUERY_THRESHOLD", 500*time.Millisecond),
+		DBStatementTimeout:       getEnvDuration("DB_STATEMENT_TIMEOUT", 30*time.Second),
+
+		RequestTimeout: getEnvDuration("REQUEST_TIMEOUT", time.Minute),
+
+		ShutdownTimeout: getEnvDuration("SHUTDOWN_TIMEOUT", 15*time.Second),
+
+		RateLimitUser:      getEnvInt("RATE_LIMIT_USER", 120),
+		RateLimitAnonymous: getEnvInt("RATE_LIMIT_ANONYMOUS", 30),
+		RateLimits:         getEnv("RATE_LIMITS", ""),
+		RateLimitBackend:   getEnv("RATE_LIMIT_BACKEND", "memory"),
+
+		GoogleFitClientID:     getEnv("GOOGLE_FIT_CLIENT_ID", ""),
+		GoogleFitClientSecret: getEnv("GOOGLE_FIT_CLIENT_SECRET", ""),
+		GoogleFitRedirectURL:  getEnv("GOOGLE_FIT_REDIRECT_URL", ""),
+		GoogleFitSyncInterval: getEnvDuration("GOOGLE_FIT_SYNC_INTERVAL", 6*time.Hour),
+
+		StravaClientID:              getEnv("STRAVA_CLIENT_ID", ""),
+		StravaClientSecret:          getEnv("STRAVA_CLIENT_SECRET", ""),
+		StravaRedirectURL:           getEnv("STRAVA_REDIRECT_URL", ""),
+		StravaWebhookVerifyToken:    getEnv("STRAVA_WEBHOOK_VERIFY_TOKEN", ""),
+		StravaWebhookSubscriptionID: getEnvInt("STRAVA_WEBHOOK_SUBSCRIPTION_ID", 0),
+		StravaSyncInterval:          getEnvDuration("STRAVA_SYNC_INTERVAL", 6*time.Hour),
+
+		WebhookDeliveryInterval: getEnvDuration("WEBHOOK_DELIVERY_INTERVAL", 15*time.Second),
+
+		JobWorkers:      getEnvInt("JOB_WORKERS", 4),
+		JobPollInterval: getEnvDuration("JOB_POLL_INTERVAL", 2*time.Second),
+
+		CronWeeklyReports:    getEnvBool("CRON_WEEKLY_REPORTS", true),
+		CronTokenRefresh:     getEnvBool("CRON_TOKEN_REFRESH", true),
+		CronAnalyticsRefresh: getEnvBool("CRON_ANALYTICS_REFRESH", true),
+
+		FCMCredentialsFile:   getEnv("FCM_CREDENTIALS_FILE", ""),
+		APNsKeyFile:          getEnv("APNS_KEY_FILE", ""),
+		APNsKeyID:            getEnv("APNS_KEY_ID", ""),
+		APNsTeamID:           getEnv("APNS_TEAM_ID", ""),
+		APNsTopic:            getEnv("APNS_TOPIC", ""),
+		APNsSandbox:          getEnvBool("APNS_SANDBOX", false),
+		PushDeliveryInterval: getEnvDuration("PUSH_DELIVERY_INTERVAL", 10*time.Second),
+
+		MailProvider:   getEnv("MAIL_PROVIDER", ""),
+		MailFrom:       getEnv("MAIL_FROM", ""),
+		SMTPHost:       getEnv("SMTP_HOST", ""),
+		SMTPPort:       getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:   getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:   getEnv("SMTP_PASSWORD", ""),
+		ResendAPIKey:   getEnv("RESEND_API_KEY", ""),
+		SendGridAPIKey: getEnv("SENDGRID_API_KEY", ""),
+
+		SLOTargets:            getEnv("SLO_TARGETS", "default=1s:99"),
+		SLOAlertWebhookURL:    getEnv("SLO_ALERT_WEBHOOK_URL", ""),
+		SLOAlertWebhookSecret: getEnv("SLO_ALERT_WEBHOOK_SECRET", ""),
+
+		FeatureFlags: getEnv("FEATURE_FLAGS", ""),
+
+		CacheBackend:    getEnv("CACHE_BACKEND", ""),
+		RedisURL:        getEnv("REDIS_URL", ""),
+		CacheMaxEntries: getEnvInt("CACHE_MAX_ENTRIES", 10000),
+
+		ShareCardSecret: getEnv("SHARE_CARD_SECRET", ""),
 	}
 }
 
@@ -36,3 +226,56 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvList reads a comma separated list, dropping empty entries
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("Invalid duration for %s (%q), using default %s", key, value, defaultValue)
+		return defaultValue
+	}
+	return duration
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("Invalid integer for %s (%q), using default %d", key, value, defaultValue)
+		return defaultValue
+	}
+	return n
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		log.Printf("Invalid boolean for %s (%q), using default %t", key, value, defaultValue)
+		return defaultValue
+	}
+	return b
+}
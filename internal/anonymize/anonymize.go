@@ -0,0 +1,283 @@
+// Package anonymize scrambles personal data in a copy of the production database so it
+// can be used in staging.
+//
+// Identities and free text are replaced while keys, numbers and timestamps are kept, so
+// relationships and the shape of the data (row counts, distributions, text lengths, which
+// values are missing) stay as they were:
+//
+//   - Emails become user-<hash>@example.invalid. The hash is keyed per run, so the same
+//     address maps to the same replacement in every table but can't be looked up.
+//   - Names and notes become filler text of the same length.
+//   - Credentials, queued work and delivery targets (integrations, webhooks, device
+//     tokens, emails and jobs) are deleted, so staging never acts on behalf of real users.
+//
+// Every table is reviewed: a table is either touched by a step or listed in keptTables.
+package anonymize
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// filler replaces free text, cut at a random offset to the original's length
+const filler = "lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor " +
+	"incididunt ut labore et dolore magna aliqua ut enim ad minim veniam quis nostrud " +
+	"exercitation ullamco laboris nisi ut aliquip ex ea commodo consequat duis aute irure " +
+	"dolor in reprehenderit in voluptate velit esse cillum dolore eu fugiat nulla pariatur " +
+	"excepteur sint occaecat cupidatat non proident sunt in culpa qui officia deserunt " +
+	"mollit anim id est laborum "
+
+// Options tune a run
+type Options struct {
+	// Salt keys the email hashes; empty picks a random one, so runs can't be correlated
+	Salt string
+	// Password is set for every user so testers can sign in as anyone; empty disables
+	// password sign-in
+	Password string
+}
+
+// Step is one statement of the run
+type Step struct {
+	Table         string
+	Description   string
+	sql           string
+	takesPassword bool // The statement takes the password as $1
+}
+
+// StepResult is how many rows a step changed
+type StepResult struct {
+	Step *Step
+	Rows int64
+}
+
+// steps run in order; auth.users comes first as identities copy the new emails
+var steps = []*Step{
+	{
+		Table:       "auth.users",
+		Description: "hash emails, drop phones, profile metadata and pending changes, reset passwords",
+		sql: `
+			UPDATE auth.users SET
+				email = pg_temp.anon_email(email),
+				phone = NULL,
+				raw_user_meta_data = '{}'::jsonb,
+				encrypted_password = CASE WHEN $1 = '' THEN '' ELSE crypt($1, gen_salt('bf')) END,
+				email_change = '', phone_change = '',
+				confirmation_token = '', recovery_token = '', email_change_token_new = '', email_change_token_current = ''`,
+		takesPassword: true,
+	},
+	{
+		Table:       "auth.identities",
+		Description: "replace identity data with the hashed email",
+		sql: `
+			UPDATE auth.identities i
+			SET identity_data = jsonb_build_object('sub', i.user_id::text, 'email', u.email)
+			FROM auth.users u
+			WHERE u.id = i.user_id`,
+	},
+	{Table: "auth.refresh_tokens", Description: "delete", sql: `DELETE FROM auth.refresh_tokens`},
+	{Table: "auth.sessions", Description: "delete", sql: `DELETE FROM auth.sessions`},
+	{Table: "auth.audit_log_entries", Description: "delete (IPs and emails)", sql: `DELETE FROM auth.audit_log_entries`},
+
+	{Table: "coach_clients", Description: "hash invited emails", sql: `UPDATE coach_clients SET client_email = pg_temp.anon_email(client_email)`},
+	{
+		Table:       "equipment",
+		Description: "scramble names and descriptions",
+		sql:         `UPDATE equipment SET name = pg_temp.scramble(name), description = pg_temp.scramble(description)`,
+	},
+	{
+		// The public library is visible to everyone already
+		Table:       "exercises",
+		Description: "scramble private exercises' names and descriptions, drop their images",
+		sql: `
+			UPDATE exercises
+			SET name = pg_temp.scramble(name), description = pg_temp.scramble(description), image_url = NULL
+			WHERE NOT is_public`,
+	},
+	{
+		Table:       "workouts",
+		Description: "scramble names and descriptions, drop images",
+		sql:         `UPDATE workouts SET name = pg_temp.scramble(name), description = pg_temp.scramble(description), image_url = NULL`,
+	},
+	{Table: "workout_exercises", Description: "scramble notes", sql: `UPDATE workout_exercises SET notes = pg_temp.scramble(notes) WHERE notes IS NOT NULL`},
+	{
+		Table:       "workout_sessions",
+		Description: "scramble names, places, moods and notes, hash provider activity IDs",
+		sql: `
+			UPDATE workout_sessions SET
+				name = pg_temp.scramble(name),
+				location = pg_temp.scramble(location),
+				weather_conditions = pg_temp.scramble(weather_conditions),
+				mood_before = pg_temp.scramble(mood_before),
+				mood_after = pg_temp.scramble(mood_after),
+				notes = pg_temp.scramble(notes),
+				external_id = pg_temp.anon_key(external_id)`,
+	},
+	{
+		Table:       "exercise_logs",
+		Description: "scramble notes and skip reasons",
+		sql: `
+			UPDATE exercise_logs SET notes = pg_temp.scramble(notes), skip_reason = pg_temp.scramble(skip_reason)
+			WHERE notes IS NOT NULL OR skip_reason IS NOT NULL`,
+	},
+	{
+		Table:       "organizations",
+		Description: "scramble names and descriptions",
+		sql:         `UPDATE organizations SET name = pg_temp.scramble(name), description = pg_temp.scramble(description)`,
+	},
+	{Table: "class_sessions", Description: "scramble names", sql: `UPDATE class_sessions SET name = pg_temp.scramble(name)`},
+	{
+		Table:       "coach_programs",
+		Description: "scramble names and notes",
+		sql:         `UPDATE coach_programs SET name = pg_temp.scramble(name), notes = pg_temp.scramble(notes)`,
+	},
+	{
+		Table:       "program_workouts",
+		Description: "scramble skip reasons",
+		sql:         `UPDATE program_workouts SET skip_reason = pg_temp.scramble(skip_reason) WHERE skip_reason IS NOT NULL`,
+	},
+	{
+		Table:       "challenges",
+		Description: "scramble names and descriptions",
+		sql:         `UPDATE challenges SET name = pg_temp.scramble(name), description = pg_temp.scramble(description)`,
+	},
+	{
+		Table:       "session_media",
+		Description: "point at placeholder URLs, scramble captions",
+		sql: `
+			UPDATE session_media SET
+				url = 'https://example.invalid/media/' || id,
+				thumbnail_url = CASE WHEN thumbnail_url IS NOT NULL THEN 'https://example.invalid/media/' || id || '/thumbnail' END,
+				caption = pg_temp.scramble(caption)`,
+	},
+	{Table: "exercise_swaps", Description: "scramble reasons", sql: `UPDATE exercise_swaps SET reason = pg_temp.scramble(reason) WHERE reason IS NOT NULL`},
+	{Table: "reminder_rules", Description: "scramble names", sql: `UPDATE reminder_rules SET name = pg_temp.scramble(name)`},
+	{Table: "injuries", Description: "scramble names and notes", sql: `UPDATE injuries SET name = pg_temp.scramble(name), notes = pg_temp.scramble(notes)`},
+	{Table: "comments", Description: "scramble bodies", sql: `UPDATE comments SET body = pg_temp.scramble(body)`},
+	{
+		Table:       "content_reports",
+		Description: "scramble details",
+		sql:         `UPDATE content_reports SET details = pg_temp.scramble(details) WHERE details IS NOT NULL`,
+	},
+	{
+		Table:       "public_profiles",
+		Description: "replace usernames with hashes, scramble display names",
+		sql:         `UPDATE public_profiles SET username = 'user_' || left(md5(user_id::text), 16), display_name = pg_temp.scramble(display_name)`,
+	},
+	{
+		Table:       "notifications",
+		Description: "scramble titles and bodies",
+		sql:         `UPDATE notifications SET title = pg_temp.scramble(title), body = pg_temp.scramble(body)`,
+	},
+
+	{Table: "account_exports", Description: "delete (full copies of users' data)", sql: `DELETE FROM account_exports`},
+	{Table: "share_cards", Description: "delete (rendered images of real sessions)", sql: `DELETE FROM share_cards`},
+	{Table: "imports", Description: "delete (copies of users' exports from other apps)", sql: `DELETE FROM imports`},
+	{Table: "integration_pushes", Description: "delete", sql: `DELETE FROM integration_pushes`},
+	{Table: "integration_connections", Description: "delete (provider credentials)", sql: `DELETE FROM integration_connections`},
+	{Table: "webhook_deliveries", Description: "delete", sql: `DELETE FROM webhook_deliveries`},
+	{Table: "webhook_events", Description: "delete", sql: `DELETE FROM webhook_events`},
+	{Table: "webhook_endpoints", Description: "delete (customers' URLs and secrets)", sql: `DELETE FROM webhook_endpoints`},
+	{Table: "push_notifications", Description: "delete", sql: `DELETE FROM push_notifications`},
+	{Table: "device_tokens", Description: "delete (real devices)", sql: `DELETE FROM device_tokens`},
+	{Table: "jobs", Description: "delete (queued emails, exports and deliveries)", sql: `DELETE FROM jobs`},
+	{Table: "revoked_tokens", Description: "delete", sql: `DELETE FROM revoked_tokens`},
+	{Table: "idempotency_keys", Description: "delete (copies of requests' responses)", sql: `DELETE FROM idempotency_keys`},
+	{Table: "audit_logs", Description: "delete (copies of changed fields)", sql: `DELETE FROM audit_logs`},
+}
+
+// keptTables hold nothing identifying beyond user IDs: numbers, dates, settings and links
+var keptTables = []string{
+	"activity_events",
+	"activity_reactions",
+	"body_measurements",
+	"challenge_completions",
+	"challenge_participants",
+	"email_preferences",
+	"exercise_equipment",
+	"feature_flag_overrides",
+	"feature_flags",
+	"goals",
+	"hydration_goals",
+	"metric_snapshots",
+	"muscle_group_body_parts",
+	"notification_preferences",
+	"organization_members",
+	"session_laps",
+	"session_types",
+	"sleep_logs",
+	"training_maxes",
+	"user_follows",
+	"user_profiles",
+	"water_logs",
+	"weekly_reports",
+}
+
+// Steps lists what a run does, in order
+func Steps() []*Step {
+	return steps
+}
+
+// Run anonymizes the database in the transaction. Webhooks and notifications triggered
+// by the updates are suppressed. The caller commits, or rolls back for a dry run.
+func Run(ctx context.Context, tx pgx.Tx, opts Options) ([]*StepResult, error) {
+	if opts.Salt == "" {
+		salt, err := randomSalt()
+		if err != nil {
+			return nil, err
+		}
+		opts.Salt = salt
+	}
+
+	setup := []struct {
+		sql  string
+		args []any
+	}{
+		{`SELECT set_config('fitapi.suppress_webhooks', 'on', true)`, nil},
+		{`SELECT set_config('fitapi.anonymize_salt', $1, true)`, []any{opts.Salt}},
+		{`
+			CREATE FUNCTION pg_temp.anon_email(e text) RETURNS text LANGUAGE sql VOLATILE AS $$
+				SELECT CASE WHEN e IS NULL OR e = '' THEN e
+				ELSE 'user-' || left(md5(current_setting('fitapi.anonymize_salt') || lower(e)), 16) || '@example.invalid' END
+			$$`, nil},
+		{`
+			CREATE FUNCTION pg_temp.anon_key(k text) RETURNS text LANGUAGE sql VOLATILE AS $$
+				SELECT CASE WHEN k IS NULL THEN k ELSE md5(current_setting('fitapi.anonymize_salt') || k) END
+			$$`, nil},
+		{fmt.Sprintf(`
+			CREATE FUNCTION pg_temp.scramble(t text) RETURNS text LANGUAGE sql VOLATILE AS $$
+				SELECT CASE WHEN t IS NULL OR t = '' THEN t
+				ELSE substr(repeat('%s', length(t) / %d + 2), 1 + floor(random() * %d)::int, length(t)) END
+			$$`, filler, len(filler), len(filler)), nil},
+	}
+	for _, s := range setup {
+		if _, err := tx.Exec(ctx, s.sql, s.args...); err != nil {
+			return nil, fmt.Errorf("failed to prepare: %w", err)
+		}
+	}
+
+	results := make([]*StepResult, 0, len(steps))
+	for _, step := range steps {
+		var args []any
+		if step.takesPassword {
+			args = []any{opts.Password}
+		}
+		tag, err := tx.Exec(ctx, step.sql, args...)
+		if err != nil {
+			return results, fmt.Errorf("failed to anonymize %s: %w", step.Table, err)
+		}
+		results = append(results, &StepResult{Step: step, Rows: tag.RowsAffected()})
+	}
+	return results, nil
+}
+
+func randomSalt() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,61 @@
+package anonymize
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// TestEveryTableReviewed fails when a migration adds a table that is neither anonymized
+// nor listed as kept, so new personal data can't slip into staging unnoticed
+func TestEveryTableReviewed(t *testing.T) {
+	reviewed := make(map[string]bool)
+	for _, s := range steps {
+		reviewed[s.Table] = true
+	}
+	for _, table := range keptTables {
+		if reviewed[table] {
+			t.Errorf("Table %s is both anonymized and kept", table)
+		}
+		reviewed[table] = true
+	}
+
+	files, err := filepath.Glob("../../migrations/*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Expected migrations, got %v", err)
+	}
+	createTable := regexp.MustCompile(`(?i)CREATE TABLE (?:IF NOT EXISTS )?(\w+)`)
+	dropTable := regexp.MustCompile(`(?i)DROP TABLE (?:IF EXISTS )?(\w+)`)
+	tables := make(map[string]bool)
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, m := range createTable.FindAllStringSubmatch(string(data), -1) {
+			tables[strings.ToLower(m[1])] = true
+		}
+		for _, m := range dropTable.FindAllStringSubmatch(string(data), -1) {
+			delete(tables, strings.ToLower(m[1]))
+		}
+	}
+
+	for table := range tables {
+		if !reviewed[table] {
+			t.Errorf("Table %s is neither anonymized nor in keptTables", table)
+		}
+	}
+	for table := range reviewed {
+		if !strings.HasPrefix(table, "auth.") && !tables[table] {
+			t.Errorf("Reviewed table %s is not created by any migration", table)
+		}
+	}
+}
+
+func TestFillerIsQuotable(t *testing.T) {
+	if strings.ContainsAny(filler, `'$\`) {
+		t.Error("Expected the filler to be safe inside a quoted SQL literal")
+	}
+}
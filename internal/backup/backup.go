@@ -0,0 +1,243 @@
+// Package backup writes users' data to a portable archive and restores it into another
+// database, for account migrations between Supabase projects and recovery drills.
+//
+// An archive is gzipped JSON lines: a header, then one line per user holding their rows
+// table by table, as the database renders them. Restoring gives every row a new ID and
+// rewrites references to match, so an archive can be loaded next to existing data, and
+// matches users by email. Accounts, organizations, coaching relationships and programs,
+// challenges and integrations aren't included: they involve other users or credentials.
+package backup
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	// format identifies archives in their header
+	format = "fitapi-backup"
+
+	// version is bumped on changes older restores can't read
+	version = 1
+
+	// maxLineBytes bounds one user's line when reading an archive
+	maxLineBytes = 1 << 30
+)
+
+// Header is the first line of an archive
+type Header struct {
+	Format        string    `json:"format"`
+	Version       int       `json:"version"`
+	CreatedAt     time.Time `json:"created_at"`
+	SchemaVersion int64     `json:"schema_version"` // Latest migration of the source database
+}
+
+// UserArchive is one user's data
+type UserArchive struct {
+	UserID string `json:"user_id"`
+	Email  string `json:"email"`
+
+	// Tables holds the user's rows by table
+	Tables map[string][]map[string]any `json:"tables"`
+
+	// ReferencedExercises are other users' public exercises the user's rows point at,
+	// matched by name on restore
+	ReferencedExercises []map[string]any `json:"referenced_exercises"`
+}
+
+// Rows counts the archive's rows
+func (a *UserArchive) Rows() int {
+	n := 0
+	for _, rows := range a.Tables {
+		n += len(rows)
+	}
+	return n
+}
+
+// Writer writes an archive
+type Writer struct {
+	gz *gzip.Writer
+	w  *bufio.Writer
+}
+
+// NewWriter starts an archive, writing its header
+func NewWriter(w io.Writer, header Header) (*Writer, error) {
+	header.Format = format
+	header.Version = version
+
+	gz := gzip.NewWriter(w)
+	aw := &Writer{gz: gz, w: bufio.NewWriter(gz)}
+	if err := aw.writeLine(header); err != nil {
+		return nil, err
+	}
+	return aw, nil
+}
+
+// Write adds a user
+func (w *Writer) Write(user *UserArchive) error {
+	return w.writeLine(user)
+}
+
+// Close flushes the archive; the underlying writer stays open
+func (w *Writer) Close() error {
+	if err := w.w.Flush(); err != nil {
+		return err
+	}
+	return w.gz.Close()
+}
+
+func (w *Writer) writeLine(v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	if _, err := w.w.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reader reads an archive
+type Reader struct {
+	Header  Header
+	scanner *bufio.Scanner
+}
+
+// NewReader opens an archive, checking its header
+func NewReader(r io.Reader) (*Reader, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("not a backup archive: %w", err)
+	}
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineBytes)
+
+	ar := &Reader{scanner: scanner}
+	if !scanner.Scan() {
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, errors.New("empty archive")
+	}
+	if err := json.Unmarshal(scanner.Bytes(), &ar.Header); err != nil || ar.Header.Format != format {
+		return nil, errors.New("not a backup archive")
+	}
+	if ar.Header.Version > version {
+		return nil, fmt.Errorf("archive version %d is newer than this restore supports (%d)", ar.Header.Version, version)
+	}
+	return ar, nil
+}
+
+// Next returns the next user, or io.EOF after the last. Numbers are kept as written.
+func (r *Reader) Next() (*UserArchive, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return nil, err
+		}
+		return nil, io.EOF
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(r.scanner.Bytes()))
+	decoder.UseNumber()
+	user := &UserArchive{}
+	if err := decoder.Decode(user); err != nil {
+		return nil, fmt.Errorf("invalid user in archive: %w", err)
+	}
+	return user, nil
+}
+
+// SchemaVersion returns the database's latest applied migration
+func SchemaVersion(ctx context.Context, db *pgxpool.Pool) (int64, error) {
+	var v int64
+	err := db.QueryRow(ctx, `SELECT version FROM schema_migrations`).Scan(&v)
+	return v, err
+}
+
+// UserIDs lists every user, oldest first
+func UserIDs(ctx context.Context, db *pgxpool.Pool) ([]string, error) {
+	rows, err := db.Query(ctx, `SELECT id FROM auth.users ORDER BY created_at, id`)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// Backup reads a user's data from a consistent snapshot
+func Backup(ctx context.Context, db *pgxpool.Pool, userID string) (*UserArchive, error) {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	archive := &UserArchive{UserID: userID, Tables: make(map[string][]map[string]any)}
+	err = tx.QueryRow(ctx, `SELECT COALESCE(email, '') FROM auth.users WHERE id = $1`, userID).Scan(&archive.Email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user %s: %w", userID, err)
+	}
+
+	for _, t := range tables {
+		query := `SELECT to_jsonb(t) FROM ` + t.name + ` t ` + t.scope + ` ORDER BY ` + t.order
+		rows, err := readRows(ctx, tx, query, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", t.name, err)
+		}
+		if len(rows) > 0 {
+			archive.Tables[t.name] = rows
+		}
+	}
+
+	// Library exercises the user trained or planned with
+	referencedQuery := `
+		SELECT jsonb_build_object('id', e.id, 'name', e.name, 'description', e.description, 'muscle_group', e.muscle_group)
+		FROM exercises e
+		WHERE e.user_id <> $1 AND e.id IN (
+			SELECT l.exercise_id FROM exercise_logs l JOIN workout_sessions s ON s.id = l.workout_session_id WHERE s.user_id = $1
+			UNION SELECT x.exercise_id FROM workout_exercises x JOIN workouts w ON w.id = x.workout_id WHERE w.user_id = $1
+			UNION SELECT exercise_id FROM training_maxes WHERE user_id = $1
+			UNION SELECT from_exercise_id FROM exercise_swaps WHERE user_id = $1
+			UNION SELECT to_exercise_id FROM exercise_swaps WHERE user_id = $1
+		)
+		ORDER BY e.name
+	`
+	if archive.ReferencedExercises, err = readRows(ctx, tx, referencedQuery, userID); err != nil {
+		return nil, fmt.Errorf("failed to read referenced exercises: %w", err)
+	}
+
+	return archive, nil
+}
+
+// readRows decodes a query's single JSON column, keeping numbers as written
+func readRows(ctx context.Context, tx pgx.Tx, query string, args ...any) ([]map[string]any, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := []map[string]any{}
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		decoder := json.NewDecoder(bytes.NewReader(raw))
+		decoder.UseNumber()
+		row := make(map[string]any)
+		if err := decoder.Decode(&row); err != nil {
+			return nil, err
+		}
+		result = append(result, row)
+	}
+	return result, rows.Err()
+}
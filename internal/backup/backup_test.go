@@ -0,0 +1,168 @@
+package backup
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+)
+
+func sequentialIDs() func() string {
+	n := 0
+	return func() string {
+		n++
+		return fmt.Sprintf("new-%d", n)
+	}
+}
+
+func tableNamed(t *testing.T, name string) *tableSpec {
+	for _, s := range tables {
+		if s.name == name {
+			return s
+		}
+	}
+	t.Fatalf("No table %s", name)
+	return nil
+}
+
+func TestRemapRewritesReferences(t *testing.T) {
+	ids := idMap{"old-user": "user", "old-session": "session", "old-log": "log", "old-squat": "squat"}
+	newID := sequentialIDs()
+
+	swap := map[string]any{
+		"id": "old-swap", "user_id": "old-user", "workout_session_id": "old-session", "exercise_log_id": "old-log",
+		"from_exercise_id": "old-squat", "to_exercise_id": "gone", "reason": "knee", "updated_at": "2026-01-01T00:00:00Z",
+	}
+	if !tableNamed(t, "exercise_swaps").remap(swap, ids, "user", newID) {
+		t.Fatal("Expected the swap to be restored")
+	}
+
+	want := map[string]any{
+		"id": "new-1", "user_id": "user", "workout_session_id": "session", "exercise_log_id": "log",
+		"from_exercise_id": "squat", "to_exercise_id": nil, "reason": "knee",
+	}
+	for column, value := range want {
+		if swap[column] != value {
+			t.Errorf("Expected %s %v, got %v", column, value, swap[column])
+		}
+	}
+	if _, ok := swap["updated_at"]; ok {
+		t.Error("Expected updated_at to be dropped")
+	}
+	if ids["old-swap"] != "new-1" {
+		t.Errorf("Expected the new ID to be recorded, got %q", ids["old-swap"])
+	}
+}
+
+func TestRemapSkipsOrphans(t *testing.T) {
+	ids := idMap{}
+	log := map[string]any{"id": "old-log", "workout_session_id": "missing", "exercise_id": "missing"}
+	if tableNamed(t, "exercise_logs").remap(log, ids, "user", sequentialIDs()) {
+		t.Error("Expected a log without its session to be skipped")
+	}
+	if _, ok := ids["old-log"]; ok {
+		t.Error("Expected no ID for a skipped row")
+	}
+}
+
+func TestRemapDropsOrganizations(t *testing.T) {
+	workout := map[string]any{"id": "old", "user_id": "old-user", "organization_id": "org"}
+	if !tableNamed(t, "workouts").remap(workout, idMap{}, "user", sequentialIDs()) {
+		t.Fatal("Expected the workout to be restored")
+	}
+	if workout["organization_id"] != nil {
+		t.Errorf("Expected no organization, got %v", workout["organization_id"])
+	}
+}
+
+func TestRemapIDList(t *testing.T) {
+	ids := idMap{"a": "1", "b": "2"}
+	if got := remapIDList(`["a","missing","b"]`, ids); got != `["1","2"]` {
+		t.Errorf("Expected [\"1\",\"2\"], got %v", got)
+	}
+	if got := remapIDList("barbell", ids); got != "barbell" {
+		t.Errorf("Expected text that isn't a list to be kept, got %v", got)
+	}
+}
+
+// TestTablesReferenceEarlierTables checks parents are restored before their children
+func TestTablesReferenceEarlierTables(t *testing.T) {
+	parents := map[string]string{
+		"exercise_id": "exercises", "equipment_id": "equipment", "workout_id": "workouts",
+		"workout_exercise_id": "workout_exercises", "workout_session_id": "workout_sessions",
+		"exercise_log_id": "exercise_logs", "from_exercise_id": "exercises", "to_exercise_id": "exercises",
+	}
+	seen := make(map[string]bool)
+	for _, s := range tables {
+		for column, kind := range s.refs {
+			if kind != refRequired && kind != refOptional {
+				continue
+			}
+			parent, ok := parents[column]
+			if !ok {
+				t.Errorf("%s.%s: unknown reference", s.name, column)
+			} else if !seen[parent] {
+				t.Errorf("%s.%s: %s is restored later", s.name, column, parent)
+			}
+		}
+		seen[s.name] = true
+	}
+}
+
+func TestArchiveRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	createdAt := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	w, err := NewWriter(&buf, Header{CreatedAt: createdAt, SchemaVersion: 37})
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &UserArchive{
+		UserID: "u1", Email: "a@example.com",
+		Tables: map[string][]map[string]any{"body_measurements": {{"weight_kg": json.Number("81.25")}}},
+	}
+	if err := w.Write(user); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewReader(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Header.Format != format || r.Header.Version != version || r.Header.SchemaVersion != 37 || !r.Header.CreatedAt.Equal(createdAt) {
+		t.Errorf("Unexpected header %+v", r.Header)
+	}
+
+	got, err := r.Next()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Email != "a@example.com" || got.Rows() != 1 {
+		t.Errorf("Unexpected user %+v", got)
+	}
+	if weight := got.Tables["body_measurements"][0]["weight_kg"]; weight != json.Number("81.25") {
+		t.Errorf("Expected the weight as written, got %#v", weight)
+	}
+	if _, err := r.Next(); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestReaderRejectsOtherFiles(t *testing.T) {
+	if _, err := NewReader(bytes.NewReader([]byte("{}"))); err == nil {
+		t.Error("Expected an error for an uncompressed file")
+	}
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	fmt.Fprintf(gz, `{"format":%q,"version":%d}`+"\n", format, version+1)
+	gz.Close()
+	if _, err := NewReader(&buf); err == nil {
+		t.Error("Expected an error for a newer version")
+	}
+}
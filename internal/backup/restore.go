@@ -0,0 +1,265 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// RestoreOptions tune a restore
+type RestoreOptions struct {
+	// UserID restores into this user instead of the one with the archive's email
+	UserID string
+	// CreateUsers creates users missing from the target, without a password: they sign
+	// in by resetting it
+	CreateUsers bool
+}
+
+// RestoreResult reports how a user's restore went
+type RestoreResult struct {
+	Email    string
+	UserID   string // In the target; empty when skipped before it was resolved
+	Skipped  string // Why nothing was restored, if so
+	Inserted map[string]int64
+	Orphaned map[string]int64 // Rows left out as a row they need wasn't restored
+}
+
+// Restorer loads archives into a database
+type Restorer struct {
+	db      *pgxpool.Pool
+	columns map[string]map[string]bool // Insertable columns by table
+	newID   func() string
+}
+
+// NewRestorer creates a restorer into db
+func NewRestorer(db *pgxpool.Pool) *Restorer {
+	return &Restorer{db: db, columns: make(map[string]map[string]bool), newID: uuid.NewString}
+}
+
+// Restore loads a user's archive in one transaction. Users who already have exercises,
+// workouts or sessions in the target are skipped, so running a restore twice doesn't
+// duplicate anyone's history.
+func (r *Restorer) Restore(ctx context.Context, archive *UserArchive, opts RestoreOptions) (*RestoreResult, error) {
+	result := &RestoreResult{Email: archive.Email, Inserted: make(map[string]int64), Orphaned: make(map[string]int64)}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Restored history isn't news to webhook subscribers or notification recipients
+	if _, err := tx.Exec(ctx, `SELECT set_config('fitapi.suppress_webhooks', 'on', true)`); err != nil {
+		return nil, err
+	}
+
+	userID, err := r.targetUser(ctx, tx, archive, opts, result)
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return result, nil
+	}
+	result.UserID = userID
+
+	var hasData bool
+	hasDataQuery := `
+		SELECT EXISTS (SELECT 1 FROM exercises WHERE user_id = $1)
+		    OR EXISTS (SELECT 1 FROM workouts WHERE user_id = $1)
+		    OR EXISTS (SELECT 1 FROM workout_sessions WHERE user_id = $1)
+	`
+	if err := tx.QueryRow(ctx, hasDataQuery, userID).Scan(&hasData); err != nil {
+		return nil, err
+	}
+	if hasData {
+		result.Skipped = "the user already has data"
+		return result, nil
+	}
+
+	ids := idMap{archive.UserID: userID}
+	aliases, err := r.mapReferencedExercises(ctx, tx, archive, userID, ids, result)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range tables {
+		if err := r.restoreTable(ctx, tx, t, archive.Tables[t.name], userID, ids, result); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", t.name, err)
+		}
+		if t.name == "exercises" {
+			for referencedID, ownID := range aliases {
+				ids[referencedID] = ids[ownID]
+			}
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// targetUser finds the user to restore into, or returns "" with the reason recorded
+func (r *Restorer) targetUser(ctx context.Context, tx pgx.Tx, archive *UserArchive, opts RestoreOptions, result *RestoreResult) (string, error) {
+	var id string
+	var err error
+	if opts.UserID != "" {
+		err = tx.QueryRow(ctx, `SELECT id FROM auth.users WHERE id = $1`, opts.UserID).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			result.Skipped = "no user " + opts.UserID
+			return "", nil
+		}
+		return id, err
+	}
+
+	err = tx.QueryRow(ctx, `SELECT id FROM auth.users WHERE lower(email) = lower($1)`, archive.Email).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+	if !opts.CreateUsers || archive.Email == "" {
+		result.Skipped = "no user with this email"
+		return "", nil
+	}
+
+	createQuery := `
+		INSERT INTO auth.users (id, aud, role, email, created_at, updated_at)
+		VALUES (gen_random_uuid(), 'authenticated', 'authenticated', $1, NOW(), NOW())
+		RETURNING id
+	`
+	if err := tx.QueryRow(ctx, createQuery, archive.Email).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to create user %s: %w", archive.Email, err)
+	}
+	result.Inserted["users"]++
+	return id, nil
+}
+
+// mapReferencedExercises points other users' exercises at the target's public exercise
+// of the same name, or at a private copy when there is none. Names are unique per user
+// regardless of case, so an exercise named like one of the user's own is returned as an
+// alias of it instead, to map once the user's exercises are restored.
+func (r *Restorer) mapReferencedExercises(ctx context.Context, tx pgx.Tx, archive *UserArchive, userID string, ids idMap, result *RestoreResult) (map[string]string, error) {
+	own := make(map[string]string)
+	for _, e := range archive.Tables["exercises"] {
+		id, _ := e["id"].(string)
+		name, _ := e["name"].(string)
+		own[strings.ToLower(name)] = id
+	}
+
+	aliases := make(map[string]string)
+	copies := make(map[string]string)
+	for _, e := range archive.ReferencedExercises {
+		oldID, _ := e["id"].(string)
+		name, _ := e["name"].(string)
+		if oldID == "" || name == "" {
+			continue
+		}
+		if ownID, ok := own[strings.ToLower(name)]; ok {
+			aliases[oldID] = ownID
+			continue
+		}
+		if id, ok := copies[strings.ToLower(name)]; ok {
+			ids[oldID] = id
+			continue
+		}
+
+		var id string
+		query := `SELECT id FROM exercises WHERE is_public AND lower(name) = lower($1) ORDER BY created_at LIMIT 1`
+		err := tx.QueryRow(ctx, query, name).Scan(&id)
+		if errors.Is(err, pgx.ErrNoRows) {
+			insertQuery := `INSERT INTO exercises (user_id, name, description, muscle_group) VALUES ($1, $2, $3, $4) RETURNING id`
+			if err = tx.QueryRow(ctx, insertQuery, userID, name, e["description"], e["muscle_group"]).Scan(&id); err == nil {
+				result.Inserted["exercises"]++
+				copies[strings.ToLower(name)] = id
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to map exercise %s: %w", name, err)
+		}
+		ids[oldID] = id
+	}
+	return aliases, nil
+}
+
+// restoreTable inserts a table's rows, with the columns both the archive and the target
+// have, and records the new IDs
+func (r *Restorer) restoreTable(ctx context.Context, tx pgx.Tx, t *tableSpec, rows []map[string]any, userID string, ids idMap, result *RestoreResult) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	columns, err := r.insertableColumns(ctx, tx, t.name)
+	if err != nil {
+		return err
+	}
+
+	batch := &pgx.Batch{}
+	for _, row := range rows {
+		if !t.remap(row, ids, userID, r.newID) {
+			result.Orphaned[t.name]++
+			continue
+		}
+
+		var names []string
+		for column := range row {
+			if columns[column] {
+				names = append(names, pgx.Identifier{column}.Sanitize())
+			}
+		}
+		sort.Strings(names)
+		list := strings.Join(names, ", ")
+
+		data, err := json.Marshal(row)
+		if err != nil {
+			return err
+		}
+		batch.Queue(`INSERT INTO `+t.name+` (`+list+`) SELECT `+list+` FROM jsonb_populate_record(NULL::`+t.name+`, $1) ON CONFLICT DO NOTHING`, data)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	defer results.Close()
+	for range batch.Len() {
+		tag, err := results.Exec()
+		if err != nil {
+			return err
+		}
+		result.Inserted[t.name] += tag.RowsAffected()
+	}
+	return results.Close()
+}
+
+// insertableColumns lists a table's columns that take values, leaving out generated ones
+func (r *Restorer) insertableColumns(ctx context.Context, tx pgx.Tx, table string) (map[string]bool, error) {
+	if columns, ok := r.columns[table]; ok {
+		return columns, nil
+	}
+
+	query := `
+		SELECT column_name FROM information_schema.columns
+		WHERE table_schema = 'public' AND table_name = $1
+		  AND is_generated = 'NEVER' AND COALESCE(identity_generation, '') <> 'ALWAYS'
+	`
+	rows, err := tx.Query(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]bool, len(names))
+	for _, name := range names {
+		columns[name] = true
+	}
+	r.columns[table] = columns
+	return columns, nil
+}
@@ -0,0 +1,235 @@
+package backup
+
+import "encoding/json"
+
+// refKind is how a reference column is rewritten on restore
+type refKind int
+
+const (
+	// refUser points at the restored user
+	refUser refKind = iota
+	// refRequired points at a restored row; rows whose target wasn't restored are skipped
+	refRequired
+	// refOptional points at a restored row, or becomes NULL
+	refOptional
+	// refDropped always becomes NULL, e.g. organizations and their classes, which aren't part
+	// of a backup
+	refDropped
+)
+
+// tableSpec is a table holding a user's data: which rows are theirs and which columns
+// reference other rows
+type tableSpec struct {
+	name  string
+	scope string // FROM clause after the table, aliased t, selecting the user's rows ($1)
+	order string
+	refs  map[string]refKind
+}
+
+// tables are backed up and restored in this order, parents before children. Rows are
+// selected whole, so new columns are carried along; updated_at is left to the target's
+// default so restored history counts as changed, e.g. for trend snapshots.
+var tables = []*tableSpec{
+	{
+		name:  "equipment",
+		scope: "WHERE t.user_id = $1",
+		order: "t.created_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser, "organization_id": refDropped},
+	},
+	{
+		name:  "exercises",
+		scope: "WHERE t.user_id = $1",
+		order: "t.created_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "exercise_equipment",
+		scope: "JOIN exercises e ON e.id = t.exercise_id WHERE e.user_id = $1",
+		order: "t.exercise_id, t.equipment_id",
+		refs:  map[string]refKind{"exercise_id": refRequired, "equipment_id": refRequired},
+	},
+	{
+		name:  "workouts",
+		scope: "WHERE t.user_id = $1",
+		order: "t.created_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser, "organization_id": refDropped},
+	},
+	{
+		name:  "workout_exercises",
+		scope: "JOIN workouts w ON w.id = t.workout_id WHERE w.user_id = $1",
+		order: "t.workout_id, t.order_index, t.id",
+		refs:  map[string]refKind{"workout_id": refRequired, "exercise_id": refRequired},
+	},
+	{
+		name:  "training_maxes",
+		scope: "WHERE t.user_id = $1",
+		order: "t.name",
+		refs:  map[string]refKind{"user_id": refUser, "exercise_id": refOptional},
+	},
+	{
+		name:  "workout_sessions",
+		scope: "WHERE t.user_id = $1",
+		order: "t.started_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser, "workout_id": refOptional, "class_session_id": refDropped},
+	},
+	{
+		name:  "exercise_logs",
+		scope: "JOIN workout_sessions s ON s.id = t.workout_session_id WHERE s.user_id = $1",
+		order: "s.started_at, t.order_index, t.id",
+		refs:  map[string]refKind{"workout_session_id": refRequired, "exercise_id": refRequired, "workout_exercise_id": refOptional},
+	},
+	{
+		name:  "session_laps",
+		scope: "JOIN workout_sessions s ON s.id = t.workout_session_id WHERE s.user_id = $1",
+		order: "s.started_at, t.lap_index",
+		refs:  map[string]refKind{"workout_session_id": refRequired},
+	},
+	{
+		name:  "exercise_swaps",
+		scope: "WHERE t.user_id = $1",
+		order: "t.created_at, t.id",
+		refs: map[string]refKind{
+			"user_id": refUser, "workout_session_id": refRequired, "exercise_log_id": refRequired,
+			"from_exercise_id": refOptional, "to_exercise_id": refOptional,
+		},
+	},
+	{
+		name:  "session_media",
+		scope: "WHERE t.user_id = $1",
+		order: "t.workout_session_id, t.position",
+		refs:  map[string]refKind{"user_id": refUser, "workout_session_id": refRequired},
+	},
+	{
+		name:  "body_measurements",
+		scope: "WHERE t.user_id = $1",
+		order: "t.measured_at",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "water_logs",
+		scope: "WHERE t.user_id = $1",
+		order: "t.logged_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "hydration_goals",
+		scope: "WHERE t.user_id = $1",
+		order: "t.user_id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "sleep_logs",
+		scope: "WHERE t.user_id = $1",
+		order: "t.started_at",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "goals",
+		scope: "WHERE t.user_id = $1",
+		order: "t.created_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser, "exercise_id": refRequired},
+	},
+	{
+		name:  "injuries",
+		scope: "WHERE t.user_id = $1",
+		order: "t.started_on, t.created_at",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "reminder_rules",
+		scope: "WHERE t.user_id = $1",
+		order: "t.created_at, t.id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "notification_preferences",
+		scope: "WHERE t.user_id = $1",
+		order: "t.user_id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "email_preferences",
+		scope: "WHERE t.user_id = $1",
+		order: "t.user_id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "user_profiles",
+		scope: "WHERE t.user_id = $1",
+		order: "t.user_id",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+	{
+		name:  "weekly_reports",
+		scope: "WHERE t.user_id = $1",
+		order: "t.week_start",
+		refs:  map[string]refKind{"user_id": refUser},
+	},
+}
+
+// droppedColumns are never restored: the target's default applies
+var droppedColumns = map[string]bool{"updated_at": true}
+
+// idMap maps the archive's IDs to the restored rows' IDs
+type idMap map[string]string
+
+// remap rewrites a row for the target: a new primary key, references through ids and
+// dropped columns removed. It reports false when a required reference wasn't restored,
+// and records the row's new ID in ids.
+func (s *tableSpec) remap(row map[string]any, ids idMap, userID string, newID func() string) bool {
+	for column, kind := range s.refs {
+		old, ok := row[column].(string)
+		if !ok {
+			continue // NULL or absent
+		}
+		switch kind {
+		case refUser:
+			row[column] = userID
+		case refDropped:
+			row[column] = nil
+		case refRequired, refOptional:
+			mapped, ok := ids[old]
+			if !ok && kind == refRequired {
+				return false
+			}
+			if ok {
+				row[column] = mapped
+			} else {
+				row[column] = nil
+			}
+		}
+	}
+
+	// equipment_used holds equipment IDs as a JSON array in text
+	if raw, ok := row["equipment_used"].(string); ok {
+		row["equipment_used"] = remapIDList(raw, ids)
+	}
+
+	for column := range droppedColumns {
+		delete(row, column)
+	}
+
+	if old, ok := row["id"].(string); ok {
+		id := newID()
+		ids[old] = id
+		row["id"] = id
+	}
+	return true
+}
+
+// remapIDList rewrites a JSON array of IDs, dropping those that weren't restored;
+// anything else is kept as it was
+func remapIDList(raw string, ids idMap) any {
+	var list []string
+	if err := json.Unmarshal([]byte(raw), &list); err != nil {
+		return raw
+	}
+	mapped := make([]string, 0, len(list))
+	for _, id := range list {
+		if newID, ok := ids[id]; ok {
+			mapped = append(mapped, newID)
+		}
+	}
+	encoded, _ := json.Marshal(mapped)
+	return string(encoded)
+}
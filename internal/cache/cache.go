@@ -0,0 +1,163 @@
+// Package cache keeps the results of hot reads in Redis or in memory.
+//
+// Values are cached within scopes: invalidating a scope drops every value cached in it at
+// once, e.g. all of a user's analytics. Scopes work by generation, stored alongside the
+// values, so invalidation costs one write whatever the number of values.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// Store keeps encoded values until their time to live runs out
+type Store interface {
+	// Get returns the value of key, and false when there is none
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+}
+
+const (
+	// generationTTL is how long a scope's generation is kept after it was last
+	// invalidated; values must expire sooner, or they would come back once it is gone
+	generationTTL = 24 * time.Hour
+
+	// MaxTTL bounds how long a value is cached
+	MaxTTL = 12 * time.Hour
+)
+
+// Cache reads through a store, counting hits and misses per namespace: the part of a
+// scope before the first colon
+type Cache struct {
+	store  Store
+	prefix string
+
+	mu    sync.Mutex
+	stats map[string]*models.CacheStats
+	now   func() time.Time
+}
+
+// New creates a cache keeping values in store under keys starting with prefix
+func New(store Store, prefix string) *Cache {
+	return &Cache{store: store, prefix: prefix, stats: make(map[string]*models.CacheStats), now: time.Now}
+}
+
+// Fetch returns the value cached under key in scope, or loads it and caches it for ttl.
+// Values are JSON encoded. Failing cache reads and writes are logged and the value loaded
+// instead, so an unavailable cache only makes reads slower. A nil cache always loads.
+func Fetch[T any](ctx context.Context, c *Cache, scope string, key string, ttl time.Duration, load func() (T, error)) (T, error) {
+	if c == nil {
+		return load()
+	}
+
+	fullKey, err := c.key(ctx, scope, key)
+	if err == nil {
+		var raw []byte
+		var ok bool
+		raw, ok, err = c.store.Get(ctx, fullKey)
+		if err == nil && ok {
+			var value T
+			if err = json.Unmarshal(raw, &value); err == nil {
+				c.record(scope, func(s *models.CacheStats) { s.Hits++ })
+				return value, nil
+			}
+		}
+	}
+	if err != nil {
+		log.Printf("Cache read of %s failed: %v", scope, err)
+		c.record(scope, func(s *models.CacheStats) { s.Errors++ })
+	}
+	c.record(scope, func(s *models.CacheStats) { s.Misses++ })
+
+	value, err := load()
+	if err != nil || fullKey == "" {
+		return value, err
+	}
+	raw, err := json.Marshal(value)
+	if err == nil {
+		err = c.store.Set(ctx, fullKey, raw, min(ttl, MaxTTL))
+	}
+	if err != nil {
+		log.Printf("Cache write of %s failed: %v", scope, err)
+		c.record(scope, func(s *models.CacheStats) { s.Errors++ })
+	}
+	return value, nil
+}
+
+// Invalidate drops every value cached in the scopes. A nil cache does nothing.
+func (c *Cache) Invalidate(ctx context.Context, scopes ...string) {
+	if c == nil {
+		return
+	}
+
+	generation := strconv.FormatInt(c.now().UnixNano(), 36)
+	for _, scope := range scopes {
+		if err := c.store.Set(ctx, c.generationKey(scope), []byte(generation), generationTTL); err != nil {
+			log.Printf("Cache invalidation of %s failed: %v", scope, err)
+			c.record(scope, func(s *models.CacheStats) { s.Errors++ })
+			continue
+		}
+		c.record(scope, func(s *models.CacheStats) { s.Invalidations++ })
+	}
+}
+
+// Stats returns the hits and misses of each namespace since the cache was created
+func (c *Cache) Stats() []*models.CacheStats {
+	if c == nil {
+		return []*models.CacheStats{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stats := make([]*models.CacheStats, 0, len(c.stats))
+	for _, s := range c.stats {
+		copied := *s
+		if lookups := copied.Hits + copied.Misses; lookups > 0 {
+			copied.HitRate = float64(copied.Hits) / float64(lookups)
+		}
+		stats = append(stats, &copied)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Namespace < stats[j].Namespace })
+	return stats
+}
+
+// key is the store key of a value: the scope's current generation is part of it, so
+// values of earlier generations are never read again
+func (c *Cache) key(ctx context.Context, scope string, key string) (string, error) {
+	generation, ok, err := c.store.Get(ctx, c.generationKey(scope))
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		generation = []byte("0")
+	}
+	return c.prefix + scope + ":" + string(generation) + ":" + key, nil
+}
+
+func (c *Cache) generationKey(scope string) string {
+	return c.prefix + scope + ":generation"
+}
+
+func (c *Cache) record(scope string, update func(*models.CacheStats)) {
+	namespace, _, _ := strings.Cut(scope, ":")
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s, ok := c.stats[namespace]
+	if !ok {
+		s = &models.CacheStats{Namespace: namespace}
+		c.stats[namespace] = s
+	}
+	update(s)
+}
@@ -0,0 +1,310 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMemory_ExpiresValues(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store := NewMemory(2)
+	store.now = func() time.Time { return now }
+
+	store.Set(ctx, "a", []byte("1"), time.Minute)
+	if value, ok, _ := store.Get(ctx, "a"); !ok || string(value) != "1" {
+		t.Fatalf("Expected a to be cached, got %q, %v", value, ok)
+	}
+
+	now = now.Add(time.Minute)
+	if _, ok, _ := store.Get(ctx, "a"); ok {
+		t.Error("Expected a to expire after its ttl")
+	}
+
+	store.Set(ctx, "b", []byte("2"), time.Minute)
+	store.Set(ctx, "c", []byte("3"), time.Minute)
+	store.Set(ctx, "d", []byte("4"), time.Minute)
+	if len(store.entries) != 2 {
+		t.Errorf("Expected at most 2 entries, got %d", len(store.entries))
+	}
+}
+
+func TestFetch_CountsHitsAndMisses(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewMemory(100), "test:")
+
+	loads := 0
+	load := func() ([]string, error) {
+		loads++
+		return []string{"squat", "bench"}, nil
+	}
+	for range 3 {
+		value, err := Fetch(ctx, c, "exercises", "list", time.Minute, load)
+		if err != nil || len(value) != 2 {
+			t.Fatalf("Expected the loaded value, got %v, %v", value, err)
+		}
+	}
+	if loads != 1 {
+		t.Errorf("Expected one load, got %d", loads)
+	}
+
+	stats := c.Stats()
+	if len(stats) != 1 || stats[0].Namespace != "exercises" || stats[0].Hits != 2 || stats[0].Misses != 1 {
+		t.Fatalf("Expected 2 hits and 1 miss, got %+v", stats[0])
+	}
+	if stats[0].HitRate < 0.66 || stats[0].HitRate > 0.67 {
+		t.Errorf("Expected a hit rate of 2/3, got %f", stats[0].HitRate)
+	}
+}
+
+func TestFetch_DoesNotCacheErrors(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewMemory(100), "test:")
+
+	loadErr := errors.New("database down")
+	if _, err := Fetch(ctx, c, "exercises", "list", time.Minute, func() (int, error) { return 0, loadErr }); !errors.Is(err, loadErr) {
+		t.Fatalf("Expected the load error, got %v", err)
+	}
+	value, err := Fetch(ctx, c, "exercises", "list", time.Minute, func() (int, error) { return 7, nil })
+	if err != nil || value != 7 {
+		t.Errorf("Expected the value to be loaded again, got %v, %v", value, err)
+	}
+}
+
+func TestFetch_NilCacheLoads(t *testing.T) {
+	var c *Cache
+	value, err := Fetch(context.Background(), c, "exercises", "list", time.Minute, func() (int, error) { return 7, nil })
+	if err != nil || value != 7 {
+		t.Errorf("Expected the loaded value, got %v, %v", value, err)
+	}
+	c.Invalidate(context.Background(), "exercises")
+	if len(c.Stats()) != 0 {
+		t.Error("Expected no stats from a nil cache")
+	}
+}
+
+func TestInvalidate_DropsScope(t *testing.T) {
+	ctx := context.Background()
+	c := New(NewMemory(100), "test:")
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	c.now = func() time.Time { return now }
+
+	version := 1
+	load := func() (int, error) { return version, nil }
+	Fetch(ctx, c, "analytics:user-1", "trend", time.Minute, load)
+	Fetch(ctx, c, "analytics:user-2", "trend", time.Minute, load)
+
+	version = 2
+	c.Invalidate(ctx, "analytics:user-1")
+
+	if value, _ := Fetch(ctx, c, "analytics:user-1", "trend", time.Minute, load); value != 2 {
+		t.Errorf("Expected the invalidated scope to load again, got %d", value)
+	}
+	if value, _ := Fetch(ctx, c, "analytics:user-2", "trend", time.Minute, load); value != 1 {
+		t.Errorf("Expected other scopes to stay cached, got %d", value)
+	}
+
+	stats := c.Stats()
+	if len(stats) != 1 || stats[0].Namespace != "analytics" || stats[0].Invalidations != 1 {
+		t.Errorf("Expected one invalidation in analytics, got %+v", stats)
+	}
+}
+
+func TestFetch_UnavailableStore(t *testing.T) {
+	store, err := NewRedis("redis://127.0.0.1:1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	c := New(store, "test:")
+
+	value, err := Fetch(context.Background(), c, "exercises", "list", time.Minute, func() (int, error) { return 7, nil })
+	if err != nil || value != 7 {
+		t.Fatalf("Expected the value to be loaded, got %v, %v", value, err)
+	}
+	if stats := c.Stats(); stats[0].Errors == 0 || stats[0].Misses != 1 {
+		t.Errorf("Expected the failure to be counted, got %+v", stats[0])
+	}
+}
+
+func TestNewRedis_ParsesURL(t *testing.T) {
+	r, err := NewRedis("rediss://:secret@cache.example.com/2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if r.addr != "cache.example.com:6379" || r.password != "secret" || r.db != 2 || r.tls == nil {
+		t.Errorf("Unexpected store %+v", r)
+	}
+
+	for _, rawURL := range []string{"http://localhost", "redis://localhost/db"} {
+		if _, err := NewRedis(rawURL); err == nil {
+			t.Errorf("Expected %q to be rejected", rawURL)
+		}
+	}
+}
+
+func TestRedis_Commands(t *testing.T) {
+	addr, commands := fakeRedis(t, "secret")
+	store, err := NewRedis("redis://:secret@" + addr + "/3")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+	ctx := context.Background()
+
+	if _, ok, err := store.Get(ctx, "missing"); err != nil || ok {
+		t.Fatalf("Expected a miss, got %v, %v", ok, err)
+	}
+	if err := store.Set(ctx, "key", []byte("value\r\nwith newline"), 1500*time.Millisecond); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	value, ok, err := store.Get(ctx, "key")
+	if err != nil || !ok || string(value) != "value\r\nwith newline" {
+		t.Fatalf("Expected the stored value, got %q, %v, %v", value, ok, err)
+	}
+	if err := store.Delete(ctx, "key", "other"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, ok, _ := store.Get(ctx, "key"); ok {
+		t.Error("Expected the key to be deleted")
+	}
+
+	want := []string{
+		"AUTH secret", "SELECT 3", "GET missing", "SET key value\r\nwith newline PX 1500",
+		"GET key", "DEL key other", "GET key",
+	}
+	got := commands()
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("Expected commands %q over one connection, got %q", want, got)
+	}
+}
+
+func TestRedis_Eval(t *testing.T) {
+	addr, commands := fakeRedis(t, "")
+	store, err := NewRedis("redis://" + addr)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer store.Close()
+
+	reply, err := store.Eval(context.Background(), "return KEYS[1]", []string{"bucket"}, "60", "1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if value, ok := reply.([]byte); !ok || string(value) != "bucket" {
+		t.Errorf("Expected the script's reply, got %v", reply)
+	}
+	if got := commands(); len(got) != 1 || got[0] != "EVAL return KEYS[1] 1 bucket 60 1" {
+		t.Errorf("Unexpected commands %q", got)
+	}
+}
+
+// fakeRedis serves GET, SET and DEL from a map and answers EVAL with the first key, recording the commands it receives.
+// Every command is answered before the store returns, so the log is complete once the
+// store's calls are.
+func fakeRedis(t *testing.T, password string) (string, func() []string) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("Cannot listen on loopback: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	var mu sync.Mutex
+	var received []string
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				defer conn.Close()
+				values := make(map[string]string)
+				reader := bufio.NewReader(conn)
+				for {
+					args, err := readCommand(reader)
+					if err != nil {
+						return
+					}
+					mu.Lock()
+					received = append(received, strings.Join(args, " "))
+					mu.Unlock()
+
+					switch strings.ToUpper(args[0]) {
+					case "AUTH":
+						if args[1] != password {
+							io.WriteString(conn, "-WRONGPASS invalid password\r\n")
+							continue
+						}
+						io.WriteString(conn, "+OK\r\n")
+					case "SELECT":
+						io.WriteString(conn, "+OK\r\n")
+					case "GET":
+						value, ok := values[args[1]]
+						if !ok {
+							io.WriteString(conn, "$-1\r\n")
+							continue
+						}
+						fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(value), value)
+					case "SET":
+						values[args[1]] = args[2]
+						io.WriteString(conn, "+OK\r\n")
+					case "EVAL":
+						// Replies with the script's first key
+						fmt.Fprintf(conn, "$%d\r\n%s\r\n", len(args[3]), args[3])
+					case "DEL":
+						for _, key := range args[1:] {
+							delete(values, key)
+						}
+						fmt.Fprintf(conn, ":%d\r\n", len(args)-1)
+					default:
+						io.WriteString(conn, "-ERR unknown command\r\n")
+					}
+				}
+			}()
+		}
+	}()
+
+	return listener.Addr().String(), func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), received...)
+	}
+}
+
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "*")))
+	if err != nil {
+		return nil, err
+	}
+	args := make([]string, n)
+	for i := range args {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		size, err := strconv.Atoi(strings.TrimSpace(strings.TrimPrefix(line, "$")))
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, size+2)
+		if _, err := io.ReadFull(r, buf); err != nil {
+			return nil, err
+		}
+		args[i] = string(buf[:size])
+	}
+	return args, nil
+}
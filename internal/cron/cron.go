@@ -0,0 +1,109 @@
+// Package cron runs recurring maintenance tasks on cron schedules
+package cron
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync/atomic"
+	"time"
+)
+
+// Task is one run of a recurring task
+type Task func(ctx context.Context) error
+
+// Scheduler runs tasks on their schedules. A task still running when it is due again
+// skips that run rather than overlapping itself.
+type Scheduler struct {
+	loc     *time.Location
+	entries []*entry
+	now     func() time.Time
+}
+
+type entry struct {
+	name     string
+	schedule Schedule
+	task     Task
+	next     time.Time
+	running  atomic.Bool
+}
+
+// New creates a scheduler matching specs in loc
+func New(loc *time.Location) *Scheduler {
+	return &Scheduler{loc: loc, now: time.Now}
+}
+
+// Add schedules a task; call it before Start
+func (s *Scheduler) Add(name, spec string, task Task) error {
+	schedule, err := Parse(spec, s.loc)
+	if err != nil {
+		return fmt.Errorf("invalid schedule for %s: %w", name, err)
+	}
+	s.entries = append(s.entries, &entry{name: name, schedule: schedule, task: task})
+	return nil
+}
+
+// Start runs due tasks in the background until ctx is cancelled
+func (s *Scheduler) Start(ctx context.Context) {
+	if len(s.entries) == 0 {
+		return
+	}
+
+	now := s.now()
+	for _, e := range s.entries {
+		e.next = e.schedule.Next(now)
+	}
+
+	go func() {
+		timer := time.NewTimer(s.untilNext())
+		defer timer.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-timer.C:
+				now := s.now()
+				for _, e := range s.entries {
+					if e.next.IsZero() || e.next.After(now) {
+						continue
+					}
+					s.run(ctx, e)
+					e.next = e.schedule.Next(now)
+				}
+				timer.Reset(s.untilNext())
+			}
+		}
+	}()
+}
+
+// untilNext is the wait until the earliest due entry; entries that never run again wait
+// an hour so the loop still notices cancellation
+func (s *Scheduler) untilNext() time.Duration {
+	wait := time.Hour
+	now := s.now()
+	for _, e := range s.entries {
+		if !e.next.IsZero() {
+			wait = min(wait, e.next.Sub(now))
+		}
+	}
+	return max(wait, 0)
+}
+
+// run starts the entry's task unless its previous run is still going
+func (s *Scheduler) run(ctx context.Context, e *entry) bool {
+	if !e.running.CompareAndSwap(false, true) {
+		log.Printf("Cron task %s skipped: previous run still in progress", e.name)
+		return false
+	}
+
+	go func() {
+		defer e.running.Store(false)
+
+		started := s.now()
+		if err := e.task(ctx); err != nil {
+			log.Printf("Cron task %s failed after %s: %v", e.name, s.now().Sub(started), err)
+		}
+	}()
+	return true
+}
@@ -0,0 +1,95 @@
+package cron
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestParse_Next(t *testing.T) {
+	// Monday
+	after := time.Date(2026, 3, 2, 9, 30, 15, 0, time.UTC)
+	tests := []struct {
+		spec string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 3, 2, 9, 31, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 3, 2, 9, 45, 0, 0, time.UTC)},
+		{"0 * * * 1", time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)},
+		{"0 3 * * *", time.Date(2026, 3, 3, 3, 0, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)},
+		{"0 8 1,15 * 5", time.Date(2026, 3, 6, 8, 0, 0, 0, time.UTC)},
+		{"30 6 1-5 4 *", time.Date(2026, 4, 1, 6, 30, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{"@every 90s", after.Add(90 * time.Second)},
+		{"0 0 30 2 *", time.Time{}},
+	}
+
+	for _, tt := range tests {
+		schedule, err := Parse(tt.spec, time.UTC)
+		if err != nil {
+			t.Fatalf("Expected %q to parse, got %v", tt.spec, err)
+		}
+		if got := schedule.Next(after); !got.Equal(tt.want) {
+			t.Errorf("Expected %q to run next at %s, got %s", tt.spec, tt.want, got)
+		}
+	}
+}
+
+func TestParse_InLocation(t *testing.T) {
+	loc := time.FixedZone("UTC-3", -3*60*60)
+	schedule, err := Parse("0 9 * * *", loc)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	want := time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC)
+	if got := schedule.Next(time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)); !got.Equal(want) {
+		t.Errorf("Expected 9:00 local to be %s, got %s", want, got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "5-1 * * * *", "*/0 * * * *", "a * * * *", "@every 10ms", "@every soon", "@yearly"} {
+		if _, err := Parse(spec, time.UTC); err == nil {
+			t.Errorf("Expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestRun_SkipsOverlappingRuns(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 2)
+	scheduler := New(time.UTC)
+	if err := scheduler.Add("slow", "@every 1m", func(ctx context.Context) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	e := scheduler.entries[0]
+
+	if !scheduler.run(context.Background(), e) {
+		t.Fatal("Expected the first run to start")
+	}
+	<-started
+	if scheduler.run(context.Background(), e) {
+		t.Error("Expected a run to be skipped while the previous one is in progress")
+	}
+
+	close(release)
+	for e.running.Load() {
+		time.Sleep(time.Millisecond)
+	}
+	if !scheduler.run(context.Background(), e) {
+		t.Error("Expected a run to start once the previous one finished")
+	}
+	<-started
+}
+
+func TestAdd_RejectsInvalidSpec(t *testing.T) {
+	if err := New(time.UTC).Add("broken", "every minute", func(ctx context.Context) error { return nil }); err == nil {
+		t.Error("Expected an invalid spec to be rejected")
+	}
+}
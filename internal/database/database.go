@@ -2,9 +2,13 @@ package database
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -12,18 +16,92 @@ type DB struct {
 	Pool *pgxpool.Pool
 }
 
-func New(databaseURL string) (*DB, error) {
+// PoolConfig tunes the connection pool and how queries are prepared
+type PoolConfig struct {
+	MaxConns          int32 // Keep below the pooler's limit (Supabase limits per project)
+	MinConns          int32 // Warm connections kept open
+	MaxConnLifetime   time.Duration
+	MaxConnIdleTime   time.Duration
+	HealthCheckPeriod time.Duration
+
+	// QueryExecMode is cache_statement, cache_describe, describe_exec, exec or
+	// simple_protocol; empty keeps the database URL's default_query_exec_mode, or
+	// cache_statement. Transaction mode poolers (Supabase's port 6543) need a mode that
+	// doesn't keep prepared statements on the server: cache_describe or later.
+	QueryExecMode string
+	// StatementCacheCapacity is how many statements (cache_statement) or descriptions
+	// (cache_describe) each connection keeps; 0 keeps pgx's default of 512
+	StatementCacheCapacity int
+
+	// StatementTimeout cancels statements running longer on the server, so a runaway query
+	// can't hold a connection after its request gave up; 0 keeps the database's setting
+	StatementTimeout time.Duration
+
+	// Tracer times every query, if set
+	Tracer *QueryTracer
+}
+
+// PoolSettings are the effective pool settings, for the verbose health check
+type PoolSettings struct {
+	MaxConns                 int32  `json:"max_conns"`
+	MinConns                 int32  `json:"min_conns"`
+	MaxConnLifetime          string `json:"max_conn_lifetime"`
+	MaxConnIdleTime          string `json:"max_conn_idle_time"`
+	HealthCheckPeriod        string `json:"health_check_period"`
+	QueryExecMode            string `json:"query_exec_mode"`
+	StatementCacheCapacity   int    `json:"statement_cache_capacity"`
+	DescriptionCacheCapacity int    `json:"description_cache_capacity"`
+	StatementTimeout         string `json:"statement_timeout"`
+
+	// Live pool counts
+	TotalConns    int32 `json:"total_conns"`
+	IdleConns     int32 `json:"idle_conns"`
+	AcquiredConns int32 `json:"acquired_conns"`
+}
+
+// queryExecModes maps the setting names, as in default_query_exec_mode, to pgx's modes
+var queryExecModes = map[string]pgx.QueryExecMode{
+	"cache_statement": pgx.QueryExecModeCacheStatement,
+	"cache_describe":  pgx.QueryExecModeCacheDescribe,
+	"describe_exec":   pgx.QueryExecModeDescribeExec,
+	"exec":            pgx.QueryExecModeExec,
+	"simple_protocol": pgx.QueryExecModeSimpleProtocol,
+}
+
+func New(databaseURL string, poolConfig PoolConfig) (*DB, error) {
 	config, err := pgxpool.ParseConfig(databaseURL)
 	if err != nil {
 		return nil, err
 	}
 
-	// Configure connection pool for production best practices
-	config.MaxConns = 10                          // Don't flood the pooler (align with Supabase limits)
-	config.MinConns = 2                           // Keep some warm connections
-	config.MaxConnLifetime = 0                    // No max lifetime (let pooler handle it)
-	config.MaxConnIdleTime = 30 * time.Minute     // 30 minutes idle timeout
-	config.HealthCheckPeriod = 1 * time.Minute    // Health check every minute
+	if poolConfig.MaxConns < 1 || poolConfig.MinConns < 0 || poolConfig.MinConns > poolConfig.MaxConns {
+		return nil, fmt.Errorf("invalid pool size: min %d and max %d connections", poolConfig.MinConns, poolConfig.MaxConns)
+	}
+	config.MaxConns = poolConfig.MaxConns
+	config.MinConns = poolConfig.MinConns
+	config.MaxConnLifetime = poolConfig.MaxConnLifetime // 0 lets the pooler handle it
+	config.MaxConnIdleTime = poolConfig.MaxConnIdleTime
+	config.HealthCheckPeriod = poolConfig.HealthCheckPeriod
+	// Timestamps are stored and compared in UTC; user timezones are applied in queries
+	config.ConnConfig.RuntimeParams["timezone"] = "UTC"
+
+	if poolConfig.QueryExecMode != "" {
+		mode, ok := queryExecModes[poolConfig.QueryExecMode]
+		if !ok {
+			return nil, fmt.Errorf("unknown query exec mode %q", poolConfig.QueryExecMode)
+		}
+		config.ConnConfig.DefaultQueryExecMode = mode
+	}
+	if poolConfig.StatementCacheCapacity > 0 {
+		config.ConnConfig.StatementCacheCapacity = poolConfig.StatementCacheCapacity
+		config.ConnConfig.DescriptionCacheCapacity = poolConfig.StatementCacheCapacity
+	}
+	if poolConfig.StatementTimeout > 0 {
+		config.ConnConfig.RuntimeParams["statement_timeout"] = strconv.FormatInt(poolConfig.StatementTimeout.Milliseconds(), 10)
+	}
+	if poolConfig.Tracer != nil {
+		config.ConnConfig.Tracer = poolConfig.Tracer
+	}
 
 	pool, err := pgxpool.NewWithConfig(context.Background(), config)
 	if err != nil {
@@ -35,11 +113,44 @@ func New(databaseURL string) (*DB, error) {
 		return nil, err
 	}
 
-	log.Println("Database connection established successfully")
+	log.Printf("Database connection established successfully (max %d connections, %s)",
+		config.MaxConns, config.ConnConfig.DefaultQueryExecMode)
 
 	return &DB{Pool: pool}, nil
 }
 
+// Settings returns the pool's effective settings and current connection counts
+func (db *DB) Settings() PoolSettings {
+	config := db.Pool.Config()
+	stat := db.Pool.Stat()
+	return PoolSettings{
+		MaxConns:                 config.MaxConns,
+		MinConns:                 config.MinConns,
+		MaxConnLifetime:          config.MaxConnLifetime.String(),
+		MaxConnIdleTime:          config.MaxConnIdleTime.String(),
+		HealthCheckPeriod:        config.HealthCheckPeriod.String(),
+		QueryExecMode:            strings.ReplaceAll(config.ConnConfig.DefaultQueryExecMode.String(), " ", "_"),
+		StatementCacheCapacity:   config.ConnConfig.StatementCacheCapacity,
+		DescriptionCacheCapacity: config.ConnConfig.DescriptionCacheCapacity,
+		StatementTimeout:         statementTimeout(config.ConnConfig.RuntimeParams["statement_timeout"]),
+		TotalConns:               stat.TotalConns(),
+		IdleConns:                stat.IdleConns(),
+		AcquiredConns:            stat.AcquiredConns(),
+	}
+}
+
+// statementTimeout formats the statement_timeout runtime parameter, in milliseconds
+func statementTimeout(ms string) string {
+	if ms == "" {
+		return "default"
+	}
+	n, err := strconv.ParseInt(ms, 10, 64)
+	if err != nil {
+		return ms
+	}
+	return (time.Duration(n) * time.Millisecond).String()
+}
+
 func (db *DB) Close() {
 	db.Pool.Close()
 }
@@ -0,0 +1,248 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+const (
+	// maxTracedStatements bounds the statements tracked separately; later ones share a
+	// single entry. The API's statements are fixed strings, so this is only reached if
+	// values end up in SQL.
+	maxTracedStatements = 1000
+
+	// otherStatements is the entry of statements beyond maxTracedStatements
+	otherStatements = "(other)"
+
+	// maxStatementLength truncates statements in logs and stats
+	maxStatementLength = 300
+)
+
+// queryBounds are the histogram bucket upper bounds; the last bucket is everything slower
+var queryBounds = [...]time.Duration{
+	time.Millisecond, 2500 * time.Microsecond, 5 * time.Millisecond, 10 * time.Millisecond,
+	25 * time.Millisecond, 50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond,
+	500 * time.Millisecond, time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// QueryTracer times every query and batch, keeping latency histograms per statement
+// since startup and logging statements slower than a threshold. Argument values are
+// never logged, only their types and sizes, as they hold users' data.
+type QueryTracer struct {
+	slowThreshold time.Duration // 0 logs nothing
+
+	mu         sync.Mutex
+	statements map[string]*statementStats
+	now        func() time.Time
+}
+
+type statementStats struct {
+	calls  int64
+	errors int64
+	total  time.Duration
+	max    time.Duration
+	counts [len(queryBounds) + 1]int64
+}
+
+type queryTraceKey struct{}
+
+type queryTrace struct {
+	sql   string
+	args  []any
+	start time.Time
+}
+
+type batchTraceKey struct{}
+
+type batchTrace struct {
+	sqls  []string
+	err   error
+	start time.Time
+}
+
+// NewQueryTracer creates a tracer logging statements slower than slowThreshold; 0 keeps
+// the statistics without logging
+func NewQueryTracer(slowThreshold time.Duration) *QueryTracer {
+	return &QueryTracer{slowThreshold: slowThreshold, statements: make(map[string]*statementStats), now: time.Now}
+}
+
+// TraceQueryStart implements pgx.QueryTracer
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, &queryTrace{sql: data.SQL, args: data.Args, start: t.now()})
+}
+
+// TraceQueryEnd implements pgx.QueryTracer; for queries returning rows it runs once the
+// rows are closed, so reading them counts towards the time
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(*queryTrace)
+	if !ok {
+		return
+	}
+
+	statement := normalizeStatement(trace.sql)
+	elapsed := t.now().Sub(trace.start)
+	t.observe(statement, elapsed, data.Err)
+
+	if t.slowThreshold > 0 && elapsed >= t.slowThreshold {
+		log.Printf("Slow query (%s, %d rows): %s args: %s", elapsed.Round(time.Millisecond), data.CommandTag.RowsAffected(), statement, describeArgs(trace.args))
+	}
+}
+
+// TraceBatchStart implements pgx.BatchTracer
+func (t *QueryTracer) TraceBatchStart(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchStartData) context.Context {
+	return context.WithValue(ctx, batchTraceKey{}, &batchTrace{start: t.now()})
+}
+
+// TraceBatchQuery implements pgx.BatchTracer
+func (t *QueryTracer) TraceBatchQuery(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchQueryData) {
+	trace, ok := ctx.Value(batchTraceKey{}).(*batchTrace)
+	if !ok {
+		return
+	}
+	trace.sqls = append(trace.sqls, normalizeStatement(data.SQL))
+	if data.Err != nil && trace.err == nil {
+		trace.err = data.Err
+	}
+}
+
+// TraceBatchEnd implements pgx.BatchTracer. A batch is one round trip, so it is tracked
+// as a whole under its statements joined by semicolons.
+func (t *QueryTracer) TraceBatchEnd(ctx context.Context, conn *pgx.Conn, data pgx.TraceBatchEndData) {
+	trace, ok := ctx.Value(batchTraceKey{}).(*batchTrace)
+	if !ok {
+		return
+	}
+
+	statement := truncateStatement("batch: " + strings.Join(trace.sqls, "; "))
+	elapsed := t.now().Sub(trace.start)
+	err := data.Err
+	if err == nil {
+		err = trace.err
+	}
+	t.observe(statement, elapsed, err)
+
+	if t.slowThreshold > 0 && elapsed >= t.slowThreshold {
+		log.Printf("Slow batch (%s, %d queries): %s", elapsed.Round(time.Millisecond), len(trace.sqls), statement)
+	}
+}
+
+// Stats returns the statements that took the most time in total, at most limit of them
+func (t *QueryTracer) Stats(limit int) []*models.QueryStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := make([]*models.QueryStats, 0, len(t.statements))
+	for statement, s := range t.statements {
+		stats = append(stats, &models.QueryStats{
+			Statement: statement,
+			Calls:     s.calls,
+			Errors:    s.errors,
+			TotalMs:   milliseconds(s.total),
+			MeanMs:    milliseconds(s.total / time.Duration(s.calls)),
+			MaxMs:     milliseconds(s.max),
+			P50Ms:     queryQuantile(s.counts, s.calls, 0.50),
+			P95Ms:     queryQuantile(s.counts, s.calls, 0.95),
+			P99Ms:     queryQuantile(s.counts, s.calls, 0.99),
+		})
+	}
+
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].TotalMs != stats[j].TotalMs {
+			return stats[i].TotalMs > stats[j].TotalMs
+		}
+		return stats[i].Statement < stats[j].Statement
+	})
+	if len(stats) > limit {
+		stats = stats[:limit]
+	}
+	return stats
+}
+
+func (t *QueryTracer) observe(statement string, elapsed time.Duration, err error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.statements[statement]
+	if !ok {
+		if len(t.statements) >= maxTracedStatements {
+			statement = otherStatements
+		}
+		if s, ok = t.statements[statement]; !ok {
+			s = &statementStats{}
+			t.statements[statement] = s
+		}
+	}
+
+	s.calls++
+	if err != nil {
+		s.errors++
+	}
+	s.total += elapsed
+	s.max = max(s.max, elapsed)
+	s.counts[sort.Search(len(queryBounds), func(i int) bool { return elapsed <= queryBounds[i] })]++
+}
+
+// normalizeStatement collapses a statement's whitespace and truncates it
+func normalizeStatement(sql string) string {
+	return truncateStatement(strings.Join(strings.Fields(sql), " "))
+}
+
+func truncateStatement(statement string) string {
+	if len(statement) <= maxStatementLength {
+		return statement
+	}
+	cut := maxStatementLength
+	for cut > 0 && !utf8.RuneStart(statement[cut]) {
+		cut--
+	}
+	return statement[:cut] + "…"
+}
+
+// describeArgs lists a query's arguments by type and, for text and bytes, length,
+// e.g. "[$1=string(36) $2=time.Time $3=nil]"
+func describeArgs(args []any) string {
+	parts := make([]string, len(args))
+	for i, arg := range args {
+		var desc string
+		switch v := arg.(type) {
+		case nil:
+			desc = "nil"
+		case string:
+			desc = fmt.Sprintf("string(%d)", len(v))
+		case []byte:
+			desc = fmt.Sprintf("[]byte(%d)", len(v))
+		default:
+			desc = fmt.Sprintf("%T", arg)
+		}
+		parts[i] = fmt.Sprintf("$%d=%s", i+1, desc)
+	}
+	return "[" + strings.Join(parts, " ") + "]"
+}
+
+// queryQuantile returns the upper bound of the bucket holding the q-th call; calls
+// slower than the last bound report that bound
+func queryQuantile(counts [len(queryBounds) + 1]int64, total int64, q float64) float64 {
+	rank := int64(math.Ceil(q * float64(total)))
+	var seen int64
+	for i, n := range counts {
+		seen += n
+		if seen >= rank {
+			return milliseconds(queryBounds[min(i, len(queryBounds)-1)])
+		}
+	}
+	return 0
+}
+
+func milliseconds(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
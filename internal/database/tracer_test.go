@@ -0,0 +1,106 @@
+package database
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// tracedQuery runs a query through the tracer as pgx would, taking elapsed
+func tracedQuery(tracer *QueryTracer, sql string, args []any, elapsed time.Duration, err error) {
+	start := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracer.now = func() time.Time { return start }
+	ctx := tracer.TraceQueryStart(context.Background(), nil, pgx.TraceQueryStartData{SQL: sql, Args: args})
+	tracer.now = func() time.Time { return start.Add(elapsed) }
+	tracer.TraceQueryEnd(ctx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1"), Err: err})
+}
+
+func TestQueryTracer_Stats(t *testing.T) {
+	tracer := NewQueryTracer(0)
+	for range 98 {
+		tracedQuery(tracer, "SELECT *\n\t\tFROM exercises WHERE id = $1", []any{"ex-1"}, 3*time.Millisecond, nil)
+	}
+	tracedQuery(tracer, "SELECT * FROM exercises   WHERE id = $1", []any{"ex-1"}, 400*time.Millisecond, nil)
+	tracedQuery(tracer, "SELECT * FROM exercises WHERE id = $1", []any{"ex-1"}, 20*time.Second, errors.New("canceled"))
+	tracedQuery(tracer, "SELECT 1", nil, time.Millisecond, nil)
+
+	stats := tracer.Stats(10)
+	if len(stats) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(stats))
+	}
+	s := stats[0]
+	if s.Statement != "SELECT * FROM exercises WHERE id = $1" || s.Calls != 100 || s.Errors != 1 {
+		t.Errorf("Unexpected stats %+v", s)
+	}
+	if s.P50Ms != 5 || s.P95Ms != 5 || s.P99Ms != 500 || s.MaxMs != 20000 {
+		t.Errorf("Expected p50 5ms, p95 5ms, p99 500ms and max 20s, got %+v", s)
+	}
+	if s.TotalMs != 98*3+400+20000 {
+		t.Errorf("Expected total of %dms, got %v", 98*3+400+20000, s.TotalMs)
+	}
+
+	if limited := tracer.Stats(1); len(limited) != 1 || limited[0].Statement != s.Statement {
+		t.Errorf("Expected only the slowest statement in total, got %+v", limited)
+	}
+}
+
+func TestQueryTracer_SlowQueryLogRedactsArgs(t *testing.T) {
+	var out bytes.Buffer
+	prev := log.Writer()
+	log.SetOutput(&out)
+	defer log.SetOutput(prev)
+
+	tracer := NewQueryTracer(100 * time.Millisecond)
+	tracedQuery(tracer, "SELECT * FROM users WHERE email = $1", []any{"jane@example.com", 42, nil}, 50*time.Millisecond, nil)
+	if out.Len() != 0 {
+		t.Errorf("Expected fast queries not to be logged, got %q", out.String())
+	}
+
+	tracedQuery(tracer, "SELECT * FROM users WHERE email = $1", []any{"jane@example.com", 42, nil}, 150*time.Millisecond, nil)
+	logged := out.String()
+	if !strings.Contains(logged, "Slow query (150ms") || !strings.Contains(logged, "[$1=string(16) $2=int $3=nil]") {
+		t.Errorf("Expected the slow query with its argument types, got %q", logged)
+	}
+	if strings.Contains(logged, "jane@example.com") || strings.Contains(logged, "42") {
+		t.Errorf("Expected argument values to be redacted, got %q", logged)
+	}
+}
+
+func TestQueryTracer_Batch(t *testing.T) {
+	tracer := NewQueryTracer(0)
+	ctx := tracer.TraceBatchStart(context.Background(), nil, pgx.TraceBatchStartData{})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{SQL: "SELECT * FROM workouts WHERE id = $1"})
+	tracer.TraceBatchQuery(ctx, nil, pgx.TraceBatchQueryData{SQL: "SELECT * FROM workout_exercises WHERE workout_id = $1", Err: errors.New("boom")})
+	tracer.TraceBatchEnd(ctx, nil, pgx.TraceBatchEndData{})
+
+	stats := tracer.Stats(10)
+	want := "batch: SELECT * FROM workouts WHERE id = $1; SELECT * FROM workout_exercises WHERE workout_id = $1"
+	if len(stats) != 1 || stats[0].Statement != want || stats[0].Calls != 1 || stats[0].Errors != 1 {
+		t.Errorf("Expected one failed batch, got %+v", stats)
+	}
+}
+
+func TestQueryTracer_BoundsStatements(t *testing.T) {
+	tracer := NewQueryTracer(0)
+	for i := range maxTracedStatements + 5 {
+		tracedQuery(tracer, "SELECT "+strconv.Itoa(i), nil, time.Millisecond, nil)
+	}
+
+	stats := tracer.Stats(maxTracedStatements + 5)
+	if len(stats) != maxTracedStatements+1 {
+		t.Fatalf("Expected %d statements, got %d", maxTracedStatements+1, len(stats))
+	}
+	for _, s := range stats {
+		if s.Statement == otherStatements && s.Calls != 5 {
+			t.Errorf("Expected 5 calls beyond the bound, got %d", s.Calls)
+		}
+	}
+}
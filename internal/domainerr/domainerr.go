@@ -0,0 +1,86 @@
+// Package domainerr sorts the errors repositories and services return into categories,
+// so handlers can answer them with one mapping instead of matching every sentinel
+//
+// Sentinels are created with New and keep their own identity, so errors.Is still matches
+// them one by one; errors.Is against a category matches every sentinel in it, wrapped or not.
+package domainerr
+
+import (
+	"context"
+	"errors"
+)
+
+// Categories. NotFound through Transient cover most errors; Gone and Unprocessable are
+// kept apart so expired resources and rows still in use keep their own status, and Timeout
+// so work that ran out of time is not reported as a server fault.
+var (
+	NotFound      = errors.New("not found")
+	Conflict      = errors.New("conflict")
+	Forbidden     = errors.New("forbidden")
+	Validation    = errors.New("validation failed")
+	Transient     = errors.New("temporarily unavailable")
+	Gone          = errors.New("gone")
+	Unprocessable = errors.New("unprocessable")
+	Timeout       = errors.New("timed out")
+)
+
+// categories lists every category, for CategoryOf
+var categories = []error{NotFound, Conflict, Forbidden, Validation, Transient, Gone, Unprocessable, Timeout}
+
+// sqlStateQueryCanceled is the SQLSTATE Postgres reports when statement_timeout cancels a
+// query, or when a context cancels it
+const sqlStateQueryCanceled = "57014"
+
+// Error is a sentinel belonging to a category
+type Error struct {
+	category error
+	message  string
+}
+
+// New returns a sentinel in category with message as its text
+func New(category error, message string) *Error {
+	return &Error{category: category, message: message}
+}
+
+func (e *Error) Error() string {
+	return e.message
+}
+
+// Is matches the error's category, so errors.Is(err, NotFound) finds any not-found sentinel
+func (e *Error) Is(target error) bool {
+	return target == e.category
+}
+
+// Category returns the category the error belongs to
+func (e *Error) Category() error {
+	return e.category
+}
+
+// CategoryOf returns the category of the first categorized error in err's chain, or nil
+// when there is none, e.g. for driver and network errors. A passed deadline and a canceled
+// query are Timeout even though neither is a sentinel.
+func CategoryOf(err error) error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.category
+	}
+	for _, category := range categories {
+		if errors.Is(err, category) {
+			return category
+		}
+	}
+	if isTimeout(err) {
+		return Timeout
+	}
+	return nil
+}
+
+// isTimeout reports whether err is a passed deadline or a query Postgres canceled. Driver
+// errors are matched by their SQLState method so this package need not import the driver.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var sqlErr interface{ SQLState() string }
+	return errors.As(err, &sqlErr) && sqlErr.SQLState() == sqlStateQueryCanceled
+}
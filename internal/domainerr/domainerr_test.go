@@ -0,0 +1,65 @@
+package domainerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestErrorMatchesItselfAndItsCategory(t *testing.T) {
+	errMissing := New(NotFound, "thing not found")
+	wrapped := fmt.Errorf("failed to get thing: %w", errMissing)
+
+	if !errors.Is(wrapped, errMissing) {
+		t.Error("expected wrapped error to match its sentinel")
+	}
+	if !errors.Is(wrapped, NotFound) {
+		t.Error("expected wrapped error to match its category")
+	}
+	if errors.Is(wrapped, Conflict) {
+		t.Error("expected wrapped error not to match another category")
+	}
+	if errors.Is(wrapped, New(NotFound, "thing not found")) {
+		t.Error("expected sentinels with the same text to stay distinct")
+	}
+}
+
+func TestErrorText(t *testing.T) {
+	err := fmt.Errorf("%w: name is required", New(Validation, "invalid thing"))
+	if got := err.Error(); got != "invalid thing: name is required" {
+		t.Errorf("expected the sentinel's text with the detail, got %q", got)
+	}
+}
+
+func TestCategoryOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"sentinel", New(Forbidden, "not yours"), Forbidden},
+		{"wrapped sentinel", fmt.Errorf("failed: %w", New(Gone, "expired")), Gone},
+		{"wrapped category", fmt.Errorf("%w: busy", Transient), Transient},
+		{"first categorized in chain", fmt.Errorf("%w: %w", New(Conflict, "duplicate"), New(Validation, "bad")), Conflict},
+		{"deadline", fmt.Errorf("failed: %w", context.DeadlineExceeded), Timeout},
+		{"canceled query", fmt.Errorf("failed: %w", sqlStateError("57014")), Timeout},
+		{"other driver error", sqlStateError("23505"), nil},
+		{"uncategorized", errors.New("connection reset"), nil},
+		{"nil", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CategoryOf(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// sqlStateError stands in for a driver error carrying a SQLSTATE
+type sqlStateError string
+
+func (e sqlStateError) Error() string    { return "sqlstate " + string(e) }
+func (e sqlStateError) SQLState() string { return string(e) }
@@ -0,0 +1,123 @@
+// Package events is a publish/subscribe hub for events addressed to users, so handlers and
+// background jobs can notify a user's connected clients without knowing about them
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// subscriberBuffer is how many events a slow subscriber may fall behind before events
+// are dropped for it
+const subscriberBuffer = 16
+
+// Publisher publishes events to a user's connected clients
+type Publisher interface {
+	Publish(ctx context.Context, userID string, eventType string, data any) error
+}
+
+// New builds an event for a user; data is marshaled to JSON
+func New(userID string, eventType string, data any) (*models.UserEvent, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return &models.UserEvent{
+		ID:        uuid.NewString(),
+		Type:      eventType,
+		UserID:    userID,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	}, nil
+}
+
+// Bus delivers events to subscribers in this process
+type Bus struct {
+	mu          sync.Mutex
+	subscribers map[string]map[chan *models.UserEvent]struct{}
+	closed      bool
+}
+
+// NewBus creates an empty bus
+func NewBus() *Bus {
+	return &Bus{subscribers: make(map[string]map[chan *models.UserEvent]struct{})}
+}
+
+// Publish delivers an event to the user's subscribers in this process
+func (b *Bus) Publish(ctx context.Context, userID string, eventType string, data any) error {
+	event, err := New(userID, eventType, data)
+	if err != nil {
+		return err
+	}
+	b.Deliver(event)
+	return nil
+}
+
+// Deliver sends an event to its user's subscribers without blocking on slow ones
+func (b *Bus) Deliver(event *models.UserEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for events := range b.subscribers[event.UserID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
+
+// Subscribe returns a channel of the user's events. The caller must call the returned
+// cancel function when it stops listening. The channel is closed when the bus is closed.
+func (b *Bus) Subscribe(userID string) (<-chan *models.UserEvent, func()) {
+	events := make(chan *models.UserEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(events)
+		return events, func() {}
+	}
+	if b.subscribers[userID] == nil {
+		b.subscribers[userID] = make(map[chan *models.UserEvent]struct{})
+	}
+	b.subscribers[userID][events] = struct{}{}
+	b.mu.Unlock()
+
+	cancel := func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(userID, events)
+	}
+	return events, cancel
+}
+
+// Close closes every subscriber's channel, so their streams end, and any channel
+// subscribed afterwards
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for userID, subscribers := range b.subscribers {
+		for events := range subscribers {
+			b.remove(userID, events)
+		}
+	}
+}
+
+// remove closes a subscriber's channel once; callers hold b.mu
+func (b *Bus) remove(userID string, events chan *models.UserEvent) {
+	if _, ok := b.subscribers[userID][events]; !ok {
+		return
+	}
+	close(events)
+	delete(b.subscribers[userID], events)
+	if len(b.subscribers[userID]) == 0 {
+		delete(b.subscribers, userID)
+	}
+}
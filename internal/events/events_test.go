@@ -0,0 +1,78 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+func TestBus_DeliversToUserSubscribers(t *testing.T) {
+	bus := NewBus()
+	events, cancel := bus.Subscribe("user-123")
+	other, cancelOther := bus.Subscribe("user-789")
+	defer cancelOther()
+
+	data := &models.ImportFinishedEvent{Source: "strong", SessionsCreated: 3}
+	if err := bus.Publish(context.Background(), "user-123", models.UserEventImportFinished, data); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case event := <-events:
+		var got models.ImportFinishedEvent
+		if err := json.Unmarshal(event.Data, &got); err != nil {
+			t.Fatalf("Expected JSON data, got %v", err)
+		}
+		if event.Type != models.UserEventImportFinished || event.ID == "" || got.SessionsCreated != 3 {
+			t.Errorf("Unexpected event: %+v %+v", event, got)
+		}
+	default:
+		t.Fatal("Expected an event for the subscriber")
+	}
+	select {
+	case event := <-other:
+		t.Errorf("Expected no event for another user, got %+v", event)
+	default:
+	}
+
+	cancel()
+	if _, ok := bus.subscribers["user-123"]; ok {
+		t.Error("Expected cancel to remove the subscriber")
+	}
+}
+
+func TestBus_DropsEventsForSlowSubscribers(t *testing.T) {
+	bus := NewBus()
+	events, cancel := bus.Subscribe("user-123")
+	defer cancel()
+
+	for i := 0; i < subscriberBuffer+5; i++ {
+		bus.Deliver(&models.UserEvent{UserID: "user-123", Type: models.UserEventPRAchieved})
+	}
+
+	if len(events) != subscriberBuffer {
+		t.Errorf("Expected %d buffered events, got %d", subscriberBuffer, len(events))
+	}
+}
+
+func TestBus_CloseEndsSubscriptions(t *testing.T) {
+	bus := NewBus()
+	events, cancel := bus.Subscribe("user-123")
+
+	bus.Close()
+	if _, ok := <-events; ok {
+		t.Error("Expected close to close the subscriber's channel")
+	}
+	cancel()
+
+	late, cancelLate := bus.Subscribe("user-123")
+	defer cancelLate()
+	if _, ok := <-late; ok {
+		t.Error("Expected subscriptions after close to be closed")
+	}
+	if len(bus.subscribers) != 0 {
+		t.Errorf("Expected no subscribers after close, got %d", len(bus.subscribers))
+	}
+}
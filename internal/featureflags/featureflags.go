@@ -0,0 +1,227 @@
+// Package featureflags decides which users get a feature that is being rolled out.
+//
+// A flag is off, or on for a percentage of users. Users are bucketed by a hash of the flag
+// name and their ID, so each keeps their answer as the percentage grows, and different
+// flags reach different users first. Overrides turn a flag on or off for single users,
+// e.g. staff trying a feature before anyone else. Flags are stored in the database and
+// edited at runtime; configuration sets the defaults of flags that aren't stored.
+package featureflags
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// Flags consulted by the API
+const (
+	// Recommendations gates the workout recommendation engine
+	Recommendations = "recommendations"
+)
+
+// RefreshInterval is how long stored flags are used before they are read again, which is
+// how long an edit made on another instance takes to apply here
+const RefreshInterval = 30 * time.Second
+
+// Builtin are the defaults of the flags the API consults, before configuration
+var Builtin = []*models.FeatureFlag{
+	{Name: Recommendations, Description: "Workout recommendations", Enabled: true, RolloutPercent: 100},
+}
+
+var namePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,62}$`)
+
+// ValidName reports whether name can name a flag: lowercase letters, digits and
+// underscores, starting with a letter
+func ValidName(name string) bool {
+	return namePattern.MatchString(name)
+}
+
+// Store loads the stored flags with their overrides
+type Store interface {
+	Load(ctx context.Context) ([]*models.FeatureFlag, error)
+}
+
+// Flags answers flag checks from a snapshot of the stored flags over the defaults
+type Flags struct {
+	store    Store
+	defaults map[string]*models.FeatureFlag
+	now      func() time.Time
+
+	mu       sync.Mutex
+	stored   map[string]*models.FeatureFlag
+	loadedAt time.Time
+}
+
+// New creates flags read from store, falling back to the builtin defaults overridden by
+// defaults
+func New(store Store, defaults []*models.FeatureFlag) *Flags {
+	f := &Flags{store: store, defaults: make(map[string]*models.FeatureFlag), now: time.Now}
+	for _, flag := range Builtin {
+		f.defaults[flag.Name] = flag
+	}
+	for _, flag := range defaults {
+		if builtin, ok := f.defaults[flag.Name]; ok && flag.Description == "" {
+			configured := *flag
+			configured.Description = builtin.Description
+			flag = &configured
+		}
+		f.defaults[flag.Name] = flag
+	}
+	return f
+}
+
+// Enabled reports whether a flag is on for userID. Anonymous callers (empty userID) only
+// get flags rolled out to everyone; unknown flags are off. A nil Flags enables everything,
+// so services built without flags work unchanged.
+func (f *Flags) Enabled(ctx context.Context, name string, userID string) bool {
+	if f == nil {
+		return true
+	}
+	flag, ok := f.flags(ctx)[name]
+	if !ok {
+		return false
+	}
+	return enabledFor(flag, userID)
+}
+
+// For returns whether each flag is on for userID
+func (f *Flags) For(ctx context.Context, userID string) map[string]bool {
+	states := make(map[string]bool)
+	if f == nil {
+		return states
+	}
+	for name, flag := range f.flags(ctx) {
+		states[name] = enabledFor(flag, userID)
+	}
+	return states
+}
+
+// All returns every flag by name, stored ones over defaults
+func (f *Flags) All(ctx context.Context) []*models.FeatureFlag {
+	flags := f.flags(ctx)
+	all := make([]*models.FeatureFlag, 0, len(flags))
+	for _, flag := range flags {
+		all = append(all, flag)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Name < all[j].Name })
+	return all
+}
+
+// Get returns a flag, or nil if it is neither stored nor a default
+func (f *Flags) Get(ctx context.Context, name string) *models.FeatureFlag {
+	return f.flags(ctx)[name]
+}
+
+// Reload reads the stored flags right away, e.g. after an edit. On failure the previous
+// snapshot is kept.
+func (f *Flags) Reload(ctx context.Context) error {
+	flags, err := f.store.Load(ctx)
+	if err != nil {
+		return err
+	}
+
+	stored := make(map[string]*models.FeatureFlag, len(flags))
+	for _, flag := range flags {
+		stored[flag.Name] = flag
+	}
+
+	f.mu.Lock()
+	f.stored = stored
+	f.loadedAt = f.now()
+	f.mu.Unlock()
+	return nil
+}
+
+// flags returns the stored flags over the defaults, reloading them once RefreshInterval
+// has passed. A failed reload is logged and retried after another interval.
+func (f *Flags) flags(ctx context.Context) map[string]*models.FeatureFlag {
+	f.mu.Lock()
+	stale := f.now().Sub(f.loadedAt) >= RefreshInterval
+	if stale {
+		// Other checks use the current snapshot meanwhile instead of loading too
+		f.loadedAt = f.now()
+	}
+	f.mu.Unlock()
+
+	if stale {
+		if err := f.Reload(ctx); err != nil {
+			log.Printf("Feature flags failed to load, keeping the previous ones: %v", err)
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	flags := make(map[string]*models.FeatureFlag, len(f.defaults)+len(f.stored))
+	for name, flag := range f.defaults {
+		flags[name] = flag
+	}
+	for name, flag := range f.stored {
+		flags[name] = flag
+	}
+	return flags
+}
+
+func enabledFor(flag *models.FeatureFlag, userID string) bool {
+	if userID == "" {
+		return flag.Enabled && flag.RolloutPercent >= 100
+	}
+	if enabled, ok := flag.Overrides[userID]; ok {
+		return enabled
+	}
+	return flag.Enabled && Bucket(flag.Name, userID) < flag.RolloutPercent
+}
+
+// Bucket places a user in 0..99 for a flag; a flag rolled out to N percent is on for the
+// users in buckets below N
+func Bucket(name string, userID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + userID))
+	return int(h.Sum32() % 100)
+}
+
+// ParseDefaults reads flag defaults written as "name=state", comma separated, where state
+// is on, off or a rollout percentage, e.g. "recommendations=25,new_feed=off"
+func ParseDefaults(spec string) ([]*models.FeatureFlag, error) {
+	var flags []*models.FeatureFlag
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, state, ok := strings.Cut(entry, "=")
+		if !ok || !ValidName(name) {
+			return nil, fmt.Errorf("invalid feature flag %q, expected name=on, name=off or name=percent", entry)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("duplicate feature flag %q", name)
+		}
+		seen[name] = true
+
+		flag := &models.FeatureFlag{Name: name, RolloutPercent: 100}
+		switch state {
+		case "on":
+			flag.Enabled = true
+		case "off":
+		default:
+			percent, err := strconv.Atoi(strings.TrimSuffix(state, "%"))
+			if err != nil || percent < 0 || percent > 100 {
+				return nil, fmt.Errorf("invalid state %q for feature flag %q, expected on, off or 0-100", state, name)
+			}
+			flag.Enabled = true
+			flag.RolloutPercent = percent
+		}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
@@ -0,0 +1,141 @@
+package featureflags
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+type stubStore struct {
+	flags []*models.FeatureFlag
+	err   error
+	loads int
+}
+
+func (s *stubStore) Load(ctx context.Context) ([]*models.FeatureFlag, error) {
+	s.loads++
+	return s.flags, s.err
+}
+
+func TestEnabled_RolloutIsStablePerUser(t *testing.T) {
+	ctx := context.Background()
+	store := &stubStore{flags: []*models.FeatureFlag{{Name: "new_feed", Enabled: true, RolloutPercent: 30}}}
+	flags := New(store, nil)
+
+	on := 0
+	for i := 0; i < 1000; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		enabled := flags.Enabled(ctx, "new_feed", userID)
+		if enabled != (Bucket("new_feed", userID) < 30) {
+			t.Fatalf("Expected %s to follow their bucket", userID)
+		}
+		if enabled {
+			on++
+		}
+	}
+	if on < 250 || on > 350 {
+		t.Errorf("Expected about 30%% of users enabled, got %d of 1000", on)
+	}
+
+	// Growing the rollout keeps everyone who had the feature
+	store.flags = []*models.FeatureFlag{{Name: "new_feed", Enabled: true, RolloutPercent: 60}}
+	if err := flags.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		userID := fmt.Sprintf("user-%d", i)
+		if Bucket("new_feed", userID) < 30 && !flags.Enabled(ctx, "new_feed", userID) {
+			t.Fatalf("Expected %s to keep the feature", userID)
+		}
+	}
+}
+
+func TestEnabled_OverridesAndAnonymousCallers(t *testing.T) {
+	ctx := context.Background()
+	flags := New(&stubStore{flags: []*models.FeatureFlag{
+		{Name: "beta", Enabled: false, RolloutPercent: 100, Overrides: map[string]bool{"staff": true}},
+		{Name: "half", Enabled: true, RolloutPercent: 50, Overrides: map[string]bool{"opted-out": false}},
+		{Name: "everyone", Enabled: true, RolloutPercent: 100},
+	}}, nil)
+
+	if !flags.Enabled(ctx, "beta", "staff") || flags.Enabled(ctx, "beta", "someone") {
+		t.Error("Expected a disabled flag to be on only for its overrides")
+	}
+	if flags.Enabled(ctx, "half", "opted-out") {
+		t.Error("Expected an override to turn the flag off")
+	}
+	if flags.Enabled(ctx, "half", "") || !flags.Enabled(ctx, "everyone", "") {
+		t.Error("Expected anonymous callers to only get fully rolled out flags")
+	}
+	if flags.Enabled(ctx, "unknown", "staff") {
+		t.Error("Expected unknown flags to be off")
+	}
+
+	var none *Flags
+	if !none.Enabled(ctx, Recommendations, "user-1") {
+		t.Error("Expected a nil Flags to enable everything")
+	}
+}
+
+func TestFlags_StoredOverDefaultsAndRefresh(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	store := &stubStore{}
+	flags := New(store, []*models.FeatureFlag{{Name: Recommendations, Enabled: false, RolloutPercent: 100}})
+	flags.now = func() time.Time { return now }
+
+	if flags.Enabled(ctx, Recommendations, "user-1") {
+		t.Error("Expected configuration to override the builtin default")
+	}
+	if got := flags.Get(ctx, Recommendations).Description; got == "" {
+		t.Error("Expected the builtin description to be kept")
+	}
+
+	store.flags = []*models.FeatureFlag{{Name: Recommendations, Enabled: true, RolloutPercent: 100, Stored: true}}
+	if flags.Enabled(ctx, Recommendations, "user-1") {
+		t.Error("Expected the snapshot to be used until it is stale")
+	}
+	now = now.Add(RefreshInterval)
+	if !flags.Enabled(ctx, Recommendations, "user-1") {
+		t.Error("Expected the stored flag after a refresh")
+	}
+
+	// A failing store keeps the last snapshot
+	store.err = errors.New("connection refused")
+	now = now.Add(RefreshInterval)
+	if !flags.Enabled(ctx, Recommendations, "user-1") {
+		t.Error("Expected the previous snapshot when loading fails")
+	}
+	if store.loads != 3 {
+		t.Errorf("Expected 3 loads, got %d", store.loads)
+	}
+}
+
+func TestParseDefaults(t *testing.T) {
+	flags, err := ParseDefaults(" recommendations=25%, new_feed=off,beta=on ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(flags) != 3 {
+		t.Fatalf("Expected 3 flags, got %d", len(flags))
+	}
+	if f := flags[0]; !f.Enabled || f.RolloutPercent != 25 {
+		t.Errorf("Expected recommendations at 25%%, got %+v", f)
+	}
+	if f := flags[1]; f.Enabled {
+		t.Errorf("Expected new_feed off, got %+v", f)
+	}
+	if f := flags[2]; !f.Enabled || f.RolloutPercent != 100 {
+		t.Errorf("Expected beta on for everyone, got %+v", f)
+	}
+
+	for _, spec := range []string{"beta", "Beta=on", "beta=101", "beta=maybe", "beta=on,beta=off"} {
+		if _, err := ParseDefaults(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
@@ -0,0 +1,321 @@
+// Package googlefit is a minimal client for Google's OAuth 2.0 and Fitness REST APIs
+package googlefit
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidGrant is returned when Google rejects an authorization code or refresh token,
+	// e.g. because the user revoked access; the user has to connect again
+	ErrInvalidGrant = errors.New("google fit authorization is no longer valid")
+)
+
+// Scopes requested when connecting: workout sessions and body measurements, read-only
+var Scopes = []string{
+	"https://www.googleapis.com/auth/fitness.activity.read",
+	"https://www.googleapis.com/auth/fitness.body.read",
+}
+
+const (
+	defaultAuthURL    = "https://accounts.google.com/o/oauth2/v2/auth"
+	defaultTokenURL   = "https://oauth2.googleapis.com/token"
+	defaultFitnessURL = "https://www.googleapis.com/fitness/v1/users/me"
+
+	// weightDataSource is Google Fit's merged stream of the user's weigh-ins
+	weightDataSource = "derived:com.google.weight:com.google.android.gms:merge_weight"
+)
+
+// Config holds the OAuth client registered in the Google Cloud console
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// Token is an OAuth token pair
+type Token struct {
+	AccessToken  string
+	RefreshToken string // Only returned on the first exchange; refreshes keep the old one
+	ExpiresAt    time.Time
+	Scope        string
+}
+
+// Session is a workout recorded in Google Fit
+type Session struct {
+	ID           string
+	Name         string
+	Description  string
+	ActivityType int
+	StartedAt    time.Time
+	EndedAt      time.Time
+}
+
+// WeightPoint is a single weigh-in recorded in Google Fit
+type WeightPoint struct {
+	MeasuredAt time.Time
+	WeightKg   float64
+}
+
+// Client calls Google's OAuth and Fitness endpoints
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	now        func() time.Time
+
+	// Endpoints, overridden in tests
+	AuthURL    string
+	TokenURL   string
+	FitnessURL string
+}
+
+// NewClient creates a Google Fit client; a nil httpClient uses one with a 30s timeout
+func NewClient(config Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		now:        time.Now,
+		AuthURL:    defaultAuthURL,
+		TokenURL:   defaultTokenURL,
+		FitnessURL: defaultFitnessURL,
+	}
+}
+
+// Configured reports whether OAuth credentials were provided
+func (c *Client) Configured() bool {
+	return c.config.ClientID != "" && c.config.ClientSecret != "" && c.config.RedirectURL != ""
+}
+
+// AuthCodeURL returns the consent page URL. Offline access with a forced consent prompt
+// makes Google return a refresh token even if the user connected before.
+func (c *Client) AuthCodeURL(state string) string {
+	params := url.Values{
+		"client_id":     {c.config.ClientID},
+		"redirect_uri":  {c.config.RedirectURL},
+		"response_type": {"code"},
+		"scope":         {strings.Join(Scopes, " ")},
+		"access_type":   {"offline"},
+		"prompt":        {"consent"},
+		"state":         {state},
+	}
+	return c.AuthURL + "?" + params.Encode()
+}
+
+// Exchange trades an authorization code for tokens
+func (c *Client) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":   {"authorization_code"},
+		"code":         {code},
+		"redirect_uri": {c.config.RedirectURL},
+	})
+}
+
+// Refresh obtains a new access token with a refresh token
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	token, err := c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if token.RefreshToken == "" {
+		token.RefreshToken = refreshToken
+	}
+	return token, nil
+}
+
+func (c *Client) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Scope        string `json:"scope"`
+		Error        string `json:"error"`
+	}
+	status, err := c.do(req, &body)
+	if err != nil {
+		return nil, err
+	}
+	if body.Error == "invalid_grant" {
+		return nil, ErrInvalidGrant
+	}
+	if status != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed with status %d: %s", status, body.Error)
+	}
+
+	return &Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		ExpiresAt:    c.now().Add(time.Duration(body.ExpiresIn) * time.Second),
+		Scope:        body.Scope,
+	}, nil
+}
+
+// Sessions lists the workout sessions that ended between from and to, following pagination
+func (c *Client) Sessions(ctx context.Context, accessToken string, from, to time.Time) ([]*Session, error) {
+	params := url.Values{
+		"startTime": {from.UTC().Format(time.RFC3339)},
+		"endTime":   {to.UTC().Format(time.RFC3339)},
+	}
+
+	var sessions []*Session
+	for {
+		var page struct {
+			Session []struct {
+				ID              string `json:"id"`
+				Name            string `json:"name"`
+				Description     string `json:"description"`
+				ActivityType    int    `json:"activityType"`
+				StartTimeMillis string `json:"startTimeMillis"`
+				EndTimeMillis   string `json:"endTimeMillis"`
+			} `json:"session"`
+			NextPageToken string `json:"nextPageToken"`
+		}
+		if err := c.get(ctx, accessToken, "/sessions?"+params.Encode(), &page); err != nil {
+			return nil, err
+		}
+
+		for _, s := range page.Session {
+			start, err := parseMillis(s.StartTimeMillis)
+			if err != nil {
+				return nil, fmt.Errorf("session %s: invalid start time", s.ID)
+			}
+			end, err := parseMillis(s.EndTimeMillis)
+			if err != nil {
+				return nil, fmt.Errorf("session %s: invalid end time", s.ID)
+			}
+			sessions = append(sessions, &Session{
+				ID:           s.ID,
+				Name:         s.Name,
+				Description:  s.Description,
+				ActivityType: s.ActivityType,
+				StartedAt:    start,
+				EndedAt:      end,
+			})
+		}
+
+		// Google repeats the last page token when there is nothing more to read
+		if page.NextPageToken == "" || page.NextPageToken == params.Get("pageToken") {
+			return sessions, nil
+		}
+		params.Set("pageToken", page.NextPageToken)
+	}
+}
+
+// Weights lists the weigh-ins recorded between from and to
+func (c *Client) Weights(ctx context.Context, accessToken string, from, to time.Time) ([]*WeightPoint, error) {
+	path := fmt.Sprintf("/dataSources/%s/datasets/%d-%d", weightDataSource, from.UnixNano(), to.UnixNano())
+
+	var dataset struct {
+		Point []struct {
+			StartTimeNanos string `json:"startTimeNanos"`
+			Value          []struct {
+				FpVal float64 `json:"fpVal"`
+			} `json:"value"`
+		} `json:"point"`
+	}
+	if err := c.get(ctx, accessToken, path, &dataset); err != nil {
+		return nil, err
+	}
+
+	weights := make([]*WeightPoint, 0, len(dataset.Point))
+	for _, p := range dataset.Point {
+		nanos, err := strconv.ParseInt(p.StartTimeNanos, 10, 64)
+		if err != nil || len(p.Value) == 0 || p.Value[0].FpVal <= 0 {
+			continue
+		}
+		weights = append(weights, &WeightPoint{
+			MeasuredAt: time.Unix(0, nanos).UTC(),
+			WeightKg:   p.Value[0].FpVal,
+		})
+	}
+
+	return weights, nil
+}
+
+func (c *Client) get(ctx context.Context, accessToken string, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.FitnessURL+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	status, err := c.do(req, out)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusUnauthorized {
+		return ErrInvalidGrant
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("google fit request %s failed with status %d", strings.SplitN(path, "?", 2)[0], status)
+	}
+	return nil
+}
+
+// do sends the request and decodes a JSON body into out, whatever the status
+func (c *Client) do(req *http.Request, out any) (int, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return 0, err
+	}
+	if len(data) > 0 && json.Unmarshal(data, out) != nil && resp.StatusCode == http.StatusOK {
+		return 0, fmt.Errorf("invalid response from %s", req.URL.Host)
+	}
+	return resp.StatusCode, nil
+}
+
+func parseMillis(raw string) (time.Time, error) {
+	ms, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.UnixMilli(ms).UTC(), nil
+}
+
+// activityNames names the Google Fit activity types commonly used for workouts
+// See https://developers.google.com/fit/rest/v1/reference/activity-types
+var activityNames = map[int]string{
+	1:   "Biking",
+	7:   "Walking",
+	8:   "Running",
+	80:  "Strength training",
+	82:  "Swimming",
+	100: "Yoga",
+}
+
+// ActivityName returns a readable name for a Google Fit activity type
+func ActivityName(activityType int) string {
+	if name, ok := activityNames[activityType]; ok {
+		return name
+	}
+	return "Workout"
+}
@@ -0,0 +1,99 @@
+package googlefit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient(Config{ClientID: "id", ClientSecret: "secret", RedirectURL: "https://app.example.com/cb"}, server.Client())
+	client.TokenURL = server.URL + "/token"
+	client.FitnessURL = server.URL + "/fitness"
+	client.now = func() time.Time { return time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC) }
+	return client
+}
+
+func TestRefresh(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("refresh_token") != "refresh" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("Unexpected token request: %v", r.PostForm)
+		}
+		w.Write([]byte(`{"access_token":"access","expires_in":3600}`))
+	})
+
+	token, err := client.Refresh(context.Background(), "refresh")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" {
+		t.Errorf("Expected new access token keeping the refresh token, got %+v", token)
+	}
+	if want := client.now().Add(time.Hour); !token.ExpiresAt.Equal(want) {
+		t.Errorf("Expected expiry %v, got %v", want, token.ExpiresAt)
+	}
+}
+
+func TestRefresh_InvalidGrant(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+	})
+
+	if _, err := client.Refresh(context.Background(), "refresh"); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Expected ErrInvalidGrant, got %v", err)
+	}
+}
+
+func TestSessions_FollowsPages(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			w.Write([]byte(`{"session":[{"id":"a","activityType":8,"startTimeMillis":"1778673600000","endTimeMillis":"1778677200000"}],"nextPageToken":"p2"}`))
+		default:
+			w.Write([]byte(`{"session":[{"id":"b","name":"Leg day","activityType":80,"startTimeMillis":"1778680800000","endTimeMillis":"1778684400000"}],"nextPageToken":"p2"}`))
+		}
+	})
+
+	sessions, err := client.Sessions(context.Background(), "access", time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 2 || sessions[1].Name != "Leg day" {
+		t.Fatalf("Expected 2 sessions across pages, got %+v", sessions)
+	}
+	if sessions[0].EndedAt.Sub(sessions[0].StartedAt) != time.Hour {
+		t.Errorf("Expected a one hour session, got %v", sessions[0].EndedAt.Sub(sessions[0].StartedAt))
+	}
+
+	if _, err := client.Sessions(context.Background(), "expired", time.Now(), time.Now()); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Expected ErrInvalidGrant for a rejected access token, got %v", err)
+	}
+}
+
+func TestWeights(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"point":[
+			{"startTimeNanos":"1778673600000000000","value":[{"fpVal":81.2}]},
+			{"startTimeNanos":"1778760000000000000","value":[]}
+		]}`))
+	})
+
+	weights, err := client.Weights(context.Background(), "access", time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(weights) != 1 || weights[0].WeightKg != 81.2 {
+		t.Errorf("Expected one weigh-in of 81.2kg, got %+v", weights)
+	}
+}
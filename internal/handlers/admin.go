@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// AdminHandler handles HTTP requests for operator endpoints
+type AdminHandler struct {
+	service *services.AdminService
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(service *services.AdminService) *AdminHandler {
+	return &AdminHandler{service: service}
+}
+
+// Storage handles GET /api/admin/storage
+// ?limit=N controls how many of the largest users are returned (default 10, max 100)
+func (h *AdminHandler) Storage(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	report, err := h.service.StorageReport(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to build storage report"})
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
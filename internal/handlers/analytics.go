@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// AnalyticsHandler handles HTTP requests for dashboard aggregates
+type AnalyticsHandler struct {
+	service *services.AnalyticsService
+}
+
+// NewAnalyticsHandler creates a new analytics handler
+func NewAnalyticsHandler(service *services.AnalyticsService) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service}
+}
+
+// MuscleVolume handles GET /api/analytics/muscle-volume?from=2026-01-05&to=2026-04-06
+// to defaults to tomorrow in the user's timezone (so today is included) and from to
+// twelve weeks before to.
+func (h *AnalyticsHandler) MuscleVolume(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var to time.Time
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+			return
+		}
+		to = parsed
+	}
+
+	var from time.Time
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+			return
+		}
+		from = parsed
+	}
+
+	volume, err := h.service.GetMuscleVolume(c.Request.Context(), userID, from, to)
+	if err != nil {
+		respondError(c, err, "failed to get muscle volume")
+		return
+	}
+
+	c.JSON(http.StatusOK, volume)
+}
+
+// DailySummary handles GET /api/analytics/daily-summary?date=2026-04-06
+// date defaults to today in the user's timezone.
+func (h *AnalyticsHandler) DailySummary(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var day time.Time
+	if raw := c.Query("date"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "date must be a date (YYYY-MM-DD)"})
+			return
+		}
+		day = parsed
+	}
+
+	summary, err := h.service.GetDailySummary(c.Request.Context(), userID, day)
+	if err != nil {
+		respondError(c, err, "failed to get daily summary")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// SleepPerformance handles GET /api/analytics/sleep-performance?from=2026-01-05&to=2026-04-06
+// Sessions are paired with the sleep logged the night before. to defaults to tomorrow in
+// the user's timezone (so today is included) and from to twelve weeks before to.
+func (h *AnalyticsHandler) SleepPerformance(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var to time.Time
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+			return
+		}
+		to = parsed
+	}
+
+	var from time.Time
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+			return
+		}
+		from = parsed
+	}
+
+	performance, err := h.service.GetSleepPerformance(c.Request.Context(), userID, from, to)
+	if err != nil {
+		respondError(c, err, "failed to get sleep performance")
+		return
+	}
+
+	c.JSON(http.StatusOK, performance)
+}
+
+// E1RMBests handles GET /api/analytics/e1rm-bests
+func (h *AnalyticsHandler) E1RMBests(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	bests, err := h.service.GetE1RMBests(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get e1RM bests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, bests)
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// AuditLogHandler handles HTTP requests for the audit log
+type AuditLogHandler struct {
+	service *services.AuditLogService
+}
+
+// NewAuditLogHandler creates a new audit log handler
+func NewAuditLogHandler(service *services.AuditLogService) *AuditLogHandler {
+	return &AuditLogHandler{service: service}
+}
+
+// List handles GET /api/admin/audit-logs?user_id=...&resource_type=equipment&resource_id=...&from=2026-03-01&to=2026-03-31&limit=100
+// Who created, updated or deleted what, newest first, with the fields each write changed.
+// user_id is the actor; from and to are inclusive days in UTC. limit defaults to 100, max 500.
+func (h *AuditLogHandler) List(c *gin.Context) {
+	filter := models.AuditLogFilter{
+		ActorID:      c.Query("user_id"),
+		ResourceType: c.Query("resource_type"),
+		ResourceID:   c.Query("resource_id"),
+	}
+	if raw := c.Query("from"); raw != "" {
+		from, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+			return
+		}
+		filter.From = &from
+	}
+	if raw := c.Query("to"); raw != "" {
+		to, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+			return
+		}
+		until := to.AddDate(0, 0, 1)
+		filter.Until = &until
+	}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		filter.Limit = n
+	}
+
+	entries, err := h.service.ListAuditLogs(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err, "failed to list audit logs")
+		return
+	}
+
+	c.JSON(http.StatusOK, entries)
+}
@@ -0,0 +1,49 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// AuthHandler handles HTTP requests for session management endpoints
+type AuthHandler struct {
+	revocations *services.TokenRevocationService
+}
+
+// NewAuthHandler creates a new auth handler
+func NewAuthHandler(revocations *services.TokenRevocationService) *AuthHandler {
+	return &AuthHandler{revocations: revocations}
+}
+
+// Logout handles POST /api/auth/logout
+// It revokes the token used for this request so it is rejected until it expires
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	// SKIP_AUTH requests carry no token, so there is nothing to revoke
+	tokenID := c.GetString("token_id")
+	if tokenID == "" {
+		c.JSON(http.StatusNoContent, nil)
+		return
+	}
+
+	expiresAt := c.GetTime("token_expires_at")
+	if expiresAt.IsZero() {
+		// Tokens without exp never expire naturally; keep the revocation for a day
+		expiresAt = time.Now().Add(24 * time.Hour)
+	}
+
+	if err := h.revocations.Revoke(c.Request.Context(), tokenID, userID, expiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to log out"})
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
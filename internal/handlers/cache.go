@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// CacheHandler handles HTTP requests for the read cache
+type CacheHandler struct {
+	service *services.CacheService
+}
+
+// NewCacheHandler creates a new cache handler
+func NewCacheHandler(service *services.CacheService) *CacheHandler {
+	return &CacheHandler{service: service}
+}
+
+// Stats handles GET /api/admin/cache
+// Figures cover the instance serving the request, since hits and misses are counted in memory.
+func (h *CacheHandler) Stats(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Stats())
+}
@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ChallengeHandler handles HTTP requests for challenge endpoints
+type ChallengeHandler struct {
+	service *services.ChallengeService
+}
+
+// NewChallengeHandler creates a new challenge handler
+func NewChallengeHandler(service *services.ChallengeService) *ChallengeHandler {
+	return &ChallengeHandler{service: service}
+}
+
+// Create handles POST /api/challenges
+// Participants check off each day, or, with metric (volume_kg, reps or sessions) and
+// goal_value, reach a total computed from their completed sessions over the challenge's
+// dates, e.g. 100000 kg of volume in March. Completing a challenge sends a
+// challenge.completed notification.
+func (h *ChallengeHandler) Create(c *gin.Context) {
+	var req models.CreateChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	challenge, err := h.service.CreateChallenge(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to create challenge")
+		return
+	}
+
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// List handles GET /api/challenges
+func (h *ChallengeHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	challenges, err := h.service.ListChallenges(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to list challenges")
+		return
+	}
+
+	c.JSON(http.StatusOK, challenges)
+}
+
+// GetByID handles GET /api/challenges/:id
+func (h *ChallengeHandler) GetByID(c *gin.Context) {
+	challenge, err := h.service.GetChallenge(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get challenge")
+		return
+	}
+
+	c.JSON(http.StatusOK, challenge)
+}
+
+// Join handles POST /api/challenges/:id/join
+func (h *ChallengeHandler) Join(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	participant, err := h.service.JoinChallenge(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to join challenge")
+		return
+	}
+
+	c.JSON(http.StatusOK, participant)
+}
+
+// Leave handles DELETE /api/challenges/:id/join
+func (h *ChallengeHandler) Leave(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.LeaveChallenge(c.Request.Context(), c.Param("id"), userID); err != nil {
+		respondError(c, err, "failed to leave challenge")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// CompleteDay handles POST /api/challenges/:id/completions
+// Conflict for challenges with a goal, whose progress comes from workout logs.
+func (h *ChallengeHandler) CompleteDay(c *gin.Context) {
+	var req models.CompleteChallengeDayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	completion, err := h.service.CompleteDay(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to record completion")
+		return
+	}
+
+	c.JSON(http.StatusOK, completion)
+}
+
+// Leaderboard handles GET /api/challenges/:id/leaderboard
+// Challenges with a goal rank participants by their progress toward it.
+func (h *ChallengeHandler) Leaderboard(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	standings, err := h.service.Leaderboard(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, standings)
+}
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ClassSessionHandler handles HTTP requests for organizations' group classes
+type ClassSessionHandler struct {
+	service *services.ClassSessionService
+}
+
+// NewClassSessionHandler creates a new class session handler
+func NewClassSessionHandler(service *services.ClassSessionService) *ClassSessionHandler {
+	return &ClassSessionHandler{service: service}
+}
+
+// Create handles POST /api/orgs/:id/classes
+// Owners and trainers schedule a class of a workout shared in the organization; the
+// creator is its trainer.
+func (h *ClassSessionHandler) Create(c *gin.Context) {
+	var req models.CreateClassSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	class, err := h.service.CreateClass(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to create class")
+		return
+	}
+
+	c.JSON(http.StatusCreated, class)
+}
+
+// List handles GET /api/orgs/:id/classes
+// Upcoming classes, soonest first, including those that started in the last day
+func (h *ClassSessionHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	classes, err := h.service.ListClasses(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list classes")
+		return
+	}
+
+	c.JSON(http.StatusOK, classes)
+}
+
+// GetByID handles GET /api/classes/:id
+func (h *ClassSessionHandler) GetByID(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	class, err := h.service.GetClass(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get class")
+		return
+	}
+
+	c.JSON(http.StatusOK, class)
+}
+
+// Join handles POST /api/classes/:id/join
+// Creates the member's own planned session of the class workout, starting with the class
+// and pre-filled with its exercises, percentage-based loads worked out from the member's
+// training maxes; log it like any other session. Joining again returns the same session.
+// Full classes answer 409.
+func (h *ClassSessionHandler) Join(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	participant, err := h.service.JoinClass(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to join class")
+		return
+	}
+
+	c.JSON(http.StatusOK, participant)
+}
+
+// Attendance handles GET /api/classes/:id/attendance
+// Who joined and how far their sessions got, for the class's trainer and the
+// organization's owners and trainers
+func (h *ClassSessionHandler) Attendance(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	attendance, err := h.service.GetAttendance(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get attendance")
+		return
+	}
+
+	c.JSON(http.StatusOK, attendance)
+}
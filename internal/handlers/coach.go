@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// CoachHandler handles HTTP requests for coach-client endpoints
+type CoachHandler struct {
+	service *services.CoachService
+}
+
+// NewCoachHandler creates a new coach handler
+func NewCoachHandler(service *services.CoachService) *CoachHandler {
+	return &CoachHandler{service: service}
+}
+
+// Invite handles POST /api/coach/invitations
+func (h *CoachHandler) Invite(c *gin.Context) {
+	var req models.CreateCoachInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	relation, err := h.service.InviteClient(c.Request.Context(), userID, c.GetString("user_email"), &req)
+	if err != nil {
+		respondError(c, err, "failed to create invitation")
+		return
+	}
+
+	c.JSON(http.StatusCreated, relation)
+}
+
+// ListInvitations handles GET /api/coach/invitations
+func (h *CoachHandler) ListInvitations(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	invitations, err := h.service.ListInvitations(c.Request.Context(), c.GetString("user_email"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitations)
+}
+
+// Accept handles POST /api/coach/invitations/:id/accept
+func (h *CoachHandler) Accept(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	relation, err := h.service.AcceptInvitation(c.Request.Context(), id, userID, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, err, "failed to accept invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, relation)
+}
+
+// Decline handles POST /api/coach/invitations/:id/decline
+func (h *CoachHandler) Decline(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	relation, err := h.service.DeclineInvitation(c.Request.Context(), id, userID, c.GetString("user_email"))
+	if err != nil {
+		respondError(c, err, "failed to decline invitation")
+		return
+	}
+
+	c.JSON(http.StatusOK, relation)
+}
+
+// ListClients handles GET /api/coach/clients
+func (h *CoachHandler) ListClients(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	clients, err := h.service.ListClients(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list clients"})
+		return
+	}
+
+	c.JSON(http.StatusOK, clients)
+}
+
+// ListCoaches handles GET /api/coach/coaches
+func (h *CoachHandler) ListCoaches(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	coaches, err := h.service.ListCoaches(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list coaches"})
+		return
+	}
+
+	c.JSON(http.StatusOK, coaches)
+}
+
+// UpdatePermissions handles PUT /api/coach/relationships/:id/permissions
+func (h *CoachHandler) UpdatePermissions(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req models.UpdateCoachPermissionsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	relation, err := h.service.UpdatePermissions(c.Request.Context(), id, userID, *req.CanWrite)
+	if err != nil {
+		respondError(c, err, "failed to update permissions")
+		return
+	}
+
+	c.JSON(http.StatusOK, relation)
+}
+
+// Revoke handles DELETE /api/coach/relationships/:id
+func (h *CoachHandler) Revoke(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	err := h.service.RevokeRelationship(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err, "failed to revoke relationship")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
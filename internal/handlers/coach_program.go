@@ -0,0 +1,137 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// CoachProgramHandler handles HTTP requests for programs coaches assign to clients
+type CoachProgramHandler struct {
+	service *services.CoachProgramService
+}
+
+// NewCoachProgramHandler creates a new coach program handler
+func NewCoachProgramHandler(service *services.CoachProgramService) *CoachProgramHandler {
+	return &CoachProgramHandler{service: service}
+}
+
+// Assign handles POST /api/coach/clients/:user_id/programs
+// Schedules workouts for an active client on given days (in the client's timezone). Each
+// workout must be the coach's or the client's own template. The client's completed
+// sessions of a scheduled workout are matched to it within the same week.
+func (h *CoachProgramHandler) Assign(c *gin.Context) {
+	var req models.CreateCoachProgramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	program, err := h.service.AssignProgram(c.Request.Context(), userID, c.Param("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to assign program")
+		return
+	}
+
+	c.JSON(http.StatusCreated, program)
+}
+
+// ListForClient handles GET /api/coach/clients/:user_id/programs
+// Coaches see the programs they assigned to the client; clients asking about themselves
+// see every coach's
+func (h *CoachProgramHandler) ListForClient(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	programs, err := h.service.ListPrograms(c.Request.Context(), userID, c.Param("user_id"))
+	if err != nil {
+		respondError(c, err, "failed to list programs")
+		return
+	}
+
+	c.JSON(http.StatusOK, programs)
+}
+
+// ListMine handles GET /api/coach/programs
+// The programs the caller's coaches assigned them, newest first
+func (h *CoachProgramHandler) ListMine(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	programs, err := h.service.ListPrograms(c.Request.Context(), userID, userID)
+	if err != nil {
+		respondError(c, err, "failed to list programs")
+		return
+	}
+
+	c.JSON(http.StatusOK, programs)
+}
+
+// Skip handles POST /api/coach/program-workouts/:id/skip
+// Clients skip a scheduled workout they haven't done, optionally saying why
+func (h *CoachProgramHandler) Skip(c *gin.Context) {
+	var req models.SkipProgramWorkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	workout, err := h.service.SkipWorkout(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to skip workout")
+		return
+	}
+
+	c.JSON(http.StatusOK, workout)
+}
+
+// Compliance handles GET /api/coach/clients/:user_id/compliance?week=2026-03-02
+// How many of the client's scheduled workouts in the week (any day of it; this week by
+// default) were completed as prescribed, completed with changes, skipped or missed, for
+// their coach or the client themself
+func (h *CoachProgramHandler) Compliance(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var day time.Time
+	if raw := c.Query("week"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "week must be a date (YYYY-MM-DD)"})
+			return
+		}
+		day = parsed
+	}
+
+	report, err := h.service.GetCompliance(c.Request.Context(), userID, c.Param("user_id"), day)
+	if err != nil {
+		respondError(c, err, "failed to get compliance report")
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
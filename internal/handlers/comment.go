@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// CommentHandler handles HTTP requests for comments on workouts and sessions
+type CommentHandler struct {
+	service *services.CommentService
+}
+
+// NewCommentHandler creates a new comment handler
+func NewCommentHandler(service *services.CommentService) *CommentHandler {
+	return &CommentHandler{service: service}
+}
+
+// ListWorkoutComments handles GET /api/workouts/:id/comments
+// Anyone who can view the workout can read and leave comments.
+func (h *CommentHandler) ListWorkoutComments(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	comments, err := h.service.ListComments(c.Request.Context(), userID, models.CommentResourceWorkout, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// CreateWorkoutComment handles POST /api/workouts/:id/comments
+// The workout's owner gets a comment.created notification.
+func (h *CommentHandler) CreateWorkoutComment(c *gin.Context) {
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	comment, err := h.service.AddComment(c.Request.Context(), userID, models.CommentResourceWorkout, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// DeleteWorkoutComment handles DELETE /api/workouts/:id/comments/:comment_id
+// Authors can delete their comments; the workout's owner, and owners and trainers of its
+// organization, can delete any.
+func (h *CommentHandler) DeleteWorkoutComment(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteComment(c.Request.Context(), userID, models.CommentResourceWorkout, c.Param("id"), c.Param("comment_id")); err != nil {
+		respondError(c, err, "failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ListSessionComments handles GET /api/sessions/:id/comments
+// Besides the owner and their coaches, users the owner's profile privacy lets see their
+// activity can read and leave comments.
+func (h *CommentHandler) ListSessionComments(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	comments, err := h.service.ListComments(c.Request.Context(), userID, models.CommentResourceSession, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list comments")
+		return
+	}
+
+	c.JSON(http.StatusOK, comments)
+}
+
+// CreateSessionComment handles POST /api/sessions/:id/comments
+// The session's owner gets a comment.created notification.
+func (h *CommentHandler) CreateSessionComment(c *gin.Context) {
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	comment, err := h.service.AddComment(c.Request.Context(), userID, models.CommentResourceSession, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, comment)
+}
+
+// DeleteSessionComment handles DELETE /api/sessions/:id/comments/:comment_id
+// Authors can delete their comments, and the session's owner any.
+func (h *CommentHandler) DeleteSessionComment(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteComment(c.Request.Context(), userID, models.CommentResourceSession, c.Param("id"), c.Param("comment_id")); err != nil {
+		respondError(c, err, "failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,60 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// EmailHandler handles HTTP requests for users' email preferences
+type EmailHandler struct {
+	service *services.EmailService
+}
+
+// NewEmailHandler creates a new email handler
+func NewEmailHandler(service *services.EmailService) *EmailHandler {
+	return &EmailHandler{service: service}
+}
+
+// GetPreferences handles GET /api/email-preferences
+func (h *EmailHandler) GetPreferences(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	prefs, err := h.service.GetPreferences(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get email preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
+
+// UpdatePreferences handles PUT /api/email-preferences with the emails to turn on or off:
+// workout_reminders (an hour before planned sessions) and weekly_summary (on Mondays)
+func (h *EmailHandler) UpdatePreferences(c *gin.Context) {
+	var req models.UpdateEmailPreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	prefs, err := h.service.UpdatePreferences(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update email preferences"})
+		return
+	}
+
+	c.JSON(http.StatusOK, prefs)
+}
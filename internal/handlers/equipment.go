@@ -1,7 +1,6 @@
 package handlers
 
 import (
-	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +22,7 @@ func NewEquipmentHandler(service *services.EquipmentService) *EquipmentHandler {
 func (h *EquipmentHandler) Create(c *gin.Context) {
 	var req models.CreateEquipmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err, &req)
 		return
 	}
 
@@ -33,13 +32,16 @@ func (h *EquipmentHandler) Create(c *gin.Context) {
 		return
 	}
 
-	equipment, err := h.service.CreateEquipment(c.Request.Context(), userID, &req)
+	// Requests scoped with X-Org-Id create equipment shared with the organization
+	var equipment *models.Equipment
+	var err error
+	if orgID := c.GetString("org_id"); orgID != "" {
+		equipment, err = h.service.CreateOrgEquipment(c.Request.Context(), userID, orgID, &req)
+	} else {
+		equipment, err = h.service.CreateEquipment(c.Request.Context(), userID, &req)
+	}
 	if err != nil {
-		// Log the actual error for debugging
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to create equipment",
-			"detail": err.Error(), // Add this temporarily for debugging
-		})
+		respondError(c, err, "failed to create equipment")
 		return
 	}
 
@@ -58,15 +60,7 @@ func (h *EquipmentHandler) GetByID(c *gin.Context) {
 
 	equipment, err := h.service.GetEquipment(c.Request.Context(), id, userID)
 	if err != nil {
-		if errors.Is(err, services.ErrEquipmentNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "equipment not found"})
-			return
-		}
-		if errors.Is(err, services.ErrUnauthorized) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to access this equipment"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get equipment"})
+		respondError(c, err, "failed to get equipment")
 		return
 	}
 
@@ -81,9 +75,19 @@ func (h *EquipmentHandler) List(c *gin.Context) {
 		return
 	}
 
-	equipment, err := h.service.ListEquipment(c.Request.Context(), userID)
+	// Organization members list shared equipment via X-Org-Id;
+	// coaches can list a client's equipment with ?user_id=<client>
+	var equipment []*models.Equipment
+	var err error
+	if orgID := c.GetString("org_id"); orgID != "" {
+		equipment, err = h.service.ListOrgEquipment(c.Request.Context(), userID, orgID)
+	} else if ownerID := c.Query("user_id"); ownerID != "" && ownerID != userID {
+		equipment, err = h.service.ListEquipmentForOwner(c.Request.Context(), userID, ownerID)
+	} else {
+		equipment, err = h.service.ListEquipment(c.Request.Context(), userID)
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list equipment"})
+		respondError(c, err, "failed to list equipment")
 		return
 	}
 
@@ -91,6 +95,7 @@ func (h *EquipmentHandler) List(c *gin.Context) {
 }
 
 // Update handles PUT /api/equipment/:id
+// The body's version must be the one the client read; 409 means someone else updated it since.
 func (h *EquipmentHandler) Update(c *gin.Context) {
 	id := c.Param("id")
 	userID := c.GetString("user_id")
@@ -102,21 +107,13 @@ func (h *EquipmentHandler) Update(c *gin.Context) {
 
 	var req models.UpdateEquipmentRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		respondBindError(c, err, &req)
 		return
 	}
 
 	equipment, err := h.service.UpdateEquipment(c.Request.Context(), id, userID, &req)
 	if err != nil {
-		if errors.Is(err, services.ErrEquipmentNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "equipment not found"})
-			return
-		}
-		if errors.Is(err, services.ErrUnauthorized) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to update this equipment"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update equipment"})
+		respondError(c, err, "failed to update equipment")
 		return
 	}
 
@@ -135,15 +132,7 @@ func (h *EquipmentHandler) Delete(c *gin.Context) {
 
 	err := h.service.DeleteEquipment(c.Request.Context(), id, userID)
 	if err != nil {
-		if errors.Is(err, services.ErrEquipmentNotFound) {
-			c.JSON(http.StatusNotFound, gin.H{"error": "equipment not found"})
-			return
-		}
-		if errors.Is(err, services.ErrUnauthorized) {
-			c.JSON(http.StatusForbidden, gin.H{"error": "you don't have permission to delete this equipment"})
-			return
-		}
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to delete equipment"})
+		respondError(c, err, "failed to delete equipment")
 		return
 	}
 
@@ -0,0 +1,34 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+)
+
+// respondError answers an error from a service with the status of its domainerr category
+// and the error's own text. Errors without a category are unexpected, so clients get
+// fallback instead of driver or network details; timeouts get a fixed text for the same reason.
+func respondError(c *gin.Context, err error, fallback string) {
+	switch domainerr.CategoryOf(err) {
+	case domainerr.Validation:
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case domainerr.Forbidden:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case domainerr.NotFound:
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case domainerr.Conflict:
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case domainerr.Gone:
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+	case domainerr.Unprocessable:
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case domainerr.Transient:
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case domainerr.Timeout:
+		c.JSON(http.StatusGatewayTimeout, gin.H{"error": "request timed out"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fallback})
+	}
+}
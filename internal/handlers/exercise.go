@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ExerciseHandler handles HTTP requests for exercise endpoints
+type ExerciseHandler struct {
+	service *services.ExerciseService
+}
+
+// NewExerciseHandler creates a new exercise handler
+func NewExerciseHandler(service *services.ExerciseService) *ExerciseHandler {
+	return &ExerciseHandler{service: service}
+}
+
+// List handles GET /api/exercises
+// Anonymous callers may browse the public library with ?public=true. For signed-in users,
+// exercises loading a body part with an active injury list it under contraindications;
+// ?exclude_contraindicated=true leaves them out instead.
+func (h *ExerciseHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	publicOnly := c.Query("public") == "true"
+
+	if userID == "" && !publicOnly {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "sign in or use ?public=true to browse the public library"})
+		return
+	}
+
+	excludeContraindicated := c.Query("exclude_contraindicated") == "true"
+	exercises, err := h.service.ListExercises(c.Request.Context(), userID, publicOnly, excludeContraindicated)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list exercises"})
+		return
+	}
+
+	c.JSON(http.StatusOK, exercises)
+}
+
+// GetByID handles GET /api/exercises/:id
+func (h *ExerciseHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	exercise, err := h.service.GetExercise(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err, "failed to get exercise")
+		return
+	}
+
+	c.JSON(http.StatusOK, exercise)
+}
+
+// Search handles GET /api/exercises/search?q=...&limit=N
+// Anonymous callers search the public library only
+func (h *ExerciseHandler) Search(c *gin.Context) {
+	userID := c.GetString("user_id")
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	result, err := h.service.SearchExercises(c.Request.Context(), userID, c.Query("q"), limit)
+	if err != nil {
+		respondError(c, err, "failed to search exercises")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// Duplicates handles GET /api/exercises/duplicates
+func (h *ExerciseHandler) Duplicates(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	duplicates, err := h.service.FindDuplicateExercises(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to find duplicate exercises"})
+		return
+	}
+
+	c.JSON(http.StatusOK, duplicates)
+}
+
+// Merge handles POST /api/exercises/merge
+func (h *ExerciseHandler) Merge(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req models.MergeExercisesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	result, err := h.service.MergeExercises(c.Request.Context(), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to merge exercises")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ExerciseLeaderboardHandler handles HTTP requests for exercise leaderboards
+type ExerciseLeaderboardHandler struct {
+	service *services.ExerciseLeaderboardService
+}
+
+// NewExerciseLeaderboardHandler creates a new exercise leaderboard handler
+func NewExerciseLeaderboardHandler(service *services.ExerciseLeaderboardService) *ExerciseLeaderboardHandler {
+	return &ExerciseLeaderboardHandler{service: service}
+}
+
+// Get handles GET /api/exercises/:id/leaderboard?metric=e1rm|top_set&relative=true
+// Ranks the caller and the people they follow by their best set of the exercise: its
+// Epley estimated 1RM (the default) or its weight. With relative=true the ranking is per kg
+// of each person's latest body weight, leaving out people who never weighed in. People
+// whose profile the caller can't see, or who hide their records, don't appear.
+func (h *ExerciseLeaderboardHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	relative := false
+	if raw := c.Query("relative"); raw != "" {
+		parsed, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "relative must be true or false"})
+			return
+		}
+		relative = parsed
+	}
+
+	leaderboard, err := h.service.GetLeaderboard(c.Request.Context(), userID, c.Param("id"), c.Query("metric"), relative)
+	if err != nil {
+		respondError(c, err, "failed to get leaderboard")
+		return
+	}
+
+	c.JSON(http.StatusOK, leaderboard)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ExerciseSwapHandler handles HTTP requests for in-session exercise swaps and skips
+type ExerciseSwapHandler struct {
+	service *services.ExerciseSwapService
+}
+
+// NewExerciseSwapHandler creates a new exercise swap handler
+func NewExerciseSwapHandler(service *services.ExerciseSwapService) *ExerciseSwapHandler {
+	return &ExerciseSwapHandler{service: service}
+}
+
+// Swap handles POST /api/sessions/:id/swap
+func (h *ExerciseSwapHandler) Swap(c *gin.Context) {
+	var req models.SwapExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	result, err := h.service.SwapExercise(c.Request.Context(), c.Param("id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to swap exercise")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// List handles GET /api/sessions/:id/swaps
+func (h *ExerciseSwapHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	swaps, err := h.service.ListSwaps(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to list swaps")
+		return
+	}
+
+	c.JSON(http.StatusOK, swaps)
+}
+
+// Skip handles POST /api/sessions/:id/skip
+func (h *ExerciseSwapHandler) Skip(c *gin.Context) {
+	var req models.SkipExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	skipped, err := h.service.SkipExercise(c.Request.Context(), c.Param("id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to skip exercise")
+		return
+	}
+
+	c.JSON(http.StatusOK, skipped)
+}
+
+// SkippedVolume handles GET /api/analytics/skipped-volume?weeks=4
+func (h *ExerciseSwapHandler) SkippedVolume(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	weeks := 4
+	if raw := c.Query("weeks"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": services.ErrInvalidWeeks.Error()})
+			return
+		}
+		weeks = n
+	}
+
+	volumes, err := h.service.WeeklySkippedVolume(c.Request.Context(), userID, weeks)
+	if err != nil {
+		respondError(c, err, "failed to get skipped volume")
+		return
+	}
+
+	c.JSON(http.StatusOK, volumes)
+}
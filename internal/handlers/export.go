@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ExportHandler handles HTTP requests for data export endpoints
+type ExportHandler struct {
+	service *services.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(service *services.ExportService) *ExportHandler {
+	return &ExportHandler{service: service}
+}
+
+// ExerciseLogsCSV handles GET /api/export/logs.csv
+// The body is streamed, so a failure midway can only truncate the download and be logged
+func (h *ExportHandler) ExerciseLogsCSV(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	filename := fmt.Sprintf("fitapi-logs-%s.csv", time.Now().UTC().Format("2006-01-02"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(http.StatusOK)
+
+	if err := h.service.WriteExerciseLogsCSV(c.Request.Context(), userID, c.Writer); err != nil {
+		log.Printf("CSV export failed: user=%s err=%v", userID, err)
+	}
+}
+
+// RequestAccountExport handles POST /api/export
+// Returns 202 with the pending export; poll GET /api/export/:id until it is ready
+func (h *ExportHandler) RequestAccountExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	export, err := h.service.RequestAccountExport(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to start export"})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, export)
+}
+
+// GetAccountExport handles GET /api/export/:id
+func (h *ExportHandler) GetAccountExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	export, err := h.service.GetAccountExport(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get export")
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// DownloadAccountExport handles GET /api/export/:id/download
+// Supports Range requests so interrupted downloads can resume; clients should send the
+// ETag back in If-Range so a regenerated archive is never spliced onto a partial one.
+func (h *ExportHandler) DownloadAccountExport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	export, archive, err := h.service.DownloadAccountExport(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to download export")
+		return
+	}
+
+	// The archive of an export never changes once ready, so its completion time
+	// identifies this exact content
+	modified := export.CreatedAt
+	if export.CompletedAt != nil {
+		modified = *export.CompletedAt
+	}
+
+	filename := fmt.Sprintf("fitapi-export-%s.json", modified.UTC().Format("2006-01-02"))
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Header("ETag", fmt.Sprintf(`"%s-%d"`, export.ID, modified.Unix()))
+	c.Header("Cache-Control", "private, no-cache")
+	http.ServeContent(c.Writer, c.Request, "", modified, bytes.NewReader(archive))
+}
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// FeatureFlagHandler handles HTTP requests for feature flags: the caller's flags, and the
+// admin endpoints that roll features out
+type FeatureFlagHandler struct {
+	service *services.FeatureFlagService
+}
+
+// NewFeatureFlagHandler creates a new feature flag handler
+func NewFeatureFlagHandler(service *services.FeatureFlagService) *FeatureFlagHandler {
+	return &FeatureFlagHandler{service: service}
+}
+
+// Mine handles GET /api/feature-flags
+// Whether each flag is on for the caller, so clients can hide features they don't get.
+func (h *FeatureFlagHandler) Mine(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.service.UserFlags(c.Request.Context(), userID))
+}
+
+// List handles GET /api/admin/feature-flags
+// Every flag with its rollout and overrides; stored is false for flags still on their
+// configured default.
+func (h *FeatureFlagHandler) List(c *gin.Context) {
+	flags, err := h.service.ListFlags(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "failed to list feature flags")
+		return
+	}
+
+	c.JSON(http.StatusOK, flags)
+}
+
+// Update handles PUT /api/admin/feature-flags/:name
+// Turns a flag on for rollout_percent of users (default 100) or off, creating it if needed.
+// Applies on every instance within 30 seconds.
+func (h *FeatureFlagHandler) Update(c *gin.Context) {
+	var req models.UpdateFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	flag, err := h.service.UpdateFlag(c.Request.Context(), c.GetString("user_id"), c.Param("name"), &req)
+	if err != nil {
+		respondError(c, err, "failed to update feature flag")
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// SetOverride handles PUT /api/admin/feature-flags/:name/users/:user_id
+// Turns a flag on or off for one user whatever its rollout, e.g. for staff to try it first.
+func (h *FeatureFlagHandler) SetOverride(c *gin.Context) {
+	var req models.SetFeatureFlagOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	flag, err := h.service.SetOverride(c.Request.Context(), c.GetString("user_id"), c.Param("name"), c.Param("user_id"), *req.Enabled)
+	if err != nil {
+		respondError(c, err, "failed to set feature flag override")
+		return
+	}
+
+	c.JSON(http.StatusOK, flag)
+}
+
+// DeleteOverride handles DELETE /api/admin/feature-flags/:name/users/:user_id
+// Returns the user to the flag's rollout.
+func (h *FeatureFlagHandler) DeleteOverride(c *gin.Context) {
+	if err := h.service.DeleteOverride(c.Request.Context(), c.GetString("user_id"), c.Param("name"), c.Param("user_id")); err != nil {
+		respondError(c, err, "failed to delete feature flag override")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// FeedHandler handles HTTP requests for activity feeds
+type FeedHandler struct {
+	service *services.FeedService
+}
+
+// NewFeedHandler creates a new feed handler
+func NewFeedHandler(service *services.FeedService) *FeedHandler {
+	return &FeedHandler{service: service}
+}
+
+// Get handles GET /api/feed?limit=20&cursor=<next_cursor>
+// Completed sessions and personal records of the users the caller follows, newest first;
+// pass the previous page's next_cursor to continue. There are no more pages once
+// next_cursor is absent. Users with a private profile don't appear.
+func (h *FeedHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.service.GetFeed(c.Request.Context(), userID, limit, c.Query("cursor"))
+	if err != nil {
+		respondError(c, err, "failed to get feed")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// React handles POST /api/feed/:id/reaction
+// Likes a feed item. Each user likes an item at most once, so liking it again changes
+// nothing; the response has the item's like count either way.
+func (h *FeedHandler) React(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	summary, err := h.service.React(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to react")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// Unreact handles DELETE /api/feed/:id/reaction
+// Takes back a like, if the caller had liked the item.
+func (h *FeedHandler) Unreact(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	summary, err := h.service.Unreact(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to remove reaction")
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// FollowHandler handles HTTP requests for following users
+type FollowHandler struct {
+	service *services.FollowService
+}
+
+// NewFollowHandler creates a new follow handler
+func NewFollowHandler(service *services.FollowService) *FollowHandler {
+	return &FollowHandler{service: service}
+}
+
+// Follow handles POST /api/users/:id/follow
+// Public profiles are followed right away; followers-only profiles get a pending request
+// they accept through /api/follow-requests. Private profiles can't be followed.
+func (h *FollowHandler) Follow(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	follow, err := h.service.Follow(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to follow user")
+		return
+	}
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// Unfollow handles DELETE /api/users/:id/follow
+// Also withdraws a pending follow request.
+func (h *FollowHandler) Unfollow(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.Unfollow(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err, "failed to unfollow user")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// ListFollowers handles GET /api/users/:id/followers
+// Forbidden unless the profile is public, the caller follows it, or it is their own.
+func (h *FollowHandler) ListFollowers(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	follows, err := h.service.ListFollowers(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list followers")
+		return
+	}
+
+	c.JSON(http.StatusOK, follows)
+}
+
+// ListFollowing handles GET /api/users/:id/following
+// Visible like the user's followers; on their own list users also see their pending requests.
+func (h *FollowHandler) ListFollowing(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	follows, err := h.service.ListFollowing(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list followed users")
+		return
+	}
+
+	c.JSON(http.StatusOK, follows)
+}
+
+// ListRequests handles GET /api/follow-requests
+func (h *FollowHandler) ListRequests(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	follows, err := h.service.ListFollowRequests(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list follow requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, follows)
+}
+
+// AcceptRequest handles POST /api/follow-requests/:id/accept
+// id is the requesting user's ID.
+func (h *FollowHandler) AcceptRequest(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	follow, err := h.service.AcceptFollowRequest(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to accept follow request")
+		return
+	}
+
+	c.JSON(http.StatusOK, follow)
+}
+
+// RemoveFollower handles DELETE /api/followers/:id
+// Removes a follower, or declines their pending request.
+func (h *FollowHandler) RemoveFollower(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.RemoveFollower(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err, "failed to remove follower")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// GoalHandler handles HTTP requests for goals
+type GoalHandler struct {
+	service *services.GoalService
+}
+
+// NewGoalHandler creates a new goal handler
+func NewGoalHandler(service *services.GoalService) *GoalHandler {
+	return &GoalHandler{service: service}
+}
+
+// List handles GET /api/goals
+// Each goal comes with its current value (latest weigh-in, best estimated 1RM or this
+// week's completed sessions) and percentage from start to target.
+func (h *GoalHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	goals, err := h.service.ListGoals(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list goals"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goals)
+}
+
+// Get handles GET /api/goals/:id
+func (h *GoalHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	goal, err := h.service.GetGoal(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// Create handles POST /api/goals
+// kind is body_weight (kg; needs a weigh-in first), e1rm (kg, with exercise_id) or
+// weekly_sessions. A goal that is already met is achieved right away, and reaching one
+// sends a goal.achieved notification.
+func (h *GoalHandler) Create(c *gin.Context) {
+	var req models.CreateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	goal, err := h.service.CreateGoal(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to create goal")
+		return
+	}
+
+	c.JSON(http.StatusCreated, goal)
+}
+
+// Update handles PUT /api/goals/:id
+// Changing the target clears the goal's achievement until the new target is met.
+func (h *GoalHandler) Update(c *gin.Context) {
+	var req models.UpdateGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	goal, err := h.service.UpdateGoal(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to update goal")
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// Delete handles DELETE /api/goals/:id
+func (h *GoalHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteGoal(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete goal")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// GoogleFitHandler handles HTTP requests for the Google Fit integration
+type GoogleFitHandler struct {
+	service *services.GoogleFitService
+}
+
+// NewGoogleFitHandler creates a new Google Fit handler
+func NewGoogleFitHandler(service *services.GoogleFitService) *GoogleFitHandler {
+	return &GoogleFitHandler{service: service}
+}
+
+// Authorize handles GET /api/integrations/googlefit/connect
+// The client opens auth_url; Google redirects back with code and state for Connect.
+func (h *GoogleFitHandler) Authorize(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	authorization, err := h.service.Authorize(userID)
+	if err != nil {
+		respondError(c, err, "failed to start google fit connection")
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+// Connect handles POST /api/integrations/googlefit/connect
+func (h *GoogleFitHandler) Connect(c *gin.Context) {
+	var req models.ConnectIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conn, err := h.service.Connect(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to connect google fit")
+		return
+	}
+
+	c.JSON(http.StatusCreated, conn)
+}
+
+// Get handles GET /api/integrations/googlefit
+func (h *GoogleFitHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conn, err := h.service.GetConnection(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to get google fit connection")
+		return
+	}
+
+	c.JSON(http.StatusOK, conn)
+}
+
+// Disconnect handles DELETE /api/integrations/googlefit
+func (h *GoogleFitHandler) Disconnect(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.Disconnect(c.Request.Context(), userID); err != nil {
+		respondError(c, err, "failed to disconnect google fit")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Sync handles POST /api/integrations/googlefit/sync
+func (h *GoogleFitHandler) Sync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	result, err := h.service.Sync(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to sync google fit")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
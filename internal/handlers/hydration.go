@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// HydrationHandler handles HTTP requests for water logs and the daily hydration goal
+type HydrationHandler struct {
+	service *services.HydrationService
+}
+
+// NewHydrationHandler creates a new hydration handler
+func NewHydrationHandler(service *services.HydrationService) *HydrationHandler {
+	return &HydrationHandler{service: service}
+}
+
+// LogWater handles POST /api/hydration/logs
+func (h *HydrationHandler) LogWater(c *gin.Context) {
+	var req models.CreateWaterLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	log, err := h.service.LogWater(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to log water")
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+// ListLogs handles GET /api/hydration/logs?date=2026-04-06
+// date defaults to today in the user's timezone.
+func (h *HydrationHandler) ListLogs(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	day, ok := hydrationDay(c)
+	if !ok {
+		return
+	}
+
+	logs, err := h.service.ListWaterLogs(c.Request.Context(), userID, day)
+	if err != nil {
+		respondError(c, err, "failed to list water logs")
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// DeleteLog handles DELETE /api/hydration/logs/:id
+func (h *HydrationHandler) DeleteLog(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteWaterLog(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete water log")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// GetGoal handles GET /api/hydration/goal
+func (h *HydrationHandler) GetGoal(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	goal, err := h.service.GetGoal(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get hydration goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// UpdateGoal handles PUT /api/hydration/goal
+func (h *HydrationHandler) UpdateGoal(c *gin.Context) {
+	var req models.UpdateHydrationGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	goal, err := h.service.UpdateGoal(c.Request.Context(), userID, &req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save hydration goal"})
+		return
+	}
+
+	c.JSON(http.StatusOK, goal)
+}
+
+// Progress handles GET /api/hydration/progress?date=2026-04-06
+// date defaults to today in the user's timezone.
+func (h *HydrationHandler) Progress(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	day, ok := hydrationDay(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.service.GetProgress(c.Request.Context(), userID, day)
+	if err != nil {
+		respondError(c, err, "failed to get hydration progress")
+		return
+	}
+
+	c.JSON(http.StatusOK, progress)
+}
+
+// hydrationDay parses the optional date query parameter, answering 400 when it isn't a
+// date; a missing date is zero
+func hydrationDay(c *gin.Context) (time.Time, bool) {
+	raw := c.Query("date")
+	if raw == "" {
+		return time.Time{}, true
+	}
+
+	day, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "date must be a date (YYYY-MM-DD)"})
+		return time.Time{}, false
+	}
+	return day, true
+}
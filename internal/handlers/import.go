@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/importer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// maxAppleHealthFileSize allows for Apple Health's export.xml, which holds every
+// health sample and not just workouts
+const maxAppleHealthFileSize = 512 << 20 // 512 MB
+
+// ImportHandler handles HTTP requests for workout history imports
+type ImportHandler struct {
+	service *services.ImportService
+}
+
+// NewImportHandler creates a new import handler
+func NewImportHandler(service *services.ImportService) *ImportHandler {
+	return &ImportHandler{service: service}
+}
+
+// Import handles POST /api/import/:source?dry_run=true&timezone=Europe/Madrid&weight_unit=kg
+// source is the app or format the export comes from ("strong", "hevy", "apple-health",
+// "fit" or "tcx"); the export is uploaded as the multipart form field "file". Returns 202
+// with the pending import; poll GET /api/import/:id for its result. With dry_run=true
+// nothing is stored and the response previews what would be imported.
+func (h *ImportHandler) Import(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	loc, err := time.LoadLocation(c.DefaultQuery("timezone", "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone"})
+		return
+	}
+
+	weightUnit := c.DefaultQuery("weight_unit", "kg")
+	if weightUnit != "kg" && weightUnit != "lbs" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weight_unit must be kg or lbs"})
+		return
+	}
+
+	maxSize := int64(maxImportFileSize)
+	if c.Param("source") == models.ImportSourceAppleHealth {
+		maxSize = maxAppleHealthFileSize
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("upload the export as multipart field 'file' (max %dMB)", maxSize>>20)})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	opts := importer.Options{Location: loc, WeightUnit: weightUnit}
+	h.start(c, userID, c.Param("source"), file, opts)
+}
+
+// ImportActivityFile handles POST /api/sessions/import-fit?dry_run=true
+// A Garmin .FIT or .TCX activity file is uploaded as the multipart form field "file"; the
+// format is detected from its content. Each activity becomes a cardio session with its laps.
+// Returns 202 with the pending import, or a preview with dry_run=true.
+func (h *ImportHandler) ImportActivityFile(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "upload the activity as multipart field 'file' (max 10MB)"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	// TCX files may start with a long XML prolog before the root element
+	br := bufio.NewReaderSize(file, 4096)
+	head, _ := br.Peek(1024)
+	source, err := importer.DetectActivityFormat(head)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	opts := importer.Options{Location: time.UTC, WeightUnit: "kg"}
+	h.start(c, userID, source, br, opts)
+}
+
+// GetImport handles GET /api/import/:id
+func (h *ImportHandler) GetImport(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	imp, err := h.service.GetImport(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get import")
+		return
+	}
+
+	c.JSON(http.StatusOK, imp)
+}
+
+// start previews the upload with dry_run=true, and otherwise queues importing it
+func (h *ImportHandler) start(c *gin.Context, userID string, source string, r io.Reader, opts importer.Options) {
+	if c.Query("dry_run") == "true" {
+		result, err := h.service.Preview(c.Request.Context(), userID, source, r, opts)
+		if err != nil {
+			respondImportError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+
+	imp, err := h.service.Import(c.Request.Context(), userID, source, r, opts)
+	if err != nil {
+		respondImportError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, imp)
+}
+
+// respondImportError answers a failed preview or import
+func respondImportError(c *gin.Context, err error) {
+	if errors.Is(err, importer.ErrUnsupportedSource) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "unsupported import source, expected strong, hevy, apple-health, fit or tcx"})
+		return
+	}
+	if errors.Is(err, importer.ErrInvalidFile) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to import workouts"})
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// InjuryHandler handles HTTP requests for injuries
+type InjuryHandler struct {
+	service *services.InjuryService
+}
+
+// NewInjuryHandler creates a new injury handler
+func NewInjuryHandler(service *services.InjuryService) *InjuryHandler {
+	return &InjuryHandler{service: service}
+}
+
+// List handles GET /api/injuries?active=true
+// active=true lists only the injuries active today in the user's timezone.
+func (h *InjuryHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	injuries, err := h.service.ListInjuries(c.Request.Context(), userID, c.Query("active") == "true")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list injuries"})
+		return
+	}
+
+	c.JSON(http.StatusOK, injuries)
+}
+
+// Create handles POST /api/injuries
+// While the injury is active, exercises whose muscle group loads one of its body parts are
+// flagged in exercise and workout listings, and starting a session from a workout with
+// such exercises sends an injury.warning notification.
+func (h *InjuryHandler) Create(c *gin.Context) {
+	var req models.InjuryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	injury, err := h.service.RecordInjury(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to record injury")
+		return
+	}
+
+	c.JSON(http.StatusCreated, injury)
+}
+
+// Update handles PUT /api/injuries/:id
+// Set ended_on to the last day of the injury once it has healed.
+func (h *InjuryHandler) Update(c *gin.Context) {
+	var req models.InjuryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	injury, err := h.service.UpdateInjury(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to update injury")
+		return
+	}
+
+	c.JSON(http.StatusOK, injury)
+}
+
+// Delete handles DELETE /api/injuries/:id
+func (h *InjuryHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteInjury(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete injury")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// JobHandler handles HTTP requests for the status of background jobs
+type JobHandler struct {
+	service *services.JobService
+}
+
+// NewJobHandler creates a new job handler
+func NewJobHandler(service *services.JobService) *JobHandler {
+	return &JobHandler{service: service}
+}
+
+// List handles GET /api/jobs?kind=account_export&status=dead&limit=50
+func (h *JobHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	filter, ok := parseJobFilter(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.service.ListJobs(c.Request.Context(), userID, filter)
+	if err != nil {
+		respondError(c, err, "failed to list jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// Get handles GET /api/jobs/:id
+func (h *JobHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	job, err := h.service.GetJob(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// AdminList handles GET /api/admin/jobs?kind=email&status=dead&limit=50
+// Lists every user's jobs; status=dead is the dead letter queue.
+func (h *JobHandler) AdminList(c *gin.Context) {
+	filter, ok := parseJobFilter(c)
+	if !ok {
+		return
+	}
+
+	jobs, err := h.service.ListAllJobs(c.Request.Context(), filter)
+	if err != nil {
+		respondError(c, err, "failed to list jobs")
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// AdminCounts handles GET /api/admin/jobs/counts
+func (h *JobHandler) AdminCounts(c *gin.Context) {
+	counts, err := h.service.Counts(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to count jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// AdminRetry handles POST /api/admin/jobs/:id/retry
+func (h *JobHandler) AdminRetry(c *gin.Context) {
+	job, err := h.service.RetryJob(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to retry job")
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+func parseJobFilter(c *gin.Context) (models.JobFilter, bool) {
+	filter := models.JobFilter{Kind: c.Query("kind"), Status: c.Query("status")}
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return filter, false
+		}
+		filter.Limit = n
+	}
+	return filter, true
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// maxImportFileSize caps uploaded import files
+const maxImportFileSize = 10 << 20 // 10 MB
+
+// MeasurementHandler handles HTTP requests for body measurement endpoints
+type MeasurementHandler struct {
+	service *services.MeasurementService
+}
+
+// NewMeasurementHandler creates a new measurement handler
+func NewMeasurementHandler(service *services.MeasurementService) *MeasurementHandler {
+	return &MeasurementHandler{service: service}
+}
+
+// List handles GET /api/measurements
+func (h *MeasurementHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	measurements, err := h.service.ListMeasurements(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list measurements"})
+		return
+	}
+
+	c.JSON(http.StatusOK, measurements)
+}
+
+// Import handles POST /api/measurements/import?source=withings|renpho&timezone=Europe/Madrid
+// The CSV export is uploaded as the multipart form field "file". Timestamps without an
+// offset are read in timezone (default UTC).
+func (h *MeasurementHandler) Import(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	loc, err := time.LoadLocation(c.DefaultQuery("timezone", "UTC"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone"})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "upload the export as multipart field 'file' (max 10MB)"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportScaleCSV(c.Request.Context(), userID, c.Query("source"), loc, file)
+	if err != nil {
+		respondError(c, err, "failed to import measurements")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
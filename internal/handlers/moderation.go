@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ModerationHandler handles HTTP requests for reporting public library content and the
+// admin endpoints that moderate it
+type ModerationHandler struct {
+	service *services.ModerationService
+}
+
+// NewModerationHandler creates a new moderation handler
+func NewModerationHandler(service *services.ModerationService) *ModerationHandler {
+	return &ModerationHandler{service: service}
+}
+
+// ReportExercise handles POST /api/exercises/:id/report
+// Flags a public exercise for admins to review. Each user can have one open report per
+// exercise.
+func (h *ModerationHandler) ReportExercise(c *gin.Context) {
+	h.report(c, models.ReportResourceExercise)
+}
+
+// ReportWorkout handles POST /api/workouts/:id/report
+// Flags a workout shared in one of the caller's organizations for admins to review. Each
+// user can have one open report per workout.
+func (h *ModerationHandler) ReportWorkout(c *gin.Context) {
+	h.report(c, models.ReportResourceWorkout)
+}
+
+func (h *ModerationHandler) report(c *gin.Context, resourceType string) {
+	var req models.ReportContentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	report, err := h.service.ReportContent(c.Request.Context(), userID, resourceType, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to report content")
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// Queue handles GET /api/admin/reports
+// Library content with open reports, most reported first, each with its reports.
+// ?limit=N caps how many entries are returned (default 50, max 200).
+func (h *ModerationHandler) Queue(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	queue, err := h.service.ReportQueue(c.Request.Context(), limit)
+	if err != nil {
+		respondError(c, err, "failed to list reported content")
+		return
+	}
+
+	c.JSON(http.StatusOK, queue)
+}
+
+// ApproveExercise handles POST /api/admin/library/exercises/:id/approve
+// Keeps a public exercise as it is and closes its open reports
+func (h *ModerationHandler) ApproveExercise(c *gin.Context) {
+	h.approve(c, models.ReportResourceExercise)
+}
+
+// ApproveWorkout handles POST /api/admin/library/workouts/:id/approve
+// Keeps a shared workout as it is and closes its open reports
+func (h *ModerationHandler) ApproveWorkout(c *gin.Context) {
+	h.approve(c, models.ReportResourceWorkout)
+}
+
+func (h *ModerationHandler) approve(c *gin.Context, resourceType string) {
+	result, err := h.service.ApproveContent(c.Request.Context(), c.GetString("user_id"), resourceType, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to approve content")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UnpublishExercise handles POST /api/admin/library/exercises/:id/unpublish
+// Makes a public exercise private to its owner and closes its open reports. Workouts and
+// sessions already using it keep it.
+func (h *ModerationHandler) UnpublishExercise(c *gin.Context) {
+	h.unpublish(c, models.ReportResourceExercise)
+}
+
+// UnpublishWorkout handles POST /api/admin/library/workouts/:id/unpublish
+// Stops sharing a workout with its organization, leaving it to its author, and closes its
+// open reports
+func (h *ModerationHandler) UnpublishWorkout(c *gin.Context) {
+	h.unpublish(c, models.ReportResourceWorkout)
+}
+
+func (h *ModerationHandler) unpublish(c *gin.Context, resourceType string) {
+	result, err := h.service.UnpublishContent(c.Request.Context(), c.GetString("user_id"), resourceType, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to unpublish content")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// UpdateExercise handles PUT /api/admin/library/exercises/:id
+// Edits a public exercise and closes its open reports. 409 if it changed since the version
+// the edit is based on.
+func (h *ModerationHandler) UpdateExercise(c *gin.Context) {
+	var req models.UpdateLibraryExerciseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	exercise, err := h.service.UpdateLibraryExercise(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to update exercise")
+		return
+	}
+
+	c.JSON(http.StatusOK, exercise)
+}
+
+// UpdateWorkout handles PUT /api/admin/library/workouts/:id
+// Edits a shared workout's name and description and closes its open reports. 409 if it
+// changed since the version the edit is based on.
+func (h *ModerationHandler) UpdateWorkout(c *gin.Context) {
+	var req models.UpdateSharedWorkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	workout, err := h.service.UpdateSharedWorkout(c.Request.Context(), c.GetString("user_id"), c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to update workout")
+		return
+	}
+
+	c.JSON(http.StatusOK, workout)
+}
+
+// MergeExercises handles POST /api/admin/library/exercises/merge
+// Folds duplicate public exercises into a public target across every user's workouts and
+// logs, then deletes them
+func (h *ModerationHandler) MergeExercises(c *gin.Context) {
+	var req models.MergeExercisesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	result, err := h.service.MergeLibraryExercises(c.Request.Context(), c.GetString("user_id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to merge exercises")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
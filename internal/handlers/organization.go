@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// OrganizationHandler handles HTTP requests for organization endpoints
+type OrganizationHandler struct {
+	service *services.OrganizationService
+}
+
+// NewOrganizationHandler creates a new organization handler
+func NewOrganizationHandler(service *services.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{service: service}
+}
+
+// Create handles POST /api/orgs
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	var req models.CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	org, err := h.service.CreateOrganization(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to create organization")
+		return
+	}
+
+	c.JSON(http.StatusCreated, org)
+}
+
+// List handles GET /api/orgs
+func (h *OrganizationHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	orgs, err := h.service.ListOrganizations(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list organizations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, orgs)
+}
+
+// GetByID handles GET /api/orgs/:id
+func (h *OrganizationHandler) GetByID(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	org, err := h.service.GetOrganization(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err, "failed to get organization")
+		return
+	}
+
+	c.JSON(http.StatusOK, org)
+}
+
+// ListMembers handles GET /api/orgs/:id/members
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	members, err := h.service.ListMembers(c.Request.Context(), id, userID)
+	if err != nil {
+		respondError(c, err, "failed to list members")
+		return
+	}
+
+	c.JSON(http.StatusOK, members)
+}
+
+// AddMember handles POST /api/orgs/:id/members
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	id := c.Param("id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req models.AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	member, err := h.service.AddMember(c.Request.Context(), id, userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to add member")
+		return
+	}
+
+	c.JSON(http.StatusCreated, member)
+}
+
+// UpdateMember handles PUT /api/orgs/:id/members/:user_id
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	id := c.Param("id")
+	memberID := c.Param("user_id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req models.UpdateOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	member, err := h.service.UpdateMemberRole(c.Request.Context(), id, userID, memberID, &req)
+	if err != nil {
+		respondError(c, err, "failed to update member")
+		return
+	}
+
+	c.JSON(http.StatusOK, member)
+}
+
+// RemoveMember handles DELETE /api/orgs/:id/members/:user_id
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	id := c.Param("id")
+	memberID := c.Param("user_id")
+	userID := c.GetString("user_id")
+
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.RemoveMember(c.Request.Context(), id, userID, memberID); err != nil {
+		respondError(c, err, "failed to remove member")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
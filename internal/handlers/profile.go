@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ProfileHandler handles HTTP requests for users' profiles
+type ProfileHandler struct {
+	service *services.ProfileService
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(service *services.ProfileService) *ProfileHandler {
+	return &ProfileHandler{service: service}
+}
+
+// Get handles GET /api/profile
+func (h *ProfileHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	profile, err := h.service.GetProfile(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to get profile"})
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// Update handles PUT /api/profile
+// timezone is an IANA name such as Europe/Madrid; daily and weekly analytics, streaks,
+// weekly reports and challenge days are counted in it.
+// privacy (public, followers or private) sets who can follow the user and see their
+// profile; making a profile public accepts pending follow requests.
+func (h *ProfileHandler) Update(c *gin.Context) {
+	var req models.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	profile, err := h.service.UpdateProfile(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to update profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
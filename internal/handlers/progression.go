@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ProgressionHandler handles HTTP requests for next-session prescriptions
+type ProgressionHandler struct {
+	service *services.ProgressionService
+}
+
+// NewProgressionHandler creates a new progression handler
+func NewProgressionHandler(service *services.ProgressionService) *ProgressionHandler {
+	return &ProgressionHandler{service: service}
+}
+
+// NextPrescription handles GET /api/workouts/:id/next-prescription?scheme=linear|531|double
+// Loads and reps for each exercise of the workout's next session, from the user's logs; clients
+// pre-fill a new session's exercise logs from it. scheme overrides each template entry's
+// progression_scheme (default linear). 531 uses the entry's training max.
+func (h *ProgressionHandler) NextPrescription(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	prescription, err := h.service.NextPrescription(c.Request.Context(), c.Param("id"), userID, c.Query("scheme"))
+	if err != nil {
+		respondError(c, err, "failed to compute prescription")
+		return
+	}
+
+	c.JSON(http.StatusOK, prescription)
+}
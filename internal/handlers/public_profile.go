@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// PublicProfileHandler handles HTTP requests for users' public profiles
+type PublicProfileHandler struct {
+	service *services.PublicProfileService
+}
+
+// NewPublicProfileHandler creates a new public profile handler
+func NewPublicProfileHandler(service *services.PublicProfileService) *PublicProfileHandler {
+	return &PublicProfileHandler{service: service}
+}
+
+// Get handles GET /api/users/:username (the :id segment, holding a username)
+// The user's display name, latest personal records, recent workouts and follower counts,
+// each only if the user shows it. Forbidden unless the profile is public, the caller
+// follows it, or it is their own.
+func (h *PublicProfileHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	profile, err := h.service.GetPublicProfile(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to get public profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// GetSettings handles GET /api/profile/public
+// Not found until the user picks a username.
+func (h *PublicProfileHandler) GetSettings(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	settings, err := h.service.GetSettings(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to get public profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
+
+// UpdateSettings handles PUT /api/profile/public
+// username (3 to 30 lowercase letters, digits or underscores) is what others find the
+// profile by; display_name left out is removed. The show_* flags pick what others see and
+// keep their current value when left out; everything is shown by default.
+func (h *PublicProfileHandler) UpdateSettings(c *gin.Context) {
+	var req models.UpdatePublicProfileSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	settings, err := h.service.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to update public profile")
+		return
+	}
+
+	c.JSON(http.StatusOK, settings)
+}
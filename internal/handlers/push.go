@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// PushHandler handles HTTP requests for devices receiving push notifications
+type PushHandler struct {
+	service *services.PushService
+}
+
+// NewPushHandler creates a new push handler
+func NewPushHandler(service *services.PushService) *PushHandler {
+	return &PushHandler{service: service}
+}
+
+// Register handles POST /api/devices with the device's platform (ios or android) and token
+func (h *PushHandler) Register(c *gin.Context) {
+	var req models.RegisterDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	device, err := h.service.RegisterDevice(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to register device")
+		return
+	}
+
+	c.JSON(http.StatusCreated, device)
+}
+
+// List handles GET /api/devices
+func (h *PushHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	devices, err := h.service.ListDevices(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to list devices")
+		return
+	}
+
+	c.JSON(http.StatusOK, devices)
+}
+
+// Unregister handles DELETE /api/devices/:id
+func (h *PushHandler) Unregister(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.UnregisterDevice(c.Request.Context(), c.Param("id"), userID); err != nil {
+		respondError(c, err, "failed to unregister device")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
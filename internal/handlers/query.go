@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// QueryStatsHandler handles HTTP requests for database query statistics
+type QueryStatsHandler struct {
+	service *services.QueryStatsService
+}
+
+// NewQueryStatsHandler creates a new query stats handler
+func NewQueryStatsHandler(service *services.QueryStatsService) *QueryStatsHandler {
+	return &QueryStatsHandler{service: service}
+}
+
+// Get handles GET /api/admin/queries
+// ?limit=N controls how many statements are returned (default 25, max 200). Figures cover
+// the instance serving the request, since queries are timed in memory.
+func (h *QueryStatsHandler) Get(c *gin.Context) {
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	c.JSON(http.StatusOK, h.service.Stats(limit))
+}
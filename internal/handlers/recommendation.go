@@ -0,0 +1,51 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// RecommendationHandler handles HTTP requests for workout recommendations
+type RecommendationHandler struct {
+	service *services.RecommendationService
+}
+
+// NewRecommendationHandler creates a new recommendation handler
+func NewRecommendationHandler(service *services.RecommendationService) *RecommendationHandler {
+	return &RecommendationHandler{service: service}
+}
+
+// Workout handles GET /api/recommendations/workout?goal=strength|hypertrophy|fat_loss|general&exercises=N
+// Proposes a session targeting the muscle groups trained least recently, using only
+// exercises the user's (or their organizations') equipment allows and that no active injury
+// rules out. Sets and reps follow goal, which defaults to the one implied by the user's open
+// goals. exercises is how many to propose (default 5, max 10).
+// Answers 404 while recommendations aren't rolled out to the user.
+func (h *RecommendationHandler) Workout(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	count := 0
+	if raw := c.Query("exercises"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "exercises must be an integer"})
+			return
+		}
+		count = parsed
+	}
+
+	recommendation, err := h.service.RecommendWorkout(c.Request.Context(), userID, c.Query("goal"), count)
+	if err != nil {
+		respondError(c, err, "failed to recommend workout")
+		return
+	}
+
+	c.JSON(http.StatusOK, recommendation)
+}
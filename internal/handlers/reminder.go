@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ReminderHandler handles HTTP requests for workout reminder rules
+type ReminderHandler struct {
+	service *services.ReminderService
+}
+
+// NewReminderHandler creates a new reminder handler
+func NewReminderHandler(service *services.ReminderService) *ReminderHandler {
+	return &ReminderHandler{service: service}
+}
+
+// Create handles POST /api/reminders, e.g.
+// {"weekdays": [1, 3, 5], "time_of_day": "18:00", "timezone": "Europe/Madrid", "channels": ["push"]}
+func (h *ReminderHandler) Create(c *gin.Context) {
+	var req models.CreateReminderRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	rule, err := h.service.CreateRule(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to create reminder rule")
+		return
+	}
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// List handles GET /api/reminders
+func (h *ReminderHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	rules, err := h.service.ListRules(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to list reminder rules")
+		return
+	}
+
+	c.JSON(http.StatusOK, rules)
+}
+
+// Update handles PUT /api/reminders/:id
+func (h *ReminderHandler) Update(c *gin.Context) {
+	var req models.UpdateReminderRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	rule, err := h.service.UpdateRule(c.Request.Context(), c.Param("id"), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to update reminder rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// Delete handles DELETE /api/reminders/:id
+func (h *ReminderHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteRule(c.Request.Context(), c.Param("id"), userID); err != nil {
+		respondError(c, err, "failed to delete reminder rule")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Preview handles GET /api/reminders/:id/preview?count=5
+func (h *ReminderHandler) Preview(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	count := 5
+	if raw := c.Query("count"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "count must be a positive integer"})
+			return
+		}
+		count = n
+	}
+
+	preview, err := h.service.Preview(c.Request.Context(), c.Param("id"), userID, count)
+	if err != nil {
+		respondError(c, err, "failed to preview reminder rule")
+		return
+	}
+
+	c.JSON(http.StatusOK, preview)
+}
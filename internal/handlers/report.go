@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ReportHandler handles HTTP requests for users' weekly reports
+type ReportHandler struct {
+	service *services.ReportService
+}
+
+// NewReportHandler creates a new report handler
+func NewReportHandler(service *services.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// Weekly handles GET /api/reports/weekly?week=2026-03-02 and GET /api/reports/weekly?limit=12
+// With week (any day of it) the report of that week is returned, otherwise the most
+// recent reports. Reports are compiled on Mondays for the week before.
+func (h *ReportHandler) Weekly(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if raw := c.Query("week"); raw != "" {
+		day, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "week must be a date (YYYY-MM-DD)"})
+			return
+		}
+
+		report, err := h.service.GetWeeklyReport(c.Request.Context(), userID, day)
+		if err != nil {
+			respondError(c, err, "failed to get weekly report")
+			return
+		}
+
+		c.JSON(http.StatusOK, report)
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	reports, err := h.service.ListWeeklyReports(c.Request.Context(), userID, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list weekly reports"})
+		return
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// restTimerKeepAlive is how often an idle event stream sends a comment so proxies keep it open
+const restTimerKeepAlive = 15 * time.Second
+
+// RestTimerHandler handles HTTP requests for session rest timers
+type RestTimerHandler struct {
+	service *services.RestTimerService
+}
+
+// NewRestTimerHandler creates a new rest timer handler
+func NewRestTimerHandler(service *services.RestTimerService) *RestTimerHandler {
+	return &RestTimerHandler{service: service}
+}
+
+// Get handles GET /api/sessions/:id/rest-timer
+func (h *RestTimerHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	timer, err := h.service.GetRestTimer(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get rest timer")
+		return
+	}
+
+	c.JSON(http.StatusOK, timer)
+}
+
+// Update handles POST /api/sessions/:id/rest-timer with action start, skip or extend
+func (h *RestTimerHandler) Update(c *gin.Context) {
+	var req models.RestTimerActionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	timer, err := h.service.UpdateRestTimer(c.Request.Context(), c.Param("id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to update rest timer")
+		return
+	}
+
+	c.JSON(http.StatusOK, timer)
+}
+
+// Events handles GET /api/sessions/:id/rest-timer/events
+// Streams Server-Sent Events: a "timer" event with the current state on connect, then a
+// "timer" event for every start, skip or extend until the client disconnects or the server
+// shuts down.
+func (h *RestTimerHandler) Events(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	timer, events, cancel, err := h.service.SubscribeRestTimer(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to subscribe to rest timer")
+		return
+	}
+	defer cancel()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.SSEvent("timer", &models.RestTimerEvent{Timer: timer})
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(restTimerKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent("timer", event)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
@@ -0,0 +1,65 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// SessionHandler handles HTTP requests for workout sessions
+type SessionHandler struct {
+	service *services.SessionService
+}
+
+// NewSessionHandler creates a new session handler
+func NewSessionHandler(service *services.SessionService) *SessionHandler {
+	return &SessionHandler{service: service}
+}
+
+// List handles GET /api/sessions?limit=20&cursor=<next_cursor>
+// Pages go back through the user's sessions by when they were recorded; pass the previous
+// page's next_cursor to continue. There are no more pages once next_cursor is absent.
+func (h *SessionHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be an integer"})
+			return
+		}
+		limit = parsed
+	}
+
+	page, err := h.service.ListSessions(c.Request.Context(), userID, limit, c.Query("cursor"))
+	if err != nil {
+		respondError(c, err, "failed to list sessions")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// GetByID handles GET /api/sessions/:id
+func (h *SessionHandler) GetByID(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	session, err := h.service.GetSession(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get session")
+		return
+	}
+
+	c.JSON(http.StatusOK, session)
+}
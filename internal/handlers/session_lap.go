@@ -0,0 +1,35 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// SessionLapHandler handles HTTP requests for cardio session laps
+type SessionLapHandler struct {
+	service *services.SessionLapService
+}
+
+// NewSessionLapHandler creates a new session lap handler
+func NewSessionLapHandler(service *services.SessionLapService) *SessionLapHandler {
+	return &SessionLapHandler{service: service}
+}
+
+// List handles GET /api/sessions/:id/laps
+func (h *SessionLapHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	laps, err := h.service.ListLaps(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to list laps")
+		return
+	}
+
+	c.JSON(http.StatusOK, laps)
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// liveSessionProtocol is the WebSocket subprotocol spoken by the live session feed
+	liveSessionProtocol = "fitapi.v1"
+
+	// liveSessionKeepAlive is how often an idle connection is pinged so proxies keep it open
+	liveSessionKeepAlive = 30 * time.Second
+
+	// liveSessionWriteTimeout bounds each message write to a watcher
+	liveSessionWriteTimeout = 10 * time.Second
+)
+
+// SessionLiveHandler handles WebSocket connections watching sessions as they are performed
+type SessionLiveHandler struct {
+	service *services.SessionLiveService
+}
+
+// NewSessionLiveHandler creates a new live session handler
+func NewSessionLiveHandler(service *services.SessionLiveService) *SessionLiveHandler {
+	return &SessionLiveHandler{service: service}
+}
+
+// Watch handles GET /api/ws/sessions/:id
+// Upgrades to a WebSocket that sends JSON events: a "snapshot" with the session's status,
+// rest timer and sets on connect, then set.logged, set.updated, set.deleted,
+// session.status and rest_timer events, and a "ping" when idle. A "resync" event means
+// events may have been missed; the server closes the connection and the client should
+// reconnect for a fresh snapshot. Messages from the client are ignored.
+func (h *SessionLiveHandler) Watch(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+	if !c.IsWebsocket() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "expected a WebSocket upgrade"})
+		return
+	}
+
+	snapshot, events, cancel, err := h.service.Watch(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to watch session")
+		return
+	}
+	defer cancel()
+
+	server := websocket.Server{
+		// Browsers always send an Origin; the bearer token, not cookies, authenticates the
+		// connection, so cross-origin watchers are as safe as cross-origin API calls
+		Handshake: func(config *websocket.Config, req *http.Request) error {
+			// Answer with our protocol only, never echoing the bearer token
+			if slices.Contains(config.Protocol, liveSessionProtocol) {
+				config.Protocol = []string{liveSessionProtocol}
+			} else {
+				config.Protocol = nil
+			}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			h.stream(conn, snapshot, events)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// stream writes events to the connection until the client goes away or the feed ends
+func (h *SessionLiveHandler) stream(conn *websocket.Conn, snapshot *models.LiveSessionEvent, events <-chan *models.LiveSessionEvent) {
+	defer conn.Close()
+
+	// Reading is how a closed connection is noticed
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		var discard []byte
+		for websocket.Message.Receive(conn, &discard) == nil {
+		}
+	}()
+
+	send := func(event *models.LiveSessionEvent) bool {
+		conn.SetWriteDeadline(time.Now().Add(liveSessionWriteTimeout))
+		return websocket.JSON.Send(conn, event) == nil
+	}
+
+	if !send(snapshot) {
+		return
+	}
+
+	keepAlive := time.NewTicker(liveSessionKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-closed:
+			return
+		case event, ok := <-events:
+			if !ok {
+				send(&models.LiveSessionEvent{Type: models.LiveEventResync, SessionID: snapshot.SessionID})
+				return
+			}
+			if !send(event) {
+				return
+			}
+		case <-keepAlive.C:
+			if !send(&models.LiveSessionEvent{Type: models.LiveEventPing}) {
+				return
+			}
+		}
+	}
+}
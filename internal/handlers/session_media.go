@@ -0,0 +1,138 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// SessionMediaHandler handles HTTP requests for session media and highlights
+type SessionMediaHandler struct {
+	service *services.SessionMediaService
+}
+
+// NewSessionMediaHandler creates a new session media handler
+func NewSessionMediaHandler(service *services.SessionMediaService) *SessionMediaHandler {
+	return &SessionMediaHandler{service: service}
+}
+
+// List handles GET /api/sessions/:id/media
+func (h *SessionMediaHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	media, err := h.service.ListMedia(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to list media")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// Create handles POST /api/sessions/:id/media
+func (h *SessionMediaHandler) Create(c *gin.Context) {
+	var req models.CreateSessionMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	media, err := h.service.AddMedia(c.Request.Context(), c.Param("id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to add media")
+		return
+	}
+
+	c.JSON(http.StatusCreated, media)
+}
+
+// Update handles PUT /api/sessions/:id/media/:media_id
+func (h *SessionMediaHandler) Update(c *gin.Context) {
+	var req models.UpdateSessionMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	media, err := h.service.UpdateMedia(c.Request.Context(), c.Param("id"), c.Param("media_id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to update media")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// Delete handles DELETE /api/sessions/:id/media/:media_id
+func (h *SessionMediaHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteMedia(c.Request.Context(), c.Param("id"), c.Param("media_id"), userID); err != nil {
+		respondError(c, err, "failed to delete media")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Reorder handles PUT /api/sessions/:id/media/order
+func (h *SessionMediaHandler) Reorder(c *gin.Context) {
+	var req models.ReorderSessionMediaRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	media, err := h.service.ReorderMedia(c.Request.Context(), c.Param("id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to reorder media")
+		return
+	}
+
+	c.JSON(http.StatusOK, media)
+}
+
+// Highlights handles GET /api/sessions/:id/highlights
+func (h *SessionMediaHandler) Highlights(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	highlights, err := h.service.GetHighlights(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get highlights")
+		return
+	}
+
+	c.JSON(http.StatusOK, highlights)
+}
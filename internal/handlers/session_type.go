@@ -0,0 +1,109 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// SessionTypeHandler handles HTTP requests for the session type registry and typed sessions
+type SessionTypeHandler struct {
+	service *services.SessionTypeService
+}
+
+// NewSessionTypeHandler creates a new session type handler
+func NewSessionTypeHandler(service *services.SessionTypeService) *SessionTypeHandler {
+	return &SessionTypeHandler{service: service}
+}
+
+// List handles GET /api/session-types
+func (h *SessionTypeHandler) List(c *gin.Context) {
+	types, err := h.service.List(c.Request.Context())
+	if err != nil {
+		respondError(c, err, "failed to list session types")
+		return
+	}
+
+	c.JSON(http.StatusOK, types)
+}
+
+// Create handles POST /api/admin/session-types
+func (h *SessionTypeHandler) Create(c *gin.Context) {
+	var req models.CreateSessionTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	sessionType, err := h.service.Register(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err, "failed to create session type")
+		return
+	}
+
+	c.JSON(http.StatusCreated, sessionType)
+}
+
+// SetType handles PUT /api/sessions/:id/type
+func (h *SessionTypeHandler) SetType(c *gin.Context) {
+	var req models.SetSessionTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	assignment, err := h.service.SetSessionType(c.Request.Context(), c.Param("id"), &req, userID)
+	if err != nil {
+		respondError(c, err, "failed to set session type")
+		return
+	}
+
+	c.JSON(http.StatusOK, assignment)
+}
+
+// Summaries handles GET /api/analytics/session-types?from=2026-01-01&to=2026-04-01
+// to defaults to tomorrow (so today is included) and from to 30 days before to.
+func (h *SessionTypeHandler) Summaries(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+			return
+		}
+		to = parsed
+	}
+
+	from := to.AddDate(0, 0, -30)
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+			return
+		}
+		from = parsed
+	}
+
+	summaries, err := h.service.Summaries(c.Request.Context(), userID, from, to)
+	if err != nil {
+		respondError(c, err, "failed to get session type summaries")
+		return
+	}
+
+	c.JSON(http.StatusOK, summaries)
+}
@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// ShareCardHandler handles HTTP requests for session share cards
+type ShareCardHandler struct {
+	service *services.ShareCardService
+}
+
+// NewShareCardHandler creates a new share card handler
+func NewShareCardHandler(service *services.ShareCardService) *ShareCardHandler {
+	return &ShareCardHandler{service: service}
+}
+
+// Create handles POST /api/sessions/:id/share-card
+// Starts rendering a summary image of a completed session (duration, volume, sets and
+// personal records) as png (the default) or svg. Returns 202 with the pending card; poll
+// GET /api/share-cards/:id until it is ready and has a download url.
+func (h *ShareCardHandler) Create(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	// The body is optional
+	var req models.CreateShareCardRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err, &req)
+			return
+		}
+	}
+
+	card, err := h.service.RequestShareCard(c.Request.Context(), userID, c.Param("id"), req.Format)
+	if err != nil {
+		respondError(c, err, "failed to start share card")
+		return
+	}
+
+	c.JSON(http.StatusAccepted, card)
+}
+
+// Get handles GET /api/share-cards/:id
+// Ready cards come with a signed url that downloads the image without authentication,
+// for handing to social apps; every call signs a new url valid for 7 days.
+func (h *ShareCardHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	card, err := h.service.GetShareCard(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get share card")
+		return
+	}
+
+	c.JSON(http.StatusOK, card)
+}
+
+// Image handles GET /api/share-cards/:id/image?expires=&signature=
+// Downloads a card's image through the signed url of GET /api/share-cards/:id; no
+// authentication is needed. Tampered links answer 403 and expired ones 410.
+func (h *ShareCardHandler) Image(c *gin.Context) {
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		respondError(c, services.ErrShareLinkInvalid, "failed to get share card image")
+		return
+	}
+
+	image, contentType, err := h.service.ShareCardImage(c.Request.Context(), c.Param("id"), expires, c.Query("signature"))
+	if err != nil {
+		respondError(c, err, "failed to get share card image")
+		return
+	}
+
+	// A card's image never changes once rendered
+	c.Header("Cache-Control", "public, max-age=86400, immutable")
+	c.Data(http.StatusOK, contentType, image)
+}
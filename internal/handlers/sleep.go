@@ -0,0 +1,141 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// SleepHandler handles HTTP requests for sleep logs
+type SleepHandler struct {
+	service *services.SleepService
+}
+
+// NewSleepHandler creates a new sleep handler
+func NewSleepHandler(service *services.SleepService) *SleepHandler {
+	return &SleepHandler{service: service}
+}
+
+// List handles GET /api/sleep?from=2026-03-01&to=2026-04-01
+// Sleep is listed by the date it ended. to defaults to tomorrow in the user's timezone (so
+// last night is included) and from to 30 days before to.
+func (h *SleepHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var to time.Time
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+			return
+		}
+		to = parsed
+	}
+
+	var from time.Time
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+			return
+		}
+		from = parsed
+	}
+
+	logs, err := h.service.ListSleep(c.Request.Context(), userID, from, to)
+	if err != nil {
+		respondError(c, err, "failed to list sleep")
+		return
+	}
+
+	c.JSON(http.StatusOK, logs)
+}
+
+// Create handles POST /api/sleep
+func (h *SleepHandler) Create(c *gin.Context) {
+	var req models.CreateSleepLogRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	log, err := h.service.LogSleep(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to log sleep")
+		return
+	}
+
+	c.JSON(http.StatusCreated, log)
+}
+
+// Delete handles DELETE /api/sleep/:id
+func (h *SleepHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteSleep(c.Request.Context(), userID, c.Param("id")); err != nil {
+		respondError(c, err, "failed to delete sleep log")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Import handles POST /api/sleep/import?source=sleepcycle|fitbit&timezone=Europe/Madrid
+// The CSV export is uploaded as the multipart form field "file". Timestamps without an
+// offset are read in timezone (default: the user's timezone).
+func (h *SleepHandler) Import(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var loc *time.Location
+	if raw := c.Query("timezone"); raw != "" {
+		parsed, err := time.LoadLocation(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid timezone"})
+			return
+		}
+		loc = parsed
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "upload the export as multipart field 'file' (max 10MB)"})
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read uploaded file"})
+		return
+	}
+	defer file.Close()
+
+	result, err := h.service.ImportSleepCSV(c.Request.Context(), userID, c.Query("source"), loc, file)
+	if err != nil {
+		respondError(c, err, "failed to import sleep")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// SLOHandler handles HTTP requests for response time objectives
+type SLOHandler struct {
+	service *services.SLOService
+}
+
+// NewSLOHandler creates a new SLO handler
+func NewSLOHandler(service *services.SLOService) *SLOHandler {
+	return &SLOHandler{service: service}
+}
+
+// Get handles GET /api/admin/slo
+// Figures cover the instance serving the request, since latencies are tracked in memory.
+func (h *SLOHandler) Get(c *gin.Context) {
+	c.JSON(http.StatusOK, h.service.Report())
+}
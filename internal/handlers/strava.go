@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// StravaHandler handles HTTP requests for the Strava integration and its webhook
+type StravaHandler struct {
+	service *services.StravaService
+}
+
+// NewStravaHandler creates a new Strava handler
+func NewStravaHandler(service *services.StravaService) *StravaHandler {
+	return &StravaHandler{service: service}
+}
+
+// Authorize handles GET /api/integrations/strava/connect
+// The client opens auth_url; Strava redirects back with code and state for Connect.
+func (h *StravaHandler) Authorize(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	authorization, err := h.service.Authorize(userID)
+	if err != nil {
+		respondError(c, err, "failed to start strava connection")
+		return
+	}
+
+	c.JSON(http.StatusOK, authorization)
+}
+
+// Connect handles POST /api/integrations/strava/connect
+func (h *StravaHandler) Connect(c *gin.Context) {
+	var req models.ConnectIntegrationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conn, err := h.service.Connect(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to connect strava")
+		return
+	}
+
+	c.JSON(http.StatusCreated, conn)
+}
+
+// Get handles GET /api/integrations/strava
+func (h *StravaHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conn, err := h.service.GetConnection(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to get strava connection")
+		return
+	}
+
+	c.JSON(http.StatusOK, conn)
+}
+
+// UpdateSettings handles PATCH /api/integrations/strava
+func (h *StravaHandler) UpdateSettings(c *gin.Context) {
+	var req models.UpdateIntegrationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	conn, err := h.service.UpdateSettings(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to update strava settings")
+		return
+	}
+
+	c.JSON(http.StatusOK, conn)
+}
+
+// Disconnect handles DELETE /api/integrations/strava
+func (h *StravaHandler) Disconnect(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.Disconnect(c.Request.Context(), userID); err != nil {
+		respondError(c, err, "failed to disconnect strava")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Sync handles POST /api/integrations/strava/sync
+func (h *StravaHandler) Sync(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	result, err := h.service.Sync(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to sync strava")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// VerifyWebhook handles GET /webhooks/strava, the subscription validation request
+func (h *StravaHandler) VerifyWebhook(c *gin.Context) {
+	challenge, err := h.service.VerifySubscription(c.Query("hub.mode"), c.Query("hub.verify_token"), c.Query("hub.challenge"))
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"hub.challenge": challenge})
+}
+
+// Webhook handles POST /webhooks/strava
+// Strava expects a 200 within two seconds and retries otherwise, so events are only queued here.
+func (h *StravaHandler) Webhook(c *gin.Context) {
+	var event models.StravaWebhookEvent
+	if err := c.ShouldBindJSON(&event); err != nil {
+		respondBindError(c, err, &event)
+		return
+	}
+
+	if err := h.service.HandleEvent(c.Request.Context(), &event); err != nil {
+		log.Printf("Strava webhook event failed: owner=%d object=%d err=%v", event.OwnerID, event.ObjectID, err)
+		respondError(c, err, "failed to handle strava event")
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{})
+}
@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// TrainingMaxHandler handles HTTP requests for training max endpoints
+type TrainingMaxHandler struct {
+	service *services.TrainingMaxService
+}
+
+// NewTrainingMaxHandler creates a new training max handler
+func NewTrainingMaxHandler(service *services.TrainingMaxService) *TrainingMaxHandler {
+	return &TrainingMaxHandler{service: service}
+}
+
+// List handles GET /api/training-maxes
+func (h *TrainingMaxHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	tms, err := h.service.ListTrainingMaxes(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list training maxes"})
+		return
+	}
+
+	c.JSON(http.StatusOK, tms)
+}
+
+// Set handles PUT /api/training-maxes/:name
+func (h *TrainingMaxHandler) Set(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var req models.SetTrainingMaxRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	tm, err := h.service.SetTrainingMax(c.Request.Context(), userID, c.Param("name"), &req)
+	if err != nil {
+		respondError(c, err, "failed to save training max")
+		return
+	}
+
+	c.JSON(http.StatusOK, tm)
+}
+
+// Delete handles DELETE /api/training-maxes/:name
+func (h *TrainingMaxHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteTrainingMax(c.Request.Context(), userID, c.Param("name")); err != nil {
+		respondError(c, err, "failed to delete training max")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// TrendHandler handles HTTP requests for metric trends
+type TrendHandler struct {
+	service *services.TrendService
+}
+
+// NewTrendHandler creates a new trend handler
+func NewTrendHandler(service *services.TrendService) *TrendHandler {
+	return &TrendHandler{service: service}
+}
+
+// Get handles GET /api/analytics/trends/:metric?from=2022-01-01&to=2025-01-01
+// to defaults to tomorrow in the user's timezone (so today is included) and from to
+// one year before to.
+func (h *TrendHandler) Get(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	var to time.Time
+	if raw := c.Query("to"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a date (YYYY-MM-DD)"})
+			return
+		}
+		to = parsed
+	}
+
+	var from time.Time
+	if raw := c.Query("from"); raw != "" {
+		parsed, err := time.Parse("2006-01-02", raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a date (YYYY-MM-DD)"})
+			return
+		}
+		from = parsed
+	}
+
+	trend, err := h.service.GetTrend(c.Request.Context(), userID, c.Param("metric"), from, to)
+	if err != nil {
+		respondError(c, err, "failed to get trend")
+		return
+	}
+
+	c.JSON(http.StatusOK, trend)
+}
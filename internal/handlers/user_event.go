@@ -0,0 +1,64 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// userEventKeepAlive is how often an idle event stream sends a comment so proxies keep it open
+const userEventKeepAlive = 15 * time.Second
+
+// UserEventHandler handles the stream of events for a user's connected clients
+type UserEventHandler struct {
+	service *services.UserEventService
+}
+
+// NewUserEventHandler creates a new user event handler
+func NewUserEventHandler(service *services.UserEventService) *UserEventHandler {
+	return &UserEventHandler{service: service}
+}
+
+// Stream handles GET /api/events
+// Streams Server-Sent Events named after the event type (pr.achieved, workout.assigned,
+// import.finished) until the client disconnects or the server shuts down. Events raised
+// while the client is disconnected are not replayed.
+func (h *UserEventHandler) Stream(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	events, cancel := h.service.Subscribe(userID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	io.WriteString(c.Writer, ": connected\n\n")
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(userEventKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		}
+	})
+}
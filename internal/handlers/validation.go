@@ -0,0 +1,183 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError is one invalid field of a request body. Field is its path in the JSON body,
+// e.g. exercises[0].reps, and Rule the binding rule it failed, e.g. required or max.
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// respondBindError answers 400 for a body ShouldBindJSON rejected. Failed binding rules and
+// values of the wrong type are listed per field, so clients can point at the invalid input;
+// malformed JSON keeps the decoder's message.
+func respondBindError(c *gin.Context, err error, obj any) {
+	fields := fieldErrors(err, reflect.TypeOf(obj))
+	if len(fields) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error": "invalid request body", "fields": fields})
+}
+
+// fieldErrors lists the fields of root, the type bound, that err reports as invalid
+func fieldErrors(err error, root reflect.Type) []FieldError {
+	var validationErrs validator.ValidationErrors
+	if errors.As(err, &validationErrs) {
+		fields := make([]FieldError, 0, len(validationErrs))
+		for _, fe := range validationErrs {
+			field := jsonPath(root, fe.StructNamespace())
+			fields = append(fields, FieldError{
+				Field:   field,
+				Rule:    fe.Tag(),
+				Message: field + " " + ruleMessage(fe),
+			})
+		}
+		return fields
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		field := indexPath(typeErr.Field)
+		message := field + " has the wrong type"
+		if typeErr.Type != nil {
+			message = field + " must be " + typeName(typeErr.Type)
+		}
+		return []FieldError{{Field: field, Rule: "type", Message: message}}
+	}
+	return nil
+}
+
+// ruleMessage describes what a failed binding rule expects
+func ruleMessage(fe validator.FieldError) string {
+	kind := fe.Kind()
+	if kind == reflect.Pointer {
+		kind = fe.Type().Elem().Kind()
+	}
+	unit := ""
+	switch kind {
+	case reflect.String:
+		unit = " characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		unit = " items"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min", "gte":
+		return "must be at least " + fe.Param() + unit
+	case "max", "lte":
+		return "must be at most " + fe.Param() + unit
+	case "gt":
+		return "must be greater than " + fe.Param() + unit
+	case "lt":
+		return "must be less than " + fe.Param() + unit
+	case "len":
+		return "must be exactly " + fe.Param() + unit
+	case "oneof":
+		return "must be one of " + strings.Join(strings.Fields(fe.Param()), ", ")
+	case "uuid":
+		return "must be a UUID"
+	case "url":
+		return "must be a URL"
+	case "email":
+		return "must be an email address"
+	case "datetime":
+		return "must be a date formatted as " + fe.Param()
+	}
+	if fe.Param() != "" {
+		return fmt.Sprintf("must satisfy %s=%s", fe.Tag(), fe.Param())
+	}
+	return "must satisfy " + fe.Tag()
+}
+
+// jsonPath converts a validator namespace, e.g. CreateWorkoutRequest.Exercises[0].Reps, to
+// the field's path in the JSON body of root, e.g. exercises[0].reps
+func jsonPath(root reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:] // The first is the type's name
+	var path []string
+	t := root
+	for _, segment := range segments {
+		name, index, _ := strings.Cut(segment, "[")
+		if index != "" {
+			index = "[" + index
+		}
+
+		for t != nil && (t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, segment)
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		t = field.Type
+
+		jsonName, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		switch {
+		case field.Anonymous && jsonName == "":
+			// Embedded struct fields are promoted into the parent object
+		case jsonName == "":
+			path = append(path, name+index)
+		default:
+			path = append(path, jsonName+index)
+		}
+	}
+	return strings.Join(path, ".")
+}
+
+// indexPath writes the array indexes of a decoder's field path, e.g. sets.0.reps, the way
+// jsonPath does: sets[0].reps
+func indexPath(field string) string {
+	var path strings.Builder
+	for i, segment := range strings.Split(field, ".") {
+		switch {
+		case segment != "" && strings.Trim(segment, "0123456789") == "":
+			path.WriteString("[" + segment + "]")
+		case i > 0:
+			path.WriteString("." + segment)
+		default:
+			path.WriteString(segment)
+		}
+	}
+	return path.String()
+}
+
+// typeName describes a Go type in JSON terms
+func typeName(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "an integer"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "an array"
+	}
+	return "an object"
+}
@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// WebhookHandler handles HTTP requests for users' outgoing webhook endpoints
+type WebhookHandler struct {
+	service *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(service *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{service: service}
+}
+
+// Create handles POST /api/webhooks
+// The response includes the signing secret, which is not shown again.
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var req models.CreateWebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	endpoint, err := h.service.CreateEndpoint(c.Request.Context(), userID, &req)
+	if err != nil {
+		respondError(c, err, "failed to create webhook endpoint")
+		return
+	}
+
+	c.JSON(http.StatusCreated, endpoint)
+}
+
+// List handles GET /api/webhooks
+func (h *WebhookHandler) List(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	endpoints, err := h.service.ListEndpoints(c.Request.Context(), userID)
+	if err != nil {
+		respondError(c, err, "failed to list webhook endpoints")
+		return
+	}
+
+	c.JSON(http.StatusOK, endpoints)
+}
+
+// Delete handles DELETE /api/webhooks/:id
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	if err := h.service.DeleteEndpoint(c.Request.Context(), c.Param("id"), userID); err != nil {
+		respondError(c, err, "failed to delete webhook endpoint")
+		return
+	}
+
+	c.JSON(http.StatusNoContent, nil)
+}
+
+// Deliveries handles GET /api/webhooks/:id/deliveries?limit=50
+func (h *WebhookHandler) Deliveries(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	limit := 50
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a positive integer"})
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Request.Context(), c.Param("id"), userID, limit)
+	if err != nil {
+		respondError(c, err, "failed to list webhook deliveries")
+		return
+	}
+
+	c.JSON(http.StatusOK, deliveries)
+}
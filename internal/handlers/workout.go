@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// WorkoutHandler handles HTTP requests for workout templates
+type WorkoutHandler struct {
+	service *services.WorkoutService
+}
+
+// NewWorkoutHandler creates a new workout handler
+func NewWorkoutHandler(service *services.WorkoutService) *WorkoutHandler {
+	return &WorkoutHandler{service: service}
+}
+
+// GetByID handles GET /api/workouts/:id
+func (h *WorkoutHandler) GetByID(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	workout, err := h.service.GetWorkout(c.Request.Context(), c.Param("id"), userID)
+	if err != nil {
+		respondError(c, err, "failed to get workout")
+		return
+	}
+
+	c.JSON(http.StatusOK, workout)
+}
+
+// ListOrg handles GET /api/orgs/:id/workouts
+// The organization's shared workout templates, by name
+func (h *WorkoutHandler) ListOrg(c *gin.Context) {
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	workouts, err := h.service.ListOrgWorkouts(c.Request.Context(), userID, c.Param("id"))
+	if err != nil {
+		respondError(c, err, "failed to list workouts")
+		return
+	}
+
+	c.JSON(http.StatusOK, workouts)
+}
+
+// Share handles POST /api/orgs/:id/workouts
+// Shares one of the caller's personal workouts with the organization (owners and trainers).
+// A workout can belong to one organization; sharing it again answers 409.
+func (h *WorkoutHandler) Share(c *gin.Context) {
+	var req models.ShareWorkoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err, &req)
+		return
+	}
+
+	userID := c.GetString("user_id")
+	if userID == "" {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "user not authenticated"})
+		return
+	}
+
+	workout, err := h.service.ShareWorkout(c.Request.Context(), userID, c.Param("id"), &req)
+	if err != nil {
+		respondError(c, err, "failed to share workout")
+		return
+	}
+
+	c.JSON(http.StatusOK, workout)
+}
@@ -0,0 +1,193 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fitWriter assembles a minimal little-endian FIT file for tests
+type fitWriter struct {
+	records bytes.Buffer
+}
+
+type fitTestField struct {
+	num   byte
+	size  byte
+	value uint32
+}
+
+// message writes a definition for local type 0 followed by one data message
+func (w *fitWriter) message(global uint16, fields ...fitTestField) {
+	w.records.Write([]byte{0x40, 0, 0})
+	binary.Write(&w.records, binary.LittleEndian, global)
+	w.records.WriteByte(byte(len(fields)))
+	for _, f := range fields {
+		w.records.Write([]byte{f.num, f.size, 0x86})
+	}
+
+	w.records.WriteByte(0x00)
+	for _, f := range fields {
+		switch f.size {
+		case 1:
+			w.records.WriteByte(byte(f.value))
+		case 2:
+			binary.Write(&w.records, binary.LittleEndian, uint16(f.value))
+		case 4:
+			binary.Write(&w.records, binary.LittleEndian, f.value)
+		}
+	}
+}
+
+func (w *fitWriter) bytes() []byte {
+	header := make([]byte, 14)
+	header[0] = 14
+	header[1] = 0x20
+	binary.LittleEndian.PutUint32(header[4:8], uint32(w.records.Len()))
+	copy(header[8:12], ".FIT")
+	return append(header, w.records.Bytes()...)
+}
+
+func fitTimestamp(t time.Time) uint32 {
+	return uint32(t.Unix() - fitEpoch)
+}
+
+func TestParseFIT(t *testing.T) {
+	start := time.Date(2026, 5, 6, 5, 30, 0, 0, time.UTC)
+
+	w := &fitWriter{}
+	for i, lap := range []struct{ ms, cm, avg, max uint32 }{{300000, 100000, 150, 160}, {290000, 100000, 0xFF, 170}} {
+		w.message(fitMesgLap,
+			fitTestField{fitFieldStartTime, 4, fitTimestamp(start.Add(time.Duration(i*300) * time.Second))},
+			fitTestField{fitFieldTotalTimerTime, 4, lap.ms},
+			fitTestField{fitFieldTotalDistance, 4, lap.cm},
+			fitTestField{fitFieldLapAvgHeartRate, 1, lap.avg},
+			fitTestField{fitFieldLapMaxHeartRate, 1, lap.max},
+		)
+	}
+	w.message(fitMesgSession,
+		fitTestField{fitFieldStartTime, 4, fitTimestamp(start)},
+		fitTestField{fitFieldSport, 1, 1},
+		fitTestField{fitFieldTotalElapsedTime, 4, 600000},
+		fitTestField{fitFieldTotalTimerTime, 4, 590000},
+		fitTestField{fitFieldTotalDistance, 4, 200000},
+		fitTestField{fitFieldTotalCalories, 2, 140},
+		fitTestField{fitFieldSessAvgHeartRate, 1, 155},
+		fitTestField{fitFieldSessMaxHeartRate, 1, 170},
+	)
+
+	sessions, err := ParseFIT(bytes.NewReader(w.bytes()), Options{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(sessions))
+	}
+
+	run := sessions[0]
+	if run.Name != "Running" || run.Type != "cardio" || !run.StartedAt.Equal(start) {
+		t.Errorf("Expected a cardio Running session at %v, got %q (%s) at %v", start, run.Name, run.Type, run.StartedAt)
+	}
+	if run.Duration != 590*time.Second || *run.Sets[0].DistanceM != 2000 {
+		t.Errorf("Expected 590s over 2000m, got %v over %v", run.Duration, *run.Sets[0].DistanceM)
+	}
+	if *run.CaloriesBurned != 140 || *run.HeartRateAvg != 155 || *run.HeartRateMax != 170 {
+		t.Errorf("Expected 140 kcal and 155/170 bpm, got %v and %v/%v", *run.CaloriesBurned, *run.HeartRateAvg, *run.HeartRateMax)
+	}
+	if len(run.Laps) != 2 {
+		t.Fatalf("Expected 2 laps, got %d", len(run.Laps))
+	}
+	if *run.Laps[0].DistanceM != 1000 || run.Laps[0].Duration != 300*time.Second || *run.Laps[0].HeartRateAvg != 150 {
+		t.Errorf("Unexpected first lap %+v", run.Laps[0])
+	}
+	if run.Laps[1].HeartRateAvg != nil {
+		t.Errorf("Expected invalid heart rate to be dropped, got %v", *run.Laps[1].HeartRateAvg)
+	}
+}
+
+func TestParseFIT_Invalid(t *testing.T) {
+	w := &fitWriter{}
+	w.message(fitMesgLap, fitTestField{fitFieldStartTime, 4, 1})
+	valid := w.bytes()
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"not fit", []byte("definitely not a fit file")},
+		{"truncated", valid[:len(valid)-2]},
+		{"no activity", (&fitWriter{}).bytes()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseFIT(bytes.NewReader(tt.data), Options{})
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Expected ErrInvalidFile, got %v", err)
+			}
+		})
+	}
+}
+
+const testTCX = `<?xml version="1.0" encoding="UTF-8"?>
+<TrainingCenterDatabase xmlns="http://www.garmin.com/xmlschemas/TrainingCenterDatabase/v2">
+ <Activities>
+  <Activity Sport="Biking">
+   <Id>2026-05-09T08:00:00Z</Id>
+   <Lap StartTime="2026-05-09T08:00:00Z">
+    <TotalTimeSeconds>1200</TotalTimeSeconds>
+    <DistanceMeters>10000</DistanceMeters>
+    <Calories>300</Calories>
+    <AverageHeartRateBpm><Value>130</Value></AverageHeartRateBpm>
+    <MaximumHeartRateBpm><Value>150</Value></MaximumHeartRateBpm>
+    <Track><Trackpoint><Time>2026-05-09T08:00:01Z</Time></Trackpoint></Track>
+   </Lap>
+   <Lap StartTime="2026-05-09T08:20:00Z">
+    <TotalTimeSeconds>600</TotalTimeSeconds>
+    <DistanceMeters>4000</DistanceMeters>
+    <Calories>150</Calories>
+    <AverageHeartRateBpm><Value>160</Value></AverageHeartRateBpm>
+    <MaximumHeartRateBpm><Value>175</Value></MaximumHeartRateBpm>
+   </Lap>
+  </Activity>
+ </Activities>
+</TrainingCenterDatabase>`
+
+func TestParseTCX(t *testing.T) {
+	sessions, err := ParseTCX(strings.NewReader(testTCX), Options{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 session, got %d", len(sessions))
+	}
+
+	ride := sessions[0]
+	if ride.Name != "Cycling" || ride.Type != "cardio" || len(ride.Laps) != 2 {
+		t.Fatalf("Expected a cardio Cycling session with 2 laps, got %q (%s) with %d", ride.Name, ride.Type, len(ride.Laps))
+	}
+	if ride.Duration != 30*time.Minute || *ride.Sets[0].DistanceM != 14000 || *ride.CaloriesBurned != 450 {
+		t.Errorf("Expected totals 30m, 14km and 450 kcal, got %v, %v and %v", ride.Duration, *ride.Sets[0].DistanceM, *ride.CaloriesBurned)
+	}
+	// Time-weighted: (130*1200 + 160*600) / 1800
+	if *ride.HeartRateAvg != 140 || *ride.HeartRateMax != 175 {
+		t.Errorf("Expected 140/175 bpm, got %v/%v", *ride.HeartRateAvg, *ride.HeartRateMax)
+	}
+}
+
+func TestDetectActivityFormat(t *testing.T) {
+	if format, err := DetectActivityFormat((&fitWriter{}).bytes()); err != nil || format != "fit" {
+		t.Errorf("Expected fit, got %q (%v)", format, err)
+	}
+	if format, err := DetectActivityFormat([]byte(testTCX)); err != nil || format != "tcx" {
+		t.Errorf("Expected tcx, got %q (%v)", format, err)
+	}
+	if _, err := DetectActivityFormat([]byte("Date,Exercise\n")); !errors.Is(err, ErrInvalidFile) {
+		t.Errorf("Expected ErrInvalidFile, got %v", err)
+	}
+}
@@ -0,0 +1,305 @@
+package importer
+
+import (
+	"bufio"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+const kilojoulesPerKcal = 4.184
+
+// appleWorkoutNames maps HealthKit activity types whose split name would read poorly
+var appleWorkoutNames = map[string]string{
+	"HighIntensityIntervalTraining": "HIIT",
+	"TraditionalStrengthTraining":   "Strength Training",
+	"MixedCardio":                   "Cardio",
+	"Other":                         "Workout",
+}
+
+// ParseAppleHealth parses Apple Health workouts into cardio sessions, one set per workout
+// carrying its distance and duration, with calories and heart rate on the session.
+// It accepts the export.xml from the Health app's "Export All Health Data" (read as a
+// stream, skipping everything but Workout elements) or JSON pushed by a companion app:
+//
+//	{"workouts": [{"activity_type": "running", "start_date": "2026-05-06T07:30:00+02:00",
+//	  "end_date": "...", "duration_seconds": 1830, "total_distance_meters": 5010,
+//	  "total_energy_burned_kcal": 320, "heart_rate_avg": 145, "heart_rate_max": 172}]}
+//
+// activity_type may be a HealthKit name (HKWorkoutActivityTypeRunning) or a short one.
+func ParseAppleHealth(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return nil, fmt.Errorf("%w: empty file", ErrInvalidFile)
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			break
+		}
+		br.ReadByte()
+	}
+
+	if b, _ := br.Peek(1); b[0] == '{' || b[0] == '[' {
+		return parseHealthKitJSON(br, opts)
+	}
+	return parseAppleHealthXML(br, opts)
+}
+
+type appleWorkout struct {
+	ActivityType string              `xml:"workoutActivityType,attr"`
+	Duration     string              `xml:"duration,attr"`
+	DurationUnit string              `xml:"durationUnit,attr"`
+	Distance     string              `xml:"totalDistance,attr"`
+	DistanceUnit string              `xml:"totalDistanceUnit,attr"`
+	Energy       string              `xml:"totalEnergyBurned,attr"`
+	EnergyUnit   string              `xml:"totalEnergyBurnedUnit,attr"`
+	SourceName   string              `xml:"sourceName,attr"`
+	StartDate    string              `xml:"startDate,attr"`
+	EndDate      string              `xml:"endDate,attr"`
+	Statistics   []appleWorkoutStats `xml:"WorkoutStatistics"`
+}
+
+// appleWorkoutStats holds per-workout aggregates; since iOS 16 distance and energy
+// are only reported here rather than as Workout attributes
+type appleWorkoutStats struct {
+	Type    string `xml:"type,attr"`
+	Sum     string `xml:"sum,attr"`
+	Average string `xml:"average,attr"`
+	Maximum string `xml:"maximum,attr"`
+	Unit    string `xml:"unit,attr"`
+}
+
+func parseAppleHealthXML(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	decoder := xml.NewDecoder(r)
+
+	var sessions []*models.ImportedSession
+	seenRoot := false
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		if !seenRoot {
+			if start.Name.Local != "HealthData" {
+				return nil, fmt.Errorf("%w: not an Apple Health export", ErrInvalidFile)
+			}
+			seenRoot = true
+		}
+		if start.Name.Local != "Workout" {
+			continue
+		}
+
+		var w appleWorkout
+		if err := decoder.DecodeElement(&w, &start); err != nil {
+			return nil, fmt.Errorf("%w: workout %d: %v", ErrInvalidFile, len(sessions)+1, err)
+		}
+		session, err := w.session(opts.Location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: workout %d: %v", ErrInvalidFile, len(sessions)+1, err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	if !seenRoot {
+		return nil, fmt.Errorf("%w: not an Apple Health export", ErrInvalidFile)
+	}
+	return sessions, nil
+}
+
+func (w *appleWorkout) session(loc *time.Location) (*models.ImportedSession, error) {
+	startedAt, err := parseAppleTime(w.StartDate, loc)
+	if err != nil {
+		return nil, err
+	}
+	endedAt, err := parseAppleTime(w.EndDate, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := endedAt.Sub(startedAt)
+	if d, err := strconv.ParseFloat(w.Duration, 64); err == nil && d > 0 {
+		duration = appleDuration(d, w.DurationUnit)
+	}
+
+	distanceM := appleDistance(w.Distance, w.DistanceUnit)
+	kcal := appleEnergy(w.Energy, w.EnergyUnit)
+	var heartRateAvg, heartRateMax *float64
+	for _, stat := range w.Statistics {
+		switch {
+		case stat.Type == "HKQuantityTypeIdentifierHeartRate":
+			heartRateAvg = parseOptionalFloat(stat.Average)
+			heartRateMax = parseOptionalFloat(stat.Maximum)
+		case stat.Type == "HKQuantityTypeIdentifierActiveEnergyBurned" && kcal == nil:
+			kcal = appleEnergy(stat.Sum, stat.Unit)
+		case strings.HasPrefix(stat.Type, "HKQuantityTypeIdentifierDistance") && distanceM == nil:
+			distanceM = appleDistance(stat.Sum, stat.Unit)
+		}
+	}
+
+	return cardioSession(appleActivityName(w.ActivityType), startedAt, duration, distanceM, kcal, heartRateAvg, heartRateMax), nil
+}
+
+type healthKitWorkout struct {
+	ActivityType     string   `json:"activity_type"`
+	StartDate        string   `json:"start_date"`
+	EndDate          string   `json:"end_date"`
+	DurationSeconds  *float64 `json:"duration_seconds"`
+	DistanceMeters   *float64 `json:"total_distance_meters"`
+	EnergyBurnedKcal *float64 `json:"total_energy_burned_kcal"`
+	HeartRateAvg     *float64 `json:"heart_rate_avg"`
+	HeartRateMax     *float64 `json:"heart_rate_max"`
+}
+
+func parseHealthKitJSON(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	var workouts []healthKitWorkout
+	if data[0] == '[' {
+		err = json.Unmarshal(data, &workouts)
+	} else {
+		var export struct {
+			Workouts []healthKitWorkout `json:"workouts"`
+		}
+		err = json.Unmarshal(data, &export)
+		workouts = export.Workouts
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	sessions := make([]*models.ImportedSession, 0, len(workouts))
+	for i, w := range workouts {
+		if w.ActivityType == "" {
+			return nil, fmt.Errorf("%w: workout %d: missing activity_type", ErrInvalidFile, i+1)
+		}
+		startedAt, err := parseAppleTime(w.StartDate, opts.Location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: workout %d: %v", ErrInvalidFile, i+1, err)
+		}
+
+		var duration time.Duration
+		if w.DurationSeconds != nil {
+			duration = time.Duration(*w.DurationSeconds * float64(time.Second))
+		} else if endedAt, err := parseAppleTime(w.EndDate, opts.Location); err == nil {
+			duration = endedAt.Sub(startedAt)
+		}
+
+		sessions = append(sessions, cardioSession(appleActivityName(w.ActivityType), startedAt, duration, w.DistanceMeters, w.EnergyBurnedKcal, w.HeartRateAvg, w.HeartRateMax))
+	}
+
+	return sessions, nil
+}
+
+// appleActivityName turns "HKWorkoutActivityTypeFunctionalStrengthTraining" or
+// "functional_strength_training" into "Functional Strength Training"
+func appleActivityName(activityType string) string {
+	name := strings.TrimPrefix(activityType, "HKWorkoutActivityType")
+	if mapped, ok := appleWorkoutNames[name]; ok {
+		return mapped
+	}
+
+	var words []string
+	var word []rune
+	for _, r := range name {
+		if r == '_' || r == ' ' || r == '-' || (unicode.IsUpper(r) && len(word) > 0) {
+			if len(word) > 0 {
+				words = append(words, string(word))
+			}
+			word = word[:0]
+			if !unicode.IsLetter(r) {
+				continue
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, string(word))
+	}
+
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + strings.ToLower(w[1:])
+	}
+	joined := strings.Join(words, " ")
+	if joined == "" {
+		return "Workout"
+	}
+	return joined
+}
+
+// parseAppleTime parses "2026-05-06 07:30:00 +0200" as written by the Health export,
+// RFC 3339, or a local time without offset read in loc
+func parseAppleTime(raw string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02 15:04:05 -0700", raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02 15:04:05", raw, loc); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", raw)
+}
+
+func appleDuration(value float64, unit string) time.Duration {
+	switch unit {
+	case "s":
+		return time.Duration(value * float64(time.Second))
+	case "hr", "h":
+		return time.Duration(value * float64(time.Hour))
+	}
+	return time.Duration(value * float64(time.Minute))
+}
+
+func appleDistance(raw string, unit string) *float64 {
+	v := parseOptionalFloat(raw)
+	if v == nil {
+		return nil
+	}
+	switch unit {
+	case "km":
+		*v *= 1000
+	case "mi":
+		*v *= milesToM
+	case "yd":
+		*v *= 0.9144
+	case "ft":
+		*v *= 0.3048
+	}
+	return v
+}
+
+func appleEnergy(raw string, unit string) *float64 {
+	v := parseOptionalFloat(raw)
+	if v != nil && unit == "kJ" {
+		*v /= kilojoulesPerKcal
+	}
+	return v
+}
+
+func parseOptionalFloat(raw string) *float64 {
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil
+	}
+	return &v
+}
@@ -0,0 +1,124 @@
+package importer
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseAppleHealth_XML(t *testing.T) {
+	data := `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE HealthData [
+<!ELEMENT HealthData (ExportDate,Me,(Record|Workout)*)>
+]>
+<HealthData locale="en_US">
+ <ExportDate value="2026-05-10 09:00:00 +0200"/>
+ <Record type="HKQuantityTypeIdentifierHeartRate" unit="count/min" value="61" startDate="2026-05-06 06:00:00 +0200" endDate="2026-05-06 06:00:00 +0200"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeRunning" duration="30.5" durationUnit="min" sourceName="Apple Watch" startDate="2026-05-06 07:30:00 +0200" endDate="2026-05-06 08:00:30 +0200">
+  <MetadataEntry key="HKIndoorWorkout" value="0"/>
+  <WorkoutStatistics type="HKQuantityTypeIdentifierHeartRate" average="145.4" minimum="98" maximum="172" unit="count/min"/>
+  <WorkoutStatistics type="HKQuantityTypeIdentifierActiveEnergyBurned" sum="1339" unit="kJ"/>
+  <WorkoutStatistics type="HKQuantityTypeIdentifierDistanceWalkingRunning" sum="3.1" unit="mi"/>
+ </Workout>
+ <Workout workoutActivityType="HKWorkoutActivityTypeTraditionalStrengthTraining" duration="45" durationUnit="min" totalEnergyBurned="210" totalEnergyBurnedUnit="kcal" startDate="2026-05-07 18:00:00 +0200" endDate="2026-05-07 18:45:00 +0200"/>
+</HealthData>`
+
+	sessions, err := ParseAppleHealth(strings.NewReader(data), Options{Location: time.UTC})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("Expected 2 workouts, got %d", len(sessions))
+	}
+
+	run := sessions[0]
+	if run.Name != "Running" || !run.StartedAt.Equal(time.Date(2026, 5, 6, 5, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected Running at 05:30 UTC, got %q at %v", run.Name, run.StartedAt)
+	}
+	if run.Duration != 30*time.Minute+30*time.Second || *run.Sets[0].DurationSec != 1830 {
+		t.Errorf("Expected 30m30s, got %v (%v s)", run.Duration, *run.Sets[0].DurationSec)
+	}
+	if *run.HeartRateAvg != 145 || *run.HeartRateMax != 172 || *run.CaloriesBurned != 320 {
+		t.Errorf("Expected 145/172 bpm and 320 kcal, got %v/%v and %v", *run.HeartRateAvg, *run.HeartRateMax, *run.CaloriesBurned)
+	}
+	if math.Abs(*run.Sets[0].DistanceM-4988.97) > 0.01 {
+		t.Errorf("Expected 3.1 mi in meters, got %v", *run.Sets[0].DistanceM)
+	}
+
+	strength := sessions[1]
+	if strength.Name != "Strength Training" || *strength.CaloriesBurned != 210 || strength.HeartRateAvg != nil {
+		t.Errorf("Expected strength training with 210 kcal and no heart rate, got %+v", strength)
+	}
+	if strength.Sets[0].DistanceM != nil {
+		t.Errorf("Expected no distance, got %v", *strength.Sets[0].DistanceM)
+	}
+}
+
+func TestParseAppleHealth_JSON(t *testing.T) {
+	data := `{"workouts": [{
+		"activity_type": "outdoor_cycling",
+		"start_date": "2026-05-08T17:00:00Z",
+		"end_date": "2026-05-08T18:00:00Z",
+		"total_distance_meters": 25000,
+		"total_energy_burned_kcal": 610.6,
+		"heart_rate_avg": 132,
+		"heart_rate_max": 168
+	}]}`
+
+	sessions, err := ParseAppleHealth(strings.NewReader(data), Options{Location: time.UTC})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 {
+		t.Fatalf("Expected 1 workout, got %d", len(sessions))
+	}
+	ride := sessions[0]
+	if ride.Name != "Outdoor Cycling" || ride.Duration != time.Hour || *ride.CaloriesBurned != 611 {
+		t.Errorf("Expected a 1h Outdoor Cycling of 611 kcal, got %q %v %v", ride.Name, ride.Duration, *ride.CaloriesBurned)
+	}
+	if *ride.Sets[0].DistanceM != 25000 || *ride.Sets[0].DurationSec != 3600 {
+		t.Errorf("Expected 25 km over 3600 s, got %v and %v", *ride.Sets[0].DistanceM, *ride.Sets[0].DurationSec)
+	}
+}
+
+func TestParseAppleHealth_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", "  \n"},
+		{"not health data", `<Workouts><Workout/></Workouts>`},
+		{"csv", "Date,Exercise Name\n2026-05-04 18:00:00,Squat\n"},
+		{"bad workout date", `<HealthData><Workout workoutActivityType="HKWorkoutActivityTypeRunning" startDate="yesterday" endDate="today"/></HealthData>`},
+		{"json without activity", `[{"start_date": "2026-05-08T17:00:00Z"}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseAppleHealth(strings.NewReader(tt.data), Options{Location: time.UTC})
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Expected ErrInvalidFile, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAppleActivityName(t *testing.T) {
+	tests := map[string]string{
+		"HKWorkoutActivityTypeRunning":                       "Running",
+		"HKWorkoutActivityTypeFunctionalStrengthTraining":    "Functional Strength Training",
+		"HKWorkoutActivityTypeHighIntensityIntervalTraining": "HIIT",
+		"stair_climbing": "Stair Climbing",
+		"":               "Workout",
+	}
+
+	for input, want := range tests {
+		if got := appleActivityName(input); got != want {
+			t.Errorf("appleActivityName(%q): expected %q, got %q", input, want, got)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// newCSVReader reads an export whose delimiter may be a comma or a semicolon,
+// depending on the app version and the phone's locale
+func newCSVReader(data []byte) *csv.Reader {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	if firstLine, _, _ := bytes.Cut(data, []byte("\n")); bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+	return reader
+}
+
+// readHeader reads the header row and checks the required columns are present
+func readHeader(reader *csv.Reader, required ...string) (map[string]int, error) {
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidFile)
+	}
+
+	cols := make(map[string]int)
+	for i, name := range header {
+		cols[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, name := range required {
+		if _, ok := cols[name]; !ok {
+			return nil, fmt.Errorf("%w: missing %q column", ErrInvalidFile, name)
+		}
+	}
+
+	return cols, nil
+}
+
+// eachRecord calls fn for every data row, wrapping errors with the line number
+func eachRecord(reader *csv.Reader, cols map[string]int, fn func(row columnReader) error) error {
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+
+		if err := fn(columnReader{record: record, cols: cols}); err != nil {
+			return fmt.Errorf("%w: line %d: %v", ErrInvalidFile, line, err)
+		}
+	}
+}
+
+// columnReader reads fields of a CSV record by normalized header name
+type columnReader struct {
+	record []string
+	cols   map[string]int
+}
+
+func (r columnReader) has(name string) bool {
+	_, ok := r.cols[name]
+	return ok
+}
+
+func (r columnReader) get(name string) string {
+	if i, ok := r.cols[name]; ok && i < len(r.record) {
+		return strings.TrimSpace(r.record[i])
+	}
+	return ""
+}
+
+func (r columnReader) getOr(name string, fallback string) string {
+	if v := r.get(name); v != "" {
+		return v
+	}
+	return fallback
+}
+
+func (r columnReader) float(name string, factor float64) (*float64, error) {
+	raw := r.get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(strings.Replace(raw, ",", ".", 1), 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s %q", name, raw)
+	}
+	v *= factor
+	return &v, nil
+}
+
+func (r columnReader) int(name string) (*int, error) {
+	v, err := r.float(name, 1)
+	if err != nil || v == nil {
+		return nil, err
+	}
+	n := int(math.Round(*v))
+	return &n, nil
+}
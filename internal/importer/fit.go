@@ -0,0 +1,334 @@
+package importer
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// FIT global message numbers and field numbers read by ParseFIT
+// See the FIT SDK profile (Profile.xlsx) for the full list.
+const (
+	fitMesgSession = 18
+	fitMesgLap     = 19
+
+	fitFieldStartTime        = 2
+	fitFieldSport            = 5 // session only
+	fitFieldTotalElapsedTime = 7 // ms
+	fitFieldTotalTimerTime   = 8 // ms, excludes pauses
+	fitFieldTotalDistance    = 9 // cm
+	fitFieldTotalCalories    = 11
+	fitFieldLapAvgHeartRate  = 15
+	fitFieldLapMaxHeartRate  = 16
+	fitFieldSessAvgHeartRate = 16
+	fitFieldSessMaxHeartRate = 17
+)
+
+// fitEpoch is the FIT timestamp origin, 1989-12-31 00:00:00 UTC, in Unix seconds
+const fitEpoch = 631065600
+
+// fitSports names the FIT sport enum values of common cardio activities
+var fitSports = map[uint32]string{
+	1:  "Running",
+	2:  "Cycling",
+	5:  "Swimming",
+	11: "Walking",
+	17: "Hiking",
+}
+
+type fitFieldDef struct {
+	num  byte
+	size int
+}
+
+type fitDefinition struct {
+	global    uint16
+	byteOrder binary.ByteOrder
+	fields    []fitFieldDef
+	devSize   int // Developer fields are skipped
+}
+
+// fitMessage holds the unsigned integer fields of a data message that were not "invalid"
+type fitMessage map[byte]uint32
+
+// ParseFIT parses a Garmin .FIT activity file into cardio sessions, one per session
+// message (multisport files have several), with their laps. Sessions are read from the
+// session and lap summary messages the device writes at the end of the activity; the
+// per-second record messages are skipped. Timestamps in FIT files are UTC.
+func ParseFIT(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	messages, err := decodeFIT(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	var sessionMsgs, lapMsgs []fitMessage
+	for _, m := range messages {
+		switch m.global {
+		case fitMesgSession:
+			sessionMsgs = append(sessionMsgs, m.fields)
+		case fitMesgLap:
+			lapMsgs = append(lapMsgs, m.fields)
+		}
+	}
+	if len(sessionMsgs) == 0 && len(lapMsgs) == 0 {
+		return nil, fmt.Errorf("%w: no activity found", ErrInvalidFile)
+	}
+
+	laps := make([]*models.ImportedLap, 0, len(lapMsgs))
+	for _, m := range lapMsgs {
+		start, ok := m.time(fitFieldStartTime)
+		if !ok {
+			continue
+		}
+		laps = append(laps, &models.ImportedLap{
+			StartedAt:      start,
+			Duration:       m.duration(),
+			DistanceM:      m.scaled(fitFieldTotalDistance, 100),
+			CaloriesBurned: m.int(fitFieldTotalCalories),
+			HeartRateAvg:   m.int(fitFieldLapAvgHeartRate),
+			HeartRateMax:   m.int(fitFieldLapMaxHeartRate),
+		})
+	}
+
+	// Devices that only write laps still describe one activity
+	if len(sessionMsgs) == 0 {
+		if len(laps) == 0 {
+			return nil, fmt.Errorf("%w: no activity found", ErrInvalidFile)
+		}
+		return []*models.ImportedSession{lapsSession("Cardio", laps[0].StartedAt, laps)}, nil
+	}
+
+	sessions := make([]*models.ImportedSession, 0, len(sessionMsgs))
+	for i, m := range sessionMsgs {
+		start, ok := m.time(fitFieldStartTime)
+		if !ok {
+			return nil, fmt.Errorf("%w: session %d: missing start time", ErrInvalidFile, i+1)
+		}
+		elapsed := time.Duration(0)
+		if v, ok := m[fitFieldTotalElapsedTime]; ok {
+			elapsed = time.Duration(v) * time.Millisecond
+		}
+
+		name := "Cardio"
+		if sport, ok := m[fitFieldSport]; ok && fitSports[sport] != "" {
+			name = fitSports[sport]
+		}
+
+		session := cardioSession(
+			name,
+			start,
+			m.duration(),
+			m.scaled(fitFieldTotalDistance, 100),
+			m.float(fitFieldTotalCalories),
+			m.float(fitFieldSessAvgHeartRate),
+			m.float(fitFieldSessMaxHeartRate),
+		)
+		end := start.Add(elapsed)
+		for _, lap := range laps {
+			if len(sessionMsgs) == 1 || (!lap.StartedAt.Before(start) && lap.StartedAt.Before(end)) {
+				session.Laps = append(session.Laps, lap)
+			}
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+type fitDataMessage struct {
+	global uint16
+	fields fitMessage
+}
+
+// decodeFIT walks the records of a FIT file and returns its data messages.
+// Only unsigned integer fields of 1, 2 or 4 bytes are kept, which covers every field
+// ParseFIT reads; the file CRC is not checked.
+func decodeFIT(data []byte) ([]fitDataMessage, error) {
+	if len(data) < 12 {
+		return nil, fmt.Errorf("file too short")
+	}
+	headerSize := int(data[0])
+	if headerSize < 12 || len(data) < headerSize || string(data[8:12]) != ".FIT" {
+		return nil, fmt.Errorf("not a FIT file")
+	}
+	end := headerSize + int(binary.LittleEndian.Uint32(data[4:8]))
+	if end > len(data) {
+		return nil, fmt.Errorf("truncated file")
+	}
+	records := data[headerSize:end]
+
+	definitions := make(map[byte]*fitDefinition)
+	var messages []fitDataMessage
+	pos := 0
+	need := func(n int) error {
+		if pos+n > len(records) {
+			return fmt.Errorf("truncated record at byte %d", headerSize+pos)
+		}
+		return nil
+	}
+
+	for pos < len(records) {
+		header := records[pos]
+		pos++
+
+		var local byte
+		switch {
+		case header&0x80 != 0: // Compressed timestamp header, always a data message
+			local = (header >> 5) & 0x03
+		case header&0x40 != 0: // Definition message
+			local = header & 0x0F
+			if err := need(5); err != nil {
+				return nil, err
+			}
+			def := &fitDefinition{byteOrder: binary.LittleEndian}
+			if records[pos+1] == 1 {
+				def.byteOrder = binary.BigEndian
+			}
+			def.global = def.byteOrder.Uint16(records[pos+2 : pos+4])
+			count := int(records[pos+4])
+			pos += 5
+
+			if err := need(3 * count); err != nil {
+				return nil, err
+			}
+			for i := 0; i < count; i++ {
+				def.fields = append(def.fields, fitFieldDef{num: records[pos], size: int(records[pos+1])})
+				pos += 3
+			}
+
+			if header&0x20 != 0 {
+				if err := need(1); err != nil {
+					return nil, err
+				}
+				devCount := int(records[pos])
+				pos++
+				if err := need(3 * devCount); err != nil {
+					return nil, err
+				}
+				for i := 0; i < devCount; i++ {
+					def.devSize += int(records[pos+1])
+					pos += 3
+				}
+			}
+
+			definitions[local] = def
+			continue
+		default:
+			local = header & 0x0F
+		}
+
+		def, ok := definitions[local]
+		if !ok {
+			return nil, fmt.Errorf("data message without definition at byte %d", headerSize+pos-1)
+		}
+
+		fields := make(fitMessage)
+		for _, f := range def.fields {
+			if err := need(f.size); err != nil {
+				return nil, err
+			}
+			raw := records[pos : pos+f.size]
+			pos += f.size
+
+			switch f.size {
+			case 1:
+				if raw[0] != 0xFF {
+					fields[f.num] = uint32(raw[0])
+				}
+			case 2:
+				if v := def.byteOrder.Uint16(raw); v != 0xFFFF {
+					fields[f.num] = uint32(v)
+				}
+			case 4:
+				if v := def.byteOrder.Uint32(raw); v != 0xFFFFFFFF {
+					fields[f.num] = v
+				}
+			}
+		}
+		if err := need(def.devSize); err != nil {
+			return nil, err
+		}
+		pos += def.devSize
+
+		messages = append(messages, fitDataMessage{global: def.global, fields: fields})
+	}
+
+	return messages, nil
+}
+
+func (m fitMessage) time(num byte) (time.Time, bool) {
+	v, ok := m[num]
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(int64(v)+fitEpoch, 0).UTC(), true
+}
+
+// duration is the timer time (moving time), falling back to elapsed time
+func (m fitMessage) duration() time.Duration {
+	if v, ok := m[fitFieldTotalTimerTime]; ok {
+		return time.Duration(v) * time.Millisecond
+	}
+	return time.Duration(m[fitFieldTotalElapsedTime]) * time.Millisecond
+}
+
+func (m fitMessage) scaled(num byte, scale float64) *float64 {
+	v, ok := m[num]
+	if !ok || v == 0 {
+		return nil
+	}
+	f := float64(v) / scale
+	return &f
+}
+
+func (m fitMessage) float(num byte) *float64 {
+	return m.scaled(num, 1)
+}
+
+func (m fitMessage) int(num byte) *int {
+	v, ok := m[num]
+	if !ok || v == 0 {
+		return nil
+	}
+	n := int(v)
+	return &n
+}
+
+// lapsSession builds a cardio session from its laps alone, summing their totals
+func lapsSession(name string, startedAt time.Time, laps []*models.ImportedLap) *models.ImportedSession {
+	var duration time.Duration
+	var distance, kcal, heartRateMax, weightedHR, hrTime float64
+	for _, lap := range laps {
+		duration += lap.Duration
+		if lap.DistanceM != nil {
+			distance += *lap.DistanceM
+		}
+		if lap.CaloriesBurned != nil {
+			kcal += float64(*lap.CaloriesBurned)
+		}
+		if lap.HeartRateAvg != nil {
+			weightedHR += float64(*lap.HeartRateAvg) * lap.Duration.Seconds()
+			hrTime += lap.Duration.Seconds()
+		}
+		if lap.HeartRateMax != nil && float64(*lap.HeartRateMax) > heartRateMax {
+			heartRateMax = float64(*lap.HeartRateMax)
+		}
+	}
+
+	var heartRateAvg float64
+	if hrTime > 0 {
+		heartRateAvg = weightedHR / hrTime
+	}
+
+	session := cardioSession(name, startedAt, duration, &distance, &kcal, &heartRateAvg, &heartRateMax)
+	session.Laps = laps
+	return session
+}
@@ -0,0 +1,207 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// hevyTimeLayouts are the timestamp formats seen in Hevy exports
+var hevyTimeLayouts = []string{
+	"2 Jan 2006, 15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+}
+
+// ParseHevy parses a Hevy export, either the app's CSV export or the JSON returned
+// by Hevy's workouts API ({"workouts": [...]} or a bare array).
+// The CSV has one row per set with columns title, start_time, end_time, description,
+// exercise_title, exercise_notes, set_index, set_type, weight_kg (or weight_lbs), reps,
+// distance_km (or distance_miles), duration_seconds and rpe. Timestamps without an
+// offset are read in opts.Location.
+func ParseHevy(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	trimmed := bytes.TrimLeft(data, " \t\r\n\ufeff")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		return parseHevyJSON(trimmed, opts)
+	}
+	return parseHevyCSV(data, opts)
+}
+
+func parseHevyCSV(data []byte, opts Options) ([]*models.ImportedSession, error) {
+	reader := newCSVReader(data)
+	cols, err := readHeader(reader, "start_time", "exercise_title")
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*models.ImportedSession
+	byStart := make(map[string]*models.ImportedSession)
+	noted := make(map[string]bool)
+	err = eachRecord(reader, cols, func(row columnReader) error {
+		key := row.get("start_time") + "|" + row.get("title")
+		session, ok := byStart[key]
+		if !ok {
+			startedAt, err := parseHevyTime(row.get("start_time"), opts.Location)
+			if err != nil {
+				return err
+			}
+			session = &models.ImportedSession{
+				StartedAt: startedAt,
+				Name:      row.get("title"),
+				Notes:     row.get("description"),
+			}
+			if endedAt, err := parseHevyTime(row.get("end_time"), opts.Location); err == nil && endedAt.After(startedAt) {
+				session.Duration = endedAt.Sub(startedAt)
+			}
+			byStart[key] = session
+			sessions = append(sessions, session)
+		}
+
+		set := &models.ImportedSet{ExerciseName: row.get("exercise_title")}
+		if set.ExerciseName == "" {
+			return fmt.Errorf("missing exercise name")
+		}
+		// Exercise notes repeat on every set; keep them on the first one only
+		if exerciseKey := key + "|" + set.ExerciseName; !noted[exerciseKey] {
+			noted[exerciseKey] = true
+			set.Notes = row.get("exercise_notes")
+		}
+
+		var err error
+		if row.has("weight_lbs") {
+			set.WeightKg, err = row.float("weight_lbs", poundsToKg)
+		} else {
+			set.WeightKg, err = row.float("weight_kg", 1)
+		}
+		if err != nil {
+			return err
+		}
+		if row.has("distance_miles") {
+			set.DistanceM, err = row.float("distance_miles", milesToM)
+		} else {
+			set.DistanceM, err = row.float("distance_km", 1000)
+		}
+		if err != nil {
+			return err
+		}
+		if set.Reps, err = row.int("reps"); err != nil {
+			return err
+		}
+		if set.DurationSec, err = row.int("duration_seconds"); err != nil {
+			return err
+		}
+		rpe, err := row.float("rpe", 1)
+		if err != nil {
+			return err
+		}
+
+		finishSet(set, rpe)
+		session.Sets = append(session.Sets, set)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+type hevyWorkout struct {
+	Title       string         `json:"title"`
+	Description string         `json:"description"`
+	StartTime   string         `json:"start_time"`
+	EndTime     string         `json:"end_time"`
+	Exercises   []hevyExercise `json:"exercises"`
+}
+
+type hevyExercise struct {
+	Title string    `json:"title"`
+	Notes string    `json:"notes"`
+	Sets  []hevySet `json:"sets"`
+}
+
+type hevySet struct {
+	WeightKg        *float64 `json:"weight_kg"`
+	Reps            *int     `json:"reps"`
+	DistanceMeters  *float64 `json:"distance_meters"`
+	DurationSeconds *int     `json:"duration_seconds"`
+	RPE             *float64 `json:"rpe"`
+}
+
+func parseHevyJSON(data []byte, opts Options) ([]*models.ImportedSession, error) {
+	var workouts []hevyWorkout
+	if data[0] == '[' {
+		if err := json.Unmarshal(data, &workouts); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+	} else {
+		var export struct {
+			Workouts []hevyWorkout `json:"workouts"`
+		}
+		if err := json.Unmarshal(data, &export); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		workouts = export.Workouts
+	}
+
+	sessions := make([]*models.ImportedSession, 0, len(workouts))
+	for i, w := range workouts {
+		startedAt, err := parseHevyTime(w.StartTime, opts.Location)
+		if err != nil {
+			return nil, fmt.Errorf("%w: workout %d: %v", ErrInvalidFile, i+1, err)
+		}
+		session := &models.ImportedSession{
+			StartedAt: startedAt,
+			Name:      w.Title,
+			Notes:     w.Description,
+		}
+		if endedAt, err := parseHevyTime(w.EndTime, opts.Location); err == nil && endedAt.After(startedAt) {
+			session.Duration = endedAt.Sub(startedAt)
+		}
+
+		for _, e := range w.Exercises {
+			if e.Title == "" {
+				return nil, fmt.Errorf("%w: workout %d: missing exercise name", ErrInvalidFile, i+1)
+			}
+			for j, s := range e.Sets {
+				set := &models.ImportedSet{
+					ExerciseName: e.Title,
+					WeightKg:     s.WeightKg,
+					Reps:         s.Reps,
+					DistanceM:    s.DistanceMeters,
+					DurationSec:  s.DurationSeconds,
+				}
+				if j == 0 {
+					set.Notes = e.Notes
+				}
+				finishSet(set, s.RPE)
+				session.Sets = append(session.Sets, set)
+			}
+		}
+
+		sessions = append(sessions, session)
+	}
+
+	return sessions, nil
+}
+
+func parseHevyTime(raw string, loc *time.Location) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return t, nil
+	}
+	for _, layout := range hevyTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q", raw)
+}
@@ -0,0 +1,157 @@
+// Package importer parses workout history exported from other fitness apps
+package importer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+var (
+	// ErrInvalidFile is returned when an export cannot be parsed
+	ErrInvalidFile = errors.New("invalid import file")
+	// ErrUnsupportedSource is returned for apps without a parser
+	ErrUnsupportedSource = errors.New("unsupported import source")
+)
+
+const (
+	poundsToKg = 0.45359237
+	milesToM   = 1609.344
+)
+
+// Options control how an export is interpreted
+type Options struct {
+	Location   *time.Location // Timezone for timestamps without an offset
+	WeightUnit string         // "kg" or "lbs", for exports that do not say
+}
+
+// Parser reads one app's export into sessions, one per workout
+type Parser interface {
+	Parse(r io.Reader, opts Options) ([]*models.ImportedSession, error)
+}
+
+// ParserFunc adapts an ordinary function to the Parser interface
+type ParserFunc func(r io.Reader, opts Options) ([]*models.ImportedSession, error)
+
+// Parse calls f(r, opts)
+func (f ParserFunc) Parse(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	return f(r, opts)
+}
+
+var parsers = map[string]Parser{
+	models.ImportSourceStrong:      ParserFunc(ParseStrong),
+	models.ImportSourceHevy:        ParserFunc(ParseHevy),
+	models.ImportSourceAppleHealth: ParserFunc(ParseAppleHealth),
+	models.ImportSourceFIT:         ParserFunc(ParseFIT),
+	models.ImportSourceTCX:         ParserFunc(ParseTCX),
+}
+
+// ForSource returns the parser for an import source such as "strong", "hevy", "apple-health", "fit" or "tcx"
+func ForSource(source string) (Parser, error) {
+	parser, ok := parsers[source]
+	if !ok {
+		return nil, ErrUnsupportedSource
+	}
+	return parser, nil
+}
+
+// DetectActivityFormat tells a FIT file from a TCX file by its first bytes (at least 12;
+// a few hundred for TCX) and returns models.ImportSourceFIT or models.ImportSourceTCX
+func DetectActivityFormat(head []byte) (string, error) {
+	if len(head) >= 12 && string(head[8:12]) == ".FIT" {
+		return models.ImportSourceFIT, nil
+	}
+	if bytes.Contains(head, []byte("<TrainingCenterDatabase")) {
+		return models.ImportSourceTCX, nil
+	}
+	return "", fmt.Errorf("%w: expected a .FIT or .TCX activity file", ErrInvalidFile)
+}
+
+// equipmentSuffix matches the "(Barbell)" style suffix Strong and Hevy append to names
+var equipmentSuffix = regexp.MustCompile(`^(.+?)\s*\(([^()]+)\)$`)
+
+// ExerciseNameCandidates lists library names an exported exercise name may correspond to,
+// most specific first: "Bench Press (Barbell)" yields itself, "Barbell Bench Press" and
+// "Bench Press".
+func ExerciseNameCandidates(name string) []string {
+	name = strings.Join(strings.Fields(name), " ")
+	candidates := []string{name}
+
+	if m := equipmentSuffix.FindStringSubmatch(name); m != nil {
+		candidates = append(candidates, m[2]+" "+m[1], m[1])
+	}
+
+	return candidates
+}
+
+// finishSet applies conventions shared by the exporting apps: zero means "not recorded",
+// and RPE, which apps log in half steps, is stored as a whole number from 1 to 10.
+func finishSet(set *models.ImportedSet, rpe *float64) {
+	if rpe != nil && *rpe >= 1 && *rpe <= 10 {
+		rounded := int(math.Round(*rpe))
+		set.RPE = &rounded
+	}
+
+	if set.WeightKg != nil && *set.WeightKg == 0 {
+		set.WeightKg = nil
+	}
+	if set.DistanceM != nil && *set.DistanceM == 0 {
+		set.DistanceM = nil
+	}
+	if set.Reps != nil && *set.Reps == 0 {
+		set.Reps = nil
+	}
+	if set.DurationSec != nil && *set.DurationSec == 0 {
+		set.DurationSec = nil
+	}
+}
+
+// cardioSession builds a cardio session holding a single set for the activity, which
+// links it to the exercise of the same name; totals that apply to the whole session
+// (calories, heart rate) go on the session
+func cardioSession(name string, startedAt time.Time, duration time.Duration, distanceM, kcal, heartRateAvg, heartRateMax *float64) *models.ImportedSession {
+	durationSec := int(math.Round(duration.Seconds()))
+
+	set := &models.ImportedSet{
+		ExerciseName: name,
+		DistanceM:    distanceM,
+		DurationSec:  &durationSec,
+	}
+	finishSet(set, nil)
+
+	return &models.ImportedSession{
+		StartedAt:      startedAt,
+		Name:           name,
+		Type:           models.SessionTypeCardio,
+		Duration:       duration,
+		CaloriesBurned: roundOptional(kcal),
+		HeartRateAvg:   roundOptional(heartRateAvg),
+		HeartRateMax:   roundOptional(heartRateMax),
+		Sets:           []*models.ImportedSet{set},
+	}
+}
+
+// roundOptional rounds a positive value to a whole number; anything else is "not recorded"
+func roundOptional(v *float64) *int {
+	if v == nil || *v <= 0 {
+		return nil
+	}
+	n := int(math.Round(*v))
+	return &n
+}
+
+// weightFactor converts weights in the given unit to kilograms
+func weightFactor(unit string) float64 {
+	switch strings.ToLower(unit) {
+	case "lbs", "lb":
+		return poundsToKg
+	}
+	return 1
+}
@@ -0,0 +1,106 @@
+package importer
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExerciseNameCandidates(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{"Bench Press (Barbell)", []string{"Bench Press (Barbell)", "Barbell Bench Press", "Bench Press"}},
+		{"  Pull  Up ", []string{"Pull Up"}},
+		{"Running", []string{"Running"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExerciseNameCandidates(tt.name); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseHevy_CSVInPounds(t *testing.T) {
+	csv := "title,start_time,end_time,description,exercise_title,superset_id,exercise_notes,set_index,set_type,weight_lbs,reps,distance_miles,duration_seconds,rpe\n" +
+		"Push,2026-05-04 18:00:00,2026-05-04 19:00:00,Quick one,Bench Press (Barbell),,Pause reps,0,normal,225,5,,,8.5\n" +
+		"Push,2026-05-04 18:00:00,2026-05-04 19:00:00,Quick one,Bench Press (Barbell),,Pause reps,1,normal,225,4,,,\n" +
+		"Push,2026-05-04 18:00:00,2026-05-04 19:00:00,Quick one,Treadmill,,,0,normal,,,1,600,\n"
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	sessions, err := ParseHevy(strings.NewReader(csv), Options{Location: madrid})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 || len(sessions[0].Sets) != 3 {
+		t.Fatalf("Expected 1 session with 3 sets, got %d sessions", len(sessions))
+	}
+
+	session := sessions[0]
+	if !session.StartedAt.Equal(time.Date(2026, 5, 4, 16, 0, 0, 0, time.UTC)) || session.Notes != "Quick one" {
+		t.Errorf("Expected Madrid start and description as notes, got %v %q", session.StartedAt, session.Notes)
+	}
+	if math.Abs(*session.Sets[0].WeightKg-102.06) > 0.01 || *session.Sets[0].RPE != 9 {
+		t.Errorf("Expected ~102.06 kg at RPE 9, got %v at %v", *session.Sets[0].WeightKg, session.Sets[0].RPE)
+	}
+	if session.Sets[0].Notes != "Pause reps" || session.Sets[1].Notes != "" {
+		t.Errorf("Expected exercise notes on the first set only, got %q and %q", session.Sets[0].Notes, session.Sets[1].Notes)
+	}
+	if math.Abs(*session.Sets[2].DistanceM-1609.344) > 0.001 || *session.Sets[2].DurationSec != 600 {
+		t.Errorf("Expected 1 mile over 600 s, got %v and %v", *session.Sets[2].DistanceM, *session.Sets[2].DurationSec)
+	}
+}
+
+func TestParseHevy_JSON(t *testing.T) {
+	data := `{"workouts": [{
+		"title": "Morning Run",
+		"start_time": "2026-05-06T07:30:00+02:00",
+		"end_time": "2026-05-06T08:00:00+02:00",
+		"exercises": [{"title": "Running", "notes": "Easy pace", "sets": [
+			{"type": "normal", "weight_kg": null, "reps": null, "distance_meters": 5000, "duration_seconds": 1800, "rpe": null}
+		]}]
+	}]}`
+
+	sessions, err := ParseHevy(strings.NewReader(data), Options{Location: time.UTC})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != 1 || sessions[0].Duration != 30*time.Minute {
+		t.Fatalf("Expected 1 session of 30m, got %+v", sessions)
+	}
+	set := sessions[0].Sets[0]
+	if set.ExerciseName != "Running" || set.Notes != "Easy pace" || *set.DistanceM != 5000 || set.WeightKg != nil {
+		t.Errorf("Expected a 5 km run without weight, got %+v", set)
+	}
+}
+
+func TestParseHevy_InvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"missing exercise column", "title,start_time\nPush,2026-05-04 18:00:00\n"},
+		{"bad date", "start_time,exercise_title\nyesterday,Squat\n"},
+		{"broken json", `{"workouts": [`},
+		{"json without exercise name", `[{"start_time": "2026-05-06T07:30:00Z", "exercises": [{"sets": []}]}]`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseHevy(strings.NewReader(tt.data), Options{Location: time.UTC})
+			if !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("Expected ErrInvalidFile, got %v", err)
+			}
+		})
+	}
+}
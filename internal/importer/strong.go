@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ParseStrong parses a Strong app CSV export.
+// Strong exports one row per set with columns such as Date, Workout Name, Duration,
+// Exercise Name, Set Order, Weight, Weight Unit, Reps, RPE, Distance, Distance Unit,
+// Seconds, Notes and Workout Notes. Dates carry no offset and are read in
+// opts.Location; weights without a unit column are assumed to be in opts.WeightUnit.
+func ParseStrong(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	reader := newCSVReader(data)
+	cols, err := readHeader(reader, "date", "exercise name")
+	if err != nil {
+		return nil, err
+	}
+
+	var sessions []*models.ImportedSession
+	byStart := make(map[string]*models.ImportedSession)
+	err = eachRecord(reader, cols, func(row columnReader) error {
+		key := row.get("date") + "|" + row.get("workout name")
+		session, ok := byStart[key]
+		if !ok {
+			startedAt, err := time.ParseInLocation("2006-01-02 15:04:05", row.get("date"), opts.Location)
+			if err != nil {
+				return fmt.Errorf("invalid date %q", row.get("date"))
+			}
+			session = &models.ImportedSession{
+				StartedAt: startedAt,
+				Name:      row.get("workout name"),
+				Duration:  parseStrongDuration(row.get("duration")),
+				Notes:     row.get("workout notes"),
+			}
+			byStart[key] = session
+			sessions = append(sessions, session)
+		}
+
+		set, err := parseStrongSet(row, opts.WeightUnit)
+		if err != nil {
+			return err
+		}
+		if set != nil {
+			session.Sets = append(session.Sets, set)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func parseStrongSet(row columnReader, defaultWeightUnit string) (*models.ImportedSet, error) {
+	// Strong writes rest timers as pseudo-sets with "Rest Timer" in Set Order
+	if strings.EqualFold(row.get("set order"), "rest timer") {
+		return nil, nil
+	}
+
+	set := &models.ImportedSet{
+		ExerciseName: row.get("exercise name"),
+		Notes:        row.get("notes"),
+	}
+	if set.ExerciseName == "" {
+		return nil, fmt.Errorf("missing exercise name")
+	}
+
+	distanceFactor := 1000.0 // Strong's default distance unit is km
+	switch strings.ToLower(row.get("distance unit")) {
+	case "mi":
+		distanceFactor = milesToM
+	case "m":
+		distanceFactor = 1
+	}
+
+	var err error
+	if set.WeightKg, err = row.float("weight", weightFactor(row.getOr("weight unit", defaultWeightUnit))); err != nil {
+		return nil, err
+	}
+	if set.DistanceM, err = row.float("distance", distanceFactor); err != nil {
+		return nil, err
+	}
+	if set.Reps, err = row.int("reps"); err != nil {
+		return nil, err
+	}
+	if set.DurationSec, err = row.int("seconds"); err != nil {
+		return nil, err
+	}
+	rpe, err := row.float("rpe", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	finishSet(set, rpe)
+	return set, nil
+}
+
+// parseStrongDuration parses durations such as "1h 5m", "45m" or "30s"
+func parseStrongDuration(raw string) time.Duration {
+	d, err := time.ParseDuration(strings.ReplaceAll(raw, " ", ""))
+	if err != nil {
+		return 0
+	}
+	return d
+}
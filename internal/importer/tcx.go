@@ -0,0 +1,91 @@
+package importer
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// tcxSports names the sports a TCX activity can declare
+var tcxSports = map[string]string{
+	"Running": "Running",
+	"Biking":  "Cycling",
+}
+
+type tcxDatabase struct {
+	XMLName    xml.Name      `xml:"TrainingCenterDatabase"`
+	Activities []tcxActivity `xml:"Activities>Activity"`
+}
+
+type tcxActivity struct {
+	Sport string   `xml:"Sport,attr"`
+	ID    string   `xml:"Id"`
+	Laps  []tcxLap `xml:"Lap"`
+}
+
+type tcxLap struct {
+	StartTime        string   `xml:"StartTime,attr"`
+	TotalTimeSeconds float64  `xml:"TotalTimeSeconds"`
+	DistanceMeters   *float64 `xml:"DistanceMeters"`
+	Calories         *float64 `xml:"Calories"`
+	HeartRateAvg     *float64 `xml:"AverageHeartRateBpm>Value"`
+	HeartRateMax     *float64 `xml:"MaximumHeartRateBpm>Value"`
+}
+
+// ParseTCX parses a Garmin Training Center (.TCX) file into cardio sessions, one per
+// activity, with their laps. Session totals are summed from the laps; trackpoints are
+// skipped. TCX timestamps carry an offset (usually Z).
+func ParseTCX(r io.Reader, opts Options) ([]*models.ImportedSession, error) {
+	var db tcxDatabase
+	if err := xml.NewDecoder(r).Decode(&db); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	if len(db.Activities) == 0 {
+		return nil, fmt.Errorf("%w: no activity found", ErrInvalidFile)
+	}
+
+	sessions := make([]*models.ImportedSession, 0, len(db.Activities))
+	for i, activity := range db.Activities {
+		laps := make([]*models.ImportedLap, 0, len(activity.Laps))
+		for j, l := range activity.Laps {
+			start, err := time.Parse(time.RFC3339, l.StartTime)
+			if err != nil {
+				return nil, fmt.Errorf("%w: activity %d, lap %d: invalid start time %q", ErrInvalidFile, i+1, j+1, l.StartTime)
+			}
+			laps = append(laps, &models.ImportedLap{
+				StartedAt:      start,
+				Duration:       time.Duration(l.TotalTimeSeconds * float64(time.Second)),
+				DistanceM:      positiveOptional(l.DistanceMeters),
+				CaloriesBurned: roundOptional(l.Calories),
+				HeartRateAvg:   roundOptional(l.HeartRateAvg),
+				HeartRateMax:   roundOptional(l.HeartRateMax),
+			})
+		}
+
+		startedAt, err := time.Parse(time.RFC3339, activity.ID)
+		if err != nil {
+			if len(laps) == 0 {
+				return nil, fmt.Errorf("%w: activity %d: invalid id %q", ErrInvalidFile, i+1, activity.ID)
+			}
+			startedAt = laps[0].StartedAt
+		}
+
+		name := tcxSports[activity.Sport]
+		if name == "" {
+			name = "Cardio"
+		}
+		sessions = append(sessions, lapsSession(name, startedAt, laps))
+	}
+
+	return sessions, nil
+}
+
+func positiveOptional(v *float64) *float64 {
+	if v == nil || *v <= 0 {
+		return nil
+	}
+	return v
+}
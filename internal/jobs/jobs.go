@@ -0,0 +1,247 @@
+// Package jobs runs background work queued in Postgres. Workers claim due jobs with a
+// lease, retry failures with exponential backoff and dead-letter jobs that keep failing,
+// where operators can inspect and requeue them.
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// ErrUnknownKind is returned when queueing a kind no handler was registered for
+var ErrUnknownKind = errors.New("unknown job kind")
+
+const (
+	// defaultMaxAttempts is how often a job is tried when its kind sets no limit; with the
+	// backoff the last attempt is about two hours after the first
+	defaultMaxAttempts = 8
+
+	// defaultTimeout bounds an attempt when its kind sets no timeout
+	defaultTimeout = time.Minute
+
+	// leaseMargin is added to the longest timeout for the lease on claimed jobs, so a job
+	// is only claimed again once its worker is surely gone
+	leaseMargin = time.Minute
+
+	// retention is how long finished and dead jobs are kept
+	retention = 30 * 24 * time.Hour
+)
+
+// Handler runs a job. A returned error retries the job with backoff until it runs out of
+// attempts; wrap it with Permanent when retrying can't help.
+type Handler func(ctx context.Context, job *models.Job) error
+
+// Options tune how jobs of a kind are run
+type Options struct {
+	MaxAttempts int           // Attempts before the job is dead-lettered; 8 when zero
+	Timeout     time.Duration // Bounds one attempt; a minute when zero
+}
+
+type registration struct {
+	handler Handler
+	opts    Options
+}
+
+// Queue queues jobs and runs them with the handlers registered for their kinds
+type Queue struct {
+	repo     repositories.JobRepository
+	handlers map[string]registration
+	now      func() time.Time
+}
+
+// NewQueue creates a new queue storing jobs in repo
+func NewQueue(repo repositories.JobRepository) *Queue {
+	return &Queue{repo: repo, handlers: make(map[string]registration), now: time.Now}
+}
+
+// Register sets the handler of a kind. Kinds must be registered before jobs of them are
+// queued or Start is called; only registered kinds are claimed.
+func (q *Queue) Register(kind string, opts Options, handler Handler) {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = defaultMaxAttempts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+	q.handlers[kind] = registration{handler: handler, opts: opts}
+}
+
+// Enqueue queues a job to run as soon as a worker is free; payload is marshaled to JSON.
+// userID is the user the work is for, or empty for system work.
+func (q *Queue) Enqueue(ctx context.Context, kind string, userID string, payload any) (*models.Job, error) {
+	reg, ok := q.handlers[kind]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKind, kind)
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode job payload: %w", err)
+	}
+	job := &models.Job{Kind: kind, Payload: raw, MaxAttempts: reg.opts.MaxAttempts}
+	if userID != "" {
+		job.UserID = &userID
+	}
+	if err := q.repo.Enqueue(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to queue job: %w", err)
+	}
+	return job, nil
+}
+
+// Start runs due jobs on up to workers goroutines, polling every interval, and prunes old
+// jobs hourly, until ctx is cancelled
+func (q *Queue) Start(ctx context.Context, workers int, interval time.Duration) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		pruneTicker := time.NewTicker(time.Hour)
+		defer pruneTicker.Stop()
+		slots := make(chan struct{}, workers)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				q.dispatch(ctx, slots)
+			case <-pruneTicker.C:
+				if _, err := q.repo.Prune(ctx, q.now().Add(-retention)); err != nil {
+					log.Printf("Jobs failed to prune: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// dispatch claims as many due jobs as there are free slots and runs each in its own
+// goroutine, which frees its slot when done
+func (q *Queue) dispatch(ctx context.Context, slots chan struct{}) {
+	free := cap(slots) - len(slots)
+	if free == 0 {
+		return
+	}
+
+	jobs, err := q.repo.Claim(ctx, q.kinds(), q.lease(), free)
+	if err != nil {
+		log.Printf("Jobs failed to claim: %v", err)
+		return
+	}
+
+	for _, job := range jobs {
+		slots <- struct{}{}
+		go func() {
+			defer func() { <-slots }()
+			q.run(ctx, job)
+		}()
+	}
+}
+
+// kinds lists the registered kinds, which are the ones this queue claims
+func (q *Queue) kinds() []string {
+	return slices.Sorted(maps.Keys(q.handlers))
+}
+
+// lease is how long claimed jobs are kept from other workers
+func (q *Queue) lease() time.Duration {
+	longest := defaultTimeout
+	for _, reg := range q.handlers {
+		longest = max(longest, reg.opts.Timeout)
+	}
+	return longest + leaseMargin
+}
+
+// run runs a claimed job and records the outcome: done, retried later with exponential
+// backoff, or dead after its last attempt or a permanent error
+func (q *Queue) run(ctx context.Context, job *models.Job) {
+	reg, ok := q.handlers[job.Kind]
+	if !ok {
+		q.fail(ctx, job, fmt.Errorf("%w: %q", ErrUnknownKind, job.Kind), false)
+		return
+	}
+	if job.Attempts > job.MaxAttempts {
+		// Claimed again after its worker died during the last attempt
+		q.fail(ctx, job, errors.New("worker stopped during the last attempt"), false)
+		return
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, reg.opts.Timeout)
+	err := call(runCtx, reg.handler, job)
+	cancel()
+
+	if err == nil {
+		if err := q.repo.Complete(ctx, job.ID); err != nil {
+			log.Printf("Job %s failed to record completion: %v", job.ID, err)
+		}
+		return
+	}
+	q.fail(ctx, job, err, !IsPermanent(err) && !LastAttempt(job))
+}
+
+// call runs a handler, turning a panic into an error so one bad job can't take the
+// workers down
+func call(ctx context.Context, handler Handler, job *models.Job) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// fail records a failed attempt, scheduled for a retry with backoff when retry is set
+func (q *Queue) fail(ctx context.Context, job *models.Job, err error, retry bool) {
+	var retryAt *time.Time
+	if retry {
+		next := q.now().Add(Backoff(job.Attempts))
+		retryAt = &next
+	} else {
+		log.Printf("Job %s (%s) is dead after %d attempts: %v", job.ID, job.Kind, job.Attempts, err)
+	}
+	if err := q.repo.Fail(ctx, job.ID, err.Error(), retryAt); err != nil {
+		log.Printf("Job %s failed to record failure: %v", job.ID, err)
+	}
+}
+
+// Backoff is how long a job waits after its given (1-based) attempt failed: a minute,
+// doubling with each attempt
+func Backoff(attempt int) time.Duration {
+	return time.Minute << max(attempt-1, 0)
+}
+
+// LastAttempt reports whether a claimed job is on its last attempt, so that handlers can
+// record a final failure on the work itself
+func LastAttempt(job *models.Job) bool {
+	return job.Attempts >= job.MaxAttempts
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error retrying can't fix, e.g. an invalid payload; the job is
+// dead-lettered right away
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether an error was marked with Permanent
+func IsPermanent(err error) bool {
+	var p *permanentError
+	return errors.As(err, &p)
+}
@@ -0,0 +1,152 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestEnqueue_RegisteredKinds(t *testing.T) {
+	var queued *models.Job
+	queue := NewQueue(&repositories.MockJobRepository{
+		EnqueueFunc: func(ctx context.Context, job *models.Job) error {
+			queued = job
+			return nil
+		},
+	})
+	queue.Register("report", Options{MaxAttempts: 3}, func(ctx context.Context, job *models.Job) error { return nil })
+
+	if _, err := queue.Enqueue(context.Background(), "report", "user-123", map[string]string{"id": "r-1"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if queued == nil || queued.UserID == nil || *queued.UserID != "user-123" || queued.MaxAttempts != 3 || string(queued.Payload) != `{"id":"r-1"}` {
+		t.Errorf("Expected the job to be queued for the user with the kind's attempts, got %+v", queued)
+	}
+
+	if _, err := queue.Enqueue(context.Background(), "unknown", "", nil); !errors.Is(err, ErrUnknownKind) {
+		t.Errorf("Expected ErrUnknownKind, got %v", err)
+	}
+}
+
+func TestRun_Outcomes(t *testing.T) {
+	now := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		err       error
+		attempts  int
+		wantDone  bool
+		wantRetry *time.Time
+	}{
+		{"succeeds", nil, 1, true, nil},
+		{"retries with backoff", errors.New("connection reset"), 3, false, ptr(now.Add(4 * time.Minute))},
+		{"dead after the last attempt", errors.New("connection reset"), 5, false, nil},
+		{"dead on a permanent error", Permanent(errors.New("bad payload")), 1, false, nil},
+		{"dead after a panic on the last attempt", nil, 5, false, nil},
+		{"dead when its worker died on the last attempt", nil, 6, false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completed, failed := false, false
+			var retryAt *time.Time
+			queue := NewQueue(&repositories.MockJobRepository{
+				CompleteFunc: func(ctx context.Context, id string) error {
+					completed = true
+					return nil
+				},
+				FailFunc: func(ctx context.Context, id string, reason string, at *time.Time) error {
+					failed = true
+					retryAt = at
+					return nil
+				},
+			})
+			queue.now = func() time.Time { return now }
+			queue.Register("report", Options{MaxAttempts: 5}, func(ctx context.Context, job *models.Job) error {
+				if tt.name == "dead after a panic on the last attempt" {
+					panic("nil map")
+				}
+				return tt.err
+			})
+
+			queue.run(context.Background(), &models.Job{ID: "job-1", Kind: "report", Attempts: tt.attempts, MaxAttempts: 5})
+
+			if completed != tt.wantDone || failed == tt.wantDone {
+				t.Fatalf("Expected done=%t, got completed=%t failed=%t", tt.wantDone, completed, failed)
+			}
+			if (retryAt == nil) != (tt.wantRetry == nil) || (retryAt != nil && !retryAt.Equal(*tt.wantRetry)) {
+				t.Errorf("Expected retry at %v, got %v", tt.wantRetry, retryAt)
+			}
+		})
+	}
+}
+
+func TestRun_Timeout(t *testing.T) {
+	var reason string
+	queue := NewQueue(&repositories.MockJobRepository{
+		FailFunc: func(ctx context.Context, id string, r string, at *time.Time) error {
+			reason = r
+			return nil
+		},
+	})
+	queue.Register("slow", Options{Timeout: 10 * time.Millisecond}, func(ctx context.Context, job *models.Job) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	queue.run(context.Background(), &models.Job{ID: "job-1", Kind: "slow", Attempts: 1, MaxAttempts: 8})
+	if reason != context.DeadlineExceeded.Error() {
+		t.Errorf("Expected the attempt to time out, got %q", reason)
+	}
+}
+
+func TestDispatch_ClaimsFreeSlots(t *testing.T) {
+	var kinds []string
+	var limit int
+	done := make(chan string, 2)
+	queue := NewQueue(&repositories.MockJobRepository{
+		ClaimFunc: func(ctx context.Context, k []string, lease time.Duration, l int) ([]*models.Job, error) {
+			kinds, limit = k, l
+			return []*models.Job{
+				{ID: "job-1", Kind: "report", Attempts: 1, MaxAttempts: 8},
+				{ID: "job-2", Kind: "email", Attempts: 1, MaxAttempts: 8},
+			}, nil
+		},
+		CompleteFunc: func(ctx context.Context, id string) error {
+			done <- id
+			return nil
+		},
+	})
+	handler := func(ctx context.Context, job *models.Job) error { return nil }
+	queue.Register("report", Options{}, handler)
+	queue.Register("email", Options{}, handler)
+
+	slots := make(chan struct{}, 3)
+	slots <- struct{}{} // One worker is busy
+	queue.dispatch(context.Background(), slots)
+
+	if !slices.Equal(kinds, []string{"email", "report"}) || limit != 2 {
+		t.Errorf("Expected the registered kinds to be claimed for the 2 free slots, got %v and %d", kinds, limit)
+	}
+	got := []string{<-done, <-done}
+	slices.Sort(got)
+	if !slices.Equal(got, []string{"job-1", "job-2"}) {
+		t.Errorf("Expected both jobs to run, got %v", got)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 8: 128 * time.Minute} {
+		if got := Backoff(attempt); got != want {
+			t.Errorf("Expected backoff %s after attempt %d, got %s", want, attempt, got)
+		}
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,101 @@
+// Package mailer renders the API's emails from templates and sends them through SMTP,
+// Resend or SendGrid
+package mailer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/mail"
+	"strings"
+)
+
+// ErrRejected is returned when the provider refuses a message for good, e.g. because the
+// address is invalid; sending it again would fail the same way
+var ErrRejected = errors.New("email rejected by provider")
+
+// Message is a rendered email to one recipient
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer sends a message through an email provider
+type Mailer interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// Queue queues a templated email to be rendered and sent in the background. userID is the
+// user the email notifies, whose notification preferences apply, or empty for an address
+// that isn't a user's (yet).
+type Queue interface {
+	Enqueue(ctx context.Context, userID string, to string, template string, data any) error
+}
+
+// Providers that New can create
+const (
+	ProviderSMTP     = "smtp"
+	ProviderResend   = "resend"
+	ProviderSendGrid = "sendgrid"
+)
+
+// Config selects and configures the email provider
+type Config struct {
+	Provider       string     // One of the providers, or empty to disable email
+	From           string     // The sender, e.g. "FitAPI <hello@example.com>"
+	SMTP           SMTPConfig // Its From is taken from the config's
+	ResendAPIKey   string
+	SendGridAPIKey string
+}
+
+// New creates the configured provider's mailer, or returns nil when no provider is set
+func New(cfg Config) (Mailer, error) {
+	if cfg.Provider == "" {
+		return nil, nil
+	}
+	if _, err := mail.ParseAddress(cfg.From); err != nil {
+		return nil, fmt.Errorf("invalid sender address %q", cfg.From)
+	}
+
+	switch cfg.Provider {
+	case ProviderSMTP:
+		if cfg.SMTP.Host == "" || cfg.SMTP.Port == 0 {
+			return nil, errors.New("an smtp host and port are required")
+		}
+		smtp := cfg.SMTP
+		smtp.From = cfg.From
+		return NewSMTP(smtp), nil
+	case ProviderResend:
+		if cfg.ResendAPIKey == "" {
+			return nil, errors.New("a resend api key is required")
+		}
+		return NewResend(cfg.ResendAPIKey, cfg.From, nil), nil
+	case ProviderSendGrid:
+		if cfg.SendGridAPIKey == "" {
+			return nil, errors.New("a sendgrid api key is required")
+		}
+		return NewSendGrid(cfg.SendGridAPIKey, cfg.From, nil), nil
+	default:
+		return nil, fmt.Errorf("unknown email provider %q, expected smtp, resend or sendgrid", cfg.Provider)
+	}
+}
+
+// readError reads a provider's error body for the error message
+func readError(resp *http.Response) string {
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	return strings.TrimSpace(string(data))
+}
+
+// statusError describes an unsuccessful API response; client errors other than rate
+// limiting are permanent
+func statusError(provider string, resp *http.Response) error {
+	err := fmt.Errorf("%s responded with %d: %s", provider, resp.StatusCode, readError(resp))
+	if resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests {
+		return fmt.Errorf("%w: %w", ErrRejected, err)
+	}
+	return err
+}
@@ -0,0 +1,198 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRender(t *testing.T) {
+	data, _ := json.Marshal(CoachInvitation{CoachEmail: "<coach>@example.com", CanWrite: true})
+	msg, err := Render(TemplateCoachInvitation, "client@example.com", data)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if msg.To != "client@example.com" || msg.Subject != "<coach>@example.com invited you to train with them" {
+		t.Errorf("Unexpected message: %+v", msg)
+	}
+	if !strings.Contains(msg.Text, "<coach>@example.com invited you") || !strings.Contains(msg.Text, "plan workouts for you") {
+		t.Errorf("Unexpected text: %q", msg.Text)
+	}
+	if strings.Contains(msg.HTML, "<coach>") || !strings.Contains(msg.HTML, "&lt;coach&gt;@example.com") {
+		t.Errorf("Expected data to be escaped in html, got %q", msg.HTML)
+	}
+}
+
+func TestRender_EveryTemplate(t *testing.T) {
+	data := map[string]any{
+		TemplateCoachInvitation: CoachInvitation{CoachEmail: "coach@example.com"},
+		TemplateWeeklySummary:   WeeklySummary{WeekStart: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Sessions: 1, Minutes: 45, VolumeKg: 5200.4, StreakWeeks: 3},
+		TemplateWorkoutReminder: WorkoutReminder{SessionID: "session-1", Name: "Leg day", StartsAt: time.Date(2026, 3, 2, 18, 30, 0, 0, time.UTC)},
+	}
+	for name := range templateData {
+		raw, _ := json.Marshal(data[name])
+		msg, err := Render(name, "user@example.com", raw)
+		if err != nil {
+			t.Errorf("%s: expected no error, got %v", name, err)
+			continue
+		}
+		if msg.Subject == "" || msg.Text == "" || msg.HTML == "" {
+			t.Errorf("%s: expected a subject, text and html, got %+v", name, msg)
+		}
+	}
+
+	raw, _ := json.Marshal(WorkoutReminder{Name: "Evening training", StartsAt: time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC), Timezone: "Europe/Madrid"})
+	msg, _ := Render(TemplateWorkoutReminder, "user@example.com", raw)
+	if msg.Subject != "Reminder: Evening training at 18:00 CET" {
+		t.Errorf("Expected the time in the reminder's timezone, got %q", msg.Subject)
+	}
+
+	raw, _ = json.Marshal(data[TemplateWeeklySummary])
+	msg, _ = Render(TemplateWeeklySummary, "user@example.com", raw)
+	if msg.Subject != "Your week in training: 1 session" || !strings.Contains(msg.Text, "Volume: 5200 kg") || !strings.Contains(msg.HTML, "Streak: 3 weeks") {
+		t.Errorf("Unexpected weekly summary: %+v", msg)
+	}
+}
+
+func TestRender_Unknown(t *testing.T) {
+	if _, err := Render("newsletter", "user@example.com", []byte(`{}`)); err == nil {
+		t.Error("Expected an error for an unknown template")
+	}
+	if Known("newsletter") || !Known(TemplateWeeklySummary) {
+		t.Error("Expected Known to report the templates")
+	}
+}
+
+func TestResendSend(t *testing.T) {
+	var sent map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer re_test" {
+			t.Errorf("Expected the API key, got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		switch sent["to"].([]any)[0] {
+		case "invalid":
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			w.Write([]byte(`{"name":"validation_error","message":"Invalid to field"}`))
+		case "busy@example.com":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.Write([]byte(`{"id":"email-1"}`))
+		}
+	}))
+	defer server.Close()
+
+	resend := NewResend("re_test", "FitAPI <hello@example.com>", nil)
+	resend.URL = server.URL
+
+	msg := &Message{To: "user@example.com", Subject: "Hi", Text: "Hello", HTML: "<p>Hello</p>"}
+	if err := resend.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if sent["from"] != "FitAPI <hello@example.com>" || sent["subject"] != "Hi" || sent["html"] != "<p>Hello</p>" {
+		t.Errorf("Unexpected request: %v", sent)
+	}
+
+	msg.To = "invalid"
+	if err := resend.Send(context.Background(), msg); !errors.Is(err, ErrRejected) {
+		t.Errorf("Expected ErrRejected, got %v", err)
+	}
+	msg.To = "busy@example.com"
+	if err := resend.Send(context.Background(), msg); err == nil || errors.Is(err, ErrRejected) {
+		t.Errorf("Expected a retryable error for rate limiting, got %v", err)
+	}
+}
+
+func TestSendGridSend(t *testing.T) {
+	var sent struct {
+		Personalizations []struct {
+			To []sendGridAddress `json:"to"`
+		} `json:"personalizations"`
+		From    sendGridAddress   `json:"from"`
+		Content []sendGridContent `json:"content"`
+	}
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer SG.test" {
+			t.Errorf("Expected the API key, got %q", r.Header.Get("Authorization"))
+		}
+		json.NewDecoder(r.Body).Decode(&sent)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sendgrid := NewSendGrid("SG.test", "FitAPI <hello@example.com>", nil)
+	sendgrid.URL = server.URL
+
+	msg := &Message{To: "user@example.com", Subject: "Hi", Text: "Hello", HTML: "<p>Hello</p>"}
+	if err := sendgrid.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sent.Personalizations) != 1 || sent.Personalizations[0].To[0].Email != "user@example.com" || sent.From != (sendGridAddress{Email: "hello@example.com", Name: "FitAPI"}) {
+		t.Errorf("Unexpected request: %+v", sent)
+	}
+	if len(sent.Content) != 2 || sent.Content[0].Type != "text/plain" {
+		t.Errorf("Expected the plain text part first, got %+v", sent.Content)
+	}
+
+	status = http.StatusBadGateway
+	if err := sendgrid.Send(context.Background(), msg); err == nil || errors.Is(err, ErrRejected) {
+		t.Errorf("Expected a retryable error, got %v", err)
+	}
+}
+
+func TestBuildMessage(t *testing.T) {
+	msg := &Message{To: "user@example.com", Subject: "Récord ☺", Text: "Plain body", HTML: "<p>Html body</p>"}
+	body, err := buildMessage(&mail.Address{Name: "FitAPI", Address: "hello@example.com"}, msg, time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	s := string(body)
+	for _, want := range []string{
+		"From: \"FitAPI\" <hello@example.com>\r\n",
+		"@example.com>\r\n", // Message-ID
+		"To: user@example.com\r\n",
+		"Subject: =?utf-8?q?R=C3=A9cord_=E2=98=BA?=\r\n",
+		"Date: Mon, 02 Mar 2026 09:00:00 +0000\r\n",
+		"Content-Type: multipart/alternative; boundary=",
+		"Plain body",
+		"<p>Html body</p>",
+	} {
+		if !strings.Contains(s, want) {
+			t.Errorf("Expected message to contain %q, got:\n%s", want, s)
+		}
+	}
+	if strings.Index(s, "Plain body") > strings.Index(s, "Html body") {
+		t.Error("Expected the plain text part first")
+	}
+}
+
+func TestNew(t *testing.T) {
+	m, err := New(Config{})
+	if m != nil || err != nil {
+		t.Errorf("Expected email to be disabled without a provider, got %v, %v", m, err)
+	}
+
+	m, err = New(Config{Provider: ProviderSMTP, From: "FitAPI <hello@example.com>", SMTP: SMTPConfig{Host: "smtp.example.com", Port: 587}})
+	if smtp, ok := m.(*SMTP); !ok || err != nil || smtp.cfg.From != "FitAPI <hello@example.com>" {
+		t.Errorf("Expected an smtp mailer sending from the sender, got %v, %v", m, err)
+	}
+
+	for _, cfg := range []Config{
+		{Provider: ProviderResend, ResendAPIKey: "re_test"},
+		{Provider: ProviderResend, From: "hello@example.com"},
+		{Provider: "mailgun", From: "hello@example.com"},
+	} {
+		if _, err := New(cfg); err == nil {
+			t.Errorf("Expected %+v to be rejected", cfg)
+		}
+	}
+}
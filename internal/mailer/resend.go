@@ -0,0 +1,63 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const defaultResendURL = "https://api.resend.com/emails"
+
+// Resend sends email with the Resend API
+type Resend struct {
+	apiKey     string
+	from       string
+	httpClient *http.Client
+
+	// URL is the API endpoint, overridden in tests
+	URL string
+}
+
+// NewResend creates a Resend mailer sending from the given address, which must belong to
+// a domain verified with Resend; a nil httpClient uses one with a 30s timeout
+func NewResend(apiKey, from string, httpClient *http.Client) *Resend {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Resend{apiKey: apiKey, from: from, httpClient: httpClient, URL: defaultResendURL}
+}
+
+// Send sends a message
+func (r *Resend) Send(ctx context.Context, msg *Message) error {
+	body, err := json.Marshal(map[string]any{
+		"from":    r.from,
+		"to":      []string{msg.To},
+		"subject": msg.Subject,
+		"text":    msg.Text,
+		"html":    msg.HTML,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+r.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("resend request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError("resend", resp)
+	}
+	return nil
+}
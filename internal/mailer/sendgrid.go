@@ -0,0 +1,82 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"time"
+)
+
+const defaultSendGridURL = "https://api.sendgrid.com/v3/mail/send"
+
+// SendGrid sends email with the SendGrid v3 Mail Send API
+type SendGrid struct {
+	apiKey     string
+	from       string
+	httpClient *http.Client
+
+	// URL is the API endpoint, overridden in tests
+	URL string
+}
+
+// NewSendGrid creates a SendGrid mailer sending from the given address, which must be a
+// verified sender of the account; a nil httpClient uses one with a 30s timeout
+func NewSendGrid(apiKey, from string, httpClient *http.Client) *SendGrid {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &SendGrid{apiKey: apiKey, from: from, httpClient: httpClient, URL: defaultSendGridURL}
+}
+
+type sendGridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendGridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Send sends a message
+func (s *SendGrid) Send(ctx context.Context, msg *Message) error {
+	// SendGrid requires the plain text part to come first
+	content := []sendGridContent{{Type: "text/plain", Value: msg.Text}}
+	if msg.HTML != "" {
+		content = append(content, sendGridContent{Type: "text/html", Value: msg.HTML})
+	}
+	from, err := mail.ParseAddress(s.from)
+	if err != nil {
+		return fmt.Errorf("invalid sender address: %w", err)
+	}
+	body, err := json.Marshal(map[string]any{
+		"personalizations": []map[string]any{{"to": []sendGridAddress{{Email: msg.To}}}},
+		"from":             sendGridAddress{Email: from.Address, Name: from.Name},
+		"subject":          msg.Subject,
+		"content":          content,
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.apiKey)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return statusError("sendgrid", resp)
+	}
+	return nil
+}
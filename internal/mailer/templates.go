@@ -0,0 +1,111 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+	"time"
+)
+
+// Template names
+const (
+	TemplateCoachInvitation = "coach_invitation"
+	TemplateWeeklySummary   = "weekly_summary"
+	TemplateWorkoutReminder = "workout_reminder"
+)
+
+// CoachInvitation is the data of a coach invitation email
+type CoachInvitation struct {
+	CoachEmail string `json:"coach_email"`
+	CanWrite   bool   `json:"can_write"` // The coach asked to also manage the client's workouts
+}
+
+// WeeklySummary is the data of a weekly summary email
+type WeeklySummary struct {
+	WeekStart       time.Time `json:"week_start"`
+	Sessions        int       `json:"sessions"`
+	Minutes         int       `json:"minutes"`
+	VolumeKg        float64   `json:"volume_kg"`
+	PersonalRecords int       `json:"personal_records"`
+	StreakWeeks     int       `json:"streak_weeks"` // Weeks in a row with a session, ending this one
+}
+
+// WorkoutReminder is the data of a reminder for a planned session or of a reminder rule
+type WorkoutReminder struct {
+	SessionID string    `json:"session_id,omitempty"`
+	RuleID    string    `json:"rule_id,omitempty"`
+	Name      string    `json:"name"`
+	StartsAt  time.Time `json:"starts_at"`
+	Timezone  string    `json:"timezone,omitempty"` // Shown in UTC without one
+}
+
+// LocalStart is StartsAt in the reminder's timezone
+func (r *WorkoutReminder) LocalStart() time.Time {
+	if loc, err := time.LoadLocation(r.Timezone); err == nil && r.Timezone != "" {
+		return r.StartsAt.In(loc)
+	}
+	return r.StartsAt.UTC()
+}
+
+// templateData creates the data value each template is rendered with
+var templateData = map[string]func() any{
+	TemplateCoachInvitation: func() any { return &CoachInvitation{} },
+	TemplateWeeklySummary:   func() any { return &WeeklySummary{} },
+	TemplateWorkoutReminder: func() any { return &WorkoutReminder{} },
+}
+
+// Each file defines a "subject", a "text" and an "html" template; the html template is
+// parsed with html/template so that data is escaped
+//
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+type emailTemplate struct {
+	text *texttemplate.Template
+	html *htmltemplate.Template
+}
+
+var templates = func() map[string]*emailTemplate {
+	parsed := make(map[string]*emailTemplate, len(templateData))
+	for name := range templateData {
+		file := "templates/" + name + ".tmpl"
+		parsed[name] = &emailTemplate{
+			text: texttemplate.Must(texttemplate.New(name).ParseFS(templateFiles, file)),
+			html: htmltemplate.Must(htmltemplate.New(name).ParseFS(templateFiles, file)),
+		}
+	}
+	return parsed
+}()
+
+// Known reports whether a template exists
+func Known(name string) bool {
+	_, ok := templates[name]
+	return ok
+}
+
+// Render renders a template for a recipient from its JSON data
+func Render(name, to string, data json.RawMessage) (*Message, error) {
+	tmpl, ok := templates[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+	value := templateData[name]()
+	if err := json.Unmarshal(data, value); err != nil {
+		return nil, fmt.Errorf("invalid %s data: %w", name, err)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, "subject", value); err != nil {
+		return nil, err
+	}
+	if err := tmpl.text.ExecuteTemplate(&text, "text", value); err != nil {
+		return nil, err
+	}
+	if err := tmpl.html.ExecuteTemplate(&html, "html", value); err != nil {
+		return nil, err
+	}
+	return &Message{To: to, Subject: subject.String(), Text: text.String(), HTML: html.String()}, nil
+}
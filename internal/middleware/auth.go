@@ -1,36 +1,51 @@
 package middleware
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+const (
+	// ServiceRole is the role claim of Supabase service-role keys and internal M2M tokens
+	ServiceRole = "service_role"
+
+	// AdminRole is the app_metadata.role value that grants access to admin endpoints
+	AdminRole = "admin"
+
+	// OnBehalfOfHeader lets a service token act for a specific user
+	OnBehalfOfHeader = "X-On-Behalf-Of"
+)
+
+// TokenRevocationChecker reports whether a token has been revoked before its expiry
+type TokenRevocationChecker interface {
+	IsRevoked(tokenID string) bool
+}
+
 // AuthRequired is a middleware that validates JWT tokens from Supabase
 // It extracts the token from the Authorization header and validates it
 // If valid, it stores the user_id in the Gin context for handlers to use
-func AuthRequired() gin.HandlerFunc {
-	// Check if auth should be skipped (development mode)
-	skipAuth := os.Getenv("SKIP_AUTH") == "true"
-
-	// Get JWT secret from environment
-	jwtSecret := os.Getenv("SUPABASE_JWT_SECRET")
-	if jwtSecret == "" && !skipAuth {
-		panic("SUPABASE_JWT_SECRET environment variable is required")
-	}
+// Tokens revoked through logout are rejected even if they have not expired yet
+// Service-role tokens (role=service_role) set is_service and service_name instead of
+// requiring a sub claim; internal jobs pick the user they act for with X-On-Behalf-Of
+func AuthRequired(revocations TokenRevocationChecker) gin.HandlerFunc {
+	skipAuth, jwtSecret := loadAuthSettings()
 
 	return func(c *gin.Context) {
 		// Development mode: bypass auth and inject test user
-		// This user was created by running: go run cmd/gettoken/main.go
 		if skipAuth {
-			c.Set("user_id", "6b37ab1f-b190-4072-9e50-5318d4bad35d") // test@example.com
-			c.Set("user_email", "test@example.com")
+			setTestUser(c)
 			c.Next()
 			return
 		}
+
 		// 1. Extract Authorization header
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
@@ -41,61 +56,201 @@ func AuthRequired() gin.HandlerFunc {
 			return
 		}
 
-		// 2. Extract token (remove "Bearer " prefix)
-		tokenString := strings.TrimPrefix(authHeader, "Bearer ")
-		if tokenString == authHeader {
-			// "Bearer " prefix not found
-			c.JSON(401, gin.H{
-				"error": "invalid authorization header format, expected 'Bearer <token>'",
-			})
-			c.Abort()
+		if !authenticate(c, authHeader, jwtSecret, revocations) {
 			return
 		}
 
-		// 3. Parse and validate JWT token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			return []byte(jwtSecret), nil
-		})
+		// Continue to the next handler
+		c.Next()
+	}
+}
 
-		if err != nil || !token.Valid {
-			c.JSON(401, gin.H{
-				"error": "invalid or expired token",
-			})
-			c.Abort()
+// OptionalAuth is a middleware for endpoints that also serve anonymous visitors
+// Requests without an Authorization header continue with no user_id in context.
+// When a header is present it is validated exactly like AuthRequired, so a bad or
+// revoked token is still rejected instead of silently downgraded to anonymous.
+func OptionalAuth(revocations TokenRevocationChecker) gin.HandlerFunc {
+	skipAuth, jwtSecret := loadAuthSettings()
+
+	return func(c *gin.Context) {
+		if skipAuth {
+			setTestUser(c)
+			c.Next()
 			return
 		}
 
-		// 4. Extract claims (user information)
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			c.JSON(401, gin.H{
-				"error": "invalid token claims",
-			})
-			c.Abort()
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" {
+			c.Next()
 			return
 		}
 
-		// 5. Extract user_id (sub claim) and email
-		userID, ok := claims["sub"].(string)
-		if !ok {
-			c.JSON(401, gin.H{
-				"error": "invalid user_id in token",
-			})
-			c.Abort()
+		if !authenticate(c, authHeader, jwtSecret, revocations) {
 			return
 		}
 
-		email, _ := claims["email"].(string) // Optional
+		c.Next()
+	}
+}
 
-		// 6. Store user information in context for handlers to use
-		c.Set("user_id", userID)
-		c.Set("user_email", email)
+// loadAuthSettings reads the auth configuration shared by the auth middlewares
+func loadAuthSettings() (skipAuth bool, jwtSecret string) {
+	// Check if auth should be skipped (development mode)
+	skipAuth = os.Getenv("SKIP_AUTH") == "true"
+
+	// Get JWT secret from environment
+	jwtSecret = os.Getenv("SUPABASE_JWT_SECRET")
+	if jwtSecret == "" && !skipAuth {
+		panic("SUPABASE_JWT_SECRET environment variable is required")
+	}
+
+	return skipAuth, jwtSecret
+}
+
+// setTestUser injects the development user used when SKIP_AUTH is enabled
+// This user was created by running: go run cmd/gettoken/main.go
+func setTestUser(c *gin.Context) {
+	c.Set("user_id", "6b37ab1f-b190-4072-9e50-5318d4bad35d") // test@example.com
+	c.Set("user_email", "test@example.com")
+}
+
+// authenticate validates the bearer token and stores the caller in context
+// On failure it writes a 401 response, aborts the chain and returns false
+func authenticate(c *gin.Context, authHeader string, jwtSecret string, revocations TokenRevocationChecker) bool {
+	// 2. Extract token (remove "Bearer " prefix)
+	tokenString := strings.TrimPrefix(authHeader, "Bearer ")
+	if tokenString == authHeader {
+		// "Bearer " prefix not found
+		c.JSON(401, gin.H{
+			"error": "invalid authorization header format, expected 'Bearer <token>'",
+		})
+		c.Abort()
+		return false
+	}
+
+	// 3. Parse and validate JWT token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (any, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return []byte(jwtSecret), nil
+	})
+
+	if err != nil || !token.Valid {
+		c.JSON(401, gin.H{
+			"error": "invalid or expired token",
+		})
+		c.Abort()
+		return false
+	}
+
+	// 4. Extract claims (user information)
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		c.JSON(401, gin.H{
+			"error": "invalid token claims",
+		})
+		c.Abort()
+		return false
+	}
+
+	// 5. Extract user_id (sub claim) and email
+	// Service tokens carry no user; they may act for one via X-On-Behalf-Of
+	isService := claims["role"] == ServiceRole
+	userID, ok := claims["sub"].(string)
+	if isService {
+		userID, ok = c.GetHeader(OnBehalfOfHeader), true
+		if userID != "" {
+			if _, err := uuid.Parse(userID); err != nil {
+				c.JSON(400, gin.H{
+					"error": "invalid " + OnBehalfOfHeader + " header, expected a UUID",
+				})
+				c.Abort()
+				return false
+			}
+		}
+	}
+	if !ok {
+		c.JSON(401, gin.H{
+			"error": "invalid user_id in token",
+		})
+		c.Abort()
+		return false
+	}
+
+	email, _ := claims["email"].(string) // Optional
+
+	// 6. Reject tokens revoked before their natural expiry
+	tokenID := TokenID(claims, tokenString)
+	if revocations.IsRevoked(tokenID) {
+		c.JSON(401, gin.H{
+			"error": "token has been revoked",
+		})
+		c.Abort()
+		return false
+	}
+
+	// 7. Store user information in context for handlers to use
+	c.Set("user_id", userID)
+	c.Set("user_email", email)
+	c.Set("token_id", tokenID)
+	if scopes, ok := TokenScopes(claims); ok {
+		c.Set("token_scopes", scopes)
+	}
+	if exp, err := claims.GetExpirationTime(); err == nil && exp != nil {
+		c.Set("token_expires_at", exp.Time)
+	}
+
+	// 8. Flag administrators (app_metadata.role = "admin", set via the service role)
+	if appMetadata, ok := claims["app_metadata"].(map[string]any); ok && appMetadata["role"] == AdminRole {
+		c.Set("is_admin", true)
+	}
+
+	// 9. Mark and audit elevated machine-to-machine access
+	if isService {
+		serviceName, _ := claims["service"].(string)
+		if serviceName == "" {
+			serviceName = ServiceRole
+		}
+		c.Set("is_service", true)
+		c.Set("service_name", serviceName)
+		log.Printf("Service access: service=%s method=%s path=%s on_behalf_of=%q",
+			serviceName, c.Request.Method, c.Request.URL.Path, userID)
+	}
+
+	return true
+}
+
+// IsService reports whether the request was authenticated with a service token
+func IsService(c *gin.Context) bool {
+	return c.GetBool("is_service")
+}
+
+// AdminRequired is a middleware that restricts a route group to administrators
+// It must run after AuthRequired. Service tokens are treated as administrators.
+func AdminRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !c.GetBool("is_admin") && !IsService(c) {
+			c.JSON(403, gin.H{
+				"error": "admin access required",
+			})
+			c.Abort()
+			return
+		}
 
-		// 7. Continue to the next handler
 		c.Next()
 	}
 }
+
+// TokenID identifies a token for revocation purposes
+// Supabase access tokens don't always carry a jti claim, so the SHA-256 of the
+// raw token is used as a stable fallback identifier
+func TokenID(claims jwt.MapClaims, tokenString string) string {
+	if jti, ok := claims["jti"].(string); ok && jti != "" {
+		return jti
+	}
+
+	sum := sha256.Sum256([]byte(tokenString))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,197 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const testJWTSecret = "test-secret"
+
+type fakeRevocations map[string]bool
+
+func (f fakeRevocations) IsRevoked(tokenID string) bool {
+	return f[tokenID]
+}
+
+func signTestToken(t *testing.T, claims jwt.MapClaims) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("Failed to sign token: %v", err)
+	}
+	return token
+}
+
+func runAuth(t *testing.T, revocations fakeRevocations, header http.Header) (*httptest.ResponseRecorder, gin.H) {
+	t.Helper()
+	return runAuthWith(t, revocations, header)
+}
+
+// runAuthWith runs AuthRequired after the given middlewares
+func runAuthWith(t *testing.T, revocations fakeRevocations, header http.Header, before ...gin.HandlerFunc) (*httptest.ResponseRecorder, gin.H) {
+	t.Helper()
+	t.Setenv("SKIP_AUTH", "false")
+	t.Setenv("SUPABASE_JWT_SECRET", testJWTSecret)
+	gin.SetMode(gin.TestMode)
+
+	var got gin.H
+	router := gin.New()
+	handlers := append(before, AuthRequired(revocations), func(c *gin.Context) {
+		got = gin.H{
+			"user_id":    c.GetString("user_id"),
+			"is_service": IsService(c),
+			"service":    c.GetString("service_name"),
+			"is_admin":   c.GetBool("is_admin"),
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/api/me", handlers...)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/me", nil)
+	req.Header = header
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	return w, got
+}
+
+func TestAuthRequired_UserToken(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"sub": "6b37ab1f-b190-4072-9e50-5318d4bad35d",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	w, got := runAuth(t, fakeRevocations{}, http.Header{"Authorization": {"Bearer " + token}})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got["user_id"] != "6b37ab1f-b190-4072-9e50-5318d4bad35d" || got["is_service"] != false {
+		t.Errorf("Unexpected context values: %v", got)
+	}
+}
+
+func TestAuthRequired_RevokedToken(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"sub": "6b37ab1f-b190-4072-9e50-5318d4bad35d",
+		"jti": "revoked-jti",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	w, _ := runAuth(t, fakeRevocations{"revoked-jti": true}, http.Header{"Authorization": {"Bearer " + token}})
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
+
+func TestAuthRequired_ServiceTokenOnBehalfOf(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"role":    ServiceRole,
+		"service": "report-generator",
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	})
+
+	header := http.Header{
+		"Authorization":  {"Bearer " + token},
+		OnBehalfOfHeader: {"6b37ab1f-b190-4072-9e50-5318d4bad35d"},
+	}
+	w, got := runAuth(t, fakeRevocations{}, header)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got["user_id"] != "6b37ab1f-b190-4072-9e50-5318d4bad35d" {
+		t.Errorf("Expected acting user to be set, got %v", got["user_id"])
+	}
+	if got["is_service"] != true || got["service"] != "report-generator" {
+		t.Errorf("Expected service context, got %v", got)
+	}
+}
+
+func TestAuthRequired_ServiceTokenInvalidOnBehalfOf(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"role": ServiceRole,
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+
+	header := http.Header{
+		"Authorization":  {"Bearer " + token},
+		OnBehalfOfHeader: {"not-a-uuid"},
+	}
+	w, _ := runAuth(t, fakeRevocations{}, header)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestAuthRequired_TokenWithoutSub(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"role": "authenticated",
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+
+	w, _ := runAuth(t, fakeRevocations{}, http.Header{"Authorization": {"Bearer " + token}})
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+}
+
+func TestAuthRequired_AdminAppMetadata(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"sub":          "6b37ab1f-b190-4072-9e50-5318d4bad35d",
+		"exp":          time.Now().Add(time.Hour).Unix(),
+		"app_metadata": map[string]any{"role": AdminRole},
+	})
+
+	w, got := runAuth(t, fakeRevocations{}, http.Header{"Authorization": {"Bearer " + token}})
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got["is_admin"] != true {
+		t.Errorf("Expected is_admin to be set, got %v", got)
+	}
+}
+
+func TestAdminRequired(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"regular user", "", http.StatusForbidden},
+		{"admin", "is_admin", http.StatusOK},
+		{"service token", "is_service", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/api/admin/storage", func(c *gin.Context) {
+				if tt.key != "" {
+					c.Set(tt.key, true)
+				}
+				c.Next()
+			}, AdminRequired(), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/storage", nil))
+
+			if w.Code != tt.status {
+				t.Errorf("Expected status %d, got %d", tt.status, w.Code)
+			}
+		})
+	}
+}
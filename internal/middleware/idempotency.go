@@ -0,0 +1,199 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"io/fs"
+	"log"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+const (
+	// maxIdempotencyKeyLength bounds the keys clients may send; UUIDs are the expected choice
+	maxIdempotencyKeyLength = 255
+
+	// maxIdempotentBodySize bounds the bodies of requests sent with an Idempotency-Key, which
+	// are read before the handler runs: the largest upload a route accepts (Apple Health
+	// exports). Handlers still apply their own limits when they read the body.
+	maxIdempotentBodySize = 512 << 20
+
+	// idempotentBodyMemory is how much of a body is kept in memory; the rest is spooled to a
+	// temporary file
+	idempotentBodyMemory = 1 << 20
+)
+
+// IdempotencyStore records requests sent with an Idempotency-Key and their responses
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, userID string, key string, requestHash string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, userID string, key string, statusCode int, contentType string, body []byte) error
+	Release(ctx context.Context, userID string, key string) error
+}
+
+// Idempotency is a middleware that makes POST requests safe to retry
+// A POST sent with an Idempotency-Key header runs once per user and key; retries get the
+// first response back with Idempotent-Replayed: true. It must run after AuthRequired so
+// user_id is available. Reusing a key for a different request gets 422, and retrying while
+// the first request is still running gets 409. Server errors aren't stored, so a retry after
+// one runs the request again. Requests without the header are unaffected.
+func Idempotency(store IdempotencyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(models.IdempotencyHeader)
+		userID := c.GetString("user_id")
+		if c.Request.Method != http.MethodPost || key == "" || userID == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		hash, body, err := spoolBody(c.Writer, c.Request)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			var pathErr *fs.PathError
+			switch {
+			case errors.As(err, &tooLarge):
+				c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+					"error": "request body is too large",
+				})
+			case errors.As(err, &pathErr):
+				log.Printf("Idempotency key failed to spool the request body: %v", err)
+				c.JSON(http.StatusInternalServerError, gin.H{
+					"error": "failed to read request body",
+				})
+			default:
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error": "failed to read request body",
+				})
+			}
+			c.Abort()
+			return
+		}
+		defer body.Close()
+		c.Request.Body = body
+
+		existing, err := store.Reserve(c.Request.Context(), userID, key, hash)
+		if err != nil {
+			log.Printf("Idempotency key failed to reserve: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"error": "failed to check idempotency key",
+			})
+			c.Abort()
+			return
+		}
+
+		switch {
+		case existing == nil:
+		case existing.RequestHash != hash:
+			c.JSON(http.StatusUnprocessableEntity, gin.H{
+				"error": "Idempotency-Key was already used for a different request",
+			})
+			c.Abort()
+			return
+		case existing.StatusCode == nil:
+			c.Header("Retry-After", "1")
+			c.JSON(http.StatusConflict, gin.H{
+				"error": "a request with this Idempotency-Key is still being processed",
+			})
+			c.Abort()
+			return
+		default:
+			c.Header("Idempotent-Replayed", "true")
+			c.Data(*existing.StatusCode, existing.ContentType, existing.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Store the outcome even if the client went away; that's when it's most likely to retry
+		ctx := context.WithoutCancel(c.Request.Context())
+		if status := recorder.Status(); status >= http.StatusInternalServerError {
+			err = store.Release(ctx, userID, key)
+		} else {
+			err = store.Complete(ctx, userID, key, status, recorder.Header().Get("Content-Type"), recorder.body.Bytes())
+		}
+		if err != nil {
+			log.Printf("Idempotency key failed to record the response: %v", err)
+		}
+	}
+}
+
+// spoolBody reads the request body, hashing the request by its method, URI and body as it
+// goes, and returns the hash and a copy of the body for the handler to read. Bodies over
+// idempotentBodyMemory are spooled to a temporary file, removed when the copy is closed.
+func spoolBody(w http.ResponseWriter, r *http.Request) (string, *spooledBody, error) {
+	h := sha256.New()
+	h.Write([]byte(r.Method + " " + r.URL.RequestURI() + "\n"))
+	reader := io.TeeReader(http.MaxBytesReader(w, r.Body, maxIdempotentBodySize), h)
+
+	head := &bytes.Buffer{}
+	if _, err := io.CopyN(head, reader, idempotentBodyMemory); err == io.EOF {
+		return hex.EncodeToString(h.Sum(nil)), &spooledBody{Reader: head}, nil
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	file, err := os.CreateTemp("", "fitapi-body-*")
+	if err != nil {
+		return "", nil, err
+	}
+	body := &spooledBody{Reader: io.MultiReader(head, file), file: file}
+	if _, err := io.Copy(file, reader); err != nil {
+		body.Close()
+		return "", nil, err
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		body.Close()
+		return "", nil, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), body, nil
+}
+
+// spooledBody is a request body read ahead of the handler
+type spooledBody struct {
+	io.Reader
+	file *os.File // Nil when the body fit in memory
+}
+
+// Close removes the temporary file, if any; closing again does nothing
+func (b *spooledBody) Close() error {
+	if b.file == nil {
+		return nil
+	}
+	file := b.file
+	b.file = nil
+	file.Close()
+	return os.Remove(file.Name())
+}
+
+// responseRecorder keeps a copy of the body written to the client
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// memoryIdempotencyStore keeps idempotency keys in memory
+type memoryIdempotencyStore struct {
+	mu      sync.Mutex
+	records map[string]*models.IdempotencyRecord
+}
+
+func (s *memoryIdempotencyStore) Reserve(ctx context.Context, userID string, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[userID+":"+key]; ok {
+		return record, nil
+	}
+	s.records[userID+":"+key] = &models.IdempotencyRecord{UserID: userID, Key: key, RequestHash: requestHash}
+	return nil, nil
+}
+
+func (s *memoryIdempotencyStore) Complete(ctx context.Context, userID string, key string, statusCode int, contentType string, body []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record := s.records[userID+":"+key]
+	record.StatusCode, record.ContentType, record.Body = &statusCode, contentType, body
+	return nil
+}
+
+func (s *memoryIdempotencyStore) Release(ctx context.Context, userID string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.records, userID+":"+key)
+	return nil
+}
+
+func TestIdempotency(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &memoryIdempotencyStore{records: make(map[string]*models.IdempotencyRecord)}
+	created := 0
+	status := http.StatusCreated
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if userID := c.GetHeader("X-Test-User"); userID != "" {
+			c.Set("user_id", userID)
+		}
+		c.Next()
+	}, Idempotency(store))
+	router.POST("/api/workouts", func(c *gin.Context) {
+		created++
+		c.JSON(status, gin.H{"created": created})
+	})
+
+	send := func(userID, key, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/workouts", strings.NewReader(body))
+		req.Header.Set("X-Test-User", userID)
+		if key != "" {
+			req.Header.Set(models.IdempotencyHeader, key)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := send("user-1", "key-1", `{"name":"Push"}`)
+	if first.Code != http.StatusCreated || first.Body.String() != `{"created":1}` {
+		t.Fatalf("Expected the first request to run, got %d %s", first.Code, first.Body.String())
+	}
+
+	retry := send("user-1", "key-1", `{"name":"Push"}`)
+	if retry.Code != http.StatusCreated || retry.Body.String() != `{"created":1}` {
+		t.Errorf("Expected the first response to be replayed, got %d %s", retry.Code, retry.Body.String())
+	}
+	if retry.Header().Get("Idempotent-Replayed") != "true" || !strings.HasPrefix(retry.Header().Get("Content-Type"), "application/json") {
+		t.Errorf("Expected a replayed JSON response, got headers %v", retry.Header())
+	}
+	if created != 1 {
+		t.Errorf("Expected the handler to run once, ran %d times", created)
+	}
+
+	if w := send("user-1", "key-1", `{"name":"Pull"}`); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected 422 for a key reused with another body, got %d", w.Code)
+	}
+
+	if w := send("user-2", "key-1", `{"name":"Push"}`); w.Code != http.StatusCreated || created != 2 {
+		t.Errorf("Expected keys to be scoped to the user, got %d after %d runs", w.Code, created)
+	}
+
+	if w := send("user-1", "", `{"name":"Push"}`); w.Code != http.StatusCreated || created != 3 {
+		t.Errorf("Expected requests without a key to run, got %d after %d runs", w.Code, created)
+	}
+
+	if w := send("user-1", strings.Repeat("k", 256), `{}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected 400 for an overlong key, got %d", w.Code)
+	}
+}
+
+func TestIdempotency_InProgressAndServerErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	store := &memoryIdempotencyStore{records: make(map[string]*models.IdempotencyRecord)}
+	hash, body, err := spoolBody(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(`{}`)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	body.Close()
+	store.records["user-1:running"] = &models.IdempotencyRecord{UserID: "user-1", Key: "running", RequestHash: hash}
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set("user_id", "user-1")
+		c.Next()
+	}, Idempotency(store))
+	router.POST("/api/sessions", func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create session"})
+	})
+
+	send := func(key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/api/sessions", strings.NewReader(`{}`))
+		req.Header.Set(models.IdempotencyHeader, key)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	w := send("running")
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Errorf("Expected 409 with Retry-After while the first request runs, got %d", w.Code)
+	}
+
+	if w := send("failing"); w.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected the handler's 500, got %d", w.Code)
+	}
+	if _, ok := store.records["user-1:failing"]; ok {
+		t.Error("Expected a server error to release the key")
+	}
+}
+
+func TestSpoolBody(t *testing.T) {
+	small := `{"name":"Push"}`
+	large := strings.Repeat("x", idempotentBodyMemory+10)
+
+	for _, content := range []string{small, large} {
+		req := httptest.NewRequest(http.MethodPost, "/api/import/strong", strings.NewReader(content))
+		hash, body, err := spoolBody(httptest.NewRecorder(), req)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		spooled := body.file
+		read, err := io.ReadAll(body)
+		if err != nil || string(read) != content {
+			t.Errorf("Expected the body replayed (%d bytes), got %d bytes, %v", len(content), len(read), err)
+		}
+		if (spooled != nil) != (content == large) {
+			t.Errorf("Expected only bodies over %d bytes spooled to a file", idempotentBodyMemory)
+		}
+		body.Close()
+		if spooled != nil {
+			if _, err := os.Stat(spooled.Name()); !os.IsNotExist(err) {
+				t.Errorf("Expected the spooled file removed, got %v", err)
+			}
+		}
+
+		again, body, _ := spoolBody(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/import/strong", strings.NewReader(content)))
+		body.Close()
+		if again != hash {
+			t.Error("Expected the same request to hash the same")
+		}
+	}
+}
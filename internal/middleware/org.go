@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/services"
+)
+
+// OrgHeader selects the organization a request acts within
+const OrgHeader = "X-Org-Id"
+
+// OrgMembershipFinder looks up a user's membership in an organization
+type OrgMembershipFinder interface {
+	GetMembership(ctx context.Context, orgID string, userID string) (*models.OrganizationMember, error)
+}
+
+// OrgContext is a middleware that resolves the X-Org-Id header
+// It must run after AuthRequired. When the header is present, the caller must be
+// a member of the organization; org_id and org_role are then stored in the Gin context.
+// Requests without the header continue in the personal (non-organization) scope.
+func OrgContext(memberships OrgMembershipFinder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orgID := c.GetHeader(OrgHeader)
+		if orgID == "" {
+			c.Next()
+			return
+		}
+
+		if _, err := uuid.Parse(orgID); err != nil {
+			c.JSON(400, gin.H{
+				"error": "invalid " + OrgHeader + " header, expected a UUID",
+			})
+			c.Abort()
+			return
+		}
+
+		member, err := memberships.GetMembership(c.Request.Context(), orgID, c.GetString("user_id"))
+		if err != nil {
+			if errors.Is(err, services.ErrOrganizationNotFound) {
+				c.JSON(403, gin.H{
+					"error": "you are not a member of this organization",
+				})
+				c.Abort()
+				return
+			}
+			c.JSON(500, gin.H{
+				"error": "failed to resolve organization",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("org_id", member.OrganizationID)
+		c.Set("org_role", member.Role)
+
+		c.Next()
+	}
+}
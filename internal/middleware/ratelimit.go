@@ -0,0 +1,401 @@
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/slo"
+)
+
+// RateLimitResult describes the outcome of a single rate limit check
+type RateLimitResult struct {
+	Allowed    bool
+	Limit      int
+	Remaining  int
+	RetryAfter time.Duration // Time until the next request would be allowed (zero when allowed)
+	Reset      time.Duration // Time until the bucket is full again
+}
+
+// RateLimiter decides whether the client identified by key may make another request
+// The in-memory TokenBucketLimiter suits a single instance; RedisTokenBucketLimiter keeps
+// the buckets in Redis so limits hold across instances.
+type RateLimiter interface {
+	// Allow takes a token from the key's bucket if one is available
+	Allow(ctx context.Context, key string) RateLimitResult
+	// Peek reports whether the key's bucket has a token, without taking it
+	Peek(ctx context.Context, key string) RateLimitResult
+}
+
+// TokenBucketLimiter is an in-memory token bucket limiter
+// Each key gets a bucket of `limit` tokens that refills continuously over one minute,
+// so clients may burst up to the limit and then sustain limit requests per minute.
+type TokenBucketLimiter struct {
+	limit      int
+	refillRate float64 // Tokens per second
+
+	mu        sync.Mutex
+	buckets   map[string]*tokenBucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+type tokenBucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// NewTokenBucketLimiter creates a limiter allowing perMinute requests per key
+// It returns nil when perMinute is not positive, which RateLimit treats as disabled.
+func NewTokenBucketLimiter(perMinute int) RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	return &TokenBucketLimiter{
+		limit:      perMinute,
+		refillRate: float64(perMinute) / 60,
+		buckets:    make(map[string]*tokenBucket),
+		now:        time.Now,
+	}
+}
+
+// Allow takes a token from the key's bucket if one is available
+func (l *TokenBucketLimiter) Allow(ctx context.Context, key string) RateLimitResult {
+	return l.take(key, 1)
+}
+
+// Peek reports whether the key's bucket has a token, without taking it
+func (l *TokenBucketLimiter) Peek(ctx context.Context, key string) RateLimitResult {
+	return l.take(key, 0)
+}
+
+// take refills the key's bucket and takes cost tokens from it if it has one
+func (l *TokenBucketLimiter) take(key string, cost int) RateLimitResult {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now)
+
+	bucket, ok := l.buckets[key]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(l.limit), updated: now}
+		l.buckets[key] = bucket
+	} else {
+		elapsed := now.Sub(bucket.updated).Seconds()
+		bucket.tokens = math.Min(float64(l.limit), bucket.tokens+elapsed*l.refillRate)
+		bucket.updated = now
+	}
+
+	allowed := bucket.tokens >= 1
+	if allowed {
+		bucket.tokens -= float64(cost)
+	}
+	return bucketResult(l.limit, l.refillRate, bucket.tokens, allowed)
+}
+
+// sweep drops buckets that have refilled completely, since they are
+// indistinguishable from a new bucket; it runs at most once a minute
+func (l *TokenBucketLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < time.Minute {
+		return
+	}
+	l.lastSweep = now
+
+	for key, bucket := range l.buckets {
+		if bucket.tokens+now.Sub(bucket.updated).Seconds()*l.refillRate >= float64(l.limit) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+// RedisScripter runs Lua scripts on a Redis server, as cache.Redis does
+type RedisScripter interface {
+	Eval(ctx context.Context, script string, keys []string, args ...string) (any, error)
+}
+
+// tokenBucketScript takes ARGV[3] tokens (one, or none to peek) from the bucket in KEYS[1]
+// holding ARGV[1] tokens and refilling ARGV[2] tokens per second, if it has one. It uses the server's clock, so instances with skewed
+// clocks agree, and expires buckets once full again. Replies "allowed tokens".
+const tokenBucketScript = `
+local limit = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local cost = tonumber(ARGV[3])
+local clock = redis.call('TIME')
+local now = tonumber(clock[1]) + tonumber(clock[2]) / 1000000
+local bucket = redis.call('HMGET', KEYS[1], 'tokens', 'updated')
+local tokens = tonumber(bucket[1]) or limit
+local updated = tonumber(bucket[2]) or now
+tokens = math.min(limit, tokens + math.max(0, now - updated) * rate)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - cost
+	allowed = 1
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'updated', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.ceil((limit - tokens) / rate * 1000) + 1000)
+return allowed .. ' ' .. tostring(tokens)
+`
+
+// RedisTokenBucketLimiter is a token bucket limiter like TokenBucketLimiter whose buckets
+// are kept in Redis, shared by every API instance. Buckets are updated atomically by a
+// script (Redis 5 or later). Requests are allowed while Redis is unavailable, so an outage
+// turns limiting off rather than failing every request; errors are logged.
+type RedisTokenBucketLimiter struct {
+	redis      RedisScripter
+	prefix     string
+	limit      int
+	refillRate float64 // Tokens per second
+}
+
+// NewRedisTokenBucketLimiter creates a limiter allowing perMinute requests per key, kept
+// under keys starting with prefix. It returns nil when perMinute is not positive.
+func NewRedisTokenBucketLimiter(redis RedisScripter, prefix string, perMinute int) RateLimiter {
+	if perMinute <= 0 {
+		return nil
+	}
+
+	return &RedisTokenBucketLimiter{
+		redis:      redis,
+		prefix:     prefix,
+		limit:      perMinute,
+		refillRate: float64(perMinute) / 60,
+	}
+}
+
+// Allow takes a token from the key's bucket if one is available
+func (l *RedisTokenBucketLimiter) Allow(ctx context.Context, key string) RateLimitResult {
+	return l.take(ctx, key, 1)
+}
+
+// Peek reports whether the key's bucket has a token, without taking it
+func (l *RedisTokenBucketLimiter) Peek(ctx context.Context, key string) RateLimitResult {
+	return l.take(ctx, key, 0)
+}
+
+func (l *RedisTokenBucketLimiter) take(ctx context.Context, key string, cost int) RateLimitResult {
+	reply, err := l.redis.Eval(ctx, tokenBucketScript, []string{l.prefix + key},
+		strconv.Itoa(l.limit), strconv.FormatFloat(l.refillRate, 'g', -1, 64), strconv.Itoa(cost))
+	if err == nil {
+		var allowed bool
+		var tokens float64
+		if allowed, tokens, err = parseBucketReply(reply); err == nil {
+			return bucketResult(l.limit, l.refillRate, tokens, allowed)
+		}
+	}
+
+	log.Printf("Rate limit check of %s failed, allowing the request: %v", key, err)
+	return RateLimitResult{Allowed: true, Limit: l.limit, Remaining: l.limit}
+}
+
+func parseBucketReply(reply any) (bool, float64, error) {
+	raw, ok := reply.([]byte)
+	if !ok {
+		return false, 0, fmt.Errorf("unexpected rate limit reply: %v", reply)
+	}
+	allowed, tokens, ok := strings.Cut(string(raw), " ")
+	value, err := strconv.ParseFloat(tokens, 64)
+	if !ok || err != nil || (allowed != "0" && allowed != "1") {
+		return false, 0, fmt.Errorf("invalid rate limit reply %q", raw)
+	}
+	return allowed == "1", value, nil
+}
+
+// bucketResult describes a bucket left with tokens after a request was allowed or not
+func bucketResult(limit int, refillRate float64, tokens float64, allowed bool) RateLimitResult {
+	result := RateLimitResult{Allowed: allowed, Limit: limit, Remaining: int(tokens)}
+	if !allowed {
+		result.RetryAfter = refillTime(1-tokens, refillRate)
+	}
+	result.Reset = refillTime(float64(limit)-tokens, refillRate)
+	return result
+}
+
+// refillTime returns how long it takes to refill the given number of tokens
+func refillTime(tokens float64, refillRate float64) time.Duration {
+	return time.Duration(tokens / refillRate * float64(time.Second))
+}
+
+// RateLimitRule is the requests per minute allowed in a route group per authenticated
+// user and per anonymous client IP; zero disables limiting for that kind of caller
+type RateLimitRule struct {
+	Group     string
+	User      int
+	Anonymous int
+}
+
+// ParseRateLimitRules reads rules written as "group=user:anonymous", comma separated, e.g.
+// "auth=30:5,exercises=240:60". Groups are route groups as for SLO targets: the first path
+// segment after /api.
+func ParseRateLimitRules(spec string) ([]RateLimitRule, error) {
+	var rules []RateLimitRule
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, rest, ok := strings.Cut(entry, "=")
+		user, anonymous, ok2 := strings.Cut(rest, ":")
+		if !ok || !ok2 || group == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, expected group=user:anonymous", entry)
+		}
+		if seen[group] {
+			return nil, fmt.Errorf("duplicate rate limit for %q", group)
+		}
+		seen[group] = true
+
+		u, err := strconv.Atoi(user)
+		if err != nil || u < 0 {
+			return nil, fmt.Errorf("invalid user rate limit %q for %q", user, group)
+		}
+		a, err := strconv.Atoi(anonymous)
+		if err != nil || a < 0 {
+			return nil, fmt.Errorf("invalid anonymous rate limit %q for %q", anonymous, group)
+		}
+		rules = append(rules, RateLimitRule{Group: group, User: u, Anonymous: a})
+	}
+	return rules, nil
+}
+
+// RateLimits holds the limiters of every route group. Groups with a rule of their own get
+// their own budget; the others share the default limiters, so a user's budget spans them.
+type RateLimits struct {
+	groups   map[string]rateLimiters
+	fallback rateLimiters
+}
+
+type rateLimiters struct {
+	users     RateLimiter
+	anonymous RateLimiter
+}
+
+// NewRateLimits creates the default limiters, allowing users and anonymous requests per
+// minute, and those of each rule. newLimiter picks the store, e.g. NewTokenBucketLimiter;
+// it returns nil to disable limiting.
+func NewRateLimits(users, anonymous int, rules []RateLimitRule, newLimiter func(perMinute int) RateLimiter) *RateLimits {
+	limits := &RateLimits{
+		groups:   make(map[string]rateLimiters, len(rules)),
+		fallback: rateLimiters{users: newLimiter(users), anonymous: newLimiter(anonymous)},
+	}
+	for _, rule := range rules {
+		limits.groups[rule.Group] = rateLimiters{users: newLimiter(rule.User), anonymous: newLimiter(rule.Anonymous)}
+	}
+	return limits
+}
+
+// RateLimitAnonymous is a middleware that limits requests per client IP until the caller is
+// authenticated. It must run before AuthRequired or OptionalAuth, and RateLimit after them.
+// Requests without an Authorization header take from the anonymous limiter of their route
+// group. Requests with one go on to authentication while the IP has a separate budget of
+// failed authentications left, and take from it when they fail with 401, so floods of
+// invalid tokens are limited at the anonymous rate without blocking signed-in users who
+// share an IP with anonymous visitors.
+// Responses carry X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset (seconds);
+// rejected requests get 429 with Retry-After. A nil limiter or RateLimits disables limiting.
+func RateLimitAnonymous(limits *RateLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limits == nil {
+			c.Next()
+			return
+		}
+
+		limiters, prefix := limits.route(c.FullPath())
+		limiter, ip := limiters.anonymous, c.ClientIP()
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		ctx := c.Request.Context()
+		if c.GetHeader("Authorization") == "" {
+			result := limiter.Allow(ctx, prefix+"ip:"+ip)
+			setRateLimitHeaders(c, result)
+			if !result.Allowed {
+				rejectRateLimited(c, result)
+				return
+			}
+			c.Next()
+			return
+		}
+
+		// Headers are left to RateLimit, which limits the caller once authenticated
+		key := prefix + "auth-failures:ip:" + ip
+		if result := limiter.Peek(ctx, key); !result.Allowed {
+			setRateLimitHeaders(c, result)
+			rejectRateLimited(c, result)
+			return
+		}
+		c.Next()
+		if c.Writer.Status() == http.StatusUnauthorized {
+			limiter.Allow(ctx, key)
+		}
+	}
+}
+
+// RateLimit is a middleware that limits requests per authenticated user
+// It must run after AuthRequired or OptionalAuth so user_id is available, with
+// RateLimitAnonymous before them for requests without one. Requests take from the users
+// limiter of their route group; service tokens are not limited. Headers and rejections are
+// as for RateLimitAnonymous.
+func RateLimit(limits *RateLimits) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID := c.GetString("user_id")
+		if limits == nil || userID == "" || IsService(c) {
+			c.Next()
+			return
+		}
+
+		limiters, prefix := limits.route(c.FullPath())
+		if limiters.users == nil {
+			c.Next()
+			return
+		}
+
+		result := limiters.users.Allow(c.Request.Context(), prefix+"user:"+userID)
+		setRateLimitHeaders(c, result)
+		if !result.Allowed {
+			rejectRateLimited(c, result)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// route returns the limiters of a matched route's group and the prefix of its keys;
+// groups without a rule of their own share the default limiters and keys
+func (l *RateLimits) route(fullPath string) (rateLimiters, string) {
+	group := slo.Group(fullPath)
+	if limiters, ok := l.groups[group]; ok {
+		return limiters, group + ":"
+	}
+	return l.fallback, ""
+}
+
+func setRateLimitHeaders(c *gin.Context, result RateLimitResult) {
+	c.Header("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	c.Header("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	c.Header("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+}
+
+func rejectRateLimited(c *gin.Context, result RateLimitResult) {
+	c.Header("Retry-After", strconv.Itoa(ceilSeconds(result.RetryAfter)))
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"error": "rate limit exceeded",
+	})
+	c.Abort()
+}
+
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
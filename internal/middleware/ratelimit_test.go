@@ -0,0 +1,226 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTokenBucketLimiter_BurstAndRefill(t *testing.T) {
+	limiter := NewTokenBucketLimiter(60).(*TokenBucketLimiter)
+	now := time.Now()
+	limiter.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	for i := 0; i < 60; i++ {
+		if !limiter.Allow(ctx, "user:1").Allowed {
+			t.Fatalf("Expected request %d to be allowed", i+1)
+		}
+	}
+
+	result := limiter.Allow(ctx, "user:1")
+	if result.Allowed {
+		t.Fatal("Expected request over the limit to be rejected")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("Expected retry after 1s, got %v", result.RetryAfter)
+	}
+
+	if !limiter.Allow(ctx, "user:2").Allowed {
+		t.Error("Expected other keys to have their own bucket")
+	}
+
+	now = now.Add(time.Second)
+	if !limiter.Allow(ctx, "user:1").Allowed {
+		t.Error("Expected a token to be refilled after one second")
+	}
+}
+
+func TestTokenBucketLimiter_Disabled(t *testing.T) {
+	if NewTokenBucketLimiter(0) != nil {
+		t.Error("Expected a zero limit to disable the limiter")
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// fakeAuth authenticates "Bearer <user>" and rejects "Bearer bad"
+	fakeAuth := func(c *gin.Context) {
+		switch header := c.GetHeader("Authorization"); header {
+		case "":
+		case "Bearer bad":
+			c.AbortWithStatus(http.StatusUnauthorized)
+			return
+		default:
+			c.Set("user_id", strings.TrimPrefix(header, "Bearer "))
+		}
+		c.Next()
+	}
+	limits := NewRateLimits(3, 2, nil, NewTokenBucketLimiter)
+
+	router := gin.New()
+	router.GET("/api/exercises", RateLimitAnonymous(limits), fakeAuth, RateLimit(limits), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(ip, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/exercises", nil)
+		req.RemoteAddr = ip + ":1234"
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	// Anonymous callers get the stricter limit
+	for i := 0; i < 2; i++ {
+		if w := send("10.0.0.1", ""); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+	w := send("10.0.0.1", "")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("Expected Retry-After header on 429")
+	}
+
+	// Authenticated users are limited per user, not per IP
+	for i := 0; i < 3; i++ {
+		if w := send("10.0.0.1", "user-123"); w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", w.Code)
+		}
+	}
+	w = send("10.0.0.1", "user-123")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", w.Code)
+	}
+	if w.Header().Get("X-RateLimit-Limit") != "3" || w.Header().Get("X-RateLimit-Remaining") != "0" {
+		t.Errorf("Unexpected rate limit headers: %v", w.Header())
+	}
+
+	// Invalid tokens count against the IP, which then can't try more
+	for i := 0; i < 2; i++ {
+		if w := send("10.0.0.2", "bad"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("Expected status 401, got %d", w.Code)
+		}
+	}
+	if w := send("10.0.0.2", "bad"); w.Code != http.StatusTooManyRequests {
+		t.Fatalf("Expected a flood of invalid tokens limited, got %d", w.Code)
+	}
+}
+
+func TestRateLimit_PerGroup(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rules, err := ParseRateLimitRules("exercises=10:2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	router := gin.New()
+	router.Use(RateLimitAnonymous(NewRateLimits(10, 1, rules, NewTokenBucketLimiter)))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/exercises", ok)
+	router.GET("/api/sessions", ok)
+	router.GET("/api/workouts", ok)
+
+	send := func(path string) int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	// Groups without a rule share the default budget
+	if code := send("/api/sessions"); code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if code := send("/api/workouts"); code != http.StatusTooManyRequests {
+		t.Fatalf("Expected the default budget to be shared, got %d", code)
+	}
+
+	// A group with a rule has a budget of its own
+	for i := 0; i < 2; i++ {
+		if code := send("/api/exercises"); code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", code)
+		}
+	}
+	if code := send("/api/exercises"); code != http.StatusTooManyRequests {
+		t.Fatalf("Expected status 429, got %d", code)
+	}
+}
+
+func TestParseRateLimitRules(t *testing.T) {
+	rules, err := ParseRateLimitRules(" auth=30:5, exercises=240:0 ")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []RateLimitRule{{Group: "auth", User: 30, Anonymous: 5}, {Group: "exercises", User: 240, Anonymous: 0}}
+	if len(rules) != len(want) || rules[0] != want[0] || rules[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, rules)
+	}
+
+	for _, spec := range []string{"auth", "auth=30", "=30:5", "auth=x:5", "auth=30:-1", "auth=1:1,auth=2:2"} {
+		if _, err := ParseRateLimitRules(spec); err == nil {
+			t.Errorf("Expected an error for %q", spec)
+		}
+	}
+}
+
+type fakeScripter struct {
+	reply any
+	err   error
+	keys  []string
+	args  []string
+}
+
+func (f *fakeScripter) Eval(ctx context.Context, script string, keys []string, args ...string) (any, error) {
+	f.keys, f.args = keys, args
+	return f.reply, f.err
+}
+
+func TestRedisTokenBucketLimiter(t *testing.T) {
+	redis := &fakeScripter{reply: []byte("0 0.25")}
+	limiter := NewRedisTokenBucketLimiter(redis, "fitapi:ratelimit:", 60)
+
+	result := limiter.Allow(context.Background(), "user:1")
+	if result.Allowed || result.Limit != 60 || result.Remaining != 0 {
+		t.Errorf("Expected a rejection with no tokens left, got %+v", result)
+	}
+	if result.RetryAfter != 750*time.Millisecond {
+		t.Errorf("Expected retry after 750ms, got %v", result.RetryAfter)
+	}
+	if len(redis.keys) != 1 || redis.keys[0] != "fitapi:ratelimit:user:1" {
+		t.Errorf("Unexpected keys %v", redis.keys)
+	}
+	if len(redis.args) != 3 || redis.args[0] != "60" || redis.args[1] != "1" || redis.args[2] != "1" {
+		t.Errorf("Unexpected args %v", redis.args)
+	}
+
+	redis.reply = []byte("1 42")
+	if result := limiter.Peek(context.Background(), "user:1"); !result.Allowed || redis.args[2] != "0" {
+		t.Errorf("Expected a peek to take no token, got %+v with args %v", result, redis.args)
+	}
+	if result := limiter.Allow(context.Background(), "user:1"); !result.Allowed || result.Remaining != 42 {
+		t.Errorf("Expected an allowed request with 42 tokens left, got %+v", result)
+	}
+
+	// An unavailable Redis does not reject requests
+	redis.err = errors.New("connection refused")
+	if !limiter.Allow(context.Background(), "user:1").Allowed {
+		t.Error("Expected requests to be allowed when Redis fails")
+	}
+
+	if NewRedisTokenBucketLimiter(redis, "fitapi:ratelimit:", 0) != nil {
+		t.Error("Expected a zero limit to disable the limiter")
+	}
+}
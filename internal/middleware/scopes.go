@@ -0,0 +1,69 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// TokenScopes extracts the scopes granted to a token
+// Scopes are read from the OAuth-style space-delimited "scope" claim or a "scopes" array.
+// The boolean is false when the token carries no scope claim at all: regular Supabase
+// user sessions have full account access, only tokens issued with scopes are restricted.
+func TokenScopes(claims jwt.MapClaims) ([]string, bool) {
+	if scope, ok := claims["scope"].(string); ok {
+		return strings.Fields(scope), true
+	}
+
+	if raw, ok := claims["scopes"].([]any); ok {
+		scopes := make([]string, 0, len(raw))
+		for _, item := range raw {
+			if scope, ok := item.(string); ok {
+				scopes = append(scopes, scope)
+			}
+		}
+		return scopes, true
+	}
+
+	return nil, false
+}
+
+// RequireScopes is a middleware for a route group that enforces least-privilege tokens
+// Safe methods (GET, HEAD, OPTIONS) need "read:<resource>"; everything else needs
+// "write:<resource>". A write scope also grants read access. Tokens without any scope
+// claim (full user sessions, SKIP_AUTH) pass through unchanged.
+func RequireScopes(resource string) gin.HandlerFunc {
+	readScope := "read:" + resource
+	writeScope := "write:" + resource
+
+	return func(c *gin.Context) {
+		value, scoped := c.Get("token_scopes")
+		if !scoped {
+			c.Next()
+			return
+		}
+		scopes, _ := value.([]string)
+
+		required := writeScope
+		allowed := slices.Contains(scopes, writeScope)
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			required = readScope
+			allowed = allowed || slices.Contains(scopes, readScope)
+		}
+
+		if !allowed {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":          "insufficient scope",
+				"required_scope": required,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestTokenScopes(t *testing.T) {
+	tests := []struct {
+		name       string
+		claims     jwt.MapClaims
+		wantScopes []string
+		wantScoped bool
+	}{
+		{"no scope claim", jwt.MapClaims{"sub": "user-123"}, nil, false},
+		{"space delimited", jwt.MapClaims{"scope": "read:sessions write:workouts"}, []string{"read:sessions", "write:workouts"}, true},
+		{"array claim", jwt.MapClaims{"scopes": []any{"read:equipment"}}, []string{"read:equipment"}, true},
+		{"empty scope", jwt.MapClaims{"scope": ""}, []string{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scopes, scoped := TokenScopes(tt.claims)
+
+			if scoped != tt.wantScoped {
+				t.Fatalf("Expected scoped=%v, got %v", tt.wantScoped, scoped)
+			}
+			if len(scopes) != len(tt.wantScopes) {
+				t.Fatalf("Expected scopes %v, got %v", tt.wantScopes, scopes)
+			}
+			for i := range scopes {
+				if scopes[i] != tt.wantScopes[i] {
+					t.Errorf("Expected scopes %v, got %v", tt.wantScopes, scopes)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireScopes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		method     string
+		scopes     []string
+		scoped     bool
+		wantStatus int
+	}{
+		{"unscoped token has full access", http.MethodDelete, nil, false, http.StatusOK},
+		{"read scope allows GET", http.MethodGet, []string{"read:equipment"}, true, http.StatusOK},
+		{"read scope denies POST", http.MethodPost, []string{"read:equipment"}, true, http.StatusForbidden},
+		{"write scope allows GET", http.MethodGet, []string{"write:equipment"}, true, http.StatusOK},
+		{"write scope allows PUT", http.MethodPut, []string{"write:equipment"}, true, http.StatusOK},
+		{"other resource denied", http.MethodGet, []string{"read:sessions"}, true, http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				if tt.scoped {
+					c.Set("token_scopes", tt.scopes)
+				}
+			})
+			router.Handle(tt.method, "/equipment", RequireScopes("equipment"), func(c *gin.Context) {
+				c.Status(http.StatusOK)
+			})
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, "/equipment", nil))
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("Expected status %d, got %d", tt.wantStatus, w.Code)
+			}
+		})
+	}
+}
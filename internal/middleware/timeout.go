@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// timeoutBody replaces the handler's error when a request runs out of time
+const timeoutBody = `{"error":"request timed out"}`
+
+// Timeout is a middleware that gives each request a deadline
+// Repositories pass the request context to every query, so a request past its deadline, or
+// one the client abandoned, stops holding a pool connection. A handler that fails because
+// its deadline passed answers 504 Gateway Timeout instead of 500. Event streams and websocket
+// upgrades are long-lived and have no deadline, nor do the streamed downloads routed at the
+// exempt paths, which would otherwise be cut off midway once their headers are sent.
+// A zero timeout disables the middleware.
+func Timeout(timeout time.Duration, exempt ...string) gin.HandlerFunc {
+	exemptPaths := make(map[string]bool, len(exempt))
+	for _, path := range exempt {
+		exemptPaths[path] = true
+	}
+
+	return func(c *gin.Context) {
+		if timeout <= 0 || isLongLived(c.Request) || exemptPaths[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Writer = &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Next()
+	}
+}
+
+// isLongLived reports whether a request opens an event stream or a websocket
+func isLongLived(r *http.Request) bool {
+	return strings.Contains(r.Header.Get("Accept"), "text/event-stream") ||
+		strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// timeoutWriter turns server errors written after the deadline into 504s
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx      context.Context
+	timedOut bool
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if code >= http.StatusInternalServerError && errors.Is(w.ctx.Err(), context.DeadlineExceeded) {
+		w.timedOut = true
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		code = http.StatusGatewayTimeout
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *timeoutWriter) Write(b []byte) (int, error) {
+	if w.timedOut {
+		return len(b), w.writeTimeout()
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if w.timedOut {
+		return len(s), w.writeTimeout()
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+// writeTimeout writes the timeout error in place of the handler's body, once
+func (w *timeoutWriter) writeTimeout() error {
+	if w.ResponseWriter.Size() > 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.WriteString(timeoutBody)
+	return err
+}
@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(Timeout(10*time.Millisecond, "/api/export/:id/download"))
+	router.GET("/api/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workouts"})
+	})
+	router.GET("/api/fast", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); !ok {
+			t.Error("Expected the request context to have a deadline")
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to list workouts"})
+	})
+	router.GET("/api/events", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			t.Error("Expected event streams to have no deadline")
+		}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/api/export/:id/download", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			t.Error("Expected exempt downloads to have no deadline")
+		}
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/slow", nil))
+	if w.Code != http.StatusGatewayTimeout || w.Body.String() != timeoutBody {
+		t.Errorf("Expected 504 with the timeout error, got %d %s", w.Code, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/fast", nil))
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("Expected errors before the deadline to be left alone, got %d", w.Code)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/events", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/export/123/download", nil))
+	if w.Code != http.StatusOK {
+		t.Errorf("Expected 200, got %d", w.Code)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UUIDParams is a middleware that rejects requests whose named path parameters aren't UUIDs
+// It answers 400 before a handler passes the value to a query, where PostgreSQL would fail
+// to cast it and the request would end in a 500. Routes without the parameters are
+// unaffected. Only the canonical 36-character form is accepted.
+func UUIDParams(names ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var fields []gin.H
+		for _, name := range names {
+			value, ok := c.Params.Get(name)
+			if ok && !isUUID(value) {
+				fields = append(fields, gin.H{
+					"field":   name,
+					"rule":    "uuid",
+					"message": name + " must be a UUID",
+				})
+			}
+		}
+
+		if len(fields) > 0 {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":  "invalid path parameter",
+				"fields": fields,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 {
+		return false
+	}
+	_, err := uuid.Parse(s)
+	return err == nil
+}
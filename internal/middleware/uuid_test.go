@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestUUIDParams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(UUIDParams("id", "user_id"))
+	router.GET("/api/orgs/:id/members/:user_id", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	router.GET("/api/import/:source", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		path   string
+		status int
+	}{
+		{"/api/orgs/6f1c8e2a-3b4d-4e5f-8a9b-0c1d2e3f4a5b/members/0b7d9c1e-2f3a-4b5c-9d6e-7f8a9b0c1d2e", http.StatusOK},
+		{"/api/orgs/not-a-uuid/members/0b7d9c1e-2f3a-4b5c-9d6e-7f8a9b0c1d2e", http.StatusBadRequest},
+		{"/api/orgs/6f1c8e2a3b4d4e5f8a9b0c1d2e3f4a5b/members/0b7d9c1e-2f3a-4b5c-9d6e-7f8a9b0c1d2e", http.StatusBadRequest},
+		{"/api/orgs/6f1c8e2a-3b4d-4e5f-8a9b-0c1d2e3f4a5b/members/1'--", http.StatusBadRequest},
+		{"/api/import/strong", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Code != tt.status {
+			t.Errorf("%s: expected %d, got %d", tt.path, tt.status, w.Code)
+		}
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/orgs/abc/members/def", nil))
+	if body := w.Body.String(); !strings.Contains(body, `"field":"id"`) || !strings.Contains(body, `"field":"user_id"`) {
+		t.Errorf("Expected both parameters to be reported, got %s", body)
+	}
+}
@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebSocketTokenPrefix marks the subprotocol carrying the access token in a WebSocket handshake
+const WebSocketTokenPrefix = "bearer."
+
+// WebSocketToken lets browser WebSocket clients, which cannot set an Authorization header,
+// authenticate by offering "bearer.<token>" among their subprotocols. It must run before
+// AuthRequired; a request that already has an Authorization header is left unchanged.
+// Keeping the token out of the query string keeps it out of access logs.
+func WebSocketToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			for _, protocol := range strings.Split(c.GetHeader("Sec-WebSocket-Protocol"), ",") {
+				token, ok := strings.CutPrefix(strings.TrimSpace(protocol), WebSocketTokenPrefix)
+				if ok && token != "" {
+					c.Request.Header.Set("Authorization", "Bearer "+token)
+					break
+				}
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestWebSocketToken_AuthenticatesFromSubprotocol(t *testing.T) {
+	token := signTestToken(t, jwt.MapClaims{
+		"sub": "6b37ab1f-b190-4072-9e50-5318d4bad35d",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+
+	header := http.Header{"Sec-Websocket-Protocol": {"fitapi.v1, " + WebSocketTokenPrefix + token}}
+	w, got := runAuthWith(t, fakeRevocations{}, header, WebSocketToken())
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got["user_id"] != "6b37ab1f-b190-4072-9e50-5318d4bad35d" {
+		t.Errorf("Expected user from subprotocol token, got %v", got["user_id"])
+	}
+}
+
+func TestWebSocketToken_AuthorizationHeaderWins(t *testing.T) {
+	header := http.Header{
+		"Authorization":          {"Bearer not-a-token"},
+		"Sec-Websocket-Protocol": {WebSocketTokenPrefix + "also-not-a-token"},
+	}
+	w, _ := runAuthWith(t, fakeRevocations{}, header, WebSocketToken())
+
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401, got %d", w.Code)
+	}
+
+	w, _ = runAuthWith(t, fakeRevocations{}, http.Header{"Sec-Websocket-Protocol": {"fitapi.v1"}}, WebSocketToken())
+	if w.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status 401 without a token, got %d", w.Code)
+	}
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// MuscleVolumeWeek is the volume a user lifted for one muscle group in one week
+type MuscleVolumeWeek struct {
+	Week        string  `json:"week"`         // Monday, YYYY-MM-DD (UTC)
+	MuscleGroup string  `json:"muscle_group"` // "other" for unclassified exercises
+	VolumeKg    float64 `json:"volume_kg"`    // Sets x reps x weight
+	Sets        int     `json:"sets"`
+}
+
+// MuscleVolume is a user's weekly volume per muscle group over a date range; weeks and
+// muscle groups without volume are omitted
+type MuscleVolume struct {
+	From  string              `json:"from"`
+	To    string              `json:"to"`
+	Weeks []*MuscleVolumeWeek `json:"weeks"`
+}
+
+// E1RMBest is a user's best estimated one-rep max (Epley) on an exercise
+type E1RMBest struct {
+	ExerciseID   string    `json:"exercise_id"`
+	ExerciseName string    `json:"exercise_name"`
+	E1RMKg       float64   `json:"e1rm_kg"`
+	WeightKg     float64   `json:"weight_kg"` // The set the estimate came from
+	Reps         int       `json:"reps"`
+	AchievedAt   time.Time `json:"achieved_at"`
+}
+
+// DailySummary is what a user did on one day of their timezone: completed sessions and
+// the water they drank
+type DailySummary struct {
+	Date      string             `json:"date"` // YYYY-MM-DD
+	Sessions  int                `json:"sessions"`
+	Minutes   int                `json:"minutes"`
+	VolumeKg  float64            `json:"volume_kg"` // Sets x reps x weight
+	Hydration *HydrationProgress `json:"hydration"`
+}
+
+// SleepSession is a completed session with the sleep that ended in the hours before it
+type SleepSession struct {
+	SessionID      string    `json:"session_id"`
+	WorkoutID      *string   `json:"workout_id,omitempty"`
+	StartedAt      time.Time `json:"started_at"`
+	SleepMinutes   *int      `json:"sleep_minutes"` // Unset when no sleep was logged
+	SleepQuality   *int      `json:"sleep_quality,omitempty"`
+	VolumeKg       float64   `json:"volume_kg"`
+	RelativeVolume float64   `json:"relative_volume"` // Against sessions of the same workout, 1 is average
+	RPE            *float64  `json:"rpe,omitempty"`   // Perceived exertion, else the average set RPE
+}
+
+// SleepBucket averages the sessions after a similar amount of sleep
+type SleepBucket struct {
+	Sleep             string   `json:"sleep"` // "<6h", "6-7h", "7-8h" or "8h+"
+	Sessions          int      `json:"sessions"`
+	AvgRelativeVolume float64  `json:"avg_relative_volume"`
+	AvgRPE            *float64 `json:"avg_rpe,omitempty"`
+}
+
+// SleepPerformance relates a user's sleep to how their next sessions went over a date
+// range. Correlations are Pearson coefficients from -1 to 1, unset without enough sessions.
+type SleepPerformance struct {
+	From                   string          `json:"from"`
+	To                     string          `json:"to"`
+	Sessions               []*SleepSession `json:"sessions"` // Only those with sleep logged
+	SessionsWithoutSleep   int             `json:"sessions_without_sleep"`
+	Buckets                []*SleepBucket  `json:"buckets"`
+	SleepVolumeCorrelation *float64        `json:"sleep_volume_correlation"`
+	SleepRPECorrelation    *float64        `json:"sleep_rpe_correlation"`
+}
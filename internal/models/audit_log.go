@@ -0,0 +1,60 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Audited actions
+const (
+	AuditActionCreate = "create"
+	AuditActionUpdate = "update"
+	AuditActionDelete = "delete"
+)
+
+// Audited resource types
+const (
+	AuditResourceAccountExport      = "account_export"
+	AuditResourceCoachClient        = "coach_client"
+	AuditResourceDevice             = "device"
+	AuditResourceEquipment          = "equipment"
+	AuditResourceExercise           = "exercise"
+	AuditResourceFeatureFlag        = "feature_flag"
+	AuditResourceFeatureFlagUser    = "feature_flag_user" // ID "<flag name>/<user_id>"
+	AuditResourceGoal               = "goal"
+	AuditResourceInjury             = "injury"
+	AuditResourceOrganization       = "organization"
+	AuditResourceOrganizationMember = "organization_member" // ID "<organization_id>/<user_id>"
+	AuditResourceSession            = "session"
+	AuditResourceSessionMedia       = "session_media"
+	AuditResourceWebhookEndpoint    = "webhook_endpoint"
+	AuditResourceWorkout            = "workout"
+)
+
+// AuditLog records who did what to which resource
+type AuditLog struct {
+	ID           string                  `json:"id"`
+	ActorID      *string                 `json:"actor_id,omitempty"` // Unset for service tokens
+	Action       string                  `json:"action"`
+	ResourceType string                  `json:"resource_type"`
+	ResourceID   string                  `json:"resource_id"`
+	Changes      map[string]*AuditChange `json:"changes"` // By field name
+	CreatedAt    time.Time               `json:"created_at"`
+}
+
+// AuditChange is a field's value before and after a write; Before is null for creations
+// and After for deletions
+type AuditChange struct {
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// AuditLogFilter selects audit log entries, newest first
+type AuditLogFilter struct {
+	ActorID      string // Empty for every actor
+	ResourceType string
+	ResourceID   string
+	From         *time.Time // Inclusive
+	Until        *time.Time // Exclusive
+	Limit        int
+}
@@ -0,0 +1,12 @@
+package models
+
+// CacheStats counts the lookups of a cache namespace, e.g. exercises or analytics, on
+// one API instance since it started
+type CacheStats struct {
+	Namespace     string  `json:"namespace"`
+	Hits          int64   `json:"hits"`
+	Misses        int64   `json:"misses"`
+	HitRate       float64 `json:"hit_rate"` // Hits over lookups, 0 before any
+	Invalidations int64   `json:"invalidations"`
+	Errors        int64   `json:"errors"` // Failed reads and writes, served from the database instead
+}
@@ -0,0 +1,81 @@
+package models
+
+import "time"
+
+// ChallengeDateLayout is the layout of challenge and completion days
+const ChallengeDateLayout = "2006-01-02"
+
+// Challenge metrics, totals computed from participants' completed sessions
+const (
+	ChallengeMetricVolume   = "volume_kg" // Weight times reps
+	ChallengeMetricReps     = "reps"
+	ChallengeMetricSessions = "sessions"
+)
+
+// Challenge is a time-boxed focus program users join and complete day by day, or by
+// reaching a goal computed from their logs over its dates
+type Challenge struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	DailyTarget *int      `json:"daily_target,omitempty"`
+	Unit        *string   `json:"unit,omitempty"`
+	Metric      *string   `json:"metric,omitempty"`     // Set for challenges with a goal
+	GoalValue   *float64  `json:"goal_value,omitempty"` // Total of metric to reach
+	StartsOn    time.Time `json:"starts_on"`
+	EndsOn      time.Time `json:"ends_on"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Days returns the number of days the challenge runs, inclusive of both ends
+func (c *Challenge) Days() int {
+	return int(c.EndsOn.Sub(c.StartsOn).Hours()/24) + 1
+}
+
+// ChallengeParticipant records a user's participation in a challenge
+type ChallengeParticipant struct {
+	ChallengeID string     `json:"challenge_id"`
+	UserID      string     `json:"user_id"`
+	JoinedAt    time.Time  `json:"joined_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// ChallengeCompletion marks one day of a challenge as done
+type ChallengeCompletion struct {
+	ChallengeID string    `json:"challenge_id"`
+	UserID      string    `json:"user_id"`
+	Day         time.Time `json:"day"`
+	Value       *int      `json:"value,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// ChallengeStanding is a participant's position on a challenge leaderboard
+type ChallengeStanding struct {
+	Rank          int        `json:"rank"`
+	UserID        string     `json:"user_id"`
+	DaysCompleted int        `json:"days_completed"`
+	TotalValue    int64      `json:"total_value"`
+	Progress      *float64   `json:"progress,omitempty"` // Toward the goal, for challenges with one
+	CompletedAt   *time.Time `json:"completed_at,omitempty"`
+}
+
+// CreateChallengeRequest represents the request body for creating a challenge
+type CreateChallengeRequest struct {
+	Name        string   `json:"name" binding:"required,max=100"`
+	Description string   `json:"description" binding:"max=500"`
+	DailyTarget *int     `json:"daily_target" binding:"omitempty,min=1"`
+	Unit        *string  `json:"unit" binding:"omitempty,max=30"`
+	Metric      *string  `json:"metric" binding:"omitempty,oneof=volume_kg reps sessions"`
+	GoalValue   *float64 `json:"goal_value" binding:"omitempty,gt=0"`
+	StartsOn    string   `json:"starts_on" binding:"required,datetime=2006-01-02"`
+	EndsOn      string   `json:"ends_on" binding:"required,datetime=2006-01-02"`
+}
+
+// CompleteChallengeDayRequest represents the request body for checking off a day
+// Day defaults to today (UTC) when omitted.
+type CompleteChallengeDayRequest struct {
+	Day   string `json:"day" binding:"omitempty,datetime=2006-01-02"`
+	Value *int   `json:"value" binding:"omitempty,min=0"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// ClassSession is a group workout an organization's trainer schedules for members to join
+type ClassSession struct {
+	ID             string    `json:"id"`
+	OrganizationID string    `json:"organization_id"`
+	TrainerID      string    `json:"trainer_id"`
+	WorkoutID      string    `json:"workout_id"`
+	Name           string    `json:"name"`
+	StartsAt       time.Time `json:"starts_at"`
+	Capacity       *int      `json:"capacity,omitempty"` // No limit when omitted
+	Participants   int       `json:"participants"`
+	Joined         bool      `json:"joined"` // Whether the caller joined
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// ClassParticipant is a member who joined a class, with their own session of it
+type ClassParticipant struct {
+	ClassSessionID string     `json:"class_session_id"`
+	UserID         string     `json:"user_id"`
+	SessionID      string     `json:"session_id"`
+	Status         string     `json:"status"` // Of the member's session
+	JoinedAt       time.Time  `json:"joined_at"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+}
+
+// ClassAttendance is who joined a class and how far they got
+type ClassAttendance struct {
+	ClassSessionID string              `json:"class_session_id"`
+	Joined         int                 `json:"joined"`
+	Attended       int                 `json:"attended"` // Started or completed their session
+	Completed      int                 `json:"completed"`
+	Participants   []*ClassParticipant `json:"participants"`
+}
+
+// CreateClassSessionRequest represents the request body for scheduling a class
+type CreateClassSessionRequest struct {
+	WorkoutID string    `json:"workout_id" binding:"required,uuid"` // Must be shared in the organization
+	Name      string    `json:"name" binding:"required,max=100"`
+	StartsAt  time.Time `json:"starts_at" binding:"required"`
+	Capacity  *int      `json:"capacity" binding:"omitempty,min=1,max=1000"`
+}
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// Coach-client relationship statuses
+const (
+	CoachClientStatusPending  = "pending"
+	CoachClientStatusActive   = "active"
+	CoachClientStatusDeclined = "declined"
+	CoachClientStatusRevoked  = "revoked"
+)
+
+// CoachClient links a coach to a client who has granted delegated access
+type CoachClient struct {
+	ID          string     `json:"id"`
+	CoachID     string     `json:"coach_id"`
+	ClientID    *string    `json:"client_id,omitempty"`
+	ClientEmail string     `json:"client_email"`
+	Status      string     `json:"status"`
+	CanWrite    bool       `json:"can_write"`
+	AcceptedAt  *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CreateCoachInvitationRequest represents the request body for inviting a client
+type CreateCoachInvitationRequest struct {
+	ClientEmail string `json:"client_email" binding:"required,email,max=254"`
+	CanWrite    bool   `json:"can_write"`
+}
+
+// UpdateCoachPermissionsRequest represents the request body for changing delegated permissions
+type UpdateCoachPermissionsRequest struct {
+	CanWrite *bool `json:"can_write" binding:"required"`
+}
@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Statuses of a program's scheduled workouts
+const (
+	ProgramWorkoutPending   = "pending"   // Today or later, not done yet
+	ProgramWorkoutCompleted = "completed" // Done as prescribed
+	ProgramWorkoutModified  = "modified"  // Done with swapped, skipped or different exercises
+	ProgramWorkoutSkipped   = "skipped"   // Skipped by the client
+	ProgramWorkoutMissed    = "missed"    // Its day passed without it being done
+)
+
+// CoachProgram is a set of workouts a coach scheduled for a client
+type CoachProgram struct {
+	ID        string            `json:"id"`
+	CoachID   string            `json:"coach_id"`
+	ClientID  string            `json:"client_id"`
+	Name      string            `json:"name"`
+	Notes     *string           `json:"notes,omitempty"`
+	Workouts  []*ProgramWorkout `json:"workouts"`
+	CreatedAt time.Time         `json:"created_at"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+// ProgramWorkout is a workout of a program scheduled on a day, in the client's timezone
+type ProgramWorkout struct {
+	ID          string     `json:"id"`
+	ProgramID   string     `json:"program_id"`
+	WorkoutID   string     `json:"workout_id"`
+	WorkoutName string     `json:"workout_name"`
+	ScheduledOn time.Time  `json:"scheduled_on"`
+	Status      string     `json:"status"`
+	SessionID   *string    `json:"session_id,omitempty"` // The completed session done for it
+	SkippedAt   *time.Time `json:"skipped_at,omitempty"`
+	SkipReason  *string    `json:"skip_reason,omitempty"`
+}
+
+// ComplianceReport is how closely a client followed their programs in a week
+type ComplianceReport struct {
+	ClientID  string    `json:"client_id"`
+	WeekStart time.Time `json:"week_start"` // Monday
+	Scheduled int       `json:"scheduled"`
+	Completed int       `json:"completed"`
+	Modified  int       `json:"modified"`
+	Skipped   int       `json:"skipped"`
+	Missed    int       `json:"missed"`
+	Pending   int       `json:"pending"`
+	// Percentage of the workouts due so far (all but pending ones) that were done, with or
+	// without changes; omitted while none are due
+	Compliance *float64          `json:"compliance_percent,omitempty"`
+	Workouts   []*ProgramWorkout `json:"workouts"`
+}
+
+// CreateCoachProgramRequest represents the request body for assigning a program to a client
+type CreateCoachProgramRequest struct {
+	Name     string                          `json:"name" binding:"required,max=100"`
+	Notes    *string                         `json:"notes" binding:"omitempty,max=1000"`
+	Workouts []ScheduleProgramWorkoutRequest `json:"workouts" binding:"required,min=1,max=100,dive"`
+}
+
+// ScheduleProgramWorkoutRequest schedules one workout of a program
+type ScheduleProgramWorkoutRequest struct {
+	WorkoutID   string `json:"workout_id" binding:"required,uuid"` // The coach's or the client's own workout
+	ScheduledOn string `json:"scheduled_on" binding:"required,datetime=2006-01-02"`
+}
+
+// SkipProgramWorkoutRequest represents the request body for skipping a scheduled workout
+type SkipProgramWorkoutRequest struct {
+	Reason *string `json:"reason" binding:"omitempty,max=500"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// Resources comments can be left on
+const (
+	CommentResourceWorkout = "workout"
+	CommentResourceSession = "session"
+)
+
+// Comment is a user's comment on a workout or session
+type Comment struct {
+	ID           string    `json:"id"`
+	ResourceType string    `json:"resource_type"`
+	ResourceID   string    `json:"resource_id"`
+	UserID       string    `json:"user_id"` // Author
+	Body         string    `json:"body"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// CommentTarget is who a commented resource belongs to
+type CommentTarget struct {
+	OwnerID        string
+	OrganizationID *string // Organization workouts
+}
+
+// CreateCommentRequest represents the request body for commenting
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=2000"`
+}
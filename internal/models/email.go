@@ -0,0 +1,28 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EmailPreferences are the recurring emails a user opted into
+type EmailPreferences struct {
+	UserID           string    `json:"user_id"`
+	WorkoutReminders bool      `json:"workout_reminders"`
+	WeeklySummary    bool      `json:"weekly_summary"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// UpdateEmailPreferencesRequest is the payload for changing email preferences; omitted
+// fields keep their value
+type UpdateEmailPreferencesRequest struct {
+	WorkoutReminders *bool `json:"workout_reminders"`
+	WeeklySummary    *bool `json:"weekly_summary"`
+}
+
+// EmailJob is the payload of an email job, rendered from its template when sent
+type EmailJob struct {
+	To       string          `json:"to"`
+	Template string          `json:"template"`
+	Data     json.RawMessage `json:"data"`
+}
@@ -4,12 +4,14 @@ import "time"
 
 // Equipment represents gym equipment that can be associated with exercises
 type Equipment struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	UserID      string    `json:"user_id"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	UserID         string    `json:"user_id"`
+	OrganizationID *string   `json:"organization_id,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"` // Bumped by every update; updates must send the version they read
 }
 
 // CreateEquipmentRequest represents the request body for creating equipment
@@ -22,4 +24,5 @@ type CreateEquipmentRequest struct {
 type UpdateEquipmentRequest struct {
 	Name        string `json:"name" binding:"required,min=1,max=100"`
 	Description string `json:"description" binding:"max=500"`
+	Version     int64  `json:"version" binding:"required,min=1"` // Version the edit is based on
 }
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Exercise represents an exercise definition, either private to its owner or public in the shared library
+type Exercise struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	IsPublic    bool      `json:"is_public"`
+	UserID      string    `json:"user_id"`
+	ImageURL    *string   `json:"image_url,omitempty"`
+	MuscleGroup *string   `json:"muscle_group,omitempty"` // Main muscle group trained, e.g. "chest"; nil if unclassified
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+	Version     int64     `json:"version"` // Bumped by every update
+
+	// Contraindications are the injured body parts the exercise loads, for signed-in users
+	// with an active injury
+	Contraindications []string `json:"contraindications,omitempty"`
+}
+
+// ExerciseSearchResult holds fuzzy name search matches
+// DidYouMean is set to the closest exercise name when nothing cleared the match threshold
+type ExerciseSearchResult struct {
+	Results    []*Exercise `json:"results"`
+	DidYouMean *string     `json:"did_you_mean,omitempty"`
+}
+
+// MergeExercisesRequest represents the request body for consolidating duplicate exercises
+// Every source is folded into the target and then deleted.
+type MergeExercisesRequest struct {
+	TargetID  string   `json:"target_id" binding:"required,uuid"`
+	SourceIDs []string `json:"source_ids" binding:"required,min=1,max=20,dive,uuid"`
+}
+
+// ExerciseMergeResult reports what a merge re-pointed to the target exercise
+type ExerciseMergeResult struct {
+	Exercise               *Exercise `json:"exercise"`
+	MergedCount            int       `json:"merged_count"`
+	ExerciseLogsMoved      int64     `json:"exercise_logs_moved"`
+	WorkoutExercisesMoved  int64     `json:"workout_exercises_moved"`
+	PersonalRecordsUpdated int64     `json:"personal_records_updated"`
+}
+
+// ExerciseDuplicate pairs two of a user's exercises whose names look alike
+type ExerciseDuplicate struct {
+	Exercise   *Exercise `json:"exercise"`
+	Candidate  *Exercise `json:"candidate"`
+	Similarity float64   `json:"similarity"`
+}
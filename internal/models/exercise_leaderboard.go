@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// Exercise leaderboard metrics
+const (
+	LeaderboardMetricE1RM   = "e1rm"    // Best Epley estimated 1RM
+	LeaderboardMetricTopSet = "top_set" // Heaviest set
+)
+
+// ExerciseLeaderboard ranks a user and the people they follow on an exercise
+type ExerciseLeaderboard struct {
+	ExerciseID string                      `json:"exercise_id"`
+	Metric     string                      `json:"metric"`
+	Relative   bool                        `json:"relative"` // Ranked by value per kg of body weight
+	Entries    []*ExerciseLeaderboardEntry `json:"entries"`
+}
+
+// ExerciseLeaderboardEntry is one person's best set of an exercise
+type ExerciseLeaderboardEntry struct {
+	Rank       int       `json:"rank"`
+	UserID     string    `json:"user_id"`
+	Username   *string   `json:"username,omitempty"`
+	Value      float64   `json:"value"` // The metric, divided by body weight when relative
+	WeightKg   float64   `json:"weight_kg"`
+	Reps       int       `json:"reps"`
+	AchievedAt time.Time `json:"achieved_at"`
+}
@@ -0,0 +1,73 @@
+package models
+
+import "time"
+
+// SwapCandidate is a planned exercise of a session that may be swapped, with its prescription
+type SwapCandidate struct {
+	SessionID           string
+	UserID              string
+	SessionStatus       string
+	ExerciseLogID       string
+	ExerciseID          string
+	SetsCompleted       int
+	SetsPlanned         *int
+	RepsPlanned         *int
+	WeightPlannedKg     *float64 // From the log, or the template entry it was planned from
+	IntensityPercentage *float64
+}
+
+// ExerciseSwap records an exercise replaced during a session
+type ExerciseSwap struct {
+	ID             string    `json:"id"`
+	SessionID      string    `json:"session_id"`
+	ExerciseLogID  string    `json:"exercise_log_id"`
+	UserID         string    `json:"user_id"`
+	FromExerciseID *string   `json:"from_exercise_id"`
+	ToExerciseID   *string   `json:"to_exercise_id"`
+	Reason         *string   `json:"reason,omitempty"`
+	StrengthRatio  *float64  `json:"strength_ratio,omitempty"`
+	FromWeightKg   *float64  `json:"from_weight_kg,omitempty"`
+	ToWeightKg     *float64  `json:"to_weight_kg,omitempty"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// ExerciseSwapResult is the swap together with the prescription carried over to the substitute
+type ExerciseSwapResult struct {
+	Swap                *ExerciseSwap `json:"swap"`
+	SetsPlanned         *int          `json:"sets_planned,omitempty"`
+	RepsPlanned         *int          `json:"reps_planned,omitempty"`
+	WeightPlannedKg     *float64      `json:"weight_planned_kg,omitempty"`
+	IntensityPercentage *float64      `json:"intensity_percentage,omitempty"`
+}
+
+// SwapExerciseRequest represents the request body for swapping a planned exercise
+type SwapExerciseRequest struct {
+	ExerciseLogID        string  `json:"exercise_log_id" binding:"required,uuid"`
+	SubstituteExerciseID string  `json:"substitute_exercise_id" binding:"required,uuid"`
+	Reason               *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// SkipExerciseRequest represents the request body for skipping a planned exercise
+type SkipExerciseRequest struct {
+	ExerciseLogID string  `json:"exercise_log_id" binding:"required,uuid"`
+	Reason        *string `json:"reason" binding:"omitempty,max=500"`
+}
+
+// SkippedExercise is a planned exercise the user skipped during a session
+type SkippedExercise struct {
+	SessionID     string    `json:"session_id"`
+	ExerciseLogID string    `json:"exercise_log_id"`
+	ExerciseID    string    `json:"exercise_id"`
+	Reason        *string   `json:"reason,omitempty"`
+	SkippedAt     time.Time `json:"skipped_at"`
+}
+
+// WeeklySkippedVolume totals the planned work skipped for one exercise in one week
+type WeeklySkippedVolume struct {
+	WeekStart    string  `json:"week_start"` // Monday, YYYY-MM-DD (UTC)
+	ExerciseID   string  `json:"exercise_id"`
+	ExerciseName string  `json:"exercise_name"`
+	Skipped      int     `json:"skipped"`   // Times the exercise was skipped
+	Sets         int     `json:"sets"`      // Planned sets skipped
+	VolumeKg     float64 `json:"volume_kg"` // Planned sets x reps x load skipped
+}
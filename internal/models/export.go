@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// ExerciseLogExport is one exercise log flattened with its session and exercise for export
+type ExerciseLogExport struct {
+	SessionID     string
+	SessionName   *string
+	SessionDate   time.Time
+	SessionStatus string
+	ExerciseName  string
+	OrderIndex    int
+	SetsCompleted *int
+	SetsPlanned   *int
+	RepsCompleted *int
+	RepsPlanned   *int
+	WeightKg      *float64
+	DurationSec   *int
+	DistanceM     *float64
+	RPE           *int
+	IsPR          bool
+	Notes         *string
+}
+
+// Account export statuses
+const (
+	AccountExportStatusPending = "pending"
+	AccountExportStatusReady   = "ready"
+	AccountExportStatusFailed  = "failed"
+)
+
+// AccountExport tracks an asynchronously generated archive of a user's data
+type AccountExport struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Status      string     `json:"status"`
+	Error       *string    `json:"error,omitempty"`
+	SizeBytes   *int64     `json:"size_bytes,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
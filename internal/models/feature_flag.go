@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// FeatureFlag is how a feature is rolled out: off, or on for a percentage of users, with
+// per-user overrides either way
+type FeatureFlag struct {
+	Name           string          `json:"name"`
+	Description    string          `json:"description"`
+	Enabled        bool            `json:"enabled"`
+	RolloutPercent int             `json:"rollout_percent"`     // Of users, when enabled
+	Overrides      map[string]bool `json:"overrides,omitempty"` // By user ID; win over the rollout
+	Stored         bool            `json:"stored"`              // False for defaults from configuration
+	UpdatedAt      *time.Time      `json:"updated_at,omitempty"`
+}
+
+// UpdateFeatureFlagRequest represents the request body for changing a flag's rollout
+type UpdateFeatureFlagRequest struct {
+	Enabled        *bool  `json:"enabled" binding:"required"`
+	RolloutPercent *int   `json:"rollout_percent" binding:"omitempty,min=0,max=100"` // 100 when omitted
+	Description    string `json:"description" binding:"max=500"`
+}
+
+// SetFeatureFlagOverrideRequest represents the request body for turning a flag on or off for one user
+type SetFeatureFlagOverrideRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// Activity event types shown in feeds
+const (
+	ActivitySessionCompleted = "session.completed"
+	ActivityPRAchieved       = "pr.achieved"
+)
+
+// ActivityEvent is something a followed user did, with the current details of the session
+// or record it is about
+type ActivityEvent struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	Type            string    `json:"type"`
+	SessionID       string    `json:"session_id"`
+	SessionName     *string   `json:"session_name,omitempty"`
+	SessionType     string    `json:"session_type"`
+	DurationMinutes *int      `json:"duration_minutes,omitempty"`
+	CreatedAt       time.Time `json:"created_at"`
+	ReactionCount   int       `json:"reaction_count"`
+	HasReacted      bool      `json:"has_reacted"` // Whether the reader liked it
+
+	// Personal records only
+	ExerciseID           *string  `json:"exercise_id,omitempty"`
+	ExerciseName         *string  `json:"exercise_name,omitempty"`
+	WeightKg             *float64 `json:"weight_kg,omitempty"`
+	Reps                 *int     `json:"reps,omitempty"`
+	PreviousBestWeightKg *float64 `json:"previous_best_weight_kg,omitempty"`
+}
+
+// ReactionSummary is how a feed item has been liked, as seen by one user
+type ReactionSummary struct {
+	ActivityID    string `json:"activity_id"`
+	ReactionCount int    `json:"reaction_count"`
+	HasReacted    bool   `json:"has_reacted"`
+}
+
+// FeedFilter selects a page of a user's feed; events are paged like the session history,
+// by creation, newest first, with the ID breaking ties
+type FeedFilter struct {
+	Limit int
+	After *SessionCursor // nil for the first page
+}
+
+// FeedPage is a page of a user's feed
+type FeedPage struct {
+	Events     []*ActivityEvent `json:"events"`
+	NextCursor string           `json:"next_cursor,omitempty"` // Empty on the last page
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// Follow statuses
+const (
+	FollowStatusPending  = "pending" // Waiting for a followers-only profile to approve it
+	FollowStatusAccepted = "accepted"
+)
+
+// Follow is one user following another
+type Follow struct {
+	FollowerID string     `json:"follower_id"`
+	FolloweeID string     `json:"followee_id"`
+	Status     string     `json:"status"`
+	AcceptedAt *time.Time `json:"accepted_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+// Goal kinds
+const (
+	GoalKindBodyWeight     = "body_weight"     // Reach a body weight, up or down from the start
+	GoalKindE1RM           = "e1rm"            // Reach an estimated one-rep max on a lift
+	GoalKindWeeklySessions = "weekly_sessions" // Complete a number of sessions every week
+)
+
+// Goal is a target the user set, with their progress towards it
+type Goal struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Kind        string     `json:"kind"`
+	ExerciseID  *string    `json:"exercise_id,omitempty"`
+	TargetValue float64    `json:"target_value"`
+	StartValue  float64    `json:"start_value"`
+	AchievedAt  *time.Time `json:"achieved_at"` // Weekly goals: the last time a week's target was met
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	// Progress, computed from measurements and logs when read
+	CurrentValue *float64 `json:"current_value"` // Nil without a weigh-in or logged set yet
+	Percentage   float64  `json:"percentage"`    // From start to target, 0 to 100
+	Achieved     bool     `json:"achieved"`      // Weekly goals: met this week
+}
+
+// CreateGoalRequest represents the request body for setting a goal
+type CreateGoalRequest struct {
+	Kind        string  `json:"kind" binding:"required,oneof=body_weight e1rm weekly_sessions"`
+	ExerciseID  *string `json:"exercise_id" binding:"omitempty,uuid"` // Required for e1rm goals
+	TargetValue float64 `json:"target_value" binding:"required,gt=0,lte=1000"`
+}
+
+// UpdateGoalRequest represents the request body for changing a goal's target
+type UpdateGoalRequest struct {
+	TargetValue float64 `json:"target_value" binding:"required,gt=0,lte=1000"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// DefaultHydrationGoalMl is the daily goal of users who never set one
+const DefaultHydrationGoalMl = 2000
+
+// WaterLog is water the user drank at one time
+type WaterLog struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	AmountMl  int       `json:"amount_ml"`
+	LoggedAt  time.Time `json:"logged_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// CreateWaterLogRequest represents the request body for logging water; logged_at
+// defaults to now
+type CreateWaterLogRequest struct {
+	AmountMl int        `json:"amount_ml" binding:"required,gt=0,lte=5000"`
+	LoggedAt *time.Time `json:"logged_at"`
+}
+
+// HydrationGoal is the water the user aims to drink per day
+type HydrationGoal struct {
+	UserID    string     `json:"user_id"`
+	DailyMl   int        `json:"daily_ml"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"` // Unset for the default goal
+}
+
+// UpdateHydrationGoalRequest represents the request body for setting the daily goal
+type UpdateHydrationGoalRequest struct {
+	DailyMl int `json:"daily_ml" binding:"required,gte=250,lte=10000"`
+}
+
+// HydrationProgress is the water the user drank on a day against their goal
+type HydrationProgress struct {
+	Date        string  `json:"date"` // YYYY-MM-DD, in the user's timezone
+	GoalMl      int     `json:"goal_ml"`
+	TotalMl     int     `json:"total_ml"`
+	RemainingMl int     `json:"remaining_ml"` // 0 once the goal is met
+	Percentage  float64 `json:"percentage"`   // Of the goal, may exceed 100
+	Logs        int     `json:"logs"`
+}
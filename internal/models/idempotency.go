@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// IdempotencyHeader is the request header carrying a client's idempotency key
+const IdempotencyHeader = "Idempotency-Key"
+
+// IdempotencyRecord is a request sent with an Idempotency-Key and, once it has been
+// handled, the response replayed to retries
+type IdempotencyRecord struct {
+	UserID      string
+	Key         string
+	RequestHash string
+	StatusCode  *int // nil while the first request is still running
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+}
@@ -0,0 +1,101 @@
+package models
+
+import "time"
+
+// Import sources
+const (
+	ImportSourceStrong      = "strong"
+	ImportSourceHevy        = "hevy"
+	ImportSourceAppleHealth = "apple-health"
+	ImportSourceFIT         = "fit"
+	ImportSourceTCX         = "tcx"
+)
+
+// Import statuses
+const (
+	ImportStatusPending   = "pending"
+	ImportStatusCompleted = "completed"
+	ImportStatusFailed    = "failed"
+)
+
+// Import tracks an uploaded export whose sessions are stored by a background job
+type Import struct {
+	ID          string        `json:"id"`
+	UserID      string        `json:"user_id"`
+	Source      string        `json:"source"`
+	Status      string        `json:"status"`
+	Timezone    string        `json:"timezone"`
+	Result      *ImportResult `json:"result,omitempty"` // Set once completed
+	Error       *string       `json:"error,omitempty"`
+	CreatedAt   time.Time     `json:"created_at"`
+	CompletedAt *time.Time    `json:"completed_at,omitempty"`
+}
+
+// ImportedSession is a workout parsed from another app's export, before it is stored
+type ImportedSession struct {
+	StartedAt time.Time
+	Name      string
+	Type      string // SessionTypeStrength when empty
+	Duration  time.Duration
+	Notes     string
+	Sets      []*ImportedSet
+	Laps      []*ImportedLap
+
+	// Summaries recorded by wearables, when the source has them
+	CaloriesBurned *int
+	HeartRateAvg   *int
+	HeartRateMax   *int
+}
+
+// ImportedSet is a single logged set of an imported session
+type ImportedSet struct {
+	ExerciseName string
+	WeightKg     *float64
+	Reps         *int
+	DistanceM    *float64
+	DurationSec  *int
+	RPE          *int
+	Notes        string
+
+	ExerciseID string // Resolved library exercise, filled in before storing
+}
+
+// ImportedLap is one lap of an imported cardio session
+type ImportedLap struct {
+	StartedAt      time.Time
+	Duration       time.Duration
+	DistanceM      *float64
+	CaloriesBurned *int
+	HeartRateAvg   *int
+	HeartRateMax   *int
+}
+
+// LoggedExercise is an exercise the user already logged in a session, used to skip
+// history that is imported twice (e.g. the overlap when switching apps)
+type LoggedExercise struct {
+	StartedAt  time.Time
+	ExerciseID string
+}
+
+// ImportSessionPreview summarizes one session an import would create
+type ImportSessionPreview struct {
+	StartedAt time.Time `json:"started_at"`
+	Name      string    `json:"name"`
+	Type      string    `json:"type"`
+	Exercises int       `json:"exercises"`
+	Sets      int       `json:"sets"`
+	Laps      int       `json:"laps,omitempty"`
+}
+
+// ImportResult reports what an import created, or would create in dry-run mode
+type ImportResult struct {
+	Source          string                  `json:"source"`
+	DryRun          bool                    `json:"dry_run"`
+	Sessions        int                     `json:"sessions"`
+	Sets            int                     `json:"sets"`
+	SkippedSessions int                     `json:"skipped_sessions"` // Every exercise already logged that day
+	SkippedSets     int                     `json:"skipped_sets"`     // Exercise already logged that day
+	MatchedNames    []string                `json:"matched_exercises"`
+	NewExercises    []string                `json:"new_exercises"` // Created as private exercises
+	Preview         []*ImportSessionPreview `json:"preview,omitempty"`
+}
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+// InjuryDateLayout is the layout of injury start and end days
+const InjuryDateLayout = "2006-01-02"
+
+// Injury is an injury the user recorded; while active, exercises loading its body parts
+// are contraindicated
+type Injury struct {
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Name      string     `json:"name"`
+	BodyParts []string   `json:"body_parts"`
+	StartedOn time.Time  `json:"started_on"`
+	EndedOn   *time.Time `json:"ended_on"` // Last day; nil while ongoing
+	Notes     string     `json:"notes"`
+	Active    bool       `json:"active"` // Today falls within the injury, in the user's timezone
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// InjuryRequest represents the request body for recording or updating an injury
+type InjuryRequest struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	BodyParts []string `json:"body_parts" binding:"required,min=1,max=11,dive,oneof=neck shoulder elbow wrist chest upper_back lower_back abdomen hip knee ankle"`
+	StartedOn string   `json:"started_on" binding:"required,datetime=2006-01-02"`
+	EndedOn   *string  `json:"ended_on" binding:"omitempty,datetime=2006-01-02"`
+	Notes     string   `json:"notes" binding:"max=1000"`
+}
@@ -0,0 +1,93 @@
+package models
+
+import "time"
+
+// Integration providers
+const (
+	IntegrationProviderGoogleFit = "googlefit"
+	IntegrationProviderStrava    = "strava"
+)
+
+// IntegrationConnection holds a user's OAuth tokens for a third-party fitness platform
+type IntegrationConnection struct {
+	ID                string     `json:"id"`
+	UserID            string     `json:"user_id"`
+	Provider          string     `json:"provider"`
+	ExternalAccountID *string    `json:"external_account_id,omitempty"` // e.g. the Strava athlete ID
+	AccessToken       string     `json:"-"`
+	RefreshToken      string     `json:"-"`
+	TokenExpiresAt    time.Time  `json:"-"`
+	Scope             *string    `json:"scope,omitempty"`
+	PullActivities    bool       `json:"pull_activities"`
+	PushSessionsSince *time.Time `json:"push_sessions_since,omitempty"` // Sessions completed since are pushed; nil when off
+	LastSyncedAt      *time.Time `json:"last_synced_at,omitempty"`
+	LastSyncError     *string    `json:"last_sync_error,omitempty"`
+	ReauthRequired    bool       `json:"reauth_required"` // Access was revoked; connect again to resume syncing
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// IntegrationAuthorization is the consent page the user opens to connect a provider
+type IntegrationAuthorization struct {
+	AuthURL   string    `json:"auth_url"`
+	State     string    `json:"state"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ConnectIntegrationRequest represents the request body for completing a provider connection
+// with the authorization code from the OAuth redirect
+type ConnectIntegrationRequest struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// UpdateIntegrationSettingsRequest represents the request body for choosing sync directions
+type UpdateIntegrationSettingsRequest struct {
+	PullActivities *bool `json:"pull_activities"`
+	PushSessions   *bool `json:"push_sessions"`
+}
+
+// ExternalSession is a completed workout pulled from a provider
+type ExternalSession struct {
+	ExternalID  string
+	Name        string
+	Notes       string
+	Type        string // A registered session type; empty keeps the default (strength)
+	StartedAt   time.Time
+	CompletedAt time.Time
+}
+
+// PushSession is a completed fitapi session to publish to a provider
+type PushSession struct {
+	ID          string
+	Name        *string
+	Notes       *string
+	Type        string
+	StartedAt   time.Time
+	CompletedAt time.Time
+	DistanceM   *float64 // Summed over the session's logs
+}
+
+// IntegrationSyncResult reports what a sync pulled in
+type IntegrationSyncResult struct {
+	Provider        string    `json:"provider"`
+	From            time.Time `json:"from"`
+	To              time.Time `json:"to"`
+	SessionsCreated int       `json:"sessions_created"`
+	SessionsUpdated int       `json:"sessions_updated"`
+	SessionsKept    int       `json:"sessions_kept"` // Edited in fitapi since the last sync, left as is
+	Weights         int64     `json:"weights_imported"`
+	PushesQueued    int64     `json:"pushes_queued"` // Sessions queued for pushing to the provider
+}
+
+// StravaWebhookEvent is an event Strava posts to the webhook subscription
+// See https://developers.strava.com/docs/webhooks/
+type StravaWebhookEvent struct {
+	ObjectType     string         `json:"object_type" binding:"required"` // activity or athlete
+	ObjectID       int64          `json:"object_id" binding:"required"`
+	AspectType     string         `json:"aspect_type" binding:"required"` // create, update or delete
+	OwnerID        int64          `json:"owner_id" binding:"required"`    // Athlete ID
+	SubscriptionID int64          `json:"subscription_id"`
+	EventTime      int64          `json:"event_time"`
+	Updates        map[string]any `json:"updates"` // e.g. {"authorized": "false"} when the athlete revokes access
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Job kinds; each is run by the service that queues it
+const (
+	JobKindEmail           = "email"
+	JobKindAccountExport   = "account_export"
+	JobKindWebhookDelivery = "webhook_delivery"
+	JobKindShareCard       = "share_card"
+	JobKindImport          = "import"
+
+	// Strava webhook events and session pushes
+	JobKindStravaImportActivity = "strava_import_activity"
+	JobKindStravaDeleteActivity = "strava_delete_activity"
+	JobKindStravaPushSession    = "strava_push_session"
+)
+
+// Job statuses
+const (
+	JobStatusPending = "pending" // Queued, or waiting for a retry
+	JobStatusRunning = "running"
+	JobStatusDone    = "done"
+	JobStatusDead    = "dead" // Gave up after too many attempts, or failed for good
+)
+
+// JobStatuses lists the valid job statuses
+var JobStatuses = []string{JobStatusPending, JobStatusRunning, JobStatusDone, JobStatusDead}
+
+// Job is background work queued in the database and run by the API's workers
+type Job struct {
+	ID          string          `json:"id"`
+	UserID      *string         `json:"user_id,omitempty"`
+	Kind        string          `json:"kind"`
+	Payload     json.RawMessage `json:"payload"`
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	RunAt       time.Time       `json:"run_at"` // Next attempt while pending
+	LastError   *string         `json:"last_error,omitempty"`
+	FinishedAt  *time.Time      `json:"finished_at,omitempty"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+// JobFilter selects jobs, newest first
+type JobFilter struct {
+	UserID string // Empty for every user's jobs
+	Kind   string
+	Status string
+	Limit  int
+}
+
+// JobCount is the number of jobs of a kind in a status
+type JobCount struct {
+	Kind        string     `json:"kind"`
+	Status      string     `json:"status"`
+	Count       int        `json:"count"`
+	OldestRunAt *time.Time `json:"oldest_run_at,omitempty"` // Of pending jobs, to spot a backlog
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Body measurement sources
+const (
+	MeasurementSourceManual    = "manual"
+	MeasurementSourceWithings  = "withings"
+	MeasurementSourceRenpho    = "renpho"
+	MeasurementSourceGoogleFit = "googlefit"
+)
+
+// BodyMeasurement is a single weigh-in
+type BodyMeasurement struct {
+	ID                string    `json:"id"`
+	UserID            string    `json:"user_id"`
+	MeasuredAt        time.Time `json:"measured_at"`
+	WeightKg          *float64  `json:"weight_kg,omitempty"`
+	BodyFatPercentage *float64  `json:"body_fat_percentage,omitempty"`
+	MuscleMassKg      *float64  `json:"muscle_mass_kg,omitempty"`
+	Source            string    `json:"source"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// MeasurementImportResult summarizes a smart scale import
+type MeasurementImportResult struct {
+	Source   string `json:"source"`
+	Rows     int    `json:"rows"`
+	Imported int64  `json:"imported"`
+	Skipped  int64  `json:"skipped"` // Rows whose timestamp was already recorded
+}
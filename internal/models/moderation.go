@@ -0,0 +1,71 @@
+package models
+
+import "time"
+
+// Public library content users can report
+const (
+	ReportResourceExercise = "exercise" // Public exercises
+	ReportResourceWorkout  = "workout"  // Workouts shared in an organization
+)
+
+// How an admin resolved a resource's open reports
+const (
+	ReportResolutionApproved    = "approved" // Reviewed and kept as is
+	ReportResolutionEdited      = "edited"
+	ReportResolutionUnpublished = "unpublished"
+)
+
+// ContentReport is a user's report of public library content
+type ContentReport struct {
+	ID           string     `json:"id"`
+	ResourceType string     `json:"resource_type"`
+	ResourceID   string     `json:"resource_id"`
+	UserID       string     `json:"user_id"` // Reporter
+	Reason       string     `json:"reason"`
+	Details      *string    `json:"details,omitempty"`
+	Resolution   *string    `json:"resolution,omitempty"` // Unset while the report is open
+	ResolvedAt   *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+}
+
+// ReportedContent is an entry of the moderation queue: a resource with open reports
+type ReportedContent struct {
+	ResourceType    string           `json:"resource_type"`
+	ResourceID      string           `json:"resource_id"`
+	Name            string           `json:"name"`
+	OwnerID         string           `json:"owner_id"`
+	OrganizationID  *string          `json:"organization_id,omitempty"` // Shared workouts
+	ReportCount     int              `json:"report_count"`
+	FirstReportedAt time.Time        `json:"first_reported_at"`
+	LastReportedAt  time.Time        `json:"last_reported_at"`
+	Reports         []*ContentReport `json:"reports"` // Oldest first
+}
+
+// ModerationResult is what a moderation action did to a resource's open reports
+type ModerationResult struct {
+	ResourceType    string `json:"resource_type"`
+	ResourceID      string `json:"resource_id"`
+	Resolution      string `json:"resolution"`
+	ReportsResolved int64  `json:"reports_resolved"`
+}
+
+// ReportContentRequest represents the request body for reporting library content
+type ReportContentRequest struct {
+	Reason  string `json:"reason" binding:"required,oneof=spam inappropriate incorrect duplicate other"`
+	Details string `json:"details" binding:"max=1000"`
+}
+
+// UpdateLibraryExerciseRequest represents the request body for an admin editing a public exercise
+type UpdateLibraryExerciseRequest struct {
+	Name        string  `json:"name" binding:"required,min=1,max=100"`
+	Description string  `json:"description" binding:"max=2000"`
+	MuscleGroup *string `json:"muscle_group" binding:"omitempty,oneof=chest back shoulders biceps triceps forearms core quads hamstrings glutes calves full_body cardio"`
+	Version     int64   `json:"version" binding:"required,min=1"` // Version the edit is based on
+}
+
+// UpdateSharedWorkoutRequest represents the request body for an admin editing a shared workout
+type UpdateSharedWorkoutRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"max=2000"`
+	Version     int64  `json:"version" binding:"required,min=1"` // Version the edit is based on
+}
@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Notification types; the database triggers of migration 030 create the in-app ones
+const (
+	NotificationPRAchieved         = "pr.achieved"
+	NotificationWorkoutAssigned    = "workout.assigned"
+	NotificationWorkoutReminder    = "workout.reminder"
+	NotificationWeeklySummary      = "weekly.summary"
+	NotificationGoalAchieved       = "goal.achieved"
+	NotificationInjuryWarning      = "injury.warning"
+	NotificationCommentCreated     = "comment.created"
+	NotificationChallengeCompleted = "challenge.completed"
+)
+
+// Notification channels
+const (
+	NotificationChannelEmail = "email"
+	NotificationChannelPush  = "push"
+	NotificationChannelInApp = "in_app"
+)
+
+// NotificationTypeChannels lists the channels each type of notification is sent on
+var NotificationTypeChannels = map[string][]string{
+	NotificationPRAchieved:         {NotificationChannelInApp},
+	NotificationWorkoutAssigned:    {NotificationChannelPush, NotificationChannelInApp},
+	NotificationWorkoutReminder:    {NotificationChannelPush, NotificationChannelEmail},
+	NotificationWeeklySummary:      {NotificationChannelEmail},
+	NotificationGoalAchieved:       {NotificationChannelInApp},
+	NotificationInjuryWarning:      {NotificationChannelInApp},
+	NotificationCommentCreated:     {NotificationChannelInApp},
+	NotificationChallengeCompleted: {NotificationChannelInApp},
+}
+
+// Notification is an entry in a user's in-app inbox
+type Notification struct {
+	ID        string          `json:"id"`
+	UserID    string          `json:"user_id"`
+	Type      string          `json:"type"`
+	Title     string          `json:"title"`
+	Body      string          `json:"body"`
+	Data      json.RawMessage `json:"data"`
+	ReadAt    *time.Time      `json:"read_at"`
+	CreatedAt time.Time       `json:"created_at"`
+}
+
+// NotificationList is a page of a user's inbox, newest first
+type NotificationList struct {
+	Notifications []*Notification `json:"notifications"`
+	UnreadCount   int             `json:"unread_count"` // Across the whole inbox, not just this page
+}
+
+// NotificationFilter selects a page of a user's inbox
+type NotificationFilter struct {
+	UnreadOnly bool
+	Before     *time.Time // Only notifications created before, to page back
+	Limit      int
+}
+
+// NotificationPreferences are the channels a user receives each type of notification on,
+// and when pushes are held back
+type NotificationPreferences struct {
+	UserID     string              `json:"user_id"`
+	Channels   map[string][]string `json:"channels"`    // By type; types left out are sent on every channel
+	QuietHours *QuietHours         `json:"quiet_hours"` // Nil for none
+	UpdatedAt  time.Time           `json:"updated_at"`
+}
+
+// QuietHours is a daily period during which pushes are held until it ends; emails and the
+// inbox are silent and not affected
+type QuietHours struct {
+	Start    string `json:"start"`    // HH:MM in Timezone
+	End      string `json:"end"`      // HH:MM in Timezone; before Start when spanning midnight
+	Timezone string `json:"timezone"` // Defaults to UTC
+}
+
+// UpdateNotificationPreferencesRequest is the payload for replacing notification
+// preferences; omitting quiet_hours turns them off
+type UpdateNotificationPreferencesRequest struct {
+	Channels   map[string][]string `json:"channels"`
+	QuietHours *QuietHours         `json:"quiet_hours"`
+}
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Organization membership roles
+const (
+	OrgRoleOwner   = "owner"
+	OrgRoleTrainer = "trainer"
+	OrgRoleMember  = "member"
+)
+
+// Organization represents a gym or team that shares resources across its members
+type Organization struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	CreatedBy   string    `json:"created_by"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// OrganizationMember represents a user's membership and role in an organization
+type OrganizationMember struct {
+	OrganizationID string    `json:"organization_id"`
+	UserID         string    `json:"user_id"`
+	Role           string    `json:"role"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// CreateOrganizationRequest represents the request body for creating an organization
+type CreateOrganizationRequest struct {
+	Name        string `json:"name" binding:"required,min=1,max=100"`
+	Description string `json:"description" binding:"max=500"`
+}
+
+// AddOrganizationMemberRequest represents the request body for adding a member
+type AddOrganizationMemberRequest struct {
+	UserID string `json:"user_id" binding:"required,uuid"`
+	Role   string `json:"role" binding:"required,oneof=owner trainer member"`
+}
+
+// UpdateOrganizationMemberRequest represents the request body for changing a member's role
+type UpdateOrganizationMemberRequest struct {
+	Role string `json:"role" binding:"required,oneof=owner trainer member"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Profile privacy settings
+const (
+	ProfilePrivacyPublic    = "public"    // Anyone can follow and see the profile
+	ProfilePrivacyFollowers = "followers" // Follows need approval; only followers see the profile
+	ProfilePrivacyPrivate   = "private"   // Nobody can follow; only the user sees the profile
+)
+
+// Profile holds a user's account-wide settings
+type Profile struct {
+	UserID    string    `json:"user_id"`
+	Timezone  string    `json:"timezone"` // IANA name days and weeks of the user's history are counted in
+	Privacy   string    `json:"privacy"`  // Who can follow the user and see their profile
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// UpdateProfileRequest represents the request body for updating the user's profile
+type UpdateProfileRequest struct {
+	Timezone string `json:"timezone" binding:"required,max=64"`
+	Privacy  string `json:"privacy" binding:"omitempty,oneof=public followers private"` // Empty keeps the current setting
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// Progression schemes
+const (
+	ProgressionLinear       = "linear" // Add load after every session that hit all sets and reps
+	ProgressionFiveThreeOne = "531"    // Wendler's 5/3/1: four-week waves of training max percentages
+	ProgressionDouble       = "double" // Add reps up to the top of the range, then load
+)
+
+// PrescribedSet is one set of a prescription
+type PrescribedSet struct {
+	Reps     int      `json:"reps"`
+	WeightKg *float64 `json:"weight_kg"`       // Nil for bodyweight work or when there's nothing to base it on
+	AMRAP    bool     `json:"amrap,omitempty"` // As many reps as possible, at least Reps
+}
+
+// ExercisePrescription is what to do for one exercise of a workout next session
+type ExercisePrescription struct {
+	WorkoutExerciseID string          `json:"workout_exercise_id"`
+	ExerciseID        string          `json:"exercise_id"`
+	ExerciseName      string          `json:"exercise_name"`
+	Scheme            string          `json:"scheme,omitempty"` // Empty when the template entry is repeated as is
+	Week              *int            `json:"week,omitempty"`   // 5/3/1 week of the cycle, 1-4 (4 is the deload)
+	Sets              []PrescribedSet `json:"sets"`
+	Note              string          `json:"note,omitempty"` // Why the prescription changed, or why it couldn't be computed
+}
+
+// WorkoutPrescription is the next session of a workout, for pre-filling its logs
+type WorkoutPrescription struct {
+	WorkoutID string                  `json:"workout_id"`
+	Exercises []*ExercisePrescription `json:"exercises"`
+}
+
+// ExercisePerformance is how the user did an exercise in one completed session
+type ExercisePerformance struct {
+	ExerciseID    string    `json:"exercise_id"`
+	PerformedAt   time.Time `json:"performed_at"`
+	WeightKg      *float64  `json:"weight_kg"`
+	SetsCompleted int       `json:"sets_completed"`
+	SetsPlanned   *int      `json:"sets_planned"`
+	RepsCompleted *int      `json:"reps_completed"`
+	RepsPlanned   *int      `json:"reps_planned"`
+}
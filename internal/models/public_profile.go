@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// PublicProfileSettings is the username a user's profile is found by and which parts of
+// it others see
+type PublicProfileSettings struct {
+	UserID             string    `json:"user_id"`
+	Username           string    `json:"username"`
+	DisplayName        *string   `json:"display_name,omitempty"`
+	ShowDisplayName    bool      `json:"show_display_name"`
+	ShowRecords        bool      `json:"show_records"`
+	ShowRecentWorkouts bool      `json:"show_recent_workouts"`
+	ShowFollowCounts   bool      `json:"show_follow_counts"`
+	UpdatedAt          time.Time `json:"updated_at"`
+}
+
+// UpdatePublicProfileSettingsRequest represents the request body for updating a user's
+// public profile; visibility flags left out keep their current value, shown by default
+type UpdatePublicProfileSettingsRequest struct {
+	Username           string  `json:"username" binding:"required,min=3,max=30"`
+	DisplayName        *string `json:"display_name" binding:"omitempty,min=1,max=60"`
+	ShowDisplayName    *bool   `json:"show_display_name"`
+	ShowRecords        *bool   `json:"show_records"`
+	ShowRecentWorkouts *bool   `json:"show_recent_workouts"`
+	ShowFollowCounts   *bool   `json:"show_follow_counts"`
+}
+
+// PublicProfile is what others see of a user's profile; hidden parts are left out
+type PublicProfile struct {
+	Username       string             `json:"username"`
+	DisplayName    *string            `json:"display_name,omitempty"`
+	Records        []*RecordHighlight `json:"records,omitempty"`
+	RecentWorkouts []*PublicSession   `json:"recent_workouts,omitempty"`
+	FollowerCount  *int               `json:"follower_count,omitempty"`
+	FollowingCount *int               `json:"following_count,omitempty"`
+}
+
+// RecordHighlight is a user's latest personal record on an exercise
+type RecordHighlight struct {
+	ExerciseID   string    `json:"exercise_id"`
+	ExerciseName string    `json:"exercise_name"`
+	WeightKg     float64   `json:"weight_kg"`
+	Reps         *int      `json:"reps,omitempty"`
+	AchievedAt   time.Time `json:"achieved_at"`
+}
+
+// PublicSession is a completed session as shown on a public profile
+type PublicSession struct {
+	ID              string     `json:"id"`
+	Name            *string    `json:"name,omitempty"`
+	SessionType     string     `json:"session_type"`
+	DurationMinutes *int       `json:"duration_minutes,omitempty"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,47 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Device platforms: iOS devices are reached through APNs, Android devices through FCM
+const (
+	DevicePlatformIOS     = "ios"
+	DevicePlatformAndroid = "android"
+)
+
+// DevicePlatforms lists the platforms devices may register for
+var DevicePlatforms = []string{DevicePlatformIOS, DevicePlatformAndroid}
+
+// Push notification kinds; the database trigger of migration 028 queues coach assignments
+// and the reminder scheduler queues reminders
+const (
+	PushKindWorkoutAssigned = "workout.assigned"
+	PushKindWorkoutReminder = "workout.reminder"
+)
+
+// DeviceToken is a device registered to receive the user's push notifications
+type DeviceToken struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Platform  string    `json:"platform"`
+	Token     string    `json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// RegisterDeviceRequest is the payload for registering a device
+type RegisterDeviceRequest struct {
+	Platform string `json:"platform" binding:"required"`
+	Token    string `json:"token" binding:"required"`
+}
+
+// PushNotification is a queued notification claimed for sending
+type PushNotification struct {
+	ID       string
+	UserID   string
+	Kind     string
+	Data     json.RawMessage
+	Attempts int
+}
@@ -0,0 +1,14 @@
+package models
+
+// QueryStats is how a database statement performed on one API instance since it started
+type QueryStats struct {
+	Statement string  `json:"statement"` // Whitespace collapsed and truncated; batches list their statements
+	Calls     int64   `json:"calls"`
+	Errors    int64   `json:"errors"`
+	TotalMs   float64 `json:"total_ms"`
+	MeanMs    float64 `json:"mean_ms"`
+	MaxMs     float64 `json:"max_ms"`
+	P50Ms     float64 `json:"p50_ms"` // Upper histogram bucket bounds
+	P95Ms     float64 `json:"p95_ms"`
+	P99Ms     float64 `json:"p99_ms"`
+}
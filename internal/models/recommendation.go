@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// Training goals a workout recommendation is built for
+const (
+	TrainingGoalStrength    = "strength"    // Low reps, heavy loads, long rests
+	TrainingGoalHypertrophy = "hypertrophy" // Moderate reps and rests, for putting on muscle
+	TrainingGoalFatLoss     = "fat_loss"    // Higher reps, short rests
+	TrainingGoalGeneral     = "general"     // Balanced, when there is no goal to steer by
+)
+
+// MuscleGroupRecency is when the user last trained a muscle group in a completed session
+type MuscleGroupRecency struct {
+	MuscleGroup   string
+	LastTrainedAt time.Time
+}
+
+// RecommendationCandidate is an exercise the user can do with their equipment, with how
+// often and how recently they have done it
+type RecommendationCandidate struct {
+	ExerciseID      string
+	Name            string
+	MuscleGroup     string
+	LastPerformedAt *time.Time
+	TimesPerformed  int
+}
+
+// RecommendedExercise is one exercise of a recommended session
+type RecommendedExercise struct {
+	ExerciseID  string `json:"exercise_id"`
+	Name        string `json:"name"`
+	MuscleGroup string `json:"muscle_group"`
+	Sets        int    `json:"sets"`
+	Reps        int    `json:"reps"`
+	RestSeconds int    `json:"rest_seconds"`
+	Reason      string `json:"reason"` // Why it was picked, for showing to the user
+}
+
+// WorkoutRecommendation is a proposed session
+type WorkoutRecommendation struct {
+	Strategy     string                 `json:"strategy"` // Which strategy built it
+	Goal         string                 `json:"goal"`
+	MuscleGroups []string               `json:"muscle_groups"` // Targeted, most overdue first
+	Exercises    []*RecommendedExercise `json:"exercises"`
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Reminder channels
+const (
+	ReminderChannelPush  = "push"
+	ReminderChannelEmail = "email"
+)
+
+// ReminderChannels lists the channels a reminder can be sent through
+var ReminderChannels = []string{ReminderChannelPush, ReminderChannelEmail}
+
+// ReminderRule is a recurring workout reminder at a local time on some weekdays
+type ReminderRule struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	Name        string     `json:"name"`
+	Weekdays    []int      `json:"weekdays"`    // 0 = Sunday ... 6 = Saturday
+	TimeOfDay   string     `json:"time_of_day"` // HH:MM in Timezone
+	Timezone    string     `json:"timezone"`
+	Channels    []string   `json:"channels"`
+	Enabled     bool       `json:"enabled"`
+	NextFireAt  *time.Time `json:"next_fire_at"`
+	LastFiredAt *time.Time `json:"last_fired_at"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// CreateReminderRuleRequest is the payload for creating a reminder rule
+type CreateReminderRuleRequest struct {
+	Name      string   `json:"name" binding:"max=100"`
+	Weekdays  []int    `json:"weekdays" binding:"required,min=1,max=7"`
+	TimeOfDay string   `json:"time_of_day" binding:"required"`
+	Timezone  string   `json:"timezone"` // Defaults to UTC
+	Channels  []string `json:"channels" binding:"required,min=1"`
+	Enabled   *bool    `json:"enabled"` // Defaults to true
+}
+
+// UpdateReminderRuleRequest is the payload for changing a reminder rule; omitted fields
+// keep their value
+type UpdateReminderRuleRequest struct {
+	Name      *string  `json:"name" binding:"omitempty,max=100"`
+	Weekdays  []int    `json:"weekdays" binding:"omitempty,min=1,max=7"`
+	TimeOfDay *string  `json:"time_of_day"`
+	Timezone  *string  `json:"timezone"`
+	Channels  []string `json:"channels" binding:"omitempty,min=1"`
+	Enabled   *bool    `json:"enabled"`
+}
+
+// ReminderPreview lists a rule's next firing times
+type ReminderPreview struct {
+	RuleID    string      `json:"rule_id"`
+	FireTimes []time.Time `json:"fire_times"`
+}
+
+// DueReminder is a reminder rule claimed for firing, with its user's email address
+type DueReminder struct {
+	Rule  *ReminderRule
+	Email string // Empty when the user has none
+}
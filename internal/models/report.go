@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// WeeklyReport is a user's training over one week (Monday to Sunday, UTC)
+type WeeklyReport struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	WeekStart       string    `json:"week_start"` // Monday, YYYY-MM-DD
+	Sessions        int       `json:"sessions"`   // Completed sessions
+	Minutes         int       `json:"minutes"`
+	VolumeKg        float64   `json:"volume_kg"`
+	PersonalRecords int       `json:"personal_records"`
+	StreakWeeks     int       `json:"streak_weeks"` // Weeks in a row with a completed session, ending this week
+	CreatedAt       time.Time `json:"created_at"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// Rest timer actions
+const (
+	RestTimerStart  = "start"
+	RestTimerSkip   = "skip"
+	RestTimerExtend = "extend"
+)
+
+// RestTimerSession is the stored rest timer of a workout session with what is needed to authorize changes
+type RestTimerSession struct {
+	SessionID       string
+	UserID          string
+	Status          string
+	StartedAt       *time.Time
+	DurationSeconds *int
+}
+
+// RestTimer is the shared rest countdown of an in-progress session
+// Clients compute the countdown from EndsAt, correcting their clock with ServerTime.
+type RestTimer struct {
+	SessionID        string     `json:"session_id"`
+	Active           bool       `json:"active"`
+	StartedAt        *time.Time `json:"started_at,omitempty"`
+	DurationSeconds  *int       `json:"duration_seconds,omitempty"`
+	EndsAt           *time.Time `json:"ends_at,omitempty"`
+	RemainingSeconds int        `json:"remaining_seconds"`
+	ServerTime       time.Time  `json:"server_time"`
+}
+
+// RestTimerEvent is pushed to every device following a session when its timer changes
+type RestTimerEvent struct {
+	Action string     `json:"action"` // start, skip or extend
+	Timer  *RestTimer `json:"timer"`
+}
+
+// RestTimerActionRequest represents the request body for starting, skipping or extending the rest timer
+// DurationSeconds is the rest length for start (default 90s) and the time added for extend (default 30s).
+type RestTimerActionRequest struct {
+	Action          string `json:"action" binding:"required,oneof=start skip extend"`
+	DurationSeconds *int   `json:"duration_seconds" binding:"omitempty,min=1,max=3600"`
+}
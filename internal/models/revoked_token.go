@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// RevokedToken represents an access token rejected before its natural expiry
+type RevokedToken struct {
+	TokenID   string    `json:"token_id"`
+	UserID    string    `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	RevokedAt time.Time `json:"revoked_at"`
+}
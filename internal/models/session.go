@@ -0,0 +1,68 @@
+package models
+
+import "time"
+
+// SessionSummary is a performed (or planned) workout session without its sets and laps,
+// as listed in the session history
+type SessionSummary struct {
+	ID              string     `json:"id"`
+	UserID          string     `json:"user_id"`
+	WorkoutID       *string    `json:"workout_id,omitempty"`
+	ClassSessionID  *string    `json:"class_session_id,omitempty"` // Set for sessions of an organization's class
+	Name            *string    `json:"name,omitempty"`
+	Type            string     `json:"type"`
+	Status          string     `json:"status"`
+	StartedAt       time.Time  `json:"started_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty"`
+	DurationMinutes *int       `json:"duration_minutes,omitempty"`
+	CaloriesBurned  *int       `json:"calories_burned,omitempty"`
+	HeartRateAvg    *int       `json:"heart_rate_avg,omitempty"`
+	HeartRateMax    *int       `json:"heart_rate_max,omitempty"`
+	Notes           *string    `json:"notes,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// Session is a performed (or planned) workout session with its sets and laps
+type Session struct {
+	SessionSummary
+	Sets []*SessionSet `json:"sets"`
+	Laps []*SessionLap `json:"laps"`
+}
+
+// SessionCursor is the position after the last session of a history page; sessions are
+// listed by creation, newest first, with the ID breaking ties
+type SessionCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// SessionFilter selects a page of a user's session history
+type SessionFilter struct {
+	Limit int
+	After *SessionCursor // nil for the first page
+}
+
+// SessionPage is a page of the session history
+type SessionPage struct {
+	Sessions   []*SessionSummary `json:"sessions"`
+	NextCursor string            `json:"next_cursor,omitempty"` // Empty on the last page
+}
+
+// SessionSet is one exercise log of a session with the exercise performed
+type SessionSet struct {
+	ID               string     `json:"id"`
+	OrderIndex       int        `json:"order_index"`
+	SetsCompleted    *int       `json:"sets_completed,omitempty"`
+	SetsPlanned      *int       `json:"sets_planned,omitempty"`
+	RepsCompleted    *int       `json:"reps_completed,omitempty"`
+	RepsPlanned      *int       `json:"reps_planned,omitempty"`
+	WeightKg         *float64   `json:"weight_kg,omitempty"`
+	DurationSeconds  *int       `json:"duration_seconds,omitempty"`
+	DistanceMeters   *float64   `json:"distance_meters,omitempty"`
+	RPE              *int       `json:"rpe,omitempty"`
+	Notes            *string    `json:"notes,omitempty"`
+	IsPersonalRecord bool       `json:"is_personal_record"`
+	SkippedAt        *time.Time `json:"skipped_at,omitempty"`
+	Exercise         *Exercise  `json:"exercise"`
+}
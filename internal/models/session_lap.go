@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// SessionLap is one lap of a cardio session, as recorded by the user's watch or bike computer
+type SessionLap struct {
+	ID              string    `json:"id"`
+	SessionID       string    `json:"session_id"`
+	LapIndex        int       `json:"lap_index"`
+	StartedAt       time.Time `json:"started_at"`
+	DurationSeconds int       `json:"duration_seconds"`
+	DistanceMeters  *float64  `json:"distance_meters,omitempty"`
+	PaceSecPerKm    *float64  `json:"pace_seconds_per_km,omitempty"` // Derived from duration and distance
+	CaloriesBurned  *int      `json:"calories_burned,omitempty"`
+	HeartRateAvg    *int      `json:"heart_rate_avg,omitempty"`
+	HeartRateMax    *int      `json:"heart_rate_max,omitempty"`
+}
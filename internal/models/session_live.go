@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// Live session event types sent to clients watching a session
+const (
+	LiveEventSnapshot      = "snapshot" // Sent first: the session as it is when watching starts
+	LiveEventSetLogged     = "set.logged"
+	LiveEventSetUpdated    = "set.updated"
+	LiveEventSetDeleted    = "set.deleted"
+	LiveEventSessionStatus = "session.status"
+	LiveEventRestTimer     = "rest_timer"
+	LiveEventResync        = "resync" // Events may have been missed; reconnect for a fresh snapshot
+	LiveEventPing          = "ping"
+)
+
+// LiveSet is an exercise log as watchers see it
+type LiveSet struct {
+	ID               string   `json:"id"`
+	ExerciseID       string   `json:"exercise_id"`
+	OrderIndex       int      `json:"order_index"`
+	SetsCompleted    *int     `json:"sets_completed"`
+	RepsCompleted    *int     `json:"reps_completed"`
+	WeightKg         *float64 `json:"weight_kg"`
+	DurationSeconds  *int     `json:"duration_seconds"`
+	DistanceMeters   *float64 `json:"distance_meters"`
+	RPE              *int     `json:"rpe"`
+	IsPersonalRecord *bool    `json:"is_personal_record"`
+}
+
+// LiveSessionEvent is a change to a watched session; fields not involved in the change
+// are left out
+type LiveSessionEvent struct {
+	Type                     string     `json:"type"`
+	SessionID                string     `json:"session_id,omitempty"`
+	Status                   *string    `json:"status,omitempty"`
+	RestTimerStartedAt       *time.Time `json:"rest_timer_started_at,omitempty"`
+	RestTimerDurationSeconds *int       `json:"rest_timer_duration_seconds,omitempty"`
+	Set                      *LiveSet   `json:"set,omitempty"`
+	Sets                     []*LiveSet `json:"sets,omitempty"` // Snapshot only; absent before the first set
+}
+
+// LiveSessionSnapshot is a session's owner and state when watching starts
+type LiveSessionSnapshot struct {
+	UserID string
+	Event  *LiveSessionEvent
+}
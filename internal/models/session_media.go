@@ -0,0 +1,55 @@
+package models
+
+import "time"
+
+// Session media types
+const (
+	MediaTypePhoto = "photo"
+	MediaTypeVideo = "video"
+)
+
+// SessionMedia is a photo or video attached to a workout session
+type SessionMedia struct {
+	ID              string    `json:"id"`
+	SessionID       string    `json:"session_id"`
+	UserID          string    `json:"user_id"`
+	MediaType       string    `json:"media_type"`
+	URL             string    `json:"url"`
+	ThumbnailURL    *string   `json:"thumbnail_url,omitempty"`
+	Caption         *string   `json:"caption,omitempty"`
+	DurationSeconds *int      `json:"duration_seconds,omitempty"`
+	Position        int       `json:"position"`
+	Highlight       bool      `json:"highlight"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// SessionHighlights is the ordered highlights reel of a session, used by share cards and feeds
+type SessionHighlights struct {
+	SessionID string          `json:"session_id"`
+	Name      *string         `json:"name,omitempty"`
+	StartedAt time.Time       `json:"started_at"`
+	Items     []*SessionMedia `json:"items"`
+}
+
+// CreateSessionMediaRequest represents the request body for attaching media to a session
+type CreateSessionMediaRequest struct {
+	MediaType       string  `json:"media_type" binding:"required,oneof=photo video"`
+	URL             string  `json:"url" binding:"required,url,max=2048"`
+	ThumbnailURL    *string `json:"thumbnail_url" binding:"omitempty,url,max=2048"`
+	Caption         *string `json:"caption" binding:"omitempty,max=280"`
+	DurationSeconds *int    `json:"duration_seconds" binding:"omitempty,min=1"`
+	Highlight       *bool   `json:"highlight"` // Defaults to true
+}
+
+// UpdateSessionMediaRequest represents the request body for editing a media item
+// Omitted fields are left unchanged.
+type UpdateSessionMediaRequest struct {
+	Caption   *string `json:"caption" binding:"omitempty,max=280"`
+	Highlight *bool   `json:"highlight"`
+}
+
+// ReorderSessionMediaRequest lists every media item of a session in its new order
+type ReorderSessionMediaRequest struct {
+	MediaIDs []string `json:"media_ids" binding:"required,min=1,dive,uuid"`
+}
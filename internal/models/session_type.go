@@ -0,0 +1,87 @@
+package models
+
+import "time"
+
+// Built-in session types; more can be registered without schema changes
+const (
+	SessionTypeStrength = "strength"
+	SessionTypeCardio   = "cardio" // Runs, rides and other distance activities, usually with laps
+	SessionTypeMobility = "mobility"
+	SessionTypeClass    = "class"
+	SessionTypeCustom   = "custom" // Free-form payload
+)
+
+// Payload field types
+const (
+	PayloadFieldNumber  = "number"
+	PayloadFieldInteger = "integer"
+	PayloadFieldString  = "string"
+	PayloadFieldBoolean = "boolean"
+	PayloadFieldEnum    = "enum"
+)
+
+// Payload field summaries, how analytics combine a numeric field across sessions
+const (
+	PayloadSummarySum = "sum"
+	PayloadSummaryAvg = "avg"
+	PayloadSummaryMax = "max"
+)
+
+// PayloadField describes one field of a session type's payload
+type PayloadField struct {
+	Type      string   `json:"type"`
+	Required  bool     `json:"required,omitempty"`
+	Min       *float64 `json:"min,omitempty"`        // number and integer
+	Max       *float64 `json:"max,omitempty"`        // number and integer
+	MaxLength int      `json:"max_length,omitempty"` // string
+	Values    []string `json:"values,omitempty"`     // enum
+	Unit      string   `json:"unit,omitempty"`
+	Summary   string   `json:"summary,omitempty"` // Aggregated in session type analytics (numeric fields)
+}
+
+// PayloadSchema describes the payload of a session type's sessions
+type PayloadSchema struct {
+	Fields           map[string]PayloadField `json:"fields"`
+	AdditionalFields bool                    `json:"additional_fields,omitempty"` // Accept fields not listed
+}
+
+// SessionType is an entry of the session type registry
+type SessionType struct {
+	Name          string        `json:"name"`
+	DisplayName   string        `json:"display_name"`
+	Description   *string       `json:"description,omitempty"`
+	PayloadSchema PayloadSchema `json:"payload_schema"`
+	Builtin       bool          `json:"builtin"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
+
+// CreateSessionTypeRequest represents the request body for registering a session type
+type CreateSessionTypeRequest struct {
+	Name          string        `json:"name" binding:"required"`
+	DisplayName   string        `json:"display_name" binding:"required,max=100"`
+	Description   *string       `json:"description" binding:"omitempty,max=500"`
+	PayloadSchema PayloadSchema `json:"payload_schema"`
+}
+
+// SetSessionTypeRequest represents the request body for setting a session's type and payload
+type SetSessionTypeRequest struct {
+	Type    string         `json:"type" binding:"required"`
+	Payload map[string]any `json:"payload"`
+}
+
+// SessionTypeAssignment is a session's type and validated payload
+type SessionTypeAssignment struct {
+	SessionID string         `json:"session_id"`
+	Type      string         `json:"type"`
+	Payload   map[string]any `json:"payload"`
+}
+
+// SessionTypeSummary aggregates a user's sessions of one type, including the payload fields
+// the type declares a summary for
+type SessionTypeSummary struct {
+	Type            string             `json:"type"`
+	Sessions        int                `json:"sessions"`
+	DurationMinutes int                `json:"duration_minutes"`
+	Metrics         map[string]float64 `json:"metrics"`
+}
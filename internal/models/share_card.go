@@ -0,0 +1,56 @@
+package models
+
+import "time"
+
+// Share card image formats
+const (
+	ShareCardFormatPNG = "png"
+	ShareCardFormatSVG = "svg"
+)
+
+// Share card statuses
+const (
+	ShareCardStatusPending = "pending"
+	ShareCardStatusReady   = "ready"
+	ShareCardStatusFailed  = "failed"
+)
+
+// ShareCard tracks an asynchronously rendered summary image of a session, for sharing
+type ShareCard struct {
+	ID          string     `json:"id"`
+	UserID      string     `json:"user_id"`
+	SessionID   string     `json:"session_id"`
+	Format      string     `json:"format"`
+	Status      string     `json:"status"`
+	Error       *string    `json:"error,omitempty"`
+	URL         string     `json:"url,omitempty"`            // Signed download link, once ready
+	URLExpires  *time.Time `json:"url_expires_at,omitempty"` // When url stops working
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+}
+
+// CreateShareCardRequest represents the request body for rendering a share card
+type CreateShareCardRequest struct {
+	Format string `json:"format" binding:"omitempty,oneof=png svg"` // Defaults to png
+}
+
+// ShareCardSummary is what a share card shows of a session
+type ShareCardSummary struct {
+	UserID          string
+	Status          string
+	Name            *string
+	SessionType     string
+	StartedAt       time.Time
+	DurationMinutes *int
+	VolumeKg        float64
+	Sets            int
+	Exercises       int
+	Records         []*ShareCardRecord
+}
+
+// ShareCardRecord is a personal record set in the session
+type ShareCardRecord struct {
+	ExerciseName string
+	WeightKg     float64
+	Reps         *int
+}
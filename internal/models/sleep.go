@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// Sleep log sources
+const (
+	SleepSourceManual     = "manual"
+	SleepSourceSleepCycle = "sleepcycle"
+	SleepSourceFitbit     = "fitbit"
+)
+
+// SleepLog is one night of sleep, or a nap
+type SleepLog struct {
+	ID              string    `json:"id"`
+	UserID          string    `json:"user_id"`
+	StartedAt       time.Time `json:"started_at"`
+	EndedAt         time.Time `json:"ended_at"`
+	DurationMinutes int       `json:"duration_minutes"`
+	Quality         *int      `json:"quality,omitempty"` // 1 (poor) to 5 (great)
+	Source          string    `json:"source"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// CreateSleepLogRequest represents the request body for logging sleep
+type CreateSleepLogRequest struct {
+	StartedAt time.Time `json:"started_at" binding:"required"`
+	EndedAt   time.Time `json:"ended_at" binding:"required"`
+	Quality   *int      `json:"quality" binding:"omitempty,min=1,max=5"`
+}
+
+// SleepImportResult summarizes a sleep tracker import
+type SleepImportResult struct {
+	Source   string `json:"source"`
+	Rows     int    `json:"rows"`
+	Imported int64  `json:"imported"`
+	Skipped  int64  `json:"skipped"` // Rows whose start was already recorded
+}
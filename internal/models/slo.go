@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// SLO alert severities
+const (
+	SLOSeverityPage   = "page"   // Fast burn: the error budget is gone within days
+	SLOSeverityTicket = "ticket" // Slow burn: the budget will not last the month
+)
+
+// SLOWindowReport is a route group's compliance over one window
+type SLOWindowReport struct {
+	Window     string  `json:"window"`
+	Requests   int64   `json:"requests"`
+	Good       int64   `json:"good"`
+	Compliance float64 `json:"compliance"` // 1 without requests
+	BurnRate   float64 `json:"burn_rate"`  // Error budget use relative to the objective; 1 spends it exactly
+}
+
+// SLOGroupReport is a route group's response time objective and how it is doing
+type SLOGroupReport struct {
+	Group       string            `json:"group"`
+	ThresholdMs int64             `json:"threshold_ms"`
+	Objective   float64           `json:"objective"`
+	Windows     []SLOWindowReport `json:"windows"`
+	P50Ms       *float64          `json:"p50_ms,omitempty"` // Last hour, upper histogram bucket bounds
+	P95Ms       *float64          `json:"p95_ms,omitempty"`
+	P99Ms       *float64          `json:"p99_ms,omitempty"`
+}
+
+// SLOAlert is a route group burning its error budget too fast
+type SLOAlert struct {
+	Group       string    `json:"group"`
+	Severity    string    `json:"severity"`
+	LongWindow  string    `json:"long_window"`
+	ShortWindow string    `json:"short_window"`
+	BurnRate    float64   `json:"burn_rate"` // Over the long window
+	Threshold   float64   `json:"threshold"`
+	Since       time.Time `json:"since"`
+}
+
+// SLOReport is the response time status of this API instance
+type SLOReport struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Groups      []*SLOGroupReport `json:"groups"`
+	Alerts      []*SLOAlert       `json:"alerts"` // Currently firing
+}
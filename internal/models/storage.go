@@ -0,0 +1,24 @@
+package models
+
+// TableStorage reports size and row statistics for one table
+type TableStorage struct {
+	Table      string `json:"table"`
+	RowCount   int64  `json:"row_count"` // Estimated live rows from pg_stat_user_tables
+	TotalBytes int64  `json:"total_bytes"`
+	TableBytes int64  `json:"table_bytes"`
+	IndexBytes int64  `json:"index_bytes"`
+}
+
+// UserStorage reports how much data a single user owns across user tables
+type UserStorage struct {
+	UserID   string `json:"user_id"`
+	RowCount int64  `json:"row_count"`
+	Bytes    int64  `json:"bytes"` // Sum of row sizes, excluding indexes and TOAST overhead
+}
+
+// StorageReport summarizes database usage for operators
+type StorageReport struct {
+	DatabaseBytes int64           `json:"database_bytes"`
+	Tables        []*TableStorage `json:"tables"`
+	LargestUsers  []*UserStorage  `json:"largest_users"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// TrainingMax is a named reference load that templates prescribe percentages of
+type TrainingMax struct {
+	ID         string    `json:"id"`
+	UserID     string    `json:"user_id"`
+	Name       string    `json:"name"`
+	ExerciseID *string   `json:"exercise_id,omitempty"`
+	WeightKg   float64   `json:"weight_kg"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// SetTrainingMaxRequest represents the request body for creating or updating a training max
+type SetTrainingMaxRequest struct {
+	WeightKg   float64 `json:"weight_kg" binding:"required,gt=0,lte=1000"`
+	ExerciseID *string `json:"exercise_id" binding:"omitempty,uuid"`
+}
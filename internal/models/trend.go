@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Trend metrics
+const (
+	TrendMetricSessions       = "sessions"          // Completed sessions
+	TrendMetricVolume         = "volume_kg"         // Sets x reps x weight lifted
+	TrendMetricCardioDistance = "cardio_distance_m" // Distance logged
+	TrendMetricBodyWeight     = "body_weight_kg"    // Average weigh-in
+)
+
+// TrendMetrics lists every metric with trends and monthly snapshots
+var TrendMetrics = []string{TrendMetricSessions, TrendMetricVolume, TrendMetricCardioDistance, TrendMetricBodyWeight}
+
+// Trend granularities
+const (
+	TrendGranularityWeek  = "week"
+	TrendGranularityMonth = "month"
+)
+
+// TrendPoint is a metric's value over one week or month
+type TrendPoint struct {
+	PeriodStart string  `json:"period_start"` // Monday or first of the month, YYYY-MM-DD (UTC)
+	Value       float64 `json:"value"`
+	Samples     int     `json:"samples"`
+}
+
+// Trend is a metric over a date range; periods without data are omitted
+type Trend struct {
+	Metric      string        `json:"metric"`
+	Granularity string        `json:"granularity"`
+	From        string        `json:"from"`
+	To          string        `json:"to"`
+	Points      []*TrendPoint `json:"points"`
+}
+
+// TrendChange is the earliest point in a user's history changed since snapshots were last refreshed
+type TrendChange struct {
+	UserID string
+	From   time.Time // Zero when the whole history changed, e.g. with the user's timezone
+}
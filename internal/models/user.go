@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// User is a Supabase auth account as operators see it
+type User struct {
+	ID             string     `json:"id"`
+	Email          string     `json:"email"`
+	IsAdmin        bool       `json:"is_admin"` // app_metadata.role = "admin"
+	CreatedAt      time.Time  `json:"created_at"`
+	LastSignInAt   *time.Time `json:"last_sign_in_at,omitempty"`
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"` // Supabase banned_until, while in the future
+}
+
+// UserFilter narrows a user listing
+type UserFilter struct {
+	Email string // Case-insensitive substring of the email; empty matches everyone
+	Limit int
+}
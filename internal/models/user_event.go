@@ -0,0 +1,32 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// User events pushed to the user's connected clients
+// Personal records and coach assignments come from the database triggers of migration 027,
+// new notifications from those of migration 030.
+const (
+	UserEventPRAchieved          = "pr.achieved"
+	UserEventWorkoutAssigned     = "workout.assigned"
+	UserEventImportFinished      = "import.finished"
+	UserEventNotificationCreated = "notification.created"
+)
+
+// UserEvent is something that happened to a user's account
+type UserEvent struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	UserID    string          `json:"user_id"`
+	CreatedAt time.Time       `json:"created_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// ImportFinishedEvent is the data of an import.finished event
+type ImportFinishedEvent struct {
+	Source          string `json:"source"` // Import format or integration provider
+	SessionsCreated int    `json:"sessions_created"`
+	SessionsUpdated int    `json:"sessions_updated"`
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Webhook events; the database triggers of migration 025 record them
+const (
+	WebhookEventSessionCompleted = "session.completed"
+	WebhookEventPRAchieved       = "pr.achieved"
+	WebhookEventWorkoutCreated   = "workout.created"
+)
+
+// WebhookEvents lists the events endpoints may subscribe to
+var WebhookEvents = []string{WebhookEventSessionCompleted, WebhookEventPRAchieved, WebhookEventWorkoutCreated}
+
+// Webhook delivery statuses
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // Gave up after too many attempts
+)
+
+// WebhookEndpoint is a URL a user registered to receive events
+type WebhookEndpoint struct {
+	ID          string    `json:"id"`
+	UserID      string    `json:"user_id"`
+	URL         string    `json:"url"`
+	Secret      string    `json:"secret,omitempty"` // Only returned when the endpoint is created
+	Events      []string  `json:"events"`
+	Description *string   `json:"description,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CreateWebhookEndpointRequest represents the request body for registering a webhook endpoint
+type CreateWebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required,url,max=2000"`
+	Events      []string `json:"events" binding:"required,min=1,dive,required"`
+	Description *string  `json:"description" binding:"omitempty,max=200"`
+}
+
+// WebhookDelivery is one event sent, or being sent, to an endpoint
+type WebhookDelivery struct {
+	ID             string     `json:"id"`
+	EndpointID     string     `json:"endpoint_id"`
+	EventID        string     `json:"event_id"`
+	Event          string     `json:"event"`
+	Status         string     `json:"status"`
+	Attempts       int        `json:"attempts"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // While pending
+	ResponseStatus *int       `json:"response_status,omitempty"`
+	LastError      *string    `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// WebhookMessage is a delivery with what is needed to send it
+type WebhookMessage struct {
+	DeliveryID     string
+	URL            string
+	Secret         string
+	EventID        string
+	Event          string
+	Data           json.RawMessage
+	EventCreatedAt time.Time
+}
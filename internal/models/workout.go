@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// Workout is a workout template with its exercises in order
+type Workout struct {
+	ID             string             `json:"id"`
+	UserID         string             `json:"user_id"`
+	OrganizationID *string            `json:"organization_id,omitempty"`
+	Name           string             `json:"name"`
+	Description    string             `json:"description"`
+	ImageURL       *string            `json:"image_url,omitempty"`
+	Exercises      []*WorkoutExercise `json:"exercises"`
+	CreatedAt      time.Time          `json:"created_at"`
+	UpdatedAt      time.Time          `json:"updated_at"`
+	Version        int64              `json:"version"` // Bumped by every update
+}
+
+// WorkoutSummary is a workout template as listed, without its exercises
+type WorkoutSummary struct {
+	ID             string    `json:"id"`
+	UserID         string    `json:"user_id"`
+	OrganizationID *string   `json:"organization_id,omitempty"`
+	Name           string    `json:"name"`
+	Description    string    `json:"description"`
+	ImageURL       *string   `json:"image_url,omitempty"`
+	ExerciseCount  int       `json:"exercise_count"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+	Version        int64     `json:"version"`
+}
+
+// ShareWorkoutRequest represents the request body for sharing a workout in an organization
+type ShareWorkoutRequest struct {
+	WorkoutID string `json:"workout_id" binding:"required,uuid"` // One of the caller's personal workouts
+}
+
+// WorkoutExercise is one planned exercise of a workout with the equipment it needs
+type WorkoutExercise struct {
+	ID                  string       `json:"id"`
+	OrderIndex          int          `json:"order_index"`
+	Sets                *int         `json:"sets,omitempty"`
+	Reps                *int         `json:"reps,omitempty"`
+	WeightKg            *float64     `json:"weight_kg,omitempty"`
+	DurationSeconds     *int         `json:"duration_seconds,omitempty"`
+	DistanceMeters      *float64     `json:"distance_meters,omitempty"`
+	RestTimeSeconds     *int         `json:"rest_time_seconds,omitempty"`
+	IntensityPercentage *float64     `json:"intensity_percentage,omitempty"` // % of the training max
+	TrainingMaxName     *string      `json:"training_max_name,omitempty"`
+	ProgressionScheme   *string      `json:"progression_scheme,omitempty"` // "linear", "531" or "double"
+	MinReps             *int         `json:"min_reps,omitempty"`           // Bottom of the double progression rep range
+	Tempo               *string      `json:"tempo,omitempty"`
+	TargetRPE           *int         `json:"target_rpe,omitempty"`
+	Notes               *string      `json:"notes,omitempty"`
+	SupersetGroupID     *string      `json:"superset_group_id,omitempty"`
+	IsDropset           bool         `json:"is_dropset"`
+	IsWarmup            bool         `json:"is_warmup"`
+	IsCooldown          bool         `json:"is_cooldown"`
+	Exercise            *Exercise    `json:"exercise"`
+	Equipment           []*Equipment `json:"equipment"`
+}
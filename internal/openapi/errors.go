@@ -0,0 +1,182 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+)
+
+// categorySet is a set of domainerr categories
+type categorySet map[types.Object]bool
+
+// funcSource is a parsed function and the type information of its package
+type funcSource struct {
+	decl *ast.FuncDecl
+	info *types.Info
+}
+
+// domainErrors finds the domainerr categories functions of the handlers and services
+// packages can return, from the sentinels they reference and the functions they call, so
+// an operation documents only the error statuses its service calls can produce
+type domainErrors struct {
+	path      string                        // Import path of the domainerr package
+	funcs     map[*types.Func]funcSource    // Functions that can be followed
+	sentinels map[types.Object]types.Object // Sentinel variable to its category
+	returns   map[*types.Func]categorySet   // Memoized categories per function
+	methods   map[string][]*types.Func      // Followed methods by name, for interface calls
+}
+
+func newDomainErrors(path string) *domainErrors {
+	return &domainErrors{
+		path:      path,
+		funcs:     make(map[*types.Func]funcSource),
+		sentinels: make(map[types.Object]types.Object),
+		returns:   make(map[*types.Func]categorySet),
+		methods:   make(map[string][]*types.Func),
+	}
+}
+
+// addPackage registers a package's functions and its sentinels, the package-level
+// variables set with domainerr.New
+func (d *domainErrors) addPackage(files []*ast.File, info *types.Info) {
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				if decl.Body == nil {
+					continue
+				}
+				fn := info.Defs[decl.Name].(*types.Func)
+				d.funcs[fn] = funcSource{decl: decl, info: info}
+				if decl.Recv != nil {
+					d.methods[fn.Name()] = append(d.methods[fn.Name()], fn)
+				}
+			case *ast.GenDecl:
+				for _, spec := range decl.Specs {
+					value, ok := spec.(*ast.ValueSpec)
+					if !ok || len(value.Names) != len(value.Values) {
+						continue
+					}
+					for i, name := range value.Names {
+						if category := d.newCategory(value.Values[i], info); category != nil {
+							d.sentinels[info.Defs[name]] = category
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+// newCategory returns the category of a domainerr.New call, or nil for other expressions
+func (d *domainErrors) newCategory(expr ast.Expr, info *types.Info) types.Object {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) == 0 {
+		return nil
+	}
+	fn, ok := calledFunc(info, call)
+	if !ok || fn.Pkg() == nil || fn.Pkg().Path() != d.path || fn.Name() != "New" {
+		return nil
+	}
+	if obj := referenced(info, call.Args[0]); d.isCategory(obj) {
+		return obj
+	}
+	return nil
+}
+
+// isCategory reports whether obj is one of the domainerr categories
+func (d *domainErrors) isCategory(obj types.Object) bool {
+	v, ok := obj.(*types.Var)
+	return ok && v.Pkg() != nil && v.Pkg().Path() == d.path && v.Parent() == v.Pkg().Scope()
+}
+
+// of returns the categories a function can return
+func (d *domainErrors) of(fn *types.Func) categorySet {
+	if set, ok := d.returns[fn]; ok {
+		return set
+	}
+	set := make(categorySet)
+	d.returns[fn] = set // Recursive calls see what is found so far
+	if src, ok := d.funcs[fn]; ok {
+		for category := range d.body(src.decl.Body, src.info) {
+			set[category] = true
+		}
+	}
+	return set
+}
+
+// body returns the categories of the sentinels a block references and the functions it
+// calls. Sentinels an error is only compared with, in errors.Is, == or a switch case, are
+// not returned, so they don't count.
+func (d *domainErrors) body(body *ast.BlockStmt, info *types.Info) categorySet {
+	set := make(categorySet)
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.CaseClause:
+			for category := range d.body(&ast.BlockStmt{List: n.Body}, info) {
+				set[category] = true
+			}
+			return false
+		case *ast.BinaryExpr:
+			if n.Op == token.EQL || n.Op == token.NEQ {
+				return false
+			}
+		case *ast.Ident:
+			obj := info.Uses[n]
+			if category, ok := d.sentinels[obj]; ok {
+				set[category] = true
+			} else if d.isCategory(obj) {
+				set[obj] = true
+			}
+		case *ast.CallExpr:
+			fn, ok := calledFunc(info, n)
+			if !ok {
+				return true
+			}
+			if fn.Pkg() != nil && fn.Pkg().Path() == "errors" && fn.Name() == "Is" {
+				return false
+			}
+			for _, callee := range d.callees(fn) {
+				for category := range d.of(callee) {
+					set[category] = true
+				}
+			}
+		}
+		return true
+	})
+	return set
+}
+
+// callees resolves a called function to those that can be followed: itself, or for an
+// interface method the followed methods of types implementing the interface
+func (d *domainErrors) callees(fn *types.Func) []*types.Func {
+	if _, ok := d.funcs[fn]; ok {
+		return []*types.Func{fn}
+	}
+	recv := fn.Type().(*types.Signature).Recv()
+	if recv == nil {
+		return nil
+	}
+	iface, ok := recv.Type().Underlying().(*types.Interface)
+	if !ok {
+		return nil
+	}
+	var callees []*types.Func
+	for _, method := range d.methods[fn.Name()] {
+		if types.Implements(method.Type().(*types.Signature).Recv().Type(), iface) {
+			callees = append(callees, method)
+		}
+	}
+	return callees
+}
+
+// referenced returns the object an identifier or qualified identifier refers to
+func referenced(info *types.Info, expr ast.Expr) types.Object {
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		return info.Uses[expr]
+	case *ast.SelectorExpr:
+		return info.Uses[expr.Sel]
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/constant"
+	"go/types"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const ginContext = "*github.com/gin-gonic/gin.Context"
+
+// response is what a handler writes for a status
+type response struct {
+	contentType string
+	schemas     []any // Distinct bodies; more than one becomes oneOf
+	isError     bool  // Every body is the error envelope
+}
+
+// queryParam is a query parameter a handler reads
+type queryParam struct {
+	name   string
+	schema *mapping
+}
+
+// operation is what a handler reads and writes, found from its body
+type operation struct {
+	query       []*queryParam
+	body        any               // JSON request body schema
+	files       []string          // Multipart file fields
+	responses   map[int]*response // By status
+	contentType string            // Set with c.Header("Content-Type", ...), for non-JSON success responses
+	streams     bool              // Server-Sent Events
+	categories  categorySet       // Domain error categories the handler's service calls can return
+}
+
+// handlerPackage is the parsed and type-checked handlers package
+type handlerPackage struct {
+	info  *types.Info
+	funcs map[*types.Func]*ast.FuncDecl
+}
+
+// analyzer finds operations in handler bodies
+type analyzer struct {
+	schemas *schemas
+	pkg     *handlerPackage
+	errors  *domainErrors
+}
+
+// analyze reads a handler's body, following calls into functions of the handlers package
+// that are passed the request context, e.g. shared error responses
+func (a *analyzer) analyze(body *ast.BlockStmt, info *types.Info) *operation {
+	op := &operation{responses: make(map[int]*response), categories: a.errors.body(body, info)}
+	a.walk(body, info, op, make(map[*ast.FuncDecl]bool))
+	return op
+}
+
+func (a *analyzer) walk(body *ast.BlockStmt, info *types.Info, op *operation, visited map[*ast.FuncDecl]bool) {
+	ast.Inspect(body, func(n ast.Node) bool {
+		// Cases of a switch on an error's category only answer the categories the handler
+		// can meet
+		if clause, ok := n.(*ast.CaseClause); ok {
+			return !a.unreachableCategories(clause, info, op)
+		}
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		if selector, ok := call.Fun.(*ast.SelectorExpr); ok && isContext(info, selector.X) {
+			a.contextCall(selector.Sel.Name, call, info, body, op)
+			return true
+		}
+		if fn, ok := calledFunc(info, call); ok {
+			if fn.Pkg() != nil && fn.Pkg().Path() == "net/http" && fn.Name() == "ServeContent" {
+				op.addResponse(http.StatusOK, nil, false)
+				return true
+			}
+			// Helpers in the handlers package that take the context write responses too
+			if decl, ok := a.pkg.funcs[fn]; ok && !visited[decl] && passesContext(info, call) {
+				visited[decl] = true
+				a.walk(decl.Body, a.pkg.info, op, visited)
+			}
+		}
+		return true
+	})
+}
+
+// unreachableCategories reports whether a case clause lists only domain error categories
+// none of which the operation's service calls return
+func (a *analyzer) unreachableCategories(clause *ast.CaseClause, info *types.Info, op *operation) bool {
+	if len(clause.List) == 0 {
+		return false
+	}
+	for _, expr := range clause.List {
+		obj := referenced(info, expr)
+		if !a.errors.isCategory(obj) || op.categories[obj] {
+			return false
+		}
+	}
+	return true
+}
+
+// contextCall records a call on the request's *gin.Context
+func (a *analyzer) contextCall(method string, call *ast.CallExpr, info *types.Info, body *ast.BlockStmt, op *operation) {
+	switch method {
+	case "JSON", "AbortWithStatusJSON", "IndentedJSON", "PureJSON":
+		if len(call.Args) != 2 {
+			return
+		}
+		status, ok := intConstant(info, call.Args[0])
+		if !ok {
+			return
+		}
+		schema, isError := a.bodySchema(call.Args[1], info)
+		op.addResponse(status, schema, isError)
+
+	case "Status", "AbortWithStatus":
+		if status, ok := intConstant(info, call.Args[0]); ok {
+			op.addResponse(status, nil, false)
+		}
+
+	case "Header":
+		if len(call.Args) == 2 && stringLit(call.Args[0]) == "Content-Type" {
+			op.contentType = stringLit(call.Args[1])
+		}
+
+	case "SSEvent", "Stream":
+		op.streams = true
+
+	case "Query", "DefaultQuery", "GetQuery", "QueryArray":
+		if len(call.Args) == 0 {
+			return
+		}
+		name := stringLit(call.Args[0])
+		if name == "" || op.hasQuery(name) {
+			return
+		}
+		schema := queryType(call, info, body)
+		if method == "QueryArray" {
+			schema = newMapping().set("type", "array").set("items", schema)
+		}
+		if method == "DefaultQuery" && len(call.Args) == 2 {
+			if value := stringLit(call.Args[1]); value != "" {
+				schema.set("default", value)
+			}
+		}
+		op.query = append(op.query, &queryParam{name: name, schema: schema})
+
+	case "ShouldBindJSON", "BindJSON", "ShouldBind", "Bind":
+		if len(call.Args) == 1 {
+			op.body = a.schemas.of(deref(info.TypeOf(call.Args[0])))
+		}
+
+	case "FormFile":
+		if len(call.Args) == 1 {
+			op.files = append(op.files, stringLit(call.Args[0]))
+		}
+	}
+}
+
+// bodySchema is the schema of a JSON response body. gin.H literals become objects of
+// their keys; those with an "error" key are the error envelope.
+func (a *analyzer) bodySchema(expr ast.Expr, info *types.Info) (any, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok || info.TypeOf(expr).String() != "github.com/gin-gonic/gin.H" {
+		return a.schemas.of(info.TypeOf(expr)), false
+	}
+
+	properties := newMapping()
+	var required []any
+	for _, elt := range lit.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			continue
+		}
+		key := stringLit(kv.Key)
+		if key == "error" {
+			return ref("Error"), true
+		}
+		properties.set(key, a.schemas.of(info.TypeOf(kv.Value)))
+		required = append(required, key)
+	}
+	schema := newMapping().set("type", "object")
+	if len(required) > 0 {
+		schema.set("properties", properties).set("required", required)
+	}
+	return schema, false
+}
+
+func (op *operation) addResponse(status int, schema any, isError bool) {
+	r, ok := op.responses[status]
+	if !ok {
+		r = &response{isError: true}
+		op.responses[status] = r
+	}
+	r.isError = r.isError && isError
+	if schema == nil {
+		return
+	}
+	for _, existing := range r.schemas {
+		if sameSchema(existing, schema) {
+			return
+		}
+	}
+	r.schemas = append(r.schemas, schema)
+}
+
+func (op *operation) hasQuery(name string) bool {
+	for _, q := range op.query {
+		if q.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// statuses lists the operation's statuses in order
+func (op *operation) statuses() []int {
+	statuses := make([]int, 0, len(op.responses))
+	for status := range op.responses {
+		statuses = append(statuses, status)
+	}
+	sort.Ints(statuses)
+	return statuses
+}
+
+// queryType guesses a query parameter's type from how the handler parses it: the value
+// or the variable holding it passed to strconv or time parsing, or compared with "true"
+func queryType(call *ast.CallExpr, info *types.Info, body *ast.BlockStmt) *mapping {
+	var variable types.Object
+	ast.Inspect(body, func(n ast.Node) bool {
+		assign, ok := n.(*ast.AssignStmt)
+		if !ok || len(assign.Rhs) != 1 || assign.Rhs[0] != call {
+			return true
+		}
+		if ident, ok := assign.Lhs[0].(*ast.Ident); ok {
+			variable = info.ObjectOf(ident)
+		}
+		return false
+	})
+	isValue := func(expr ast.Expr) bool {
+		if expr == call {
+			return true
+		}
+		ident, ok := expr.(*ast.Ident)
+		return ok && variable != nil && info.ObjectOf(ident) == variable
+	}
+
+	schema := newMapping().set("type", "string")
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.BinaryExpr:
+			if (isValue(n.X) && stringLit(n.Y) == "true") || (isValue(n.Y) && stringLit(n.X) == "true") {
+				schema.set("type", "boolean")
+			}
+		case *ast.CallExpr:
+			fn, ok := calledFunc(info, n)
+			if !ok || fn.Pkg() == nil {
+				return true
+			}
+			for _, arg := range n.Args {
+				if !isValue(arg) {
+					continue
+				}
+				switch fn.Pkg().Path() + "." + fn.Name() {
+				case "strconv.Atoi", "strconv.ParseInt", "strconv.ParseUint":
+					schema.set("type", "integer")
+				case "strconv.ParseFloat":
+					schema.set("type", "number")
+				case "strconv.ParseBool":
+					schema.set("type", "boolean")
+				case "time.Parse":
+					if layout, ok := stringConstant(info, n.Args[0]); ok && layout == "2006-01-02" {
+						schema.set("format", "date")
+					} else {
+						schema.set("format", "date-time")
+					}
+				case "time.ParseDuration":
+					schema.set("format", "duration")
+				case "time.LoadLocation":
+					schema.set("description", "IANA time zone, e.g. Europe/Madrid")
+				}
+			}
+		}
+		return true
+	})
+	return schema
+}
+
+func isContext(info *types.Info, expr ast.Expr) bool {
+	t := info.TypeOf(expr)
+	return t != nil && t.String() == ginContext
+}
+
+// passesContext reports whether a call is given a *gin.Context
+func passesContext(info *types.Info, call *ast.CallExpr) bool {
+	for _, arg := range call.Args {
+		if isContext(info, arg) {
+			return true
+		}
+	}
+	return false
+}
+
+// calledFunc returns the function or method a call invokes
+func calledFunc(info *types.Info, call *ast.CallExpr) (*types.Func, bool) {
+	var ident *ast.Ident
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		ident = fun
+	case *ast.SelectorExpr:
+		ident = fun.Sel
+	default:
+		return nil, false
+	}
+	fn, ok := info.Uses[ident].(*types.Func)
+	if ok {
+		fn = fn.Origin()
+	}
+	return fn, ok
+}
+
+func intConstant(info *types.Info, expr ast.Expr) (int, bool) {
+	value := info.Types[expr].Value
+	if value == nil || value.Kind() != constant.Int {
+		return 0, false
+	}
+	n, ok := constant.Int64Val(value)
+	return int(n), ok
+}
+
+func stringConstant(info *types.Info, expr ast.Expr) (string, bool) {
+	value := info.Types[expr].Value
+	if value == nil || value.Kind() != constant.String {
+		return "", false
+	}
+	return constant.StringVal(value), true
+}
+
+// sameSchema compares schemas by their YAML
+func sameSchema(a, b any) bool {
+	return yamlString(a) == yamlString(b)
+}
+
+func yamlString(v any) string {
+	var s stringWriter
+	writeYAML(&s, v)
+	return string(s)
+}
+
+type stringWriter []byte
+
+func (w *stringWriter) Write(p []byte) (int, error) {
+	*w = append(*w, p...)
+	return len(p), nil
+}
+
+// statusText is the description of a response without a better one
+func statusText(status int) string {
+	if text := http.StatusText(status); text != "" {
+		return text
+	}
+	return strconv.Itoa(status)
+}
@@ -0,0 +1,511 @@
+// Package openapi generates the API's OpenAPI 3.1 document from its code: the routes
+// registered in cmd/api, the middleware guarding them, and what their handlers bind and
+// write. It reads the source with go/parser and go/types rather than annotations, so the
+// document can't drift from the router; regenerate it with go generate ./cmd/api.
+//
+// Handlers are read statically. JSON bodies come from the types passed to ShouldBindJSON
+// and c.JSON, gin.H literals with an "error" key are the error envelope, and query
+// parameters are typed by how the handler parses them.
+package openapi
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Version is the document's info.version
+const Version = "1.0.0"
+
+// Generate writes the OpenAPI document of the API in the module at root as YAML
+func Generate(root string, w io.Writer) error {
+	document, err := build(root)
+	if err != nil {
+		return err
+	}
+	return writeYAML(w, document)
+}
+
+func build(root string) (*mapping, error) {
+	modulePath, err := readModulePath(filepath.Join(root, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	imports := &packageImporter{
+		fallback: importer.ForCompiler(fset, "source", nil),
+		packages: make(map[string]*types.Package),
+	}
+
+	// Services are checked from source, not imported, so their sentinels can be followed
+	servicesPath := modulePath + "/internal/services"
+	serviceFiles, err := parsePackage(fset, filepath.Join(root, "internal", "services"))
+	if err != nil {
+		return nil, err
+	}
+	serviceInfo, servicePkg, err := check(fset, servicesPath, serviceFiles, imports)
+	if err != nil {
+		return nil, err
+	}
+	imports.packages[servicesPath] = servicePkg
+
+	handlersPath := modulePath + "/internal/handlers"
+	handlerFiles, err := parsePackage(fset, filepath.Join(root, "internal", "handlers"))
+	if err != nil {
+		return nil, err
+	}
+	handlerInfo, handlerPkg, err := check(fset, handlersPath, handlerFiles, imports)
+	if err != nil {
+		return nil, err
+	}
+	imports.packages[handlersPath] = handlerPkg
+
+	mainFiles, err := parsePackage(fset, filepath.Join(root, "cmd", "api"))
+	if err != nil {
+		return nil, err
+	}
+	mainInfo, _, err := check(fset, modulePath+"/cmd/api", mainFiles, imports)
+	if err != nil {
+		return nil, err
+	}
+
+	pkg := &handlerPackage{info: handlerInfo, funcs: make(map[*types.Func]*ast.FuncDecl)}
+	for _, file := range handlerFiles {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				pkg.funcs[handlerInfo.Defs[fn.Name].(*types.Func)] = fn
+			}
+		}
+	}
+
+	errs := newDomainErrors(modulePath + "/internal/domainerr")
+	errs.addPackage(serviceFiles, serviceInfo)
+	errs.addPackage(handlerFiles, handlerInfo)
+
+	var routes []*route
+	for _, file := range mainFiles {
+		for _, decl := range file.Decls {
+			if fn, ok := decl.(*ast.FuncDecl); ok && fn.Body != nil {
+				routes = append(routes, findRoutes(fn.Body)...)
+			}
+		}
+	}
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no routes registered in %s", filepath.Join(root, "cmd", "api"))
+	}
+
+	g := &generator{
+		analyzer:     &analyzer{schemas: newSchemas(), pkg: pkg, errors: errs},
+		mainInfo:     mainInfo,
+		paths:        newMapping(),
+		operationIDs: make(map[string]bool),
+	}
+	for _, r := range routes {
+		if err := g.add(r); err != nil {
+			return nil, err
+		}
+	}
+	return g.document(), nil
+}
+
+// generator collects the document's paths
+type generator struct {
+	analyzer     *analyzer
+	mainInfo     *types.Info
+	paths        *mapping
+	operationIDs map[string]bool
+}
+
+func (g *generator) add(r *route) error {
+	var op *operation
+	var name, doc string
+	switch handler := r.handler.(type) {
+	case *ast.FuncLit:
+		op = g.analyzer.analyze(handler.Body, g.mainInfo)
+		name = pathName(r.path)
+	case *ast.SelectorExpr:
+		fn, ok := g.mainInfo.Uses[handler.Sel].(*types.Func)
+		if !ok {
+			return fmt.Errorf("%s %s: handler %s is not a function", r.method, r.path, handler.Sel.Name)
+		}
+		decl, ok := g.analyzer.pkg.funcs[fn]
+		if !ok {
+			return fmt.Errorf("%s %s: handler %s is not in the handlers package", r.method, r.path, handler.Sel.Name)
+		}
+		op = g.analyzer.analyze(decl.Body, g.analyzer.pkg.info)
+		name = fn.Name()
+		if recv := fn.Type().(*types.Signature).Recv(); recv != nil {
+			if named, ok := deref(recv.Type()).(*types.Named); ok {
+				if prefix := strings.TrimSuffix(named.Obj().Name(), "Handler"); !strings.HasPrefix(name, prefix) {
+					name = prefix + name
+				}
+			}
+		}
+		doc = description(decl.Doc)
+	default:
+		return fmt.Errorf("%s %s: unsupported handler expression", r.method, r.path)
+	}
+
+	path, params := openAPIPath(r.path)
+	item, ok := g.paths.get(path)
+	if !ok {
+		item = newMapping()
+		g.paths.set(path, item)
+	}
+	item.(*mapping).set(strings.ToLower(r.method), g.operation(r, op, name, doc, params))
+	return nil
+}
+
+// operation builds the Operation object of a route
+func (g *generator) operation(r *route, op *operation, name, doc string, pathParams []string) *mapping {
+	operationID := lowerFirst(name)
+	for i := 2; g.operationIDs[operationID]; i++ {
+		operationID = lowerFirst(name) + strconv.Itoa(i)
+	}
+	g.operationIDs[operationID] = true
+
+	result := newMapping().set("tags", []any{tag(r.path)}).set("summary", humanize(name))
+	if r.admin {
+		doc = strings.TrimSpace(doc + "\n\nRequires an admin or service token.")
+	}
+	if doc != "" {
+		result.set("description", doc)
+	}
+	result.set("operationId", operationID)
+
+	var parameters []any
+	uuidParams := strings.Fields(r.uuidParams)
+	for _, param := range pathParams {
+		schema := newMapping().set("type", "string")
+		if slices.Contains(uuidParams, param) {
+			schema.set("format", "uuid")
+			op.addResponse(http.StatusBadRequest, ref("Error"), true)
+		}
+		parameters = append(parameters, newMapping().
+			set("name", param).
+			set("in", "path").
+			set("required", true).
+			set("schema", schema))
+	}
+	for _, q := range op.query {
+		parameters = append(parameters, newMapping().set("name", q.name).set("in", "query").set("schema", q.schema))
+	}
+	if r.orgContext {
+		parameters = append(parameters, newMapping().set("$ref", "#/components/parameters/OrgId"))
+	}
+	idempotent := r.idempotent && r.method == http.MethodPost
+	if idempotent {
+		parameters = append(parameters, newMapping().set("$ref", "#/components/parameters/IdempotencyKey"))
+	}
+	if len(parameters) > 0 {
+		result.set("parameters", parameters)
+	}
+
+	switch {
+	case len(op.files) > 0:
+		properties := newMapping()
+		var required []any
+		for _, field := range op.files {
+			properties.set(field, newMapping().set("type", "string").set("format", "binary"))
+			required = append(required, field)
+		}
+		schema := newMapping().set("type", "object").set("properties", properties).set("required", required)
+		result.set("requestBody", newMapping().
+			set("required", true).
+			set("content", newMapping().set("multipart/form-data", newMapping().set("schema", schema))))
+	case op.body != nil:
+		result.set("requestBody", newMapping().
+			set("required", true).
+			set("content", newMapping().set("application/json", newMapping().set("schema", op.body))))
+	}
+
+	// Middleware rejects requests before the handler runs
+	if r.auth != authNone {
+		op.addResponse(http.StatusUnauthorized, ref("Error"), true) // Optional auth still rejects invalid tokens
+	}
+	if r.scope != "" || r.admin {
+		op.addResponse(http.StatusForbidden, ref("Error"), true)
+	}
+	if r.orgContext {
+		op.addResponse(http.StatusBadRequest, ref("Error"), true)
+		op.addResponse(http.StatusForbidden, ref("Error"), true)
+	}
+	if idempotent {
+		op.addResponse(http.StatusConflict, ref("Error"), true)
+		op.addResponse(http.StatusUnprocessableEntity, ref("Error"), true)
+	}
+	if r.websocket {
+		op.addResponse(http.StatusSwitchingProtocols, nil, false)
+	}
+	if r.rateLimited {
+		op.addResponse(http.StatusTooManyRequests, nil, true)
+	}
+
+	responses := newMapping()
+	for _, status := range op.statuses() {
+		if status == http.StatusTooManyRequests && r.rateLimited {
+			responses.set("429", newMapping().set("$ref", "#/components/responses/TooManyRequests"))
+			continue
+		}
+		responses.set(strconv.Itoa(status), g.response(status, op))
+	}
+	if len(responses.keys) == 0 {
+		responses.set("default", newMapping().set("description", "Response"))
+	}
+	result.set("responses", responses)
+
+	switch r.auth {
+	case authRequired:
+		result.set("security", []any{newMapping().set("bearerAuth", scopes(r))})
+	case authOptional:
+		result.set("security", []any{newMapping(), newMapping().set("bearerAuth", scopes(r))})
+	}
+	return result
+}
+
+// response builds the Response object of one of an operation's statuses
+func (g *generator) response(status int, op *operation) *mapping {
+	r := op.responses[status]
+	result := newMapping().set("description", statusText(status))
+	success := status >= 200 && status < 300
+
+	switch {
+	case success && op.streams:
+		result.set("content", newMapping().set("text/event-stream", newMapping().set("schema", newMapping().set("type", "string"))))
+	case success && op.contentType != "" && len(r.schemas) == 0:
+		result.set("content", newMapping().set(op.contentType, newMapping().set("schema", newMapping().set("type", "string").set("format", "binary"))))
+	case len(r.schemas) == 1:
+		result.set("content", newMapping().set("application/json", newMapping().set("schema", r.schemas[0])))
+	case len(r.schemas) > 1:
+		result.set("content", newMapping().set("application/json", newMapping().set("schema", newMapping().set("oneOf", r.schemas))))
+	}
+	return result
+}
+
+// document assembles the OpenAPI document
+func (g *generator) document() *mapping {
+	components := g.analyzer.schemas.components
+	components["Error"] = newMapping().
+		set("type", "object").
+		set("description", "Error envelope of every failed request. Some errors add fields, e.g. required_scope on 403s from scoped tokens.").
+		set("properties", newMapping().
+			set("error", newMapping().set("type", "string")).
+			set("fields", newMapping().
+				set("type", "array").
+				set("description", "Invalid fields of a 400's request body").
+				set("items", newMapping().
+					set("type", "object").
+					set("properties", newMapping().
+						set("field", newMapping().set("type", "string").set("description", "Path in the JSON body, e.g. exercises[0].reps")).
+						set("rule", newMapping().set("type", "string").set("description", "Binding rule the value failed, e.g. required or max; type for a value of the wrong type")).
+						set("message", newMapping().set("type", "string"))).
+					set("required", []any{"field", "rule", "message"})))).
+		set("required", []any{"error"}).
+		set("additionalProperties", true)
+
+	return newMapping().
+		set("openapi", "3.1.0").
+		set("info", newMapping().
+			set("title", "fitapi").
+			set("version", Version).
+			set("description", "Generated from the router and handlers by go generate ./cmd/api. Do not edit.")).
+		set("paths", g.paths).
+		set("components", newMapping().
+			set("schemas", map[string]any(components)).
+			set("parameters", newMapping().set("OrgId", newMapping().
+				set("name", "X-Org-Id").
+				set("in", "header").
+				set("description", "Act within an organization the caller is a member of; without it requests use the personal scope").
+				set("schema", newMapping().set("type", "string").set("format", "uuid"))).
+				set("IdempotencyKey", newMapping().
+					set("name", "Idempotency-Key").
+					set("in", "header").
+					set("description", "Run the request once; retries with the same key within 24 hours get its response replayed, 409 while it is still running and 422 if the request differs").
+					set("schema", newMapping().set("type", "string").set("maxLength", 255)))).
+			set("responses", newMapping().set("TooManyRequests", newMapping().
+				set("description", "Rate limit exceeded").
+				set("headers", newMapping().set("Retry-After", newMapping().
+					set("description", "Seconds until a request is allowed").
+					set("schema", newMapping().set("type", "integer")))).
+				set("content", newMapping().set("application/json", newMapping().set("schema", ref("Error")))))).
+			set("securitySchemes", newMapping().set("bearerAuth", newMapping().
+				set("type", "http").
+				set("scheme", "bearer").
+				set("bearerFormat", "JWT").
+				set("description", "Supabase access token, or a service token. Scoped tokens need the read: or write: scope listed on each operation; unscoped tokens have them all."))))
+}
+
+// scopes lists the scope a route needs from a scoped token: read:<resource> for safe
+// methods, write:<resource> otherwise
+func scopes(r *route) []any {
+	if r.scope == "" {
+		return []any{}
+	}
+	switch r.method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return []any{"read:" + r.scope}
+	}
+	return []any{"write:" + r.scope}
+}
+
+// description is a handler's doc comment without its "X handles METHOD path" line
+func description(doc *ast.CommentGroup) string {
+	if doc == nil {
+		return ""
+	}
+	lines := strings.Split(strings.TrimSpace(doc.Text()), "\n")
+	if len(lines) > 0 && strings.Contains(lines[0], " handles ") {
+		lines = lines[1:]
+	}
+	return strings.TrimSpace(strings.Join(lines, " "))
+}
+
+// tag groups an operation by the first path segment after /api, e.g. workouts
+func tag(path string) string {
+	for _, segment := range strings.Split(path, "/") {
+		if segment != "" && segment != "api" && segment != "ws" && segment != "admin" {
+			return segment
+		}
+	}
+	return "default"
+}
+
+// pathName names an inline handler after its method-less path, e.g. /api/me -> Me
+func pathName(path string) string {
+	var name strings.Builder
+	for _, segment := range strings.Split(path, "/") {
+		if segment == "" || segment == "api" || strings.HasPrefix(segment, ":") {
+			continue
+		}
+		for _, word := range strings.FieldsFunc(segment, func(r rune) bool { return r == '-' || r == '_' }) {
+			name.WriteString(strings.ToUpper(word[:1]) + word[1:])
+		}
+	}
+	return "Get" + name.String()
+}
+
+// humanize turns a Go name into a summary, e.g. GetByID -> Get by ID
+func humanize(name string) string {
+	words := splitWords(name)
+	for i, word := range words {
+		if i > 0 && !isAcronym(word) {
+			words[i] = strings.ToLower(word)
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// splitWords splits a mixed-caps name, keeping acronyms and digits together, e.g.
+// E1RMBests -> E1RM, Bests
+func splitWords(name string) []string {
+	runes := []rune(name)
+	var words []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		lowerToUpper := unicode.IsLower(runes[i-1]) && unicode.IsUpper(runes[i])
+		acronymEnd := unicode.IsUpper(runes[i-1]) && unicode.IsUpper(runes[i]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+		if lowerToUpper || acronymEnd {
+			words = append(words, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(words, string(runes[start:]))
+}
+
+func isAcronym(word string) bool {
+	for _, r := range word {
+		if unicode.IsLower(r) {
+			return false
+		}
+	}
+	return true
+}
+
+func lowerFirst(s string) string {
+	words := splitWords(s)
+	words[0] = strings.ToLower(words[0])
+	return strings.Join(words, "")
+}
+
+// packageImporter imports packages already checked by the generator, and the rest from source
+type packageImporter struct {
+	fallback types.Importer
+	packages map[string]*types.Package
+}
+
+func (p *packageImporter) Import(path string) (*types.Package, error) {
+	if pkg, ok := p.packages[path]; ok {
+		return pkg, nil
+	}
+	return p.fallback.Import(path)
+}
+
+// parsePackage parses a directory's Go files, without tests
+func parsePackage(fset *token.FileSet, dir string) ([]*ast.File, error) {
+	names, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+	var files []*ast.File
+	for _, name := range names {
+		if strings.HasSuffix(name, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, name, nil, parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files in %s", dir)
+	}
+	return files, nil
+}
+
+func check(fset *token.FileSet, path string, files []*ast.File, imports types.Importer) (*types.Info, *types.Package, error) {
+	info := &types.Info{
+		Types:      make(map[ast.Expr]types.TypeAndValue),
+		Defs:       make(map[*ast.Ident]types.Object),
+		Uses:       make(map[*ast.Ident]types.Object),
+		Selections: make(map[*ast.SelectorExpr]*types.Selection),
+	}
+	config := &types.Config{Importer: imports}
+	pkg, err := config.Check(path, fset, files, info)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to type-check %s: %w", path, err)
+	}
+	return info, pkg, nil
+}
+
+func readModulePath(goMod string) (string, error) {
+	file, err := os.Open(goMod)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`), nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", fmt.Errorf("no module path in %s", goMod)
+}
@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWriteYAML(t *testing.T) {
+	document := newMapping().
+		set("openapi", "3.1.0").
+		set("paths", newMapping().set("/api/workouts/{id}", newMapping().set("get", newMapping().
+			set("tags", []any{"workouts"}).
+			set("security", []any{newMapping(), newMapping().set("bearerAuth", []any{"read:workouts"})}).
+			set("description", "Returns 404: not found").
+			set("deprecated", false)))).
+		set("required", []any{"yes", "name"})
+
+	var got strings.Builder
+	if err := writeYAML(&got, document); err != nil {
+		t.Fatal(err)
+	}
+	want := `openapi: "3.1.0"
+paths:
+  /api/workouts/{id}:
+    get:
+      tags:
+        - workouts
+      security:
+        - {}
+        - bearerAuth:
+            - "read:workouts"
+      description: "Returns 404: not found"
+      deprecated: false
+required:
+  - "yes"
+  - name
+`
+	if got.String() != want {
+		t.Errorf("Expected\n%s\ngot\n%s", want, got.String())
+	}
+}
+
+func TestFindRoutes(t *testing.T) {
+	src := `package main
+
+func main() {
+	router := gin.Default()
+	router.GET("/health", func(c *gin.Context) {})
+
+	api := router.Group("/api")
+	api.Use(middleware.AuthRequired(revocations))
+	api.Use(middleware.RateLimit(users, anonymous))
+	api.Use(middleware.UUIDParams("id", "user_id"))
+	api.Use(middleware.Idempotency(idempotency))
+	{
+		workouts := api.Group("/workouts", middleware.RequireScopes("workouts"))
+		workouts.GET("/:id", workoutHandler.GetByID)
+
+		admin := api.Group("/admin", middleware.AdminRequired())
+		admin.DELETE("/cache", cacheHandler.Flush)
+	}
+}
+`
+	file, err := parser.ParseFile(token.NewFileSet(), "main.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	routes := findRoutes(file.Decls[0].(*ast.FuncDecl).Body)
+
+	want := []route{
+		{method: "GET", path: "/health"},
+		{method: "GET", path: "/api/workouts/:id", middlewareSet: middlewareSet{auth: authRequired, scope: "workouts", rateLimited: true, idempotent: true, uuidParams: "id user_id"}},
+		{method: "DELETE", path: "/api/admin/cache", middlewareSet: middlewareSet{auth: authRequired, admin: true, rateLimited: true, idempotent: true, uuidParams: "id user_id"}},
+	}
+	if len(routes) != len(want) {
+		t.Fatalf("Expected %d routes, got %d", len(want), len(routes))
+	}
+	for i, r := range routes {
+		if r.method != want[i].method || r.path != want[i].path || r.middlewareSet != want[i].middlewareSet {
+			t.Errorf("Route %d: expected %s %s %+v, got %s %s %+v", i, want[i].method, want[i].path, want[i].middlewareSet, r.method, r.path, r.middlewareSet)
+		}
+	}
+}
+
+func TestSchemas(t *testing.T) {
+	src := `package models
+
+type Base struct {
+	ID string ` + "`json:\"id\"`" + `
+}
+
+type Workout struct {
+	Base
+	Name      string   ` + "`json:\"name\" binding:\"required,max=100\"`" + `
+	Notes     *string  ` + "`json:\"notes,omitempty\"`" + `
+	Exercises []Set    ` + "`json:\"exercises\"`" + `
+	internal  bool
+}
+
+type Set struct {
+	Reps   int     ` + "`json:\"reps\" binding:\"gte=1\"`" + `
+	Weight float64 ` + "`json:\"weight\"`" + `
+	Next   *Set    ` + "`json:\"next,omitempty\"`" + `
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "models.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg, err := (&types.Config{}).Check("models", fset, []*ast.File{file}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := newSchemas()
+	got := yamlString(s.of(types.NewPointer(pkg.Scope().Lookup("Workout").Type())))
+	if got != "$ref: \"#/components/schemas/Workout\"\n" {
+		t.Errorf("Expected a reference to Workout, got %s", got)
+	}
+
+	want := `type: object
+properties:
+  id:
+    type: string
+  name:
+    type: string
+    maxLength: 100
+  notes:
+    type:
+      - string
+      - "null"
+  exercises:
+    type: array
+    items:
+      $ref: "#/components/schemas/Set"
+required:
+  - id
+  - name
+`
+	if got := yamlString(s.components["Workout"]); got != want {
+		t.Errorf("Expected Workout\n%s\ngot\n%s", want, got)
+	}
+	if got := yamlString(s.components["Set"]); !strings.Contains(got, "minimum: 1") || !strings.Contains(got, "$ref: \"#/components/schemas/Set\"") {
+		t.Errorf("Expected Set with its binding rules and a reference to itself, got\n%s", got)
+	}
+}
+
+func TestDomainErrors(t *testing.T) {
+	src := `package domainerr
+
+import "errors"
+
+type Error struct{ category error }
+
+func (e *Error) Error() string { return "" }
+
+func New(category error, message string) *Error { return &Error{category} }
+
+var (
+	NotFound  = errors.New("not found")
+	Conflict  = errors.New("conflict")
+	Forbidden = errors.New("forbidden")
+)
+
+var (
+	ErrMissing  = New(NotFound, "missing")
+	ErrTaken    = New(Conflict, "taken")
+	ErrNotYours = New(Forbidden, "not yours")
+)
+
+type Policy interface{ Check(owner string) error }
+
+type ownerPolicy struct{}
+
+func (ownerPolicy) Check(owner string) error {
+	if owner == "" {
+		return ErrNotYours
+	}
+	return nil
+}
+
+type Service struct{ policy Policy }
+
+func (s *Service) get(id string) error {
+	if id == "" {
+		return ErrMissing
+	}
+	return nil
+}
+
+func (s *Service) Rename(id, owner string) error {
+	if err := s.get(id); err != nil {
+		return err
+	}
+	return s.policy.Check(owner)
+}
+
+func (s *Service) Create(err error) error {
+	if errors.Is(err, ErrMissing) || err == ErrNotYours {
+		return nil
+	}
+	return ErrTaken
+}
+`
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "domainerr.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := &types.Info{Defs: make(map[*ast.Ident]types.Object), Uses: make(map[*ast.Ident]types.Object)}
+	config := &types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+	pkg, err := config.Check("domainerr", fset, []*ast.File{file}, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := newDomainErrors("domainerr")
+	d.addPackage([]*ast.File{file}, info)
+	method := func(name string) *types.Func {
+		service := pkg.Scope().Lookup("Service").Type().(*types.Named)
+		for i := range service.NumMethods() {
+			if service.Method(i).Name() == name {
+				return service.Method(i)
+			}
+		}
+		t.Fatalf("no method %s", name)
+		return nil
+	}
+	names := func(set categorySet) []string {
+		var names []string
+		for category := range set {
+			names = append(names, category.Name())
+		}
+		slices.Sort(names)
+		return names
+	}
+
+	// Followed through a method and an interface implementation
+	if got := names(d.of(method("Rename"))); !slices.Equal(got, []string{"Forbidden", "NotFound"}) {
+		t.Errorf("Expected Rename to return Forbidden and NotFound, got %v", got)
+	}
+	// Sentinels only compared with don't count
+	if got := names(d.of(method("Create"))); !slices.Equal(got, []string{"Conflict"}) {
+		t.Errorf("Expected Create to return Conflict, got %v", got)
+	}
+}
+
+func TestOpenAPIPath(t *testing.T) {
+	path, params := openAPIPath("/api/orgs/:id/members/:user_id")
+	if path != "/api/orgs/{id}/members/{user_id}" || strings.Join(params, ",") != "id,user_id" {
+		t.Errorf("Expected /api/orgs/{id}/members/{user_id} with id and user_id, got %s with %v", path, params)
+	}
+}
+
+func TestHumanize(t *testing.T) {
+	tests := map[string]string{
+		"WorkoutGetByID":        "Workout get by ID",
+		"AnalyticsE1RMBests":    "Analytics E1RM bests",
+		"ExportExerciseLogsCSV": "Export exercise logs CSV",
+		"GetHealth":             "Get health",
+		"SLOStatus":             "SLO status",
+	}
+	for name, want := range tests {
+		if got := humanize(name); got != want {
+			t.Errorf("humanize(%q): expected %q, got %q", name, want, got)
+		}
+	}
+	if got := lowerFirst("SLOStatus"); got != "sloStatus" {
+		t.Errorf("Expected sloStatus, got %s", got)
+	}
+}
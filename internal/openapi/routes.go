@@ -0,0 +1,186 @@
+package openapi
+
+import (
+	"go/ast"
+	"strconv"
+	"strings"
+)
+
+// Authentication a route requires
+const (
+	authNone     = ""
+	authRequired = "required"
+	authOptional = "optional"
+)
+
+// middlewareSet is what a route's middleware means for its callers
+type middlewareSet struct {
+	auth        string
+	scope       string // Resource of RequireScopes: read:<scope> or write:<scope>
+	admin       bool
+	rateLimited bool
+	orgContext  bool
+	idempotent  bool   // POSTs accept an Idempotency-Key
+	uuidParams  string // Space-separated path parameters UUIDParams checks
+	websocket   bool
+}
+
+// route is an endpoint registered on the router
+type route struct {
+	method  string
+	path    string // Gin syntax, e.g. /api/workouts/:id
+	handler ast.Expr
+	middlewareSet
+}
+
+// routeMethods are the router methods that register routes
+var routeMethods = map[string]bool{
+	"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true, "HEAD": true, "OPTIONS": true,
+}
+
+// findRoutes lists the routes a function registers, in order. It follows the router from
+// gin.New or gin.Default through Group and Use, so handlers registered on a group get
+// its prefix and middleware.
+func findRoutes(body *ast.BlockStmt) []*route {
+	groups := make(map[string]*route) // Prefix and middleware by variable
+	var routes []*route
+
+	ast.Inspect(body, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.AssignStmt:
+			if len(n.Lhs) != 1 || len(n.Rhs) != 1 {
+				return true
+			}
+			name, ok := n.Lhs[0].(*ast.Ident)
+			call, isCall := n.Rhs[0].(*ast.CallExpr)
+			if !ok || !isCall {
+				return true
+			}
+			if isPackageCall(call, "gin", "New") || isPackageCall(call, "gin", "Default") {
+				groups[name.Name] = &route{}
+				return true
+			}
+			receiver, method := methodCall(call)
+			parent, ok := groups[receiver]
+			if !ok || method != "Group" || len(call.Args) == 0 {
+				return true
+			}
+			group := &route{path: parent.path + stringLit(call.Args[0]), middlewareSet: parent.middlewareSet}
+			for _, arg := range call.Args[1:] {
+				group.apply(arg)
+			}
+			groups[name.Name] = group
+
+		case *ast.CallExpr:
+			receiver, method := methodCall(n)
+			group, ok := groups[receiver]
+			if !ok {
+				return true
+			}
+			switch {
+			case method == "Use":
+				for _, arg := range n.Args {
+					group.apply(arg)
+				}
+			case routeMethods[method] && len(n.Args) >= 2:
+				r := &route{
+					method:        method,
+					path:          group.path + stringLit(n.Args[0]),
+					handler:       n.Args[len(n.Args)-1],
+					middlewareSet: group.middlewareSet,
+				}
+				for _, arg := range n.Args[1 : len(n.Args)-1] {
+					r.apply(arg)
+				}
+				routes = append(routes, r)
+			}
+		}
+		return true
+	})
+	return routes
+}
+
+// apply records what a middleware from the middleware package means for the route
+func (r *route) apply(expr ast.Expr) {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return
+	}
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return
+	}
+	if pkg, ok := selector.X.(*ast.Ident); !ok || pkg.Name != "middleware" {
+		return
+	}
+
+	switch selector.Sel.Name {
+	case "AuthRequired":
+		r.auth = authRequired
+	case "OptionalAuth":
+		r.auth = authOptional
+	case "RequireScopes":
+		if len(call.Args) == 1 {
+			r.scope = stringLit(call.Args[0])
+		}
+	case "AdminRequired":
+		r.admin = true
+	case "RateLimit", "RateLimitAnonymous":
+		r.rateLimited = true
+	case "OrgContext":
+		r.orgContext = true
+	case "Idempotency":
+		r.idempotent = true
+	case "UUIDParams":
+		var names []string
+		for _, arg := range call.Args {
+			names = append(names, stringLit(arg))
+		}
+		r.uuidParams = strings.Join(names, " ")
+	case "WebSocketToken":
+		r.websocket = true
+	}
+}
+
+// openAPIPath converts Gin's :param and *param segments to {param}
+func openAPIPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var params []string
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			params = append(params, segment[1:])
+			segments[i] = "{" + segment[1:] + "}"
+		}
+	}
+	return strings.Join(segments, "/"), params
+}
+
+// methodCall returns the receiver variable and method of a call like x.Method(...)
+func methodCall(call *ast.CallExpr) (string, string) {
+	selector, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok {
+		return "", ""
+	}
+	receiver, ok := selector.X.(*ast.Ident)
+	if !ok {
+		return "", ""
+	}
+	return receiver.Name, selector.Sel.Name
+}
+
+func isPackageCall(call *ast.CallExpr, pkg, name string) bool {
+	receiver, method := methodCall(call)
+	return receiver == pkg && method == name
+}
+
+func stringLit(expr ast.Expr) string {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	s, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,308 @@
+package openapi
+
+import (
+	"go/types"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// schemas turns Go types into JSON schemas, collecting named structs as components
+type schemas struct {
+	components map[string]any
+	names      map[*types.TypeName]string // Component name of each named type
+	taken      map[string]*types.TypeName
+}
+
+func newSchemas() *schemas {
+	return &schemas{
+		components: make(map[string]any),
+		names:      make(map[*types.TypeName]string),
+		taken:      make(map[string]*types.TypeName),
+	}
+}
+
+func ref(name string) *mapping {
+	return newMapping().set("$ref", "#/components/schemas/"+name)
+}
+
+// of returns the schema of values of t as encoding/json writes them
+func (s *schemas) of(t types.Type) any {
+	switch t := t.(type) {
+	case *types.Pointer:
+		return s.of(t.Elem())
+	case *types.Alias:
+		return s.of(types.Unalias(t))
+	case *types.Named:
+		obj := t.Obj()
+		if obj.Pkg() != nil {
+			switch obj.Pkg().Path() + "." + obj.Name() {
+			case "time.Time":
+				return newMapping().set("type", "string").set("format", "date-time")
+			case "time.Duration":
+				return newMapping().set("type", "integer").set("description", "Nanoseconds")
+			case "encoding/json.RawMessage", "encoding/json/jsontext.Value": // RawMessage is an alias of Value with jsonv2
+				return newMapping()
+			case "github.com/gin-gonic/gin.H":
+				return newMapping().set("type", "object")
+			}
+		}
+		if types.Implements(t, jsonMarshaler) || types.Implements(types.NewPointer(t), jsonMarshaler) {
+			return newMapping().set("description", "Custom JSON encoding of "+obj.Name())
+		}
+		if _, ok := t.Underlying().(*types.Struct); ok {
+			return ref(s.component(t))
+		}
+		return s.of(t.Underlying())
+	case *types.Basic:
+		return basicSchema(t)
+	case *types.Slice:
+		if b, ok := t.Elem().(*types.Basic); ok && b.Kind() == types.Byte {
+			return newMapping().set("type", "string").set("format", "byte")
+		}
+		return newMapping().set("type", "array").set("items", s.of(t.Elem()))
+	case *types.Array:
+		return newMapping().set("type", "array").set("items", s.of(t.Elem()))
+	case *types.Map:
+		return newMapping().set("type", "object").set("additionalProperties", s.of(t.Elem()))
+	case *types.Struct:
+		return s.object(t)
+	}
+	return newMapping() // Interfaces: any value
+}
+
+// component registers a named struct and returns its component name: the type's name, or
+// with its package in front when another package has a type of that name
+func (s *schemas) component(t *types.Named) string {
+	obj := t.Obj()
+	if name, ok := s.names[obj]; ok && t.TypeArgs().Len() == 0 {
+		return name
+	}
+
+	name := obj.Name()
+	for i := range t.TypeArgs().Len() {
+		if arg, ok := types.Unalias(t.TypeArgs().At(i)).(*types.Named); ok {
+			name += arg.Obj().Name()
+		}
+	}
+	if other, ok := s.taken[name]; ok && other != obj && t.TypeArgs().Len() == 0 {
+		name = strings.ToUpper(obj.Pkg().Name()[:1]) + obj.Pkg().Name()[1:] + name
+	}
+	if _, ok := s.components[name]; ok {
+		return name
+	}
+
+	s.taken[name] = obj
+	if t.TypeArgs().Len() == 0 {
+		s.names[obj] = name
+	}
+	s.components[name] = newMapping() // Placeholder for recursive types
+	s.components[name] = s.object(t.Underlying().(*types.Struct))
+	return name
+}
+
+// object is the schema of a struct's JSON fields; embedded structs are flattened
+func (s *schemas) object(st *types.Struct) any {
+	properties := newMapping()
+	var required []any
+	s.addFields(st, properties, &required)
+
+	schema := newMapping().set("type", "object")
+	if len(properties.keys) > 0 {
+		schema.set("properties", properties)
+	}
+	if len(required) > 0 {
+		schema.set("required", required)
+	}
+	return schema
+}
+
+func (s *schemas) addFields(st *types.Struct, properties *mapping, required *[]any) {
+	for i := range st.NumFields() {
+		field := st.Field(i)
+		tag := reflect.StructTag(st.Tag(i))
+		name, options, _ := strings.Cut(tag.Get("json"), ",")
+		if name == "-" && options == "" {
+			continue
+		}
+
+		if field.Embedded() && name == "" {
+			if embedded, ok := deref(field.Type()).Underlying().(*types.Struct); ok {
+				s.addFields(embedded, properties, required)
+				continue
+			}
+		}
+		if !field.Exported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name()
+		}
+
+		schema := s.of(field.Type())
+		if options == "string" {
+			schema = newMapping().set("type", "string")
+		}
+		rules := bindingRules(tag.Get("binding"))
+		if len(rules) > 0 {
+			schema = withRules(schema, field.Type(), rules)
+		}
+		if _, isPointer := field.Type().(*types.Pointer); isPointer {
+			schema = nullable(schema)
+		}
+		properties.set(name, schema)
+
+		// Responses always carry fields without omitempty; requests need what binding requires
+		if _, ok := rules["required"]; ok || (!strings.Contains(options, "omitempty") && !isNilable(field.Type()) && tag.Get("binding") == "") {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// bindingRules parses a binding tag, e.g. "required,max=100"
+func bindingRules(tag string) map[string]string {
+	rules := make(map[string]string)
+	for _, rule := range strings.Split(tag, ",") {
+		if rule == "" || rule == "dive" {
+			break // Rules after dive apply to the elements
+		}
+		key, value, _ := strings.Cut(rule, "=")
+		rules[key] = value
+	}
+	return rules
+}
+
+// withRules adds the validation a binding tag describes to a field's schema
+func withRules(schema any, t types.Type, rules map[string]string) any {
+	m, ok := schema.(*mapping)
+	if !ok || m.values["$ref"] != nil {
+		return schema
+	}
+	copied := newMapping()
+	for _, k := range m.keys {
+		copied.set(k, m.values[k])
+	}
+
+	kind := "number"
+	switch deref(t).Underlying().(type) {
+	case *types.Slice, *types.Array, *types.Map:
+		kind = "array"
+	case *types.Basic:
+		if b := deref(t).Underlying().(*types.Basic); b.Info()&types.IsString != 0 {
+			kind = "string"
+		}
+	}
+	bounds := map[string]map[string]string{
+		"string": {"min": "minLength", "max": "maxLength"},
+		"array":  {"min": "minItems", "max": "maxItems"},
+		"number": {"min": "minimum", "max": "maximum", "gte": "minimum", "lte": "maximum"},
+	}
+
+	names := make([]string, 0, len(rules))
+	for rule := range rules {
+		names = append(names, rule)
+	}
+	sort.Strings(names) // Keeps the document stable between runs
+	for _, rule := range names {
+		value := rules[rule]
+		switch rule {
+		case "min", "max", "gte", "lte":
+			if key, ok := bounds[kind][rule]; ok {
+				if n, err := strconv.ParseFloat(value, 64); err == nil {
+					copied.set(key, n)
+				}
+			}
+		case "oneof":
+			var values []any
+			for _, v := range strings.Fields(value) {
+				values = append(values, v)
+			}
+			copied.set("enum", values)
+		case "email":
+			copied.set("format", "email")
+		case "uuid", "uuid4":
+			copied.set("format", "uuid")
+		case "url", "http_url":
+			copied.set("format", "uri")
+		case "datetime":
+			if value == "2006-01-02" {
+				copied.set("format", "date")
+			} else {
+				copied.set("format", "date-time")
+			}
+		}
+	}
+	return copied
+}
+
+func nullable(schema any) any {
+	m, ok := schema.(*mapping)
+	if !ok {
+		return schema
+	}
+	if _, isRef := m.get("$ref"); isRef {
+		return newMapping().set("anyOf", []any{m, newMapping().set("type", "null")})
+	}
+	if t, ok := m.values["type"].(string); ok {
+		copied := newMapping()
+		for _, k := range m.keys {
+			copied.set(k, m.values[k])
+		}
+		return copied.set("type", []any{t, "null"})
+	}
+	return schema
+}
+
+func basicSchema(t *types.Basic) any {
+	info := t.Info()
+	switch {
+	case info&types.IsBoolean != 0:
+		return newMapping().set("type", "boolean")
+	case info&types.IsInteger != 0:
+		schema := newMapping().set("type", "integer")
+		switch t.Kind() {
+		case types.Int64, types.Uint64:
+			schema.set("format", "int64")
+		case types.Int32, types.Uint32:
+			schema.set("format", "int32")
+		}
+		return schema
+	case info&types.IsFloat != 0:
+		schema := newMapping().set("type", "number")
+		if t.Kind() == types.Float32 {
+			schema.set("format", "float")
+		} else {
+			schema.set("format", "double")
+		}
+		return schema
+	case info&types.IsString != 0:
+		return newMapping().set("type", "string")
+	}
+	return newMapping()
+}
+
+func deref(t types.Type) types.Type {
+	if p, ok := t.(*types.Pointer); ok {
+		return p.Elem()
+	}
+	return t
+}
+
+func isNilable(t types.Type) bool {
+	switch t.Underlying().(type) {
+	case *types.Pointer, *types.Interface, *types.Map, *types.Slice:
+		return true
+	}
+	return false
+}
+
+// jsonMarshaler is encoding/json.Marshaler, built by hand so schemas need no import of it
+var jsonMarshaler = func() *types.Interface {
+	byteSlice := types.NewSlice(types.Typ[types.Byte])
+	errorType := types.Universe.Lookup("error").Type()
+	signature := types.NewSignatureType(nil, nil, nil, nil,
+		types.NewTuple(types.NewVar(0, nil, "", byteSlice), types.NewVar(0, nil, "", errorType)), false)
+	method := types.NewFunc(0, nil, "MarshalJSON", signature)
+	return types.NewInterfaceType([]*types.Func{method}, nil).Complete()
+}()
@@ -0,0 +1,156 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// mapping is a YAML mapping that keeps its keys in insertion order; plain maps are
+// written with sorted keys
+type mapping struct {
+	keys   []string
+	values map[string]any
+}
+
+func newMapping() *mapping {
+	return &mapping{values: make(map[string]any)}
+}
+
+// set adds or replaces a key, keeping its first position
+func (m *mapping) set(key string, value any) *mapping {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+	return m
+}
+
+func (m *mapping) get(key string) (any, bool) {
+	v, ok := m.values[key]
+	return v, ok
+}
+
+// plainScalar matches strings that read back as the same string without quotes
+var plainScalar = regexp.MustCompile(`^[A-Za-z_/$][A-Za-z0-9_ ./{}$()+-]*$`)
+
+// reservedScalars read back as other types unquoted
+var reservedScalars = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"null": true, "y": true, "n": true,
+}
+
+// writeYAML writes v as a YAML document. Values are mappings, maps, slices, strings,
+// bools and numbers; strings are quoted only when they have to be.
+func writeYAML(w io.Writer, v any) error {
+	var b strings.Builder
+	writeValue(&b, v, 0, false)
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeValue writes v at indent; inline continues a "- " line, so a mapping's first key
+// or a sequence's first item isn't indented again
+func writeValue(b *strings.Builder, v any, indent int, inline bool) {
+	switch v := v.(type) {
+	case *mapping:
+		writeMapping(b, v.keys, v.values, indent, inline)
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		writeMapping(b, keys, v, indent, inline)
+	case []any:
+		writeSequence(b, v, indent, inline)
+	case []string:
+		items := make([]any, len(v))
+		for i, s := range v {
+			items[i] = s
+		}
+		writeSequence(b, items, indent, inline)
+	default:
+		b.WriteString(scalar(v))
+		b.WriteByte('\n')
+	}
+}
+
+func writeMapping(b *strings.Builder, keys []string, values map[string]any, indent int, inline bool) {
+	if len(keys) == 0 {
+		b.WriteString("{}\n")
+		return
+	}
+	for i, k := range keys {
+		if i > 0 || !inline {
+			b.WriteString(strings.Repeat("  ", indent))
+		}
+		b.WriteString(scalar(k))
+		b.WriteByte(':')
+
+		v := values[k]
+		if isCollection(v) && !isEmpty(v) {
+			b.WriteByte('\n')
+			writeValue(b, v, indent+1, false)
+		} else {
+			b.WriteByte(' ')
+			writeValue(b, v, 0, true)
+		}
+	}
+}
+
+func writeSequence(b *strings.Builder, items []any, indent int, inline bool) {
+	if len(items) == 0 {
+		b.WriteString("[]\n")
+		return
+	}
+	for i, item := range items {
+		if i > 0 || !inline {
+			b.WriteString(strings.Repeat("  ", indent))
+		}
+		b.WriteString("- ")
+		// A nested mapping or sequence starts on the dash's line, the rest lines up under it
+		writeValue(b, item, indent+1, true)
+	}
+}
+
+func isCollection(v any) bool {
+	switch v.(type) {
+	case *mapping, map[string]any, []any, []string:
+		return true
+	}
+	return false
+}
+
+func isEmpty(v any) bool {
+	switch v := v.(type) {
+	case *mapping:
+		return len(v.keys) == 0
+	case map[string]any:
+		return len(v) == 0
+	case []any:
+		return len(v) == 0
+	case []string:
+		return len(v) == 0
+	}
+	return false
+}
+
+func scalar(v any) string {
+	switch v := v.(type) {
+	case string:
+		if plainScalar.MatchString(v) && !reservedScalars[strings.ToLower(v)] && !strings.HasSuffix(v, " ") {
+			return v
+		}
+		// JSON strings are valid YAML double-quoted scalars
+		quoted, _ := json.Marshal(v)
+		return string(quoted)
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,253 @@
+// Package progression computes next-session prescriptions for a template exercise from the
+// user's recent history, under a progression scheme
+package progression
+
+import (
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+var (
+	// ErrUnknownScheme is returned for scheme names without an implementation
+	ErrUnknownScheme = errors.New("unknown progression scheme")
+	// ErrNoTrainingMax is returned by 5/3/1 when the exercise has no training max
+	ErrNoTrainingMax = errors.New("no training max")
+	// ErrNoStartingLoad is returned when there is neither history nor a template load
+	ErrNoStartingLoad = errors.New("no starting load")
+)
+
+const (
+	// PlateIncrementKg is the smallest jump available with standard plates (1.25kg per side)
+	PlateIncrementKg = 2.5
+
+	// upperBodyIncrementKg and lowerBodyIncrementKg are the load added per progression step
+	upperBodyIncrementKg = 2.5
+	lowerBodyIncrementKg = 5
+
+	// stallSessions missed in a row at the same load trigger a linear deload
+	stallSessions = 3
+	// deloadFraction is how much load a linear deload takes off
+	deloadFraction = 0.1
+
+	// doubleRangeWidth is the default width of a double progression rep range
+	doubleRangeWidth = 4
+)
+
+// lowerBodyGroups progress by lowerBodyIncrementKg
+var lowerBodyGroups = map[string]bool{
+	"quads": true, "hamstrings": true, "glutes": true, "calves": true, "full_body": true,
+}
+
+// Input is a template exercise with the user's history of it
+type Input struct {
+	Sets        int      // Planned sets
+	Reps        int      // Planned reps; the top of the range for double progression
+	MinReps     int      // Bottom of the double progression range; 0 for the default
+	WeightKg    *float64 // Template load, used until there is history
+	IncrementKg float64  // Load added per step; see IncrementFor
+
+	// History is the user's recent completed sessions of the exercise, most recent first
+	History []*models.ExercisePerformance
+
+	// TrainingMaxKg and SessionsSinceTrainingMax drive 5/3/1: the training max, and how many
+	// completed sessions of the exercise there have been since it was last set
+	TrainingMaxKg            *float64
+	SessionsSinceTrainingMax int
+}
+
+// Scheme computes the next prescription for a template exercise
+type Scheme interface {
+	Next(in Input) (*models.ExercisePrescription, error)
+}
+
+// SchemeFunc adapts an ordinary function to the Scheme interface
+type SchemeFunc func(in Input) (*models.ExercisePrescription, error)
+
+// Next calls f(in)
+func (f SchemeFunc) Next(in Input) (*models.ExercisePrescription, error) {
+	return f(in)
+}
+
+var schemes = map[string]Scheme{
+	models.ProgressionLinear:       SchemeFunc(Linear),
+	models.ProgressionFiveThreeOne: SchemeFunc(FiveThreeOne),
+	models.ProgressionDouble:       SchemeFunc(Double),
+}
+
+// ForScheme returns the scheme named "linear", "531" or "double"
+func ForScheme(name string) (Scheme, error) {
+	scheme, ok := schemes[name]
+	if !ok {
+		return nil, ErrUnknownScheme
+	}
+	return scheme, nil
+}
+
+// RoundLoad rounds a load to the nearest plate increment
+func RoundLoad(kg float64) float64 {
+	return math.Round(kg/PlateIncrementKg) * PlateIncrementKg
+}
+
+// IncrementFor returns the load step for exercises of a muscle group: lower body lifts
+// progress twice as fast as upper body ones
+func IncrementFor(muscleGroup *string) float64 {
+	if muscleGroup != nil && lowerBodyGroups[*muscleGroup] {
+		return lowerBodyIncrementKg
+	}
+	return upperBodyIncrementKg
+}
+
+// Linear adds IncrementKg after a session that hit every planned set and rep, repeats the
+// load after a miss, and takes 10% off after three misses in a row at the same load
+func Linear(in Input) (*models.ExercisePrescription, error) {
+	last, ok := lastLoaded(in.History)
+	if !ok {
+		if in.WeightKg == nil {
+			return nil, ErrNoStartingLoad
+		}
+		return &models.ExercisePrescription{Sets: uniformSets(in.Sets, in.Reps, *in.WeightKg)}, nil
+	}
+
+	load := *last.WeightKg
+	var note string
+	switch {
+	case completed(last, in.Sets, in.Reps):
+		load += in.IncrementKg
+		note = fmt.Sprintf("All sets completed last time: +%g kg", in.IncrementKg)
+	case missedInARow(in.History, load, in.Sets, in.Reps) >= stallSessions:
+		load = RoundLoad(load * (1 - deloadFraction))
+		note = fmt.Sprintf("Missed reps %d sessions in a row: deload to %g kg", stallSessions, load)
+	default:
+		note = "Missed reps last time: repeat the load"
+	}
+
+	return &models.ExercisePrescription{Sets: uniformSets(in.Sets, in.Reps, load), Note: note}, nil
+}
+
+// Double works up from MinReps to Reps one rep per session at the same load; once every set
+// reaches Reps it adds IncrementKg and drops back to MinReps
+func Double(in Input) (*models.ExercisePrescription, error) {
+	top, bottom := in.Reps, in.MinReps
+	if bottom <= 0 || bottom > top {
+		bottom = max(1, top-doubleRangeWidth)
+	}
+
+	last, ok := lastLoaded(in.History)
+	if !ok {
+		if in.WeightKg == nil {
+			return nil, ErrNoStartingLoad
+		}
+		return &models.ExercisePrescription{Sets: uniformSets(in.Sets, bottom, *in.WeightKg)}, nil
+	}
+
+	load := *last.WeightKg
+	lastReps := 0
+	if last.RepsCompleted != nil {
+		lastReps = *last.RepsCompleted
+	}
+
+	// Against the top of the range, not the reps planned for that session
+	setsDone := last.SetsCompleted >= plannedOr(last.SetsPlanned, in.Sets)
+	reps := max(bottom, lastReps)
+	var note string
+	switch {
+	case setsDone && lastReps >= top:
+		load += in.IncrementKg
+		reps = bottom
+		note = fmt.Sprintf("Top of the %d-%d range reached: +%g kg", bottom, top, in.IncrementKg)
+	case setsDone && lastReps >= bottom:
+		reps = min(top, lastReps+1)
+		note = "Add a rep"
+	default:
+		note = "Repeat last session"
+	}
+
+	return &models.ExercisePrescription{Sets: uniformSets(in.Sets, reps, load), Note: note}, nil
+}
+
+// fiveThreeOneWeeks are each week's sets as percentages of the training max; the last set
+// of weeks 1-3 is as many reps as possible, week 4 is the deload
+var fiveThreeOneWeeks = [4][3]struct {
+	percent float64
+	reps    int
+}{
+	{{65, 5}, {75, 5}, {85, 5}},
+	{{70, 3}, {80, 3}, {90, 3}},
+	{{75, 5}, {85, 3}, {95, 1}},
+	{{40, 5}, {50, 5}, {60, 5}},
+}
+
+// FiveThreeOne runs four-week waves off the training max, one week per session of the
+// exercise, and adds IncrementKg to the training max after each completed wave
+func FiveThreeOne(in Input) (*models.ExercisePrescription, error) {
+	if in.TrainingMaxKg == nil {
+		return nil, ErrNoTrainingMax
+	}
+
+	n := max(0, in.SessionsSinceTrainingMax)
+	week := n%len(fiveThreeOneWeeks) + 1
+	cycles := n / len(fiveThreeOneWeeks)
+	trainingMax := *in.TrainingMaxKg + float64(cycles)*in.IncrementKg
+
+	sets := make([]models.PrescribedSet, 0, len(fiveThreeOneWeeks[week-1]))
+	for i, s := range fiveThreeOneWeeks[week-1] {
+		load := RoundLoad(trainingMax * s.percent / 100)
+		sets = append(sets, models.PrescribedSet{
+			Reps:     s.reps,
+			WeightKg: &load,
+			AMRAP:    week < 4 && i == len(fiveThreeOneWeeks[week-1])-1,
+		})
+	}
+
+	var note string
+	if cycles > 0 {
+		note = fmt.Sprintf("Training max %g kg after %d completed cycles", trainingMax, cycles)
+	}
+	return &models.ExercisePrescription{Week: &week, Sets: sets, Note: note}, nil
+}
+
+// lastLoaded returns the most recent session with a load
+func lastLoaded(history []*models.ExercisePerformance) (*models.ExercisePerformance, bool) {
+	if len(history) == 0 || history[0].WeightKg == nil {
+		return nil, false
+	}
+	return history[0], true
+}
+
+// completed reports whether a session hit its planned sets and reps, falling back to the
+// template's for logs that didn't record a plan
+func completed(p *models.ExercisePerformance, sets, reps int) bool {
+	return p.SetsCompleted >= plannedOr(p.SetsPlanned, sets) &&
+		p.RepsCompleted != nil && *p.RepsCompleted >= plannedOr(p.RepsPlanned, reps)
+}
+
+// missedInARow counts the most recent sessions at load that missed their plan
+func missedInARow(history []*models.ExercisePerformance, load float64, sets, reps int) int {
+	n := 0
+	for _, p := range history {
+		if p.WeightKg == nil || *p.WeightKg != load || completed(p, sets, reps) {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+func plannedOr(planned *int, fallback int) int {
+	if planned != nil && *planned > 0 {
+		return *planned
+	}
+	return fallback
+}
+
+func uniformSets(sets, reps int, load float64) []models.PrescribedSet {
+	prescribed := make([]models.PrescribedSet, sets)
+	for i := range prescribed {
+		load := load
+		prescribed[i] = models.PrescribedSet{Reps: reps, WeightKg: &load}
+	}
+	return prescribed
+}
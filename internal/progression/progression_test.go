@@ -0,0 +1,101 @@
+package progression
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+func performance(weight float64, sets, reps int) *models.ExercisePerformance {
+	return &models.ExercisePerformance{WeightKg: &weight, SetsCompleted: sets, RepsCompleted: &reps}
+}
+
+func TestLinear(t *testing.T) {
+	start := 60.0
+	tests := []struct {
+		name    string
+		history []*models.ExercisePerformance
+		want    float64
+	}{
+		{"no history starts at the template load", nil, 60},
+		{"all sets completed adds the increment", []*models.ExercisePerformance{performance(80, 3, 5)}, 82.5},
+		{"a miss repeats the load", []*models.ExercisePerformance{performance(80, 3, 4), performance(77.5, 3, 5)}, 80},
+		{"three misses deload 10%", []*models.ExercisePerformance{
+			performance(80, 3, 4), performance(80, 2, 5), performance(80, 3, 3),
+		}, 72.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Linear(Input{Sets: 3, Reps: 5, WeightKg: &start, IncrementKg: 2.5, History: tt.history})
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(p.Sets) != 3 || *p.Sets[0].WeightKg != tt.want || p.Sets[0].Reps != 5 {
+				t.Errorf("Expected 3x5 at %v kg, got %d sets of %+v", tt.want, len(p.Sets), p.Sets[0])
+			}
+		})
+	}
+
+	if _, err := Linear(Input{Sets: 3, Reps: 5}); !errors.Is(err, ErrNoStartingLoad) {
+		t.Errorf("Expected ErrNoStartingLoad, got %v", err)
+	}
+}
+
+func TestDouble(t *testing.T) {
+	tests := []struct {
+		name     string
+		history  []*models.ExercisePerformance
+		wantReps int
+		wantKg   float64
+	}{
+		{"adds a rep within the range", []*models.ExercisePerformance{performance(20, 3, 9)}, 10, 20},
+		{"top of the range adds load and resets reps", []*models.ExercisePerformance{performance(20, 3, 12)}, 8, 22.5},
+		{"missed sets repeat", []*models.ExercisePerformance{performance(20, 2, 10)}, 10, 20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Double(Input{Sets: 3, Reps: 12, MinReps: 8, IncrementKg: 2.5, History: tt.history})
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if p.Sets[0].Reps != tt.wantReps || *p.Sets[0].WeightKg != tt.wantKg {
+				t.Errorf("Expected %d reps at %v kg, got %+v", tt.wantReps, tt.wantKg, p.Sets[0])
+			}
+		})
+	}
+}
+
+func TestFiveThreeOne(t *testing.T) {
+	tm := 100.0
+
+	p, err := FiveThreeOne(Input{TrainingMaxKg: &tm, IncrementKg: 5, SessionsSinceTrainingMax: 6})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Second cycle, week 3: 75/85/95% of 105 kg
+	if *p.Week != 3 || len(p.Sets) != 3 {
+		t.Fatalf("Expected week 3 with 3 sets, got week %d with %d", *p.Week, len(p.Sets))
+	}
+	wantKg := []float64{80, 90, 100}
+	wantReps := []int{5, 3, 1}
+	for i, s := range p.Sets {
+		if *s.WeightKg != wantKg[i] || s.Reps != wantReps[i] || s.AMRAP != (i == 2) {
+			t.Errorf("Set %d: expected %dx%v kg, got %+v", i, wantReps[i], wantKg[i], s)
+		}
+	}
+
+	deload, _ := FiveThreeOne(Input{TrainingMaxKg: &tm, IncrementKg: 5, SessionsSinceTrainingMax: 3})
+	if *deload.Week != 4 || deload.Sets[2].AMRAP {
+		t.Errorf("Expected a deload week without AMRAP sets, got week %d", *deload.Week)
+	}
+
+	if _, err := FiveThreeOne(Input{}); !errors.Is(err, ErrNoTrainingMax) {
+		t.Errorf("Expected ErrNoTrainingMax, got %v", err)
+	}
+}
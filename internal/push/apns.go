@@ -0,0 +1,149 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultAPNsURL = "https://api.push.apple.com"
+	sandboxAPNsURL = "https://api.sandbox.push.apple.com"
+
+	// apnsTokenLifetime is how long a provider token is reused; Apple rejects tokens older
+	// than an hour and throttles providers that renew them more than every 20 minutes
+	apnsTokenLifetime = 50 * time.Minute
+)
+
+// APNsConfig holds the token-based authentication key created in the Apple developer account
+type APNsConfig struct {
+	KeyID      string
+	TeamID     string
+	Topic      string // The app's bundle ID
+	PrivateKey []byte // Contents of the .p8 key file
+	Sandbox    bool   // Development builds of the app get sandbox tokens
+}
+
+// APNs sends notifications with the Apple Push Notification service HTTP/2 API
+type APNs struct {
+	config     APNsConfig
+	key        *ecdsa.PrivateKey
+	httpClient *http.Client
+
+	mu            sync.Mutex
+	providerToken string
+	issuedAt      time.Time
+
+	// URL is the service endpoint, overridden in tests
+	URL string
+}
+
+// NewAPNs creates an APNs sender; a nil httpClient uses one with a 30s timeout
+// HTTP/2, which APNs requires, is negotiated by the default transport.
+func NewAPNs(config APNsConfig, httpClient *http.Client) (*APNs, error) {
+	if config.KeyID == "" || config.TeamID == "" || config.Topic == "" {
+		return nil, errors.New("apns key id, team id and topic are required")
+	}
+	key, err := jwt.ParseECPrivateKeyFromPEM(config.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid apns private key: %w", err)
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	endpoint := defaultAPNsURL
+	if config.Sandbox {
+		endpoint = sandboxAPNsURL
+	}
+	return &APNs{config: config, key: key, httpClient: httpClient, URL: endpoint}, nil
+}
+
+// Send delivers a notification to an APNs device token
+func (a *APNs) Send(ctx context.Context, token string, n *Notification) error {
+	providerToken, err := a.token()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]any{
+		"aps": map[string]any{
+			"alert": map[string]string{"title": n.Title, "body": n.Body},
+			"sound": "default",
+		},
+	}
+	for key, value := range n.Data {
+		if key != "aps" {
+			payload[key] = value
+		}
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, a.URL+"/3/device/"+token, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "bearer "+providerToken)
+	req.Header.Set("apns-topic", a.config.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Reason string `json:"reason"`
+	}
+	readError(resp, &failure)
+	switch {
+	case resp.StatusCode == http.StatusGone, failure.Reason == "BadDeviceToken", failure.Reason == "DeviceTokenNotForTopic":
+		return ErrInvalidToken
+	case failure.Reason == "ExpiredProviderToken":
+		a.mu.Lock()
+		a.providerToken = ""
+		a.mu.Unlock()
+	}
+	return fmt.Errorf("apns request failed with status %d: %s", resp.StatusCode, failure.Reason)
+}
+
+// token returns the cached provider token, signing a new one when it is due
+func (a *APNs) token() (string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := time.Now()
+	if a.providerToken != "" && now.Sub(a.issuedAt) < apnsTokenLifetime {
+		return a.providerToken, nil
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": a.config.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = a.config.KeyID
+	signed, err := token.SignedString(a.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign apns provider token: %w", err)
+	}
+
+	a.providerToken, a.issuedAt = signed, now
+	return signed, nil
+}
@@ -0,0 +1,188 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	defaultFCMURL = "https://fcm.googleapis.com/v1/projects/"
+	fcmScope      = "https://www.googleapis.com/auth/firebase.messaging"
+
+	// fcmTokenLifetime is requested for access tokens; they are renewed a minute early
+	fcmTokenLifetime = time.Hour
+)
+
+// FCM sends notifications with the Firebase Cloud Messaging HTTP v1 API, authenticating
+// as a service account
+type FCM struct {
+	projectID   string
+	clientEmail string
+	key         *rsa.PrivateKey
+	httpClient  *http.Client
+
+	mu          sync.Mutex
+	accessToken string
+	expiresAt   time.Time
+
+	// Endpoints, overridden in tests
+	URL      string
+	TokenURL string
+}
+
+// NewFCM creates an FCM sender from a service account key file (JSON) of the Firebase
+// project; a nil httpClient uses one with a 30s timeout
+func NewFCM(credentials []byte, httpClient *http.Client) (*FCM, error) {
+	var account struct {
+		Type        string `json:"type"`
+		ProjectID   string `json:"project_id"`
+		PrivateKey  string `json:"private_key"`
+		ClientEmail string `json:"client_email"`
+		TokenURI    string `json:"token_uri"`
+	}
+	if err := json.Unmarshal(credentials, &account); err != nil {
+		return nil, fmt.Errorf("invalid service account file: %w", err)
+	}
+	if account.Type != "service_account" || account.ProjectID == "" || account.ClientEmail == "" || account.TokenURI == "" {
+		return nil, errors.New("invalid service account file: expected a service_account key with project_id, client_email and token_uri")
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM([]byte(account.PrivateKey))
+	if err != nil {
+		return nil, fmt.Errorf("invalid service account private key: %w", err)
+	}
+
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &FCM{
+		projectID:   account.ProjectID,
+		clientEmail: account.ClientEmail,
+		key:         key,
+		httpClient:  httpClient,
+		URL:         defaultFCMURL,
+		TokenURL:    account.TokenURI,
+	}, nil
+}
+
+// Send delivers a notification to an FCM registration token
+func (f *FCM) Send(ctx context.Context, token string, n *Notification) error {
+	accessToken, err := f.token(ctx)
+	if err != nil {
+		return err
+	}
+
+	message := map[string]any{
+		"token":        token,
+		"notification": map[string]string{"title": n.Title, "body": n.Body},
+		"android":      map[string]string{"priority": "high"},
+	}
+	if len(n.Data) > 0 {
+		message["data"] = n.Data
+	}
+	body, err := json.Marshal(map[string]any{"message": message})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.URL+f.projectID+"/messages:send", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusOK {
+		return nil
+	}
+
+	var failure struct {
+		Error struct {
+			Message string `json:"message"`
+			Details []struct {
+				ErrorCode string `json:"errorCode"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	readError(resp, &failure)
+	for _, detail := range failure.Error.Details {
+		// The app was uninstalled, or the token belongs to another Firebase project
+		if detail.ErrorCode == "UNREGISTERED" || detail.ErrorCode == "SENDER_ID_MISMATCH" {
+			return ErrInvalidToken
+		}
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		f.mu.Lock()
+		f.accessToken = ""
+		f.mu.Unlock()
+	}
+	return fmt.Errorf("fcm request failed with status %d: %s", resp.StatusCode, failure.Error.Message)
+}
+
+// token returns a cached access token, exchanging a signed assertion for a new one
+// when it is about to expire
+func (f *FCM) token(ctx context.Context) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	now := time.Now()
+	if f.accessToken != "" && now.Add(time.Minute).Before(f.expiresAt) {
+		return f.accessToken, nil
+	}
+
+	assertion, err := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{
+		"iss":   f.clientEmail,
+		"scope": fcmScope,
+		"aud":   f.TokenURL,
+		"iat":   now.Unix(),
+		"exp":   now.Add(fcmTokenLifetime).Unix(),
+	}).SignedString(f.key)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign fcm assertion: %w", err)
+	}
+
+	form := url.Values{
+		"grant_type": {"urn:ietf:params:oauth:grant-type:jwt-bearer"},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, f.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := f.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	var body struct {
+		AccessToken      string `json:"access_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		ErrorDescription string `json:"error_description"`
+	}
+	readError(resp, &body)
+	if resp.StatusCode != http.StatusOK || body.AccessToken == "" {
+		return "", fmt.Errorf("fcm token request failed with status %d: %s", resp.StatusCode, body.ErrorDescription)
+	}
+
+	f.accessToken = body.AccessToken
+	f.expiresAt = now.Add(time.Duration(body.ExpiresIn) * time.Second)
+	return f.accessToken, nil
+}
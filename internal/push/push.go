@@ -0,0 +1,29 @@
+// Package push sends notifications to mobile devices through Firebase Cloud Messaging
+// (Android) and the Apple Push Notification service (iOS)
+package push
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrInvalidToken is returned when the provider reports that a device token is no longer
+// valid, e.g. because the app was uninstalled; the token should be forgotten
+var ErrInvalidToken = errors.New("device token is no longer valid")
+
+// Notification is an alert shown on a device
+type Notification struct {
+	Title string
+	Body  string
+	Data  map[string]string // Passed to the app along with the alert
+}
+
+// readError reads a provider's JSON error body into out, ignoring bodies that are not JSON
+func readError(resp *http.Response, out any) {
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err == nil && len(data) > 0 {
+		json.Unmarshal(data, out)
+	}
+}
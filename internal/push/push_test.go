@@ -0,0 +1,152 @@
+package push
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestFCM(t *testing.T, handler http.HandlerFunc) *FCM {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	credentials, _ := json.Marshal(map[string]string{
+		"type":         "service_account",
+		"project_id":   "fitapi-test",
+		"private_key":  string(keyPEM),
+		"client_email": "push@fitapi-test.iam.gserviceaccount.com",
+		"token_uri":    server.URL + "/token",
+	})
+	fcm, err := NewFCM(credentials, nil)
+	if err != nil {
+		t.Fatalf("Expected valid credentials, got %v", err)
+	}
+	fcm.URL = server.URL + "/v1/projects/"
+	return fcm
+}
+
+func TestFCMSend(t *testing.T) {
+	tokenRequests := 0
+	var sent map[string]map[string]any
+	fcm := newTestFCM(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/token":
+			tokenRequests++
+			if r.FormValue("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" || r.FormValue("assertion") == "" {
+				t.Errorf("Unexpected token request: %v", r.Form)
+			}
+			w.Write([]byte(`{"access_token":"ya29.test","expires_in":3599}`))
+		case "/v1/projects/fitapi-test/messages:send":
+			if r.Header.Get("Authorization") != "Bearer ya29.test" {
+				t.Errorf("Expected the access token, got %q", r.Header.Get("Authorization"))
+			}
+			json.NewDecoder(r.Body).Decode(&sent)
+			if sent["message"]["token"] == "gone" {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"code":404,"message":"Requested entity was not found.","status":"NOT_FOUND","details":[{"@type":"type.googleapis.com/google.firebase.fcm.v1.FcmError","errorCode":"UNREGISTERED"}]}}`))
+				return
+			}
+			w.Write([]byte(`{"name":"projects/fitapi-test/messages/1"}`))
+		default:
+			t.Errorf("Unexpected request to %s", r.URL.Path)
+		}
+	})
+
+	n := &Notification{Title: "New workout", Body: "Leg day", Data: map[string]string{"workout_id": "workout-1"}}
+	if err := fcm.Send(context.Background(), "device-1", n); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	notification, _ := sent["message"]["notification"].(map[string]any)
+	data, _ := sent["message"]["data"].(map[string]any)
+	if notification["title"] != "New workout" || data["workout_id"] != "workout-1" {
+		t.Errorf("Unexpected message: %v", sent)
+	}
+
+	if err := fcm.Send(context.Background(), "gone", n); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+	if tokenRequests != 1 {
+		t.Errorf("Expected the access token to be reused, got %d token requests", tokenRequests)
+	}
+}
+
+func TestNewFCM_RejectsOtherCredentials(t *testing.T) {
+	if _, err := NewFCM([]byte(`{"type":"authorized_user"}`), nil); err == nil {
+		t.Error("Expected user credentials to be rejected")
+	}
+}
+
+func TestAPNsSend(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(key)
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})
+
+	var payload map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bearer := strings.TrimPrefix(r.Header.Get("Authorization"), "bearer ")
+		token, err := jwt.Parse(bearer, func(token *jwt.Token) (any, error) { return &key.PublicKey, nil })
+		if err != nil || token.Header["kid"] != "KEY123" {
+			t.Errorf("Expected a provider token signed with the key, got %v", err)
+		}
+		if r.Header.Get("apns-topic") != "com.example.fitapi" || r.Header.Get("apns-push-type") != "alert" {
+			t.Errorf("Unexpected headers: %v", r.Header)
+		}
+
+		switch r.URL.Path {
+		case "/3/device/gone":
+			w.WriteHeader(http.StatusGone)
+			w.Write([]byte(`{"reason":"Unregistered","timestamp":1715600000000}`))
+		case "/3/device/bad":
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"reason":"PayloadTooLarge"}`))
+		default:
+			json.NewDecoder(r.Body).Decode(&payload)
+		}
+	}))
+	defer server.Close()
+
+	apns, err := NewAPNs(APNsConfig{KeyID: "KEY123", TeamID: "TEAM123", Topic: "com.example.fitapi", PrivateKey: keyPEM}, nil)
+	if err != nil {
+		t.Fatalf("Expected a valid key, got %v", err)
+	}
+	apns.URL = server.URL
+
+	n := &Notification{Title: "New workout", Body: "Leg day", Data: map[string]string{"workout_id": "workout-1"}}
+	if err := apns.Send(context.Background(), "device-1", n); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	aps, _ := payload["aps"].(map[string]any)
+	alert, _ := aps["alert"].(map[string]any)
+	if alert["body"] != "Leg day" || payload["workout_id"] != "workout-1" {
+		t.Errorf("Unexpected payload: %v", payload)
+	}
+
+	if err := apns.Send(context.Background(), "gone", n); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected ErrInvalidToken, got %v", err)
+	}
+	if err := apns.Send(context.Background(), "bad", n); err == nil || errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Expected a payload error to keep the token, got %v", err)
+	}
+}
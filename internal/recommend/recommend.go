@@ -0,0 +1,181 @@
+// Package recommend proposes a workout from the user's training history, equipment and
+// goal. Strategy is the extension point: the rule-based Heuristic is the default, and a
+// learned model can replace it without the service or API changing.
+package recommend
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+const (
+	// groupsPerSession is how many of the most overdue muscle groups a session targets
+	groupsPerSession = 3
+	// exercisesPerGroup caps the exercises picked for one of those groups
+	exercisesPerGroup = 2
+)
+
+// Input is what a strategy knows about the user when recommending a session
+type Input struct {
+	Now       time.Time
+	Goal      string // One of the models.TrainingGoal constants
+	Exercises int    // How many exercises to recommend
+
+	// FocusExerciseID is the lift of the user's e1RM goal, recommended first when it is
+	// among the candidates
+	FocusExerciseID *string
+
+	// Recency is when each muscle group was last trained; groups never trained are absent
+	Recency []*models.MuscleGroupRecency
+	// Candidates are the exercises the user can do with their equipment and injuries
+	Candidates []*models.RecommendationCandidate
+}
+
+// Strategy builds a recommended session
+type Strategy interface {
+	Name() string
+	Recommend(ctx context.Context, in *Input) (*models.WorkoutRecommendation, error)
+}
+
+// Prescription is the sets, reps and rest a goal calls for
+type Prescription struct {
+	Sets        int
+	Reps        int
+	RestSeconds int
+}
+
+var prescriptions = map[string]Prescription{
+	models.TrainingGoalStrength:    {Sets: 5, Reps: 5, RestSeconds: 180},
+	models.TrainingGoalHypertrophy: {Sets: 4, Reps: 10, RestSeconds: 90},
+	models.TrainingGoalFatLoss:     {Sets: 3, Reps: 15, RestSeconds: 45},
+	models.TrainingGoalGeneral:     {Sets: 3, Reps: 10, RestSeconds: 60},
+}
+
+// PrescriptionFor returns the sets and reps for a training goal, falling back to general
+func PrescriptionFor(goal string) Prescription {
+	if p, ok := prescriptions[goal]; ok {
+		return p
+	}
+	return prescriptions[models.TrainingGoalGeneral]
+}
+
+// Heuristic targets the muscle groups trained least recently, preferring exercises the user
+// already knows, and sets sets and reps by goal
+type Heuristic struct{}
+
+// NewHeuristic creates the rule-based strategy
+func NewHeuristic() *Heuristic {
+	return &Heuristic{}
+}
+
+// Name identifies the strategy in recommendations
+func (h *Heuristic) Name() string {
+	return "least_recently_trained"
+}
+
+// Recommend picks up to two exercises from each of the three most overdue muscle groups in
+// turn, topping up from the other groups when that falls short of in.Exercises
+func (h *Heuristic) Recommend(ctx context.Context, in *Input) (*models.WorkoutRecommendation, error) {
+	lastTrained := make(map[string]time.Time, len(in.Recency))
+	for _, r := range in.Recency {
+		lastTrained[r.MuscleGroup] = r.LastTrainedAt
+	}
+
+	byGroup := make(map[string][]*models.RecommendationCandidate)
+	for _, c := range in.Candidates {
+		byGroup[c.MuscleGroup] = append(byGroup[c.MuscleGroup], c)
+	}
+	groups := make([]string, 0, len(byGroup))
+	for group, candidates := range byGroup {
+		groups = append(groups, group)
+		sort.SliceStable(candidates, func(i, j int) bool {
+			if candidates[i].TimesPerformed != candidates[j].TimesPerformed {
+				return candidates[i].TimesPerformed > candidates[j].TimesPerformed
+			}
+			return candidates[i].Name < candidates[j].Name
+		})
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		a, aTrained := lastTrained[groups[i]]
+		b, bTrained := lastTrained[groups[j]]
+		switch {
+		case aTrained != bTrained:
+			return !aTrained
+		case !a.Equal(b):
+			return a.Before(b)
+		default:
+			return groups[i] < groups[j]
+		}
+	})
+
+	rx := PrescriptionFor(in.Goal)
+	rec := &models.WorkoutRecommendation{
+		Strategy:     h.Name(),
+		Goal:         in.Goal,
+		MuscleGroups: []string{},
+		Exercises:    []*models.RecommendedExercise{},
+	}
+	picked := make(map[string]bool)
+	targeted := make(map[string]bool)
+	add := func(c *models.RecommendationCandidate, reason string) {
+		if len(rec.Exercises) >= in.Exercises || picked[c.ExerciseID] {
+			return
+		}
+		picked[c.ExerciseID] = true
+		if !targeted[c.MuscleGroup] {
+			targeted[c.MuscleGroup] = true
+			rec.MuscleGroups = append(rec.MuscleGroups, c.MuscleGroup)
+		}
+		rec.Exercises = append(rec.Exercises, &models.RecommendedExercise{
+			ExerciseID:  c.ExerciseID,
+			Name:        c.Name,
+			MuscleGroup: c.MuscleGroup,
+			Sets:        rx.Sets,
+			Reps:        rx.Reps,
+			RestSeconds: rx.RestSeconds,
+			Reason:      reason,
+		})
+	}
+
+	if in.FocusExerciseID != nil {
+		for _, c := range in.Candidates {
+			if c.ExerciseID == *in.FocusExerciseID {
+				add(c, "The lift of your strength goal")
+			}
+		}
+	}
+
+	primary := groups[:min(groupsPerSession, len(groups))]
+	for round := range exercisesPerGroup {
+		for _, group := range primary {
+			if round < len(byGroup[group]) {
+				add(byGroup[group][round], recencyReason(group, lastTrained, in.Now))
+			}
+		}
+	}
+	for _, group := range groups[len(primary):] {
+		add(byGroup[group][0], recencyReason(group, lastTrained, in.Now))
+	}
+
+	return rec, nil
+}
+
+// recencyReason explains a pick by how long ago its muscle group was trained
+func recencyReason(group string, lastTrained map[string]time.Time, now time.Time) string {
+	at, ok := lastTrained[group]
+	if !ok {
+		return fmt.Sprintf("You haven't trained %s yet", group)
+	}
+	switch days := int(now.Sub(at).Hours() / 24); days {
+	case 0:
+		return fmt.Sprintf("You trained %s today", group)
+	case 1:
+		return fmt.Sprintf("You last trained %s yesterday", group)
+	default:
+		return fmt.Sprintf("You last trained %s %d days ago", group, days)
+	}
+}
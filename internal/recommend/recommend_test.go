@@ -0,0 +1,114 @@
+package recommend
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+func candidate(id, group string, times int) *models.RecommendationCandidate {
+	return &models.RecommendationCandidate{ExerciseID: id, Name: id, MuscleGroup: group, TimesPerformed: times}
+}
+
+func exerciseIDs(rec *models.WorkoutRecommendation) []string {
+	ids := make([]string, 0, len(rec.Exercises))
+	for _, e := range rec.Exercises {
+		ids = append(ids, e.ExerciseID)
+	}
+	return ids
+}
+
+func TestHeuristic_LeastRecentlyTrainedFirst(t *testing.T) {
+	now := time.Date(2026, 6, 10, 12, 0, 0, 0, time.UTC)
+	in := &Input{
+		Now:       now,
+		Goal:      models.TrainingGoalGeneral,
+		Exercises: 5,
+		Recency: []*models.MuscleGroupRecency{
+			{MuscleGroup: "chest", LastTrainedAt: now.Add(-24 * time.Hour)},
+			{MuscleGroup: "back", LastTrainedAt: now.Add(-9 * 24 * time.Hour)},
+			{MuscleGroup: "quads", LastTrainedAt: now.Add(-4 * 24 * time.Hour)},
+		},
+		Candidates: []*models.RecommendationCandidate{
+			candidate("bench", "chest", 20),
+			candidate("row", "back", 2),
+			candidate("pullup", "back", 8),
+			candidate("deadlift", "back", 0),
+			candidate("squat", "quads", 5),
+			candidate("lunge", "quads", 0),
+			candidate("curl", "biceps", 0),
+		},
+	}
+
+	rec, err := NewHeuristic().Recommend(context.Background(), in)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	// Biceps never trained, then back, then quads; the most familiar exercises first
+	if want := []string{"curl", "pullup", "squat", "row", "lunge"}; !slices.Equal(exerciseIDs(rec), want) {
+		t.Errorf("Expected %v, got %v", want, exerciseIDs(rec))
+	}
+	if !slices.Equal(rec.MuscleGroups, []string{"biceps", "back", "quads"}) {
+		t.Errorf("Expected biceps, back and quads targeted, got %v", rec.MuscleGroups)
+	}
+	if rec.Exercises[1].Reason != "You last trained back 9 days ago" || rec.Exercises[0].Reason != "You haven't trained biceps yet" {
+		t.Errorf("Unexpected reasons %q and %q", rec.Exercises[0].Reason, rec.Exercises[1].Reason)
+	}
+	if e := rec.Exercises[0]; e.Sets != 3 || e.Reps != 10 {
+		t.Errorf("Expected 3x10 for general training, got %dx%d", e.Sets, e.Reps)
+	}
+}
+
+func TestHeuristic_TopsUpFromOtherGroups(t *testing.T) {
+	in := &Input{
+		Now:       time.Now(),
+		Goal:      models.TrainingGoalHypertrophy,
+		Exercises: 4,
+		Candidates: []*models.RecommendationCandidate{
+			candidate("a", "biceps", 0),
+			candidate("b", "calves", 0),
+			candidate("c", "chest", 0),
+			candidate("d", "core", 0),
+		},
+	}
+
+	rec, err := NewHeuristic().Recommend(context.Background(), in)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(exerciseIDs(rec), want) {
+		t.Errorf("Expected %v, got %v", want, exerciseIDs(rec))
+	}
+}
+
+func TestHeuristic_FocusExerciseFirst(t *testing.T) {
+	focus := "squat"
+	in := &Input{
+		Now:             time.Now(),
+		Goal:            models.TrainingGoalStrength,
+		Exercises:       2,
+		FocusExerciseID: &focus,
+		Recency:         []*models.MuscleGroupRecency{{MuscleGroup: "quads", LastTrainedAt: time.Now()}},
+		Candidates: []*models.RecommendationCandidate{
+			candidate("bench", "chest", 3),
+			candidate("squat", "quads", 3),
+		},
+	}
+
+	rec, err := NewHeuristic().Recommend(context.Background(), in)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := []string{"squat", "bench"}; !slices.Equal(exerciseIDs(rec), want) {
+		t.Errorf("Expected %v, got %v", want, exerciseIDs(rec))
+	}
+	if e := rec.Exercises[0]; e.Sets != 5 || e.Reps != 5 || e.RestSeconds != 180 {
+		t.Errorf("Expected 5x5 with 3 minutes rest for strength, got %+v", e)
+	}
+}
@@ -0,0 +1,191 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// AnalyticsRepository defines the interface for dashboard aggregates, read from
+// materialized views that lag the history until refreshed; daily figures are live
+type AnalyticsRepository interface {
+	WeeklyMuscleVolume(ctx context.Context, userID string, from, to time.Time) ([]*models.MuscleVolumeWeek, error)
+	E1RMBests(ctx context.Context, userID string) ([]*models.E1RMBest, error)
+	DailyTraining(ctx context.Context, userID string, day time.Time) (*models.DailySummary, error)
+	SleepSessions(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepSession, error)
+	RefreshViews(ctx context.Context) (bool, error)
+}
+
+// PostgresAnalyticsRepository is the PostgreSQL implementation of AnalyticsRepository
+type PostgresAnalyticsRepository struct {
+	db DB
+}
+
+// NewPostgresAnalyticsRepository creates a new PostgreSQL analytics repository
+func NewPostgresAnalyticsRepository(db DB) AnalyticsRepository {
+	return &PostgresAnalyticsRepository{db: db}
+}
+
+// analyticsViews are refreshed in order by RefreshViews
+var analyticsViews = []string{"weekly_muscle_volume", "exercise_e1rm_bests"}
+
+// WeeklyMuscleVolume retrieves the user's volume per muscle group for weeks starting in
+// [from, to), oldest week first
+func (r *PostgresAnalyticsRepository) WeeklyMuscleVolume(ctx context.Context, userID string, from, to time.Time) ([]*models.MuscleVolumeWeek, error) {
+	query := `
+		SELECT to_char(week, 'YYYY-MM-DD'), muscle_group, volume_kg, sets
+		FROM weekly_muscle_volume
+		WHERE user_id = $1 AND week >= $2::date AND week < $3::date
+		ORDER BY week ASC, volume_kg DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	weeks := []*models.MuscleVolumeWeek{}
+	for rows.Next() {
+		w := &models.MuscleVolumeWeek{}
+		if err := rows.Scan(&w.Week, &w.MuscleGroup, &w.VolumeKg, &w.Sets); err != nil {
+			return nil, err
+		}
+		weeks = append(weeks, w)
+	}
+
+	return weeks, rows.Err()
+}
+
+// E1RMBests retrieves the user's best estimated one-rep max per exercise, highest first
+func (r *PostgresAnalyticsRepository) E1RMBests(ctx context.Context, userID string) ([]*models.E1RMBest, error) {
+	query := `
+		SELECT b.exercise_id, e.name, b.e1rm_kg, b.weight_kg, b.reps, b.achieved_at
+		FROM exercise_e1rm_bests b
+		JOIN exercises e ON e.id = b.exercise_id
+		WHERE b.user_id = $1
+		ORDER BY b.e1rm_kg DESC, e.name ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	bests := []*models.E1RMBest{}
+	for rows.Next() {
+		b := &models.E1RMBest{}
+		if err := rows.Scan(&b.ExerciseID, &b.ExerciseName, &b.E1RMKg, &b.WeightKg, &b.Reps, &b.AchievedAt); err != nil {
+			return nil, err
+		}
+		bests = append(bests, b)
+	}
+
+	return bests, rows.Err()
+}
+
+// DailyTraining totals the sessions the user completed on a day of their timezone; the
+// summary's date and hydration are left to the caller
+func (r *PostgresAnalyticsRepository) DailyTraining(ctx context.Context, userID string, day time.Time) (*models.DailySummary, error) {
+	query := `
+		SELECT COUNT(*),
+		       COALESCE(SUM(COALESCE(s.duration_minutes, 0)), 0),
+		       COALESCE(SUM((SELECT COALESCE(SUM(COALESCE(l.weight_kg, 0) * COALESCE(l.reps_completed, 0) * COALESCE(l.sets_completed, 1)), 0)
+		                      FROM exercise_logs l WHERE l.workout_session_id = s.id AND l.skipped_at IS NULL)), 0)::float8
+		FROM workout_sessions s, (SELECT user_timezone($1) AS tz) z
+		WHERE s.user_id = $1
+		  AND s.status = 'completed'
+		  AND s.started_at >= ($2::date::timestamp AT TIME ZONE z.tz)
+		  AND s.started_at < (($2::date + 1)::timestamp AT TIME ZONE z.tz)
+	`
+
+	summary := &models.DailySummary{}
+	err := r.db.QueryRow(ctx, query, userID, day).Scan(&summary.Sessions, &summary.Minutes, &summary.VolumeKg)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// SleepSessions retrieves the user's sessions completed between the dates from and to
+// (exclusive) of their timezone, oldest first, each with the sleep that ended in the 18
+// hours before it started, if any. Relative volume is left to the caller.
+func (r *PostgresAnalyticsRepository) SleepSessions(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepSession, error) {
+	query := `
+		SELECT s.id, s.workout_id, s.started_at, sl.duration_minutes, sl.quality,
+		       COALESCE(v.volume_kg, 0)::float8,
+		       COALESCE(s.perceived_exertion::float8, v.rpe)
+		FROM workout_sessions s
+		CROSS JOIN (SELECT user_timezone($1) AS tz) z
+		LEFT JOIN LATERAL (
+			SELECT l.duration_minutes, l.quality
+			FROM sleep_logs l
+			WHERE l.user_id = s.user_id
+			  AND l.ended_at <= s.started_at AND l.ended_at > s.started_at - INTERVAL '18 hours'
+			ORDER BY l.ended_at DESC
+			LIMIT 1
+		) sl ON TRUE
+		LEFT JOIN LATERAL (
+			SELECT SUM(COALESCE(l.weight_kg, 0) * COALESCE(l.reps_completed, 0) * COALESCE(l.sets_completed, 1)) AS volume_kg,
+			       AVG(l.rpe)::float8 AS rpe
+			FROM exercise_logs l
+			WHERE l.workout_session_id = s.id AND l.skipped_at IS NULL
+		) v ON TRUE
+		WHERE s.user_id = $1
+		  AND s.status = 'completed'
+		  AND s.started_at >= ($2::date::timestamp AT TIME ZONE z.tz)
+		  AND s.started_at < ($3::date::timestamp AT TIME ZONE z.tz)
+		ORDER BY s.started_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*models.SleepSession{}
+	for rows.Next() {
+		ss := &models.SleepSession{}
+		if err := rows.Scan(&ss.SessionID, &ss.WorkoutID, &ss.StartedAt, &ss.SleepMinutes, &ss.SleepQuality, &ss.VolumeKg, &ss.RPE); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, ss)
+	}
+
+	return sessions, rows.Err()
+}
+
+// RefreshViews recomputes the analytics views. Refreshing concurrently keeps them readable
+// meanwhile. An advisory lock lets one instance refresh at a time; the others skip, and
+// false is returned.
+func (r *PostgresAnalyticsRepository) RefreshViews(ctx context.Context) (bool, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback(ctx)
+
+	var locked bool
+	if err := tx.QueryRow(ctx, `SELECT pg_try_advisory_xact_lock(hashtext('analytics_views'))`).Scan(&locked); err != nil {
+		return false, err
+	}
+	if !locked {
+		return false, nil
+	}
+
+	// Refreshes scan every user's history and may outlast the pool's statement timeout
+	if _, err := tx.Exec(ctx, `SET LOCAL statement_timeout = 0`); err != nil {
+		return false, err
+	}
+
+	for _, view := range analyticsViews {
+		if _, err := tx.Exec(ctx, `REFRESH MATERIALIZED VIEW CONCURRENTLY `+view); err != nil {
+			return false, err
+		}
+	}
+
+	return true, tx.Commit(ctx)
+}
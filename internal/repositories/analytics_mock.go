@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockAnalyticsRepository is a mock implementation for testing
+type MockAnalyticsRepository struct {
+	WeeklyMuscleVolumeFunc func(ctx context.Context, userID string, from, to time.Time) ([]*models.MuscleVolumeWeek, error)
+	E1RMBestsFunc          func(ctx context.Context, userID string) ([]*models.E1RMBest, error)
+	DailyTrainingFunc      func(ctx context.Context, userID string, day time.Time) (*models.DailySummary, error)
+	SleepSessionsFunc      func(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepSession, error)
+	RefreshViewsFunc       func(ctx context.Context) (bool, error)
+}
+
+func (m *MockAnalyticsRepository) WeeklyMuscleVolume(ctx context.Context, userID string, from, to time.Time) ([]*models.MuscleVolumeWeek, error) {
+	if m.WeeklyMuscleVolumeFunc != nil {
+		return m.WeeklyMuscleVolumeFunc(ctx, userID, from, to)
+	}
+	return []*models.MuscleVolumeWeek{}, nil
+}
+
+func (m *MockAnalyticsRepository) E1RMBests(ctx context.Context, userID string) ([]*models.E1RMBest, error) {
+	if m.E1RMBestsFunc != nil {
+		return m.E1RMBestsFunc(ctx, userID)
+	}
+	return []*models.E1RMBest{}, nil
+}
+
+func (m *MockAnalyticsRepository) DailyTraining(ctx context.Context, userID string, day time.Time) (*models.DailySummary, error) {
+	if m.DailyTrainingFunc != nil {
+		return m.DailyTrainingFunc(ctx, userID, day)
+	}
+	return &models.DailySummary{}, nil
+}
+
+func (m *MockAnalyticsRepository) SleepSessions(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepSession, error) {
+	if m.SleepSessionsFunc != nil {
+		return m.SleepSessionsFunc(ctx, userID, from, to)
+	}
+	return []*models.SleepSession{}, nil
+}
+
+func (m *MockAnalyticsRepository) RefreshViews(ctx context.Context) (bool, error) {
+	if m.RefreshViewsFunc != nil {
+		return m.RefreshViewsFunc(ctx)
+	}
+	return true, nil
+}
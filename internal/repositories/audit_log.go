@@ -0,0 +1,82 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// AuditLogRepository defines the interface for audit log data access
+type AuditLogRepository interface {
+	Create(ctx context.Context, entry *models.AuditLog) error
+	List(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error)
+}
+
+// PostgresAuditLogRepository is the PostgreSQL implementation of AuditLogRepository
+type PostgresAuditLogRepository struct {
+	db DB
+}
+
+// NewPostgresAuditLogRepository creates a new PostgreSQL audit log repository
+func NewPostgresAuditLogRepository(db DB) AuditLogRepository {
+	return &PostgresAuditLogRepository{db: db}
+}
+
+const auditLogColumns = `id, actor_id, action, resource_type, resource_id, changes, created_at`
+
+// Create stores an entry and sets its ID and creation time
+func (r *PostgresAuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	query := `
+		INSERT INTO audit_logs (actor_id, action, resource_type, resource_id, changes)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, entry.ActorID, entry.Action, entry.ResourceType, entry.ResourceID, entry.Changes).
+		Scan(&entry.ID, &entry.CreatedAt)
+}
+
+// List retrieves the most recent entries matching the filter
+func (r *PostgresAuditLogRepository) List(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	query := `
+		SELECT ` + auditLogColumns + `
+		FROM audit_logs
+		WHERE ($1 = '' OR actor_id = $1::uuid)
+		  AND ($2 = '' OR resource_type = $2)
+		  AND ($3 = '' OR resource_id = $3)
+		  AND ($4::timestamptz IS NULL OR created_at >= $4)
+		  AND ($5::timestamptz IS NULL OR created_at < $5)
+		ORDER BY created_at DESC, id
+		LIMIT $6
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.ActorID, filter.ResourceType, filter.ResourceID, filter.From, filter.Until, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanAuditLogs(rows)
+}
+
+func scanAuditLogs(rows pgx.Rows) ([]*models.AuditLog, error) {
+	entries := []*models.AuditLog{}
+	for rows.Next() {
+		entry := &models.AuditLog{}
+		err := rows.Scan(
+			&entry.ID,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.ResourceType,
+			&entry.ResourceID,
+			&entry.Changes,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
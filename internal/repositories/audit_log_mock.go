@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockAuditLogRepository is a mock implementation for testing
+type MockAuditLogRepository struct {
+	CreateFunc func(ctx context.Context, entry *models.AuditLog) error
+	ListFunc   func(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error)
+}
+
+func (m *MockAuditLogRepository) Create(ctx context.Context, entry *models.AuditLog) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, entry)
+	}
+	return nil
+}
+
+func (m *MockAuditLogRepository) List(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter)
+	}
+	return []*models.AuditLog{}, nil
+}
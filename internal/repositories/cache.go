@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// cacheInvalidationChannel is the notification channel written by the cache invalidation triggers
+const cacheInvalidationChannel = "cache_invalidation"
+
+// CacheRepository defines the interface for learning which cached reads went stale
+type CacheRepository interface {
+	Listen(ctx context.Context, handle func(scope string)) error
+}
+
+// PostgresCacheRepository is the PostgreSQL implementation of CacheRepository
+type PostgresCacheRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresCacheRepository creates a new PostgreSQL cache repository
+func NewPostgresCacheRepository(db *pgxpool.Pool) CacheRepository {
+	return &PostgresCacheRepository{db: db}
+}
+
+// Listen calls handle with the scope of every cache invalidation until ctx is cancelled or
+// the connection fails
+func (r *PostgresCacheRepository) Listen(ctx context.Context, handle func(scope string)) error {
+	return listen(ctx, r.db, cacheInvalidationChannel, handle)
+}
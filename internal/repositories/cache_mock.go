@@ -0,0 +1,18 @@
+package repositories
+
+import (
+	"context"
+)
+
+// MockCacheRepository is a mock implementation for testing
+type MockCacheRepository struct {
+	ListenFunc func(ctx context.Context, handle func(scope string)) error
+}
+
+func (m *MockCacheRepository) Listen(ctx context.Context, handle func(scope string)) error {
+	if m.ListenFunc != nil {
+		return m.ListenFunc(ctx, handle)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
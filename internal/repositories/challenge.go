@@ -0,0 +1,254 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ChallengeRepository defines the interface for challenge data access
+type ChallengeRepository interface {
+	Create(ctx context.Context, challenge *models.Challenge) error
+	FindByID(ctx context.Context, id string) (*models.Challenge, error)
+	FindCurrent(ctx context.Context, today time.Time) ([]*models.Challenge, error)
+	FindParticipant(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error)
+	AddParticipant(ctx context.Context, participant *models.ChallengeParticipant) error
+	RemoveParticipant(ctx context.Context, challengeID string, userID string) error
+	UpsertCompletion(ctx context.Context, completion *models.ChallengeCompletion) error
+	CountCompletions(ctx context.Context, challengeID string, userID string) (int, error)
+	MarkCompleted(ctx context.Context, challengeID string, userID string, at time.Time) error
+	Leaderboard(ctx context.Context, challengeID string, limit int) ([]*models.ChallengeStanding, error)
+}
+
+// PostgresChallengeRepository is the PostgreSQL implementation of ChallengeRepository
+type PostgresChallengeRepository struct {
+	db DB
+}
+
+// NewPostgresChallengeRepository creates a new PostgreSQL challenge repository
+func NewPostgresChallengeRepository(db DB) ChallengeRepository {
+	return &PostgresChallengeRepository{db: db}
+}
+
+const challengeColumns = `id, name, COALESCE(description, ''), daily_target, unit, metric, goal_value::float8, starts_on, ends_on, created_by, created_at, updated_at`
+
+func scanChallenge(row pgx.Row) (*models.Challenge, error) {
+	challenge := &models.Challenge{}
+	err := row.Scan(
+		&challenge.ID,
+		&challenge.Name,
+		&challenge.Description,
+		&challenge.DailyTarget,
+		&challenge.Unit,
+		&challenge.Metric,
+		&challenge.GoalValue,
+		&challenge.StartsOn,
+		&challenge.EndsOn,
+		&challenge.CreatedBy,
+		&challenge.CreatedAt,
+		&challenge.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+// Create inserts a new challenge
+func (r *PostgresChallengeRepository) Create(ctx context.Context, challenge *models.Challenge) error {
+	query := `
+		INSERT INTO challenges (name, description, daily_target, unit, metric, goal_value, starts_on, ends_on, created_by)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		challenge.Name,
+		challenge.Description,
+		challenge.DailyTarget,
+		challenge.Unit,
+		challenge.Metric,
+		challenge.GoalValue,
+		challenge.StartsOn,
+		challenge.EndsOn,
+		challenge.CreatedBy,
+	).Scan(&challenge.ID, &challenge.CreatedAt, &challenge.UpdatedAt)
+}
+
+// FindByID retrieves a single challenge by ID
+func (r *PostgresChallengeRepository) FindByID(ctx context.Context, id string) (*models.Challenge, error) {
+	query := `SELECT ` + challengeColumns + ` FROM challenges WHERE id = $1`
+	return scanChallenge(r.db.QueryRow(ctx, query, id))
+}
+
+// FindCurrent retrieves challenges that are running or upcoming on the given day
+func (r *PostgresChallengeRepository) FindCurrent(ctx context.Context, today time.Time) ([]*models.Challenge, error) {
+	query := `
+		SELECT ` + challengeColumns + `
+		FROM challenges
+		WHERE ends_on >= $1
+		ORDER BY starts_on ASC, name ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, today)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var challenges []*models.Challenge
+	for rows.Next() {
+		challenge, err := scanChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		challenges = append(challenges, challenge)
+	}
+
+	return challenges, rows.Err()
+}
+
+// FindParticipant retrieves a user's participation in a challenge
+func (r *PostgresChallengeRepository) FindParticipant(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+	query := `
+		SELECT challenge_id, user_id, joined_at, completed_at
+		FROM challenge_participants
+		WHERE challenge_id = $1 AND user_id = $2
+	`
+
+	participant := &models.ChallengeParticipant{}
+	err := r.db.QueryRow(ctx, query, challengeID, userID).Scan(
+		&participant.ChallengeID,
+		&participant.UserID,
+		&participant.JoinedAt,
+		&participant.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return participant, nil
+}
+
+// AddParticipant joins a user to a challenge; joining twice keeps the original row
+func (r *PostgresChallengeRepository) AddParticipant(ctx context.Context, participant *models.ChallengeParticipant) error {
+	query := `
+		INSERT INTO challenge_participants (challenge_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (challenge_id, user_id) DO UPDATE SET user_id = EXCLUDED.user_id
+		RETURNING joined_at, completed_at
+	`
+
+	return r.db.QueryRow(ctx, query, participant.ChallengeID, participant.UserID).Scan(
+		&participant.JoinedAt,
+		&participant.CompletedAt,
+	)
+}
+
+// RemoveParticipant removes a user and their completions from a challenge
+// Returns pgx.ErrNoRows if the user had not joined.
+func (r *PostgresChallengeRepository) RemoveParticipant(ctx context.Context, challengeID string, userID string) error {
+	query := `DELETE FROM challenge_participants WHERE challenge_id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, challengeID, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// UpsertCompletion records a completed day, replacing the value if the day was already checked off
+func (r *PostgresChallengeRepository) UpsertCompletion(ctx context.Context, completion *models.ChallengeCompletion) error {
+	query := `
+		INSERT INTO challenge_completions (challenge_id, user_id, day, value)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (challenge_id, user_id, day) DO UPDATE SET value = EXCLUDED.value
+		RETURNING created_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		completion.ChallengeID,
+		completion.UserID,
+		completion.Day,
+		completion.Value,
+	).Scan(&completion.CreatedAt)
+}
+
+// CountCompletions returns how many days a participant has completed
+func (r *PostgresChallengeRepository) CountCompletions(ctx context.Context, challengeID string, userID string) (int, error) {
+	query := `SELECT COUNT(*) FROM challenge_completions WHERE challenge_id = $1 AND user_id = $2`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, challengeID, userID).Scan(&count)
+	return count, err
+}
+
+// MarkCompleted records when a participant finished the whole challenge
+func (r *PostgresChallengeRepository) MarkCompleted(ctx context.Context, challengeID string, userID string, at time.Time) error {
+	query := `
+		UPDATE challenge_participants
+		SET completed_at = $3
+		WHERE challenge_id = $1 AND user_id = $2 AND completed_at IS NULL
+	`
+
+	_, err := r.db.Exec(ctx, query, challengeID, userID, at)
+	return err
+}
+
+// Leaderboard ranks participants by completed days, then total value, then who finished
+// first. Challenges with a goal have no completed days and rank by progress instead.
+func (r *PostgresChallengeRepository) Leaderboard(ctx context.Context, challengeID string, limit int) ([]*models.ChallengeStanding, error) {
+	query := `
+		SELECT RANK() OVER (ORDER BY COUNT(c.day) DESC, COALESCE(SUM(c.value), 0) DESC, progress DESC NULLS LAST),
+		       p.user_id,
+		       COUNT(c.day),
+		       COALESCE(SUM(c.value), 0),
+		       progress,
+		       p.completed_at
+		FROM challenge_participants p
+		JOIN challenges ch ON ch.id = p.challenge_id
+		CROSS JOIN LATERAL (
+			SELECT CASE WHEN ch.metric IS NOT NULL THEN challenge_progress(p.challenge_id, p.user_id) END AS progress
+		) g
+		LEFT JOIN challenge_completions c ON c.challenge_id = p.challenge_id AND c.user_id = p.user_id
+		WHERE p.challenge_id = $1
+		GROUP BY p.user_id, p.completed_at, p.joined_at, progress
+		ORDER BY 1 ASC, p.completed_at ASC NULLS LAST, p.joined_at ASC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, challengeID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var standings []*models.ChallengeStanding
+	for rows.Next() {
+		standing := &models.ChallengeStanding{}
+		err := rows.Scan(
+			&standing.Rank,
+			&standing.UserID,
+			&standing.DaysCompleted,
+			&standing.TotalValue,
+			&standing.Progress,
+			&standing.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		standings = append(standings, standing)
+	}
+
+	return standings, rows.Err()
+}
@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockChallengeRepository is a mock implementation for testing
+type MockChallengeRepository struct {
+	CreateFunc            func(ctx context.Context, challenge *models.Challenge) error
+	FindByIDFunc          func(ctx context.Context, id string) (*models.Challenge, error)
+	FindCurrentFunc       func(ctx context.Context, today time.Time) ([]*models.Challenge, error)
+	FindParticipantFunc   func(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error)
+	AddParticipantFunc    func(ctx context.Context, participant *models.ChallengeParticipant) error
+	RemoveParticipantFunc func(ctx context.Context, challengeID string, userID string) error
+	UpsertCompletionFunc  func(ctx context.Context, completion *models.ChallengeCompletion) error
+	CountCompletionsFunc  func(ctx context.Context, challengeID string, userID string) (int, error)
+	MarkCompletedFunc     func(ctx context.Context, challengeID string, userID string, at time.Time) error
+	LeaderboardFunc       func(ctx context.Context, challengeID string, limit int) ([]*models.ChallengeStanding, error)
+}
+
+func (m *MockChallengeRepository) Create(ctx context.Context, challenge *models.Challenge) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, challenge)
+	}
+	challenge.ID = "mock-challenge-id"
+	return nil
+}
+
+func (m *MockChallengeRepository) FindByID(ctx context.Context, id string) (*models.Challenge, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockChallengeRepository) FindCurrent(ctx context.Context, today time.Time) ([]*models.Challenge, error) {
+	if m.FindCurrentFunc != nil {
+		return m.FindCurrentFunc(ctx, today)
+	}
+	return []*models.Challenge{}, nil
+}
+
+func (m *MockChallengeRepository) FindParticipant(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+	if m.FindParticipantFunc != nil {
+		return m.FindParticipantFunc(ctx, challengeID, userID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockChallengeRepository) AddParticipant(ctx context.Context, participant *models.ChallengeParticipant) error {
+	if m.AddParticipantFunc != nil {
+		return m.AddParticipantFunc(ctx, participant)
+	}
+	return nil
+}
+
+func (m *MockChallengeRepository) RemoveParticipant(ctx context.Context, challengeID string, userID string) error {
+	if m.RemoveParticipantFunc != nil {
+		return m.RemoveParticipantFunc(ctx, challengeID, userID)
+	}
+	return nil
+}
+
+func (m *MockChallengeRepository) UpsertCompletion(ctx context.Context, completion *models.ChallengeCompletion) error {
+	if m.UpsertCompletionFunc != nil {
+		return m.UpsertCompletionFunc(ctx, completion)
+	}
+	return nil
+}
+
+func (m *MockChallengeRepository) CountCompletions(ctx context.Context, challengeID string, userID string) (int, error) {
+	if m.CountCompletionsFunc != nil {
+		return m.CountCompletionsFunc(ctx, challengeID, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockChallengeRepository) MarkCompleted(ctx context.Context, challengeID string, userID string, at time.Time) error {
+	if m.MarkCompletedFunc != nil {
+		return m.MarkCompletedFunc(ctx, challengeID, userID, at)
+	}
+	return nil
+}
+
+func (m *MockChallengeRepository) Leaderboard(ctx context.Context, challengeID string, limit int) ([]*models.ChallengeStanding, error) {
+	if m.LeaderboardFunc != nil {
+		return m.LeaderboardFunc(ctx, challengeID, limit)
+	}
+	return []*models.ChallengeStanding{}, nil
+}
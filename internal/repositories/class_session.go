@@ -0,0 +1,212 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ClassSessionRepository defines the interface for organization class data access
+type ClassSessionRepository interface {
+	Create(ctx context.Context, class *models.ClassSession) error
+	FindByID(ctx context.Context, id string, userID string) (*models.ClassSession, error)
+	ListByOrganization(ctx context.Context, orgID string, userID string, since time.Time) ([]*models.ClassSession, error)
+	Join(ctx context.Context, classID string, userID string, loads map[string]float64) (*models.ClassParticipant, error)
+	Participants(ctx context.Context, classID string) ([]*models.ClassParticipant, error)
+}
+
+// PostgresClassSessionRepository is the PostgreSQL implementation of ClassSessionRepository
+type PostgresClassSessionRepository struct {
+	db DB
+}
+
+// NewPostgresClassSessionRepository creates a new PostgreSQL class session repository
+func NewPostgresClassSessionRepository(db DB) ClassSessionRepository {
+	return &PostgresClassSessionRepository{db: db}
+}
+
+// classSessionColumns selects a class with its participant count and whether $2 joined it
+const classSessionColumns = `c.id, c.organization_id, c.trainer_id, c.workout_id, c.name, c.starts_at, c.capacity,
+	(SELECT COUNT(*) FROM workout_sessions s WHERE s.class_session_id = c.id)::int,
+	EXISTS (SELECT 1 FROM workout_sessions s WHERE s.class_session_id = c.id AND s.user_id = $2),
+	c.created_at, c.updated_at`
+
+func scanClassSession(row pgx.Row) (*models.ClassSession, error) {
+	class := &models.ClassSession{}
+	err := row.Scan(
+		&class.ID,
+		&class.OrganizationID,
+		&class.TrainerID,
+		&class.WorkoutID,
+		&class.Name,
+		&class.StartsAt,
+		&class.Capacity,
+		&class.Participants,
+		&class.Joined,
+		&class.CreatedAt,
+		&class.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return class, nil
+}
+
+// Create inserts a class
+func (r *PostgresClassSessionRepository) Create(ctx context.Context, class *models.ClassSession) error {
+	query := `
+		INSERT INTO class_sessions (organization_id, trainer_id, workout_id, name, starts_at, capacity)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		class.OrganizationID,
+		class.TrainerID,
+		class.WorkoutID,
+		class.Name,
+		class.StartsAt,
+		class.Capacity,
+	).Scan(&class.ID, &class.CreatedAt, &class.UpdatedAt)
+	return translateError(err)
+}
+
+// FindByID retrieves a class as seen by userID
+// Returns pgx.ErrNoRows if the class does not exist.
+func (r *PostgresClassSessionRepository) FindByID(ctx context.Context, id string, userID string) (*models.ClassSession, error) {
+	query := `SELECT ` + classSessionColumns + ` FROM class_sessions c WHERE c.id = $1`
+	return scanClassSession(r.db.QueryRow(ctx, query, id, userID))
+}
+
+// ListByOrganization retrieves an organization's classes starting from since, soonest first
+func (r *PostgresClassSessionRepository) ListByOrganization(ctx context.Context, orgID string, userID string, since time.Time) ([]*models.ClassSession, error) {
+	query := `
+		SELECT ` + classSessionColumns + `
+		FROM class_sessions c
+		WHERE c.organization_id = $1 AND c.starts_at >= $3
+		ORDER BY c.starts_at, c.id
+	`
+
+	rows, err := r.db.Query(ctx, query, orgID, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	classes := []*models.ClassSession{}
+	for rows.Next() {
+		class, err := scanClassSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, class)
+	}
+
+	return classes, rows.Err()
+}
+
+// Join gives the user their own planned session of the class, starting when the class does,
+// with a set for each exercise of the class workout; loads are their planned weights by
+// workout exercise ID. Joining again returns the session created the first time. The class
+// row is locked so concurrent joins can't overfill it.
+// Returns pgx.ErrNoRows if the class does not exist or is full.
+func (r *PostgresClassSessionRepository) Join(ctx context.Context, classID string, userID string, loads map[string]float64) (*models.ClassParticipant, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var workoutID, name string
+	var startsAt time.Time
+	var capacity *int
+	err = tx.QueryRow(ctx, `SELECT workout_id, name, starts_at, capacity FROM class_sessions WHERE id = $1 FOR UPDATE`, classID).
+		Scan(&workoutID, &name, &startsAt, &capacity)
+	if err != nil {
+		return nil, err
+	}
+
+	participant := &models.ClassParticipant{ClassSessionID: classID, UserID: userID}
+	existing := `
+		SELECT id, status, created_at, completed_at
+		FROM workout_sessions
+		WHERE class_session_id = $1 AND user_id = $2
+	`
+	err = tx.QueryRow(ctx, existing, classID, userID).
+		Scan(&participant.SessionID, &participant.Status, &participant.JoinedAt, &participant.CompletedAt)
+	if err == nil {
+		return participant, tx.Commit(ctx)
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+
+	if capacity != nil {
+		var joined int
+		if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM workout_sessions WHERE class_session_id = $1`, classID).Scan(&joined); err != nil {
+			return nil, err
+		}
+		if joined >= *capacity {
+			return nil, pgx.ErrNoRows
+		}
+	}
+
+	insertSession := `
+		INSERT INTO workout_sessions (user_id, workout_id, class_session_id, name, started_at, status)
+		VALUES ($1, $2, $3, $4, $5, 'planned')
+		RETURNING id, status, created_at
+	`
+	err = tx.QueryRow(ctx, insertSession, userID, workoutID, classID, name, startsAt).
+		Scan(&participant.SessionID, &participant.Status, &participant.JoinedAt)
+	if err != nil {
+		return nil, translateError(err)
+	}
+
+	insertSets := `
+		INSERT INTO exercise_logs (
+			workout_session_id, exercise_id, workout_exercise_id, order_index, sets_completed,
+			sets_planned, reps_planned, rest_time_seconds, intensity_percentage, weight_planned_kg
+		)
+		SELECT $1, we.exercise_id, we.id, we.order_index, 0,
+		       COALESCE(we.sets, 1), we.reps, we.rest_time_seconds, we.intensity_percentage,
+		       ($3::jsonb ->> we.id::text)::real
+		FROM workout_exercises we
+		WHERE we.workout_id = $2
+	`
+	if _, err := tx.Exec(ctx, insertSets, participant.SessionID, workoutID, loads); err != nil {
+		return nil, err
+	}
+
+	return participant, tx.Commit(ctx)
+}
+
+// Participants retrieves the members who joined a class with their sessions' status, in
+// the order they joined
+func (r *PostgresClassSessionRepository) Participants(ctx context.Context, classID string) ([]*models.ClassParticipant, error) {
+	query := `
+		SELECT class_session_id, user_id, id, status, created_at, completed_at
+		FROM workout_sessions
+		WHERE class_session_id = $1
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, classID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	participants := []*models.ClassParticipant{}
+	for rows.Next() {
+		p := &models.ClassParticipant{}
+		if err := rows.Scan(&p.ClassSessionID, &p.UserID, &p.SessionID, &p.Status, &p.JoinedAt, &p.CompletedAt); err != nil {
+			return nil, err
+		}
+		participants = append(participants, p)
+	}
+
+	return participants, rows.Err()
+}
@@ -0,0 +1,54 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockClassSessionRepository is a mock implementation for testing
+type MockClassSessionRepository struct {
+	CreateFunc             func(ctx context.Context, class *models.ClassSession) error
+	FindByIDFunc           func(ctx context.Context, id string, userID string) (*models.ClassSession, error)
+	ListByOrganizationFunc func(ctx context.Context, orgID string, userID string, since time.Time) ([]*models.ClassSession, error)
+	JoinFunc               func(ctx context.Context, classID string, userID string, loads map[string]float64) (*models.ClassParticipant, error)
+	ParticipantsFunc       func(ctx context.Context, classID string) ([]*models.ClassParticipant, error)
+}
+
+func (m *MockClassSessionRepository) Create(ctx context.Context, class *models.ClassSession) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, class)
+	}
+	class.ID = "mock-class-id"
+	return nil
+}
+
+func (m *MockClassSessionRepository) FindByID(ctx context.Context, id string, userID string) (*models.ClassSession, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id, userID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockClassSessionRepository) ListByOrganization(ctx context.Context, orgID string, userID string, since time.Time) ([]*models.ClassSession, error) {
+	if m.ListByOrganizationFunc != nil {
+		return m.ListByOrganizationFunc(ctx, orgID, userID, since)
+	}
+	return []*models.ClassSession{}, nil
+}
+
+func (m *MockClassSessionRepository) Join(ctx context.Context, classID string, userID string, loads map[string]float64) (*models.ClassParticipant, error) {
+	if m.JoinFunc != nil {
+		return m.JoinFunc(ctx, classID, userID, loads)
+	}
+	return &models.ClassParticipant{ClassSessionID: classID, UserID: userID, SessionID: "mock-session-id", Status: "planned"}, nil
+}
+
+func (m *MockClassSessionRepository) Participants(ctx context.Context, classID string) ([]*models.ClassParticipant, error) {
+	if m.ParticipantsFunc != nil {
+		return m.ParticipantsFunc(ctx, classID)
+	}
+	return []*models.ClassParticipant{}, nil
+}
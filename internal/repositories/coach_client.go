@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// CoachClientRepository defines the interface for coach-client relationship data access
+type CoachClientRepository interface {
+	Create(ctx context.Context, relation *models.CoachClient) error
+	FindByID(ctx context.Context, id string) (*models.CoachClient, error)
+	FindByCoach(ctx context.Context, coachID string) ([]*models.CoachClient, error)
+	FindByClient(ctx context.Context, clientID string) ([]*models.CoachClient, error)
+	FindPendingByEmail(ctx context.Context, email string) ([]*models.CoachClient, error)
+	FindActive(ctx context.Context, coachID, clientID string) (*models.CoachClient, error)
+	Update(ctx context.Context, relation *models.CoachClient) error
+}
+
+// PostgresCoachClientRepository is the PostgreSQL implementation of CoachClientRepository
+type PostgresCoachClientRepository struct {
+	db DB
+}
+
+// NewPostgresCoachClientRepository creates a new PostgreSQL coach-client repository
+func NewPostgresCoachClientRepository(db DB) CoachClientRepository {
+	return &PostgresCoachClientRepository{db: db}
+}
+
+const coachClientColumns = `id, coach_id, client_id, client_email, status, can_write, accepted_at, created_at, updated_at`
+
+func scanCoachClient(row pgx.Row) (*models.CoachClient, error) {
+	relation := &models.CoachClient{}
+	err := row.Scan(
+		&relation.ID,
+		&relation.CoachID,
+		&relation.ClientID,
+		&relation.ClientEmail,
+		&relation.Status,
+		&relation.CanWrite,
+		&relation.AcceptedAt,
+		&relation.CreatedAt,
+		&relation.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return relation, nil
+}
+
+func (r *PostgresCoachClientRepository) queryList(ctx context.Context, query string, args ...any) ([]*models.CoachClient, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var relations []*models.CoachClient
+	for rows.Next() {
+		relation, err := scanCoachClient(rows)
+		if err != nil {
+			return nil, err
+		}
+		relations = append(relations, relation)
+	}
+
+	return relations, rows.Err()
+}
+
+// Create inserts a new pending invitation
+func (r *PostgresCoachClientRepository) Create(ctx context.Context, relation *models.CoachClient) error {
+	relation.ID = uuid.New().String()
+
+	query := `
+		INSERT INTO coach_clients (id, coach_id, client_email, status, can_write, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		relation.ID,
+		relation.CoachID,
+		relation.ClientEmail,
+		relation.Status,
+		relation.CanWrite,
+	).Scan(&relation.CreatedAt, &relation.UpdatedAt)
+}
+
+// FindByID retrieves a single relationship by ID
+func (r *PostgresCoachClientRepository) FindByID(ctx context.Context, id string) (*models.CoachClient, error) {
+	query := `SELECT ` + coachClientColumns + ` FROM coach_clients WHERE id = $1`
+	return scanCoachClient(r.db.QueryRow(ctx, query, id))
+}
+
+// FindByCoach retrieves all relationships created by a coach
+func (r *PostgresCoachClientRepository) FindByCoach(ctx context.Context, coachID string) ([]*models.CoachClient, error) {
+	query := `
+		SELECT ` + coachClientColumns + `
+		FROM coach_clients
+		WHERE coach_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.queryList(ctx, query, coachID)
+}
+
+// FindByClient retrieves all accepted relationships for a client
+func (r *PostgresCoachClientRepository) FindByClient(ctx context.Context, clientID string) ([]*models.CoachClient, error) {
+	query := `
+		SELECT ` + coachClientColumns + `
+		FROM coach_clients
+		WHERE client_id = $1
+		ORDER BY created_at DESC
+	`
+	return r.queryList(ctx, query, clientID)
+}
+
+// FindPendingByEmail retrieves open invitations addressed to an email
+func (r *PostgresCoachClientRepository) FindPendingByEmail(ctx context.Context, email string) ([]*models.CoachClient, error) {
+	query := `
+		SELECT ` + coachClientColumns + `
+		FROM coach_clients
+		WHERE client_email = $1 AND status = 'pending'
+		ORDER BY created_at DESC
+	`
+	return r.queryList(ctx, query, email)
+}
+
+// FindActive retrieves the active relationship between a coach and a client
+func (r *PostgresCoachClientRepository) FindActive(ctx context.Context, coachID, clientID string) (*models.CoachClient, error) {
+	query := `
+		SELECT ` + coachClientColumns + `
+		FROM coach_clients
+		WHERE coach_id = $1 AND client_id = $2 AND status = 'active'
+	`
+	return scanCoachClient(r.db.QueryRow(ctx, query, coachID, clientID))
+}
+
+// Update persists status, client and permission changes
+func (r *PostgresCoachClientRepository) Update(ctx context.Context, relation *models.CoachClient) error {
+	query := `
+		UPDATE coach_clients
+		SET client_id = $1, status = $2, can_write = $3, accepted_at = $4, updated_at = NOW()
+		WHERE id = $5
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		relation.ClientID,
+		relation.Status,
+		relation.CanWrite,
+		relation.AcceptedAt,
+		relation.ID,
+	).Scan(&relation.UpdatedAt)
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockCoachClientRepository is a mock implementation for testing
+type MockCoachClientRepository struct {
+	CreateFunc             func(ctx context.Context, relation *models.CoachClient) error
+	FindByIDFunc           func(ctx context.Context, id string) (*models.CoachClient, error)
+	FindByCoachFunc        func(ctx context.Context, coachID string) ([]*models.CoachClient, error)
+	FindByClientFunc       func(ctx context.Context, clientID string) ([]*models.CoachClient, error)
+	FindPendingByEmailFunc func(ctx context.Context, email string) ([]*models.CoachClient, error)
+	FindActiveFunc         func(ctx context.Context, coachID, clientID string) (*models.CoachClient, error)
+	UpdateFunc             func(ctx context.Context, relation *models.CoachClient) error
+}
+
+func (m *MockCoachClientRepository) Create(ctx context.Context, relation *models.CoachClient) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, relation)
+	}
+	return nil
+}
+
+func (m *MockCoachClientRepository) FindByID(ctx context.Context, id string) (*models.CoachClient, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockCoachClientRepository) FindByCoach(ctx context.Context, coachID string) ([]*models.CoachClient, error) {
+	if m.FindByCoachFunc != nil {
+		return m.FindByCoachFunc(ctx, coachID)
+	}
+	return []*models.CoachClient{}, nil
+}
+
+func (m *MockCoachClientRepository) FindByClient(ctx context.Context, clientID string) ([]*models.CoachClient, error) {
+	if m.FindByClientFunc != nil {
+		return m.FindByClientFunc(ctx, clientID)
+	}
+	return []*models.CoachClient{}, nil
+}
+
+func (m *MockCoachClientRepository) FindPendingByEmail(ctx context.Context, email string) ([]*models.CoachClient, error) {
+	if m.FindPendingByEmailFunc != nil {
+		return m.FindPendingByEmailFunc(ctx, email)
+	}
+	return []*models.CoachClient{}, nil
+}
+
+func (m *MockCoachClientRepository) FindActive(ctx context.Context, coachID, clientID string) (*models.CoachClient, error) {
+	if m.FindActiveFunc != nil {
+		return m.FindActiveFunc(ctx, coachID, clientID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockCoachClientRepository) Update(ctx context.Context, relation *models.CoachClient) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, relation)
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// CoachProgramRepository defines the interface for coach program data access
+type CoachProgramRepository interface {
+	Create(ctx context.Context, program *models.CoachProgram) error
+	FindByID(ctx context.Context, id string) (*models.CoachProgram, error)
+	ListByClient(ctx context.Context, clientID string, coachID string) ([]*models.CoachProgram, error)
+	FindWorkout(ctx context.Context, id string) (*models.ProgramWorkout, error)
+	SkipWorkout(ctx context.Context, id string, reason *string) error
+	WeekWorkouts(ctx context.Context, clientID string, coachID string, weekStart time.Time) ([]*models.ProgramWorkout, error)
+}
+
+// PostgresCoachProgramRepository is the PostgreSQL implementation of CoachProgramRepository
+type PostgresCoachProgramRepository struct {
+	db DB
+}
+
+// NewPostgresCoachProgramRepository creates a new PostgreSQL coach program repository
+func NewPostgresCoachProgramRepository(db DB) CoachProgramRepository {
+	return &PostgresCoachProgramRepository{db: db}
+}
+
+// programWorkoutSelect selects scheduled workouts with their status. A linked session
+// counts as modified when exercises were swapped or skipped in it, or its exercises aren't
+// the workout's; unlinked workouts are missed once their day is over for the client.
+const programWorkoutSelect = `
+	SELECT pw.id, pw.program_id, pw.workout_id, w.name, pw.scheduled_on,
+		CASE
+			WHEN pw.skipped_at IS NOT NULL THEN 'skipped'
+			WHEN pw.session_id IS NOT NULL AND (
+				EXISTS (SELECT 1 FROM exercise_swaps x WHERE x.workout_session_id = pw.session_id)
+				OR EXISTS (SELECT 1 FROM exercise_logs l WHERE l.workout_session_id = pw.session_id AND l.skipped_at IS NOT NULL)
+				OR ARRAY(SELECT DISTINCT l.exercise_id FROM exercise_logs l WHERE l.workout_session_id = pw.session_id ORDER BY 1)
+					IS DISTINCT FROM ARRAY(SELECT DISTINCT we.exercise_id FROM workout_exercises we WHERE we.workout_id = pw.workout_id ORDER BY 1)
+			) THEN 'modified'
+			WHEN pw.session_id IS NOT NULL THEN 'completed'
+			WHEN pw.scheduled_on < (NOW() AT TIME ZONE user_timezone(p.client_id))::date THEN 'missed'
+			ELSE 'pending'
+		END,
+		pw.session_id, pw.skipped_at, pw.skip_reason
+	FROM program_workouts pw
+	JOIN coach_programs p ON p.id = pw.program_id
+	JOIN workouts w ON w.id = pw.workout_id
+`
+
+func scanProgramWorkouts(rows pgx.Rows) ([]*models.ProgramWorkout, error) {
+	defer rows.Close()
+
+	workouts := []*models.ProgramWorkout{}
+	for rows.Next() {
+		w := &models.ProgramWorkout{}
+		err := rows.Scan(
+			&w.ID,
+			&w.ProgramID,
+			&w.WorkoutID,
+			&w.WorkoutName,
+			&w.ScheduledOn,
+			&w.Status,
+			&w.SessionID,
+			&w.SkippedAt,
+			&w.SkipReason,
+		)
+		if err != nil {
+			return nil, err
+		}
+		workouts = append(workouts, w)
+	}
+
+	return workouts, rows.Err()
+}
+
+// Create inserts a program with its scheduled workouts in one transaction
+func (r *PostgresCoachProgramRepository) Create(ctx context.Context, program *models.CoachProgram) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO coach_programs (coach_id, client_id, name, notes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at, updated_at
+	`
+	err = tx.QueryRow(ctx, query, program.CoachID, program.ClientID, program.Name, program.Notes).
+		Scan(&program.ID, &program.CreatedAt, &program.UpdatedAt)
+	if err != nil {
+		return translateError(err)
+	}
+
+	for _, w := range program.Workouts {
+		w.ProgramID = program.ID
+		err := tx.QueryRow(ctx,
+			`INSERT INTO program_workouts (program_id, workout_id, scheduled_on) VALUES ($1, $2, $3) RETURNING id`,
+			program.ID, w.WorkoutID, w.ScheduledOn,
+		).Scan(&w.ID)
+		if err != nil {
+			return translateError(err)
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindByID retrieves a program with its scheduled workouts in order
+// Returns pgx.ErrNoRows if the program does not exist.
+func (r *PostgresCoachProgramRepository) FindByID(ctx context.Context, id string) (*models.CoachProgram, error) {
+	query := `
+		SELECT id, coach_id, client_id, name, notes, created_at, updated_at
+		FROM coach_programs
+		WHERE id = $1
+	`
+
+	program := &models.CoachProgram{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&program.ID,
+		&program.CoachID,
+		&program.ClientID,
+		&program.Name,
+		&program.Notes,
+		&program.CreatedAt,
+		&program.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.Query(ctx, programWorkoutSelect+` WHERE pw.program_id = $1 ORDER BY pw.scheduled_on, pw.created_at, pw.id`, id)
+	if err != nil {
+		return nil, err
+	}
+	program.Workouts, err = scanProgramWorkouts(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return program, nil
+}
+
+// ListByClient retrieves a client's programs with their scheduled workouts, newest first;
+// a coachID limits them to that coach's
+func (r *PostgresCoachProgramRepository) ListByClient(ctx context.Context, clientID string, coachID string) ([]*models.CoachProgram, error) {
+	query := `
+		SELECT id, coach_id, client_id, name, notes, created_at, updated_at
+		FROM coach_programs
+		WHERE client_id = $1 AND ($2 = '' OR coach_id::text = $2)
+		ORDER BY created_at DESC, id
+	`
+
+	rows, err := r.db.Query(ctx, query, clientID, coachID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	programs := []*models.CoachProgram{}
+	byID := make(map[string]*models.CoachProgram)
+	for rows.Next() {
+		program := &models.CoachProgram{Workouts: []*models.ProgramWorkout{}}
+		err := rows.Scan(
+			&program.ID,
+			&program.CoachID,
+			&program.ClientID,
+			&program.Name,
+			&program.Notes,
+			&program.CreatedAt,
+			&program.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, program)
+		byID[program.ID] = program
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(programs) == 0 {
+		return programs, nil
+	}
+
+	ids := make([]string, 0, len(programs))
+	for _, p := range programs {
+		ids = append(ids, p.ID)
+	}
+	workoutRows, err := r.db.Query(ctx, programWorkoutSelect+` WHERE pw.program_id = ANY($1) ORDER BY pw.scheduled_on, pw.created_at, pw.id`, ids)
+	if err != nil {
+		return nil, err
+	}
+	workouts, err := scanProgramWorkouts(workoutRows)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range workouts {
+		byID[w.ProgramID].Workouts = append(byID[w.ProgramID].Workouts, w)
+	}
+
+	return programs, nil
+}
+
+// FindWorkout retrieves a scheduled workout with its status
+// Returns pgx.ErrNoRows if it does not exist.
+func (r *PostgresCoachProgramRepository) FindWorkout(ctx context.Context, id string) (*models.ProgramWorkout, error) {
+	rows, err := r.db.Query(ctx, programWorkoutSelect+` WHERE pw.id = $1`, id)
+	if err != nil {
+		return nil, err
+	}
+	workouts, err := scanProgramWorkouts(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(workouts) == 0 {
+		return nil, pgx.ErrNoRows
+	}
+	return workouts[0], nil
+}
+
+// SkipWorkout marks a scheduled workout skipped with an optional reason
+func (r *PostgresCoachProgramRepository) SkipWorkout(ctx context.Context, id string, reason *string) error {
+	query := `UPDATE program_workouts SET skipped_at = NOW(), skip_reason = $2 WHERE id = $1`
+
+	_, err := r.db.Exec(ctx, query, id, reason)
+	return err
+}
+
+// WeekWorkouts retrieves the client's scheduled workouts in the week starting on weekStart,
+// in order; a coachID limits them to that coach's programs
+func (r *PostgresCoachProgramRepository) WeekWorkouts(ctx context.Context, clientID string, coachID string, weekStart time.Time) ([]*models.ProgramWorkout, error) {
+	query := programWorkoutSelect + `
+		WHERE p.client_id = $1 AND ($2 = '' OR p.coach_id::text = $2)
+		  AND pw.scheduled_on >= $3::date AND pw.scheduled_on < $3::date + 7
+		ORDER BY pw.scheduled_on, pw.created_at, pw.id
+	`
+
+	rows, err := r.db.Query(ctx, query, clientID, coachID, weekStart)
+	if err != nil {
+		return nil, err
+	}
+	return scanProgramWorkouts(rows)
+}
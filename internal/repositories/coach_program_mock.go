@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockCoachProgramRepository is a mock implementation for testing
+type MockCoachProgramRepository struct {
+	CreateFunc       func(ctx context.Context, program *models.CoachProgram) error
+	FindByIDFunc     func(ctx context.Context, id string) (*models.CoachProgram, error)
+	ListByClientFunc func(ctx context.Context, clientID string, coachID string) ([]*models.CoachProgram, error)
+	FindWorkoutFunc  func(ctx context.Context, id string) (*models.ProgramWorkout, error)
+	SkipWorkoutFunc  func(ctx context.Context, id string, reason *string) error
+	WeekWorkoutsFunc func(ctx context.Context, clientID string, coachID string, weekStart time.Time) ([]*models.ProgramWorkout, error)
+}
+
+func (m *MockCoachProgramRepository) Create(ctx context.Context, program *models.CoachProgram) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, program)
+	}
+	program.ID = "mock-program-id"
+	return nil
+}
+
+func (m *MockCoachProgramRepository) FindByID(ctx context.Context, id string) (*models.CoachProgram, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockCoachProgramRepository) ListByClient(ctx context.Context, clientID string, coachID string) ([]*models.CoachProgram, error) {
+	if m.ListByClientFunc != nil {
+		return m.ListByClientFunc(ctx, clientID, coachID)
+	}
+	return []*models.CoachProgram{}, nil
+}
+
+func (m *MockCoachProgramRepository) FindWorkout(ctx context.Context, id string) (*models.ProgramWorkout, error) {
+	if m.FindWorkoutFunc != nil {
+		return m.FindWorkoutFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockCoachProgramRepository) SkipWorkout(ctx context.Context, id string, reason *string) error {
+	if m.SkipWorkoutFunc != nil {
+		return m.SkipWorkoutFunc(ctx, id, reason)
+	}
+	return nil
+}
+
+func (m *MockCoachProgramRepository) WeekWorkouts(ctx context.Context, clientID string, coachID string, weekStart time.Time) ([]*models.ProgramWorkout, error) {
+	if m.WeekWorkoutsFunc != nil {
+		return m.WeekWorkoutsFunc(ctx, clientID, coachID, weekStart)
+	}
+	return []*models.ProgramWorkout{}, nil
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// CommentRepository defines the interface for comments on workouts and sessions
+type CommentRepository interface {
+	FindTarget(ctx context.Context, resourceType string, resourceID string) (*models.CommentTarget, error)
+	Create(ctx context.Context, comment *models.Comment) error
+	FindByResource(ctx context.Context, resourceType string, resourceID string) ([]*models.Comment, error)
+	FindByID(ctx context.Context, id string) (*models.Comment, error)
+	Delete(ctx context.Context, id string) error
+}
+
+// PostgresCommentRepository is the PostgreSQL implementation of CommentRepository
+type PostgresCommentRepository struct {
+	db DB
+}
+
+// NewPostgresCommentRepository creates a new PostgreSQL comment repository
+func NewPostgresCommentRepository(db DB) CommentRepository {
+	return &PostgresCommentRepository{db: db}
+}
+
+const commentColumns = `id, resource_type, resource_id, user_id, body, created_at`
+
+// commentTargetQueries look up who owns each kind of commented resource
+var commentTargetQueries = map[string]string{
+	models.CommentResourceWorkout: `SELECT user_id, organization_id FROM workouts WHERE id = $1`,
+	models.CommentResourceSession: `SELECT user_id, NULL::uuid FROM workout_sessions WHERE id = $1`,
+}
+
+// FindTarget retrieves who owns a workout or session
+// Returns pgx.ErrNoRows if it does not exist.
+func (r *PostgresCommentRepository) FindTarget(ctx context.Context, resourceType string, resourceID string) (*models.CommentTarget, error) {
+	query, ok := commentTargetQueries[resourceType]
+	if !ok {
+		return nil, pgx.ErrNoRows
+	}
+
+	target := &models.CommentTarget{}
+	if err := r.db.QueryRow(ctx, query, resourceID).Scan(&target.OwnerID, &target.OrganizationID); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// Create stores a comment and sets its ID and creation time; the resource's owner is
+// notified by trigger
+func (r *PostgresCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	query := `
+		INSERT INTO comments (resource_type, resource_id, user_id, body)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, comment.ResourceType, comment.ResourceID, comment.UserID, comment.Body).Scan(
+		&comment.ID,
+		&comment.CreatedAt,
+	)
+}
+
+// FindByResource retrieves a resource's comments, oldest first
+func (r *PostgresCommentRepository) FindByResource(ctx context.Context, resourceType string, resourceID string) ([]*models.Comment, error) {
+	query := `
+		SELECT ` + commentColumns + `
+		FROM comments
+		WHERE resource_type = $1 AND resource_id = $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	comments := []*models.Comment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+
+	return comments, rows.Err()
+}
+
+// FindByID retrieves a comment
+// Returns pgx.ErrNoRows if it does not exist.
+func (r *PostgresCommentRepository) FindByID(ctx context.Context, id string) (*models.Comment, error) {
+	return scanComment(r.db.QueryRow(ctx, `SELECT `+commentColumns+` FROM comments WHERE id = $1`, id))
+}
+
+// Delete removes a comment
+// Returns pgx.ErrNoRows if it does not exist.
+func (r *PostgresCommentRepository) Delete(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM comments WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+func scanComment(row pgx.Row) (*models.Comment, error) {
+	c := &models.Comment{}
+	err := row.Scan(
+		&c.ID,
+		&c.ResourceType,
+		&c.ResourceID,
+		&c.UserID,
+		&c.Body,
+		&c.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
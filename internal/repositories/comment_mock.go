@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockCommentRepository is a mock implementation for testing
+type MockCommentRepository struct {
+	FindTargetFunc     func(ctx context.Context, resourceType string, resourceID string) (*models.CommentTarget, error)
+	CreateFunc         func(ctx context.Context, comment *models.Comment) error
+	FindByResourceFunc func(ctx context.Context, resourceType string, resourceID string) ([]*models.Comment, error)
+	FindByIDFunc       func(ctx context.Context, id string) (*models.Comment, error)
+	DeleteFunc         func(ctx context.Context, id string) error
+}
+
+func (m *MockCommentRepository) FindTarget(ctx context.Context, resourceType string, resourceID string) (*models.CommentTarget, error) {
+	if m.FindTargetFunc != nil {
+		return m.FindTargetFunc(ctx, resourceType, resourceID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockCommentRepository) Create(ctx context.Context, comment *models.Comment) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, comment)
+	}
+	comment.ID = "comment-123"
+	comment.CreatedAt = time.Now()
+	return nil
+}
+
+func (m *MockCommentRepository) FindByResource(ctx context.Context, resourceType string, resourceID string) ([]*models.Comment, error) {
+	if m.FindByResourceFunc != nil {
+		return m.FindByResourceFunc(ctx, resourceType, resourceID)
+	}
+	return []*models.Comment{}, nil
+}
+
+func (m *MockCommentRepository) FindByID(ctx context.Context, id string) (*models.Comment, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockCommentRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
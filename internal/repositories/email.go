@@ -0,0 +1,124 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// EmailRepository defines the interface for email preferences and scheduled emails
+type EmailRepository interface {
+	GetPreferences(ctx context.Context, userID string) (*models.EmailPreferences, error)
+	SavePreferences(ctx context.Context, prefs *models.EmailPreferences) error
+	QueueWorkoutReminders(ctx context.Context, within time.Duration) (int64, error)
+	QueueWeeklySummaries(ctx context.Context, weekStart time.Time) (int64, error)
+}
+
+// PostgresEmailRepository is the PostgreSQL implementation of EmailRepository
+type PostgresEmailRepository struct {
+	db DB
+}
+
+// NewPostgresEmailRepository creates a new PostgreSQL email repository
+func NewPostgresEmailRepository(db DB) EmailRepository {
+	return &PostgresEmailRepository{db: db}
+}
+
+// GetPreferences retrieves the user's email preferences; users who never saved any have
+// every email turned off
+func (r *PostgresEmailRepository) GetPreferences(ctx context.Context, userID string) (*models.EmailPreferences, error) {
+	query := `SELECT user_id, workout_reminders, weekly_summary, updated_at FROM email_preferences WHERE user_id = $1`
+
+	prefs := &models.EmailPreferences{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&prefs.UserID, &prefs.WorkoutReminders, &prefs.WeeklySummary, &prefs.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.EmailPreferences{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return prefs, nil
+}
+
+// SavePreferences stores the user's email preferences and sets their updated_at
+func (r *PostgresEmailRepository) SavePreferences(ctx context.Context, prefs *models.EmailPreferences) error {
+	query := `
+		INSERT INTO email_preferences (user_id, workout_reminders, weekly_summary)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET workout_reminders = EXCLUDED.workout_reminders, weekly_summary = EXCLUDED.weekly_summary
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, prefs.UserID, prefs.WorkoutReminders, prefs.WeeklySummary).Scan(&prefs.UpdatedAt)
+}
+
+// QueueWorkoutReminders queues a reminder for each planned session starting within the
+// given time whose user turned reminders on, once per session
+func (r *PostgresEmailRepository) QueueWorkoutReminders(ctx context.Context, within time.Duration) (int64, error) {
+	query := `
+		INSERT INTO jobs (user_id, kind, payload, dedupe_key)
+		SELECT s.user_id, 'email',
+		       jsonb_build_object(
+		           'to', u.email,
+		           'template', 'workout_reminder',
+		           'data', jsonb_build_object(
+		               'session_id', s.id,
+		               'name', COALESCE(s.name, w.name, 'Workout'),
+		               'starts_at', s.started_at
+		           )
+		       ),
+		       'workout_reminder:' || s.id
+		FROM workout_sessions s
+		JOIN email_preferences p ON p.user_id = s.user_id AND p.workout_reminders
+		JOIN auth.users u ON u.id = s.user_id
+		LEFT JOIN workouts w ON w.id = s.workout_id
+		WHERE s.status = 'planned'
+		  AND s.started_at > NOW()
+		  AND s.started_at <= NOW() + make_interval(secs => $1)
+		  AND u.email IS NOT NULL
+		ON CONFLICT (dedupe_key) DO NOTHING
+	`
+
+	tag, err := r.db.Exec(ctx, query, within.Seconds())
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// QueueWeeklySummaries queues an email of the report of the week starting at weekStart
+// for each user who turned summaries on, once per user and week
+func (r *PostgresEmailRepository) QueueWeeklySummaries(ctx context.Context, weekStart time.Time) (int64, error) {
+	query := `
+		INSERT INTO jobs (user_id, kind, payload, dedupe_key)
+		SELECT w.user_id, 'email',
+		       jsonb_build_object(
+		           'to', u.email,
+		           'template', 'weekly_summary',
+		           'data', jsonb_build_object(
+		               'week_start', $1::timestamptz,
+		               'sessions', w.sessions,
+		               'minutes', w.minutes,
+		               'volume_kg', w.volume_kg,
+		               'personal_records', w.personal_records,
+		               'streak_weeks', w.streak_weeks
+		           )
+		       ),
+		       'weekly_summary:' || w.user_id || ':' || to_char(w.week_start, 'YYYY-MM-DD')
+		FROM weekly_reports w
+		JOIN email_preferences p ON p.user_id = w.user_id AND p.weekly_summary
+		JOIN auth.users u ON u.id = w.user_id
+		WHERE w.week_start = ($1::timestamptz AT TIME ZONE 'UTC')::date AND u.email IS NOT NULL
+		ON CONFLICT (dedupe_key) DO NOTHING
+	`
+
+	tag, err := r.db.Exec(ctx, query, weekStart)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
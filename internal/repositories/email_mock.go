@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockEmailRepository is a mock implementation for testing
+type MockEmailRepository struct {
+	GetPreferencesFunc        func(ctx context.Context, userID string) (*models.EmailPreferences, error)
+	SavePreferencesFunc       func(ctx context.Context, prefs *models.EmailPreferences) error
+	QueueWorkoutRemindersFunc func(ctx context.Context, within time.Duration) (int64, error)
+	QueueWeeklySummariesFunc  func(ctx context.Context, weekStart time.Time) (int64, error)
+}
+
+func (m *MockEmailRepository) GetPreferences(ctx context.Context, userID string) (*models.EmailPreferences, error) {
+	if m.GetPreferencesFunc != nil {
+		return m.GetPreferencesFunc(ctx, userID)
+	}
+	return &models.EmailPreferences{UserID: userID}, nil
+}
+
+func (m *MockEmailRepository) SavePreferences(ctx context.Context, prefs *models.EmailPreferences) error {
+	if m.SavePreferencesFunc != nil {
+		return m.SavePreferencesFunc(ctx, prefs)
+	}
+	prefs.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MockEmailRepository) QueueWorkoutReminders(ctx context.Context, within time.Duration) (int64, error) {
+	if m.QueueWorkoutRemindersFunc != nil {
+		return m.QueueWorkoutRemindersFunc(ctx, within)
+	}
+	return 0, nil
+}
+
+func (m *MockEmailRepository) QueueWeeklySummaries(ctx context.Context, weekStart time.Time) (int64, error) {
+	if m.QueueWeeklySummariesFunc != nil {
+		return m.QueueWeeklySummariesFunc(ctx, weekStart)
+	}
+	return 0, nil
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 
 	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/juan-cantero/fitapi/internal/models"
 )
 
@@ -13,28 +12,30 @@ type EquipmentRepository interface {
 	Create(ctx context.Context, equipment *models.Equipment) error
 	FindByID(ctx context.Context, id string) (*models.Equipment, error)
 	FindAll(ctx context.Context, userID string) ([]*models.Equipment, error)
+	FindByOrganization(ctx context.Context, orgID string) ([]*models.Equipment, error)
 	Update(ctx context.Context, equipment *models.Equipment) error
 	Delete(ctx context.Context, id string) error
 }
 
 // PostgresEquipmentRepository is the PostgreSQL implementation of EquipmentRepository
 type PostgresEquipmentRepository struct {
-	db *pgxpool.Pool
+	db DB
 }
 
 // NewPostgresEquipmentRepository creates a new PostgreSQL equipment repository
-func NewPostgresEquipmentRepository(db *pgxpool.Pool) EquipmentRepository {
+func NewPostgresEquipmentRepository(db DB) EquipmentRepository {
 	return &PostgresEquipmentRepository{db: db}
 }
 
 // Create inserts a new equipment record into the database
+// Returns ErrDuplicate if its owner already has equipment with the same name.
 func (r *PostgresEquipmentRepository) Create(ctx context.Context, equipment *models.Equipment) error {
 	equipment.ID = uuid.New().String()
 
 	query := `
-		INSERT INTO equipment (id, name, description, user_id, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, NOW(), NOW())
-		RETURNING created_at, updated_at
+		INSERT INTO equipment (id, name, description, user_id, organization_id, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, NOW(), NOW())
+		RETURNING created_at, updated_at, version
 	`
 
 	err := r.db.QueryRow(
@@ -44,15 +45,16 @@ func (r *PostgresEquipmentRepository) Create(ctx context.Context, equipment *mod
 		equipment.Name,
 		equipment.Description,
 		equipment.UserID,
-	).Scan(&equipment.CreatedAt, &equipment.UpdatedAt)
+		equipment.OrganizationID,
+	).Scan(&equipment.CreatedAt, &equipment.UpdatedAt, &equipment.Version)
 
-	return err
+	return translateError(err)
 }
 
 // FindByID retrieves a single equipment by ID
 func (r *PostgresEquipmentRepository) FindByID(ctx context.Context, id string) (*models.Equipment, error) {
 	query := `
-		SELECT id, name, description, user_id, created_at, updated_at
+		SELECT id, name, description, user_id, organization_id, created_at, updated_at, version
 		FROM equipment
 		WHERE id = $1
 	`
@@ -63,8 +65,10 @@ func (r *PostgresEquipmentRepository) FindByID(ctx context.Context, id string) (
 		&equipment.Name,
 		&equipment.Description,
 		&equipment.UserID,
+		&equipment.OrganizationID,
 		&equipment.CreatedAt,
 		&equipment.UpdatedAt,
+		&equipment.Version,
 	)
 
 	if err != nil {
@@ -74,16 +78,32 @@ func (r *PostgresEquipmentRepository) FindByID(ctx context.Context, id string) (
 	return equipment, nil
 }
 
-// FindAll retrieves all equipment for a specific user
+// FindAll retrieves all personal (non-organization) equipment for a specific user
 func (r *PostgresEquipmentRepository) FindAll(ctx context.Context, userID string) ([]*models.Equipment, error) {
 	query := `
-		SELECT id, name, description, user_id, created_at, updated_at
+		SELECT id, name, description, user_id, organization_id, created_at, updated_at, version
 		FROM equipment
-		WHERE user_id = $1
+		WHERE user_id = $1 AND organization_id IS NULL
 		ORDER BY name ASC
 	`
 
-	rows, err := r.db.Query(ctx, query, userID)
+	return r.queryList(ctx, query, userID)
+}
+
+// FindByOrganization retrieves all equipment shared within an organization
+func (r *PostgresEquipmentRepository) FindByOrganization(ctx context.Context, orgID string) ([]*models.Equipment, error) {
+	query := `
+		SELECT id, name, description, user_id, organization_id, created_at, updated_at, version
+		FROM equipment
+		WHERE organization_id = $1
+		ORDER BY name ASC
+	`
+
+	return r.queryList(ctx, query, orgID)
+}
+
+func (r *PostgresEquipmentRepository) queryList(ctx context.Context, query string, args ...any) ([]*models.Equipment, error) {
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -97,8 +117,10 @@ func (r *PostgresEquipmentRepository) FindAll(ctx context.Context, userID string
 			&equipment.Name,
 			&equipment.Description,
 			&equipment.UserID,
+			&equipment.OrganizationID,
 			&equipment.CreatedAt,
 			&equipment.UpdatedAt,
+			&equipment.Version,
 		)
 		if err != nil {
 			return nil, err
@@ -109,13 +131,15 @@ func (r *PostgresEquipmentRepository) FindAll(ctx context.Context, userID string
 	return equipmentList, rows.Err()
 }
 
-// Update updates an existing equipment record
+// Update updates an existing equipment record if it is still at equipment.Version, and sets
+// the new version. Returns pgx.ErrNoRows if the equipment was deleted or updated since, and
+// ErrDuplicate if the new name is taken.
 func (r *PostgresEquipmentRepository) Update(ctx context.Context, equipment *models.Equipment) error {
 	query := `
 		UPDATE equipment
 		SET name = $1, description = $2, updated_at = NOW()
-		WHERE id = $3
-		RETURNING updated_at
+		WHERE id = $3 AND version = $4
+		RETURNING updated_at, version
 	`
 
 	err := r.db.QueryRow(
@@ -124,14 +148,16 @@ func (r *PostgresEquipmentRepository) Update(ctx context.Context, equipment *mod
 		equipment.Name,
 		equipment.Description,
 		equipment.ID,
-	).Scan(&equipment.UpdatedAt)
+		equipment.Version,
+	).Scan(&equipment.UpdatedAt, &equipment.Version)
 
-	return err
+	return translateError(err)
 }
 
 // Delete removes an equipment record from the database
+// Returns ErrReferenced if exercises still use it.
 func (r *PostgresEquipmentRepository) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM equipment WHERE id = $1`
 	_, err := r.db.Exec(ctx, query, id)
-	return err
+	return translateError(err)
 }
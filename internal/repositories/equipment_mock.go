@@ -8,11 +8,12 @@ import (
 
 // MockEquipmentRepository is a mock implementation for testing
 type MockEquipmentRepository struct {
-	CreateFunc  func(ctx context.Context, equipment *models.Equipment) error
-	FindByIDFunc func(ctx context.Context, id string) (*models.Equipment, error)
-	FindAllFunc  func(ctx context.Context, userID string) ([]*models.Equipment, error)
-	UpdateFunc   func(ctx context.Context, equipment *models.Equipment) error
-	DeleteFunc   func(ctx context.Context, id string) error
+	CreateFunc             func(ctx context.Context, equipment *models.Equipment) error
+	FindByIDFunc           func(ctx context.Context, id string) (*models.Equipment, error)
+	FindAllFunc            func(ctx context.Context, userID string) ([]*models.Equipment, error)
+	FindByOrganizationFunc func(ctx context.Context, orgID string) ([]*models.Equipment, error)
+	UpdateFunc             func(ctx context.Context, equipment *models.Equipment) error
+	DeleteFunc             func(ctx context.Context, id string) error
 }
 
 func (m *MockEquipmentRepository) Create(ctx context.Context, equipment *models.Equipment) error {
@@ -36,6 +37,13 @@ func (m *MockEquipmentRepository) FindAll(ctx context.Context, userID string) ([
 	return []*models.Equipment{}, nil
 }
 
+func (m *MockEquipmentRepository) FindByOrganization(ctx context.Context, orgID string) ([]*models.Equipment, error) {
+	if m.FindByOrganizationFunc != nil {
+		return m.FindByOrganizationFunc(ctx, orgID)
+	}
+	return []*models.Equipment{}, nil
+}
+
 func (m *MockEquipmentRepository) Update(ctx context.Context, equipment *models.Equipment) error {
 	if m.UpdateFunc != nil {
 		return m.UpdateFunc(ctx, equipment)
@@ -0,0 +1,42 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+)
+
+// PostgreSQL error codes translated by translateError
+// https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+)
+
+var (
+	// ErrDuplicate is returned when a write would duplicate a row another one already has,
+	// e.g. a second piece of equipment with the same name
+	ErrDuplicate = domainerr.New(domainerr.Conflict, "duplicate")
+	// ErrReferenced is returned when a write would break a reference between rows: deleting
+	// a row others still use, or pointing at a row that doesn't exist
+	ErrReferenced = domainerr.New(domainerr.Unprocessable, "referenced")
+)
+
+// translateError converts constraint violations into ErrDuplicate and ErrReferenced, keeping
+// the driver error in the chain so the violated constraint is still logged
+func translateError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	switch pgErr.Code {
+	case pgUniqueViolation:
+		return fmt.Errorf("%w: %w", ErrDuplicate, err)
+	case pgForeignKeyViolation:
+		return fmt.Errorf("%w: %w", ErrReferenced, err)
+	}
+	return err
+}
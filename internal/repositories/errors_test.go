@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+)
+
+func TestTranslateError(t *testing.T) {
+	unique := &pgconn.PgError{Code: pgUniqueViolation, ConstraintName: "idx_equipment_user_name"}
+	foreignKey := &pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "exercise_equipment_equipment_id_fkey"}
+
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"unique violation", unique, ErrDuplicate},
+		{"wrapped unique violation", fmt.Errorf("insert: %w", unique), ErrDuplicate},
+		{"foreign key violation", foreignKey, ErrReferenced},
+		{"other PostgreSQL error", &pgconn.PgError{Code: "23514"}, nil},
+		{"not found", pgx.ErrNoRows, pgx.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := translateError(tt.err)
+			if tt.want != nil && !errors.Is(got, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, got)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("Expected the original error to stay in the chain, got %v", got)
+			}
+			if tt.want == nil && (errors.Is(got, ErrDuplicate) || errors.Is(got, ErrReferenced)) {
+				t.Errorf("Expected %v to be left alone, got %v", tt.err, got)
+			}
+		})
+	}
+}
+
+func TestTranslateErrorCategories(t *testing.T) {
+	if got := translateError(&pgconn.PgError{Code: pgUniqueViolation}); !errors.Is(got, domainerr.Conflict) {
+		t.Errorf("Expected a unique violation to be a conflict, got %v", got)
+	}
+	if got := translateError(&pgconn.PgError{Code: pgForeignKeyViolation}); !errors.Is(got, domainerr.Unprocessable) {
+		t.Errorf("Expected a foreign key violation to be unprocessable, got %v", got)
+	}
+}
@@ -0,0 +1,276 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ExerciseRepository defines the interface for exercise data access
+type ExerciseRepository interface {
+	FindByID(ctx context.Context, id string) (*models.Exercise, error)
+	FindPublic(ctx context.Context) ([]*models.Exercise, error)
+	FindVisible(ctx context.Context, userID string) ([]*models.Exercise, error)
+	Search(ctx context.Context, userID string, query string, threshold float64, limit int) ([]*models.Exercise, error)
+	Suggest(ctx context.Context, userID string, query string, threshold float64) (string, error)
+	FindDuplicates(ctx context.Context, userID string, threshold float64) ([]*models.ExerciseDuplicate, error)
+	Merge(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error)
+}
+
+// PostgresExerciseRepository is the PostgreSQL implementation of ExerciseRepository
+type PostgresExerciseRepository struct {
+	db DB
+}
+
+// NewPostgresExerciseRepository creates a new PostgreSQL exercise repository
+func NewPostgresExerciseRepository(db DB) ExerciseRepository {
+	return &PostgresExerciseRepository{db: db}
+}
+
+const exerciseColumns = `id, name, COALESCE(description, ''), is_public, user_id, image_url, muscle_group, created_at, updated_at, version`
+
+func scanExercise(row pgx.Row) (*models.Exercise, error) {
+	exercise := &models.Exercise{}
+	err := row.Scan(
+		&exercise.ID,
+		&exercise.Name,
+		&exercise.Description,
+		&exercise.IsPublic,
+		&exercise.UserID,
+		&exercise.ImageURL,
+		&exercise.MuscleGroup,
+		&exercise.CreatedAt,
+		&exercise.UpdatedAt,
+		&exercise.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return exercise, nil
+}
+
+func (r *PostgresExerciseRepository) queryList(ctx context.Context, query string, args ...any) ([]*models.Exercise, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var exercises []*models.Exercise
+	for rows.Next() {
+		exercise, err := scanExercise(rows)
+		if err != nil {
+			return nil, err
+		}
+		exercises = append(exercises, exercise)
+	}
+
+	return exercises, rows.Err()
+}
+
+// FindByID retrieves a single exercise by ID
+func (r *PostgresExerciseRepository) FindByID(ctx context.Context, id string) (*models.Exercise, error) {
+	query := `SELECT ` + exerciseColumns + ` FROM exercises WHERE id = $1`
+	return scanExercise(r.db.QueryRow(ctx, query, id))
+}
+
+// FindPublic retrieves the public exercise library
+func (r *PostgresExerciseRepository) FindPublic(ctx context.Context) ([]*models.Exercise, error) {
+	query := `
+		SELECT ` + exerciseColumns + `
+		FROM exercises
+		WHERE is_public = TRUE
+		ORDER BY name ASC
+	`
+	return r.queryList(ctx, query)
+}
+
+// FindVisible retrieves public exercises plus the user's private ones
+func (r *PostgresExerciseRepository) FindVisible(ctx context.Context, userID string) ([]*models.Exercise, error) {
+	query := `
+		SELECT ` + exerciseColumns + `
+		FROM exercises
+		WHERE is_public = TRUE OR user_id = $1
+		ORDER BY name ASC
+	`
+	return r.queryList(ctx, query, userID)
+}
+
+// visibleOwner returns the owner parameter for visibility filters
+// Anonymous callers pass NULL so only public exercises match
+func visibleOwner(userID string) any {
+	if userID == "" {
+		return nil
+	}
+	return userID
+}
+
+// likeEscaper escapes the wildcards of LIKE patterns, with backslash as the escape character
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLike makes s match itself literally inside a LIKE pattern declaring ESCAPE '\'
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// Search finds visible exercises whose accent-folded name contains the query (% and _
+// included, as literal characters) or has a trigram word similarity of at least threshold.
+// Prefix matches rank first, so the same query serves autocomplete.
+func (r *PostgresExerciseRepository) Search(ctx context.Context, userID string, query string, threshold float64, limit int) ([]*models.Exercise, error) {
+	sql := `
+		SELECT ` + exerciseColumns + `
+		FROM exercises, (SELECT immutable_unaccent(lower($2)) AS q, immutable_unaccent(lower($5)) AS pattern) search
+		WHERE (is_public = TRUE OR user_id = $1)
+		  AND (immutable_unaccent(lower(name)) LIKE '%' || search.pattern || '%' ESCAPE '\'
+		       OR word_similarity(search.q, immutable_unaccent(lower(name))) >= $3)
+		ORDER BY immutable_unaccent(lower(name)) LIKE search.pattern || '%' ESCAPE '\' DESC,
+		         word_similarity(search.q, immutable_unaccent(lower(name))) DESC,
+		         name ASC
+		LIMIT $4
+	`
+	return r.queryList(ctx, sql, visibleOwner(userID), query, threshold, limit, escapeLike(query))
+}
+
+// Suggest returns the visible exercise name most similar to the query
+// Returns pgx.ErrNoRows when no name reaches the threshold.
+func (r *PostgresExerciseRepository) Suggest(ctx context.Context, userID string, query string, threshold float64) (string, error) {
+	sql := `
+		SELECT name
+		FROM exercises, (SELECT immutable_unaccent(lower($2)) AS q) search
+		WHERE (is_public = TRUE OR user_id = $1)
+		  AND similarity(search.q, immutable_unaccent(lower(name))) >= $3
+		ORDER BY similarity(search.q, immutable_unaccent(lower(name))) DESC, name ASC
+		LIMIT 1
+	`
+
+	var name string
+	err := r.db.QueryRow(ctx, sql, visibleOwner(userID), query, threshold).Scan(&name)
+	return name, err
+}
+
+// FindDuplicates pairs the user's private exercises whose accent-folded names have a
+// trigram similarity of at least threshold, most similar pairs first
+func (r *PostgresExerciseRepository) FindDuplicates(ctx context.Context, userID string, threshold float64) ([]*models.ExerciseDuplicate, error) {
+	query := `
+		WITH own AS (
+			SELECT id, name, COALESCE(description, '') AS description, is_public, user_id, image_url,
+			       muscle_group, created_at, updated_at, version, immutable_unaccent(lower(name)) AS normalized
+			FROM exercises
+			WHERE user_id = $1 AND is_public = FALSE
+		)
+		SELECT a.id, a.name, a.description, a.is_public, a.user_id, a.image_url, a.muscle_group, a.created_at, a.updated_at, a.version,
+		       b.id, b.name, b.description, b.is_public, b.user_id, b.image_url, b.muscle_group, b.created_at, b.updated_at, b.version,
+		       similarity(a.normalized, b.normalized)
+		FROM own a
+		JOIN own b ON a.id < b.id
+		WHERE similarity(a.normalized, b.normalized) >= $2
+		ORDER BY similarity(a.normalized, b.normalized) DESC, a.name ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, threshold)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var duplicates []*models.ExerciseDuplicate
+	for rows.Next() {
+		a, b := &models.Exercise{}, &models.Exercise{}
+		duplicate := &models.ExerciseDuplicate{Exercise: a, Candidate: b}
+		err := rows.Scan(
+			&a.ID, &a.Name, &a.Description, &a.IsPublic, &a.UserID, &a.ImageURL, &a.MuscleGroup, &a.CreatedAt, &a.UpdatedAt, &a.Version,
+			&b.ID, &b.Name, &b.Description, &b.IsPublic, &b.UserID, &b.ImageURL, &b.MuscleGroup, &b.CreatedAt, &b.UpdatedAt, &b.Version,
+			&duplicate.Similarity,
+		)
+		if err != nil {
+			return nil, err
+		}
+		duplicates = append(duplicates, duplicate)
+	}
+
+	return duplicates, rows.Err()
+}
+
+// Merge folds the source exercises into the target in a single transaction.
+// Workout templates, session logs and equipment links are re-pointed to the target,
+// the sources are deleted, and personal record flags on the target's logs are
+// recomputed per user in chronological order (a PR is a weight above every earlier one).
+func (r *PostgresExerciseRepository) Merge(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	// Recomputed records are not new ones for webhook subscribers
+	if _, err := tx.Exec(ctx, suppressWebhooks); err != nil {
+		return nil, err
+	}
+
+	result := &models.ExerciseMergeResult{MergedCount: len(sourceIDs)}
+
+	tag, err := tx.Exec(ctx, `UPDATE workout_exercises SET exercise_id = $1 WHERE exercise_id = ANY($2)`, targetID, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	result.WorkoutExercisesMoved = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, `UPDATE exercise_logs SET exercise_id = $1 WHERE exercise_id = ANY($2)`, targetID, sourceIDs)
+	if err != nil {
+		return nil, err
+	}
+	result.ExerciseLogsMoved = tag.RowsAffected()
+
+	equipmentQuery := `
+		INSERT INTO exercise_equipment (exercise_id, equipment_id)
+		SELECT $1, equipment_id FROM exercise_equipment WHERE exercise_id = ANY($2)
+		ON CONFLICT DO NOTHING
+	`
+	if _, err := tx.Exec(ctx, equipmentQuery, targetID, sourceIDs); err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM exercises WHERE id = ANY($1)`, sourceIDs); err != nil {
+		return nil, err
+	}
+
+	recordsQuery := `
+		WITH history AS (
+			SELECT l.id,
+			       l.weight_kg,
+			       MAX(l.weight_kg) OVER w AS best_weight,
+			       MAX(l.reps_completed) OVER w AS best_reps,
+			       MAX(l.duration_seconds) OVER w AS best_duration
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE l.exercise_id = $1
+			WINDOW w AS (
+				PARTITION BY s.user_id
+				ORDER BY l.created_at, l.id
+				ROWS BETWEEN UNBOUNDED PRECEDING AND 1 PRECEDING
+			)
+		)
+		UPDATE exercise_logs l
+		SET is_personal_record = h.weight_kg IS NOT NULL AND (h.best_weight IS NULL OR h.weight_kg > h.best_weight),
+		    previous_best_weight = h.best_weight,
+		    previous_best_reps = h.best_reps,
+		    previous_best_duration = h.best_duration
+		FROM history h
+		WHERE l.id = h.id
+	`
+	tag, err = tx.Exec(ctx, recordsQuery, targetID)
+	if err != nil {
+		return nil, err
+	}
+	result.PersonalRecordsUpdated = tag.RowsAffected()
+
+	exercise, err := scanExercise(tx.QueryRow(ctx, `SELECT `+exerciseColumns+` FROM exercises WHERE id = $1`, targetID))
+	if err != nil {
+		return nil, err
+	}
+	result.Exercise = exercise
+
+	return result, tx.Commit(ctx)
+}
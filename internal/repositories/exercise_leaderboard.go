@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ExerciseLeaderboardRepository defines the interface for ranking users on an exercise
+type ExerciseLeaderboardRepository interface {
+	Leaderboard(ctx context.Context, exerciseID string, userID string, metric string, relative bool, limit int) ([]*models.ExerciseLeaderboardEntry, error)
+}
+
+// PostgresExerciseLeaderboardRepository is the PostgreSQL implementation of
+// ExerciseLeaderboardRepository
+type PostgresExerciseLeaderboardRepository struct {
+	db DB
+}
+
+// NewPostgresExerciseLeaderboardRepository creates a new PostgreSQL exercise leaderboard repository
+func NewPostgresExerciseLeaderboardRepository(db DB) ExerciseLeaderboardRepository {
+	return &PostgresExerciseLeaderboardRepository{db: db}
+}
+
+// Leaderboard ranks the user and the users they follow by their best set of the exercise
+// in completed sessions, by Epley estimated 1RM or by weight. Followed users whose
+// profile the user can no longer see, or who hide their records on their public profile,
+// are left out. Relative rankings divide by each user's latest weigh-in and leave out
+// users who never weighed in.
+func (r *PostgresExerciseLeaderboardRepository) Leaderboard(ctx context.Context, exerciseID string, userID string, metric string, relative bool, limit int) ([]*models.ExerciseLeaderboardEntry, error) {
+	query := `
+		WITH people AS (
+			SELECT $2::uuid AS user_id
+			UNION
+			SELECT f.followee_id
+			FROM user_follows f
+			WHERE f.follower_id = $2 AND f.status = 'accepted'
+			  AND can_view_profile($2, f.followee_id)
+			  AND NOT EXISTS (SELECT 1 FROM public_profiles p WHERE p.user_id = f.followee_id AND NOT p.show_records)
+		), best AS (
+			SELECT DISTINCT ON (s.user_id)
+			       s.user_id, l.weight_kg::float8 AS weight_kg, l.reps_completed, s.started_at,
+			       CASE WHEN $3 = 'e1rm' THEN l.weight_kg * (1 + l.reps_completed / 30.0) ELSE l.weight_kg END::float8 AS value
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE s.user_id IN (SELECT user_id FROM people) AND s.status = 'completed'
+			  AND l.exercise_id = $1 AND l.skipped_at IS NULL AND l.weight_kg > 0 AND l.reps_completed > 0
+			ORDER BY s.user_id, value DESC, s.started_at ASC
+		), scored AS (
+			SELECT b.user_id, b.weight_kg, b.reps_completed, b.started_at,
+			       CASE WHEN $4 THEN b.value / w.weight_kg ELSE b.value END AS score
+			FROM best b
+			LEFT JOIN LATERAL (
+				SELECT m.weight_kg::float8 AS weight_kg
+				FROM body_measurements m
+				WHERE m.user_id = b.user_id AND m.weight_kg IS NOT NULL
+				ORDER BY m.measured_at DESC
+				LIMIT 1
+			) w ON TRUE
+			WHERE NOT $4 OR w.weight_kg IS NOT NULL
+		)
+		SELECT RANK() OVER (ORDER BY sc.score DESC), sc.user_id, p.username, sc.score, sc.weight_kg, sc.reps_completed, sc.started_at
+		FROM scored sc
+		LEFT JOIN public_profiles p ON p.user_id = sc.user_id
+		ORDER BY 1, sc.started_at
+		LIMIT $5
+	`
+
+	rows, err := r.db.Query(ctx, query, exerciseID, userID, metric, relative, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	entries := []*models.ExerciseLeaderboardEntry{}
+	for rows.Next() {
+		e := &models.ExerciseLeaderboardEntry{}
+		err := rows.Scan(
+			&e.Rank,
+			&e.UserID,
+			&e.Username,
+			&e.Value,
+			&e.WeightKg,
+			&e.Reps,
+			&e.AchievedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, e)
+	}
+
+	return entries, rows.Err()
+}
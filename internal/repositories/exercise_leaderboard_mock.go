@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockExerciseLeaderboardRepository is a mock implementation for testing
+type MockExerciseLeaderboardRepository struct {
+	LeaderboardFunc func(ctx context.Context, exerciseID string, userID string, metric string, relative bool, limit int) ([]*models.ExerciseLeaderboardEntry, error)
+}
+
+func (m *MockExerciseLeaderboardRepository) Leaderboard(ctx context.Context, exerciseID string, userID string, metric string, relative bool, limit int) ([]*models.ExerciseLeaderboardEntry, error) {
+	if m.LeaderboardFunc != nil {
+		return m.LeaderboardFunc(ctx, exerciseID, userID, metric, relative, limit)
+	}
+	return []*models.ExerciseLeaderboardEntry{}, nil
+}
@@ -0,0 +1,68 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockExerciseRepository is a mock implementation for testing
+type MockExerciseRepository struct {
+	FindByIDFunc       func(ctx context.Context, id string) (*models.Exercise, error)
+	FindPublicFunc     func(ctx context.Context) ([]*models.Exercise, error)
+	FindVisibleFunc    func(ctx context.Context, userID string) ([]*models.Exercise, error)
+	SearchFunc         func(ctx context.Context, userID string, query string, threshold float64, limit int) ([]*models.Exercise, error)
+	SuggestFunc        func(ctx context.Context, userID string, query string, threshold float64) (string, error)
+	FindDuplicatesFunc func(ctx context.Context, userID string, threshold float64) ([]*models.ExerciseDuplicate, error)
+	MergeFunc          func(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error)
+}
+
+func (m *MockExerciseRepository) FindByID(ctx context.Context, id string) (*models.Exercise, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockExerciseRepository) FindPublic(ctx context.Context) ([]*models.Exercise, error) {
+	if m.FindPublicFunc != nil {
+		return m.FindPublicFunc(ctx)
+	}
+	return []*models.Exercise{}, nil
+}
+
+func (m *MockExerciseRepository) FindVisible(ctx context.Context, userID string) ([]*models.Exercise, error) {
+	if m.FindVisibleFunc != nil {
+		return m.FindVisibleFunc(ctx, userID)
+	}
+	return []*models.Exercise{}, nil
+}
+
+func (m *MockExerciseRepository) Search(ctx context.Context, userID string, query string, threshold float64, limit int) ([]*models.Exercise, error) {
+	if m.SearchFunc != nil {
+		return m.SearchFunc(ctx, userID, query, threshold, limit)
+	}
+	return []*models.Exercise{}, nil
+}
+
+func (m *MockExerciseRepository) Suggest(ctx context.Context, userID string, query string, threshold float64) (string, error) {
+	if m.SuggestFunc != nil {
+		return m.SuggestFunc(ctx, userID, query, threshold)
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (m *MockExerciseRepository) FindDuplicates(ctx context.Context, userID string, threshold float64) ([]*models.ExerciseDuplicate, error) {
+	if m.FindDuplicatesFunc != nil {
+		return m.FindDuplicatesFunc(ctx, userID, threshold)
+	}
+	return []*models.ExerciseDuplicate{}, nil
+}
+
+func (m *MockExerciseRepository) Merge(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error) {
+	if m.MergeFunc != nil {
+		return m.MergeFunc(ctx, targetID, sourceIDs)
+	}
+	return &models.ExerciseMergeResult{MergedCount: len(sourceIDs)}, nil
+}
@@ -0,0 +1,285 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ExerciseSwapRepository defines the interface for swapping and skipping planned exercises during a session
+type ExerciseSwapRepository interface {
+	FindCandidate(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error)
+	MissingEquipment(ctx context.Context, userID string, exerciseID string) ([]string, error)
+	EstimatedMaxes(ctx context.Context, userID string, exerciseIDs []string) (map[string]float64, error)
+	Swap(ctx context.Context, swap *models.ExerciseSwap) error
+	FindBySession(ctx context.Context, sessionID string) ([]*models.ExerciseSwap, error)
+	Skip(ctx context.Context, skipped *models.SkippedExercise) error
+	WeeklySkippedVolume(ctx context.Context, userID string, since time.Time) ([]*models.WeeklySkippedVolume, error)
+}
+
+// PostgresExerciseSwapRepository is the PostgreSQL implementation of ExerciseSwapRepository
+type PostgresExerciseSwapRepository struct {
+	db DB
+}
+
+// NewPostgresExerciseSwapRepository creates a new PostgreSQL exercise swap repository
+func NewPostgresExerciseSwapRepository(db DB) ExerciseSwapRepository {
+	return &PostgresExerciseSwapRepository{db: db}
+}
+
+// FindCandidate retrieves a session's exercise log with its prescription
+// Returns pgx.ErrNoRows if the log does not belong to the session.
+func (r *PostgresExerciseSwapRepository) FindCandidate(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+	query := `
+		SELECT s.id, s.user_id, s.status, l.id, l.exercise_id, COALESCE(l.sets_completed, 0),
+		       l.sets_planned, COALESCE(l.reps_planned, we.reps),
+		       COALESCE(l.weight_planned_kg, we.weight_kg),
+		       COALESCE(l.intensity_percentage, we.intensity_percentage)
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		LEFT JOIN workout_exercises we ON we.id = l.workout_exercise_id
+		WHERE l.id = $1 AND s.id = $2
+	`
+
+	c := &models.SwapCandidate{}
+	err := r.db.QueryRow(ctx, query, exerciseLogID, sessionID).Scan(
+		&c.SessionID,
+		&c.UserID,
+		&c.SessionStatus,
+		&c.ExerciseLogID,
+		&c.ExerciseID,
+		&c.SetsCompleted,
+		&c.SetsPlanned,
+		&c.RepsPlanned,
+		&c.WeightPlannedKg,
+		&c.IntensityPercentage,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// MissingEquipment returns the names of equipment the exercise needs that the user does not
+// have, either as their own or shared in one of their organizations. Equipment is compared
+// by name since public exercises link to their author's equipment. Users who have not
+// registered any equipment are assumed to have everything, so nothing is reported missing.
+func (r *PostgresExerciseSwapRepository) MissingEquipment(ctx context.Context, userID string, exerciseID string) ([]string, error) {
+	query := `
+		WITH available AS (
+			SELECT lower(e.name) AS name
+			FROM equipment e
+			WHERE e.user_id = $1
+			   OR e.organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		)
+		SELECT DISTINCT eq.name
+		FROM exercise_equipment ee
+		JOIN equipment eq ON eq.id = ee.equipment_id
+		WHERE ee.exercise_id = $2
+		  AND EXISTS (SELECT 1 FROM available)
+		  AND lower(eq.name) NOT IN (SELECT name FROM available)
+		ORDER BY eq.name
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, exerciseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var missing []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		missing = append(missing, name)
+	}
+
+	return missing, rows.Err()
+}
+
+// EstimatedMaxes returns the user's best estimated one-rep max (Epley) per exercise,
+// from logged sets with both weight and reps. Exercises without history are absent.
+func (r *PostgresExerciseSwapRepository) EstimatedMaxes(ctx context.Context, userID string, exerciseIDs []string) (map[string]float64, error) {
+	query := `
+		SELECT l.exercise_id, MAX(l.weight_kg * (1 + l.reps_completed / 30.0))
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		WHERE s.user_id = $1
+		  AND l.exercise_id = ANY($2::uuid[])
+		  AND l.weight_kg > 0 AND l.reps_completed > 0
+		GROUP BY l.exercise_id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, exerciseIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	maxes := make(map[string]float64)
+	for rows.Next() {
+		var exerciseID string
+		var estimate float64
+		if err := rows.Scan(&exerciseID, &estimate); err != nil {
+			return nil, err
+		}
+		maxes[exerciseID] = estimate
+	}
+
+	return maxes, rows.Err()
+}
+
+// Swap points the exercise log at the substitute with the rescaled planned load and
+// records the swap, in one transaction
+func (r *PostgresExerciseSwapRepository) Swap(ctx context.Context, swap *models.ExerciseSwap) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(
+		ctx,
+		`UPDATE exercise_logs SET exercise_id = $2, weight_planned_kg = $3 WHERE id = $1`,
+		swap.ExerciseLogID,
+		swap.ToExerciseID,
+		swap.ToWeightKg,
+	)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	query := `
+		INSERT INTO exercise_swaps (
+			workout_session_id, exercise_log_id, user_id, from_exercise_id, to_exercise_id,
+			reason, strength_ratio, from_weight_kg, to_weight_kg
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING id, created_at
+	`
+	err = tx.QueryRow(
+		ctx,
+		query,
+		swap.SessionID,
+		swap.ExerciseLogID,
+		swap.UserID,
+		swap.FromExerciseID,
+		swap.ToExerciseID,
+		swap.Reason,
+		swap.StrengthRatio,
+		swap.FromWeightKg,
+		swap.ToWeightKg,
+	).Scan(&swap.ID, &swap.CreatedAt)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindBySession retrieves a session's swaps, oldest first
+func (r *PostgresExerciseSwapRepository) FindBySession(ctx context.Context, sessionID string) ([]*models.ExerciseSwap, error) {
+	query := `
+		SELECT id, workout_session_id, exercise_log_id, user_id, from_exercise_id, to_exercise_id,
+		       reason, strength_ratio, from_weight_kg, to_weight_kg, created_at
+		FROM exercise_swaps
+		WHERE workout_session_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	swaps := []*models.ExerciseSwap{}
+	for rows.Next() {
+		s := &models.ExerciseSwap{}
+		err := rows.Scan(
+			&s.ID,
+			&s.SessionID,
+			&s.ExerciseLogID,
+			&s.UserID,
+			&s.FromExerciseID,
+			&s.ToExerciseID,
+			&s.Reason,
+			&s.StrengthRatio,
+			&s.FromWeightKg,
+			&s.ToWeightKg,
+			&s.CreatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		swaps = append(swaps, s)
+	}
+
+	return swaps, rows.Err()
+}
+
+// Skip marks a session's exercise log as skipped
+// Returns pgx.ErrNoRows if the log does not belong to the session.
+func (r *PostgresExerciseSwapRepository) Skip(ctx context.Context, skipped *models.SkippedExercise) error {
+	query := `
+		UPDATE exercise_logs
+		SET skipped_at = $3, skip_reason = $4
+		WHERE id = $1 AND workout_session_id = $2
+		RETURNING exercise_id
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		skipped.ExerciseLogID,
+		skipped.SessionID,
+		skipped.SkippedAt,
+		skipped.Reason,
+	).Scan(&skipped.ExerciseID)
+}
+
+// WeeklySkippedVolume totals the planned sets and volume the user skipped per exercise and
+// ISO week for sessions started since the given date, both in the user's timezone, newest
+// week first
+func (r *PostgresExerciseSwapRepository) WeeklySkippedVolume(ctx context.Context, userID string, since time.Time) ([]*models.WeeklySkippedVolume, error) {
+	query := `
+		SELECT to_char(date_trunc('week', s.started_at AT TIME ZONE z.tz), 'YYYY-MM-DD') AS week_start,
+		       e.id, e.name, COUNT(*),
+		       SUM(COALESCE(l.sets_planned, we.sets, 1)),
+		       SUM(COALESCE(l.sets_planned, we.sets, 1) * COALESCE(l.reps_planned, we.reps, 0)
+		           * COALESCE(l.weight_planned_kg, we.weight_kg, 0))
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		JOIN exercises e ON e.id = l.exercise_id
+		LEFT JOIN workout_exercises we ON we.id = l.workout_exercise_id
+		CROSS JOIN (SELECT user_timezone($1) AS tz) z
+		WHERE s.user_id = $1 AND l.skipped_at IS NOT NULL
+		  AND s.started_at >= ($2::date::timestamp AT TIME ZONE z.tz)
+		GROUP BY week_start, e.id, e.name
+		ORDER BY week_start DESC, 6 DESC, e.name ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	volumes := []*models.WeeklySkippedVolume{}
+	for rows.Next() {
+		v := &models.WeeklySkippedVolume{}
+		if err := rows.Scan(&v.WeekStart, &v.ExerciseID, &v.ExerciseName, &v.Skipped, &v.Sets, &v.VolumeKg); err != nil {
+			return nil, err
+		}
+		volumes = append(volumes, v)
+	}
+
+	return volumes, rows.Err()
+}
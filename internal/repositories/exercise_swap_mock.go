@@ -0,0 +1,70 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockExerciseSwapRepository is a mock implementation for testing
+type MockExerciseSwapRepository struct {
+	FindCandidateFunc       func(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error)
+	MissingEquipmentFunc    func(ctx context.Context, userID string, exerciseID string) ([]string, error)
+	EstimatedMaxesFunc      func(ctx context.Context, userID string, exerciseIDs []string) (map[string]float64, error)
+	SwapFunc                func(ctx context.Context, swap *models.ExerciseSwap) error
+	FindBySessionFunc       func(ctx context.Context, sessionID string) ([]*models.ExerciseSwap, error)
+	SkipFunc                func(ctx context.Context, skipped *models.SkippedExercise) error
+	WeeklySkippedVolumeFunc func(ctx context.Context, userID string, since time.Time) ([]*models.WeeklySkippedVolume, error)
+}
+
+func (m *MockExerciseSwapRepository) FindCandidate(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+	if m.FindCandidateFunc != nil {
+		return m.FindCandidateFunc(ctx, sessionID, exerciseLogID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockExerciseSwapRepository) MissingEquipment(ctx context.Context, userID string, exerciseID string) ([]string, error) {
+	if m.MissingEquipmentFunc != nil {
+		return m.MissingEquipmentFunc(ctx, userID, exerciseID)
+	}
+	return nil, nil
+}
+
+func (m *MockExerciseSwapRepository) EstimatedMaxes(ctx context.Context, userID string, exerciseIDs []string) (map[string]float64, error) {
+	if m.EstimatedMaxesFunc != nil {
+		return m.EstimatedMaxesFunc(ctx, userID, exerciseIDs)
+	}
+	return map[string]float64{}, nil
+}
+
+func (m *MockExerciseSwapRepository) Swap(ctx context.Context, swap *models.ExerciseSwap) error {
+	if m.SwapFunc != nil {
+		return m.SwapFunc(ctx, swap)
+	}
+	swap.ID = "mock-swap-id"
+	return nil
+}
+
+func (m *MockExerciseSwapRepository) FindBySession(ctx context.Context, sessionID string) ([]*models.ExerciseSwap, error) {
+	if m.FindBySessionFunc != nil {
+		return m.FindBySessionFunc(ctx, sessionID)
+	}
+	return []*models.ExerciseSwap{}, nil
+}
+
+func (m *MockExerciseSwapRepository) Skip(ctx context.Context, skipped *models.SkippedExercise) error {
+	if m.SkipFunc != nil {
+		return m.SkipFunc(ctx, skipped)
+	}
+	return nil
+}
+
+func (m *MockExerciseSwapRepository) WeeklySkippedVolume(ctx context.Context, userID string, since time.Time) ([]*models.WeeklySkippedVolume, error) {
+	if m.WeeklySkippedVolumeFunc != nil {
+		return m.WeeklySkippedVolumeFunc(ctx, userID, since)
+	}
+	return []*models.WeeklySkippedVolume{}, nil
+}
@@ -0,0 +1,252 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ExportRepository defines the interface for bulk reads used by data exports
+type ExportRepository interface {
+	StreamExerciseLogs(ctx context.Context, userID string, fn func(*models.ExerciseLogExport) error) error
+	BuildAccountArchive(ctx context.Context, userID string) ([]byte, error)
+	CreateAccountExport(ctx context.Context, export *models.AccountExport) error
+	FindAccountExport(ctx context.Context, id string) (*models.AccountExport, error)
+	FindPendingAccountExport(ctx context.Context, userID string, since time.Time) (*models.AccountExport, error)
+	FindAccountArchive(ctx context.Context, id string) ([]byte, error)
+	CompleteAccountExport(ctx context.Context, id string, archive []byte, expiresAt time.Time) error
+	FailAccountExport(ctx context.Context, id string, reason string) error
+}
+
+// PostgresExportRepository is the PostgreSQL implementation of ExportRepository
+type PostgresExportRepository struct {
+	db DB
+}
+
+// NewPostgresExportRepository creates a new PostgreSQL export repository
+func NewPostgresExportRepository(db DB) ExportRepository {
+	return &PostgresExportRepository{db: db}
+}
+
+// StreamExerciseLogs calls fn for each of the user's exercise logs in chronological order
+// Rows are read one at a time so exports never hold a user's full history in memory.
+// Iteration stops at the first error returned by fn.
+func (r *PostgresExportRepository) StreamExerciseLogs(ctx context.Context, userID string, fn func(*models.ExerciseLogExport) error) error {
+	query := `
+		SELECT s.id, s.name, s.started_at, s.status,
+		       e.name, l.order_index,
+		       l.sets_completed, l.sets_planned, l.reps_completed, l.reps_planned,
+		       l.weight_kg, l.duration_seconds, l.distance_meters, l.rpe,
+		       COALESCE(l.is_personal_record, FALSE), l.notes
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		JOIN exercises e ON e.id = l.exercise_id
+		WHERE s.user_id = $1
+		ORDER BY s.started_at ASC, l.order_index ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		log := &models.ExerciseLogExport{}
+		err := rows.Scan(
+			&log.SessionID,
+			&log.SessionName,
+			&log.SessionDate,
+			&log.SessionStatus,
+			&log.ExerciseName,
+			&log.OrderIndex,
+			&log.SetsCompleted,
+			&log.SetsPlanned,
+			&log.RepsCompleted,
+			&log.RepsPlanned,
+			&log.WeightKg,
+			&log.DurationSec,
+			&log.DistanceM,
+			&log.RPE,
+			&log.IsPR,
+			&log.Notes,
+		)
+		if err != nil {
+			return err
+		}
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// accountArchiveQuery assembles every user-owned table into one JSON document
+// Child tables without a user_id are scoped through their parent row.
+const accountArchiveQuery = `
+	SELECT jsonb_build_object(
+		'user_id', $1::uuid,
+		'generated_at', NOW(),
+		'equipment', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]'::jsonb)
+			FROM equipment t WHERE t.user_id = $1),
+		'exercises', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]'::jsonb)
+			FROM exercises t WHERE t.user_id = $1),
+		'exercise_equipment', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t)), '[]'::jsonb)
+			FROM exercise_equipment t JOIN exercises e ON e.id = t.exercise_id
+			WHERE e.user_id = $1),
+		'workouts', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]'::jsonb)
+			FROM workouts t WHERE t.user_id = $1),
+		'workout_exercises', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.workout_id, t.order_index), '[]'::jsonb)
+			FROM workout_exercises t JOIN workouts w ON w.id = t.workout_id
+			WHERE w.user_id = $1),
+		'workout_sessions', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.started_at), '[]'::jsonb)
+			FROM workout_sessions t WHERE t.user_id = $1),
+		'exercise_logs', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY s.started_at, t.order_index), '[]'::jsonb)
+			FROM exercise_logs t JOIN workout_sessions s ON s.id = t.workout_session_id
+			WHERE s.user_id = $1),
+		'session_laps', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY s.started_at, t.lap_index), '[]'::jsonb)
+			FROM session_laps t JOIN workout_sessions s ON s.id = t.workout_session_id
+			WHERE s.user_id = $1),
+		'exercise_swaps', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]'::jsonb)
+			FROM exercise_swaps t WHERE t.user_id = $1),
+		'session_media', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.workout_session_id, t.position), '[]'::jsonb)
+			FROM session_media t WHERE t.user_id = $1),
+		'body_measurements', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.measured_at), '[]'::jsonb)
+			FROM body_measurements t WHERE t.user_id = $1),
+		'training_maxes', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.name), '[]'::jsonb)
+			FROM training_maxes t WHERE t.user_id = $1),
+		'challenge_participations', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.joined_at), '[]'::jsonb)
+			FROM challenge_participants t WHERE t.user_id = $1),
+		'challenge_completions', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.day), '[]'::jsonb)
+			FROM challenge_completions t WHERE t.user_id = $1),
+		'coach_relationships', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]'::jsonb)
+			FROM coach_clients t WHERE t.coach_id = $1 OR t.client_id = $1),
+		'integration_connections', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) - 'access_token' - 'refresh_token' ORDER BY t.created_at), '[]'::jsonb)
+			FROM integration_connections t WHERE t.user_id = $1),
+		'organization_memberships', (
+			SELECT COALESCE(jsonb_agg(to_jsonb(t) ORDER BY t.created_at), '[]'::jsonb)
+			FROM organization_members t WHERE t.user_id = $1)
+	)
+`
+
+// BuildAccountArchive assembles the user's data into a single JSON document
+// It runs from a background job, whose context bounds it in place of the pool's statement
+// timeout, which is sized for requests and would cancel the archive of a long history.
+func (r *PostgresExportRepository) BuildAccountArchive(ctx context.Context, userID string) ([]byte, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SET LOCAL statement_timeout = 0`); err != nil {
+		return nil, err
+	}
+
+	var archive []byte
+	if err := tx.QueryRow(ctx, accountArchiveQuery, userID).Scan(&archive); err != nil {
+		return nil, err
+	}
+	return archive, tx.Commit(ctx)
+}
+
+const accountExportColumns = `id, user_id, status, error, size_bytes, created_at, completed_at, expires_at`
+
+func scanAccountExport(row pgx.Row) (*models.AccountExport, error) {
+	export := &models.AccountExport{}
+	err := row.Scan(
+		&export.ID,
+		&export.UserID,
+		&export.Status,
+		&export.Error,
+		&export.SizeBytes,
+		&export.CreatedAt,
+		&export.CompletedAt,
+		&export.ExpiresAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return export, nil
+}
+
+// CreateAccountExport inserts a pending export
+func (r *PostgresExportRepository) CreateAccountExport(ctx context.Context, export *models.AccountExport) error {
+	query := `
+		INSERT INTO account_exports (user_id, status)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, export.UserID, export.Status).Scan(&export.ID, &export.CreatedAt)
+}
+
+// FindAccountExport retrieves an export's status without its archive
+func (r *PostgresExportRepository) FindAccountExport(ctx context.Context, id string) (*models.AccountExport, error) {
+	query := `SELECT ` + accountExportColumns + ` FROM account_exports WHERE id = $1`
+	return scanAccountExport(r.db.QueryRow(ctx, query, id))
+}
+
+// FindPendingAccountExport retrieves the user's newest pending export created after since
+func (r *PostgresExportRepository) FindPendingAccountExport(ctx context.Context, userID string, since time.Time) (*models.AccountExport, error) {
+	query := `
+		SELECT ` + accountExportColumns + `
+		FROM account_exports
+		WHERE user_id = $1 AND status = 'pending' AND created_at > $2
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+	return scanAccountExport(r.db.QueryRow(ctx, query, userID, since))
+}
+
+// FindAccountArchive retrieves the generated archive of a ready export
+func (r *PostgresExportRepository) FindAccountArchive(ctx context.Context, id string) ([]byte, error) {
+	query := `SELECT archive FROM account_exports WHERE id = $1 AND status = 'ready'`
+
+	var archive []byte
+	err := r.db.QueryRow(ctx, query, id).Scan(&archive)
+	return archive, err
+}
+
+// CompleteAccountExport stores the archive and marks the export ready
+func (r *PostgresExportRepository) CompleteAccountExport(ctx context.Context, id string, archive []byte, expiresAt time.Time) error {
+	query := `
+		UPDATE account_exports
+		SET status = 'ready', archive = $2, size_bytes = $3, completed_at = NOW(), expires_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, archive, len(archive), expiresAt)
+	return err
+}
+
+// FailAccountExport marks the export failed with a reason
+func (r *PostgresExportRepository) FailAccountExport(ctx context.Context, id string, reason string) error {
+	query := `
+		UPDATE account_exports
+		SET status = 'failed', error = $2, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, reason)
+	return err
+}
@@ -0,0 +1,85 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockExportRepository is a mock implementation for testing
+type MockExportRepository struct {
+	ExerciseLogs []*models.ExerciseLogExport
+
+	StreamExerciseLogsFunc       func(ctx context.Context, userID string, fn func(*models.ExerciseLogExport) error) error
+	BuildAccountArchiveFunc      func(ctx context.Context, userID string) ([]byte, error)
+	CreateAccountExportFunc      func(ctx context.Context, export *models.AccountExport) error
+	FindAccountExportFunc        func(ctx context.Context, id string) (*models.AccountExport, error)
+	FindPendingAccountExportFunc func(ctx context.Context, userID string, since time.Time) (*models.AccountExport, error)
+	FindAccountArchiveFunc       func(ctx context.Context, id string) ([]byte, error)
+	CompleteAccountExportFunc    func(ctx context.Context, id string, archive []byte, expiresAt time.Time) error
+	FailAccountExportFunc        func(ctx context.Context, id string, reason string) error
+}
+
+func (m *MockExportRepository) StreamExerciseLogs(ctx context.Context, userID string, fn func(*models.ExerciseLogExport) error) error {
+	if m.StreamExerciseLogsFunc != nil {
+		return m.StreamExerciseLogsFunc(ctx, userID, fn)
+	}
+	for _, log := range m.ExerciseLogs {
+		if err := fn(log); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *MockExportRepository) BuildAccountArchive(ctx context.Context, userID string) ([]byte, error) {
+	if m.BuildAccountArchiveFunc != nil {
+		return m.BuildAccountArchiveFunc(ctx, userID)
+	}
+	return []byte(`{}`), nil
+}
+
+func (m *MockExportRepository) CreateAccountExport(ctx context.Context, export *models.AccountExport) error {
+	if m.CreateAccountExportFunc != nil {
+		return m.CreateAccountExportFunc(ctx, export)
+	}
+	export.ID = "mock-export-id"
+	return nil
+}
+
+func (m *MockExportRepository) FindAccountExport(ctx context.Context, id string) (*models.AccountExport, error) {
+	if m.FindAccountExportFunc != nil {
+		return m.FindAccountExportFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockExportRepository) FindPendingAccountExport(ctx context.Context, userID string, since time.Time) (*models.AccountExport, error) {
+	if m.FindPendingAccountExportFunc != nil {
+		return m.FindPendingAccountExportFunc(ctx, userID, since)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockExportRepository) FindAccountArchive(ctx context.Context, id string) ([]byte, error) {
+	if m.FindAccountArchiveFunc != nil {
+		return m.FindAccountArchiveFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockExportRepository) CompleteAccountExport(ctx context.Context, id string, archive []byte, expiresAt time.Time) error {
+	if m.CompleteAccountExportFunc != nil {
+		return m.CompleteAccountExportFunc(ctx, id, archive, expiresAt)
+	}
+	return nil
+}
+
+func (m *MockExportRepository) FailAccountExport(ctx context.Context, id string, reason string) error {
+	if m.FailAccountExportFunc != nil {
+		return m.FailAccountExportFunc(ctx, id, reason)
+	}
+	return nil
+}
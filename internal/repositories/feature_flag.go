@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// FeatureFlagRepository defines the interface for feature flag data access
+type FeatureFlagRepository interface {
+	Load(ctx context.Context) ([]*models.FeatureFlag, error)
+	Upsert(ctx context.Context, flag *models.FeatureFlag) error
+	SetOverride(ctx context.Context, name string, userID string, enabled bool) error
+	DeleteOverride(ctx context.Context, name string, userID string) error
+}
+
+// PostgresFeatureFlagRepository is the PostgreSQL implementation of FeatureFlagRepository
+type PostgresFeatureFlagRepository struct {
+	db DB
+}
+
+// NewPostgresFeatureFlagRepository creates a new PostgreSQL feature flag repository
+func NewPostgresFeatureFlagRepository(db DB) FeatureFlagRepository {
+	return &PostgresFeatureFlagRepository{db: db}
+}
+
+// Load retrieves the stored flags with their overrides
+func (r *PostgresFeatureFlagRepository) Load(ctx context.Context) ([]*models.FeatureFlag, error) {
+	query := `
+		SELECT f.name, f.description, f.enabled, f.rollout_percent, f.updated_at,
+		       COALESCE(jsonb_object_agg(o.user_id::text, o.enabled) FILTER (WHERE o.user_id IS NOT NULL), '{}')
+		FROM feature_flags f
+		LEFT JOIN feature_flag_overrides o ON o.flag_name = f.name
+		GROUP BY f.name
+		ORDER BY f.name
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	flags := []*models.FeatureFlag{}
+	for rows.Next() {
+		flag := &models.FeatureFlag{Stored: true}
+		err := rows.Scan(
+			&flag.Name,
+			&flag.Description,
+			&flag.Enabled,
+			&flag.RolloutPercent,
+			&flag.UpdatedAt,
+			&flag.Overrides,
+		)
+		if err != nil {
+			return nil, err
+		}
+		flags = append(flags, flag)
+	}
+	return flags, rows.Err()
+}
+
+// Upsert stores a flag's settings, keeping its overrides, and sets its update time
+func (r *PostgresFeatureFlagRepository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	query := `
+		INSERT INTO feature_flags (name, description, enabled, rollout_percent)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO UPDATE
+		SET description = EXCLUDED.description,
+		    enabled = EXCLUDED.enabled,
+		    rollout_percent = EXCLUDED.rollout_percent
+		RETURNING updated_at
+	`
+
+	flag.Stored = true
+	return r.db.QueryRow(ctx, query, flag.Name, flag.Description, flag.Enabled, flag.RolloutPercent).
+		Scan(&flag.UpdatedAt)
+}
+
+// SetOverride turns a stored flag on or off for one user
+// Returns ErrReferenced if the flag isn't stored or the user does not exist.
+func (r *PostgresFeatureFlagRepository) SetOverride(ctx context.Context, name string, userID string, enabled bool) error {
+	query := `
+		INSERT INTO feature_flag_overrides (flag_name, user_id, enabled)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (flag_name, user_id) DO UPDATE SET enabled = EXCLUDED.enabled
+	`
+
+	_, err := r.db.Exec(ctx, query, name, userID, enabled)
+	return translateError(err)
+}
+
+// DeleteOverride returns a user to a flag's rollout
+// Returns pgx.ErrNoRows if there is no override.
+func (r *PostgresFeatureFlagRepository) DeleteOverride(ctx context.Context, name string, userID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM feature_flag_overrides WHERE flag_name = $1 AND user_id = $2`, name, userID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockFeatureFlagRepository is a mock implementation for testing
+type MockFeatureFlagRepository struct {
+	LoadFunc           func(ctx context.Context) ([]*models.FeatureFlag, error)
+	UpsertFunc         func(ctx context.Context, flag *models.FeatureFlag) error
+	SetOverrideFunc    func(ctx context.Context, name string, userID string, enabled bool) error
+	DeleteOverrideFunc func(ctx context.Context, name string, userID string) error
+}
+
+func (m *MockFeatureFlagRepository) Load(ctx context.Context) ([]*models.FeatureFlag, error) {
+	if m.LoadFunc != nil {
+		return m.LoadFunc(ctx)
+	}
+	return []*models.FeatureFlag{}, nil
+}
+
+func (m *MockFeatureFlagRepository) Upsert(ctx context.Context, flag *models.FeatureFlag) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, flag)
+	}
+	return nil
+}
+
+func (m *MockFeatureFlagRepository) SetOverride(ctx context.Context, name string, userID string, enabled bool) error {
+	if m.SetOverrideFunc != nil {
+		return m.SetOverrideFunc(ctx, name, userID, enabled)
+	}
+	return nil
+}
+
+func (m *MockFeatureFlagRepository) DeleteOverride(ctx context.Context, name string, userID string) error {
+	if m.DeleteOverrideFunc != nil {
+		return m.DeleteOverrideFunc(ctx, name, userID)
+	}
+	return nil
+}
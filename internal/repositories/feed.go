@@ -0,0 +1,139 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// FeedRepository defines the interface for reading users' activity feeds
+type FeedRepository interface {
+	Feed(ctx context.Context, userID string, filter models.FeedFilter) ([]*models.ActivityEvent, error)
+	FindEventOwner(ctx context.Context, eventID string) (string, error)
+	React(ctx context.Context, eventID string, userID string) error
+	Unreact(ctx context.Context, eventID string, userID string) error
+	Reactions(ctx context.Context, eventID string, userID string) (*models.ReactionSummary, error)
+}
+
+// PostgresFeedRepository is the PostgreSQL implementation of FeedRepository
+type PostgresFeedRepository struct {
+	db DB
+}
+
+// NewPostgresFeedRepository creates a new PostgreSQL feed repository
+func NewPostgresFeedRepository(db DB) FeedRepository {
+	return &PostgresFeedRepository{db: db}
+}
+
+// feedSelect selects the activity of the users $1 follows and may still see, with the
+// sessions and records it is about and how it has been liked
+const feedSelect = `
+	SELECT a.id, a.user_id, a.type, s.id, s.name, s.session_type, s.duration_minutes, a.created_at,
+	       (SELECT COUNT(*) FROM activity_reactions r WHERE r.activity_event_id = a.id),
+	       EXISTS (SELECT 1 FROM activity_reactions r WHERE r.activity_event_id = a.id AND r.user_id = $1),
+	       l.exercise_id, e.name, l.weight_kg::float8, l.reps_completed, l.previous_best_weight::float8
+	FROM activity_events a
+	JOIN user_follows f ON f.followee_id = a.user_id AND f.follower_id = $1 AND f.status = 'accepted'
+	JOIN workout_sessions s ON s.id = a.workout_session_id
+	LEFT JOIN exercise_logs l ON l.id = a.exercise_log_id
+	LEFT JOIN exercises e ON e.id = l.exercise_id
+	WHERE can_view_profile($1, a.user_id)
+`
+
+// Feed retrieves a page of the activity of the users the user follows. The feed is
+// assembled on read: each page merges the followed users' events through the
+// (user_id, created_at, id) index, continuing from the cursor with a keyset predicate.
+// Users who made their profile private drop out of their followers' feeds.
+func (r *PostgresFeedRepository) Feed(ctx context.Context, userID string, filter models.FeedFilter) ([]*models.ActivityEvent, error) {
+	var rows pgx.Rows
+	var err error
+	if filter.After == nil {
+		query := feedSelect + `
+			ORDER BY a.created_at DESC, a.id DESC
+			LIMIT $2
+		`
+		rows, err = r.db.Query(ctx, query, userID, filter.Limit)
+	} else {
+		query := feedSelect + `
+			  AND (a.created_at, a.id) < ($3, $4::uuid)
+			ORDER BY a.created_at DESC, a.id DESC
+			LIMIT $2
+		`
+		rows, err = r.db.Query(ctx, query, userID, filter.Limit, filter.After.CreatedAt, filter.After.ID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*models.ActivityEvent{}
+	for rows.Next() {
+		e := &models.ActivityEvent{}
+		err := rows.Scan(
+			&e.ID,
+			&e.UserID,
+			&e.Type,
+			&e.SessionID,
+			&e.SessionName,
+			&e.SessionType,
+			&e.DurationMinutes,
+			&e.CreatedAt,
+			&e.ReactionCount,
+			&e.HasReacted,
+			&e.ExerciseID,
+			&e.ExerciseName,
+			&e.WeightKg,
+			&e.Reps,
+			&e.PreviousBestWeightKg,
+		)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// FindEventOwner retrieves the user whose activity an event is
+// Returns pgx.ErrNoRows if the event does not exist.
+func (r *PostgresFeedRepository) FindEventOwner(ctx context.Context, eventID string) (string, error) {
+	var ownerID string
+	err := r.db.QueryRow(ctx, `SELECT user_id FROM activity_events WHERE id = $1`, eventID).Scan(&ownerID)
+	return ownerID, err
+}
+
+// React records that the user likes an event; liking it again changes nothing
+func (r *PostgresFeedRepository) React(ctx context.Context, eventID string, userID string) error {
+	query := `
+		INSERT INTO activity_reactions (activity_event_id, user_id)
+		VALUES ($1, $2)
+		ON CONFLICT (activity_event_id, user_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(ctx, query, eventID, userID)
+	return translateError(err)
+}
+
+// Unreact removes the user's like of an event, if any
+func (r *PostgresFeedRepository) Unreact(ctx context.Context, eventID string, userID string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM activity_reactions WHERE activity_event_id = $1 AND user_id = $2`, eventID, userID)
+	return err
+}
+
+// Reactions counts the likes of an event and whether the user is among them
+func (r *PostgresFeedRepository) Reactions(ctx context.Context, eventID string, userID string) (*models.ReactionSummary, error) {
+	query := `
+		SELECT COUNT(*), COALESCE(BOOL_OR(user_id = $2), FALSE)
+		FROM activity_reactions
+		WHERE activity_event_id = $1
+	`
+
+	summary := &models.ReactionSummary{ActivityID: eventID}
+	err := r.db.QueryRow(ctx, query, eventID, userID).Scan(&summary.ReactionCount, &summary.HasReacted)
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
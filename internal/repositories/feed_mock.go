@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockFeedRepository is a mock implementation for testing
+type MockFeedRepository struct {
+	FeedFunc           func(ctx context.Context, userID string, filter models.FeedFilter) ([]*models.ActivityEvent, error)
+	FindEventOwnerFunc func(ctx context.Context, eventID string) (string, error)
+	ReactFunc          func(ctx context.Context, eventID string, userID string) error
+	UnreactFunc        func(ctx context.Context, eventID string, userID string) error
+	ReactionsFunc      func(ctx context.Context, eventID string, userID string) (*models.ReactionSummary, error)
+}
+
+func (m *MockFeedRepository) Feed(ctx context.Context, userID string, filter models.FeedFilter) ([]*models.ActivityEvent, error) {
+	if m.FeedFunc != nil {
+		return m.FeedFunc(ctx, userID, filter)
+	}
+	return []*models.ActivityEvent{}, nil
+}
+
+func (m *MockFeedRepository) FindEventOwner(ctx context.Context, eventID string) (string, error) {
+	if m.FindEventOwnerFunc != nil {
+		return m.FindEventOwnerFunc(ctx, eventID)
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (m *MockFeedRepository) React(ctx context.Context, eventID string, userID string) error {
+	if m.ReactFunc != nil {
+		return m.ReactFunc(ctx, eventID, userID)
+	}
+	return nil
+}
+
+func (m *MockFeedRepository) Unreact(ctx context.Context, eventID string, userID string) error {
+	if m.UnreactFunc != nil {
+		return m.UnreactFunc(ctx, eventID, userID)
+	}
+	return nil
+}
+
+func (m *MockFeedRepository) Reactions(ctx context.Context, eventID string, userID string) (*models.ReactionSummary, error) {
+	if m.ReactionsFunc != nil {
+		return m.ReactionsFunc(ctx, eventID, userID)
+	}
+	return &models.ReactionSummary{ActivityID: eventID}, nil
+}
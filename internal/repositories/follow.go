@@ -0,0 +1,138 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// FollowRepository defines the interface for users following each other
+type FollowRepository interface {
+	Create(ctx context.Context, follow *models.Follow) error
+	Accept(ctx context.Context, followerID string, followeeID string) (*models.Follow, error)
+	Delete(ctx context.Context, followerID string, followeeID string) error
+	FindFollowers(ctx context.Context, followeeID string, status string) ([]*models.Follow, error)
+	FindFollowing(ctx context.Context, followerID string, status string) ([]*models.Follow, error)
+	CanView(ctx context.Context, viewerID string, ownerID string) (bool, error)
+}
+
+// PostgresFollowRepository is the PostgreSQL implementation of FollowRepository
+type PostgresFollowRepository struct {
+	db DB
+}
+
+// NewPostgresFollowRepository creates a new PostgreSQL follow repository
+func NewPostgresFollowRepository(db DB) FollowRepository {
+	return &PostgresFollowRepository{db: db}
+}
+
+const followColumns = `follower_id, followee_id, status, accepted_at, created_at`
+
+// Create stores a follow, accepted now when its status says so, and sets its timestamps.
+// Following someone already followed (or asked) leaves the follow as it is and loads it
+// into follow. Returns ErrReferenced if the followee does not exist.
+func (r *PostgresFollowRepository) Create(ctx context.Context, follow *models.Follow) error {
+	query := `
+		INSERT INTO user_follows (follower_id, followee_id, status, accepted_at)
+		VALUES ($1, $2, $3, CASE WHEN $3 = 'accepted' THEN NOW() END)
+		ON CONFLICT (follower_id, followee_id) DO UPDATE SET status = user_follows.status
+		RETURNING status, accepted_at, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, follow.FollowerID, follow.FolloweeID, follow.Status).Scan(
+		&follow.Status,
+		&follow.AcceptedAt,
+		&follow.CreatedAt,
+	)
+	return translateError(err)
+}
+
+// Accept approves a pending follow request
+// Returns pgx.ErrNoRows if there is no pending request from the follower.
+func (r *PostgresFollowRepository) Accept(ctx context.Context, followerID string, followeeID string) (*models.Follow, error) {
+	query := `
+		UPDATE user_follows SET status = 'accepted', accepted_at = NOW()
+		WHERE follower_id = $1 AND followee_id = $2 AND status = 'pending'
+		RETURNING ` + followColumns
+
+	return scanFollow(r.db.QueryRow(ctx, query, followerID, followeeID))
+}
+
+// Delete removes a follow or follow request
+// Returns pgx.ErrNoRows if there is none.
+func (r *PostgresFollowRepository) Delete(ctx context.Context, followerID string, followeeID string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM user_follows WHERE follower_id = $1 AND followee_id = $2`, followerID, followeeID)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// FindFollowers retrieves the follows of a user with status, or all of them when status is
+// empty, newest first
+func (r *PostgresFollowRepository) FindFollowers(ctx context.Context, followeeID string, status string) ([]*models.Follow, error) {
+	query := `
+		SELECT ` + followColumns + `
+		FROM user_follows
+		WHERE followee_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, follower_id
+	`
+	return r.queryList(ctx, query, followeeID, status)
+}
+
+// FindFollowing retrieves the follows a user made with status, or all of them when status
+// is empty, newest first
+func (r *PostgresFollowRepository) FindFollowing(ctx context.Context, followerID string, status string) ([]*models.Follow, error) {
+	query := `
+		SELECT ` + followColumns + `
+		FROM user_follows
+		WHERE follower_id = $1 AND ($2 = '' OR status = $2)
+		ORDER BY created_at DESC, followee_id
+	`
+	return r.queryList(ctx, query, followerID, status)
+}
+
+// CanView reports whether the viewer may see the owner's profile under its privacy
+func (r *PostgresFollowRepository) CanView(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+	var visible bool
+	err := r.db.QueryRow(ctx, `SELECT can_view_profile($1, $2)`, viewerID, ownerID).Scan(&visible)
+	return visible, err
+}
+
+func (r *PostgresFollowRepository) queryList(ctx context.Context, query string, args ...any) ([]*models.Follow, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	follows := []*models.Follow{}
+	for rows.Next() {
+		follow, err := scanFollow(rows)
+		if err != nil {
+			return nil, err
+		}
+		follows = append(follows, follow)
+	}
+
+	return follows, rows.Err()
+}
+
+func scanFollow(row pgx.Row) (*models.Follow, error) {
+	f := &models.Follow{}
+	err := row.Scan(
+		&f.FollowerID,
+		&f.FolloweeID,
+		&f.Status,
+		&f.AcceptedAt,
+		&f.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil
+}
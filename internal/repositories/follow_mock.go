@@ -0,0 +1,65 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockFollowRepository is a mock implementation for testing
+type MockFollowRepository struct {
+	CreateFunc        func(ctx context.Context, follow *models.Follow) error
+	AcceptFunc        func(ctx context.Context, followerID string, followeeID string) (*models.Follow, error)
+	DeleteFunc        func(ctx context.Context, followerID string, followeeID string) error
+	FindFollowersFunc func(ctx context.Context, followeeID string, status string) ([]*models.Follow, error)
+	FindFollowingFunc func(ctx context.Context, followerID string, status string) ([]*models.Follow, error)
+	CanViewFunc       func(ctx context.Context, viewerID string, ownerID string) (bool, error)
+}
+
+func (m *MockFollowRepository) Create(ctx context.Context, follow *models.Follow) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, follow)
+	}
+	follow.CreatedAt = time.Now()
+	if follow.Status == models.FollowStatusAccepted {
+		follow.AcceptedAt = &follow.CreatedAt
+	}
+	return nil
+}
+
+func (m *MockFollowRepository) Accept(ctx context.Context, followerID string, followeeID string) (*models.Follow, error) {
+	if m.AcceptFunc != nil {
+		return m.AcceptFunc(ctx, followerID, followeeID)
+	}
+	now := time.Now()
+	return &models.Follow{FollowerID: followerID, FolloweeID: followeeID, Status: models.FollowStatusAccepted, AcceptedAt: &now, CreatedAt: now}, nil
+}
+
+func (m *MockFollowRepository) Delete(ctx context.Context, followerID string, followeeID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, followerID, followeeID)
+	}
+	return nil
+}
+
+func (m *MockFollowRepository) FindFollowers(ctx context.Context, followeeID string, status string) ([]*models.Follow, error) {
+	if m.FindFollowersFunc != nil {
+		return m.FindFollowersFunc(ctx, followeeID, status)
+	}
+	return []*models.Follow{}, nil
+}
+
+func (m *MockFollowRepository) FindFollowing(ctx context.Context, followerID string, status string) ([]*models.Follow, error) {
+	if m.FindFollowingFunc != nil {
+		return m.FindFollowingFunc(ctx, followerID, status)
+	}
+	return []*models.Follow{}, nil
+}
+
+func (m *MockFollowRepository) CanView(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+	if m.CanViewFunc != nil {
+		return m.CanViewFunc(ctx, viewerID, ownerID)
+	}
+	return viewerID == ownerID, nil
+}
@@ -0,0 +1,152 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// GoalRepository defines the interface for goal data access
+type GoalRepository interface {
+	CurrentValue(ctx context.Context, userID string, kind string, exerciseID *string) (*float64, error)
+	Create(ctx context.Context, goal *models.Goal) error
+	FindByUser(ctx context.Context, userID string) ([]*models.Goal, error)
+	FindByID(ctx context.Context, userID string, id string) (*models.Goal, error)
+	UpdateTarget(ctx context.Context, userID string, id string, target float64) error
+	Delete(ctx context.Context, userID string, id string) error
+}
+
+// PostgresGoalRepository is the PostgreSQL implementation of GoalRepository
+type PostgresGoalRepository struct {
+	db DB
+}
+
+// NewPostgresGoalRepository creates a new PostgreSQL goal repository
+func NewPostgresGoalRepository(db DB) GoalRepository {
+	return &PostgresGoalRepository{db: db}
+}
+
+// goalColumns selects a goal with its current value and whether it is achieved; weekly
+// goals count as achieved only when met in the user's current week
+const goalColumns = `
+	g.id, g.user_id, g.kind, g.exercise_id, g.target_value::float8, g.start_value::float8,
+	g.achieved_at, g.created_at, g.updated_at,
+	goal_current_value(g.user_id, g.kind, g.exercise_id),
+	g.achieved_at IS NOT NULL AND (g.kind <> 'weekly_sessions' OR g.achieved_at >= user_week_start(g.user_id))
+`
+
+// CurrentValue computes what a goal of kind would be measured against right now: the
+// latest weigh-in, the lift's best estimated 1RM or this week's completed sessions
+// Returns nil when the user has no data for it yet.
+func (r *PostgresGoalRepository) CurrentValue(ctx context.Context, userID string, kind string, exerciseID *string) (*float64, error) {
+	var value *float64
+	err := r.db.QueryRow(ctx, `SELECT goal_current_value($1, $2, $3)`, userID, kind, exerciseID).Scan(&value)
+	return value, err
+}
+
+// Create inserts a goal and sets its ID and timestamps
+// A goal already met is marked achieved by the database; read it back to see it.
+func (r *PostgresGoalRepository) Create(ctx context.Context, goal *models.Goal) error {
+	query := `
+		INSERT INTO goals (user_id, kind, exercise_id, target_value, start_value)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		goal.UserID,
+		goal.Kind,
+		goal.ExerciseID,
+		goal.TargetValue,
+		goal.StartValue,
+	).Scan(&goal.ID, &goal.CreatedAt, &goal.UpdatedAt)
+}
+
+// FindByUser retrieves the user's goals with their progress, oldest first
+func (r *PostgresGoalRepository) FindByUser(ctx context.Context, userID string) ([]*models.Goal, error) {
+	query := `SELECT ` + goalColumns + ` FROM goals g WHERE g.user_id = $1 ORDER BY g.created_at, g.id`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	goals := []*models.Goal{}
+	for rows.Next() {
+		goal, err := scanGoal(rows)
+		if err != nil {
+			return nil, err
+		}
+		goals = append(goals, goal)
+	}
+
+	return goals, rows.Err()
+}
+
+// FindByID retrieves one of the user's goals with its progress
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresGoalRepository) FindByID(ctx context.Context, userID string, id string) (*models.Goal, error) {
+	query := `SELECT ` + goalColumns + ` FROM goals g WHERE g.id = $1 AND g.user_id = $2`
+
+	return scanGoal(r.db.QueryRow(ctx, query, id, userID))
+}
+
+// UpdateTarget changes a goal's target and clears its achievement; the database marks it
+// achieved again if the new target is already met
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresGoalRepository) UpdateTarget(ctx context.Context, userID string, id string, target float64) error {
+	query := `UPDATE goals SET target_value = $3, achieved_at = NULL WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, userID, target)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Delete removes one of the user's goals
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresGoalRepository) Delete(ctx context.Context, userID string, id string) error {
+	query := `DELETE FROM goals WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+func scanGoal(row pgx.Row) (*models.Goal, error) {
+	g := &models.Goal{}
+	err := row.Scan(
+		&g.ID,
+		&g.UserID,
+		&g.Kind,
+		&g.ExerciseID,
+		&g.TargetValue,
+		&g.StartValue,
+		&g.AchievedAt,
+		&g.CreatedAt,
+		&g.UpdatedAt,
+		&g.CurrentValue,
+		&g.Achieved,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return g, nil
+}
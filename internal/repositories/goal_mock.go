@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockGoalRepository is a mock implementation for testing
+type MockGoalRepository struct {
+	CurrentValueFunc func(ctx context.Context, userID string, kind string, exerciseID *string) (*float64, error)
+	CreateFunc       func(ctx context.Context, goal *models.Goal) error
+	FindByUserFunc   func(ctx context.Context, userID string) ([]*models.Goal, error)
+	FindByIDFunc     func(ctx context.Context, userID string, id string) (*models.Goal, error)
+	UpdateTargetFunc func(ctx context.Context, userID string, id string, target float64) error
+	DeleteFunc       func(ctx context.Context, userID string, id string) error
+}
+
+func (m *MockGoalRepository) CurrentValue(ctx context.Context, userID string, kind string, exerciseID *string) (*float64, error) {
+	if m.CurrentValueFunc != nil {
+		return m.CurrentValueFunc(ctx, userID, kind, exerciseID)
+	}
+	return nil, nil
+}
+
+func (m *MockGoalRepository) Create(ctx context.Context, goal *models.Goal) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, goal)
+	}
+	goal.ID = "mock-goal-id"
+	return nil
+}
+
+func (m *MockGoalRepository) FindByUser(ctx context.Context, userID string) ([]*models.Goal, error) {
+	if m.FindByUserFunc != nil {
+		return m.FindByUserFunc(ctx, userID)
+	}
+	return []*models.Goal{}, nil
+}
+
+func (m *MockGoalRepository) FindByID(ctx context.Context, userID string, id string) (*models.Goal, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, userID, id)
+	}
+	return &models.Goal{ID: id, UserID: userID, Kind: models.GoalKindWeeklySessions, TargetValue: 3}, nil
+}
+
+func (m *MockGoalRepository) UpdateTarget(ctx context.Context, userID string, id string, target float64) error {
+	if m.UpdateTargetFunc != nil {
+		return m.UpdateTargetFunc(ctx, userID, id, target)
+	}
+	return nil
+}
+
+func (m *MockGoalRepository) Delete(ctx context.Context, userID string, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, id)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// HydrationRepository defines the interface for water logs and daily hydration goals.
+// Days are calendar dates, at midnight UTC, counted in the user's timezone.
+type HydrationRepository interface {
+	Create(ctx context.Context, log *models.WaterLog) error
+	FindByDay(ctx context.Context, userID string, day time.Time) ([]*models.WaterLog, error)
+	Delete(ctx context.Context, userID string, id string) error
+	DailyTotal(ctx context.Context, userID string, day time.Time) (totalMl int, logs int, err error)
+	FindGoal(ctx context.Context, userID string) (*models.HydrationGoal, error)
+	SaveGoal(ctx context.Context, goal *models.HydrationGoal) error
+}
+
+// PostgresHydrationRepository is the PostgreSQL implementation of HydrationRepository
+type PostgresHydrationRepository struct {
+	db DB
+}
+
+// NewPostgresHydrationRepository creates a new PostgreSQL hydration repository
+func NewPostgresHydrationRepository(db DB) HydrationRepository {
+	return &PostgresHydrationRepository{db: db}
+}
+
+// waterLogDay selects the user's ($1) logs of a day ($2) in their timezone
+const waterLogDay = `
+	FROM water_logs w, (SELECT user_timezone($1) AS tz) z
+	WHERE w.user_id = $1
+	  AND w.logged_at >= ($2::date::timestamp AT TIME ZONE z.tz)
+	  AND w.logged_at < (($2::date + 1)::timestamp AT TIME ZONE z.tz)
+`
+
+// Create logs water and sets its ID and created_at
+func (r *PostgresHydrationRepository) Create(ctx context.Context, log *models.WaterLog) error {
+	query := `
+		INSERT INTO water_logs (user_id, amount_ml, logged_at)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, log.UserID, log.AmountMl, log.LoggedAt).Scan(&log.ID, &log.CreatedAt)
+}
+
+// FindByDay retrieves the user's logs of a day, oldest first
+func (r *PostgresHydrationRepository) FindByDay(ctx context.Context, userID string, day time.Time) ([]*models.WaterLog, error) {
+	query := `SELECT w.id, w.user_id, w.amount_ml, w.logged_at, w.created_at` + waterLogDay + `ORDER BY w.logged_at ASC`
+
+	rows, err := r.db.Query(ctx, query, userID, day)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*models.WaterLog{}
+	for rows.Next() {
+		l := &models.WaterLog{}
+		if err := rows.Scan(&l.ID, &l.UserID, &l.AmountMl, &l.LoggedAt, &l.CreatedAt); err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
+
+// Delete removes one of the user's logs
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresHydrationRepository) Delete(ctx context.Context, userID string, id string) error {
+	query := `DELETE FROM water_logs WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// DailyTotal sums the water the user logged on a day
+func (r *PostgresHydrationRepository) DailyTotal(ctx context.Context, userID string, day time.Time) (int, int, error) {
+	query := `SELECT COALESCE(SUM(w.amount_ml), 0), COUNT(*)` + waterLogDay
+
+	var total, logs int
+	err := r.db.QueryRow(ctx, query, userID, day).Scan(&total, &logs)
+	return total, logs, err
+}
+
+// FindGoal retrieves the user's daily goal; users who never set one get the default
+func (r *PostgresHydrationRepository) FindGoal(ctx context.Context, userID string) (*models.HydrationGoal, error) {
+	query := `SELECT user_id, daily_ml, updated_at FROM hydration_goals WHERE user_id = $1`
+
+	goal := &models.HydrationGoal{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&goal.UserID, &goal.DailyMl, &goal.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.HydrationGoal{UserID: userID, DailyMl: models.DefaultHydrationGoalMl}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return goal, nil
+}
+
+// SaveGoal stores the user's daily goal and sets its updated_at
+func (r *PostgresHydrationRepository) SaveGoal(ctx context.Context, goal *models.HydrationGoal) error {
+	query := `
+		INSERT INTO hydration_goals (user_id, daily_ml)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO UPDATE SET daily_ml = EXCLUDED.daily_ml
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, goal.UserID, goal.DailyMl).Scan(&goal.UpdatedAt)
+}
@@ -0,0 +1,64 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockHydrationRepository is a mock implementation for testing
+type MockHydrationRepository struct {
+	CreateFunc     func(ctx context.Context, log *models.WaterLog) error
+	FindByDayFunc  func(ctx context.Context, userID string, day time.Time) ([]*models.WaterLog, error)
+	DeleteFunc     func(ctx context.Context, userID string, id string) error
+	DailyTotalFunc func(ctx context.Context, userID string, day time.Time) (int, int, error)
+	FindGoalFunc   func(ctx context.Context, userID string) (*models.HydrationGoal, error)
+	SaveGoalFunc   func(ctx context.Context, goal *models.HydrationGoal) error
+}
+
+func (m *MockHydrationRepository) Create(ctx context.Context, log *models.WaterLog) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, log)
+	}
+	log.ID = "mock-water-log-id"
+	log.CreatedAt = time.Now()
+	return nil
+}
+
+func (m *MockHydrationRepository) FindByDay(ctx context.Context, userID string, day time.Time) ([]*models.WaterLog, error) {
+	if m.FindByDayFunc != nil {
+		return m.FindByDayFunc(ctx, userID, day)
+	}
+	return []*models.WaterLog{}, nil
+}
+
+func (m *MockHydrationRepository) Delete(ctx context.Context, userID string, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+func (m *MockHydrationRepository) DailyTotal(ctx context.Context, userID string, day time.Time) (int, int, error) {
+	if m.DailyTotalFunc != nil {
+		return m.DailyTotalFunc(ctx, userID, day)
+	}
+	return 0, 0, nil
+}
+
+func (m *MockHydrationRepository) FindGoal(ctx context.Context, userID string) (*models.HydrationGoal, error) {
+	if m.FindGoalFunc != nil {
+		return m.FindGoalFunc(ctx, userID)
+	}
+	return &models.HydrationGoal{UserID: userID, DailyMl: models.DefaultHydrationGoalMl}, nil
+}
+
+func (m *MockHydrationRepository) SaveGoal(ctx context.Context, goal *models.HydrationGoal) error {
+	if m.SaveGoalFunc != nil {
+		return m.SaveGoalFunc(ctx, goal)
+	}
+	now := time.Now()
+	goal.UpdatedAt = &now
+	return nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// IdempotencyRepository defines the interface for idempotency key data access
+type IdempotencyRepository interface {
+	Reserve(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error)
+	Find(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Release(ctx context.Context, userID string, key string) error
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PostgresIdempotencyRepository is the PostgreSQL implementation of IdempotencyRepository
+type PostgresIdempotencyRepository struct {
+	db DB
+}
+
+// NewPostgresIdempotencyRepository creates a new PostgreSQL idempotency repository
+func NewPostgresIdempotencyRepository(db DB) IdempotencyRepository {
+	return &PostgresIdempotencyRepository{db: db}
+}
+
+// Reserve records a key for a request about to run, and reports false if the key is taken.
+// Keys created before reusableBefore have expired and are taken over, as are reservations
+// still running since before abandonedBefore, whose request died without releasing them.
+func (r *PostgresIdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error) {
+	query := `
+		INSERT INTO idempotency_keys (user_id, key, request_hash, created_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (user_id, key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash, status_code = NULL, content_type = NULL, body = NULL, created_at = NOW()
+		WHERE idempotency_keys.created_at < $4
+		   OR (idempotency_keys.status_code IS NULL AND idempotency_keys.created_at < $5)
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, record.UserID, record.Key, record.RequestHash, reusableBefore, abandonedBefore).Scan(&record.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Find retrieves a key's request and response
+// Returns pgx.ErrNoRows if the key is not recorded.
+func (r *PostgresIdempotencyRepository) Find(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error) {
+	query := `
+		SELECT user_id, key, request_hash, status_code, COALESCE(content_type, ''), body, created_at
+		FROM idempotency_keys
+		WHERE user_id = $1 AND key = $2
+	`
+
+	record := &models.IdempotencyRecord{}
+	err := r.db.QueryRow(ctx, query, userID, key).Scan(
+		&record.UserID,
+		&record.Key,
+		&record.RequestHash,
+		&record.StatusCode,
+		&record.ContentType,
+		&record.Body,
+		&record.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// Complete stores the response of a reserved key's request
+func (r *PostgresIdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys
+		SET status_code = $3, content_type = $4, body = $5
+		WHERE user_id = $1 AND key = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, record.UserID, record.Key, record.StatusCode, record.ContentType, record.Body)
+	return err
+}
+
+// Release drops a reservation whose request failed, so a retry runs it again
+func (r *PostgresIdempotencyRepository) Release(ctx context.Context, userID string, key string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE user_id = $1 AND key = $2 AND status_code IS NULL`, userID, key)
+	return err
+}
+
+// Prune deletes keys created before a cutoff
+func (r *PostgresIdempotencyRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM idempotency_keys WHERE created_at < $1`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockIdempotencyRepository is a mock implementation for testing
+type MockIdempotencyRepository struct {
+	ReserveFunc  func(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error)
+	FindFunc     func(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error)
+	CompleteFunc func(ctx context.Context, record *models.IdempotencyRecord) error
+	ReleaseFunc  func(ctx context.Context, userID string, key string) error
+	PruneFunc    func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockIdempotencyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error) {
+	if m.ReserveFunc != nil {
+		return m.ReserveFunc(ctx, record, reusableBefore, abandonedBefore)
+	}
+	return true, nil
+}
+
+func (m *MockIdempotencyRepository) Find(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error) {
+	if m.FindFunc != nil {
+		return m.FindFunc(ctx, userID, key)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockIdempotencyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, record)
+	}
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Release(ctx context.Context, userID string, key string) error {
+	if m.ReleaseFunc != nil {
+		return m.ReleaseFunc(ctx, userID, key)
+	}
+	return nil
+}
+
+func (m *MockIdempotencyRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	if m.PruneFunc != nil {
+		return m.PruneFunc(ctx, before)
+	}
+	return 0, nil
+}
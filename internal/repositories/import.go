@@ -0,0 +1,312 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ImportRepository defines the interface for storing workout history imported from other apps
+type ImportRepository interface {
+	MatchExercises(ctx context.Context, userID string, names []string) (map[string]string, error)
+	FindLoggedExercises(ctx context.Context, userID string, from, to time.Time) ([]*models.LoggedExercise, error)
+	CreateSessions(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error
+	CreateImport(ctx context.Context, imp *models.Import, sessions []*models.ImportedSession) error
+	FindImport(ctx context.Context, id string) (*models.Import, error)
+	FindImportSessions(ctx context.Context, id string) ([]*models.ImportedSession, error)
+	CompleteImport(ctx context.Context, id string, result *models.ImportResult) error
+	FailImport(ctx context.Context, id string, reason string) error
+}
+
+// PostgresImportRepository is the PostgreSQL implementation of ImportRepository
+type PostgresImportRepository struct {
+	db DB
+}
+
+// NewPostgresImportRepository creates a new PostgreSQL import repository
+func NewPostgresImportRepository(db DB) ImportRepository {
+	return &PostgresImportRepository{db: db}
+}
+
+// MatchExercises maps exercise names to visible library exercises by accent- and
+// case-insensitive name. The user's own exercises win over public ones.
+// Names without a match are absent from the returned map.
+func (r *PostgresImportRepository) MatchExercises(ctx context.Context, userID string, names []string) (map[string]string, error) {
+	query := `
+		SELECT DISTINCT ON (n.name) n.name, e.id
+		FROM unnest($2::text[]) AS n(name)
+		JOIN exercises e
+		  ON immutable_unaccent(lower(e.name)) = immutable_unaccent(lower(n.name))
+		 AND (e.user_id = $1 OR e.is_public = TRUE)
+		ORDER BY n.name, (e.user_id = $1) DESC, e.created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	matches := make(map[string]string)
+	for rows.Next() {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		matches[name] = id
+	}
+
+	return matches, rows.Err()
+}
+
+// FindLoggedExercises returns the exercises the user logged in sessions started between from and to
+func (r *PostgresImportRepository) FindLoggedExercises(ctx context.Context, userID string, from, to time.Time) ([]*models.LoggedExercise, error) {
+	query := `
+		SELECT DISTINCT s.started_at, l.exercise_id
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		WHERE s.user_id = $1 AND s.started_at BETWEEN $2 AND $3
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var logged []*models.LoggedExercise
+	for rows.Next() {
+		l := &models.LoggedExercise{}
+		if err := rows.Scan(&l.StartedAt, &l.ExerciseID); err != nil {
+			return nil, err
+		}
+		logged = append(logged, l)
+	}
+
+	return logged, rows.Err()
+}
+
+// CreateSessions stores imported sessions and their sets in a single transaction.
+// newExercises are created first as private exercises; sets without an ExerciseID are
+// linked to them by name. Each set becomes one completed exercise log; cardio laps are
+// stored in order. Rows are written with COPY, as imports run to thousands of sets.
+func (r *PostgresImportRepository) CreateSessions(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	// Imported history is not news to webhook subscribers
+	if _, err := tx.Exec(ctx, suppressWebhooks); err != nil {
+		return err
+	}
+
+	created, err := createImportedExercises(ctx, tx, userID, newExercises)
+	if err != nil {
+		return err
+	}
+	if err := copySessions(ctx, tx, userID, sessions, created); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// createImportedExercises creates private exercises and maps their names to their IDs
+func createImportedExercises(ctx context.Context, tx pgx.Tx, userID string, names []string) (map[string]string, error) {
+	created := make(map[string]string, len(names))
+	if len(names) == 0 {
+		return created, nil
+	}
+
+	query := `
+		INSERT INTO exercises (user_id, name, is_public)
+		SELECT $1, name, FALSE FROM unnest($2::text[]) AS name
+		RETURNING id, name
+	`
+	rows, err := tx.Query(ctx, query, userID, names)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id, name string
+		if err := rows.Scan(&id, &name); err != nil {
+			return nil, err
+		}
+		created[name] = id
+	}
+
+	return created, rows.Err()
+}
+
+// copySessions writes completed sessions with their sets and laps using one COPY per
+// table. Session IDs are drawn up front so sets and laps can reference them.
+func copySessions(ctx context.Context, tx pgx.Tx, userID string, sessions []*models.ImportedSession, created map[string]string) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+
+	rows, err := tx.Query(ctx, `SELECT gen_random_uuid()::text FROM generate_series(1, $1)`, len(sessions))
+	if err != nil {
+		return err
+	}
+	sessionIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return err
+	}
+
+	sessionRows := make([][]any, 0, len(sessions))
+	var logRows, lapRows [][]any
+	for i, session := range sessions {
+		sessionID := sessionIDs[i]
+		sessionType := session.Type
+		if sessionType == "" {
+			sessionType = models.SessionTypeStrength
+		}
+		sessionRows = append(sessionRows, []any{
+			sessionID,
+			userID,
+			nullIfEmpty(session.Name),
+			session.StartedAt,
+			session.StartedAt.Add(session.Duration),
+			int(session.Duration.Minutes()),
+			"completed",
+			nullIfEmpty(session.Notes),
+			session.CaloriesBurned,
+			session.HeartRateAvg,
+			session.HeartRateMax,
+			sessionType,
+		})
+
+		for j, set := range session.Sets {
+			exerciseID := set.ExerciseID
+			if exerciseID == "" {
+				exerciseID = created[set.ExerciseName]
+			}
+			logRows = append(logRows, []any{
+				sessionID, exerciseID, j + 1, 1, 1,
+				set.Reps, set.WeightKg, set.DurationSec, set.DistanceM, set.RPE, nullIfEmpty(set.Notes),
+			})
+		}
+		for j, lap := range session.Laps {
+			lapRows = append(lapRows, []any{
+				sessionID, j + 1, lap.StartedAt, int(lap.Duration.Seconds()),
+				lap.DistanceM, lap.CaloriesBurned, lap.HeartRateAvg, lap.HeartRateMax,
+			})
+		}
+	}
+
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"workout_sessions", []string{
+			"id", "user_id", "name", "started_at", "completed_at", "duration_minutes", "status", "notes",
+			"calories_burned", "heart_rate_avg", "heart_rate_max", "session_type",
+		}, sessionRows},
+		{"exercise_logs", []string{
+			"workout_session_id", "exercise_id", "order_index", "sets_completed", "sets_planned",
+			"reps_completed", "weight_kg", "duration_seconds", "distance_meters", "rpe", "notes",
+		}, logRows},
+		{"session_laps", []string{
+			"workout_session_id", "lap_index", "started_at", "duration_seconds", "distance_meters",
+			"calories_burned", "heart_rate_avg", "heart_rate_max",
+		}, lapRows},
+	}
+	for _, c := range copies {
+		if len(c.rows) == 0 {
+			continue
+		}
+		if _, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows)); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", c.table, err)
+		}
+	}
+
+	return nil
+}
+
+const importColumns = `id, user_id, source, status, timezone, result, error, created_at, completed_at`
+
+func scanImport(row pgx.Row) (*models.Import, error) {
+	imp := &models.Import{}
+	err := row.Scan(
+		&imp.ID,
+		&imp.UserID,
+		&imp.Source,
+		&imp.Status,
+		&imp.Timezone,
+		&imp.Result,
+		&imp.Error,
+		&imp.CreatedAt,
+		&imp.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return imp, nil
+}
+
+// CreateImport inserts a pending import holding the parsed sessions until it runs
+func (r *PostgresImportRepository) CreateImport(ctx context.Context, imp *models.Import, sessions []*models.ImportedSession) error {
+	query := `
+		INSERT INTO imports (user_id, source, status, timezone, sessions)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, imp.UserID, imp.Source, imp.Status, imp.Timezone, sessions).Scan(&imp.ID, &imp.CreatedAt)
+}
+
+// FindImport retrieves an import's status without its sessions
+func (r *PostgresImportRepository) FindImport(ctx context.Context, id string) (*models.Import, error) {
+	query := `SELECT ` + importColumns + ` FROM imports WHERE id = $1`
+	return scanImport(r.db.QueryRow(ctx, query, id))
+}
+
+// FindImportSessions retrieves the parsed sessions of a pending import
+func (r *PostgresImportRepository) FindImportSessions(ctx context.Context, id string) ([]*models.ImportedSession, error) {
+	query := `SELECT sessions FROM imports WHERE id = $1 AND status = 'pending'`
+
+	var sessions []*models.ImportedSession
+	err := r.db.QueryRow(ctx, query, id).Scan(&sessions)
+	return sessions, err
+}
+
+// CompleteImport marks the import completed with its result and drops its sessions
+func (r *PostgresImportRepository) CompleteImport(ctx context.Context, id string, result *models.ImportResult) error {
+	query := `
+		UPDATE imports
+		SET status = 'completed', result = $2, sessions = NULL, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, result)
+	return err
+}
+
+// FailImport marks the import failed with a reason and drops its sessions
+func (r *PostgresImportRepository) FailImport(ctx context.Context, id string, reason string) error {
+	query := `
+		UPDATE imports
+		SET status = 'failed', error = $2, sessions = NULL, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, reason)
+	return err
+}
+
+// nullIfEmpty stores empty text as NULL
+func nullIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
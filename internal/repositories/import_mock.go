@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockImportRepository is a mock implementation for testing
+type MockImportRepository struct {
+	MatchExercisesFunc      func(ctx context.Context, userID string, names []string) (map[string]string, error)
+	FindLoggedExercisesFunc func(ctx context.Context, userID string, from, to time.Time) ([]*models.LoggedExercise, error)
+	CreateSessionsFunc      func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error
+	CreateImportFunc        func(ctx context.Context, imp *models.Import, sessions []*models.ImportedSession) error
+	FindImportFunc          func(ctx context.Context, id string) (*models.Import, error)
+	FindImportSessionsFunc  func(ctx context.Context, id string) ([]*models.ImportedSession, error)
+	CompleteImportFunc      func(ctx context.Context, id string, result *models.ImportResult) error
+	FailImportFunc          func(ctx context.Context, id string, reason string) error
+}
+
+func (m *MockImportRepository) MatchExercises(ctx context.Context, userID string, names []string) (map[string]string, error) {
+	if m.MatchExercisesFunc != nil {
+		return m.MatchExercisesFunc(ctx, userID, names)
+	}
+	return map[string]string{}, nil
+}
+
+func (m *MockImportRepository) FindLoggedExercises(ctx context.Context, userID string, from, to time.Time) ([]*models.LoggedExercise, error) {
+	if m.FindLoggedExercisesFunc != nil {
+		return m.FindLoggedExercisesFunc(ctx, userID, from, to)
+	}
+	return []*models.LoggedExercise{}, nil
+}
+
+func (m *MockImportRepository) CreateSessions(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+	if m.CreateSessionsFunc != nil {
+		return m.CreateSessionsFunc(ctx, userID, sessions, newExercises)
+	}
+	return nil
+}
+
+func (m *MockImportRepository) CreateImport(ctx context.Context, imp *models.Import, sessions []*models.ImportedSession) error {
+	if m.CreateImportFunc != nil {
+		return m.CreateImportFunc(ctx, imp, sessions)
+	}
+	imp.ID = "mock-import-id"
+	return nil
+}
+
+func (m *MockImportRepository) FindImport(ctx context.Context, id string) (*models.Import, error) {
+	if m.FindImportFunc != nil {
+		return m.FindImportFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockImportRepository) FindImportSessions(ctx context.Context, id string) ([]*models.ImportedSession, error) {
+	if m.FindImportSessionsFunc != nil {
+		return m.FindImportSessionsFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockImportRepository) CompleteImport(ctx context.Context, id string, result *models.ImportResult) error {
+	if m.CompleteImportFunc != nil {
+		return m.CompleteImportFunc(ctx, id, result)
+	}
+	return nil
+}
+
+func (m *MockImportRepository) FailImport(ctx context.Context, id string, reason string) error {
+	if m.FailImportFunc != nil {
+		return m.FailImportFunc(ctx, id, reason)
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// InjuryRepository defines the interface for injury data access
+type InjuryRepository interface {
+	Create(ctx context.Context, injury *models.Injury) error
+	FindByUser(ctx context.Context, userID string, activeOnly bool) ([]*models.Injury, error)
+	FindByID(ctx context.Context, userID string, id string) (*models.Injury, error)
+	Update(ctx context.Context, injury *models.Injury) error
+	Delete(ctx context.Context, userID string, id string) error
+	Contraindications(ctx context.Context, userID string) (map[string][]string, error)
+}
+
+// PostgresInjuryRepository is the PostgreSQL implementation of InjuryRepository
+type PostgresInjuryRepository struct {
+	db DB
+}
+
+// NewPostgresInjuryRepository creates a new PostgreSQL injury repository
+func NewPostgresInjuryRepository(db DB) InjuryRepository {
+	return &PostgresInjuryRepository{db: db}
+}
+
+// injuryActive tells whether injury i is active today in the user's timezone
+const injuryActive = `
+	i.started_on <= (NOW() AT TIME ZONE user_timezone(i.user_id))::date
+	AND (i.ended_on IS NULL OR i.ended_on >= (NOW() AT TIME ZONE user_timezone(i.user_id))::date)
+`
+
+// Create inserts an injury and sets its ID, whether it's active and timestamps
+func (r *PostgresInjuryRepository) Create(ctx context.Context, injury *models.Injury) error {
+	query := `
+		INSERT INTO injuries AS i (user_id, name, body_parts, started_on, ended_on, notes)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING i.id, ` + injuryActive + `, i.created_at, i.updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		injury.UserID,
+		injury.Name,
+		injury.BodyParts,
+		injury.StartedOn,
+		injury.EndedOn,
+		injury.Notes,
+	).Scan(&injury.ID, &injury.Active, &injury.CreatedAt, &injury.UpdatedAt)
+}
+
+// FindByUser retrieves the user's injuries, latest first, or only those active today
+func (r *PostgresInjuryRepository) FindByUser(ctx context.Context, userID string, activeOnly bool) ([]*models.Injury, error) {
+	query := `
+		SELECT i.id, i.user_id, i.name, i.body_parts, i.started_on, i.ended_on, i.notes,
+		       ` + injuryActive + ` AS active, i.created_at, i.updated_at
+		FROM injuries i
+		WHERE i.user_id = $1
+	`
+	if activeOnly {
+		query += ` AND ` + injuryActive
+	}
+	query += ` ORDER BY i.started_on DESC, i.created_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	injuries := []*models.Injury{}
+	for rows.Next() {
+		i, err := scanInjury(rows)
+		if err != nil {
+			return nil, err
+		}
+		injuries = append(injuries, i)
+	}
+
+	return injuries, rows.Err()
+}
+
+// FindByID retrieves one of the user's injuries
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresInjuryRepository) FindByID(ctx context.Context, userID string, id string) (*models.Injury, error) {
+	query := `
+		SELECT i.id, i.user_id, i.name, i.body_parts, i.started_on, i.ended_on, i.notes,
+		       ` + injuryActive + ` AS active, i.created_at, i.updated_at
+		FROM injuries i
+		WHERE i.id = $1 AND i.user_id = $2
+	`
+
+	return scanInjury(r.db.QueryRow(ctx, query, id, userID))
+}
+
+func scanInjury(row pgx.Row) (*models.Injury, error) {
+	i := &models.Injury{}
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.BodyParts,
+		&i.StartedOn,
+		&i.EndedOn,
+		&i.Notes,
+		&i.Active,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+// Update replaces one of the user's injuries and refreshes whether it's active
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresInjuryRepository) Update(ctx context.Context, injury *models.Injury) error {
+	query := `
+		UPDATE injuries AS i
+		SET name = $3, body_parts = $4, started_on = $5, ended_on = $6, notes = $7
+		WHERE i.id = $1 AND i.user_id = $2
+		RETURNING ` + injuryActive + `, i.created_at, i.updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		injury.ID,
+		injury.UserID,
+		injury.Name,
+		injury.BodyParts,
+		injury.StartedOn,
+		injury.EndedOn,
+		injury.Notes,
+	).Scan(&injury.Active, &injury.CreatedAt, &injury.UpdatedAt)
+}
+
+// Delete removes one of the user's injuries
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresInjuryRepository) Delete(ctx context.Context, userID string, id string) error {
+	query := `DELETE FROM injuries WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// Contraindications maps muscle groups to the body parts of the user's active injuries
+// their exercises load; muscle groups that load none are left out
+func (r *PostgresInjuryRepository) Contraindications(ctx context.Context, userID string) (map[string][]string, error) {
+	query := `
+		SELECT m.muscle_group, array_agg(m.body_part ORDER BY m.body_part)
+		FROM muscle_group_body_parts m
+		WHERE m.body_part = ANY(active_injury_body_parts($1))
+		GROUP BY m.muscle_group
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	contraindications := make(map[string][]string)
+	for rows.Next() {
+		var group string
+		var parts []string
+		if err := rows.Scan(&group, &parts); err != nil {
+			return nil, err
+		}
+		contraindications[group] = parts
+	}
+
+	return contraindications, rows.Err()
+}
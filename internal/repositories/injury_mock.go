@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockInjuryRepository is a mock implementation for testing
+type MockInjuryRepository struct {
+	CreateFunc            func(ctx context.Context, injury *models.Injury) error
+	FindByUserFunc        func(ctx context.Context, userID string, activeOnly bool) ([]*models.Injury, error)
+	FindByIDFunc          func(ctx context.Context, userID string, id string) (*models.Injury, error)
+	UpdateFunc            func(ctx context.Context, injury *models.Injury) error
+	DeleteFunc            func(ctx context.Context, userID string, id string) error
+	ContraindicationsFunc func(ctx context.Context, userID string) (map[string][]string, error)
+}
+
+func (m *MockInjuryRepository) Create(ctx context.Context, injury *models.Injury) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, injury)
+	}
+	injury.ID = "mock-injury-id"
+	return nil
+}
+
+func (m *MockInjuryRepository) FindByUser(ctx context.Context, userID string, activeOnly bool) ([]*models.Injury, error) {
+	if m.FindByUserFunc != nil {
+		return m.FindByUserFunc(ctx, userID, activeOnly)
+	}
+	return []*models.Injury{}, nil
+}
+
+func (m *MockInjuryRepository) FindByID(ctx context.Context, userID string, id string) (*models.Injury, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, userID, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockInjuryRepository) Update(ctx context.Context, injury *models.Injury) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, injury)
+	}
+	return nil
+}
+
+func (m *MockInjuryRepository) Delete(ctx context.Context, userID string, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, id)
+	}
+	return nil
+}
+
+func (m *MockInjuryRepository) Contraindications(ctx context.Context, userID string) (map[string][]string, error) {
+	if m.ContraindicationsFunc != nil {
+		return m.ContraindicationsFunc(ctx, userID)
+	}
+	return map[string][]string{}, nil
+}
@@ -0,0 +1,375 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// IntegrationRepository defines the interface for third-party platform connections and synced data
+type IntegrationRepository interface {
+	FindConnection(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error)
+	FindDueConnections(ctx context.Context, provider string, syncedBefore time.Time, limit int) ([]*models.IntegrationConnection, error)
+	SaveConnection(ctx context.Context, conn *models.IntegrationConnection) error
+	UpdateTokens(ctx context.Context, conn *models.IntegrationConnection) error
+	MarkSynced(ctx context.Context, id string, syncedAt time.Time) error
+	MarkSyncFailed(ctx context.Context, id string, reason string, reauthRequired bool) error
+	DeleteConnection(ctx context.Context, userID string, provider string) error
+	UpsertSessions(ctx context.Context, userID string, source string, sessions []*models.ExternalSession) (created, updated int, err error)
+	FindConnectionByID(ctx context.Context, id string) (*models.IntegrationConnection, error)
+	FindConnectionByAccount(ctx context.Context, provider string, accountID string) (*models.IntegrationConnection, error)
+	UpdateSettings(ctx context.Context, conn *models.IntegrationConnection) error
+	DeleteExternalSession(ctx context.Context, userID string, source string, externalID string) (bool, error)
+	FindPushSession(ctx context.Context, sessionID string) (*models.PushSession, error)
+	IsPushedActivity(ctx context.Context, connectionID string, activityID string) (bool, error)
+	EnqueuePushes(ctx context.Context, conn *models.IntegrationConnection) (int64, error)
+	RecordPush(ctx context.Context, connectionID string, sessionID string, activityID string) error
+}
+
+// PostgresIntegrationRepository is the PostgreSQL implementation of IntegrationRepository
+type PostgresIntegrationRepository struct {
+	db DB
+}
+
+// NewPostgresIntegrationRepository creates a new PostgreSQL integration repository
+func NewPostgresIntegrationRepository(db DB) IntegrationRepository {
+	return &PostgresIntegrationRepository{db: db}
+}
+
+const integrationConnectionColumns = `id, user_id, provider, external_account_id, access_token, refresh_token,
+	token_expires_at, scope, pull_activities, push_sessions_since, last_synced_at, last_sync_error, reauth_required,
+	created_at, updated_at`
+
+func scanIntegrationConnection(row pgx.Row) (*models.IntegrationConnection, error) {
+	conn := &models.IntegrationConnection{}
+	err := row.Scan(
+		&conn.ID,
+		&conn.UserID,
+		&conn.Provider,
+		&conn.ExternalAccountID,
+		&conn.AccessToken,
+		&conn.RefreshToken,
+		&conn.TokenExpiresAt,
+		&conn.Scope,
+		&conn.PullActivities,
+		&conn.PushSessionsSince,
+		&conn.LastSyncedAt,
+		&conn.LastSyncError,
+		&conn.ReauthRequired,
+		&conn.CreatedAt,
+		&conn.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return conn, nil
+}
+
+// FindConnection retrieves the user's connection to a provider
+func (r *PostgresIntegrationRepository) FindConnection(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error) {
+	query := `SELECT ` + integrationConnectionColumns + ` FROM integration_connections WHERE user_id = $1 AND provider = $2`
+	return scanIntegrationConnection(r.db.QueryRow(ctx, query, userID, provider))
+}
+
+// FindDueConnections retrieves connections never synced or last synced before syncedBefore,
+// least recently synced first. Connections waiting for the user to reconnect are left out.
+func (r *PostgresIntegrationRepository) FindDueConnections(ctx context.Context, provider string, syncedBefore time.Time, limit int) ([]*models.IntegrationConnection, error) {
+	query := `
+		SELECT ` + integrationConnectionColumns + `
+		FROM integration_connections
+		WHERE provider = $1 AND NOT reauth_required
+		  AND (last_synced_at IS NULL OR last_synced_at < $2)
+		ORDER BY last_synced_at ASC NULLS FIRST
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(ctx, query, provider, syncedBefore, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	connections := []*models.IntegrationConnection{}
+	for rows.Next() {
+		conn, err := scanIntegrationConnection(rows)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, conn)
+	}
+
+	return connections, rows.Err()
+}
+
+// SaveConnection creates the user's connection to a provider, or replaces the tokens of an
+// existing one (reconnecting keeps the sync history and settings)
+func (r *PostgresIntegrationRepository) SaveConnection(ctx context.Context, conn *models.IntegrationConnection) error {
+	query := `
+		INSERT INTO integration_connections (user_id, provider, external_account_id, access_token, refresh_token, token_expires_at, scope)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id, provider) DO UPDATE
+		SET external_account_id = EXCLUDED.external_account_id,
+		    access_token = EXCLUDED.access_token,
+		    refresh_token = EXCLUDED.refresh_token,
+		    token_expires_at = EXCLUDED.token_expires_at,
+		    scope = EXCLUDED.scope,
+		    last_sync_error = NULL,
+		    reauth_required = FALSE
+		RETURNING id, pull_activities, push_sessions_since, last_synced_at, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		conn.UserID,
+		conn.Provider,
+		conn.ExternalAccountID,
+		conn.AccessToken,
+		conn.RefreshToken,
+		conn.TokenExpiresAt,
+		conn.Scope,
+	).Scan(&conn.ID, &conn.PullActivities, &conn.PushSessionsSince, &conn.LastSyncedAt, &conn.CreatedAt, &conn.UpdatedAt)
+}
+
+// UpdateTokens stores refreshed tokens
+func (r *PostgresIntegrationRepository) UpdateTokens(ctx context.Context, conn *models.IntegrationConnection) error {
+	query := `
+		UPDATE integration_connections
+		SET access_token = $2, refresh_token = $3, token_expires_at = $4
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, conn.ID, conn.AccessToken, conn.RefreshToken, conn.TokenExpiresAt)
+	return err
+}
+
+// MarkSynced records a successful sync up to syncedAt
+func (r *PostgresIntegrationRepository) MarkSynced(ctx context.Context, id string, syncedAt time.Time) error {
+	query := `
+		UPDATE integration_connections
+		SET last_synced_at = $2, last_sync_error = NULL
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, syncedAt)
+	return err
+}
+
+// MarkSyncFailed records why a sync failed; last_synced_at is kept so the next sync
+// retries the same window
+func (r *PostgresIntegrationRepository) MarkSyncFailed(ctx context.Context, id string, reason string, reauthRequired bool) error {
+	query := `
+		UPDATE integration_connections
+		SET last_sync_error = $2, reauth_required = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, reason, reauthRequired)
+	return err
+}
+
+// DeleteConnection removes the user's connection to a provider
+// Returns pgx.ErrNoRows if the user was not connected.
+func (r *PostgresIntegrationRepository) DeleteConnection(ctx context.Context, userID string, provider string) error {
+	query := `DELETE FROM integration_connections WHERE user_id = $1 AND provider = $2`
+
+	tag, err := r.db.Exec(ctx, query, userID, provider)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// UpsertSessions stores completed sessions pulled from a provider in one transaction, keyed
+// by the provider's session ID. Sessions synced before are updated, unless the user edited
+// them in fitapi since they were last synced, in which case their edits win. Empty notes
+// keep the stored ones, since some provider listings leave descriptions out.
+func (r *PostgresIntegrationRepository) UpsertSessions(ctx context.Context, userID string, source string, sessions []*models.ExternalSession) (created, updated int, err error) {
+	query := `
+		INSERT INTO workout_sessions (
+			user_id, name, started_at, completed_at, duration_minutes, status, notes, session_type,
+			external_source, external_id, external_synced_at
+		)
+		VALUES ($1, NULLIF($2, ''), $3, $4, $5, 'completed', NULLIF($6, ''), COALESCE(NULLIF($9, ''), 'strength'), $7, $8, NOW())
+		ON CONFLICT (user_id, external_source, external_id) WHERE external_id IS NOT NULL DO UPDATE
+		SET name = EXCLUDED.name,
+		    session_type = EXCLUDED.session_type,
+		    started_at = EXCLUDED.started_at,
+		    completed_at = EXCLUDED.completed_at,
+		    duration_minutes = EXCLUDED.duration_minutes,
+		    notes = COALESCE(EXCLUDED.notes, workout_sessions.notes),
+		    external_synced_at = NOW()
+		WHERE workout_sessions.updated_at <= workout_sessions.external_synced_at
+		RETURNING (xmax = 0)
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, s := range sessions {
+		batch.Queue(
+			query,
+			userID,
+			s.Name,
+			s.StartedAt,
+			s.CompletedAt,
+			int(s.CompletedAt.Sub(s.StartedAt).Minutes()),
+			s.Notes,
+			source,
+			s.ExternalID,
+			s.Type,
+		)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	for range sessions {
+		var inserted bool
+		err := results.QueryRow().Scan(&inserted)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			// Edited locally; left untouched
+		case err != nil:
+			results.Close()
+			return 0, 0, err
+		case inserted:
+			created++
+		default:
+			updated++
+		}
+	}
+	if err := results.Close(); err != nil {
+		return 0, 0, err
+	}
+
+	return created, updated, tx.Commit(ctx)
+}
+
+// FindConnectionByID retrieves a connection by ID
+func (r *PostgresIntegrationRepository) FindConnectionByID(ctx context.Context, id string) (*models.IntegrationConnection, error) {
+	query := `SELECT ` + integrationConnectionColumns + ` FROM integration_connections WHERE id = $1`
+	return scanIntegrationConnection(r.db.QueryRow(ctx, query, id))
+}
+
+// FindConnectionByAccount retrieves the connection of a provider account, e.g. a Strava athlete
+func (r *PostgresIntegrationRepository) FindConnectionByAccount(ctx context.Context, provider string, accountID string) (*models.IntegrationConnection, error) {
+	query := `SELECT ` + integrationConnectionColumns + ` FROM integration_connections WHERE provider = $1 AND external_account_id = $2`
+	return scanIntegrationConnection(r.db.QueryRow(ctx, query, provider, accountID))
+}
+
+// UpdateSettings stores the connection's sync directions
+func (r *PostgresIntegrationRepository) UpdateSettings(ctx context.Context, conn *models.IntegrationConnection) error {
+	query := `
+		UPDATE integration_connections
+		SET pull_activities = $2, push_sessions_since = $3
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, conn.ID, conn.PullActivities, conn.PushSessionsSince).Scan(&conn.UpdatedAt)
+}
+
+// DeleteExternalSession deletes a session pulled from a provider and reports whether it did.
+// Sessions the user edited in fitapi since they were last synced are kept.
+func (r *PostgresIntegrationRepository) DeleteExternalSession(ctx context.Context, userID string, source string, externalID string) (bool, error) {
+	query := `
+		DELETE FROM workout_sessions
+		WHERE user_id = $1 AND external_source = $2 AND external_id = $3
+		  AND updated_at <= external_synced_at
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, source, externalID)
+	if err != nil {
+		return false, err
+	}
+	return tag.RowsAffected() > 0, nil
+}
+
+// FindPushSession retrieves a completed session with its total distance for pushing
+func (r *PostgresIntegrationRepository) FindPushSession(ctx context.Context, sessionID string) (*models.PushSession, error) {
+	query := `
+		SELECT s.id, s.name, s.notes, s.session_type, s.started_at, s.completed_at,
+		       (SELECT SUM(l.distance_meters)::float8 FROM exercise_logs l WHERE l.workout_session_id = s.id)
+		FROM workout_sessions s
+		WHERE s.id = $1 AND s.status = 'completed' AND s.completed_at IS NOT NULL
+	`
+
+	session := &models.PushSession{}
+	err := r.db.QueryRow(ctx, query, sessionID).Scan(
+		&session.ID,
+		&session.Name,
+		&session.Notes,
+		&session.Type,
+		&session.StartedAt,
+		&session.CompletedAt,
+		&session.DistanceM,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// IsPushedActivity reports whether a provider activity was created by pushing a fitapi session
+func (r *PostgresIntegrationRepository) IsPushedActivity(ctx context.Context, connectionID string, activityID string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1 FROM integration_pushes
+			WHERE connection_id = $1 AND activity_id = $2
+		)
+	`
+
+	var pushed bool
+	err := r.db.QueryRow(ctx, query, connectionID, activityID).Scan(&pushed)
+	return pushed, err
+}
+
+// EnqueuePushes queues a Strava push job for every session of the connection's user completed
+// since pushing was turned on and not queued before. Sessions pulled from a provider are skipped.
+func (r *PostgresIntegrationRepository) EnqueuePushes(ctx context.Context, conn *models.IntegrationConnection) (int64, error) {
+	if conn.PushSessionsSince == nil {
+		return 0, nil
+	}
+
+	query := `
+		WITH queued AS (
+			INSERT INTO integration_pushes (connection_id, session_id)
+			SELECT $1, s.id
+			FROM workout_sessions s
+			WHERE s.user_id = $2 AND s.status = 'completed' AND s.completed_at >= $3
+			  AND s.external_source IS NULL
+			ON CONFLICT (connection_id, session_id) DO NOTHING
+			RETURNING session_id
+		)
+		INSERT INTO jobs (user_id, kind, payload)
+		SELECT $2, 'strava_push_session', jsonb_build_object('connection_id', $1::uuid, 'object_id', q.session_id)
+		FROM queued q
+	`
+
+	tag, err := r.db.Exec(ctx, query, conn.ID, conn.UserID, conn.PushSessionsSince)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// RecordPush records the provider activity a session's push created
+func (r *PostgresIntegrationRepository) RecordPush(ctx context.Context, connectionID string, sessionID string, activityID string) error {
+	query := `
+		UPDATE integration_pushes
+		SET activity_id = $3
+		WHERE connection_id = $1 AND session_id = $2
+	`
+
+	_, err := r.db.Exec(ctx, query, connectionID, sessionID, activityID)
+	return err
+}
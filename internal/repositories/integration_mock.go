@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockIntegrationRepository is a mock implementation for testing
+type MockIntegrationRepository struct {
+	FindConnectionFunc     func(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error)
+	FindDueConnectionsFunc func(ctx context.Context, provider string, syncedBefore time.Time, limit int) ([]*models.IntegrationConnection, error)
+	SaveConnectionFunc     func(ctx context.Context, conn *models.IntegrationConnection) error
+	UpdateTokensFunc       func(ctx context.Context, conn *models.IntegrationConnection) error
+	MarkSyncedFunc         func(ctx context.Context, id string, syncedAt time.Time) error
+	MarkSyncFailedFunc     func(ctx context.Context, id string, reason string, reauthRequired bool) error
+	DeleteConnectionFunc   func(ctx context.Context, userID string, provider string) error
+	UpsertSessionsFunc     func(ctx context.Context, userID string, source string, sessions []*models.ExternalSession) (int, int, error)
+
+	FindConnectionByIDFunc      func(ctx context.Context, id string) (*models.IntegrationConnection, error)
+	FindConnectionByAccountFunc func(ctx context.Context, provider string, accountID string) (*models.IntegrationConnection, error)
+	UpdateSettingsFunc          func(ctx context.Context, conn *models.IntegrationConnection) error
+	DeleteExternalSessionFunc   func(ctx context.Context, userID string, source string, externalID string) (bool, error)
+	FindPushSessionFunc         func(ctx context.Context, sessionID string) (*models.PushSession, error)
+	IsPushedActivityFunc        func(ctx context.Context, connectionID string, activityID string) (bool, error)
+	EnqueuePushesFunc           func(ctx context.Context, conn *models.IntegrationConnection) (int64, error)
+	RecordPushFunc              func(ctx context.Context, connectionID string, sessionID string, activityID string) error
+}
+
+func (m *MockIntegrationRepository) FindConnection(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error) {
+	if m.FindConnectionFunc != nil {
+		return m.FindConnectionFunc(ctx, userID, provider)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockIntegrationRepository) FindDueConnections(ctx context.Context, provider string, syncedBefore time.Time, limit int) ([]*models.IntegrationConnection, error) {
+	if m.FindDueConnectionsFunc != nil {
+		return m.FindDueConnectionsFunc(ctx, provider, syncedBefore, limit)
+	}
+	return []*models.IntegrationConnection{}, nil
+}
+
+func (m *MockIntegrationRepository) SaveConnection(ctx context.Context, conn *models.IntegrationConnection) error {
+	if m.SaveConnectionFunc != nil {
+		return m.SaveConnectionFunc(ctx, conn)
+	}
+	conn.ID = "mock-connection-id"
+	return nil
+}
+
+func (m *MockIntegrationRepository) UpdateTokens(ctx context.Context, conn *models.IntegrationConnection) error {
+	if m.UpdateTokensFunc != nil {
+		return m.UpdateTokensFunc(ctx, conn)
+	}
+	return nil
+}
+
+func (m *MockIntegrationRepository) MarkSynced(ctx context.Context, id string, syncedAt time.Time) error {
+	if m.MarkSyncedFunc != nil {
+		return m.MarkSyncedFunc(ctx, id, syncedAt)
+	}
+	return nil
+}
+
+func (m *MockIntegrationRepository) MarkSyncFailed(ctx context.Context, id string, reason string, reauthRequired bool) error {
+	if m.MarkSyncFailedFunc != nil {
+		return m.MarkSyncFailedFunc(ctx, id, reason, reauthRequired)
+	}
+	return nil
+}
+
+func (m *MockIntegrationRepository) DeleteConnection(ctx context.Context, userID string, provider string) error {
+	if m.DeleteConnectionFunc != nil {
+		return m.DeleteConnectionFunc(ctx, userID, provider)
+	}
+	return nil
+}
+
+func (m *MockIntegrationRepository) UpsertSessions(ctx context.Context, userID string, source string, sessions []*models.ExternalSession) (int, int, error) {
+	if m.UpsertSessionsFunc != nil {
+		return m.UpsertSessionsFunc(ctx, userID, source, sessions)
+	}
+	return len(sessions), 0, nil
+}
+
+func (m *MockIntegrationRepository) FindConnectionByID(ctx context.Context, id string) (*models.IntegrationConnection, error) {
+	if m.FindConnectionByIDFunc != nil {
+		return m.FindConnectionByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockIntegrationRepository) FindConnectionByAccount(ctx context.Context, provider string, accountID string) (*models.IntegrationConnection, error) {
+	if m.FindConnectionByAccountFunc != nil {
+		return m.FindConnectionByAccountFunc(ctx, provider, accountID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockIntegrationRepository) UpdateSettings(ctx context.Context, conn *models.IntegrationConnection) error {
+	if m.UpdateSettingsFunc != nil {
+		return m.UpdateSettingsFunc(ctx, conn)
+	}
+	return nil
+}
+
+func (m *MockIntegrationRepository) DeleteExternalSession(ctx context.Context, userID string, source string, externalID string) (bool, error) {
+	if m.DeleteExternalSessionFunc != nil {
+		return m.DeleteExternalSessionFunc(ctx, userID, source, externalID)
+	}
+	return true, nil
+}
+
+func (m *MockIntegrationRepository) FindPushSession(ctx context.Context, sessionID string) (*models.PushSession, error) {
+	if m.FindPushSessionFunc != nil {
+		return m.FindPushSessionFunc(ctx, sessionID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockIntegrationRepository) IsPushedActivity(ctx context.Context, connectionID string, activityID string) (bool, error) {
+	if m.IsPushedActivityFunc != nil {
+		return m.IsPushedActivityFunc(ctx, connectionID, activityID)
+	}
+	return false, nil
+}
+
+func (m *MockIntegrationRepository) EnqueuePushes(ctx context.Context, conn *models.IntegrationConnection) (int64, error) {
+	if m.EnqueuePushesFunc != nil {
+		return m.EnqueuePushesFunc(ctx, conn)
+	}
+	return 0, nil
+}
+
+func (m *MockIntegrationRepository) RecordPush(ctx context.Context, connectionID string, sessionID string, activityID string) error {
+	if m.RecordPushFunc != nil {
+		return m.RecordPushFunc(ctx, connectionID, sessionID, activityID)
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// JobRepository defines the interface for the background job queue
+type JobRepository interface {
+	Enqueue(ctx context.Context, job *models.Job) error
+	Claim(ctx context.Context, kinds []string, lease time.Duration, limit int) ([]*models.Job, error)
+	Complete(ctx context.Context, id string) error
+	Fail(ctx context.Context, id string, reason string, retryAt *time.Time) error
+	Requeue(ctx context.Context, id string) (*models.Job, error)
+	FindByID(ctx context.Context, id string) (*models.Job, error)
+	List(ctx context.Context, filter models.JobFilter) ([]*models.Job, error)
+	Counts(ctx context.Context) ([]*models.JobCount, error)
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PostgresJobRepository is the PostgreSQL implementation of JobRepository
+type PostgresJobRepository struct {
+	db DB
+}
+
+// NewPostgresJobRepository creates a new PostgreSQL job repository
+func NewPostgresJobRepository(db DB) JobRepository {
+	return &PostgresJobRepository{db: db}
+}
+
+const jobColumns = `id, user_id, kind, payload, status, attempts, max_attempts, run_at, last_error, finished_at, created_at, updated_at`
+
+// Enqueue queues a job to run now
+func (r *PostgresJobRepository) Enqueue(ctx context.Context, job *models.Job) error {
+	query := `
+		INSERT INTO jobs (user_id, kind, payload, max_attempts)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + jobColumns
+
+	row := r.db.QueryRow(ctx, query, job.UserID, job.Kind, job.Payload, job.MaxAttempts)
+	return scanJobInto(row, job)
+}
+
+// Claim leases up to limit due jobs of the given kinds, oldest first, and counts the
+// attempt. Running jobs whose lease ran out (their worker died) are claimed again.
+func (r *PostgresJobRepository) Claim(ctx context.Context, kinds []string, lease time.Duration, limit int) ([]*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'running', attempts = attempts + 1, run_at = NOW() + make_interval(secs => $2)
+		WHERE id IN (
+			SELECT id FROM jobs
+			WHERE status IN ('pending', 'running') AND run_at <= NOW() AND kind = ANY($1)
+			ORDER BY run_at ASC
+			LIMIT $3
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns
+
+	rows, err := r.db.Query(ctx, query, kinds, lease.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
+// Complete marks a job done
+func (r *PostgresJobRepository) Complete(ctx context.Context, id string) error {
+	query := `UPDATE jobs SET status = 'done', last_error = NULL, finished_at = NOW() WHERE id = $1`
+
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// Fail records a failed attempt and schedules a retry at retryAt, or dead-letters the job
+// when retryAt is nil
+func (r *PostgresJobRepository) Fail(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE jobs
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'dead' ELSE 'pending' END,
+		    run_at = COALESCE($3, run_at),
+		    finished_at = CASE WHEN $3::timestamptz IS NULL THEN NOW() END,
+		    last_error = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, reason, retryAt)
+	return err
+}
+
+// Requeue queues a dead job again with a fresh set of attempts
+// Returns pgx.ErrNoRows if the job does not exist or is not dead.
+func (r *PostgresJobRepository) Requeue(ctx context.Context, id string) (*models.Job, error) {
+	query := `
+		UPDATE jobs
+		SET status = 'pending', attempts = 0, run_at = NOW(), finished_at = NULL
+		WHERE id = $1 AND status = 'dead'
+		RETURNING ` + jobColumns
+
+	job := &models.Job{}
+	if err := scanJobInto(r.db.QueryRow(ctx, query, id), job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// FindByID retrieves a job by ID
+func (r *PostgresJobRepository) FindByID(ctx context.Context, id string) (*models.Job, error) {
+	query := `SELECT ` + jobColumns + ` FROM jobs WHERE id = $1`
+
+	job := &models.Job{}
+	if err := scanJobInto(r.db.QueryRow(ctx, query, id), job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// List retrieves the jobs matching the filter, newest first
+func (r *PostgresJobRepository) List(ctx context.Context, filter models.JobFilter) ([]*models.Job, error) {
+	query := `
+		SELECT ` + jobColumns + `
+		FROM jobs
+		WHERE ($1 = '' OR user_id = $1::uuid)
+		  AND ($2 = '' OR kind = $2)
+		  AND ($3 = '' OR status = $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, filter.UserID, filter.Kind, filter.Status, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanJobs(rows)
+}
+
+// Counts counts the jobs of each kind and status
+func (r *PostgresJobRepository) Counts(ctx context.Context) ([]*models.JobCount, error) {
+	query := `
+		SELECT kind, status, COUNT(*), MIN(run_at) FILTER (WHERE status = 'pending')
+		FROM jobs
+		GROUP BY kind, status
+		ORDER BY kind, status
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := []*models.JobCount{}
+	for rows.Next() {
+		c := &models.JobCount{}
+		if err := rows.Scan(&c.Kind, &c.Status, &c.Count, &c.OldestRunAt); err != nil {
+			return nil, err
+		}
+		counts = append(counts, c)
+	}
+
+	return counts, rows.Err()
+}
+
+// Prune deletes finished jobs queued before the cutoff; their dedupe keys go with them,
+// so the cutoff must be older than anything still being scheduled
+func (r *PostgresJobRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM jobs WHERE created_at < $1 AND status IN ('done', 'dead')`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+func scanJobs(rows pgx.Rows) ([]*models.Job, error) {
+	jobs := []*models.Job{}
+	for rows.Next() {
+		job := &models.Job{}
+		if err := scanJobInto(rows, job); err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+
+	return jobs, rows.Err()
+}
+
+func scanJobInto(row pgx.Row, job *models.Job) error {
+	return row.Scan(&job.ID, &job.UserID, &job.Kind, &job.Payload, &job.Status, &job.Attempts, &job.MaxAttempts,
+		&job.RunAt, &job.LastError, &job.FinishedAt, &job.CreatedAt, &job.UpdatedAt)
+}
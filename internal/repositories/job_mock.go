@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockJobRepository is a mock implementation for testing
+type MockJobRepository struct {
+	EnqueueFunc  func(ctx context.Context, job *models.Job) error
+	ClaimFunc    func(ctx context.Context, kinds []string, lease time.Duration, limit int) ([]*models.Job, error)
+	CompleteFunc func(ctx context.Context, id string) error
+	FailFunc     func(ctx context.Context, id string, reason string, retryAt *time.Time) error
+	RequeueFunc  func(ctx context.Context, id string) (*models.Job, error)
+	FindByIDFunc func(ctx context.Context, id string) (*models.Job, error)
+	ListFunc     func(ctx context.Context, filter models.JobFilter) ([]*models.Job, error)
+	CountsFunc   func(ctx context.Context) ([]*models.JobCount, error)
+	PruneFunc    func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockJobRepository) Enqueue(ctx context.Context, job *models.Job) error {
+	if m.EnqueueFunc != nil {
+		return m.EnqueueFunc(ctx, job)
+	}
+	job.ID = "mock-job-id"
+	job.Status = models.JobStatusPending
+	job.RunAt = time.Now()
+	job.CreatedAt = job.RunAt
+	job.UpdatedAt = job.RunAt
+	return nil
+}
+
+func (m *MockJobRepository) Claim(ctx context.Context, kinds []string, lease time.Duration, limit int) ([]*models.Job, error) {
+	if m.ClaimFunc != nil {
+		return m.ClaimFunc(ctx, kinds, lease, limit)
+	}
+	return []*models.Job{}, nil
+}
+
+func (m *MockJobRepository) Complete(ctx context.Context, id string) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockJobRepository) Fail(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+	if m.FailFunc != nil {
+		return m.FailFunc(ctx, id, reason, retryAt)
+	}
+	return nil
+}
+
+func (m *MockJobRepository) Requeue(ctx context.Context, id string) (*models.Job, error) {
+	if m.RequeueFunc != nil {
+		return m.RequeueFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockJobRepository) FindByID(ctx context.Context, id string) (*models.Job, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockJobRepository) List(ctx context.Context, filter models.JobFilter) ([]*models.Job, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter)
+	}
+	return []*models.Job{}, nil
+}
+
+func (m *MockJobRepository) Counts(ctx context.Context) ([]*models.JobCount, error) {
+	if m.CountsFunc != nil {
+		return m.CountsFunc(ctx)
+	}
+	return []*models.JobCount{}, nil
+}
+
+func (m *MockJobRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	if m.PruneFunc != nil {
+		return m.PruneFunc(ctx, before)
+	}
+	return 0, nil
+}
@@ -0,0 +1,39 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// listen holds a pooled connection subscribed to a notification channel and calls handle
+// with every payload until ctx is cancelled or the connection fails.
+// Notifications are only delivered while listening, so any sent during a reconnect are lost.
+func listen(ctx context.Context, db *pgxpool.Pool, channel string, handle func(payload string)) error {
+	conn, err := db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		// Stop listening before the connection goes back to the pool
+		unlistenCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if _, err := conn.Exec(unlistenCtx, "UNLISTEN *"); err != nil {
+			conn.Conn().Close(unlistenCtx)
+		}
+		conn.Release()
+	}()
+
+	if _, err := conn.Exec(ctx, "LISTEN "+channel); err != nil {
+		return err
+	}
+
+	for {
+		notification, err := conn.Conn().WaitForNotification(ctx)
+		if err != nil {
+			return err
+		}
+		handle(notification.Payload)
+	}
+}
@@ -0,0 +1,99 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MeasurementRepository defines the interface for body measurement data access
+type MeasurementRepository interface {
+	FindByUser(ctx context.Context, userID string) ([]*models.BodyMeasurement, error)
+	CreateMany(ctx context.Context, measurements []*models.BodyMeasurement) (int64, error)
+}
+
+// PostgresMeasurementRepository is the PostgreSQL implementation of MeasurementRepository
+type PostgresMeasurementRepository struct {
+	db DB
+}
+
+// NewPostgresMeasurementRepository creates a new PostgreSQL measurement repository
+func NewPostgresMeasurementRepository(db DB) MeasurementRepository {
+	return &PostgresMeasurementRepository{db: db}
+}
+
+// FindByUser retrieves the user's measurements, newest first
+func (r *PostgresMeasurementRepository) FindByUser(ctx context.Context, userID string) ([]*models.BodyMeasurement, error) {
+	query := `
+		SELECT id, user_id, measured_at, weight_kg, body_fat_percentage, muscle_mass_kg, source, created_at, updated_at
+		FROM body_measurements
+		WHERE user_id = $1
+		ORDER BY measured_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var measurements []*models.BodyMeasurement
+	for rows.Next() {
+		m := &models.BodyMeasurement{}
+		err := rows.Scan(
+			&m.ID,
+			&m.UserID,
+			&m.MeasuredAt,
+			&m.WeightKg,
+			&m.BodyFatPercentage,
+			&m.MuscleMassKg,
+			&m.Source,
+			&m.CreatedAt,
+			&m.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		measurements = append(measurements, m)
+	}
+
+	return measurements, rows.Err()
+}
+
+// CreateMany inserts measurements in one transaction, skipping timestamps the user
+// already has, and returns how many rows were inserted
+func (r *PostgresMeasurementRepository) CreateMany(ctx context.Context, measurements []*models.BodyMeasurement) (int64, error) {
+	query := `
+		INSERT INTO body_measurements (user_id, measured_at, weight_kg, body_fat_percentage, muscle_mass_kg, source)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		ON CONFLICT (user_id, measured_at) DO NOTHING
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, m := range measurements {
+		batch.Queue(query, m.UserID, m.MeasuredAt, m.WeightKg, m.BodyFatPercentage, m.MuscleMassKg, m.Source)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	var inserted int64
+	for range measurements {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, err
+		}
+		inserted += tag.RowsAffected()
+	}
+	if err := results.Close(); err != nil {
+		return 0, err
+	}
+
+	return inserted, tx.Commit(ctx)
+}
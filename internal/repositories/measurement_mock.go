@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockMeasurementRepository is a mock implementation for testing
+type MockMeasurementRepository struct {
+	FindByUserFunc func(ctx context.Context, userID string) ([]*models.BodyMeasurement, error)
+	CreateManyFunc func(ctx context.Context, measurements []*models.BodyMeasurement) (int64, error)
+}
+
+func (m *MockMeasurementRepository) FindByUser(ctx context.Context, userID string) ([]*models.BodyMeasurement, error) {
+	if m.FindByUserFunc != nil {
+		return m.FindByUserFunc(ctx, userID)
+	}
+	return []*models.BodyMeasurement{}, nil
+}
+
+func (m *MockMeasurementRepository) CreateMany(ctx context.Context, measurements []*models.BodyMeasurement) (int64, error) {
+	if m.CreateManyFunc != nil {
+		return m.CreateManyFunc(ctx, measurements)
+	}
+	return int64(len(measurements)), nil
+}
@@ -0,0 +1,258 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ModerationRepository defines the interface for reports of public library content and
+// the admin edits that resolve them
+type ModerationRepository interface {
+	CreateReport(ctx context.Context, report *models.ContentReport) error
+	Queue(ctx context.Context, limit int) ([]*models.ReportedContent, error)
+	Resolve(ctx context.Context, resourceType string, resourceID string, resolution string, adminID string) (int64, error)
+	UpdateExercise(ctx context.Context, exercise *models.Exercise, adminID string) (int64, error)
+	UpdateWorkout(ctx context.Context, workout *models.Workout, adminID string) (int64, error)
+	Unpublish(ctx context.Context, resourceType string, resourceID string, adminID string) (int64, error)
+}
+
+// PostgresModerationRepository is the PostgreSQL implementation of ModerationRepository
+type PostgresModerationRepository struct {
+	db DB
+}
+
+// NewPostgresModerationRepository creates a new PostgreSQL moderation repository
+func NewPostgresModerationRepository(db DB) ModerationRepository {
+	return &PostgresModerationRepository{db: db}
+}
+
+const contentReportColumns = `id, resource_type, resource_id, user_id, reason, details, resolution, resolved_at, created_at`
+
+// unpublishQueries take each kind of resource out of the public library, leaving it to its
+// owner; they match nothing if it isn't in the library
+var unpublishQueries = map[string]string{
+	models.ReportResourceExercise: `UPDATE exercises SET is_public = FALSE WHERE id = $1 AND is_public`,
+	models.ReportResourceWorkout:  `UPDATE workouts SET organization_id = NULL WHERE id = $1 AND organization_id IS NOT NULL`,
+}
+
+func scanContentReport(row pgx.Row) (*models.ContentReport, error) {
+	report := &models.ContentReport{}
+	err := row.Scan(
+		&report.ID,
+		&report.ResourceType,
+		&report.ResourceID,
+		&report.UserID,
+		&report.Reason,
+		&report.Details,
+		&report.Resolution,
+		&report.ResolvedAt,
+		&report.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// CreateReport stores an open report and sets its ID and creation time
+// Returns ErrDuplicate if the user already has an open report of the resource.
+func (r *PostgresModerationRepository) CreateReport(ctx context.Context, report *models.ContentReport) error {
+	query := `
+		INSERT INTO content_reports (resource_type, resource_id, user_id, reason, details)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRow(ctx, query, report.ResourceType, report.ResourceID, report.UserID, report.Reason, report.Details).
+		Scan(&report.ID, &report.CreatedAt)
+	return translateError(err)
+}
+
+// Queue retrieves up to limit resources with open reports, most reported first and then
+// longest waiting, each with its open reports
+func (r *PostgresModerationRepository) Queue(ctx context.Context, limit int) ([]*models.ReportedContent, error) {
+	query := `
+		SELECT r.resource_type, r.resource_id,
+		       COALESCE(e.name, w.name, ''), COALESCE(e.user_id, w.user_id)::text, w.organization_id,
+		       COUNT(*), MIN(r.created_at), MAX(r.created_at)
+		FROM content_reports r
+		LEFT JOIN exercises e ON r.resource_type = 'exercise' AND e.id = r.resource_id
+		LEFT JOIN workouts w ON r.resource_type = 'workout' AND w.id = r.resource_id
+		WHERE r.resolved_at IS NULL
+		GROUP BY r.resource_type, r.resource_id, e.name, e.user_id, w.name, w.user_id, w.organization_id
+		ORDER BY COUNT(*) DESC, MIN(r.created_at), r.resource_id
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	queue := []*models.ReportedContent{}
+	byResource := make(map[string]*models.ReportedContent)
+	var ids []string
+	for rows.Next() {
+		item := &models.ReportedContent{Reports: []*models.ContentReport{}}
+		err := rows.Scan(
+			&item.ResourceType,
+			&item.ResourceID,
+			&item.Name,
+			&item.OwnerID,
+			&item.OrganizationID,
+			&item.ReportCount,
+			&item.FirstReportedAt,
+			&item.LastReportedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, item)
+		byResource[item.ResourceType+":"+item.ResourceID] = item
+		ids = append(ids, item.ResourceID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(queue) == 0 {
+		return queue, nil
+	}
+
+	reportsQuery := `
+		SELECT ` + contentReportColumns + `
+		FROM content_reports
+		WHERE resolved_at IS NULL AND resource_id = ANY($1)
+		ORDER BY created_at, id
+	`
+	reportRows, err := r.db.Query(ctx, reportsQuery, ids)
+	if err != nil {
+		return nil, err
+	}
+	defer reportRows.Close()
+
+	for reportRows.Next() {
+		report, err := scanContentReport(reportRows)
+		if err != nil {
+			return nil, err
+		}
+		if item, ok := byResource[report.ResourceType+":"+report.ResourceID]; ok {
+			item.Reports = append(item.Reports, report)
+		}
+	}
+
+	return queue, reportRows.Err()
+}
+
+// Resolve closes a resource's open reports with a resolution and returns how many it closed;
+// an empty adminID (a service token) leaves the resolver unset
+func (r *PostgresModerationRepository) Resolve(ctx context.Context, resourceType string, resourceID string, resolution string, adminID string) (int64, error) {
+	return resolveReports(ctx, r.db, resourceType, resourceID, resolution, adminID)
+}
+
+// UpdateExercise saves an admin's edit of a public exercise and resolves its open reports
+// as edited, in one transaction
+// Returns pgx.ErrNoRows if the exercise was updated since the version it carries, or left
+// the library.
+func (r *PostgresModerationRepository) UpdateExercise(ctx context.Context, exercise *models.Exercise, adminID string) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE exercises
+		SET name = $1, description = $2, muscle_group = $3, updated_at = NOW()
+		WHERE id = $4 AND version = $5 AND is_public
+		RETURNING updated_at, version
+	`
+	err = tx.QueryRow(ctx, query, exercise.Name, exercise.Description, exercise.MuscleGroup, exercise.ID, exercise.Version).
+		Scan(&exercise.UpdatedAt, &exercise.Version)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	resolved, err := resolveReports(ctx, tx, models.ReportResourceExercise, exercise.ID, models.ReportResolutionEdited, adminID)
+	if err != nil {
+		return 0, err
+	}
+	return resolved, tx.Commit(ctx)
+}
+
+// UpdateWorkout saves an admin's edit of a shared workout and resolves its open reports as
+// edited, in one transaction
+// Returns pgx.ErrNoRows if the workout was updated since the version it carries, or is no
+// longer shared.
+func (r *PostgresModerationRepository) UpdateWorkout(ctx context.Context, workout *models.Workout, adminID string) (int64, error) {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		UPDATE workouts
+		SET name = $1, description = $2, updated_at = NOW()
+		WHERE id = $3 AND version = $4 AND organization_id IS NOT NULL
+		RETURNING updated_at, version
+	`
+	err = tx.QueryRow(ctx, query, workout.Name, workout.Description, workout.ID, workout.Version).
+		Scan(&workout.UpdatedAt, &workout.Version)
+	if err != nil {
+		return 0, translateError(err)
+	}
+
+	resolved, err := resolveReports(ctx, tx, models.ReportResourceWorkout, workout.ID, models.ReportResolutionEdited, adminID)
+	if err != nil {
+		return 0, err
+	}
+	return resolved, tx.Commit(ctx)
+}
+
+// Unpublish takes a public exercise or shared workout out of the library, leaving it private
+// to its owner, and resolves its open reports as unpublished, in one transaction
+// Returns pgx.ErrNoRows if it isn't in the library.
+func (r *PostgresModerationRepository) Unpublish(ctx context.Context, resourceType string, resourceID string, adminID string) (int64, error) {
+	query, ok := unpublishQueries[resourceType]
+	if !ok {
+		return 0, pgx.ErrNoRows
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, query, resourceID)
+	if err != nil {
+		return 0, err
+	}
+	if tag.RowsAffected() == 0 {
+		return 0, pgx.ErrNoRows
+	}
+
+	resolved, err := resolveReports(ctx, tx, resourceType, resourceID, models.ReportResolutionUnpublished, adminID)
+	if err != nil {
+		return 0, err
+	}
+	return resolved, tx.Commit(ctx)
+}
+
+// resolveReports closes a resource's open reports on the pool or within a transaction
+func resolveReports(ctx context.Context, db DB, resourceType string, resourceID string, resolution string, adminID string) (int64, error) {
+	query := `
+		UPDATE content_reports
+		SET resolution = $3, resolved_by = NULLIF($4, '')::uuid, resolved_at = NOW()
+		WHERE resource_type = $1 AND resource_id = $2 AND resolved_at IS NULL
+	`
+
+	tag, err := db.Exec(ctx, query, resourceType, resourceID, resolution, adminID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
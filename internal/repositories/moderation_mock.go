@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockModerationRepository is a mock implementation for testing
+type MockModerationRepository struct {
+	CreateReportFunc   func(ctx context.Context, report *models.ContentReport) error
+	QueueFunc          func(ctx context.Context, limit int) ([]*models.ReportedContent, error)
+	ResolveFunc        func(ctx context.Context, resourceType string, resourceID string, resolution string, adminID string) (int64, error)
+	UpdateExerciseFunc func(ctx context.Context, exercise *models.Exercise, adminID string) (int64, error)
+	UpdateWorkoutFunc  func(ctx context.Context, workout *models.Workout, adminID string) (int64, error)
+	UnpublishFunc      func(ctx context.Context, resourceType string, resourceID string, adminID string) (int64, error)
+}
+
+func (m *MockModerationRepository) CreateReport(ctx context.Context, report *models.ContentReport) error {
+	if m.CreateReportFunc != nil {
+		return m.CreateReportFunc(ctx, report)
+	}
+	report.ID = "report-1"
+	return nil
+}
+
+func (m *MockModerationRepository) Queue(ctx context.Context, limit int) ([]*models.ReportedContent, error) {
+	if m.QueueFunc != nil {
+		return m.QueueFunc(ctx, limit)
+	}
+	return []*models.ReportedContent{}, nil
+}
+
+func (m *MockModerationRepository) Resolve(ctx context.Context, resourceType string, resourceID string, resolution string, adminID string) (int64, error) {
+	if m.ResolveFunc != nil {
+		return m.ResolveFunc(ctx, resourceType, resourceID, resolution, adminID)
+	}
+	return 0, nil
+}
+
+func (m *MockModerationRepository) UpdateExercise(ctx context.Context, exercise *models.Exercise, adminID string) (int64, error) {
+	if m.UpdateExerciseFunc != nil {
+		return m.UpdateExerciseFunc(ctx, exercise, adminID)
+	}
+	exercise.Version++
+	return 0, nil
+}
+
+func (m *MockModerationRepository) UpdateWorkout(ctx context.Context, workout *models.Workout, adminID string) (int64, error) {
+	if m.UpdateWorkoutFunc != nil {
+		return m.UpdateWorkoutFunc(ctx, workout, adminID)
+	}
+	workout.Version++
+	return 0, nil
+}
+
+func (m *MockModerationRepository) Unpublish(ctx context.Context, resourceType string, resourceID string, adminID string) (int64, error) {
+	if m.UnpublishFunc != nil {
+		return m.UnpublishFunc(ctx, resourceType, resourceID, adminID)
+	}
+	return 0, nil
+}
@@ -0,0 +1,156 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// NotificationRepository defines the interface for users' in-app notifications
+type NotificationRepository interface {
+	List(ctx context.Context, userID string, filter models.NotificationFilter) ([]*models.Notification, error)
+	UnreadCount(ctx context.Context, userID string) (int, error)
+	FindByID(ctx context.Context, id string) (*models.Notification, error)
+	MarkRead(ctx context.Context, id string) (*time.Time, error)
+	MarkAllRead(ctx context.Context, userID string) (int64, error)
+	Prune(ctx context.Context, readBefore time.Time) (int64, error)
+	GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, prefs *models.NotificationPreferences) error
+}
+
+// PostgresNotificationRepository is the PostgreSQL implementation of NotificationRepository
+type PostgresNotificationRepository struct {
+	db DB
+}
+
+// NewPostgresNotificationRepository creates a new PostgreSQL notification repository
+func NewPostgresNotificationRepository(db DB) NotificationRepository {
+	return &PostgresNotificationRepository{db: db}
+}
+
+const notificationColumns = `id, user_id, type, title, body, data, read_at, created_at`
+
+// List retrieves a page of the user's notifications, newest first
+func (r *PostgresNotificationRepository) List(ctx context.Context, userID string, filter models.NotificationFilter) ([]*models.Notification, error) {
+	query := `
+		SELECT ` + notificationColumns + `
+		FROM notifications
+		WHERE user_id = $1
+		  AND (NOT $2 OR read_at IS NULL)
+		  AND ($3::timestamptz IS NULL OR created_at < $3)
+		ORDER BY created_at DESC
+		LIMIT $4
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, filter.UnreadOnly, filter.Before, filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*models.Notification{}
+	for rows.Next() {
+		n, err := scanNotification(rows)
+		if err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// UnreadCount counts the user's unread notifications
+func (r *PostgresNotificationRepository) UnreadCount(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+// FindByID retrieves a notification by ID
+func (r *PostgresNotificationRepository) FindByID(ctx context.Context, id string) (*models.Notification, error) {
+	query := `SELECT ` + notificationColumns + ` FROM notifications WHERE id = $1`
+	return scanNotification(r.db.QueryRow(ctx, query, id))
+}
+
+func scanNotification(row pgx.Row) (*models.Notification, error) {
+	n := &models.Notification{}
+	if err := row.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Body, &n.Data, &n.ReadAt, &n.CreatedAt); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// MarkRead marks a notification read, keeping the time it was first read, and returns
+// that time
+// Returns pgx.ErrNoRows if the notification does not exist.
+func (r *PostgresNotificationRepository) MarkRead(ctx context.Context, id string) (*time.Time, error) {
+	query := `UPDATE notifications SET read_at = COALESCE(read_at, NOW()) WHERE id = $1 RETURNING read_at`
+
+	var readAt time.Time
+	if err := r.db.QueryRow(ctx, query, id).Scan(&readAt); err != nil {
+		return nil, err
+	}
+	return &readAt, nil
+}
+
+// MarkAllRead marks every unread notification of the user read
+func (r *PostgresNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	tag, err := r.db.Exec(ctx, `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`, userID)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// Prune deletes notifications read before the cutoff
+func (r *PostgresNotificationRepository) Prune(ctx context.Context, readBefore time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM notifications WHERE read_at < $1`, readBefore)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// GetPreferences retrieves the user's notification preferences; users who never saved any
+// get every notification on every channel, at any time
+func (r *PostgresNotificationRepository) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	query := `SELECT user_id, channels, quiet_start, quiet_end, timezone, updated_at FROM notification_preferences WHERE user_id = $1`
+
+	prefs := &models.NotificationPreferences{}
+	var quietStart, quietEnd *string
+	var timezone string
+	err := r.db.QueryRow(ctx, query, userID).Scan(&prefs.UserID, &prefs.Channels, &quietStart, &quietEnd, &timezone, &prefs.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.NotificationPreferences{UserID: userID, Channels: map[string][]string{}}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if quietStart != nil && quietEnd != nil {
+		prefs.QuietHours = &models.QuietHours{Start: *quietStart, End: *quietEnd, Timezone: timezone}
+	}
+	return prefs, nil
+}
+
+// SavePreferences stores the user's notification preferences and sets their updated_at
+func (r *PostgresNotificationRepository) SavePreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	query := `
+		INSERT INTO notification_preferences (user_id, channels, quiet_start, quiet_end, timezone)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id) DO UPDATE
+		SET channels = EXCLUDED.channels, quiet_start = EXCLUDED.quiet_start,
+		    quiet_end = EXCLUDED.quiet_end, timezone = EXCLUDED.timezone
+		RETURNING updated_at
+	`
+
+	var quietStart, quietEnd *string
+	timezone := "UTC"
+	if q := prefs.QuietHours; q != nil {
+		quietStart, quietEnd, timezone = &q.Start, &q.End, q.Timezone
+	}
+	return r.db.QueryRow(ctx, query, prefs.UserID, prefs.Channels, quietStart, quietEnd, timezone).Scan(&prefs.UpdatedAt)
+}
@@ -0,0 +1,80 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockNotificationRepository is a mock implementation for testing
+type MockNotificationRepository struct {
+	ListFunc        func(ctx context.Context, userID string, filter models.NotificationFilter) ([]*models.Notification, error)
+	UnreadCountFunc func(ctx context.Context, userID string) (int, error)
+	FindByIDFunc    func(ctx context.Context, id string) (*models.Notification, error)
+	MarkReadFunc    func(ctx context.Context, id string) (*time.Time, error)
+	MarkAllReadFunc func(ctx context.Context, userID string) (int64, error)
+	PruneFunc       func(ctx context.Context, readBefore time.Time) (int64, error)
+
+	GetPreferencesFunc  func(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	SavePreferencesFunc func(ctx context.Context, prefs *models.NotificationPreferences) error
+}
+
+func (m *MockNotificationRepository) List(ctx context.Context, userID string, filter models.NotificationFilter) ([]*models.Notification, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, userID, filter)
+	}
+	return []*models.Notification{}, nil
+}
+
+func (m *MockNotificationRepository) UnreadCount(ctx context.Context, userID string) (int, error) {
+	if m.UnreadCountFunc != nil {
+		return m.UnreadCountFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationRepository) FindByID(ctx context.Context, id string) (*models.Notification, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockNotificationRepository) MarkRead(ctx context.Context, id string) (*time.Time, error) {
+	if m.MarkReadFunc != nil {
+		return m.MarkReadFunc(ctx, id)
+	}
+	now := time.Now()
+	return &now, nil
+}
+
+func (m *MockNotificationRepository) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	if m.MarkAllReadFunc != nil {
+		return m.MarkAllReadFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationRepository) Prune(ctx context.Context, readBefore time.Time) (int64, error) {
+	if m.PruneFunc != nil {
+		return m.PruneFunc(ctx, readBefore)
+	}
+	return 0, nil
+}
+
+func (m *MockNotificationRepository) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	if m.GetPreferencesFunc != nil {
+		return m.GetPreferencesFunc(ctx, userID)
+	}
+	return &models.NotificationPreferences{UserID: userID, Channels: map[string][]string{}}, nil
+}
+
+func (m *MockNotificationRepository) SavePreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	if m.SavePreferencesFunc != nil {
+		return m.SavePreferencesFunc(ctx, prefs)
+	}
+	prefs.UpdatedAt = time.Now()
+	return nil
+}
@@ -0,0 +1,223 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/google/uuid"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// OrganizationRepository defines the interface for organization and membership data access
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *models.Organization) error
+	FindByID(ctx context.Context, id string) (*models.Organization, error)
+	FindByMember(ctx context.Context, userID string) ([]*models.Organization, error)
+	FindMember(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error)
+	FindMembers(ctx context.Context, orgID string) ([]*models.OrganizationMember, error)
+	AddMember(ctx context.Context, member *models.OrganizationMember) error
+	UpdateMember(ctx context.Context, member *models.OrganizationMember) error
+	RemoveMember(ctx context.Context, orgID, userID string) error
+}
+
+// PostgresOrganizationRepository is the PostgreSQL implementation of OrganizationRepository
+type PostgresOrganizationRepository struct {
+	db DB
+}
+
+// NewPostgresOrganizationRepository creates a new PostgreSQL organization repository
+func NewPostgresOrganizationRepository(db DB) OrganizationRepository {
+	return &PostgresOrganizationRepository{db: db}
+}
+
+// Create inserts a new organization and makes its creator the owner, atomically
+func (r *PostgresOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	org.ID = uuid.New().String()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	query := `
+		INSERT INTO organizations (id, name, description, created_by, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	err = tx.QueryRow(
+		ctx,
+		query,
+		org.ID,
+		org.Name,
+		org.Description,
+		org.CreatedBy,
+	).Scan(&org.CreatedAt, &org.UpdatedAt)
+	if err != nil {
+		return err
+	}
+
+	memberQuery := `
+		INSERT INTO organization_members (organization_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+	`
+	if _, err := tx.Exec(ctx, memberQuery, org.ID, org.CreatedBy, models.OrgRoleOwner); err != nil {
+		return fmt.Errorf("failed to add owner membership: %w", err)
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindByID retrieves a single organization by ID
+func (r *PostgresOrganizationRepository) FindByID(ctx context.Context, id string) (*models.Organization, error) {
+	query := `
+		SELECT id, name, COALESCE(description, ''), created_by, created_at, updated_at
+		FROM organizations
+		WHERE id = $1
+	`
+
+	org := &models.Organization{}
+	err := r.db.QueryRow(ctx, query, id).Scan(
+		&org.ID,
+		&org.Name,
+		&org.Description,
+		&org.CreatedBy,
+		&org.CreatedAt,
+		&org.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return org, nil
+}
+
+// FindByMember retrieves every organization a user belongs to
+func (r *PostgresOrganizationRepository) FindByMember(ctx context.Context, userID string) ([]*models.Organization, error) {
+	query := `
+		SELECT o.id, o.name, COALESCE(o.description, ''), o.created_by, o.created_at, o.updated_at
+		FROM organizations o
+		JOIN organization_members m ON m.organization_id = o.id
+		WHERE m.user_id = $1
+		ORDER BY o.name ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var orgs []*models.Organization
+	for rows.Next() {
+		org := &models.Organization{}
+		err := rows.Scan(
+			&org.ID,
+			&org.Name,
+			&org.Description,
+			&org.CreatedBy,
+			&org.CreatedAt,
+			&org.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+
+	return orgs, rows.Err()
+}
+
+// FindMember retrieves a user's membership in an organization
+func (r *PostgresOrganizationRepository) FindMember(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+	query := `
+		SELECT organization_id, user_id, role, created_at, updated_at
+		FROM organization_members
+		WHERE organization_id = $1 AND user_id = $2
+	`
+
+	member := &models.OrganizationMember{}
+	err := r.db.QueryRow(ctx, query, orgID, userID).Scan(
+		&member.OrganizationID,
+		&member.UserID,
+		&member.Role,
+		&member.CreatedAt,
+		&member.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// FindMembers retrieves all members of an organization
+func (r *PostgresOrganizationRepository) FindMembers(ctx context.Context, orgID string) ([]*models.OrganizationMember, error) {
+	query := `
+		SELECT organization_id, user_id, role, created_at, updated_at
+		FROM organization_members
+		WHERE organization_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var members []*models.OrganizationMember
+	for rows.Next() {
+		member := &models.OrganizationMember{}
+		err := rows.Scan(
+			&member.OrganizationID,
+			&member.UserID,
+			&member.Role,
+			&member.CreatedAt,
+			&member.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		members = append(members, member)
+	}
+
+	return members, rows.Err()
+}
+
+// AddMember inserts a new membership
+func (r *PostgresOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	query := `
+		INSERT INTO organization_members (organization_id, user_id, role, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		RETURNING created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		member.OrganizationID,
+		member.UserID,
+		member.Role,
+	).Scan(&member.CreatedAt, &member.UpdatedAt)
+}
+
+// UpdateMember changes a member's role
+func (r *PostgresOrganizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	query := `
+		UPDATE organization_members
+		SET role = $1, updated_at = NOW()
+		WHERE organization_id = $2 AND user_id = $3
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, member.Role, member.OrganizationID, member.UserID).Scan(&member.UpdatedAt)
+}
+
+// RemoveMember deletes a membership
+func (r *PostgresOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID string) error {
+	query := `DELETE FROM organization_members WHERE organization_id = $1 AND user_id = $2`
+	_, err := r.db.Exec(ctx, query, orgID, userID)
+	return err
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockOrganizationRepository is a mock implementation for testing
+type MockOrganizationRepository struct {
+	CreateFunc       func(ctx context.Context, org *models.Organization) error
+	FindByIDFunc     func(ctx context.Context, id string) (*models.Organization, error)
+	FindByMemberFunc func(ctx context.Context, userID string) ([]*models.Organization, error)
+	FindMemberFunc   func(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error)
+	FindMembersFunc  func(ctx context.Context, orgID string) ([]*models.OrganizationMember, error)
+	AddMemberFunc    func(ctx context.Context, member *models.OrganizationMember) error
+	UpdateMemberFunc func(ctx context.Context, member *models.OrganizationMember) error
+	RemoveMemberFunc func(ctx context.Context, orgID, userID string) error
+}
+
+func (m *MockOrganizationRepository) Create(ctx context.Context, org *models.Organization) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, org)
+	}
+	return nil
+}
+
+func (m *MockOrganizationRepository) FindByID(ctx context.Context, id string) (*models.Organization, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockOrganizationRepository) FindByMember(ctx context.Context, userID string) ([]*models.Organization, error) {
+	if m.FindByMemberFunc != nil {
+		return m.FindByMemberFunc(ctx, userID)
+	}
+	return []*models.Organization{}, nil
+}
+
+func (m *MockOrganizationRepository) FindMember(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+	if m.FindMemberFunc != nil {
+		return m.FindMemberFunc(ctx, orgID, userID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockOrganizationRepository) FindMembers(ctx context.Context, orgID string) ([]*models.OrganizationMember, error) {
+	if m.FindMembersFunc != nil {
+		return m.FindMembersFunc(ctx, orgID)
+	}
+	return []*models.OrganizationMember{}, nil
+}
+
+func (m *MockOrganizationRepository) AddMember(ctx context.Context, member *models.OrganizationMember) error {
+	if m.AddMemberFunc != nil {
+		return m.AddMemberFunc(ctx, member)
+	}
+	return nil
+}
+
+func (m *MockOrganizationRepository) UpdateMember(ctx context.Context, member *models.OrganizationMember) error {
+	if m.UpdateMemberFunc != nil {
+		return m.UpdateMemberFunc(ctx, member)
+	}
+	return nil
+}
+
+func (m *MockOrganizationRepository) RemoveMember(ctx context.Context, orgID, userID string) error {
+	if m.RemoveMemberFunc != nil {
+		return m.RemoveMemberFunc(ctx, orgID, userID)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ProfileRepository defines the interface for users' profiles
+type ProfileRepository interface {
+	FindProfile(ctx context.Context, userID string) (*models.Profile, error)
+	SaveProfile(ctx context.Context, profile *models.Profile) error
+}
+
+// PostgresProfileRepository is the PostgreSQL implementation of ProfileRepository
+type PostgresProfileRepository struct {
+	db DB
+}
+
+// NewPostgresProfileRepository creates a new PostgreSQL profile repository
+func NewPostgresProfileRepository(db DB) ProfileRepository {
+	return &PostgresProfileRepository{db: db}
+}
+
+// FindProfile retrieves the user's profile; users who never saved one are in UTC, with a
+// public profile
+func (r *PostgresProfileRepository) FindProfile(ctx context.Context, userID string) (*models.Profile, error) {
+	query := `SELECT user_id, timezone, privacy, updated_at FROM user_profiles WHERE user_id = $1`
+
+	profile := &models.Profile{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(&profile.UserID, &profile.Timezone, &profile.Privacy, &profile.UpdatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &models.Profile{UserID: userID, Timezone: "UTC", Privacy: models.ProfilePrivacyPublic}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return profile, nil
+}
+
+// SaveProfile stores the user's profile and sets its updated_at
+func (r *PostgresProfileRepository) SaveProfile(ctx context.Context, profile *models.Profile) error {
+	query := `
+		INSERT INTO user_profiles (user_id, timezone, privacy)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (user_id) DO UPDATE SET timezone = EXCLUDED.timezone, privacy = EXCLUDED.privacy
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, profile.UserID, profile.Timezone, profile.Privacy).Scan(&profile.UpdatedAt)
+}
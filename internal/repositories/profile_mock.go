@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockProfileRepository is a mock implementation for testing
+type MockProfileRepository struct {
+	FindProfileFunc func(ctx context.Context, userID string) (*models.Profile, error)
+	SaveProfileFunc func(ctx context.Context, profile *models.Profile) error
+}
+
+func (m *MockProfileRepository) FindProfile(ctx context.Context, userID string) (*models.Profile, error) {
+	if m.FindProfileFunc != nil {
+		return m.FindProfileFunc(ctx, userID)
+	}
+	return &models.Profile{UserID: userID, Timezone: "UTC", Privacy: models.ProfilePrivacyPublic}, nil
+}
+
+func (m *MockProfileRepository) SaveProfile(ctx context.Context, profile *models.Profile) error {
+	if m.SaveProfileFunc != nil {
+		return m.SaveProfileFunc(ctx, profile)
+	}
+	profile.UpdatedAt = time.Now()
+	return nil
+}
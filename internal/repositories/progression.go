@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ProgressionRepository defines the interface for the log history progression schemes read
+type ProgressionRepository interface {
+	RecentPerformances(ctx context.Context, userID string, exerciseIDs []string, limit int) (map[string][]*models.ExercisePerformance, error)
+	SessionsSince(ctx context.Context, userID string, exerciseID string, since time.Time) (int, error)
+}
+
+// PostgresProgressionRepository is the PostgreSQL implementation of ProgressionRepository
+type PostgresProgressionRepository struct {
+	db DB
+}
+
+// NewPostgresProgressionRepository creates a new PostgreSQL progression repository
+func NewPostgresProgressionRepository(db DB) ProgressionRepository {
+	return &PostgresProgressionRepository{db: db}
+}
+
+// RecentPerformances retrieves the user's last limit completed sessions of each exercise,
+// most recent first, keyed by exercise ID. Skipped exercises don't count; when a session
+// logged an exercise more than once, its heaviest entry stands for it.
+func (r *PostgresProgressionRepository) RecentPerformances(ctx context.Context, userID string, exerciseIDs []string, limit int) (map[string][]*models.ExercisePerformance, error) {
+	query := `
+		WITH per_session AS (
+			SELECT DISTINCT ON (l.exercise_id, s.id)
+			       l.exercise_id, s.started_at, l.weight_kg::float8 AS weight_kg,
+			       COALESCE(l.sets_completed, 0) AS sets_completed, l.sets_planned,
+			       l.reps_completed, l.reps_planned
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE s.user_id = $1 AND s.status = 'completed'
+			  AND l.exercise_id = ANY($2) AND l.skipped_at IS NULL
+			ORDER BY l.exercise_id, s.id, l.weight_kg DESC NULLS LAST, l.reps_completed DESC NULLS LAST
+		)
+		SELECT exercise_id, started_at, weight_kg, sets_completed, sets_planned, reps_completed, reps_planned
+		FROM (
+			SELECT p.*, ROW_NUMBER() OVER (PARTITION BY p.exercise_id ORDER BY p.started_at DESC) AS n
+			FROM per_session p
+		) ranked
+		WHERE n <= $3
+		ORDER BY exercise_id, started_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, exerciseIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	history := make(map[string][]*models.ExercisePerformance)
+	for rows.Next() {
+		p := &models.ExercisePerformance{}
+		err := rows.Scan(
+			&p.ExerciseID,
+			&p.PerformedAt,
+			&p.WeightKg,
+			&p.SetsCompleted,
+			&p.SetsPlanned,
+			&p.RepsCompleted,
+			&p.RepsPlanned,
+		)
+		if err != nil {
+			return nil, err
+		}
+		history[p.ExerciseID] = append(history[p.ExerciseID], p)
+	}
+
+	return history, rows.Err()
+}
+
+// SessionsSince counts the user's completed sessions that logged the exercise, without
+// skipping it, and started after since
+func (r *PostgresProgressionRepository) SessionsSince(ctx context.Context, userID string, exerciseID string, since time.Time) (int, error) {
+	query := `
+		SELECT COUNT(DISTINCT s.id)
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		WHERE s.user_id = $1 AND s.status = 'completed' AND s.started_at > $3
+		  AND l.exercise_id = $2 AND l.skipped_at IS NULL
+	`
+
+	var n int
+	err := r.db.QueryRow(ctx, query, userID, exerciseID, since).Scan(&n)
+	return n, err
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockProgressionRepository is a mock implementation for testing
+type MockProgressionRepository struct {
+	RecentPerformancesFunc func(ctx context.Context, userID string, exerciseIDs []string, limit int) (map[string][]*models.ExercisePerformance, error)
+	SessionsSinceFunc      func(ctx context.Context, userID string, exerciseID string, since time.Time) (int, error)
+}
+
+func (m *MockProgressionRepository) RecentPerformances(ctx context.Context, userID string, exerciseIDs []string, limit int) (map[string][]*models.ExercisePerformance, error) {
+	if m.RecentPerformancesFunc != nil {
+		return m.RecentPerformancesFunc(ctx, userID, exerciseIDs, limit)
+	}
+	return map[string][]*models.ExercisePerformance{}, nil
+}
+
+func (m *MockProgressionRepository) SessionsSince(ctx context.Context, userID string, exerciseID string, since time.Time) (int, error) {
+	if m.SessionsSinceFunc != nil {
+		return m.SessionsSinceFunc(ctx, userID, exerciseID, since)
+	}
+	return 0, nil
+}
@@ -0,0 +1,176 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// PublicProfileRepository defines the interface for users' public profiles and what they
+// show
+type PublicProfileRepository interface {
+	FindSettings(ctx context.Context, userID string) (*models.PublicProfileSettings, error)
+	FindSettingsByUsername(ctx context.Context, username string) (*models.PublicProfileSettings, error)
+	SaveSettings(ctx context.Context, settings *models.PublicProfileSettings) error
+	RecordHighlights(ctx context.Context, userID string, limit int) ([]*models.RecordHighlight, error)
+	RecentSessions(ctx context.Context, userID string, limit int) ([]*models.PublicSession, error)
+	FollowCounts(ctx context.Context, userID string) (followers int, following int, err error)
+}
+
+// PostgresPublicProfileRepository is the PostgreSQL implementation of PublicProfileRepository
+type PostgresPublicProfileRepository struct {
+	db DB
+}
+
+// NewPostgresPublicProfileRepository creates a new PostgreSQL public profile repository
+func NewPostgresPublicProfileRepository(db DB) PublicProfileRepository {
+	return &PostgresPublicProfileRepository{db: db}
+}
+
+const publicProfileColumns = `user_id, username, display_name, show_display_name, show_records, show_recent_workouts, show_follow_counts, updated_at`
+
+// FindSettings retrieves the user's public profile settings
+// Returns pgx.ErrNoRows if the user hasn't picked a username.
+func (r *PostgresPublicProfileRepository) FindSettings(ctx context.Context, userID string) (*models.PublicProfileSettings, error) {
+	query := `SELECT ` + publicProfileColumns + ` FROM public_profiles WHERE user_id = $1`
+
+	return scanPublicProfileSettings(r.db.QueryRow(ctx, query, userID))
+}
+
+// FindSettingsByUsername retrieves the public profile settings of the user with username
+// Returns pgx.ErrNoRows if nobody has it.
+func (r *PostgresPublicProfileRepository) FindSettingsByUsername(ctx context.Context, username string) (*models.PublicProfileSettings, error) {
+	query := `SELECT ` + publicProfileColumns + ` FROM public_profiles WHERE username = $1`
+
+	return scanPublicProfileSettings(r.db.QueryRow(ctx, query, username))
+}
+
+// SaveSettings stores the user's public profile settings and sets their updated_at
+// Returns ErrDuplicate if another user has the username.
+func (r *PostgresPublicProfileRepository) SaveSettings(ctx context.Context, settings *models.PublicProfileSettings) error {
+	query := `
+		INSERT INTO public_profiles (user_id, username, display_name, show_display_name, show_records, show_recent_workouts, show_follow_counts)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			username = EXCLUDED.username,
+			display_name = EXCLUDED.display_name,
+			show_display_name = EXCLUDED.show_display_name,
+			show_records = EXCLUDED.show_records,
+			show_recent_workouts = EXCLUDED.show_recent_workouts,
+			show_follow_counts = EXCLUDED.show_follow_counts
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query,
+		settings.UserID,
+		settings.Username,
+		settings.DisplayName,
+		settings.ShowDisplayName,
+		settings.ShowRecords,
+		settings.ShowRecentWorkouts,
+		settings.ShowFollowCounts,
+	).Scan(&settings.UpdatedAt)
+	return translateError(err)
+}
+
+// RecordHighlights retrieves the user's latest personal record on each exercise, most
+// recent first
+func (r *PostgresPublicProfileRepository) RecordHighlights(ctx context.Context, userID string, limit int) ([]*models.RecordHighlight, error) {
+	query := `
+		SELECT * FROM (
+			SELECT DISTINCT ON (l.exercise_id) l.exercise_id, e.name, l.weight_kg::float8, l.reps_completed, s.started_at
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			JOIN exercises e ON e.id = l.exercise_id
+			WHERE s.user_id = $1 AND l.is_personal_record AND l.weight_kg IS NOT NULL
+			ORDER BY l.exercise_id, s.started_at DESC
+		) latest
+		ORDER BY started_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []*models.RecordHighlight
+	for rows.Next() {
+		h := &models.RecordHighlight{}
+		if err := rows.Scan(&h.ExerciseID, &h.ExerciseName, &h.WeightKg, &h.Reps, &h.AchievedAt); err != nil {
+			return nil, err
+		}
+		records = append(records, h)
+	}
+
+	return records, rows.Err()
+}
+
+// RecentSessions retrieves the user's latest completed sessions, newest first
+func (r *PostgresPublicProfileRepository) RecentSessions(ctx context.Context, userID string, limit int) ([]*models.PublicSession, error) {
+	query := `
+		SELECT id, name, session_type, duration_minutes, started_at, completed_at
+		FROM workout_sessions
+		WHERE user_id = $1 AND status = 'completed'
+		ORDER BY started_at DESC, id DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*models.PublicSession
+	for rows.Next() {
+		s := &models.PublicSession{}
+		err := rows.Scan(
+			&s.ID,
+			&s.Name,
+			&s.SessionType,
+			&s.DurationMinutes,
+			&s.StartedAt,
+			&s.CompletedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, s)
+	}
+
+	return sessions, rows.Err()
+}
+
+// FollowCounts counts the user's accepted followers and the users they follow
+func (r *PostgresPublicProfileRepository) FollowCounts(ctx context.Context, userID string) (int, int, error) {
+	query := `
+		SELECT
+			(SELECT COUNT(*) FROM user_follows WHERE followee_id = $1 AND status = 'accepted'),
+			(SELECT COUNT(*) FROM user_follows WHERE follower_id = $1 AND status = 'accepted')
+	`
+
+	var followers, following int
+	err := r.db.QueryRow(ctx, query, userID).Scan(&followers, &following)
+	return followers, following, err
+}
+
+func scanPublicProfileSettings(row pgx.Row) (*models.PublicProfileSettings, error) {
+	settings := &models.PublicProfileSettings{}
+	err := row.Scan(
+		&settings.UserID,
+		&settings.Username,
+		&settings.DisplayName,
+		&settings.ShowDisplayName,
+		&settings.ShowRecords,
+		&settings.ShowRecentWorkouts,
+		&settings.ShowFollowCounts,
+		&settings.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
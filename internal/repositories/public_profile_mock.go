@@ -0,0 +1,62 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockPublicProfileRepository is a mock implementation for testing
+type MockPublicProfileRepository struct {
+	FindSettingsFunc           func(ctx context.Context, userID string) (*models.PublicProfileSettings, error)
+	FindSettingsByUsernameFunc func(ctx context.Context, username string) (*models.PublicProfileSettings, error)
+	SaveSettingsFunc           func(ctx context.Context, settings *models.PublicProfileSettings) error
+	RecordHighlightsFunc       func(ctx context.Context, userID string, limit int) ([]*models.RecordHighlight, error)
+	RecentSessionsFunc         func(ctx context.Context, userID string, limit int) ([]*models.PublicSession, error)
+	FollowCountsFunc           func(ctx context.Context, userID string) (int, int, error)
+}
+
+func (m *MockPublicProfileRepository) FindSettings(ctx context.Context, userID string) (*models.PublicProfileSettings, error) {
+	if m.FindSettingsFunc != nil {
+		return m.FindSettingsFunc(ctx, userID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockPublicProfileRepository) FindSettingsByUsername(ctx context.Context, username string) (*models.PublicProfileSettings, error) {
+	if m.FindSettingsByUsernameFunc != nil {
+		return m.FindSettingsByUsernameFunc(ctx, username)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockPublicProfileRepository) SaveSettings(ctx context.Context, settings *models.PublicProfileSettings) error {
+	if m.SaveSettingsFunc != nil {
+		return m.SaveSettingsFunc(ctx, settings)
+	}
+	settings.UpdatedAt = time.Now()
+	return nil
+}
+
+func (m *MockPublicProfileRepository) RecordHighlights(ctx context.Context, userID string, limit int) ([]*models.RecordHighlight, error) {
+	if m.RecordHighlightsFunc != nil {
+		return m.RecordHighlightsFunc(ctx, userID, limit)
+	}
+	return []*models.RecordHighlight{}, nil
+}
+
+func (m *MockPublicProfileRepository) RecentSessions(ctx context.Context, userID string, limit int) ([]*models.PublicSession, error) {
+	if m.RecentSessionsFunc != nil {
+		return m.RecentSessionsFunc(ctx, userID, limit)
+	}
+	return []*models.PublicSession{}, nil
+}
+
+func (m *MockPublicProfileRepository) FollowCounts(ctx context.Context, userID string) (int, int, error) {
+	if m.FollowCountsFunc != nil {
+		return m.FollowCountsFunc(ctx, userID)
+	}
+	return 0, 0, nil
+}
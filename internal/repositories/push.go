@@ -0,0 +1,200 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// PushRepository defines the interface for device tokens and queued push notifications
+type PushRepository interface {
+	RegisterDevice(ctx context.Context, device *models.DeviceToken) error
+	ListDevices(ctx context.Context, userID string) ([]*models.DeviceToken, error)
+	FindDevice(ctx context.Context, id string) (*models.DeviceToken, error)
+	DeleteDevice(ctx context.Context, id string) error
+	DeleteToken(ctx context.Context, token string) error
+	Enqueue(ctx context.Context, userID string, kind string, data []byte) error
+	ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.PushNotification, error)
+	Complete(ctx context.Context, id string) error
+	Fail(ctx context.Context, id string, reason string, retryAt *time.Time) error
+	Hold(ctx context.Context, id string, until time.Time) error
+	Prune(ctx context.Context, before time.Time) (int64, error)
+}
+
+// PostgresPushRepository is the PostgreSQL implementation of PushRepository
+type PostgresPushRepository struct {
+	db DB
+}
+
+// NewPostgresPushRepository creates a new PostgreSQL push repository
+func NewPostgresPushRepository(db DB) PushRepository {
+	return &PostgresPushRepository{db: db}
+}
+
+const deviceTokenColumns = `id, user_id, platform, token, created_at, updated_at`
+
+// RegisterDevice stores a device token, moving it to the user if another account had
+// registered it, and sets the device's ID and timestamps
+func (r *PostgresPushRepository) RegisterDevice(ctx context.Context, device *models.DeviceToken) error {
+	query := `
+		INSERT INTO device_tokens (user_id, platform, token)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (token) DO UPDATE
+		SET user_id = EXCLUDED.user_id, platform = EXCLUDED.platform, updated_at = NOW()
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query, device.UserID, device.Platform, device.Token).Scan(
+		&device.ID,
+		&device.CreatedAt,
+		&device.UpdatedAt,
+	)
+}
+
+// ListDevices retrieves the user's devices, most recently registered first
+func (r *PostgresPushRepository) ListDevices(ctx context.Context, userID string) ([]*models.DeviceToken, error) {
+	query := `SELECT ` + deviceTokenColumns + ` FROM device_tokens WHERE user_id = $1 ORDER BY updated_at DESC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	devices := []*models.DeviceToken{}
+	for rows.Next() {
+		device, err := scanDeviceToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, device)
+	}
+
+	return devices, rows.Err()
+}
+
+// FindDevice retrieves a device by ID
+func (r *PostgresPushRepository) FindDevice(ctx context.Context, id string) (*models.DeviceToken, error) {
+	query := `SELECT ` + deviceTokenColumns + ` FROM device_tokens WHERE id = $1`
+	return scanDeviceToken(r.db.QueryRow(ctx, query, id))
+}
+
+func scanDeviceToken(row pgx.Row) (*models.DeviceToken, error) {
+	d := &models.DeviceToken{}
+	if err := row.Scan(&d.ID, &d.UserID, &d.Platform, &d.Token, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DeleteDevice removes a device
+// Returns pgx.ErrNoRows if the device does not exist.
+func (r *PostgresPushRepository) DeleteDevice(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// DeleteToken forgets a token the push provider no longer accepts
+func (r *PostgresPushRepository) DeleteToken(ctx context.Context, token string) error {
+	_, err := r.db.Exec(ctx, `DELETE FROM device_tokens WHERE token = $1`, token)
+	return err
+}
+
+// Enqueue queues a notification for the user's devices, if they registered any
+func (r *PostgresPushRepository) Enqueue(ctx context.Context, userID string, kind string, data []byte) error {
+	query := `
+		INSERT INTO push_notifications (user_id, kind, data)
+		SELECT $1, $2, $3
+		WHERE EXISTS (SELECT 1 FROM device_tokens WHERE user_id = $1)
+	`
+
+	_, err := r.db.Exec(ctx, query, userID, kind, data)
+	return err
+}
+
+// ClaimDue leases up to limit pending notifications whose attempt is due, so that
+// concurrent workers (or a restarted one) skip them until the lease runs out
+func (r *PostgresPushRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.PushNotification, error) {
+	query := `
+		UPDATE push_notifications
+		SET run_at = NOW() + make_interval(secs => $1)
+		WHERE id IN (
+			SELECT id FROM push_notifications
+			WHERE status = 'pending' AND run_at <= NOW()
+			ORDER BY run_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING id, user_id, kind, data, attempts
+	`
+
+	rows, err := r.db.Query(ctx, query, lease.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []*models.PushNotification{}
+	for rows.Next() {
+		n := &models.PushNotification{}
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Data, &n.Attempts); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+
+	return notifications, rows.Err()
+}
+
+// Complete marks a notification sent
+func (r *PostgresPushRepository) Complete(ctx context.Context, id string) error {
+	query := `
+		UPDATE push_notifications
+		SET status = 'sent', attempts = attempts + 1, last_error = NULL, sent_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id)
+	return err
+}
+
+// Fail records a failed attempt and schedules a retry at retryAt, or gives up on the
+// notification when retryAt is nil
+func (r *PostgresPushRepository) Fail(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE push_notifications
+		SET status = CASE WHEN $3::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+		    run_at = COALESCE($3, run_at),
+		    attempts = attempts + 1,
+		    last_error = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, reason, retryAt)
+	return err
+}
+
+// Hold puts a claimed notification back to be sent at until, without counting an attempt
+func (r *PostgresPushRepository) Hold(ctx context.Context, id string, until time.Time) error {
+	_, err := r.db.Exec(ctx, `UPDATE push_notifications SET run_at = $2 WHERE id = $1 AND status = 'pending'`, id, until)
+	return err
+}
+
+// Prune deletes finished notifications queued before the cutoff
+func (r *PostgresPushRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM push_notifications WHERE created_at < $1 AND status <> 'pending'`, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,102 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockPushRepository is a mock implementation for testing
+type MockPushRepository struct {
+	RegisterDeviceFunc func(ctx context.Context, device *models.DeviceToken) error
+	ListDevicesFunc    func(ctx context.Context, userID string) ([]*models.DeviceToken, error)
+	FindDeviceFunc     func(ctx context.Context, id string) (*models.DeviceToken, error)
+	DeleteDeviceFunc   func(ctx context.Context, id string) error
+	DeleteTokenFunc    func(ctx context.Context, token string) error
+	EnqueueFunc        func(ctx context.Context, userID string, kind string, data []byte) error
+	ClaimDueFunc       func(ctx context.Context, lease time.Duration, limit int) ([]*models.PushNotification, error)
+	CompleteFunc       func(ctx context.Context, id string) error
+	FailFunc           func(ctx context.Context, id string, reason string, retryAt *time.Time) error
+	HoldFunc           func(ctx context.Context, id string, until time.Time) error
+	PruneFunc          func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockPushRepository) RegisterDevice(ctx context.Context, device *models.DeviceToken) error {
+	if m.RegisterDeviceFunc != nil {
+		return m.RegisterDeviceFunc(ctx, device)
+	}
+	device.ID = "mock-device-id"
+	return nil
+}
+
+func (m *MockPushRepository) ListDevices(ctx context.Context, userID string) ([]*models.DeviceToken, error) {
+	if m.ListDevicesFunc != nil {
+		return m.ListDevicesFunc(ctx, userID)
+	}
+	return []*models.DeviceToken{}, nil
+}
+
+func (m *MockPushRepository) FindDevice(ctx context.Context, id string) (*models.DeviceToken, error) {
+	if m.FindDeviceFunc != nil {
+		return m.FindDeviceFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockPushRepository) DeleteDevice(ctx context.Context, id string) error {
+	if m.DeleteDeviceFunc != nil {
+		return m.DeleteDeviceFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockPushRepository) DeleteToken(ctx context.Context, token string) error {
+	if m.DeleteTokenFunc != nil {
+		return m.DeleteTokenFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockPushRepository) Enqueue(ctx context.Context, userID string, kind string, data []byte) error {
+	if m.EnqueueFunc != nil {
+		return m.EnqueueFunc(ctx, userID, kind, data)
+	}
+	return nil
+}
+
+func (m *MockPushRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.PushNotification, error) {
+	if m.ClaimDueFunc != nil {
+		return m.ClaimDueFunc(ctx, lease, limit)
+	}
+	return []*models.PushNotification{}, nil
+}
+
+func (m *MockPushRepository) Complete(ctx context.Context, id string) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockPushRepository) Fail(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+	if m.FailFunc != nil {
+		return m.FailFunc(ctx, id, reason, retryAt)
+	}
+	return nil
+}
+
+func (m *MockPushRepository) Hold(ctx context.Context, id string, until time.Time) error {
+	if m.HoldFunc != nil {
+		return m.HoldFunc(ctx, id, until)
+	}
+	return nil
+}
+
+func (m *MockPushRepository) Prune(ctx context.Context, before time.Time) (int64, error) {
+	if m.PruneFunc != nil {
+		return m.PruneFunc(ctx, before)
+	}
+	return 0, nil
+}
@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// RecommendationRepository defines the interface for the history and equipment workout
+// recommendations are built from
+type RecommendationRepository interface {
+	MuscleGroupRecency(ctx context.Context, userID string) ([]*models.MuscleGroupRecency, error)
+	Candidates(ctx context.Context, userID string) ([]*models.RecommendationCandidate, error)
+}
+
+// PostgresRecommendationRepository is the PostgreSQL implementation of RecommendationRepository
+type PostgresRecommendationRepository struct {
+	db DB
+}
+
+// NewPostgresRecommendationRepository creates a new PostgreSQL recommendation repository
+func NewPostgresRecommendationRepository(db DB) RecommendationRepository {
+	return &PostgresRecommendationRepository{db: db}
+}
+
+// MuscleGroupRecency retrieves when the user last trained each muscle group, counting
+// exercises logged without being skipped in completed sessions
+func (r *PostgresRecommendationRepository) MuscleGroupRecency(ctx context.Context, userID string) ([]*models.MuscleGroupRecency, error) {
+	query := `
+		SELECT e.muscle_group, MAX(s.started_at)
+		FROM exercise_logs l
+		JOIN workout_sessions s ON s.id = l.workout_session_id
+		JOIN exercises e ON e.id = l.exercise_id
+		WHERE s.user_id = $1 AND s.status = 'completed'
+		  AND l.skipped_at IS NULL AND e.muscle_group IS NOT NULL
+		GROUP BY e.muscle_group
+		ORDER BY e.muscle_group
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var recency []*models.MuscleGroupRecency
+	for rows.Next() {
+		r := &models.MuscleGroupRecency{}
+		if err := rows.Scan(&r.MuscleGroup, &r.LastTrainedAt); err != nil {
+			return nil, err
+		}
+		recency = append(recency, r)
+	}
+
+	return recency, rows.Err()
+}
+
+// Candidates retrieves the strength exercises visible to the user that need no equipment
+// they lack, with how often and how recently they did each. Equipment is matched by name
+// against the user's own and their organizations', as for swaps; users who have not
+// registered any equipment can do every exercise.
+func (r *PostgresRecommendationRepository) Candidates(ctx context.Context, userID string) ([]*models.RecommendationCandidate, error) {
+	query := `
+		WITH available AS (
+			SELECT lower(e.name) AS name
+			FROM equipment e
+			WHERE e.user_id = $1
+			   OR e.organization_id IN (SELECT organization_id FROM organization_members WHERE user_id = $1)
+		), history AS (
+			SELECT l.exercise_id, MAX(s.started_at) AS last_performed_at, COUNT(DISTINCT s.id) AS times
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE s.user_id = $1 AND s.status = 'completed' AND l.skipped_at IS NULL
+			GROUP BY l.exercise_id
+		)
+		SELECT e.id, e.name, e.muscle_group, h.last_performed_at, COALESCE(h.times, 0)
+		FROM exercises e
+		LEFT JOIN history h ON h.exercise_id = e.id
+		WHERE (e.is_public OR e.user_id = $1)
+		  AND e.muscle_group IS NOT NULL AND e.muscle_group NOT IN ('cardio', 'full_body')
+		  AND NOT EXISTS (
+			SELECT 1
+			FROM exercise_equipment ee
+			JOIN equipment eq ON eq.id = ee.equipment_id
+			WHERE ee.exercise_id = e.id
+			  AND EXISTS (SELECT 1 FROM available)
+			  AND lower(eq.name) NOT IN (SELECT name FROM available)
+		  )
+		ORDER BY e.name, e.id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candidates []*models.RecommendationCandidate
+	for rows.Next() {
+		c := &models.RecommendationCandidate{}
+		err := rows.Scan(
+			&c.ExerciseID,
+			&c.Name,
+			&c.MuscleGroup,
+			&c.LastPerformedAt,
+			&c.TimesPerformed,
+		)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+
+	return candidates, rows.Err()
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockRecommendationRepository is a mock implementation for testing
+type MockRecommendationRepository struct {
+	MuscleGroupRecencyFunc func(ctx context.Context, userID string) ([]*models.MuscleGroupRecency, error)
+	CandidatesFunc         func(ctx context.Context, userID string) ([]*models.RecommendationCandidate, error)
+}
+
+func (m *MockRecommendationRepository) MuscleGroupRecency(ctx context.Context, userID string) ([]*models.MuscleGroupRecency, error) {
+	if m.MuscleGroupRecencyFunc != nil {
+		return m.MuscleGroupRecencyFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *MockRecommendationRepository) Candidates(ctx context.Context, userID string) ([]*models.RecommendationCandidate, error) {
+	if m.CandidatesFunc != nil {
+		return m.CandidatesFunc(ctx, userID)
+	}
+	return nil, nil
+}
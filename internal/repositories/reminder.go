@@ -0,0 +1,220 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ReminderRepository defines the interface for users' reminder rules
+type ReminderRepository interface {
+	Create(ctx context.Context, rule *models.ReminderRule) error
+	FindByID(ctx context.Context, id string) (*models.ReminderRule, error)
+	ListByUser(ctx context.Context, userID string) ([]*models.ReminderRule, error)
+	CountByUser(ctx context.Context, userID string) (int, error)
+	Update(ctx context.Context, rule *models.ReminderRule) error
+	Delete(ctx context.Context, id string) error
+	ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.DueReminder, error)
+	Fired(ctx context.Context, id string, firedAt *time.Time, next time.Time) error
+}
+
+// PostgresReminderRepository is the PostgreSQL implementation of ReminderRepository
+type PostgresReminderRepository struct {
+	db DB
+}
+
+// NewPostgresReminderRepository creates a new PostgreSQL reminder repository
+func NewPostgresReminderRepository(db DB) ReminderRepository {
+	return &PostgresReminderRepository{db: db}
+}
+
+const reminderRuleColumns = `id, user_id, name, weekdays, time_of_day, timezone, channels, enabled,
+	next_fire_at, last_fired_at, created_at, updated_at`
+
+// Create inserts a reminder rule and sets its ID and timestamps
+func (r *PostgresReminderRepository) Create(ctx context.Context, rule *models.ReminderRule) error {
+	query := `
+		INSERT INTO reminder_rules (user_id, name, weekdays, time_of_day, timezone, channels, enabled, next_fire_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		rule.UserID,
+		rule.Name,
+		rule.Weekdays,
+		rule.TimeOfDay,
+		rule.Timezone,
+		rule.Channels,
+		rule.Enabled,
+		rule.NextFireAt,
+	).Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+// FindByID retrieves a reminder rule by ID
+func (r *PostgresReminderRepository) FindByID(ctx context.Context, id string) (*models.ReminderRule, error) {
+	query := `SELECT ` + reminderRuleColumns + ` FROM reminder_rules WHERE id = $1`
+	return scanReminderRule(r.db.QueryRow(ctx, query, id))
+}
+
+// ListByUser retrieves the user's reminder rules, oldest first
+func (r *PostgresReminderRepository) ListByUser(ctx context.Context, userID string) ([]*models.ReminderRule, error) {
+	query := `SELECT ` + reminderRuleColumns + ` FROM reminder_rules WHERE user_id = $1 ORDER BY created_at ASC`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []*models.ReminderRule{}
+	for rows.Next() {
+		rule, err := scanReminderRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules, rows.Err()
+}
+
+func scanReminderRule(row pgx.Row) (*models.ReminderRule, error) {
+	rule := &models.ReminderRule{}
+	err := row.Scan(
+		&rule.ID,
+		&rule.UserID,
+		&rule.Name,
+		&rule.Weekdays,
+		&rule.TimeOfDay,
+		&rule.Timezone,
+		&rule.Channels,
+		&rule.Enabled,
+		&rule.NextFireAt,
+		&rule.LastFiredAt,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// CountByUser counts the user's reminder rules
+func (r *PostgresReminderRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow(ctx, `SELECT COUNT(*) FROM reminder_rules WHERE user_id = $1`, userID).Scan(&count)
+	return count, err
+}
+
+// Update saves a reminder rule's settings and next firing time, and sets its updated_at
+// Returns pgx.ErrNoRows if the rule does not exist.
+func (r *PostgresReminderRepository) Update(ctx context.Context, rule *models.ReminderRule) error {
+	query := `
+		UPDATE reminder_rules
+		SET name = $2, weekdays = $3, time_of_day = $4, timezone = $5, channels = $6,
+		    enabled = $7, next_fire_at = $8
+		WHERE id = $1
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(ctx, query,
+		rule.ID,
+		rule.Name,
+		rule.Weekdays,
+		rule.TimeOfDay,
+		rule.Timezone,
+		rule.Channels,
+		rule.Enabled,
+		rule.NextFireAt,
+	).Scan(&rule.UpdatedAt)
+}
+
+// Delete removes a reminder rule
+// Returns pgx.ErrNoRows if the rule does not exist.
+func (r *PostgresReminderRepository) Delete(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `DELETE FROM reminder_rules WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
+
+// ClaimDue leases up to limit enabled rules whose firing time has come, so that
+// concurrent schedulers (or a restarted one) skip them until the lease runs out. The
+// claimed rules keep their due time, which the scheduler needs to compute the next one.
+func (r *PostgresReminderRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.DueReminder, error) {
+	query := `
+		WITH due AS (
+			SELECT id, next_fire_at FROM reminder_rules
+			WHERE enabled AND next_fire_at <= NOW()
+			ORDER BY next_fire_at ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		), claimed AS (
+			UPDATE reminder_rules r
+			SET next_fire_at = NOW() + make_interval(secs => $1)
+			FROM due
+			WHERE r.id = due.id
+			RETURNING r.id, r.user_id, r.name, r.weekdays, r.time_of_day, r.timezone, r.channels,
+			          r.enabled, due.next_fire_at, r.last_fired_at, r.created_at, r.updated_at
+		)
+		SELECT claimed.*, COALESCE(u.email, '')
+		FROM claimed
+		LEFT JOIN auth.users u ON u.id = claimed.user_id
+	`
+
+	rows, err := r.db.Query(ctx, query, lease.Seconds(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	due := []*models.DueReminder{}
+	for rows.Next() {
+		rule := &models.ReminderRule{}
+		d := &models.DueReminder{Rule: rule}
+		err := rows.Scan(
+			&rule.ID,
+			&rule.UserID,
+			&rule.Name,
+			&rule.Weekdays,
+			&rule.TimeOfDay,
+			&rule.Timezone,
+			&rule.Channels,
+			&rule.Enabled,
+			&rule.NextFireAt,
+			&rule.LastFiredAt,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+			&d.Email,
+		)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, d)
+	}
+
+	return due, rows.Err()
+}
+
+// Fired schedules a claimed rule's next firing, recording when it fired unless firedAt is
+// nil (the firing was skipped)
+func (r *PostgresReminderRepository) Fired(ctx context.Context, id string, firedAt *time.Time, next time.Time) error {
+	query := `
+		UPDATE reminder_rules
+		SET next_fire_at = $3, last_fired_at = COALESCE($2, last_fired_at)
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, firedAt, next)
+	return err
+}
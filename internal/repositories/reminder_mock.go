@@ -0,0 +1,78 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockReminderRepository is a mock implementation for testing
+type MockReminderRepository struct {
+	CreateFunc      func(ctx context.Context, rule *models.ReminderRule) error
+	FindByIDFunc    func(ctx context.Context, id string) (*models.ReminderRule, error)
+	ListByUserFunc  func(ctx context.Context, userID string) ([]*models.ReminderRule, error)
+	CountByUserFunc func(ctx context.Context, userID string) (int, error)
+	UpdateFunc      func(ctx context.Context, rule *models.ReminderRule) error
+	DeleteFunc      func(ctx context.Context, id string) error
+	ClaimDueFunc    func(ctx context.Context, lease time.Duration, limit int) ([]*models.DueReminder, error)
+	FiredFunc       func(ctx context.Context, id string, firedAt *time.Time, next time.Time) error
+}
+
+func (m *MockReminderRepository) Create(ctx context.Context, rule *models.ReminderRule) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, rule)
+	}
+	rule.ID = "mock-reminder-id"
+	return nil
+}
+
+func (m *MockReminderRepository) FindByID(ctx context.Context, id string) (*models.ReminderRule, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockReminderRepository) ListByUser(ctx context.Context, userID string) ([]*models.ReminderRule, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID)
+	}
+	return []*models.ReminderRule{}, nil
+}
+
+func (m *MockReminderRepository) CountByUser(ctx context.Context, userID string) (int, error) {
+	if m.CountByUserFunc != nil {
+		return m.CountByUserFunc(ctx, userID)
+	}
+	return 0, nil
+}
+
+func (m *MockReminderRepository) Update(ctx context.Context, rule *models.ReminderRule) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, rule)
+	}
+	return nil
+}
+
+func (m *MockReminderRepository) Delete(ctx context.Context, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockReminderRepository) ClaimDue(ctx context.Context, lease time.Duration, limit int) ([]*models.DueReminder, error) {
+	if m.ClaimDueFunc != nil {
+		return m.ClaimDueFunc(ctx, lease, limit)
+	}
+	return []*models.DueReminder{}, nil
+}
+
+func (m *MockReminderRepository) Fired(ctx context.Context, id string, firedAt *time.Time, next time.Time) error {
+	if m.FiredFunc != nil {
+		return m.FiredFunc(ctx, id, firedAt, next)
+	}
+	return nil
+}
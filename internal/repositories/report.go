@@ -0,0 +1,134 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ReportRepository defines the interface for users' weekly reports
+type ReportRepository interface {
+	CreateWeeklyReports(ctx context.Context, weekStart time.Time) (int64, error)
+	FindWeeklyReport(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReport, error)
+	ListWeeklyReports(ctx context.Context, userID string, limit int) ([]*models.WeeklyReport, error)
+}
+
+// PostgresReportRepository is the PostgreSQL implementation of ReportRepository
+type PostgresReportRepository struct {
+	db DB
+}
+
+// NewPostgresReportRepository creates a new PostgreSQL report repository
+func NewPostgresReportRepository(db DB) ReportRepository {
+	return &PostgresReportRepository{db: db}
+}
+
+const weeklyReportColumns = `id, user_id, to_char(week_start, 'YYYY-MM-DD'), sessions, minutes, volume_kg, personal_records, streak_weeks, created_at`
+
+// CreateWeeklyReports compiles the report of the week starting on weekStart's date for each
+// user who completed a session that week or gets weekly summary emails; reports already
+// compiled are kept. Weeks run Monday to Sunday in each user's timezone, and a user's
+// report waits until their week is over. Streaks look back at most two years.
+func (r *PostgresReportRepository) CreateWeeklyReports(ctx context.Context, weekStart time.Time) (int64, error) {
+	query := `
+		INSERT INTO weekly_reports (user_id, week_start, sessions, minutes, volume_kg, personal_records, streak_weeks)
+		SELECT u.user_id, d.week_start,
+		       COALESCE(week.sessions, 0), COALESCE(week.minutes, 0),
+		       COALESCE(week.volume_kg, 0), COALESCE(week.personal_records, 0),
+		       streak.weeks
+		FROM (SELECT ($1::timestamptz AT TIME ZONE 'UTC')::date AS week_start) d
+		CROSS JOIN (
+			-- Local weeks start up to 14 hours before and 12 hours after the UTC one
+			SELECT s.user_id FROM workout_sessions s
+			WHERE s.status = 'completed'
+			  AND s.started_at >= $1::timestamptz - INTERVAL '14 hours'
+			  AND s.started_at < $1::timestamptz + INTERVAL '7 days 12 hours'
+			UNION
+			SELECT p.user_id FROM email_preferences p WHERE p.weekly_summary
+		) u
+		CROSS JOIN LATERAL (
+			SELECT d.week_start::timestamp AT TIME ZONE z.tz AS starts,
+			       (d.week_start + 7)::timestamp AT TIME ZONE z.tz AS ends,
+			       z.tz
+			FROM (SELECT user_timezone(u.user_id) AS tz) z
+		) b
+		LEFT JOIN LATERAL (
+			SELECT COUNT(*) AS sessions,
+			       SUM(COALESCE(s.duration_minutes, 0)) AS minutes,
+			       SUM((SELECT COALESCE(SUM(COALESCE(l.weight_kg, 0) * COALESCE(l.reps_completed, 0) * COALESCE(l.sets_completed, 1)), 0)
+			            FROM exercise_logs l WHERE l.workout_session_id = s.id AND l.skipped_at IS NULL)) AS volume_kg,
+			       SUM((SELECT COUNT(*) FROM exercise_logs l
+			            WHERE l.workout_session_id = s.id AND l.is_personal_record)) AS personal_records
+			FROM workout_sessions s
+			WHERE s.user_id = u.user_id
+			  AND s.status = 'completed'
+			  AND s.started_at >= b.starts AND s.started_at < b.ends
+		) week ON TRUE
+		CROSS JOIN LATERAL (
+			-- The streak ends at the most recent week without a completed session
+			SELECT COALESCE(MIN(g.n), 105) AS weeks
+			FROM generate_series(0, 104) AS g(n)
+			WHERE NOT EXISTS (
+				SELECT 1 FROM workout_sessions s
+				WHERE s.user_id = u.user_id
+				  AND s.status = 'completed'
+				  AND s.started_at >= (d.week_start - 7 * g.n)::timestamp AT TIME ZONE b.tz
+				  AND s.started_at < (d.week_start - 7 * (g.n - 1))::timestamp AT TIME ZONE b.tz
+			)
+		) streak
+		WHERE b.ends <= NOW()
+		  AND (week.sessions > 0
+		       OR EXISTS (SELECT 1 FROM email_preferences p WHERE p.user_id = u.user_id AND p.weekly_summary))
+		ON CONFLICT (user_id, week_start) DO NOTHING
+	`
+
+	tag, err := r.db.Exec(ctx, query, weekStart)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// FindWeeklyReport retrieves the user's report of the week starting at weekStart
+func (r *PostgresReportRepository) FindWeeklyReport(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReport, error) {
+	query := `SELECT ` + weeklyReportColumns + ` FROM weekly_reports
+		WHERE user_id = $1 AND week_start = ($2::timestamptz AT TIME ZONE 'UTC')::date`
+
+	return scanWeeklyReport(r.db.QueryRow(ctx, query, userID, weekStart))
+}
+
+// ListWeeklyReports retrieves the user's most recent reports, newest first
+func (r *PostgresReportRepository) ListWeeklyReports(ctx context.Context, userID string, limit int) ([]*models.WeeklyReport, error) {
+	query := `SELECT ` + weeklyReportColumns + ` FROM weekly_reports
+		WHERE user_id = $1
+		ORDER BY week_start DESC
+		LIMIT $2`
+
+	rows, err := r.db.Query(ctx, query, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports := []*models.WeeklyReport{}
+	for rows.Next() {
+		report, err := scanWeeklyReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+func scanWeeklyReport(row pgx.Row) (*models.WeeklyReport, error) {
+	report := &models.WeeklyReport{}
+	err := row.Scan(&report.ID, &report.UserID, &report.WeekStart, &report.Sessions, &report.Minutes,
+		&report.VolumeKg, &report.PersonalRecords, &report.StreakWeeks, &report.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockReportRepository is a mock implementation for testing
+type MockReportRepository struct {
+	CreateWeeklyReportsFunc func(ctx context.Context, weekStart time.Time) (int64, error)
+	FindWeeklyReportFunc    func(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReport, error)
+	ListWeeklyReportsFunc   func(ctx context.Context, userID string, limit int) ([]*models.WeeklyReport, error)
+}
+
+func (m *MockReportRepository) CreateWeeklyReports(ctx context.Context, weekStart time.Time) (int64, error) {
+	if m.CreateWeeklyReportsFunc != nil {
+		return m.CreateWeeklyReportsFunc(ctx, weekStart)
+	}
+	return 0, nil
+}
+
+func (m *MockReportRepository) FindWeeklyReport(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReport, error) {
+	if m.FindWeeklyReportFunc != nil {
+		return m.FindWeeklyReportFunc(ctx, userID, weekStart)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockReportRepository) ListWeeklyReports(ctx context.Context, userID string, limit int) ([]*models.WeeklyReport, error) {
+	if m.ListWeeklyReportsFunc != nil {
+		return m.ListWeeklyReportsFunc(ctx, userID, limit)
+	}
+	return []*models.WeeklyReport{}, nil
+}
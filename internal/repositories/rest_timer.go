@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// RestTimerRepository defines the interface for session rest timer data access
+type RestTimerRepository interface {
+	FindBySession(ctx context.Context, sessionID string) (*models.RestTimerSession, error)
+	Set(ctx context.Context, sessionID string, startedAt *time.Time, durationSeconds *int) error
+}
+
+// PostgresRestTimerRepository is the PostgreSQL implementation of RestTimerRepository
+type PostgresRestTimerRepository struct {
+	db DB
+}
+
+// NewPostgresRestTimerRepository creates a new PostgreSQL rest timer repository
+func NewPostgresRestTimerRepository(db DB) RestTimerRepository {
+	return &PostgresRestTimerRepository{db: db}
+}
+
+// FindBySession retrieves a session's rest timer along with its owner and status
+// Returns pgx.ErrNoRows if the session does not exist.
+func (r *PostgresRestTimerRepository) FindBySession(ctx context.Context, sessionID string) (*models.RestTimerSession, error) {
+	query := `
+		SELECT id, user_id, status, rest_timer_started_at, rest_timer_duration_seconds
+		FROM workout_sessions
+		WHERE id = $1
+	`
+
+	s := &models.RestTimerSession{}
+	err := r.db.QueryRow(ctx, query, sessionID).Scan(
+		&s.SessionID,
+		&s.UserID,
+		&s.Status,
+		&s.StartedAt,
+		&s.DurationSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Set replaces the session's rest timer; nil values clear it
+// Returns pgx.ErrNoRows if the session does not exist.
+func (r *PostgresRestTimerRepository) Set(ctx context.Context, sessionID string, startedAt *time.Time, durationSeconds *int) error {
+	query := `
+		UPDATE workout_sessions
+		SET rest_timer_started_at = $2, rest_timer_duration_seconds = $3
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, sessionID, startedAt, durationSeconds)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockRestTimerRepository is a mock implementation for testing
+type MockRestTimerRepository struct {
+	FindBySessionFunc func(ctx context.Context, sessionID string) (*models.RestTimerSession, error)
+	SetFunc           func(ctx context.Context, sessionID string, startedAt *time.Time, durationSeconds *int) error
+}
+
+func (m *MockRestTimerRepository) FindBySession(ctx context.Context, sessionID string) (*models.RestTimerSession, error) {
+	if m.FindBySessionFunc != nil {
+		return m.FindBySessionFunc(ctx, sessionID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockRestTimerRepository) Set(ctx context.Context, sessionID string, startedAt *time.Time, durationSeconds *int) error {
+	if m.SetFunc != nil {
+		return m.SetFunc(ctx, sessionID, startedAt, durationSeconds)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// RevokedTokenRepository defines the interface for revoked token data access
+type RevokedTokenRepository interface {
+	Create(ctx context.Context, token *models.RevokedToken) error
+	FindUnexpired(ctx context.Context) ([]*models.RevokedToken, error)
+	DeleteExpired(ctx context.Context) (int64, error)
+}
+
+// PostgresRevokedTokenRepository is the PostgreSQL implementation of RevokedTokenRepository
+type PostgresRevokedTokenRepository struct {
+	db DB
+}
+
+// NewPostgresRevokedTokenRepository creates a new PostgreSQL revoked token repository
+func NewPostgresRevokedTokenRepository(db DB) RevokedTokenRepository {
+	return &PostgresRevokedTokenRepository{db: db}
+}
+
+// Create records a revocation; revoking the same token twice is a no-op
+func (r *PostgresRevokedTokenRepository) Create(ctx context.Context, token *models.RevokedToken) error {
+	query := `
+		INSERT INTO revoked_tokens (token_id, user_id, expires_at, revoked_at)
+		VALUES ($1, $2, $3, NOW())
+		ON CONFLICT (token_id) DO UPDATE SET token_id = EXCLUDED.token_id
+		RETURNING revoked_at
+	`
+
+	return r.db.QueryRow(ctx, query, token.TokenID, token.UserID, token.ExpiresAt).Scan(&token.RevokedAt)
+}
+
+// FindUnexpired retrieves every revocation whose token could still be presented
+func (r *PostgresRevokedTokenRepository) FindUnexpired(ctx context.Context) ([]*models.RevokedToken, error) {
+	query := `
+		SELECT token_id, user_id, expires_at, revoked_at
+		FROM revoked_tokens
+		WHERE expires_at > NOW()
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tokens []*models.RevokedToken
+	for rows.Next() {
+		token := &models.RevokedToken{}
+		if err := rows.Scan(&token.TokenID, &token.UserID, &token.ExpiresAt, &token.RevokedAt); err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, token)
+	}
+
+	return tokens, rows.Err()
+}
+
+// DeleteExpired purges revocations for tokens that have expired anyway
+func (r *PostgresRevokedTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM revoked_tokens WHERE expires_at <= NOW()`)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockRevokedTokenRepository is a mock implementation for testing
+type MockRevokedTokenRepository struct {
+	CreateFunc        func(ctx context.Context, token *models.RevokedToken) error
+	FindUnexpiredFunc func(ctx context.Context) ([]*models.RevokedToken, error)
+	DeleteExpiredFunc func(ctx context.Context) (int64, error)
+}
+
+func (m *MockRevokedTokenRepository) Create(ctx context.Context, token *models.RevokedToken) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, token)
+	}
+	return nil
+}
+
+func (m *MockRevokedTokenRepository) FindUnexpired(ctx context.Context) ([]*models.RevokedToken, error) {
+	if m.FindUnexpiredFunc != nil {
+		return m.FindUnexpiredFunc(ctx)
+	}
+	return []*models.RevokedToken{}, nil
+}
+
+func (m *MockRevokedTokenRepository) DeleteExpired(ctx context.Context) (int64, error) {
+	if m.DeleteExpiredFunc != nil {
+		return m.DeleteExpiredFunc(ctx)
+	}
+	return 0, nil
+}
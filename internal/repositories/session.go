@@ -0,0 +1,187 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// SessionRepository defines the interface for workout session data access
+type SessionRepository interface {
+	FindByID(ctx context.Context, id string) (*models.Session, error)
+	FindOwner(ctx context.Context, id string) (string, error)
+	ListByUser(ctx context.Context, userID string, filter models.SessionFilter) ([]*models.SessionSummary, error)
+}
+
+// PostgresSessionRepository is the PostgreSQL implementation of SessionRepository
+type PostgresSessionRepository struct {
+	db DB
+}
+
+// NewPostgresSessionRepository creates a new PostgreSQL session repository
+func NewPostgresSessionRepository(db DB) SessionRepository {
+	return &PostgresSessionRepository{db: db}
+}
+
+const sessionColumns = `id, user_id, workout_id, class_session_id, name, session_type, status, started_at, completed_at,
+	duration_minutes, calories_burned, heart_rate_avg, heart_rate_max, notes, created_at, updated_at`
+
+func scanSessionSummary(row pgx.Row, session *models.SessionSummary) error {
+	return row.Scan(
+		&session.ID,
+		&session.UserID,
+		&session.WorkoutID,
+		&session.ClassSessionID,
+		&session.Name,
+		&session.Type,
+		&session.Status,
+		&session.StartedAt,
+		&session.CompletedAt,
+		&session.DurationMinutes,
+		&session.CaloriesBurned,
+		&session.HeartRateAvg,
+		&session.HeartRateMax,
+		&session.Notes,
+		&session.CreatedAt,
+		&session.UpdatedAt,
+	)
+}
+
+// FindByID retrieves a session with its sets in order, each with its library exercise,
+// and its laps. The three queries go out in one batch, a single round trip.
+// Returns pgx.ErrNoRows if the session does not exist.
+func (r *PostgresSessionRepository) FindByID(ctx context.Context, id string) (*models.Session, error) {
+	sessionQuery := `SELECT ` + sessionColumns + ` FROM workout_sessions WHERE id = $1`
+	setsQuery := `
+		SELECT l.id, l.order_index, l.sets_completed, l.sets_planned, l.reps_completed, l.reps_planned,
+		       l.weight_kg, l.duration_seconds, l.distance_meters, l.rpe, l.notes,
+		       COALESCE(l.is_personal_record, FALSE), l.skipped_at,
+		       e.id, e.name, COALESCE(e.description, ''), e.is_public, e.user_id, e.image_url, e.muscle_group, e.created_at, e.updated_at
+		FROM exercise_logs l
+		JOIN exercises e ON e.id = l.exercise_id
+		WHERE l.workout_session_id = $1
+		ORDER BY l.order_index ASC, l.created_at ASC
+	`
+
+	batch := &pgx.Batch{}
+	batch.Queue(sessionQuery, id)
+	batch.Queue(setsQuery, id)
+	batch.Queue(sessionLapsQuery, id)
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	session := &models.Session{}
+	if err := scanSessionSummary(results.QueryRow(), &session.SessionSummary); err != nil {
+		return nil, err
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	session.Sets, err = scanSessionSets(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err = results.Query()
+	if err != nil {
+		return nil, err
+	}
+	session.Laps, err = scanSessionLaps(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	return session, nil
+}
+
+// FindOwner returns the user who owns a session, for services guarding what hangs off it
+// Returns pgx.ErrNoRows if the session does not exist.
+func (r *PostgresSessionRepository) FindOwner(ctx context.Context, id string) (string, error) {
+	var userID string
+	err := r.db.QueryRow(ctx, `SELECT user_id FROM workout_sessions WHERE id = $1`, id).Scan(&userID)
+	return userID, err
+}
+
+// ListByUser retrieves a page of the user's sessions, newest first. Pages after the first
+// continue from the cursor with a keyset predicate rather than OFFSET, so each page is an
+// index range scan on (user_id, created_at, id) however deep the history goes.
+func (r *PostgresSessionRepository) ListByUser(ctx context.Context, userID string, filter models.SessionFilter) ([]*models.SessionSummary, error) {
+	var rows pgx.Rows
+	var err error
+	if filter.After == nil {
+		query := `
+			SELECT ` + sessionColumns + `
+			FROM workout_sessions
+			WHERE user_id = $1
+			ORDER BY created_at DESC, id DESC
+			LIMIT $2
+		`
+		rows, err = r.db.Query(ctx, query, userID, filter.Limit)
+	} else {
+		query := `
+			SELECT ` + sessionColumns + `
+			FROM workout_sessions
+			WHERE user_id = $1 AND (created_at, id) < ($2, $3::uuid)
+			ORDER BY created_at DESC, id DESC
+			LIMIT $4
+		`
+		rows, err = r.db.Query(ctx, query, userID, filter.After.CreatedAt, filter.After.ID, filter.Limit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*models.SessionSummary{}
+	for rows.Next() {
+		session := &models.SessionSummary{}
+		if err := scanSessionSummary(rows, session); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+
+	return sessions, rows.Err()
+}
+
+func scanSessionSets(rows pgx.Rows) ([]*models.SessionSet, error) {
+	defer rows.Close()
+
+	sets := []*models.SessionSet{}
+	for rows.Next() {
+		set := &models.SessionSet{Exercise: &models.Exercise{}}
+		err := rows.Scan(
+			&set.ID,
+			&set.OrderIndex,
+			&set.SetsCompleted,
+			&set.SetsPlanned,
+			&set.RepsCompleted,
+			&set.RepsPlanned,
+			&set.WeightKg,
+			&set.DurationSeconds,
+			&set.DistanceMeters,
+			&set.RPE,
+			&set.Notes,
+			&set.IsPersonalRecord,
+			&set.SkippedAt,
+			&set.Exercise.ID,
+			&set.Exercise.Name,
+			&set.Exercise.Description,
+			&set.Exercise.IsPublic,
+			&set.Exercise.UserID,
+			&set.Exercise.ImageURL,
+			&set.Exercise.MuscleGroup,
+			&set.Exercise.CreatedAt,
+			&set.Exercise.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		sets = append(sets, set)
+	}
+
+	return sets, rows.Err()
+}
@@ -0,0 +1,67 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// SessionLapRepository defines the interface for cardio session lap data access
+type SessionLapRepository interface {
+	FindBySession(ctx context.Context, sessionID string) ([]*models.SessionLap, error)
+}
+
+// PostgresSessionLapRepository is the PostgreSQL implementation of SessionLapRepository
+type PostgresSessionLapRepository struct {
+	db DB
+}
+
+// NewPostgresSessionLapRepository creates a new PostgreSQL session lap repository
+func NewPostgresSessionLapRepository(db DB) SessionLapRepository {
+	return &PostgresSessionLapRepository{db: db}
+}
+
+// sessionLapsQuery selects a session's laps in order
+const sessionLapsQuery = `
+	SELECT id, workout_session_id, lap_index, started_at, duration_seconds, distance_meters,
+	       calories_burned, heart_rate_avg, heart_rate_max
+	FROM session_laps
+	WHERE workout_session_id = $1
+	ORDER BY lap_index ASC
+`
+
+// FindBySession retrieves a session's laps in order
+func (r *PostgresSessionLapRepository) FindBySession(ctx context.Context, sessionID string) ([]*models.SessionLap, error) {
+	rows, err := r.db.Query(ctx, sessionLapsQuery, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	return scanSessionLaps(rows)
+}
+
+func scanSessionLaps(rows pgx.Rows) ([]*models.SessionLap, error) {
+	defer rows.Close()
+
+	laps := []*models.SessionLap{}
+	for rows.Next() {
+		lap := &models.SessionLap{}
+		err := rows.Scan(
+			&lap.ID,
+			&lap.SessionID,
+			&lap.LapIndex,
+			&lap.StartedAt,
+			&lap.DurationSeconds,
+			&lap.DistanceMeters,
+			&lap.CaloriesBurned,
+			&lap.HeartRateAvg,
+			&lap.HeartRateMax,
+		)
+		if err != nil {
+			return nil, err
+		}
+		laps = append(laps, lap)
+	}
+
+	return laps, rows.Err()
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockSessionLapRepository is a mock implementation for testing
+type MockSessionLapRepository struct {
+	FindBySessionFunc func(ctx context.Context, sessionID string) ([]*models.SessionLap, error)
+}
+
+func (m *MockSessionLapRepository) FindBySession(ctx context.Context, sessionID string) ([]*models.SessionLap, error) {
+	if m.FindBySessionFunc != nil {
+		return m.FindBySessionFunc(ctx, sessionID)
+	}
+	return []*models.SessionLap{}, nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// sessionActivityChannel is the notification channel written by the session activity triggers
+const sessionActivityChannel = "session_activity"
+
+// SessionLiveRepository defines the interface for following sessions as they are performed
+type SessionLiveRepository interface {
+	FindSnapshot(ctx context.Context, sessionID string) (*models.LiveSessionSnapshot, error)
+	Listen(ctx context.Context, handle func(*models.LiveSessionEvent)) error
+}
+
+// PostgresSessionLiveRepository is the PostgreSQL implementation of SessionLiveRepository
+type PostgresSessionLiveRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresSessionLiveRepository creates a new PostgreSQL live session repository
+func NewPostgresSessionLiveRepository(db *pgxpool.Pool) SessionLiveRepository {
+	return &PostgresSessionLiveRepository{db: db}
+}
+
+// FindSnapshot retrieves a session's owner, status, rest timer and logged sets in order,
+// in a single round trip.
+// Returns pgx.ErrNoRows if the session does not exist.
+func (r *PostgresSessionLiveRepository) FindSnapshot(ctx context.Context, sessionID string) (*models.LiveSessionSnapshot, error) {
+	query := `
+		SELECT user_id, status, rest_timer_started_at, rest_timer_duration_seconds
+		FROM workout_sessions
+		WHERE id = $1
+	`
+	setsQuery := `
+		SELECT id, exercise_id, order_index, sets_completed, reps_completed, weight_kg,
+		       duration_seconds, distance_meters, rpe, is_personal_record
+		FROM exercise_logs
+		WHERE workout_session_id = $1
+		ORDER BY order_index ASC, created_at ASC
+	`
+
+	batch := &pgx.Batch{}
+	batch.Queue(query, sessionID)
+	batch.Queue(setsQuery, sessionID)
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	var status string
+	snapshot := &models.LiveSessionSnapshot{
+		Event: &models.LiveSessionEvent{Type: models.LiveEventSnapshot, SessionID: sessionID, Status: &status},
+	}
+	err := results.QueryRow().Scan(
+		&snapshot.UserID,
+		&status,
+		&snapshot.Event.RestTimerStartedAt,
+		&snapshot.Event.RestTimerDurationSeconds,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		set := &models.LiveSet{}
+		err := rows.Scan(
+			&set.ID,
+			&set.ExerciseID,
+			&set.OrderIndex,
+			&set.SetsCompleted,
+			&set.RepsCompleted,
+			&set.WeightKg,
+			&set.DurationSeconds,
+			&set.DistanceMeters,
+			&set.RPE,
+			&set.IsPersonalRecord,
+		)
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Event.Sets = append(snapshot.Event.Sets, set)
+	}
+
+	return snapshot, rows.Err()
+}
+
+// Listen calls handle for every session activity notification until ctx is cancelled or
+// the connection fails
+func (r *PostgresSessionLiveRepository) Listen(ctx context.Context, handle func(*models.LiveSessionEvent)) error {
+	return listen(ctx, r.db, sessionActivityChannel, func(payload string) {
+		event := &models.LiveSessionEvent{}
+		if err := json.Unmarshal([]byte(payload), event); err != nil {
+			log.Printf("Ignoring invalid session activity notification: %v", err)
+			return
+		}
+		handle(event)
+	})
+}
@@ -0,0 +1,29 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockSessionLiveRepository is a mock implementation for testing
+type MockSessionLiveRepository struct {
+	FindSnapshotFunc func(ctx context.Context, sessionID string) (*models.LiveSessionSnapshot, error)
+	ListenFunc       func(ctx context.Context, handle func(*models.LiveSessionEvent)) error
+}
+
+func (m *MockSessionLiveRepository) FindSnapshot(ctx context.Context, sessionID string) (*models.LiveSessionSnapshot, error) {
+	if m.FindSnapshotFunc != nil {
+		return m.FindSnapshotFunc(ctx, sessionID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockSessionLiveRepository) Listen(ctx context.Context, handle func(*models.LiveSessionEvent)) error {
+	if m.ListenFunc != nil {
+		return m.ListenFunc(ctx, handle)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -0,0 +1,204 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// SessionMediaRepository defines the interface for session media data access
+type SessionMediaRepository interface {
+	FindBySession(ctx context.Context, sessionID string) ([]*models.SessionMedia, error)
+	FindHighlights(ctx context.Context, sessionID string) (*models.SessionHighlights, error)
+	Create(ctx context.Context, media *models.SessionMedia) error
+	Update(ctx context.Context, media *models.SessionMedia) error
+	Delete(ctx context.Context, sessionID string, mediaID string) error
+	Reorder(ctx context.Context, sessionID string, mediaIDs []string) error
+}
+
+// PostgresSessionMediaRepository is the PostgreSQL implementation of SessionMediaRepository
+type PostgresSessionMediaRepository struct {
+	db DB
+}
+
+// NewPostgresSessionMediaRepository creates a new PostgreSQL session media repository
+func NewPostgresSessionMediaRepository(db DB) SessionMediaRepository {
+	return &PostgresSessionMediaRepository{db: db}
+}
+
+const sessionMediaColumns = `id, workout_session_id, user_id, media_type, url, thumbnail_url, caption,
+	duration_seconds, position, highlight, created_at, updated_at`
+
+func scanSessionMedia(row pgx.Row) (*models.SessionMedia, error) {
+	m := &models.SessionMedia{}
+	err := row.Scan(
+		&m.ID,
+		&m.SessionID,
+		&m.UserID,
+		&m.MediaType,
+		&m.URL,
+		&m.ThumbnailURL,
+		&m.Caption,
+		&m.DurationSeconds,
+		&m.Position,
+		&m.Highlight,
+		&m.CreatedAt,
+		&m.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// FindBySession retrieves all media of a session in order
+func (r *PostgresSessionMediaRepository) FindBySession(ctx context.Context, sessionID string) ([]*models.SessionMedia, error) {
+	return r.queryMedia(ctx, `
+		SELECT `+sessionMediaColumns+`
+		FROM session_media
+		WHERE workout_session_id = $1
+		ORDER BY position ASC
+	`, sessionID)
+}
+
+// FindHighlights retrieves a session together with its media marked as highlights, in order
+// Returns pgx.ErrNoRows if the session does not exist.
+func (r *PostgresSessionMediaRepository) FindHighlights(ctx context.Context, sessionID string) (*models.SessionHighlights, error) {
+	highlights := &models.SessionHighlights{SessionID: sessionID}
+	err := r.db.QueryRow(
+		ctx,
+		`SELECT name, started_at FROM workout_sessions WHERE id = $1`,
+		sessionID,
+	).Scan(&highlights.Name, &highlights.StartedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	highlights.Items, err = r.queryMedia(ctx, `
+		SELECT `+sessionMediaColumns+`
+		FROM session_media
+		WHERE workout_session_id = $1 AND highlight = TRUE
+		ORDER BY position ASC
+	`, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if highlights.Items == nil {
+		highlights.Items = []*models.SessionMedia{}
+	}
+
+	return highlights, nil
+}
+
+func (r *PostgresSessionMediaRepository) queryMedia(ctx context.Context, query string, args ...any) ([]*models.SessionMedia, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var media []*models.SessionMedia
+	for rows.Next() {
+		m, err := scanSessionMedia(rows)
+		if err != nil {
+			return nil, err
+		}
+		media = append(media, m)
+	}
+
+	return media, rows.Err()
+}
+
+// Create appends a media item to the end of its session
+func (r *PostgresSessionMediaRepository) Create(ctx context.Context, media *models.SessionMedia) error {
+	query := `
+		INSERT INTO session_media (
+			workout_session_id, user_id, media_type, url, thumbnail_url, caption,
+			duration_seconds, highlight, position
+		)
+		SELECT $1, $2, $3, $4, $5, $6, $7, $8, COALESCE(MAX(position) + 1, 0)
+		FROM session_media
+		WHERE workout_session_id = $1
+		RETURNING id, position, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		media.SessionID,
+		media.UserID,
+		media.MediaType,
+		media.URL,
+		media.ThumbnailURL,
+		media.Caption,
+		media.DurationSeconds,
+		media.Highlight,
+	).Scan(&media.ID, &media.Position, &media.CreatedAt, &media.UpdatedAt)
+}
+
+// Update saves the caption and highlight flag of a media item
+// Returns pgx.ErrNoRows if the item does not belong to the session.
+func (r *PostgresSessionMediaRepository) Update(ctx context.Context, media *models.SessionMedia) error {
+	query := `
+		UPDATE session_media
+		SET caption = $3, highlight = $4
+		WHERE id = $1 AND workout_session_id = $2
+		RETURNING updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		media.ID,
+		media.SessionID,
+		media.Caption,
+		media.Highlight,
+	).Scan(&media.UpdatedAt)
+}
+
+// Delete removes a media item and closes the gap it leaves in the ordering
+// Returns pgx.ErrNoRows if the item does not belong to the session.
+func (r *PostgresSessionMediaRepository) Delete(ctx context.Context, sessionID string, mediaID string) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	var position int
+	err = tx.QueryRow(
+		ctx,
+		`DELETE FROM session_media WHERE id = $1 AND workout_session_id = $2 RETURNING position`,
+		mediaID,
+		sessionID,
+	).Scan(&position)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		`UPDATE session_media SET position = position - 1 WHERE workout_session_id = $1 AND position > $2`,
+		sessionID,
+		position,
+	)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Reorder sets each item's position to its index in mediaIDs
+// mediaIDs must list every media item of the session.
+func (r *PostgresSessionMediaRepository) Reorder(ctx context.Context, sessionID string, mediaIDs []string) error {
+	query := `
+		UPDATE session_media
+		SET position = array_position($2::uuid[], id) - 1
+		WHERE workout_session_id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, sessionID, mediaIDs)
+	return err
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockSessionMediaRepository is a mock implementation for testing
+type MockSessionMediaRepository struct {
+	FindBySessionFunc  func(ctx context.Context, sessionID string) ([]*models.SessionMedia, error)
+	FindHighlightsFunc func(ctx context.Context, sessionID string) (*models.SessionHighlights, error)
+	CreateFunc         func(ctx context.Context, media *models.SessionMedia) error
+	UpdateFunc         func(ctx context.Context, media *models.SessionMedia) error
+	DeleteFunc         func(ctx context.Context, sessionID string, mediaID string) error
+	ReorderFunc        func(ctx context.Context, sessionID string, mediaIDs []string) error
+}
+
+func (m *MockSessionMediaRepository) FindBySession(ctx context.Context, sessionID string) ([]*models.SessionMedia, error) {
+	if m.FindBySessionFunc != nil {
+		return m.FindBySessionFunc(ctx, sessionID)
+	}
+	return []*models.SessionMedia{}, nil
+}
+
+func (m *MockSessionMediaRepository) FindHighlights(ctx context.Context, sessionID string) (*models.SessionHighlights, error) {
+	if m.FindHighlightsFunc != nil {
+		return m.FindHighlightsFunc(ctx, sessionID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockSessionMediaRepository) Create(ctx context.Context, media *models.SessionMedia) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, media)
+	}
+	media.ID = "mock-media-id"
+	return nil
+}
+
+func (m *MockSessionMediaRepository) Update(ctx context.Context, media *models.SessionMedia) error {
+	if m.UpdateFunc != nil {
+		return m.UpdateFunc(ctx, media)
+	}
+	return nil
+}
+
+func (m *MockSessionMediaRepository) Delete(ctx context.Context, sessionID string, mediaID string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, sessionID, mediaID)
+	}
+	return nil
+}
+
+func (m *MockSessionMediaRepository) Reorder(ctx context.Context, sessionID string, mediaIDs []string) error {
+	if m.ReorderFunc != nil {
+		return m.ReorderFunc(ctx, sessionID, mediaIDs)
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockSessionRepository is a mock implementation for testing
+type MockSessionRepository struct {
+	FindByIDFunc   func(ctx context.Context, id string) (*models.Session, error)
+	FindOwnerFunc  func(ctx context.Context, id string) (string, error)
+	ListByUserFunc func(ctx context.Context, userID string, filter models.SessionFilter) ([]*models.SessionSummary, error)
+}
+
+func (m *MockSessionRepository) FindByID(ctx context.Context, id string) (*models.Session, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockSessionRepository) FindOwner(ctx context.Context, id string) (string, error) {
+	if m.FindOwnerFunc != nil {
+		return m.FindOwnerFunc(ctx, id)
+	}
+	return "", pgx.ErrNoRows
+}
+
+func (m *MockSessionRepository) ListByUser(ctx context.Context, userID string, filter models.SessionFilter) ([]*models.SessionSummary, error) {
+	if m.ListByUserFunc != nil {
+		return m.ListByUserFunc(ctx, userID, filter)
+	}
+	return []*models.SessionSummary{}, nil
+}
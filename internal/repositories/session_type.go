@@ -0,0 +1,188 @@
+package repositories
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// SessionTypeRepository defines the interface for the session type registry and typed sessions
+type SessionTypeRepository interface {
+	List(ctx context.Context) ([]*models.SessionType, error)
+	FindByName(ctx context.Context, name string) (*models.SessionType, error)
+	Create(ctx context.Context, sessionType *models.SessionType) error
+	SetSessionType(ctx context.Context, sessionID string, sessionType string, payload map[string]any) error
+	Summaries(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error)
+	PayloadMetrics(ctx context.Context, userID string, sessionType string, summaries map[string]string, from, to time.Time) (map[string]float64, error)
+}
+
+// PostgresSessionTypeRepository is the PostgreSQL implementation of SessionTypeRepository
+type PostgresSessionTypeRepository struct {
+	db DB
+}
+
+// NewPostgresSessionTypeRepository creates a new PostgreSQL session type repository
+func NewPostgresSessionTypeRepository(db DB) SessionTypeRepository {
+	return &PostgresSessionTypeRepository{db: db}
+}
+
+const sessionTypeColumns = `name, display_name, description, payload_schema, builtin, created_at, updated_at`
+
+func scanSessionType(row pgx.Row) (*models.SessionType, error) {
+	t := &models.SessionType{}
+	err := row.Scan(&t.Name, &t.DisplayName, &t.Description, &t.PayloadSchema, &t.Builtin, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// List retrieves every registered session type, built-in ones first
+func (r *PostgresSessionTypeRepository) List(ctx context.Context) ([]*models.SessionType, error) {
+	query := `SELECT ` + sessionTypeColumns + ` FROM session_types ORDER BY builtin DESC, name ASC`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	types := []*models.SessionType{}
+	for rows.Next() {
+		t, err := scanSessionType(rows)
+		if err != nil {
+			return nil, err
+		}
+		types = append(types, t)
+	}
+
+	return types, rows.Err()
+}
+
+// FindByName retrieves a session type
+func (r *PostgresSessionTypeRepository) FindByName(ctx context.Context, name string) (*models.SessionType, error) {
+	query := `SELECT ` + sessionTypeColumns + ` FROM session_types WHERE name = $1`
+	return scanSessionType(r.db.QueryRow(ctx, query, name))
+}
+
+// Create registers a session type
+// Returns pgx.ErrNoRows if a type with the same name exists.
+func (r *PostgresSessionTypeRepository) Create(ctx context.Context, sessionType *models.SessionType) error {
+	query := `
+		INSERT INTO session_types (name, display_name, description, payload_schema)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (name) DO NOTHING
+		RETURNING builtin, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		sessionType.Name,
+		sessionType.DisplayName,
+		sessionType.Description,
+		sessionType.PayloadSchema,
+	).Scan(&sessionType.Builtin, &sessionType.CreatedAt, &sessionType.UpdatedAt)
+}
+
+// SetSessionType stores a session's type and payload
+func (r *PostgresSessionTypeRepository) SetSessionType(ctx context.Context, sessionID string, sessionType string, payload map[string]any) error {
+	query := `UPDATE workout_sessions SET session_type = $2, type_payload = $3 WHERE id = $1`
+
+	tag, err := r.db.Exec(ctx, query, sessionID, sessionType, payload)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Summaries counts the user's completed sessions and their minutes per type for sessions
+// started in [from, to)
+func (r *PostgresSessionTypeRepository) Summaries(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error) {
+	query := `
+		SELECT session_type, COUNT(*), COALESCE(SUM(duration_minutes), 0)
+		FROM workout_sessions
+		WHERE user_id = $1 AND status = 'completed' AND started_at >= $2 AND started_at < $3
+		GROUP BY session_type
+		ORDER BY session_type ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summaries := []*models.SessionTypeSummary{}
+	for rows.Next() {
+		s := &models.SessionTypeSummary{Metrics: map[string]float64{}}
+		if err := rows.Scan(&s.Type, &s.Sessions, &s.DurationMinutes); err != nil {
+			return nil, err
+		}
+		summaries = append(summaries, s)
+	}
+
+	return summaries, rows.Err()
+}
+
+// payloadAggregates maps payload field summaries to SQL aggregates
+var payloadAggregates = map[string]string{
+	models.PayloadSummarySum: "SUM",
+	models.PayloadSummaryAvg: "AVG",
+	models.PayloadSummaryMax: "MAX",
+}
+
+// PayloadMetrics aggregates numeric payload fields (field name to summary) over the user's
+// completed sessions of a type started in [from, to). Values that are not numbers are ignored,
+// and fields without any value are left out.
+func (r *PostgresSessionTypeRepository) PayloadMetrics(ctx context.Context, userID string, sessionType string, summaries map[string]string, from, to time.Time) (map[string]float64, error) {
+	metrics := map[string]float64{}
+	if len(summaries) == 0 {
+		return metrics, nil
+	}
+
+	args := []any{userID, sessionType, from, to}
+	var fields, columns []string
+	for field, summary := range summaries {
+		aggregate, ok := payloadAggregates[summary]
+		if !ok {
+			continue
+		}
+		args = append(args, field)
+		param := "$" + strconv.Itoa(len(args))
+		fields = append(fields, field)
+		columns = append(columns, aggregate+`(CASE WHEN jsonb_typeof(type_payload->`+param+`) = 'number' THEN (type_payload->>`+param+`)::float8 END)`)
+	}
+	if len(columns) == 0 {
+		return metrics, nil
+	}
+
+	query := `
+		SELECT ` + strings.Join(columns, ", ") + `
+		FROM workout_sessions
+		WHERE user_id = $1 AND session_type = $2 AND status = 'completed' AND started_at >= $3 AND started_at < $4
+	`
+
+	values := make([]*float64, len(columns))
+	dest := make([]any, len(columns))
+	for i := range values {
+		dest[i] = &values[i]
+	}
+	if err := r.db.QueryRow(ctx, query, args...).Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	for i, field := range fields {
+		if values[i] != nil {
+			metrics[field] = *values[i]
+		}
+	}
+	return metrics, nil
+}
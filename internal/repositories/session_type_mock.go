@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockSessionTypeRepository is a mock implementation for testing
+type MockSessionTypeRepository struct {
+	ListFunc           func(ctx context.Context) ([]*models.SessionType, error)
+	FindByNameFunc     func(ctx context.Context, name string) (*models.SessionType, error)
+	CreateFunc         func(ctx context.Context, sessionType *models.SessionType) error
+	SetSessionTypeFunc func(ctx context.Context, sessionID string, sessionType string, payload map[string]any) error
+	SummariesFunc      func(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error)
+	PayloadMetricsFunc func(ctx context.Context, userID string, sessionType string, summaries map[string]string, from, to time.Time) (map[string]float64, error)
+}
+
+func (m *MockSessionTypeRepository) List(ctx context.Context) ([]*models.SessionType, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx)
+	}
+	return []*models.SessionType{}, nil
+}
+
+func (m *MockSessionTypeRepository) FindByName(ctx context.Context, name string) (*models.SessionType, error) {
+	if m.FindByNameFunc != nil {
+		return m.FindByNameFunc(ctx, name)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockSessionTypeRepository) Create(ctx context.Context, sessionType *models.SessionType) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, sessionType)
+	}
+	return nil
+}
+
+func (m *MockSessionTypeRepository) SetSessionType(ctx context.Context, sessionID string, sessionType string, payload map[string]any) error {
+	if m.SetSessionTypeFunc != nil {
+		return m.SetSessionTypeFunc(ctx, sessionID, sessionType, payload)
+	}
+	return nil
+}
+
+func (m *MockSessionTypeRepository) Summaries(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error) {
+	if m.SummariesFunc != nil {
+		return m.SummariesFunc(ctx, userID, from, to)
+	}
+	return []*models.SessionTypeSummary{}, nil
+}
+
+func (m *MockSessionTypeRepository) PayloadMetrics(ctx context.Context, userID string, sessionType string, summaries map[string]string, from, to time.Time) (map[string]float64, error) {
+	if m.PayloadMetricsFunc != nil {
+		return m.PayloadMetricsFunc(ctx, userID, sessionType, summaries, from, to)
+	}
+	return map[string]float64{}, nil
+}
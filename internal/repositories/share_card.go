@@ -0,0 +1,155 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// ShareCardRepository defines the interface for share card data access
+type ShareCardRepository interface {
+	Create(ctx context.Context, card *models.ShareCard) error
+	FindByID(ctx context.Context, id string) (*models.ShareCard, error)
+	FindImage(ctx context.Context, id string) ([]byte, error)
+	Complete(ctx context.Context, id string, image []byte) error
+	Fail(ctx context.Context, id string, reason string) error
+	Summary(ctx context.Context, sessionID string) (*models.ShareCardSummary, error)
+}
+
+// PostgresShareCardRepository is the PostgreSQL implementation of ShareCardRepository
+type PostgresShareCardRepository struct {
+	db DB
+}
+
+// NewPostgresShareCardRepository creates a new PostgreSQL share card repository
+func NewPostgresShareCardRepository(db DB) ShareCardRepository {
+	return &PostgresShareCardRepository{db: db}
+}
+
+const shareCardColumns = `id, user_id, workout_session_id, format, status, error, created_at, completed_at`
+
+func scanShareCard(row pgx.Row) (*models.ShareCard, error) {
+	card := &models.ShareCard{}
+	err := row.Scan(
+		&card.ID,
+		&card.UserID,
+		&card.SessionID,
+		&card.Format,
+		&card.Status,
+		&card.Error,
+		&card.CreatedAt,
+		&card.CompletedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return card, nil
+}
+
+// Create inserts a pending share card
+func (r *PostgresShareCardRepository) Create(ctx context.Context, card *models.ShareCard) error {
+	query := `
+		INSERT INTO share_cards (user_id, workout_session_id, format, status)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	return r.db.QueryRow(ctx, query, card.UserID, card.SessionID, card.Format, card.Status).Scan(&card.ID, &card.CreatedAt)
+}
+
+// FindByID retrieves a share card's status without its image
+func (r *PostgresShareCardRepository) FindByID(ctx context.Context, id string) (*models.ShareCard, error) {
+	query := `SELECT ` + shareCardColumns + ` FROM share_cards WHERE id = $1`
+	return scanShareCard(r.db.QueryRow(ctx, query, id))
+}
+
+// FindImage retrieves the rendered image of a ready share card
+func (r *PostgresShareCardRepository) FindImage(ctx context.Context, id string) ([]byte, error) {
+	query := `SELECT image FROM share_cards WHERE id = $1 AND status = 'ready'`
+
+	var image []byte
+	err := r.db.QueryRow(ctx, query, id).Scan(&image)
+	return image, err
+}
+
+// Complete stores the rendered image and marks the share card ready
+func (r *PostgresShareCardRepository) Complete(ctx context.Context, id string, image []byte) error {
+	query := `
+		UPDATE share_cards
+		SET status = 'ready', image = $2, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, image)
+	return err
+}
+
+// Fail marks the share card failed with a reason
+func (r *PostgresShareCardRepository) Fail(ctx context.Context, id string, reason string) error {
+	query := `
+		UPDATE share_cards
+		SET status = 'failed', error = $2, completed_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, reason)
+	return err
+}
+
+// Summary totals what a share card shows of a session: its volume, completed sets and
+// distinct exercises over the logs that weren't skipped, and the personal records set in it
+func (r *PostgresShareCardRepository) Summary(ctx context.Context, sessionID string) (*models.ShareCardSummary, error) {
+	query := `
+		SELECT s.user_id, s.status, s.name, s.session_type, s.started_at, s.duration_minutes,
+			COALESCE(SUM(l.weight_kg * l.reps_completed), 0)::float8,
+			COALESCE(SUM(l.sets_completed), 0)::int,
+			COUNT(DISTINCT l.exercise_id)::int
+		FROM workout_sessions s
+		LEFT JOIN exercise_logs l ON l.workout_session_id = s.id AND l.skipped_at IS NULL
+		WHERE s.id = $1
+		GROUP BY s.id
+	`
+
+	summary := &models.ShareCardSummary{}
+	err := r.db.QueryRow(ctx, query, sessionID).Scan(
+		&summary.UserID,
+		&summary.Status,
+		&summary.Name,
+		&summary.SessionType,
+		&summary.StartedAt,
+		&summary.DurationMinutes,
+		&summary.VolumeKg,
+		&summary.Sets,
+		&summary.Exercises,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	recordsQuery := `
+		SELECT e.name, l.weight_kg, l.reps_completed
+		FROM exercise_logs l
+		JOIN exercises e ON e.id = l.exercise_id
+		WHERE l.workout_session_id = $1 AND l.is_personal_record AND l.weight_kg IS NOT NULL
+		  AND l.skipped_at IS NULL
+		ORDER BY l.weight_kg DESC, l.order_index
+	`
+
+	rows, err := r.db.Query(ctx, recordsQuery, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	summary.Records = []*models.ShareCardRecord{}
+	for rows.Next() {
+		record := &models.ShareCardRecord{}
+		if err := rows.Scan(&record.ExerciseName, &record.WeightKg, &record.Reps); err != nil {
+			return nil, err
+		}
+		summary.Records = append(summary.Records, record)
+	}
+
+	return summary, rows.Err()
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockShareCardRepository is a mock implementation for testing
+type MockShareCardRepository struct {
+	CreateFunc    func(ctx context.Context, card *models.ShareCard) error
+	FindByIDFunc  func(ctx context.Context, id string) (*models.ShareCard, error)
+	FindImageFunc func(ctx context.Context, id string) ([]byte, error)
+	CompleteFunc  func(ctx context.Context, id string, image []byte) error
+	FailFunc      func(ctx context.Context, id string, reason string) error
+	SummaryFunc   func(ctx context.Context, sessionID string) (*models.ShareCardSummary, error)
+}
+
+func (m *MockShareCardRepository) Create(ctx context.Context, card *models.ShareCard) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, card)
+	}
+	card.ID = "mock-share-card-id"
+	return nil
+}
+
+func (m *MockShareCardRepository) FindByID(ctx context.Context, id string) (*models.ShareCard, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockShareCardRepository) FindImage(ctx context.Context, id string) ([]byte, error) {
+	if m.FindImageFunc != nil {
+		return m.FindImageFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockShareCardRepository) Complete(ctx context.Context, id string, image []byte) error {
+	if m.CompleteFunc != nil {
+		return m.CompleteFunc(ctx, id, image)
+	}
+	return nil
+}
+
+func (m *MockShareCardRepository) Fail(ctx context.Context, id string, reason string) error {
+	if m.FailFunc != nil {
+		return m.FailFunc(ctx, id, reason)
+	}
+	return nil
+}
+
+func (m *MockShareCardRepository) Summary(ctx context.Context, sessionID string) (*models.ShareCardSummary, error) {
+	if m.SummaryFunc != nil {
+		return m.SummaryFunc(ctx, sessionID)
+	}
+	return nil, pgx.ErrNoRows
+}
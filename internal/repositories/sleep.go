@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// SleepRepository defines the interface for sleep log data access
+type SleepRepository interface {
+	Create(ctx context.Context, log *models.SleepLog) error
+	CreateMany(ctx context.Context, logs []*models.SleepLog) (int64, error)
+	FindByUser(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepLog, error)
+	Delete(ctx context.Context, userID string, id string) error
+}
+
+// PostgresSleepRepository is the PostgreSQL implementation of SleepRepository
+type PostgresSleepRepository struct {
+	db DB
+}
+
+// NewPostgresSleepRepository creates a new PostgreSQL sleep repository
+func NewPostgresSleepRepository(db DB) SleepRepository {
+	return &PostgresSleepRepository{db: db}
+}
+
+// Create inserts a sleep log and sets its ID, duration and timestamps
+// Returns ErrDuplicate if the user already has sleep starting at the same time.
+func (r *PostgresSleepRepository) Create(ctx context.Context, log *models.SleepLog) error {
+	query := `
+		INSERT INTO sleep_logs (user_id, started_at, ended_at, quality, source)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, duration_minutes, created_at, updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		log.UserID,
+		log.StartedAt,
+		log.EndedAt,
+		log.Quality,
+		log.Source,
+	).Scan(&log.ID, &log.DurationMinutes, &log.CreatedAt, &log.UpdatedAt)
+
+	return translateError(err)
+}
+
+// CreateMany inserts sleep logs in one transaction, skipping starts the user already
+// has, and returns how many rows were inserted
+func (r *PostgresSleepRepository) CreateMany(ctx context.Context, logs []*models.SleepLog) (int64, error) {
+	query := `
+		INSERT INTO sleep_logs (user_id, started_at, ended_at, quality, source)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, started_at) DO NOTHING
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	batch := &pgx.Batch{}
+	for _, l := range logs {
+		batch.Queue(query, l.UserID, l.StartedAt, l.EndedAt, l.Quality, l.Source)
+	}
+
+	results := tx.SendBatch(ctx, batch)
+	var inserted int64
+	for range logs {
+		tag, err := results.Exec()
+		if err != nil {
+			results.Close()
+			return 0, err
+		}
+		inserted += tag.RowsAffected()
+	}
+	if err := results.Close(); err != nil {
+		return 0, err
+	}
+
+	return inserted, tx.Commit(ctx)
+}
+
+// FindByUser retrieves the user's sleep that ended on the dates from to to (exclusive) of
+// their timezone, newest first
+func (r *PostgresSleepRepository) FindByUser(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepLog, error) {
+	query := `
+		SELECT l.id, l.user_id, l.started_at, l.ended_at, l.duration_minutes, l.quality, l.source, l.created_at, l.updated_at
+		FROM sleep_logs l, (SELECT user_timezone($1) AS tz) z
+		WHERE l.user_id = $1
+		  AND l.ended_at >= ($2::date::timestamp AT TIME ZONE z.tz)
+		  AND l.ended_at < ($3::date::timestamp AT TIME ZONE z.tz)
+		ORDER BY l.ended_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	logs := []*models.SleepLog{}
+	for rows.Next() {
+		l := &models.SleepLog{}
+		err := rows.Scan(
+			&l.ID,
+			&l.UserID,
+			&l.StartedAt,
+			&l.EndedAt,
+			&l.DurationMinutes,
+			&l.Quality,
+			&l.Source,
+			&l.CreatedAt,
+			&l.UpdatedAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, rows.Err()
+}
+
+// Delete removes one of the user's sleep logs
+// Returns pgx.ErrNoRows if it does not exist or belongs to someone else.
+func (r *PostgresSleepRepository) Delete(ctx context.Context, userID string, id string) error {
+	query := `DELETE FROM sleep_logs WHERE id = $1 AND user_id = $2`
+
+	result, err := r.db.Exec(ctx, query, id, userID)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
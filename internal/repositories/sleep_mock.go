@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockSleepRepository is a mock implementation for testing
+type MockSleepRepository struct {
+	CreateFunc     func(ctx context.Context, log *models.SleepLog) error
+	CreateManyFunc func(ctx context.Context, logs []*models.SleepLog) (int64, error)
+	FindByUserFunc func(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepLog, error)
+	DeleteFunc     func(ctx context.Context, userID string, id string) error
+}
+
+func (m *MockSleepRepository) Create(ctx context.Context, log *models.SleepLog) error {
+	if m.CreateFunc != nil {
+		return m.CreateFunc(ctx, log)
+	}
+	log.ID = "mock-sleep-log-id"
+	log.DurationMinutes = int(log.EndedAt.Sub(log.StartedAt).Minutes())
+	return nil
+}
+
+func (m *MockSleepRepository) CreateMany(ctx context.Context, logs []*models.SleepLog) (int64, error) {
+	if m.CreateManyFunc != nil {
+		return m.CreateManyFunc(ctx, logs)
+	}
+	return int64(len(logs)), nil
+}
+
+func (m *MockSleepRepository) FindByUser(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepLog, error) {
+	if m.FindByUserFunc != nil {
+		return m.FindByUserFunc(ctx, userID, from, to)
+	}
+	return []*models.SleepLog{}, nil
+}
+
+func (m *MockSleepRepository) Delete(ctx context.Context, userID string, id string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, id)
+	}
+	return nil
+}
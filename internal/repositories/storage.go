@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// StorageRepository defines the interface for database usage statistics
+type StorageRepository interface {
+	DatabaseSize(ctx context.Context) (int64, error)
+	TableStats(ctx context.Context) ([]*models.TableStorage, error)
+	LargestUsers(ctx context.Context, limit int) ([]*models.UserStorage, error)
+}
+
+// PostgresStorageRepository is the PostgreSQL implementation of StorageRepository
+type PostgresStorageRepository struct {
+	db DB
+}
+
+// NewPostgresStorageRepository creates a new PostgreSQL storage repository
+func NewPostgresStorageRepository(db DB) StorageRepository {
+	return &PostgresStorageRepository{db: db}
+}
+
+// DatabaseSize returns the on-disk size of the current database
+func (r *PostgresStorageRepository) DatabaseSize(ctx context.Context) (int64, error) {
+	var size int64
+	err := r.db.QueryRow(ctx, `SELECT pg_database_size(current_database())`).Scan(&size)
+	return size, err
+}
+
+// TableStats returns row estimates and sizes for every application table
+func (r *PostgresStorageRepository) TableStats(ctx context.Context) ([]*models.TableStorage, error) {
+	query := `
+		SELECT relname,
+		       n_live_tup,
+		       pg_total_relation_size(relid),
+		       pg_relation_size(relid),
+		       pg_indexes_size(relid)
+		FROM pg_stat_user_tables
+		WHERE schemaname = 'public'
+		ORDER BY pg_total_relation_size(relid) DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []*models.TableStorage
+	for rows.Next() {
+		table := &models.TableStorage{}
+		err := rows.Scan(
+			&table.Table,
+			&table.RowCount,
+			&table.TotalBytes,
+			&table.TableBytes,
+			&table.IndexBytes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+// LargestUsers ranks users by the size of the rows they own
+// This scans every user-owned table, so it is meant for occasional admin use only.
+func (r *PostgresStorageRepository) LargestUsers(ctx context.Context, limit int) ([]*models.UserStorage, error) {
+	query := `
+		SELECT user_id, SUM(row_count)::BIGINT, SUM(bytes)::BIGINT
+		FROM (
+			SELECT user_id, COUNT(*) AS row_count, SUM(pg_column_size(t.*)) AS bytes
+			FROM equipment t GROUP BY user_id
+			UNION ALL
+			SELECT user_id, COUNT(*), SUM(pg_column_size(t.*))
+			FROM exercises t GROUP BY user_id
+			UNION ALL
+			SELECT user_id, COUNT(*), SUM(pg_column_size(t.*))
+			FROM workouts t GROUP BY user_id
+			UNION ALL
+			SELECT user_id, COUNT(*), SUM(pg_column_size(t.*))
+			FROM workout_sessions t GROUP BY user_id
+			UNION ALL
+			SELECT s.user_id, COUNT(*), SUM(pg_column_size(l.*))
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			GROUP BY s.user_id
+		) usage
+		GROUP BY user_id
+		ORDER BY SUM(bytes) DESC
+		LIMIT $1
+	`
+
+	rows, err := r.db.Query(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*models.UserStorage
+	for rows.Next() {
+		user := &models.UserStorage{}
+		if err := rows.Scan(&user.UserID, &user.RowCount, &user.Bytes); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockStorageRepository is a mock implementation for testing
+type MockStorageRepository struct {
+	DatabaseSizeFunc func(ctx context.Context) (int64, error)
+	TableStatsFunc   func(ctx context.Context) ([]*models.TableStorage, error)
+	LargestUsersFunc func(ctx context.Context, limit int) ([]*models.UserStorage, error)
+}
+
+func (m *MockStorageRepository) DatabaseSize(ctx context.Context) (int64, error) {
+	if m.DatabaseSizeFunc != nil {
+		return m.DatabaseSizeFunc(ctx)
+	}
+	return 0, nil
+}
+
+func (m *MockStorageRepository) TableStats(ctx context.Context) ([]*models.TableStorage, error) {
+	if m.TableStatsFunc != nil {
+		return m.TableStatsFunc(ctx)
+	}
+	return []*models.TableStorage{}, nil
+}
+
+func (m *MockStorageRepository) LargestUsers(ctx context.Context, limit int) ([]*models.UserStorage, error) {
+	if m.LargestUsersFunc != nil {
+		return m.LargestUsersFunc(ctx, limit)
+	}
+	return []*models.UserStorage{}, nil
+}
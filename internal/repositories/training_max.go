@@ -0,0 +1,115 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// TrainingMaxRepository defines the interface for training max data access
+type TrainingMaxRepository interface {
+	FindByUser(ctx context.Context, userID string) ([]*models.TrainingMax, error)
+	FindByName(ctx context.Context, userID string, name string) (*models.TrainingMax, error)
+	Upsert(ctx context.Context, tm *models.TrainingMax) error
+	Delete(ctx context.Context, userID string, name string) error
+}
+
+// PostgresTrainingMaxRepository is the PostgreSQL implementation of TrainingMaxRepository
+type PostgresTrainingMaxRepository struct {
+	db DB
+}
+
+// NewPostgresTrainingMaxRepository creates a new PostgreSQL training max repository
+func NewPostgresTrainingMaxRepository(db DB) TrainingMaxRepository {
+	return &PostgresTrainingMaxRepository{db: db}
+}
+
+const trainingMaxColumns = `id, user_id, name, exercise_id, weight_kg, created_at, updated_at`
+
+func scanTrainingMax(row pgx.Row) (*models.TrainingMax, error) {
+	tm := &models.TrainingMax{}
+	err := row.Scan(
+		&tm.ID,
+		&tm.UserID,
+		&tm.Name,
+		&tm.ExerciseID,
+		&tm.WeightKg,
+		&tm.CreatedAt,
+		&tm.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return tm, nil
+}
+
+// FindByUser retrieves all of a user's training maxes
+func (r *PostgresTrainingMaxRepository) FindByUser(ctx context.Context, userID string) ([]*models.TrainingMax, error) {
+	query := `
+		SELECT ` + trainingMaxColumns + `
+		FROM training_maxes
+		WHERE user_id = $1
+		ORDER BY name ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tms []*models.TrainingMax
+	for rows.Next() {
+		tm, err := scanTrainingMax(rows)
+		if err != nil {
+			return nil, err
+		}
+		tms = append(tms, tm)
+	}
+
+	return tms, rows.Err()
+}
+
+// FindByName retrieves a single training max by its name
+func (r *PostgresTrainingMaxRepository) FindByName(ctx context.Context, userID string, name string) (*models.TrainingMax, error) {
+	query := `SELECT ` + trainingMaxColumns + ` FROM training_maxes WHERE user_id = $1 AND name = $2`
+	return scanTrainingMax(r.db.QueryRow(ctx, query, userID, name))
+}
+
+// Upsert creates the training max or replaces the weight of an existing one
+func (r *PostgresTrainingMaxRepository) Upsert(ctx context.Context, tm *models.TrainingMax) error {
+	query := `
+		INSERT INTO training_maxes (user_id, name, exercise_id, weight_kg)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, name) DO UPDATE
+		SET exercise_id = EXCLUDED.exercise_id, weight_kg = EXCLUDED.weight_kg
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		tm.UserID,
+		tm.Name,
+		tm.ExerciseID,
+		tm.WeightKg,
+	).Scan(&tm.ID, &tm.CreatedAt, &tm.UpdatedAt)
+}
+
+// Delete removes a training max
+// Returns pgx.ErrNoRows if it does not exist.
+func (r *PostgresTrainingMaxRepository) Delete(ctx context.Context, userID string, name string) error {
+	query := `DELETE FROM training_maxes WHERE user_id = $1 AND name = $2`
+
+	result, err := r.db.Exec(ctx, query, userID, name)
+	if err != nil {
+		return err
+	}
+
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	return nil
+}
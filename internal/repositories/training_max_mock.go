@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockTrainingMaxRepository is a mock implementation for testing
+type MockTrainingMaxRepository struct {
+	FindByUserFunc func(ctx context.Context, userID string) ([]*models.TrainingMax, error)
+	FindByNameFunc func(ctx context.Context, userID string, name string) (*models.TrainingMax, error)
+	UpsertFunc     func(ctx context.Context, tm *models.TrainingMax) error
+	DeleteFunc     func(ctx context.Context, userID string, name string) error
+}
+
+func (m *MockTrainingMaxRepository) FindByUser(ctx context.Context, userID string) ([]*models.TrainingMax, error) {
+	if m.FindByUserFunc != nil {
+		return m.FindByUserFunc(ctx, userID)
+	}
+	return []*models.TrainingMax{}, nil
+}
+
+func (m *MockTrainingMaxRepository) FindByName(ctx context.Context, userID string, name string) (*models.TrainingMax, error) {
+	if m.FindByNameFunc != nil {
+		return m.FindByNameFunc(ctx, userID, name)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockTrainingMaxRepository) Upsert(ctx context.Context, tm *models.TrainingMax) error {
+	if m.UpsertFunc != nil {
+		return m.UpsertFunc(ctx, tm)
+	}
+	tm.ID = "mock-training-max-id"
+	return nil
+}
+
+func (m *MockTrainingMaxRepository) Delete(ctx context.Context, userID string, name string) error {
+	if m.DeleteFunc != nil {
+		return m.DeleteFunc(ctx, userID, name)
+	}
+	return nil
+}
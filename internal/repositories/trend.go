@@ -0,0 +1,222 @@
+package repositories
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// TrendRepository defines the interface for metric trends and their monthly snapshots
+type TrendRepository interface {
+	Points(ctx context.Context, userID string, metric string, granularity string, from, to time.Time) ([]*models.TrendPoint, error)
+	Snapshots(ctx context.Context, userID string, metric string, from, to time.Time) ([]*models.TrendPoint, error)
+	LastSnapshotAt(ctx context.Context) (time.Time, error)
+	FindChangedSince(ctx context.Context, since time.Time) ([]*models.TrendChange, error)
+	RefreshSnapshots(ctx context.Context, userID string, from, to time.Time) error
+}
+
+// PostgresTrendRepository is the PostgreSQL implementation of TrendRepository
+type PostgresTrendRepository struct {
+	db DB
+}
+
+// NewPostgresTrendRepository creates a new PostgreSQL trend repository
+func NewPostgresTrendRepository(db DB) TrendRepository {
+	return &PostgresTrendRepository{db: db}
+}
+
+// trendMetric is the row source of a metric ("at" timestamp and "value" per row, for user $1)
+// and how its values combine within a period
+type trendMetric struct {
+	source    string
+	aggregate string
+}
+
+var trendMetrics = map[string]trendMetric{
+	models.TrendMetricSessions: {
+		source: `
+			SELECT s.started_at AS at, 1::float8 AS value
+			FROM workout_sessions s
+			WHERE s.user_id = $1 AND s.status = 'completed'`,
+		aggregate: "SUM",
+	},
+	models.TrendMetricVolume: {
+		source: `
+			SELECT s.started_at AS at, (COALESCE(l.sets_completed, 1) * l.reps_completed * l.weight_kg)::float8 AS value
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE s.user_id = $1 AND s.status = 'completed' AND l.reps_completed IS NOT NULL AND l.weight_kg IS NOT NULL`,
+		aggregate: "SUM",
+	},
+	models.TrendMetricCardioDistance: {
+		source: `
+			SELECT s.started_at AS at, l.distance_meters::float8 AS value
+			FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE s.user_id = $1 AND s.status = 'completed' AND l.distance_meters IS NOT NULL`,
+		aggregate: "SUM",
+	},
+	models.TrendMetricBodyWeight: {
+		source: `
+			SELECT measured_at AS at, weight_kg::float8 AS value
+			FROM body_measurements
+			WHERE user_id = $1 AND weight_kg IS NOT NULL`,
+		aggregate: "AVG",
+	},
+}
+
+func lookupTrendMetric(metric string, granularity string) (trendMetric, error) {
+	m, ok := trendMetrics[metric]
+	if !ok {
+		return trendMetric{}, fmt.Errorf("unknown trend metric %q", metric)
+	}
+	if granularity != models.TrendGranularityWeek && granularity != models.TrendGranularityMonth {
+		return trendMetric{}, fmt.Errorf("unknown trend granularity %q", granularity)
+	}
+	return m, nil
+}
+
+// Points aggregates a metric live from the user's history into weeks or months of their
+// timezone, for the dates [from, to) there, oldest first
+func (r *PostgresTrendRepository) Points(ctx context.Context, userID string, metric string, granularity string, from, to time.Time) ([]*models.TrendPoint, error) {
+	m, err := lookupTrendMetric(metric, granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	query := `
+		SELECT to_char(date_trunc('` + granularity + `', m.at AT TIME ZONE z.tz), 'YYYY-MM-DD') AS period,
+		       ` + m.aggregate + `(m.value), COUNT(*)
+		FROM (` + m.source + `) m, (SELECT user_timezone($1) AS tz) z
+		WHERE m.at >= ($2::date::timestamp AT TIME ZONE z.tz) AND m.at < ($3::date::timestamp AT TIME ZONE z.tz)
+		GROUP BY period
+		ORDER BY period ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrendPoints(rows)
+}
+
+// Snapshots retrieves the precomputed monthly values of a metric for months in [from, to)
+func (r *PostgresTrendRepository) Snapshots(ctx context.Context, userID string, metric string, from, to time.Time) ([]*models.TrendPoint, error) {
+	query := `
+		SELECT to_char(month, 'YYYY-MM-DD'), value, samples
+		FROM metric_snapshots
+		WHERE user_id = $1 AND metric = $2 AND month >= $3::date AND month < $4::date
+		ORDER BY month ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, metric, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return scanTrendPoints(rows)
+}
+
+func scanTrendPoints(rows pgx.Rows) ([]*models.TrendPoint, error) {
+	defer rows.Close()
+
+	points := []*models.TrendPoint{}
+	for rows.Next() {
+		p := &models.TrendPoint{}
+		if err := rows.Scan(&p.PeriodStart, &p.Value, &p.Samples); err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, rows.Err()
+}
+
+// LastSnapshotAt returns when snapshots were last computed, or the zero time if never
+func (r *PostgresTrendRepository) LastSnapshotAt(ctx context.Context) (time.Time, error) {
+	var last *time.Time
+	if err := r.db.QueryRow(ctx, `SELECT MAX(computed_at) FROM metric_snapshots`).Scan(&last); err != nil {
+		return time.Time{}, err
+	}
+	if last == nil {
+		return time.Time{}, nil
+	}
+	return *last, nil
+}
+
+// FindChangedSince returns, per user, the earliest session or weigh-in created or updated
+// since the given time. Users whose timezone changed get the zero time, as every period
+// of their history moved.
+func (r *PostgresTrendRepository) FindChangedSince(ctx context.Context, since time.Time) ([]*models.TrendChange, error) {
+	query := `
+		SELECT user_id, CASE WHEN bool_or(at IS NULL) THEN NULL ELSE MIN(at) END
+		FROM (
+			SELECT s.user_id, s.started_at AS at FROM workout_sessions s WHERE s.updated_at >= $1
+			UNION ALL
+			SELECT s.user_id, s.started_at FROM exercise_logs l
+			JOIN workout_sessions s ON s.id = l.workout_session_id
+			WHERE l.updated_at >= $1
+			UNION ALL
+			SELECT user_id, measured_at FROM body_measurements WHERE updated_at >= $1
+			UNION ALL
+			SELECT user_id, NULL FROM user_profiles WHERE updated_at >= $1
+		) changes
+		GROUP BY user_id
+	`
+
+	rows, err := r.db.Query(ctx, query, since)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []*models.TrendChange{}
+	for rows.Next() {
+		c := &models.TrendChange{}
+		var from *time.Time
+		if err := rows.Scan(&c.UserID, &from); err != nil {
+			return nil, err
+		}
+		if from != nil {
+			c.From = *from
+		}
+		changes = append(changes, c)
+	}
+
+	return changes, rows.Err()
+}
+
+// RefreshSnapshots recomputes every metric's monthly snapshots of the user for months in
+// [from, to) of their timezone in one transaction. Months left without data lose their
+// snapshot.
+func (r *PostgresTrendRepository) RefreshSnapshots(ctx context.Context, userID string, from, to time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	deleteQuery := `DELETE FROM metric_snapshots WHERE user_id = $1 AND month >= $2::date AND month < $3::date`
+	if _, err := tx.Exec(ctx, deleteQuery, userID, from, to); err != nil {
+		return err
+	}
+
+	for _, metric := range models.TrendMetrics {
+		m := trendMetrics[metric]
+		query := `
+			INSERT INTO metric_snapshots (user_id, metric, month, value, samples)
+			SELECT $1, '` + metric + `', date_trunc('month', m.at AT TIME ZONE z.tz)::date AS month,
+			       ` + m.aggregate + `(m.value), COUNT(*)
+			FROM (` + m.source + `) m, (SELECT user_timezone($1) AS tz) z
+			WHERE m.at >= ($2::date::timestamp AT TIME ZONE z.tz) AND m.at < ($3::date::timestamp AT TIME ZONE z.tz)
+			GROUP BY month
+		`
+		if _, err := tx.Exec(ctx, query, userID, from, to); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
@@ -0,0 +1,52 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockTrendRepository is a mock implementation for testing
+type MockTrendRepository struct {
+	PointsFunc           func(ctx context.Context, userID string, metric string, granularity string, from, to time.Time) ([]*models.TrendPoint, error)
+	SnapshotsFunc        func(ctx context.Context, userID string, metric string, from, to time.Time) ([]*models.TrendPoint, error)
+	LastSnapshotAtFunc   func(ctx context.Context) (time.Time, error)
+	FindChangedSinceFunc func(ctx context.Context, since time.Time) ([]*models.TrendChange, error)
+	RefreshSnapshotsFunc func(ctx context.Context, userID string, from, to time.Time) error
+}
+
+func (m *MockTrendRepository) Points(ctx context.Context, userID string, metric string, granularity string, from, to time.Time) ([]*models.TrendPoint, error) {
+	if m.PointsFunc != nil {
+		return m.PointsFunc(ctx, userID, metric, granularity, from, to)
+	}
+	return []*models.TrendPoint{}, nil
+}
+
+func (m *MockTrendRepository) Snapshots(ctx context.Context, userID string, metric string, from, to time.Time) ([]*models.TrendPoint, error) {
+	if m.SnapshotsFunc != nil {
+		return m.SnapshotsFunc(ctx, userID, metric, from, to)
+	}
+	return []*models.TrendPoint{}, nil
+}
+
+func (m *MockTrendRepository) LastSnapshotAt(ctx context.Context) (time.Time, error) {
+	if m.LastSnapshotAtFunc != nil {
+		return m.LastSnapshotAtFunc(ctx)
+	}
+	return time.Time{}, nil
+}
+
+func (m *MockTrendRepository) FindChangedSince(ctx context.Context, since time.Time) ([]*models.TrendChange, error) {
+	if m.FindChangedSinceFunc != nil {
+		return m.FindChangedSinceFunc(ctx, since)
+	}
+	return []*models.TrendChange{}, nil
+}
+
+func (m *MockTrendRepository) RefreshSnapshots(ctx context.Context, userID string, from, to time.Time) error {
+	if m.RefreshSnapshotsFunc != nil {
+		return m.RefreshSnapshotsFunc(ctx, userID, from, to)
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DB runs a repository's queries: the connection pool, or a transaction of a unit of work.
+// Repositories that begin their own transaction get a savepoint inside a unit of work, so
+// their writes commit or roll back with it.
+type DB interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	SendBatch(ctx context.Context, b *pgx.Batch) pgx.BatchResults
+}
+
+// Repositories are the repositories bound to one DB. The ones that listen for notifications
+// need a pooled connection of their own and aren't included.
+type Repositories struct {
+	Analytics     AnalyticsRepository
+	AuditLogs     AuditLogRepository
+	Challenges    ChallengeRepository
+	Classes       ClassSessionRepository
+	CoachClients  CoachClientRepository
+	CoachPrograms CoachProgramRepository
+	Comments      CommentRepository
+	Emails        EmailRepository
+	Equipment     EquipmentRepository
+	Exercises     ExerciseRepository
+	ExerciseSwaps ExerciseSwapRepository
+	Exports       ExportRepository
+	FeatureFlags  FeatureFlagRepository
+	Feed          FeedRepository
+	Follows       FollowRepository
+	Goals         GoalRepository
+	Hydration     HydrationRepository
+	Idempotency   IdempotencyRepository
+	Imports       ImportRepository
+	Injuries      InjuryRepository
+	Integrations  IntegrationRepository
+	Jobs          JobRepository
+	Leaderboards  ExerciseLeaderboardRepository
+	Measurements  MeasurementRepository
+	Moderation    ModerationRepository
+	Notifications NotificationRepository
+	Organizations OrganizationRepository
+	Profiles      ProfileRepository
+	Progression   ProgressionRepository
+	PublicProfile PublicProfileRepository
+	Push          PushRepository
+	Recommender   RecommendationRepository
+	Reminders     ReminderRepository
+	Reports       ReportRepository
+	RestTimers    RestTimerRepository
+	RevokedTokens RevokedTokenRepository
+	Sessions      SessionRepository
+	SessionLaps   SessionLapRepository
+	SessionMedia  SessionMediaRepository
+	SessionTypes  SessionTypeRepository
+	ShareCards    ShareCardRepository
+	Sleep         SleepRepository
+	Storage       StorageRepository
+	TrainingMaxes TrainingMaxRepository
+	Trends        TrendRepository
+	Users         UserRepository
+	Webhooks      WebhookRepository
+	Workouts      WorkoutRepository
+}
+
+// NewRepositories creates the PostgreSQL repositories bound to db
+func NewRepositories(db DB) *Repositories {
+	return &Repositories{
+		Analytics:     NewPostgresAnalyticsRepository(db),
+		AuditLogs:     NewPostgresAuditLogRepository(db),
+		Challenges:    NewPostgresChallengeRepository(db),
+		Classes:       NewPostgresClassSessionRepository(db),
+		CoachClients:  NewPostgresCoachClientRepository(db),
+		CoachPrograms: NewPostgresCoachProgramRepository(db),
+		Comments:      NewPostgresCommentRepository(db),
+		Emails:        NewPostgresEmailRepository(db),
+		Equipment:     NewPostgresEquipmentRepository(db),
+		Exercises:     NewPostgresExerciseRepository(db),
+		ExerciseSwaps: NewPostgresExerciseSwapRepository(db),
+		Exports:       NewPostgresExportRepository(db),
+		FeatureFlags:  NewPostgresFeatureFlagRepository(db),
+		Feed:          NewPostgresFeedRepository(db),
+		Follows:       NewPostgresFollowRepository(db),
+		Goals:         NewPostgresGoalRepository(db),
+		Hydration:     NewPostgresHydrationRepository(db),
+		Idempotency:   NewPostgresIdempotencyRepository(db),
+		Imports:       NewPostgresImportRepository(db),
+		Injuries:      NewPostgresInjuryRepository(db),
+		Integrations:  NewPostgresIntegrationRepository(db),
+		Jobs:          NewPostgresJobRepository(db),
+		Leaderboards:  NewPostgresExerciseLeaderboardRepository(db),
+		Measurements:  NewPostgresMeasurementRepository(db),
+		Moderation:    NewPostgresModerationRepository(db),
+		Notifications: NewPostgresNotificationRepository(db),
+		Organizations: NewPostgresOrganizationRepository(db),
+		Profiles:      NewPostgresProfileRepository(db),
+		Progression:   NewPostgresProgressionRepository(db),
+		PublicProfile: NewPostgresPublicProfileRepository(db),
+		Push:          NewPostgresPushRepository(db),
+		Recommender:   NewPostgresRecommendationRepository(db),
+		Reminders:     NewPostgresReminderRepository(db),
+		Reports:       NewPostgresReportRepository(db),
+		RestTimers:    NewPostgresRestTimerRepository(db),
+		RevokedTokens: NewPostgresRevokedTokenRepository(db),
+		Sessions:      NewPostgresSessionRepository(db),
+		SessionLaps:   NewPostgresSessionLapRepository(db),
+		SessionMedia:  NewPostgresSessionMediaRepository(db),
+		SessionTypes:  NewPostgresSessionTypeRepository(db),
+		ShareCards:    NewPostgresShareCardRepository(db),
+		Sleep:         NewPostgresSleepRepository(db),
+		Storage:       NewPostgresStorageRepository(db),
+		TrainingMaxes: NewPostgresTrainingMaxRepository(db),
+		Trends:        NewPostgresTrendRepository(db),
+		Users:         NewPostgresUserRepository(db),
+		Webhooks:      NewPostgresWebhookRepository(db),
+		Workouts:      NewPostgresWorkoutRepository(db),
+	}
+}
+
+// UnitOfWork runs a function with repositories bound to one transaction, so a service can
+// compose calls to several repositories atomically
+type UnitOfWork interface {
+	// Do commits when fn returns nil and rolls back when it returns an error or panics
+	Do(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+// PostgresUnitOfWork is the PostgreSQL implementation of UnitOfWork
+type PostgresUnitOfWork struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresUnitOfWork creates a unit of work that begins its transactions on db
+func NewPostgresUnitOfWork(db *pgxpool.Pool) UnitOfWork {
+	return &PostgresUnitOfWork{db: db}
+}
+
+// Do runs fn in a transaction
+func (u *PostgresUnitOfWork) Do(ctx context.Context, fn func(repos *Repositories) error) error {
+	return pgx.BeginFunc(ctx, u.db, func(tx pgx.Tx) error {
+		return fn(NewRepositories(tx))
+	})
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import "context"
+
+// MockUnitOfWork is a mock implementation for testing
+// Do runs fn with Repositories, which tests fill with the mocks the service uses.
+type MockUnitOfWork struct {
+	Repositories *Repositories
+	DoFunc       func(ctx context.Context, fn func(repos *Repositories) error) error
+}
+
+func (m *MockUnitOfWork) Do(ctx context.Context, fn func(repos *Repositories) error) error {
+	if m.DoFunc != nil {
+		return m.DoFunc(ctx, fn)
+	}
+	if m.Repositories == nil {
+		m.Repositories = &Repositories{}
+	}
+	return fn(m.Repositories)
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// UserRepository defines the interface for managing Supabase auth accounts directly
+type UserRepository interface {
+	List(ctx context.Context, filter models.UserFilter) ([]*models.User, error)
+	FindByID(ctx context.Context, id string) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	SetRole(ctx context.Context, id string, role string) error
+	Suspend(ctx context.Context, id string, until time.Time) error
+	Unsuspend(ctx context.Context, id string) error
+}
+
+// PostgresUserRepository is the PostgreSQL implementation of UserRepository, working on
+// Supabase's auth schema
+type PostgresUserRepository struct {
+	db DB
+}
+
+// NewPostgresUserRepository creates a new PostgreSQL user repository
+func NewPostgresUserRepository(db DB) UserRepository {
+	return &PostgresUserRepository{db: db}
+}
+
+const userColumns = `
+	id, COALESCE(email, ''), COALESCE(raw_app_meta_data->>'role', '') = 'admin',
+	created_at, last_sign_in_at, CASE WHEN banned_until > NOW() THEN banned_until END
+`
+
+func scanUser(row pgx.Row) (*models.User, error) {
+	user := &models.User{}
+	err := row.Scan(&user.ID, &user.Email, &user.IsAdmin, &user.CreatedAt, &user.LastSignInAt, &user.SuspendedUntil)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// List retrieves the newest users first, optionally those whose email contains filter.Email,
+// % and _ included as literal characters
+func (r *PostgresUserRepository) List(ctx context.Context, filter models.UserFilter) ([]*models.User, error) {
+	query := `
+		SELECT ` + userColumns + `
+		FROM auth.users
+		WHERE $1 = '' OR email ILIKE '%' || $1 || '%' ESCAPE '\'
+		ORDER BY created_at DESC, id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, escapeLike(filter.Email), filter.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := []*models.User{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	return users, rows.Err()
+}
+
+// FindByID retrieves a user by ID
+func (r *PostgresUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM auth.users WHERE id = $1`, id))
+}
+
+// FindByEmail retrieves a user by email, ignoring case
+func (r *PostgresUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	return scanUser(r.db.QueryRow(ctx, `SELECT `+userColumns+` FROM auth.users WHERE lower(email) = lower($1)`, email))
+}
+
+// SetRole sets app_metadata.role, or removes it when role is empty. Tokens carry the
+// role from the next time they are issued.
+func (r *PostgresUserRepository) SetRole(ctx context.Context, id string, role string) error {
+	query := `
+		UPDATE auth.users
+		SET raw_app_meta_data = CASE
+		        WHEN $2 = '' THEN COALESCE(raw_app_meta_data, '{}'::jsonb) - 'role'
+		        ELSE COALESCE(raw_app_meta_data, '{}'::jsonb) || jsonb_build_object('role', $2::text)
+		    END,
+		    updated_at = NOW()
+		WHERE id = $1
+	`
+
+	result, err := r.db.Exec(ctx, query, id, role)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// Suspend bans the user from signing in until the given time and ends their sessions,
+// so refresh tokens stop working, in one transaction
+func (r *PostgresUserRepository) Suspend(ctx context.Context, id string, until time.Time) error {
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	result, err := tx.Exec(ctx, `UPDATE auth.users SET banned_until = $2, updated_at = NOW() WHERE id = $1`, id, until)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+
+	// Refresh tokens belong to sessions and go with them
+	if _, err := tx.Exec(ctx, `DELETE FROM auth.sessions WHERE user_id = $1`, id); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Unsuspend lifts a ban
+func (r *PostgresUserRepository) Unsuspend(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, `UPDATE auth.users SET banned_until = NULL, updated_at = NOW() WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if result.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// userEventChannel is the notification channel carrying user events between instances
+const userEventChannel = "user_events"
+
+// UserEventRepository defines the interface for passing user events between API instances
+type UserEventRepository interface {
+	Notify(ctx context.Context, event *models.UserEvent) error
+	Listen(ctx context.Context, handle func(*models.UserEvent)) error
+}
+
+// PostgresUserEventRepository is the PostgreSQL implementation of UserEventRepository
+type PostgresUserEventRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPostgresUserEventRepository creates a new PostgreSQL user event repository
+func NewPostgresUserEventRepository(db *pgxpool.Pool) UserEventRepository {
+	return &PostgresUserEventRepository{db: db}
+}
+
+// Notify sends an event to every listening instance, this one included
+func (r *PostgresUserEventRepository) Notify(ctx context.Context, event *models.UserEvent) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	_, err = r.db.Exec(ctx, `SELECT pg_notify($1, $2)`, userEventChannel, string(payload))
+	return err
+}
+
+// Listen calls handle for every user event until ctx is cancelled or the connection fails
+func (r *PostgresUserEventRepository) Listen(ctx context.Context, handle func(*models.UserEvent)) error {
+	return listen(ctx, r.db, userEventChannel, func(payload string) {
+		event := &models.UserEvent{}
+		if err := json.Unmarshal([]byte(payload), event); err != nil {
+			log.Printf("Ignoring invalid user event notification: %v", err)
+			return
+		}
+		handle(event)
+	})
+}
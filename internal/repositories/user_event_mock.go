@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockUserEventRepository is a mock implementation for testing
+type MockUserEventRepository struct {
+	NotifyFunc func(ctx context.Context, event *models.UserEvent) error
+	ListenFunc func(ctx context.Context, handle func(*models.UserEvent)) error
+}
+
+func (m *MockUserEventRepository) Notify(ctx context.Context, event *models.UserEvent) error {
+	if m.NotifyFunc != nil {
+		return m.NotifyFunc(ctx, event)
+	}
+	return nil
+}
+
+func (m *MockUserEventRepository) Listen(ctx context.Context, handle func(*models.UserEvent)) error {
+	if m.ListenFunc != nil {
+		return m.ListenFunc(ctx, handle)
+	}
+	<-ctx.Done()
+	return ctx.Err()
+}
@@ -0,0 +1,61 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockUserRepository is a mock implementation for testing
+type MockUserRepository struct {
+	ListFunc        func(ctx context.Context, filter models.UserFilter) ([]*models.User, error)
+	FindByIDFunc    func(ctx context.Context, id string) (*models.User, error)
+	FindByEmailFunc func(ctx context.Context, email string) (*models.User, error)
+	SetRoleFunc     func(ctx context.Context, id string, role string) error
+	SuspendFunc     func(ctx context.Context, id string, until time.Time) error
+	UnsuspendFunc   func(ctx context.Context, id string) error
+}
+
+func (m *MockUserRepository) List(ctx context.Context, filter models.UserFilter) ([]*models.User, error) {
+	if m.ListFunc != nil {
+		return m.ListFunc(ctx, filter)
+	}
+	return []*models.User{}, nil
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	if m.FindByEmailFunc != nil {
+		return m.FindByEmailFunc(ctx, email)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockUserRepository) SetRole(ctx context.Context, id string, role string) error {
+	if m.SetRoleFunc != nil {
+		return m.SetRoleFunc(ctx, id, role)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Suspend(ctx context.Context, id string, until time.Time) error {
+	if m.SuspendFunc != nil {
+		return m.SuspendFunc(ctx, id, until)
+	}
+	return nil
+}
+
+func (m *MockUserRepository) Unsuspend(ctx context.Context, id string) error {
+	if m.UnsuspendFunc != nil {
+		return m.UnsuspendFunc(ctx, id)
+	}
+	return nil
+}
@@ -0,0 +1,246 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// WebhookRepository defines the interface for webhook endpoints and their deliveries
+type WebhookRepository interface {
+	CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	ListEndpoints(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error)
+	FindEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error)
+	DeleteEndpoint(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*models.WebhookDelivery, error)
+	DispatchEvents(ctx context.Context, limit int, maxAttempts int) (int64, error)
+	FindMessage(ctx context.Context, deliveryID string) (*models.WebhookMessage, error)
+	CompleteDelivery(ctx context.Context, id string, responseStatus int) error
+	FailDelivery(ctx context.Context, id string, responseStatus *int, reason string, retryAt *time.Time) error
+	PruneEvents(ctx context.Context, before time.Time) (int64, error)
+}
+
+// suppressWebhooks keeps the webhook triggers from recording events for the rest of the
+// transaction; bulk writers that rewrite history run it first
+const suppressWebhooks = `SELECT set_config('fitapi.suppress_webhooks', 'on', true)`
+
+// PostgresWebhookRepository is the PostgreSQL implementation of WebhookRepository
+type PostgresWebhookRepository struct {
+	db DB
+}
+
+// NewPostgresWebhookRepository creates a new PostgreSQL webhook repository
+func NewPostgresWebhookRepository(db DB) WebhookRepository {
+	return &PostgresWebhookRepository{db: db}
+}
+
+// CreateEndpoint registers a webhook endpoint
+func (r *PostgresWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	query := `
+		INSERT INTO webhook_endpoints (user_id, url, secret, events, description)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`
+
+	return r.db.QueryRow(
+		ctx,
+		query,
+		endpoint.UserID,
+		endpoint.URL,
+		endpoint.Secret,
+		endpoint.Events,
+		endpoint.Description,
+	).Scan(&endpoint.ID, &endpoint.CreatedAt, &endpoint.UpdatedAt)
+}
+
+// ListEndpoints retrieves a user's endpoints, without their secrets
+func (r *PostgresWebhookRepository) ListEndpoints(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error) {
+	query := `
+		SELECT id, user_id, url, events, description, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE user_id = $1
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	endpoints := []*models.WebhookEndpoint{}
+	for rows.Next() {
+		e := &models.WebhookEndpoint{}
+		if err := rows.Scan(&e.ID, &e.UserID, &e.URL, &e.Events, &e.Description, &e.CreatedAt, &e.UpdatedAt); err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, e)
+	}
+
+	return endpoints, rows.Err()
+}
+
+// FindEndpoint retrieves an endpoint, without its secret
+func (r *PostgresWebhookRepository) FindEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	query := `
+		SELECT id, user_id, url, events, description, created_at, updated_at
+		FROM webhook_endpoints
+		WHERE id = $1
+	`
+
+	e := &models.WebhookEndpoint{}
+	err := r.db.QueryRow(ctx, query, id).Scan(&e.ID, &e.UserID, &e.URL, &e.Events, &e.Description, &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// DeleteEndpoint removes an endpoint along with its deliveries
+func (r *PostgresWebhookRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	tag, err := r.db.Exec(ctx, `DELETE FROM webhook_endpoints WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return pgx.ErrNoRows
+	}
+	return nil
+}
+
+// ListDeliveries retrieves an endpoint's most recent deliveries, newest first
+func (r *PostgresWebhookRepository) ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*models.WebhookDelivery, error) {
+	query := `
+		SELECT d.id, d.endpoint_id, d.event_id, ev.event, d.status, d.attempts,
+		       CASE WHEN d.status = 'pending' THEN d.run_at END,
+		       d.response_status, d.last_error, d.delivered_at, d.created_at
+		FROM webhook_deliveries d
+		JOIN webhook_events ev ON ev.id = d.event_id
+		WHERE d.endpoint_id = $1
+		ORDER BY d.created_at DESC
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(ctx, query, endpointID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*models.WebhookDelivery{}
+	for rows.Next() {
+		d := &models.WebhookDelivery{}
+		err := rows.Scan(&d.ID, &d.EndpointID, &d.EventID, &d.Event, &d.Status, &d.Attempts,
+			&d.NextAttemptAt, &d.ResponseStatus, &d.LastError, &d.DeliveredAt, &d.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, d)
+	}
+
+	return deliveries, rows.Err()
+}
+
+// DispatchEvents creates a delivery per subscribed endpoint for up to limit recorded events,
+// oldest first, each with a delivery job tried up to maxAttempts times, and returns how
+// many deliveries were created
+func (r *PostgresWebhookRepository) DispatchEvents(ctx context.Context, limit int, maxAttempts int) (int64, error) {
+	query := `
+		WITH events AS (
+			UPDATE webhook_events
+			SET dispatched_at = NOW()
+			WHERE id IN (
+				SELECT id FROM webhook_events
+				WHERE dispatched_at IS NULL
+				ORDER BY created_at ASC
+				LIMIT $1
+				FOR UPDATE SKIP LOCKED
+			)
+			RETURNING id, user_id, event
+		), deliveries AS (
+			INSERT INTO webhook_deliveries (endpoint_id, event_id)
+			SELECT e.id, ev.id
+			FROM events ev
+			JOIN webhook_endpoints e ON e.user_id = ev.user_id AND ev.event = ANY(e.events)
+			ON CONFLICT (endpoint_id, event_id) DO NOTHING
+			RETURNING id, endpoint_id
+		)
+		INSERT INTO jobs (user_id, kind, payload, max_attempts)
+		SELECT e.user_id, 'webhook_delivery', jsonb_build_object('delivery_id', d.id), $2
+		FROM deliveries d
+		JOIN webhook_endpoints e ON e.id = d.endpoint_id
+	`
+
+	tag, err := r.db.Exec(ctx, query, limit, maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
+
+// FindMessage retrieves a delivery with what is needed to send it; failed deliveries are
+// included so that a requeued job sends them again
+// Returns pgx.ErrNoRows if the delivery is gone or was delivered.
+func (r *PostgresWebhookRepository) FindMessage(ctx context.Context, deliveryID string) (*models.WebhookMessage, error) {
+	query := `
+		SELECT d.id, e.url, e.secret, ev.id, ev.event, ev.data, ev.created_at
+		FROM webhook_deliveries d
+		JOIN webhook_endpoints e ON e.id = d.endpoint_id
+		JOIN webhook_events ev ON ev.id = d.event_id
+		WHERE d.id = $1 AND d.status <> 'delivered'
+	`
+
+	m := &models.WebhookMessage{}
+	err := r.db.QueryRow(ctx, query, deliveryID).Scan(&m.DeliveryID, &m.URL, &m.Secret, &m.EventID, &m.Event, &m.Data, &m.EventCreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// CompleteDelivery marks a delivery delivered
+func (r *PostgresWebhookRepository) CompleteDelivery(ctx context.Context, id string, responseStatus int) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'delivered', attempts = attempts + 1, response_status = $2, last_error = NULL, delivered_at = NOW()
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, responseStatus)
+	return err
+}
+
+// FailDelivery records a failed attempt and schedules a retry at retryAt, or gives up on the
+// delivery when retryAt is nil
+func (r *PostgresWebhookRepository) FailDelivery(ctx context.Context, id string, responseStatus *int, reason string, retryAt *time.Time) error {
+	query := `
+		UPDATE webhook_deliveries
+		SET status = CASE WHEN $4::timestamptz IS NULL THEN 'failed' ELSE 'pending' END,
+		    run_at = COALESCE($4, run_at),
+		    attempts = attempts + 1,
+		    response_status = $2,
+		    last_error = $3
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, id, responseStatus, reason, retryAt)
+	return err
+}
+
+// PruneEvents deletes dispatched events recorded before the cutoff whose deliveries are all
+// finished, along with those deliveries
+func (r *PostgresWebhookRepository) PruneEvents(ctx context.Context, before time.Time) (int64, error) {
+	query := `
+		DELETE FROM webhook_events ev
+		WHERE ev.created_at < $1 AND ev.dispatched_at IS NOT NULL
+		  AND NOT EXISTS (SELECT 1 FROM webhook_deliveries d WHERE d.event_id = ev.id AND d.status = 'pending')
+	`
+
+	tag, err := r.db.Exec(ctx, query, before)
+	if err != nil {
+		return 0, err
+	}
+	return tag.RowsAffected(), nil
+}
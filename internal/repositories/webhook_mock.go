@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockWebhookRepository is a mock implementation for testing
+type MockWebhookRepository struct {
+	CreateEndpointFunc   func(ctx context.Context, endpoint *models.WebhookEndpoint) error
+	ListEndpointsFunc    func(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error)
+	FindEndpointFunc     func(ctx context.Context, id string) (*models.WebhookEndpoint, error)
+	DeleteEndpointFunc   func(ctx context.Context, id string) error
+	ListDeliveriesFunc   func(ctx context.Context, endpointID string, limit int) ([]*models.WebhookDelivery, error)
+	DispatchEventsFunc   func(ctx context.Context, limit int, maxAttempts int) (int64, error)
+	FindMessageFunc      func(ctx context.Context, deliveryID string) (*models.WebhookMessage, error)
+	CompleteDeliveryFunc func(ctx context.Context, id string, responseStatus int) error
+	FailDeliveryFunc     func(ctx context.Context, id string, responseStatus *int, reason string, retryAt *time.Time) error
+	PruneEventsFunc      func(ctx context.Context, before time.Time) (int64, error)
+}
+
+func (m *MockWebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	if m.CreateEndpointFunc != nil {
+		return m.CreateEndpointFunc(ctx, endpoint)
+	}
+	endpoint.ID = "mock-webhook-endpoint-id"
+	return nil
+}
+
+func (m *MockWebhookRepository) ListEndpoints(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error) {
+	if m.ListEndpointsFunc != nil {
+		return m.ListEndpointsFunc(ctx, userID)
+	}
+	return []*models.WebhookEndpoint{}, nil
+}
+
+func (m *MockWebhookRepository) FindEndpoint(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+	if m.FindEndpointFunc != nil {
+		return m.FindEndpointFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockWebhookRepository) DeleteEndpoint(ctx context.Context, id string) error {
+	if m.DeleteEndpointFunc != nil {
+		return m.DeleteEndpointFunc(ctx, id)
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) ListDeliveries(ctx context.Context, endpointID string, limit int) ([]*models.WebhookDelivery, error) {
+	if m.ListDeliveriesFunc != nil {
+		return m.ListDeliveriesFunc(ctx, endpointID, limit)
+	}
+	return []*models.WebhookDelivery{}, nil
+}
+
+func (m *MockWebhookRepository) DispatchEvents(ctx context.Context, limit int, maxAttempts int) (int64, error) {
+	if m.DispatchEventsFunc != nil {
+		return m.DispatchEventsFunc(ctx, limit, maxAttempts)
+	}
+	return 0, nil
+}
+
+func (m *MockWebhookRepository) FindMessage(ctx context.Context, deliveryID string) (*models.WebhookMessage, error) {
+	if m.FindMessageFunc != nil {
+		return m.FindMessageFunc(ctx, deliveryID)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockWebhookRepository) CompleteDelivery(ctx context.Context, id string, responseStatus int) error {
+	if m.CompleteDeliveryFunc != nil {
+		return m.CompleteDeliveryFunc(ctx, id, responseStatus)
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) FailDelivery(ctx context.Context, id string, responseStatus *int, reason string, retryAt *time.Time) error {
+	if m.FailDeliveryFunc != nil {
+		return m.FailDeliveryFunc(ctx, id, responseStatus, reason, retryAt)
+	}
+	return nil
+}
+
+func (m *MockWebhookRepository) PruneEvents(ctx context.Context, before time.Time) (int64, error) {
+	if m.PruneEventsFunc != nil {
+		return m.PruneEventsFunc(ctx, before)
+	}
+	return 0, nil
+}
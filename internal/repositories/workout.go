@@ -0,0 +1,211 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// WorkoutRepository defines the interface for workout template data access
+type WorkoutRepository interface {
+	FindByID(ctx context.Context, id string) (*models.Workout, error)
+	FindByOrganization(ctx context.Context, orgID string) ([]*models.WorkoutSummary, error)
+	Share(ctx context.Context, workout *models.Workout, orgID string) error
+}
+
+// PostgresWorkoutRepository is the PostgreSQL implementation of WorkoutRepository
+type PostgresWorkoutRepository struct {
+	db DB
+}
+
+// NewPostgresWorkoutRepository creates a new PostgreSQL workout repository
+func NewPostgresWorkoutRepository(db DB) WorkoutRepository {
+	return &PostgresWorkoutRepository{db: db}
+}
+
+// FindByID retrieves a workout with its exercises in order, each with its library
+// exercise and equipment. Both queries go out in one batch, so the whole workout
+// costs a single round trip however many exercises it has.
+// Returns pgx.ErrNoRows if the workout does not exist.
+func (r *PostgresWorkoutRepository) FindByID(ctx context.Context, id string) (*models.Workout, error) {
+	workoutQuery := `
+		SELECT id, user_id, organization_id, name, COALESCE(description, ''), image_url, created_at, updated_at, version
+		FROM workouts
+		WHERE id = $1
+	`
+	// One row per exercise and piece of equipment; exercises without equipment get one
+	// row with NULL equipment
+	exercisesQuery := `
+		SELECT we.id, we.order_index, we.sets, we.reps, we.weight_kg, we.duration_seconds,
+		       we.distance_meters, we.rest_time_seconds, we.intensity_percentage, we.training_max_name,
+		       we.progression_scheme, we.min_reps,
+		       we.tempo, we.target_rpe, we.notes, we.superset_group_id,
+		       COALESCE(we.is_dropset, FALSE), COALESCE(we.is_warmup, FALSE), COALESCE(we.is_cooldown, FALSE),
+		       e.id, e.name, COALESCE(e.description, ''), e.is_public, e.user_id, e.image_url, e.muscle_group, e.created_at, e.updated_at, e.version,
+		       eq.id, eq.name, COALESCE(eq.description, ''), eq.user_id, eq.organization_id, eq.created_at, eq.updated_at, eq.version
+		FROM workout_exercises we
+		JOIN exercises e ON e.id = we.exercise_id
+		LEFT JOIN exercise_equipment ee ON ee.exercise_id = e.id
+		LEFT JOIN equipment eq ON eq.id = ee.equipment_id
+		WHERE we.workout_id = $1
+		ORDER BY we.order_index ASC, we.id ASC, eq.name ASC
+	`
+
+	batch := &pgx.Batch{}
+	batch.Queue(workoutQuery, id)
+	batch.Queue(exercisesQuery, id)
+	results := r.db.SendBatch(ctx, batch)
+	defer results.Close()
+
+	workout := &models.Workout{Exercises: []*models.WorkoutExercise{}}
+	err := results.QueryRow().Scan(
+		&workout.ID,
+		&workout.UserID,
+		&workout.OrganizationID,
+		&workout.Name,
+		&workout.Description,
+		&workout.ImageURL,
+		&workout.CreatedAt,
+		&workout.UpdatedAt,
+		&workout.Version,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := results.Query()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var current *models.WorkoutExercise
+	for rows.Next() {
+		we := &models.WorkoutExercise{Exercise: &models.Exercise{}, Equipment: []*models.Equipment{}}
+		// Equipment columns are NULL for exercises without equipment
+		var equipmentID, equipmentName, equipmentUserID, equipmentOrgID *string
+		var equipmentDescription string
+		var equipmentCreatedAt, equipmentUpdatedAt *time.Time
+		var equipmentVersion *int64
+		err := rows.Scan(
+			&we.ID,
+			&we.OrderIndex,
+			&we.Sets,
+			&we.Reps,
+			&we.WeightKg,
+			&we.DurationSeconds,
+			&we.DistanceMeters,
+			&we.RestTimeSeconds,
+			&we.IntensityPercentage,
+			&we.TrainingMaxName,
+			&we.ProgressionScheme,
+			&we.MinReps,
+			&we.Tempo,
+			&we.TargetRPE,
+			&we.Notes,
+			&we.SupersetGroupID,
+			&we.IsDropset,
+			&we.IsWarmup,
+			&we.IsCooldown,
+			&we.Exercise.ID,
+			&we.Exercise.Name,
+			&we.Exercise.Description,
+			&we.Exercise.IsPublic,
+			&we.Exercise.UserID,
+			&we.Exercise.ImageURL,
+			&we.Exercise.MuscleGroup,
+			&we.Exercise.CreatedAt,
+			&we.Exercise.UpdatedAt,
+			&we.Exercise.Version,
+			&equipmentID,
+			&equipmentName,
+			&equipmentDescription,
+			&equipmentUserID,
+			&equipmentOrgID,
+			&equipmentCreatedAt,
+			&equipmentUpdatedAt,
+			&equipmentVersion,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		if current == nil || current.ID != we.ID {
+			current = we
+			workout.Exercises = append(workout.Exercises, current)
+		}
+		if equipmentID != nil {
+			current.Equipment = append(current.Equipment, &models.Equipment{
+				ID:             *equipmentID,
+				Name:           *equipmentName,
+				Description:    equipmentDescription,
+				UserID:         *equipmentUserID,
+				OrganizationID: equipmentOrgID,
+				CreatedAt:      *equipmentCreatedAt,
+				UpdatedAt:      *equipmentUpdatedAt,
+				Version:        *equipmentVersion,
+			})
+		}
+	}
+
+	return workout, rows.Err()
+}
+
+// FindByOrganization retrieves the workouts shared in an organization by name
+func (r *PostgresWorkoutRepository) FindByOrganization(ctx context.Context, orgID string) ([]*models.WorkoutSummary, error) {
+	query := `
+		SELECT w.id, w.user_id, w.organization_id, w.name, COALESCE(w.description, ''), w.image_url,
+		       (SELECT COUNT(*) FROM workout_exercises we WHERE we.workout_id = w.id),
+		       w.created_at, w.updated_at, w.version
+		FROM workouts w
+		WHERE w.organization_id = $1
+		ORDER BY w.name ASC, w.id ASC
+	`
+
+	rows, err := r.db.Query(ctx, query, orgID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	workouts := []*models.WorkoutSummary{}
+	for rows.Next() {
+		w := &models.WorkoutSummary{}
+		err := rows.Scan(
+			&w.ID,
+			&w.UserID,
+			&w.OrganizationID,
+			&w.Name,
+			&w.Description,
+			&w.ImageURL,
+			&w.ExerciseCount,
+			&w.CreatedAt,
+			&w.UpdatedAt,
+			&w.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		workouts = append(workouts, w)
+	}
+
+	return workouts, rows.Err()
+}
+
+// Share moves a personal workout into an organization, updating its organization, version
+// and timestamp
+// Returns pgx.ErrNoRows if the workout was updated since the version it carries, or is
+// already shared.
+func (r *PostgresWorkoutRepository) Share(ctx context.Context, workout *models.Workout, orgID string) error {
+	query := `
+		UPDATE workouts
+		SET organization_id = $1, updated_at = NOW()
+		WHERE id = $2 AND version = $3 AND organization_id IS NULL
+		RETURNING organization_id, updated_at, version
+	`
+
+	return r.db.QueryRow(ctx, query, orgID, workout.ID, workout.Version).
+		Scan(&workout.OrganizationID, &workout.UpdatedAt, &workout.Version)
+}
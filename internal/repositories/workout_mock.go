@@ -0,0 +1,38 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// MockWorkoutRepository is a mock implementation for testing
+type MockWorkoutRepository struct {
+	FindByIDFunc           func(ctx context.Context, id string) (*models.Workout, error)
+	FindByOrganizationFunc func(ctx context.Context, orgID string) ([]*models.WorkoutSummary, error)
+	ShareFunc              func(ctx context.Context, workout *models.Workout, orgID string) error
+}
+
+func (m *MockWorkoutRepository) FindByID(ctx context.Context, id string) (*models.Workout, error) {
+	if m.FindByIDFunc != nil {
+		return m.FindByIDFunc(ctx, id)
+	}
+	return nil, pgx.ErrNoRows
+}
+
+func (m *MockWorkoutRepository) FindByOrganization(ctx context.Context, orgID string) ([]*models.WorkoutSummary, error) {
+	if m.FindByOrganizationFunc != nil {
+		return m.FindByOrganizationFunc(ctx, orgID)
+	}
+	return []*models.WorkoutSummary{}, nil
+}
+
+func (m *MockWorkoutRepository) Share(ctx context.Context, workout *models.Workout, orgID string) error {
+	if m.ShareFunc != nil {
+		return m.ShareFunc(ctx, workout, orgID)
+	}
+	workout.OrganizationID = &orgID
+	workout.Version++
+	return nil
+}
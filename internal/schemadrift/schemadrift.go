@@ -0,0 +1,262 @@
+// Package schemadrift compares a live database's schema with the schema the migrations
+// produce, so changes made by hand are caught before they break a deploy or a migration.
+//
+// The expected schema comes from a database freshly migrated to the latest version: either
+// directly, or through a snapshot of one saved as JSON. Only the public schema is compared;
+// auth and storage belong to Supabase.
+package schemadrift
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// Schema is the public schema of a database
+type Schema struct {
+	Version int64             `json:"version"` // Latest applied migration, 0 for none
+	Tables  map[string]*Table `json:"tables"`  // Tables and materialized views by name
+}
+
+// Table is a table's definition
+type Table struct {
+	Columns     map[string]Column `json:"columns"`
+	Indexes     map[string]string `json:"indexes"`     // Definitions by name
+	Constraints map[string]string `json:"constraints"` // Definitions by name
+}
+
+// Column is a column's definition
+type Column struct {
+	Type      string `json:"type"`
+	Nullable  bool   `json:"nullable"`
+	Default   string `json:"default,omitempty"` // Expression, or the generation expression
+	Generated bool   `json:"generated,omitempty"`
+}
+
+func (c Column) String() string {
+	s := c.Type
+	if !c.Nullable {
+		s += " NOT NULL"
+	}
+	if c.Generated {
+		s += " GENERATED ALWAYS AS (" + c.Default + ")"
+	} else if c.Default != "" {
+		s += " DEFAULT " + c.Default
+	}
+	return s
+}
+
+// Drift kinds
+const (
+	Missing    = "missing"    // Expected but not in the database
+	Unexpected = "unexpected" // In the database but not expected
+	Changed    = "changed"    // Defined differently
+)
+
+// Drift is one difference between the expected and the actual schema
+type Drift struct {
+	Kind     string // Missing, Unexpected or Changed
+	Object   string // table, column, index or constraint
+	Name     string // e.g. workouts.name
+	Expected string // Definition, for Missing and Changed
+	Actual   string // Definition, for Unexpected and Changed
+}
+
+func (d Drift) String() string {
+	switch d.Kind {
+	case Missing:
+		return fmt.Sprintf("missing %s %s: %s", d.Object, d.Name, d.Expected)
+	case Unexpected:
+		return fmt.Sprintf("unexpected %s %s: %s", d.Object, d.Name, d.Actual)
+	}
+	return fmt.Sprintf("changed %s %s: expected %s, found %s", d.Object, d.Name, d.Expected, d.Actual)
+}
+
+// Querier runs queries: a connection, pool or transaction
+type Querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// Inspect reads the database's public schema
+func Inspect(ctx context.Context, db Querier) (*Schema, error) {
+	schema := &Schema{Tables: make(map[string]*Table)}
+
+	err := db.QueryRow(ctx, `SELECT version FROM schema_migrations`).Scan(&schema.Version)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to read the migration version: %w", err)
+	}
+
+	columnsQuery := `
+		SELECT c.relname, a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+		       COALESCE(pg_get_expr(d.adbin, d.adrelid), ''), a.attgenerated <> ''
+		FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		JOIN pg_attribute a ON a.attrelid = c.oid AND a.attnum > 0 AND NOT a.attisdropped
+		LEFT JOIN pg_attrdef d ON d.adrelid = c.oid AND d.adnum = a.attnum
+		WHERE n.nspname = 'public' AND c.relkind IN ('r', 'p', 'm')
+	`
+	rows, err := db.Query(ctx, columnsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+	var table, name string
+	var column Column
+	_, err = pgx.ForEachRow(rows, []any{&table, &name, &column.Type, &column.Nullable, &column.Default, &column.Generated}, func() error {
+		schema.table(table).Columns[name] = column
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read columns: %w", err)
+	}
+
+	var definition string
+	rows, err = db.Query(ctx, `SELECT tablename, indexname, indexdef FROM pg_indexes WHERE schemaname = 'public'`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+	_, err = pgx.ForEachRow(rows, []any{&table, &name, &definition}, func() error {
+		schema.table(table).Indexes[name] = definition
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read indexes: %w", err)
+	}
+
+	// NOT NULL constraints are compared as part of their columns
+	constraintsQuery := `
+		SELECT c.relname, con.conname, pg_get_constraintdef(con.oid)
+		FROM pg_constraint con
+		JOIN pg_class c ON c.oid = con.conrelid
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = 'public' AND con.contype <> 'n'
+	`
+	rows, err = db.Query(ctx, constraintsQuery)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+	_, err = pgx.ForEachRow(rows, []any{&table, &name, &definition}, func() error {
+		schema.table(table).Constraints[name] = definition
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read constraints: %w", err)
+	}
+
+	return schema, nil
+}
+
+func (s *Schema) table(name string) *Table {
+	t, ok := s.Tables[name]
+	if !ok {
+		t = &Table{Columns: make(map[string]Column), Indexes: make(map[string]string), Constraints: make(map[string]string)}
+		s.Tables[name] = t
+	}
+	return t
+}
+
+// Write saves the schema as a snapshot
+func (s *Schema) Write(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(s)
+}
+
+// Read loads a snapshot saved with Write
+func Read(r io.Reader) (*Schema, error) {
+	schema := &Schema{}
+	if err := json.NewDecoder(r).Decode(schema); err != nil {
+		return nil, fmt.Errorf("invalid schema snapshot: %w", err)
+	}
+	for name := range schema.Tables {
+		// Fill in what an empty snapshot section leaves out
+		t := schema.Tables[name]
+		if t == nil {
+			t = &Table{}
+			schema.Tables[name] = t
+		}
+		if t.Columns == nil {
+			t.Columns = make(map[string]Column)
+		}
+		if t.Indexes == nil {
+			t.Indexes = make(map[string]string)
+		}
+		if t.Constraints == nil {
+			t.Constraints = make(map[string]string)
+		}
+	}
+	return schema, nil
+}
+
+// Diff lists how actual differs from expected, sorted by name. The tables missing or
+// unexpected are reported once, without their columns, indexes and constraints.
+func Diff(expected, actual *Schema) []Drift {
+	var drifts []Drift
+	for _, name := range unionKeys(expected.Tables, actual.Tables) {
+		e, inExpected := expected.Tables[name]
+		a, inActual := actual.Tables[name]
+		switch {
+		case !inActual:
+			drifts = append(drifts, Drift{Kind: Missing, Object: "table", Name: name, Expected: fmt.Sprintf("%d columns", len(e.Columns))})
+			continue
+		case !inExpected:
+			drifts = append(drifts, Drift{Kind: Unexpected, Object: "table", Name: name, Actual: fmt.Sprintf("%d columns", len(a.Columns))})
+			continue
+		}
+
+		drifts = append(drifts, diffDefinitions("column", name, columnStrings(e.Columns), columnStrings(a.Columns))...)
+		drifts = append(drifts, diffDefinitions("index", name, e.Indexes, a.Indexes)...)
+		drifts = append(drifts, diffDefinitions("constraint", name, e.Constraints, a.Constraints)...)
+	}
+	return drifts
+}
+
+// diffDefinitions compares a table's named definitions of one kind
+func diffDefinitions(object, table string, expected, actual map[string]string) []Drift {
+	var drifts []Drift
+	for _, name := range unionKeys(expected, actual) {
+		e, inExpected := expected[name]
+		a, inActual := actual[name]
+		drift := Drift{Object: object, Name: table + "." + name, Expected: e, Actual: a}
+		switch {
+		case !inActual:
+			drift.Kind = Missing
+		case !inExpected:
+			drift.Kind = Unexpected
+		case e != a:
+			drift.Kind = Changed
+		default:
+			continue
+		}
+		drifts = append(drifts, drift)
+	}
+	return drifts
+}
+
+func columnStrings(columns map[string]Column) map[string]string {
+	strings := make(map[string]string, len(columns))
+	for name, c := range columns {
+		strings[name] = c.String()
+	}
+	return strings
+}
+
+// unionKeys returns the keys of both maps, sorted
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := make([]string, 0, len(a))
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,88 @@
+package schemadrift
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func testSchema() *Schema {
+	s := &Schema{Version: 37, Tables: make(map[string]*Table)}
+	workouts := s.table("workouts")
+	workouts.Columns["id"] = Column{Type: "uuid", Default: "gen_random_uuid()"}
+	workouts.Columns["name"] = Column{Type: "text"}
+	workouts.Indexes["idx_workouts_user_id"] = "CREATE INDEX idx_workouts_user_id ON public.workouts USING btree (user_id)"
+	workouts.Constraints["workouts_pkey"] = "PRIMARY KEY (id)"
+	s.table("jobs").Columns["id"] = Column{Type: "uuid"}
+	return s
+}
+
+func TestDiff_NoDrift(t *testing.T) {
+	if drifts := Diff(testSchema(), testSchema()); len(drifts) != 0 {
+		t.Errorf("Expected no drift, got %v", drifts)
+	}
+}
+
+func TestDiff_ReportsHandEdits(t *testing.T) {
+	actual := testSchema()
+	workouts := actual.Tables["workouts"]
+	workouts.Columns["name"] = Column{Type: "character varying(100)", Nullable: true}
+	workouts.Columns["legacy_flag"] = Column{Type: "boolean", Nullable: true}
+	delete(workouts.Indexes, "idx_workouts_user_id")
+	workouts.Constraints["workouts_name_check"] = "CHECK ((length(name) > 0))"
+	delete(actual.Tables, "jobs")
+	actual.table("workouts_backup_2024").Columns["id"] = Column{Type: "uuid"}
+
+	var got []string
+	for _, d := range Diff(testSchema(), actual) {
+		got = append(got, d.String())
+	}
+	want := []string{
+		"missing table jobs: 1 columns",
+		"unexpected column workouts.legacy_flag: boolean",
+		"changed column workouts.name: expected text NOT NULL, found character varying(100)",
+		"missing index workouts.idx_workouts_user_id: CREATE INDEX idx_workouts_user_id ON public.workouts USING btree (user_id)",
+		"unexpected constraint workouts.workouts_name_check: CHECK ((length(name) > 0))",
+		"unexpected table workouts_backup_2024: 1 columns",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("Expected\n%s\ngot\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	if err := testSchema().Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Version != 37 {
+		t.Errorf("Expected version 37, got %d", read.Version)
+	}
+	if drifts := Diff(testSchema(), read); len(drifts) != 0 {
+		t.Errorf("Expected the snapshot to match, got %v", drifts)
+	}
+
+	// A table with nothing but columns still reads with empty sections
+	read, err = Read(strings.NewReader(`{"version": 1, "tables": {"t": {"columns": {"id": {"type": "uuid"}}}}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if read.Tables["t"].Indexes == nil || read.Tables["t"].Constraints == nil {
+		t.Error("Expected empty indexes and constraints")
+	}
+}
+
+func TestColumnString(t *testing.T) {
+	generated := Column{Type: "integer", Default: "(reps * sets)", Generated: true, Nullable: true}
+	if got := generated.String(); got != "integer GENERATED ALWAYS AS ((reps * sets))" {
+		t.Errorf("Unexpected definition %q", got)
+	}
+	defaulted := Column{Type: "timestamp with time zone", Default: "now()"}
+	if got := defaulted.String(); got != "timestamp with time zone NOT NULL DEFAULT now()" {
+		t.Errorf("Unexpected definition %q", got)
+	}
+}
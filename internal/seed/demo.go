@@ -0,0 +1,207 @@
+package seed
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// demoFixture is a user following a weekly program from start for a number of weeks
+type demoFixture struct {
+	User       fixtureUser     `json:"user"`
+	Start      string          `json:"start"` // Monday of the first week, YYYY-MM-DD (UTC)
+	Weeks      int             `json:"weeks"`
+	Workouts   []*demoWorkout  `json:"workouts"`
+	BodyWeight *demoBodyWeight `json:"body_weight"`
+}
+
+// demoWorkout is a workout template performed once a week
+type demoWorkout struct {
+	Name            string          `json:"name"`
+	Day             int             `json:"day"`  // Days after Monday
+	Time            string          `json:"time"` // HH:MM (UTC)
+	DurationMinutes int             `json:"duration_minutes"`
+	Exercises       []*demoExercise `json:"exercises"`
+}
+
+// demoExercise is a planned exercise; its weight goes up every week
+type demoExercise struct {
+	Exercise         string   `json:"exercise"` // Library exercise name
+	Sets             int      `json:"sets"`
+	Reps             *int     `json:"reps"`
+	WeightKg         *float64 `json:"weight_kg"`
+	WeeklyIncreaseKg float64  `json:"weekly_increase_kg"`
+	DurationSeconds  *int     `json:"duration_seconds"`
+	DistanceMeters   *float64 `json:"distance_meters"`
+}
+
+// demoBodyWeight is a weigh-in every Monday morning
+type demoBodyWeight struct {
+	StartKg        float64 `json:"start_kg"`
+	WeeklyChangeKg float64 `json:"weekly_change_kg"`
+}
+
+// demoSession is a performed workout: week is how many weeks after the start
+type demoSession struct {
+	Workout   *demoWorkout
+	Week      int
+	StartedAt time.Time
+}
+
+// weightKg is the exercise's weight in the given week, nil for unweighted exercises
+func (e *demoExercise) weightKg(week int) *float64 {
+	if e.WeightKg == nil {
+		return nil
+	}
+	weight := *e.WeightKg + float64(week)*e.WeeklyIncreaseKg
+	return &weight
+}
+
+// sessions lays out every workout of every week, oldest first
+func (f *demoFixture) sessions() ([]*demoSession, error) {
+	start, err := time.Parse("2006-01-02", f.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start %q", f.Start)
+	}
+	if start.Weekday() != time.Monday {
+		return nil, fmt.Errorf("start %s is not a Monday", f.Start)
+	}
+
+	var sessions []*demoSession
+	for week := range f.Weeks {
+		for _, w := range f.Workouts {
+			at, err := time.Parse("15:04", w.Time)
+			if err != nil {
+				return nil, fmt.Errorf("invalid time %q of %s", w.Time, w.Name)
+			}
+			day := start.AddDate(0, 0, week*7+w.Day)
+			startedAt := day.Add(time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute)
+			sessions = append(sessions, &demoSession{Workout: w, Week: week, StartedAt: startedAt})
+		}
+	}
+	return sessions, nil
+}
+
+func loadDemoUser(ctx context.Context, tx pgx.Tx, report *Report) error {
+	var demo demoFixture
+	if err := readFixture("demo-user", &demo); err != nil {
+		return err
+	}
+	sessions, err := demo.sessions()
+	if err != nil {
+		return err
+	}
+	exerciseIDs, err := libraryExercises(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err := ensureUser(ctx, tx, demo.User, report); err != nil {
+		return err
+	}
+
+	workoutIDs := make(map[*demoWorkout]string)
+	for _, w := range demo.Workouts {
+		id, err := ensureDemoWorkout(ctx, tx, demo.User.ID, w, exerciseIDs, report)
+		if err != nil {
+			return err
+		}
+		workoutIDs[w] = id
+	}
+
+	sessionQuery := `
+		INSERT INTO workout_sessions (user_id, workout_id, name, started_at, completed_at, duration_minutes, status)
+		SELECT $1, $2, $3, $4, $5, $6, 'completed'
+		WHERE NOT EXISTS (SELECT 1 FROM workout_sessions WHERE user_id = $1 AND started_at = $4)
+		RETURNING id
+	`
+	logQuery := `
+		INSERT INTO exercise_logs (
+			workout_session_id, exercise_id, order_index, sets_completed, sets_planned,
+			reps_completed, reps_planned, weight_kg, duration_seconds, distance_meters
+		)
+		VALUES ($1, $2, $3, $4, $4, $5, $5, $6, $7, $8)
+	`
+	for _, s := range sessions {
+		w := s.Workout
+		duration := time.Duration(w.DurationMinutes) * time.Minute
+		var sessionID string
+		err := tx.QueryRow(ctx, sessionQuery, demo.User.ID, workoutIDs[w], w.Name, s.StartedAt, s.StartedAt.Add(duration), w.DurationMinutes).Scan(&sessionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue // Already seeded
+		}
+		if err != nil {
+			return fmt.Errorf("failed to insert session of %s: %w", s.StartedAt.Format(time.DateOnly), err)
+		}
+		report.add("workout_sessions", 1)
+
+		batch := &pgx.Batch{}
+		for i, e := range w.Exercises {
+			batch.Queue(logQuery, sessionID, exerciseIDs[e.Exercise], i+1, e.Sets, e.Reps, e.weightKg(s.Week), e.DurationSeconds, e.DistanceMeters)
+		}
+		if err := tx.SendBatch(ctx, batch).Close(); err != nil {
+			return fmt.Errorf("failed to insert sets: %w", err)
+		}
+		report.add("exercise_logs", int64(len(w.Exercises)))
+	}
+
+	if demo.BodyWeight != nil {
+		measurementQuery := `
+			INSERT INTO body_measurements (user_id, measured_at, weight_kg, source)
+			VALUES ($1, $2, $3, 'manual')
+			ON CONFLICT (user_id, measured_at) DO NOTHING
+		`
+		start, _ := time.Parse("2006-01-02", demo.Start)
+		for week := range demo.Weeks {
+			measuredAt := start.AddDate(0, 0, week*7).Add(7 * time.Hour)
+			weight := demo.BodyWeight.StartKg + float64(week)*demo.BodyWeight.WeeklyChangeKg
+			tag, err := tx.Exec(ctx, measurementQuery, demo.User.ID, measuredAt, weight)
+			if err != nil {
+				return fmt.Errorf("failed to insert weigh-in: %w", err)
+			}
+			report.add("body_measurements", tag.RowsAffected())
+		}
+	}
+
+	return nil
+}
+
+// ensureDemoWorkout returns the user's workout of the name, creating it with its
+// exercises if missing
+func ensureDemoWorkout(ctx context.Context, tx pgx.Tx, userID string, w *demoWorkout, exerciseIDs map[string]string, report *Report) (string, error) {
+	for _, e := range w.Exercises {
+		if _, ok := exerciseIDs[e.Exercise]; !ok {
+			return "", fmt.Errorf("workout %s uses %s, which is not in the exercise library", w.Name, e.Exercise)
+		}
+	}
+
+	var id string
+	err := tx.QueryRow(ctx, `SELECT id FROM workouts WHERE user_id = $1 AND name = $2`, userID, w.Name).Scan(&id)
+	if err == nil {
+		return id, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return "", err
+	}
+
+	if err := tx.QueryRow(ctx, `INSERT INTO workouts (user_id, name) VALUES ($1, $2) RETURNING id`, userID, w.Name).Scan(&id); err != nil {
+		return "", fmt.Errorf("failed to insert workout %s: %w", w.Name, err)
+	}
+	report.add("workouts", 1)
+
+	query := `
+		INSERT INTO workout_exercises (workout_id, exercise_id, order_index, sets, reps, weight_kg, duration_seconds, distance_meters)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`
+	for i, e := range w.Exercises {
+		if _, err := tx.Exec(ctx, query, id, exerciseIDs[e.Exercise], i+1, e.Sets, e.Reps, e.WeightKg, e.DurationSeconds, e.DistanceMeters); err != nil {
+			return "", fmt.Errorf("failed to insert workout exercise: %w", err)
+		}
+	}
+	report.add("workout_exercises", int64(len(w.Exercises)))
+
+	return id, nil
+}
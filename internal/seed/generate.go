@@ -0,0 +1,474 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// GenerateOptions size a generated data set
+type GenerateOptions struct {
+	Users   int       // Users generated: loadgen-000000@fitapi.dev and on
+	Years   int       // Length of each history
+	End     time.Time // Histories end before the Monday of this week; zero is the current week
+	Seed    uint64    // Varies the histories; users keep their IDs across seeds
+	Workers int       // Users written at once; zero is 4
+
+	// Progress, if set, is called after each user is written or skipped
+	Progress func(done, total int)
+}
+
+// generatedLift is how a library exercise is trained in generated histories
+type generatedLift struct {
+	ratio     float64 // An intermediate's weight for 5 reps, relative to body weight
+	minReps   int
+	maxReps   int
+	increment float64 // Weights are rounded to this
+}
+
+// generatedLifts are the library exercises generated histories train
+var generatedLifts = map[string]generatedLift{
+	"Back Squat":             {ratio: 1.0, minReps: 3, maxReps: 8, increment: 2.5},
+	"Front Squat":            {ratio: 0.8, minReps: 3, maxReps: 8, increment: 2.5},
+	"Romanian Deadlift":      {ratio: 0.9, minReps: 6, maxReps: 10, increment: 2.5},
+	"Deadlift":               {ratio: 1.2, minReps: 1, maxReps: 5, increment: 2.5},
+	"Hip Thrust":             {ratio: 1.1, minReps: 8, maxReps: 12, increment: 2.5},
+	"Standing Calf Raise":    {ratio: 0.7, minReps: 10, maxReps: 15, increment: 2.5},
+	"Bench Press":            {ratio: 0.75, minReps: 3, maxReps: 8, increment: 2.5},
+	"Incline Dumbbell Press": {ratio: 0.25, minReps: 8, maxReps: 12, increment: 2},
+	"Overhead Press":         {ratio: 0.45, minReps: 3, maxReps: 8, increment: 2.5},
+	"Lateral Raise":          {ratio: 0.08, minReps: 12, maxReps: 20, increment: 1},
+	"Barbell Row":            {ratio: 0.65, minReps: 6, maxReps: 10, increment: 2.5},
+	"Lat Pulldown":           {ratio: 0.6, minReps: 8, maxReps: 12, increment: 2.5},
+	"Dumbbell Curl":          {ratio: 0.13, minReps: 8, maxReps: 15, increment: 1},
+	"Triceps Pushdown":       {ratio: 0.3, minReps: 10, maxReps: 15, increment: 2.5},
+}
+
+// generatedTemplate is a workout users following a split repeat
+type generatedTemplate struct {
+	name      string
+	exercises []string
+}
+
+var (
+	fullBodyA = generatedTemplate{"Full Body A", []string{"Back Squat", "Bench Press", "Barbell Row", "Lateral Raise"}}
+	fullBodyB = generatedTemplate{"Full Body B", []string{"Deadlift", "Overhead Press", "Lat Pulldown", "Dumbbell Curl"}}
+	upper     = generatedTemplate{"Upper", []string{"Bench Press", "Barbell Row", "Overhead Press", "Lat Pulldown", "Dumbbell Curl", "Triceps Pushdown"}}
+	lower     = generatedTemplate{"Lower", []string{"Back Squat", "Romanian Deadlift", "Hip Thrust", "Standing Calf Raise"}}
+	push      = generatedTemplate{"Push", []string{"Bench Press", "Overhead Press", "Incline Dumbbell Press", "Lateral Raise", "Triceps Pushdown"}}
+	pull      = generatedTemplate{"Pull", []string{"Deadlift", "Barbell Row", "Lat Pulldown", "Dumbbell Curl"}}
+	legs      = generatedTemplate{"Legs", []string{"Back Squat", "Front Squat", "Romanian Deadlift", "Standing Calf Raise"}}
+)
+
+// generatedSplits are the programs users follow, by sessions a week; sessions go through
+// the workouts in turn
+var generatedSplits = map[int][]generatedTemplate{
+	2: {fullBodyA, fullBodyB},
+	3: {fullBodyA, fullBodyB},
+	4: {upper, lower},
+	5: {push, pull, legs},
+}
+
+// generatedLevel is a user's experience: stronger users start heavier and gain less
+type generatedLevel struct {
+	strength float64 // Multiplies the lifts' ratios
+	gain     float64 // Fraction added to the lifts over years of training
+}
+
+var generatedLevels = []generatedLevel{
+	{strength: 0.6, gain: 0.8},  // Novice
+	{strength: 1.0, gain: 0.3},  // Intermediate
+	{strength: 1.35, gain: 0.1}, // Advanced
+}
+
+// generatedUser is a user's generated history
+type generatedUser struct {
+	User         fixtureUser
+	Workouts     []*generatedWorkout
+	Sessions     []*generatedSession
+	Measurements []*generatedMeasurement
+}
+
+type generatedWorkout struct {
+	ID        string
+	Name      string
+	CreatedAt time.Time
+	Exercises []*generatedWorkoutExercise
+}
+
+type generatedWorkoutExercise struct {
+	ID       string
+	Exercise string // Library exercise name
+	Sets     int
+	Reps     int
+}
+
+type generatedSession struct {
+	ID        string
+	Workout   *generatedWorkout
+	StartedAt time.Time
+	Duration  time.Duration
+	RPE       int
+	Rating    int
+	Logs      []*generatedLog
+}
+
+// generatedLog is one set; PRs are flagged as the app flags them, against earlier sets
+type generatedLog struct {
+	Exercise           *generatedWorkoutExercise
+	LoggedAt           time.Time
+	Reps               int
+	WeightKg           float64
+	RPE                int
+	PersonalRecord     bool
+	PreviousBestWeight *float64
+	PreviousBestReps   *int
+}
+
+type generatedMeasurement struct {
+	MeasuredAt time.Time
+	WeightKg   float64
+	BodyFat    *float64
+}
+
+// generatedUserFixture is the i-th generated user; IDs are derived from i, so they are
+// the same in every database
+func generatedUserFixture(i int) fixtureUser {
+	return fixtureUser{
+		ID:    uuid.NewSHA1(namespace, fmt.Appendf(nil, "loadgen/%d", i)).String(),
+		Email: fmt.Sprintf("loadgen-%06d@fitapi.dev", i),
+	}
+}
+
+// endMonday is the Monday of the week histories end before
+func (o *GenerateOptions) endMonday() time.Time {
+	end := o.End
+	if end.IsZero() {
+		end = time.Now()
+	}
+	end = end.UTC().Truncate(24 * time.Hour)
+	return end.AddDate(0, 0, -(int(end.Weekday())+6)%7)
+}
+
+// user generates the i-th user's history, the same for the same options and i. Users join
+// within the first half year, train most weeks of their split with holidays and the odd
+// layoff, deload every sixth week and progress with diminishing returns.
+func (o *GenerateOptions) user(i int) *generatedUser {
+	rng := rand.New(rand.NewPCG(o.Seed, uint64(i)))
+	u := &generatedUser{User: generatedUserFixture(i)}
+
+	level := generatedLevels[rng.IntN(len(generatedLevels))]
+	bodyWeight := 55 + rng.Float64()*50
+	var bodyFat *float64
+	if rng.IntN(10) < 4 {
+		fat := 12 + rng.Float64()*18
+		bodyFat = &fat
+	}
+	sessionsPerWeek := 2 + rng.IntN(4)
+	adherence := 0.65 + rng.Float64()*0.3
+
+	weeks := o.Years * 52
+	firstWeek := rng.IntN(26)
+	start := o.endMonday().AddDate(0, 0, -7*weeks)
+
+	// Workouts are planned when the user joins
+	joined := start.AddDate(0, 0, 7*firstWeek).Add(time.Duration(rng.IntN(24*60)) * time.Minute)
+	for _, t := range generatedSplits[sessionsPerWeek] {
+		w := &generatedWorkout{ID: uuid.NewString(), Name: t.name, CreatedAt: joined}
+		for _, name := range t.exercises {
+			lift := generatedLifts[name]
+			w.Exercises = append(w.Exercises, &generatedWorkoutExercise{
+				ID:       uuid.NewString(),
+				Exercise: name,
+				Sets:     3 + rng.IntN(3),
+				Reps:     (lift.minReps + lift.maxReps) / 2,
+			})
+		}
+		u.Workouts = append(u.Workouts, w)
+	}
+
+	baseStrength := make(map[string]float64, len(generatedLifts))
+	for _, w := range u.Workouts {
+		for _, e := range w.Exercises {
+			if _, ok := baseStrength[e.Exercise]; !ok {
+				baseStrength[e.Exercise] = bodyWeight * generatedLifts[e.Exercise].ratio * level.strength * (0.85 + rng.Float64()*0.3)
+			}
+		}
+	}
+
+	bestWeight := make(map[string]float64)
+	bestReps := make(map[string]int)
+	trainedWeeks, detraining, weeksSinceDeload, skipWeeks, next := 0, 1.0, 0, 0, 0
+
+	for week := firstWeek; week < weeks; week++ {
+		weekStart := start.AddDate(0, 0, 7*week)
+
+		if rng.Float64() < 0.6 {
+			bodyWeight += rng.NormFloat64() * 0.3
+			measuredAt := weekStart.AddDate(0, 0, rng.IntN(7)).Add(time.Duration(6*60+rng.IntN(180)) * time.Minute)
+			m := &generatedMeasurement{MeasuredAt: measuredAt, WeightKg: math.Round(bodyWeight*10) / 10}
+			if bodyFat != nil {
+				fat := math.Round((*bodyFat+rng.NormFloat64()*0.5)*10) / 10
+				m.BodyFat = &fat
+			}
+			u.Measurements = append(u.Measurements, m)
+		}
+
+		if skipWeeks > 0 {
+			skipWeeks--
+			continue
+		}
+		switch r := rng.Float64(); {
+		case r < 1.0/150: // Layoff: injury or life; strength is lost
+			skipWeeks = 3 + rng.IntN(9)
+			detraining *= 0.85
+			continue
+		case r < 1.0/150+1.0/20: // Holiday
+			skipWeeks = rng.IntN(2)
+			continue
+		}
+
+		deload := weeksSinceDeload == 5
+		if deload {
+			weeksSinceDeload = 0
+		} else {
+			weeksSinceDeload++
+		}
+		progress := 1 + level.gain*(1-math.Exp(-float64(trainedWeeks)/60))
+		trainedWeeks++
+		detraining = math.Min(1, detraining+0.02)
+
+		for n := range sessionsPerWeek {
+			if rng.Float64() > adherence {
+				continue
+			}
+			workout := u.Workouts[next%len(u.Workouts)]
+			next++
+
+			day := n * 7 / sessionsPerWeek
+			startedAt := weekStart.AddDate(0, 0, day).Add(time.Duration(6*60+rng.IntN(15*60)) * time.Minute)
+			session := &generatedSession{ID: uuid.NewString(), Workout: workout, StartedAt: startedAt, Rating: 3 + rng.IntN(3)}
+
+			at := startedAt.Add(5 * time.Minute)
+			for _, e := range workout.Exercises {
+				lift := generatedLifts[e.Exercise]
+				strength := baseStrength[e.Exercise] * progress * detraining * (0.97 + rng.Float64()*0.06)
+				reps := lift.minReps + rng.IntN(lift.maxReps-lift.minReps+1)
+				sets := e.Sets
+				if deload {
+					strength *= 0.85
+					sets = max(2, sets-1)
+				}
+				// Epley: the weight for reps with the same estimated max as 5 reps of strength
+				weight := math.Max(lift.increment, math.Round(strength*(1+5.0/30)/(1+float64(reps)/30)/lift.increment)*lift.increment)
+
+				for set := range sets {
+					log := &generatedLog{
+						Exercise: e,
+						LoggedAt: at,
+						Reps:     max(1, reps-rng.IntN(set+1)/2),
+						WeightKg: weight,
+						RPE:      min(10, 6+set+rng.IntN(2)),
+					}
+					if deload {
+						log.RPE = 5 + rng.IntN(2)
+					}
+					if best, ok := bestWeight[e.Exercise]; ok {
+						log.PreviousBestWeight = &best
+						previousReps := bestReps[e.Exercise]
+						log.PreviousBestReps = &previousReps
+						log.PersonalRecord = log.WeightKg > best
+					} else {
+						log.PersonalRecord = true
+					}
+					bestWeight[e.Exercise] = math.Max(bestWeight[e.Exercise], log.WeightKg)
+					bestReps[e.Exercise] = max(bestReps[e.Exercise], log.Reps)
+
+					session.RPE = max(session.RPE, log.RPE)
+					session.Logs = append(session.Logs, log)
+					at = at.Add(time.Duration(150+rng.IntN(90)) * time.Second)
+				}
+			}
+			session.Duration = at.Sub(startedAt).Round(time.Minute)
+			u.Sessions = append(u.Sessions, session)
+		}
+	}
+	return u
+}
+
+// Generate creates users with multi-year training histories, for testing pagination,
+// analytics and indexes at scale. Each user is written in one transaction with webhooks
+// and notifications suppressed; users who already have sessions are skipped, so an
+// interrupted run can be resumed. The exercise library is loaded first.
+func Generate(ctx context.Context, db *pgxpool.Pool, opts GenerateOptions) ([]*Report, error) {
+	reports, err := Load(ctx, db, "exercise-library")
+	if err != nil {
+		return reports, err
+	}
+
+	exerciseIDs, err := generatedExerciseIDs(ctx, db)
+	if err != nil {
+		return reports, err
+	}
+
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = 4
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	report := &Report{Bundle: "loadgen", Inserted: make(map[string]int64)}
+	indexes := make(chan int)
+	var (
+		mu       sync.Mutex
+		firstErr error
+		done     int
+		wg       sync.WaitGroup
+	)
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				userReport := &Report{Inserted: make(map[string]int64)}
+				err := writeGeneratedUser(ctx, db, opts.user(i), exerciseIDs, userReport)
+
+				mu.Lock()
+				if err != nil && firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				for table, n := range userReport.Inserted {
+					report.add(table, n)
+				}
+				done++
+				if opts.Progress != nil {
+					opts.Progress(done, opts.Users)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+send:
+	for i := range opts.Users {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break send
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	return append(reports, report), firstErr
+}
+
+// generatedExerciseIDs finds the library exercises generated histories train
+func generatedExerciseIDs(ctx context.Context, db *pgxpool.Pool) (map[string]string, error) {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	ids, err := libraryExercises(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	for name := range generatedLifts {
+		if _, ok := ids[name]; !ok {
+			return nil, fmt.Errorf("the exercise library has no %s", name)
+		}
+	}
+	return ids, nil
+}
+
+// writeGeneratedUser writes a user's history with COPY, unless the user has sessions.
+// Rows are created when they happened but updated now, so trend snapshots pick them up.
+func writeGeneratedUser(ctx context.Context, db *pgxpool.Pool, u *generatedUser, exerciseIDs map[string]string, report *Report) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, `SELECT set_config('fitapi.suppress_webhooks', 'on', true)`); err != nil {
+		return err
+	}
+	if err := ensureUser(ctx, tx, u.User, report); err != nil {
+		return err
+	}
+
+	var seeded bool
+	if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM workout_sessions WHERE user_id = $1)`, u.User.ID).Scan(&seeded); err != nil {
+		return err
+	}
+	if seeded {
+		return nil
+	}
+
+	var workoutRows, workoutExerciseRows, sessionRows, logRows, measurementRows [][]any
+	for _, w := range u.Workouts {
+		workoutRows = append(workoutRows, []any{w.ID, u.User.ID, w.Name, w.CreatedAt})
+		for i, e := range w.Exercises {
+			workoutExerciseRows = append(workoutExerciseRows, []any{e.ID, w.ID, exerciseIDs[e.Exercise], i + 1, e.Sets, e.Reps})
+		}
+	}
+	for _, s := range u.Sessions {
+		completedAt := s.StartedAt.Add(s.Duration)
+		sessionRows = append(sessionRows, []any{
+			s.ID, u.User.ID, s.Workout.ID, s.Workout.Name, s.StartedAt, completedAt, int(s.Duration.Minutes()), "completed",
+			s.RPE, s.Rating, completedAt,
+		})
+		for i, l := range s.Logs {
+			logRows = append(logRows, []any{
+				s.ID, exerciseIDs[l.Exercise.Exercise], l.Exercise.ID, i + 1, 1, 1, l.Reps, l.Exercise.Reps, l.WeightKg, l.RPE,
+				l.PersonalRecord, l.PreviousBestWeight, l.PreviousBestReps, l.LoggedAt,
+			})
+		}
+	}
+	for _, m := range u.Measurements {
+		measurementRows = append(measurementRows, []any{u.User.ID, m.MeasuredAt, m.WeightKg, m.BodyFat, m.MeasuredAt})
+	}
+
+	copies := []struct {
+		table   string
+		columns []string
+		rows    [][]any
+	}{
+		{"workouts", []string{"id", "user_id", "name", "created_at"}, workoutRows},
+		{"workout_exercises", []string{"id", "workout_id", "exercise_id", "order_index", "sets", "reps"}, workoutExerciseRows},
+		{"workout_sessions", []string{
+			"id", "user_id", "workout_id", "name", "started_at", "completed_at", "duration_minutes", "status",
+			"perceived_exertion", "workout_rating", "created_at",
+		}, sessionRows},
+		{"exercise_logs", []string{
+			"workout_session_id", "exercise_id", "workout_exercise_id", "order_index", "sets_completed", "sets_planned",
+			"reps_completed", "reps_planned", "weight_kg", "rpe", "is_personal_record", "previous_best_weight",
+			"previous_best_reps", "created_at",
+		}, logRows},
+		{"body_measurements", []string{"user_id", "measured_at", "weight_kg", "body_fat_percentage", "created_at"}, measurementRows},
+	}
+	for _, c := range copies {
+		n, err := tx.CopyFrom(ctx, pgx.Identifier{c.table}, c.columns, pgx.CopyFromRows(c.rows))
+		if err != nil {
+			return fmt.Errorf("failed to write %s of %s: %w", c.table, u.User.Email, err)
+		}
+		report.add(c.table, n)
+	}
+
+	return tx.Commit(ctx)
+}
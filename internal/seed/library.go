@@ -0,0 +1,117 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// libraryFixture is the public exercise library; equipment and exercises are matched by
+// name among the owner's
+type libraryFixture struct {
+	Owner     fixtureUser `json:"owner"`
+	Equipment []struct {
+		Name        string `json:"name"`
+		Description string `json:"description"`
+	} `json:"equipment"`
+	Exercises []struct {
+		Name        string   `json:"name"`
+		Description string   `json:"description"`
+		MuscleGroup string   `json:"muscle_group"`
+		Equipment   []string `json:"equipment"`
+	} `json:"exercises"`
+}
+
+func loadExerciseLibrary(ctx context.Context, tx pgx.Tx, report *Report) error {
+	var library libraryFixture
+	if err := readFixture("exercise-library", &library); err != nil {
+		return err
+	}
+	if err := ensureUser(ctx, tx, library.Owner, report); err != nil {
+		return err
+	}
+
+	equipmentQuery := `
+		INSERT INTO equipment (user_id, name, description)
+		SELECT $1, $2, $3
+		WHERE NOT EXISTS (SELECT 1 FROM equipment WHERE user_id = $1 AND organization_id IS NULL AND LOWER(name) = LOWER($2))
+	`
+	for _, e := range library.Equipment {
+		tag, err := tx.Exec(ctx, equipmentQuery, library.Owner.ID, e.Name, e.Description)
+		if err != nil {
+			return fmt.Errorf("failed to insert equipment %s: %w", e.Name, err)
+		}
+		report.add("equipment", tag.RowsAffected())
+	}
+	equipmentIDs, err := namedIDs(ctx, tx, `SELECT name, id FROM equipment WHERE user_id = $1`, library.Owner.ID)
+	if err != nil {
+		return err
+	}
+
+	exerciseQuery := `
+		INSERT INTO exercises (user_id, name, description, is_public, muscle_group)
+		SELECT $1, $2, $3, TRUE, $4
+		WHERE NOT EXISTS (SELECT 1 FROM exercises WHERE user_id = $1 AND LOWER(name) = LOWER($2))
+	`
+	for _, e := range library.Exercises {
+		tag, err := tx.Exec(ctx, exerciseQuery, library.Owner.ID, e.Name, e.Description, e.MuscleGroup)
+		if err != nil {
+			return fmt.Errorf("failed to insert exercise %s: %w", e.Name, err)
+		}
+		report.add("exercises", tag.RowsAffected())
+	}
+	exerciseIDs, err := namedIDs(ctx, tx, `SELECT name, id FROM exercises WHERE user_id = $1`, library.Owner.ID)
+	if err != nil {
+		return err
+	}
+
+	linkQuery := `
+		INSERT INTO exercise_equipment (exercise_id, equipment_id)
+		VALUES ($1, $2)
+		ON CONFLICT DO NOTHING
+	`
+	for _, e := range library.Exercises {
+		for _, name := range e.Equipment {
+			equipmentID, ok := equipmentIDs[name]
+			if !ok {
+				return fmt.Errorf("exercise %s uses unknown equipment %s", e.Name, name)
+			}
+			tag, err := tx.Exec(ctx, linkQuery, exerciseIDs[e.Name], equipmentID)
+			if err != nil {
+				return fmt.Errorf("failed to link %s to %s: %w", e.Name, name, err)
+			}
+			report.add("exercise_equipment", tag.RowsAffected())
+		}
+	}
+
+	return nil
+}
+
+// libraryExercises returns the IDs of the library's exercises by name
+func libraryExercises(ctx context.Context, tx pgx.Tx) (map[string]string, error) {
+	var library libraryFixture
+	if err := readFixture("exercise-library", &library); err != nil {
+		return nil, err
+	}
+	return namedIDs(ctx, tx, `SELECT name, id FROM exercises WHERE user_id = $1 AND is_public`, library.Owner.ID)
+}
+
+// namedIDs runs a query selecting name and id pairs into a map
+func namedIDs(ctx context.Context, tx pgx.Tx, query string, args ...any) (map[string]string, error) {
+	rows, err := tx.Query(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := make(map[string]string)
+	for rows.Next() {
+		var name, id string
+		if err := rows.Scan(&name, &id); err != nil {
+			return nil, err
+		}
+		ids[name] = id
+	}
+	return ids, rows.Err()
+}
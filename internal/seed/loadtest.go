@@ -0,0 +1,172 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// loadTestFixture sizes the load test users' histories
+type loadTestFixture struct {
+	Users               int    `json:"users"`
+	Start               string `json:"start"` // Monday of the first week, YYYY-MM-DD (UTC)
+	Weeks               int    `json:"weeks"`
+	SessionsPerWeek     int    `json:"sessions_per_week"`
+	ExercisesPerSession int    `json:"exercises_per_session"`
+	SetsPerExercise     int    `json:"sets_per_exercise"`
+}
+
+// loadTestSession is a generated session and its exercise logs
+type loadTestSession struct {
+	ID        string
+	StartedAt time.Time
+	Duration  time.Duration
+	Logs      []*loadTestLog
+}
+
+type loadTestLog struct {
+	ExerciseID string
+	Reps       int
+	WeightKg   float64
+}
+
+// loadTestUser is the i-th load test user; IDs are derived from i, so they are the same
+// in every database
+func loadTestUser(i int) fixtureUser {
+	return fixtureUser{
+		ID:    uuid.NewSHA1(namespace, fmt.Appendf(nil, "load-test/%d", i)).String(),
+		Email: fmt.Sprintf("load-%03d@fitapi.dev", i),
+	}
+}
+
+// sessions generates the i-th user's history from exercises, with the same dates, sets and
+// weights for the same i: sessions spread over each week, rotating through the exercises
+// with slowly rising weights
+func (f *loadTestFixture) sessions(i int, exerciseIDs []string) ([]*loadTestSession, error) {
+	start, err := time.Parse("2006-01-02", f.Start)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start %q", f.Start)
+	}
+
+	rng := rand.New(rand.NewPCG(uint64(i), 0x5eed))
+	baseWeights := make([]float64, len(exerciseIDs))
+	for j := range baseWeights {
+		baseWeights[j] = 20 + float64(rng.IntN(60))
+	}
+
+	var sessions []*loadTestSession
+	for week := range f.Weeks {
+		for n := range f.SessionsPerWeek {
+			day := n * 7 / f.SessionsPerWeek
+			startedAt := start.AddDate(0, 0, week*7+day).Add(time.Duration(6+rng.IntN(14)) * time.Hour)
+			session := &loadTestSession{
+				ID:        uuid.NewString(),
+				StartedAt: startedAt,
+				Duration:  time.Duration(40+rng.IntN(40)) * time.Minute,
+			}
+
+			first := len(sessions) * f.ExercisesPerSession
+			for k := range f.ExercisesPerSession {
+				j := (first + k) % len(exerciseIDs)
+				weight := baseWeights[j] * (1 + float64(week)*0.005)
+				for range f.SetsPerExercise {
+					session.Logs = append(session.Logs, &loadTestLog{
+						ExerciseID: exerciseIDs[j],
+						Reps:       5 + rng.IntN(8),
+						WeightKg:   math.Round(weight/2.5) * 2.5,
+					})
+				}
+			}
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+func loadLoadTest(ctx context.Context, tx pgx.Tx, report *Report) error {
+	var fixture loadTestFixture
+	if err := readFixture("load-test", &fixture); err != nil {
+		return err
+	}
+	var library libraryFixture
+	if err := readFixture("exercise-library", &library); err != nil {
+		return err
+	}
+	ids, err := libraryExercises(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	// Weighted exercises, in library order so every database gets the same histories
+	var exerciseIDs []string
+	for _, e := range library.Exercises {
+		if id, ok := ids[e.Name]; ok && e.MuscleGroup != "cardio" && e.MuscleGroup != "core" {
+			exerciseIDs = append(exerciseIDs, id)
+		}
+	}
+	if len(exerciseIDs) == 0 {
+		return fmt.Errorf("the exercise library has no weighted exercises")
+	}
+
+	for i := range fixture.Users {
+		user := loadTestUser(i)
+		if err := ensureUser(ctx, tx, user, report); err != nil {
+			return err
+		}
+
+		// A user's history is written whole, so any session means it is already seeded
+		var seeded bool
+		if err := tx.QueryRow(ctx, `SELECT EXISTS (SELECT 1 FROM workout_sessions WHERE user_id = $1)`, user.ID).Scan(&seeded); err != nil {
+			return err
+		}
+		if seeded {
+			continue
+		}
+
+		sessions, err := fixture.sessions(i, exerciseIDs)
+		if err != nil {
+			return err
+		}
+		if err := copyLoadTestSessions(ctx, tx, user.ID, sessions, report); err != nil {
+			return fmt.Errorf("failed to write sessions of %s: %w", user.Email, err)
+		}
+	}
+
+	return nil
+}
+
+// copyLoadTestSessions writes a user's sessions and logs with COPY, IDs generated upfront
+func copyLoadTestSessions(ctx context.Context, tx pgx.Tx, userID string, sessions []*loadTestSession, report *Report) error {
+	var sessionRows, logRows [][]any
+	for _, s := range sessions {
+		sessionRows = append(sessionRows, []any{
+			s.ID, userID, "Load test", s.StartedAt, s.StartedAt.Add(s.Duration), int(s.Duration.Minutes()), "completed",
+		})
+		for i, l := range s.Logs {
+			logRows = append(logRows, []any{s.ID, l.ExerciseID, i + 1, 1, 1, l.Reps, l.WeightKg})
+		}
+	}
+
+	n, err := tx.CopyFrom(ctx, pgx.Identifier{"workout_sessions"},
+		[]string{"id", "user_id", "name", "started_at", "completed_at", "duration_minutes", "status"},
+		pgx.CopyFromRows(sessionRows))
+	if err != nil {
+		return err
+	}
+	report.add("workout_sessions", n)
+
+	n, err = tx.CopyFrom(ctx, pgx.Identifier{"exercise_logs"},
+		[]string{"workout_session_id", "exercise_id", "order_index", "sets_completed", "sets_planned", "reps_completed", "weight_kg"},
+		pgx.CopyFromRows(logRows))
+	if err != nil {
+		return err
+	}
+	report.add("exercise_logs", n)
+
+	return nil
+}
@@ -0,0 +1,198 @@
+// Package seed loads named fixture bundles into a migrated database: the public exercise
+// library, a demo user with a few months of training, and many users for load tests.
+//
+// Bundles are idempotent: rows are matched by natural keys (names, session start times,
+// weigh-in times) and only missing ones are inserted, so loading a bundle again is safe.
+// cmd/seed loads bundles from the command line; tests against a database call Load.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+//go:embed fixtures/*.json
+var fixtures embed.FS
+
+// namespace derives the fixed IDs of generated users
+var namespace = uuid.MustParse("5eed0000-0000-4000-8000-000000000000")
+
+// Bundle is a named set of fixtures
+type Bundle struct {
+	Name        string
+	Description string
+	Requires    []string // Bundles loaded first
+
+	load func(ctx context.Context, tx pgx.Tx, report *Report) error
+}
+
+var bundles = []*Bundle{
+	{
+		Name:        "exercise-library",
+		Description: "Public exercises with muscle groups and equipment, owned by library@fitapi.dev",
+		load:        loadExerciseLibrary,
+	},
+	{
+		Name:        "demo-user",
+		Description: "demo@fitapi.dev (password demo-password) with three workouts, twelve weeks of sessions and weekly weigh-ins",
+		Requires:    []string{"exercise-library"},
+		load:        loadDemoUser,
+	},
+	{
+		Name:        "load-test",
+		Description: "100 users (load-000@fitapi.dev...) with a year of sessions each",
+		Requires:    []string{"exercise-library"},
+		load:        loadLoadTest,
+	},
+}
+
+// Bundles lists every bundle
+func Bundles() []*Bundle {
+	return bundles
+}
+
+// Report counts the rows a bundle inserted, by table
+type Report struct {
+	Bundle   string
+	Inserted map[string]int64
+}
+
+func (r *Report) add(table string, n int64) {
+	r.Inserted[table] += n
+}
+
+// String summarizes the report, e.g. "demo-user: inserted 36 workout_sessions, 3 workouts"
+func (r *Report) String() string {
+	var parts []string
+	for table, n := range r.Inserted {
+		if n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", n, table))
+		}
+	}
+	if len(parts) == 0 {
+		return r.Bundle + ": already loaded"
+	}
+	sort.Strings(parts)
+	return r.Bundle + ": inserted " + strings.Join(parts, ", ")
+}
+
+// Resolve returns the named bundles and the bundles they require, each once, every bundle
+// after those it requires
+func Resolve(names []string) ([]*Bundle, error) {
+	byName := make(map[string]*Bundle, len(bundles))
+	for _, b := range bundles {
+		byName[b.Name] = b
+	}
+
+	var resolved []*Bundle
+	seen := make(map[string]bool)
+	var visit func(name string) error
+	visit = func(name string) error {
+		b, ok := byName[name]
+		if !ok {
+			return fmt.Errorf("unknown bundle %q", name)
+		}
+		if seen[name] {
+			return nil
+		}
+		seen[name] = true
+		for _, required := range b.Requires {
+			if err := visit(required); err != nil {
+				return err
+			}
+		}
+		resolved = append(resolved, b)
+		return nil
+	}
+
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return resolved, nil
+}
+
+// Load loads the named bundles and those they require, each in its own transaction, and
+// reports what each inserted. Bundles loaded before a failure stay loaded.
+func Load(ctx context.Context, db *pgxpool.Pool, names ...string) ([]*Report, error) {
+	resolved, err := Resolve(names)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []*Report
+	for _, b := range resolved {
+		report := &Report{Bundle: b.Name, Inserted: make(map[string]int64)}
+		if err := loadBundle(ctx, db, b, report); err != nil {
+			return reports, fmt.Errorf("failed to load %s: %w", b.Name, err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func loadBundle(ctx context.Context, db *pgxpool.Pool, b *Bundle, report *Report) error {
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := b.load(ctx, tx, report); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
+
+// readFixture decodes fixtures/<name>.json, rejecting unknown fields so typos surface
+func readFixture(name string, v any) error {
+	data, err := fixtures.ReadFile("fixtures/" + name + ".json")
+	if err != nil {
+		return err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("invalid fixture %s: %w", name, err)
+	}
+	return nil
+}
+
+// fixtureUser is a Supabase auth user, matched by ID
+type fixtureUser struct {
+	ID       string `json:"id"`
+	Email    string `json:"email"`
+	Password string `json:"password"` // Set on creation so the user can sign in; optional
+}
+
+// ensureUser creates the auth user unless it exists, confirmed so it can sign in
+func ensureUser(ctx context.Context, tx pgx.Tx, user fixtureUser, report *Report) error {
+	query := `
+		INSERT INTO auth.users (id, aud, role, email, email_confirmed_at, created_at, updated_at)
+		VALUES ($1, 'authenticated', 'authenticated', $2, NOW(), NOW(), NOW())
+		ON CONFLICT (id) DO NOTHING
+	`
+	tag, err := tx.Exec(ctx, query, user.ID, user.Email)
+	if err != nil {
+		return fmt.Errorf("failed to create user %s: %w", user.Email, err)
+	}
+	report.add("users", tag.RowsAffected())
+
+	if tag.RowsAffected() == 1 && user.Password != "" {
+		_, err := tx.Exec(ctx, `UPDATE auth.users SET encrypted_password = crypt($2, gen_salt('bf')) WHERE id = $1`, user.ID, user.Password)
+		if err != nil {
+			return fmt.Errorf("failed to set the password of %s: %w", user.Email, err)
+		}
+	}
+	return nil
+}
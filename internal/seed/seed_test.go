@@ -0,0 +1,191 @@
+package seed
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolve_RequiredBundlesFirst(t *testing.T) {
+	resolved, err := Resolve([]string{"demo-user", "load-test", "exercise-library"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	var names []string
+	for _, b := range resolved {
+		names = append(names, b.Name)
+	}
+	if len(names) != 3 || names[0] != "exercise-library" || names[1] != "demo-user" || names[2] != "load-test" {
+		t.Errorf("Expected the library once and first, got %v", names)
+	}
+
+	if _, err := Resolve([]string{"demo-user", "nope"}); err == nil {
+		t.Error("Expected an error for an unknown bundle")
+	}
+}
+
+func TestFixtures_Valid(t *testing.T) {
+	var library libraryFixture
+	if err := readFixture("exercise-library", &library); err != nil {
+		t.Fatalf("Expected a valid library, got %v", err)
+	}
+	equipment := make(map[string]bool)
+	for _, e := range library.Equipment {
+		equipment[e.Name] = true
+	}
+	exercises := make(map[string]bool)
+	for _, e := range library.Exercises {
+		if exercises[e.Name] {
+			t.Errorf("Exercise %s is listed twice", e.Name)
+		}
+		exercises[e.Name] = true
+		for _, name := range e.Equipment {
+			if !equipment[name] {
+				t.Errorf("Exercise %s uses unknown equipment %s", e.Name, name)
+			}
+		}
+	}
+
+	var demo demoFixture
+	if err := readFixture("demo-user", &demo); err != nil {
+		t.Fatalf("Expected a valid demo user, got %v", err)
+	}
+	for _, w := range demo.Workouts {
+		for _, e := range w.Exercises {
+			if !exercises[e.Exercise] {
+				t.Errorf("Workout %s uses %s, which is not in the library", w.Name, e.Exercise)
+			}
+		}
+	}
+
+	var loadTest loadTestFixture
+	if err := readFixture("load-test", &loadTest); err != nil {
+		t.Fatalf("Expected a valid load test, got %v", err)
+	}
+}
+
+func TestDemoSessions_WeeklyProgram(t *testing.T) {
+	var demo demoFixture
+	if err := readFixture("demo-user", &demo); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := demo.sessions()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(sessions) != demo.Weeks*len(demo.Workouts) {
+		t.Fatalf("Expected %d sessions, got %d", demo.Weeks*len(demo.Workouts), len(sessions))
+	}
+
+	first := sessions[0]
+	if !first.StartedAt.Equal(time.Date(2026, 1, 5, 18, 0, 0, 0, time.UTC)) || first.Workout.Name != "Upper" {
+		t.Errorf("Expected Upper on Monday January 5th at 18:00, got %s at %v", first.Workout.Name, first.StartedAt)
+	}
+	last := sessions[len(sessions)-1]
+	if last.Week != demo.Weeks-1 || last.StartedAt.Weekday() != time.Saturday {
+		t.Errorf("Expected the last session on the last Saturday, got week %d on %v", last.Week, last.StartedAt)
+	}
+
+	bench := first.Workout.Exercises[0]
+	if w := bench.weightKg(4); w == nil || *w != 75 {
+		t.Errorf("Expected the bench press at 75kg in week 5, got %v", w)
+	}
+
+	demo.Start = "2026-01-06"
+	if _, err := demo.sessions(); err == nil {
+		t.Error("Expected an error for a start that is not a Monday")
+	}
+}
+
+func TestLoadTest_Deterministic(t *testing.T) {
+	if loadTestUser(7) != loadTestUser(7) || loadTestUser(7).ID == loadTestUser(8).ID {
+		t.Error("Expected load test user IDs to be derived from their index")
+	}
+
+	fixture := &loadTestFixture{Start: "2025-01-06", Weeks: 4, SessionsPerWeek: 3, ExercisesPerSession: 2, SetsPerExercise: 3}
+	exercises := []string{"a", "b", "c"}
+	a, err := fixture.sessions(3, exercises)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	b, _ := fixture.sessions(3, exercises)
+	if len(a) != 12 || len(a[0].Logs) != 6 {
+		t.Fatalf("Expected 12 sessions of 6 sets, got %d sessions of %d", len(a), len(a[0].Logs))
+	}
+	for i := range a {
+		if !a[i].StartedAt.Equal(b[i].StartedAt) || a[i].Logs[0].WeightKg != b[i].Logs[0].WeightKg || a[i].Logs[0].Reps != b[i].Logs[0].Reps {
+			t.Fatalf("Expected the same history for the same user, session %d differs", i)
+		}
+	}
+	if a[1].Logs[0].ExerciseID != "c" {
+		t.Errorf("Expected the second session to continue the rotation, got %s", a[1].Logs[0].ExerciseID)
+	}
+}
+
+func TestGenerate_Realistic(t *testing.T) {
+	var library libraryFixture
+	if err := readFixture("exercise-library", &library); err != nil {
+		t.Fatal(err)
+	}
+	inLibrary := make(map[string]bool)
+	for _, e := range library.Exercises {
+		inLibrary[e.Name] = true
+	}
+	for name, lift := range generatedLifts {
+		if !inLibrary[name] || lift.minReps > lift.maxReps {
+			t.Errorf("Lift %s is not in the library or has no rep range", name)
+		}
+	}
+	for n, split := range generatedSplits {
+		for _, template := range split {
+			for _, name := range template.exercises {
+				if _, ok := generatedLifts[name]; !ok {
+					t.Errorf("Split of %d sessions: %s has no lift %s", n, template.name, name)
+				}
+			}
+		}
+	}
+
+	opts := &GenerateOptions{Years: 3, End: time.Date(2026, 10, 14, 0, 0, 0, 0, time.UTC), Seed: 1}
+	end := time.Date(2026, 10, 12, 0, 0, 0, 0, time.UTC)
+	if !opts.endMonday().Equal(end) {
+		t.Fatalf("Expected histories to end on Monday %s, got %s", end, opts.endMonday())
+	}
+
+	for i := range 20 {
+		u := opts.user(i)
+		if len(u.Sessions) < 3*52 {
+			t.Fatalf("User %d: expected years of sessions, got %d", i, len(u.Sessions))
+		}
+		if first := u.Sessions[0].StartedAt; first.Before(end.AddDate(-3, 0, -7)) || u.Sessions[len(u.Sessions)-1].StartedAt.After(end) {
+			t.Errorf("User %d: sessions outside the history", i)
+		}
+
+		best := make(map[string]float64)
+		for _, s := range u.Sessions {
+			for _, l := range s.Logs {
+				previous, seen := best[l.Exercise.Exercise]
+				if l.PersonalRecord != (!seen || l.WeightKg > previous) {
+					t.Fatalf("User %d: %s at %.1fkg after %.1fkg flagged PR %v", i, l.Exercise.Exercise, l.WeightKg, previous, l.PersonalRecord)
+				}
+				best[l.Exercise.Exercise] = max(previous, l.WeightKg)
+			}
+		}
+
+		// Training over years makes everyone stronger
+		first, last := u.Sessions[0].Logs[0], u.Sessions[len(u.Sessions)-1].Logs
+		for _, l := range last {
+			if l.Exercise.Exercise == first.Exercise.Exercise && best[l.Exercise.Exercise] <= first.WeightKg {
+				t.Errorf("User %d: expected %s to progress", i, l.Exercise.Exercise)
+			}
+		}
+	}
+
+	a, b := opts.user(3), opts.user(3)
+	if len(a.Sessions) != len(b.Sessions) || !a.Sessions[10].StartedAt.Equal(b.Sessions[10].StartedAt) || a.Sessions[10].Logs[0].WeightKg != b.Sessions[10].Logs[0].WeightKg {
+		t.Error("Expected the same history for the same seed")
+	}
+	reseeded := &GenerateOptions{Years: 3, End: opts.End, Seed: 2}
+	if c := reseeded.user(3); c.User != a.User || len(c.Sessions) == len(a.Sessions) && c.Sessions[0].StartedAt.Equal(a.Sessions[0].StartedAt) {
+		t.Error("Expected another seed to change the history but not the user")
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// AccessPolicy decides whether an actor may read or modify data owned by another user
+// or shared within an organization. Owners always have full access to their own data;
+// other actors need a delegated grant or an organization role.
+type AccessPolicy interface {
+	CanRead(ctx context.Context, actorID, ownerID string) (bool, error)
+	CanWrite(ctx context.Context, actorID, ownerID string) (bool, error)
+	CanReadOrg(ctx context.Context, actorID, orgID string) (bool, error)
+	CanWriteOrg(ctx context.Context, actorID, orgID string) (bool, error)
+}
+
+// DelegatedAccessPolicy grants coaches access to their clients' data
+// through active coach-client relationships, and organization members
+// access to org-scoped resources according to their role
+type DelegatedAccessPolicy struct {
+	coachClients  repositories.CoachClientRepository
+	organizations repositories.OrganizationRepository
+}
+
+// NewAccessPolicy creates the default access policy
+func NewAccessPolicy(coachClients repositories.CoachClientRepository, organizations repositories.OrganizationRepository) *DelegatedAccessPolicy {
+	return &DelegatedAccessPolicy{coachClients: coachClients, organizations: organizations}
+}
+
+// CanRead reports whether actorID may view ownerID's data
+func (p *DelegatedAccessPolicy) CanRead(ctx context.Context, actorID, ownerID string) (bool, error) {
+	if actorID == ownerID {
+		return true, nil
+	}
+
+	_, found, err := p.activeRelation(ctx, actorID, ownerID)
+	return found, err
+}
+
+// CanWrite reports whether actorID may modify ownerID's data
+func (p *DelegatedAccessPolicy) CanWrite(ctx context.Context, actorID, ownerID string) (bool, error) {
+	if actorID == ownerID {
+		return true, nil
+	}
+
+	canWrite, found, err := p.activeRelation(ctx, actorID, ownerID)
+	return found && canWrite, err
+}
+
+// CanReadOrg reports whether actorID may view resources shared in orgID (any member)
+func (p *DelegatedAccessPolicy) CanReadOrg(ctx context.Context, actorID, orgID string) (bool, error) {
+	role, err := p.orgRole(ctx, actorID, orgID)
+	return role != "", err
+}
+
+// CanWriteOrg reports whether actorID may manage resources shared in orgID (owners and trainers)
+func (p *DelegatedAccessPolicy) CanWriteOrg(ctx context.Context, actorID, orgID string) (bool, error) {
+	role, err := p.orgRole(ctx, actorID, orgID)
+	return role == models.OrgRoleOwner || role == models.OrgRoleTrainer, err
+}
+
+func (p *DelegatedAccessPolicy) activeRelation(ctx context.Context, coachID, clientID string) (canWrite bool, found bool, err error) {
+	relation, err := p.coachClients.FindActive(ctx, coachID, clientID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return false, false, nil
+		}
+		return false, false, fmt.Errorf("failed to check coach access: %w", err)
+	}
+
+	return relation.CanWrite, true, nil
+}
+
+// orgRole returns the actor's role in the organization, or "" when not a member
+func (p *DelegatedAccessPolicy) orgRole(ctx context.Context, actorID, orgID string) (string, error) {
+	member, err := p.organizations.FindMember(ctx, orgID, actorID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to check organization membership: %w", err)
+	}
+
+	return member.Role, nil
+}
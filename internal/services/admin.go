@@ -0,0 +1,53 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+const (
+	defaultLargestUsers = 10
+	maxLargestUsers     = 100
+)
+
+// AdminService handles operator-only reporting
+type AdminService struct {
+	storage repositories.StorageRepository
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(storage repositories.StorageRepository) *AdminService {
+	return &AdminService{storage: storage}
+}
+
+// StorageReport summarizes database size, per-table usage and the users owning the most data
+// limit caps the number of users returned; values outside 1..100 fall back to the default of 10
+func (s *AdminService) StorageReport(ctx context.Context, limit int) (*models.StorageReport, error) {
+	if limit <= 0 || limit > maxLargestUsers {
+		limit = defaultLargestUsers
+	}
+
+	size, err := s.storage.DatabaseSize(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get database size: %w", err)
+	}
+
+	tables, err := s.storage.TableStats(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get table stats: %w", err)
+	}
+
+	users, err := s.storage.LargestUsers(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get largest users: %w", err)
+	}
+
+	return &models.StorageReport{
+		DatabaseBytes: size,
+		Tables:        tables,
+		LargestUsers:  users,
+	}, nil
+}
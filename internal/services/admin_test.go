@@ -0,0 +1,75 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestStorageReport_Success(t *testing.T) {
+	var gotLimit int
+	mockRepo := &repositories.MockStorageRepository{
+		DatabaseSizeFunc: func(ctx context.Context) (int64, error) {
+			return 4096, nil
+		},
+		TableStatsFunc: func(ctx context.Context) ([]*models.TableStorage, error) {
+			return []*models.TableStorage{{Table: "exercise_logs", RowCount: 10, TotalBytes: 2048}}, nil
+		},
+		LargestUsersFunc: func(ctx context.Context, limit int) ([]*models.UserStorage, error) {
+			gotLimit = limit
+			return []*models.UserStorage{{UserID: "user-123", RowCount: 10, Bytes: 1024}}, nil
+		},
+	}
+
+	service := NewAdminService(mockRepo)
+	report, err := service.StorageReport(context.Background(), 5)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.DatabaseBytes != 4096 {
+		t.Errorf("Expected database size 4096, got %d", report.DatabaseBytes)
+	}
+	if len(report.Tables) != 1 || len(report.LargestUsers) != 1 {
+		t.Errorf("Expected 1 table and 1 user, got %d and %d", len(report.Tables), len(report.LargestUsers))
+	}
+	if gotLimit != 5 {
+		t.Errorf("Expected limit 5, got %d", gotLimit)
+	}
+}
+
+func TestStorageReport_LimitOutOfRange(t *testing.T) {
+	var gotLimit int
+	mockRepo := &repositories.MockStorageRepository{
+		LargestUsersFunc: func(ctx context.Context, limit int) ([]*models.UserStorage, error) {
+			gotLimit = limit
+			return []*models.UserStorage{}, nil
+		},
+	}
+
+	service := NewAdminService(mockRepo)
+	for _, limit := range []int{0, -1, 1000} {
+		if _, err := service.StorageReport(context.Background(), limit); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if gotLimit != defaultLargestUsers {
+			t.Errorf("Expected limit %d for input %d, got %d", defaultLargestUsers, limit, gotLimit)
+		}
+	}
+}
+
+func TestStorageReport_RepositoryError(t *testing.T) {
+	mockRepo := &repositories.MockStorageRepository{
+		TableStatsFunc: func(ctx context.Context) ([]*models.TableStorage, error) {
+			return nil, errors.New("permission denied")
+		},
+	}
+
+	service := NewAdminService(mockRepo)
+	if _, err := service.StorageReport(context.Background(), 10); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
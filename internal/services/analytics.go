@@ -0,0 +1,290 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrInvalidAnalyticsRange = domainerr.New(domainerr.Validation, "from must be before to and the range at most 2 years")
+
+const (
+	// maxAnalyticsRange caps the range of a muscle volume or sleep request (about 105 weeks)
+	maxAnalyticsRange = 2 * 366 * 24 * time.Hour
+
+	// minCorrelationSessions is how many sessions a correlation needs to be reported
+	minCorrelationSessions = 5
+)
+
+// sleepBuckets group sessions by the minutes slept before them, below each bound
+var sleepBuckets = []struct {
+	label string
+	below int
+}{
+	{"<6h", 6 * 60},
+	{"6-7h", 7 * 60},
+	{"7-8h", 8 * 60},
+	{"8h+", math.MaxInt},
+}
+
+// AnalyticsService serves dashboard aggregates from materialized views, which the
+// scheduler refreshes; figures lag the history by up to the refresh interval, except for
+// daily summaries, which are read live
+type AnalyticsService struct {
+	repo      repositories.AnalyticsRepository
+	profiles  repositories.ProfileRepository
+	hydration *HydrationService
+	now       func() time.Time
+}
+
+// NewAnalyticsService creates a new analytics service; hydration supplies the water
+// figures of daily summaries
+func NewAnalyticsService(repo repositories.AnalyticsRepository, profiles repositories.ProfileRepository, hydration *HydrationService) *AnalyticsService {
+	return &AnalyticsService{repo: repo, profiles: profiles, hydration: hydration, now: time.Now}
+}
+
+// GetMuscleVolume returns the user's weekly volume per muscle group between the dates from
+// and to (exclusive), in weeks of their timezone. from is moved back to its Monday so its
+// whole week is included. A zero to is tomorrow, so today is included, and a zero from
+// twelve weeks before to.
+func (s *AnalyticsService) GetMuscleVolume(ctx context.Context, userID string, from, to time.Time) (*models.MuscleVolume, error) {
+	if to.IsZero() {
+		loc, err := userLocation(ctx, s.profiles, userID)
+		if err != nil {
+			return nil, err
+		}
+		to = localDate(s.now(), loc).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -12*7)
+	}
+	from, to = weekStart(from), to.UTC()
+	if !from.Before(to) || to.Sub(from) > maxAnalyticsRange {
+		return nil, ErrInvalidAnalyticsRange
+	}
+
+	weeks, err := s.repo.WeeklyMuscleVolume(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get muscle volume: %w", err)
+	}
+
+	return &models.MuscleVolume{
+		From:  from.Format("2006-01-02"),
+		To:    to.Format("2006-01-02"),
+		Weeks: weeks,
+	}, nil
+}
+
+// GetE1RMBests returns the user's best estimated one-rep max per exercise
+func (s *AnalyticsService) GetE1RMBests(ctx context.Context, userID string) ([]*models.E1RMBest, error) {
+	bests, err := s.repo.E1RMBests(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get e1RM bests: %w", err)
+	}
+
+	return bests, nil
+}
+
+// GetDailySummary returns what the user did on a day of their timezone: the sessions they
+// completed and their hydration progress. A zero day is today.
+func (s *AnalyticsService) GetDailySummary(ctx context.Context, userID string, day time.Time) (*models.DailySummary, error) {
+	day, err := pastDay(ctx, s.profiles, s.now(), userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	summary, err := s.repo.DailyTraining(ctx, userID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily training: %w", err)
+	}
+
+	hydration, err := s.hydration.GetProgress(ctx, userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Date = day.Format("2006-01-02")
+	summary.Hydration = hydration
+	return summary, nil
+}
+
+// GetSleepPerformance relates the user's sleep to the sessions they completed between the
+// dates from and to (exclusive) of their timezone. Each session is paired with the sleep
+// that ended in the 18 hours before it. Volume is compared with the average of sessions of
+// the same workout in the range, so heavy and light days don't skew the result. A zero to
+// is tomorrow, so today is included, and a zero from twelve weeks before to.
+func (s *AnalyticsService) GetSleepPerformance(ctx context.Context, userID string, from, to time.Time) (*models.SleepPerformance, error) {
+	if to.IsZero() {
+		loc, err := userLocation(ctx, s.profiles, userID)
+		if err != nil {
+			return nil, err
+		}
+		to = localDate(s.now(), loc).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -12*7)
+	}
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) || to.Sub(from) > maxAnalyticsRange {
+		return nil, ErrInvalidAnalyticsRange
+	}
+
+	sessions, err := s.repo.SleepSessions(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sleep sessions: %w", err)
+	}
+	setRelativeVolume(sessions)
+
+	performance := &models.SleepPerformance{
+		From:     from.Format("2006-01-02"),
+		To:       to.Format("2006-01-02"),
+		Sessions: []*models.SleepSession{},
+	}
+	var sleepVolume, volume, sleepRPE, rpe []float64
+	for _, ss := range sessions {
+		if ss.SleepMinutes == nil {
+			performance.SessionsWithoutSleep++
+			continue
+		}
+		performance.Sessions = append(performance.Sessions, ss)
+		if ss.VolumeKg > 0 {
+			sleepVolume = append(sleepVolume, float64(*ss.SleepMinutes))
+			volume = append(volume, ss.RelativeVolume)
+		}
+		if ss.RPE != nil {
+			sleepRPE = append(sleepRPE, float64(*ss.SleepMinutes))
+			rpe = append(rpe, *ss.RPE)
+		}
+	}
+
+	performance.Buckets = bucketSleep(performance.Sessions)
+	performance.SleepVolumeCorrelation = correlation(sleepVolume, volume)
+	performance.SleepRPECorrelation = correlation(sleepRPE, rpe)
+	return performance, nil
+}
+
+// setRelativeVolume sets each session's volume relative to the average of the sessions of
+// the same workout that moved any weight; ad-hoc sessions are compared with each other
+func setRelativeVolume(sessions []*models.SleepSession) {
+	type total struct {
+		volume   float64
+		sessions int
+	}
+	totals := make(map[string]*total)
+	key := func(ss *models.SleepSession) string {
+		if ss.WorkoutID == nil {
+			return ""
+		}
+		return *ss.WorkoutID
+	}
+
+	for _, ss := range sessions {
+		if ss.VolumeKg <= 0 {
+			continue
+		}
+		t, ok := totals[key(ss)]
+		if !ok {
+			t = &total{}
+			totals[key(ss)] = t
+		}
+		t.volume += ss.VolumeKg
+		t.sessions++
+	}
+
+	for _, ss := range sessions {
+		if t, ok := totals[key(ss)]; ok && ss.VolumeKg > 0 {
+			ss.RelativeVolume = round2(ss.VolumeKg / (t.volume / float64(t.sessions)))
+		}
+	}
+}
+
+// bucketSleep averages sessions by how long the user slept before them; every bucket is
+// listed, empty ones with zero sessions
+func bucketSleep(sessions []*models.SleepSession) []*models.SleepBucket {
+	buckets := make([]*models.SleepBucket, len(sleepBuckets))
+	volumes := make([]int, len(sleepBuckets))
+	rpes := make([][]float64, len(sleepBuckets))
+	for i, b := range sleepBuckets {
+		buckets[i] = &models.SleepBucket{Sleep: b.label}
+	}
+
+	for _, ss := range sessions {
+		i := 0
+		for *ss.SleepMinutes >= sleepBuckets[i].below {
+			i++
+		}
+		buckets[i].Sessions++
+		if ss.VolumeKg > 0 {
+			buckets[i].AvgRelativeVolume += ss.RelativeVolume
+			volumes[i]++
+		}
+		if ss.RPE != nil {
+			rpes[i] = append(rpes[i], *ss.RPE)
+		}
+	}
+
+	for i, b := range buckets {
+		if volumes[i] > 0 {
+			b.AvgRelativeVolume = round2(b.AvgRelativeVolume / float64(volumes[i]))
+		}
+		if len(rpes[i]) > 0 {
+			avg := round2(mean(rpes[i]))
+			b.AvgRPE = &avg
+		}
+	}
+	return buckets
+}
+
+// correlation returns the Pearson correlation of xs and ys, or nil with fewer than
+// minCorrelationSessions pairs or when either doesn't vary
+func correlation(xs, ys []float64) *float64 {
+	if len(xs) < minCorrelationSessions {
+		return nil
+	}
+
+	mx, my := mean(xs), mean(ys)
+	var cov, vx, vy float64
+	for i := range xs {
+		dx, dy := xs[i]-mx, ys[i]-my
+		cov += dx * dy
+		vx += dx * dx
+		vy += dy * dy
+	}
+	if vx == 0 || vy == 0 {
+		return nil
+	}
+
+	r := round2(cov / math.Sqrt(vx*vy))
+	return &r
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// RefreshViews recomputes the analytics views, unless another instance is already at it
+func (s *AnalyticsService) RefreshViews(ctx context.Context) error {
+	refreshed, err := s.repo.RefreshViews(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh analytics views: %w", err)
+	}
+	if !refreshed {
+		log.Printf("Analytics views are being refreshed by another instance, skipping")
+	}
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGetMuscleVolume_IncludesFirstWeek(t *testing.T) {
+	var gotFrom, gotTo time.Time
+	repo := &repositories.MockAnalyticsRepository{
+		WeeklyMuscleVolumeFunc: func(ctx context.Context, userID string, from, to time.Time) ([]*models.MuscleVolumeWeek, error) {
+			gotFrom, gotTo = from, to
+			return []*models.MuscleVolumeWeek{{Week: "2026-03-02", MuscleGroup: "chest", VolumeKg: 4200, Sets: 12}}, nil
+		},
+	}
+	service := NewAnalyticsService(repo, &repositories.MockProfileRepository{}, nil)
+
+	// Thursday March 5th belongs to the week of Monday March 2nd
+	from := time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	volume, err := service.GetMuscleVolume(context.Background(), "user-1", from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !gotFrom.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) || !gotTo.Equal(to) {
+		t.Errorf("Expected weeks from 2026-03-02, got %v to %v", gotFrom, gotTo)
+	}
+	if volume.From != "2026-03-02" || volume.To != "2026-04-01" || len(volume.Weeks) != 1 {
+		t.Errorf("Unexpected volume %+v", volume)
+	}
+}
+
+func TestGetMuscleVolume_InvalidRange(t *testing.T) {
+	service := NewAnalyticsService(&repositories.MockAnalyticsRepository{}, &repositories.MockProfileRepository{}, nil)
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+
+	for _, to := range []time.Time{from, from.AddDate(-1, 0, 0), from.AddDate(3, 0, 0)} {
+		if _, err := service.GetMuscleVolume(context.Background(), "user-1", from, to); !errors.Is(err, ErrInvalidAnalyticsRange) {
+			t.Errorf("to %v: expected ErrInvalidAnalyticsRange, got %v", to, err)
+		}
+	}
+}
+
+func TestRefreshViews_SkippedByLockIsNotAnError(t *testing.T) {
+	service := NewAnalyticsService(&repositories.MockAnalyticsRepository{
+		RefreshViewsFunc: func(ctx context.Context) (bool, error) { return false, nil },
+	}, &repositories.MockProfileRepository{}, nil)
+	if err := service.RefreshViews(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	service = NewAnalyticsService(&repositories.MockAnalyticsRepository{
+		RefreshViewsFunc: func(ctx context.Context) (bool, error) { return false, errors.New("connection lost") },
+	}, &repositories.MockProfileRepository{}, nil)
+	if err := service.RefreshViews(context.Background()); err == nil {
+		t.Error("Expected the refresh error")
+	}
+}
+
+func TestGetDailySummary_IncludesHydration(t *testing.T) {
+	day := time.Date(2026, 6, 17, 0, 0, 0, 0, time.UTC)
+	repo := &repositories.MockAnalyticsRepository{
+		DailyTrainingFunc: func(ctx context.Context, userID string, d time.Time) (*models.DailySummary, error) {
+			if !d.Equal(day) {
+				t.Errorf("Expected training of %v, got %v", day, d)
+			}
+			return &models.DailySummary{Sessions: 1, Minutes: 55, VolumeKg: 8400}, nil
+		},
+	}
+	hydration := NewHydrationService(&repositories.MockHydrationRepository{
+		DailyTotalFunc: func(ctx context.Context, userID string, d time.Time) (int, int, error) { return 1500, 3, nil },
+	}, &repositories.MockProfileRepository{})
+	service := NewAnalyticsService(repo, &repositories.MockProfileRepository{}, hydration)
+
+	summary, err := service.GetDailySummary(context.Background(), "user-1", day)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if summary.Date != "2026-06-17" || summary.Sessions != 1 || summary.VolumeKg != 8400 {
+		t.Errorf("Unexpected summary %+v", summary)
+	}
+	if summary.Hydration == nil || summary.Hydration.TotalMl != 1500 || summary.Hydration.RemainingMl != 500 {
+		t.Errorf("Expected the day's hydration progress, got %+v", summary.Hydration)
+	}
+}
+
+func TestGetSleepPerformance(t *testing.T) {
+	minutes := func(m int) *int { return &m }
+	rpe := func(r float64) *float64 { return &r }
+	push, pull := "workout-push", "workout-pull"
+
+	// Push days move twice the volume of pull days; within each, more sleep goes further
+	repo := &repositories.MockAnalyticsRepository{
+		SleepSessionsFunc: func(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepSession, error) {
+			return []*models.SleepSession{
+				{SessionID: "s1", WorkoutID: &push, SleepMinutes: minutes(330), VolumeKg: 8000, RPE: rpe(9)},
+				{SessionID: "s2", WorkoutID: &pull, SleepMinutes: minutes(350), VolumeKg: 4000, RPE: rpe(9)},
+				{SessionID: "s3", WorkoutID: &push, SleepMinutes: minutes(450), VolumeKg: 10000, RPE: rpe(8)},
+				{SessionID: "s4", WorkoutID: &pull, SleepMinutes: minutes(470), VolumeKg: 5000, RPE: rpe(7)},
+				{SessionID: "s5", WorkoutID: &push, SleepMinutes: minutes(510), VolumeKg: 12000, RPE: rpe(7)},
+				{SessionID: "s6", WorkoutID: &pull, SleepMinutes: nil, VolumeKg: 6000},
+			}, nil
+		},
+	}
+	service := NewAnalyticsService(repo, &repositories.MockProfileRepository{}, nil)
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	performance, err := service.GetSleepPerformance(context.Background(), "user-1", from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(performance.Sessions) != 5 || performance.SessionsWithoutSleep != 1 {
+		t.Fatalf("Expected 5 sessions with sleep and 1 without, got %d and %d", len(performance.Sessions), performance.SessionsWithoutSleep)
+	}
+	if got := performance.Sessions[0].RelativeVolume; got != 0.8 {
+		t.Errorf("Expected volume relative to the push average of 10000kg, got %v", got)
+	}
+	if r := performance.SleepVolumeCorrelation; r == nil || *r < 0.9 {
+		t.Errorf("Expected a strong positive sleep-volume correlation, got %v", r)
+	}
+	if r := performance.SleepRPECorrelation; r == nil || *r > -0.8 {
+		t.Errorf("Expected a strong negative sleep-RPE correlation, got %v", r)
+	}
+
+	want := map[string]int{"<6h": 2, "6-7h": 0, "7-8h": 2, "8h+": 1}
+	for _, b := range performance.Buckets {
+		if b.Sessions != want[b.Sleep] {
+			t.Errorf("Expected %d sessions after %s of sleep, got %d", want[b.Sleep], b.Sleep, b.Sessions)
+		}
+	}
+}
+
+func TestGetSleepPerformance_TooFewSessions(t *testing.T) {
+	minutes := 420
+	repo := &repositories.MockAnalyticsRepository{
+		SleepSessionsFunc: func(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepSession, error) {
+			return []*models.SleepSession{{SessionID: "s1", SleepMinutes: &minutes, VolumeKg: 5000}}, nil
+		},
+	}
+	service := NewAnalyticsService(repo, &repositories.MockProfileRepository{}, nil)
+
+	performance, err := service.GetSleepPerformance(context.Background(), "user-1", time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if performance.SleepVolumeCorrelation != nil || performance.SleepRPECorrelation != nil {
+		t.Errorf("Expected no correlations from a single session, got %v and %v", performance.SleepVolumeCorrelation, performance.SleepRPECorrelation)
+	}
+}
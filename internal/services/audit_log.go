@@ -0,0 +1,149 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrInvalidAuditFilter = domainerr.New(domainerr.Validation, "invalid audit log filter")
+
+const (
+	defaultAuditPage = 100
+	maxAuditPage     = 500
+)
+
+// auditIgnoredFields change with every write and would only clutter the diffs; secret and
+// token are credentials (webhook signing secrets, push tokens) and must not be stored
+var auditIgnoredFields = map[string]bool{"updated_at": true, "version": true, "secret": true, "token": true}
+
+// AuditLogService records writes made through the services and lets admins read them back.
+// A nil service records nothing, so services built without one (tests, tools) work alike.
+type AuditLogService struct {
+	repo repositories.AuditLogRepository
+}
+
+// NewAuditLogService creates a new audit log service
+func NewAuditLogService(repo repositories.AuditLogRepository) *AuditLogService {
+	return &AuditLogService{repo: repo}
+}
+
+// ListAuditLogs retrieves the most recent entries matching the filter, for admins
+func (s *AuditLogService) ListAuditLogs(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+	if filter.ActorID != "" {
+		if _, err := uuid.Parse(filter.ActorID); err != nil {
+			return nil, fmt.Errorf("%w: user_id must be a UUID", ErrInvalidAuditFilter)
+		}
+	}
+	if filter.From != nil && filter.Until != nil && !filter.Until.After(*filter.From) {
+		return nil, fmt.Errorf("%w: the range must end after it starts", ErrInvalidAuditFilter)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditPage
+	}
+	filter.Limit = min(filter.Limit, maxAuditPage)
+
+	entries, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit logs: %w", err)
+	}
+	return entries, nil
+}
+
+// audited decorates a service write with an audit log entry. It runs write and, once it
+// succeeds, records that actorID (empty for service tokens) performed action on the
+// resource, with the fields that differ between before and what write returned: nil before
+// for creations, nil result for deletions. An empty resourceID is taken from the result's
+// "id". Failing to record is logged rather than failing a write that already happened.
+func audited[T any](ctx context.Context, s *AuditLogService, actorID string, action string, resourceType string, resourceID string, before T, write func() (T, error)) (T, error) {
+	after, err := write()
+	if err != nil {
+		return after, err
+	}
+
+	s.recordWrite(ctx, actorID, action, resourceType, resourceID, before, after)
+	return after, nil
+}
+
+// recordWrite records a write that already happened, for writes audited outside of audited
+func (s *AuditLogService) recordWrite(ctx context.Context, actorID string, action string, resourceType string, resourceID string, before any, after any) {
+	if s == nil {
+		return
+	}
+	if err := s.record(ctx, actorID, action, resourceType, resourceID, before, after); err != nil {
+		log.Printf("Failed to record audit log for %s %s %s: %v", action, resourceType, resourceID, err)
+	}
+}
+
+// record stores an entry for a write that already happened
+func (s *AuditLogService) record(ctx context.Context, actorID string, action string, resourceType string, resourceID string, before any, after any) error {
+	beforeFields, err := auditFields(before)
+	if err != nil {
+		return err
+	}
+	afterFields, err := auditFields(after)
+	if err != nil {
+		return err
+	}
+	if resourceID == "" {
+		if err := json.Unmarshal(afterFields["id"], &resourceID); err != nil {
+			return fmt.Errorf("no resource ID: %w", err)
+		}
+	}
+
+	entry := &models.AuditLog{
+		Action:       action,
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		Changes:      auditChanges(beforeFields, afterFields),
+	}
+	if actorID != "" {
+		entry.ActorID = &actorID
+	}
+	return s.repo.Create(ctx, entry)
+}
+
+// auditFields renders a resource as its JSON fields; nil renders as no fields
+func auditFields(resource any) (map[string]json.RawMessage, error) {
+	data, err := json.Marshal(resource)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// auditChanges lists the fields whose JSON differs, a missing field counting as null
+func auditChanges(before, after map[string]json.RawMessage) map[string]*models.AuditChange {
+	null := json.RawMessage("null")
+	value := func(fields map[string]json.RawMessage, name string) json.RawMessage {
+		if v, ok := fields[name]; ok {
+			return v
+		}
+		return null
+	}
+
+	changes := make(map[string]*models.AuditChange)
+	for _, fields := range []map[string]json.RawMessage{before, after} {
+		for name := range fields {
+			if auditIgnoredFields[name] || changes[name] != nil {
+				continue
+			}
+			b, a := value(before, name), value(after, name)
+			if !bytes.Equal(b, a) {
+				changes[name] = &models.AuditChange{Before: b, After: a}
+			}
+		}
+	}
+	return changes
+}
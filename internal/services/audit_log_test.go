@@ -0,0 +1,141 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func recordingAudit() (*AuditLogService, *[]*models.AuditLog) {
+	var entries []*models.AuditLog
+	repo := &repositories.MockAuditLogRepository{
+		CreateFunc: func(ctx context.Context, entry *models.AuditLog) error {
+			entries = append(entries, entry)
+			return nil
+		},
+	}
+	return NewAuditLogService(repo), &entries
+}
+
+func TestAudited_CreateTakesIDFromResult(t *testing.T) {
+	audit, entries := recordingAudit()
+
+	_, err := audited(context.Background(), audit, "user-1", models.AuditActionCreate, models.AuditResourceEquipment, "", nil, func() (*models.Equipment, error) {
+		return &models.Equipment{ID: "eq-1", Name: "Barbell", UserID: "user-1", Version: 1}, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(*entries))
+	}
+	entry := (*entries)[0]
+	if entry.ResourceID != "eq-1" || entry.ActorID == nil || *entry.ActorID != "user-1" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	name := entry.Changes["name"]
+	if name == nil || string(name.Before) != "null" || string(name.After) != `"Barbell"` {
+		t.Errorf("Expected name created as Barbell, got %+v", name)
+	}
+	if _, ok := entry.Changes["version"]; ok {
+		t.Error("Expected version to be left out of the changes")
+	}
+}
+
+func TestAudited_UpdateRecordsChangedFieldsOnly(t *testing.T) {
+	audit, entries := recordingAudit()
+	mockRepo := &repositories.MockEquipmentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
+			return &models.Equipment{ID: id, Name: "Barbell", Description: "Olympic", UserID: "user-1", Version: 2}, nil
+		},
+	}
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), audit)
+
+	_, err := service.UpdateEquipment(context.Background(), "eq-1", "user-1", &models.UpdateEquipmentRequest{Name: "EZ bar", Description: "Olympic", Version: 2})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(*entries))
+	}
+	entry := (*entries)[0]
+	if entry.Action != models.AuditActionUpdate || entry.ResourceID != "eq-1" {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if len(entry.Changes) != 1 {
+		t.Fatalf("Expected only the name to change, got %v", entry.Changes)
+	}
+	if name := entry.Changes["name"]; string(name.Before) != `"Barbell"` || string(name.After) != `"EZ bar"` {
+		t.Errorf("Expected Barbell -> EZ bar, got %s -> %s", name.Before, name.After)
+	}
+}
+
+func TestAudited_DeleteAndFailedWrites(t *testing.T) {
+	audit, entries := recordingAudit()
+	before := &models.OrganizationMember{OrganizationID: "org-1", UserID: "user-2", Role: models.OrgRoleTrainer}
+
+	_, err := audited(context.Background(), audit, "", models.AuditActionDelete, models.AuditResourceOrganizationMember, "org-1/user-2", before, func() (*models.OrganizationMember, error) {
+		return nil, nil
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	entry := (*entries)[0]
+	if entry.ActorID != nil {
+		t.Errorf("Expected no actor for a service token, got %v", *entry.ActorID)
+	}
+	if role := entry.Changes["role"]; role == nil || string(role.After) != "null" {
+		t.Errorf("Expected role deleted, got %+v", role)
+	}
+
+	failure := errors.New("boom")
+	_, err = audited(context.Background(), audit, "user-1", models.AuditActionDelete, models.AuditResourceOrganizationMember, "org-1/user-3", before, func() (*models.OrganizationMember, error) {
+		return nil, failure
+	})
+	if !errors.Is(err, failure) {
+		t.Errorf("Expected the write's error, got %v", err)
+	}
+	if len(*entries) != 1 {
+		t.Errorf("Expected failed writes not to be recorded, got %d entries", len(*entries))
+	}
+
+	// Services built without an audit log still write
+	if _, err := audited(context.Background(), nil, "user-1", models.AuditActionDelete, models.AuditResourceEquipment, "eq-1", before, func() (*models.OrganizationMember, error) {
+		return nil, nil
+	}); err != nil {
+		t.Errorf("Expected no error without an audit log, got %v", err)
+	}
+}
+
+func TestListAuditLogs(t *testing.T) {
+	var got models.AuditLogFilter
+	service := NewAuditLogService(&repositories.MockAuditLogRepository{
+		ListFunc: func(ctx context.Context, filter models.AuditLogFilter) ([]*models.AuditLog, error) {
+			got = filter
+			return []*models.AuditLog{}, nil
+		},
+	})
+
+	if _, err := service.ListAuditLogs(context.Background(), models.AuditLogFilter{Limit: 1000}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if got.Limit != maxAuditPage {
+		t.Errorf("Expected limit capped at %d, got %d", maxAuditPage, got.Limit)
+	}
+
+	if _, err := service.ListAuditLogs(context.Background(), models.AuditLogFilter{ActorID: "not-a-uuid"}); !errors.Is(err, ErrInvalidAuditFilter) {
+		t.Errorf("Expected ErrInvalidAuditFilter for a bad user ID, got %v", err)
+	}
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	until := from
+	if _, err := service.ListAuditLogs(context.Background(), models.AuditLogFilter{From: &from, Until: &until}); !errors.Is(err, ErrInvalidAuditFilter) {
+		t.Errorf("Expected ErrInvalidAuditFilter for an empty range, got %v", err)
+	}
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+const (
+	// exerciseCacheScope holds the exercise library, public and private
+	exerciseCacheScope = "exercises"
+
+	// exerciseCacheTTL bounds staleness should an invalidation be missed
+	exerciseCacheTTL = time.Hour
+
+	// analyticsCacheTTL is shorter, as analytics also depend on the current date
+	analyticsCacheTTL = 10 * time.Minute
+
+	// cacheRetryDelay is how long to wait before listening again after the notification
+	// connection fails
+	cacheRetryDelay = 5 * time.Second
+)
+
+// analyticsCacheScope holds a user's trends and summaries
+func analyticsCacheScope(userID string) string {
+	return "analytics:" + userID
+}
+
+// CacheService drops cached reads when the data behind them changes. Database triggers
+// announce every write, whichever instance or client made it, so each instance's cache
+// and a shared Redis are invalidated alike.
+type CacheService struct {
+	repo  repositories.CacheRepository
+	cache *cache.Cache
+}
+
+// NewCacheService creates a new cache service; a nil cache disables caching
+func NewCacheService(repo repositories.CacheRepository, c *cache.Cache) *CacheService {
+	return &CacheService{repo: repo, cache: c}
+}
+
+// Start invalidates scopes as writes are announced, in the background until ctx is
+// cancelled, listening again after connection failures. Writes made while the listener is
+// down are only caught by the TTLs.
+func (s *CacheService) Start(ctx context.Context) {
+	if s.cache == nil {
+		return
+	}
+
+	go func() {
+		for {
+			err := s.repo.Listen(ctx, func(scope string) {
+				s.cache.Invalidate(ctx, scope)
+			})
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Cache invalidation listener failed: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(cacheRetryDelay):
+			}
+		}
+	}()
+}
+
+// Stats returns the hits and misses of this instance's cache per namespace
+func (s *CacheService) Stats() []*models.CacheStats {
+	return s.cache.Stats()
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestListExercises_CachedUntilInvalidated(t *testing.T) {
+	loads := 0
+	mockRepo := &repositories.MockExerciseRepository{
+		FindPublicFunc: func(ctx context.Context) ([]*models.Exercise, error) {
+			loads++
+			return []*models.Exercise{{ID: "ex-1", Name: "Squat", IsPublic: true}}, nil
+		},
+	}
+	readCache := cache.New(cache.NewMemory(100), "test:")
+	policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{})
+	service := NewExerciseService(mockRepo, &repositories.MockInjuryRepository{}, policy, readCache)
+
+	// The listener announces one write, as the exercises trigger would
+	written := make(chan struct{})
+	invalidated := make(chan struct{})
+	cacheService := NewCacheService(&repositories.MockCacheRepository{
+		ListenFunc: func(ctx context.Context, handle func(scope string)) error {
+			<-written
+			handle(exerciseCacheScope)
+			close(invalidated)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}, readCache)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cacheService.Start(ctx)
+
+	for range 2 {
+		if _, err := service.ListExercises(ctx, "", true, false); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("Expected the second list to be cached, got %d loads", loads)
+	}
+
+	close(written)
+	select {
+	case <-invalidated:
+	case <-time.After(time.Second):
+		t.Fatal("Expected the write to be announced")
+	}
+	if _, err := service.ListExercises(ctx, "", true, false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if loads != 2 {
+		t.Errorf("Expected the list to be read again after the write, got %d loads", loads)
+	}
+
+	stats := cacheService.Stats()
+	if len(stats) != 1 || stats[0].Hits != 1 || stats[0].Misses != 2 || stats[0].Invalidations != 1 {
+		t.Errorf("Expected 1 hit, 2 misses and 1 invalidation, got %+v", stats)
+	}
+}
+
+func TestGetTrend_CachedPerUser(t *testing.T) {
+	var users []string
+	repo := &repositories.MockTrendRepository{
+		PointsFunc: func(ctx context.Context, userID string, metric string, granularity string, from, to time.Time) ([]*models.TrendPoint, error) {
+			users = append(users, userID)
+			return []*models.TrendPoint{}, nil
+		},
+	}
+	service := NewTrendService(repo, &repositories.MockProfileRepository{}, cache.New(cache.NewMemory(100), "test:"))
+
+	from := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 3, 0)
+	for _, userID := range []string{"user-1", "user-1", "user-2"} {
+		if _, err := service.GetTrend(context.Background(), userID, "sessions", from, to); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if len(users) != 2 || users[0] != "user-1" || users[1] != "user-2" {
+		t.Errorf("Expected one read per user, got %v", users)
+	}
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrChallengeNotFound     = domainerr.New(domainerr.NotFound, "challenge not found")
+	ErrNotChallengeMember    = domainerr.New(domainerr.Forbidden, "user has not joined this challenge")
+	ErrChallengeEnded        = domainerr.New(domainerr.Conflict, "challenge has already ended")
+	ErrInvalidChallengeDates = domainerr.New(domainerr.Validation, "challenge must end on or after its start and last at most a year")
+	ErrInvalidChallengeDay   = domainerr.New(domainerr.Validation, "day must fall within the challenge and not be in the future")
+	ErrInvalidChallengeGoal  = domainerr.New(domainerr.Validation, "metric and goal_value go together and rule out a daily_target")
+	ErrChallengeTracksLogs   = domainerr.New(domainerr.Conflict, "progress on this challenge is computed from workout logs")
+)
+
+const (
+	maxChallengeDays = 366
+
+	defaultLeaderboardSize = 50
+)
+
+// ChallengeService handles challenges, participation and daily completion tracking
+// Challenges are visible to every signed-in user; progress and leaderboards are
+// only visible to participants. Progress toward a challenge's goal is computed from the
+// participants' logs, and database triggers mark them as having completed it and notify
+// them, as for challenges completed day by day.
+type ChallengeService struct {
+	repo     repositories.ChallengeRepository
+	profiles repositories.ProfileRepository
+	tx       repositories.UnitOfWork
+	now      func() time.Time
+}
+
+// NewChallengeService creates a new challenge service
+func NewChallengeService(repo repositories.ChallengeRepository, profiles repositories.ProfileRepository, tx repositories.UnitOfWork) *ChallengeService {
+	return &ChallengeService{repo: repo, profiles: profiles, tx: tx, now: time.Now}
+}
+
+// today returns the user's current date, so a day checked off late in the evening counts
+// on the day the user is living
+func (s *ChallengeService) today(ctx context.Context, userID string) (time.Time, error) {
+	loc, err := userLocation(ctx, s.profiles, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return localDate(s.now(), loc), nil
+}
+
+// CreateChallenge creates a challenge hosted by the requesting user
+func (s *ChallengeService) CreateChallenge(ctx context.Context, userID string, req *models.CreateChallengeRequest) (*models.Challenge, error) {
+	startsOn, err := time.Parse(models.ChallengeDateLayout, req.StartsOn)
+	if err != nil {
+		return nil, ErrInvalidChallengeDates
+	}
+	endsOn, err := time.Parse(models.ChallengeDateLayout, req.EndsOn)
+	if err != nil {
+		return nil, ErrInvalidChallengeDates
+	}
+
+	challenge := &models.Challenge{
+		Name:        normalizeName(req.Name),
+		Description: normalizeText(req.Description),
+		DailyTarget: req.DailyTarget,
+		Unit:        req.Unit,
+		Metric:      req.Metric,
+		GoalValue:   req.GoalValue,
+		StartsOn:    startsOn,
+		EndsOn:      endsOn,
+		CreatedBy:   userID,
+	}
+	if challenge.Name == "" {
+		return nil, ErrBlankName
+	}
+	if endsOn.Before(startsOn) || challenge.Days() > maxChallengeDays {
+		return nil, ErrInvalidChallengeDates
+	}
+	if (req.Metric == nil) != (req.GoalValue == nil) || (req.Metric != nil && req.DailyTarget != nil) {
+		return nil, ErrInvalidChallengeGoal
+	}
+
+	if err := s.repo.Create(ctx, challenge); err != nil {
+		return nil, fmt.Errorf("failed to create challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// ListChallenges retrieves challenges that are running or upcoming for the user
+func (s *ChallengeService) ListChallenges(ctx context.Context, userID string) ([]*models.Challenge, error) {
+	today, err := s.today(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	challenges, err := s.repo.FindCurrent(ctx, today)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list challenges: %w", err)
+	}
+
+	return challenges, nil
+}
+
+// GetChallenge retrieves a single challenge
+func (s *ChallengeService) GetChallenge(ctx context.Context, id string) (*models.Challenge, error) {
+	challenge, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrChallengeNotFound
+		}
+		return nil, fmt.Errorf("failed to get challenge: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// JoinChallenge adds the user to a challenge that has not ended yet
+// Joining a challenge twice is a no-op that returns the existing participation.
+func (s *ChallengeService) JoinChallenge(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+	challenge, err := s.GetChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := s.today(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.EndsOn.Before(today) {
+		return nil, ErrChallengeEnded
+	}
+
+	participant := &models.ChallengeParticipant{
+		ChallengeID: challenge.ID,
+		UserID:      userID,
+	}
+	if err := s.repo.AddParticipant(ctx, participant); err != nil {
+		return nil, fmt.Errorf("failed to join challenge: %w", err)
+	}
+
+	return participant, nil
+}
+
+// LeaveChallenge removes the user from a challenge, discarding their progress
+func (s *ChallengeService) LeaveChallenge(ctx context.Context, challengeID string, userID string) error {
+	if err := s.repo.RemoveParticipant(ctx, challengeID, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotChallengeMember
+		}
+		return fmt.Errorf("failed to leave challenge: %w", err)
+	}
+
+	return nil
+}
+
+// CompleteDay checks off a day of the challenge for a participant.
+// Days default to today and must fall within the challenge without being in the future.
+// Challenges with a goal can't be checked off; their progress comes from logs.
+// Once every day is checked off the participant is marked as having completed the challenge,
+// in the same transaction as the completion.
+func (s *ChallengeService) CompleteDay(ctx context.Context, challengeID string, userID string, req *models.CompleteChallengeDayRequest) (*models.ChallengeCompletion, error) {
+	challenge, err := s.GetChallenge(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.Metric != nil {
+		return nil, ErrChallengeTracksLogs
+	}
+
+	participant, err := s.findParticipant(ctx, challengeID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	today, err := s.today(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	day := today
+	if req.Day != "" {
+		day, err = time.Parse(models.ChallengeDateLayout, req.Day)
+		if err != nil {
+			return nil, ErrInvalidChallengeDay
+		}
+	}
+	if day.Before(challenge.StartsOn) || day.After(challenge.EndsOn) || day.After(today) {
+		return nil, ErrInvalidChallengeDay
+	}
+
+	completion := &models.ChallengeCompletion{
+		ChallengeID: challengeID,
+		UserID:      userID,
+		Day:         day,
+		Value:       req.Value,
+	}
+	err = s.tx.Do(ctx, func(repos *repositories.Repositories) error {
+		if err := repos.Challenges.UpsertCompletion(ctx, completion); err != nil {
+			return fmt.Errorf("failed to record completion: %w", err)
+		}
+		if participant.CompletedAt == nil {
+			return s.checkCompleted(ctx, repos.Challenges, challenge, userID)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return completion, nil
+}
+
+// checkCompleted marks the participant as finished once every day is checked off, which
+// sends them a challenge.completed notification
+func (s *ChallengeService) checkCompleted(ctx context.Context, repo repositories.ChallengeRepository, challenge *models.Challenge, userID string) error {
+	completed, err := repo.CountCompletions(ctx, challenge.ID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to count completions: %w", err)
+	}
+
+	if completed < challenge.Days() {
+		return nil
+	}
+
+	if err := repo.MarkCompleted(ctx, challenge.ID, userID, s.now()); err != nil {
+		return fmt.Errorf("failed to mark challenge completed: %w", err)
+	}
+	log.Printf("Challenge completed: challenge=%s user=%s", challenge.ID, userID)
+
+	return nil
+}
+
+// Leaderboard ranks the participants of a challenge; only participants may view it
+func (s *ChallengeService) Leaderboard(ctx context.Context, challengeID string, userID string) ([]*models.ChallengeStanding, error) {
+	if _, err := s.GetChallenge(ctx, challengeID); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.findParticipant(ctx, challengeID, userID); err != nil {
+		return nil, err
+	}
+
+	standings, err := s.repo.Leaderboard(ctx, challengeID, defaultLeaderboardSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	return standings, nil
+}
+
+func (s *ChallengeService) findParticipant(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+	participant, err := s.repo.FindParticipant(ctx, challengeID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotChallengeMember
+		}
+		return nil, fmt.Errorf("failed to get participant: %w", err)
+	}
+
+	return participant, nil
+}
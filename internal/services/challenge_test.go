@@ -0,0 +1,253 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestChallenge() *models.Challenge {
+	return &models.Challenge{
+		ID:       "challenge-1",
+		Name:     "30-day squat challenge",
+		StartsOn: time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC),
+		EndsOn:   time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func newTestChallengeService(repo repositories.ChallengeRepository, now time.Time) *ChallengeService {
+	service := NewChallengeService(repo, &repositories.MockProfileRepository{}, &repositories.MockUnitOfWork{
+		Repositories: &repositories.Repositories{Challenges: repo},
+	})
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestCreateChallenge_InvalidDates(t *testing.T) {
+	service := newTestChallengeService(&repositories.MockChallengeRepository{}, time.Now())
+
+	req := &models.CreateChallengeRequest{Name: "Backwards", StartsOn: "2026-06-30", EndsOn: "2026-06-01"}
+	_, err := service.CreateChallenge(context.Background(), "user-123", req)
+
+	if !errors.Is(err, ErrInvalidChallengeDates) {
+		t.Errorf("Expected ErrInvalidChallengeDates, got %v", err)
+	}
+}
+
+func TestJoinChallenge_Ended(t *testing.T) {
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+	}
+
+	service := newTestChallengeService(mockRepo, time.Date(2026, 7, 1, 9, 0, 0, 0, time.UTC))
+
+	_, err := service.JoinChallenge(context.Background(), "challenge-1", "user-123")
+
+	if !errors.Is(err, ErrChallengeEnded) {
+		t.Errorf("Expected ErrChallengeEnded, got %v", err)
+	}
+}
+
+func TestCompleteDay_NotParticipant(t *testing.T) {
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+	}
+
+	service := newTestChallengeService(mockRepo, time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC))
+
+	_, err := service.CompleteDay(context.Background(), "challenge-1", "user-123", &models.CompleteChallengeDayRequest{})
+
+	if !errors.Is(err, ErrNotChallengeMember) {
+		t.Errorf("Expected ErrNotChallengeMember, got %v", err)
+	}
+}
+
+func TestCompleteDay_InvalidDay(t *testing.T) {
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+		FindParticipantFunc: func(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+			return &models.ChallengeParticipant{ChallengeID: challengeID, UserID: userID}, nil
+		},
+	}
+
+	service := newTestChallengeService(mockRepo, time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC))
+
+	for _, day := range []string{"2026-05-31", "2026-06-11"} {
+		_, err := service.CompleteDay(context.Background(), "challenge-1", "user-123", &models.CompleteChallengeDayRequest{Day: day})
+		if !errors.Is(err, ErrInvalidChallengeDay) {
+			t.Errorf("Expected ErrInvalidChallengeDay for %s, got %v", day, err)
+		}
+	}
+}
+
+func TestCompleteDay_MarksChallengeCompleted(t *testing.T) {
+	var recordedDay time.Time
+	markedCompleted := false
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+		FindParticipantFunc: func(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+			return &models.ChallengeParticipant{ChallengeID: challengeID, UserID: userID}, nil
+		},
+		UpsertCompletionFunc: func(ctx context.Context, completion *models.ChallengeCompletion) error {
+			recordedDay = completion.Day
+			return nil
+		},
+		CountCompletionsFunc: func(ctx context.Context, challengeID string, userID string) (int, error) {
+			return 30, nil
+		},
+		MarkCompletedFunc: func(ctx context.Context, challengeID string, userID string, at time.Time) error {
+			markedCompleted = true
+			return nil
+		},
+	}
+
+	service := newTestChallengeService(mockRepo, time.Date(2026, 6, 30, 21, 0, 0, 0, time.UTC))
+
+	_, err := service.CompleteDay(context.Background(), "challenge-1", "user-123", &models.CompleteChallengeDayRequest{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !recordedDay.Equal(time.Date(2026, 6, 30, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected completion for today, got %v", recordedDay)
+	}
+	if !markedCompleted {
+		t.Error("Expected participant to be marked as completed after the last day")
+	}
+}
+
+func TestCompleteDay_WritesInTransaction(t *testing.T) {
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+		FindParticipantFunc: func(ctx context.Context, challengeID string, userID string) (*models.ChallengeParticipant, error) {
+			return &models.ChallengeParticipant{ChallengeID: challengeID, UserID: userID}, nil
+		},
+		UpsertCompletionFunc: func(ctx context.Context, completion *models.ChallengeCompletion) error {
+			t.Error("Expected the completion to be written in the transaction")
+			return nil
+		},
+	}
+	markErr := errors.New("connection reset")
+	txRepo := &repositories.MockChallengeRepository{
+		CountCompletionsFunc: func(ctx context.Context, challengeID string, userID string) (int, error) {
+			return 30, nil
+		},
+		MarkCompletedFunc: func(ctx context.Context, challengeID string, userID string, at time.Time) error {
+			return markErr
+		},
+	}
+
+	service := NewChallengeService(mockRepo, &repositories.MockProfileRepository{}, &repositories.MockUnitOfWork{
+		Repositories: &repositories.Repositories{Challenges: txRepo},
+	})
+	service.now = func() time.Time { return time.Date(2026, 6, 30, 21, 0, 0, 0, time.UTC) }
+
+	_, err := service.CompleteDay(context.Background(), "challenge-1", "user-123", &models.CompleteChallengeDayRequest{})
+
+	// The error rolls the completion back with the failed update
+	if !errors.Is(err, markErr) {
+		t.Errorf("Expected the update's error, got %v", err)
+	}
+}
+
+func TestLeaderboard_RequiresParticipation(t *testing.T) {
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+	}
+
+	service := newTestChallengeService(mockRepo, time.Now())
+
+	_, err := service.Leaderboard(context.Background(), "challenge-1", "user-123")
+
+	if !errors.Is(err, ErrNotChallengeMember) {
+		t.Errorf("Expected ErrNotChallengeMember, got %v", err)
+	}
+}
+
+func TestJoinChallenge_LastDayInUserTimezone(t *testing.T) {
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			return newTestChallenge(), nil
+		},
+	}
+	profiles := &repositories.MockProfileRepository{
+		FindProfileFunc: func(ctx context.Context, userID string) (*models.Profile, error) {
+			return &models.Profile{UserID: userID, Timezone: "America/New_York"}, nil
+		},
+	}
+
+	// 02:00 UTC on July 1st is still June 30th in New York
+	service := NewChallengeService(mockRepo, profiles, &repositories.MockUnitOfWork{})
+	service.now = func() time.Time { return time.Date(2026, 7, 1, 2, 0, 0, 0, time.UTC) }
+
+	if _, err := service.JoinChallenge(context.Background(), "challenge-1", "user-123"); err != nil {
+		t.Errorf("Expected the challenge to still be running, got %v", err)
+	}
+}
+
+func TestCreateChallenge_Goal(t *testing.T) {
+	metric := models.ChallengeMetricVolume
+	goal := 100000.0
+	daily := 100
+	tests := []struct {
+		name    string
+		req     *models.CreateChallengeRequest
+		wantErr error
+	}{
+		{"volume goal", &models.CreateChallengeRequest{Metric: &metric, GoalValue: &goal}, nil},
+		{"metric without a goal", &models.CreateChallengeRequest{Metric: &metric}, ErrInvalidChallengeGoal},
+		{"goal without a metric", &models.CreateChallengeRequest{GoalValue: &goal}, ErrInvalidChallengeGoal},
+		{"goal and daily target", &models.CreateChallengeRequest{Metric: &metric, GoalValue: &goal, DailyTarget: &daily}, ErrInvalidChallengeGoal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestChallengeService(&repositories.MockChallengeRepository{}, time.Now())
+			tt.req.Name, tt.req.StartsOn, tt.req.EndsOn = "March volume", "2026-03-01", "2026-03-31"
+
+			challenge, err := service.CreateChallenge(context.Background(), "user-123", tt.req)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (*challenge.Metric != metric || *challenge.GoalValue != goal) {
+				t.Errorf("Expected the goal stored, got %+v", challenge)
+			}
+		})
+	}
+}
+
+func TestCompleteDay_GoalChallenge(t *testing.T) {
+	metric := models.ChallengeMetricSessions
+	goal := 12.0
+	mockRepo := &repositories.MockChallengeRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Challenge, error) {
+			challenge := newTestChallenge()
+			challenge.Metric, challenge.GoalValue = &metric, &goal
+			return challenge, nil
+		},
+	}
+	service := newTestChallengeService(mockRepo, time.Date(2026, 6, 10, 9, 0, 0, 0, time.UTC))
+
+	_, err := service.CompleteDay(context.Background(), "challenge-1", "user-123", &models.CompleteChallengeDayRequest{})
+
+	if !errors.Is(err, ErrChallengeTracksLogs) {
+		t.Errorf("Expected ErrChallengeTracksLogs, got %v", err)
+	}
+}
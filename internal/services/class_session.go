@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrClassNotFound         = domainerr.New(domainerr.NotFound, "class not found")
+	ErrClassInPast           = domainerr.New(domainerr.Validation, "classes must start in the future")
+	ErrClassWorkoutNotShared = domainerr.New(domainerr.Unprocessable, "class workouts must be shared in the organization")
+	ErrClassFull             = domainerr.New(domainerr.Conflict, "class is full")
+	ErrClassOver             = domainerr.New(domainerr.Conflict, "class has already taken place")
+)
+
+// classJoinWindow is how long after its start a class is still listed and can be joined,
+// for members who arrive late
+const classJoinWindow = 24 * time.Hour
+
+// ClassSessionService handles group workouts organizations' trainers run for their members
+type ClassSessionService struct {
+	repo          repositories.ClassSessionRepository
+	workouts      repositories.WorkoutRepository
+	trainingMaxes *TrainingMaxService
+	policy        AccessPolicy
+	now           func() time.Time
+}
+
+// NewClassSessionService creates a new class session service
+func NewClassSessionService(repo repositories.ClassSessionRepository, workouts repositories.WorkoutRepository, trainingMaxes *TrainingMaxService, policy AccessPolicy) *ClassSessionService {
+	return &ClassSessionService{repo: repo, workouts: workouts, trainingMaxes: trainingMaxes, policy: policy, now: time.Now}
+}
+
+// CreateClass schedules a class of a workout shared in the organization (owners and
+// trainers only); the creator runs it
+func (s *ClassSessionService) CreateClass(ctx context.Context, userID string, orgID string, req *models.CreateClassSessionRequest) (*models.ClassSession, error) {
+	ok, err := s.policy.CanWriteOrg(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	name := normalizeName(req.Name)
+	if name == "" {
+		return nil, ErrBlankName
+	}
+	if !req.StartsAt.After(s.now()) {
+		return nil, ErrClassInPast
+	}
+
+	workout, err := s.workouts.FindByID(ctx, req.WorkoutID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to get workout: %w", err)
+	}
+	if err != nil || workout.OrganizationID == nil || *workout.OrganizationID != orgID {
+		return nil, ErrClassWorkoutNotShared
+	}
+
+	class := &models.ClassSession{
+		OrganizationID: orgID,
+		TrainerID:      userID,
+		WorkoutID:      workout.ID,
+		Name:           name,
+		StartsAt:       req.StartsAt,
+		Capacity:       req.Capacity,
+	}
+	if err := s.repo.Create(ctx, class); err != nil {
+		return nil, fmt.Errorf("failed to create class: %w", err)
+	}
+
+	return class, nil
+}
+
+// ListClasses retrieves an organization's upcoming classes, and those that started within
+// the join window (any member)
+func (s *ClassSessionService) ListClasses(ctx context.Context, userID string, orgID string) ([]*models.ClassSession, error) {
+	ok, err := s.policy.CanReadOrg(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	classes, err := s.repo.ListByOrganization(ctx, orgID, userID, s.now().Add(-classJoinWindow))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list classes: %w", err)
+	}
+
+	return classes, nil
+}
+
+// GetClass retrieves a class of an organization the user belongs to
+func (s *ClassSessionService) GetClass(ctx context.Context, id string, userID string) (*models.ClassSession, error) {
+	class, err := s.repo.FindByID(ctx, id, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClassNotFound
+		}
+		return nil, fmt.Errorf("failed to get class: %w", err)
+	}
+
+	// Other organizations' classes are indistinguishable from missing ones
+	ok, err := s.policy.CanReadOrg(ctx, userID, class.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrClassNotFound
+	}
+
+	return class, nil
+}
+
+// JoinClass signs a member up for a class, giving them their own planned session of the
+// class workout pre-filled with its exercises, loads worked out from the member's own
+// training maxes. Joining again returns the same session.
+func (s *ClassSessionService) JoinClass(ctx context.Context, id string, userID string) (*models.ClassParticipant, error) {
+	class, err := s.GetClass(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !class.Joined && s.now().After(class.StartsAt.Add(classJoinWindow)) {
+		return nil, ErrClassOver
+	}
+
+	var loads map[string]float64
+	if !class.Joined {
+		loads, err = s.resolveLoads(ctx, class.WorkoutID, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	participant, err := s.repo.Join(ctx, id, userID, loads)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrClassFull
+		}
+		return nil, fmt.Errorf("failed to join class: %w", err)
+	}
+
+	return participant, nil
+}
+
+// resolveLoads works out the member's weights for the class workout's entries prescribed as
+// a percentage of a training max, by workout exercise ID. Entries naming a training max the
+// member hasn't set are left without a load.
+func (s *ClassSessionService) resolveLoads(ctx context.Context, workoutID string, userID string) (map[string]float64, error) {
+	workout, err := s.workouts.FindByID(ctx, workoutID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workout: %w", err)
+	}
+
+	loads := make(map[string]float64)
+	for _, we := range workout.Exercises {
+		if we.TrainingMaxName == nil || we.IntensityPercentage == nil {
+			continue
+		}
+		load, err := s.trainingMaxes.ResolveLoad(ctx, userID, *we.TrainingMaxName, *we.IntensityPercentage)
+		switch {
+		case isMissingTrainingMax(err):
+			continue
+		case err != nil:
+			return nil, err
+		}
+		loads[we.ID] = load
+	}
+	return loads, nil
+}
+
+// GetAttendance retrieves who joined a class and how far their sessions got, for the
+// class's trainer and the organization's owners and trainers
+func (s *ClassSessionService) GetAttendance(ctx context.Context, id string, userID string) (*models.ClassAttendance, error) {
+	class, err := s.GetClass(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if class.TrainerID != userID {
+		ok, err := s.policy.CanWriteOrg(ctx, userID, class.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrUnauthorized
+		}
+	}
+
+	participants, err := s.repo.Participants(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list class participants: %w", err)
+	}
+
+	attendance := &models.ClassAttendance{
+		ClassSessionID: id,
+		Joined:         len(participants),
+		Participants:   participants,
+	}
+	for _, p := range participants {
+		switch p.Status {
+		case "completed":
+			attendance.Completed++
+			attendance.Attended++
+		case "in_progress", "paused":
+			attendance.Attended++
+		}
+	}
+
+	return attendance, nil
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var classRoles = map[string]string{
+	"owner-1":   models.OrgRoleOwner,
+	"trainer-1": models.OrgRoleTrainer,
+	"trainer-2": models.OrgRoleTrainer,
+	"member-1":  models.OrgRoleMember,
+}
+
+func newTestClassSessionService(repo repositories.ClassSessionRepository, workouts repositories.WorkoutRepository, now time.Time) *ClassSessionService {
+	policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(classRoles),
+	})
+	trainingMaxes := NewTrainingMaxService(&repositories.MockTrainingMaxRepository{
+		FindByNameFunc: func(ctx context.Context, userID string, name string) (*models.TrainingMax, error) {
+			if userID == "member-1" && name == "squat" {
+				return &models.TrainingMax{UserID: userID, Name: name, WeightKg: 140}, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+	})
+	service := NewClassSessionService(repo, workouts, trainingMaxes, policy)
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func orgWorkouts(orgID string) *repositories.MockWorkoutRepository {
+	squat, bench, pct := "Squat", "bench", 75.0
+	return &repositories.MockWorkoutRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Workout, error) {
+			switch id {
+			case "org-workout":
+				return &models.Workout{ID: id, UserID: "trainer-1", OrganizationID: &orgID, Exercises: []*models.WorkoutExercise{
+					{ID: "we-1", TrainingMaxName: &squat, IntensityPercentage: &pct},
+					{ID: "we-2", TrainingMaxName: &bench, IntensityPercentage: &pct},
+					{ID: "we-3", IntensityPercentage: &pct},
+				}}, nil
+			case "personal-workout":
+				return &models.Workout{ID: id, UserID: "trainer-1"}, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+	}
+}
+
+func TestCreateClass(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	var created *models.ClassSession
+	mockRepo := &repositories.MockClassSessionRepository{
+		CreateFunc: func(ctx context.Context, class *models.ClassSession) error {
+			created = class
+			class.ID = "class-1"
+			return nil
+		},
+	}
+	service := newTestClassSessionService(mockRepo, orgWorkouts("org-1"), now)
+
+	class, err := service.CreateClass(context.Background(), "trainer-1", "org-1", &models.CreateClassSessionRequest{
+		WorkoutID: "org-workout",
+		Name:      "  Morning   HIIT ",
+		StartsAt:  now.Add(2 * time.Hour),
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if created == nil || class.TrainerID != "trainer-1" || class.Name != "Morning HIIT" {
+		t.Errorf("Expected a class run by trainer-1 named Morning HIIT, got %+v", class)
+	}
+}
+
+func TestCreateClass_Rejected(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	service := newTestClassSessionService(&repositories.MockClassSessionRepository{}, orgWorkouts("org-1"), now)
+
+	tests := []struct {
+		name    string
+		userID  string
+		req     models.CreateClassSessionRequest
+		wantErr error
+	}{
+		{"member", "member-1", models.CreateClassSessionRequest{WorkoutID: "org-workout", Name: "HIIT", StartsAt: now.Add(time.Hour)}, ErrUnauthorized},
+		{"outsider", "stranger", models.CreateClassSessionRequest{WorkoutID: "org-workout", Name: "HIIT", StartsAt: now.Add(time.Hour)}, ErrUnauthorized},
+		{"past", "trainer-1", models.CreateClassSessionRequest{WorkoutID: "org-workout", Name: "HIIT", StartsAt: now.Add(-time.Hour)}, ErrClassInPast},
+		{"personal workout", "trainer-1", models.CreateClassSessionRequest{WorkoutID: "personal-workout", Name: "HIIT", StartsAt: now.Add(time.Hour)}, ErrClassWorkoutNotShared},
+		{"missing workout", "trainer-1", models.CreateClassSessionRequest{WorkoutID: "missing", Name: "HIIT", StartsAt: now.Add(time.Hour)}, ErrClassWorkoutNotShared},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.CreateClass(context.Background(), tt.userID, "org-1", &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func testClass(startsAt time.Time) func(ctx context.Context, id string, userID string) (*models.ClassSession, error) {
+	return func(ctx context.Context, id string, userID string) (*models.ClassSession, error) {
+		return &models.ClassSession{ID: id, OrganizationID: "org-1", TrainerID: "trainer-1", WorkoutID: "org-workout", StartsAt: startsAt}, nil
+	}
+}
+
+func TestJoinClass(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	var joinedLoads map[string]float64
+	mockRepo := &repositories.MockClassSessionRepository{
+		FindByIDFunc: testClass(now.Add(time.Hour)),
+		JoinFunc: func(ctx context.Context, classID string, userID string, loads map[string]float64) (*models.ClassParticipant, error) {
+			joinedLoads = loads
+			return &models.ClassParticipant{ClassSessionID: classID, UserID: userID, SessionID: "session-1", Status: "planned"}, nil
+		},
+	}
+	service := newTestClassSessionService(mockRepo, orgWorkouts("org-1"), now)
+
+	participant, err := service.JoinClass(context.Background(), "class-1", "member-1")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if participant.SessionID == "" || participant.Status != "planned" {
+		t.Errorf("Expected a planned session, got %+v", participant)
+	}
+	// 75% of the member's 140kg squat; the unset bench max and the entry without one get no load
+	if len(joinedLoads) != 1 || joinedLoads["we-1"] != 105 {
+		t.Errorf("Expected only we-1 loaded at 105kg, got %v", joinedLoads)
+	}
+
+	// Outsiders can't tell the class exists
+	if _, err := service.JoinClass(context.Background(), "class-1", "stranger"); !errors.Is(err, ErrClassNotFound) {
+		t.Errorf("Expected ErrClassNotFound, got %v", err)
+	}
+}
+
+func TestJoinClass_FullOrOver(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &repositories.MockClassSessionRepository{
+		FindByIDFunc: testClass(now.Add(time.Hour)),
+		JoinFunc: func(ctx context.Context, classID string, userID string, loads map[string]float64) (*models.ClassParticipant, error) {
+			return nil, pgx.ErrNoRows
+		},
+	}
+	service := newTestClassSessionService(mockRepo, orgWorkouts("org-1"), now)
+
+	if _, err := service.JoinClass(context.Background(), "class-1", "member-1"); !errors.Is(err, ErrClassFull) {
+		t.Errorf("Expected ErrClassFull, got %v", err)
+	}
+
+	mockRepo.FindByIDFunc = testClass(now.Add(-classJoinWindow - time.Minute))
+	if _, err := service.JoinClass(context.Background(), "class-1", "member-1"); !errors.Is(err, ErrClassOver) {
+		t.Errorf("Expected ErrClassOver, got %v", err)
+	}
+}
+
+func TestGetAttendance(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &repositories.MockClassSessionRepository{
+		FindByIDFunc: testClass(now),
+		ParticipantsFunc: func(ctx context.Context, classID string) ([]*models.ClassParticipant, error) {
+			return []*models.ClassParticipant{
+				{UserID: "a", Status: "completed"},
+				{UserID: "b", Status: "in_progress"},
+				{UserID: "c", Status: "planned"},
+			}, nil
+		},
+	}
+	service := newTestClassSessionService(mockRepo, orgWorkouts("org-1"), now)
+
+	for _, userID := range []string{"trainer-1", "trainer-2", "owner-1"} {
+		attendance, err := service.GetAttendance(context.Background(), "class-1", userID)
+		if err != nil {
+			t.Fatalf("Expected no error for %s, got %v", userID, err)
+		}
+		if attendance.Joined != 3 || attendance.Attended != 2 || attendance.Completed != 1 {
+			t.Errorf("Expected 3 joined, 2 attended and 1 completed, got %+v", attendance)
+		}
+	}
+
+	if _, err := service.GetAttendance(context.Background(), "class-1", "member-1"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for a member, got %v", err)
+	}
+}
@@ -0,0 +1,229 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrCoachRelationNotFound = domainerr.New(domainerr.NotFound, "coach relationship not found")
+	ErrCoachInvitationExists = domainerr.New(domainerr.Conflict, "an invitation for this client already exists")
+	ErrCannotCoachSelf       = domainerr.New(domainerr.Validation, "cannot coach yourself")
+	ErrInvalidCoachStatus    = domainerr.New(domainerr.Conflict, "relationship is not in a valid state for this action")
+)
+
+// CoachService handles coach-client invitations and relationships
+type CoachService struct {
+	repo   repositories.CoachClientRepository
+	emails mailer.Queue
+	audit  *AuditLogService
+}
+
+// NewCoachService creates a new coach service that emails invitations through emails;
+// changes to relationships, which grant coaches access, are audited unless audit is nil
+func NewCoachService(repo repositories.CoachClientRepository, emails mailer.Queue, audit *AuditLogService) *CoachService {
+	return &CoachService{repo: repo, emails: emails, audit: audit}
+}
+
+// InviteClient creates a pending invitation from a coach to a client email
+func (s *CoachService) InviteClient(ctx context.Context, coachID string, coachEmail string, req *models.CreateCoachInvitationRequest) (*models.CoachClient, error) {
+	clientEmail := normalizeEmail(req.ClientEmail)
+	if clientEmail == normalizeEmail(coachEmail) {
+		return nil, ErrCannotCoachSelf
+	}
+
+	existing, err := s.repo.FindByCoach(ctx, coachID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing invitations: %w", err)
+	}
+	for _, relation := range existing {
+		isOpen := relation.Status == models.CoachClientStatusPending || relation.Status == models.CoachClientStatusActive
+		if isOpen && relation.ClientEmail == clientEmail {
+			return nil, ErrCoachInvitationExists
+		}
+	}
+
+	relation := &models.CoachClient{
+		CoachID:     coachID,
+		ClientEmail: clientEmail,
+		Status:      models.CoachClientStatusPending,
+		CanWrite:    req.CanWrite,
+	}
+
+	_, err = audited(ctx, s.audit, coachID, models.AuditActionCreate, models.AuditResourceCoachClient, "", nil, func() (*models.CoachClient, error) {
+		if err := s.repo.Create(ctx, relation); err != nil {
+			return nil, fmt.Errorf("failed to create invitation: %w", err)
+		}
+		return relation, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The invitation stands without the email; the client also sees it when signing in
+	invitation := mailer.CoachInvitation{CoachEmail: normalizeEmail(coachEmail), CanWrite: req.CanWrite}
+	if err := s.emails.Enqueue(ctx, "", clientEmail, mailer.TemplateCoachInvitation, invitation); err != nil {
+		log.Printf("Failed to queue invitation email for relationship %s: %v", relation.ID, err)
+	}
+
+	return relation, nil
+}
+
+// ListClients retrieves every relationship a coach has created
+func (s *CoachService) ListClients(ctx context.Context, coachID string) ([]*models.CoachClient, error) {
+	relations, err := s.repo.FindByCoach(ctx, coachID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list clients: %w", err)
+	}
+
+	return relations, nil
+}
+
+// ListCoaches retrieves every accepted relationship for a client
+func (s *CoachService) ListCoaches(ctx context.Context, clientID string) ([]*models.CoachClient, error) {
+	relations, err := s.repo.FindByClient(ctx, clientID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list coaches: %w", err)
+	}
+
+	return relations, nil
+}
+
+// ListInvitations retrieves pending invitations addressed to the user's email
+func (s *CoachService) ListInvitations(ctx context.Context, email string) ([]*models.CoachClient, error) {
+	relations, err := s.repo.FindPendingByEmail(ctx, normalizeEmail(email))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list invitations: %w", err)
+	}
+
+	return relations, nil
+}
+
+// AcceptInvitation activates a pending invitation addressed to the user
+func (s *CoachService) AcceptInvitation(ctx context.Context, id string, userID string, email string) (*models.CoachClient, error) {
+	relation, err := s.findInvitation(ctx, id, email)
+	if err != nil {
+		return nil, err
+	}
+
+	if relation.CoachID == userID {
+		return nil, ErrCannotCoachSelf
+	}
+
+	before := *relation
+	now := time.Now()
+	relation.ClientID = &userID
+	relation.Status = models.CoachClientStatusActive
+	relation.AcceptedAt = &now
+
+	return s.update(ctx, userID, &before, relation, "failed to accept invitation")
+}
+
+// DeclineInvitation rejects a pending invitation addressed to the user
+func (s *CoachService) DeclineInvitation(ctx context.Context, id string, userID string, email string) (*models.CoachClient, error) {
+	relation, err := s.findInvitation(ctx, id, email)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *relation
+	relation.Status = models.CoachClientStatusDeclined
+
+	return s.update(ctx, userID, &before, relation, "failed to decline invitation")
+}
+
+// UpdatePermissions lets a client grant or withdraw write access for their coach
+func (s *CoachService) UpdatePermissions(ctx context.Context, id string, clientID string, canWrite bool) (*models.CoachClient, error) {
+	relation, err := s.findRelation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if relation.ClientID == nil || *relation.ClientID != clientID {
+		return nil, ErrUnauthorized
+	}
+	if relation.Status != models.CoachClientStatusActive {
+		return nil, ErrInvalidCoachStatus
+	}
+
+	before := *relation
+	relation.CanWrite = canWrite
+
+	return s.update(ctx, clientID, &before, relation, "failed to update permissions")
+}
+
+// RevokeRelationship ends a pending or active relationship; either party may revoke
+func (s *CoachService) RevokeRelationship(ctx context.Context, id string, userID string) error {
+	relation, err := s.findRelation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	isClient := relation.ClientID != nil && *relation.ClientID == userID
+	if relation.CoachID != userID && !isClient {
+		return ErrUnauthorized
+	}
+
+	if relation.Status != models.CoachClientStatusPending && relation.Status != models.CoachClientStatusActive {
+		return ErrInvalidCoachStatus
+	}
+
+	before := *relation
+	relation.Status = models.CoachClientStatusRevoked
+
+	_, err = s.update(ctx, userID, &before, relation, "failed to revoke relationship")
+	return err
+}
+
+// update stores a changed relationship, auditing the change
+func (s *CoachService) update(ctx context.Context, userID string, before *models.CoachClient, relation *models.CoachClient, failure string) (*models.CoachClient, error) {
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceCoachClient, relation.ID, before, func() (*models.CoachClient, error) {
+		if err := s.repo.Update(ctx, relation); err != nil {
+			return nil, fmt.Errorf("%s: %w", failure, err)
+		}
+		return relation, nil
+	})
+}
+
+func (s *CoachService) findRelation(ctx context.Context, id string) (*models.CoachClient, error) {
+	relation, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCoachRelationNotFound
+		}
+		return nil, fmt.Errorf("failed to get coach relationship: %w", err)
+	}
+
+	return relation, nil
+}
+
+// findInvitation loads a pending invitation, hiding invitations addressed to someone else
+func (s *CoachService) findInvitation(ctx context.Context, id string, email string) (*models.CoachClient, error) {
+	relation, err := s.findRelation(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if relation.ClientEmail != normalizeEmail(email) {
+		return nil, ErrCoachRelationNotFound
+	}
+	if relation.Status != models.CoachClientStatusPending {
+		return nil, ErrInvalidCoachStatus
+	}
+
+	return relation, nil
+}
+
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
@@ -0,0 +1,219 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrProgramWorkoutNotFound   = domainerr.New(domainerr.NotFound, "scheduled workout not found")
+	ErrInvalidProgramDate       = domainerr.New(domainerr.Validation, "scheduled_on must be a date (YYYY-MM-DD)")
+	ErrProgramWorkoutNotAllowed = domainerr.New(domainerr.Unprocessable, "program workouts must belong to the coach or the client")
+	ErrProgramWorkoutClosed     = domainerr.New(domainerr.Conflict, "scheduled workout was already done or skipped")
+)
+
+// CoachProgramService handles programs coaches assign to their clients and how closely
+// clients follow them
+type CoachProgramService struct {
+	repo     repositories.CoachProgramRepository
+	workouts repositories.WorkoutRepository
+	profiles repositories.ProfileRepository
+	policy   AccessPolicy
+	now      func() time.Time
+}
+
+// NewCoachProgramService creates a new coach program service
+func NewCoachProgramService(repo repositories.CoachProgramRepository, workouts repositories.WorkoutRepository, profiles repositories.ProfileRepository, policy AccessPolicy) *CoachProgramService {
+	return &CoachProgramService{repo: repo, workouts: workouts, profiles: profiles, policy: policy, now: time.Now}
+}
+
+// AssignProgram schedules workouts for a client of the coach. Workouts must be the coach's
+// or the client's own templates, so the client can open them.
+func (s *CoachProgramService) AssignProgram(ctx context.Context, coachID string, clientID string, req *models.CreateCoachProgramRequest) (*models.CoachProgram, error) {
+	if coachID == clientID {
+		return nil, ErrCannotCoachSelf
+	}
+	if err := s.checkCoach(ctx, coachID, clientID); err != nil {
+		return nil, err
+	}
+
+	name := normalizeName(req.Name)
+	if name == "" {
+		return nil, ErrBlankName
+	}
+
+	program := &models.CoachProgram{
+		CoachID:  coachID,
+		ClientID: clientID,
+		Name:     name,
+		Notes:    req.Notes,
+	}
+	owners := make(map[string]string)
+	for _, w := range req.Workouts {
+		day, err := time.Parse(models.ChallengeDateLayout, w.ScheduledOn)
+		if err != nil {
+			return nil, ErrInvalidProgramDate
+		}
+
+		owner, ok := owners[w.WorkoutID]
+		if !ok {
+			workout, err := s.workouts.FindByID(ctx, w.WorkoutID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return nil, fmt.Errorf("failed to get workout: %w", err)
+			}
+			if err == nil {
+				owner = workout.UserID
+			}
+			owners[w.WorkoutID] = owner
+		}
+		if owner != coachID && owner != clientID {
+			return nil, ErrProgramWorkoutNotAllowed
+		}
+
+		program.Workouts = append(program.Workouts, &models.ProgramWorkout{WorkoutID: w.WorkoutID, ScheduledOn: day})
+	}
+
+	if err := s.repo.Create(ctx, program); err != nil {
+		return nil, fmt.Errorf("failed to create program: %w", err)
+	}
+
+	// Read back for the workouts' names and statuses
+	created, err := s.repo.FindByID(ctx, program.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program: %w", err)
+	}
+	return created, nil
+}
+
+// ListPrograms retrieves a client's programs: all of them for the client, and their own
+// for a coach of the client
+func (s *CoachProgramService) ListPrograms(ctx context.Context, actorID string, clientID string) ([]*models.CoachProgram, error) {
+	coachID, err := s.programScope(ctx, actorID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	programs, err := s.repo.ListByClient(ctx, clientID, coachID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list programs: %w", err)
+	}
+
+	return programs, nil
+}
+
+// SkipWorkout lets a client skip one of their scheduled workouts that isn't done yet,
+// including ones already missed
+func (s *CoachProgramService) SkipWorkout(ctx context.Context, clientID string, id string, req *models.SkipProgramWorkoutRequest) (*models.ProgramWorkout, error) {
+	workout, err := s.repo.FindWorkout(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrProgramWorkoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get scheduled workout: %w", err)
+	}
+
+	program, err := s.repo.FindByID(ctx, workout.ProgramID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get program: %w", err)
+	}
+	if program.ClientID != clientID {
+		return nil, ErrProgramWorkoutNotFound
+	}
+
+	if workout.Status != models.ProgramWorkoutPending && workout.Status != models.ProgramWorkoutMissed {
+		return nil, ErrProgramWorkoutClosed
+	}
+
+	var reason *string
+	if req.Reason != nil {
+		if text := normalizeText(*req.Reason); text != "" {
+			reason = &text
+		}
+	}
+	if err := s.repo.SkipWorkout(ctx, id, reason); err != nil {
+		return nil, fmt.Errorf("failed to skip scheduled workout: %w", err)
+	}
+
+	now := s.now()
+	workout.Status = models.ProgramWorkoutSkipped
+	workout.SkippedAt = &now
+	workout.SkipReason = reason
+	return workout, nil
+}
+
+// GetCompliance reports how closely a client followed their programs in the week (Monday
+// to Sunday) containing day, which defaults to today in the client's timezone. Coaches see
+// only their own programs.
+func (s *CoachProgramService) GetCompliance(ctx context.Context, actorID string, clientID string, day time.Time) (*models.ComplianceReport, error) {
+	coachID, err := s.programScope(ctx, actorID, clientID)
+	if err != nil {
+		return nil, err
+	}
+
+	if day.IsZero() {
+		loc, err := userLocation(ctx, s.profiles, clientID)
+		if err != nil {
+			return nil, err
+		}
+		day = localDate(s.now(), loc)
+	}
+
+	report := &models.ComplianceReport{ClientID: clientID, WeekStart: weekStart(day)}
+	report.Workouts, err = s.repo.WeekWorkouts(ctx, clientID, coachID, report.WeekStart)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scheduled workouts: %w", err)
+	}
+
+	report.Scheduled = len(report.Workouts)
+	for _, w := range report.Workouts {
+		switch w.Status {
+		case models.ProgramWorkoutCompleted:
+			report.Completed++
+		case models.ProgramWorkoutModified:
+			report.Modified++
+		case models.ProgramWorkoutSkipped:
+			report.Skipped++
+		case models.ProgramWorkoutMissed:
+			report.Missed++
+		default:
+			report.Pending++
+		}
+	}
+	if due := report.Scheduled - report.Pending; due > 0 {
+		percent := float64(report.Completed+report.Modified) / float64(due) * 100
+		report.Compliance = &percent
+	}
+
+	return report, nil
+}
+
+// programScope returns whose programs of the client the actor sees: "" (all) for the
+// client themself, or the actor's own for a coach with access to the client
+func (s *CoachProgramService) programScope(ctx context.Context, actorID string, clientID string) (string, error) {
+	if actorID == clientID {
+		return "", nil
+	}
+	if err := s.checkCoach(ctx, actorID, clientID); err != nil {
+		return "", err
+	}
+	return actorID, nil
+}
+
+// checkCoach fails unless coachID has an active relationship with clientID
+func (s *CoachProgramService) checkCoach(ctx context.Context, coachID string, clientID string) error {
+	ok, err := s.policy.CanRead(ctx, coachID, clientID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrCoachRelationNotFound
+	}
+	return nil
+}
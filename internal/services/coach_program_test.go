@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// newTestCoachProgramService creates a program service where coach-1 coaches client-1
+func newTestCoachProgramService(repo repositories.CoachProgramRepository, now time.Time) *CoachProgramService {
+	coachClients := &repositories.MockCoachClientRepository{
+		FindActiveFunc: func(ctx context.Context, coachID, clientID string) (*models.CoachClient, error) {
+			if coachID == "coach-1" && clientID == "client-1" {
+				return &models.CoachClient{CoachID: coachID, ClientID: &clientID, Status: models.CoachClientStatusActive}, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+	}
+	workouts := &repositories.MockWorkoutRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Workout, error) {
+			owners := map[string]string{"coach-workout": "coach-1", "client-workout": "client-1", "other-workout": "someone"}
+			if owner, ok := owners[id]; ok {
+				return &models.Workout{ID: id, UserID: owner}, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+	}
+	service := NewCoachProgramService(repo, workouts, &repositories.MockProfileRepository{}, NewAccessPolicy(coachClients, &repositories.MockOrganizationRepository{}))
+	service.now = func() time.Time { return now }
+	return service
+}
+
+func TestAssignProgram(t *testing.T) {
+	var created *models.CoachProgram
+	mockRepo := &repositories.MockCoachProgramRepository{
+		CreateFunc: func(ctx context.Context, program *models.CoachProgram) error {
+			program.ID = "program-1"
+			created = program
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*models.CoachProgram, error) {
+			return created, nil
+		},
+	}
+	service := newTestCoachProgramService(mockRepo, time.Now())
+
+	program, err := service.AssignProgram(context.Background(), "coach-1", "client-1", &models.CreateCoachProgramRequest{
+		Name: "Base block",
+		Workouts: []models.ScheduleProgramWorkoutRequest{
+			{WorkoutID: "coach-workout", ScheduledOn: "2026-06-01"},
+			{WorkoutID: "client-workout", ScheduledOn: "2026-06-03"},
+			{WorkoutID: "coach-workout", ScheduledOn: "2026-06-05"},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if program.CoachID != "coach-1" || program.ClientID != "client-1" || len(program.Workouts) != 3 {
+		t.Fatalf("Expected a program of 3 workouts from coach-1 to client-1, got %+v", program)
+	}
+	if want := time.Date(2026, 6, 3, 0, 0, 0, 0, time.UTC); !program.Workouts[1].ScheduledOn.Equal(want) {
+		t.Errorf("Expected second workout on %v, got %v", want, program.Workouts[1].ScheduledOn)
+	}
+}
+
+func TestAssignProgram_Rejected(t *testing.T) {
+	service := newTestCoachProgramService(&repositories.MockCoachProgramRepository{}, time.Now())
+	workouts := func(id, day string) []models.ScheduleProgramWorkoutRequest {
+		return []models.ScheduleProgramWorkoutRequest{{WorkoutID: id, ScheduledOn: day}}
+	}
+
+	tests := []struct {
+		name     string
+		coachID  string
+		clientID string
+		workouts []models.ScheduleProgramWorkoutRequest
+		wantErr  error
+	}{
+		{"self", "client-1", "client-1", workouts("client-workout", "2026-06-01"), ErrCannotCoachSelf},
+		{"not their coach", "coach-2", "client-1", workouts("coach-workout", "2026-06-01"), ErrCoachRelationNotFound},
+		{"someone else's workout", "coach-1", "client-1", workouts("other-workout", "2026-06-01"), ErrProgramWorkoutNotAllowed},
+		{"missing workout", "coach-1", "client-1", workouts("missing", "2026-06-01"), ErrProgramWorkoutNotAllowed},
+		{"bad date", "coach-1", "client-1", workouts("coach-workout", "2026-02-30"), ErrInvalidProgramDate},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.AssignProgram(context.Background(), tt.coachID, tt.clientID, &models.CreateCoachProgramRequest{Name: "Block", Workouts: tt.workouts})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListPrograms_Scope(t *testing.T) {
+	var scopes []string
+	mockRepo := &repositories.MockCoachProgramRepository{
+		ListByClientFunc: func(ctx context.Context, clientID string, coachID string) ([]*models.CoachProgram, error) {
+			scopes = append(scopes, coachID)
+			return []*models.CoachProgram{}, nil
+		},
+	}
+	service := newTestCoachProgramService(mockRepo, time.Now())
+
+	if _, err := service.ListPrograms(context.Background(), "client-1", "client-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.ListPrograms(context.Background(), "coach-1", "client-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(scopes) != 2 || scopes[0] != "" || scopes[1] != "coach-1" {
+		t.Errorf("Expected the client to see every coach's programs and the coach their own, got %q", scopes)
+	}
+
+	if _, err := service.ListPrograms(context.Background(), "coach-2", "client-1"); !errors.Is(err, ErrCoachRelationNotFound) {
+		t.Errorf("Expected ErrCoachRelationNotFound, got %v", err)
+	}
+}
+
+func TestSkipProgramWorkout(t *testing.T) {
+	status := models.ProgramWorkoutMissed
+	skipped := false
+	mockRepo := &repositories.MockCoachProgramRepository{
+		FindWorkoutFunc: func(ctx context.Context, id string) (*models.ProgramWorkout, error) {
+			return &models.ProgramWorkout{ID: id, ProgramID: "program-1", Status: status}, nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*models.CoachProgram, error) {
+			return &models.CoachProgram{ID: id, CoachID: "coach-1", ClientID: "client-1"}, nil
+		},
+		SkipWorkoutFunc: func(ctx context.Context, id string, reason *string) error {
+			skipped = true
+			return nil
+		},
+	}
+	service := newTestCoachProgramService(mockRepo, time.Now())
+	reason := "  sick "
+
+	workout, err := service.SkipWorkout(context.Background(), "client-1", "pw-1", &models.SkipProgramWorkoutRequest{Reason: &reason})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !skipped || workout.Status != models.ProgramWorkoutSkipped || workout.SkipReason == nil || *workout.SkipReason != "sick" {
+		t.Errorf("Expected the workout to be skipped because sick, got %+v", workout)
+	}
+
+	if _, err := service.SkipWorkout(context.Background(), "coach-1", "pw-1", &models.SkipProgramWorkoutRequest{}); !errors.Is(err, ErrProgramWorkoutNotFound) {
+		t.Errorf("Expected ErrProgramWorkoutNotFound for the coach, got %v", err)
+	}
+
+	status = models.ProgramWorkoutCompleted
+	if _, err := service.SkipWorkout(context.Background(), "client-1", "pw-1", &models.SkipProgramWorkoutRequest{}); !errors.Is(err, ErrProgramWorkoutClosed) {
+		t.Errorf("Expected ErrProgramWorkoutClosed, got %v", err)
+	}
+}
+
+func TestGetCompliance(t *testing.T) {
+	now := time.Date(2026, 6, 4, 15, 0, 0, 0, time.UTC) // A Thursday
+	var gotWeek time.Time
+	mockRepo := &repositories.MockCoachProgramRepository{
+		WeekWorkoutsFunc: func(ctx context.Context, clientID string, coachID string, weekStart time.Time) ([]*models.ProgramWorkout, error) {
+			gotWeek = weekStart
+			return []*models.ProgramWorkout{
+				{Status: models.ProgramWorkoutCompleted},
+				{Status: models.ProgramWorkoutModified},
+				{Status: models.ProgramWorkoutSkipped},
+				{Status: models.ProgramWorkoutMissed},
+				{Status: models.ProgramWorkoutPending},
+			}, nil
+		},
+	}
+	service := newTestCoachProgramService(mockRepo, now)
+
+	report, err := service.GetCompliance(context.Background(), "coach-1", "client-1", time.Time{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC); !gotWeek.Equal(want) || !report.WeekStart.Equal(want) {
+		t.Errorf("Expected the week of Monday %v, got %v", want, gotWeek)
+	}
+	if report.Scheduled != 5 || report.Completed != 1 || report.Modified != 1 || report.Skipped != 1 || report.Missed != 1 || report.Pending != 1 {
+		t.Errorf("Unexpected counts: %+v", report)
+	}
+	// 2 of the 4 workouts due were done
+	if report.Compliance == nil || *report.Compliance != 50 {
+		t.Errorf("Expected 50%% compliance, got %v", report.Compliance)
+	}
+}
+
+func TestGetCompliance_NothingDue(t *testing.T) {
+	mockRepo := &repositories.MockCoachProgramRepository{
+		WeekWorkoutsFunc: func(ctx context.Context, clientID string, coachID string, weekStart time.Time) ([]*models.ProgramWorkout, error) {
+			return []*models.ProgramWorkout{{Status: models.ProgramWorkoutPending}}, nil
+		},
+	}
+	service := newTestCoachProgramService(mockRepo, time.Now())
+
+	report, err := service.GetCompliance(context.Background(), "client-1", "client-1", time.Date(2026, 6, 10, 0, 0, 0, 0, time.UTC))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.Compliance != nil {
+		t.Errorf("Expected no compliance while nothing is due, got %v", *report.Compliance)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestInviteClient_NormalizesEmail(t *testing.T) {
+	var created *models.CoachClient
+	mockRepo := &repositories.MockCoachClientRepository{
+		CreateFunc: func(ctx context.Context, relation *models.CoachClient) error {
+			created = relation
+			return nil
+		},
+	}
+
+	service := NewCoachService(mockRepo, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), nil)
+
+	req := &models.CreateCoachInvitationRequest{ClientEmail: "  Client@Example.com "}
+
+	relation, err := service.InviteClient(context.Background(), "coach-1", "coach@example.com", req)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if created == nil || relation.ClientEmail != "client@example.com" {
+		t.Errorf("Expected normalized email 'client@example.com', got '%s'", relation.ClientEmail)
+	}
+
+	if relation.Status != models.CoachClientStatusPending {
+		t.Errorf("Expected status 'pending', got '%s'", relation.Status)
+	}
+}
+
+func TestInviteClient_QueuesEmail(t *testing.T) {
+	var emailed []*models.EmailJob
+	emails := newTestEmailService(&repositories.MockEmailRepository{}, &fakeMailer{}, &emailed)
+	service := NewCoachService(&repositories.MockCoachClientRepository{}, emails, nil)
+
+	req := &models.CreateCoachInvitationRequest{ClientEmail: "Client@Example.com", CanWrite: true}
+	if _, err := service.InviteClient(context.Background(), "coach-1", "Coach@Example.com", req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(emailed) != 1 {
+		t.Fatalf("Expected an invitation email, got %+v", emailed)
+	}
+	queued := emailed[0]
+	if queued.To != "client@example.com" || queued.Template != mailer.TemplateCoachInvitation {
+		t.Fatalf("Expected an invitation email to the client, got %+v", queued)
+	}
+	if string(queued.Data) != `{"coach_email":"coach@example.com","can_write":true}` {
+		t.Errorf("Unexpected invitation data: %s", queued.Data)
+	}
+}
+
+func TestInviteClient_Self(t *testing.T) {
+	service := NewCoachService(&repositories.MockCoachClientRepository{}, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), nil)
+
+	req := &models.CreateCoachInvitationRequest{ClientEmail: "coach@example.com"}
+
+	_, err := service.InviteClient(context.Background(), "coach-1", "Coach@example.com", req)
+
+	if !errors.Is(err, ErrCannotCoachSelf) {
+		t.Errorf("Expected ErrCannotCoachSelf, got %v", err)
+	}
+}
+
+func TestInviteClient_Duplicate(t *testing.T) {
+	mockRepo := &repositories.MockCoachClientRepository{
+		FindByCoachFunc: func(ctx context.Context, coachID string) ([]*models.CoachClient, error) {
+			return []*models.CoachClient{
+				{ID: "rel-1", CoachID: coachID, ClientEmail: "client@example.com", Status: models.CoachClientStatusActive},
+			}, nil
+		},
+	}
+
+	service := NewCoachService(mockRepo, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), nil)
+
+	req := &models.CreateCoachInvitationRequest{ClientEmail: "client@example.com"}
+
+	_, err := service.InviteClient(context.Background(), "coach-1", "coach@example.com", req)
+
+	if !errors.Is(err, ErrCoachInvitationExists) {
+		t.Errorf("Expected ErrCoachInvitationExists, got %v", err)
+	}
+}
+
+func TestAcceptInvitation_Success(t *testing.T) {
+	mockRepo := &repositories.MockCoachClientRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.CoachClient, error) {
+			return &models.CoachClient{
+				ID:          id,
+				CoachID:     "coach-1",
+				ClientEmail: "client@example.com",
+				Status:      models.CoachClientStatusPending,
+			}, nil
+		},
+	}
+
+	service := NewCoachService(mockRepo, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), nil)
+
+	relation, err := service.AcceptInvitation(context.Background(), "rel-1", "client-1", "client@example.com")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if relation.Status != models.CoachClientStatusActive {
+		t.Errorf("Expected status 'active', got '%s'", relation.Status)
+	}
+
+	if relation.ClientID == nil || *relation.ClientID != "client-1" {
+		t.Errorf("Expected client ID 'client-1', got %v", relation.ClientID)
+	}
+}
+
+func TestAcceptInvitation_WrongRecipient(t *testing.T) {
+	mockRepo := &repositories.MockCoachClientRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.CoachClient, error) {
+			return &models.CoachClient{
+				ID:          id,
+				CoachID:     "coach-1",
+				ClientEmail: "client@example.com",
+				Status:      models.CoachClientStatusPending,
+			}, nil
+		},
+	}
+
+	service := NewCoachService(mockRepo, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), nil)
+
+	_, err := service.AcceptInvitation(context.Background(), "rel-1", "other-1", "other@example.com")
+
+	if !errors.Is(err, ErrCoachRelationNotFound) {
+		t.Errorf("Expected ErrCoachRelationNotFound, got %v", err)
+	}
+}
+
+func TestUpdatePermissions_Audited(t *testing.T) {
+	clientID := "client-1"
+	mockRepo := &repositories.MockCoachClientRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.CoachClient, error) {
+			return &models.CoachClient{
+				ID:       id,
+				CoachID:  "coach-1",
+				ClientID: &clientID,
+				Status:   models.CoachClientStatusActive,
+			}, nil
+		},
+	}
+	audit, entries := recordingAudit()
+	service := NewCoachService(mockRepo, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), audit)
+
+	if _, err := service.UpdatePermissions(context.Background(), "rel-1", clientID, true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(*entries))
+	}
+	entry := (*entries)[0]
+	if entry.ResourceType != models.AuditResourceCoachClient || entry.ResourceID != "rel-1" || *entry.ActorID != clientID {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if change := entry.Changes["can_write"]; len(entry.Changes) != 1 || string(change.Before) != "false" || string(change.After) != "true" {
+		t.Errorf("Expected only can_write granted, got %v", entry.Changes)
+	}
+}
+
+func TestRevokeRelationship_Outsider(t *testing.T) {
+	clientID := "client-1"
+	mockRepo := &repositories.MockCoachClientRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.CoachClient, error) {
+			return &models.CoachClient{
+				ID:       id,
+				CoachID:  "coach-1",
+				ClientID: &clientID,
+				Status:   models.CoachClientStatusActive,
+			}, nil
+		},
+	}
+
+	service := NewCoachService(mockRepo, NewEmailService(&repositories.MockEmailRepository{}, nil, nil, nil), nil)
+
+	err := service.RevokeRelationship(context.Background(), "rel-1", "someone-else")
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestEquipment_CoachDelegatedAccess(t *testing.T) {
+	equipmentRepo := &repositories.MockEquipmentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
+			return &models.Equipment{ID: id, UserID: "client-1"}, nil
+		},
+	}
+	coachRepo := &repositories.MockCoachClientRepository{
+		FindActiveFunc: func(ctx context.Context, coachID, clientID string) (*models.CoachClient, error) {
+			return &models.CoachClient{CoachID: coachID, ClientID: &clientID, CanWrite: false}, nil
+		},
+	}
+
+	service := NewEquipmentService(equipmentRepo, NewAccessPolicy(coachRepo, &repositories.MockOrganizationRepository{}), nil)
+
+	if _, err := service.GetEquipment(context.Background(), "eq-1", "coach-1"); err != nil {
+		t.Fatalf("Expected coach read access, got %v", err)
+	}
+
+	err := service.DeleteEquipment(context.Background(), "eq-1", "coach-1")
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for read-only coach, got %v", err)
+	}
+}
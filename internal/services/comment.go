@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrCommentNotFound = domainerr.New(domainerr.NotFound, "comment not found")
+	ErrEmptyComment    = domainerr.New(domainerr.Validation, "comment can't be empty")
+)
+
+// CommentService handles comments on workouts and sessions
+// Whoever can see a resource can comment on it: a workout's owner, their coaches and, for
+// organization workouts, the members; a session's owner, their coaches and whoever the
+// owner's profile privacy lets see their activity. Authors can delete their comments, and
+// owners (plus owners and trainers of an organization workout) any comment on theirs.
+type CommentService struct {
+	repo    repositories.CommentRepository
+	policy  AccessPolicy
+	follows repositories.FollowRepository
+}
+
+// NewCommentService creates a new comment service
+func NewCommentService(repo repositories.CommentRepository, policy AccessPolicy, follows repositories.FollowRepository) *CommentService {
+	return &CommentService{repo: repo, policy: policy, follows: follows}
+}
+
+// ListComments retrieves the comments on a workout or session, oldest first
+func (s *CommentService) ListComments(ctx context.Context, actorID string, resourceType string, resourceID string) ([]*models.Comment, error) {
+	if _, err := s.readableTarget(ctx, actorID, resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	comments, err := s.repo.FindByResource(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list comments: %w", err)
+	}
+	return comments, nil
+}
+
+// AddComment comments on a workout or session; its owner is notified
+func (s *CommentService) AddComment(ctx context.Context, actorID string, resourceType string, resourceID string, req *models.CreateCommentRequest) (*models.Comment, error) {
+	body := strings.TrimSpace(req.Body)
+	if body == "" {
+		return nil, ErrEmptyComment
+	}
+	if _, err := s.readableTarget(ctx, actorID, resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	comment := &models.Comment{ResourceType: resourceType, ResourceID: resourceID, UserID: actorID, Body: body}
+	if err := s.repo.Create(ctx, comment); err != nil {
+		return nil, fmt.Errorf("failed to create comment: %w", err)
+	}
+	return comment, nil
+}
+
+// DeleteComment removes a comment on a workout or session, if the actor wrote it or
+// moderates the resource
+func (s *CommentService) DeleteComment(ctx context.Context, actorID string, resourceType string, resourceID string, commentID string) error {
+	comment, err := s.repo.FindByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("failed to get comment: %w", err)
+	}
+	if comment.ResourceType != resourceType || comment.ResourceID != resourceID {
+		return ErrCommentNotFound
+	}
+
+	if comment.UserID != actorID {
+		target, err := s.target(ctx, resourceType, resourceID)
+		if err != nil {
+			return err
+		}
+		ok, err := s.canModerate(ctx, actorID, target)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return ErrUnauthorized
+		}
+	}
+
+	if err := s.repo.Delete(ctx, commentID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCommentNotFound
+		}
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	return nil
+}
+
+// readableTarget returns the resource's owner if the actor may see the resource
+func (s *CommentService) readableTarget(ctx context.Context, actorID string, resourceType string, resourceID string) (*models.CommentTarget, error) {
+	target, err := s.target(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var ok bool
+	if target.OrganizationID != nil {
+		ok, err = s.policy.CanReadOrg(ctx, actorID, *target.OrganizationID)
+	} else {
+		ok, err = s.policy.CanRead(ctx, actorID, target.OwnerID)
+		// Sessions are also seen by whoever the owner shares their activity with
+		if err == nil && !ok && resourceType == models.CommentResourceSession {
+			ok, err = s.follows.CanView(ctx, actorID, target.OwnerID)
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+	return target, nil
+}
+
+// target looks up who owns a workout or session
+func (s *CommentService) target(ctx context.Context, resourceType string, resourceID string) (*models.CommentTarget, error) {
+	target, err := s.repo.FindTarget(ctx, resourceType, resourceID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			if resourceType == models.CommentResourceWorkout {
+				return nil, ErrWorkoutNotFound
+			}
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get %s: %w", resourceType, err)
+	}
+	return target, nil
+}
+
+// canModerate reports whether the actor may delete others' comments on the resource
+func (s *CommentService) canModerate(ctx context.Context, actorID string, target *models.CommentTarget) (bool, error) {
+	if actorID == target.OwnerID {
+		return true, nil
+	}
+	if target.OrganizationID == nil {
+		return false, nil
+	}
+	ok, err := s.policy.CanWriteOrg(ctx, actorID, *target.OrganizationID)
+	if err != nil {
+		return false, fmt.Errorf("failed to check access: %w", err)
+	}
+	return ok, nil
+}
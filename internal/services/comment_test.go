@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func commentTargets(target *models.CommentTarget) *repositories.MockCommentRepository {
+	return &repositories.MockCommentRepository{
+		FindTargetFunc: func(ctx context.Context, resourceType string, resourceID string) (*models.CommentTarget, error) {
+			return target, nil
+		},
+	}
+}
+
+func TestAddComment_SessionVisibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		visible bool
+		wantErr error
+	}{
+		{"owner's profile visible to the commenter", true, nil},
+		{"private to the commenter", false, ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			follows := &repositories.MockFollowRepository{
+				CanViewFunc: func(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+					return tt.visible, nil
+				},
+			}
+			policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{})
+			service := NewCommentService(commentTargets(&models.CommentTarget{OwnerID: "user-456"}), policy, follows)
+
+			comment, err := service.AddComment(context.Background(), "user-123", models.CommentResourceSession, "session-1",
+				&models.CreateCommentRequest{Body: "  Great session!  "})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (comment.Body != "Great session!" || comment.UserID != "user-123") {
+				t.Errorf("Expected a trimmed comment by the commenter, got %+v", comment)
+			}
+		})
+	}
+}
+
+func TestAddComment_WorkoutsAreNotOpenToFollowers(t *testing.T) {
+	follows := &repositories.MockFollowRepository{
+		CanViewFunc: func(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+			return true, nil
+		},
+	}
+	policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{})
+	service := NewCommentService(commentTargets(&models.CommentTarget{OwnerID: "user-456"}), policy, follows)
+
+	_, err := service.AddComment(context.Background(), "user-123", models.CommentResourceWorkout, "workout-1",
+		&models.CreateCommentRequest{Body: "Nice plan"})
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAddComment_Empty(t *testing.T) {
+	service := NewCommentService(&repositories.MockCommentRepository{}, nil, &repositories.MockFollowRepository{})
+
+	_, err := service.AddComment(context.Background(), "user-123", models.CommentResourceSession, "session-1",
+		&models.CreateCommentRequest{Body: " \n "})
+
+	if !errors.Is(err, ErrEmptyComment) {
+		t.Errorf("Expected ErrEmptyComment, got %v", err)
+	}
+}
+
+func TestAddComment_MissingResource(t *testing.T) {
+	service := NewCommentService(&repositories.MockCommentRepository{}, nil, &repositories.MockFollowRepository{})
+
+	_, err := service.AddComment(context.Background(), "user-123", models.CommentResourceWorkout, "workout-1",
+		&models.CreateCommentRequest{Body: "Nice plan"})
+
+	if !errors.Is(err, ErrWorkoutNotFound) {
+		t.Errorf("Expected ErrWorkoutNotFound, got %v", err)
+	}
+}
+
+func TestDeleteComment_Moderation(t *testing.T) {
+	orgID := "org-1"
+	tests := []struct {
+		name    string
+		actorID string
+		role    string
+		wantErr error
+	}{
+		{"author", "user-author", "", nil},
+		{"workout owner", "user-owner", "", nil},
+		{"organization trainer", "user-trainer", models.OrgRoleTrainer, nil},
+		{"organization member", "user-member", models.OrgRoleMember, ErrUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := commentTargets(&models.CommentTarget{OwnerID: "user-owner", OrganizationID: &orgID})
+			repo.FindByIDFunc = func(ctx context.Context, id string) (*models.Comment, error) {
+				return &models.Comment{ID: id, ResourceType: models.CommentResourceWorkout, ResourceID: "workout-1", UserID: "user-author"}, nil
+			}
+			orgs := &repositories.MockOrganizationRepository{
+				FindMemberFunc: func(ctx context.Context, orgID string, userID string) (*models.OrganizationMember, error) {
+					return &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: tt.role}, nil
+				},
+			}
+			policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, orgs)
+			service := NewCommentService(repo, policy, &repositories.MockFollowRepository{})
+
+			err := service.DeleteComment(context.Background(), tt.actorID, models.CommentResourceWorkout, "workout-1", "comment-1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestDeleteComment_OtherResource(t *testing.T) {
+	repo := &repositories.MockCommentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Comment, error) {
+			return &models.Comment{ID: id, ResourceType: models.CommentResourceSession, ResourceID: "session-2", UserID: "user-123"}, nil
+		},
+	}
+	service := NewCommentService(repo, nil, &repositories.MockFollowRepository{})
+
+	err := service.DeleteComment(context.Background(), "user-123", models.CommentResourceSession, "session-1", "comment-1")
+
+	if !errors.Is(err, ErrCommentNotFound) {
+		t.Errorf("Expected ErrCommentNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInvalidEmailAddress  = domainerr.New(domainerr.Validation, "invalid email address")
+	ErrUnknownEmailTemplate = domainerr.New(domainerr.Validation, "unknown email template")
+)
+
+const (
+	// emailSendTimeout bounds sending one email
+	emailSendTimeout = 30 * time.Second
+
+	// emailMaxAttempts is how often an email is tried before giving up; with exponential
+	// backoff the last attempt is about two hours after the first
+	emailMaxAttempts = 8
+
+	// reminderLead is how long before a planned session its reminder is sent
+	reminderLead = time.Hour
+)
+
+// emailNotificationTypes are the notification types of templates sent to users; others
+// are sent regardless of notification preferences
+var emailNotificationTypes = map[string]string{
+	mailer.TemplateWorkoutReminder: models.NotificationWorkoutReminder,
+	mailer.TemplateWeeklySummary:   models.NotificationWeeklySummary,
+}
+
+// EmailService queues emails as jobs, sends them and schedules the recurring ones users
+// opted into
+type EmailService struct {
+	repo          repositories.EmailRepository
+	jobs          *jobs.Queue
+	mailer        mailer.Mailer        // Nil when no provider is configured; nothing is queued then
+	notifications *NotificationService // Users' notification preferences; nil sends everything
+	now           func() time.Time
+}
+
+// NewEmailService creates a new email service sending through the given mailer and
+// honouring users' notification preferences, and registers the email job with the queue
+// when there is a mailer
+func NewEmailService(repo repositories.EmailRepository, queue *jobs.Queue, m mailer.Mailer, notifications *NotificationService) *EmailService {
+	s := &EmailService{repo: repo, jobs: queue, mailer: m, notifications: notifications, now: time.Now}
+	if m != nil {
+		queue.Register(models.JobKindEmail, jobs.Options{MaxAttempts: emailMaxAttempts, Timeout: emailSendTimeout}, s.send)
+	}
+	return s
+}
+
+// Enqueue queues a templated email to an address, on behalf of the user it notifies if
+// any; without a mailer it is dropped
+func (s *EmailService) Enqueue(ctx context.Context, userID string, to string, template string, data any) error {
+	if !mailer.Known(template) {
+		return fmt.Errorf("%w: %q", ErrUnknownEmailTemplate, template)
+	}
+	addr, err := mail.ParseAddress(to)
+	if err != nil {
+		return fmt.Errorf("%w: %q", ErrInvalidEmailAddress, to)
+	}
+	if s.mailer == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode email: %w", err)
+	}
+	email := &models.EmailJob{To: addr.Address, Template: template, Data: raw}
+	if _, err := s.jobs.Enqueue(ctx, models.JobKindEmail, userID, email); err != nil {
+		return fmt.Errorf("failed to queue email: %w", err)
+	}
+	return nil
+}
+
+// GetPreferences retrieves the user's email preferences
+func (s *EmailService) GetPreferences(ctx context.Context, userID string) (*models.EmailPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get email preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences changes the user's email preferences
+func (s *EmailService) UpdatePreferences(ctx context.Context, userID string, req *models.UpdateEmailPreferencesRequest) (*models.EmailPreferences, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.WorkoutReminders != nil {
+		prefs.WorkoutReminders = *req.WorkoutReminders
+	}
+	if req.WeeklySummary != nil {
+		prefs.WeeklySummary = *req.WeeklySummary
+	}
+
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save email preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// Start queues reminders for upcoming sessions every interval until ctx is cancelled;
+// the job queue sends them
+func (s *EmailService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.repo.QueueWorkoutReminders(ctx, reminderLead); err != nil {
+					log.Printf("Workout reminders failed to queue: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// QueueWeeklySummaries queues the emails of the reports of the week starting at
+// weekStart for users who turned summaries on; summaries already queued are skipped, and
+// without a mailer nothing is queued
+func (s *EmailService) QueueWeeklySummaries(ctx context.Context, weekStart time.Time) error {
+	if s.mailer == nil {
+		return nil
+	}
+	if _, err := s.repo.QueueWeeklySummaries(ctx, weekStart); err != nil {
+		return fmt.Errorf("failed to queue weekly summaries: %w", err)
+	}
+	return nil
+}
+
+// send renders and sends an email job, unless the user it notifies turned its type of
+// email off; emails that can't be rendered or that the provider rejects are not retried
+func (s *EmailService) send(ctx context.Context, job *models.Job) error {
+	var email models.EmailJob
+	if err := json.Unmarshal(job.Payload, &email); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid email job: %w", err))
+	}
+
+	if kind, ok := emailNotificationTypes[email.Template]; ok && job.UserID != nil && s.notifications != nil {
+		allowed, _, err := s.notifications.Deliverable(ctx, *job.UserID, kind, models.NotificationChannelEmail)
+		if err != nil {
+			return err
+		}
+		if !allowed {
+			return nil
+		}
+	}
+
+	msg, err := mailer.Render(email.Template, email.To, email.Data)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+	if err := s.mailer.Send(ctx, msg); err != nil {
+		if errors.Is(err, mailer.ErrRejected) {
+			return jobs.Permanent(err)
+		}
+		return err
+	}
+	return nil
+}
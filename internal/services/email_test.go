@@ -0,0 +1,167 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+type fakeMailer struct {
+	sent []*mailer.Message
+	err  error
+}
+
+func (f *fakeMailer) Send(ctx context.Context, msg *mailer.Message) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+// newTestEmailService creates an email service whose queued emails are collected in queued
+func newTestEmailService(repo repositories.EmailRepository, m mailer.Mailer, queued *[]*models.EmailJob) *EmailService {
+	return NewEmailService(repo, jobs.NewQueue(&repositories.MockJobRepository{
+		EnqueueFunc: func(ctx context.Context, job *models.Job) error {
+			email := &models.EmailJob{}
+			if job.Kind != models.JobKindEmail || json.Unmarshal(job.Payload, email) != nil {
+				return fmt.Errorf("unexpected job %s: %s", job.Kind, job.Payload)
+			}
+			*queued = append(*queued, email)
+			return nil
+		},
+	}), m, nil)
+}
+
+func TestEnqueueEmail_Validates(t *testing.T) {
+	var queued []*models.EmailJob
+	service := newTestEmailService(&repositories.MockEmailRepository{}, &fakeMailer{}, &queued)
+
+	err := service.Enqueue(context.Background(), "", "Client <client@example.com>", mailer.TemplateCoachInvitation, mailer.CoachInvitation{CoachEmail: "coach@example.com"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(queued) != 1 || queued[0].To != "client@example.com" || queued[0].Template != mailer.TemplateCoachInvitation {
+		t.Errorf("Expected the email to be queued to the bare address, got %+v", queued)
+	}
+
+	if err := service.Enqueue(context.Background(), "", "not an address", mailer.TemplateCoachInvitation, nil); !errors.Is(err, ErrInvalidEmailAddress) {
+		t.Errorf("Expected ErrInvalidEmailAddress, got %v", err)
+	}
+	if err := service.Enqueue(context.Background(), "", "client@example.com", "newsletter", nil); !errors.Is(err, ErrUnknownEmailTemplate) {
+		t.Errorf("Expected ErrUnknownEmailTemplate, got %v", err)
+	}
+}
+
+func TestEnqueueEmail_DroppedWithoutMailer(t *testing.T) {
+	var queued []*models.EmailJob
+	service := newTestEmailService(&repositories.MockEmailRepository{}, nil, &queued)
+
+	if err := service.Enqueue(context.Background(), "", "client@example.com", mailer.TemplateCoachInvitation, mailer.CoachInvitation{}); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if len(queued) != 0 {
+		t.Errorf("Expected nothing to be queued without a mailer, got %+v", queued)
+	}
+}
+
+func TestUpdateEmailPreferences_KeepsOmittedFields(t *testing.T) {
+	var saved *models.EmailPreferences
+	mockRepo := &repositories.MockEmailRepository{
+		GetPreferencesFunc: func(ctx context.Context, userID string) (*models.EmailPreferences, error) {
+			return &models.EmailPreferences{UserID: userID, WorkoutReminders: true}, nil
+		},
+		SavePreferencesFunc: func(ctx context.Context, prefs *models.EmailPreferences) error {
+			saved = prefs
+			return nil
+		},
+	}
+	service := NewEmailService(mockRepo, nil, nil, nil)
+
+	on := true
+	prefs, err := service.UpdatePreferences(context.Background(), "user-123", &models.UpdateEmailPreferencesRequest{WeeklySummary: &on})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved != prefs || !prefs.WorkoutReminders || !prefs.WeeklySummary {
+		t.Errorf("Expected reminders to stay on and summaries to turn on, got %+v", prefs)
+	}
+}
+
+func emailJob(t *testing.T, to string, template string, data any) *models.Job {
+	t.Helper()
+	raw, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(&models.EmailJob{To: to, Template: template, Data: raw})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &models.Job{ID: "job-1", Kind: models.JobKindEmail, Payload: payload, Attempts: 1, MaxAttempts: emailMaxAttempts}
+}
+
+func TestEmailSend_Renders(t *testing.T) {
+	m := &fakeMailer{}
+	var queued []*models.EmailJob
+	service := newTestEmailService(&repositories.MockEmailRepository{}, m, &queued)
+
+	job := emailJob(t, "client@example.com", mailer.TemplateCoachInvitation, mailer.CoachInvitation{CoachEmail: "coach@example.com"})
+	if err := service.send(context.Background(), job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(m.sent) != 1 || m.sent[0].To != "client@example.com" || m.sent[0].Subject == "" {
+		t.Fatalf("Expected the rendered email to be sent, got %+v", m.sent)
+	}
+}
+
+func TestEmailSend_RetriesUnlessRejected(t *testing.T) {
+	m := &fakeMailer{err: errors.New("connection reset")}
+	var queued []*models.EmailJob
+	service := newTestEmailService(&repositories.MockEmailRepository{}, m, &queued)
+
+	job := emailJob(t, "client@example.com", mailer.TemplateCoachInvitation, mailer.CoachInvitation{CoachEmail: "coach@example.com"})
+	if err := service.send(context.Background(), job); err == nil || jobs.IsPermanent(err) {
+		t.Errorf("Expected a failure to be retried, got %v", err)
+	}
+
+	m.err = fmt.Errorf("%w: unknown recipient", mailer.ErrRejected)
+	if err := service.send(context.Background(), job); !jobs.IsPermanent(err) || !errors.Is(err, mailer.ErrRejected) {
+		t.Errorf("Expected a rejected email not to be retried, got %v", err)
+	}
+
+	unknown := emailJob(t, "client@example.com", "newsletter", nil)
+	if err := service.send(context.Background(), unknown); !jobs.IsPermanent(err) {
+		t.Errorf("Expected an email that can't be rendered not to be retried, got %v", err)
+	}
+}
+
+func TestEmailSend_SkipsTurnedOffTypes(t *testing.T) {
+	m := &fakeMailer{}
+	service := newTestEmailService(&repositories.MockEmailRepository{}, m, &[]*models.EmailJob{})
+	service.notifications = NewNotificationService(&repositories.MockNotificationRepository{
+		GetPreferencesFunc: func(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+			return &models.NotificationPreferences{UserID: userID, Channels: map[string][]string{models.NotificationWorkoutReminder: {models.NotificationChannelPush}}}, nil
+		},
+	})
+
+	userID := "user-123"
+	reminder := emailJob(t, "user@example.com", mailer.TemplateWorkoutReminder, mailer.WorkoutReminder{RuleID: "rule-1", Name: "Leg day"})
+	reminder.UserID = &userID
+	if err := service.send(context.Background(), reminder); err != nil || len(m.sent) != 0 {
+		t.Errorf("Expected the turned off reminder to be skipped, got %v and %d sent", err, len(m.sent))
+	}
+
+	invitation := emailJob(t, "client@example.com", mailer.TemplateCoachInvitation, mailer.CoachInvitation{CoachEmail: "coach@example.com"})
+	if err := service.send(context.Background(), invitation); err != nil || len(m.sent) != 1 {
+		t.Errorf("Expected emails to other addresses to be sent, got %v and %d sent", err, len(m.sent))
+	}
+}
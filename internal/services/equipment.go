@@ -6,42 +6,88 @@ import (
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
 	"github.com/juan-cantero/fitapi/internal/models"
 	"github.com/juan-cantero/fitapi/internal/repositories"
 )
 
 var (
-	ErrEquipmentNotFound = errors.New("equipment not found")
-	ErrUnauthorized      = errors.New("unauthorized to perform this action")
+	ErrEquipmentNotFound = domainerr.New(domainerr.NotFound, "equipment not found")
+	ErrUnauthorized      = domainerr.New(domainerr.Forbidden, "unauthorized to perform this action")
+	ErrVersionConflict   = domainerr.New(domainerr.Conflict, "changed by another request since it was read")
+	ErrEquipmentExists   = domainerr.New(domainerr.Conflict, "equipment with this name already exists")
+	ErrEquipmentInUse    = domainerr.New(domainerr.Unprocessable, "equipment is used by exercises")
 )
 
 // EquipmentService handles business logic for equipment
 type EquipmentService struct {
-	repo repositories.EquipmentRepository
+	repo   repositories.EquipmentRepository
+	policy AccessPolicy
+	audit  *AuditLogService
 }
 
-// NewEquipmentService creates a new equipment service
-func NewEquipmentService(repo repositories.EquipmentRepository) *EquipmentService {
-	return &EquipmentService{repo: repo}
+// NewEquipmentService creates a new equipment service; writes are audited unless audit is nil
+func NewEquipmentService(repo repositories.EquipmentRepository, policy AccessPolicy, audit *AuditLogService) *EquipmentService {
+	return &EquipmentService{repo: repo, policy: policy, audit: audit}
 }
 
 // CreateEquipment creates a new equipment for a user
 func (s *EquipmentService) CreateEquipment(ctx context.Context, userID string, req *models.CreateEquipmentRequest) (*models.Equipment, error) {
 	equipment := &models.Equipment{
-		Name:        req.Name,
-		Description: req.Description,
+		Name:        normalizeName(req.Name),
+		Description: normalizeText(req.Description),
 		UserID:      userID,
 	}
+	if equipment.Name == "" {
+		return nil, ErrBlankName
+	}
+
+	return s.create(ctx, userID, equipment)
+}
 
-	if err := s.repo.Create(ctx, equipment); err != nil {
-		return nil, fmt.Errorf("failed to create equipment: %w", err)
+// CreateOrgEquipment creates equipment shared within an organization (owners and trainers only)
+func (s *EquipmentService) CreateOrgEquipment(ctx context.Context, userID string, orgID string, req *models.CreateEquipmentRequest) (*models.Equipment, error) {
+	ok, err := s.policy.CanWriteOrg(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
 	}
 
-	return equipment, nil
+	equipment := &models.Equipment{
+		Name:           normalizeName(req.Name),
+		Description:    normalizeText(req.Description),
+		UserID:         userID,
+		OrganizationID: &orgID,
+	}
+	if equipment.Name == "" {
+		return nil, ErrBlankName
+	}
+
+	return s.create(ctx, userID, equipment)
+}
+
+// create stores new equipment, auditing its creation
+func (s *EquipmentService) create(ctx context.Context, userID string, equipment *models.Equipment) (*models.Equipment, error) {
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceEquipment, "", nil, func() (*models.Equipment, error) {
+		if err := s.repo.Create(ctx, equipment); err != nil {
+			if errors.Is(err, repositories.ErrDuplicate) {
+				return nil, ErrEquipmentExists
+			}
+			return nil, fmt.Errorf("failed to create equipment: %w", err)
+		}
+		return equipment, nil
+	})
 }
 
 // GetEquipment retrieves a single equipment by ID
 func (s *EquipmentService) GetEquipment(ctx context.Context, id string, userID string) (*models.Equipment, error) {
+	return s.findAuthorized(ctx, id, userID, false)
+}
+
+// findAuthorized loads equipment and verifies the actor may read it (or modify it when write is set)
+func (s *EquipmentService) findAuthorized(ctx context.Context, id string, userID string, write bool) (*models.Equipment, error) {
 	equipment, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -50,8 +96,22 @@ func (s *EquipmentService) GetEquipment(ctx context.Context, id string, userID s
 		return nil, fmt.Errorf("failed to get equipment: %w", err)
 	}
 
-	// Check ownership
-	if equipment.UserID != userID {
+	// Check ownership, delegated access, or organization role
+	var ok bool
+	switch {
+	case equipment.OrganizationID != nil && write:
+		ok, err = s.policy.CanWriteOrg(ctx, userID, *equipment.OrganizationID)
+	case equipment.OrganizationID != nil:
+		ok, err = s.policy.CanReadOrg(ctx, userID, *equipment.OrganizationID)
+	case write:
+		ok, err = s.policy.CanWrite(ctx, userID, equipment.UserID)
+	default:
+		ok, err = s.policy.CanRead(ctx, userID, equipment.UserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
 		return nil, ErrUnauthorized
 	}
 
@@ -68,35 +128,90 @@ func (s *EquipmentService) ListEquipment(ctx context.Context, userID string) ([]
 	return equipment, nil
 }
 
+// ListEquipmentForOwner retrieves another user's equipment on behalf of an actor with delegated read access
+func (s *EquipmentService) ListEquipmentForOwner(ctx context.Context, actorID string, ownerID string) ([]*models.Equipment, error) {
+	ok, err := s.policy.CanRead(ctx, actorID, ownerID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	return s.ListEquipment(ctx, ownerID)
+}
+
+// ListOrgEquipment retrieves all equipment shared within an organization (any member)
+func (s *EquipmentService) ListOrgEquipment(ctx context.Context, userID string, orgID string) ([]*models.Equipment, error) {
+	ok, err := s.policy.CanReadOrg(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	equipment, err := s.repo.FindByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization equipment: %w", err)
+	}
+
+	return equipment, nil
+}
+
 // UpdateEquipment updates an existing equipment
+// The request carries the version the edit is based on; if the equipment has been updated
+// since, nothing is written and ErrVersionConflict is returned.
 func (s *EquipmentService) UpdateEquipment(ctx context.Context, id string, userID string, req *models.UpdateEquipmentRequest) (*models.Equipment, error) {
-	// First check if equipment exists and user owns it
-	equipment, err := s.GetEquipment(ctx, id, userID)
+	// First check if equipment exists and user may modify it
+	equipment, err := s.findAuthorized(ctx, id, userID, true)
 	if err != nil {
 		return nil, err
 	}
 
-	// Update fields
-	equipment.Name = req.Name
-	equipment.Description = req.Description
+	if equipment.Version != req.Version {
+		return nil, ErrVersionConflict
+	}
+	before := *equipment
 
-	if err := s.repo.Update(ctx, equipment); err != nil {
-		return nil, fmt.Errorf("failed to update equipment: %w", err)
+	// Update fields
+	equipment.Name = normalizeName(req.Name)
+	equipment.Description = normalizeText(req.Description)
+	if equipment.Name == "" {
+		return nil, ErrBlankName
 	}
 
-	return equipment, nil
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceEquipment, id, &before, func() (*models.Equipment, error) {
+		if err := s.repo.Update(ctx, equipment); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Updated or deleted between the read and the write
+				return nil, ErrVersionConflict
+			}
+			if errors.Is(err, repositories.ErrDuplicate) {
+				return nil, ErrEquipmentExists
+			}
+			return nil, fmt.Errorf("failed to update equipment: %w", err)
+		}
+		return equipment, nil
+	})
 }
 
 // DeleteEquipment deletes an equipment
 func (s *EquipmentService) DeleteEquipment(ctx context.Context, id string, userID string) error {
-	// First check if equipment exists and user owns it
-	if _, err := s.GetEquipment(ctx, id, userID); err != nil {
+	// First check if equipment exists and user may modify it
+	equipment, err := s.findAuthorized(ctx, id, userID, true)
+	if err != nil {
 		return err
 	}
 
-	if err := s.repo.Delete(ctx, id); err != nil {
-		return fmt.Errorf("failed to delete equipment: %w", err)
-	}
-
-	return nil
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceEquipment, id, equipment, func() (*models.Equipment, error) {
+		if err := s.repo.Delete(ctx, id); err != nil {
+			if errors.Is(err, repositories.ErrReferenced) {
+				return nil, ErrEquipmentInUse
+			}
+			return nil, fmt.Errorf("failed to delete equipment: %w", err)
+		}
+		return nil, nil
+	})
+	return err
 }
@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/jackc/pgx/v5"
@@ -19,7 +20,7 @@ func TestCreateEquipment(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	req := &models.CreateEquipmentRequest{
 		Name:        "Barbell",
@@ -48,7 +49,7 @@ func TestCreateEquipment_RepositoryError(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	req := &models.CreateEquipmentRequest{
 		Name: "Barbell",
@@ -61,6 +62,49 @@ func TestCreateEquipment_RepositoryError(t *testing.T) {
 	}
 }
 
+func TestCreateEquipment_NormalizesInput(t *testing.T) {
+	var stored *models.Equipment
+	mockRepo := &repositories.MockEquipmentRepository{
+		CreateFunc: func(ctx context.Context, eq *models.Equipment) error {
+			stored = eq
+			return nil
+		},
+	}
+
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	_, err := service.CreateEquipment(context.Background(), "user-123", &models.CreateEquipmentRequest{
+		Name:        "  adjustable   bench ",
+		Description: " Flat to incline\n",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stored.Name != "Adjustable bench" || stored.Description != "Flat to incline" {
+		t.Errorf("Expected normalized name and description, got %q and %q", stored.Name, stored.Description)
+	}
+
+	if _, err := service.CreateEquipment(context.Background(), "user-123", &models.CreateEquipmentRequest{Name: " \t "}); !errors.Is(err, ErrBlankName) {
+		t.Errorf("Expected ErrBlankName, got %v", err)
+	}
+}
+
+func TestCreateEquipment_DuplicateName(t *testing.T) {
+	mockRepo := &repositories.MockEquipmentRepository{
+		CreateFunc: func(ctx context.Context, eq *models.Equipment) error {
+			return fmt.Errorf("%w: unique violation", repositories.ErrDuplicate)
+		},
+	}
+
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	_, err := service.CreateEquipment(context.Background(), "user-123", &models.CreateEquipmentRequest{Name: "Barbell"})
+
+	if !errors.Is(err, ErrEquipmentExists) {
+		t.Errorf("Expected ErrEquipmentExists, got %v", err)
+	}
+}
+
 func TestGetEquipment_Success(t *testing.T) {
 	expectedEquipment := &models.Equipment{
 		ID:     "eq-1",
@@ -74,7 +118,7 @@ func TestGetEquipment_Success(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	equipment, err := service.GetEquipment(context.Background(), "eq-1", "user-123")
 
@@ -94,7 +138,7 @@ func TestGetEquipment_NotFound(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	_, err := service.GetEquipment(context.Background(), "nonexistent", "user-123")
 
@@ -113,7 +157,7 @@ func TestGetEquipment_Unauthorized(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	_, err := service.GetEquipment(context.Background(), "eq-1", "user-123")
 
@@ -137,7 +181,7 @@ func TestListEquipment(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	list, err := service.ListEquipment(context.Background(), "user-123")
 
@@ -154,21 +198,24 @@ func TestUpdateEquipment_Success(t *testing.T) {
 	mockRepo := &repositories.MockEquipmentRepository{
 		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
 			return &models.Equipment{
-				ID:     "eq-1",
-				Name:   "Old Name",
-				UserID: "user-123",
+				ID:      "eq-1",
+				Name:    "Old Name",
+				UserID:  "user-123",
+				Version: 3,
 			}, nil
 		},
 		UpdateFunc: func(ctx context.Context, eq *models.Equipment) error {
+			eq.Version++
 			return nil
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	req := &models.UpdateEquipmentRequest{
 		Name:        "New Name",
 		Description: "Updated description",
+		Version:     3,
 	}
 
 	updated, err := service.UpdateEquipment(context.Background(), "eq-1", "user-123", req)
@@ -180,6 +227,9 @@ func TestUpdateEquipment_Success(t *testing.T) {
 	if updated.Name != "New Name" {
 		t.Errorf("Expected name 'New Name', got '%s'", updated.Name)
 	}
+	if updated.Version != 4 {
+		t.Errorf("Expected version 4, got %d", updated.Version)
+	}
 }
 
 func TestUpdateEquipment_Unauthorized(t *testing.T) {
@@ -192,7 +242,7 @@ func TestUpdateEquipment_Unauthorized(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	req := &models.UpdateEquipmentRequest{Name: "New Name"}
 
@@ -203,6 +253,49 @@ func TestUpdateEquipment_Unauthorized(t *testing.T) {
 	}
 }
 
+func TestUpdateEquipment_StaleVersion(t *testing.T) {
+	mockRepo := &repositories.MockEquipmentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
+			return &models.Equipment{ID: "eq-1", UserID: "user-123", Version: 4}, nil
+		},
+		UpdateFunc: func(ctx context.Context, eq *models.Equipment) error {
+			t.Error("Expected no update for a stale version")
+			return nil
+		},
+	}
+
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	req := &models.UpdateEquipmentRequest{Name: "New Name", Version: 3}
+
+	_, err := service.UpdateEquipment(context.Background(), "eq-1", "user-123", req)
+
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+}
+
+func TestUpdateEquipment_ConcurrentUpdate(t *testing.T) {
+	mockRepo := &repositories.MockEquipmentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
+			return &models.Equipment{ID: "eq-1", UserID: "user-123", Version: 3}, nil
+		},
+		UpdateFunc: func(ctx context.Context, eq *models.Equipment) error {
+			return pgx.ErrNoRows // Another device's update got in between
+		},
+	}
+
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	req := &models.UpdateEquipmentRequest{Name: "New Name", Version: 3}
+
+	_, err := service.UpdateEquipment(context.Background(), "eq-1", "user-123", req)
+
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict, got %v", err)
+	}
+}
+
 func TestDeleteEquipment_Success(t *testing.T) {
 	mockRepo := &repositories.MockEquipmentRepository{
 		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
@@ -216,7 +309,7 @@ func TestDeleteEquipment_Success(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	err := service.DeleteEquipment(context.Background(), "eq-1", "user-123")
 
@@ -235,7 +328,7 @@ func TestDeleteEquipment_Unauthorized(t *testing.T) {
 		},
 	}
 
-	service := NewEquipmentService(mockRepo)
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
 
 	err := service.DeleteEquipment(context.Background(), "eq-1", "user-123")
 
@@ -243,3 +336,25 @@ func TestDeleteEquipment_Unauthorized(t *testing.T) {
 		t.Errorf("Expected ErrUnauthorized, got %v", err)
 	}
 }
+
+func TestDeleteEquipment_InUse(t *testing.T) {
+	mockRepo := &repositories.MockEquipmentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
+			return &models.Equipment{
+				ID:     "eq-1",
+				UserID: "user-123",
+			}, nil
+		},
+		DeleteFunc: func(ctx context.Context, id string) error {
+			return fmt.Errorf("%w: foreign key violation", repositories.ErrReferenced)
+		},
+	}
+
+	service := NewEquipmentService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	err := service.DeleteEquipment(context.Background(), "eq-1", "user-123")
+
+	if !errors.Is(err, ErrEquipmentInUse) {
+		t.Errorf("Expected ErrEquipmentInUse, got %v", err)
+	}
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrExerciseNotFound   = domainerr.New(domainerr.NotFound, "exercise not found")
+	ErrInvalidSearchQuery = domainerr.New(domainerr.Validation, "search query must not be empty")
+	ErrInvalidMerge       = domainerr.New(domainerr.Validation, "invalid exercise merge")
+)
+
+const (
+	// searchMatchThreshold is the word similarity a name needs to count as a match
+	searchMatchThreshold = 0.4
+	// searchSuggestThreshold is the looser similarity used for did-you-mean suggestions
+	searchSuggestThreshold = 0.2
+
+	// duplicateThreshold is the name similarity at which two exercises are flagged as duplicates
+	duplicateThreshold = 0.5
+
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+)
+
+// ExerciseService handles business logic for the exercise library
+type ExerciseService struct {
+	repo     repositories.ExerciseRepository
+	injuries repositories.InjuryRepository
+	policy   AccessPolicy
+	cache    *cache.Cache
+}
+
+// NewExerciseService creates a new exercise service; a nil cache reads every time
+func NewExerciseService(repo repositories.ExerciseRepository, injuries repositories.InjuryRepository, policy AccessPolicy, c *cache.Cache) *ExerciseService {
+	return &ExerciseService{repo: repo, injuries: injuries, policy: policy, cache: c}
+}
+
+// ListExercises retrieves the exercise library visible to a user.
+// Anonymous callers (empty userID) and publicOnly requests only see public exercises;
+// signed-in users otherwise also see their own private exercises. For signed-in users,
+// exercises loading a body part with an active injury are flagged, or left out with
+// excludeContraindicated.
+func (s *ExerciseService) ListExercises(ctx context.Context, userID string, publicOnly bool, excludeContraindicated bool) ([]*models.Exercise, error) {
+	var exercises []*models.Exercise
+	var err error
+	if userID == "" || publicOnly {
+		exercises, err = cache.Fetch(ctx, s.cache, exerciseCacheScope, "public", exerciseCacheTTL, func() ([]*models.Exercise, error) {
+			return s.repo.FindPublic(ctx)
+		})
+	} else {
+		exercises, err = cache.Fetch(ctx, s.cache, exerciseCacheScope, "visible:"+userID, exerciseCacheTTL, func() ([]*models.Exercise, error) {
+			return s.repo.FindVisible(ctx, userID)
+		})
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list exercises: %w", err)
+	}
+
+	if userID == "" {
+		return exercises, nil
+	}
+	return flagContraindicated(ctx, s.injuries, userID, exercises, excludeContraindicated)
+}
+
+// GetExercise retrieves a single exercise that is public or readable by the user
+func (s *ExerciseService) GetExercise(ctx context.Context, id string, userID string) (*models.Exercise, error) {
+	exercise, err := cache.Fetch(ctx, s.cache, exerciseCacheScope, "id:"+id, exerciseCacheTTL, func() (*models.Exercise, error) {
+		return s.repo.FindByID(ctx, id)
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseNotFound
+		}
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+
+	if exercise.IsPublic {
+		return exercise, nil
+	}
+
+	// Private exercises are hidden from anonymous callers entirely
+	if userID == "" {
+		return nil, ErrExerciseNotFound
+	}
+
+	ok, err := s.policy.CanRead(ctx, userID, exercise.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	return exercise, nil
+}
+
+// SearchExercises finds visible exercises by accent-insensitive fuzzy name match.
+// When nothing matches, the closest name is returned as a did-you-mean suggestion.
+// limit outside 1..50 falls back to the default of 20.
+func (s *ExerciseService) SearchExercises(ctx context.Context, userID string, query string, limit int) (*models.ExerciseSearchResult, error) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil, ErrInvalidSearchQuery
+	}
+	if limit <= 0 || limit > maxSearchLimit {
+		limit = defaultSearchLimit
+	}
+
+	exercises, err := s.repo.Search(ctx, userID, query, searchMatchThreshold, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search exercises: %w", err)
+	}
+
+	result := &models.ExerciseSearchResult{Results: exercises}
+	if len(exercises) > 0 {
+		return result, nil
+	}
+
+	suggestion, err := s.repo.Suggest(ctx, userID, query, searchSuggestThreshold)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to suggest exercise: %w", err)
+	}
+	result.DidYouMean = &suggestion
+
+	return result, nil
+}
+
+// FindDuplicateExercises suggests pairs of the user's private exercises that look like duplicates
+func (s *ExerciseService) FindDuplicateExercises(ctx context.Context, userID string) ([]*models.ExerciseDuplicate, error) {
+	duplicates, err := s.repo.FindDuplicates(ctx, userID, duplicateThreshold)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find duplicate exercises: %w", err)
+	}
+
+	return duplicates, nil
+}
+
+// MergeExercises folds duplicate exercises into a target exercise.
+// The user must own the target and every source; sources must be private so that
+// other users' workouts never get re-pointed to an exercise they cannot see.
+func (s *ExerciseService) MergeExercises(ctx context.Context, req *models.MergeExercisesRequest, userID string) (*models.ExerciseMergeResult, error) {
+	target, err := s.findOwned(ctx, req.TargetID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{target.ID: true}
+	for _, sourceID := range req.SourceIDs {
+		if seen[sourceID] {
+			return nil, fmt.Errorf("%w: source %s is the target or listed twice", ErrInvalidMerge, sourceID)
+		}
+		seen[sourceID] = true
+
+		source, err := s.findOwned(ctx, sourceID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if source.IsPublic {
+			return nil, fmt.Errorf("%w: public exercise %s cannot be merged away", ErrInvalidMerge, sourceID)
+		}
+	}
+
+	result, err := s.repo.Merge(ctx, target.ID, req.SourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge exercises: %w", err)
+	}
+
+	// The triggers invalidate every instance, but asynchronously; the user reads their
+	// merged library right away
+	s.cache.Invalidate(ctx, exerciseCacheScope, analyticsCacheScope(userID))
+
+	return result, nil
+}
+
+// findOwned retrieves an exercise and verifies the user owns it
+func (s *ExerciseService) findOwned(ctx context.Context, id string, userID string) (*models.Exercise, error) {
+	exercise, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseNotFound
+		}
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+
+	if exercise.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return exercise, nil
+}
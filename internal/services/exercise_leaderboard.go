@@ -0,0 +1,47 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrInvalidLeaderboardMetric = domainerr.New(domainerr.Validation, "metric must be e1rm or top_set")
+
+// ExerciseLeaderboardService ranks users on an exercise among the people they follow
+type ExerciseLeaderboardService struct {
+	repo      repositories.ExerciseLeaderboardRepository
+	exercises *ExerciseService
+}
+
+// NewExerciseLeaderboardService creates a new exercise leaderboard service; exercises
+// decides which exercises the user may see
+func NewExerciseLeaderboardService(repo repositories.ExerciseLeaderboardRepository, exercises *ExerciseService) *ExerciseLeaderboardService {
+	return &ExerciseLeaderboardService{repo: repo, exercises: exercises}
+}
+
+// GetLeaderboard ranks the user and the people they follow by their best set of an
+// exercise the user can see. metric is e1rm (the default) or top_set; relative ranks by
+// the metric per kg of body weight.
+func (s *ExerciseLeaderboardService) GetLeaderboard(ctx context.Context, userID string, exerciseID string, metric string, relative bool) (*models.ExerciseLeaderboard, error) {
+	if metric == "" {
+		metric = models.LeaderboardMetricE1RM
+	}
+	if metric != models.LeaderboardMetricE1RM && metric != models.LeaderboardMetricTopSet {
+		return nil, ErrInvalidLeaderboardMetric
+	}
+
+	if _, err := s.exercises.GetExercise(ctx, exerciseID, userID); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.Leaderboard(ctx, exerciseID, userID, metric, relative, defaultLeaderboardSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get leaderboard: %w", err)
+	}
+
+	return &models.ExerciseLeaderboard{ExerciseID: exerciseID, Metric: metric, Relative: relative, Entries: entries}, nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGetExerciseLeaderboard(t *testing.T) {
+	exercises := newTestExerciseService(&repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, Name: "Squat", IsPublic: true}, nil
+		},
+	})
+	var gotMetric string
+	var gotRelative bool
+	repo := &repositories.MockExerciseLeaderboardRepository{
+		LeaderboardFunc: func(ctx context.Context, exerciseID string, userID string, metric string, relative bool, limit int) ([]*models.ExerciseLeaderboardEntry, error) {
+			gotMetric, gotRelative = metric, relative
+			return []*models.ExerciseLeaderboardEntry{{Rank: 1, UserID: userID, Value: 1.5}}, nil
+		},
+	}
+	service := NewExerciseLeaderboardService(repo, exercises)
+
+	leaderboard, err := service.GetLeaderboard(context.Background(), "user-123", "ex-squat", "", true)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if gotMetric != models.LeaderboardMetricE1RM || !gotRelative || leaderboard.Metric != models.LeaderboardMetricE1RM {
+		t.Errorf("Expected a relative e1rm ranking by default, got %s (relative %v)", gotMetric, gotRelative)
+	}
+	if len(leaderboard.Entries) != 1 {
+		t.Errorf("Expected 1 entry, got %d", len(leaderboard.Entries))
+	}
+}
+
+func TestGetExerciseLeaderboard_Errors(t *testing.T) {
+	privateExercise := newTestExerciseService(&repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: "user-456"}, nil
+		},
+	})
+	service := NewExerciseLeaderboardService(&repositories.MockExerciseLeaderboardRepository{}, privateExercise)
+
+	if _, err := service.GetLeaderboard(context.Background(), "user-123", "ex-1", "volume", false); !errors.Is(err, ErrInvalidLeaderboardMetric) {
+		t.Errorf("Expected ErrInvalidLeaderboardMetric, got %v", err)
+	}
+	if _, err := service.GetLeaderboard(context.Background(), "user-123", "ex-1", models.LeaderboardMetricTopSet, false); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for another user's exercise, got %v", err)
+	}
+}
@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/progression"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrExerciseLogNotFound    = domainerr.New(domainerr.NotFound, "exercise is not part of this session")
+	ErrExerciseAlreadyStarted = domainerr.New(domainerr.Conflict, "exercise already has completed sets")
+	ErrInvalidSwap            = domainerr.New(domainerr.Validation, "substitute must differ from the planned exercise")
+	ErrEquipmentUnavailable   = domainerr.New(domainerr.Conflict, "substitute needs equipment you don't have")
+	ErrInvalidWeeks           = domainerr.New(domainerr.Validation, "weeks must be between 1 and 52")
+)
+
+// maxSkippedVolumeWeeks caps how far back weekly skipped volume reaches
+const maxSkippedVolumeWeeks = 52
+
+// ExerciseSwapService replaces or skips planned exercises during a session
+type ExerciseSwapService struct {
+	repo      repositories.ExerciseSwapRepository
+	sessions  repositories.SessionRepository
+	exercises repositories.ExerciseRepository
+	profiles  repositories.ProfileRepository
+	policy    AccessPolicy
+	now       func() time.Time
+}
+
+// NewExerciseSwapService creates a new exercise swap service
+func NewExerciseSwapService(repo repositories.ExerciseSwapRepository, sessions repositories.SessionRepository, exercises repositories.ExerciseRepository, profiles repositories.ProfileRepository, policy AccessPolicy) *ExerciseSwapService {
+	return &ExerciseSwapService{repo: repo, sessions: sessions, exercises: exercises, profiles: profiles, policy: policy, now: time.Now}
+}
+
+// SwapExercise replaces a planned exercise of the user's in-progress session with a substitute.
+// Sets, reps and intensity carry over unchanged. The planned load is scaled by the ratio of the
+// substitute's estimated one-rep max to the original's, from the user's history, and rounded to
+// the nearest plate increment; without history for both, no load is prescribed. The swap and
+// its reason are recorded for the user's coach.
+func (s *ExerciseSwapService) SwapExercise(ctx context.Context, sessionID string, req *models.SwapExerciseRequest, userID string) (*models.ExerciseSwapResult, error) {
+	ownerID, err := sessionOwner(ctx, s.sessions, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	candidate, err := s.repo.FindCandidate(ctx, sessionID, req.ExerciseLogID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseLogNotFound
+		}
+		return nil, fmt.Errorf("failed to get session exercise: %w", err)
+	}
+	if candidate.SessionStatus != "in_progress" {
+		return nil, ErrSessionNotInProgress
+	}
+	if candidate.SetsCompleted > 0 {
+		return nil, ErrExerciseAlreadyStarted
+	}
+	if candidate.ExerciseID == req.SubstituteExerciseID {
+		return nil, ErrInvalidSwap
+	}
+
+	substitute, err := s.exercises.FindByID(ctx, req.SubstituteExerciseID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseNotFound
+		}
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+	if !substitute.IsPublic && substitute.UserID != userID {
+		return nil, ErrExerciseNotFound
+	}
+
+	missing, err := s.repo.MissingEquipment(ctx, userID, substitute.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check equipment: %w", err)
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrEquipmentUnavailable, strings.Join(missing, ", "))
+	}
+
+	maxes, err := s.repo.EstimatedMaxes(ctx, userID, []string{candidate.ExerciseID, substitute.ID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get strength history: %w", err)
+	}
+
+	swap := &models.ExerciseSwap{
+		SessionID:      sessionID,
+		ExerciseLogID:  candidate.ExerciseLogID,
+		UserID:         userID,
+		FromExerciseID: &candidate.ExerciseID,
+		ToExerciseID:   &substitute.ID,
+		Reason:         req.Reason,
+		FromWeightKg:   candidate.WeightPlannedKg,
+	}
+	if from, to := maxes[candidate.ExerciseID], maxes[substitute.ID]; from > 0 && to > 0 {
+		ratio := math.Round(to/from*1000) / 1000
+		swap.StrengthRatio = &ratio
+		if candidate.WeightPlannedKg != nil {
+			if load := progression.RoundLoad(*candidate.WeightPlannedKg * ratio); load > 0 {
+				swap.ToWeightKg = &load
+			}
+		}
+	}
+
+	if err := s.repo.Swap(ctx, swap); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseLogNotFound
+		}
+		return nil, fmt.Errorf("failed to swap exercise: %w", err)
+	}
+
+	return &models.ExerciseSwapResult{
+		Swap:                swap,
+		SetsPlanned:         candidate.SetsPlanned,
+		RepsPlanned:         candidate.RepsPlanned,
+		WeightPlannedKg:     swap.ToWeightKg,
+		IntensityPercentage: candidate.IntensityPercentage,
+	}, nil
+}
+
+// ListSwaps retrieves a session's swaps; the session's owner and their coaches may view them
+func (s *ExerciseSwapService) ListSwaps(ctx context.Context, sessionID string, actorID string) ([]*models.ExerciseSwap, error) {
+	ownerID, err := sessionOwner(ctx, s.sessions, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.policy.CanRead(ctx, actorID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	swaps, err := s.repo.FindBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list swaps: %w", err)
+	}
+
+	return swaps, nil
+}
+
+// SkipExercise marks a planned exercise of the user's in-progress session as skipped, so the
+// work it would have added shows up in weekly skipped volume. Skipping again updates the reason.
+func (s *ExerciseSwapService) SkipExercise(ctx context.Context, sessionID string, req *models.SkipExerciseRequest, userID string) (*models.SkippedExercise, error) {
+	ownerID, err := sessionOwner(ctx, s.sessions, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	candidate, err := s.repo.FindCandidate(ctx, sessionID, req.ExerciseLogID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseLogNotFound
+		}
+		return nil, fmt.Errorf("failed to get session exercise: %w", err)
+	}
+	if candidate.SessionStatus != "in_progress" {
+		return nil, ErrSessionNotInProgress
+	}
+	if candidate.SetsCompleted > 0 {
+		return nil, ErrExerciseAlreadyStarted
+	}
+
+	skipped := &models.SkippedExercise{
+		SessionID:     sessionID,
+		ExerciseLogID: candidate.ExerciseLogID,
+		ExerciseID:    candidate.ExerciseID,
+		Reason:        req.Reason,
+		SkippedAt:     s.now().UTC(),
+	}
+	if err := s.repo.Skip(ctx, skipped); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseLogNotFound
+		}
+		return nil, fmt.Errorf("failed to skip exercise: %w", err)
+	}
+
+	return skipped, nil
+}
+
+// WeeklySkippedVolume totals the planned work the user skipped per exercise over the current
+// and previous weeks (Monday to Sunday, in the user's timezone), newest week first
+func (s *ExerciseSwapService) WeeklySkippedVolume(ctx context.Context, userID string, weeks int) ([]*models.WeeklySkippedVolume, error) {
+	if weeks < 1 || weeks > maxSkippedVolumeWeeks {
+		return nil, ErrInvalidWeeks
+	}
+
+	loc, err := userLocation(ctx, s.profiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	today := localDate(s.now(), loc)
+	sinceMonday := (int(today.Weekday()) + 6) % 7
+	since := today.AddDate(0, 0, -sinceMonday-7*(weeks-1))
+
+	volumes, err := s.repo.WeeklySkippedVolume(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get skipped volume: %w", err)
+	}
+
+	return volumes, nil
+}
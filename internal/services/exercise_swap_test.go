@@ -0,0 +1,260 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestSwapService(repo repositories.ExerciseSwapRepository, sessions repositories.SessionRepository) *ExerciseSwapService {
+	exercises := &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			switch id {
+			case "exercise-db-press":
+				return &models.Exercise{ID: id, Name: "Dumbbell Bench Press", IsPublic: true}, nil
+			case "exercise-private":
+				return &models.Exercise{ID: id, Name: "Someone's Press", UserID: "user-456"}, nil
+			}
+			return &models.Exercise{ID: id, Name: "Bench Press", IsPublic: true}, nil
+		},
+	}
+	return NewExerciseSwapService(repo, sessions, exercises, &repositories.MockProfileRepository{}, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+}
+
+func plannedBench() *models.SwapCandidate {
+	sets, reps, weight := 5, 5, 100.0
+	return &models.SwapCandidate{
+		SessionID:       "session-1",
+		UserID:          "user-123",
+		SessionStatus:   "in_progress",
+		ExerciseLogID:   "log-1",
+		ExerciseID:      "exercise-bench",
+		SetsPlanned:     &sets,
+		RepsPlanned:     &reps,
+		WeightPlannedKg: &weight,
+	}
+}
+
+func TestSwapExercise_ScalesLoad(t *testing.T) {
+	var saved *models.ExerciseSwap
+	mockRepo := &repositories.MockExerciseSwapRepository{
+		FindCandidateFunc: func(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+			return plannedBench(), nil
+		},
+		EstimatedMaxesFunc: func(ctx context.Context, userID string, exerciseIDs []string) (map[string]float64, error) {
+			return map[string]float64{"exercise-bench": 120, "exercise-db-press": 84}, nil
+		},
+		SwapFunc: func(ctx context.Context, swap *models.ExerciseSwap) error {
+			saved = swap
+			return nil
+		},
+	}
+
+	service := newTestSwapService(mockRepo, ownedSession("user-123"))
+
+	reason := "Bench taken"
+	req := &models.SwapExerciseRequest{ExerciseLogID: "log-1", SubstituteExerciseID: "exercise-db-press", Reason: &reason}
+	result, err := service.SwapExercise(context.Background(), "session-1", req, "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if *saved.StrengthRatio != 0.7 {
+		t.Errorf("Expected strength ratio 0.7, got %v", *saved.StrengthRatio)
+	}
+	if *result.WeightPlannedKg != 70 || *saved.FromWeightKg != 100 {
+		t.Errorf("Expected 100kg scaled to 70kg, got %v", *result.WeightPlannedKg)
+	}
+	if *result.SetsPlanned != 5 || *result.RepsPlanned != 5 {
+		t.Errorf("Expected 5x5 carried over, got %vx%v", *result.SetsPlanned, *result.RepsPlanned)
+	}
+	if *saved.Reason != "Bench taken" || *saved.ToExerciseID != "exercise-db-press" {
+		t.Errorf("Expected swap to record the reason and substitute, got %+v", saved)
+	}
+}
+
+func TestSwapExercise_NoHistoryLeavesLoadOpen(t *testing.T) {
+	mockRepo := &repositories.MockExerciseSwapRepository{
+		FindCandidateFunc: func(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+			return plannedBench(), nil
+		},
+		EstimatedMaxesFunc: func(ctx context.Context, userID string, exerciseIDs []string) (map[string]float64, error) {
+			return map[string]float64{"exercise-bench": 120}, nil
+		},
+	}
+
+	service := newTestSwapService(mockRepo, ownedSession("user-123"))
+
+	req := &models.SwapExerciseRequest{ExerciseLogID: "log-1", SubstituteExerciseID: "exercise-db-press"}
+	result, err := service.SwapExercise(context.Background(), "session-1", req, "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.WeightPlannedKg != nil || result.Swap.StrengthRatio != nil {
+		t.Errorf("Expected no load or ratio without substitute history, got %+v", result)
+	}
+}
+
+func TestSwapExercise_Errors(t *testing.T) {
+	tests := []struct {
+		name       string
+		owner      string
+		candidate  func(c *models.SwapCandidate)
+		substitute string
+		missing    []string
+		wantErr    error
+	}{
+		{name: "other user's session", owner: "user-456", substitute: "exercise-db-press", wantErr: ErrUnauthorized},
+		{name: "session not in progress", candidate: func(c *models.SwapCandidate) { c.SessionStatus = "completed" }, substitute: "exercise-db-press", wantErr: ErrSessionNotInProgress},
+		{name: "exercise already started", candidate: func(c *models.SwapCandidate) { c.SetsCompleted = 2 }, substitute: "exercise-db-press", wantErr: ErrExerciseAlreadyStarted},
+		{name: "same exercise", substitute: "exercise-bench", wantErr: ErrInvalidSwap},
+		{name: "private substitute", substitute: "exercise-private", wantErr: ErrExerciseNotFound},
+		{name: "missing equipment", substitute: "exercise-db-press", missing: []string{"Dumbbells"}, wantErr: ErrEquipmentUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			owner := tt.owner
+			if owner == "" {
+				owner = "user-123"
+			}
+			mockRepo := &repositories.MockExerciseSwapRepository{
+				FindCandidateFunc: func(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+					c := plannedBench()
+					if tt.candidate != nil {
+						tt.candidate(c)
+					}
+					return c, nil
+				},
+				MissingEquipmentFunc: func(ctx context.Context, userID string, exerciseID string) ([]string, error) {
+					return tt.missing, nil
+				},
+				SwapFunc: func(ctx context.Context, swap *models.ExerciseSwap) error {
+					t.Error("Expected swap not to be saved")
+					return nil
+				},
+			}
+
+			service := newTestSwapService(mockRepo, ownedSession(owner))
+
+			req := &models.SwapExerciseRequest{ExerciseLogID: "log-1", SubstituteExerciseID: tt.substitute}
+			_, err := service.SwapExercise(context.Background(), "session-1", req, "user-123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListSwaps_CoachAccess(t *testing.T) {
+	coachRepo := &repositories.MockCoachClientRepository{
+		FindActiveFunc: func(ctx context.Context, coachID, clientID string) (*models.CoachClient, error) {
+			return &models.CoachClient{CoachID: coachID, ClientID: &clientID, Status: models.CoachClientStatusActive}, nil
+		},
+	}
+	mockRepo := &repositories.MockExerciseSwapRepository{}
+
+	service := NewExerciseSwapService(mockRepo, ownedSession("client-123"), &repositories.MockExerciseRepository{}, &repositories.MockProfileRepository{}, NewAccessPolicy(coachRepo, &repositories.MockOrganizationRepository{}))
+
+	if _, err := service.ListSwaps(context.Background(), "session-1", "coach-456"); err != nil {
+		t.Fatalf("Expected coach to read client swaps, got %v", err)
+	}
+
+	strangers := NewExerciseSwapService(mockRepo, ownedSession("client-123"), &repositories.MockExerciseRepository{}, &repositories.MockProfileRepository{}, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+	if _, err := strangers.ListSwaps(context.Background(), "session-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestSkipExercise(t *testing.T) {
+	var saved *models.SkippedExercise
+	mockRepo := &repositories.MockExerciseSwapRepository{
+		FindCandidateFunc: func(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+			return plannedBench(), nil
+		},
+		SkipFunc: func(ctx context.Context, skipped *models.SkippedExercise) error {
+			saved = skipped
+			return nil
+		},
+	}
+
+	service := newTestSwapService(mockRepo, ownedSession("user-123"))
+	service.now = func() time.Time { return time.Date(2026, 5, 13, 18, 0, 0, 0, time.UTC) }
+
+	reason := "Shoulder pain"
+	req := &models.SkipExerciseRequest{ExerciseLogID: "log-1", Reason: &reason}
+	result, err := service.SkipExercise(context.Background(), "session-1", req, "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved != result || result.ExerciseID != "exercise-bench" || *result.Reason != "Shoulder pain" {
+		t.Errorf("Expected skip of exercise-bench to be saved with its reason, got %+v", result)
+	}
+	if !result.SkippedAt.Equal(service.now()) {
+		t.Errorf("Expected skipped_at %v, got %v", service.now(), result.SkippedAt)
+	}
+}
+
+func TestSkipExercise_Errors(t *testing.T) {
+	tests := []struct {
+		name     string
+		userID   string
+		status   string
+		setsDone int
+		wantErr  error
+	}{
+		{"not owner", "user-456", "in_progress", 0, ErrUnauthorized},
+		{"session completed", "user-123", "completed", 0, ErrSessionNotInProgress},
+		{"already started", "user-123", "in_progress", 2, ErrExerciseAlreadyStarted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &repositories.MockExerciseSwapRepository{
+				FindCandidateFunc: func(ctx context.Context, sessionID string, exerciseLogID string) (*models.SwapCandidate, error) {
+					candidate := plannedBench()
+					candidate.SessionStatus = tt.status
+					candidate.SetsCompleted = tt.setsDone
+					return candidate, nil
+				},
+			}
+
+			service := newTestSwapService(mockRepo, ownedSession("user-123"))
+
+			_, err := service.SkipExercise(context.Background(), "session-1", &models.SkipExerciseRequest{ExerciseLogID: "log-1"}, tt.userID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestWeeklySkippedVolume_StartsOnMonday(t *testing.T) {
+	var gotSince time.Time
+	mockRepo := &repositories.MockExerciseSwapRepository{
+		WeeklySkippedVolumeFunc: func(ctx context.Context, userID string, since time.Time) ([]*models.WeeklySkippedVolume, error) {
+			gotSince = since
+			return []*models.WeeklySkippedVolume{}, nil
+		},
+	}
+
+	service := newTestSwapService(mockRepo, &repositories.MockSessionRepository{})
+	service.now = func() time.Time { return time.Date(2026, 5, 17, 21, 0, 0, 0, time.UTC) } // Sunday
+
+	if _, err := service.WeeklySkippedVolume(context.Background(), "user-123", 2); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := time.Date(2026, 5, 4, 0, 0, 0, 0, time.UTC); !gotSince.Equal(want) {
+		t.Errorf("Expected since %v, got %v", want, gotSince)
+	}
+
+	if _, err := service.WeeklySkippedVolume(context.Background(), "user-123", 0); !errors.Is(err, ErrInvalidWeeks) {
+		t.Errorf("Expected ErrInvalidWeeks, got %v", err)
+	}
+}
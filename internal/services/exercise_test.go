@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestExerciseService(repo repositories.ExerciseRepository) *ExerciseService {
+	policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{})
+	return NewExerciseService(repo, &repositories.MockInjuryRepository{}, policy, nil)
+}
+
+func TestListExercises_AnonymousGetsPublicOnly(t *testing.T) {
+	publicCalled := false
+	mockRepo := &repositories.MockExerciseRepository{
+		FindPublicFunc: func(ctx context.Context) ([]*models.Exercise, error) {
+			publicCalled = true
+			return []*models.Exercise{{ID: "ex-1", Name: "Squat", IsPublic: true}}, nil
+		},
+		FindVisibleFunc: func(ctx context.Context, userID string) ([]*models.Exercise, error) {
+			t.Fatal("Expected FindVisible not to be called for anonymous users")
+			return nil, nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	list, err := service.ListExercises(context.Background(), "", false, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !publicCalled || len(list) != 1 {
+		t.Errorf("Expected public library with 1 item, got %d", len(list))
+	}
+}
+
+func TestListExercises_SignedInIncludesPrivate(t *testing.T) {
+	mockRepo := &repositories.MockExerciseRepository{
+		FindVisibleFunc: func(ctx context.Context, userID string) ([]*models.Exercise, error) {
+			return []*models.Exercise{
+				{ID: "ex-1", Name: "Squat", IsPublic: true},
+				{ID: "ex-2", Name: "My Curl", UserID: userID},
+			}, nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	list, err := service.ListExercises(context.Background(), "user-123", false, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(list) != 2 {
+		t.Errorf("Expected 2 items, got %d", len(list))
+	}
+}
+
+func TestGetExercise_PrivateHiddenFromAnonymous(t *testing.T) {
+	mockRepo := &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: "user-123", IsPublic: false}, nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	_, err := service.GetExercise(context.Background(), "ex-1", "")
+
+	if !errors.Is(err, ErrExerciseNotFound) {
+		t.Errorf("Expected ErrExerciseNotFound, got %v", err)
+	}
+}
+
+func TestGetExercise_PrivateOtherUser(t *testing.T) {
+	mockRepo := &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: "different-user", IsPublic: false}, nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	_, err := service.GetExercise(context.Background(), "ex-1", "user-123")
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestSearchExercises_Matches(t *testing.T) {
+	mockRepo := &repositories.MockExerciseRepository{
+		SearchFunc: func(ctx context.Context, userID string, query string, threshold float64, limit int) ([]*models.Exercise, error) {
+			if query != "pres de banca" || limit != defaultSearchLimit {
+				t.Errorf("Unexpected search args: query=%q limit=%d", query, limit)
+			}
+			return []*models.Exercise{{ID: "ex-1", Name: "Press banca", IsPublic: true}}, nil
+		},
+		SuggestFunc: func(ctx context.Context, userID string, query string, threshold float64) (string, error) {
+			t.Fatal("Expected Suggest not to be called when there are matches")
+			return "", nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	result, err := service.SearchExercises(context.Background(), "", "  pres de banca ", 0)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Results) != 1 || result.DidYouMean != nil {
+		t.Errorf("Expected 1 result and no suggestion, got %d and %v", len(result.Results), result.DidYouMean)
+	}
+}
+
+func TestSearchExercises_DidYouMean(t *testing.T) {
+	mockRepo := &repositories.MockExerciseRepository{
+		SuggestFunc: func(ctx context.Context, userID string, query string, threshold float64) (string, error) {
+			return "Press banca", nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	result, err := service.SearchExercises(context.Background(), "user-123", "prss bnca", 10)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(result.Results) != 0 {
+		t.Errorf("Expected no results, got %d", len(result.Results))
+	}
+	if result.DidYouMean == nil || *result.DidYouMean != "Press banca" {
+		t.Errorf("Expected suggestion 'Press banca', got %v", result.DidYouMean)
+	}
+}
+
+func TestSearchExercises_NoSuggestion(t *testing.T) {
+	service := newTestExerciseService(&repositories.MockExerciseRepository{})
+
+	result, err := service.SearchExercises(context.Background(), "", "zzzz", 10)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.DidYouMean != nil {
+		t.Errorf("Expected no suggestion, got %v", *result.DidYouMean)
+	}
+}
+
+func TestSearchExercises_EmptyQuery(t *testing.T) {
+	service := newTestExerciseService(&repositories.MockExerciseRepository{})
+
+	_, err := service.SearchExercises(context.Background(), "", "   ", 10)
+
+	if !errors.Is(err, ErrInvalidSearchQuery) {
+		t.Errorf("Expected ErrInvalidSearchQuery, got %v", err)
+	}
+}
+
+func TestMergeExercises_Success(t *testing.T) {
+	exercises := map[string]*models.Exercise{
+		"ex-1": {ID: "ex-1", Name: "Press banca", UserID: "user-123"},
+		"ex-2": {ID: "ex-2", Name: "Press de banca", UserID: "user-123"},
+	}
+	var mergedSources []string
+	mockRepo := &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			return exercises[id], nil
+		},
+		MergeFunc: func(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error) {
+			mergedSources = sourceIDs
+			return &models.ExerciseMergeResult{Exercise: exercises[targetID], MergedCount: len(sourceIDs)}, nil
+		},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	req := &models.MergeExercisesRequest{TargetID: "ex-1", SourceIDs: []string{"ex-2"}}
+	result, err := service.MergeExercises(context.Background(), req, "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.MergedCount != 1 || len(mergedSources) != 1 || mergedSources[0] != "ex-2" {
+		t.Errorf("Expected ex-2 to be merged, got %v", mergedSources)
+	}
+}
+
+func TestMergeExercises_Invalid(t *testing.T) {
+	exercises := map[string]*models.Exercise{
+		"ex-1":   {ID: "ex-1", UserID: "user-123"},
+		"ex-2":   {ID: "ex-2", UserID: "user-123"},
+		"public": {ID: "public", UserID: "user-123", IsPublic: true},
+		"other":  {ID: "other", UserID: "other-user"},
+	}
+	mockRepo := &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			if exercise, ok := exercises[id]; ok {
+				return exercise, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+		MergeFunc: func(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error) {
+			t.Fatal("Expected Merge not to be called")
+			return nil, nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		req     *models.MergeExercisesRequest
+		wantErr error
+	}{
+		{"source is target", &models.MergeExercisesRequest{TargetID: "ex-1", SourceIDs: []string{"ex-1"}}, ErrInvalidMerge},
+		{"duplicate source", &models.MergeExercisesRequest{TargetID: "ex-1", SourceIDs: []string{"ex-2", "ex-2"}}, ErrInvalidMerge},
+		{"public source", &models.MergeExercisesRequest{TargetID: "ex-1", SourceIDs: []string{"public"}}, ErrInvalidMerge},
+		{"other user's source", &models.MergeExercisesRequest{TargetID: "ex-1", SourceIDs: []string{"other"}}, ErrUnauthorized},
+		{"missing target", &models.MergeExercisesRequest{TargetID: "missing", SourceIDs: []string{"ex-2"}}, ErrExerciseNotFound},
+	}
+
+	service := newTestExerciseService(mockRepo)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.MergeExercises(context.Background(), tt.req, "user-123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
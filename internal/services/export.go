@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrExportNotFound = domainerr.New(domainerr.NotFound, "export not found")
+	ErrExportNotReady = domainerr.New(domainerr.Conflict, "export is not ready for download")
+	ErrExportExpired  = domainerr.New(domainerr.Gone, "export has expired")
+)
+
+const (
+	// accountExportTimeout bounds one attempt at generating an archive
+	accountExportTimeout = 5 * time.Minute
+
+	// accountExportAttempts is how often generating an archive is tried before the
+	// export fails
+	accountExportAttempts = 3
+
+	// accountExportAbandoned covers every attempt and the backoff between them; older
+	// pending exports are treated as abandoned
+	accountExportAbandoned = 30 * time.Minute
+
+	// accountExportTTL is how long a generated archive stays downloadable
+	accountExportTTL = 7 * 24 * time.Hour
+)
+
+// exerciseLogCSVHeader lists the columns of the training history CSV export
+var exerciseLogCSVHeader = []string{
+	"session_date", "session_id", "session_name", "session_status",
+	"order", "exercise", "sets_completed", "sets_planned", "reps_completed", "reps_planned",
+	"weight_kg", "duration_seconds", "distance_meters", "rpe", "personal_record", "notes",
+}
+
+// ExportService produces downloadable copies of a user's data
+type ExportService struct {
+	repo  repositories.ExportRepository
+	jobs  *jobs.Queue
+	audit *AuditLogService
+	now   func() time.Time
+}
+
+// NewExportService creates a new export service generating archives as jobs of the queue;
+// account export requests are audited unless audit is nil
+func NewExportService(repo repositories.ExportRepository, queue *jobs.Queue, audit *AuditLogService) *ExportService {
+	s := &ExportService{repo: repo, jobs: queue, audit: audit, now: time.Now}
+	queue.Register(models.JobKindAccountExport, jobs.Options{MaxAttempts: accountExportAttempts, Timeout: accountExportTimeout}, s.generateAccountExport)
+	return s
+}
+
+// accountExportJob is the payload of an account export job
+type accountExportJob struct {
+	ExportID string `json:"export_id"`
+}
+
+// WriteExerciseLogsCSV streams the user's full training history to w as CSV.
+// Session dates are RFC 3339 in UTC; empty cells mean the value was not logged.
+func (s *ExportService) WriteExerciseLogsCSV(ctx context.Context, userID string, w io.Writer) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exerciseLogCSVHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+
+	err := s.repo.StreamExerciseLogs(ctx, userID, func(log *models.ExerciseLogExport) error {
+		return writer.Write([]string{
+			log.SessionDate.UTC().Format(time.RFC3339),
+			log.SessionID,
+			stringOrEmpty(log.SessionName),
+			log.SessionStatus,
+			strconv.Itoa(log.OrderIndex),
+			log.ExerciseName,
+			intOrEmpty(log.SetsCompleted),
+			intOrEmpty(log.SetsPlanned),
+			intOrEmpty(log.RepsCompleted),
+			intOrEmpty(log.RepsPlanned),
+			floatOrEmpty(log.WeightKg),
+			intOrEmpty(log.DurationSec),
+			floatOrEmpty(log.DistanceM),
+			intOrEmpty(log.RPE),
+			strconv.FormatBool(log.IsPR),
+			stringOrEmpty(log.Notes),
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export exercise logs: %w", err)
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
+
+// RequestAccountExport starts generating a JSON archive of all the user's data.
+// The export is created pending and completed by a background job; poll GetAccountExport
+// for its status. A request while another export is still pending returns that export.
+func (s *ExportService) RequestAccountExport(ctx context.Context, userID string) (*models.AccountExport, error) {
+	pending, err := s.repo.FindPendingAccountExport(ctx, userID, s.now().Add(-accountExportAbandoned))
+	if err == nil {
+		return pending, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check pending exports: %w", err)
+	}
+
+	export := &models.AccountExport{
+		UserID: userID,
+		Status: models.AccountExportStatusPending,
+	}
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceAccountExport, "", nil, func() (*models.AccountExport, error) {
+		if err := s.repo.CreateAccountExport(ctx, export); err != nil {
+			return nil, fmt.Errorf("failed to create export: %w", err)
+		}
+
+		if _, err := s.jobs.Enqueue(ctx, models.JobKindAccountExport, userID, &accountExportJob{ExportID: export.ID}); err != nil {
+			if err := s.repo.FailAccountExport(ctx, export.ID, "archive generation could not be queued"); err != nil {
+				log.Printf("Failed to mark export %s as failed: %v", export.ID, err)
+			}
+			return nil, fmt.Errorf("failed to queue export: %w", err)
+		}
+		return export, nil
+	})
+}
+
+// generateAccountExport builds and stores an export job's archive; a failed last attempt
+// is recorded on the export
+func (s *ExportService) generateAccountExport(ctx context.Context, job *models.Job) error {
+	var payload accountExportJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid account export job: %w", err))
+	}
+	if job.UserID == nil {
+		return jobs.Permanent(errors.New("account export job has no user"))
+	}
+
+	archive, err := s.repo.BuildAccountArchive(ctx, *job.UserID)
+	if err == nil {
+		err = s.repo.CompleteAccountExport(ctx, payload.ExportID, archive, s.now().Add(accountExportTTL))
+	}
+	if err != nil {
+		log.Printf("Account export failed: export=%s user=%s attempt=%d err=%v", payload.ExportID, *job.UserID, job.Attempts, err)
+		if jobs.LastAttempt(job) {
+			if err := s.repo.FailAccountExport(ctx, payload.ExportID, "archive generation failed"); err != nil {
+				log.Printf("Failed to mark export %s as failed: %v", payload.ExportID, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// GetAccountExport retrieves the status of one of the user's exports
+func (s *ExportService) GetAccountExport(ctx context.Context, id string, userID string) (*models.AccountExport, error) {
+	export, err := s.repo.FindAccountExport(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExportNotFound
+		}
+		return nil, fmt.Errorf("failed to get export: %w", err)
+	}
+
+	// Other users' exports are indistinguishable from missing ones
+	if export.UserID != userID {
+		return nil, ErrExportNotFound
+	}
+
+	return export, nil
+}
+
+// DownloadAccountExport retrieves a ready, unexpired export along with its archive
+func (s *ExportService) DownloadAccountExport(ctx context.Context, id string, userID string) (*models.AccountExport, []byte, error) {
+	export, err := s.GetAccountExport(ctx, id, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if export.Status != models.AccountExportStatusReady {
+		return nil, nil, ErrExportNotReady
+	}
+	if export.ExpiresAt != nil && s.now().After(*export.ExpiresAt) {
+		return nil, nil, ErrExportExpired
+	}
+
+	archive, err := s.repo.FindAccountArchive(ctx, id)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get export archive: %w", err)
+	}
+
+	return export, archive, nil
+}
+
+func stringOrEmpty(v *string) string {
+	if v == nil {
+		return ""
+	}
+	return *v
+}
+
+func intOrEmpty(v *int) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.Itoa(*v)
+}
+
+func floatOrEmpty(v *float64) string {
+	if v == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*v, 'f', -1, 64)
+}
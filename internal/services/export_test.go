@@ -0,0 +1,223 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestWriteExerciseLogsCSV(t *testing.T) {
+	sets, reps, rpe := 3, 5, 8
+	weight := 102.5
+	notes := "felt fast, \"easy\""
+	mockRepo := &repositories.MockExportRepository{
+		ExerciseLogs: []*models.ExerciseLogExport{
+			{
+				SessionID:     "session-1",
+				SessionDate:   time.Date(2026, 6, 1, 18, 30, 0, 0, time.UTC),
+				SessionStatus: "completed",
+				ExerciseName:  "Squat",
+				OrderIndex:    1,
+				SetsCompleted: &sets,
+				RepsCompleted: &reps,
+				WeightKg:      &weight,
+				RPE:           &rpe,
+				IsPR:          true,
+				Notes:         &notes,
+			},
+		},
+	}
+
+	service := NewExportService(mockRepo, jobs.NewQueue(&repositories.MockJobRepository{}), nil)
+
+	var buf bytes.Buffer
+	if err := service.WriteExerciseLogsCSV(context.Background(), "user-123", &buf); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected header and 1 row, got %d lines", len(lines))
+	}
+	if !strings.HasPrefix(lines[0], "session_date,session_id") {
+		t.Errorf("Unexpected header: %s", lines[0])
+	}
+	want := `2026-06-01T18:30:00Z,session-1,,completed,1,Squat,3,,5,,102.5,,,8,true,"felt fast, ""easy"""`
+	if lines[1] != want {
+		t.Errorf("Expected row %s, got %s", want, lines[1])
+	}
+}
+
+func TestWriteExerciseLogsCSV_RepositoryError(t *testing.T) {
+	mockRepo := &repositories.MockExportRepository{
+		StreamExerciseLogsFunc: func(ctx context.Context, userID string, fn func(*models.ExerciseLogExport) error) error {
+			return errors.New("connection reset")
+		},
+	}
+
+	service := NewExportService(mockRepo, jobs.NewQueue(&repositories.MockJobRepository{}), nil)
+
+	var buf bytes.Buffer
+	if err := service.WriteExerciseLogsCSV(context.Background(), "user-123", &buf); err == nil {
+		t.Error("Expected error, got nil")
+	}
+}
+
+// newTestExportService creates an export service whose queued jobs are collected in queued
+func newTestExportService(repo repositories.ExportRepository, queued *[]*models.Job) *ExportService {
+	return NewExportService(repo, jobs.NewQueue(&repositories.MockJobRepository{
+		EnqueueFunc: func(ctx context.Context, job *models.Job) error {
+			job.ID = "job-1"
+			*queued = append(*queued, job)
+			return nil
+		},
+	}), nil)
+}
+
+func TestRequestAccountExport_GeneratesArchive(t *testing.T) {
+	var completedID string
+	var completedArchive []byte
+	mockRepo := &repositories.MockExportRepository{
+		BuildAccountArchiveFunc: func(ctx context.Context, userID string) ([]byte, error) {
+			return []byte(`{"user_id":"` + userID + `"}`), nil
+		},
+		CompleteAccountExportFunc: func(ctx context.Context, id string, archive []byte, expiresAt time.Time) error {
+			completedID, completedArchive = id, archive
+			return nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestExportService(mockRepo, &queued)
+
+	export, err := service.RequestAccountExport(context.Background(), "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if export.Status != models.AccountExportStatusPending {
+		t.Errorf("Expected status pending, got %s", export.Status)
+	}
+	if len(queued) != 1 || queued[0].Kind != models.JobKindAccountExport || queued[0].MaxAttempts != accountExportAttempts {
+		t.Fatalf("Expected an account export job, got %+v", queued)
+	}
+
+	job := queued[0]
+	job.Attempts = 1
+	if err := service.generateAccountExport(context.Background(), job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if completedID != export.ID || string(completedArchive) != `{"user_id":"user-123"}` {
+		t.Errorf("Expected archive to be stored on export %s, got %s on %s", export.ID, completedArchive, completedID)
+	}
+}
+
+func TestRequestAccountExport_ReusesPending(t *testing.T) {
+	mockRepo := &repositories.MockExportRepository{
+		FindPendingAccountExportFunc: func(ctx context.Context, userID string, since time.Time) (*models.AccountExport, error) {
+			return &models.AccountExport{ID: "export-1", UserID: userID, Status: models.AccountExportStatusPending}, nil
+		},
+		CreateAccountExportFunc: func(ctx context.Context, export *models.AccountExport) error {
+			t.Fatal("Expected no new export while one is pending")
+			return nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestExportService(mockRepo, &queued)
+
+	export, err := service.RequestAccountExport(context.Background(), "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if export.ID != "export-1" {
+		t.Errorf("Expected pending export-1, got %s", export.ID)
+	}
+}
+
+func TestGenerateAccountExport_RecordsFailureOnLastAttempt(t *testing.T) {
+	failed := false
+	mockRepo := &repositories.MockExportRepository{
+		BuildAccountArchiveFunc: func(ctx context.Context, userID string) ([]byte, error) {
+			return nil, errors.New("statement timeout")
+		},
+		FailAccountExportFunc: func(ctx context.Context, id string, reason string) error {
+			failed = true
+			return nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestExportService(mockRepo, &queued)
+
+	if _, err := service.RequestAccountExport(context.Background(), "user-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	job := queued[0]
+
+	job.Attempts = 1
+	if err := service.generateAccountExport(context.Background(), job); err == nil {
+		t.Fatal("Expected the attempt to fail")
+	}
+	if failed {
+		t.Error("Expected export to stay pending while attempts remain")
+	}
+
+	job.Attempts = accountExportAttempts
+	if err := service.generateAccountExport(context.Background(), job); err == nil {
+		t.Fatal("Expected the attempt to fail")
+	}
+	if !failed {
+		t.Error("Expected export to be marked as failed")
+	}
+}
+
+func TestDownloadAccountExport(t *testing.T) {
+	expiresAt := time.Date(2026, 6, 8, 0, 0, 0, 0, time.UTC)
+	exports := map[string]*models.AccountExport{
+		"ready":   {ID: "ready", UserID: "user-123", Status: models.AccountExportStatusReady, ExpiresAt: &expiresAt},
+		"pending": {ID: "pending", UserID: "user-123", Status: models.AccountExportStatusPending},
+		"other":   {ID: "other", UserID: "other-user", Status: models.AccountExportStatusReady},
+	}
+	mockRepo := &repositories.MockExportRepository{
+		FindAccountExportFunc: func(ctx context.Context, id string) (*models.AccountExport, error) {
+			return exports[id], nil
+		},
+		FindAccountArchiveFunc: func(ctx context.Context, id string) ([]byte, error) {
+			return []byte(`{}`), nil
+		},
+	}
+
+	tests := []struct {
+		name    string
+		id      string
+		now     time.Time
+		wantErr error
+	}{
+		{"ready", "ready", time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), nil},
+		{"expired", "ready", time.Date(2026, 6, 9, 0, 0, 0, 0, time.UTC), ErrExportExpired},
+		{"pending", "pending", time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), ErrExportNotReady},
+		{"other user's export", "other", time.Date(2026, 6, 2, 0, 0, 0, 0, time.UTC), ErrExportNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var queued []*models.Job
+			service := newTestExportService(mockRepo, &queued)
+			service.now = func() time.Time { return tt.now }
+
+			_, _, err := service.DownloadAccountExport(context.Background(), tt.id, "user-123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
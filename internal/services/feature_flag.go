@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/featureflags"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInvalidFeatureFlag          = domainerr.New(domainerr.Validation, "flag names are lowercase letters, digits and underscores, starting with a letter")
+	ErrFeatureFlagNotFound         = domainerr.New(domainerr.NotFound, "feature flag not found")
+	ErrFeatureFlagOverrideNotFound = domainerr.New(domainerr.NotFound, "user has no override for this flag")
+	ErrFeatureDisabled             = domainerr.New(domainerr.NotFound, "feature not available")
+)
+
+// flagOverride is how an override is audited
+type flagOverride struct {
+	Enabled bool `json:"enabled"`
+}
+
+// FeatureFlagService lets admins roll features out at runtime and users see which they get
+type FeatureFlagService struct {
+	repo  repositories.FeatureFlagRepository
+	flags *featureflags.Flags
+	audit *AuditLogService
+}
+
+// NewFeatureFlagService creates a new feature flag service editing the flags read by flags;
+// edits are audited unless audit is nil
+func NewFeatureFlagService(repo repositories.FeatureFlagRepository, flags *featureflags.Flags, audit *AuditLogService) *FeatureFlagService {
+	return &FeatureFlagService{repo: repo, flags: flags, audit: audit}
+}
+
+// ListFlags retrieves every flag as currently stored, for admins
+func (s *FeatureFlagService) ListFlags(ctx context.Context) ([]*models.FeatureFlag, error) {
+	if err := s.flags.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	return s.flags.All(ctx), nil
+}
+
+// UserFlags returns whether each flag is on for the user
+func (s *FeatureFlagService) UserFlags(ctx context.Context, userID string) map[string]bool {
+	return s.flags.For(ctx, userID)
+}
+
+// UpdateFlag turns a flag on for a percentage of users (all when omitted) or off, storing
+// it if it was a default or didn't exist. The description is kept when none is given.
+func (s *FeatureFlagService) UpdateFlag(ctx context.Context, adminID string, name string, req *models.UpdateFeatureFlagRequest) (*models.FeatureFlag, error) {
+	if !featureflags.ValidName(name) {
+		return nil, ErrInvalidFeatureFlag
+	}
+	if err := s.flags.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+
+	flag := &models.FeatureFlag{
+		Name:           name,
+		Description:    normalizeText(req.Description),
+		Enabled:        *req.Enabled,
+		RolloutPercent: 100,
+	}
+	if req.RolloutPercent != nil {
+		flag.RolloutPercent = *req.RolloutPercent
+	}
+
+	action, before := models.AuditActionCreate, (*models.FeatureFlag)(nil)
+	if current := s.flags.Get(ctx, name); current != nil {
+		if flag.Description == "" {
+			flag.Description = current.Description
+		}
+		if current.Stored {
+			action, before = models.AuditActionUpdate, flagSettings(current)
+		}
+	}
+
+	flag, err := audited(ctx, s.audit, adminID, action, models.AuditResourceFeatureFlag, name, before, func() (*models.FeatureFlag, error) {
+		if err := s.repo.Upsert(ctx, flag); err != nil {
+			return nil, fmt.Errorf("failed to update feature flag: %w", err)
+		}
+		return flag, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx)
+	if updated := s.flags.Get(ctx, name); updated != nil && updated.Stored {
+		return updated, nil
+	}
+	return flag, nil
+}
+
+// SetOverride turns a flag on or off for one user whatever its rollout
+func (s *FeatureFlagService) SetOverride(ctx context.Context, adminID string, name string, userID string, enabled bool) (*models.FeatureFlag, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, ErrUserNotFound
+	}
+	if err := s.flags.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	flag := s.flags.Get(ctx, name)
+	if flag == nil {
+		return nil, ErrFeatureFlagNotFound
+	}
+	if !flag.Stored {
+		// Overrides belong to stored flags, so a default is stored as it stands first
+		stored := flagSettings(flag)
+		if err := s.repo.Upsert(ctx, stored); err != nil {
+			return nil, fmt.Errorf("failed to store feature flag: %w", err)
+		}
+	}
+
+	action, before := models.AuditActionCreate, (*flagOverride)(nil)
+	if current, ok := flag.Overrides[userID]; ok {
+		action, before = models.AuditActionUpdate, &flagOverride{Enabled: current}
+	}
+
+	_, err := audited(ctx, s.audit, adminID, action, models.AuditResourceFeatureFlagUser, flagUserResourceID(name, userID), before, func() (*flagOverride, error) {
+		if err := s.repo.SetOverride(ctx, name, userID, enabled); err != nil {
+			if errors.Is(err, repositories.ErrReferenced) {
+				return nil, ErrUserNotFound
+			}
+			return nil, fmt.Errorf("failed to set feature flag override: %w", err)
+		}
+		return &flagOverride{Enabled: enabled}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.reload(ctx)
+	return s.flags.Get(ctx, name), nil
+}
+
+// DeleteOverride returns a user to a flag's rollout
+func (s *FeatureFlagService) DeleteOverride(ctx context.Context, adminID string, name string, userID string) error {
+	if _, err := uuid.Parse(userID); err != nil {
+		return ErrFeatureFlagOverrideNotFound
+	}
+	if err := s.flags.Reload(ctx); err != nil {
+		return fmt.Errorf("failed to load feature flags: %w", err)
+	}
+	before := &flagOverride{}
+	if flag := s.flags.Get(ctx, name); flag != nil {
+		before.Enabled = flag.Overrides[userID]
+	}
+
+	_, err := audited(ctx, s.audit, adminID, models.AuditActionDelete, models.AuditResourceFeatureFlagUser, flagUserResourceID(name, userID), before, func() (*flagOverride, error) {
+		if err := s.repo.DeleteOverride(ctx, name, userID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrFeatureFlagOverrideNotFound
+			}
+			return nil, fmt.Errorf("failed to delete feature flag override: %w", err)
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.reload(ctx)
+	return nil
+}
+
+// reload applies an edit to this instance right away; others pick it up within
+// featureflags.RefreshInterval
+func (s *FeatureFlagService) reload(ctx context.Context) {
+	if err := s.flags.Reload(ctx); err != nil {
+		log.Printf("Failed to reload feature flags after an edit: %v", err)
+	}
+}
+
+// flagSettings copies a flag without its overrides, which are audited on their own
+func flagSettings(flag *models.FeatureFlag) *models.FeatureFlag {
+	return &models.FeatureFlag{
+		Name:           flag.Name,
+		Description:    flag.Description,
+		Enabled:        flag.Enabled,
+		RolloutPercent: flag.RolloutPercent,
+		Stored:         flag.Stored,
+		UpdatedAt:      flag.UpdatedAt,
+	}
+}
+
+func flagUserResourceID(name string, userID string) string {
+	return name + "/" + userID
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/featureflags"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+const flagUser = "5b0c8f4e-8a43-4c2b-9d9e-1f6a2b3c4d5e"
+
+// storedFlags keeps the flags written through the mock repository
+func storedFlags() (*repositories.MockFeatureFlagRepository, map[string]*models.FeatureFlag) {
+	stored := map[string]*models.FeatureFlag{}
+	repo := &repositories.MockFeatureFlagRepository{
+		LoadFunc: func(ctx context.Context) ([]*models.FeatureFlag, error) {
+			flags := []*models.FeatureFlag{}
+			for _, flag := range stored {
+				copied := *flag
+				flags = append(flags, &copied)
+			}
+			return flags, nil
+		},
+		UpsertFunc: func(ctx context.Context, flag *models.FeatureFlag) error {
+			flag.Stored = true
+			copied := *flag
+			if current, ok := stored[flag.Name]; ok {
+				copied.Overrides = current.Overrides
+			}
+			stored[flag.Name] = &copied
+			return nil
+		},
+		SetOverrideFunc: func(ctx context.Context, name string, userID string, enabled bool) error {
+			flag, ok := stored[name]
+			if !ok {
+				return repositories.ErrReferenced
+			}
+			if flag.Overrides == nil {
+				flag.Overrides = map[string]bool{}
+			}
+			flag.Overrides[userID] = enabled
+			return nil
+		},
+	}
+	return repo, stored
+}
+
+func TestUpdateFlag_GatesRecommendations(t *testing.T) {
+	ctx := context.Background()
+	repo, _ := storedFlags()
+	audit, entries := recordingAudit()
+	flags := featureflags.New(repo, nil)
+	service := NewFeatureFlagService(repo, flags, audit)
+	recommendations := NewRecommendationService(&repositories.MockRecommendationRepository{}, &repositories.MockGoalRepository{}, &repositories.MockInjuryRepository{}, nil, flags)
+
+	off := false
+	flag, err := service.UpdateFlag(ctx, "admin-1", featureflags.Recommendations, &models.UpdateFeatureFlagRequest{Enabled: &off})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !flag.Stored || flag.Enabled || flag.Description == "" {
+		t.Errorf("Expected the flag stored off with its builtin description, got %+v", flag)
+	}
+	if _, err := recommendations.RecommendWorkout(ctx, flagUser, "", 0); !errors.Is(err, ErrFeatureDisabled) {
+		t.Errorf("Expected ErrFeatureDisabled, got %v", err)
+	}
+	if len(*entries) != 1 || (*entries)[0].Action != models.AuditActionCreate || (*entries)[0].ResourceID != featureflags.Recommendations {
+		t.Errorf("Expected the flag creation audited, got %+v", *entries)
+	}
+}
+
+func TestUpdateFlag_Validation(t *testing.T) {
+	repo, _ := storedFlags()
+	service := NewFeatureFlagService(repo, featureflags.New(repo, nil), nil)
+
+	on := true
+	if _, err := service.UpdateFlag(context.Background(), "admin-1", "New-Feed", &models.UpdateFeatureFlagRequest{Enabled: &on}); !errors.Is(err, ErrInvalidFeatureFlag) {
+		t.Errorf("Expected ErrInvalidFeatureFlag, got %v", err)
+	}
+}
+
+func TestSetOverride_StoresDefaultFlagFirst(t *testing.T) {
+	ctx := context.Background()
+	repo, stored := storedFlags()
+	audit, entries := recordingAudit()
+	flags := featureflags.New(repo, []*models.FeatureFlag{{Name: "new_feed", Enabled: false, RolloutPercent: 100}})
+	service := NewFeatureFlagService(repo, flags, audit)
+
+	flag, err := service.SetOverride(ctx, "admin-1", "new_feed", flagUser, true)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if stored["new_feed"] == nil || !flag.Overrides[flagUser] {
+		t.Errorf("Expected the default stored with the override, got %+v", flag)
+	}
+	if !flags.Enabled(ctx, "new_feed", flagUser) || flags.Enabled(ctx, "new_feed", "someone-else") {
+		t.Error("Expected the flag on for the overridden user only")
+	}
+	if len(*entries) != 1 || (*entries)[0].ResourceID != "new_feed/"+flagUser {
+		t.Errorf("Expected the override audited, got %+v", *entries)
+	}
+
+	if _, err := service.SetOverride(ctx, "admin-1", "unknown", flagUser, true); !errors.Is(err, ErrFeatureFlagNotFound) {
+		t.Errorf("Expected ErrFeatureFlagNotFound, got %v", err)
+	}
+	if _, err := service.SetOverride(ctx, "admin-1", "new_feed", "not-a-uuid", true); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestDeleteOverride_NotFound(t *testing.T) {
+	repo, _ := storedFlags()
+	repo.DeleteOverrideFunc = func(ctx context.Context, name string, userID string) error {
+		return pgx.ErrNoRows
+	}
+	service := NewFeatureFlagService(repo, featureflags.New(repo, nil), nil)
+
+	if err := service.DeleteOverride(context.Background(), "admin-1", "new_feed", flagUser); !errors.Is(err, ErrFeatureFlagOverrideNotFound) {
+		t.Errorf("Expected ErrFeatureFlagOverrideNotFound, got %v", err)
+	}
+}
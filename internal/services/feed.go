@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrActivityNotFound = domainerr.New(domainerr.NotFound, "activity not found")
+
+const (
+	defaultFeedPageSize = 20
+	maxFeedPageSize     = 100
+)
+
+// FeedService handles users' activity feeds
+// Completed sessions and personal records are recorded as activity by database triggers,
+// whichever client wrote them, and each feed is read from the events of followed users.
+type FeedService struct {
+	repo    repositories.FeedRepository
+	follows repositories.FollowRepository
+}
+
+// NewFeedService creates a new feed service
+func NewFeedService(repo repositories.FeedRepository, follows repositories.FollowRepository) *FeedService {
+	return &FeedService{repo: repo, follows: follows}
+}
+
+// GetFeed returns a page of the activity of the users the user follows, newest first.
+// cursor is empty for the first page, then the previous page's next_cursor. limit outside
+// 1..100 falls back to the default of 20.
+func (s *FeedService) GetFeed(ctx context.Context, userID string, limit int, cursor string) (*models.FeedPage, error) {
+	if limit <= 0 || limit > maxFeedPageSize {
+		limit = defaultFeedPageSize
+	}
+	filter := models.FeedFilter{Limit: limit + 1} // One more tells whether a next page exists
+	if cursor != "" {
+		after, err := decodeSessionCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	events, err := s.repo.Feed(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	page := &models.FeedPage{Events: events}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		last := page.Events[limit-1]
+		page.NextCursor = encodeSessionCursor(&models.SessionCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// React likes an activity event for the user, who must be able to see its owner's profile.
+// Liking an event again keeps the single like.
+func (s *FeedService) React(ctx context.Context, userID string, eventID string) (*models.ReactionSummary, error) {
+	if err := s.visibleEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.React(ctx, eventID, userID); err != nil {
+		if errors.Is(err, repositories.ErrReferenced) {
+			return nil, ErrActivityNotFound
+		}
+		return nil, fmt.Errorf("failed to react: %w", err)
+	}
+	return s.reactions(ctx, userID, eventID)
+}
+
+// Unreact removes the user's like of an activity event; there may be none
+func (s *FeedService) Unreact(ctx context.Context, userID string, eventID string) (*models.ReactionSummary, error) {
+	if err := s.visibleEvent(ctx, userID, eventID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Unreact(ctx, eventID, userID); err != nil {
+		return nil, fmt.Errorf("failed to remove reaction: %w", err)
+	}
+	return s.reactions(ctx, userID, eventID)
+}
+
+// visibleEvent checks the event exists and the user may see it. Events of profiles hidden
+// from the user are reported as missing, like their feeds leave them out.
+func (s *FeedService) visibleEvent(ctx context.Context, userID string, eventID string) error {
+	ownerID, err := s.repo.FindEventOwner(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrActivityNotFound
+		}
+		return fmt.Errorf("failed to get activity: %w", err)
+	}
+	if ownerID == userID {
+		return nil
+	}
+	visible, err := s.follows.CanView(ctx, userID, ownerID)
+	if err != nil {
+		return fmt.Errorf("failed to check profile visibility: %w", err)
+	}
+	if !visible {
+		return ErrActivityNotFound
+	}
+	return nil
+}
+
+func (s *FeedService) reactions(ctx context.Context, userID string, eventID string) (*models.ReactionSummary, error) {
+	summary, err := s.repo.Reactions(ctx, eventID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reactions: %w", err)
+	}
+	return summary, nil
+}
@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGetFeed_KeysetPages(t *testing.T) {
+	base := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	activity := make([]*models.ActivityEvent, 4)
+	for i := range activity {
+		activity[i] = &models.ActivityEvent{
+			ID:        fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", 4-i),
+			Type:      models.ActivitySessionCompleted,
+			CreatedAt: base.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	var filters []models.FeedFilter
+	mockRepo := &repositories.MockFeedRepository{
+		FeedFunc: func(ctx context.Context, userID string, filter models.FeedFilter) ([]*models.ActivityEvent, error) {
+			filters = append(filters, filter)
+			start := 0
+			if filter.After != nil {
+				for start < len(activity) && !activity[start].CreatedAt.Before(filter.After.CreatedAt) {
+					start++
+				}
+			}
+			return activity[start:min(start+filter.Limit, len(activity))], nil
+		},
+	}
+	service := NewFeedService(mockRepo, &repositories.MockFollowRepository{})
+
+	first, err := service.GetFeed(context.Background(), "user-123", 2, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(first.Events) != 2 || first.NextCursor == "" || filters[0].Limit != 3 {
+		t.Fatalf("Expected 2 events and a next cursor from one extra read, got %+v", first)
+	}
+
+	second, err := service.GetFeed(context.Background(), "user-123", 2, first.NextCursor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if after := filters[1].After; after == nil || after.ID != activity[1].ID {
+		t.Errorf("Expected to continue after the second event, got %+v", after)
+	}
+	if len(second.Events) != 2 || second.Events[0].ID != activity[2].ID || second.NextCursor != "" {
+		t.Errorf("Expected the last two events and no next cursor, got %+v", second)
+	}
+}
+
+func TestGetFeed_InvalidCursor(t *testing.T) {
+	service := NewFeedService(&repositories.MockFeedRepository{}, &repositories.MockFollowRepository{})
+
+	if _, err := service.GetFeed(context.Background(), "user-123", 0, "not-a-cursor"); !errors.Is(err, ErrInvalidSessionCursor) {
+		t.Errorf("Expected ErrInvalidSessionCursor, got %v", err)
+	}
+}
+
+func TestReact_Visibility(t *testing.T) {
+	tests := []struct {
+		name    string
+		ownerID string
+		visible bool
+		wantErr error
+	}{
+		{"own activity", "user-123", false, nil},
+		{"visible profile", "user-456", true, nil},
+		{"hidden profile", "user-456", false, ErrActivityNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reacted := false
+			repo := &repositories.MockFeedRepository{
+				FindEventOwnerFunc: func(ctx context.Context, eventID string) (string, error) {
+					return tt.ownerID, nil
+				},
+				ReactFunc: func(ctx context.Context, eventID string, userID string) error {
+					reacted = true
+					return nil
+				},
+			}
+			follows := &repositories.MockFollowRepository{
+				CanViewFunc: func(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+					return tt.visible, nil
+				},
+			}
+			service := NewFeedService(repo, follows)
+
+			summary, err := service.React(context.Background(), "user-123", "event-1")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if reacted != (tt.wantErr == nil) {
+				t.Errorf("Expected reaction stored: %v, got %v", tt.wantErr == nil, reacted)
+			}
+			if err == nil && summary.ActivityID != "event-1" {
+				t.Errorf("Expected the event's reactions, got %+v", summary)
+			}
+		})
+	}
+}
+
+func TestReact_MissingEvent(t *testing.T) {
+	service := NewFeedService(&repositories.MockFeedRepository{}, &repositories.MockFollowRepository{})
+
+	if _, err := service.React(context.Background(), "user-123", "event-1"); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("Expected ErrActivityNotFound, got %v", err)
+	}
+	if _, err := service.Unreact(context.Background(), "user-123", "event-1"); !errors.Is(err, ErrActivityNotFound) {
+		t.Errorf("Expected ErrActivityNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrCannotFollowSelf      = domainerr.New(domainerr.Validation, "cannot follow yourself")
+	ErrProfilePrivate        = domainerr.New(domainerr.Forbidden, "this profile is private")
+	ErrProfileNotVisible     = domainerr.New(domainerr.Forbidden, "profile is not visible to you")
+	ErrFollowNotFound        = domainerr.New(domainerr.NotFound, "follow not found")
+	ErrFollowRequestNotFound = domainerr.New(domainerr.NotFound, "follow request not found")
+)
+
+// FollowService handles users following each other under their profiles' privacy
+// Who may see a profile is decided in the database (can_view_profile), so social features
+// built on follows share one rule.
+type FollowService struct {
+	repo     repositories.FollowRepository
+	profiles repositories.ProfileRepository
+}
+
+// NewFollowService creates a new follow service
+func NewFollowService(repo repositories.FollowRepository, profiles repositories.ProfileRepository) *FollowService {
+	return &FollowService{repo: repo, profiles: profiles}
+}
+
+// Follow follows another user: right away if their profile is public, as a request they
+// have to accept if it is followers-only. Private profiles can't be followed. Following
+// someone again returns the existing follow.
+func (s *FollowService) Follow(ctx context.Context, followerID string, followeeID string) (*models.Follow, error) {
+	if followerID == followeeID {
+		return nil, ErrCannotFollowSelf
+	}
+
+	profile, err := s.profiles.FindProfile(ctx, followeeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	follow := &models.Follow{FollowerID: followerID, FolloweeID: followeeID, Status: models.FollowStatusAccepted}
+	switch profile.Privacy {
+	case models.ProfilePrivacyPrivate:
+		return nil, ErrProfilePrivate
+	case models.ProfilePrivacyFollowers:
+		follow.Status = models.FollowStatusPending
+	}
+
+	if err := s.repo.Create(ctx, follow); err != nil {
+		if errors.Is(err, repositories.ErrReferenced) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to follow user: %w", err)
+	}
+	return follow, nil
+}
+
+// Unfollow stops following a user, or withdraws a pending request
+func (s *FollowService) Unfollow(ctx context.Context, followerID string, followeeID string) error {
+	if err := s.repo.Delete(ctx, followerID, followeeID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFollowNotFound
+		}
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+	return nil
+}
+
+// ListFollowers retrieves a user's accepted followers, if the viewer may see their profile
+func (s *FollowService) ListFollowers(ctx context.Context, viewerID string, userID string) ([]*models.Follow, error) {
+	if err := s.checkVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+
+	follows, err := s.repo.FindFollowers(ctx, userID, models.FollowStatusAccepted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list followers: %w", err)
+	}
+	return follows, nil
+}
+
+// ListFollowing retrieves who a user follows, if the viewer may see their profile. Users
+// looking at their own list also see the requests they are waiting on.
+func (s *FollowService) ListFollowing(ctx context.Context, viewerID string, userID string) ([]*models.Follow, error) {
+	if err := s.checkVisible(ctx, viewerID, userID); err != nil {
+		return nil, err
+	}
+
+	status := models.FollowStatusAccepted
+	if viewerID == userID {
+		status = ""
+	}
+	follows, err := s.repo.FindFollowing(ctx, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list followed users: %w", err)
+	}
+	return follows, nil
+}
+
+// ListFollowRequests retrieves the requests waiting for the user's approval
+func (s *FollowService) ListFollowRequests(ctx context.Context, userID string) ([]*models.Follow, error) {
+	follows, err := s.repo.FindFollowers(ctx, userID, models.FollowStatusPending)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list follow requests: %w", err)
+	}
+	return follows, nil
+}
+
+// AcceptFollowRequest lets a user who asked to follow the user in
+func (s *FollowService) AcceptFollowRequest(ctx context.Context, userID string, followerID string) (*models.Follow, error) {
+	follow, err := s.repo.Accept(ctx, followerID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrFollowRequestNotFound
+		}
+		return nil, fmt.Errorf("failed to accept follow request: %w", err)
+	}
+	return follow, nil
+}
+
+// RemoveFollower removes one of the user's followers, or declines their request
+func (s *FollowService) RemoveFollower(ctx context.Context, userID string, followerID string) error {
+	if err := s.repo.Delete(ctx, followerID, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrFollowNotFound
+		}
+		return fmt.Errorf("failed to remove follower: %w", err)
+	}
+	return nil
+}
+
+// checkVisible returns an error unless the viewer may see the user's profile
+func (s *FollowService) checkVisible(ctx context.Context, viewerID string, userID string) error {
+	visible, err := s.repo.CanView(ctx, viewerID, userID)
+	if err != nil {
+		return fmt.Errorf("failed to check profile privacy: %w", err)
+	}
+	if !visible {
+		return ErrProfileNotVisible
+	}
+	return nil
+}
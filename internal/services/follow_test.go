@@ -0,0 +1,102 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func profilesWithPrivacy(privacy string) *repositories.MockProfileRepository {
+	return &repositories.MockProfileRepository{
+		FindProfileFunc: func(ctx context.Context, userID string) (*models.Profile, error) {
+			return &models.Profile{UserID: userID, Timezone: "UTC", Privacy: privacy}, nil
+		},
+	}
+}
+
+func TestFollow_Privacy(t *testing.T) {
+	tests := []struct {
+		privacy    string
+		wantStatus string
+		wantErr    error
+	}{
+		{models.ProfilePrivacyPublic, models.FollowStatusAccepted, nil},
+		{models.ProfilePrivacyFollowers, models.FollowStatusPending, nil},
+		{models.ProfilePrivacyPrivate, "", ErrProfilePrivate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.privacy, func(t *testing.T) {
+			service := NewFollowService(&repositories.MockFollowRepository{}, profilesWithPrivacy(tt.privacy))
+
+			follow, err := service.Follow(context.Background(), "user-123", "user-456")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && follow.Status != tt.wantStatus {
+				t.Errorf("Expected a %s follow, got %s", tt.wantStatus, follow.Status)
+			}
+		})
+	}
+}
+
+func TestFollow_Self(t *testing.T) {
+	service := NewFollowService(&repositories.MockFollowRepository{}, &repositories.MockProfileRepository{})
+
+	if _, err := service.Follow(context.Background(), "user-123", "user-123"); !errors.Is(err, ErrCannotFollowSelf) {
+		t.Errorf("Expected ErrCannotFollowSelf, got %v", err)
+	}
+}
+
+func TestFollow_UnknownUser(t *testing.T) {
+	repo := &repositories.MockFollowRepository{
+		CreateFunc: func(ctx context.Context, follow *models.Follow) error {
+			return fmt.Errorf("%w: foreign key violation", repositories.ErrReferenced)
+		},
+	}
+	service := NewFollowService(repo, &repositories.MockProfileRepository{})
+
+	if _, err := service.Follow(context.Background(), "user-123", "user-456"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestListFollowing_Visibility(t *testing.T) {
+	var statuses []string
+	repo := &repositories.MockFollowRepository{
+		FindFollowingFunc: func(ctx context.Context, followerID string, status string) ([]*models.Follow, error) {
+			statuses = append(statuses, status)
+			return []*models.Follow{}, nil
+		},
+	}
+	service := NewFollowService(repo, &repositories.MockProfileRepository{})
+
+	if _, err := service.ListFollowing(context.Background(), "user-456", "user-123"); !errors.Is(err, ErrProfileNotVisible) {
+		t.Errorf("Expected ErrProfileNotVisible, got %v", err)
+	}
+	if _, err := service.ListFollowing(context.Background(), "user-123", "user-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(statuses) != 1 || statuses[0] != "" {
+		t.Errorf("Expected the user's own list to include pending requests, got statuses %q", statuses)
+	}
+}
+
+func TestAcceptFollowRequest_NotPending(t *testing.T) {
+	repo := &repositories.MockFollowRepository{
+		AcceptFunc: func(ctx context.Context, followerID string, followeeID string) (*models.Follow, error) {
+			return nil, pgx.ErrNoRows
+		},
+	}
+	service := NewFollowService(repo, &repositories.MockProfileRepository{})
+
+	if _, err := service.AcceptFollowRequest(context.Background(), "user-123", "user-456"); !errors.Is(err, ErrFollowRequestNotFound) {
+		t.Errorf("Expected ErrFollowRequestNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrGoalNotFound         = domainerr.New(domainerr.NotFound, "goal not found")
+	ErrGoalExerciseRequired = domainerr.New(domainerr.Validation, "exercise_id is required for e1rm goals and not allowed for other kinds")
+	ErrGoalNeedsWeighIn     = domainerr.New(domainerr.Validation, "log your body weight before setting a body weight goal")
+	ErrInvalidGoalTarget    = domainerr.New(domainerr.Validation, "target_value must differ from the starting body weight, and be a whole number of sessions up to 14 for weekly_sessions")
+)
+
+// maxWeeklySessionsGoal caps a weekly_sessions target at two sessions a day
+const maxWeeklySessionsGoal = 14
+
+// GoalService handles users' goals
+// Progress is computed by the database from measurements and logs when goals are read, and
+// database triggers mark goals achieved and send the goal.achieved notification.
+type GoalService struct {
+	repo      repositories.GoalRepository
+	exercises repositories.ExerciseRepository
+	audit     *AuditLogService
+}
+
+// NewGoalService creates a new goal service; writes are audited unless audit is nil
+func NewGoalService(repo repositories.GoalRepository, exercises repositories.ExerciseRepository, audit *AuditLogService) *GoalService {
+	return &GoalService{repo: repo, exercises: exercises, audit: audit}
+}
+
+// ListGoals retrieves the user's goals with their progress
+func (s *GoalService) ListGoals(ctx context.Context, userID string) ([]*models.Goal, error) {
+	goals, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+
+	for _, g := range goals {
+		setGoalProgress(g)
+	}
+	return goals, nil
+}
+
+// GetGoal retrieves one of the user's goals with its progress
+func (s *GoalService) GetGoal(ctx context.Context, userID string, id string) (*models.Goal, error) {
+	goal, err := s.repo.FindByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrGoalNotFound
+		}
+		return nil, fmt.Errorf("failed to get goal: %w", err)
+	}
+
+	setGoalProgress(goal)
+	return goal, nil
+}
+
+// CreateGoal sets a goal, starting from the user's current value
+// Body weight goals need a weigh-in to tell losing from gaining; e1rm goals start from the
+// lift's best so far, or 0; weekly goals start from 0 every week.
+func (s *GoalService) CreateGoal(ctx context.Context, userID string, req *models.CreateGoalRequest) (*models.Goal, error) {
+	if (req.Kind == models.GoalKindE1RM) != (req.ExerciseID != nil) {
+		return nil, ErrGoalExerciseRequired
+	}
+
+	if req.ExerciseID != nil {
+		exercise, err := s.exercises.FindByID(ctx, *req.ExerciseID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrExerciseNotFound
+			}
+			return nil, fmt.Errorf("failed to get exercise: %w", err)
+		}
+		if !exercise.IsPublic && exercise.UserID != userID {
+			return nil, ErrExerciseNotFound
+		}
+	}
+
+	var start float64
+	if req.Kind != models.GoalKindWeeklySessions {
+		current, err := s.repo.CurrentValue(ctx, userID, req.Kind, req.ExerciseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get current value: %w", err)
+		}
+		if current == nil && req.Kind == models.GoalKindBodyWeight {
+			return nil, ErrGoalNeedsWeighIn
+		}
+		if current != nil {
+			start = *current
+		}
+	}
+
+	if !validGoalTarget(req.Kind, req.TargetValue, start) {
+		return nil, ErrInvalidGoalTarget
+	}
+
+	goal := &models.Goal{
+		UserID:      userID,
+		Kind:        req.Kind,
+		ExerciseID:  req.ExerciseID,
+		TargetValue: req.TargetValue,
+		StartValue:  start,
+	}
+	_, err := audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceGoal, "", nil, func() (*models.Goal, error) {
+		if err := s.repo.Create(ctx, goal); err != nil {
+			return nil, fmt.Errorf("failed to create goal: %w", err)
+		}
+		return goal, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Read it back for its progress, and in case it was met on creation
+	return s.GetGoal(ctx, userID, goal.ID)
+}
+
+// UpdateGoal changes a goal's target; progress still counts from where the goal started
+func (s *GoalService) UpdateGoal(ctx context.Context, userID string, id string, req *models.UpdateGoalRequest) (*models.Goal, error) {
+	goal, err := s.GetGoal(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if !validGoalTarget(goal.Kind, req.TargetValue, goal.StartValue) {
+		return nil, ErrInvalidGoalTarget
+	}
+
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceGoal, id, goal, func() (*models.Goal, error) {
+		if err := s.repo.UpdateTarget(ctx, userID, id, req.TargetValue); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrGoalNotFound
+			}
+			return nil, fmt.Errorf("failed to update goal: %w", err)
+		}
+		return s.GetGoal(ctx, userID, id)
+	})
+}
+
+// DeleteGoal removes one of the user's goals
+func (s *GoalService) DeleteGoal(ctx context.Context, userID string, id string) error {
+	goal, err := s.GetGoal(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceGoal, id, goal, func() (*models.Goal, error) {
+		if err := s.repo.Delete(ctx, userID, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrGoalNotFound
+			}
+			return nil, fmt.Errorf("failed to delete goal: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// validGoalTarget reports whether target can be aimed at from start
+func validGoalTarget(kind string, target, start float64) bool {
+	switch kind {
+	case models.GoalKindBodyWeight:
+		return target != start
+	case models.GoalKindWeeklySessions:
+		return target == math.Trunc(target) && target <= maxWeeklySessionsGoal
+	}
+	return true
+}
+
+// setGoalProgress sets how far the goal has come from its start towards its target
+func setGoalProgress(goal *models.Goal) {
+	switch {
+	case goal.Achieved:
+		goal.Percentage = 100
+	case goal.CurrentValue == nil || goal.TargetValue == goal.StartValue:
+		goal.Percentage = 0
+	default:
+		pct := (*goal.CurrentValue - goal.StartValue) / (goal.TargetValue - goal.StartValue) * 100
+		goal.Percentage = round2(math.Max(0, math.Min(100, pct)))
+	}
+}
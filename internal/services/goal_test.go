@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestCreateGoal_BodyWeightStartsFromLatestWeighIn(t *testing.T) {
+	var created *models.Goal
+	mockRepo := &repositories.MockGoalRepository{
+		CurrentValueFunc: func(ctx context.Context, userID string, kind string, exerciseID *string) (*float64, error) {
+			weight := 82.5
+			return &weight, nil
+		},
+		CreateFunc: func(ctx context.Context, goal *models.Goal) error {
+			goal.ID = "goal-1"
+			created = goal
+			return nil
+		},
+		FindByIDFunc: func(ctx context.Context, userID string, id string) (*models.Goal, error) {
+			return created, nil
+		},
+	}
+
+	service := NewGoalService(mockRepo, &repositories.MockExerciseRepository{}, nil)
+
+	goal, err := service.CreateGoal(context.Background(), "user-123", &models.CreateGoalRequest{
+		Kind:        models.GoalKindBodyWeight,
+		TargetValue: 78,
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if goal.StartValue != 82.5 || goal.UserID != "user-123" {
+		t.Errorf("Expected start 82.5 for user-123, got %v for %q", goal.StartValue, goal.UserID)
+	}
+}
+
+func TestCreateGoal_Validation(t *testing.T) {
+	exerciseID := "ex-1"
+	privateID := "ex-private"
+	exercises := &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			return &models.Exercise{ID: id, UserID: "someone-else", IsPublic: id == exerciseID}, nil
+		},
+	}
+	weight := 80.0
+
+	tests := []struct {
+		name    string
+		req     *models.CreateGoalRequest
+		current *float64
+		wantErr error
+	}{
+		{"e1rm without exercise", &models.CreateGoalRequest{Kind: models.GoalKindE1RM, TargetValue: 100}, nil, ErrGoalExerciseRequired},
+		{"exercise on weekly goal", &models.CreateGoalRequest{Kind: models.GoalKindWeeklySessions, ExerciseID: &exerciseID, TargetValue: 3}, nil, ErrGoalExerciseRequired},
+		{"private exercise", &models.CreateGoalRequest{Kind: models.GoalKindE1RM, ExerciseID: &privateID, TargetValue: 100}, nil, ErrExerciseNotFound},
+		{"body weight without weigh-in", &models.CreateGoalRequest{Kind: models.GoalKindBodyWeight, TargetValue: 75}, nil, ErrGoalNeedsWeighIn},
+		{"body weight already there", &models.CreateGoalRequest{Kind: models.GoalKindBodyWeight, TargetValue: 80}, &weight, ErrInvalidGoalTarget},
+		{"fractional sessions", &models.CreateGoalRequest{Kind: models.GoalKindWeeklySessions, TargetValue: 2.5}, nil, ErrInvalidGoalTarget},
+		{"too many sessions", &models.CreateGoalRequest{Kind: models.GoalKindWeeklySessions, TargetValue: 20}, nil, ErrInvalidGoalTarget},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &repositories.MockGoalRepository{
+				CurrentValueFunc: func(ctx context.Context, userID string, kind string, exerciseID *string) (*float64, error) {
+					return tt.current, nil
+				},
+			}
+			service := NewGoalService(mockRepo, exercises, nil)
+
+			_, err := service.CreateGoal(context.Background(), "user-123", tt.req)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestListGoals_Progress(t *testing.T) {
+	current := func(v float64) *float64 { return &v }
+	mockRepo := &repositories.MockGoalRepository{
+		FindByUserFunc: func(ctx context.Context, userID string) ([]*models.Goal, error) {
+			return []*models.Goal{
+				{Kind: models.GoalKindBodyWeight, StartValue: 90, TargetValue: 80, CurrentValue: current(87.5)},
+				{Kind: models.GoalKindBodyWeight, StartValue: 90, TargetValue: 80, CurrentValue: current(92)},
+				{Kind: models.GoalKindE1RM, StartValue: 100, TargetValue: 120, CurrentValue: current(110)},
+				{Kind: models.GoalKindWeeklySessions, TargetValue: 4, CurrentValue: current(5), Achieved: true},
+				{Kind: models.GoalKindE1RM, TargetValue: 100},
+			}, nil
+		},
+	}
+
+	service := NewGoalService(mockRepo, &repositories.MockExerciseRepository{}, nil)
+
+	goals, err := service.ListGoals(context.Background(), "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []float64{25, 0, 50, 100, 0}
+	for i, g := range goals {
+		if g.Percentage != want[i] {
+			t.Errorf("Goal %d: expected %v%%, got %v%%", i, want[i], g.Percentage)
+		}
+	}
+}
+
+func TestUpdateGoal_NotFound(t *testing.T) {
+	mockRepo := &repositories.MockGoalRepository{
+		FindByIDFunc: func(ctx context.Context, userID string, id string) (*models.Goal, error) {
+			return nil, pgx.ErrNoRows
+		},
+	}
+
+	service := NewGoalService(mockRepo, &repositories.MockExerciseRepository{}, nil)
+
+	_, err := service.UpdateGoal(context.Background(), "user-123", "missing", &models.UpdateGoalRequest{TargetValue: 5})
+
+	if !errors.Is(err, ErrGoalNotFound) {
+		t.Errorf("Expected ErrGoalNotFound, got %v", err)
+	}
+}
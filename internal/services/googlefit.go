@@ -0,0 +1,310 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/googlefit"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrIntegrationNotConfigured  = domainerr.New(domainerr.Transient, "google fit integration is not configured")
+	ErrIntegrationNotConnected   = domainerr.New(domainerr.NotFound, "google fit is not connected")
+	ErrInvalidOAuthState         = domainerr.New(domainerr.Validation, "invalid or expired authorization state")
+	ErrIntegrationAuthFailed     = domainerr.New(domainerr.Validation, "google fit authorization failed")
+	ErrIntegrationReauthRequired = domainerr.New(domainerr.Conflict, "google fit access was revoked, connect again")
+)
+
+const (
+	// googleFitStateTTL is how long a consent page URL can be completed
+	googleFitStateTTL = 10 * time.Minute
+
+	// googleFitInitialSync is how much history the first sync pulls
+	googleFitInitialSync = 30 * 24 * time.Hour
+
+	// googleFitSyncOverlap re-reads recent history on every sync to catch data that
+	// reached Google Fit late (e.g. a watch that synced to the phone hours later)
+	googleFitSyncOverlap = 48 * time.Hour
+
+	// googleFitSyncTimeout bounds a single user's sync
+	googleFitSyncTimeout = 2 * time.Minute
+
+	// googleFitSyncBatch caps how many connections one background run syncs
+	googleFitSyncBatch = 50
+)
+
+// GoogleFitClient is the subset of the Google Fit API the service uses
+type GoogleFitClient interface {
+	Configured() bool
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*googlefit.Token, error)
+	Refresh(ctx context.Context, refreshToken string) (*googlefit.Token, error)
+	Sessions(ctx context.Context, accessToken string, from, to time.Time) ([]*googlefit.Session, error)
+	Weights(ctx context.Context, accessToken string, from, to time.Time) ([]*googlefit.WeightPoint, error)
+}
+
+// GoogleFitService connects users' Google Fit accounts and pulls their workouts and weigh-ins
+type GoogleFitService struct {
+	repo         repositories.IntegrationRepository
+	measurements repositories.MeasurementRepository
+	client       GoogleFitClient
+	stateKey     []byte
+	now          func() time.Time
+	run          func(task func()) // Runs the first sync after connecting; a goroutine outside tests
+}
+
+// NewGoogleFitService creates a new Google Fit service
+// stateKey signs OAuth state values so a consent redirect can only complete the
+// connection of the user who started it.
+func NewGoogleFitService(repo repositories.IntegrationRepository, measurements repositories.MeasurementRepository, client GoogleFitClient, stateKey []byte) *GoogleFitService {
+	return &GoogleFitService{
+		repo:         repo,
+		measurements: measurements,
+		client:       client,
+		stateKey:     stateKey,
+		now:          time.Now,
+		run:          func(task func()) { go task() },
+	}
+}
+
+// Authorize returns the Google consent page the user opens to connect Google Fit
+func (s *GoogleFitService) Authorize(userID string) (*models.IntegrationAuthorization, error) {
+	if !s.client.Configured() {
+		return nil, ErrIntegrationNotConfigured
+	}
+
+	expiresAt := s.now().Add(googleFitStateTTL).UTC().Truncate(time.Second)
+	state := signOAuthState(s.stateKey, userID, expiresAt)
+
+	return &models.IntegrationAuthorization{
+		AuthURL:   s.client.AuthCodeURL(state),
+		State:     state,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Connect completes the connection with the authorization code Google redirected back with
+// and starts a first sync in the background
+func (s *GoogleFitService) Connect(ctx context.Context, userID string, req *models.ConnectIntegrationRequest) (*models.IntegrationConnection, error) {
+	if !s.client.Configured() {
+		return nil, ErrIntegrationNotConfigured
+	}
+	if !verifyOAuthState(s.stateKey, userID, req.State, s.now()) {
+		return nil, ErrInvalidOAuthState
+	}
+
+	token, err := s.client.Exchange(ctx, req.Code)
+	if err != nil {
+		if errors.Is(err, googlefit.ErrInvalidGrant) {
+			return nil, ErrIntegrationAuthFailed
+		}
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if token.RefreshToken == "" {
+		// Without offline access the connection would stop working within the hour
+		return nil, ErrIntegrationAuthFailed
+	}
+
+	conn := &models.IntegrationConnection{
+		UserID:         userID,
+		Provider:       models.IntegrationProviderGoogleFit,
+		AccessToken:    token.AccessToken,
+		RefreshToken:   token.RefreshToken,
+		TokenExpiresAt: token.ExpiresAt,
+	}
+	if token.Scope != "" {
+		conn.Scope = &token.Scope
+	}
+	if err := s.repo.SaveConnection(ctx, conn); err != nil {
+		return nil, fmt.Errorf("failed to save connection: %w", err)
+	}
+
+	initial := *conn // The sync refreshes tokens on its own copy
+	s.run(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), googleFitSyncTimeout)
+		defer cancel()
+		if _, err := s.syncConnection(ctx, &initial); err != nil {
+			log.Printf("Google Fit initial sync for user %s failed: %v", userID, err)
+		}
+	})
+
+	return conn, nil
+}
+
+// GetConnection retrieves the user's Google Fit connection and its sync status
+func (s *GoogleFitService) GetConnection(ctx context.Context, userID string) (*models.IntegrationConnection, error) {
+	conn, err := s.repo.FindConnection(ctx, userID, models.IntegrationProviderGoogleFit)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrIntegrationNotConnected
+		}
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return conn, nil
+}
+
+// Disconnect deletes the user's stored tokens; sessions and weigh-ins already synced are kept
+func (s *GoogleFitService) Disconnect(ctx context.Context, userID string) error {
+	if err := s.repo.DeleteConnection(ctx, userID, models.IntegrationProviderGoogleFit); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrIntegrationNotConnected
+		}
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	return nil
+}
+
+// Sync pulls the user's recent Google Fit workouts and weigh-ins now
+func (s *GoogleFitService) Sync(ctx context.Context, userID string) (*models.IntegrationSyncResult, error) {
+	conn, err := s.GetConnection(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.syncConnection(ctx, conn)
+}
+
+// Start syncs connections not synced within interval, every interval, until ctx is cancelled
+func (s *GoogleFitService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.syncDue(ctx, interval)
+			}
+		}
+	}()
+}
+
+// syncDue syncs one batch of connections whose last sync is older than interval
+func (s *GoogleFitService) syncDue(ctx context.Context, interval time.Duration) {
+	connections, err := s.repo.FindDueConnections(ctx, models.IntegrationProviderGoogleFit, s.now().Add(-interval), googleFitSyncBatch)
+	if err != nil {
+		log.Printf("Google Fit sync failed to list connections: %v", err)
+		return
+	}
+
+	for _, conn := range connections {
+		if ctx.Err() != nil {
+			return
+		}
+		syncCtx, cancel := context.WithTimeout(ctx, googleFitSyncTimeout)
+		if _, err := s.syncConnection(syncCtx, conn); err != nil {
+			log.Printf("Google Fit sync for user %s failed: %v", conn.UserID, err)
+		}
+		cancel()
+	}
+}
+
+// syncConnection pulls sessions and weigh-ins since shortly before the last successful sync.
+// Failures are recorded on the connection; a revoked refresh token pauses background syncs
+// until the user connects again.
+func (s *GoogleFitService) syncConnection(ctx context.Context, conn *models.IntegrationConnection) (*models.IntegrationSyncResult, error) {
+	result, err := s.pull(ctx, conn)
+	if err != nil {
+		reauth := errors.Is(err, googlefit.ErrInvalidGrant)
+		if reauth {
+			err = ErrIntegrationReauthRequired
+		}
+		if markErr := s.repo.MarkSyncFailed(ctx, conn.ID, err.Error(), reauth); markErr != nil {
+			log.Printf("Failed to record Google Fit sync failure for user %s: %v", conn.UserID, markErr)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.MarkSynced(ctx, conn.ID, result.To); err != nil {
+		return nil, fmt.Errorf("failed to record sync: %w", err)
+	}
+	return result, nil
+}
+
+func (s *GoogleFitService) pull(ctx context.Context, conn *models.IntegrationConnection) (*models.IntegrationSyncResult, error) {
+	now := s.now().UTC()
+	result := &models.IntegrationSyncResult{
+		Provider: models.IntegrationProviderGoogleFit,
+		From:     now.Add(-googleFitInitialSync),
+		To:       now,
+	}
+	if conn.LastSyncedAt != nil {
+		result.From = conn.LastSyncedAt.Add(-googleFitSyncOverlap)
+	}
+
+	// Refresh a minute early so the token cannot expire mid-sync
+	if conn.TokenExpiresAt.Before(now.Add(time.Minute)) {
+		token, err := s.client.Refresh(ctx, conn.RefreshToken)
+		if err != nil {
+			return nil, fmt.Errorf("failed to refresh token: %w", err)
+		}
+		conn.AccessToken, conn.RefreshToken, conn.TokenExpiresAt = token.AccessToken, token.RefreshToken, token.ExpiresAt
+		if err := s.repo.UpdateTokens(ctx, conn); err != nil {
+			return nil, fmt.Errorf("failed to save refreshed token: %w", err)
+		}
+	}
+
+	sessions, err := s.client.Sessions(ctx, conn.AccessToken, result.From, result.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sessions: %w", err)
+	}
+
+	var external []*models.ExternalSession
+	for _, session := range sessions {
+		if !session.EndedAt.After(session.StartedAt) {
+			continue
+		}
+		name := strings.TrimSpace(session.Name)
+		if name == "" {
+			name = googlefit.ActivityName(session.ActivityType)
+		}
+		external = append(external, &models.ExternalSession{
+			ExternalID:  session.ID,
+			Name:        name,
+			Notes:       session.Description,
+			StartedAt:   session.StartedAt,
+			CompletedAt: session.EndedAt,
+		})
+	}
+	if len(external) > 0 {
+		created, updated, err := s.repo.UpsertSessions(ctx, conn.UserID, models.IntegrationProviderGoogleFit, external)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store sessions: %w", err)
+		}
+		result.SessionsCreated, result.SessionsUpdated = created, updated
+		result.SessionsKept = len(external) - created - updated
+	}
+
+	weights, err := s.client.Weights(ctx, conn.AccessToken, result.From, result.To)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch weights: %w", err)
+	}
+
+	measurements := make([]*models.BodyMeasurement, 0, len(weights))
+	for _, w := range weights {
+		weightKg := w.WeightKg
+		measurements = append(measurements, &models.BodyMeasurement{
+			UserID:     conn.UserID,
+			MeasuredAt: w.MeasuredAt,
+			WeightKg:   &weightKg,
+			Source:     models.MeasurementSourceGoogleFit,
+		})
+	}
+	if len(measurements) > 0 {
+		// Weigh-ins the user already has at the same timestamp are kept as is
+		if result.Weights, err = s.measurements.CreateMany(ctx, measurements); err != nil {
+			return nil, fmt.Errorf("failed to store weights: %w", err)
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/googlefit"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// fakeGoogleFit is an in-memory GoogleFitClient
+type fakeGoogleFit struct {
+	token      *googlefit.Token
+	refreshErr error
+	sessions   []*googlefit.Session
+	weights    []*googlefit.WeightPoint
+	refreshed  bool
+	from       time.Time
+}
+
+func (f *fakeGoogleFit) Configured() bool { return true }
+func (f *fakeGoogleFit) AuthCodeURL(state string) string {
+	return "https://accounts.example.com/auth?state=" + state
+}
+
+func (f *fakeGoogleFit) Exchange(ctx context.Context, code string) (*googlefit.Token, error) {
+	if code != "good-code" {
+		return nil, googlefit.ErrInvalidGrant
+	}
+	return f.token, nil
+}
+
+func (f *fakeGoogleFit) Refresh(ctx context.Context, refreshToken string) (*googlefit.Token, error) {
+	f.refreshed = true
+	if f.refreshErr != nil {
+		return nil, f.refreshErr
+	}
+	return f.token, nil
+}
+
+func (f *fakeGoogleFit) Sessions(ctx context.Context, accessToken string, from, to time.Time) ([]*googlefit.Session, error) {
+	f.from = from
+	return f.sessions, nil
+}
+
+func (f *fakeGoogleFit) Weights(ctx context.Context, accessToken string, from, to time.Time) ([]*googlefit.WeightPoint, error) {
+	return f.weights, nil
+}
+
+var googleFitNow = time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+
+func newTestGoogleFitService(repo repositories.IntegrationRepository, measurements repositories.MeasurementRepository, client *fakeGoogleFit) *GoogleFitService {
+	service := NewGoogleFitService(repo, measurements, client, []byte("test-secret"))
+	service.now = func() time.Time { return googleFitNow }
+	service.run = func(task func()) {}
+	return service
+}
+
+func TestGoogleFitConnect_VerifiesState(t *testing.T) {
+	client := &fakeGoogleFit{token: &googlefit.Token{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: googleFitNow.Add(time.Hour)}}
+	var saved *models.IntegrationConnection
+	mockRepo := &repositories.MockIntegrationRepository{
+		SaveConnectionFunc: func(ctx context.Context, conn *models.IntegrationConnection) error {
+			saved = conn
+			return nil
+		},
+	}
+
+	service := newTestGoogleFitService(mockRepo, &repositories.MockMeasurementRepository{}, client)
+
+	auth, err := service.Authorize("user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := service.Connect(context.Background(), "user-456", &models.ConnectIntegrationRequest{Code: "good-code", State: auth.State}); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("Expected another user's state to be rejected, got %v", err)
+	}
+	if _, err := service.Connect(context.Background(), "user-123", &models.ConnectIntegrationRequest{Code: "good-code", State: auth.State + "0"}); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("Expected a tampered state to be rejected, got %v", err)
+	}
+	if _, err := service.Connect(context.Background(), "user-123", &models.ConnectIntegrationRequest{Code: "bad-code", State: auth.State}); !errors.Is(err, ErrIntegrationAuthFailed) {
+		t.Errorf("Expected ErrIntegrationAuthFailed, got %v", err)
+	}
+
+	conn, err := service.Connect(context.Background(), "user-123", &models.ConnectIntegrationRequest{Code: "good-code", State: auth.State})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved != conn || conn.RefreshToken != "refresh" || conn.Provider != models.IntegrationProviderGoogleFit {
+		t.Errorf("Expected tokens to be saved, got %+v", conn)
+	}
+
+	service.now = func() time.Time { return googleFitNow.Add(googleFitStateTTL + time.Second) }
+	if _, err := service.Connect(context.Background(), "user-123", &models.ConnectIntegrationRequest{Code: "good-code", State: auth.State}); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("Expected an expired state to be rejected, got %v", err)
+	}
+}
+
+func TestGoogleFitSync(t *testing.T) {
+	lastSynced := googleFitNow.Add(-6 * time.Hour)
+	client := &fakeGoogleFit{
+		token: &googlefit.Token{AccessToken: "new-access", RefreshToken: "refresh", ExpiresAt: googleFitNow.Add(time.Hour)},
+		sessions: []*googlefit.Session{
+			{ID: "run-1", ActivityType: 8, StartedAt: googleFitNow.Add(-3 * time.Hour), EndedAt: googleFitNow.Add(-2 * time.Hour)},
+			{ID: "yoga-1", Name: "Morning flow", ActivityType: 100, StartedAt: googleFitNow.Add(-5 * time.Hour), EndedAt: googleFitNow.Add(-4 * time.Hour)},
+			{ID: "live-1", ActivityType: 7, StartedAt: googleFitNow, EndedAt: googleFitNow},
+		},
+		weights: []*googlefit.WeightPoint{{MeasuredAt: googleFitNow.Add(-time.Hour), WeightKg: 80.5}},
+	}
+
+	var upserted []*models.ExternalSession
+	var stored []*models.BodyMeasurement
+	var tokensSaved bool
+	var syncedAt time.Time
+	mockRepo := &repositories.MockIntegrationRepository{
+		FindConnectionFunc: func(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error) {
+			return &models.IntegrationConnection{
+				ID:             "conn-1",
+				UserID:         userID,
+				Provider:       provider,
+				AccessToken:    "old-access",
+				RefreshToken:   "refresh",
+				TokenExpiresAt: googleFitNow.Add(-time.Minute),
+				LastSyncedAt:   &lastSynced,
+			}, nil
+		},
+		UpdateTokensFunc: func(ctx context.Context, conn *models.IntegrationConnection) error {
+			tokensSaved = conn.AccessToken == "new-access"
+			return nil
+		},
+		UpsertSessionsFunc: func(ctx context.Context, userID string, source string, sessions []*models.ExternalSession) (int, int, error) {
+			upserted = sessions
+			return 1, 0, nil
+		},
+		MarkSyncedFunc: func(ctx context.Context, id string, at time.Time) error {
+			syncedAt = at
+			return nil
+		},
+	}
+	measurements := &repositories.MockMeasurementRepository{
+		CreateManyFunc: func(ctx context.Context, m []*models.BodyMeasurement) (int64, error) {
+			stored = m
+			return 1, nil
+		},
+	}
+
+	service := newTestGoogleFitService(mockRepo, measurements, client)
+
+	result, err := service.Sync(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !client.refreshed || !tokensSaved {
+		t.Error("Expected the expired access token to be refreshed and saved")
+	}
+	if !client.from.Equal(lastSynced.Add(-googleFitSyncOverlap)) {
+		t.Errorf("Expected sync to start %v before the last sync, got %v", googleFitSyncOverlap, client.from)
+	}
+	if len(upserted) != 2 || upserted[0].Name != "Running" || upserted[1].Name != "Morning flow" {
+		t.Fatalf("Expected 2 finished sessions named by activity or title, got %+v", upserted)
+	}
+	if result.SessionsCreated != 1 || result.SessionsKept != 1 || result.Weights != 1 {
+		t.Errorf("Expected 1 created, 1 kept and 1 weight, got %+v", result)
+	}
+	if len(stored) != 1 || *stored[0].WeightKg != 80.5 || stored[0].Source != models.MeasurementSourceGoogleFit {
+		t.Errorf("Expected weigh-in stored from googlefit, got %+v", stored)
+	}
+	if !syncedAt.Equal(googleFitNow) {
+		t.Errorf("Expected sync recorded at %v, got %v", googleFitNow, syncedAt)
+	}
+}
+
+func TestGoogleFitSync_RevokedAccess(t *testing.T) {
+	client := &fakeGoogleFit{refreshErr: googlefit.ErrInvalidGrant}
+
+	var reauth, synced bool
+	mockRepo := &repositories.MockIntegrationRepository{
+		FindConnectionFunc: func(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error) {
+			return &models.IntegrationConnection{ID: "conn-1", UserID: userID, TokenExpiresAt: googleFitNow.Add(-time.Hour)}, nil
+		},
+		MarkSyncFailedFunc: func(ctx context.Context, id string, reason string, reauthRequired bool) error {
+			reauth = reauthRequired
+			return nil
+		},
+		MarkSyncedFunc: func(ctx context.Context, id string, at time.Time) error {
+			synced = true
+			return nil
+		},
+	}
+
+	service := newTestGoogleFitService(mockRepo, &repositories.MockMeasurementRepository{}, client)
+
+	_, err := service.Sync(context.Background(), "user-123")
+	if !errors.Is(err, ErrIntegrationReauthRequired) {
+		t.Fatalf("Expected ErrIntegrationReauthRequired, got %v", err)
+	}
+	if !reauth || synced {
+		t.Error("Expected the connection to be flagged for reconnecting without advancing the sync window")
+	}
+}
+
+func TestGoogleFitSync_NotConnected(t *testing.T) {
+	service := newTestGoogleFitService(&repositories.MockIntegrationRepository{}, &repositories.MockMeasurementRepository{}, &fakeGoogleFit{})
+
+	if _, err := service.Sync(context.Background(), "user-123"); !errors.Is(err, ErrIntegrationNotConnected) {
+		t.Errorf("Expected ErrIntegrationNotConnected, got %v", err)
+	}
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrWaterLogNotFound = domainerr.New(domainerr.NotFound, "water log not found")
+	ErrWaterLogInFuture = domainerr.New(domainerr.Validation, "logged_at can't be in the future")
+)
+
+// clockSkew is how far ahead of the server's clock a logged time may be, for devices whose
+// clock runs a little fast
+const clockSkew = 5 * time.Minute
+
+// HydrationService tracks the water users drink against a daily goal. Days are counted in
+// the user's timezone.
+type HydrationService struct {
+	repo     repositories.HydrationRepository
+	profiles repositories.ProfileRepository
+	now      func() time.Time
+}
+
+// NewHydrationService creates a new hydration service
+func NewHydrationService(repo repositories.HydrationRepository, profiles repositories.ProfileRepository) *HydrationService {
+	return &HydrationService{repo: repo, profiles: profiles, now: time.Now}
+}
+
+// LogWater records water the user drank, now unless logged_at says otherwise
+func (s *HydrationService) LogWater(ctx context.Context, userID string, req *models.CreateWaterLogRequest) (*models.WaterLog, error) {
+	now := s.now()
+	loggedAt := now
+	if req.LoggedAt != nil {
+		if req.LoggedAt.After(now.Add(clockSkew)) {
+			return nil, ErrWaterLogInFuture
+		}
+		loggedAt = *req.LoggedAt
+	}
+
+	log := &models.WaterLog{
+		UserID:   userID,
+		AmountMl: req.AmountMl,
+		LoggedAt: loggedAt.UTC(),
+	}
+	if err := s.repo.Create(ctx, log); err != nil {
+		return nil, fmt.Errorf("failed to log water: %w", err)
+	}
+
+	return log, nil
+}
+
+// ListWaterLogs retrieves the user's logs of a day, oldest first; a zero day is today
+func (s *HydrationService) ListWaterLogs(ctx context.Context, userID string, day time.Time) ([]*models.WaterLog, error) {
+	day, err := pastDay(ctx, s.profiles, s.now(), userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	logs, err := s.repo.FindByDay(ctx, userID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list water logs: %w", err)
+	}
+
+	return logs, nil
+}
+
+// DeleteWaterLog removes one of the user's logs
+func (s *HydrationService) DeleteWaterLog(ctx context.Context, userID string, id string) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrWaterLogNotFound
+		}
+		return fmt.Errorf("failed to delete water log: %w", err)
+	}
+
+	return nil
+}
+
+// GetGoal retrieves the user's daily goal
+func (s *HydrationService) GetGoal(ctx context.Context, userID string) (*models.HydrationGoal, error) {
+	goal, err := s.repo.FindGoal(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hydration goal: %w", err)
+	}
+
+	return goal, nil
+}
+
+// UpdateGoal sets the user's daily goal
+func (s *HydrationService) UpdateGoal(ctx context.Context, userID string, req *models.UpdateHydrationGoalRequest) (*models.HydrationGoal, error) {
+	goal := &models.HydrationGoal{UserID: userID, DailyMl: req.DailyMl}
+	if err := s.repo.SaveGoal(ctx, goal); err != nil {
+		return nil, fmt.Errorf("failed to save hydration goal: %w", err)
+	}
+
+	return goal, nil
+}
+
+// GetProgress totals the water the user drank on a day against their goal; a zero day is
+// today
+func (s *HydrationService) GetProgress(ctx context.Context, userID string, day time.Time) (*models.HydrationProgress, error) {
+	day, err := pastDay(ctx, s.profiles, s.now(), userID, day)
+	if err != nil {
+		return nil, err
+	}
+
+	goal, err := s.GetGoal(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	total, logs, err := s.repo.DailyTotal(ctx, userID, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get water total: %w", err)
+	}
+
+	return &models.HydrationProgress{
+		Date:        day.Format("2006-01-02"),
+		GoalMl:      goal.DailyMl,
+		TotalMl:     total,
+		RemainingMl: max(goal.DailyMl-total, 0),
+		Percentage:  math.Round(float64(total)/float64(goal.DailyMl)*1000) / 10,
+		Logs:        logs,
+	}, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestHydrationService(repo repositories.HydrationRepository, profiles repositories.ProfileRepository) *HydrationService {
+	service := NewHydrationService(repo, profiles)
+	service.now = func() time.Time { return time.Date(2026, 6, 18, 23, 30, 0, 0, time.UTC) }
+	return service
+}
+
+func TestLogWater_InFuture(t *testing.T) {
+	service := newTestHydrationService(&repositories.MockHydrationRepository{
+		CreateFunc: func(ctx context.Context, log *models.WaterLog) error {
+			t.Fatal("Expected a log in the future not to be saved")
+			return nil
+		},
+	}, &repositories.MockProfileRepository{})
+
+	later := service.now().Add(time.Hour)
+	_, err := service.LogWater(context.Background(), "user-123", &models.CreateWaterLogRequest{AmountMl: 250, LoggedAt: &later})
+
+	if !errors.Is(err, ErrWaterLogInFuture) {
+		t.Errorf("Expected ErrWaterLogInFuture, got %v", err)
+	}
+}
+
+func TestGetProgress_TodayInUserTimezone(t *testing.T) {
+	var queried time.Time
+	mockRepo := &repositories.MockHydrationRepository{
+		DailyTotalFunc: func(ctx context.Context, userID string, day time.Time) (int, int, error) {
+			queried = day
+			return 2250, 5, nil
+		},
+	}
+	profiles := &repositories.MockProfileRepository{
+		FindProfileFunc: func(ctx context.Context, userID string) (*models.Profile, error) {
+			return &models.Profile{UserID: userID, Timezone: "Asia/Tokyo"}, nil
+		},
+	}
+
+	// 23:30 UTC is already the next morning in Tokyo
+	service := newTestHydrationService(mockRepo, profiles)
+	progress, err := service.GetProgress(context.Background(), "user-123", time.Time{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if want := time.Date(2026, 6, 19, 0, 0, 0, 0, time.UTC); !queried.Equal(want) || progress.Date != "2026-06-19" {
+		t.Errorf("Expected the user's local day 2026-06-19, got %v (%s)", queried, progress.Date)
+	}
+	if progress.GoalMl != models.DefaultHydrationGoalMl || progress.TotalMl != 2250 || progress.Logs != 5 {
+		t.Errorf("Unexpected progress %+v", progress)
+	}
+	if progress.RemainingMl != 0 || progress.Percentage != 112.5 {
+		t.Errorf("Expected the goal to be exceeded with nothing remaining, got %+v", progress)
+	}
+}
+
+func TestGetProgress_FutureDay(t *testing.T) {
+	service := newTestHydrationService(&repositories.MockHydrationRepository{}, &repositories.MockProfileRepository{})
+
+	_, err := service.GetProgress(context.Background(), "user-123", time.Date(2026, 6, 19, 0, 0, 0, 0, time.UTC))
+
+	if !errors.Is(err, ErrFutureDay) {
+		t.Errorf("Expected ErrFutureDay, got %v", err)
+	}
+}
+
+func TestDeleteWaterLog_NotFound(t *testing.T) {
+	service := newTestHydrationService(&repositories.MockHydrationRepository{
+		DeleteFunc: func(ctx context.Context, userID string, id string) error { return pgx.ErrNoRows },
+	}, &repositories.MockProfileRepository{})
+
+	err := service.DeleteWaterLog(context.Background(), "user-123", "log-1")
+
+	if !errors.Is(err, ErrWaterLogNotFound) {
+		t.Errorf("Expected ErrWaterLogNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+const (
+	// idempotencyRetention is how long a key's response is replayed to retries
+	idempotencyRetention = 24 * time.Hour
+	// idempotencyAbandonAfter is how long a request may run before its key is considered
+	// abandoned, for instance by an instance that crashed, and a retry may run it again
+	idempotencyAbandonAfter = 5 * time.Minute
+)
+
+// IdempotencyService records the requests sent with an Idempotency-Key and their responses,
+// so retries of a request that already ran get its response instead of running it twice
+type IdempotencyService struct {
+	repo repositories.IdempotencyRepository
+	now  func() time.Time
+}
+
+// NewIdempotencyService creates a new idempotency service
+func NewIdempotencyService(repo repositories.IdempotencyRepository) *IdempotencyService {
+	return &IdempotencyService{
+		repo: repo,
+		now:  time.Now,
+	}
+}
+
+// Reserve claims a key for a request about to run
+// It returns nil when the caller should run the request, and otherwise the key's record:
+// still running when its StatusCode is nil, and for a different request when its
+// RequestHash differs.
+func (s *IdempotencyService) Reserve(ctx context.Context, userID string, key string, requestHash string) (*models.IdempotencyRecord, error) {
+	now := s.now()
+	record := &models.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		RequestHash: requestHash,
+	}
+
+	// The key may be released between a failed reservation and the lookup; try once more then
+	for attempt := 0; attempt < 2; attempt++ {
+		reserved, err := s.repo.Reserve(ctx, record, now.Add(-idempotencyRetention), now.Add(-idempotencyAbandonAfter))
+		if err != nil {
+			return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+		}
+		if reserved {
+			return nil, nil
+		}
+
+		existing, err := s.repo.Find(ctx, userID, key)
+		if errors.Is(err, pgx.ErrNoRows) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to find idempotency key: %w", err)
+		}
+		return existing, nil
+	}
+
+	return nil, fmt.Errorf("failed to reserve idempotency key: key %q keeps being released", key)
+}
+
+// Complete stores the response of a reserved key's request for retries to replay
+func (s *IdempotencyService) Complete(ctx context.Context, userID string, key string, statusCode int, contentType string, body []byte) error {
+	record := &models.IdempotencyRecord{
+		UserID:      userID,
+		Key:         key,
+		StatusCode:  &statusCode,
+		ContentType: contentType,
+		Body:        body,
+	}
+
+	if err := s.repo.Complete(ctx, record); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+// Release gives up a reserved key without a response, so a retry runs the request again
+func (s *IdempotencyService) Release(ctx context.Context, userID string, key string) error {
+	if err := s.repo.Release(ctx, userID, key); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// Start prunes expired idempotency keys every interval until ctx is cancelled
+func (s *IdempotencyService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.repo.Prune(ctx, s.now().Add(-idempotencyRetention)); err != nil {
+					log.Printf("Idempotency keys failed to prune: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestIdempotencyReserve_NewKey(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &repositories.MockIdempotencyRepository{
+		ReserveFunc: func(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error) {
+			if !reusableBefore.Equal(now.Add(-idempotencyRetention)) || !abandonedBefore.Equal(now.Add(-idempotencyAbandonAfter)) {
+				t.Errorf("Unexpected cutoffs %v and %v", reusableBefore, abandonedBefore)
+			}
+			return true, nil
+		},
+	}
+
+	service := NewIdempotencyService(mockRepo)
+	service.now = func() time.Time { return now }
+
+	existing, err := service.Reserve(context.Background(), "user-123", "key-1", "hash")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if existing != nil {
+		t.Errorf("Expected the key to be reserved, got %+v", existing)
+	}
+}
+
+func TestIdempotencyReserve_ReturnsCompletedRecord(t *testing.T) {
+	status := http.StatusCreated
+	mockRepo := &repositories.MockIdempotencyRepository{
+		ReserveFunc: func(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error) {
+			return false, nil
+		},
+		FindFunc: func(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error) {
+			return &models.IdempotencyRecord{UserID: userID, Key: key, RequestHash: "hash", StatusCode: &status, Body: []byte(`{}`)}, nil
+		},
+	}
+
+	service := NewIdempotencyService(mockRepo)
+
+	existing, err := service.Reserve(context.Background(), "user-123", "key-1", "hash")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if existing == nil || existing.StatusCode == nil || *existing.StatusCode != http.StatusCreated {
+		t.Errorf("Expected the completed record, got %+v", existing)
+	}
+}
+
+func TestIdempotencyReserve_RetriesReleasedKey(t *testing.T) {
+	reserves := 0
+	mockRepo := &repositories.MockIdempotencyRepository{
+		ReserveFunc: func(ctx context.Context, record *models.IdempotencyRecord, reusableBefore time.Time, abandonedBefore time.Time) (bool, error) {
+			reserves++
+			return reserves > 1, nil
+		},
+		FindFunc: func(ctx context.Context, userID string, key string) (*models.IdempotencyRecord, error) {
+			return nil, pgx.ErrNoRows
+		},
+	}
+
+	service := NewIdempotencyService(mockRepo)
+
+	existing, err := service.Reserve(context.Background(), "user-123", "key-1", "hash")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if existing != nil || reserves != 2 {
+		t.Errorf("Expected the key to be reserved on the second attempt, got %+v after %d attempts", existing, reserves)
+	}
+}
@@ -0,0 +1,400 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/importer"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrImportNotFound = domainerr.New(domainerr.NotFound, "import not found")
+
+const (
+	// maxImportPreview caps the sessions listed in a dry-run preview
+	maxImportPreview = 100
+
+	// cardioStartTolerance absorbs clock differences between devices recording the same
+	// activity (e.g. a watch and the phone app)
+	cardioStartTolerance = 2 * time.Minute
+
+	// importTimeout bounds one attempt at storing an import's sessions
+	importTimeout = 5 * time.Minute
+
+	// importAttempts is how often storing an import is tried before the import fails
+	importAttempts = 3
+)
+
+// ImportService imports workout history exported from other fitness apps. Exports are
+// parsed when uploaded and their sessions stored by a job of the queue.
+type ImportService struct {
+	repo      repositories.ImportRepository
+	publisher events.Publisher
+	jobs      *jobs.Queue
+}
+
+// NewImportService creates a new import service storing imports as jobs of the queue
+func NewImportService(repo repositories.ImportRepository, publisher events.Publisher, queue *jobs.Queue) *ImportService {
+	s := &ImportService{repo: repo, publisher: publisher, jobs: queue}
+	queue.Register(models.JobKindImport, jobs.Options{MaxAttempts: importAttempts, Timeout: importTimeout}, s.runImport)
+	return s
+}
+
+// importJob is the payload of an import job
+type importJob struct {
+	ImportID string `json:"import_id"`
+}
+
+// Preview parses an export from the given source ("strong", "hevy", "apple-health", "fit"
+// or "tcx") and reports what importing it would create, without storing anything
+func (s *ImportService) Preview(ctx context.Context, userID string, source string, r io.Reader, opts importer.Options) (*models.ImportResult, error) {
+	sessions, loc, err := parseImport(source, r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result, _, err := s.plan(ctx, userID, source, sessions, loc, true)
+	return result, err
+}
+
+// Import parses an export from the given source and queues storing it. The import is
+// created pending and completed by a background job; poll GetImport for its result.
+// Exercise names are matched to the library; unmatched names become private exercises.
+// Sets of an exercise the user already logged on the same day (in opts.Location) are
+// skipped, so re-importing an export, or importing the overlap when switching apps,
+// does not duplicate history.
+func (s *ImportService) Import(ctx context.Context, userID string, source string, r io.Reader, opts importer.Options) (*models.Import, error) {
+	sessions, loc, err := parseImport(source, r, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	imp := &models.Import{
+		UserID:   userID,
+		Source:   source,
+		Status:   models.ImportStatusPending,
+		Timezone: loc.String(),
+	}
+	if err := s.repo.CreateImport(ctx, imp, sessions); err != nil {
+		return nil, fmt.Errorf("failed to create import: %w", err)
+	}
+
+	if _, err := s.jobs.Enqueue(ctx, models.JobKindImport, userID, &importJob{ImportID: imp.ID}); err != nil {
+		if err := s.repo.FailImport(ctx, imp.ID, "import could not be queued"); err != nil {
+			log.Printf("Failed to mark import %s as failed: %v", imp.ID, err)
+		}
+		return nil, fmt.Errorf("failed to queue import: %w", err)
+	}
+	return imp, nil
+}
+
+// GetImport retrieves the status and result of one of the user's imports
+func (s *ImportService) GetImport(ctx context.Context, id string, userID string) (*models.Import, error) {
+	imp, err := s.repo.FindImport(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrImportNotFound
+		}
+		return nil, fmt.Errorf("failed to get import: %w", err)
+	}
+
+	// Other users' imports are indistinguishable from missing ones
+	if imp.UserID != userID {
+		return nil, ErrImportNotFound
+	}
+
+	return imp, nil
+}
+
+// runImport stores an import job's sessions; a failed last attempt is recorded on the import
+func (s *ImportService) runImport(ctx context.Context, job *models.Job) error {
+	var payload importJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid import job: %w", err))
+	}
+	if job.UserID == nil {
+		return jobs.Permanent(errors.New("import job has no user"))
+	}
+
+	if err := s.store(ctx, *job.UserID, payload.ImportID); err != nil {
+		log.Printf("Import failed: import=%s user=%s attempt=%d err=%v", payload.ImportID, *job.UserID, job.Attempts, err)
+		if jobs.LastAttempt(job) || jobs.IsPermanent(err) {
+			if err := s.repo.FailImport(ctx, payload.ImportID, "storing the sessions failed"); err != nil {
+				log.Printf("Failed to mark import %s as failed: %v", payload.ImportID, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// store matches and stores a pending import's sessions and completes it. A retry after the
+// sessions were stored skips them as already logged.
+func (s *ImportService) store(ctx context.Context, userID string, id string) error {
+	imp, err := s.repo.FindImport(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return jobs.Permanent(ErrImportNotFound)
+		}
+		return fmt.Errorf("failed to get import: %w", err)
+	}
+	if imp.Status != models.ImportStatusPending {
+		return nil
+	}
+	loc, err := time.LoadLocation(imp.Timezone)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid timezone %q: %w", imp.Timezone, err))
+	}
+
+	sessions, err := s.repo.FindImportSessions(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get import sessions: %w", err)
+	}
+	result, sessions, err := s.plan(ctx, userID, imp.Source, sessions, loc, false)
+	if err != nil {
+		return err
+	}
+
+	if len(sessions) > 0 {
+		if err := s.repo.CreateSessions(ctx, userID, sessions, result.NewExercises); err != nil {
+			return fmt.Errorf("failed to import sessions: %w", err)
+		}
+	}
+	if err := s.repo.CompleteImport(ctx, id, result); err != nil {
+		return fmt.Errorf("failed to complete import: %w", err)
+	}
+
+	if len(sessions) > 0 {
+		// The user's other devices learn about the new history; the import stands either way
+		finished := &models.ImportFinishedEvent{Source: imp.Source, SessionsCreated: result.Sessions}
+		if err := s.publisher.Publish(ctx, userID, models.UserEventImportFinished, finished); err != nil {
+			log.Printf("Failed to publish import event for user %s: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+// parseImport parses an export into its sessions with sets, dropping empty ones, and
+// returns the location days are compared in, UTC unless opts sets one
+func parseImport(source string, r io.Reader, opts importer.Options) ([]*models.ImportedSession, *time.Location, error) {
+	parser, err := importer.ForSource(source)
+	if err != nil {
+		return nil, nil, err
+	}
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	sessions, err := parser.Parse(r, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nonEmpty []*models.ImportedSession
+	for _, session := range sessions {
+		if len(session.Sets) > 0 {
+			nonEmpty = append(nonEmpty, session)
+		}
+	}
+	return nonEmpty, opts.Location, nil
+}
+
+// plan matches the sessions' exercises and skips history already logged, returning what
+// importing them creates (with a preview in dry-run mode) and the sessions left to store
+func (s *ImportService) plan(ctx context.Context, userID string, source string, sessions []*models.ImportedSession, loc *time.Location, dryRun bool) (*models.ImportResult, []*models.ImportedSession, error) {
+	result := &models.ImportResult{
+		Source:       source,
+		DryRun:       dryRun,
+		MatchedNames: []string{},
+		NewExercises: []string{},
+	}
+	if len(sessions) == 0 {
+		return result, nil, nil
+	}
+
+	if err := s.matchExercises(ctx, userID, sessions); err != nil {
+		return nil, nil, err
+	}
+
+	sessions, err := s.skipLogged(ctx, userID, sessions, loc, result)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	matched := make(map[string]bool)
+	unmatched := make(map[string]bool)
+	for _, session := range sessions {
+		exercises := make(map[string]bool)
+		for _, set := range session.Sets {
+			exercises[set.ExerciseName] = true
+			if set.ExerciseID != "" {
+				matched[set.ExerciseName] = true
+			} else {
+				unmatched[set.ExerciseName] = true
+			}
+		}
+
+		result.Sessions++
+		result.Sets += len(session.Sets)
+		if dryRun && len(result.Preview) < maxImportPreview {
+			result.Preview = append(result.Preview, &models.ImportSessionPreview{
+				StartedAt: session.StartedAt,
+				Name:      session.Name,
+				Type:      sessionType(session),
+				Exercises: len(exercises),
+				Sets:      len(session.Sets),
+				Laps:      len(session.Laps),
+			})
+		}
+	}
+	result.MatchedNames = sortedKeys(matched)
+	result.NewExercises = sortedKeys(unmatched)
+
+	return result, sessions, nil
+}
+
+// matchExercises resolves each set's exercise against the library using the name
+// candidates from importer.ExerciseNameCandidates. Names are normalized like names typed in
+// the app, and spellings that differ only in case are folded into one name so they become a
+// single new exercise when unmatched.
+func (s *ImportService) matchExercises(ctx context.Context, userID string, sessions []*models.ImportedSession) error {
+	canonical := make(map[string]string)
+	candidates := make(map[string][]string)
+	var lookup []string
+	seen := make(map[string]bool)
+	for _, session := range sessions {
+		for _, set := range session.Sets {
+			set.ExerciseName = normalizeName(set.ExerciseName)
+			key := strings.ToLower(set.ExerciseName)
+			if _, ok := canonical[key]; !ok {
+				canonical[key] = set.ExerciseName
+				candidates[set.ExerciseName] = importer.ExerciseNameCandidates(set.ExerciseName)
+				for _, candidate := range candidates[set.ExerciseName] {
+					if !seen[candidate] {
+						seen[candidate] = true
+						lookup = append(lookup, candidate)
+					}
+				}
+			}
+			set.ExerciseName = canonical[key]
+		}
+	}
+
+	matches, err := s.repo.MatchExercises(ctx, userID, lookup)
+	if err != nil {
+		return fmt.Errorf("failed to match exercises: %w", err)
+	}
+
+	resolved := make(map[string]string, len(candidates))
+	for name, names := range candidates {
+		for _, candidate := range names {
+			if id, ok := matches[candidate]; ok {
+				resolved[name] = id
+				break
+			}
+		}
+	}
+	for _, session := range sessions {
+		for _, set := range session.Sets {
+			set.ExerciseID = resolved[set.ExerciseName]
+		}
+	}
+
+	return nil
+}
+
+// skipLogged drops sets of exercises the user already logged on the same day and
+// sessions left without sets, counting both in result. Cardio sessions are only
+// duplicates of a logged session of the same exercise that started within
+// cardioStartTolerance, since runners often log more than one run a day.
+func (s *ImportService) skipLogged(ctx context.Context, userID string, sessions []*models.ImportedSession, loc *time.Location, result *models.ImportResult) ([]*models.ImportedSession, error) {
+	from, to := sessions[0].StartedAt, sessions[0].StartedAt
+	for _, session := range sessions {
+		if session.StartedAt.Before(from) {
+			from = session.StartedAt
+		}
+		if session.StartedAt.After(to) {
+			to = session.StartedAt
+		}
+	}
+
+	// Widen by a day so sessions near midnight in loc are still compared
+	logged, err := s.repo.FindLoggedExercises(ctx, userID, from.Add(-24*time.Hour), to.Add(24*time.Hour))
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing sessions: %w", err)
+	}
+	if len(logged) == 0 {
+		return sessions, nil
+	}
+
+	dayKey := func(t time.Time, exerciseID string) string {
+		return t.In(loc).Format("2006-01-02") + "|" + exerciseID
+	}
+	existing := make(map[string]bool, len(logged))
+	starts := make(map[string][]time.Time)
+	for _, l := range logged {
+		existing[dayKey(l.StartedAt, l.ExerciseID)] = true
+		starts[l.ExerciseID] = append(starts[l.ExerciseID], l.StartedAt)
+	}
+	isLogged := func(session *models.ImportedSession, exerciseID string) bool {
+		if sessionType(session) != models.SessionTypeCardio {
+			return existing[dayKey(session.StartedAt, exerciseID)]
+		}
+		for _, start := range starts[exerciseID] {
+			if d := session.StartedAt.Sub(start); d < cardioStartTolerance && d > -cardioStartTolerance {
+				return true
+			}
+		}
+		return false
+	}
+
+	var remaining []*models.ImportedSession
+	for _, session := range sessions {
+		var sets []*models.ImportedSet
+		for _, set := range session.Sets {
+			if set.ExerciseID != "" && isLogged(session, set.ExerciseID) {
+				result.SkippedSets++
+				continue
+			}
+			sets = append(sets, set)
+		}
+
+		if len(sets) == 0 {
+			result.SkippedSessions++
+			continue
+		}
+		session.Sets = sets
+		remaining = append(remaining, session)
+	}
+
+	return remaining, nil
+}
+
+// sessionType returns the session's type, strength unless the parser said otherwise
+func sessionType(session *models.ImportedSession) string {
+	if session.Type == "" {
+		return models.SessionTypeStrength
+	}
+	return session.Type
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/importer"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+const strongCSV = "Date;Workout Name;Duration;Exercise Name;Set Order;Weight;Weight Unit;Reps;RPE;Distance;Distance Unit;Seconds;Notes;Workout Notes\n" +
+	"2026-05-04 18:00:00;Push;1h 5m;Bench Press (Barbell);1;100;kg;5;8.5;;;0;;Felt good\n" +
+	"2026-05-04 18:00:00;Push;1h 5m;Bench Press (Barbell);Rest Timer;0;kg;0;;;;90;;Felt good\n" +
+	"2026-05-04 18:00:00;Push;1h 5m;bench press (barbell);2;225;lbs;3;;;;0;;Felt good\n" +
+	"2026-05-04 18:00:00;Push;1h 5m;Cable Fly;1;20;kg;12;;;;0;slow;Felt good\n" +
+	"2026-05-06 07:30:00;Run;30m;Running;1;0;kg;0;;5;km;1800;;\n"
+
+// importNow uploads an export and runs its import job the way the queue would, with the
+// sessions stored as JSON in between, and returns the completed import's result
+func importNow(service *ImportService, repo *repositories.MockImportRepository, source string, r io.Reader, opts importer.Options) (*models.ImportResult, error) {
+	var stored []byte
+	var imp *models.Import
+	var result *models.ImportResult
+	repo.CreateImportFunc = func(ctx context.Context, i *models.Import, sessions []*models.ImportedSession) error {
+		i.ID, imp = "import-1", i
+		var err error
+		stored, err = json.Marshal(sessions)
+		return err
+	}
+	repo.FindImportFunc = func(ctx context.Context, id string) (*models.Import, error) {
+		return imp, nil
+	}
+	repo.FindImportSessionsFunc = func(ctx context.Context, id string) ([]*models.ImportedSession, error) {
+		var sessions []*models.ImportedSession
+		err := json.Unmarshal(stored, &sessions)
+		return sessions, err
+	}
+	repo.CompleteImportFunc = func(ctx context.Context, id string, r *models.ImportResult) error {
+		result = r
+		return nil
+	}
+
+	if _, err := service.Import(context.Background(), "user-123", source, r, opts); err != nil {
+		return nil, err
+	}
+	userID := "user-123"
+	payload, _ := json.Marshal(&importJob{ImportID: imp.ID})
+	job := &models.Job{ID: "job-1", UserID: &userID, Kind: models.JobKindImport, Payload: payload, Attempts: 1, MaxAttempts: importAttempts}
+	if err := service.runImport(context.Background(), job); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func TestImport_StrongCreatesSessions(t *testing.T) {
+	var created []*models.ImportedSession
+	var createdExercises []string
+	mockRepo := &repositories.MockImportRepository{
+		MatchExercisesFunc: func(ctx context.Context, userID string, names []string) (map[string]string, error) {
+			return map[string]string{"Bench Press (Barbell)": "exercise-bench"}, nil
+		},
+		CreateSessionsFunc: func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+			created = sessions
+			createdExercises = newExercises
+			return nil
+		},
+	}
+
+	bus := events.NewBus()
+	userEvents, cancel := bus.Subscribe("user-123")
+	defer cancel()
+	service := NewImportService(mockRepo, bus, jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	result, err := importNow(service, mockRepo, "strong", strings.NewReader(strongCSV), importer.Options{Location: madrid, WeightUnit: "kg"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Sessions != 2 || result.Sets != 4 {
+		t.Errorf("Expected 2 sessions and 4 sets, got %d and %d", result.Sessions, result.Sets)
+	}
+	if len(result.Preview) != 0 {
+		t.Errorf("Expected no preview outside dry run, got %d entries", len(result.Preview))
+	}
+	if len(createdExercises) != 2 || createdExercises[0] != "Cable Fly" || createdExercises[1] != "Running" {
+		t.Errorf("Expected new exercises [Cable Fly Running], got %v", createdExercises)
+	}
+
+	push := created[0]
+	if !push.StartedAt.Equal(time.Date(2026, 5, 4, 16, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected start in Madrid time, got %v", push.StartedAt)
+	}
+	if push.Duration != 65*time.Minute {
+		t.Errorf("Expected 65m duration, got %v", push.Duration)
+	}
+	if len(push.Sets) != 3 {
+		t.Fatalf("Expected rest timer to be skipped, got %d sets", len(push.Sets))
+	}
+
+	second := push.Sets[1]
+	if second.ExerciseName != "Bench Press (Barbell)" || second.ExerciseID != "exercise-bench" {
+		t.Errorf("Expected case variant to match the library exercise, got %q (%q)", second.ExerciseName, second.ExerciseID)
+	}
+	if math.Abs(*second.WeightKg-102.06) > 0.01 {
+		t.Errorf("Expected 225 lbs converted to ~102.06 kg, got %v", *second.WeightKg)
+	}
+	if push.Sets[0].RPE == nil || *push.Sets[0].RPE != 9 {
+		t.Errorf("Expected RPE 8.5 rounded to 9, got %v", push.Sets[0].RPE)
+	}
+
+	run := created[1].Sets[0]
+	if run.WeightKg != nil || run.Reps != nil {
+		t.Errorf("Expected zero weight and reps to be blank, got %v and %v", run.WeightKg, run.Reps)
+	}
+	if run.DistanceM == nil || *run.DistanceM != 5000 || run.DurationSec == nil || *run.DurationSec != 1800 {
+		t.Errorf("Expected 5000 m over 1800 s, got %v and %v", run.DistanceM, run.DurationSec)
+	}
+
+	select {
+	case event := <-userEvents:
+		if event.Type != models.UserEventImportFinished || !strings.Contains(string(event.Data), `"sessions_created":2`) {
+			t.Errorf("Unexpected import event: %s %s", event.Type, event.Data)
+		}
+	default:
+		t.Error("Expected an import.finished event")
+	}
+}
+
+func TestImport_QueuesJob(t *testing.T) {
+	var queued *models.Job
+	mockRepo := &repositories.MockImportRepository{
+		CreateSessionsFunc: func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+			t.Error("Expected sessions stored by the job, not on upload")
+			return nil
+		},
+	}
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{
+		EnqueueFunc: func(ctx context.Context, job *models.Job) error {
+			queued = job
+			return nil
+		},
+	}))
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	imp, err := service.Import(context.Background(), "user-123", "strong", strings.NewReader(strongCSV), importer.Options{Location: madrid, WeightUnit: "kg"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if imp.Status != models.ImportStatusPending || imp.Timezone != "Europe/Madrid" {
+		t.Errorf("Expected a pending import in Europe/Madrid, got %+v", imp)
+	}
+	if queued == nil || queued.Kind != models.JobKindImport || string(queued.Payload) != `{"import_id":"mock-import-id"}` {
+		t.Errorf("Expected an import job for the import, got %+v", queued)
+	}
+}
+
+func TestGetImport_OtherUsersHidden(t *testing.T) {
+	mockRepo := &repositories.MockImportRepository{
+		FindImportFunc: func(ctx context.Context, id string) (*models.Import, error) {
+			return &models.Import{ID: id, UserID: "other-user", Status: models.ImportStatusCompleted}, nil
+		},
+	}
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	if _, err := service.GetImport(context.Background(), "import-1", "user-123"); !errors.Is(err, ErrImportNotFound) {
+		t.Errorf("Expected ErrImportNotFound, got %v", err)
+	}
+}
+
+func TestImport_StrongDryRun(t *testing.T) {
+	mockRepo := &repositories.MockImportRepository{
+		CreateSessionsFunc: func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+			t.Error("Expected dry run not to create sessions")
+			return nil
+		},
+	}
+
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	result, err := service.Preview(context.Background(), "user-123", "strong", strings.NewReader(strongCSV), importer.Options{WeightUnit: "kg"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !result.DryRun || len(result.Preview) != 2 {
+		t.Fatalf("Expected a dry run preview of 2 sessions, got %+v", result)
+	}
+	if result.Preview[0].Name != "Push" || result.Preview[0].Exercises != 2 || result.Preview[0].Sets != 3 {
+		t.Errorf("Expected Push with 2 exercises and 3 sets, got %+v", result.Preview[0])
+	}
+	if len(result.NewExercises) != 3 {
+		t.Errorf("Expected 3 new exercises, got %v", result.NewExercises)
+	}
+}
+
+func TestImport_SkipsExercisesLoggedThatDay(t *testing.T) {
+	var created []*models.ImportedSession
+	mockRepo := &repositories.MockImportRepository{
+		MatchExercisesFunc: func(ctx context.Context, userID string, names []string) (map[string]string, error) {
+			return map[string]string{"Bench Press (Barbell)": "exercise-bench", "Running": "exercise-run"}, nil
+		},
+		FindLoggedExercisesFunc: func(ctx context.Context, userID string, from, to time.Time) ([]*models.LoggedExercise, error) {
+			// Logged by another app at a different time on the same days
+			return []*models.LoggedExercise{
+				{StartedAt: time.Date(2026, 5, 4, 9, 0, 0, 0, time.UTC), ExerciseID: "exercise-bench"},
+				{StartedAt: time.Date(2026, 5, 6, 20, 0, 0, 0, time.UTC), ExerciseID: "exercise-run"},
+			}, nil
+		},
+		CreateSessionsFunc: func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+			created = sessions
+			return nil
+		},
+	}
+
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	result, err := importNow(service, mockRepo, "strong", strings.NewReader(strongCSV), importer.Options{WeightUnit: "kg"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.SkippedSets != 3 || result.SkippedSessions != 1 {
+		t.Errorf("Expected 3 skipped sets and 1 skipped session, got %+v", result)
+	}
+	if result.Sessions != 1 || result.Sets != 1 {
+		t.Errorf("Expected 1 session with 1 set left, got %d and %d", result.Sessions, result.Sets)
+	}
+	if len(created) != 1 || created[0].Sets[0].ExerciseName != "Cable Fly" {
+		t.Errorf("Expected only the Cable Fly set to be created, got %d sessions", len(created))
+	}
+	if len(result.MatchedNames) != 0 {
+		t.Errorf("Expected no matched exercises among imported sets, got %v", result.MatchedNames)
+	}
+}
+
+func TestImport_CardioSkipsOnlySameActivity(t *testing.T) {
+	const tcx = `<TrainingCenterDatabase><Activities>
+  <Activity Sport="Running"><Id>2026-05-06T07:30:00Z</Id>
+   <Lap StartTime="2026-05-06T07:30:00Z"><TotalTimeSeconds>1500</TotalTimeSeconds><DistanceMeters>5000</DistanceMeters></Lap>
+  </Activity>
+  <Activity Sport="Running"><Id>2026-05-06T18:00:00Z</Id>
+   <Lap StartTime="2026-05-06T18:00:00Z"><TotalTimeSeconds>1200</TotalTimeSeconds><DistanceMeters>4000</DistanceMeters></Lap>
+  </Activity>
+ </Activities></TrainingCenterDatabase>`
+
+	var created []*models.ImportedSession
+	mockRepo := &repositories.MockImportRepository{
+		MatchExercisesFunc: func(ctx context.Context, userID string, names []string) (map[string]string, error) {
+			return map[string]string{"Running": "exercise-run"}, nil
+		},
+		FindLoggedExercisesFunc: func(ctx context.Context, userID string, from, to time.Time) ([]*models.LoggedExercise, error) {
+			// The morning run, already synced from the watch with a slightly different clock
+			return []*models.LoggedExercise{{StartedAt: time.Date(2026, 5, 6, 7, 30, 40, 0, time.UTC), ExerciseID: "exercise-run"}}, nil
+		},
+		CreateSessionsFunc: func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+			created = sessions
+			return nil
+		},
+	}
+
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	result, err := importNow(service, mockRepo, "tcx", strings.NewReader(tcx), importer.Options{})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.SkippedSessions != 1 || result.Sessions != 1 {
+		t.Errorf("Expected the morning run skipped and the evening run kept, got %+v", result)
+	}
+	if len(created) != 1 || created[0].StartedAt.Hour() != 18 || created[0].Type != models.SessionTypeCardio || len(created[0].Laps) != 1 {
+		t.Errorf("Expected the evening run created as cardio with its lap, got %+v", created)
+	}
+}
+
+func TestImport_MatchesNameCandidates(t *testing.T) {
+	var lookedUp []string
+	mockRepo := &repositories.MockImportRepository{
+		MatchExercisesFunc: func(ctx context.Context, userID string, names []string) (map[string]string, error) {
+			lookedUp = names
+			return map[string]string{"Barbell Bench Press": "exercise-bench", "Cable Fly": "exercise-fly"}, nil
+		},
+	}
+
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	result, err := service.Preview(context.Background(), "user-123", "strong", strings.NewReader(strongCSV), importer.Options{WeightUnit: "kg"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(lookedUp) != 5 {
+		t.Errorf("Expected 5 candidate names looked up, got %v", lookedUp)
+	}
+	if len(result.MatchedNames) != 2 || result.MatchedNames[0] != "Bench Press (Barbell)" {
+		t.Errorf("Expected bench press matched through its candidate, got %v", result.MatchedNames)
+	}
+	if len(result.NewExercises) != 1 || result.NewExercises[0] != "Running" {
+		t.Errorf("Expected only Running to be new, got %v", result.NewExercises)
+	}
+}
+
+func TestImport_Hevy(t *testing.T) {
+	var created []*models.ImportedSession
+	mockRepo := &repositories.MockImportRepository{
+		CreateSessionsFunc: func(ctx context.Context, userID string, sessions []*models.ImportedSession, newExercises []string) error {
+			created = sessions
+			return nil
+		},
+	}
+
+	service := NewImportService(mockRepo, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	csv := `"title","start_time","end_time","description","exercise_title","superset_id","exercise_notes","set_index","set_type","weight_kg","reps","distance_km","duration_seconds","rpe"` + "\n" +
+		`"Legs","4 May 2026, 18:00","4 May 2026, 19:10","","Squat (Barbell)","","Belt on","0","warmup","60","8","","",""` + "\n" +
+		`"Legs","4 May 2026, 18:00","4 May 2026, 19:10","","Squat (Barbell)","","Belt on","1","normal","140","5","","","9"` + "\n"
+
+	result, err := importNow(service, mockRepo, "hevy", strings.NewReader(csv), importer.Options{WeightUnit: "kg"})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Source != "hevy" || result.Sessions != 1 || result.Sets != 2 {
+		t.Errorf("Expected 1 hevy session with 2 sets, got %+v", result)
+	}
+	if created[0].Duration != 70*time.Minute {
+		t.Errorf("Expected 70m duration, got %v", created[0].Duration)
+	}
+}
+
+func TestImport_UnsupportedSource(t *testing.T) {
+	service := NewImportService(&repositories.MockImportRepository{}, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	_, err := service.Import(context.Background(), "user-123", "fitbod", strings.NewReader(""), importer.Options{})
+
+	if !errors.Is(err, importer.ErrUnsupportedSource) {
+		t.Errorf("Expected ErrUnsupportedSource, got %v", err)
+	}
+}
+
+func TestImport_StrongInvalidFile(t *testing.T) {
+	tests := []struct {
+		name string
+		csv  string
+	}{
+		{"empty", ""},
+		{"missing exercise column", "Date,Workout Name\n2026-05-04 18:00:00,Push\n"},
+		{"bad date", "Date,Exercise Name\nyesterday,Squat\n"},
+		{"bad weight", "Date,Exercise Name,Weight\n2026-05-04 18:00:00,Squat,heavy\n"},
+	}
+
+	service := NewImportService(&repositories.MockImportRepository{}, events.NewBus(), jobs.NewQueue(&repositories.MockJobRepository{}))
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.Import(context.Background(), "user-123", "strong", strings.NewReader(tt.csv), importer.Options{WeightUnit: "kg"})
+			if !errors.Is(err, importer.ErrInvalidFile) {
+				t.Errorf("Expected ErrInvalidFile, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInjuryNotFound     = domainerr.New(domainerr.NotFound, "injury not found")
+	ErrInvalidInjuryDates = domainerr.New(domainerr.Validation, "ended_on must not be before started_on")
+)
+
+// InjuryService handles users' injuries and the exercises they contraindicate
+// Which body parts a muscle group loads is kept in the database (muscle_group_body_parts),
+// which also warns users starting a session from a workout that conflicts with an injury.
+type InjuryService struct {
+	repo     repositories.InjuryRepository
+	profiles repositories.ProfileRepository
+	audit    *AuditLogService
+	now      func() time.Time
+}
+
+// NewInjuryService creates a new injury service; writes are audited unless audit is nil
+func NewInjuryService(repo repositories.InjuryRepository, profiles repositories.ProfileRepository, audit *AuditLogService) *InjuryService {
+	return &InjuryService{repo: repo, profiles: profiles, audit: audit, now: time.Now}
+}
+
+// ListInjuries retrieves the user's injuries, or only those active today
+func (s *InjuryService) ListInjuries(ctx context.Context, userID string, activeOnly bool) ([]*models.Injury, error) {
+	injuries, err := s.repo.FindByUser(ctx, userID, activeOnly)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list injuries: %w", err)
+	}
+
+	return injuries, nil
+}
+
+// RecordInjury records an injury that started today or earlier
+func (s *InjuryService) RecordInjury(ctx context.Context, userID string, req *models.InjuryRequest) (*models.Injury, error) {
+	injury, err := s.injuryFromRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceInjury, "", nil, func() (*models.Injury, error) {
+		if err := s.repo.Create(ctx, injury); err != nil {
+			return nil, fmt.Errorf("failed to record injury: %w", err)
+		}
+		return injury, nil
+	})
+}
+
+// UpdateInjury replaces one of the user's injuries, e.g. to set the day it ended
+func (s *InjuryService) UpdateInjury(ctx context.Context, userID string, id string, req *models.InjuryRequest) (*models.Injury, error) {
+	before, err := s.findInjury(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	injury, err := s.injuryFromRequest(ctx, userID, req)
+	if err != nil {
+		return nil, err
+	}
+	injury.ID = id
+
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceInjury, id, before, func() (*models.Injury, error) {
+		if err := s.repo.Update(ctx, injury); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInjuryNotFound
+			}
+			return nil, fmt.Errorf("failed to update injury: %w", err)
+		}
+		return injury, nil
+	})
+}
+
+// DeleteInjury removes one of the user's injuries
+func (s *InjuryService) DeleteInjury(ctx context.Context, userID string, id string) error {
+	injury, err := s.findInjury(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceInjury, id, injury, func() (*models.Injury, error) {
+		if err := s.repo.Delete(ctx, userID, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrInjuryNotFound
+			}
+			return nil, fmt.Errorf("failed to delete injury: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// findInjury retrieves one of the user's injuries
+func (s *InjuryService) findInjury(ctx context.Context, userID string, id string) (*models.Injury, error) {
+	injury, err := s.repo.FindByID(ctx, userID, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrInjuryNotFound
+		}
+		return nil, fmt.Errorf("failed to get injury: %w", err)
+	}
+	return injury, nil
+}
+
+// injuryFromRequest validates the request's days: the injury can't start after today in
+// the user's timezone, nor end before it started
+func (s *InjuryService) injuryFromRequest(ctx context.Context, userID string, req *models.InjuryRequest) (*models.Injury, error) {
+	startedOn, err := time.Parse(models.InjuryDateLayout, req.StartedOn)
+	if err != nil {
+		return nil, ErrInvalidInjuryDates
+	}
+	if _, err := pastDay(ctx, s.profiles, s.now(), userID, startedOn); err != nil {
+		return nil, err
+	}
+
+	var endedOn *time.Time
+	if req.EndedOn != nil {
+		parsed, err := time.Parse(models.InjuryDateLayout, *req.EndedOn)
+		if err != nil || parsed.Before(startedOn) {
+			return nil, ErrInvalidInjuryDates
+		}
+		endedOn = &parsed
+	}
+
+	bodyParts := slices.Clone(req.BodyParts)
+	slices.Sort(bodyParts)
+
+	return &models.Injury{
+		UserID:    userID,
+		Name:      req.Name,
+		BodyParts: slices.Compact(bodyParts),
+		StartedOn: startedOn,
+		EndedOn:   endedOn,
+		Notes:     req.Notes,
+	}, nil
+}
+
+// contraindicated returns the exercise flagged with the injured body parts it loads, as a
+// copy since exercises may be shared through the cache, or the exercise itself when it
+// loads none
+func contraindicated(exercise *models.Exercise, byMuscleGroup map[string][]string) *models.Exercise {
+	if exercise == nil || exercise.MuscleGroup == nil {
+		return exercise
+	}
+	parts, ok := byMuscleGroup[*exercise.MuscleGroup]
+	if !ok {
+		return exercise
+	}
+
+	flagged := *exercise
+	flagged.Contraindications = parts
+	return &flagged
+}
+
+// flagContraindicated flags the exercises that load the user's injured body parts, and
+// drops them instead when exclude is set
+func flagContraindicated(ctx context.Context, injuries repositories.InjuryRepository, userID string, exercises []*models.Exercise, exclude bool) ([]*models.Exercise, error) {
+	byMuscleGroup, err := injuries.Contraindications(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contraindications: %w", err)
+	}
+	if len(byMuscleGroup) == 0 {
+		return exercises, nil
+	}
+
+	flagged := make([]*models.Exercise, 0, len(exercises))
+	for _, e := range exercises {
+		e = contraindicated(e, byMuscleGroup)
+		if exclude && len(e.Contraindications) > 0 {
+			continue
+		}
+		flagged = append(flagged, e)
+	}
+	return flagged, nil
+}
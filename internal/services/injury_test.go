@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestRecordInjury_Dates(t *testing.T) {
+	profiles := &repositories.MockProfileRepository{
+		FindProfileFunc: func(ctx context.Context, userID string) (*models.Profile, error) {
+			return &models.Profile{UserID: userID, Timezone: "Pacific/Auckland"}, nil
+		},
+	}
+	service := NewInjuryService(&repositories.MockInjuryRepository{}, profiles, nil)
+	// Already June 2 in Auckland
+	service.now = func() time.Time { return time.Date(2026, 6, 1, 14, 0, 0, 0, time.UTC) }
+
+	earlier := "2026-05-20"
+	tests := []struct {
+		name      string
+		startedOn string
+		endedOn   *string
+		wantErr   error
+	}{
+		{"today in the user's timezone", "2026-06-02", nil, nil},
+		{"tomorrow", "2026-06-03", nil, ErrFutureDay},
+		{"ends before it starts", "2026-05-25", &earlier, ErrInvalidInjuryDates},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.RecordInjury(context.Background(), "user-123", &models.InjuryRequest{
+				Name:      "Sprained ankle",
+				BodyParts: []string{"ankle"},
+				StartedOn: tt.startedOn,
+				EndedOn:   tt.endedOn,
+			})
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRecordInjury_DeduplicatesBodyParts(t *testing.T) {
+	service := NewInjuryService(&repositories.MockInjuryRepository{}, &repositories.MockProfileRepository{}, nil)
+
+	injury, err := service.RecordInjury(context.Background(), "user-123", &models.InjuryRequest{
+		Name:      "Tennis elbow",
+		BodyParts: []string{"wrist", "elbow", "wrist"},
+		StartedOn: "2026-01-10",
+	})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !slices.Equal(injury.BodyParts, []string{"elbow", "wrist"}) {
+		t.Errorf("Expected elbow and wrist, got %v", injury.BodyParts)
+	}
+}
+
+func TestListExercises_FlagsContraindicated(t *testing.T) {
+	chest, quads := "chest", "quads"
+	library := []*models.Exercise{
+		{ID: "ex-1", Name: "Bench Press", IsPublic: true, MuscleGroup: &chest},
+		{ID: "ex-2", Name: "Squat", IsPublic: true, MuscleGroup: &quads},
+		{ID: "ex-3", Name: "Stretch", IsPublic: true},
+	}
+	mockRepo := &repositories.MockExerciseRepository{
+		FindVisibleFunc: func(ctx context.Context, userID string) ([]*models.Exercise, error) {
+			return library, nil
+		},
+	}
+	injuries := &repositories.MockInjuryRepository{
+		ContraindicationsFunc: func(ctx context.Context, userID string) (map[string][]string, error) {
+			return map[string][]string{"chest": {"shoulder"}}, nil
+		},
+	}
+	policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{})
+	service := NewExerciseService(mockRepo, injuries, policy, nil)
+
+	list, err := service.ListExercises(context.Background(), "user-123", false, false)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list) != 3 || !slices.Equal(list[0].Contraindications, []string{"shoulder"}) || list[1].Contraindications != nil {
+		t.Errorf("Expected only the bench press flagged for the shoulder, got %+v", list)
+	}
+	if library[0].Contraindications != nil {
+		t.Error("Expected the shared exercise to be copied, not flagged in place")
+	}
+
+	list, err = service.ListExercises(context.Background(), "user-123", false, true)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list) != 2 || list[0].ID != "ex-2" {
+		t.Errorf("Expected the bench press to be left out, got %+v", list)
+	}
+}
+
+func TestGetWorkout_FlagsOwnersInjuries(t *testing.T) {
+	back := "back"
+	mockRepo := &repositories.MockWorkoutRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Workout, error) {
+			return &models.Workout{ID: id, UserID: "user-123", Exercises: []*models.WorkoutExercise{
+				{ID: "we-1", Exercise: &models.Exercise{ID: "ex-1", Name: "Deadlift", MuscleGroup: &back}},
+			}}, nil
+		},
+	}
+	var checked string
+	injuries := &repositories.MockInjuryRepository{
+		ContraindicationsFunc: func(ctx context.Context, userID string) (map[string][]string, error) {
+			checked = userID
+			return map[string][]string{"back": {"lower_back"}}, nil
+		},
+	}
+	service := NewWorkoutService(mockRepo, injuries, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	workout, err := service.GetWorkout(context.Background(), "workout-1", "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if checked != "user-123" || !slices.Equal(workout.Exercises[0].Exercise.Contraindications, []string{"lower_back"}) {
+		t.Errorf("Expected the deadlift flagged for the owner's lower back, got %v for %q",
+			workout.Exercises[0].Exercise.Contraindications, checked)
+	}
+}
@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrJobNotFound      = domainerr.New(domainerr.NotFound, "job not found")
+	ErrJobNotDead       = domainerr.New(domainerr.Conflict, "only dead jobs can be retried")
+	ErrInvalidJobFilter = domainerr.New(domainerr.Validation, "invalid job filter")
+)
+
+const (
+	// defaultJobPage and maxJobPage bound a page of jobs
+	defaultJobPage = 50
+	maxJobPage     = 200
+)
+
+// JobService reports on the background job queue: users see their own jobs, operators
+// see every job and requeue dead ones
+type JobService struct {
+	repo repositories.JobRepository
+}
+
+// NewJobService creates a new job service
+func NewJobService(repo repositories.JobRepository) *JobService {
+	return &JobService{repo: repo}
+}
+
+// ListJobs retrieves the user's most recent jobs
+func (s *JobService) ListJobs(ctx context.Context, userID string, filter models.JobFilter) ([]*models.Job, error) {
+	filter.UserID = userID
+	return s.ListAllJobs(ctx, filter)
+}
+
+// GetJob retrieves one of the user's jobs
+func (s *JobService) GetJob(ctx context.Context, id string, userID string) (*models.Job, error) {
+	job, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// Other users' and system jobs are indistinguishable from missing ones
+	if job.UserID == nil || *job.UserID != userID {
+		return nil, ErrJobNotFound
+	}
+	return job, nil
+}
+
+// ListAllJobs retrieves the most recent jobs matching the filter, for operators
+func (s *JobService) ListAllJobs(ctx context.Context, filter models.JobFilter) ([]*models.Job, error) {
+	if filter.Status != "" && !slices.Contains(models.JobStatuses, filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %q, expected one of %v", ErrInvalidJobFilter, filter.Status, models.JobStatuses)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultJobPage
+	}
+	filter.Limit = min(filter.Limit, maxJobPage)
+
+	jobs, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+	return jobs, nil
+}
+
+// Counts counts the jobs of each kind and status, for operators
+func (s *JobService) Counts(ctx context.Context) ([]*models.JobCount, error) {
+	counts, err := s.repo.Counts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count jobs: %w", err)
+	}
+	return counts, nil
+}
+
+// RetryJob queues a dead job again with a fresh set of attempts, for operators
+func (s *JobService) RetryJob(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.find(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Status != models.JobStatusDead {
+		return nil, ErrJobNotDead
+	}
+
+	job, err = s.repo.Requeue(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// Deleted or requeued by someone else in the meantime
+			return nil, ErrJobNotDead
+		}
+		return nil, fmt.Errorf("failed to requeue job: %w", err)
+	}
+	return job, nil
+}
+
+func (s *JobService) find(ctx context.Context, id string) (*models.Job, error) {
+	job, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrJobNotFound
+		}
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	return job, nil
+}
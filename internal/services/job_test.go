@@ -0,0 +1,89 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestListJobs_ScopesToUserAndClampsLimit(t *testing.T) {
+	var filters []models.JobFilter
+	mockRepo := &repositories.MockJobRepository{
+		ListFunc: func(ctx context.Context, filter models.JobFilter) ([]*models.Job, error) {
+			filters = append(filters, filter)
+			return []*models.Job{}, nil
+		},
+	}
+	service := NewJobService(mockRepo)
+
+	if _, err := service.ListJobs(context.Background(), "user-123", models.JobFilter{UserID: "user-789"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.ListAllJobs(context.Background(), models.JobFilter{Status: models.JobStatusDead, Limit: 1000}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if filters[0].UserID != "user-123" || filters[0].Limit != defaultJobPage || filters[1].Limit != maxJobPage {
+		t.Errorf("Expected the user's jobs and clamped limits, got %+v", filters)
+	}
+
+	if _, err := service.ListAllJobs(context.Background(), models.JobFilter{Status: "failed"}); !errors.Is(err, ErrInvalidJobFilter) {
+		t.Errorf("Expected ErrInvalidJobFilter, got %v", err)
+	}
+}
+
+func TestGetJob_HidesOtherUsersJobs(t *testing.T) {
+	owner := "user-123"
+	jobs := map[string]*models.Job{
+		"own":    {ID: "own", UserID: &owner},
+		"system": {ID: "system"},
+	}
+	mockRepo := &repositories.MockJobRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Job, error) {
+			if job, ok := jobs[id]; ok {
+				return job, nil
+			}
+			return (&repositories.MockJobRepository{}).FindByID(ctx, id)
+		},
+	}
+	service := NewJobService(mockRepo)
+
+	if _, err := service.GetJob(context.Background(), "own", "user-123"); err != nil {
+		t.Errorf("Expected the user's own job, got %v", err)
+	}
+	for _, tc := range []struct{ id, userID string }{{"own", "user-789"}, {"system", "user-123"}, {"missing", "user-123"}} {
+		if _, err := service.GetJob(context.Background(), tc.id, tc.userID); !errors.Is(err, ErrJobNotFound) {
+			t.Errorf("Expected ErrJobNotFound for %s as %s, got %v", tc.id, tc.userID, err)
+		}
+	}
+}
+
+func TestRetryJob_OnlyDead(t *testing.T) {
+	status := models.JobStatusDone
+	requeued := false
+	mockRepo := &repositories.MockJobRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Job, error) {
+			return &models.Job{ID: id, Status: status}, nil
+		},
+		RequeueFunc: func(ctx context.Context, id string) (*models.Job, error) {
+			requeued = true
+			return &models.Job{ID: id, Status: models.JobStatusPending}, nil
+		},
+	}
+	service := NewJobService(mockRepo)
+
+	if _, err := service.RetryJob(context.Background(), "job-1"); !errors.Is(err, ErrJobNotDead) || requeued {
+		t.Errorf("Expected a finished job not to be requeued, got %v", err)
+	}
+
+	status = models.JobStatusDead
+	job, err := service.RetryJob(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !requeued || job.Status != models.JobStatusPending {
+		t.Errorf("Expected the dead job to be requeued, got %+v", job)
+	}
+}
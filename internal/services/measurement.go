@@ -0,0 +1,226 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrUnsupportedSource = domainerr.New(domainerr.Validation, "unsupported measurement source, expected withings or renpho")
+	ErrInvalidImportFile = domainerr.New(domainerr.Validation, "invalid import file")
+)
+
+const (
+	maxImportRows = 50000
+
+	poundsToKg = 0.45359237
+)
+
+// scaleColumns maps normalized CSV headers (lowercase, no spaces) to measurement fields
+// Covers Withings ("Date", "Weight (kg)", "Fat mass (kg)", "Muscle mass (kg)") and
+// Renpho ("Time of Measurement", "Weight(kg)" or "Weight(lb)", "Body Fat(%)", "Muscle Mass(kg)").
+var scaleColumns = map[string]string{
+	"date":              "time",
+	"timeofmeasurement": "time",
+	"measuredat":        "time",
+	"weight(kg)":        "weight_kg",
+	"weight(lb)":        "weight_lb",
+	"bodyfat(%)":        "fat_pct",
+	"fat(%)":            "fat_pct",
+	"fatmass(kg)":       "fat_kg",
+	"musclemass(kg)":    "muscle_kg",
+	"musclemass(lb)":    "muscle_lb",
+}
+
+// scaleTimeLayouts are the timestamp formats seen in scale exports
+var scaleTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006.01.02 15:04:05",
+	"2006/01/02 15:04:05",
+	"2006-01-02",
+}
+
+// MeasurementService handles body measurements and smart scale imports
+type MeasurementService struct {
+	repo repositories.MeasurementRepository
+}
+
+// NewMeasurementService creates a new measurement service
+func NewMeasurementService(repo repositories.MeasurementRepository) *MeasurementService {
+	return &MeasurementService{repo: repo}
+}
+
+// ListMeasurements retrieves the user's measurements, newest first
+func (s *MeasurementService) ListMeasurements(ctx context.Context, userID string) ([]*models.BodyMeasurement, error) {
+	measurements, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list measurements: %w", err)
+	}
+
+	return measurements, nil
+}
+
+// ImportScaleCSV imports a Withings or Renpho CSV export.
+// Timestamps without an offset are read in loc (the user's timezone). Rows whose
+// timestamp the user already has are skipped, so re-importing an export is harmless.
+func (s *MeasurementService) ImportScaleCSV(ctx context.Context, userID string, source string, loc *time.Location, r io.Reader) (*models.MeasurementImportResult, error) {
+	if source != models.MeasurementSourceWithings && source != models.MeasurementSourceRenpho {
+		return nil, ErrUnsupportedSource
+	}
+
+	measurements, err := parseScaleCSV(r, loc)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range measurements {
+		m.UserID = userID
+		m.Source = source
+	}
+
+	result := &models.MeasurementImportResult{Source: source, Rows: len(measurements)}
+	if len(measurements) == 0 {
+		return result, nil
+	}
+
+	imported, err := s.repo.CreateMany(ctx, measurements)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import measurements: %w", err)
+	}
+	result.Imported = imported
+	result.Skipped = int64(len(measurements)) - imported
+
+	return result, nil
+}
+
+// parseScaleCSV reads a scale export into measurements
+// Rows without a weight, body fat or muscle value are ignored.
+func parseScaleCSV(r io.Reader, loc *time.Location) ([]*models.BodyMeasurement, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidImportFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(name, "\ufeff")), ""))
+		if field, ok := scaleColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	if _, ok := columns["time"]; !ok {
+		return nil, fmt.Errorf("%w: no date column found", ErrInvalidImportFile)
+	}
+
+	var measurements []*models.BodyMeasurement
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImportFile, line, err)
+		}
+		if len(measurements) >= maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImportFile, maxImportRows)
+		}
+
+		m, err := parseScaleRecord(record, columns, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImportFile, line, err)
+		}
+		if m != nil {
+			measurements = append(measurements, m)
+		}
+	}
+
+	return measurements, nil
+}
+
+func parseScaleRecord(record []string, columns map[string]int, loc *time.Location) (*models.BodyMeasurement, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+	number := func(name string, factor float64) (*float64, error) {
+		raw := strings.TrimSuffix(field(name), "%")
+		if raw == "" || raw == "--" {
+			return nil, nil
+		}
+		v, err := strconv.ParseFloat(strings.Replace(raw, ",", ".", 1), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s value %q", name, raw)
+		}
+		v *= factor
+		return &v, nil
+	}
+
+	measuredAt, err := parseScaleTime(field("time"), loc)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &models.BodyMeasurement{MeasuredAt: measuredAt}
+	if m.WeightKg, err = number("weight_kg", 1); err != nil {
+		return nil, err
+	}
+	if m.WeightKg == nil {
+		if m.WeightKg, err = number("weight_lb", poundsToKg); err != nil {
+			return nil, err
+		}
+	}
+	if m.BodyFatPercentage, err = number("fat_pct", 1); err != nil {
+		return nil, err
+	}
+	if m.MuscleMassKg, err = number("muscle_kg", 1); err != nil {
+		return nil, err
+	}
+	if m.MuscleMassKg == nil {
+		if m.MuscleMassKg, err = number("muscle_lb", poundsToKg); err != nil {
+			return nil, err
+		}
+	}
+
+	// Withings reports fat as a mass; derive the percentage from the weight
+	if m.BodyFatPercentage == nil && m.WeightKg != nil && *m.WeightKg > 0 {
+		fatKg, err := number("fat_kg", 1)
+		if err != nil {
+			return nil, err
+		}
+		if fatKg != nil {
+			pct := *fatKg / *m.WeightKg * 100
+			m.BodyFatPercentage = &pct
+		}
+	}
+
+	if m.WeightKg == nil && m.BodyFatPercentage == nil && m.MuscleMassKg == nil {
+		return nil, nil
+	}
+
+	return m, nil
+}
+
+func parseScaleTime(raw string, loc *time.Location) (time.Time, error) {
+	for _, layout := range scaleTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", raw)
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestImportScaleCSV_Withings(t *testing.T) {
+	var saved []*models.BodyMeasurement
+	mockRepo := &repositories.MockMeasurementRepository{
+		CreateManyFunc: func(ctx context.Context, measurements []*models.BodyMeasurement) (int64, error) {
+			saved = measurements
+			return 1, nil
+		},
+	}
+
+	service := NewMeasurementService(mockRepo)
+
+	csv := "\ufeffDate,Weight (kg),Fat mass (kg),Bone mass (kg),Muscle mass (kg),Hydration (kg),Comments\n" +
+		"2026-06-01 07:30:00,80.0,16.0,3.2,60.5,45.1,\n" +
+		"2026-06-02 07:31:00,79.6,,,,,\n"
+
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	result, err := service.ImportScaleCSV(context.Background(), "user-123", "withings", madrid, strings.NewReader(csv))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Rows != 2 || result.Imported != 1 || result.Skipped != 1 {
+		t.Errorf("Expected 2 rows, 1 imported, 1 skipped, got %+v", result)
+	}
+
+	first := saved[0]
+	if first.UserID != "user-123" || first.Source != models.MeasurementSourceWithings {
+		t.Errorf("Expected user and source to be set, got %q and %q", first.UserID, first.Source)
+	}
+	if !first.MeasuredAt.Equal(time.Date(2026, 6, 1, 5, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected timestamp in Madrid time, got %v", first.MeasuredAt.UTC())
+	}
+	if first.BodyFatPercentage == nil || math.Abs(*first.BodyFatPercentage-20) > 0.001 {
+		t.Errorf("Expected body fat derived as 20%%, got %v", first.BodyFatPercentage)
+	}
+	if first.MuscleMassKg == nil || *first.MuscleMassKg != 60.5 {
+		t.Errorf("Expected muscle mass 60.5, got %v", first.MuscleMassKg)
+	}
+}
+
+func TestImportScaleCSV_RenphoPounds(t *testing.T) {
+	var saved []*models.BodyMeasurement
+	mockRepo := &repositories.MockMeasurementRepository{
+		CreateManyFunc: func(ctx context.Context, measurements []*models.BodyMeasurement) (int64, error) {
+			saved = measurements
+			return int64(len(measurements)), nil
+		},
+	}
+
+	service := NewMeasurementService(mockRepo)
+
+	csv := "Time of Measurement,Weight(lb),BMI,Body Fat(%),Muscle Mass(lb)\n" +
+		"2026.06.01 07:30:00,176.4,24.1,18.5%,--\n"
+
+	_, err := service.ImportScaleCSV(context.Background(), "user-123", "renpho", time.UTC, strings.NewReader(csv))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved) != 1 {
+		t.Fatalf("Expected 1 measurement, got %d", len(saved))
+	}
+	if math.Abs(*saved[0].WeightKg-80.01) > 0.01 {
+		t.Errorf("Expected weight converted to ~80.01kg, got %v", *saved[0].WeightKg)
+	}
+	if *saved[0].BodyFatPercentage != 18.5 || saved[0].MuscleMassKg != nil {
+		t.Errorf("Expected 18.5%% body fat and no muscle mass, got %v and %v", *saved[0].BodyFatPercentage, saved[0].MuscleMassKg)
+	}
+}
+
+func TestImportScaleCSV_Invalid(t *testing.T) {
+	service := NewMeasurementService(&repositories.MockMeasurementRepository{})
+
+	tests := []struct {
+		name    string
+		source  string
+		csv     string
+		wantErr error
+	}{
+		{"unknown source", "fitbit", "Date,Weight (kg)\n", ErrUnsupportedSource},
+		{"no date column", "withings", "Weight (kg)\n80\n", ErrInvalidImportFile},
+		{"bad date", "withings", "Date,Weight (kg)\nyesterday,80\n", ErrInvalidImportFile},
+		{"bad number", "withings", "Date,Weight (kg)\n2026-06-01,heavy\n", ErrInvalidImportFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ImportScaleCSV(context.Background(), "user-123", tt.source, time.UTC, strings.NewReader(tt.csv))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
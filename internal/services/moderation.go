@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrNotInLibrary    = domainerr.New(domainerr.Unprocessable, "only public exercises and shared workouts can be reported")
+	ErrCannotReportOwn = domainerr.New(domainerr.Validation, "you can't report your own content")
+	ErrAlreadyReported = domainerr.New(domainerr.Conflict, "you already reported this")
+	ErrLibraryNotFound = domainerr.New(domainerr.NotFound, "not in the public library")
+)
+
+const (
+	defaultQueueLimit = 50
+	maxQueueLimit     = 200
+)
+
+// ModerationService handles reports of public library content and the admin actions that
+// resolve them. The library is made of public exercises and workouts shared in an
+// organization; users who can see an entry may report it, and admins approve (keep as is),
+// edit, merge or unpublish it. Unpublishing leaves content private to its owner, so
+// workouts and sessions already using it are unaffected.
+type ModerationService struct {
+	repo      repositories.ModerationRepository
+	exercises repositories.ExerciseRepository
+	workouts  repositories.WorkoutRepository
+	policy    AccessPolicy
+	cache     *cache.Cache
+	audit     *AuditLogService
+}
+
+// NewModerationService creates a new moderation service; a nil cache reads every time, and
+// admin edits are audited unless audit is nil
+func NewModerationService(repo repositories.ModerationRepository, exercises repositories.ExerciseRepository, workouts repositories.WorkoutRepository, policy AccessPolicy, c *cache.Cache, audit *AuditLogService) *ModerationService {
+	return &ModerationService{repo: repo, exercises: exercises, workouts: workouts, policy: policy, cache: c, audit: audit}
+}
+
+// ReportContent reports a public exercise or a workout shared in one of the actor's
+// organizations for admins to review
+func (s *ModerationService) ReportContent(ctx context.Context, actorID string, resourceType string, resourceID string, req *models.ReportContentRequest) (*models.ContentReport, error) {
+	var ownerID string
+	switch resourceType {
+	case models.ReportResourceExercise:
+		exercise, err := s.findExercise(ctx, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		if !exercise.IsPublic {
+			return nil, ErrNotInLibrary
+		}
+		ownerID = exercise.UserID
+	case models.ReportResourceWorkout:
+		workout, err := s.findWorkout(ctx, resourceID)
+		if err != nil {
+			return nil, err
+		}
+		if workout.OrganizationID == nil {
+			return nil, ErrNotInLibrary
+		}
+		ok, err := s.policy.CanReadOrg(ctx, actorID, *workout.OrganizationID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check access: %w", err)
+		}
+		if !ok {
+			return nil, ErrUnauthorized
+		}
+		ownerID = workout.UserID
+	default:
+		return nil, ErrNotInLibrary
+	}
+	if ownerID == actorID {
+		return nil, ErrCannotReportOwn
+	}
+
+	report := &models.ContentReport{
+		ResourceType: resourceType,
+		ResourceID:   resourceID,
+		UserID:       actorID,
+		Reason:       req.Reason,
+	}
+	if details := normalizeText(req.Details); details != "" {
+		report.Details = &details
+	}
+	if err := s.repo.CreateReport(ctx, report); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return nil, ErrAlreadyReported
+		}
+		return nil, fmt.Errorf("failed to create report: %w", err)
+	}
+	return report, nil
+}
+
+// ReportQueue retrieves the library content with open reports, most reported first.
+// limit outside 1..200 falls back to the default of 50.
+func (s *ModerationService) ReportQueue(ctx context.Context, limit int) ([]*models.ReportedContent, error) {
+	if limit <= 0 || limit > maxQueueLimit {
+		limit = defaultQueueLimit
+	}
+
+	queue, err := s.repo.Queue(ctx, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reported content: %w", err)
+	}
+	return queue, nil
+}
+
+// ApproveContent keeps a library entry as it is and closes its open reports
+func (s *ModerationService) ApproveContent(ctx context.Context, adminID string, resourceType string, resourceID string) (*models.ModerationResult, error) {
+	if err := s.checkInLibrary(ctx, resourceType, resourceID); err != nil {
+		return nil, err
+	}
+
+	resolved, err := s.repo.Resolve(ctx, resourceType, resourceID, models.ReportResolutionApproved, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve reports: %w", err)
+	}
+	return &models.ModerationResult{
+		ResourceType:    resourceType,
+		ResourceID:      resourceID,
+		Resolution:      models.ReportResolutionApproved,
+		ReportsResolved: resolved,
+	}, nil
+}
+
+// UnpublishContent takes an entry out of the library and closes its open reports
+func (s *ModerationService) UnpublishContent(ctx context.Context, adminID string, resourceType string, resourceID string) (*models.ModerationResult, error) {
+	before, after, err := s.unpublished(ctx, resourceType, resourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var resolved int64
+	_, err = audited(ctx, s.audit, adminID, models.AuditActionUpdate, resourceType, resourceID, before, func() (any, error) {
+		resolved, err = s.repo.Unpublish(ctx, resourceType, resourceID, adminID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Unpublished or deleted since the check
+				return nil, ErrLibraryNotFound
+			}
+			return nil, fmt.Errorf("failed to unpublish: %w", err)
+		}
+		return after, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resourceType == models.ReportResourceExercise {
+		s.cache.Invalidate(ctx, exerciseCacheScope)
+	}
+
+	return &models.ModerationResult{
+		ResourceType:    resourceType,
+		ResourceID:      resourceID,
+		Resolution:      models.ReportResolutionUnpublished,
+		ReportsResolved: resolved,
+	}, nil
+}
+
+// UpdateLibraryExercise edits a public exercise and closes its open reports as edited
+// The request carries the version the edit is based on; if the exercise has been updated
+// since, nothing is written and ErrVersionConflict is returned.
+func (s *ModerationService) UpdateLibraryExercise(ctx context.Context, adminID string, id string, req *models.UpdateLibraryExerciseRequest) (*models.Exercise, error) {
+	exercise, err := s.libraryExercise(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if exercise.Version != req.Version {
+		return nil, ErrVersionConflict
+	}
+	before := *exercise
+
+	exercise.Name = normalizeName(req.Name)
+	exercise.Description = normalizeText(req.Description)
+	exercise.MuscleGroup = req.MuscleGroup
+	if exercise.Name == "" {
+		return nil, ErrBlankName
+	}
+
+	exercise, err = audited(ctx, s.audit, adminID, models.AuditActionUpdate, models.AuditResourceExercise, id, &before, func() (*models.Exercise, error) {
+		if _, err := s.repo.UpdateExercise(ctx, exercise, adminID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Updated or unpublished between the read and the write
+				return nil, ErrVersionConflict
+			}
+			return nil, fmt.Errorf("failed to update exercise: %w", err)
+		}
+		return exercise, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.cache.Invalidate(ctx, exerciseCacheScope)
+
+	return exercise, nil
+}
+
+// UpdateSharedWorkout edits a shared workout's name and description and closes its open
+// reports as edited, with the same version check as UpdateLibraryExercise
+func (s *ModerationService) UpdateSharedWorkout(ctx context.Context, adminID string, id string, req *models.UpdateSharedWorkoutRequest) (*models.Workout, error) {
+	workout, err := s.sharedWorkout(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if workout.Version != req.Version {
+		return nil, ErrVersionConflict
+	}
+	before := *workout
+
+	workout.Name = normalizeName(req.Name)
+	workout.Description = normalizeText(req.Description)
+	if workout.Name == "" {
+		return nil, ErrBlankName
+	}
+
+	return audited(ctx, s.audit, adminID, models.AuditActionUpdate, models.AuditResourceWorkout, id, &before, func() (*models.Workout, error) {
+		if _, err := s.repo.UpdateWorkout(ctx, workout, adminID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrVersionConflict
+			}
+			return nil, fmt.Errorf("failed to update workout: %w", err)
+		}
+		return workout, nil
+	})
+}
+
+// MergeLibraryExercises folds duplicate public exercises into a public target. Every user's
+// workouts and logs are re-pointed to the target; the sources and their reports are deleted.
+func (s *ModerationService) MergeLibraryExercises(ctx context.Context, adminID string, req *models.MergeExercisesRequest) (*models.ExerciseMergeResult, error) {
+	if _, err := s.libraryExercise(ctx, req.TargetID); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{req.TargetID: true}
+	sources := make([]*models.Exercise, 0, len(req.SourceIDs))
+	for _, sourceID := range req.SourceIDs {
+		if seen[sourceID] {
+			return nil, fmt.Errorf("%w: source %s is the target or listed twice", ErrInvalidMerge, sourceID)
+		}
+		seen[sourceID] = true
+
+		source, err := s.libraryExercise(ctx, sourceID)
+		if err != nil {
+			return nil, err
+		}
+		sources = append(sources, source)
+	}
+
+	result, err := s.exercises.Merge(ctx, req.TargetID, req.SourceIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to merge exercises: %w", err)
+	}
+	s.cache.Invalidate(ctx, exerciseCacheScope)
+
+	// Each merged source is audited as deleted; the target itself is unchanged
+	for _, source := range sources {
+		s.audit.recordWrite(ctx, adminID, models.AuditActionDelete, models.AuditResourceExercise, source.ID, source, nil)
+	}
+
+	return result, nil
+}
+
+// checkInLibrary returns ErrLibraryNotFound unless the resource is a public exercise or a
+// shared workout
+func (s *ModerationService) checkInLibrary(ctx context.Context, resourceType string, resourceID string) error {
+	var err error
+	switch resourceType {
+	case models.ReportResourceExercise:
+		_, err = s.libraryExercise(ctx, resourceID)
+	case models.ReportResourceWorkout:
+		_, err = s.sharedWorkout(ctx, resourceID)
+	default:
+		err = ErrLibraryNotFound
+	}
+	return err
+}
+
+// unpublished returns a library entry as it is and as it will be once unpublished: a
+// private exercise, or a workout no longer shared with its organization
+func (s *ModerationService) unpublished(ctx context.Context, resourceType string, resourceID string) (any, any, error) {
+	switch resourceType {
+	case models.ReportResourceExercise:
+		exercise, err := s.libraryExercise(ctx, resourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		after := *exercise
+		after.IsPublic = false
+		return exercise, &after, nil
+	case models.ReportResourceWorkout:
+		workout, err := s.sharedWorkout(ctx, resourceID)
+		if err != nil {
+			return nil, nil, err
+		}
+		after := *workout
+		after.OrganizationID = nil
+		return workout, &after, nil
+	}
+	return nil, nil, ErrLibraryNotFound
+}
+
+// libraryExercise retrieves a public exercise
+func (s *ModerationService) libraryExercise(ctx context.Context, id string) (*models.Exercise, error) {
+	exercise, err := s.findExercise(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrExerciseNotFound) {
+			return nil, ErrLibraryNotFound
+		}
+		return nil, err
+	}
+	if !exercise.IsPublic {
+		return nil, ErrLibraryNotFound
+	}
+	return exercise, nil
+}
+
+// sharedWorkout retrieves a workout shared in an organization
+func (s *ModerationService) sharedWorkout(ctx context.Context, id string) (*models.Workout, error) {
+	workout, err := s.findWorkout(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrWorkoutNotFound) {
+			return nil, ErrLibraryNotFound
+		}
+		return nil, err
+	}
+	if workout.OrganizationID == nil {
+		return nil, ErrLibraryNotFound
+	}
+	return workout, nil
+}
+
+func (s *ModerationService) findExercise(ctx context.Context, id string) (*models.Exercise, error) {
+	exercise, err := s.exercises.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrExerciseNotFound
+		}
+		return nil, fmt.Errorf("failed to get exercise: %w", err)
+	}
+	return exercise, nil
+}
+
+func (s *ModerationService) findWorkout(ctx context.Context, id string) (*models.Workout, error) {
+	workout, err := s.workouts.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get workout: %w", err)
+	}
+	return workout, nil
+}
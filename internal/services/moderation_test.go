@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestModerationService(repo repositories.ModerationRepository, exercises repositories.ExerciseRepository) *ModerationService {
+	policy := NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(classRoles),
+	})
+	return NewModerationService(repo, exercises, orgWorkouts("org-1"), policy, nil, nil)
+}
+
+func libraryExercises() *repositories.MockExerciseRepository {
+	return &repositories.MockExerciseRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Exercise, error) {
+			switch id {
+			case "public-1", "public-2", "public-3":
+				return &models.Exercise{ID: id, Name: "Squat", UserID: "library", IsPublic: true, Version: 3}, nil
+			case "private-1":
+				return &models.Exercise{ID: id, Name: "My squat", UserID: "member-1", Version: 1}, nil
+			}
+			return nil, pgx.ErrNoRows
+		},
+	}
+}
+
+func TestReportContent_PublicExercise(t *testing.T) {
+	var created *models.ContentReport
+	mockRepo := &repositories.MockModerationRepository{
+		CreateReportFunc: func(ctx context.Context, report *models.ContentReport) error {
+			created = report
+			report.ID = "report-1"
+			return nil
+		},
+	}
+	service := newTestModerationService(mockRepo, libraryExercises())
+
+	report, err := service.ReportContent(context.Background(), "member-1", models.ReportResourceExercise, "public-1", &models.ReportContentRequest{
+		Reason:  "incorrect",
+		Details: "  Wrong muscle group ",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.ID != "report-1" || created.UserID != "member-1" || created.ResourceID != "public-1" {
+		t.Errorf("Unexpected report %+v", created)
+	}
+	if created.Details == nil || *created.Details != "Wrong muscle group" {
+		t.Errorf("Expected trimmed details, got %v", created.Details)
+	}
+}
+
+func TestReportContent_Rejections(t *testing.T) {
+	mockRepo := &repositories.MockModerationRepository{
+		CreateReportFunc: func(ctx context.Context, report *models.ContentReport) error {
+			return fmt.Errorf("%w: open report", repositories.ErrDuplicate)
+		},
+	}
+	service := newTestModerationService(mockRepo, libraryExercises())
+	req := &models.ReportContentRequest{Reason: "spam"}
+
+	tests := []struct {
+		name         string
+		actorID      string
+		resourceType string
+		resourceID   string
+		want         error
+	}{
+		{"private exercise", "member-1", models.ReportResourceExercise, "private-1", ErrNotInLibrary},
+		{"missing exercise", "member-1", models.ReportResourceExercise, "missing", ErrExerciseNotFound},
+		{"personal workout", "member-1", models.ReportResourceWorkout, "personal-workout", ErrNotInLibrary},
+		{"workout of another organization", "outsider", models.ReportResourceWorkout, "org-workout", ErrUnauthorized},
+		{"own workout", "trainer-1", models.ReportResourceWorkout, "org-workout", ErrCannotReportOwn},
+		{"already reported", "member-1", models.ReportResourceWorkout, "org-workout", ErrAlreadyReported},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ReportContent(context.Background(), tt.actorID, tt.resourceType, tt.resourceID, req)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestApproveContent(t *testing.T) {
+	var resolution, adminID string
+	mockRepo := &repositories.MockModerationRepository{
+		ResolveFunc: func(ctx context.Context, resourceType string, resourceID string, res string, admin string) (int64, error) {
+			resolution, adminID = res, admin
+			return 2, nil
+		},
+	}
+	service := newTestModerationService(mockRepo, libraryExercises())
+
+	result, err := service.ApproveContent(context.Background(), "admin-1", models.ReportResourceWorkout, "org-workout")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.ReportsResolved != 2 || resolution != models.ReportResolutionApproved || adminID != "admin-1" {
+		t.Errorf("Unexpected result %+v (resolution %q, admin %q)", result, resolution, adminID)
+	}
+
+	if _, err := service.ApproveContent(context.Background(), "admin-1", models.ReportResourceExercise, "private-1"); !errors.Is(err, ErrLibraryNotFound) {
+		t.Errorf("Expected ErrLibraryNotFound for a private exercise, got %v", err)
+	}
+}
+
+func TestUnpublishContent_GoneSinceCheck(t *testing.T) {
+	mockRepo := &repositories.MockModerationRepository{
+		UnpublishFunc: func(ctx context.Context, resourceType string, resourceID string, adminID string) (int64, error) {
+			return 0, pgx.ErrNoRows
+		},
+	}
+	service := newTestModerationService(mockRepo, libraryExercises())
+
+	_, err := service.UnpublishContent(context.Background(), "", models.ReportResourceExercise, "public-1")
+	if !errors.Is(err, ErrLibraryNotFound) {
+		t.Errorf("Expected ErrLibraryNotFound, got %v", err)
+	}
+}
+
+func TestUpdateLibraryExercise(t *testing.T) {
+	var saved *models.Exercise
+	mockRepo := &repositories.MockModerationRepository{
+		UpdateExerciseFunc: func(ctx context.Context, exercise *models.Exercise, adminID string) (int64, error) {
+			saved = exercise
+			exercise.Version++
+			return 1, nil
+		},
+	}
+	service := newTestModerationService(mockRepo, libraryExercises())
+	chest := "chest"
+
+	exercise, err := service.UpdateLibraryExercise(context.Background(), "admin-1", "public-1", &models.UpdateLibraryExerciseRequest{
+		Name:        " Bench  press ",
+		MuscleGroup: &chest,
+		Version:     3,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.Name != "Bench press" || exercise.MuscleGroup == nil || *exercise.MuscleGroup != "chest" || exercise.Version != 4 {
+		t.Errorf("Unexpected exercise %+v", exercise)
+	}
+
+	_, err = service.UpdateLibraryExercise(context.Background(), "admin-1", "public-1", &models.UpdateLibraryExerciseRequest{Name: "Squat", Version: 2})
+	if !errors.Is(err, ErrVersionConflict) {
+		t.Errorf("Expected ErrVersionConflict for a stale version, got %v", err)
+	}
+}
+
+func TestMergeLibraryExercises(t *testing.T) {
+	merged := false
+	exercises := libraryExercises()
+	exercises.MergeFunc = func(ctx context.Context, targetID string, sourceIDs []string) (*models.ExerciseMergeResult, error) {
+		merged = true
+		return &models.ExerciseMergeResult{MergedCount: len(sourceIDs)}, nil
+	}
+	service := newTestModerationService(&repositories.MockModerationRepository{}, exercises)
+
+	if _, err := service.MergeLibraryExercises(context.Background(), "admin-1", &models.MergeExercisesRequest{TargetID: "public-1", SourceIDs: []string{"private-1"}}); !errors.Is(err, ErrLibraryNotFound) {
+		t.Errorf("Expected ErrLibraryNotFound for a private source, got %v", err)
+	}
+	if _, err := service.MergeLibraryExercises(context.Background(), "admin-1", &models.MergeExercisesRequest{TargetID: "public-1", SourceIDs: []string{"public-2", "public-2"}}); !errors.Is(err, ErrInvalidMerge) {
+		t.Errorf("Expected ErrInvalidMerge for a repeated source, got %v", err)
+	}
+	if merged {
+		t.Fatal("Expected no merge after rejected requests")
+	}
+
+	result, err := service.MergeLibraryExercises(context.Background(), "admin-1", &models.MergeExercisesRequest{TargetID: "public-1", SourceIDs: []string{"public-2", "public-3"}})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.MergedCount != 2 {
+		t.Errorf("Expected 2 merged, got %d", result.MergedCount)
+	}
+}
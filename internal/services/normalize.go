@@ -0,0 +1,37 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"golang.org/x/text/unicode/norm"
+)
+
+// ErrBlankName is returned when a name is empty once its whitespace is removed
+var ErrBlankName = domainerr.New(domainerr.Validation, "name must not be blank")
+
+// normalizeName prepares a user-given name for storage: Unicode NFC, so names typed on
+// different keyboards compare equal, whitespace trimmed and collapsed to single spaces, and
+// a lowercase first word capitalized. Other casing is the user's ("EZ bar", "iPad stand");
+// uniqueness checks compare names case-insensitively instead.
+func normalizeName(name string) string {
+	name = strings.Join(strings.Fields(norm.NFC.String(name)), " ")
+
+	firstWord, _, _ := strings.Cut(name, " ")
+	if strings.IndexFunc(firstWord, unicode.IsUpper) >= 0 {
+		return name
+	}
+	first, size := utf8.DecodeRuneInString(name)
+	if first == utf8.RuneError {
+		return name
+	}
+	return string(unicode.ToUpper(first)) + name[size:]
+}
+
+// normalizeText prepares free text such as a description for storage: Unicode NFC with
+// surrounding whitespace trimmed. Line breaks inside are kept.
+func normalizeText(text string) string {
+	return strings.TrimSpace(norm.NFC.String(text))
+}
@@ -0,0 +1,29 @@
+package services
+
+import "testing"
+
+func TestNormalizeName(t *testing.T) {
+	tests := map[string]string{
+		"  barbell  ":          "Barbell",
+		"EZ   curl\tbar":       "EZ curl bar",
+		"iPad stand":           "iPad stand",
+		"cafe\u0301 squat":     "Café squat", // Decomposed é is composed
+		"élliptical":           "Élliptical",
+		"   ":                  "",
+		"kettlebell\n swings ": "Kettlebell swings",
+	}
+	for input, want := range tests {
+		if got := normalizeName(input); got != want {
+			t.Errorf("normalizeName(%q): expected %q, got %q", input, want, got)
+		}
+	}
+}
+
+func TestNormalizeText(t *testing.T) {
+	if got := normalizeText("  Keep it\nstrict  "); got != "Keep it\nstrict" {
+		t.Errorf("Expected surrounding whitespace trimmed and line breaks kept, got %q", got)
+	}
+	if got := normalizeText("cafe\u0301"); got != "café" {
+		t.Errorf("Expected NFC, got %q", got)
+	}
+}
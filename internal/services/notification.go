@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrNotificationNotFound           = domainerr.New(domainerr.NotFound, "notification not found")
+	ErrInvalidNotificationPreferences = domainerr.New(domainerr.Validation, "invalid notification preferences")
+)
+
+const (
+	// defaultNotificationPage and maxNotificationPage bound an inbox page
+	defaultNotificationPage = 50
+	maxNotificationPage     = 200
+
+	// notificationRetention is how long read notifications are kept
+	notificationRetention = 90 * 24 * time.Hour
+)
+
+// NotificationService serves users' in-app inboxes and the preferences every channel's
+// sender consults
+type NotificationService struct {
+	repo repositories.NotificationRepository
+	now  func() time.Time
+}
+
+// NewNotificationService creates a new notification service
+func NewNotificationService(repo repositories.NotificationRepository) *NotificationService {
+	return &NotificationService{repo: repo, now: time.Now}
+}
+
+// List retrieves a page of the user's notifications along with their unread count
+func (s *NotificationService) List(ctx context.Context, userID string, filter models.NotificationFilter) (*models.NotificationList, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultNotificationPage
+	}
+	filter.Limit = min(filter.Limit, maxNotificationPage)
+
+	notifications, err := s.repo.List(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+	unread, err := s.UnreadCount(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.NotificationList{Notifications: notifications, UnreadCount: unread}, nil
+}
+
+// UnreadCount counts the user's unread notifications
+func (s *NotificationService) UnreadCount(ctx context.Context, userID string) (int, error) {
+	count, err := s.repo.UnreadCount(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+	return count, nil
+}
+
+// MarkRead marks one of the user's notifications read
+func (s *NotificationService) MarkRead(ctx context.Context, id string, userID string) (*models.Notification, error) {
+	notification, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to get notification: %w", err)
+	}
+	if notification.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	readAt, err := s.repo.MarkRead(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotificationNotFound
+		}
+		return nil, fmt.Errorf("failed to mark notification read: %w", err)
+	}
+	notification.ReadAt = readAt
+	return notification, nil
+}
+
+// MarkAllRead marks all of the user's notifications read and returns how many were unread
+func (s *NotificationService) MarkAllRead(ctx context.Context, userID string) (int64, error) {
+	n, err := s.repo.MarkAllRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications read: %w", err)
+	}
+	return n, nil
+}
+
+// GetPreferences retrieves the user's notification preferences
+func (s *NotificationService) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences replaces the user's notification preferences. Each type given is sent
+// on the listed channels only, none for an empty list; other types keep every channel.
+func (s *NotificationService) UpdatePreferences(ctx context.Context, userID string, req *models.UpdateNotificationPreferencesRequest) (*models.NotificationPreferences, error) {
+	prefs := &models.NotificationPreferences{UserID: userID, Channels: make(map[string][]string, len(req.Channels))}
+	for kind, channels := range req.Channels {
+		sentOn, ok := models.NotificationTypeChannels[kind]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown notification type %q", ErrInvalidNotificationPreferences, kind)
+		}
+		for _, channel := range channels {
+			if !slices.Contains(sentOn, channel) {
+				return nil, fmt.Errorf("%w: %s notifications are sent on %v, not %q", ErrInvalidNotificationPreferences, kind, sentOn, channel)
+			}
+		}
+		channels = slices.Clone(channels)
+		slices.Sort(channels)
+		prefs.Channels[kind] = slices.Compact(channels)
+	}
+
+	if q := req.QuietHours; q != nil {
+		quiet := *q
+		if quiet.Timezone == "" {
+			quiet.Timezone = "UTC"
+		}
+		if _, _, _, err := parseQuietHours(&quiet); err != nil {
+			return nil, err
+		}
+		prefs.QuietHours = &quiet
+	}
+
+	if err := s.repo.SavePreferences(ctx, prefs); err != nil {
+		return nil, fmt.Errorf("failed to save notification preferences: %w", err)
+	}
+	return prefs, nil
+}
+
+// Deliverable reports whether the user lets a notification of the type be sent on the
+// channel, and for pushes during their quiet hours, until when it is held. Senders consult
+// it right before sending.
+func (s *NotificationService) Deliverable(ctx context.Context, userID string, kind string, channel string) (bool, time.Time, error) {
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return false, time.Time{}, err
+	}
+	if channels, ok := prefs.Channels[kind]; ok && !slices.Contains(channels, channel) {
+		return false, time.Time{}, nil
+	}
+	if channel == models.NotificationChannelPush && prefs.QuietHours != nil {
+		return true, quietUntil(prefs.QuietHours, s.now()), nil
+	}
+	return true, time.Time{}, nil
+}
+
+// parseQuietHours returns the location of quiet hours and their start and end in minutes
+// into the day
+func parseQuietHours(q *models.QuietHours) (*time.Location, int, int, error) {
+	loc, err := time.LoadLocation(q.Timezone)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: unknown timezone %q", ErrInvalidNotificationPreferences, q.Timezone)
+	}
+	start, errStart := time.Parse("15:04", q.Start)
+	end, errEnd := time.Parse("15:04", q.End)
+	if errStart != nil || errEnd != nil {
+		return nil, 0, 0, fmt.Errorf("%w: quiet hours must be HH:MM", ErrInvalidNotificationPreferences)
+	}
+	if start.Equal(end) {
+		return nil, 0, 0, fmt.Errorf("%w: quiet hours must start and end at different times", ErrInvalidNotificationPreferences)
+	}
+	return loc, start.Hour()*60 + start.Minute(), end.Hour()*60 + end.Minute(), nil
+}
+
+// quietUntil returns when the quiet hours now falls in end, or the zero time outside them
+func quietUntil(q *models.QuietHours, now time.Time) time.Time {
+	loc, start, end, err := parseQuietHours(q)
+	if err != nil {
+		// Validated on save; this only happens if the tz database changed
+		return time.Time{}
+	}
+
+	local := now.In(loc)
+	minute := local.Hour()*60 + local.Minute()
+	endDay := local
+	switch {
+	case start < end && minute >= start && minute < end:
+	case start > end && minute < end:
+	case start > end && minute >= start:
+		endDay = local.AddDate(0, 0, 1)
+	default:
+		return time.Time{}
+	}
+	return time.Date(endDay.Year(), endDay.Month(), endDay.Day(), end/60, end%60, 0, 0, loc)
+}
+
+// Start prunes notifications read long ago every interval until ctx is cancelled
+func (s *NotificationService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.repo.Prune(ctx, s.now().Add(-notificationRetention)); err != nil {
+					log.Printf("Notifications failed to prune: %v", err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestListNotifications_ClampsLimitAndCountsUnread(t *testing.T) {
+	var limits []int
+	mockRepo := &repositories.MockNotificationRepository{
+		ListFunc: func(ctx context.Context, userID string, filter models.NotificationFilter) ([]*models.Notification, error) {
+			limits = append(limits, filter.Limit)
+			return []*models.Notification{{ID: "notification-1", UserID: userID}}, nil
+		},
+		UnreadCountFunc: func(ctx context.Context, userID string) (int, error) {
+			return 3, nil
+		},
+	}
+	service := NewNotificationService(mockRepo)
+
+	list, err := service.List(context.Background(), "user-123", models.NotificationFilter{})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(list.Notifications) != 1 || list.UnreadCount != 3 {
+		t.Errorf("Expected the page with the unread count, got %+v", list)
+	}
+
+	service.List(context.Background(), "user-123", models.NotificationFilter{Limit: 1000})
+	if limits[0] != defaultNotificationPage || limits[1] != maxNotificationPage {
+		t.Errorf("Expected limits %d and %d, got %v", defaultNotificationPage, maxNotificationPage, limits)
+	}
+}
+
+func TestMarkNotificationRead_ChecksOwner(t *testing.T) {
+	marked := false
+	mockRepo := &repositories.MockNotificationRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Notification, error) {
+			return &models.Notification{ID: id, UserID: "user-123"}, nil
+		},
+		MarkReadFunc: func(ctx context.Context, id string) (*time.Time, error) {
+			marked = true
+			readAt := time.Now()
+			return &readAt, nil
+		},
+	}
+	service := NewNotificationService(mockRepo)
+
+	if _, err := service.MarkRead(context.Background(), "notification-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if marked {
+		t.Error("Expected another user's notification to stay unread")
+	}
+
+	notification, err := service.MarkRead(context.Background(), "notification-1", "user-123")
+	if err != nil || !marked || notification.ReadAt == nil {
+		t.Errorf("Expected the owner to mark the notification read, got %+v, %v", notification, err)
+	}
+}
+
+func TestMarkNotificationRead_NotFound(t *testing.T) {
+	service := NewNotificationService(&repositories.MockNotificationRepository{})
+
+	if _, err := service.MarkRead(context.Background(), "missing", "user-123"); !errors.Is(err, ErrNotificationNotFound) {
+		t.Errorf("Expected ErrNotificationNotFound, got %v", err)
+	}
+}
+
+func TestUpdateNotificationPreferences_Validates(t *testing.T) {
+	service := NewNotificationService(&repositories.MockNotificationRepository{})
+
+	prefs, err := service.UpdatePreferences(context.Background(), "user-123", &models.UpdateNotificationPreferencesRequest{
+		Channels:   map[string][]string{models.NotificationWorkoutReminder: {"push", "push"}, models.NotificationPRAchieved: {}},
+		QuietHours: &models.QuietHours{Start: "22:00", End: "07:00"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(prefs.Channels[models.NotificationWorkoutReminder]) != 1 || prefs.QuietHours.Timezone != "UTC" {
+		t.Errorf("Expected deduplicated channels and UTC quiet hours, got %+v", prefs)
+	}
+
+	for _, req := range []*models.UpdateNotificationPreferencesRequest{
+		{Channels: map[string][]string{"newsletter": {"email"}}},
+		{Channels: map[string][]string{models.NotificationPRAchieved: {"email"}}},
+		{QuietHours: &models.QuietHours{Start: "22:00", End: "7am"}},
+		{QuietHours: &models.QuietHours{Start: "22:00", End: "22:00"}},
+		{QuietHours: &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Mars/Olympus"}},
+	} {
+		if _, err := service.UpdatePreferences(context.Background(), "user-123", req); !errors.Is(err, ErrInvalidNotificationPreferences) {
+			t.Errorf("Expected ErrInvalidNotificationPreferences for %+v, got %v", req, err)
+		}
+	}
+}
+
+func TestDeliverable_ChannelsAndQuietHours(t *testing.T) {
+	mockRepo := &repositories.MockNotificationRepository{
+		GetPreferencesFunc: func(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+			return &models.NotificationPreferences{
+				UserID:     userID,
+				Channels:   map[string][]string{models.NotificationWorkoutReminder: {models.NotificationChannelPush}},
+				QuietHours: &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "Europe/Madrid"},
+			}, nil
+		},
+	}
+	service := NewNotificationService(mockRepo)
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+
+	tests := []struct {
+		name        string
+		kind        string
+		channel     string
+		now         time.Time
+		wantAllowed bool
+		wantHold    time.Time
+	}{
+		{"turned off", models.NotificationWorkoutReminder, models.NotificationChannelEmail, time.Date(2026, 3, 2, 12, 0, 0, 0, madrid), false, time.Time{}},
+		{"other types on every channel", models.NotificationWeeklySummary, models.NotificationChannelEmail, time.Date(2026, 3, 2, 23, 0, 0, 0, madrid), true, time.Time{}},
+		{"push outside quiet hours", models.NotificationWorkoutReminder, models.NotificationChannelPush, time.Date(2026, 3, 2, 12, 0, 0, 0, madrid), true, time.Time{}},
+		{"push before midnight", models.NotificationWorkoutReminder, models.NotificationChannelPush, time.Date(2026, 3, 2, 23, 0, 0, 0, madrid), true, time.Date(2026, 3, 3, 7, 0, 0, 0, madrid)},
+		{"push after midnight", models.NotificationWorkoutAssigned, models.NotificationChannelPush, time.Date(2026, 3, 3, 6, 59, 0, 0, madrid), true, time.Date(2026, 3, 3, 7, 0, 0, 0, madrid)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service.now = func() time.Time { return tt.now }
+			allowed, hold, err := service.Deliverable(context.Background(), "user-123", tt.kind, tt.channel)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if allowed != tt.wantAllowed || !hold.Equal(tt.wantHold) {
+				t.Errorf("Expected %v held until %v, got %v held until %v", tt.wantAllowed, tt.wantHold, allowed, hold)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// signOAuthState encodes the user and an expiry, signed with key: "<user>.<unix>.<mac>"
+// Integrations pass it through the provider's consent page so the redirect can only complete
+// the connection of the user who started it.
+func signOAuthState(key []byte, userID string, expiresAt time.Time) string {
+	payload := userID + "." + strconv.FormatInt(expiresAt.Unix(), 10)
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return payload + "." + hex.EncodeToString(mac.Sum(nil))
+}
+
+// verifyOAuthState checks that state was issued to userID and has not expired at now
+func verifyOAuthState(key []byte, userID string, state string, now time.Time) bool {
+	parts := strings.Split(state, ".")
+	if len(parts) != 3 || parts[0] != userID {
+		return false
+	}
+
+	expiry, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil || now.After(time.Unix(expiry, 0)) {
+		return false
+	}
+
+	return hmac.Equal([]byte(signOAuthState(key, userID, time.Unix(expiry, 0))), []byte(state))
+}
@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrOrganizationNotFound = domainerr.New(domainerr.NotFound, "organization not found")
+	ErrMemberNotFound       = domainerr.New(domainerr.NotFound, "organization member not found")
+	ErrMemberExists         = domainerr.New(domainerr.Conflict, "user is already a member of this organization")
+	ErrLastOwner            = domainerr.New(domainerr.Conflict, "organization must keep at least one owner")
+)
+
+// OrganizationService handles organizations and their memberships
+type OrganizationService struct {
+	repo  repositories.OrganizationRepository
+	audit *AuditLogService
+}
+
+// NewOrganizationService creates a new organization service; organization and membership
+// writes are audited unless audit is nil
+func NewOrganizationService(repo repositories.OrganizationRepository, audit *AuditLogService) *OrganizationService {
+	return &OrganizationService{repo: repo, audit: audit}
+}
+
+// CreateOrganization creates an organization owned by the requesting user
+func (s *OrganizationService) CreateOrganization(ctx context.Context, userID string, req *models.CreateOrganizationRequest) (*models.Organization, error) {
+	org := &models.Organization{
+		Name:        normalizeName(req.Name),
+		Description: normalizeText(req.Description),
+		CreatedBy:   userID,
+	}
+	if org.Name == "" {
+		return nil, ErrBlankName
+	}
+
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceOrganization, "", nil, func() (*models.Organization, error) {
+		if err := s.repo.Create(ctx, org); err != nil {
+			return nil, fmt.Errorf("failed to create organization: %w", err)
+		}
+		return org, nil
+	})
+}
+
+// ListOrganizations retrieves every organization the user belongs to
+func (s *OrganizationService) ListOrganizations(ctx context.Context, userID string) ([]*models.Organization, error) {
+	orgs, err := s.repo.FindByMember(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organizations: %w", err)
+	}
+
+	return orgs, nil
+}
+
+// GetOrganization retrieves an organization the user belongs to
+func (s *OrganizationService) GetOrganization(ctx context.Context, id string, userID string) (*models.Organization, error) {
+	if _, err := s.GetMembership(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	org, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	return org, nil
+}
+
+// GetMembership retrieves the user's membership, hiding organizations they don't belong to
+func (s *OrganizationService) GetMembership(ctx context.Context, orgID string, userID string) (*models.OrganizationMember, error) {
+	member, err := s.repo.FindMember(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("failed to get membership: %w", err)
+	}
+
+	return member, nil
+}
+
+// ListMembers retrieves all members of an organization the user belongs to
+func (s *OrganizationService) ListMembers(ctx context.Context, orgID string, userID string) ([]*models.OrganizationMember, error) {
+	if _, err := s.GetMembership(ctx, orgID, userID); err != nil {
+		return nil, err
+	}
+
+	members, err := s.repo.FindMembers(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list members: %w", err)
+	}
+
+	return members, nil
+}
+
+// AddMember adds a user to an organization. Owners may grant any role;
+// trainers may only add regular members.
+func (s *OrganizationService) AddMember(ctx context.Context, orgID string, userID string, req *models.AddOrganizationMemberRequest) (*models.OrganizationMember, error) {
+	actor, err := s.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	canAdd := actor.Role == models.OrgRoleOwner ||
+		(actor.Role == models.OrgRoleTrainer && req.Role == models.OrgRoleMember)
+	if !canAdd {
+		return nil, ErrUnauthorized
+	}
+
+	if _, err := s.repo.FindMember(ctx, orgID, req.UserID); err == nil {
+		return nil, ErrMemberExists
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("failed to check membership: %w", err)
+	}
+
+	member := &models.OrganizationMember{
+		OrganizationID: orgID,
+		UserID:         req.UserID,
+		Role:           req.Role,
+	}
+
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceOrganizationMember, memberResourceID(orgID, req.UserID), nil, func() (*models.OrganizationMember, error) {
+		if err := s.repo.AddMember(ctx, member); err != nil {
+			return nil, fmt.Errorf("failed to add member: %w", err)
+		}
+		return member, nil
+	})
+}
+
+// UpdateMemberRole changes a member's role (owners only)
+func (s *OrganizationService) UpdateMemberRole(ctx context.Context, orgID string, userID string, memberID string, req *models.UpdateOrganizationMemberRequest) (*models.OrganizationMember, error) {
+	actor, err := s.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if actor.Role != models.OrgRoleOwner {
+		return nil, ErrUnauthorized
+	}
+
+	member, err := s.findMember(ctx, orgID, memberID)
+	if err != nil {
+		return nil, err
+	}
+
+	if member.Role == models.OrgRoleOwner && req.Role != models.OrgRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID, memberID); err != nil {
+			return nil, err
+		}
+	}
+
+	before := *member
+	member.Role = req.Role
+
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceOrganizationMember, memberResourceID(orgID, memberID), &before, func() (*models.OrganizationMember, error) {
+		if err := s.repo.UpdateMember(ctx, member); err != nil {
+			return nil, fmt.Errorf("failed to update member: %w", err)
+		}
+		return member, nil
+	})
+}
+
+// RemoveMember removes a member. Owners may remove anyone; any member may leave.
+func (s *OrganizationService) RemoveMember(ctx context.Context, orgID string, userID string, memberID string) error {
+	actor, err := s.GetMembership(ctx, orgID, userID)
+	if err != nil {
+		return err
+	}
+	if actor.Role != models.OrgRoleOwner && userID != memberID {
+		return ErrUnauthorized
+	}
+
+	member, err := s.findMember(ctx, orgID, memberID)
+	if err != nil {
+		return err
+	}
+
+	if member.Role == models.OrgRoleOwner {
+		if err := s.ensureAnotherOwner(ctx, orgID, memberID); err != nil {
+			return err
+		}
+	}
+
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceOrganizationMember, memberResourceID(orgID, memberID), member, func() (*models.OrganizationMember, error) {
+		if err := s.repo.RemoveMember(ctx, orgID, memberID); err != nil {
+			return nil, fmt.Errorf("failed to remove member: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// memberResourceID identifies a membership in the audit log
+func memberResourceID(orgID string, userID string) string {
+	return orgID + "/" + userID
+}
+
+func (s *OrganizationService) findMember(ctx context.Context, orgID string, memberID string) (*models.OrganizationMember, error) {
+	member, err := s.repo.FindMember(ctx, orgID, memberID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrMemberNotFound
+		}
+		return nil, fmt.Errorf("failed to get member: %w", err)
+	}
+
+	return member, nil
+}
+
+// ensureAnotherOwner fails when memberID is the organization's only owner
+func (s *OrganizationService) ensureAnotherOwner(ctx context.Context, orgID string, memberID string) error {
+	members, err := s.repo.FindMembers(ctx, orgID)
+	if err != nil {
+		return fmt.Errorf("failed to list members: %w", err)
+	}
+
+	for _, m := range members {
+		if m.Role == models.OrgRoleOwner && m.UserID != memberID {
+			return nil
+		}
+	}
+
+	return ErrLastOwner
+}
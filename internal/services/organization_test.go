@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func memberLookup(roles map[string]string) func(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+	return func(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+		role, ok := roles[userID]
+		if !ok {
+			return nil, pgx.ErrNoRows
+		}
+		return &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: role}, nil
+	}
+}
+
+func TestAddMember_TrainerCanAddMember(t *testing.T) {
+	mockRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(map[string]string{"trainer-1": models.OrgRoleTrainer}),
+	}
+
+	service := NewOrganizationService(mockRepo, nil)
+
+	req := &models.AddOrganizationMemberRequest{UserID: "new-user", Role: models.OrgRoleMember}
+
+	member, err := service.AddMember(context.Background(), "org-1", "trainer-1", req)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if member.Role != models.OrgRoleMember {
+		t.Errorf("Expected role 'member', got '%s'", member.Role)
+	}
+}
+
+func TestAddMember_TrainerCannotGrantTrainer(t *testing.T) {
+	mockRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(map[string]string{"trainer-1": models.OrgRoleTrainer}),
+	}
+
+	service := NewOrganizationService(mockRepo, nil)
+
+	req := &models.AddOrganizationMemberRequest{UserID: "new-user", Role: models.OrgRoleTrainer}
+
+	_, err := service.AddMember(context.Background(), "org-1", "trainer-1", req)
+
+	if !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestAddMember_AlreadyMember(t *testing.T) {
+	mockRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(map[string]string{
+			"owner-1":  models.OrgRoleOwner,
+			"member-1": models.OrgRoleMember,
+		}),
+	}
+
+	service := NewOrganizationService(mockRepo, nil)
+
+	req := &models.AddOrganizationMemberRequest{UserID: "member-1", Role: models.OrgRoleMember}
+
+	_, err := service.AddMember(context.Background(), "org-1", "owner-1", req)
+
+	if !errors.Is(err, ErrMemberExists) {
+		t.Errorf("Expected ErrMemberExists, got %v", err)
+	}
+}
+
+func TestRemoveMember_LastOwner(t *testing.T) {
+	mockRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(map[string]string{"owner-1": models.OrgRoleOwner}),
+		FindMembersFunc: func(ctx context.Context, orgID string) ([]*models.OrganizationMember, error) {
+			return []*models.OrganizationMember{
+				{OrganizationID: orgID, UserID: "owner-1", Role: models.OrgRoleOwner},
+				{OrganizationID: orgID, UserID: "member-1", Role: models.OrgRoleMember},
+			}, nil
+		},
+	}
+
+	service := NewOrganizationService(mockRepo, nil)
+
+	err := service.RemoveMember(context.Background(), "org-1", "owner-1", "owner-1")
+
+	if !errors.Is(err, ErrLastOwner) {
+		t.Errorf("Expected ErrLastOwner, got %v", err)
+	}
+}
+
+func TestGetOrganization_NotMember(t *testing.T) {
+	service := NewOrganizationService(&repositories.MockOrganizationRepository{}, nil)
+
+	_, err := service.GetOrganization(context.Background(), "org-1", "stranger")
+
+	if !errors.Is(err, ErrOrganizationNotFound) {
+		t.Errorf("Expected ErrOrganizationNotFound, got %v", err)
+	}
+}
+
+func TestEquipment_OrgScopedAccess(t *testing.T) {
+	orgID := "org-1"
+	equipmentRepo := &repositories.MockEquipmentRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Equipment, error) {
+			return &models.Equipment{ID: id, UserID: "trainer-1", OrganizationID: &orgID}, nil
+		},
+	}
+	orgRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: memberLookup(map[string]string{
+			"trainer-1": models.OrgRoleTrainer,
+			"member-1":  models.OrgRoleMember,
+		}),
+	}
+
+	service := NewEquipmentService(equipmentRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, orgRepo), nil)
+
+	if _, err := service.GetEquipment(context.Background(), "eq-1", "member-1"); err != nil {
+		t.Fatalf("Expected member read access, got %v", err)
+	}
+
+	if err := service.DeleteEquipment(context.Background(), "eq-1", "member-1"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for member delete, got %v", err)
+	}
+
+	if _, err := service.GetEquipment(context.Background(), "eq-1", "stranger"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for non-member, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInvalidTimezone = domainerr.New(domainerr.Validation, "unknown timezone, expected an IANA name such as Europe/Madrid")
+	ErrFutureDay       = domainerr.New(domainerr.Validation, "date can't be in the future")
+)
+
+// ProfileService handles users' account-wide settings
+type ProfileService struct {
+	repo  repositories.ProfileRepository
+	cache *cache.Cache
+}
+
+// NewProfileService creates a new profile service; the cache holds the analytics a
+// timezone change makes stale
+func NewProfileService(repo repositories.ProfileRepository, c *cache.Cache) *ProfileService {
+	return &ProfileService{repo: repo, cache: c}
+}
+
+// GetProfile retrieves the user's profile
+func (s *ProfileService) GetProfile(ctx context.Context, userID string) (*models.Profile, error) {
+	profile, err := s.repo.FindProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	return profile, nil
+}
+
+// UpdateProfile replaces the user's profile, keeping its privacy when the request leaves it
+// out. Cached analytics are dropped right away; trend snapshots are rebuilt in the new
+// timezone by their next refresh.
+func (s *ProfileService) UpdateProfile(ctx context.Context, userID string, req *models.UpdateProfileRequest) (*models.Profile, error) {
+	// "Local" is the server's zone, not a place the user can be
+	if req.Timezone == "Local" {
+		return nil, ErrInvalidTimezone
+	}
+	if _, err := time.LoadLocation(req.Timezone); err != nil {
+		return nil, ErrInvalidTimezone
+	}
+
+	privacy := req.Privacy
+	if privacy == "" {
+		current, err := s.repo.FindProfile(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get profile: %w", err)
+		}
+		privacy = current.Privacy
+	}
+
+	profile := &models.Profile{UserID: userID, Timezone: req.Timezone, Privacy: privacy}
+	if err := s.repo.SaveProfile(ctx, profile); err != nil {
+		return nil, fmt.Errorf("failed to save profile: %w", err)
+	}
+
+	s.cache.Invalidate(ctx, analyticsCacheScope(userID))
+	return profile, nil
+}
+
+// userLocation returns the location the user's days and weeks are counted in. Zones the
+// server's time zone database doesn't know fall back to UTC.
+func userLocation(ctx context.Context, profiles repositories.ProfileRepository, userID string) (*time.Location, error) {
+	profile, err := profiles.FindProfile(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get profile: %w", err)
+	}
+	loc, err := time.LoadLocation(profile.Timezone)
+	if err != nil {
+		return time.UTC, nil
+	}
+	return loc, nil
+}
+
+// localDate returns the calendar date t falls on in loc, at midnight UTC like the dates
+// clients send
+func localDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// pastDay resolves a requested day for reports on a single day: zero is today in
+// the user's timezone, and days after it are rejected
+func pastDay(ctx context.Context, profiles repositories.ProfileRepository, now time.Time, userID string, day time.Time) (time.Time, error) {
+	loc, err := userLocation(ctx, profiles, userID)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	today := localDate(now, loc)
+	if day.IsZero() {
+		return today, nil
+	}
+	if day.After(today) {
+		return time.Time{}, ErrFutureDay
+	}
+	return day, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestUpdateProfile_InvalidTimezone(t *testing.T) {
+	service := NewProfileService(&repositories.MockProfileRepository{
+		SaveProfileFunc: func(ctx context.Context, profile *models.Profile) error {
+			t.Fatal("Expected an invalid timezone not to be saved")
+			return nil
+		},
+	}, nil)
+
+	for _, timezone := range []string{"Mars/Olympus_Mons", "Local"} {
+		_, err := service.UpdateProfile(context.Background(), "user-123", &models.UpdateProfileRequest{Timezone: timezone})
+		if !errors.Is(err, ErrInvalidTimezone) {
+			t.Errorf("Expected ErrInvalidTimezone for %q, got %v", timezone, err)
+		}
+	}
+}
+
+func TestUpdateProfile_SavesTimezone(t *testing.T) {
+	var saved *models.Profile
+	service := NewProfileService(&repositories.MockProfileRepository{
+		SaveProfileFunc: func(ctx context.Context, profile *models.Profile) error {
+			saved = profile
+			return nil
+		},
+	}, nil)
+
+	profile, err := service.UpdateProfile(context.Background(), "user-123", &models.UpdateProfileRequest{Timezone: "Europe/Madrid"})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved == nil || saved.UserID != "user-123" || saved.Timezone != "Europe/Madrid" || profile != saved {
+		t.Errorf("Expected the timezone to be saved for the user, got %+v", saved)
+	}
+}
+
+func TestUpdateProfile_KeepsPrivacy(t *testing.T) {
+	var saved *models.Profile
+	service := NewProfileService(&repositories.MockProfileRepository{
+		FindProfileFunc: func(ctx context.Context, userID string) (*models.Profile, error) {
+			return &models.Profile{UserID: userID, Timezone: "UTC", Privacy: models.ProfilePrivacyFollowers}, nil
+		},
+		SaveProfileFunc: func(ctx context.Context, profile *models.Profile) error {
+			saved = profile
+			return nil
+		},
+	}, nil)
+
+	if _, err := service.UpdateProfile(context.Background(), "user-123", &models.UpdateProfileRequest{Timezone: "Europe/Madrid"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.Privacy != models.ProfilePrivacyFollowers {
+		t.Errorf("Expected the privacy to be kept, got %q", saved.Privacy)
+	}
+
+	if _, err := service.UpdateProfile(context.Background(), "user-123", &models.UpdateProfileRequest{Timezone: "Europe/Madrid", Privacy: models.ProfilePrivacyPrivate}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.Privacy != models.ProfilePrivacyPrivate {
+		t.Errorf("Expected the privacy to change to private, got %q", saved.Privacy)
+	}
+}
+
+func TestLocalDate(t *testing.T) {
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %v", err)
+	}
+
+	// 01:30 in Madrid is still the previous day in UTC during summer time
+	early := time.Date(2025, 6, 19, 1, 30, 0, 0, madrid)
+	if got, want := localDate(early, madrid), time.Date(2025, 6, 19, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+	if got, want := localDate(early, time.UTC), time.Date(2025, 6, 18, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
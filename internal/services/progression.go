@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/progression"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrUnknownProgressionScheme = domainerr.New(domainerr.Validation, "scheme must be linear, 531 or double")
+
+// progressionHistory is how many past sessions of an exercise the schemes look at
+const progressionHistory = 3
+
+// ProgressionService computes next-session prescriptions for workout templates
+type ProgressionService struct {
+	workouts      *WorkoutService
+	repo          repositories.ProgressionRepository
+	trainingMaxes *TrainingMaxService
+}
+
+// NewProgressionService creates a new progression service
+func NewProgressionService(workouts *WorkoutService, repo repositories.ProgressionRepository, trainingMaxes *TrainingMaxService) *ProgressionService {
+	return &ProgressionService{workouts: workouts, repo: repo, trainingMaxes: trainingMaxes}
+}
+
+// NextPrescription computes the loads and reps of each exercise for the next session of a
+// workout, from the performer's history (see workoutPerformer). Exercises use scheme, or
+// their template's progression_scheme, or linear progression. Warm-ups, cool-downs and
+// exercises without reps repeat the template; exercises that can't be progressed yet get
+// a note saying why instead of sets.
+func (s *ProgressionService) NextPrescription(ctx context.Context, workoutID string, actorID string, scheme string) (*models.WorkoutPrescription, error) {
+	if scheme != "" {
+		if _, err := progression.ForScheme(scheme); err != nil {
+			return nil, ErrUnknownProgressionScheme
+		}
+	}
+
+	workout, err := s.workouts.GetWorkout(ctx, workoutID, actorID)
+	if err != nil {
+		return nil, err
+	}
+	userID := workoutPerformer(workout, actorID)
+
+	exerciseIDs := make([]string, 0, len(workout.Exercises))
+	for _, we := range workout.Exercises {
+		exerciseIDs = append(exerciseIDs, we.Exercise.ID)
+	}
+	history, err := s.repo.RecentPerformances(ctx, userID, exerciseIDs, progressionHistory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get exercise history: %w", err)
+	}
+
+	prescription := &models.WorkoutPrescription{WorkoutID: workout.ID, Exercises: make([]*models.ExercisePrescription, 0, len(workout.Exercises))}
+	for _, we := range workout.Exercises {
+		p, err := s.prescribe(ctx, userID, we, scheme, history[we.Exercise.ID])
+		if err != nil {
+			return nil, err
+		}
+		p.WorkoutExerciseID = we.ID
+		p.ExerciseID = we.Exercise.ID
+		p.ExerciseName = we.Exercise.Name
+		if p.Sets == nil {
+			p.Sets = []models.PrescribedSet{}
+		}
+		prescription.Exercises = append(prescription.Exercises, p)
+	}
+
+	return prescription, nil
+}
+
+// prescribe computes one template exercise's prescription
+func (s *ProgressionService) prescribe(ctx context.Context, userID string, we *models.WorkoutExercise, scheme string, history []*models.ExercisePerformance) (*models.ExercisePrescription, error) {
+	load := we.WeightKg
+	if we.TrainingMaxName != nil && we.IntensityPercentage != nil {
+		resolved, err := s.trainingMaxes.ResolveLoad(ctx, userID, *we.TrainingMaxName, *we.IntensityPercentage)
+		switch {
+		case err == nil:
+			load = &resolved
+		case !isMissingTrainingMax(err):
+			return nil, err
+		}
+	}
+
+	sets := 1
+	if we.Sets != nil && *we.Sets > 0 {
+		sets = *we.Sets
+	}
+	if we.IsWarmup || we.IsCooldown || we.Reps == nil {
+		p := &models.ExercisePrescription{}
+		if we.Reps != nil {
+			p.Sets = templateSets(sets, *we.Reps, load)
+		}
+		return p, nil
+	}
+
+	if scheme == "" {
+		scheme = models.ProgressionLinear
+		if we.ProgressionScheme != nil {
+			scheme = *we.ProgressionScheme
+		}
+	}
+	engine, err := progression.ForScheme(scheme)
+	if err != nil {
+		return nil, ErrUnknownProgressionScheme
+	}
+
+	in := progression.Input{
+		Sets:        sets,
+		Reps:        *we.Reps,
+		WeightKg:    load,
+		IncrementKg: progression.IncrementFor(we.Exercise.MuscleGroup),
+		History:     history,
+	}
+	if we.MinReps != nil {
+		in.MinReps = *we.MinReps
+	}
+	if scheme == models.ProgressionFiveThreeOne && we.TrainingMaxName != nil {
+		tm, err := s.trainingMaxes.GetTrainingMax(ctx, userID, *we.TrainingMaxName)
+		switch {
+		case err == nil:
+			in.TrainingMaxKg = &tm.WeightKg
+			in.SessionsSinceTrainingMax, err = s.repo.SessionsSince(ctx, userID, we.Exercise.ID, tm.UpdatedAt)
+			if err != nil {
+				return nil, fmt.Errorf("failed to count sessions: %w", err)
+			}
+		case !isMissingTrainingMax(err):
+			return nil, err
+		}
+	}
+
+	p, err := engine.Next(in)
+	switch {
+	case errors.Is(err, progression.ErrNoTrainingMax):
+		p = &models.ExercisePrescription{Note: "5/3/1 needs a training max: set training_max_name on the template entry"}
+	case errors.Is(err, progression.ErrNoStartingLoad):
+		p = &models.ExercisePrescription{Note: "No load to start from: set weight_kg on the template entry or log a session"}
+	case err != nil:
+		return nil, err
+	}
+	p.Scheme = scheme
+	return p, nil
+}
+
+// isMissingTrainingMax reports whether err means the template names a training max the
+// user hasn't set, which leaves the entry to its other prescription
+func isMissingTrainingMax(err error) bool {
+	return errors.Is(err, ErrTrainingMaxNotFound) || errors.Is(err, ErrInvalidTrainingMaxName)
+}
+
+// templateSets repeats a template entry as is
+func templateSets(sets, reps int, load *float64) []models.PrescribedSet {
+	prescribed := make([]models.PrescribedSet, sets)
+	for i := range prescribed {
+		prescribed[i] = models.PrescribedSet{Reps: reps, WeightKg: load}
+	}
+	return prescribed
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestNextPrescription(t *testing.T) {
+	sets, reps, pct := 3, 5, 75.0
+	fiveThreeOne := models.ProgressionFiveThreeOne
+	tmName := "Squat"
+	quads := "quads"
+	mockWorkouts := &repositories.MockWorkoutRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Workout, error) {
+			return &models.Workout{ID: id, UserID: "user-123", Exercises: []*models.WorkoutExercise{
+				{ID: "we-1", Sets: &sets, Reps: &reps, IntensityPercentage: &pct, TrainingMaxName: &tmName, ProgressionScheme: &fiveThreeOne,
+					Exercise: &models.Exercise{ID: "ex-squat", Name: "Squat", MuscleGroup: &quads}},
+				{ID: "we-2", Sets: &sets, Reps: &reps,
+					Exercise: &models.Exercise{ID: "ex-row", Name: "Row"}},
+				{ID: "we-3", IsWarmup: true,
+					Exercise: &models.Exercise{ID: "ex-bike", Name: "Bike"}},
+			}}, nil
+		},
+	}
+	workouts := NewWorkoutService(mockWorkouts, &repositories.MockInjuryRepository{},
+		NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil)
+
+	weight, done := 60.0, 5
+	repo := &repositories.MockProgressionRepository{
+		RecentPerformancesFunc: func(ctx context.Context, userID string, exerciseIDs []string, limit int) (map[string][]*models.ExercisePerformance, error) {
+			return map[string][]*models.ExercisePerformance{
+				"ex-row": {{ExerciseID: "ex-row", WeightKg: &weight, SetsCompleted: 3, RepsCompleted: &done}},
+			}, nil
+		},
+		SessionsSinceFunc: func(ctx context.Context, userID string, exerciseID string, since time.Time) (int, error) {
+			return 1, nil
+		},
+	}
+	trainingMaxes := NewTrainingMaxService(&repositories.MockTrainingMaxRepository{
+		FindByNameFunc: func(ctx context.Context, userID string, name string) (*models.TrainingMax, error) {
+			if name != "squat" {
+				t.Errorf("Expected the normalized name, got %q", name)
+			}
+			return &models.TrainingMax{Name: name, WeightKg: 140}, nil
+		},
+	})
+
+	service := NewProgressionService(workouts, repo, trainingMaxes)
+
+	prescription, err := service.NextPrescription(context.Background(), "workout-1", "user-123", "")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	squat, row, bike := prescription.Exercises[0], prescription.Exercises[1], prescription.Exercises[2]
+	if squat.Scheme != fiveThreeOne || *squat.Week != 2 || *squat.Sets[2].WeightKg != 125 {
+		t.Errorf("Expected 5/3/1 week 2 topping at 125 kg, got %+v", squat)
+	}
+	if row.Scheme != models.ProgressionLinear || *row.Sets[0].WeightKg != 62.5 {
+		t.Errorf("Expected linear progression to 62.5 kg, got %+v", row)
+	}
+	if bike.Scheme != "" || len(bike.Sets) != 0 {
+		t.Errorf("Expected the warm-up repeated as is, got %+v", bike)
+	}
+
+	if _, err := service.NextPrescription(context.Background(), "workout-1", "user-123", "wave"); !errors.Is(err, ErrUnknownProgressionScheme) {
+		t.Errorf("Expected ErrUnknownProgressionScheme, got %v", err)
+	}
+}
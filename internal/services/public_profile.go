@@ -0,0 +1,146 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInvalidUsername       = domainerr.New(domainerr.Validation, "username must be 3 to 30 lowercase letters, digits or underscores")
+	ErrUsernameTaken         = domainerr.New(domainerr.Conflict, "username is already taken")
+	ErrPublicProfileNotFound = domainerr.New(domainerr.NotFound, "public profile not found")
+)
+
+var usernamePattern = regexp.MustCompile(`^[a-z0-9_]{3,30}$`)
+
+const (
+	publicRecordHighlights = 5
+	publicRecentWorkouts   = 5
+)
+
+// PublicProfileService handles the profiles users show others under a username
+type PublicProfileService struct {
+	repo    repositories.PublicProfileRepository
+	follows repositories.FollowRepository
+}
+
+// NewPublicProfileService creates a new public profile service
+func NewPublicProfileService(repo repositories.PublicProfileRepository, follows repositories.FollowRepository) *PublicProfileService {
+	return &PublicProfileService{repo: repo, follows: follows}
+}
+
+// GetSettings retrieves the user's public profile settings
+func (s *PublicProfileService) GetSettings(ctx context.Context, userID string) (*models.PublicProfileSettings, error) {
+	settings, err := s.repo.FindSettings(ctx, userID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPublicProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to get public profile: %w", err)
+	}
+	return settings, nil
+}
+
+// UpdateSettings replaces the user's username and display name; visibility flags the
+// request leaves out keep their current value. Usernames are case-insensitive and stored
+// in lowercase.
+func (s *PublicProfileService) UpdateSettings(ctx context.Context, userID string, req *models.UpdatePublicProfileSettingsRequest) (*models.PublicProfileSettings, error) {
+	username := strings.ToLower(strings.TrimSpace(req.Username))
+	if !usernamePattern.MatchString(username) {
+		return nil, ErrInvalidUsername
+	}
+
+	settings, err := s.repo.FindSettings(ctx, userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		settings = &models.PublicProfileSettings{ShowDisplayName: true, ShowRecords: true, ShowRecentWorkouts: true, ShowFollowCounts: true}
+	} else if err != nil {
+		return nil, fmt.Errorf("failed to get public profile: %w", err)
+	}
+
+	settings.UserID = userID
+	settings.Username = username
+	settings.DisplayName = nil
+	if req.DisplayName != nil {
+		if name := strings.TrimSpace(*req.DisplayName); name != "" {
+			settings.DisplayName = &name
+		}
+	}
+	if req.ShowDisplayName != nil {
+		settings.ShowDisplayName = *req.ShowDisplayName
+	}
+	if req.ShowRecords != nil {
+		settings.ShowRecords = *req.ShowRecords
+	}
+	if req.ShowRecentWorkouts != nil {
+		settings.ShowRecentWorkouts = *req.ShowRecentWorkouts
+	}
+	if req.ShowFollowCounts != nil {
+		settings.ShowFollowCounts = *req.ShowFollowCounts
+	}
+
+	if err := s.repo.SaveSettings(ctx, settings); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return nil, ErrUsernameTaken
+		}
+		return nil, fmt.Errorf("failed to save public profile: %w", err)
+	}
+	return settings, nil
+}
+
+// GetPublicProfile returns the profile of the user with username as the viewer may see
+// it: the user's privacy decides whether they see it at all, and the user's settings which
+// parts. Users see all of their own profile.
+func (s *PublicProfileService) GetPublicProfile(ctx context.Context, viewerID string, username string) (*models.PublicProfile, error) {
+	settings, err := s.repo.FindSettingsByUsername(ctx, strings.ToLower(username))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPublicProfileNotFound
+		}
+		return nil, fmt.Errorf("failed to get public profile: %w", err)
+	}
+
+	own := settings.UserID == viewerID
+	if !own {
+		visible, err := s.follows.CanView(ctx, viewerID, settings.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check profile visibility: %w", err)
+		}
+		if !visible {
+			return nil, ErrProfileNotVisible
+		}
+	}
+
+	profile := &models.PublicProfile{Username: settings.Username}
+	if own || settings.ShowDisplayName {
+		profile.DisplayName = settings.DisplayName
+	}
+	if own || settings.ShowRecords {
+		profile.Records, err = s.repo.RecordHighlights(ctx, settings.UserID, publicRecordHighlights)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get personal records: %w", err)
+		}
+	}
+	if own || settings.ShowRecentWorkouts {
+		profile.RecentWorkouts, err = s.repo.RecentSessions(ctx, settings.UserID, publicRecentWorkouts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get recent workouts: %w", err)
+		}
+	}
+	if own || settings.ShowFollowCounts {
+		followers, following, err := s.repo.FollowCounts(ctx, settings.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count follows: %w", err)
+		}
+		profile.FollowerCount, profile.FollowingCount = &followers, &following
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGetPublicProfile_HidesFields(t *testing.T) {
+	name := "Ana"
+	repo := &repositories.MockPublicProfileRepository{
+		FindSettingsByUsernameFunc: func(ctx context.Context, username string) (*models.PublicProfileSettings, error) {
+			if username != "ana_lifts" {
+				t.Errorf("Expected a lowercase username lookup, got %s", username)
+			}
+			return &models.PublicProfileSettings{UserID: "user-456", Username: username, DisplayName: &name, ShowDisplayName: true, ShowFollowCounts: true}, nil
+		},
+		FollowCountsFunc: func(ctx context.Context, userID string) (int, int, error) {
+			return 12, 3, nil
+		},
+	}
+	follows := &repositories.MockFollowRepository{
+		CanViewFunc: func(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+			return true, nil
+		},
+	}
+	service := NewPublicProfileService(repo, follows)
+
+	profile, err := service.GetPublicProfile(context.Background(), "user-123", "Ana_Lifts")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if profile.DisplayName == nil || *profile.FollowerCount != 12 || *profile.FollowingCount != 3 {
+		t.Errorf("Expected the display name and follow counts, got %+v", profile)
+	}
+	if profile.Records != nil || profile.RecentWorkouts != nil {
+		t.Errorf("Expected records and workouts hidden, got %+v", profile)
+	}
+}
+
+func TestGetPublicProfile_Visibility(t *testing.T) {
+	repo := &repositories.MockPublicProfileRepository{
+		FindSettingsByUsernameFunc: func(ctx context.Context, username string) (*models.PublicProfileSettings, error) {
+			return &models.PublicProfileSettings{UserID: "user-456", Username: username}, nil
+		},
+	}
+	follows := &repositories.MockFollowRepository{
+		CanViewFunc: func(ctx context.Context, viewerID string, ownerID string) (bool, error) {
+			return false, nil
+		},
+	}
+	service := NewPublicProfileService(repo, follows)
+
+	if _, err := service.GetPublicProfile(context.Background(), "user-123", "ana"); !errors.Is(err, ErrProfileNotVisible) {
+		t.Errorf("Expected ErrProfileNotVisible, got %v", err)
+	}
+
+	own, err := service.GetPublicProfile(context.Background(), "user-456", "ana")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if own.FollowerCount == nil || own.Records == nil {
+		t.Errorf("Expected users to see all of their own profile, got %+v", own)
+	}
+
+	if _, err := NewPublicProfileService(&repositories.MockPublicProfileRepository{}, follows).GetPublicProfile(context.Background(), "user-123", "nobody"); !errors.Is(err, ErrPublicProfileNotFound) {
+		t.Errorf("Expected ErrPublicProfileNotFound, got %v", err)
+	}
+}
+
+func TestUpdatePublicProfileSettings(t *testing.T) {
+	hide := false
+	var saved *models.PublicProfileSettings
+	repo := &repositories.MockPublicProfileRepository{
+		SaveSettingsFunc: func(ctx context.Context, settings *models.PublicProfileSettings) error {
+			saved = settings
+			return nil
+		},
+	}
+	service := NewPublicProfileService(repo, &repositories.MockFollowRepository{})
+
+	_, err := service.UpdateSettings(context.Background(), "user-123", &models.UpdatePublicProfileSettingsRequest{Username: " Ana_Lifts ", ShowRecords: &hide})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.Username != "ana_lifts" || saved.ShowRecords || !saved.ShowRecentWorkouts || !saved.ShowFollowCounts {
+		t.Errorf("Expected a lowercase username with only records hidden, got %+v", saved)
+	}
+}
+
+func TestUpdatePublicProfileSettings_Errors(t *testing.T) {
+	repo := &repositories.MockPublicProfileRepository{
+		SaveSettingsFunc: func(ctx context.Context, settings *models.PublicProfileSettings) error {
+			return repositories.ErrDuplicate
+		},
+	}
+	service := NewPublicProfileService(repo, &repositories.MockFollowRepository{})
+
+	if _, err := service.UpdateSettings(context.Background(), "user-123", &models.UpdatePublicProfileSettingsRequest{Username: "ana lifts"}); !errors.Is(err, ErrInvalidUsername) {
+		t.Errorf("Expected ErrInvalidUsername, got %v", err)
+	}
+	if _, err := service.UpdateSettings(context.Background(), "user-123", &models.UpdatePublicProfileSettingsRequest{Username: "ana"}); !errors.Is(err, ErrUsernameTaken) {
+		t.Errorf("Expected ErrUsernameTaken, got %v", err)
+	}
+}
@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/push"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrDeviceNotFound     = domainerr.New(domainerr.NotFound, "device not found")
+	ErrInvalidDevice      = domainerr.New(domainerr.Validation, "invalid device")
+	ErrUnknownPushKind    = domainerr.New(domainerr.Validation, "unknown push notification kind")
+	errIncompletePushData = errors.New("notification data is incomplete")
+)
+
+const (
+	// maxDeviceTokenLength bounds tokens; APNs tokens are 64 hex characters and FCM
+	// tokens a few hundred characters
+	maxDeviceTokenLength = 4096
+
+	// pushBatch caps how many notifications one background run sends
+	pushBatch = 50
+
+	// pushSendTimeout bounds sending one notification to all of a user's devices
+	pushSendTimeout = 20 * time.Second
+
+	// pushLease keeps a claimed notification from being picked up again while its batch runs
+	pushLease = 5 * time.Minute
+
+	// pushMaxAttempts is how often a notification is tried before giving up; a push that
+	// arrives hours late is noise, so retries stop after about half an hour
+	pushMaxAttempts = 5
+
+	// pushRetention is how long sent and failed notifications are kept
+	pushRetention = 7 * 24 * time.Hour
+)
+
+// PushSender delivers a notification to one device token of its platform
+type PushSender interface {
+	Send(ctx context.Context, token string, n *push.Notification) error
+}
+
+// pushFormats renders each kind of notification from its queued data
+var pushFormats = map[string]func(data json.RawMessage) (*push.Notification, error){
+	models.PushKindWorkoutAssigned: func(data json.RawMessage) (*push.Notification, error) {
+		var workout struct {
+			WorkoutID string `json:"workout_id"`
+			Name      string `json:"name"`
+		}
+		if err := json.Unmarshal(data, &workout); err != nil {
+			return nil, err
+		}
+		if workout.WorkoutID == "" {
+			return nil, errIncompletePushData
+		}
+		return &push.Notification{
+			Title: "New workout from your coach",
+			Body:  workout.Name,
+			Data:  map[string]string{"kind": models.PushKindWorkoutAssigned, "workout_id": workout.WorkoutID},
+		}, nil
+	},
+	models.PushKindWorkoutReminder: func(data json.RawMessage) (*push.Notification, error) {
+		var reminder struct {
+			RuleID string `json:"rule_id"`
+			Name   string `json:"name"`
+		}
+		if err := json.Unmarshal(data, &reminder); err != nil {
+			return nil, err
+		}
+		if reminder.RuleID == "" {
+			return nil, errIncompletePushData
+		}
+		return &push.Notification{
+			Title: "Time to train",
+			Body:  reminder.Name,
+			Data:  map[string]string{"kind": models.PushKindWorkoutReminder, "rule_id": reminder.RuleID},
+		}, nil
+	},
+}
+
+// PushService manages users' devices and sends them the notifications queued for them
+type PushService struct {
+	repo          repositories.PushRepository
+	senders       map[string]PushSender // By platform; devices of other platforms are skipped
+	notifications *NotificationService  // Users' notification preferences; nil sends everything
+	audit         *AuditLogService
+	now           func() time.Time
+}
+
+// NewPushService creates a new push service with the senders of the configured platforms,
+// honouring users' notification preferences; device registrations are audited unless audit is nil
+func NewPushService(repo repositories.PushRepository, senders map[string]PushSender, notifications *NotificationService, audit *AuditLogService) *PushService {
+	return &PushService{repo: repo, senders: senders, notifications: notifications, audit: audit, now: time.Now}
+}
+
+// RegisterDevice registers a device for the user's notifications; registering a known
+// token again refreshes it
+func (s *PushService) RegisterDevice(ctx context.Context, userID string, req *models.RegisterDeviceRequest) (*models.DeviceToken, error) {
+	if !slices.Contains(models.DevicePlatforms, req.Platform) {
+		return nil, fmt.Errorf("%w: unknown platform %q, expected one of %v", ErrInvalidDevice, req.Platform, models.DevicePlatforms)
+	}
+	token := strings.TrimSpace(req.Token)
+	if token == "" || len(token) > maxDeviceTokenLength {
+		return nil, fmt.Errorf("%w: token must be 1 to %d characters", ErrInvalidDevice, maxDeviceTokenLength)
+	}
+
+	device := &models.DeviceToken{UserID: userID, Platform: req.Platform, Token: token}
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceDevice, "", nil, func() (*models.DeviceToken, error) {
+		if err := s.repo.RegisterDevice(ctx, device); err != nil {
+			return nil, fmt.Errorf("failed to register device: %w", err)
+		}
+		return device, nil
+	})
+}
+
+// ListDevices retrieves the user's devices
+func (s *PushService) ListDevices(ctx context.Context, userID string) ([]*models.DeviceToken, error) {
+	devices, err := s.repo.ListDevices(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	return devices, nil
+}
+
+// UnregisterDevice removes one of the user's devices
+func (s *PushService) UnregisterDevice(ctx context.Context, id string, userID string) error {
+	device, err := s.repo.FindDevice(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDeviceNotFound
+		}
+		return fmt.Errorf("failed to get device: %w", err)
+	}
+	if device.UserID != userID {
+		return ErrUnauthorized
+	}
+
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceDevice, id, device, func() (*models.DeviceToken, error) {
+		if err := s.repo.DeleteDevice(ctx, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrDeviceNotFound
+			}
+			return nil, fmt.Errorf("failed to delete device: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// Notify queues a notification of the given kind for the user's devices
+func (s *PushService) Notify(ctx context.Context, userID string, kind string, data any) error {
+	if _, ok := pushFormats[kind]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownPushKind, kind)
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to encode notification: %w", err)
+	}
+	if err := s.repo.Enqueue(ctx, userID, kind, raw); err != nil {
+		return fmt.Errorf("failed to queue notification: %w", err)
+	}
+	return nil
+}
+
+// Start sends due notifications every interval, and prunes old ones hourly, until ctx
+// is cancelled
+func (s *PushService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		pruneTicker := time.NewTicker(time.Hour)
+		defer pruneTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sendDue(ctx)
+			case <-pruneTicker.C:
+				if _, err := s.repo.Prune(ctx, s.now().Add(-pushRetention)); err != nil {
+					log.Printf("Push notifications failed to prune: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// sendDue sends one batch of due notifications
+func (s *PushService) sendDue(ctx context.Context) {
+	notifications, err := s.repo.ClaimDue(ctx, pushLease, pushBatch)
+	if err != nil {
+		log.Printf("Push notifications failed to claim: %v", err)
+		return
+	}
+
+	for _, n := range notifications {
+		if ctx.Err() != nil {
+			return
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, pushSendTimeout)
+		s.send(sendCtx, n)
+		cancel()
+	}
+}
+
+// send delivers a notification to each of the user's devices and records the outcome.
+// Notifications the user turned off are dropped and those due in their quiet hours held
+// until the end. Tokens the provider rejects are forgotten. The notification is retried
+// with backoff only when no device received it, so devices are not alerted twice.
+func (s *PushService) send(ctx context.Context, n *models.PushNotification) {
+	format, ok := pushFormats[n.Kind]
+	if !ok {
+		s.fail(ctx, n, fmt.Errorf("%w: %q", ErrUnknownPushKind, n.Kind), false)
+		return
+	}
+	content, err := format(n.Data)
+	if err != nil {
+		s.fail(ctx, n, fmt.Errorf("invalid notification data: %w", err), false)
+		return
+	}
+
+	if s.notifications != nil {
+		allowed, holdUntil, err := s.notifications.Deliverable(ctx, n.UserID, n.Kind, models.NotificationChannelPush)
+		switch {
+		case err != nil:
+			s.fail(ctx, n, err, true)
+			return
+		case !allowed:
+			if err := s.repo.Fail(ctx, n.ID, "turned off in the user's notification preferences", nil); err != nil {
+				log.Printf("Push notification %s failed to record it was turned off: %v", n.ID, err)
+			}
+			return
+		case !holdUntil.IsZero():
+			if err := s.repo.Hold(ctx, n.ID, holdUntil); err != nil {
+				log.Printf("Push notification %s failed to hold for quiet hours: %v", n.ID, err)
+			}
+			return
+		}
+	}
+
+	devices, err := s.repo.ListDevices(ctx, n.UserID)
+	if err != nil {
+		s.fail(ctx, n, fmt.Errorf("failed to list devices: %w", err), true)
+		return
+	}
+
+	delivered := 0
+	var sendErr error
+	for _, device := range devices {
+		sender, ok := s.senders[device.Platform]
+		if !ok {
+			continue
+		}
+		err := sender.Send(ctx, device.Token, content)
+		switch {
+		case err == nil:
+			delivered++
+		case errors.Is(err, push.ErrInvalidToken):
+			if err := s.repo.DeleteToken(ctx, device.Token); err != nil {
+				log.Printf("Failed to forget invalid %s device token of user %s: %v", device.Platform, n.UserID, err)
+			}
+		default:
+			sendErr = err
+		}
+	}
+
+	if sendErr != nil && delivered == 0 {
+		s.fail(ctx, n, sendErr, true)
+		return
+	}
+	if err := s.repo.Complete(ctx, n.ID); err != nil {
+		log.Printf("Push notification %s failed to record completion: %v", n.ID, err)
+	}
+}
+
+// fail records a failed attempt, scheduled for a retry with exponential backoff when
+// retry is set and attempts remain
+func (s *PushService) fail(ctx context.Context, n *models.PushNotification, err error, retry bool) {
+	var retryAt *time.Time
+	if retry && n.Attempts+1 < pushMaxAttempts {
+		next := s.now().Add(time.Minute << n.Attempts)
+		retryAt = &next
+	} else {
+		log.Printf("Push notification %s (%s) gave up after %d attempts: %v", n.ID, n.Kind, n.Attempts+1, err)
+	}
+	if err := s.repo.Fail(ctx, n.ID, err.Error(), retryAt); err != nil {
+		log.Printf("Push notification %s failed to record failure: %v", n.ID, err)
+	}
+}
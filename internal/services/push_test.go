@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/push"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+type fakePushSender struct {
+	sent []*push.Notification
+	errs map[string]error
+}
+
+func (f *fakePushSender) Send(ctx context.Context, token string, n *push.Notification) error {
+	if err := f.errs[token]; err != nil {
+		return err
+	}
+	f.sent = append(f.sent, n)
+	return nil
+}
+
+func TestRegisterDevice_Validates(t *testing.T) {
+	service := NewPushService(&repositories.MockPushRepository{}, nil, nil, nil)
+
+	device, err := service.RegisterDevice(context.Background(), "user-123", &models.RegisterDeviceRequest{Platform: "ios", Token: " abc123 "})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if device.ID != "mock-device-id" || device.Token != "abc123" {
+		t.Errorf("Expected a registered, trimmed token, got %+v", device)
+	}
+
+	if _, err := service.RegisterDevice(context.Background(), "user-123", &models.RegisterDeviceRequest{Platform: "web", Token: "abc123"}); !errors.Is(err, ErrInvalidDevice) {
+		t.Errorf("Expected ErrInvalidDevice for an unknown platform, got %v", err)
+	}
+}
+
+func TestUnregisterDevice_ChecksOwner(t *testing.T) {
+	deleted := false
+	mockRepo := &repositories.MockPushRepository{
+		FindDeviceFunc: func(ctx context.Context, id string) (*models.DeviceToken, error) {
+			return &models.DeviceToken{ID: id, UserID: "user-123"}, nil
+		},
+		DeleteDeviceFunc: func(ctx context.Context, id string) error {
+			deleted = true
+			return nil
+		},
+	}
+	service := NewPushService(mockRepo, nil, nil, nil)
+
+	if err := service.UnregisterDevice(context.Background(), "device-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if err := service.UnregisterDevice(context.Background(), "device-1", "user-123"); err != nil || !deleted {
+		t.Errorf("Expected the owner to delete the device, got %v", err)
+	}
+}
+
+func TestPushSend_FormatsAndForgetsInvalidTokens(t *testing.T) {
+	var forgotten []string
+	completed := false
+	mockRepo := &repositories.MockPushRepository{
+		ListDevicesFunc: func(ctx context.Context, userID string) ([]*models.DeviceToken, error) {
+			return []*models.DeviceToken{
+				{Platform: models.DevicePlatformIOS, Token: "iphone"},
+				{Platform: models.DevicePlatformAndroid, Token: "uninstalled"},
+				{Platform: models.DevicePlatformAndroid, Token: "pixel"},
+			}, nil
+		},
+		DeleteTokenFunc: func(ctx context.Context, token string) error {
+			forgotten = append(forgotten, token)
+			return nil
+		},
+		CompleteFunc: func(ctx context.Context, id string) error {
+			completed = true
+			return nil
+		},
+	}
+	apns := &fakePushSender{}
+	fcm := &fakePushSender{errs: map[string]error{"uninstalled": push.ErrInvalidToken}}
+	service := NewPushService(mockRepo, map[string]PushSender{models.DevicePlatformIOS: apns, models.DevicePlatformAndroid: fcm}, nil, nil)
+
+	service.send(context.Background(), &models.PushNotification{
+		ID:     "push-1",
+		UserID: "user-123",
+		Kind:   models.PushKindWorkoutAssigned,
+		Data:   []byte(`{"workout_id":"workout-1","name":"Leg day","coach_id":"coach-1"}`),
+	})
+
+	if len(apns.sent) != 1 || len(fcm.sent) != 1 {
+		t.Fatalf("Expected one push per valid device, got %d and %d", len(apns.sent), len(fcm.sent))
+	}
+	if n := apns.sent[0]; n.Body != "Leg day" || n.Data["workout_id"] != "workout-1" {
+		t.Errorf("Unexpected notification: %+v", n)
+	}
+	if len(forgotten) != 1 || forgotten[0] != "uninstalled" {
+		t.Errorf("Expected the invalid token to be forgotten, got %v", forgotten)
+	}
+	if !completed {
+		t.Error("Expected the notification to be completed")
+	}
+}
+
+func TestPushSend_RetriesWhenNoDeviceReceivedIt(t *testing.T) {
+	now := time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+	var retryAt *time.Time
+	mockRepo := &repositories.MockPushRepository{
+		ListDevicesFunc: func(ctx context.Context, userID string) ([]*models.DeviceToken, error) {
+			return []*models.DeviceToken{{Platform: models.DevicePlatformIOS, Token: "iphone"}}, nil
+		},
+		FailFunc: func(ctx context.Context, id string, reason string, at *time.Time) error {
+			retryAt = at
+			return nil
+		},
+	}
+	apns := &fakePushSender{errs: map[string]error{"iphone": errors.New("apns request failed with status 503")}}
+	service := NewPushService(mockRepo, map[string]PushSender{models.DevicePlatformIOS: apns}, nil, nil)
+	service.now = func() time.Time { return now }
+
+	n := &models.PushNotification{ID: "push-1", Kind: models.PushKindWorkoutAssigned, Data: []byte(`{"workout_id":"workout-1"}`), Attempts: 2}
+	service.send(context.Background(), n)
+	if retryAt == nil || !retryAt.Equal(now.Add(4*time.Minute)) {
+		t.Errorf("Expected a retry in 4 minutes, got %v", retryAt)
+	}
+
+	n.Attempts = pushMaxAttempts - 1
+	service.send(context.Background(), n)
+	if retryAt != nil {
+		t.Errorf("Expected to give up after %d attempts, got retry at %v", pushMaxAttempts, retryAt)
+	}
+}
+
+func TestPushSend_HonoursPreferences(t *testing.T) {
+	now := time.Date(2026, 5, 13, 23, 0, 0, 0, time.UTC)
+	var failedReason string
+	var failedRetry *time.Time
+	var heldUntil time.Time
+	mockRepo := &repositories.MockPushRepository{
+		ListDevicesFunc: func(ctx context.Context, userID string) ([]*models.DeviceToken, error) {
+			return []*models.DeviceToken{{Platform: models.DevicePlatformIOS, Token: "iphone"}}, nil
+		},
+		FailFunc: func(ctx context.Context, id string, reason string, retryAt *time.Time) error {
+			failedReason, failedRetry = reason, retryAt
+			return nil
+		},
+		HoldFunc: func(ctx context.Context, id string, until time.Time) error {
+			heldUntil = until
+			return nil
+		},
+	}
+	notifications := NewNotificationService(&repositories.MockNotificationRepository{
+		GetPreferencesFunc: func(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+			return &models.NotificationPreferences{
+				UserID:     userID,
+				Channels:   map[string][]string{models.NotificationWorkoutReminder: {}},
+				QuietHours: &models.QuietHours{Start: "22:00", End: "07:00", Timezone: "UTC"},
+			}, nil
+		},
+	})
+	notifications.now = func() time.Time { return now }
+	apns := &fakePushSender{}
+	service := NewPushService(mockRepo, map[string]PushSender{models.DevicePlatformIOS: apns}, notifications, nil)
+
+	service.send(context.Background(), &models.PushNotification{ID: "push-1", UserID: "user-123", Kind: models.PushKindWorkoutReminder, Data: []byte(`{"rule_id":"rule-1"}`)})
+	if failedReason == "" || failedRetry != nil {
+		t.Errorf("Expected a turned off notification to be dropped, got %q retrying at %v", failedReason, failedRetry)
+	}
+
+	service.send(context.Background(), &models.PushNotification{ID: "push-2", UserID: "user-123", Kind: models.PushKindWorkoutAssigned, Data: []byte(`{"workout_id":"workout-1"}`)})
+	if want := time.Date(2026, 5, 14, 7, 0, 0, 0, time.UTC); !heldUntil.Equal(want) {
+		t.Errorf("Expected the notification to be held until %v, got %v", want, heldUntil)
+	}
+	if len(apns.sent) != 0 {
+		t.Errorf("Expected nothing to be sent, got %d", len(apns.sent))
+	}
+}
+
+func TestNotify_RejectsUnknownKinds(t *testing.T) {
+	service := NewPushService(&repositories.MockPushRepository{}, nil, nil, nil)
+
+	if err := service.Notify(context.Background(), "user-123", "reminder.unknown", nil); !errors.Is(err, ErrUnknownPushKind) {
+		t.Errorf("Expected ErrUnknownPushKind, got %v", err)
+	}
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"github.com/juan-cantero/fitapi/internal/database"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+const (
+	defaultQueryStats = 25
+	maxQueryStats     = 200
+)
+
+// QueryStatsService reports how database statements perform, from the pool's tracer
+type QueryStatsService struct {
+	tracer *database.QueryTracer
+}
+
+// NewQueryStatsService creates a new query stats service
+func NewQueryStatsService(tracer *database.QueryTracer) *QueryStatsService {
+	return &QueryStatsService{tracer: tracer}
+}
+
+// Stats returns the statements taking the most database time in total.
+// limit outside 1..200 falls back to the default of 25.
+func (s *QueryStatsService) Stats(limit int) []*models.QueryStats {
+	if limit <= 0 || limit > maxQueryStats {
+		limit = defaultQueryStats
+	}
+	return s.tracer.Stats(limit)
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/featureflags"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/recommend"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInvalidTrainingGoal        = domainerr.New(domainerr.Validation, "goal must be strength, hypertrophy, fat_loss or general")
+	ErrInvalidRecommendationCount = domainerr.New(domainerr.Validation, "exercises must be between 1 and 10")
+)
+
+const (
+	defaultRecommendedExercises = 5
+	maxRecommendedExercises     = 10
+)
+
+var trainingGoals = map[string]bool{
+	models.TrainingGoalStrength:    true,
+	models.TrainingGoalHypertrophy: true,
+	models.TrainingGoalFatLoss:     true,
+	models.TrainingGoalGeneral:     true,
+}
+
+// RecommendationService proposes workouts through a pluggable recommend.Strategy
+type RecommendationService struct {
+	repo     repositories.RecommendationRepository
+	goals    repositories.GoalRepository
+	injuries repositories.InjuryRepository
+	strategy recommend.Strategy
+	flags    *featureflags.Flags
+	now      func() time.Time
+}
+
+// NewRecommendationService creates a new recommendation service that builds sessions with
+// strategy, for the users flags roll featureflags.Recommendations out to (all when nil)
+func NewRecommendationService(repo repositories.RecommendationRepository, goals repositories.GoalRepository, injuries repositories.InjuryRepository, strategy recommend.Strategy, flags *featureflags.Flags) *RecommendationService {
+	return &RecommendationService{repo: repo, goals: goals, injuries: injuries, strategy: strategy, flags: flags, now: time.Now}
+}
+
+// RecommendWorkout proposes a session of count exercises (0 for the default) for the user.
+// goal overrides the training goal read from the user's goals (see trainingGoal). Exercises
+// that need equipment the user lacks, or load a body part with an active injury, are never
+// proposed. Users the recommendations aren't rolled out to get ErrFeatureDisabled.
+func (s *RecommendationService) RecommendWorkout(ctx context.Context, userID string, goal string, count int) (*models.WorkoutRecommendation, error) {
+	if !s.flags.Enabled(ctx, featureflags.Recommendations, userID) {
+		return nil, ErrFeatureDisabled
+	}
+	if goal != "" && !trainingGoals[goal] {
+		return nil, ErrInvalidTrainingGoal
+	}
+	if count == 0 {
+		count = defaultRecommendedExercises
+	}
+	if count < 1 || count > maxRecommendedExercises {
+		return nil, ErrInvalidRecommendationCount
+	}
+
+	goals, err := s.goals.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list goals: %w", err)
+	}
+	derived, focus := trainingGoal(goals)
+	if goal == "" {
+		goal = derived
+	}
+	if goal != models.TrainingGoalStrength {
+		focus = nil
+	}
+
+	recency, err := s.repo.MuscleGroupRecency(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get training history: %w", err)
+	}
+	candidates, err := s.repo.Candidates(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get candidate exercises: %w", err)
+	}
+	byMuscleGroup, err := s.injuries.Contraindications(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contraindications: %w", err)
+	}
+	if len(byMuscleGroup) > 0 {
+		safe := candidates[:0:0]
+		for _, c := range candidates {
+			if _, injured := byMuscleGroup[c.MuscleGroup]; !injured {
+				safe = append(safe, c)
+			}
+		}
+		candidates = safe
+	}
+
+	rec, err := s.strategy.Recommend(ctx, &recommend.Input{
+		Now:             s.now(),
+		Goal:            goal,
+		Exercises:       count,
+		FocusExerciseID: focus,
+		Recency:         recency,
+		Candidates:      candidates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to recommend workout: %w", err)
+	}
+	return rec, nil
+}
+
+// trainingGoal reads a training goal from the user's oldest open goal that implies one: an
+// e1RM goal means strength, with its lift as the focus, and a body weight goal means fat
+// loss or hypertrophy depending on its direction. Without one, training is general.
+func trainingGoal(goals []*models.Goal) (string, *string) {
+	for _, g := range goals {
+		if g.Achieved {
+			continue
+		}
+		switch {
+		case g.Kind == models.GoalKindE1RM:
+			return models.TrainingGoalStrength, g.ExerciseID
+		case g.Kind == models.GoalKindBodyWeight && g.TargetValue < g.StartValue:
+			return models.TrainingGoalFatLoss, nil
+		case g.Kind == models.GoalKindBodyWeight && g.TargetValue > g.StartValue:
+			return models.TrainingGoalHypertrophy, nil
+		}
+	}
+	return models.TrainingGoalGeneral, nil
+}
@@ -0,0 +1,103 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/recommend"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// recordingStrategy captures the input it is given
+type recordingStrategy struct {
+	in *recommend.Input
+}
+
+func (s *recordingStrategy) Name() string { return "recording" }
+
+func (s *recordingStrategy) Recommend(ctx context.Context, in *recommend.Input) (*models.WorkoutRecommendation, error) {
+	s.in = in
+	return &models.WorkoutRecommendation{Strategy: s.Name(), Goal: in.Goal}, nil
+}
+
+func TestRecommendWorkout_GoalFromUserGoals(t *testing.T) {
+	squat := "ex-squat"
+	tests := []struct {
+		name      string
+		goals     []*models.Goal
+		override  string
+		wantGoal  string
+		wantFocus bool
+	}{
+		{"no goals", nil, "", models.TrainingGoalGeneral, false},
+		{"losing weight", []*models.Goal{{Kind: models.GoalKindBodyWeight, StartValue: 90, TargetValue: 80}}, "", models.TrainingGoalFatLoss, false},
+		{"gaining weight", []*models.Goal{{Kind: models.GoalKindBodyWeight, StartValue: 70, TargetValue: 75}}, "", models.TrainingGoalHypertrophy, false},
+		{"achieved goals are ignored", []*models.Goal{
+			{Kind: models.GoalKindBodyWeight, StartValue: 90, TargetValue: 80, Achieved: true},
+			{Kind: models.GoalKindE1RM, ExerciseID: &squat, TargetValue: 140},
+		}, "", models.TrainingGoalStrength, true},
+		{"override drops the focus lift", []*models.Goal{{Kind: models.GoalKindE1RM, ExerciseID: &squat, TargetValue: 140}}, models.TrainingGoalHypertrophy, models.TrainingGoalHypertrophy, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			goals := &repositories.MockGoalRepository{
+				FindByUserFunc: func(ctx context.Context, userID string) ([]*models.Goal, error) {
+					return tt.goals, nil
+				},
+			}
+			strategy := &recordingStrategy{}
+			service := NewRecommendationService(&repositories.MockRecommendationRepository{}, goals, &repositories.MockInjuryRepository{}, strategy, nil)
+
+			_, err := service.RecommendWorkout(context.Background(), "user-123", tt.override, 0)
+
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if strategy.in.Goal != tt.wantGoal || (strategy.in.FocusExerciseID != nil) != tt.wantFocus {
+				t.Errorf("Expected goal %s (focus %v), got %s (focus %v)", tt.wantGoal, tt.wantFocus, strategy.in.Goal, strategy.in.FocusExerciseID)
+			}
+			if strategy.in.Exercises != defaultRecommendedExercises {
+				t.Errorf("Expected %d exercises by default, got %d", defaultRecommendedExercises, strategy.in.Exercises)
+			}
+		})
+	}
+}
+
+func TestRecommendWorkout_ExcludesContraindicated(t *testing.T) {
+	repo := &repositories.MockRecommendationRepository{
+		CandidatesFunc: func(ctx context.Context, userID string) ([]*models.RecommendationCandidate, error) {
+			return []*models.RecommendationCandidate{
+				{ExerciseID: "ex-1", Name: "Bench Press", MuscleGroup: "chest"},
+				{ExerciseID: "ex-2", Name: "Squat", MuscleGroup: "quads"},
+			}, nil
+		},
+	}
+	injuries := &repositories.MockInjuryRepository{
+		ContraindicationsFunc: func(ctx context.Context, userID string) (map[string][]string, error) {
+			return map[string][]string{"chest": {"shoulder"}}, nil
+		},
+	}
+	strategy := &recordingStrategy{}
+	service := NewRecommendationService(repo, &repositories.MockGoalRepository{}, injuries, strategy, nil)
+
+	if _, err := service.RecommendWorkout(context.Background(), "user-123", "", 3); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(strategy.in.Candidates) != 1 || strategy.in.Candidates[0].ExerciseID != "ex-2" {
+		t.Errorf("Expected only the squat offered, got %+v", strategy.in.Candidates)
+	}
+}
+
+func TestRecommendWorkout_Validation(t *testing.T) {
+	service := NewRecommendationService(&repositories.MockRecommendationRepository{}, &repositories.MockGoalRepository{}, &repositories.MockInjuryRepository{}, recommend.NewHeuristic(), nil)
+
+	if _, err := service.RecommendWorkout(context.Background(), "user-123", "bulking", 0); !errors.Is(err, ErrInvalidTrainingGoal) {
+		t.Errorf("Expected ErrInvalidTrainingGoal, got %v", err)
+	}
+	if _, err := service.RecommendWorkout(context.Background(), "user-123", "", 11); !errors.Is(err, ErrInvalidRecommendationCount) {
+		t.Errorf("Expected ErrInvalidRecommendationCount, got %v", err)
+	}
+}
@@ -0,0 +1,340 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrReminderNotFound      = domainerr.New(domainerr.NotFound, "reminder rule not found")
+	ErrInvalidReminder       = domainerr.New(domainerr.Validation, "invalid reminder rule")
+	ErrReminderLimitExceeded = domainerr.New(domainerr.Validation, "too many reminder rules")
+)
+
+const (
+	// maxReminderRules caps the rules a user can have
+	maxReminderRules = 20
+
+	// defaultReminderName names rules created without one
+	defaultReminderName = "Workout"
+
+	// reminderBatch caps how many rules one scheduler run fires
+	reminderBatch = 100
+
+	// reminderLease keeps a claimed rule from being fired again while its batch runs
+	reminderLease = 5 * time.Minute
+
+	// reminderGrace is how late a reminder may still be sent, e.g. after downtime; later
+	// firings are skipped since "time to train" an hour late is noise
+	reminderGrace = 15 * time.Minute
+
+	// maxReminderPreview caps the firing times a preview lists
+	maxReminderPreview = 20
+)
+
+// ReminderService manages users' reminder rules and fires them through push and email
+type ReminderService struct {
+	repo   repositories.ReminderRepository
+	push   *PushService
+	emails mailer.Queue
+	now    func() time.Time
+}
+
+// NewReminderService creates a new reminder service dispatching through push and emails
+func NewReminderService(repo repositories.ReminderRepository, push *PushService, emails mailer.Queue) *ReminderService {
+	return &ReminderService{repo: repo, push: push, emails: emails, now: time.Now}
+}
+
+// CreateRule creates a reminder rule for the user
+func (s *ReminderService) CreateRule(ctx context.Context, userID string, req *models.CreateReminderRuleRequest) (*models.ReminderRule, error) {
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count reminder rules: %w", err)
+	}
+	if count >= maxReminderRules {
+		return nil, fmt.Errorf("%w: a user can have at most %d", ErrReminderLimitExceeded, maxReminderRules)
+	}
+
+	rule := &models.ReminderRule{
+		UserID:    userID,
+		Name:      req.Name,
+		Weekdays:  req.Weekdays,
+		TimeOfDay: req.TimeOfDay,
+		Timezone:  req.Timezone,
+		Channels:  req.Channels,
+		Enabled:   req.Enabled == nil || *req.Enabled,
+	}
+	if err := s.prepare(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Create(ctx, rule); err != nil {
+		return nil, fmt.Errorf("failed to create reminder rule: %w", err)
+	}
+	return rule, nil
+}
+
+// ListRules retrieves the user's reminder rules
+func (s *ReminderService) ListRules(ctx context.Context, userID string) ([]*models.ReminderRule, error) {
+	rules, err := s.repo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list reminder rules: %w", err)
+	}
+	return rules, nil
+}
+
+// UpdateRule changes one of the user's reminder rules
+func (s *ReminderService) UpdateRule(ctx context.Context, id string, userID string, req *models.UpdateReminderRuleRequest) (*models.ReminderRule, error) {
+	rule, err := s.findOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		rule.Name = *req.Name
+	}
+	if req.Weekdays != nil {
+		rule.Weekdays = req.Weekdays
+	}
+	if req.TimeOfDay != nil {
+		rule.TimeOfDay = *req.TimeOfDay
+	}
+	if req.Timezone != nil {
+		rule.Timezone = *req.Timezone
+	}
+	if req.Channels != nil {
+		rule.Channels = req.Channels
+	}
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	if err := s.prepare(rule); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Update(ctx, rule); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReminderNotFound
+		}
+		return nil, fmt.Errorf("failed to update reminder rule: %w", err)
+	}
+	return rule, nil
+}
+
+// DeleteRule removes one of the user's reminder rules
+func (s *ReminderService) DeleteRule(ctx context.Context, id string, userID string) error {
+	if _, err := s.findOwned(ctx, id, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrReminderNotFound
+		}
+		return fmt.Errorf("failed to delete reminder rule: %w", err)
+	}
+	return nil
+}
+
+// Preview lists the next count firing times of one of the user's rules; disabled rules
+// are previewed as if enabled
+func (s *ReminderService) Preview(ctx context.Context, id string, userID string, count int) (*models.ReminderPreview, error) {
+	rule, err := s.findOwned(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count <= 0 || count > maxReminderPreview {
+		count = maxReminderPreview
+	}
+
+	loc, hour, minute, err := parseReminderSchedule(rule)
+	if err != nil {
+		return nil, err
+	}
+	preview := &models.ReminderPreview{RuleID: rule.ID, FireTimes: make([]time.Time, 0, count)}
+	after := s.now()
+	for range count {
+		after = nextReminderFire(rule.Weekdays, hour, minute, loc, after)
+		preview.FireTimes = append(preview.FireTimes, after)
+	}
+	return preview, nil
+}
+
+func (s *ReminderService) findOwned(ctx context.Context, id string, userID string) (*models.ReminderRule, error) {
+	rule, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReminderNotFound
+		}
+		return nil, fmt.Errorf("failed to get reminder rule: %w", err)
+	}
+	if rule.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	return rule, nil
+}
+
+// prepare validates and normalizes a rule and computes its next firing time
+func (s *ReminderService) prepare(rule *models.ReminderRule) error {
+	rule.Name = normalizeName(rule.Name)
+	if rule.Name == "" {
+		rule.Name = defaultReminderName
+	}
+	if rule.Timezone == "" {
+		rule.Timezone = "UTC"
+	}
+
+	for _, day := range rule.Weekdays {
+		if day < 0 || day > 6 {
+			return fmt.Errorf("%w: weekdays must be 0 (Sunday) to 6 (Saturday)", ErrInvalidReminder)
+		}
+	}
+	slices.Sort(rule.Weekdays)
+	rule.Weekdays = slices.Compact(rule.Weekdays)
+	if len(rule.Weekdays) == 0 {
+		return fmt.Errorf("%w: at least one weekday is required", ErrInvalidReminder)
+	}
+
+	for _, channel := range rule.Channels {
+		if !slices.Contains(models.ReminderChannels, channel) {
+			return fmt.Errorf("%w: unknown channel %q, expected one of %v", ErrInvalidReminder, channel, models.ReminderChannels)
+		}
+	}
+	slices.Sort(rule.Channels)
+	rule.Channels = slices.Compact(rule.Channels)
+	if len(rule.Channels) == 0 {
+		return fmt.Errorf("%w: at least one channel is required", ErrInvalidReminder)
+	}
+
+	loc, hour, minute, err := parseReminderSchedule(rule)
+	if err != nil {
+		return err
+	}
+	rule.NextFireAt = nil
+	if rule.Enabled {
+		next := nextReminderFire(rule.Weekdays, hour, minute, loc, s.now())
+		rule.NextFireAt = &next
+	}
+	return nil
+}
+
+// parseReminderSchedule reads a rule's timezone and HH:MM time of day
+func parseReminderSchedule(rule *models.ReminderRule) (*time.Location, int, int, error) {
+	loc, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: unknown timezone %q", ErrInvalidReminder, rule.Timezone)
+	}
+	t, err := time.Parse("15:04", rule.TimeOfDay)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("%w: time_of_day must be HH:MM, got %q", ErrInvalidReminder, rule.TimeOfDay)
+	}
+	return loc, t.Hour(), t.Minute(), nil
+}
+
+// nextReminderFire returns the first time after the given one that falls on one of the
+// weekdays at the local time of day. A time skipped by a DST change fires at the
+// corresponding time after the change.
+func nextReminderFire(weekdays []int, hour, minute int, loc *time.Location, after time.Time) time.Time {
+	local := after.In(loc)
+	for i := 0; i <= 7; i++ {
+		candidate := time.Date(local.Year(), local.Month(), local.Day()+i, hour, minute, 0, 0, loc)
+		if candidate.After(after) && slices.Contains(weekdays, int(candidate.Weekday())) {
+			return candidate
+		}
+	}
+	// Unreachable with at least one weekday; a week from now keeps the rule alive
+	return after.Add(7 * 24 * time.Hour)
+}
+
+// Start fires due reminder rules every interval until ctx is cancelled
+func (s *ReminderService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.fireDue(ctx)
+			}
+		}
+	}()
+}
+
+// fireDue fires one batch of due rules and schedules their next firings
+func (s *ReminderService) fireDue(ctx context.Context) {
+	due, err := s.repo.ClaimDue(ctx, reminderLease, reminderBatch)
+	if err != nil {
+		log.Printf("Reminder rules failed to claim: %v", err)
+		return
+	}
+
+	for _, d := range due {
+		if ctx.Err() != nil {
+			return
+		}
+		s.fire(ctx, d)
+	}
+}
+
+// fire dispatches a claimed rule's reminder, unless it is too late, and schedules the rule
+// again. A failed channel is logged rather than retried, since the next firing is close.
+func (s *ReminderService) fire(ctx context.Context, d *models.DueReminder) {
+	rule := d.Rule
+	now := s.now()
+
+	loc, hour, minute, err := parseReminderSchedule(rule)
+	if err != nil {
+		// Rules are validated on save; this only happens if the tz database changed
+		log.Printf("Reminder rule %s has an invalid schedule, retrying tomorrow: %v", rule.ID, err)
+		s.reschedule(ctx, rule.ID, nil, now.Add(24*time.Hour))
+		return
+	}
+	next := nextReminderFire(rule.Weekdays, hour, minute, loc, now)
+
+	dueAt := now
+	if rule.NextFireAt != nil {
+		dueAt = *rule.NextFireAt
+	}
+	if now.Sub(dueAt) > reminderGrace {
+		s.reschedule(ctx, rule.ID, nil, next)
+		return
+	}
+
+	for _, channel := range rule.Channels {
+		switch channel {
+		case models.ReminderChannelPush:
+			data := map[string]string{"rule_id": rule.ID, "name": rule.Name}
+			if err := s.push.Notify(ctx, rule.UserID, models.PushKindWorkoutReminder, data); err != nil {
+				log.Printf("Reminder rule %s failed to queue push: %v", rule.ID, err)
+			}
+		case models.ReminderChannelEmail:
+			if d.Email == "" {
+				continue
+			}
+			data := mailer.WorkoutReminder{RuleID: rule.ID, Name: rule.Name, StartsAt: dueAt, Timezone: rule.Timezone}
+			if err := s.emails.Enqueue(ctx, rule.UserID, d.Email, mailer.TemplateWorkoutReminder, data); err != nil {
+				log.Printf("Reminder rule %s failed to queue email: %v", rule.ID, err)
+			}
+		}
+	}
+	s.reschedule(ctx, rule.ID, &now, next)
+}
+
+func (s *ReminderService) reschedule(ctx context.Context, id string, firedAt *time.Time, next time.Time) {
+	if err := s.repo.Fired(ctx, id, firedAt, next); err != nil {
+		log.Printf("Reminder rule %s failed to reschedule: %v", id, err)
+	}
+}
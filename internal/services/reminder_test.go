@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/mailer"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestNextReminderFire(t *testing.T) {
+	madrid, _ := time.LoadLocation("Europe/Madrid")
+	weekdays := []int{1, 3, 5} // Mon/Wed/Fri
+
+	tests := []struct {
+		name  string
+		after time.Time
+		want  time.Time
+	}{
+		{"later today", time.Date(2026, 3, 2, 17, 0, 0, 0, madrid), time.Date(2026, 3, 2, 18, 0, 0, 0, madrid)},
+		{"exactly at the time", time.Date(2026, 3, 2, 18, 0, 0, 0, madrid), time.Date(2026, 3, 4, 18, 0, 0, 0, madrid)},
+		{"over the weekend", time.Date(2026, 3, 6, 19, 0, 0, 0, madrid), time.Date(2026, 3, 9, 18, 0, 0, 0, madrid)},
+		{"across DST", time.Date(2026, 3, 27, 19, 0, 0, 0, madrid), time.Date(2026, 3, 30, 18, 0, 0, 0, madrid)},
+	}
+	for _, tt := range tests {
+		got := nextReminderFire(weekdays, 18, 0, madrid, tt.after.UTC())
+		if !got.Equal(tt.want) {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// 02:30 does not exist on the day clocks spring forward
+	got := nextReminderFire([]int{0}, 2, 30, madrid, time.Date(2026, 3, 28, 12, 0, 0, 0, madrid))
+	if want := time.Date(2026, 3, 29, 3, 30, 0, 0, madrid); !got.Equal(want) {
+		t.Errorf("Expected a skipped time to fire after the change at %v, got %v", want, got)
+	}
+}
+
+func TestCreateReminderRule_ValidatesAndSchedules(t *testing.T) {
+	service := NewReminderService(&repositories.MockReminderRepository{}, nil, nil)
+	service.now = func() time.Time { return time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) } // Monday
+
+	rule, err := service.CreateRule(context.Background(), "user-123", &models.CreateReminderRuleRequest{
+		Weekdays:  []int{5, 1, 1},
+		TimeOfDay: "18:00",
+		Timezone:  "Europe/Madrid",
+		Channels:  []string{"push", "email", "push"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if rule.Name != "Workout" || len(rule.Weekdays) != 2 || len(rule.Channels) != 2 || !rule.Enabled {
+		t.Errorf("Expected a normalized, enabled rule, got %+v", rule)
+	}
+	if want := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC); rule.NextFireAt == nil || !rule.NextFireAt.Equal(want) {
+		t.Errorf("Expected the rule to fire at %v, got %v", want, rule.NextFireAt)
+	}
+
+	invalid := []models.CreateReminderRuleRequest{
+		{Weekdays: []int{7}, TimeOfDay: "18:00", Channels: []string{"push"}},
+		{Weekdays: []int{1}, TimeOfDay: "6pm", Channels: []string{"push"}},
+		{Weekdays: []int{1}, TimeOfDay: "18:00", Timezone: "Mars/Olympus", Channels: []string{"push"}},
+		{Weekdays: []int{1}, TimeOfDay: "18:00", Channels: []string{"sms"}},
+	}
+	for _, req := range invalid {
+		if _, err := service.CreateRule(context.Background(), "user-123", &req); !errors.Is(err, ErrInvalidReminder) {
+			t.Errorf("Expected ErrInvalidReminder for %+v, got %v", req, err)
+		}
+	}
+
+	disabled := false
+	rule, err = service.CreateRule(context.Background(), "user-123", &models.CreateReminderRuleRequest{
+		Weekdays: []int{1}, TimeOfDay: "18:00", Channels: []string{"push"}, Enabled: &disabled,
+	})
+	if err != nil || rule.NextFireAt != nil {
+		t.Errorf("Expected a disabled rule not to be scheduled, got %v, %v", rule, err)
+	}
+}
+
+func TestCreateReminderRule_Limit(t *testing.T) {
+	mockRepo := &repositories.MockReminderRepository{
+		CountByUserFunc: func(ctx context.Context, userID string) (int, error) {
+			return maxReminderRules, nil
+		},
+	}
+	service := NewReminderService(mockRepo, nil, nil)
+
+	_, err := service.CreateRule(context.Background(), "user-123", &models.CreateReminderRuleRequest{
+		Weekdays: []int{1}, TimeOfDay: "18:00", Channels: []string{"push"},
+	})
+	if !errors.Is(err, ErrReminderLimitExceeded) {
+		t.Errorf("Expected ErrReminderLimitExceeded, got %v", err)
+	}
+}
+
+func TestReminderFire_DispatchesAndReschedules(t *testing.T) {
+	now := time.Date(2026, 3, 2, 17, 0, 30, 0, time.UTC)
+	var pushed []string
+	pushService := NewPushService(&repositories.MockPushRepository{
+		EnqueueFunc: func(ctx context.Context, userID string, kind string, data []byte) error {
+			pushed = append(pushed, kind+" "+string(data))
+			return nil
+		},
+	}, nil, nil, nil)
+	var emailed []*models.EmailJob
+	emailService := newTestEmailService(&repositories.MockEmailRepository{}, &fakeMailer{}, &emailed)
+
+	var fired []*time.Time
+	var nexts []time.Time
+	mockRepo := &repositories.MockReminderRepository{
+		FiredFunc: func(ctx context.Context, id string, firedAt *time.Time, next time.Time) error {
+			fired = append(fired, firedAt)
+			nexts = append(nexts, next)
+			return nil
+		},
+	}
+	service := NewReminderService(mockRepo, pushService, emailService)
+	service.now = func() time.Time { return now }
+
+	dueAt := time.Date(2026, 3, 2, 17, 0, 0, 0, time.UTC)
+	rule := &models.ReminderRule{
+		ID: "rule-1", UserID: "user-123", Name: "Evening training", Weekdays: []int{1, 3},
+		TimeOfDay: "18:00", Timezone: "Europe/Madrid", Channels: []string{"email", "push"}, NextFireAt: &dueAt,
+	}
+	service.fire(context.Background(), &models.DueReminder{Rule: rule, Email: "user@example.com"})
+
+	if len(pushed) != 1 || pushed[0] != `workout.reminder {"name":"Evening training","rule_id":"rule-1"}` {
+		t.Errorf("Expected a reminder push, got %v", pushed)
+	}
+	if len(emailed) != 1 || emailed[0].To != "user@example.com" || emailed[0].Template != mailer.TemplateWorkoutReminder {
+		t.Fatalf("Expected a reminder email, got %+v", emailed)
+	}
+	var data mailer.WorkoutReminder
+	json.Unmarshal(emailed[0].Data, &data)
+	if !data.StartsAt.Equal(dueAt) || data.Timezone != "Europe/Madrid" {
+		t.Errorf("Expected the email to show the due time in the rule's timezone, got %+v", data)
+	}
+	if want := time.Date(2026, 3, 4, 17, 0, 0, 0, time.UTC); fired[0] == nil || !nexts[0].Equal(want) {
+		t.Errorf("Expected the rule to be fired and rescheduled for %v, got %v", want, nexts[0])
+	}
+
+	// A firing missed by more than the grace period is skipped
+	late := now.Add(-time.Hour)
+	rule.NextFireAt = &late
+	service.fire(context.Background(), &models.DueReminder{Rule: rule, Email: "user@example.com"})
+	if len(pushed) != 1 || len(emailed) != 1 || fired[1] != nil {
+		t.Errorf("Expected a late firing to be skipped, got %d pushes, %d emails", len(pushed), len(emailed))
+	}
+}
+
+func TestReminderPreview_ChecksOwner(t *testing.T) {
+	mockRepo := &repositories.MockReminderRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.ReminderRule, error) {
+			return &models.ReminderRule{ID: id, UserID: "user-123", Weekdays: []int{1}, TimeOfDay: "06:00", Timezone: "UTC"}, nil
+		},
+	}
+	service := NewReminderService(mockRepo, nil, nil)
+	service.now = func() time.Time { return time.Date(2026, 3, 2, 12, 0, 0, 0, time.UTC) }
+
+	if _, err := service.Preview(context.Background(), "rule-1", "user-789", 3); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+
+	preview, err := service.Preview(context.Background(), "rule-1", "user-123", 3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := []time.Time{
+		time.Date(2026, 3, 9, 6, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 16, 6, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 23, 6, 0, 0, 0, time.UTC),
+	}
+	if len(preview.FireTimes) != 3 {
+		t.Fatalf("Expected 3 firing times, got %v", preview.FireTimes)
+	}
+	for i := range want {
+		if !preview.FireTimes[i].Equal(want[i]) {
+			t.Errorf("Expected firing %d at %v, got %v", i, want[i], preview.FireTimes[i])
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrReportNotFound = domainerr.New(domainerr.NotFound, "report not found")
+
+const (
+	// defaultReportPage and maxReportPage bound a page of weekly reports
+	defaultReportPage = 12
+	maxReportPage     = 52
+
+	// summaryWindow is how long into a week the previous week's summaries are emailed, so
+	// that a late run doesn't send a summary of a stale week
+	summaryWindow = 24 * time.Hour
+)
+
+// ReportService compiles users' weekly training reports and emails them to users who
+// turned weekly summaries on
+type ReportService struct {
+	repo   repositories.ReportRepository
+	emails *EmailService
+	now    func() time.Time
+}
+
+// NewReportService creates a new report service
+func NewReportService(repo repositories.ReportRepository, emails *EmailService) *ReportService {
+	return &ReportService{repo: repo, emails: emails, now: time.Now}
+}
+
+// GenerateWeeklyReports compiles the reports of last week (Monday to Sunday, in each
+// user's timezone) and, during the first day of the week, queues their summary emails.
+// Reports and emails already created are skipped, so the scheduler can run it repeatedly;
+// users whose Sunday isn't over yet get theirs on a later run.
+func (s *ReportService) GenerateWeeklyReports(ctx context.Context) error {
+	now := s.now().UTC()
+	thisWeek := weekStart(now)
+	lastWeek := thisWeek.AddDate(0, 0, -7)
+
+	if _, err := s.repo.CreateWeeklyReports(ctx, lastWeek); err != nil {
+		return fmt.Errorf("failed to compile weekly reports: %w", err)
+	}
+
+	if now.Sub(thisWeek) >= summaryWindow {
+		return nil
+	}
+	return s.emails.QueueWeeklySummaries(ctx, lastWeek)
+}
+
+// GetWeeklyReport retrieves the user's report of the week containing day
+func (s *ReportService) GetWeeklyReport(ctx context.Context, userID string, day time.Time) (*models.WeeklyReport, error) {
+	report, err := s.repo.FindWeeklyReport(ctx, userID, weekStart(day))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReportNotFound
+		}
+		return nil, fmt.Errorf("failed to get weekly report: %w", err)
+	}
+	return report, nil
+}
+
+// ListWeeklyReports retrieves the user's most recent weekly reports, newest first
+func (s *ReportService) ListWeeklyReports(ctx context.Context, userID string, limit int) ([]*models.WeeklyReport, error) {
+	if limit <= 0 {
+		limit = defaultReportPage
+	}
+	limit = min(limit, maxReportPage)
+
+	reports, err := s.repo.ListWeeklyReports(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list weekly reports: %w", err)
+	}
+	return reports, nil
+}
+
+// weekStart is the Monday (UTC) of the week containing t
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
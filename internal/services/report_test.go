@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGenerateWeeklyReports_EmailsOnFirstDayOfWeek(t *testing.T) {
+	var compiled, emailed []time.Time
+	reportRepo := &repositories.MockReportRepository{
+		CreateWeeklyReportsFunc: func(ctx context.Context, weekStart time.Time) (int64, error) {
+			compiled = append(compiled, weekStart)
+			return 1, nil
+		},
+	}
+	emailRepo := &repositories.MockEmailRepository{
+		QueueWeeklySummariesFunc: func(ctx context.Context, weekStart time.Time) (int64, error) {
+			emailed = append(emailed, weekStart)
+			return 1, nil
+		},
+	}
+	service := NewReportService(reportRepo, newTestEmailService(emailRepo, &fakeMailer{}, &[]*models.EmailJob{}))
+
+	for _, now := range []time.Time{
+		time.Date(2026, 3, 9, 0, 30, 0, 0, time.UTC),  // Monday
+		time.Date(2026, 3, 9, 23, 59, 0, 0, time.UTC), // Still Monday
+		time.Date(2026, 3, 10, 0, 30, 0, 0, time.UTC), // Tuesday, too late to email
+	} {
+		service.now = func() time.Time { return now }
+		if err := service.GenerateWeeklyReports(context.Background()); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	want := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	for _, weekStart := range compiled {
+		if !weekStart.Equal(want) {
+			t.Errorf("Expected last week's reports to be compiled, got %s", weekStart)
+		}
+	}
+	if len(compiled) != 3 || len(emailed) != 2 || !emailed[0].Equal(want) {
+		t.Errorf("Expected reports on every run and emails on Monday only, got %v and %v", compiled, emailed)
+	}
+}
+
+func TestGenerateWeeklyReports_WithoutMailer(t *testing.T) {
+	emailRepo := &repositories.MockEmailRepository{
+		QueueWeeklySummariesFunc: func(ctx context.Context, weekStart time.Time) (int64, error) {
+			t.Error("Expected no summaries to be queued without a mailer")
+			return 0, nil
+		},
+	}
+	service := NewReportService(&repositories.MockReportRepository{}, NewEmailService(emailRepo, nil, nil, nil))
+	service.now = func() time.Time { return time.Date(2026, 3, 9, 0, 30, 0, 0, time.UTC) }
+
+	if err := service.GenerateWeeklyReports(context.Background()); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetWeeklyReport_AnyDayOfTheWeek(t *testing.T) {
+	var weekStarts []time.Time
+	mockRepo := &repositories.MockReportRepository{
+		FindWeeklyReportFunc: func(ctx context.Context, userID string, weekStart time.Time) (*models.WeeklyReport, error) {
+			weekStarts = append(weekStarts, weekStart)
+			if userID != "user-123" {
+				return (&repositories.MockReportRepository{}).FindWeeklyReport(ctx, userID, weekStart)
+			}
+			return &models.WeeklyReport{UserID: userID, WeekStart: weekStart.Format("2006-01-02")}, nil
+		},
+	}
+	service := NewReportService(mockRepo, nil)
+
+	report, err := service.GetWeeklyReport(context.Background(), "user-123", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if report.WeekStart != "2026-03-02" {
+		t.Errorf("Expected the week starting on Monday 2026-03-02, got %s", report.WeekStart)
+	}
+
+	if _, err := service.GetWeeklyReport(context.Background(), "user-789", time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC)); !errors.Is(err, ErrReportNotFound) {
+		t.Errorf("Expected ErrReportNotFound, got %v", err)
+	}
+}
+
+func TestListWeeklyReports_ClampsLimit(t *testing.T) {
+	var limits []int
+	mockRepo := &repositories.MockReportRepository{
+		ListWeeklyReportsFunc: func(ctx context.Context, userID string, limit int) ([]*models.WeeklyReport, error) {
+			limits = append(limits, limit)
+			return []*models.WeeklyReport{}, nil
+		},
+	}
+	service := NewReportService(mockRepo, nil)
+
+	for _, limit := range []int{0, 5, 500} {
+		if _, err := service.ListWeeklyReports(context.Background(), "user-123", limit); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+	if limits[0] != defaultReportPage || limits[1] != 5 || limits[2] != maxReportPage {
+		t.Errorf("Expected clamped limits, got %v", limits)
+	}
+}
@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrSessionNotInProgress = domainerr.New(domainerr.Conflict, "rest timers can only be changed while the session is in progress")
+	ErrNoActiveRestTimer    = domainerr.New(domainerr.Conflict, "no rest timer is running")
+)
+
+const (
+	defaultRestSeconds   = 90
+	defaultExtendSeconds = 30
+
+	// restTimerEventBuffer is how many events a slow subscriber may fall behind before
+	// events are dropped; clients resync from the timer state they receive next
+	restTimerEventBuffer = 8
+)
+
+// RestTimerService keeps a session's rest countdown in sync across the user's devices.
+// The timer is stored on the session, so any device can derive the same countdown, and
+// changes are pushed to subscribers. Subscriptions are held in memory: with several
+// instances, devices connected to another instance see changes on their next read.
+type RestTimerService struct {
+	repo repositories.RestTimerRepository
+	now  func() time.Time
+
+	mu          sync.Mutex
+	subscribers map[string]map[chan *models.RestTimerEvent]struct{}
+	closed      bool
+}
+
+// NewRestTimerService creates a new rest timer service
+func NewRestTimerService(repo repositories.RestTimerRepository) *RestTimerService {
+	return &RestTimerService{
+		repo:        repo,
+		now:         time.Now,
+		subscribers: make(map[string]map[chan *models.RestTimerEvent]struct{}),
+	}
+}
+
+// findOwned retrieves the session's timer state, checking the session belongs to the user
+func (s *RestTimerService) findOwned(ctx context.Context, sessionID string, userID string) (*models.RestTimerSession, error) {
+	session, err := s.repo.FindBySession(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if session.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	return session, nil
+}
+
+// timer builds the client view of a stored timer at the current time
+func (s *RestTimerService) timer(sessionID string, startedAt *time.Time, durationSeconds *int) *models.RestTimer {
+	now := s.now().UTC()
+	timer := &models.RestTimer{SessionID: sessionID, ServerTime: now}
+	if startedAt == nil || durationSeconds == nil {
+		return timer
+	}
+
+	endsAt := startedAt.Add(time.Duration(*durationSeconds) * time.Second)
+	timer.StartedAt = startedAt
+	timer.DurationSeconds = durationSeconds
+	timer.EndsAt = &endsAt
+	if remaining := endsAt.Sub(now); remaining > 0 {
+		timer.Active = true
+		timer.RemainingSeconds = int((remaining + time.Second - 1) / time.Second)
+	}
+	return timer
+}
+
+// GetRestTimer returns the session's current rest timer
+func (s *RestTimerService) GetRestTimer(ctx context.Context, sessionID string, userID string) (*models.RestTimer, error) {
+	session, err := s.findOwned(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.timer(sessionID, session.StartedAt, session.DurationSeconds), nil
+}
+
+// UpdateRestTimer starts, skips or extends the session's rest timer and notifies subscribers.
+// Starting replaces a running timer; extending requires one to be running.
+func (s *RestTimerService) UpdateRestTimer(ctx context.Context, sessionID string, req *models.RestTimerActionRequest, userID string) (*models.RestTimer, error) {
+	session, err := s.findOwned(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != "in_progress" {
+		return nil, ErrSessionNotInProgress
+	}
+
+	var startedAt *time.Time
+	var duration *int
+	switch req.Action {
+	case models.RestTimerStart:
+		now := s.now().UTC()
+		seconds := defaultRestSeconds
+		if req.DurationSeconds != nil {
+			seconds = *req.DurationSeconds
+		}
+		startedAt, duration = &now, &seconds
+	case models.RestTimerExtend:
+		if !s.timer(sessionID, session.StartedAt, session.DurationSeconds).Active {
+			return nil, ErrNoActiveRestTimer
+		}
+		seconds := *session.DurationSeconds + defaultExtendSeconds
+		if req.DurationSeconds != nil {
+			seconds = *session.DurationSeconds + *req.DurationSeconds
+		}
+		startedAt, duration = session.StartedAt, &seconds
+	case models.RestTimerSkip:
+		// Clearing the timer ends the rest for every device
+	}
+
+	if err := s.repo.Set(ctx, sessionID, startedAt, duration); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to update rest timer: %w", err)
+	}
+
+	timer := s.timer(sessionID, startedAt, duration)
+	s.publish(sessionID, &models.RestTimerEvent{Action: req.Action, Timer: timer})
+
+	return timer, nil
+}
+
+// SubscribeRestTimer returns the session's current timer and a channel of subsequent changes.
+// The caller must call the returned cancel function when it stops listening. The channel is
+// closed on Close.
+func (s *RestTimerService) SubscribeRestTimer(ctx context.Context, sessionID string, userID string) (*models.RestTimer, <-chan *models.RestTimerEvent, func(), error) {
+	timer, err := s.GetRestTimer(ctx, sessionID, userID)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	events := make(chan *models.RestTimerEvent, restTimerEventBuffer)
+
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		close(events)
+		return timer, events, func() {}, nil
+	}
+	if s.subscribers[sessionID] == nil {
+		s.subscribers[sessionID] = make(map[chan *models.RestTimerEvent]struct{})
+	}
+	s.subscribers[sessionID][events] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.remove(sessionID, events)
+	}
+
+	return timer, events, cancel, nil
+}
+
+// Close ends every subscription, so timer streams return on shutdown instead of holding
+// the server open until their clients go away
+func (s *RestTimerService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.closed = true
+	for sessionID, subscribers := range s.subscribers {
+		for events := range subscribers {
+			s.remove(sessionID, events)
+		}
+	}
+}
+
+// remove closes a subscriber's channel once; callers hold s.mu
+func (s *RestTimerService) remove(sessionID string, events chan *models.RestTimerEvent) {
+	if _, ok := s.subscribers[sessionID][events]; !ok {
+		return
+	}
+	close(events)
+	delete(s.subscribers[sessionID], events)
+	if len(s.subscribers[sessionID]) == 0 {
+		delete(s.subscribers, sessionID)
+	}
+}
+
+// publish sends an event to the session's subscribers without blocking on slow ones
+func (s *RestTimerService) publish(sessionID string, event *models.RestTimerEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for events := range s.subscribers[sessionID] {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
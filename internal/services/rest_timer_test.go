@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// restTimerRepo stores the timer in memory so consecutive calls see each other's changes
+func restTimerRepo(session *models.RestTimerSession) *repositories.MockRestTimerRepository {
+	return &repositories.MockRestTimerRepository{
+		FindBySessionFunc: func(ctx context.Context, sessionID string) (*models.RestTimerSession, error) {
+			copied := *session
+			return &copied, nil
+		},
+		SetFunc: func(ctx context.Context, sessionID string, startedAt *time.Time, durationSeconds *int) error {
+			session.StartedAt = startedAt
+			session.DurationSeconds = durationSeconds
+			return nil
+		},
+	}
+}
+
+func TestUpdateRestTimer_StartAndExtend(t *testing.T) {
+	session := &models.RestTimerSession{SessionID: "session-1", UserID: "user-123", Status: "in_progress"}
+	service := NewRestTimerService(restTimerRepo(session))
+	now := time.Date(2026, 5, 4, 18, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+
+	timer, err := service.UpdateRestTimer(context.Background(), "session-1", &models.RestTimerActionRequest{Action: models.RestTimerStart}, "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !timer.Active || timer.RemainingSeconds != 90 || !timer.EndsAt.Equal(now.Add(90*time.Second)) {
+		t.Errorf("Expected a 90s timer, got %+v", timer)
+	}
+
+	now = now.Add(60 * time.Second)
+	timer, err = service.UpdateRestTimer(context.Background(), "session-1", &models.RestTimerActionRequest{Action: models.RestTimerExtend}, "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timer.RemainingSeconds != 60 || *timer.DurationSeconds != 120 {
+		t.Errorf("Expected 30s added to a 120s timer with 60s left, got %+v", timer)
+	}
+
+	now = now.Add(2 * time.Minute)
+	timer, err = service.GetRestTimer(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timer.Active || timer.RemainingSeconds != 0 {
+		t.Errorf("Expected the timer to have run out, got %+v", timer)
+	}
+}
+
+func TestUpdateRestTimer_Errors(t *testing.T) {
+	expired := time.Date(2026, 5, 4, 17, 0, 0, 0, time.UTC)
+	duration := 90
+
+	tests := []struct {
+		name    string
+		session *models.RestTimerSession
+		action  string
+		wantErr error
+	}{
+		{
+			name:    "other user's session",
+			session: &models.RestTimerSession{UserID: "user-456", Status: "in_progress"},
+			action:  models.RestTimerStart,
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "completed session",
+			session: &models.RestTimerSession{UserID: "user-123", Status: "completed"},
+			action:  models.RestTimerStart,
+			wantErr: ErrSessionNotInProgress,
+		},
+		{
+			name:    "extend without a timer",
+			session: &models.RestTimerSession{UserID: "user-123", Status: "in_progress"},
+			action:  models.RestTimerExtend,
+			wantErr: ErrNoActiveRestTimer,
+		},
+		{
+			name:    "extend an expired timer",
+			session: &models.RestTimerSession{UserID: "user-123", Status: "in_progress", StartedAt: &expired, DurationSeconds: &duration},
+			action:  models.RestTimerExtend,
+			wantErr: ErrNoActiveRestTimer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := NewRestTimerService(restTimerRepo(tt.session))
+			service.now = func() time.Time { return time.Date(2026, 5, 4, 18, 0, 0, 0, time.UTC) }
+
+			_, err := service.UpdateRestTimer(context.Background(), "session-1", &models.RestTimerActionRequest{Action: tt.action}, "user-123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestSubscribeRestTimer(t *testing.T) {
+	session := &models.RestTimerSession{SessionID: "session-1", UserID: "user-123", Status: "in_progress"}
+	service := NewRestTimerService(restTimerRepo(session))
+
+	current, events, cancel, err := service.SubscribeRestTimer(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if current.Active {
+		t.Errorf("Expected no running timer on subscribe, got %+v", current)
+	}
+
+	seconds := 120
+	req := &models.RestTimerActionRequest{Action: models.RestTimerStart, DurationSeconds: &seconds}
+	if _, err := service.UpdateRestTimer(context.Background(), "session-1", req, "user-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case event := <-events:
+		if event.Action != models.RestTimerStart || !event.Timer.Active || *event.Timer.DurationSeconds != 120 {
+			t.Errorf("Expected a start event for a 120s timer, got %+v", event)
+		}
+	default:
+		t.Fatal("Expected an event after starting the timer")
+	}
+
+	cancel()
+	if _, err := service.UpdateRestTimer(context.Background(), "session-1", &models.RestTimerActionRequest{Action: models.RestTimerSkip}, "user-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if event, ok := <-events; ok {
+		t.Errorf("Expected no events after cancel, got %+v", event)
+	}
+	if session.StartedAt != nil {
+		t.Error("Expected skip to clear the stored timer")
+	}
+}
+
+func TestRestTimerService_CloseEndsSubscriptions(t *testing.T) {
+	session := &models.RestTimerSession{SessionID: "session-1", UserID: "user-123", Status: "in_progress"}
+	service := NewRestTimerService(restTimerRepo(session))
+
+	_, events, cancel, err := service.SubscribeRestTimer(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer cancel()
+
+	service.Close()
+	if _, ok := <-events; ok {
+		t.Error("Expected close to close the subscriber's channel")
+	}
+
+	_, late, cancelLate, err := service.SubscribeRestTimer(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer cancelLate()
+	if _, ok := <-late; ok {
+		t.Error("Expected subscriptions after close to be closed")
+	}
+}
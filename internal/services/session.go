@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var ErrInvalidSessionCursor = domainerr.New(domainerr.Validation, "invalid cursor, pass next_cursor from the previous page")
+
+const (
+	defaultSessionPageSize = 20
+	maxSessionPageSize     = 100
+)
+
+// SessionService handles reading workout sessions
+type SessionService struct {
+	repo   repositories.SessionRepository
+	policy AccessPolicy
+}
+
+// NewSessionService creates a new session service
+func NewSessionService(repo repositories.SessionRepository, policy AccessPolicy) *SessionService {
+	return &SessionService{repo: repo, policy: policy}
+}
+
+// GetSession retrieves a session with its sets and laps; the session's owner and their
+// coaches may view it
+func (s *SessionService) GetSession(ctx context.Context, id string, actorID string) (*models.Session, error) {
+	session, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	ok, err := s.policy.CanRead(ctx, actorID, session.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	setLapPaces(session.Laps)
+
+	return session, nil
+}
+
+// sessionOwner returns the user who owns a session, for services guarding what hangs off it
+func sessionOwner(ctx context.Context, sessions repositories.SessionRepository, sessionID string) (string, error) {
+	ownerID, err := sessions.FindOwner(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrSessionNotFound
+		}
+		return "", fmt.Errorf("failed to get session: %w", err)
+	}
+	return ownerID, nil
+}
+
+// ListSessions returns a page of the user's session history, newest first. cursor is
+// empty for the first page, then the previous page's next_cursor. limit outside 1..100
+// falls back to the default of 20.
+func (s *SessionService) ListSessions(ctx context.Context, userID string, limit int, cursor string) (*models.SessionPage, error) {
+	if limit <= 0 || limit > maxSessionPageSize {
+		limit = defaultSessionPageSize
+	}
+	filter := models.SessionFilter{Limit: limit + 1} // One more tells whether a next page exists
+	if cursor != "" {
+		after, err := decodeSessionCursor(cursor)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	sessions, err := s.repo.ListByUser(ctx, userID, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sessions: %w", err)
+	}
+
+	page := &models.SessionPage{Sessions: sessions}
+	if len(sessions) > limit {
+		page.Sessions = sessions[:limit]
+		last := page.Sessions[limit-1]
+		page.NextCursor = encodeSessionCursor(&models.SessionCursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	return page, nil
+}
+
+// encodeSessionCursor makes a cursor opaque to clients, so its format can change
+func encodeSessionCursor(cursor *models.SessionCursor) string {
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSessionCursor(cursor string) (*models.SessionCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidSessionCursor
+	}
+	rawTime, id, ok := strings.Cut(string(raw), ",")
+	if !ok {
+		return nil, ErrInvalidSessionCursor
+	}
+	createdAt, err := time.Parse(time.RFC3339Nano, rawTime)
+	if err != nil {
+		return nil, ErrInvalidSessionCursor
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, ErrInvalidSessionCursor
+	}
+	return &models.SessionCursor{CreatedAt: createdAt, ID: id}, nil
+}
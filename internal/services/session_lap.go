@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// SessionLapService handles the laps of cardio sessions
+type SessionLapService struct {
+	repo     repositories.SessionLapRepository
+	sessions repositories.SessionRepository
+	policy   AccessPolicy
+}
+
+// NewSessionLapService creates a new session lap service
+func NewSessionLapService(repo repositories.SessionLapRepository, sessions repositories.SessionRepository, policy AccessPolicy) *SessionLapService {
+	return &SessionLapService{repo: repo, sessions: sessions, policy: policy}
+}
+
+// ListLaps retrieves a session's laps with their pace; the session's owner and their
+// coaches may view them
+func (s *SessionLapService) ListLaps(ctx context.Context, sessionID string, actorID string) ([]*models.SessionLap, error) {
+	ownerID, err := sessionOwner(ctx, s.sessions, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.policy.CanRead(ctx, actorID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	laps, err := s.repo.FindBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list laps: %w", err)
+	}
+
+	setLapPaces(laps)
+
+	return laps, nil
+}
+
+// setLapPaces derives the pace of laps with a distance
+func setLapPaces(laps []*models.SessionLap) {
+	for _, lap := range laps {
+		if lap.DistanceMeters != nil && *lap.DistanceMeters > 0 && lap.DurationSeconds > 0 {
+			pace := math.Round(float64(lap.DurationSeconds)/(*lap.DistanceMeters/1000)*10) / 10
+			lap.PaceSecPerKm = &pace
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestListLaps_ComputesPace(t *testing.T) {
+	km, short := 1000.0, 0.0
+	mockRepo := &repositories.MockSessionLapRepository{
+		FindBySessionFunc: func(ctx context.Context, sessionID string) ([]*models.SessionLap, error) {
+			return []*models.SessionLap{
+				{LapIndex: 1, DurationSeconds: 275, DistanceMeters: &km},
+				{LapIndex: 2, DurationSeconds: 30, DistanceMeters: &short},
+			}, nil
+		},
+	}
+
+	service := NewSessionLapService(mockRepo, ownedSession("user-123"), NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+
+	laps, err := service.ListLaps(context.Background(), "session-1", "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if laps[0].PaceSecPerKm == nil || *laps[0].PaceSecPerKm != 275 {
+		t.Errorf("Expected 275 s/km, got %v", laps[0].PaceSecPerKm)
+	}
+	if laps[1].PaceSecPerKm != nil {
+		t.Errorf("Expected no pace without distance, got %v", *laps[1].PaceSecPerKm)
+	}
+
+	if _, err := service.ListLaps(context.Background(), "session-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+const (
+	// liveSessionEventBuffer is how many events a slow watcher may fall behind before it
+	// is dropped
+	liveSessionEventBuffer = 32
+
+	// liveSessionRetryDelay is how long to wait before listening again after the
+	// notification connection fails
+	liveSessionRetryDelay = 5 * time.Second
+)
+
+// SessionLiveService lets a session's owner and their coaches watch it as it is performed.
+// Changes are announced by database triggers, so watchers connected to any instance see
+// sets logged through any client.
+type SessionLiveService struct {
+	repo   repositories.SessionLiveRepository
+	policy AccessPolicy
+
+	mu       sync.Mutex
+	watchers map[string]map[chan *models.LiveSessionEvent]struct{}
+}
+
+// NewSessionLiveService creates a new live session service
+func NewSessionLiveService(repo repositories.SessionLiveRepository, policy AccessPolicy) *SessionLiveService {
+	return &SessionLiveService{
+		repo:     repo,
+		policy:   policy,
+		watchers: make(map[string]map[chan *models.LiveSessionEvent]struct{}),
+	}
+}
+
+// Start listens for session activity in the background until ctx is cancelled, listening
+// again after connection failures. Watchers are dropped whenever events may have been
+// missed and on shutdown.
+func (s *SessionLiveService) Start(ctx context.Context) {
+	go func() {
+		defer s.closeAll()
+		for {
+			err := s.repo.Listen(ctx, s.publish)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("Session activity listener failed: %v", err)
+			s.closeAll()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(liveSessionRetryDelay):
+			}
+		}
+	}()
+}
+
+// Watch returns a snapshot of the session and a channel of subsequent changes; the
+// session's owner and their coaches may watch it. The channel is closed when events may
+// have been missed or the service stops; the watcher then needs a fresh snapshot. The caller must call the returned cancel function
+// when it stops watching.
+func (s *SessionLiveService) Watch(ctx context.Context, sessionID string, actorID string) (*models.LiveSessionEvent, <-chan *models.LiveSessionEvent, func(), error) {
+	// Watch before reading the snapshot so no change falls between the two; clients
+	// apply set events by ID, so one already in the snapshot is harmless
+	events, cancel := s.subscribe(sessionID)
+
+	snapshot, err := s.repo.FindSnapshot(ctx, sessionID)
+	if err != nil {
+		cancel()
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, nil, ErrSessionNotFound
+		}
+		return nil, nil, nil, fmt.Errorf("failed to get session: %w", err)
+	}
+
+	ok, err := s.policy.CanRead(ctx, actorID, snapshot.UserID)
+	if err != nil {
+		cancel()
+		return nil, nil, nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		cancel()
+		return nil, nil, nil, ErrUnauthorized
+	}
+
+	return snapshot.Event, events, cancel, nil
+}
+
+func (s *SessionLiveService) subscribe(sessionID string) (chan *models.LiveSessionEvent, func()) {
+	events := make(chan *models.LiveSessionEvent, liveSessionEventBuffer)
+
+	s.mu.Lock()
+	if s.watchers[sessionID] == nil {
+		s.watchers[sessionID] = make(map[chan *models.LiveSessionEvent]struct{})
+	}
+	s.watchers[sessionID][events] = struct{}{}
+	s.mu.Unlock()
+
+	cancel := func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.remove(sessionID, events)
+	}
+	return events, cancel
+}
+
+// remove closes a watcher's channel once; callers hold s.mu
+func (s *SessionLiveService) remove(sessionID string, events chan *models.LiveSessionEvent) {
+	if _, ok := s.watchers[sessionID][events]; !ok {
+		return
+	}
+	close(events)
+	delete(s.watchers[sessionID], events)
+	if len(s.watchers[sessionID]) == 0 {
+		delete(s.watchers, sessionID)
+	}
+}
+
+// publish sends an event to the session's watchers. A watcher whose buffer is full is
+// dropped rather than silently missing a set.
+func (s *SessionLiveService) publish(event *models.LiveSessionEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for events := range s.watchers[event.SessionID] {
+		select {
+		case events <- event:
+		default:
+			s.remove(event.SessionID, events)
+		}
+	}
+}
+
+// closeAll drops every watcher
+func (s *SessionLiveService) closeAll() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sessionID, watchers := range s.watchers {
+		for events := range watchers {
+			s.remove(sessionID, events)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newLiveSessionRepo(status string) *repositories.MockSessionLiveRepository {
+	return &repositories.MockSessionLiveRepository{
+		FindSnapshotFunc: func(ctx context.Context, sessionID string) (*models.LiveSessionSnapshot, error) {
+			return &models.LiveSessionSnapshot{
+				UserID: "user-123",
+				Event:  &models.LiveSessionEvent{Type: models.LiveEventSnapshot, SessionID: sessionID, Status: &status},
+			}, nil
+		},
+	}
+}
+
+func TestWatch_ForwardsSessionActivity(t *testing.T) {
+	service := NewSessionLiveService(newLiveSessionRepo("in_progress"), NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+
+	snapshot, events, cancel, err := service.Watch(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer cancel()
+	if snapshot.Type != models.LiveEventSnapshot || *snapshot.Status != "in_progress" {
+		t.Errorf("Unexpected snapshot: %+v", snapshot)
+	}
+
+	service.publish(&models.LiveSessionEvent{Type: models.LiveEventSetLogged, SessionID: "session-2"})
+	service.publish(&models.LiveSessionEvent{Type: models.LiveEventSetLogged, SessionID: "session-1", Set: &models.LiveSet{ID: "log-1"}})
+
+	select {
+	case event := <-events:
+		if event.SessionID != "session-1" || event.Set.ID != "log-1" {
+			t.Errorf("Expected the watched session's set, got %+v", event)
+		}
+	default:
+		t.Fatal("Expected an event for the watched session")
+	}
+	select {
+	case event := <-events:
+		t.Errorf("Expected no event for other sessions, got %+v", event)
+	default:
+	}
+}
+
+func TestWatch_DeniesOthers(t *testing.T) {
+	service := NewSessionLiveService(newLiveSessionRepo("in_progress"), NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+
+	if _, _, _, err := service.Watch(context.Background(), "session-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if len(service.watchers) != 0 {
+		t.Errorf("Expected denied watcher to be removed, got %d sessions", len(service.watchers))
+	}
+
+	missing := NewSessionLiveService(&repositories.MockSessionLiveRepository{}, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+	if _, _, _, err := missing.Watch(context.Background(), "session-1", "user-123"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestWatch_DropsSlowWatchers(t *testing.T) {
+	service := NewSessionLiveService(newLiveSessionRepo("in_progress"), NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+
+	_, events, cancel, err := service.Watch(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := 0; i <= liveSessionEventBuffer; i++ {
+		service.publish(&models.LiveSessionEvent{Type: models.LiveEventSetUpdated, SessionID: "session-1"})
+	}
+
+	received := 0
+	for range events {
+		received++
+	}
+	if received != liveSessionEventBuffer {
+		t.Errorf("Expected %d buffered events before the channel closed, got %d", liveSessionEventBuffer, received)
+	}
+
+	cancel() // Safe after the watcher was dropped
+}
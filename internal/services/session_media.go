@@ -0,0 +1,207 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrSessionNotFound   = domainerr.New(domainerr.NotFound, "workout session not found")
+	ErrMediaNotFound     = domainerr.New(domainerr.NotFound, "media item not found")
+	ErrTooManyMedia      = domainerr.New(domainerr.Conflict, "session already has the maximum number of media items")
+	ErrInvalidMedia      = domainerr.New(domainerr.Validation, "duration_seconds is only allowed for videos")
+	ErrInvalidMediaOrder = domainerr.New(domainerr.Validation, "media_ids must list every media item of the session exactly once")
+)
+
+// maxSessionMedia caps the media items attached to one session
+const maxSessionMedia = 20
+
+// SessionMediaService manages photos and videos attached to workout sessions
+// and the highlights reel built from them.
+type SessionMediaService struct {
+	repo     repositories.SessionMediaRepository
+	sessions repositories.SessionRepository
+	audit    *AuditLogService
+}
+
+// NewSessionMediaService creates a new session media service; writes are audited unless
+// audit is nil
+func NewSessionMediaService(repo repositories.SessionMediaRepository, sessions repositories.SessionRepository, audit *AuditLogService) *SessionMediaService {
+	return &SessionMediaService{repo: repo, sessions: sessions, audit: audit}
+}
+
+// checkOwner verifies the session exists and belongs to the user
+func (s *SessionMediaService) checkOwner(ctx context.Context, sessionID string, userID string) error {
+	ownerID, err := sessionOwner(ctx, s.sessions, sessionID)
+	if err != nil {
+		return err
+	}
+	if ownerID != userID {
+		return ErrUnauthorized
+	}
+	return nil
+}
+
+// ListMedia retrieves all media of the user's session in order
+func (s *SessionMediaService) ListMedia(ctx context.Context, sessionID string, userID string) ([]*models.SessionMedia, error) {
+	if err := s.checkOwner(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	media, err := s.repo.FindBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+
+	return media, nil
+}
+
+// AddMedia attaches a media item to the end of the user's session
+func (s *SessionMediaService) AddMedia(ctx context.Context, sessionID string, req *models.CreateSessionMediaRequest, userID string) (*models.SessionMedia, error) {
+	if req.DurationSeconds != nil && req.MediaType != models.MediaTypeVideo {
+		return nil, ErrInvalidMedia
+	}
+	if err := s.checkOwner(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.repo.FindBySession(ctx, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list media: %w", err)
+	}
+	if len(existing) >= maxSessionMedia {
+		return nil, ErrTooManyMedia
+	}
+
+	media := &models.SessionMedia{
+		SessionID:       sessionID,
+		UserID:          userID,
+		MediaType:       req.MediaType,
+		URL:             req.URL,
+		ThumbnailURL:    req.ThumbnailURL,
+		Caption:         req.Caption,
+		DurationSeconds: req.DurationSeconds,
+		Highlight:       req.Highlight == nil || *req.Highlight,
+	}
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceSessionMedia, "", nil, func() (*models.SessionMedia, error) {
+		if err := s.repo.Create(ctx, media); err != nil {
+			return nil, fmt.Errorf("failed to add media: %w", err)
+		}
+		return media, nil
+	})
+}
+
+// UpdateMedia changes the caption or highlight flag of a media item
+func (s *SessionMediaService) UpdateMedia(ctx context.Context, sessionID string, mediaID string, req *models.UpdateSessionMediaRequest, userID string) (*models.SessionMedia, error) {
+	item, err := s.findMedia(ctx, sessionID, mediaID, userID)
+	if err != nil {
+		return nil, err
+	}
+	before := *item
+
+	if req.Caption != nil {
+		item.Caption = req.Caption
+	}
+	if req.Highlight != nil {
+		item.Highlight = *req.Highlight
+	}
+
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceSessionMedia, mediaID, &before, func() (*models.SessionMedia, error) {
+		if err := s.repo.Update(ctx, item); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrMediaNotFound
+			}
+			return nil, fmt.Errorf("failed to update media: %w", err)
+		}
+		return item, nil
+	})
+}
+
+// DeleteMedia removes a media item from the user's session
+func (s *SessionMediaService) DeleteMedia(ctx context.Context, sessionID string, mediaID string, userID string) error {
+	item, err := s.findMedia(ctx, sessionID, mediaID, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceSessionMedia, mediaID, item, func() (*models.SessionMedia, error) {
+		if err := s.repo.Delete(ctx, sessionID, mediaID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrMediaNotFound
+			}
+			return nil, fmt.Errorf("failed to delete media: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// findMedia retrieves a media item of the user's session
+func (s *SessionMediaService) findMedia(ctx context.Context, sessionID string, mediaID string, userID string) (*models.SessionMedia, error) {
+	media, err := s.ListMedia(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, m := range media {
+		if m.ID == mediaID {
+			return m, nil
+		}
+	}
+	return nil, ErrMediaNotFound
+}
+
+// ReorderMedia sets the order of the session's media; every item must be listed once
+func (s *SessionMediaService) ReorderMedia(ctx context.Context, sessionID string, req *models.ReorderSessionMediaRequest, userID string) ([]*models.SessionMedia, error) {
+	media, err := s.ListMedia(ctx, sessionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(req.MediaIDs) != len(media) {
+		return nil, ErrInvalidMediaOrder
+	}
+	byID := make(map[string]*models.SessionMedia, len(media))
+	for _, m := range media {
+		byID[m.ID] = m
+	}
+	ordered := make([]*models.SessionMedia, 0, len(media))
+	for i, id := range req.MediaIDs {
+		m, ok := byID[id]
+		if !ok {
+			return nil, ErrInvalidMediaOrder
+		}
+		delete(byID, id)
+		m.Position = i
+		ordered = append(ordered, m)
+	}
+
+	if err := s.repo.Reorder(ctx, sessionID, req.MediaIDs); err != nil {
+		return nil, fmt.Errorf("failed to reorder media: %w", err)
+	}
+
+	return ordered, nil
+}
+
+// GetHighlights returns the session's highlights reel: its media marked as highlights, in order
+func (s *SessionMediaService) GetHighlights(ctx context.Context, sessionID string, userID string) (*models.SessionHighlights, error) {
+	if err := s.checkOwner(ctx, sessionID, userID); err != nil {
+		return nil, err
+	}
+
+	highlights, err := s.repo.FindHighlights(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get highlights: %w", err)
+	}
+
+	return highlights, nil
+}
@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func ownedSession(userID string) *repositories.MockSessionRepository {
+	return &repositories.MockSessionRepository{
+		FindOwnerFunc: func(ctx context.Context, id string) (string, error) {
+			return userID, nil
+		},
+	}
+}
+
+func TestAddMedia(t *testing.T) {
+	var created *models.SessionMedia
+	mockRepo := &repositories.MockSessionMediaRepository{
+		CreateFunc: func(ctx context.Context, media *models.SessionMedia) error {
+			created = media
+			media.ID = "media-1"
+			return nil
+		},
+	}
+
+	service := NewSessionMediaService(mockRepo, ownedSession("user-123"), nil)
+
+	caption := "New PR!"
+	req := &models.CreateSessionMediaRequest{
+		MediaType: models.MediaTypePhoto,
+		URL:       "https://cdn.example.com/pr.jpg",
+		Caption:   &caption,
+	}
+	media, err := service.AddMedia(context.Background(), "session-1", req, "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if media.ID != "media-1" || created.SessionID != "session-1" || created.UserID != "user-123" {
+		t.Errorf("Expected media attached to session-1 for user-123, got %+v", created)
+	}
+	if !created.Highlight {
+		t.Error("Expected media to be a highlight by default")
+	}
+}
+
+func TestAddMedia_Errors(t *testing.T) {
+	duration := 30
+	tooMany := make([]*models.SessionMedia, maxSessionMedia)
+
+	tests := []struct {
+		name    string
+		owner   string
+		media   []*models.SessionMedia
+		req     *models.CreateSessionMediaRequest
+		wantErr error
+	}{
+		{
+			name:    "other user's session",
+			owner:   "user-456",
+			req:     &models.CreateSessionMediaRequest{MediaType: models.MediaTypePhoto, URL: "https://cdn.example.com/a.jpg"},
+			wantErr: ErrUnauthorized,
+		},
+		{
+			name:    "duration on a photo",
+			owner:   "user-123",
+			req:     &models.CreateSessionMediaRequest{MediaType: models.MediaTypePhoto, URL: "https://cdn.example.com/a.jpg", DurationSeconds: &duration},
+			wantErr: ErrInvalidMedia,
+		},
+		{
+			name:    "session full",
+			owner:   "user-123",
+			media:   tooMany,
+			req:     &models.CreateSessionMediaRequest{MediaType: models.MediaTypeVideo, URL: "https://cdn.example.com/a.mp4", DurationSeconds: &duration},
+			wantErr: ErrTooManyMedia,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := &repositories.MockSessionMediaRepository{
+				FindBySessionFunc: func(ctx context.Context, sessionID string) ([]*models.SessionMedia, error) {
+					return tt.media, nil
+				},
+			}
+
+			service := NewSessionMediaService(mockRepo, ownedSession(tt.owner), nil)
+
+			_, err := service.AddMedia(context.Background(), "session-1", tt.req, "user-123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestAddMedia_SessionNotFound(t *testing.T) {
+	service := NewSessionMediaService(&repositories.MockSessionMediaRepository{}, &repositories.MockSessionRepository{}, nil)
+
+	req := &models.CreateSessionMediaRequest{MediaType: models.MediaTypePhoto, URL: "https://cdn.example.com/a.jpg"}
+	_, err := service.AddMedia(context.Background(), "missing", req, "user-123")
+
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestReorderMedia(t *testing.T) {
+	existing := func() []*models.SessionMedia {
+		return []*models.SessionMedia{
+			{ID: "a", Position: 0},
+			{ID: "b", Position: 1},
+			{ID: "c", Position: 2},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		ids     []string
+		wantErr error
+	}{
+		{"valid order", []string{"c", "a", "b"}, nil},
+		{"missing item", []string{"c", "a"}, ErrInvalidMediaOrder},
+		{"duplicate item", []string{"c", "a", "a"}, ErrInvalidMediaOrder},
+		{"unknown item", []string{"c", "a", "x"}, ErrInvalidMediaOrder},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved []string
+			mockRepo := &repositories.MockSessionMediaRepository{
+				FindBySessionFunc: func(ctx context.Context, sessionID string) ([]*models.SessionMedia, error) {
+					return existing(), nil
+				},
+				ReorderFunc: func(ctx context.Context, sessionID string, mediaIDs []string) error {
+					saved = mediaIDs
+					return nil
+				},
+			}
+
+			service := NewSessionMediaService(mockRepo, ownedSession("user-123"), nil)
+
+			media, err := service.ReorderMedia(context.Background(), "session-1", &models.ReorderSessionMediaRequest{MediaIDs: tt.ids}, "user-123")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if saved != nil {
+					t.Error("Expected invalid order not to be saved")
+				}
+				return
+			}
+			if media[0].ID != "c" || media[0].Position != 0 || media[2].ID != "b" || media[2].Position != 2 {
+				t.Errorf("Expected items in new order with updated positions, got %+v", media)
+			}
+		})
+	}
+}
+
+func TestUpdateMedia(t *testing.T) {
+	caption := "Old caption"
+	mockRepo := &repositories.MockSessionMediaRepository{
+		FindBySessionFunc: func(ctx context.Context, sessionID string) ([]*models.SessionMedia, error) {
+			return []*models.SessionMedia{{ID: "a", Caption: &caption, Highlight: true}}, nil
+		},
+	}
+
+	service := NewSessionMediaService(mockRepo, ownedSession("user-123"), nil)
+
+	highlight := false
+	media, err := service.UpdateMedia(context.Background(), "session-1", "a", &models.UpdateSessionMediaRequest{Highlight: &highlight}, "user-123")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if media.Highlight || media.Caption == nil || *media.Caption != "Old caption" {
+		t.Errorf("Expected highlight cleared and caption kept, got %+v", media)
+	}
+
+	_, err = service.UpdateMedia(context.Background(), "session-1", "missing", &models.UpdateSessionMediaRequest{}, "user-123")
+	if !errors.Is(err, ErrMediaNotFound) {
+		t.Errorf("Expected ErrMediaNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGetSession_AccessAndLapPace(t *testing.T) {
+	km := 1000.0
+	mockRepo := &repositories.MockSessionRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Session, error) {
+			if id != "session-1" {
+				return (&repositories.MockSessionRepository{}).FindByID(ctx, id)
+			}
+			return &models.Session{
+				SessionSummary: models.SessionSummary{ID: id, UserID: "user-123"},
+				Sets:           []*models.SessionSet{{ID: "log-1", Exercise: &models.Exercise{ID: "ex-1"}}},
+				Laps:           []*models.SessionLap{{LapIndex: 1, DurationSeconds: 300, DistanceMeters: &km}},
+			}, nil
+		},
+	}
+	service := NewSessionService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+
+	session, err := service.GetSession(context.Background(), "session-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(session.Sets) != 1 || session.Laps[0].PaceSecPerKm == nil || *session.Laps[0].PaceSecPerKm != 300 {
+		t.Errorf("Expected the sets and a 300 s/km lap, got %+v", session)
+	}
+
+	if _, err := service.GetSession(context.Background(), "session-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := service.GetSession(context.Background(), "session-2", "user-123"); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+}
+
+func TestListSessions_KeysetPages(t *testing.T) {
+	base := time.Date(2026, 6, 1, 12, 0, 0, 123456000, time.UTC)
+	history := make([]*models.SessionSummary, 5)
+	for i := range history {
+		history[i] = &models.SessionSummary{ID: fmt.Sprintf("00000000-0000-0000-0000-00000000000%d", 5-i), CreatedAt: base.Add(-time.Duration(i) * time.Hour)}
+	}
+	var filters []models.SessionFilter
+	mockRepo := &repositories.MockSessionRepository{
+		ListByUserFunc: func(ctx context.Context, userID string, filter models.SessionFilter) ([]*models.SessionSummary, error) {
+			filters = append(filters, filter)
+			start := 0
+			if filter.After != nil {
+				for start < len(history) && !history[start].CreatedAt.Before(filter.After.CreatedAt) {
+					start++
+				}
+			}
+			return history[start:min(start+filter.Limit, len(history))], nil
+		},
+	}
+	service := NewSessionService(mockRepo, NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}))
+
+	first, err := service.ListSessions(context.Background(), "user-123", 3, "")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(first.Sessions) != 3 || first.NextCursor == "" {
+		t.Fatalf("Expected 3 sessions and a next cursor, got %+v", first)
+	}
+	if filters[0].Limit != 4 || filters[0].After != nil {
+		t.Errorf("Expected the first page to read one extra session, got %+v", filters[0])
+	}
+
+	second, err := service.ListSessions(context.Background(), "user-123", 3, first.NextCursor)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	after := filters[1].After
+	if after == nil || !after.CreatedAt.Equal(history[2].CreatedAt) || after.ID != history[2].ID {
+		t.Errorf("Expected to continue after the third session, got %+v", after)
+	}
+	if len(second.Sessions) != 2 || second.Sessions[0].ID != history[3].ID || second.NextCursor != "" {
+		t.Errorf("Expected the last 2 sessions without a next cursor, got %+v", second)
+	}
+
+	for _, cursor := range []string{"not base64!", "bm8gY29tbWE", "MjAyNi0wNi0wMSxub3QtYS11dWlk"} {
+		if _, err := service.ListSessions(context.Background(), "user-123", 3, cursor); !errors.Is(err, ErrInvalidSessionCursor) {
+			t.Errorf("cursor %q: expected ErrInvalidSessionCursor, got %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,303 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrSessionTypeNotFound    = domainerr.New(domainerr.NotFound, "session type not found")
+	ErrSessionTypeExists      = domainerr.New(domainerr.Conflict, "session type already exists")
+	ErrInvalidSessionType     = domainerr.New(domainerr.Validation, "invalid session type")
+	ErrInvalidSessionPayload  = domainerr.New(domainerr.Validation, "invalid session payload")
+	ErrInvalidSessionTypeSpan = domainerr.New(domainerr.Validation, "from must be before to")
+)
+
+// sessionTypeName matches registry names; the table enforces the same pattern
+var sessionTypeName = regexp.MustCompile(`^[a-z][a-z0-9_]{1,31}$`)
+
+// SessionTypeService manages the session type registry and validates typed session payloads
+type SessionTypeService struct {
+	repo     repositories.SessionTypeRepository
+	sessions repositories.SessionRepository
+	policy   AccessPolicy
+	cache    *cache.Cache
+	audit    *AuditLogService
+}
+
+// NewSessionTypeService creates a new session type service; a nil cache reads every time.
+// Changes to sessions' types are audited unless audit is nil.
+func NewSessionTypeService(repo repositories.SessionTypeRepository, sessions repositories.SessionRepository, policy AccessPolicy, c *cache.Cache, audit *AuditLogService) *SessionTypeService {
+	return &SessionTypeService{repo: repo, sessions: sessions, policy: policy, cache: c, audit: audit}
+}
+
+// List retrieves the registered session types
+func (s *SessionTypeService) List(ctx context.Context) ([]*models.SessionType, error) {
+	types, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session types: %w", err)
+	}
+	return types, nil
+}
+
+// Register adds a session type to the registry. Existing types are never changed, since
+// sessions already carry payloads validated against their schema.
+func (s *SessionTypeService) Register(ctx context.Context, req *models.CreateSessionTypeRequest) (*models.SessionType, error) {
+	if !sessionTypeName.MatchString(req.Name) {
+		return nil, fmt.Errorf("%w: name must be 2-32 lowercase letters, digits or underscores, starting with a letter", ErrInvalidSessionType)
+	}
+	if err := validateSchema(&req.PayloadSchema); err != nil {
+		return nil, err
+	}
+
+	sessionType := &models.SessionType{
+		Name:          req.Name,
+		DisplayName:   normalizeName(req.DisplayName),
+		Description:   req.Description,
+		PayloadSchema: req.PayloadSchema,
+	}
+	if sessionType.DisplayName == "" {
+		return nil, fmt.Errorf("%w: display_name must not be blank", ErrInvalidSessionType)
+	}
+	if err := s.repo.Create(ctx, sessionType); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionTypeExists
+		}
+		return nil, fmt.Errorf("failed to create session type: %w", err)
+	}
+
+	return sessionType, nil
+}
+
+// SetSessionType changes a session's type and replaces its payload, after validating the
+// payload against the type's schema. The session's owner and coaches with write access may
+// set it.
+func (s *SessionTypeService) SetSessionType(ctx context.Context, sessionID string, req *models.SetSessionTypeRequest, actorID string) (*models.SessionTypeAssignment, error) {
+	ownerID, err := sessionOwner(ctx, s.sessions, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := s.policy.CanWrite(ctx, actorID, ownerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	sessionType, err := s.repo.FindByName(ctx, req.Type)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionTypeNotFound
+		}
+		return nil, fmt.Errorf("failed to get session type: %w", err)
+	}
+
+	payload := req.Payload
+	if payload == nil {
+		payload = map[string]any{}
+	}
+	if err := validatePayload(&sessionType.PayloadSchema, payload); err != nil {
+		return nil, err
+	}
+
+	// The previous payload is not read, so the entry lists the type and payload set
+	return audited(ctx, s.audit, actorID, models.AuditActionUpdate, models.AuditResourceSession, sessionID, nil, func() (*models.SessionTypeAssignment, error) {
+		if err := s.repo.SetSessionType(ctx, sessionID, sessionType.Name, payload); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrSessionNotFound
+			}
+			return nil, fmt.Errorf("failed to set session type: %w", err)
+		}
+		return &models.SessionTypeAssignment{SessionID: sessionID, Type: sessionType.Name, Payload: payload}, nil
+	})
+}
+
+// Summaries aggregates the user's completed sessions started in [from, to) per type. Each
+// type's summary also combines the payload fields its schema declares a summary for.
+func (s *SessionTypeService) Summaries(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error) {
+	if !from.Before(to) {
+		return nil, ErrInvalidSessionTypeSpan
+	}
+
+	key := "session-types:" + from.UTC().Format(time.RFC3339) + ":" + to.UTC().Format(time.RFC3339)
+	return cache.Fetch(ctx, s.cache, analyticsCacheScope(userID), key, analyticsCacheTTL, func() ([]*models.SessionTypeSummary, error) {
+		return s.summaries(ctx, userID, from, to)
+	})
+}
+
+// summaries reads validated session type summaries
+func (s *SessionTypeService) summaries(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error) {
+	summaries, err := s.repo.Summaries(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session type summaries: %w", err)
+	}
+	if len(summaries) == 0 {
+		return summaries, nil
+	}
+
+	types, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list session types: %w", err)
+	}
+	schemas := make(map[string]*models.PayloadSchema, len(types))
+	for _, t := range types {
+		schemas[t.Name] = &t.PayloadSchema
+	}
+
+	for _, summary := range summaries {
+		schema, ok := schemas[summary.Type]
+		if !ok {
+			continue
+		}
+		fields := map[string]string{}
+		for name, field := range schema.Fields {
+			if field.Summary != "" {
+				fields[name] = field.Summary
+			}
+		}
+		if len(fields) == 0 {
+			continue
+		}
+
+		metrics, err := s.repo.PayloadMetrics(ctx, userID, summary.Type, fields, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get %s metrics: %w", summary.Type, err)
+		}
+		summary.Metrics = metrics
+	}
+
+	return summaries, nil
+}
+
+// validateSchema checks that a payload schema is well-formed
+func validateSchema(schema *models.PayloadSchema) error {
+	if schema.Fields == nil {
+		schema.Fields = map[string]models.PayloadField{}
+	}
+
+	for _, name := range sortedFields(schema.Fields) {
+		field := schema.Fields[name]
+		if !sessionTypeName.MatchString(name) {
+			return fmt.Errorf("%w: field %q must be 2-32 lowercase letters, digits or underscores, starting with a letter", ErrInvalidSessionType, name)
+		}
+
+		numeric := field.Type == models.PayloadFieldNumber || field.Type == models.PayloadFieldInteger
+		switch field.Type {
+		case models.PayloadFieldNumber, models.PayloadFieldInteger, models.PayloadFieldString, models.PayloadFieldBoolean:
+		case models.PayloadFieldEnum:
+			if len(field.Values) == 0 {
+				return fmt.Errorf("%w: enum field %q needs values", ErrInvalidSessionType, name)
+			}
+		default:
+			return fmt.Errorf("%w: field %q has unknown type %q", ErrInvalidSessionType, name, field.Type)
+		}
+
+		if (field.Min != nil || field.Max != nil) && !numeric {
+			return fmt.Errorf("%w: only numeric field %q may set min or max", ErrInvalidSessionType, name)
+		}
+		if field.Min != nil && field.Max != nil && *field.Min > *field.Max {
+			return fmt.Errorf("%w: field %q has min above max", ErrInvalidSessionType, name)
+		}
+		if field.MaxLength < 0 || (field.MaxLength > 0 && field.Type != models.PayloadFieldString) {
+			return fmt.Errorf("%w: only string field %q may set a positive max_length", ErrInvalidSessionType, name)
+		}
+		if len(field.Values) > 0 && field.Type != models.PayloadFieldEnum {
+			return fmt.Errorf("%w: only enum field %q may set values", ErrInvalidSessionType, name)
+		}
+		if field.Summary != "" {
+			switch field.Summary {
+			case models.PayloadSummarySum, models.PayloadSummaryAvg, models.PayloadSummaryMax:
+			default:
+				return fmt.Errorf("%w: field %q has unknown summary %q", ErrInvalidSessionType, name, field.Summary)
+			}
+			if !numeric {
+				return fmt.Errorf("%w: only numeric field %q may set a summary", ErrInvalidSessionType, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// validatePayload checks a session payload against its type's schema. JSON numbers decode
+// as float64, so integer fields accept whole numbers only.
+func validatePayload(schema *models.PayloadSchema, payload map[string]any) error {
+	for _, name := range sortedFields(schema.Fields) {
+		field := schema.Fields[name]
+		value, ok := payload[name]
+		if !ok || value == nil {
+			if field.Required {
+				return fmt.Errorf("%w: %s is required", ErrInvalidSessionPayload, name)
+			}
+			continue
+		}
+
+		switch field.Type {
+		case models.PayloadFieldNumber, models.PayloadFieldInteger:
+			number, ok := value.(float64)
+			if !ok {
+				return fmt.Errorf("%w: %s must be a number", ErrInvalidSessionPayload, name)
+			}
+			if field.Type == models.PayloadFieldInteger && number != math.Trunc(number) {
+				return fmt.Errorf("%w: %s must be a whole number", ErrInvalidSessionPayload, name)
+			}
+			if field.Min != nil && number < *field.Min {
+				return fmt.Errorf("%w: %s must be at least %g", ErrInvalidSessionPayload, name, *field.Min)
+			}
+			if field.Max != nil && number > *field.Max {
+				return fmt.Errorf("%w: %s must be at most %g", ErrInvalidSessionPayload, name, *field.Max)
+			}
+		case models.PayloadFieldString:
+			text, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("%w: %s must be a string", ErrInvalidSessionPayload, name)
+			}
+			if field.MaxLength > 0 && len([]rune(text)) > field.MaxLength {
+				return fmt.Errorf("%w: %s must be at most %d characters", ErrInvalidSessionPayload, name, field.MaxLength)
+			}
+		case models.PayloadFieldBoolean:
+			if _, ok := value.(bool); !ok {
+				return fmt.Errorf("%w: %s must be true or false", ErrInvalidSessionPayload, name)
+			}
+		case models.PayloadFieldEnum:
+			text, ok := value.(string)
+			if !ok || !slices.Contains(field.Values, text) {
+				return fmt.Errorf("%w: %s must be one of %v", ErrInvalidSessionPayload, name, field.Values)
+			}
+		}
+	}
+
+	if !schema.AdditionalFields {
+		for _, name := range sortedFields(payload) {
+			if _, ok := schema.Fields[name]; !ok {
+				return fmt.Errorf("%w: unknown field %s", ErrInvalidSessionPayload, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// sortedFields returns a map's keys in order, so validation reports the same error first
+func sortedFields[V any](fields map[string]V) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
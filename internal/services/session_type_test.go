@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func cardioType() *models.SessionType {
+	return &models.SessionType{
+		Name: models.SessionTypeCardio,
+		PayloadSchema: models.PayloadSchema{Fields: map[string]models.PayloadField{
+			"distance_m":     {Type: models.PayloadFieldNumber, Min: float(0), Summary: models.PayloadSummarySum},
+			"heart_rate_avg": {Type: models.PayloadFieldInteger, Min: float(20), Max: float(250), Summary: models.PayloadSummaryAvg},
+			"surface":        {Type: models.PayloadFieldEnum, Values: []string{"road", "trail"}},
+			"route":          {Type: models.PayloadFieldString, MaxLength: 5},
+			"indoor":         {Type: models.PayloadFieldBoolean, Required: true},
+		}},
+	}
+}
+
+func newSessionTypeService(repo *repositories.MockSessionTypeRepository) *SessionTypeService {
+	return NewSessionTypeService(repo, ownedSession("user-123"), NewAccessPolicy(&repositories.MockCoachClientRepository{}, &repositories.MockOrganizationRepository{}), nil, nil)
+}
+
+func TestSetSessionType_ValidatesPayload(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload map[string]any
+		wantErr error
+	}{
+		{"valid", map[string]any{"distance_m": 5000.5, "heart_rate_avg": 151.0, "surface": "trail", "indoor": false}, nil},
+		{"missing required", map[string]any{"distance_m": 5000.0}, ErrInvalidSessionPayload},
+		{"wrong type", map[string]any{"indoor": "no"}, ErrInvalidSessionPayload},
+		{"fractional integer", map[string]any{"indoor": true, "heart_rate_avg": 150.5}, ErrInvalidSessionPayload},
+		{"below min", map[string]any{"indoor": true, "distance_m": -1.0}, ErrInvalidSessionPayload},
+		{"above max", map[string]any{"indoor": true, "heart_rate_avg": 300.0}, ErrInvalidSessionPayload},
+		{"unknown enum value", map[string]any{"indoor": true, "surface": "sand"}, ErrInvalidSessionPayload},
+		{"string too long", map[string]any{"indoor": true, "route": "riverside"}, ErrInvalidSessionPayload},
+		{"unknown field", map[string]any{"indoor": true, "cadence": 180.0}, ErrInvalidSessionPayload},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stored map[string]any
+			mockRepo := &repositories.MockSessionTypeRepository{
+				FindByNameFunc: func(ctx context.Context, name string) (*models.SessionType, error) {
+					return cardioType(), nil
+				},
+				SetSessionTypeFunc: func(ctx context.Context, sessionID string, sessionType string, payload map[string]any) error {
+					stored = payload
+					return nil
+				},
+			}
+
+			req := &models.SetSessionTypeRequest{Type: models.SessionTypeCardio, Payload: tt.payload}
+			_, err := newSessionTypeService(mockRepo).SetSessionType(context.Background(), "session-1", req, "user-123")
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && stored == nil {
+				t.Error("Expected the payload to be stored")
+			}
+			if tt.wantErr != nil && stored != nil {
+				t.Error("Expected an invalid payload not to be stored")
+			}
+		})
+	}
+}
+
+func TestSetSessionType_AdditionalFields(t *testing.T) {
+	mockRepo := &repositories.MockSessionTypeRepository{
+		FindByNameFunc: func(ctx context.Context, name string) (*models.SessionType, error) {
+			return &models.SessionType{Name: models.SessionTypeCustom, PayloadSchema: models.PayloadSchema{AdditionalFields: true}}, nil
+		},
+	}
+	service := newSessionTypeService(mockRepo)
+
+	req := &models.SetSessionTypeRequest{Type: models.SessionTypeCustom, Payload: map[string]any{"laps_of_pool": 40.0, "buddy": "Sam"}}
+	if _, err := service.SetSessionType(context.Background(), "session-1", req, "user-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := service.SetSessionType(context.Background(), "session-1", req, "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+}
+
+func TestSetSessionType_UnknownType(t *testing.T) {
+	mockRepo := &repositories.MockSessionTypeRepository{}
+
+	req := &models.SetSessionTypeRequest{Type: "curling"}
+	if _, err := newSessionTypeService(mockRepo).SetSessionType(context.Background(), "session-1", req, "user-123"); !errors.Is(err, ErrSessionTypeNotFound) {
+		t.Errorf("Expected ErrSessionTypeNotFound, got %v", err)
+	}
+}
+
+func TestRegisterSessionType(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.CreateSessionTypeRequest
+		wantErr error
+	}{
+		{"valid", models.CreateSessionTypeRequest{Name: "climbing", DisplayName: "Climbing", PayloadSchema: models.PayloadSchema{Fields: map[string]models.PayloadField{
+			"grade":  {Type: models.PayloadFieldEnum, Values: []string{"5a", "6a", "7a"}},
+			"routes": {Type: models.PayloadFieldInteger, Min: float(0), Summary: models.PayloadSummarySum},
+		}}}, nil},
+		{"bad name", models.CreateSessionTypeRequest{Name: "Rock Climbing", DisplayName: "Climbing"}, ErrInvalidSessionType},
+		{"unknown field type", models.CreateSessionTypeRequest{Name: "climbing", DisplayName: "Climbing", PayloadSchema: models.PayloadSchema{Fields: map[string]models.PayloadField{
+			"grade": {Type: "date"},
+		}}}, ErrInvalidSessionType},
+		{"enum without values", models.CreateSessionTypeRequest{Name: "climbing", DisplayName: "Climbing", PayloadSchema: models.PayloadSchema{Fields: map[string]models.PayloadField{
+			"grade": {Type: models.PayloadFieldEnum},
+		}}}, ErrInvalidSessionType},
+		{"summary of a string", models.CreateSessionTypeRequest{Name: "climbing", DisplayName: "Climbing", PayloadSchema: models.PayloadSchema{Fields: map[string]models.PayloadField{
+			"crag": {Type: models.PayloadFieldString, Summary: models.PayloadSummarySum},
+		}}}, ErrInvalidSessionType},
+		{"min above max", models.CreateSessionTypeRequest{Name: "climbing", DisplayName: "Climbing", PayloadSchema: models.PayloadSchema{Fields: map[string]models.PayloadField{
+			"routes": {Type: models.PayloadFieldInteger, Min: float(10), Max: float(1)},
+		}}}, ErrInvalidSessionType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := newSessionTypeService(&repositories.MockSessionTypeRepository{}).Register(context.Background(), &tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRegisterSessionType_Exists(t *testing.T) {
+	mockRepo := &repositories.MockSessionTypeRepository{
+		CreateFunc: func(ctx context.Context, sessionType *models.SessionType) error {
+			return pgx.ErrNoRows
+		},
+	}
+
+	req := &models.CreateSessionTypeRequest{Name: models.SessionTypeCardio, DisplayName: "Cardio"}
+	if _, err := newSessionTypeService(mockRepo).Register(context.Background(), req); !errors.Is(err, ErrSessionTypeExists) {
+		t.Errorf("Expected ErrSessionTypeExists, got %v", err)
+	}
+}
+
+func TestSessionTypeSummaries_AggregatesDeclaredFields(t *testing.T) {
+	var requested map[string]string
+	mockRepo := &repositories.MockSessionTypeRepository{
+		SummariesFunc: func(ctx context.Context, userID string, from, to time.Time) ([]*models.SessionTypeSummary, error) {
+			return []*models.SessionTypeSummary{
+				{Type: models.SessionTypeCardio, Sessions: 3, DurationMinutes: 150, Metrics: map[string]float64{}},
+				{Type: models.SessionTypeStrength, Sessions: 2, DurationMinutes: 120, Metrics: map[string]float64{}},
+			}, nil
+		},
+		ListFunc: func(ctx context.Context) ([]*models.SessionType, error) {
+			return []*models.SessionType{cardioType(), {Name: models.SessionTypeStrength}}, nil
+		},
+		PayloadMetricsFunc: func(ctx context.Context, userID string, sessionType string, summaries map[string]string, from, to time.Time) (map[string]float64, error) {
+			if sessionType != models.SessionTypeCardio {
+				t.Errorf("Expected metrics only for cardio, got %s", sessionType)
+			}
+			requested = summaries
+			return map[string]float64{"distance_m": 21000}, nil
+		},
+	}
+
+	to := time.Date(2026, 5, 1, 0, 0, 0, 0, time.UTC)
+	summaries, err := newSessionTypeService(mockRepo).Summaries(context.Background(), "user-123", to.AddDate(0, -1, 0), to)
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(requested) != 2 || requested["distance_m"] != models.PayloadSummarySum || requested["heart_rate_avg"] != models.PayloadSummaryAvg {
+		t.Errorf("Expected the cardio summary fields, got %v", requested)
+	}
+	if summaries[0].Metrics["distance_m"] != 21000 {
+		t.Errorf("Expected 21000 m, got %v", summaries[0].Metrics)
+	}
+
+	if _, err := newSessionTypeService(mockRepo).Summaries(context.Background(), "user-123", to, to); !errors.Is(err, ErrInvalidSessionTypeSpan) {
+		t.Errorf("Expected ErrInvalidSessionTypeSpan, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/sharecard"
+)
+
+var (
+	ErrShareCardNotFound          = domainerr.New(domainerr.NotFound, "share card not found")
+	ErrShareCardSessionIncomplete = domainerr.New(domainerr.Conflict, "share cards can only be made of completed sessions")
+	ErrShareLinkInvalid           = domainerr.New(domainerr.Forbidden, "share link signature is invalid")
+	ErrShareLinkExpired           = domainerr.New(domainerr.Gone, "share link has expired")
+)
+
+const (
+	// shareCardTimeout bounds one attempt at rendering a card
+	shareCardTimeout = time.Minute
+
+	// shareCardAttempts is how often rendering a card is tried before the card fails
+	shareCardAttempts = 3
+
+	// shareLinkTTL is how long a signed download link works after it is handed out
+	shareLinkTTL = 7 * 24 * time.Hour
+)
+
+// ShareCardService renders summary images of sessions for sharing to social apps
+type ShareCardService struct {
+	repo repositories.ShareCardRepository
+	jobs *jobs.Queue
+	key  []byte
+	now  func() time.Time
+}
+
+// NewShareCardService creates a new share card service rendering cards as jobs of the
+// queue; download links are signed with key
+func NewShareCardService(repo repositories.ShareCardRepository, queue *jobs.Queue, key []byte) *ShareCardService {
+	s := &ShareCardService{repo: repo, jobs: queue, key: key, now: time.Now}
+	queue.Register(models.JobKindShareCard, jobs.Options{MaxAttempts: shareCardAttempts, Timeout: shareCardTimeout}, s.renderShareCard)
+	return s
+}
+
+// shareCardJob is the payload of a share card job
+type shareCardJob struct {
+	ShareCardID string `json:"share_card_id"`
+}
+
+// RequestShareCard starts rendering a card of one of the user's completed sessions.
+// The card is created pending and rendered by a background job; poll GetShareCard for
+// its status and download link.
+func (s *ShareCardService) RequestShareCard(ctx context.Context, userID string, sessionID string, format string) (*models.ShareCard, error) {
+	summary, err := s.repo.Summary(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSessionNotFound
+		}
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if summary.UserID != userID {
+		return nil, ErrSessionNotFound
+	}
+	if summary.Status != "completed" {
+		return nil, ErrShareCardSessionIncomplete
+	}
+
+	if format == "" {
+		format = models.ShareCardFormatPNG
+	}
+	card := &models.ShareCard{
+		UserID:    userID,
+		SessionID: sessionID,
+		Format:    format,
+		Status:    models.ShareCardStatusPending,
+	}
+	if err := s.repo.Create(ctx, card); err != nil {
+		return nil, fmt.Errorf("failed to create share card: %w", err)
+	}
+
+	if _, err := s.jobs.Enqueue(ctx, models.JobKindShareCard, userID, &shareCardJob{ShareCardID: card.ID}); err != nil {
+		if err := s.repo.Fail(ctx, card.ID, "rendering could not be queued"); err != nil {
+			log.Printf("Failed to mark share card %s as failed: %v", card.ID, err)
+		}
+		return nil, fmt.Errorf("failed to queue share card: %w", err)
+	}
+
+	return card, nil
+}
+
+// renderShareCard draws and stores a share card job's image from the session as it is
+// now; a failed last attempt is recorded on the card
+func (s *ShareCardService) renderShareCard(ctx context.Context, job *models.Job) error {
+	var payload shareCardJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid share card job: %w", err))
+	}
+
+	card, err := s.repo.FindByID(ctx, payload.ShareCardID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The session or its owner was deleted in the meantime
+			return jobs.Permanent(ErrShareCardNotFound)
+		}
+		return err
+	}
+
+	summary, err := s.repo.Summary(ctx, card.SessionID)
+	var image []byte
+	if err == nil {
+		image, err = sharecard.Render(summary, card.Format)
+	}
+	if err == nil {
+		err = s.repo.Complete(ctx, card.ID, image)
+	}
+	if err != nil {
+		log.Printf("Share card failed: card=%s session=%s attempt=%d err=%v", card.ID, card.SessionID, job.Attempts, err)
+		if jobs.LastAttempt(job) {
+			if err := s.repo.Fail(ctx, card.ID, "rendering failed"); err != nil {
+				log.Printf("Failed to mark share card %s as failed: %v", card.ID, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// GetShareCard retrieves the status of one of the user's share cards; ready cards come
+// with a freshly signed download link
+func (s *ShareCardService) GetShareCard(ctx context.Context, id string, userID string) (*models.ShareCard, error) {
+	card, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrShareCardNotFound
+		}
+		return nil, fmt.Errorf("failed to get share card: %w", err)
+	}
+
+	// Other users' cards are indistinguishable from missing ones
+	if card.UserID != userID {
+		return nil, ErrShareCardNotFound
+	}
+
+	if card.Status == models.ShareCardStatusReady {
+		expiresAt := s.now().Add(shareLinkTTL).Truncate(time.Second)
+		query := url.Values{
+			"expires":   {strconv.FormatInt(expiresAt.Unix(), 10)},
+			"signature": {signShareLink(s.key, card.ID, expiresAt)},
+		}
+		card.URL = "/api/share-cards/" + card.ID + "/image?" + query.Encode()
+		card.URLExpires = &expiresAt
+	}
+
+	return card, nil
+}
+
+// ShareCardImage retrieves a ready card's image and media type through a signed download
+// link, without authentication; expires is the link's expiry in Unix seconds
+func (s *ShareCardService) ShareCardImage(ctx context.Context, id string, expires int64, signature string) ([]byte, string, error) {
+	expiresAt := time.Unix(expires, 0)
+	if !hmac.Equal([]byte(signShareLink(s.key, id, expiresAt)), []byte(signature)) {
+		return nil, "", ErrShareLinkInvalid
+	}
+	if s.now().After(expiresAt) {
+		return nil, "", ErrShareLinkExpired
+	}
+
+	card, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrShareCardNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get share card: %w", err)
+	}
+
+	image, err := s.repo.FindImage(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, "", ErrShareCardNotFound
+		}
+		return nil, "", fmt.Errorf("failed to get share card image: %w", err)
+	}
+
+	return image, sharecard.ContentType(card.Format), nil
+}
+
+// signShareLink signs a card's download link until expiresAt with key, as hex
+func signShareLink(key []byte, cardID string, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(cardID + "." + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
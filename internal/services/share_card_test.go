@@ -0,0 +1,181 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func testShareCardSummary(userID string, status string) *models.ShareCardSummary {
+	duration := 45
+	return &models.ShareCardSummary{
+		UserID:          userID,
+		Status:          status,
+		SessionType:     "strength",
+		StartedAt:       time.Date(2026, 6, 1, 18, 0, 0, 0, time.UTC),
+		DurationMinutes: &duration,
+		VolumeKg:        5400,
+		Sets:            15,
+		Exercises:       4,
+		Records:         []*models.ShareCardRecord{},
+	}
+}
+
+// newTestShareCardService creates a share card service whose queued jobs are collected in queued
+func newTestShareCardService(repo repositories.ShareCardRepository, queued *[]*models.Job) *ShareCardService {
+	return NewShareCardService(repo, jobs.NewQueue(&repositories.MockJobRepository{
+		EnqueueFunc: func(ctx context.Context, job *models.Job) error {
+			job.ID = "job-1"
+			*queued = append(*queued, job)
+			return nil
+		},
+	}), []byte("test-key"))
+}
+
+func TestRequestShareCard_RendersImage(t *testing.T) {
+	var completedImage []byte
+	mockRepo := &repositories.MockShareCardRepository{
+		SummaryFunc: func(ctx context.Context, sessionID string) (*models.ShareCardSummary, error) {
+			return testShareCardSummary("user-123", "completed"), nil
+		},
+		FindByIDFunc: func(ctx context.Context, id string) (*models.ShareCard, error) {
+			return &models.ShareCard{ID: id, UserID: "user-123", SessionID: "session-1", Format: models.ShareCardFormatPNG, Status: models.ShareCardStatusPending}, nil
+		},
+		CompleteFunc: func(ctx context.Context, id string, image []byte) error {
+			completedImage = image
+			return nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestShareCardService(mockRepo, &queued)
+
+	card, err := service.RequestShareCard(context.Background(), "user-123", "session-1", "")
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if card.Format != models.ShareCardFormatPNG || card.Status != models.ShareCardStatusPending {
+		t.Errorf("Expected a pending png card, got %s %s", card.Format, card.Status)
+	}
+	if len(queued) != 1 || queued[0].Kind != models.JobKindShareCard {
+		t.Fatalf("Expected a share card job, got %+v", queued)
+	}
+
+	job := queued[0]
+	job.Attempts = 1
+	if err := service.renderShareCard(context.Background(), job); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !bytes.HasPrefix(completedImage, []byte("\x89PNG")) {
+		t.Error("Expected a PNG to be stored on the card")
+	}
+}
+
+func TestRequestShareCard_OtherUsersSession(t *testing.T) {
+	mockRepo := &repositories.MockShareCardRepository{
+		SummaryFunc: func(ctx context.Context, sessionID string) (*models.ShareCardSummary, error) {
+			return testShareCardSummary("other-user", "completed"), nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestShareCardService(mockRepo, &queued)
+
+	_, err := service.RequestShareCard(context.Background(), "user-123", "session-1", "svg")
+
+	if !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("Expected ErrSessionNotFound, got %v", err)
+	}
+	if len(queued) != 0 {
+		t.Error("Expected no job to be queued")
+	}
+}
+
+func TestRequestShareCard_IncompleteSession(t *testing.T) {
+	mockRepo := &repositories.MockShareCardRepository{
+		SummaryFunc: func(ctx context.Context, sessionID string) (*models.ShareCardSummary, error) {
+			return testShareCardSummary("user-123", "in_progress"), nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestShareCardService(mockRepo, &queued)
+
+	_, err := service.RequestShareCard(context.Background(), "user-123", "session-1", "png")
+
+	if !errors.Is(err, ErrShareCardSessionIncomplete) {
+		t.Errorf("Expected ErrShareCardSessionIncomplete, got %v", err)
+	}
+}
+
+func TestShareCardImage_SignedLink(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	mockRepo := &repositories.MockShareCardRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.ShareCard, error) {
+			return &models.ShareCard{ID: id, UserID: "user-123", Format: models.ShareCardFormatSVG, Status: models.ShareCardStatusReady}, nil
+		},
+		FindImageFunc: func(ctx context.Context, id string) ([]byte, error) {
+			return []byte("<svg/>"), nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestShareCardService(mockRepo, &queued)
+	service.now = func() time.Time { return now }
+
+	card, err := service.GetShareCard(context.Background(), "card-1", "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	link, err := url.Parse(card.URL)
+	if err != nil || link.Path != "/api/share-cards/card-1/image" {
+		t.Fatalf("Expected a download link, got %q", card.URL)
+	}
+	expires, _ := strconv.ParseInt(link.Query().Get("expires"), 10, 64)
+	signature := link.Query().Get("signature")
+
+	image, contentType, err := service.ShareCardImage(context.Background(), "card-1", expires, signature)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if string(image) != "<svg/>" || contentType != "image/svg+xml" {
+		t.Errorf("Expected the svg image, got %s (%s)", image, contentType)
+	}
+
+	// The signature covers the card and the expiry
+	if _, _, err := service.ShareCardImage(context.Background(), "card-2", expires, signature); !errors.Is(err, ErrShareLinkInvalid) {
+		t.Errorf("Expected ErrShareLinkInvalid for another card, got %v", err)
+	}
+	if _, _, err := service.ShareCardImage(context.Background(), "card-1", expires+3600, signature); !errors.Is(err, ErrShareLinkInvalid) {
+		t.Errorf("Expected ErrShareLinkInvalid for an extended expiry, got %v", err)
+	}
+
+	now = now.Add(shareLinkTTL + time.Second)
+	if _, _, err := service.ShareCardImage(context.Background(), "card-1", expires, signature); !errors.Is(err, ErrShareLinkExpired) {
+		t.Errorf("Expected ErrShareLinkExpired, got %v", err)
+	}
+}
+
+func TestGetShareCard_OtherUsersCard(t *testing.T) {
+	mockRepo := &repositories.MockShareCardRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.ShareCard, error) {
+			return &models.ShareCard{ID: id, UserID: "other-user", Status: models.ShareCardStatusReady}, nil
+		},
+	}
+
+	var queued []*models.Job
+	service := newTestShareCardService(mockRepo, &queued)
+
+	if _, err := service.GetShareCard(context.Background(), "card-1", "user-123"); !errors.Is(err, ErrShareCardNotFound) {
+		t.Errorf("Expected ErrShareCardNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,275 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrSleepLogNotFound       = domainerr.New(domainerr.NotFound, "sleep log not found")
+	ErrSleepLogExists         = domainerr.New(domainerr.Conflict, "sleep starting at this time is already logged")
+	ErrInvalidSleepTimes      = domainerr.New(domainerr.Validation, "ended_at must be after started_at, at most 24 hours later and not in the future")
+	ErrInvalidSleepRange      = domainerr.New(domainerr.Validation, "from must be before to and the range at most 1 year")
+	ErrUnsupportedSleepSource = domainerr.New(domainerr.Validation, "unsupported sleep source, expected sleepcycle or fitbit")
+)
+
+const (
+	// maxSleepDuration is the longest sleep that can be logged
+	maxSleepDuration = 24 * time.Hour
+
+	// maxSleepRange caps the range of a sleep listing
+	maxSleepRange = 366 * 24 * time.Hour
+)
+
+// sleepColumns maps normalized CSV headers (lowercase, no spaces) to sleep fields
+// Covers Sleep Cycle ("Start", "End", "Sleep Quality" as a percentage) and Fitbit
+// ("Start Time", "End Time").
+var sleepColumns = map[string]string{
+	"start":        "start",
+	"starttime":    "start",
+	"bedtime":      "start",
+	"end":          "end",
+	"endtime":      "end",
+	"wakeup":       "end",
+	"sleepquality": "quality_pct",
+}
+
+// sleepTimeLayouts are the timestamp formats seen in sleep tracker exports
+var sleepTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+	"2006-01-02 3:04PM",
+	"2006-01-02 3:04 PM",
+}
+
+// SleepService handles sleep logs and sleep tracker imports
+type SleepService struct {
+	repo     repositories.SleepRepository
+	profiles repositories.ProfileRepository
+	now      func() time.Time
+}
+
+// NewSleepService creates a new sleep service
+func NewSleepService(repo repositories.SleepRepository, profiles repositories.ProfileRepository) *SleepService {
+	return &SleepService{repo: repo, profiles: profiles, now: time.Now}
+}
+
+// LogSleep records a night of sleep, or a nap, entered by the user
+func (s *SleepService) LogSleep(ctx context.Context, userID string, req *models.CreateSleepLogRequest) (*models.SleepLog, error) {
+	if !validSleep(req.StartedAt, req.EndedAt) || req.EndedAt.After(s.now().Add(clockSkew)) {
+		return nil, ErrInvalidSleepTimes
+	}
+
+	log := &models.SleepLog{
+		UserID:    userID,
+		StartedAt: req.StartedAt.UTC(),
+		EndedAt:   req.EndedAt.UTC(),
+		Quality:   req.Quality,
+		Source:    models.SleepSourceManual,
+	}
+	if err := s.repo.Create(ctx, log); err != nil {
+		if errors.Is(err, repositories.ErrDuplicate) {
+			return nil, ErrSleepLogExists
+		}
+		return nil, fmt.Errorf("failed to log sleep: %w", err)
+	}
+
+	return log, nil
+}
+
+// ListSleep retrieves the user's sleep that ended between the dates from and to
+// (exclusive) of their timezone, newest first. A zero to is tomorrow, so last night is
+// included, and a zero from 30 days before to.
+func (s *SleepService) ListSleep(ctx context.Context, userID string, from, to time.Time) ([]*models.SleepLog, error) {
+	if to.IsZero() {
+		loc, err := userLocation(ctx, s.profiles, userID)
+		if err != nil {
+			return nil, err
+		}
+		to = localDate(s.now(), loc).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+	}
+	if !from.Before(to) || to.Sub(from) > maxSleepRange {
+		return nil, ErrInvalidSleepRange
+	}
+
+	logs, err := s.repo.FindByUser(ctx, userID, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sleep: %w", err)
+	}
+
+	return logs, nil
+}
+
+// DeleteSleep removes one of the user's sleep logs
+func (s *SleepService) DeleteSleep(ctx context.Context, userID string, id string) error {
+	if err := s.repo.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrSleepLogNotFound
+		}
+		return fmt.Errorf("failed to delete sleep log: %w", err)
+	}
+
+	return nil
+}
+
+// ImportSleepCSV imports a Sleep Cycle or Fitbit CSV export.
+// Timestamps without an offset are read in loc, or the user's timezone when loc is nil.
+// Rows whose start the user already has are skipped, so re-importing an export is harmless.
+func (s *SleepService) ImportSleepCSV(ctx context.Context, userID string, source string, loc *time.Location, r io.Reader) (*models.SleepImportResult, error) {
+	if source != models.SleepSourceSleepCycle && source != models.SleepSourceFitbit {
+		return nil, ErrUnsupportedSleepSource
+	}
+	if loc == nil {
+		userLoc, err := userLocation(ctx, s.profiles, userID)
+		if err != nil {
+			return nil, err
+		}
+		loc = userLoc
+	}
+
+	logs, err := parseSleepCSV(r, loc)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range logs {
+		l.UserID = userID
+		l.Source = source
+	}
+
+	result := &models.SleepImportResult{Source: source, Rows: len(logs)}
+	if len(logs) == 0 {
+		return result, nil
+	}
+
+	imported, err := s.repo.CreateMany(ctx, logs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to import sleep: %w", err)
+	}
+	result.Imported = imported
+	result.Skipped = int64(len(logs)) - imported
+
+	return result, nil
+}
+
+// validSleep reports whether sleep from start to end has a duration that can be logged
+func validSleep(start, end time.Time) bool {
+	return end.After(start) && end.Sub(start) <= maxSleepDuration
+}
+
+// parseSleepCSV reads a sleep tracker export into sleep logs. Sleep Cycle separates
+// fields with semicolons, so the separator is taken from the header row.
+func parseSleepCSV(r io.Reader, loc *time.Location) ([]*models.SleepLog, error) {
+	buffered := bufio.NewReader(r)
+	head, _ := buffered.Peek(4096)
+	if i := bytes.IndexByte(head, '\n'); i >= 0 {
+		head = head[:i]
+	}
+
+	reader := csv.NewReader(buffered)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(head, []byte(";")) > bytes.Count(head, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: missing header row", ErrInvalidImportFile)
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		key := strings.ToLower(strings.Join(strings.Fields(strings.TrimPrefix(name, "\ufeff")), ""))
+		if field, ok := sleepColumns[key]; ok {
+			columns[field] = i
+		}
+	}
+	_, hasStart := columns["start"]
+	_, hasEnd := columns["end"]
+	if !hasStart || !hasEnd {
+		return nil, fmt.Errorf("%w: no start and end columns found", ErrInvalidImportFile)
+	}
+
+	var logs []*models.SleepLog
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImportFile, line, err)
+		}
+		if len(logs) >= maxImportRows {
+			return nil, fmt.Errorf("%w: more than %d rows", ErrInvalidImportFile, maxImportRows)
+		}
+
+		l, err := parseSleepRecord(record, columns, loc)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %v", ErrInvalidImportFile, line, err)
+		}
+		logs = append(logs, l)
+	}
+
+	return logs, nil
+}
+
+func parseSleepRecord(record []string, columns map[string]int, loc *time.Location) (*models.SleepLog, error) {
+	field := func(name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	start, err := parseSleepTime(field("start"), loc)
+	if err != nil {
+		return nil, err
+	}
+	end, err := parseSleepTime(field("end"), loc)
+	if err != nil {
+		return nil, err
+	}
+	if !validSleep(start, end) {
+		return nil, fmt.Errorf("sleep from %s to %s is not between 0 and 24 hours", field("start"), field("end"))
+	}
+
+	l := &models.SleepLog{StartedAt: start.UTC(), EndedAt: end.UTC()}
+
+	// Sleep Cycle rates nights from 0 to 100%; spread that over the 1 to 5 scale
+	if raw := strings.TrimSuffix(field("quality_pct"), "%"); raw != "" {
+		pct, err := strconv.ParseFloat(raw, 64)
+		if err != nil || pct < 0 || pct > 100 {
+			return nil, fmt.Errorf("invalid sleep quality %q", field("quality_pct"))
+		}
+		quality := max(1, min(5, int(pct+19)/20))
+		l.Quality = &quality
+	}
+
+	return l, nil
+}
+
+func parseSleepTime(raw string, loc *time.Location) (time.Time, error) {
+	for _, layout := range sleepTimeLayouts {
+		if t, err := time.ParseInLocation(layout, raw, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized date %q", raw)
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestImportSleepCSV_SleepCycle(t *testing.T) {
+	var saved []*models.SleepLog
+	mockRepo := &repositories.MockSleepRepository{
+		CreateManyFunc: func(ctx context.Context, logs []*models.SleepLog) (int64, error) {
+			saved = logs
+			return int64(len(logs)), nil
+		},
+	}
+	profiles := &repositories.MockProfileRepository{
+		FindProfileFunc: func(ctx context.Context, userID string) (*models.Profile, error) {
+			return &models.Profile{UserID: userID, Timezone: "Europe/Madrid"}, nil
+		},
+	}
+
+	service := NewSleepService(mockRepo, profiles)
+
+	csv := "Start;End;Sleep Quality;Regularity;Time in bed (seconds)\n" +
+		"2026-06-01 23:10:00;2026-06-02 07:05:00;85%;90%;28500\n" +
+		"2026-06-02 23:40:00;2026-06-03 06:30:00;12%;88%;24600\n"
+
+	result, err := service.ImportSleepCSV(context.Background(), "user-123", "sleepcycle", nil, strings.NewReader(csv))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if result.Rows != 2 || result.Imported != 2 {
+		t.Errorf("Expected 2 rows imported, got %+v", result)
+	}
+
+	first := saved[0]
+	if first.UserID != "user-123" || first.Source != models.SleepSourceSleepCycle {
+		t.Errorf("Expected user and source to be set, got %q and %q", first.UserID, first.Source)
+	}
+	if !first.StartedAt.Equal(time.Date(2026, 6, 1, 21, 10, 0, 0, time.UTC)) {
+		t.Errorf("Expected start in the user's timezone, got %v", first.StartedAt)
+	}
+	if first.Quality == nil || *first.Quality != 5 || saved[1].Quality == nil || *saved[1].Quality != 1 {
+		t.Errorf("Expected quality 5 and 1, got %v and %v", first.Quality, saved[1].Quality)
+	}
+}
+
+func TestImportSleepCSV_Fitbit(t *testing.T) {
+	var saved []*models.SleepLog
+	mockRepo := &repositories.MockSleepRepository{
+		CreateManyFunc: func(ctx context.Context, logs []*models.SleepLog) (int64, error) {
+			saved = logs
+			return int64(len(logs)), nil
+		},
+	}
+
+	service := NewSleepService(mockRepo, &repositories.MockProfileRepository{})
+
+	csv := "Start Time,End Time,Minutes Asleep,Minutes Awake,Number of Awakenings,Time in Bed\n" +
+		"\"2026-06-01 10:57PM\",\"2026-06-02 6:41AM\",\"431\",\"33\",\"2\",\"464\"\n"
+
+	_, err := service.ImportSleepCSV(context.Background(), "user-123", "fitbit", time.UTC, strings.NewReader(csv))
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(saved) != 1 || !saved[0].EndedAt.Equal(time.Date(2026, 6, 2, 6, 41, 0, 0, time.UTC)) || saved[0].Quality != nil {
+		t.Errorf("Expected one night ending at 06:41 without quality, got %+v", saved[0])
+	}
+}
+
+func TestImportSleepCSV_Invalid(t *testing.T) {
+	service := NewSleepService(&repositories.MockSleepRepository{}, &repositories.MockProfileRepository{})
+
+	tests := []struct {
+		name   string
+		source string
+		csv    string
+		want   error
+	}{
+		{"unknown source", "oura", "Start,End\n", ErrUnsupportedSleepSource},
+		{"no end column", "fitbit", "Start Time,Minutes Asleep\n2026-06-01 10:57PM,431\n", ErrInvalidImportFile},
+		{"ends before it starts", "sleepcycle", "Start;End\n2026-06-02 07:00:00;2026-06-01 23:00:00\n", ErrInvalidImportFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.ImportSleepCSV(context.Background(), "user-123", tt.source, time.UTC, strings.NewReader(tt.csv))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestLogSleep_InvalidTimes(t *testing.T) {
+	service := NewSleepService(&repositories.MockSleepRepository{}, &repositories.MockProfileRepository{})
+	service.now = func() time.Time { return time.Date(2026, 6, 2, 9, 0, 0, 0, time.UTC) }
+
+	start := time.Date(2026, 6, 1, 23, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		end  time.Time
+	}{
+		{"ends before it starts", start.Add(-time.Hour)},
+		{"longer than a day", start.Add(25 * time.Hour)},
+		{"ends in the future", time.Date(2026, 6, 2, 11, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.LogSleep(context.Background(), "user-123", &models.CreateSleepLogRequest{StartedAt: start, EndedAt: tt.end})
+			if !errors.Is(err, ErrInvalidSleepTimes) {
+				t.Errorf("Expected ErrInvalidSleepTimes, got %v", err)
+			}
+		})
+	}
+}
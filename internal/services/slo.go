@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/slo"
+	"github.com/juan-cantero/fitapi/internal/webhooks"
+)
+
+// sloAlertRules are the multiwindow burn rate alerts from Google's SRE workbook: an alert
+// fires while both windows burn faster than the threshold, so it starts quickly and stops
+// soon after the problem does. Against a 30-day budget, 14.4 spends 2% of it in an hour
+// and 6 spends 5% in six hours.
+var sloAlertRules = []struct {
+	severity    string
+	longWindow  string
+	shortWindow string
+	burnRate    float64
+}{
+	{models.SLOSeverityPage, "1h", "5m", 14.4},
+	{models.SLOSeverityTicket, "6h", "30m", 6},
+}
+
+// sloMinRequests keeps a handful of slow requests on a quiet group from firing alerts
+const sloMinRequests = 50
+
+// SLOAlerting is where alerts are sent besides the log; alerts are only logged without a URL
+type SLOAlerting struct {
+	WebhookURL    string
+	WebhookSecret string // whsec_ secret, signs alerts (Standard Webhooks)
+}
+
+// SLOService reports response time objectives and alerts when route groups burn their
+// error budget too fast
+type SLOService struct {
+	tracker  *slo.Tracker
+	sender   *webhooks.Sender
+	alerting SLOAlerting
+	now      func() time.Time
+
+	mu     sync.Mutex
+	firing map[string]*models.SLOAlert // group/severity -> alert
+}
+
+// NewSLOService creates a new SLO service
+func NewSLOService(tracker *slo.Tracker, sender *webhooks.Sender, alerting SLOAlerting) *SLOService {
+	return &SLOService{
+		tracker:  tracker,
+		sender:   sender,
+		alerting: alerting,
+		now:      time.Now,
+		firing:   make(map[string]*models.SLOAlert),
+	}
+}
+
+// Report returns this instance's compliance per route group and the alerts firing
+func (s *SLOService) Report() *models.SLOReport {
+	s.mu.Lock()
+	alerts := make([]*models.SLOAlert, 0, len(s.firing))
+	for _, alert := range s.firing {
+		alerts = append(alerts, alert)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(alerts, func(i, j int) bool {
+		if alerts[i].Group != alerts[j].Group {
+			return alerts[i].Group < alerts[j].Group
+		}
+		return alerts[i].Severity < alerts[j].Severity
+	})
+
+	return &models.SLOReport{GeneratedAt: s.now(), Groups: s.tracker.Report(), Alerts: alerts}
+}
+
+// Start evaluates the alert rules every interval until ctx is cancelled
+func (s *SLOService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.evaluate(ctx)
+			}
+		}
+	}()
+}
+
+// evaluate fires alerts for groups that started burning too fast and resolves those that
+// stopped, notifying once per change
+func (s *SLOService) evaluate(ctx context.Context) {
+	type change struct {
+		alert    *models.SLOAlert
+		resolved bool
+	}
+	var changes []change
+
+	s.mu.Lock()
+	for _, report := range s.tracker.Report() {
+		windows := make(map[string]models.SLOWindowReport, len(report.Windows))
+		for _, w := range report.Windows {
+			windows[w.Window] = w
+		}
+
+		for _, rule := range sloAlertRules {
+			long, short := windows[rule.longWindow], windows[rule.shortWindow]
+			burning := long.Requests >= sloMinRequests && long.BurnRate >= rule.burnRate && short.BurnRate >= rule.burnRate
+
+			key := report.Group + "/" + rule.severity
+			alert, fired := s.firing[key]
+			switch {
+			case burning && !fired:
+				alert = &models.SLOAlert{
+					Group:       report.Group,
+					Severity:    rule.severity,
+					LongWindow:  rule.longWindow,
+					ShortWindow: rule.shortWindow,
+					BurnRate:    long.BurnRate,
+					Threshold:   rule.burnRate,
+					Since:       s.now(),
+				}
+				s.firing[key] = alert
+				changes = append(changes, change{alert: alert})
+			case burning:
+				alert.BurnRate = long.BurnRate
+			case fired:
+				delete(s.firing, key)
+				changes = append(changes, change{alert: alert, resolved: true})
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for _, c := range changes {
+		s.notify(ctx, c.alert, c.resolved)
+	}
+}
+
+// notify logs an alert change and posts it to the alert webhook, if configured. Failed
+// posts are logged and not retried; the report keeps showing firing alerts.
+func (s *SLOService) notify(ctx context.Context, alert *models.SLOAlert, resolved bool) {
+	state := "firing"
+	if resolved {
+		state = "resolved"
+	}
+	log.Printf("SLO alert %s: group=%s severity=%s burn_rate=%.1f threshold=%.1f windows=%s/%s",
+		state, alert.Group, alert.Severity, alert.BurnRate, alert.Threshold, alert.LongWindow, alert.ShortWindow)
+
+	if s.alerting.WebhookURL == "" {
+		return
+	}
+
+	data, err := json.Marshal(alert)
+	if err != nil {
+		log.Printf("SLO alert for %s failed to encode: %v", alert.Group, err)
+		return
+	}
+	msg := &webhooks.Message{
+		ID:        fmt.Sprintf("slo-%s-%s-%d-%s", alert.Group, alert.Severity, alert.Since.Unix(), state),
+		Type:      "slo.alert." + state,
+		Timestamp: s.now(),
+		Data:      data,
+	}
+	if _, err := s.sender.Send(ctx, s.alerting.WebhookURL, s.alerting.WebhookSecret, msg); err != nil {
+		log.Printf("SLO alert for %s failed to send: %v", alert.Group, err)
+	}
+}
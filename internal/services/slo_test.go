@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/slo"
+	"github.com/juan-cantero/fitapi/internal/webhooks"
+)
+
+func TestSLOEvaluate_FiresAndResolvesOnce(t *testing.T) {
+	var mu sync.Mutex
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var payload struct {
+			Type string          `json:"type"`
+			Data models.SLOAlert `json:"data"`
+		}
+		json.Unmarshal(body, &payload)
+		mu.Lock()
+		received = append(received, payload.Type+" "+payload.Data.Severity)
+		mu.Unlock()
+	}))
+	defer server.Close()
+
+	secret, _ := webhooks.NewSecret()
+	targets := []slo.Target{{Group: "sessions", Threshold: 300 * time.Millisecond, Objective: 0.99}}
+	tracker := slo.NewTracker(targets)
+	for i := range 100 {
+		status := http.StatusOK
+		if i%2 == 0 {
+			status = http.StatusInternalServerError
+		}
+		tracker.Observe("sessions", status, 10*time.Millisecond)
+	}
+
+	service := NewSLOService(tracker, webhooks.NewSender(server.Client()), SLOAlerting{WebhookURL: server.URL, WebhookSecret: secret})
+	service.evaluate(context.Background())
+	service.evaluate(context.Background())
+
+	report := service.Report()
+	if len(report.Alerts) != 2 || report.Alerts[0].Severity != models.SLOSeverityPage || report.Alerts[1].Severity != models.SLOSeverityTicket {
+		t.Fatalf("Expected page and ticket alerts, got %+v", report.Alerts)
+	}
+	if report.Alerts[0].BurnRate < 49.9 || report.Alerts[0].BurnRate > 50.1 {
+		t.Errorf("Expected a burn rate of 50, got %v", report.Alerts[0].BurnRate)
+	}
+
+	// Healthy traffic from here on
+	service.tracker = slo.NewTracker(targets)
+	service.evaluate(context.Background())
+
+	if alerts := service.Report().Alerts; len(alerts) != 0 {
+		t.Errorf("Expected alerts to resolve, got %+v", alerts)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"slo.alert.firing page", "slo.alert.firing ticket", "slo.alert.resolved page", "slo.alert.resolved ticket"}
+	if len(received) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, received)
+	}
+	for i := range want {
+		if received[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, received)
+			break
+		}
+	}
+}
+
+func TestSLOEvaluate_IgnoresQuietGroups(t *testing.T) {
+	tracker := slo.NewTracker([]slo.Target{{Group: "sessions", Threshold: 300 * time.Millisecond, Objective: 0.99}})
+	for range sloMinRequests - 1 {
+		tracker.Observe("sessions", http.StatusInternalServerError, 10*time.Millisecond)
+	}
+
+	service := NewSLOService(tracker, nil, SLOAlerting{})
+	service.evaluate(context.Background())
+
+	if alerts := service.Report().Alerts; len(alerts) != 0 {
+		t.Errorf("Expected no alerts below %d requests, got %+v", sloMinRequests, alerts)
+	}
+}
@@ -0,0 +1,651 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/strava"
+)
+
+var (
+	ErrStravaNotConfigured  = domainerr.New(domainerr.Transient, "strava integration is not configured")
+	ErrStravaNotConnected   = domainerr.New(domainerr.NotFound, "strava is not connected")
+	ErrStravaAuthFailed     = domainerr.New(domainerr.Validation, "strava authorization failed")
+	ErrStravaReauthRequired = domainerr.New(domainerr.Conflict, "strava access was revoked, connect again")
+	ErrInvalidStravaWebhook = domainerr.New(domainerr.Forbidden, "strava webhook subscription does not match")
+)
+
+const (
+	// stravaStateTTL is how long a consent page URL can be completed
+	stravaStateTTL = 10 * time.Minute
+
+	// stravaInitialSync is how much history the first sync pulls
+	stravaInitialSync = 30 * 24 * time.Hour
+
+	// stravaSyncOverlap re-reads recent history on every sync to catch activities whose
+	// webhook event was missed
+	stravaSyncOverlap = 48 * time.Hour
+
+	// stravaSyncTimeout bounds a single user's sync or job
+	stravaSyncTimeout = 2 * time.Minute
+
+	// stravaSyncBatch caps how many connections one background sync handles
+	stravaSyncBatch = 50
+
+	// stravaJobMaxAttempts is how often a job is tried before it is dead-lettered, the
+	// jobs table's default that push jobs queued by the repository get as well
+	stravaJobMaxAttempts = 8
+)
+
+// StravaClient is the subset of the Strava API the service uses
+type StravaClient interface {
+	Configured() bool
+	AuthCodeURL(state string) string
+	Exchange(ctx context.Context, code string) (*strava.Token, error)
+	Refresh(ctx context.Context, refreshToken string) (*strava.Token, error)
+	Deauthorize(ctx context.Context, accessToken string) error
+	Activities(ctx context.Context, accessToken string, after, before time.Time) ([]*strava.Activity, error)
+	Activity(ctx context.Context, accessToken string, id int64) (*strava.Activity, error)
+	CreateActivity(ctx context.Context, accessToken string, activity *strava.NewActivity) (int64, error)
+}
+
+// StravaWebhook identifies the application's Strava webhook subscription
+type StravaWebhook struct {
+	VerifyToken    string // Echoed by Strava when the subscription is created
+	SubscriptionID int64  // Events for other subscriptions are rejected; 0 rejects every event
+}
+
+// StravaService connects users' Strava accounts, pulls their activities in (from webhook events
+// and periodic syncs) and pushes their completed sessions out. Webhook events and pushes are
+// run as jobs of the queue.
+type StravaService struct {
+	repo      repositories.IntegrationRepository
+	client    StravaClient
+	stateKey  []byte
+	webhook   StravaWebhook
+	publisher events.Publisher
+	jobs      *jobs.Queue
+	now       func() time.Time
+	run       func(task func()) // Runs the first sync after connecting; a goroutine outside tests
+}
+
+// NewStravaService creates a new Strava service running webhook events and pushes as jobs
+// of the queue. stateKey signs OAuth state values so a consent redirect can only complete
+// the connection of the user who started it.
+func NewStravaService(repo repositories.IntegrationRepository, client StravaClient, stateKey []byte, webhook StravaWebhook, publisher events.Publisher, queue *jobs.Queue) *StravaService {
+	s := &StravaService{
+		repo:      repo,
+		client:    client,
+		stateKey:  stateKey,
+		webhook:   webhook,
+		publisher: publisher,
+		jobs:      queue,
+		now:       time.Now,
+		run:       func(task func()) { go task() },
+	}
+	opts := jobs.Options{MaxAttempts: stravaJobMaxAttempts, Timeout: stravaSyncTimeout}
+	queue.Register(models.JobKindStravaImportActivity, opts, s.connectionJob(s.importActivity))
+	queue.Register(models.JobKindStravaDeleteActivity, opts, s.connectionJob(s.deleteActivity))
+	queue.Register(models.JobKindStravaPushSession, opts, s.connectionJob(s.pushSession))
+	return s
+}
+
+// stravaJob is the payload of a Strava job
+type stravaJob struct {
+	ConnectionID string `json:"connection_id"`
+	ObjectID     string `json:"object_id"` // Strava activity ID, or the fitapi session ID for pushes
+}
+
+// Authorize returns the Strava consent page the user opens to connect Strava
+func (s *StravaService) Authorize(userID string) (*models.IntegrationAuthorization, error) {
+	if !s.client.Configured() {
+		return nil, ErrStravaNotConfigured
+	}
+
+	expiresAt := s.now().Add(stravaStateTTL).UTC().Truncate(time.Second)
+	state := signOAuthState(s.stateKey, userID, expiresAt)
+
+	return &models.IntegrationAuthorization{
+		AuthURL:   s.client.AuthCodeURL(state),
+		State:     state,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Connect completes the connection with the authorization code Strava redirected back with
+// and starts a first sync in the background. New connections pull activities and do not
+// push sessions until the user turns it on.
+func (s *StravaService) Connect(ctx context.Context, userID string, req *models.ConnectIntegrationRequest) (*models.IntegrationConnection, error) {
+	if !s.client.Configured() {
+		return nil, ErrStravaNotConfigured
+	}
+	if !verifyOAuthState(s.stateKey, userID, req.State, s.now()) {
+		return nil, ErrInvalidOAuthState
+	}
+
+	token, err := s.client.Exchange(ctx, req.Code)
+	if err != nil {
+		if errors.Is(err, strava.ErrInvalidGrant) {
+			return nil, ErrStravaAuthFailed
+		}
+		return nil, fmt.Errorf("failed to exchange authorization code: %w", err)
+	}
+	if token.RefreshToken == "" || token.AthleteID == 0 {
+		return nil, ErrStravaAuthFailed
+	}
+
+	athleteID := strconv.FormatInt(token.AthleteID, 10)
+	conn := &models.IntegrationConnection{
+		UserID:            userID,
+		Provider:          models.IntegrationProviderStrava,
+		ExternalAccountID: &athleteID,
+		AccessToken:       token.AccessToken,
+		RefreshToken:      token.RefreshToken,
+		TokenExpiresAt:    token.ExpiresAt,
+	}
+	if err := s.repo.SaveConnection(ctx, conn); err != nil {
+		return nil, fmt.Errorf("failed to save connection: %w", err)
+	}
+
+	initial := *conn // The sync refreshes tokens on its own copy
+	s.run(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), stravaSyncTimeout)
+		defer cancel()
+		if _, err := s.syncConnection(ctx, &initial); err != nil {
+			log.Printf("Strava initial sync for user %s failed: %v", userID, err)
+		}
+	})
+
+	return conn, nil
+}
+
+// GetConnection retrieves the user's Strava connection and its sync status
+func (s *StravaService) GetConnection(ctx context.Context, userID string) (*models.IntegrationConnection, error) {
+	conn, err := s.repo.FindConnection(ctx, userID, models.IntegrationProviderStrava)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrStravaNotConnected
+		}
+		return nil, fmt.Errorf("failed to get connection: %w", err)
+	}
+	return conn, nil
+}
+
+// UpdateSettings chooses whether Strava activities are pulled in and whether completed
+// sessions are pushed to Strava. Turning pushing on only pushes sessions completed from now on.
+func (s *StravaService) UpdateSettings(ctx context.Context, userID string, req *models.UpdateIntegrationSettingsRequest) (*models.IntegrationConnection, error) {
+	conn, err := s.GetConnection(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.PullActivities != nil {
+		conn.PullActivities = *req.PullActivities
+	}
+	if req.PushSessions != nil {
+		switch {
+		case !*req.PushSessions:
+			conn.PushSessionsSince = nil
+		case conn.PushSessionsSince == nil:
+			since := s.now().UTC()
+			conn.PushSessionsSince = &since
+		}
+	}
+
+	if err := s.repo.UpdateSettings(ctx, conn); err != nil {
+		return nil, fmt.Errorf("failed to update settings: %w", err)
+	}
+	return conn, nil
+}
+
+// Disconnect revokes fitapi's access on Strava and deletes the stored tokens; jobs still
+// queued find the connection gone and do nothing. Sessions already synced are kept. Revoking is best effort so a user can always disconnect.
+func (s *StravaService) Disconnect(ctx context.Context, userID string) error {
+	conn, err := s.GetConnection(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if !conn.ReauthRequired {
+		if err := s.client.Deauthorize(ctx, conn.AccessToken); err != nil && !errors.Is(err, strava.ErrInvalidGrant) {
+			log.Printf("Failed to revoke Strava access for user %s: %v", userID, err)
+		}
+	}
+
+	if err := s.repo.DeleteConnection(ctx, userID, models.IntegrationProviderStrava); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrStravaNotConnected
+		}
+		return fmt.Errorf("failed to delete connection: %w", err)
+	}
+	return nil
+}
+
+// Sync pulls the user's recent Strava activities and queues pushes of their new sessions now
+func (s *StravaService) Sync(ctx context.Context, userID string) (*models.IntegrationSyncResult, error) {
+	conn, err := s.GetConnection(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.syncConnection(ctx, conn)
+}
+
+// VerifySubscription answers the validation request Strava sends when the webhook
+// subscription is created, returning the challenge to echo
+func (s *StravaService) VerifySubscription(mode string, verifyToken string, challenge string) (string, error) {
+	if mode != "subscribe" || challenge == "" || s.webhook.VerifyToken == "" || verifyToken != s.webhook.VerifyToken {
+		return "", ErrInvalidStravaWebhook
+	}
+	return challenge, nil
+}
+
+// HandleEvent queues the work for a webhook event. Strava does not sign its events, so they
+// are only hints: activity events of connected athletes who pull activities become import or
+// delete jobs that check the activity with the athlete's token first, and an athlete revoking
+// access has the connection's token checked in the background. Events for unknown athletes
+// are ignored.
+func (s *StravaService) HandleEvent(ctx context.Context, event *models.StravaWebhookEvent) error {
+	if s.webhook.SubscriptionID == 0 || event.SubscriptionID != s.webhook.SubscriptionID {
+		return ErrInvalidStravaWebhook
+	}
+
+	conn, err := s.repo.FindConnectionByAccount(ctx, models.IntegrationProviderStrava, strconv.FormatInt(event.OwnerID, 10))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return fmt.Errorf("failed to find connection: %w", err)
+	}
+
+	switch event.ObjectType {
+	case "athlete":
+		if authorized, ok := event.Updates["authorized"]; ok && fmt.Sprint(authorized) == "false" && !conn.ReauthRequired {
+			s.run(func() {
+				ctx, cancel := context.WithTimeout(context.Background(), stravaSyncTimeout)
+				defer cancel()
+				if err := s.checkAccess(ctx, conn); err != nil {
+					log.Printf("Strava access check for user %s failed: %v", conn.UserID, err)
+				}
+			})
+		}
+		return nil
+	case "activity":
+		if !conn.PullActivities {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	kind := models.JobKindStravaImportActivity
+	if event.AspectType == "delete" {
+		kind = models.JobKindStravaDeleteActivity
+	}
+	payload := &stravaJob{ConnectionID: conn.ID, ObjectID: strconv.FormatInt(event.ObjectID, 10)}
+	if _, err := s.jobs.Enqueue(ctx, kind, conn.UserID, payload); err != nil {
+		return fmt.Errorf("failed to queue event: %w", err)
+	}
+	return nil
+}
+
+// checkAccess refreshes the connection's token, pausing the connection until the user
+// connects again only if Strava rejects it
+func (s *StravaService) checkAccess(ctx context.Context, conn *models.IntegrationConnection) error {
+	err := s.refreshToken(ctx, conn)
+	if errors.Is(err, strava.ErrInvalidGrant) {
+		if err := s.repo.MarkSyncFailed(ctx, conn.ID, ErrStravaReauthRequired.Error(), true); err != nil {
+			return fmt.Errorf("failed to record revoked access: %w", err)
+		}
+		return nil
+	}
+	return err
+}
+
+// Start syncs connections not synced within interval, every interval, until ctx is cancelled
+func (s *StravaService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.syncDue(ctx, interval)
+			}
+		}
+	}()
+}
+
+// syncDue syncs one batch of connections whose last sync is older than interval
+func (s *StravaService) syncDue(ctx context.Context, interval time.Duration) {
+	connections, err := s.repo.FindDueConnections(ctx, models.IntegrationProviderStrava, s.now().Add(-interval), stravaSyncBatch)
+	if err != nil {
+		log.Printf("Strava sync failed to list connections: %v", err)
+		return
+	}
+
+	for _, conn := range connections {
+		if ctx.Err() != nil {
+			return
+		}
+		syncCtx, cancel := context.WithTimeout(ctx, stravaSyncTimeout)
+		if _, err := s.syncConnection(syncCtx, conn); err != nil {
+			log.Printf("Strava sync for user %s failed: %v", conn.UserID, err)
+		}
+		cancel()
+	}
+}
+
+// syncConnection pulls activities since shortly before the last successful sync and queues
+// pushes of sessions completed since. Failures are recorded on the connection; revoked
+// access pauses background syncs until the user connects again.
+func (s *StravaService) syncConnection(ctx context.Context, conn *models.IntegrationConnection) (*models.IntegrationSyncResult, error) {
+	result, err := s.sync(ctx, conn)
+	if err != nil {
+		reauth := errors.Is(err, strava.ErrInvalidGrant)
+		if reauth {
+			err = ErrStravaReauthRequired
+		}
+		if markErr := s.repo.MarkSyncFailed(ctx, conn.ID, err.Error(), reauth); markErr != nil {
+			log.Printf("Failed to record Strava sync failure for user %s: %v", conn.UserID, markErr)
+		}
+		return nil, err
+	}
+
+	if err := s.repo.MarkSynced(ctx, conn.ID, result.To); err != nil {
+		return nil, fmt.Errorf("failed to record sync: %w", err)
+	}
+	return result, nil
+}
+
+func (s *StravaService) sync(ctx context.Context, conn *models.IntegrationConnection) (*models.IntegrationSyncResult, error) {
+	now := s.now().UTC()
+	result := &models.IntegrationSyncResult{
+		Provider: models.IntegrationProviderStrava,
+		From:     now.Add(-stravaInitialSync),
+		To:       now,
+	}
+	if conn.LastSyncedAt != nil {
+		result.From = conn.LastSyncedAt.Add(-stravaSyncOverlap)
+	}
+
+	if conn.PullActivities {
+		if err := s.ensureToken(ctx, conn); err != nil {
+			return nil, err
+		}
+
+		activities, err := s.client.Activities(ctx, conn.AccessToken, result.From, result.To)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch activities: %w", err)
+		}
+
+		var external []*models.ExternalSession
+		for _, activity := range activities {
+			session, err := s.externalSession(ctx, conn, activity)
+			if err != nil {
+				return nil, err
+			}
+			if session != nil {
+				external = append(external, session)
+			}
+		}
+		if len(external) > 0 {
+			created, updated, err := s.repo.UpsertSessions(ctx, conn.UserID, models.IntegrationProviderStrava, external)
+			if err != nil {
+				return nil, fmt.Errorf("failed to store sessions: %w", err)
+			}
+			result.SessionsCreated, result.SessionsUpdated = created, updated
+			result.SessionsKept = len(external) - created - updated
+			s.publishImport(ctx, conn.UserID, created, updated)
+		}
+	}
+
+	queued, err := s.repo.EnqueuePushes(ctx, conn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to queue pushes: %w", err)
+	}
+	result.PushesQueued = queued
+
+	return result, nil
+}
+
+// publishImport tells the user's clients about sessions pulled from Strava, if any changed
+func (s *StravaService) publishImport(ctx context.Context, userID string, created, updated int) {
+	if created+updated == 0 {
+		return
+	}
+	finished := &models.ImportFinishedEvent{
+		Source:          models.IntegrationProviderStrava,
+		SessionsCreated: created,
+		SessionsUpdated: updated,
+	}
+	if err := s.publisher.Publish(ctx, userID, models.UserEventImportFinished, finished); err != nil {
+		log.Printf("Failed to publish Strava import event for user %s: %v", userID, err)
+	}
+}
+
+// stravaSessionTypes maps Strava sport types to session types; everything else is cardio
+var stravaSessionTypes = map[string]string{
+	"WeightTraining": models.SessionTypeStrength,
+	"Crossfit":       models.SessionTypeStrength,
+	"Workout":        models.SessionTypeStrength,
+	"Yoga":           models.SessionTypeMobility,
+	"Pilates":        models.SessionTypeMobility,
+}
+
+// externalSession converts an activity into a session to store, or nil for activities that
+// have no duration or that fitapi pushed itself
+func (s *StravaService) externalSession(ctx context.Context, conn *models.IntegrationConnection, activity *strava.Activity) (*models.ExternalSession, error) {
+	if activity.ElapsedSeconds <= 0 {
+		return nil, nil
+	}
+
+	id := strconv.FormatInt(activity.ID, 10)
+	pushed, err := s.repo.IsPushedActivity(ctx, conn.ID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check pushed activities: %w", err)
+	}
+	if pushed {
+		return nil, nil
+	}
+
+	name := strings.TrimSpace(activity.Name)
+	if name == "" {
+		name = activity.SportType
+	}
+	sessionType, ok := stravaSessionTypes[activity.SportType]
+	if !ok {
+		sessionType = models.SessionTypeCardio
+	}
+
+	return &models.ExternalSession{
+		ExternalID:  id,
+		Name:        name,
+		Notes:       activity.Description,
+		Type:        sessionType,
+		StartedAt:   activity.StartDate,
+		CompletedAt: activity.StartDate.Add(time.Duration(activity.ElapsedSeconds) * time.Second),
+	}, nil
+}
+
+// connectionJob turns a Strava job's work into a handler that loads the job's connection
+// first. Jobs of a connection deleted since they were queued have nothing left to do;
+// revoked access pauses the connection until the user connects again, and the job is
+// retried in case they do.
+func (s *StravaService) connectionJob(run func(ctx context.Context, conn *models.IntegrationConnection, objectID string) error) jobs.Handler {
+	return func(ctx context.Context, job *models.Job) error {
+		var payload stravaJob
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return jobs.Permanent(fmt.Errorf("invalid strava job: %w", err))
+		}
+
+		conn, err := s.repo.FindConnectionByID(ctx, payload.ConnectionID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to load connection: %w", err)
+		}
+		if conn.ReauthRequired {
+			return ErrStravaReauthRequired
+		}
+
+		err = run(ctx, conn, payload.ObjectID)
+		if errors.Is(err, strava.ErrInvalidGrant) {
+			if markErr := s.repo.MarkSyncFailed(ctx, conn.ID, ErrStravaReauthRequired.Error(), true); markErr != nil {
+				log.Printf("Failed to record revoked Strava access for user %s: %v", conn.UserID, markErr)
+			}
+		}
+		return err
+	}
+}
+
+// importActivity pulls in an activity a webhook event announced as created or updated
+func (s *StravaService) importActivity(ctx context.Context, conn *models.IntegrationConnection, objectID string) error {
+	if !conn.PullActivities {
+		return nil
+	}
+	activityID, err := strconv.ParseInt(objectID, 10, 64)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid activity id %q", objectID))
+	}
+	if err := s.ensureToken(ctx, conn); err != nil {
+		return err
+	}
+
+	activity, err := s.client.Activity(ctx, conn.AccessToken, activityID)
+	if errors.Is(err, strava.ErrNotFound) {
+		return nil // Deleted or made private since the event
+	}
+	if err != nil {
+		return fmt.Errorf("failed to fetch activity: %w", err)
+	}
+	session, err := s.externalSession(ctx, conn, activity)
+	if err != nil || session == nil {
+		return err
+	}
+
+	created, updated, err := s.repo.UpsertSessions(ctx, conn.UserID, models.IntegrationProviderStrava, []*models.ExternalSession{session})
+	if err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	s.publishImport(ctx, conn.UserID, created, updated)
+	return nil
+}
+
+// deleteActivity deletes the session of an activity a webhook event announced as deleted,
+// once Strava confirms the activity is gone
+func (s *StravaService) deleteActivity(ctx context.Context, conn *models.IntegrationConnection, objectID string) error {
+	activityID, err := strconv.ParseInt(objectID, 10, 64)
+	if err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid activity id %q", objectID))
+	}
+	if err := s.ensureToken(ctx, conn); err != nil {
+		return err
+	}
+
+	_, err = s.client.Activity(ctx, conn.AccessToken, activityID)
+	if err == nil {
+		return jobs.Permanent(errors.New("activity still exists on strava"))
+	}
+	if !errors.Is(err, strava.ErrNotFound) {
+		return fmt.Errorf("failed to fetch activity: %w", err)
+	}
+	if _, err := s.repo.DeleteExternalSession(ctx, conn.UserID, models.IntegrationProviderStrava, objectID); err != nil {
+		return fmt.Errorf("failed to delete session: %w", err)
+	}
+	return nil
+}
+
+// pushSession creates a Strava activity for a completed session and records it, so that
+// the activity is not pulled back in as a new session
+func (s *StravaService) pushSession(ctx context.Context, conn *models.IntegrationConnection, sessionID string) error {
+	if conn.PushSessionsSince == nil {
+		return jobs.Permanent(errors.New("pushing sessions was turned off"))
+	}
+	session, err := s.repo.FindPushSession(ctx, sessionID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return jobs.Permanent(errors.New("session was deleted"))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get session: %w", err)
+	}
+	elapsed := int(session.CompletedAt.Sub(session.StartedAt).Seconds())
+	if elapsed <= 0 {
+		return jobs.Permanent(errors.New("session has no duration"))
+	}
+
+	if err := s.ensureToken(ctx, conn); err != nil {
+		return err
+	}
+	activity := &strava.NewActivity{
+		Name:           "Workout",
+		StartDate:      session.StartedAt,
+		ElapsedSeconds: elapsed,
+	}
+	if session.Name != nil && strings.TrimSpace(*session.Name) != "" {
+		activity.Name = strings.TrimSpace(*session.Name)
+	}
+	switch session.Type {
+	case models.SessionTypeMobility:
+		activity.SportType = "Yoga"
+	case models.SessionTypeClass, models.SessionTypeCustom:
+		activity.SportType = "Workout"
+	default:
+		activity.SportType = strava.SportType(activity.Name, session.Type == models.SessionTypeCardio)
+	}
+	if session.Notes != nil {
+		activity.Description = *session.Notes
+	}
+	if session.DistanceM != nil {
+		activity.DistanceM = *session.DistanceM
+	}
+
+	created, err := s.client.CreateActivity(ctx, conn.AccessToken, activity)
+	if err != nil {
+		return fmt.Errorf("failed to create activity: %w", err)
+	}
+	// Retrying would push the session twice
+	if err := s.repo.RecordPush(ctx, conn.ID, sessionID, strconv.FormatInt(created, 10)); err != nil {
+		return jobs.Permanent(fmt.Errorf("failed to record pushed activity %d: %w", created, err))
+	}
+	return nil
+}
+
+// ensureToken refreshes the access token a minute before it expires and stores the new pair
+func (s *StravaService) ensureToken(ctx context.Context, conn *models.IntegrationConnection) error {
+	if conn.TokenExpiresAt.After(s.now().Add(time.Minute)) {
+		return nil
+	}
+	return s.refreshToken(ctx, conn)
+}
+
+// refreshToken exchanges the refresh token for a new pair and stores it
+func (s *StravaService) refreshToken(ctx context.Context, conn *models.IntegrationConnection) error {
+	token, err := s.client.Refresh(ctx, conn.RefreshToken)
+	if err != nil {
+		return fmt.Errorf("failed to refresh token: %w", err)
+	}
+	conn.AccessToken, conn.TokenExpiresAt = token.AccessToken, token.ExpiresAt
+	if token.RefreshToken != "" {
+		conn.RefreshToken = token.RefreshToken
+	}
+	if err := s.repo.UpdateTokens(ctx, conn); err != nil {
+		return fmt.Errorf("failed to save refreshed token: %w", err)
+	}
+	return nil
+}
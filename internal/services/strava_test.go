@@ -0,0 +1,409 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/strava"
+)
+
+// fakeStrava is an in-memory StravaClient
+type fakeStrava struct {
+	token       *strava.Token
+	activities  []*strava.Activity
+	activityErr error
+	refreshErr  error
+	created     *strava.NewActivity
+}
+
+func (f *fakeStrava) Configured() bool { return true }
+func (f *fakeStrava) AuthCodeURL(state string) string {
+	return "https://www.strava.example.com/oauth/authorize?state=" + state
+}
+
+func (f *fakeStrava) Exchange(ctx context.Context, code string) (*strava.Token, error) {
+	if code != "good-code" {
+		return nil, strava.ErrInvalidGrant
+	}
+	return f.token, nil
+}
+
+func (f *fakeStrava) Refresh(ctx context.Context, refreshToken string) (*strava.Token, error) {
+	if f.refreshErr != nil {
+		return nil, f.refreshErr
+	}
+	return f.token, nil
+}
+
+func (f *fakeStrava) Deauthorize(ctx context.Context, accessToken string) error { return nil }
+
+func (f *fakeStrava) Activities(ctx context.Context, accessToken string, after, before time.Time) ([]*strava.Activity, error) {
+	return f.activities, nil
+}
+
+func (f *fakeStrava) Activity(ctx context.Context, accessToken string, id int64) (*strava.Activity, error) {
+	if f.activityErr != nil {
+		return nil, f.activityErr
+	}
+	for _, a := range f.activities {
+		if a.ID == id {
+			return a, nil
+		}
+	}
+	return nil, strava.ErrNotFound
+}
+
+func (f *fakeStrava) CreateActivity(ctx context.Context, accessToken string, activity *strava.NewActivity) (int64, error) {
+	f.created = activity
+	return 555, nil
+}
+
+var stravaNow = time.Date(2026, 5, 13, 12, 0, 0, 0, time.UTC)
+
+func newTestStravaService(repo repositories.IntegrationRepository, jobRepo repositories.JobRepository, client *fakeStrava) *StravaService {
+	service := NewStravaService(repo, client, []byte("test-secret"), StravaWebhook{VerifyToken: "verify", SubscriptionID: 42}, events.NewBus(), jobs.NewQueue(jobRepo))
+	service.now = func() time.Time { return stravaNow }
+	service.run = func(task func()) {}
+	return service
+}
+
+func stravaConnection() *models.IntegrationConnection {
+	athleteID := "134815"
+	return &models.IntegrationConnection{
+		ID:                "conn-1",
+		UserID:            "user-123",
+		Provider:          models.IntegrationProviderStrava,
+		ExternalAccountID: &athleteID,
+		AccessToken:       "access",
+		RefreshToken:      "refresh",
+		TokenExpiresAt:    stravaNow.Add(time.Hour),
+		PullActivities:    true,
+	}
+}
+
+// runStravaJob runs the work of a Strava job on an object of conn the way the queue would
+func runStravaJob(service *StravaService, repo *repositories.MockIntegrationRepository, conn *models.IntegrationConnection, run func(context.Context, *models.IntegrationConnection, string) error, objectID string) error {
+	if repo.FindConnectionByIDFunc == nil {
+		repo.FindConnectionByIDFunc = func(ctx context.Context, id string) (*models.IntegrationConnection, error) {
+			return conn, nil
+		}
+	}
+	payload, _ := json.Marshal(&stravaJob{ConnectionID: conn.ID, ObjectID: objectID})
+	job := &models.Job{ID: "job-1", Payload: payload, Attempts: 1, MaxAttempts: stravaJobMaxAttempts}
+	return service.connectionJob(run)(context.Background(), job)
+}
+
+func TestStravaConnect_StoresAthlete(t *testing.T) {
+	client := &fakeStrava{token: &strava.Token{AccessToken: "access", RefreshToken: "refresh", ExpiresAt: stravaNow.Add(6 * time.Hour), AthleteID: 134815}}
+	var saved *models.IntegrationConnection
+	mockRepo := &repositories.MockIntegrationRepository{
+		SaveConnectionFunc: func(ctx context.Context, conn *models.IntegrationConnection) error {
+			saved = conn
+			return nil
+		},
+	}
+
+	service := newTestStravaService(mockRepo, &repositories.MockJobRepository{}, client)
+
+	auth, err := service.Authorize("user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.Connect(context.Background(), "user-456", &models.ConnectIntegrationRequest{Code: "good-code", State: auth.State}); !errors.Is(err, ErrInvalidOAuthState) {
+		t.Errorf("Expected another user's state to be rejected, got %v", err)
+	}
+	if _, err := service.Connect(context.Background(), "user-123", &models.ConnectIntegrationRequest{Code: "bad-code", State: auth.State}); !errors.Is(err, ErrStravaAuthFailed) {
+		t.Errorf("Expected ErrStravaAuthFailed, got %v", err)
+	}
+
+	if _, err := service.Connect(context.Background(), "user-123", &models.ConnectIntegrationRequest{Code: "good-code", State: auth.State}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved == nil || saved.Provider != models.IntegrationProviderStrava || saved.ExternalAccountID == nil || *saved.ExternalAccountID != "134815" {
+		t.Errorf("Expected a strava connection for athlete 134815, got %+v", saved)
+	}
+}
+
+func TestStravaSync_PullsActivitiesAndQueuesPushes(t *testing.T) {
+	client := &fakeStrava{activities: []*strava.Activity{
+		{ID: 1, Name: "Morning Run", SportType: "Run", StartDate: stravaNow.Add(-3 * time.Hour), ElapsedSeconds: 1800},
+		{ID: 2, Name: "Gym", SportType: "WeightTraining", StartDate: stravaNow.Add(-5 * time.Hour), ElapsedSeconds: 3600},
+		{ID: 555, Name: "Push day", SportType: "WeightTraining", StartDate: stravaNow.Add(-24 * time.Hour), ElapsedSeconds: 3600},
+	}}
+
+	var upserted []*models.ExternalSession
+	var pushesQueued bool
+	mockRepo := &repositories.MockIntegrationRepository{
+		FindConnectionFunc: func(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error) {
+			conn := stravaConnection()
+			since := stravaNow.Add(-48 * time.Hour)
+			conn.PushSessionsSince = &since
+			return conn, nil
+		},
+		IsPushedActivityFunc: func(ctx context.Context, connectionID string, activityID string) (bool, error) {
+			return activityID == "555", nil
+		},
+		UpsertSessionsFunc: func(ctx context.Context, userID string, source string, sessions []*models.ExternalSession) (int, int, error) {
+			upserted = sessions
+			return len(sessions), 0, nil
+		},
+		EnqueuePushesFunc: func(ctx context.Context, conn *models.IntegrationConnection) (int64, error) {
+			pushesQueued = conn.PushSessionsSince != nil
+			return 2, nil
+		},
+	}
+
+	service := newTestStravaService(mockRepo, &repositories.MockJobRepository{}, client)
+	bus := events.NewBus()
+	userEvents, cancel := bus.Subscribe("user-123")
+	defer cancel()
+	service.publisher = bus
+
+	result, err := service.Sync(context.Background(), "user-123")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(userEvents) != 1 {
+		t.Errorf("Expected one import.finished event, got %d", len(userEvents))
+	}
+	if len(upserted) != 2 {
+		t.Fatalf("Expected the activity fitapi pushed itself to be skipped, got %+v", upserted)
+	}
+	if upserted[0].Type != models.SessionTypeCardio || upserted[1].Type != models.SessionTypeStrength {
+		t.Errorf("Expected a cardio run and a strength session, got %q and %q", upserted[0].Type, upserted[1].Type)
+	}
+	if upserted[0].CompletedAt.Sub(upserted[0].StartedAt) != 30*time.Minute {
+		t.Errorf("Expected a 30 minute run, got %v", upserted[0].CompletedAt.Sub(upserted[0].StartedAt))
+	}
+	if !pushesQueued || result.PushesQueued != 2 || result.SessionsCreated != 2 {
+		t.Errorf("Expected 2 sessions created and 2 pushes queued, got %+v", result)
+	}
+}
+
+func TestStravaHandleEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      models.StravaWebhookEvent
+		pull       bool
+		refreshErr error
+		wantErr    error
+		wantJob    string
+		wantReauth bool
+	}{
+		{"activity created", models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "create", OwnerID: 134815, SubscriptionID: 42}, true, nil, nil, models.JobKindStravaImportActivity, false},
+		{"activity updated", models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "update", OwnerID: 134815, SubscriptionID: 42}, true, nil, nil, models.JobKindStravaImportActivity, false},
+		{"activity deleted", models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "delete", OwnerID: 134815, SubscriptionID: 42}, true, nil, nil, models.JobKindStravaDeleteActivity, false},
+		{"pulling turned off", models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "create", OwnerID: 134815, SubscriptionID: 42}, false, nil, nil, "", false},
+		{"unknown athlete", models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "create", OwnerID: 999, SubscriptionID: 42}, true, nil, nil, "", false},
+		{"other subscription", models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "create", OwnerID: 134815, SubscriptionID: 41}, true, nil, ErrInvalidStravaWebhook, "", false},
+		{"access revoked", models.StravaWebhookEvent{ObjectType: "athlete", ObjectID: 134815, AspectType: "update", OwnerID: 134815, SubscriptionID: 42, Updates: map[string]any{"authorized": "false"}}, true, strava.ErrInvalidGrant, nil, "", true},
+		{"forged revocation", models.StravaWebhookEvent{ObjectType: "athlete", ObjectID: 134815, AspectType: "update", OwnerID: 134815, SubscriptionID: 42, Updates: map[string]any{"authorized": "false"}}, true, nil, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var job *models.Job
+			var reauth bool
+			mockRepo := &repositories.MockIntegrationRepository{
+				FindConnectionByAccountFunc: func(ctx context.Context, provider string, accountID string) (*models.IntegrationConnection, error) {
+					if accountID != "134815" {
+						return nil, pgx.ErrNoRows
+					}
+					conn := stravaConnection()
+					conn.PullActivities = tt.pull
+					return conn, nil
+				},
+				MarkSyncFailedFunc: func(ctx context.Context, id string, reason string, reauthRequired bool) error {
+					reauth = reauthRequired
+					return nil
+				},
+			}
+
+			jobRepo := &repositories.MockJobRepository{
+				EnqueueFunc: func(ctx context.Context, j *models.Job) error {
+					job = j
+					return nil
+				},
+			}
+
+			service := newTestStravaService(mockRepo, jobRepo, &fakeStrava{token: &strava.Token{AccessToken: "new", RefreshToken: "refresh"}, refreshErr: tt.refreshErr})
+			service.run = func(task func()) { task() }
+
+			err := service.HandleEvent(context.Background(), &tt.event)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			switch {
+			case tt.wantJob == "" && job != nil:
+				t.Errorf("Expected no job, got %+v", job)
+			case tt.wantJob != "" && (job == nil || job.Kind != tt.wantJob || string(job.Payload) != `{"connection_id":"conn-1","object_id":"7"}`):
+				t.Errorf("Expected a %s job for activity 7, got %+v", tt.wantJob, job)
+			}
+			if reauth != tt.wantReauth {
+				t.Errorf("Expected reauth %v, got %v", tt.wantReauth, reauth)
+			}
+		})
+	}
+}
+
+func TestStravaHandleEvent_RequiresSubscription(t *testing.T) {
+	service := newTestStravaService(&repositories.MockIntegrationRepository{}, &repositories.MockJobRepository{}, &fakeStrava{})
+	service.webhook.SubscriptionID = 0
+
+	event := &models.StravaWebhookEvent{ObjectType: "activity", ObjectID: 7, AspectType: "delete", OwnerID: 134815}
+	if err := service.HandleEvent(context.Background(), event); !errors.Is(err, ErrInvalidStravaWebhook) {
+		t.Errorf("Expected events rejected without a subscription, got %v", err)
+	}
+}
+
+func TestStravaDeleteActivity_OnlyGoneActivities(t *testing.T) {
+	for _, exists := range []bool{true, false} {
+		var deleted bool
+		mockRepo := &repositories.MockIntegrationRepository{
+			DeleteExternalSessionFunc: func(ctx context.Context, userID string, provider string, externalID string) (bool, error) {
+				deleted = true
+				return true, nil
+			},
+		}
+		client := &fakeStrava{}
+		if exists {
+			client.activities = []*strava.Activity{{ID: 7, Name: "Morning Run", ElapsedSeconds: 1800}}
+		}
+
+		service := newTestStravaService(mockRepo, &repositories.MockJobRepository{}, client)
+		err := runStravaJob(service, mockRepo, stravaConnection(), service.deleteActivity, "7")
+
+		if exists != jobs.IsPermanent(err) {
+			t.Errorf("Activity exists on Strava: %v, expected the job dead-lettered: %v, got %v", exists, exists, err)
+		}
+		if deleted == exists {
+			t.Errorf("Activity exists on Strava: %v, expected session deleted: %v", exists, !exists)
+		}
+	}
+}
+
+func TestStravaVerifySubscription(t *testing.T) {
+	service := newTestStravaService(&repositories.MockIntegrationRepository{}, &repositories.MockJobRepository{}, &fakeStrava{})
+
+	challenge, err := service.VerifySubscription("subscribe", "verify", "15f7d1a91c1f40f8")
+	if err != nil || challenge != "15f7d1a91c1f40f8" {
+		t.Errorf("Expected the challenge echoed, got %q, %v", challenge, err)
+	}
+	if _, err := service.VerifySubscription("subscribe", "guess", "15f7d1a91c1f40f8"); !errors.Is(err, ErrInvalidStravaWebhook) {
+		t.Errorf("Expected ErrInvalidStravaWebhook for a wrong verify token, got %v", err)
+	}
+}
+
+func TestStravaPushSession(t *testing.T) {
+	client := &fakeStrava{}
+	name, distance := "Running", 5200.0
+	var activityID string
+	mockRepo := &repositories.MockIntegrationRepository{
+		FindPushSessionFunc: func(ctx context.Context, sessionID string) (*models.PushSession, error) {
+			return &models.PushSession{
+				ID:          sessionID,
+				Name:        &name,
+				Type:        models.SessionTypeCardio,
+				StartedAt:   stravaNow.Add(-time.Hour),
+				CompletedAt: stravaNow.Add(-30 * time.Minute),
+				DistanceM:   &distance,
+			}, nil
+		},
+		RecordPushFunc: func(ctx context.Context, connectionID string, sessionID string, activity string) error {
+			activityID = activity
+			return nil
+		},
+	}
+
+	service := newTestStravaService(mockRepo, &repositories.MockJobRepository{}, client)
+	conn := stravaConnection()
+	since := stravaNow.Add(-24 * time.Hour)
+	conn.PushSessionsSince = &since
+
+	if err := runStravaJob(service, mockRepo, conn, service.pushSession, "session-1"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if client.created == nil || client.created.SportType != "Run" || client.created.ElapsedSeconds != 1800 || client.created.DistanceM != 5200 {
+		t.Fatalf("Expected a 30 minute run pushed, got %+v", client.created)
+	}
+	if activityID != "555" {
+		t.Errorf("Expected the push recorded with activity 555, got %q", activityID)
+	}
+}
+
+func TestStravaImportActivity_Failures(t *testing.T) {
+	tests := []struct {
+		name          string
+		objectID      string
+		err           error
+		deleted       bool
+		wantErr       bool
+		wantPermanent bool
+		wantReauth    bool
+	}{
+		{"fetch fails", "7", errors.New("connection reset"), false, true, false, false},
+		{"rate limited", "7", strava.ErrRateLimited, false, true, false, false},
+		{"access revoked", "7", strava.ErrInvalidGrant, false, true, false, true},
+		{"invalid activity", "seven", nil, false, true, true, false},
+		{"disconnected", "7", errors.New("connection reset"), true, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var reauth bool
+			mockRepo := &repositories.MockIntegrationRepository{
+				MarkSyncFailedFunc: func(ctx context.Context, id string, reason string, reauthRequired bool) error {
+					reauth = reauthRequired
+					return nil
+				},
+			}
+			if tt.deleted {
+				mockRepo.FindConnectionByIDFunc = func(ctx context.Context, id string) (*models.IntegrationConnection, error) {
+					return nil, pgx.ErrNoRows
+				}
+			}
+
+			service := newTestStravaService(mockRepo, &repositories.MockJobRepository{}, &fakeStrava{activityErr: tt.err})
+			err := runStravaJob(service, mockRepo, stravaConnection(), service.importActivity, tt.objectID)
+
+			if (err != nil) != tt.wantErr || jobs.IsPermanent(err) != tt.wantPermanent {
+				t.Errorf("Expected error %v (permanent %v), got %v", tt.wantErr, tt.wantPermanent, err)
+			}
+			if reauth != tt.wantReauth {
+				t.Errorf("Expected reauth %v, got %v", tt.wantReauth, reauth)
+			}
+		})
+	}
+}
+
+func TestStravaUpdateSettings_PushesFromNow(t *testing.T) {
+	var saved *models.IntegrationConnection
+	mockRepo := &repositories.MockIntegrationRepository{
+		FindConnectionFunc: func(ctx context.Context, userID string, provider string) (*models.IntegrationConnection, error) {
+			return stravaConnection(), nil
+		},
+		UpdateSettingsFunc: func(ctx context.Context, conn *models.IntegrationConnection) error {
+			saved = conn
+			return nil
+		},
+	}
+
+	service := newTestStravaService(mockRepo, &repositories.MockJobRepository{}, &fakeStrava{})
+
+	push, pull := true, false
+	if _, err := service.UpdateSettings(context.Background(), "user-123", &models.UpdateIntegrationSettingsRequest{PushSessions: &push, PullActivities: &pull}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.PushSessionsSince == nil || !saved.PushSessionsSince.Equal(stravaNow) || saved.PullActivities {
+		t.Errorf("Expected pushing from now and pulling off, got %+v", saved)
+	}
+}
@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// TokenRevocationService keeps an in-memory cache of revoked token IDs backed by the database.
+// AuthRequired consults the cache on every request, so lookups never touch the database;
+// Refresh reloads the cache so revocations made by other instances are picked up.
+type TokenRevocationService struct {
+	repo repositories.RevokedTokenRepository
+	now  func() time.Time
+
+	mu      sync.RWMutex
+	revoked map[string]time.Time // token ID -> token expiry
+}
+
+// NewTokenRevocationService creates a new token revocation service
+func NewTokenRevocationService(repo repositories.RevokedTokenRepository) *TokenRevocationService {
+	return &TokenRevocationService{
+		repo:    repo,
+		now:     time.Now,
+		revoked: make(map[string]time.Time),
+	}
+}
+
+// Revoke rejects a token until its natural expiry
+func (s *TokenRevocationService) Revoke(ctx context.Context, tokenID string, userID string, expiresAt time.Time) error {
+	token := &models.RevokedToken{
+		TokenID:   tokenID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+
+	s.mu.Lock()
+	s.revoked[tokenID] = expiresAt
+	s.mu.Unlock()
+
+	return nil
+}
+
+// IsRevoked reports whether a token ID has been revoked and has not yet expired
+func (s *TokenRevocationService) IsRevoked(tokenID string) bool {
+	s.mu.RLock()
+	expiresAt, ok := s.revoked[tokenID]
+	s.mu.RUnlock()
+
+	return ok && s.now().Before(expiresAt)
+}
+
+// Refresh purges expired revocations and reloads the cache from the database
+func (s *TokenRevocationService) Refresh(ctx context.Context) error {
+	if _, err := s.repo.DeleteExpired(ctx); err != nil {
+		return fmt.Errorf("failed to purge expired revocations: %w", err)
+	}
+
+	tokens, err := s.repo.FindUnexpired(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load revocations: %w", err)
+	}
+
+	revoked := make(map[string]time.Time, len(tokens))
+	for _, token := range tokens {
+		revoked[token.TokenID] = token.ExpiresAt
+	}
+
+	s.mu.Lock()
+	s.revoked = revoked
+	s.mu.Unlock()
+
+	return nil
+}
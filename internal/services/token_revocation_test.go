@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestTokenRevocation_RevokeAndCheck(t *testing.T) {
+	service := NewTokenRevocationService(&repositories.MockRevokedTokenRepository{})
+
+	expiresAt := time.Now().Add(time.Hour)
+	if err := service.Revoke(context.Background(), "jti-1", "user-123", expiresAt); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !service.IsRevoked("jti-1") {
+		t.Error("Expected token to be revoked")
+	}
+
+	if service.IsRevoked("jti-2") {
+		t.Error("Expected unrelated token not to be revoked")
+	}
+}
+
+func TestTokenRevocation_ExpiredEntryIgnored(t *testing.T) {
+	service := NewTokenRevocationService(&repositories.MockRevokedTokenRepository{})
+
+	expiresAt := time.Now().Add(time.Minute)
+	if err := service.Revoke(context.Background(), "jti-1", "user-123", expiresAt); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	service.now = func() time.Time { return expiresAt.Add(time.Second) }
+
+	if service.IsRevoked("jti-1") {
+		t.Error("Expected expired revocation to be ignored")
+	}
+}
+
+func TestTokenRevocation_RefreshLoadsFromRepository(t *testing.T) {
+	mockRepo := &repositories.MockRevokedTokenRepository{
+		FindUnexpiredFunc: func(ctx context.Context) ([]*models.RevokedToken, error) {
+			return []*models.RevokedToken{
+				{TokenID: "jti-other-instance", UserID: "user-123", ExpiresAt: time.Now().Add(time.Hour)},
+			}, nil
+		},
+	}
+
+	service := NewTokenRevocationService(mockRepo)
+
+	if err := service.Refresh(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !service.IsRevoked("jti-other-instance") {
+		t.Error("Expected token revoked by another instance to be loaded")
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/progression"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrTrainingMaxNotFound    = domainerr.New(domainerr.NotFound, "training max not found")
+	ErrInvalidTrainingMaxName = domainerr.New(domainerr.Validation, "training max name must be 1-50 characters")
+)
+
+// TrainingMaxService manages training maxes and resolves percentage-based loads
+type TrainingMaxService struct {
+	repo repositories.TrainingMaxRepository
+}
+
+// NewTrainingMaxService creates a new training max service
+func NewTrainingMaxService(repo repositories.TrainingMaxRepository) *TrainingMaxService {
+	return &TrainingMaxService{repo: repo}
+}
+
+// normalizeTrainingMaxName lowercases names so "Squat" and "squat" refer to the same max
+func normalizeTrainingMaxName(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" || len(name) > 50 {
+		return "", ErrInvalidTrainingMaxName
+	}
+	return name, nil
+}
+
+// ListTrainingMaxes retrieves all of the user's training maxes
+func (s *TrainingMaxService) ListTrainingMaxes(ctx context.Context, userID string) ([]*models.TrainingMax, error) {
+	tms, err := s.repo.FindByUser(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list training maxes: %w", err)
+	}
+
+	return tms, nil
+}
+
+// SetTrainingMax creates or updates the user's training max with the given name
+func (s *TrainingMaxService) SetTrainingMax(ctx context.Context, userID string, name string, req *models.SetTrainingMaxRequest) (*models.TrainingMax, error) {
+	name, err := normalizeTrainingMaxName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tm := &models.TrainingMax{
+		UserID:     userID,
+		Name:       name,
+		ExerciseID: req.ExerciseID,
+		WeightKg:   req.WeightKg,
+	}
+	if err := s.repo.Upsert(ctx, tm); err != nil {
+		return nil, fmt.Errorf("failed to save training max: %w", err)
+	}
+
+	return tm, nil
+}
+
+// DeleteTrainingMax removes the user's training max with the given name
+func (s *TrainingMaxService) DeleteTrainingMax(ctx context.Context, userID string, name string) error {
+	name, err := normalizeTrainingMaxName(name)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.Delete(ctx, userID, name); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTrainingMaxNotFound
+		}
+		return fmt.Errorf("failed to delete training max: %w", err)
+	}
+
+	return nil
+}
+
+// GetTrainingMax retrieves the user's training max with the given name
+func (s *TrainingMaxService) GetTrainingMax(ctx context.Context, userID string, name string) (*models.TrainingMax, error) {
+	name, err := normalizeTrainingMaxName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	tm, err := s.repo.FindByName(ctx, userID, name)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrTrainingMaxNotFound
+		}
+		return nil, fmt.Errorf("failed to get training max: %w", err)
+	}
+
+	return tm, nil
+}
+
+// ResolveLoad converts a template prescription ("75% of squat") into an absolute weight
+// using the user's current training max, rounded to the nearest plate increment.
+// Class sessions and next-session prescriptions load template entries that set
+// training_max_name through this.
+func (s *TrainingMaxService) ResolveLoad(ctx context.Context, userID string, name string, percentage float64) (float64, error) {
+	tm, err := s.GetTrainingMax(ctx, userID, name)
+	if err != nil {
+		return 0, err
+	}
+
+	return progression.RoundLoad(tm.WeightKg * percentage / 100), nil
+}
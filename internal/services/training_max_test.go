@@ -0,0 +1,80 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestSetTrainingMax_NormalizesName(t *testing.T) {
+	var saved *models.TrainingMax
+	mockRepo := &repositories.MockTrainingMaxRepository{
+		UpsertFunc: func(ctx context.Context, tm *models.TrainingMax) error {
+			saved = tm
+			return nil
+		},
+	}
+
+	service := NewTrainingMaxService(mockRepo)
+
+	_, err := service.SetTrainingMax(context.Background(), "user-123", "  Squat ", &models.SetTrainingMaxRequest{WeightKg: 140})
+
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if saved.Name != "squat" || saved.UserID != "user-123" {
+		t.Errorf("Expected normalized name 'squat' for user-123, got %q for %q", saved.Name, saved.UserID)
+	}
+}
+
+func TestSetTrainingMax_InvalidName(t *testing.T) {
+	service := NewTrainingMaxService(&repositories.MockTrainingMaxRepository{})
+
+	_, err := service.SetTrainingMax(context.Background(), "user-123", "   ", &models.SetTrainingMaxRequest{WeightKg: 140})
+
+	if !errors.Is(err, ErrInvalidTrainingMaxName) {
+		t.Errorf("Expected ErrInvalidTrainingMaxName, got %v", err)
+	}
+}
+
+func TestResolveLoad(t *testing.T) {
+	mockRepo := &repositories.MockTrainingMaxRepository{
+		FindByNameFunc: func(ctx context.Context, userID string, name string) (*models.TrainingMax, error) {
+			return &models.TrainingMax{UserID: userID, Name: name, WeightKg: 140}, nil
+		},
+	}
+
+	service := NewTrainingMaxService(mockRepo)
+
+	tests := []struct {
+		percentage float64
+		want       float64
+	}{
+		{75, 105},     // exact
+		{65, 90},      // 91 rounds down to 90
+		{72.5, 102.5}, // 101.5 rounds up to 102.5
+	}
+
+	for _, tt := range tests {
+		load, err := service.ResolveLoad(context.Background(), "user-123", "squat", tt.percentage)
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if load != tt.want {
+			t.Errorf("Expected %v%% of 140kg to resolve to %vkg, got %v", tt.percentage, tt.want, load)
+		}
+	}
+}
+
+func TestResolveLoad_MissingTrainingMax(t *testing.T) {
+	service := NewTrainingMaxService(&repositories.MockTrainingMaxRepository{})
+
+	_, err := service.ResolveLoad(context.Background(), "user-123", "deadlift", 80)
+
+	if !errors.Is(err, ErrTrainingMaxNotFound) {
+		t.Errorf("Expected ErrTrainingMaxNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/cache"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrInvalidTrendMetric = domainerr.New(domainerr.Validation, "unknown metric, expected sessions, volume_kg, cardio_distance_m or body_weight_kg")
+	ErrInvalidTrendRange  = domainerr.New(domainerr.Validation, "from must be before to and the range at most 20 years")
+)
+
+const (
+	// weeklyTrendLimit is the longest range served week by week; longer ranges switch to
+	// monthly points so payloads stay bounded (about 100 weekly or 240 monthly points)
+	weeklyTrendLimit = 2 * 366 * 24 * time.Hour
+
+	// maxTrendYears caps the range of a trend request
+	maxTrendYears = 20
+)
+
+// TrendService serves metric trends, using precomputed monthly snapshots for long ranges
+type TrendService struct {
+	repo     repositories.TrendRepository
+	profiles repositories.ProfileRepository
+	cache    *cache.Cache
+	now      func() time.Time
+}
+
+// NewTrendService creates a new trend service; a nil cache reads every time
+func NewTrendService(repo repositories.TrendRepository, profiles repositories.ProfileRepository, c *cache.Cache) *TrendService {
+	return &TrendService{repo: repo, profiles: profiles, cache: c, now: time.Now}
+}
+
+// GetTrend returns the user's metric between the dates from and to (exclusive) of their
+// timezone. A zero to is tomorrow, so today is included, and a zero from a year before to.
+// Ranges up to two years are aggregated weekly from the live history. Longer ranges are
+// monthly: completed months are read from snapshots and only the current month is
+// aggregated live.
+func (s *TrendService) GetTrend(ctx context.Context, userID string, metric string, from, to time.Time) (*models.Trend, error) {
+	if !slices.Contains(models.TrendMetrics, metric) {
+		return nil, ErrInvalidTrendMetric
+	}
+	loc, err := userLocation(ctx, s.profiles, userID)
+	if err != nil {
+		return nil, err
+	}
+	if to.IsZero() {
+		to = localDate(s.now(), loc).AddDate(0, 0, 1)
+	}
+	if from.IsZero() {
+		from = to.AddDate(-1, 0, 0)
+	}
+	from, to = from.UTC(), to.UTC()
+	if !from.Before(to) || to.After(from.AddDate(maxTrendYears, 0, 0)) {
+		return nil, ErrInvalidTrendRange
+	}
+
+	key := "trend:" + metric + ":" + from.Format(time.RFC3339) + ":" + to.Format(time.RFC3339)
+	return cache.Fetch(ctx, s.cache, analyticsCacheScope(userID), key, analyticsCacheTTL, func() (*models.Trend, error) {
+		return s.trend(ctx, userID, metric, from, to, loc)
+	})
+}
+
+// trend reads a validated trend
+func (s *TrendService) trend(ctx context.Context, userID string, metric string, from, to time.Time, loc *time.Location) (*models.Trend, error) {
+	trend := &models.Trend{
+		Metric:      metric,
+		Granularity: models.TrendGranularityWeek,
+		From:        from.Format("2006-01-02"),
+		To:          to.Format("2006-01-02"),
+	}
+
+	if to.Sub(from) <= weeklyTrendLimit {
+		points, err := s.repo.Points(ctx, userID, metric, models.TrendGranularityWeek, from, to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trend: %w", err)
+		}
+		trend.Points = points
+		return trend, nil
+	}
+
+	trend.Granularity = models.TrendGranularityMonth
+	from = monthStart(from)
+	currentMonth := monthStart(localDate(s.now(), loc))
+
+	trend.Points = []*models.TrendPoint{}
+	if from.Before(currentMonth) {
+		snapshots, err := s.repo.Snapshots(ctx, userID, metric, from, minTime(to, currentMonth))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trend snapshots: %w", err)
+		}
+		trend.Points = append(trend.Points, snapshots...)
+	}
+	if to.After(currentMonth) {
+		live, err := s.repo.Points(ctx, userID, metric, models.TrendGranularityMonth, maxTime(from, currentMonth), to)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get trend: %w", err)
+		}
+		trend.Points = append(trend.Points, live...)
+	}
+
+	return trend, nil
+}
+
+// RefreshSnapshots recomputes the completed months of every user whose history changed
+// since the given time, from the month of their earliest change, and returns when the
+// refresh started so the next one can pick up from there. The zero time rebuilds everything.
+// Months are the user's, in their timezone.
+func (s *TrendService) RefreshSnapshots(ctx context.Context, since time.Time) (time.Time, error) {
+	started := s.now().UTC()
+
+	changes, err := s.repo.FindChangedSince(ctx, since)
+	if err != nil {
+		return since, fmt.Errorf("failed to find changed history: %w", err)
+	}
+
+	for _, change := range changes {
+		loc, err := userLocation(ctx, s.profiles, change.UserID)
+		if err != nil {
+			return since, err
+		}
+		currentMonth := monthStart(localDate(started, loc))
+		var from time.Time
+		if !change.From.IsZero() {
+			from = monthStart(localDate(change.From, loc))
+		}
+		if !from.Before(currentMonth) {
+			continue
+		}
+		if err := s.repo.RefreshSnapshots(ctx, change.UserID, from, currentMonth); err != nil {
+			return since, fmt.Errorf("failed to refresh snapshots for user %s: %w", change.UserID, err)
+		}
+	}
+
+	return started, nil
+}
+
+// RefreshUser rebuilds every completed month of the user's snapshots, for when their
+// history changed without updating it, e.g. a direct database fix
+func (s *TrendService) RefreshUser(ctx context.Context, userID string) error {
+	loc, err := userLocation(ctx, s.profiles, userID)
+	if err != nil {
+		return err
+	}
+	currentMonth := monthStart(localDate(s.now(), loc))
+	if err := s.repo.RefreshSnapshots(ctx, userID, time.Time{}, currentMonth); err != nil {
+		return fmt.Errorf("failed to refresh snapshots for user %s: %w", userID, err)
+	}
+	s.cache.Invalidate(ctx, analyticsCacheScope(userID))
+	return nil
+}
+
+// Start refreshes snapshots now and then every interval until ctx is cancelled
+func (s *TrendService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		since, err := s.repo.LastSnapshotAt(ctx)
+		if err != nil {
+			log.Printf("Trend snapshot refresh failed to read last run: %v", err)
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			next, err := s.RefreshSnapshots(ctx, since)
+			if err != nil {
+				log.Printf("Trend snapshot refresh failed: %v", err)
+			}
+			since = next
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+func monthStart(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,213 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestTrendService(repo repositories.TrendRepository) *TrendService {
+	service := NewTrendService(repo, &repositories.MockProfileRepository{}, nil)
+	service.now = func() time.Time { return time.Date(2025, 6, 18, 9, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func TestGetTrend_ShortRangeIsWeekly(t *testing.T) {
+	var granularity string
+	mockRepo := &repositories.MockTrendRepository{
+		PointsFunc: func(ctx context.Context, userID string, metric string, g string, from, to time.Time) ([]*models.TrendPoint, error) {
+			granularity = g
+			return []*models.TrendPoint{{PeriodStart: "2025-06-09", Value: 3, Samples: 3}}, nil
+		},
+		SnapshotsFunc: func(ctx context.Context, userID string, metric string, from, to time.Time) ([]*models.TrendPoint, error) {
+			t.Fatal("Expected short ranges not to read snapshots")
+			return nil, nil
+		},
+	}
+
+	service := newTestTrendService(mockRepo)
+	from := time.Date(2024, 6, 19, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 19, 0, 0, 0, 0, time.UTC)
+	trend, err := service.GetTrend(context.Background(), "user-123", models.TrendMetricSessions, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if granularity != models.TrendGranularityWeek || trend.Granularity != models.TrendGranularityWeek {
+		t.Errorf("Expected weekly granularity, got %q (repo %q)", trend.Granularity, granularity)
+	}
+	if len(trend.Points) != 1 {
+		t.Errorf("Expected 1 point, got %d", len(trend.Points))
+	}
+}
+
+func TestGetTrend_LongRangeMergesSnapshotsAndCurrentMonth(t *testing.T) {
+	var snapshotFrom, snapshotTo, liveFrom time.Time
+	mockRepo := &repositories.MockTrendRepository{
+		SnapshotsFunc: func(ctx context.Context, userID string, metric string, from, to time.Time) ([]*models.TrendPoint, error) {
+			snapshotFrom, snapshotTo = from, to
+			return []*models.TrendPoint{
+				{PeriodStart: "2021-03-01", Value: 81.5, Samples: 4},
+				{PeriodStart: "2025-05-01", Value: 78.2, Samples: 5},
+			}, nil
+		},
+		PointsFunc: func(ctx context.Context, userID string, metric string, g string, from, to time.Time) ([]*models.TrendPoint, error) {
+			if g != models.TrendGranularityMonth {
+				t.Errorf("Expected live points by month, got %q", g)
+			}
+			liveFrom = from
+			return []*models.TrendPoint{{PeriodStart: "2025-06-01", Value: 77.9, Samples: 2}}, nil
+		},
+	}
+
+	service := newTestTrendService(mockRepo)
+	from := time.Date(2021, 3, 14, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2025, 6, 19, 0, 0, 0, 0, time.UTC)
+	trend, err := service.GetTrend(context.Background(), "user-123", models.TrendMetricBodyWeight, from, to)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if trend.Granularity != models.TrendGranularityMonth {
+		t.Errorf("Expected monthly granularity, got %q", trend.Granularity)
+	}
+	if !snapshotFrom.Equal(time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected snapshots from the start of the month, got %v", snapshotFrom)
+	}
+	currentMonth := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	if !snapshotTo.Equal(currentMonth) || !liveFrom.Equal(currentMonth) {
+		t.Errorf("Expected snapshots to end and live points to start at %v, got %v and %v", currentMonth, snapshotTo, liveFrom)
+	}
+	if len(trend.Points) != 3 || trend.Points[2].PeriodStart != "2025-06-01" {
+		t.Errorf("Expected 2 snapshots followed by the current month, got %+v", trend.Points)
+	}
+}
+
+func TestGetTrend_PastRangeUsesOnlySnapshots(t *testing.T) {
+	mockRepo := &repositories.MockTrendRepository{
+		PointsFunc: func(ctx context.Context, userID string, metric string, g string, from, to time.Time) ([]*models.TrendPoint, error) {
+			t.Fatal("Expected a range of completed months not to aggregate live")
+			return nil, nil
+		},
+	}
+
+	service := newTestTrendService(mockRepo)
+	from := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := service.GetTrend(context.Background(), "user-123", models.TrendMetricVolume, from, to); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+}
+
+func TestGetTrend_Validation(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name    string
+		metric  string
+		from    time.Time
+		to      time.Time
+		wantErr error
+	}{
+		{"unknown metric", "steps", day(2024, 1, 1), day(2025, 1, 1), ErrInvalidTrendMetric},
+		{"empty range", models.TrendMetricSessions, day(2025, 1, 1), day(2025, 1, 1), ErrInvalidTrendRange},
+		{"reversed range", models.TrendMetricSessions, day(2025, 1, 1), day(2024, 1, 1), ErrInvalidTrendRange},
+		{"too long", models.TrendMetricSessions, day(2000, 1, 1), day(2025, 1, 1), ErrInvalidTrendRange},
+	}
+
+	service := newTestTrendService(&repositories.MockTrendRepository{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.GetTrend(context.Background(), "user-123", tt.metric, tt.from, tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestRefreshSnapshots_RebuildsFromEarliestChangedMonth(t *testing.T) {
+	type refresh struct {
+		userID   string
+		from, to time.Time
+	}
+	var refreshed []refresh
+	since := time.Date(2025, 6, 18, 8, 0, 0, 0, time.UTC)
+	mockRepo := &repositories.MockTrendRepository{
+		FindChangedSinceFunc: func(ctx context.Context, s time.Time) ([]*models.TrendChange, error) {
+			if !s.Equal(since) {
+				t.Errorf("Expected changes since %v, got %v", since, s)
+			}
+			return []*models.TrendChange{
+				{UserID: "user-123", From: time.Date(2023, 2, 11, 18, 30, 0, 0, time.UTC)},
+				{UserID: "user-456", From: time.Date(2025, 6, 17, 7, 0, 0, 0, time.UTC)},
+			}, nil
+		},
+		RefreshSnapshotsFunc: func(ctx context.Context, userID string, from, to time.Time) error {
+			refreshed = append(refreshed, refresh{userID, from, to})
+			return nil
+		},
+	}
+
+	service := newTestTrendService(mockRepo)
+	next, err := service.RefreshSnapshots(context.Background(), since)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !next.Equal(service.now()) {
+		t.Errorf("Expected next refresh since %v, got %v", service.now(), next)
+	}
+	// Changes in the current month have no snapshot to rebuild
+	if len(refreshed) != 1 {
+		t.Fatalf("Expected 1 refresh, got %d", len(refreshed))
+	}
+	want := refresh{"user-123", time.Date(2023, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)}
+	if refreshed[0].userID != want.userID || !refreshed[0].from.Equal(want.from) || !refreshed[0].to.Equal(want.to) {
+		t.Errorf("Expected refresh %+v, got %+v", want, refreshed[0])
+	}
+}
+
+func TestRefreshSnapshots_KeepsSinceOnFailure(t *testing.T) {
+	since := time.Date(2025, 6, 18, 8, 0, 0, 0, time.UTC)
+	mockRepo := &repositories.MockTrendRepository{
+		FindChangedSinceFunc: func(ctx context.Context, s time.Time) ([]*models.TrendChange, error) {
+			return []*models.TrendChange{{UserID: "user-123", From: time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC)}}, nil
+		},
+		RefreshSnapshotsFunc: func(ctx context.Context, userID string, from, to time.Time) error {
+			return errors.New("connection reset")
+		},
+	}
+
+	service := newTestTrendService(mockRepo)
+	next, err := service.RefreshSnapshots(context.Background(), since)
+	if err == nil {
+		t.Fatal("Expected an error")
+	}
+	if !next.Equal(since) {
+		t.Errorf("Expected the failed run to be retried from %v, got %v", since, next)
+	}
+}
+
+func TestRefreshUser_RebuildsEveryCompletedMonth(t *testing.T) {
+	var gotUser string
+	var gotFrom, gotTo time.Time
+	mockRepo := &repositories.MockTrendRepository{
+		RefreshSnapshotsFunc: func(ctx context.Context, userID string, from, to time.Time) error {
+			gotUser, gotFrom, gotTo = userID, from, to
+			return nil
+		},
+	}
+
+	service := newTestTrendService(mockRepo)
+	if err := service.RefreshUser(context.Background(), "user-123"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if gotUser != "user-123" || !gotFrom.IsZero() || !gotTo.Equal(monthStart(service.now())) {
+		t.Errorf("Expected user-123 from the beginning to %v, got %s from %v to %v", monthStart(service.now()), gotUser, gotFrom, gotTo)
+	}
+}
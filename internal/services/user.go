@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrUserNotFound      = domainerr.New(domainerr.NotFound, "user not found")
+	ErrInvalidSuspension = domainerr.New(domainerr.Validation, "suspension must last a positive duration")
+)
+
+const (
+	defaultUserListLimit = 50
+	maxUserListLimit     = 1000
+
+	// adminRole is the app_metadata.role granting admin endpoints, as checked by
+	// middleware.AdminRequired
+	adminRole = "admin"
+
+	// indefiniteSuspension stands for a suspension without end, as Supabase bans
+	// need a time
+	indefiniteSuspension = 100 * 365 * 24 * time.Hour
+)
+
+// UserService handles operator tasks on user accounts
+type UserService struct {
+	repo repositories.UserRepository
+	now  func() time.Time
+}
+
+// NewUserService creates a new user service
+func NewUserService(repo repositories.UserRepository) *UserService {
+	return &UserService{repo: repo, now: time.Now}
+}
+
+// List returns the newest users, optionally those whose email contains filter.Email.
+// Limits outside 1..1000 fall back to the default of 50.
+func (s *UserService) List(ctx context.Context, filter models.UserFilter) ([]*models.User, error) {
+	if filter.Limit <= 0 || filter.Limit > maxUserListLimit {
+		filter.Limit = defaultUserListLimit
+	}
+
+	users, err := s.repo.List(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	return users, nil
+}
+
+// Find retrieves a user by ID or, for anything that is not a UUID, by email
+func (s *UserService) Find(ctx context.Context, idOrEmail string) (*models.User, error) {
+	var user *models.User
+	var err error
+	if _, parseErr := uuid.Parse(idOrEmail); parseErr == nil {
+		user, err = s.repo.FindByID(ctx, idOrEmail)
+	} else {
+		user, err = s.repo.FindByEmail(ctx, strings.TrimSpace(idOrEmail))
+	}
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+	return user, nil
+}
+
+// SetAdmin grants or revokes admin access. It applies to tokens issued afterwards, so a
+// user signed in keeps their access until their token is refreshed.
+func (s *UserService) SetAdmin(ctx context.Context, userID string, admin bool) error {
+	role := ""
+	if admin {
+		role = adminRole
+	}
+
+	if err := s.repo.SetRole(ctx, userID, role); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to set role: %w", err)
+	}
+	return nil
+}
+
+// Suspend stops the user from signing in for the duration, or indefinitely when it is 0,
+// and signs them out everywhere. Access tokens already issued stay valid until they
+// expire. Returns when the suspension ends.
+func (s *UserService) Suspend(ctx context.Context, userID string, duration time.Duration) (time.Time, error) {
+	if duration < 0 {
+		return time.Time{}, ErrInvalidSuspension
+	}
+	if duration == 0 {
+		duration = indefiniteSuspension
+	}
+	until := s.now().UTC().Add(duration).Truncate(time.Second)
+
+	if err := s.repo.Suspend(ctx, userID, until); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return time.Time{}, ErrUserNotFound
+		}
+		return time.Time{}, fmt.Errorf("failed to suspend user: %w", err)
+	}
+	return until, nil
+}
+
+// Unsuspend lets a suspended user sign in again
+func (s *UserService) Unsuspend(ctx context.Context, userID string) error {
+	if err := s.repo.Unsuspend(ctx, userID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to unsuspend user: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+// userEventRetryDelay is how long to wait before listening again after the notification
+// connection fails
+const userEventRetryDelay = 5 * time.Second
+
+// UserEventService pushes events to users' connected clients. Events are passed through
+// PostgreSQL, so clients connected to any instance receive them, along with the events
+// raised by database triggers.
+type UserEventService struct {
+	repo repositories.UserEventRepository
+	bus  *events.Bus
+}
+
+// NewUserEventService creates a new user event service delivering to the bus's subscribers
+func NewUserEventService(repo repositories.UserEventRepository, bus *events.Bus) *UserEventService {
+	return &UserEventService{repo: repo, bus: bus}
+}
+
+// Publish sends an event to the user's clients on every instance
+func (s *UserEventService) Publish(ctx context.Context, userID string, eventType string, data any) error {
+	event, err := events.New(userID, eventType, data)
+	if err != nil {
+		return fmt.Errorf("failed to build event: %w", err)
+	}
+	if err := s.repo.Notify(ctx, event); err != nil {
+		return fmt.Errorf("failed to publish event: %w", err)
+	}
+	return nil
+}
+
+// Subscribe returns a channel of the user's events. The caller must call the returned
+// cancel function when it stops listening. The channel is closed on Close.
+func (s *UserEventService) Subscribe(userID string) (<-chan *models.UserEvent, func()) {
+	return s.bus.Subscribe(userID)
+}
+
+// Close ends every subscription, so event streams return on shutdown instead of holding
+// the server open until their clients go away
+func (s *UserEventService) Close() {
+	s.bus.Close()
+}
+
+// Start delivers published events to this instance's subscribers in the background until
+// ctx is cancelled, listening again after connection failures
+func (s *UserEventService) Start(ctx context.Context) {
+	go func() {
+		for {
+			err := s.repo.Listen(ctx, s.bus.Deliver)
+			if ctx.Err() != nil {
+				return
+			}
+			log.Printf("User event listener failed: %v", err)
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(userEventRetryDelay):
+			}
+		}
+	}()
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/events"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestUserEvents_PublishedThroughListener(t *testing.T) {
+	notified := make(chan *models.UserEvent, 1)
+	mockRepo := &repositories.MockUserEventRepository{
+		NotifyFunc: func(ctx context.Context, event *models.UserEvent) error {
+			notified <- event
+			return nil
+		},
+		ListenFunc: func(ctx context.Context, handle func(*models.UserEvent)) error {
+			// Events come back from the database, alongside those raised by triggers
+			handle(<-notified)
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}
+
+	service := NewUserEventService(mockRepo, events.NewBus())
+	userEvents, cancel := service.Subscribe("user-123")
+	defer cancel()
+
+	ctx, stop := context.WithCancel(context.Background())
+	defer stop()
+	service.Start(ctx)
+
+	data := &models.ImportFinishedEvent{Source: "hevy", SessionsCreated: 1}
+	if err := service.Publish(ctx, "user-123", models.UserEventImportFinished, data); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	select {
+	case event := <-userEvents:
+		if event.Type != models.UserEventImportFinished || event.UserID != "user-123" {
+			t.Errorf("Unexpected event: %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the published event to reach the subscriber")
+	}
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func newTestUserService(repo repositories.UserRepository) *UserService {
+	service := NewUserService(repo)
+	service.now = func() time.Time { return time.Date(2025, 6, 18, 8, 0, 0, 0, time.UTC) }
+	return service
+}
+
+func TestListUsers_ClampsLimit(t *testing.T) {
+	var gotFilter models.UserFilter
+	mockRepo := &repositories.MockUserRepository{
+		ListFunc: func(ctx context.Context, filter models.UserFilter) ([]*models.User, error) {
+			gotFilter = filter
+			return []*models.User{{ID: "user-123"}}, nil
+		},
+	}
+
+	service := newTestUserService(mockRepo)
+	users, err := service.List(context.Background(), models.UserFilter{Email: "example.com", Limit: 5000})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(users) != 1 {
+		t.Errorf("Expected 1 user, got %d", len(users))
+	}
+	if gotFilter.Limit != defaultUserListLimit || gotFilter.Email != "example.com" {
+		t.Errorf("Expected the default limit and the email filter, got %+v", gotFilter)
+	}
+}
+
+func TestFindUser_ByIDOrEmail(t *testing.T) {
+	var byID, byEmail string
+	mockRepo := &repositories.MockUserRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.User, error) {
+			byID = id
+			return &models.User{ID: id}, nil
+		},
+		FindByEmailFunc: func(ctx context.Context, email string) (*models.User, error) {
+			byEmail = email
+			return nil, pgx.ErrNoRows
+		},
+	}
+
+	service := newTestUserService(mockRepo)
+	id := "3f2b8a52-8f0e-4a8e-9a43-1b0c6e6f2d10"
+	if _, err := service.Find(context.Background(), id); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if byID != id {
+		t.Errorf("Expected a lookup by ID, got %q", byID)
+	}
+
+	_, err := service.Find(context.Background(), " someone@example.com ")
+	if !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+	if byEmail != "someone@example.com" {
+		t.Errorf("Expected a lookup by the trimmed email, got %q", byEmail)
+	}
+}
+
+func TestSetAdmin_SetsAndClearsRole(t *testing.T) {
+	var roles []string
+	mockRepo := &repositories.MockUserRepository{
+		SetRoleFunc: func(ctx context.Context, id string, role string) error {
+			roles = append(roles, role)
+			return nil
+		},
+	}
+
+	service := newTestUserService(mockRepo)
+	if err := service.SetAdmin(context.Background(), "user-123", true); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := service.SetAdmin(context.Background(), "user-123", false); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(roles) != 2 || roles[0] != "admin" || roles[1] != "" {
+		t.Errorf("Expected the admin role set then cleared, got %q", roles)
+	}
+
+	mockRepo.SetRoleFunc = func(ctx context.Context, id string, role string) error { return pgx.ErrNoRows }
+	if err := service.SetAdmin(context.Background(), "missing", true); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("Expected ErrUserNotFound, got %v", err)
+	}
+}
+
+func TestSuspendUser(t *testing.T) {
+	var gotUntil time.Time
+	mockRepo := &repositories.MockUserRepository{
+		SuspendFunc: func(ctx context.Context, id string, until time.Time) error {
+			gotUntil = until
+			return nil
+		},
+	}
+
+	service := newTestUserService(mockRepo)
+	until, err := service.Suspend(context.Background(), "user-123", 72*time.Hour)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	want := time.Date(2025, 6, 21, 8, 0, 0, 0, time.UTC)
+	if !until.Equal(want) || !gotUntil.Equal(want) {
+		t.Errorf("Expected a suspension until %v, got %v", want, until)
+	}
+
+	until, err = service.Suspend(context.Background(), "user-123", 0)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if until.Year() < 2100 {
+		t.Errorf("Expected an indefinite suspension, got one until %v", until)
+	}
+
+	if _, err := service.Suspend(context.Background(), "user-123", -time.Hour); !errors.Is(err, ErrInvalidSuspension) {
+		t.Errorf("Expected ErrInvalidSuspension, got %v", err)
+	}
+}
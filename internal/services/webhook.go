@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/webhooks"
+)
+
+var (
+	ErrWebhookEndpointNotFound = domainerr.New(domainerr.NotFound, "webhook endpoint not found")
+	ErrInvalidWebhookEndpoint  = domainerr.New(domainerr.Validation, "invalid webhook endpoint")
+	ErrWebhookLimitReached     = domainerr.New(domainerr.Conflict, "webhook endpoint limit reached")
+)
+
+const (
+	// maxWebhookEndpoints caps how many endpoints a user may register
+	maxWebhookEndpoints = 10
+
+	// maxWebhookDeliveries caps a delivery log page
+	maxWebhookDeliveries = 200
+
+	// webhookBatch caps how many events one background run dispatches
+	webhookBatch = 20
+
+	// webhookSendTimeout bounds a single delivery attempt
+	webhookSendTimeout = 10 * time.Second
+
+	// webhookMaxAttempts is how often a delivery is tried before giving up; with doubling
+	// backoff from a minute, the last attempt is about 8.5 hours after the first
+	webhookMaxAttempts = 10
+
+	// webhookRetention is how long events and their delivery logs are kept
+	webhookRetention = 30 * 24 * time.Hour
+)
+
+// WebhookService manages users' webhook endpoints and delivers the events the database
+// records for them
+type WebhookService struct {
+	repo   repositories.WebhookRepository
+	sender *webhooks.Sender
+	audit  *AuditLogService
+	now    func() time.Time
+}
+
+// NewWebhookService creates a new webhook service and registers the delivery job with the
+// queue; endpoint changes are audited unless audit is nil
+func NewWebhookService(repo repositories.WebhookRepository, sender *webhooks.Sender, queue *jobs.Queue, audit *AuditLogService) *WebhookService {
+	s := &WebhookService{repo: repo, sender: sender, audit: audit, now: time.Now}
+	queue.Register(models.JobKindWebhookDelivery, jobs.Options{MaxAttempts: webhookMaxAttempts, Timeout: webhookSendTimeout}, s.deliver)
+	return s
+}
+
+// webhookDeliveryJob is the payload of a webhook delivery job
+type webhookDeliveryJob struct {
+	DeliveryID string `json:"delivery_id"`
+}
+
+// CreateEndpoint registers an endpoint for the user. The returned endpoint carries its
+// signing secret, which is not shown again.
+func (s *WebhookService) CreateEndpoint(ctx context.Context, userID string, req *models.CreateWebhookEndpointRequest) (*models.WebhookEndpoint, error) {
+	if err := webhooks.ValidateURL(req.URL); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhookEndpoint, err)
+	}
+
+	var events []string
+	for _, event := range req.Events {
+		if !slices.Contains(models.WebhookEvents, event) {
+			return nil, fmt.Errorf("%w: unknown event %q, expected one of %v", ErrInvalidWebhookEndpoint, event, models.WebhookEvents)
+		}
+		if !slices.Contains(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	existing, err := s.repo.ListEndpoints(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	if len(existing) >= maxWebhookEndpoints {
+		return nil, ErrWebhookLimitReached
+	}
+
+	secret, err := webhooks.NewSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		UserID:      userID,
+		URL:         req.URL,
+		Secret:      secret,
+		Events:      events,
+		Description: req.Description,
+	}
+	return audited(ctx, s.audit, userID, models.AuditActionCreate, models.AuditResourceWebhookEndpoint, "", nil, func() (*models.WebhookEndpoint, error) {
+		if err := s.repo.CreateEndpoint(ctx, endpoint); err != nil {
+			return nil, fmt.Errorf("failed to create webhook endpoint: %w", err)
+		}
+		return endpoint, nil
+	})
+}
+
+// ListEndpoints retrieves the user's endpoints
+func (s *WebhookService) ListEndpoints(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error) {
+	endpoints, err := s.repo.ListEndpoints(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// DeleteEndpoint removes one of the user's endpoints; pending deliveries are dropped
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id string, userID string) error {
+	endpoint, err := s.findOwned(ctx, id, userID)
+	if err != nil {
+		return err
+	}
+
+	_, err = audited(ctx, s.audit, userID, models.AuditActionDelete, models.AuditResourceWebhookEndpoint, id, endpoint, func() (*models.WebhookEndpoint, error) {
+		if err := s.repo.DeleteEndpoint(ctx, id); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, ErrWebhookEndpointNotFound
+			}
+			return nil, fmt.Errorf("failed to delete webhook endpoint: %w", err)
+		}
+		return nil, nil
+	})
+	return err
+}
+
+// ListDeliveries retrieves the most recent deliveries to one of the user's endpoints
+func (s *WebhookService) ListDeliveries(ctx context.Context, id string, userID string, limit int) ([]*models.WebhookDelivery, error) {
+	if limit <= 0 || limit > maxWebhookDeliveries {
+		limit = maxWebhookDeliveries
+	}
+
+	if _, err := s.findOwned(ctx, id, userID); err != nil {
+		return nil, err
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// findOwned retrieves an endpoint and verifies the user owns it
+func (s *WebhookService) findOwned(ctx context.Context, id string, userID string) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.repo.FindEndpoint(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWebhookEndpointNotFound
+		}
+		return nil, fmt.Errorf("failed to get webhook endpoint: %w", err)
+	}
+
+	if endpoint.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+
+	return endpoint, nil
+}
+
+// Start turns recorded events into deliveries every interval, which the job queue sends,
+// and prunes old events hourly, until ctx is cancelled
+func (s *WebhookService) Start(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		pruneTicker := time.NewTicker(time.Hour)
+		defer pruneTicker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.repo.DispatchEvents(ctx, webhookBatch, webhookMaxAttempts); err != nil {
+					log.Printf("Webhook events failed to dispatch: %v", err)
+				}
+			case <-pruneTicker.C:
+				if _, err := s.repo.PruneEvents(ctx, s.now().Add(-webhookRetention)); err != nil {
+					log.Printf("Webhook events failed to prune: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// deliver sends a delivery job's delivery and records the outcome on it: delivered, to be
+// retried with the job, or failed for good after its last attempt or a 410 Gone
+func (s *WebhookService) deliver(ctx context.Context, job *models.Job) error {
+	var payload webhookDeliveryJob
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("invalid webhook delivery job: %w", err))
+	}
+
+	msg, err := s.repo.FindMessage(ctx, payload.DeliveryID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// The endpoint was deleted since, or the delivery already went through
+			return nil
+		}
+		return fmt.Errorf("failed to load webhook delivery: %w", err)
+	}
+
+	status, err := s.sender.Send(ctx, msg.URL, msg.Secret, &webhooks.Message{
+		ID:        msg.EventID,
+		Type:      msg.Event,
+		Timestamp: msg.EventCreatedAt,
+		Data:      msg.Data,
+	})
+	if err == nil {
+		if err := s.repo.CompleteDelivery(ctx, msg.DeliveryID, status); err != nil {
+			log.Printf("Webhook delivery %s failed to record completion: %v", msg.DeliveryID, err)
+		}
+		return nil
+	}
+
+	var responseStatus *int
+	if status != 0 {
+		responseStatus = &status
+	}
+
+	permanent := errors.Is(err, webhooks.ErrGone) || errors.Is(err, webhooks.ErrUnsafeURL)
+	var retryAt *time.Time
+	if !permanent && !jobs.LastAttempt(job) {
+		next := s.now().Add(jobs.Backoff(job.Attempts))
+		retryAt = &next
+	}
+	if err := s.repo.FailDelivery(ctx, msg.DeliveryID, responseStatus, err.Error(), retryAt); err != nil {
+		log.Printf("Webhook delivery %s failed to record failure: %v", msg.DeliveryID, err)
+	}
+	if permanent {
+		return jobs.Permanent(err)
+	}
+	return err
+}
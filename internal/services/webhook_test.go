@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/jobs"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+	"github.com/juan-cantero/fitapi/internal/webhooks"
+)
+
+func TestCreateWebhookEndpoint(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     models.CreateWebhookEndpointRequest
+		wantErr error
+	}{
+		{"valid", models.CreateWebhookEndpointRequest{URL: "https://hooks.example.com/fitapi", Events: []string{"session.completed", "pr.achieved", "session.completed"}}, nil},
+		{"plain http", models.CreateWebhookEndpointRequest{URL: "http://hooks.example.com/fitapi", Events: []string{"session.completed"}}, ErrInvalidWebhookEndpoint},
+		{"private address", models.CreateWebhookEndpointRequest{URL: "https://192.168.1.10/hook", Events: []string{"session.completed"}}, ErrInvalidWebhookEndpoint},
+		{"unknown event", models.CreateWebhookEndpointRequest{URL: "https://hooks.example.com/fitapi", Events: []string{"session.deleted"}}, ErrInvalidWebhookEndpoint},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var created *models.WebhookEndpoint
+			mockRepo := &repositories.MockWebhookRepository{
+				CreateEndpointFunc: func(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+					created = endpoint
+					return nil
+				},
+			}
+
+			endpoint, err := NewWebhookService(mockRepo, nil, jobs.NewQueue(&repositories.MockJobRepository{}), nil).CreateEndpoint(context.Background(), "user-123", &tt.req)
+
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr != nil {
+				if created != nil {
+					t.Error("Expected no endpoint to be created")
+				}
+				return
+			}
+			if endpoint.Secret == "" {
+				t.Error("Expected the secret to be returned on creation")
+			}
+			if len(created.Events) != 2 {
+				t.Errorf("Expected duplicate events to be dropped, got %v", created.Events)
+			}
+		})
+	}
+}
+
+func TestCreateWebhookEndpoint_Limit(t *testing.T) {
+	mockRepo := &repositories.MockWebhookRepository{
+		ListEndpointsFunc: func(ctx context.Context, userID string) ([]*models.WebhookEndpoint, error) {
+			return make([]*models.WebhookEndpoint, maxWebhookEndpoints), nil
+		},
+	}
+
+	req := &models.CreateWebhookEndpointRequest{URL: "https://hooks.example.com/fitapi", Events: []string{"workout.created"}}
+	if _, err := NewWebhookService(mockRepo, nil, jobs.NewQueue(&repositories.MockJobRepository{}), nil).CreateEndpoint(context.Background(), "user-123", req); !errors.Is(err, ErrWebhookLimitReached) {
+		t.Errorf("Expected ErrWebhookLimitReached, got %v", err)
+	}
+}
+
+func TestCreateWebhookEndpoint_AuditsWithoutSecret(t *testing.T) {
+	audit, entries := recordingAudit()
+	mockRepo := &repositories.MockWebhookRepository{
+		CreateEndpointFunc: func(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+			endpoint.ID = "endpoint-1"
+			return nil
+		},
+	}
+
+	req := &models.CreateWebhookEndpointRequest{URL: "https://hooks.example.com/fitapi", Events: []string{"session.completed"}}
+	if _, err := NewWebhookService(mockRepo, nil, jobs.NewQueue(&repositories.MockJobRepository{}), audit).CreateEndpoint(context.Background(), "user-123", req); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(*entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(*entries))
+	}
+	entry := (*entries)[0]
+	if entry.ResourceType != models.AuditResourceWebhookEndpoint || entry.ResourceID != "endpoint-1" || entry.Changes["url"] == nil {
+		t.Errorf("Unexpected entry %+v", entry)
+	}
+	if _, ok := entry.Changes["secret"]; ok {
+		t.Error("Expected the signing secret to be left out of the audit log")
+	}
+}
+
+func TestListWebhookDeliveries_Ownership(t *testing.T) {
+	mockRepo := &repositories.MockWebhookRepository{
+		FindEndpointFunc: func(ctx context.Context, id string) (*models.WebhookEndpoint, error) {
+			return &models.WebhookEndpoint{ID: id, UserID: "user-123"}, nil
+		},
+	}
+	service := NewWebhookService(mockRepo, nil, jobs.NewQueue(&repositories.MockJobRepository{}), nil)
+
+	if _, err := service.ListDeliveries(context.Background(), "endpoint-1", "user-123", 50); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := service.ListDeliveries(context.Background(), "endpoint-1", "user-789", 50); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if err := service.DeleteEndpoint(context.Background(), "endpoint-1", "user-789"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized, got %v", err)
+	}
+	if _, err := NewWebhookService(&repositories.MockWebhookRepository{}, nil, jobs.NewQueue(&repositories.MockJobRepository{}), nil).ListDeliveries(context.Background(), "missing", "user-123", 50); !errors.Is(err, ErrWebhookEndpointNotFound) {
+		t.Errorf("Expected ErrWebhookEndpointNotFound, got %v", err)
+	}
+}
+
+func TestDeliverWebhook_RecordsOutcomes(t *testing.T) {
+	now := time.Date(2026, 5, 12, 7, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		status      int
+		attempts    int
+		wantRetryAt *time.Time
+		wantFailed  bool
+	}{
+		{"delivered", http.StatusOK, 1, nil, false},
+		{"retried with backoff", http.StatusServiceUnavailable, 4, func() *time.Time { t := now.Add(8 * time.Minute); return &t }(), true},
+		{"gave up", http.StatusInternalServerError, webhookMaxAttempts, nil, true},
+		{"gone", http.StatusGone, 1, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			secret, _ := webhooks.NewSecret()
+			var completed, failed bool
+			var retryAt *time.Time
+			var responseStatus *int
+			mockRepo := &repositories.MockWebhookRepository{
+				FindMessageFunc: func(ctx context.Context, deliveryID string) (*models.WebhookMessage, error) {
+					return &models.WebhookMessage{
+						DeliveryID: deliveryID,
+						URL:        server.URL,
+						Secret:     secret,
+						EventID:    "event-1",
+						Event:      models.WebhookEventSessionCompleted,
+						Data:       json.RawMessage(`{"session_id":"session-1"}`),
+					}, nil
+				},
+				CompleteDeliveryFunc: func(ctx context.Context, id string, status int) error {
+					completed = true
+					return nil
+				},
+				FailDeliveryFunc: func(ctx context.Context, id string, status *int, reason string, next *time.Time) error {
+					failed, retryAt, responseStatus = true, next, status
+					return nil
+				},
+			}
+
+			service := NewWebhookService(mockRepo, webhooks.NewSender(server.Client()), jobs.NewQueue(&repositories.MockJobRepository{}), nil)
+			service.now = func() time.Time { return now }
+			job := &models.Job{
+				ID:          "job-1",
+				Kind:        models.JobKindWebhookDelivery,
+				Payload:     json.RawMessage(`{"delivery_id":"delivery-1"}`),
+				Attempts:    tt.attempts,
+				MaxAttempts: webhookMaxAttempts,
+			}
+			err := service.deliver(context.Background(), job)
+
+			if completed == tt.wantFailed || failed != tt.wantFailed {
+				t.Fatalf("Expected failed=%v, got completed=%v failed=%v", tt.wantFailed, completed, failed)
+			}
+			if !tt.wantFailed {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || jobs.IsPermanent(err) != (tt.status == http.StatusGone) {
+				t.Errorf("Expected the job to fail, permanently only when gone, got %v", err)
+			}
+			if responseStatus == nil || *responseStatus != tt.status {
+				t.Errorf("Expected response status %d to be recorded, got %v", tt.status, responseStatus)
+			}
+			if (retryAt == nil) != (tt.wantRetryAt == nil) || (retryAt != nil && !retryAt.Equal(*tt.wantRetryAt)) {
+				t.Errorf("Expected retry at %v, got %v", tt.wantRetryAt, retryAt)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/juan-cantero/fitapi/internal/domainerr"
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+var (
+	ErrWorkoutNotFound      = domainerr.New(domainerr.NotFound, "workout not found")
+	ErrWorkoutAlreadyShared = domainerr.New(domainerr.Conflict, "workout is already shared in an organization")
+)
+
+// WorkoutService handles workout templates, personal and shared in organizations
+type WorkoutService struct {
+	repo     repositories.WorkoutRepository
+	injuries repositories.InjuryRepository
+	policy   AccessPolicy
+	audit    *AuditLogService
+}
+
+// NewWorkoutService creates a new workout service; writes are audited unless audit is nil
+func NewWorkoutService(repo repositories.WorkoutRepository, injuries repositories.InjuryRepository, policy AccessPolicy, audit *AuditLogService) *WorkoutService {
+	return &WorkoutService{repo: repo, injuries: injuries, policy: policy, audit: audit}
+}
+
+// GetWorkout retrieves a workout with its exercises and their equipment. The owner and
+// their coaches may view personal workouts; members may view organization workouts.
+// Exercises are flagged with the injured body parts they load for whoever performs the
+// workout (see workoutPerformer).
+func (s *WorkoutService) GetWorkout(ctx context.Context, id string, actorID string) (*models.Workout, error) {
+	workout, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get workout: %w", err)
+	}
+
+	var ok bool
+	if workout.OrganizationID != nil {
+		ok, err = s.policy.CanReadOrg(ctx, actorID, *workout.OrganizationID)
+	} else {
+		ok, err = s.policy.CanRead(ctx, actorID, workout.UserID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to check access: %w", err)
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	byMuscleGroup, err := s.injuries.Contraindications(ctx, workoutPerformer(workout, actorID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get contraindications: %w", err)
+	}
+	for _, we := range workout.Exercises {
+		we.Exercise = contraindicated(we.Exercise, byMuscleGroup)
+	}
+
+	return workout, nil
+}
+
+// ListOrgWorkouts retrieves the workouts shared in an organization (any member)
+func (s *WorkoutService) ListOrgWorkouts(ctx context.Context, userID string, orgID string) ([]*models.WorkoutSummary, error) {
+	ok, err := s.policy.CanReadOrg(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	workouts, err := s.repo.FindByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization workouts: %w", err)
+	}
+
+	return workouts, nil
+}
+
+// ShareWorkout moves one of the user's personal workouts into an organization, where every
+// member can use it and trainers can schedule classes of it (owners and trainers only).
+// The user stays its author.
+func (s *WorkoutService) ShareWorkout(ctx context.Context, userID string, orgID string, req *models.ShareWorkoutRequest) (*models.Workout, error) {
+	ok, err := s.policy.CanWriteOrg(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrUnauthorized
+	}
+
+	workout, err := s.repo.FindByID(ctx, req.WorkoutID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrWorkoutNotFound
+		}
+		return nil, fmt.Errorf("failed to get workout: %w", err)
+	}
+	if workout.OrganizationID != nil {
+		return nil, ErrWorkoutAlreadyShared
+	}
+	if workout.UserID != userID {
+		return nil, ErrUnauthorized
+	}
+	before := *workout
+
+	return audited(ctx, s.audit, userID, models.AuditActionUpdate, models.AuditResourceWorkout, workout.ID, &before, func() (*models.Workout, error) {
+		if err := s.repo.Share(ctx, workout, orgID); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				// Shared or changed between the read and the write
+				return nil, ErrWorkoutAlreadyShared
+			}
+			return nil, fmt.Errorf("failed to share workout: %w", err)
+		}
+		return workout, nil
+	})
+}
+
+// workoutPerformer returns who performs a workout viewed by actorID: the owner of a personal
+// workout (also when their coach views it), or the member viewing an organization one
+func workoutPerformer(workout *models.Workout, actorID string) string {
+	if workout.OrganizationID != nil {
+		return actorID
+	}
+	return workout.UserID
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+	"github.com/juan-cantero/fitapi/internal/repositories"
+)
+
+func TestGetWorkout_Access(t *testing.T) {
+	orgID := "org-1"
+	mockRepo := &repositories.MockWorkoutRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Workout, error) {
+			switch id {
+			case "personal":
+				return &models.Workout{ID: id, UserID: "user-123"}, nil
+			case "shared":
+				return &models.Workout{ID: id, UserID: "trainer-1", OrganizationID: &orgID}, nil
+			}
+			return (&repositories.MockWorkoutRepository{}).FindByID(ctx, id)
+		},
+	}
+	orgRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: func(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+			if userID == "member-1" {
+				return &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: models.OrgRoleMember}, nil
+			}
+			return (&repositories.MockOrganizationRepository{}).FindMember(ctx, orgID, userID)
+		},
+	}
+	service := NewWorkoutService(mockRepo, &repositories.MockInjuryRepository{}, NewAccessPolicy(&repositories.MockCoachClientRepository{}, orgRepo), nil)
+
+	tests := []struct {
+		id      string
+		actorID string
+		wantErr error
+	}{
+		{"personal", "user-123", nil},
+		{"personal", "user-789", ErrUnauthorized},
+		{"shared", "member-1", nil},
+		{"shared", "user-123", ErrUnauthorized},
+		{"missing", "user-123", ErrWorkoutNotFound},
+	}
+	for _, tt := range tests {
+		workout, err := service.GetWorkout(context.Background(), tt.id, tt.actorID)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s as %s: expected %v, got %v", tt.id, tt.actorID, tt.wantErr, err)
+		}
+		if err == nil && workout.ID != tt.id {
+			t.Errorf("Expected workout %s, got %s", tt.id, workout.ID)
+		}
+	}
+}
+
+func TestShareWorkout(t *testing.T) {
+	otherOrg := "org-2"
+	mockRepo := &repositories.MockWorkoutRepository{
+		FindByIDFunc: func(ctx context.Context, id string) (*models.Workout, error) {
+			switch id {
+			case "personal":
+				return &models.Workout{ID: id, UserID: "trainer-1", Version: 1}, nil
+			case "someone-elses":
+				return &models.Workout{ID: id, UserID: "user-789", Version: 1}, nil
+			case "shared":
+				return &models.Workout{ID: id, UserID: "trainer-1", OrganizationID: &otherOrg, Version: 2}, nil
+			}
+			return (&repositories.MockWorkoutRepository{}).FindByID(ctx, id)
+		},
+	}
+	orgRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: func(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+			switch userID {
+			case "trainer-1":
+				return &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: models.OrgRoleTrainer}, nil
+			case "member-1":
+				return &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: models.OrgRoleMember}, nil
+			}
+			return (&repositories.MockOrganizationRepository{}).FindMember(ctx, orgID, userID)
+		},
+	}
+	service := NewWorkoutService(mockRepo, &repositories.MockInjuryRepository{}, NewAccessPolicy(&repositories.MockCoachClientRepository{}, orgRepo), nil)
+
+	tests := []struct {
+		workoutID string
+		actorID   string
+		wantErr   error
+	}{
+		{"personal", "trainer-1", nil},
+		{"personal", "member-1", ErrUnauthorized},
+		{"someone-elses", "trainer-1", ErrUnauthorized},
+		{"shared", "trainer-1", ErrWorkoutAlreadyShared},
+		{"missing", "trainer-1", ErrWorkoutNotFound},
+	}
+	for _, tt := range tests {
+		workout, err := service.ShareWorkout(context.Background(), tt.actorID, "org-1", &models.ShareWorkoutRequest{WorkoutID: tt.workoutID})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s as %s: expected %v, got %v", tt.workoutID, tt.actorID, tt.wantErr, err)
+		}
+		if err == nil && (workout.OrganizationID == nil || *workout.OrganizationID != "org-1") {
+			t.Errorf("Expected workout shared in org-1, got %v", workout.OrganizationID)
+		}
+	}
+}
+
+func TestListOrgWorkouts_MembersOnly(t *testing.T) {
+	mockRepo := &repositories.MockWorkoutRepository{
+		FindByOrganizationFunc: func(ctx context.Context, orgID string) ([]*models.WorkoutSummary, error) {
+			return []*models.WorkoutSummary{{ID: "w-1", OrganizationID: &orgID, Name: "Push"}}, nil
+		},
+	}
+	orgRepo := &repositories.MockOrganizationRepository{
+		FindMemberFunc: func(ctx context.Context, orgID, userID string) (*models.OrganizationMember, error) {
+			if userID == "member-1" {
+				return &models.OrganizationMember{OrganizationID: orgID, UserID: userID, Role: models.OrgRoleMember}, nil
+			}
+			return (&repositories.MockOrganizationRepository{}).FindMember(ctx, orgID, userID)
+		},
+	}
+	service := NewWorkoutService(mockRepo, &repositories.MockInjuryRepository{}, NewAccessPolicy(&repositories.MockCoachClientRepository{}, orgRepo), nil)
+
+	workouts, err := service.ListOrgWorkouts(context.Background(), "member-1", "org-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(workouts) != 1 || workouts[0].ID != "w-1" {
+		t.Errorf("Expected the shared workout, got %v", workouts)
+	}
+
+	if _, err := service.ListOrgWorkouts(context.Background(), "user-789", "org-1"); !errors.Is(err, ErrUnauthorized) {
+		t.Errorf("Expected ErrUnauthorized for non-members, got %v", err)
+	}
+}
@@ -0,0 +1,217 @@
+// Package sharecard renders summary images of workout sessions for sharing to social apps.
+// Cards are drawn with a built-in bitmap font, so rendering needs no fonts installed on
+// the server; the SVG flavour leaves typesetting to the viewer.
+package sharecard
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// Size is the width and height of a card, square as most social apps crop to
+const Size = 1080
+
+const (
+	margin     = 72
+	maxRecords = 3
+)
+
+var (
+	background = color.RGBA{R: 0x11, G: 0x18, B: 0x27, A: 0xff}
+	accent     = color.RGBA{R: 0xf9, G: 0x73, B: 0x16, A: 0xff}
+	foreground = color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+	muted      = color.RGBA{R: 0x9c, G: 0xa3, B: 0xaf, A: 0xff}
+)
+
+// item is a line of text on a card; scale is the size of a font pixel in image pixels
+type item struct {
+	text  string
+	x, y  int
+	scale int
+	color color.RGBA
+}
+
+// ContentType returns the media type of a card of format
+func ContentType(format string) string {
+	if format == models.ShareCardFormatSVG {
+		return "image/svg+xml"
+	}
+	return "image/png"
+}
+
+// Render draws the card of a session in format (png or svg)
+func Render(summary *models.ShareCardSummary, format string) ([]byte, error) {
+	switch format {
+	case models.ShareCardFormatPNG:
+		return RenderPNG(summary)
+	case models.ShareCardFormatSVG:
+		return RenderSVG(summary), nil
+	}
+	return nil, fmt.Errorf("unknown share card format %q", format)
+}
+
+// RenderPNG draws the card of a session as a PNG
+func RenderPNG(summary *models.ShareCardSummary) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, Size, Size))
+	fill(img, image.Rect(0, 0, Size, Size), background)
+	fill(img, image.Rect(0, Size-24, Size, Size), accent)
+	for _, it := range layout(summary) {
+		drawText(img, it)
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderSVG draws the card of a session as an SVG document
+func RenderSVG(summary *models.ShareCardSummary) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`, Size, Size, Size, Size)
+	fmt.Fprintf(&b, `<rect width="%d" height="%d" fill="%s"/>`, Size, Size, hex(background))
+	fmt.Fprintf(&b, `<rect y="%d" width="%d" height="24" fill="%s"/>`, Size-24, Size, hex(accent))
+	for _, it := range layout(summary) {
+		// A font pixel is a tenth of the em, so capitals are as tall as the bitmap font's
+		fmt.Fprintf(&b, `<text x="%d" y="%d" font-family="Helvetica, Arial, sans-serif" font-weight="bold" font-size="%d" fill="%s">`,
+			it.x, it.y+glyphHeight*it.scale, 10*it.scale, hex(it.color))
+		escapeXML(&b, it.text)
+		b.WriteString(`</text>`)
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// layout places the session's name and date, its stats in a two by two grid and its
+// personal records
+func layout(s *models.ShareCardSummary) []*item {
+	width := Size - 2*margin
+	title := strings.ToUpper(s.SessionType[:min(len(s.SessionType), 1)]) + s.SessionType[min(len(s.SessionType), 1):] + " workout"
+	if s.Name != nil && *s.Name != "" {
+		title = *s.Name
+	}
+
+	items := []*item{
+		{text: "FITAPI", x: margin, y: margin, scale: 4, color: accent},
+		{text: fit(title, 8, width), x: margin, y: 160, scale: 8, color: foreground},
+		{text: s.StartedAt.Format("Monday, Jan 2 2006"), x: margin, y: 240, scale: 5, color: muted},
+	}
+
+	duration := "-"
+	if s.DurationMinutes != nil {
+		duration = strconv.Itoa(*s.DurationMinutes) + " min"
+	}
+	stats := [][2]string{
+		{"Duration", duration},
+		{"Volume", thousands(int64(s.VolumeKg+0.5)) + " kg"},
+		{"Sets", strconv.Itoa(s.Sets)},
+		{"Exercises", strconv.Itoa(s.Exercises)},
+	}
+	for i, stat := range stats {
+		x := margin + (i%2)*(width/2)
+		y := 340 + (i/2)*160
+		items = append(items,
+			&item{text: strings.ToUpper(stat[0]), x: x, y: y, scale: 4, color: muted},
+			&item{text: fit(stat[1], 7, width/2-24), x: x, y: y + 44, scale: 7, color: foreground},
+		)
+	}
+
+	if len(s.Records) > 0 {
+		items = append(items, &item{text: "PERSONAL RECORDS", x: margin, y: 680, scale: 4, color: accent})
+		for i, r := range s.Records[:min(len(s.Records), maxRecords)] {
+			text := r.ExerciseName + " " + strconv.FormatFloat(r.WeightKg, 'f', -1, 64) + " kg"
+			if r.Reps != nil {
+				text += " x " + strconv.Itoa(*r.Reps)
+			}
+			items = append(items, &item{text: fit(text, 5, width), x: margin, y: 730 + i*60, scale: 5, color: foreground})
+		}
+	}
+
+	return items
+}
+
+// fit shortens text with an ellipsis to the characters of the bitmap font at scale that
+// fit in width
+func fit(text string, scale int, width int) string {
+	max := width / ((glyphWidth + 1) * scale)
+	runes := []rune(text)
+	if len(runes) <= max {
+		return text
+	}
+	return strings.TrimSpace(string(runes[:max-3])) + "..."
+}
+
+// thousands formats n with comma thousands separators
+func thousands(n int64) string {
+	digits := strconv.FormatInt(n, 10)
+	if n < 0 {
+		return "-" + thousands(-n)
+	}
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	return b.String()
+}
+
+func fill(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
+
+// drawText draws an item with the bitmap font, in upper case
+func drawText(img *image.RGBA, it *item) {
+	x := it.x
+	for _, r := range it.text {
+		glyph, ok := glyphs[unicode.ToUpper(r)]
+		if !ok {
+			glyph = glyphs['?']
+		}
+		for row, bits := range glyph {
+			for col, bit := range bits {
+				if bit == '#' {
+					px := x + col*it.scale
+					py := it.y + row*it.scale
+					fill(img, image.Rect(px, py, px+it.scale, py+it.scale).Intersect(img.Bounds()), it.color)
+				}
+			}
+		}
+		x += (glyphWidth + 1) * it.scale
+	}
+}
+
+func hex(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
+
+func escapeXML(b *strings.Builder, s string) {
+	for _, r := range s {
+		switch r {
+		case '<':
+			b.WriteString("&lt;")
+		case '>':
+			b.WriteString("&gt;")
+		case '&':
+			b.WriteString("&amp;")
+		case '"':
+			b.WriteString("&quot;")
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
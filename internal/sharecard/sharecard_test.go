@@ -0,0 +1,101 @@
+package sharecard
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+func testSummary() *models.ShareCardSummary {
+	name := "Push <day> & more"
+	duration := 62
+	reps := 5
+	return &models.ShareCardSummary{
+		UserID:          "user-1",
+		Status:          "completed",
+		Name:            &name,
+		SessionType:     "strength",
+		StartedAt:       time.Date(2026, 3, 2, 18, 0, 0, 0, time.UTC),
+		DurationMinutes: &duration,
+		VolumeKg:        12345.6,
+		Sets:            24,
+		Exercises:       6,
+		Records: []*models.ShareCardRecord{
+			{ExerciseName: "Bench Press", WeightKg: 100, Reps: &reps},
+		},
+	}
+}
+
+func TestRenderPNG(t *testing.T) {
+	data, err := RenderPNG(testSummary())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("Expected a valid PNG, got %v", err)
+	}
+	if b := img.Bounds(); b.Dx() != Size || b.Dy() != Size {
+		t.Errorf("Expected %dx%d, got %dx%d", Size, Size, b.Dx(), b.Dy())
+	}
+
+	// The top left pixel of the title's first letter, a P, is drawn
+	c := color.RGBAModel.Convert(img.At(margin+1, 160+1)).(color.RGBA)
+	if c != foreground {
+		t.Errorf("Expected the title to be drawn, got %v", c)
+	}
+}
+
+func TestRenderSVG(t *testing.T) {
+	svg := string(RenderSVG(testSummary()))
+	for _, want := range []string{"Push &lt;day&gt; &amp; more", "Monday, Mar 2 2026", "62 min", "12,346 kg", "Bench Press 100 kg x 5"} {
+		if !strings.Contains(svg, want) {
+			t.Errorf("Expected SVG to contain %q", want)
+		}
+	}
+}
+
+func TestLayoutWithoutName(t *testing.T) {
+	s := testSummary()
+	s.Name = nil
+	s.Records = nil
+
+	items := layout(s)
+	if items[1].text != "Strength workout" {
+		t.Errorf("Expected title from session type, got %q", items[1].text)
+	}
+	for _, it := range items {
+		if it.text == "PERSONAL RECORDS" {
+			t.Error("Expected no records section without records")
+		}
+	}
+}
+
+func TestFit(t *testing.T) {
+	if got := fit("Short", 10, 600); got != "Short" {
+		t.Errorf("Expected text to fit, got %q", got)
+	}
+	// 600px holds 10 characters at scale 10
+	if got := fit("A very long session name", 10, 600); got != "A very..." {
+		t.Errorf("Expected truncated text, got %q", got)
+	}
+}
+
+func TestThousands(t *testing.T) {
+	for n, want := range map[int64]string{0: "0", 999: "999", 1000: "1,000", 1234567: "1,234,567", -4200: "-4,200"} {
+		if got := thousands(n); got != want {
+			t.Errorf("thousands(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestRenderUnknownFormat(t *testing.T) {
+	if _, err := Render(testSummary(), "gif"); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
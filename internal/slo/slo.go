@@ -0,0 +1,242 @@
+// Package slo tracks response times per route group against service level objectives
+//
+// Each group keeps per-minute latency histograms for the last six hours in memory, so
+// compliance and burn rates are per API instance and start over on restart.
+package slo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+// DefaultGroup is the target for route groups without their own
+const DefaultGroup = "default"
+
+// Retention is how far back the tracker remembers requests
+const Retention = 6 * time.Hour
+
+// Windows over which compliance and burn rates are reported
+var Windows = []time.Duration{5 * time.Minute, 30 * time.Minute, time.Hour, Retention}
+
+// bounds are the histogram bucket upper bounds; the last bucket is everything slower
+var bounds = [...]time.Duration{
+	5 * time.Millisecond, 10 * time.Millisecond, 25 * time.Millisecond, 50 * time.Millisecond,
+	100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// Target is the objective for a route group: Objective of requests (e.g. 0.99) answer
+// within Threshold without a server error
+type Target struct {
+	Group     string
+	Threshold time.Duration
+	Objective float64
+}
+
+// ParseTargets reads targets written as "group=threshold:percent", comma separated, e.g.
+// "sessions=300ms:99.5,analytics=2s:95,default=500ms:99"
+func ParseTargets(spec string) ([]Target, error) {
+	var targets []Target
+	seen := map[string]bool{}
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		group, rest, ok := strings.Cut(entry, "=")
+		threshold, percent, ok2 := strings.Cut(rest, ":")
+		if !ok || !ok2 || group == "" {
+			return nil, fmt.Errorf("invalid SLO target %q, expected group=threshold:percent", entry)
+		}
+		if seen[group] {
+			return nil, fmt.Errorf("duplicate SLO target for %q", group)
+		}
+		seen[group] = true
+
+		d, err := time.ParseDuration(threshold)
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid SLO threshold %q for %q", threshold, group)
+		}
+		p, err := strconv.ParseFloat(percent, 64)
+		if err != nil || p <= 0 || p >= 100 {
+			return nil, fmt.Errorf("invalid SLO objective %q for %q, expected a percentage below 100", percent, group)
+		}
+		targets = append(targets, Target{Group: group, Threshold: d, Objective: p / 100})
+	}
+	return targets, nil
+}
+
+// bucket holds one minute of a group's requests
+type bucket struct {
+	minute int64
+	counts [len(bounds) + 1]int64
+	good   int64
+	total  int64
+}
+
+// series is a group's ring of minute buckets
+type series struct {
+	target  Target
+	buckets [int(Retention / time.Minute)]bucket
+}
+
+// Tracker records request latencies per route group
+type Tracker struct {
+	mu     sync.Mutex
+	groups map[string]*series
+	now    func() time.Time
+}
+
+// NewTracker creates a tracker for the targets; groups without a target are only tracked
+// when a default target is given
+func NewTracker(targets []Target) *Tracker {
+	t := &Tracker{groups: make(map[string]*series, len(targets)), now: time.Now}
+	for _, target := range targets {
+		t.groups[target.Group] = &series{target: target}
+	}
+	return t
+}
+
+// Observe records a request; server errors count against the objective however fast
+func (t *Tracker) Observe(group string, status int, elapsed time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	s, ok := t.groups[group]
+	if !ok {
+		def, ok := t.groups[DefaultGroup]
+		if !ok {
+			return
+		}
+		s = &series{target: Target{Group: group, Threshold: def.target.Threshold, Objective: def.target.Objective}}
+		t.groups[group] = s
+	}
+
+	minute := t.now().Unix() / 60
+	b := &s.buckets[minute%int64(len(s.buckets))]
+	if b.minute != minute {
+		*b = bucket{minute: minute}
+	}
+
+	b.counts[sort.Search(len(bounds), func(i int) bool { return elapsed <= bounds[i] })]++
+	b.total++
+	if status < 500 && elapsed <= s.target.Threshold {
+		b.good++
+	}
+}
+
+// Report summarizes every tracked group, in name order
+func (t *Tracker) Report() []*models.SLOGroupReport {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	minute := t.now().Unix() / 60
+	reports := make([]*models.SLOGroupReport, 0, len(t.groups))
+	for _, s := range t.groups {
+		report := &models.SLOGroupReport{
+			Group:       s.target.Group,
+			ThresholdMs: s.target.Threshold.Milliseconds(),
+			Objective:   s.target.Objective,
+		}
+		for _, window := range Windows {
+			good, total, _ := s.sum(minute, window)
+			w := models.SLOWindowReport{Window: formatWindow(window), Requests: total, Good: good, Compliance: 1}
+			if total > 0 {
+				w.Compliance = float64(good) / float64(total)
+				w.BurnRate = (1 - w.Compliance) / (1 - s.target.Objective)
+			}
+			report.Windows = append(report.Windows, w)
+		}
+
+		_, total, counts := s.sum(minute, time.Hour)
+		report.P50Ms = quantile(counts, total, 0.50)
+		report.P95Ms = quantile(counts, total, 0.95)
+		report.P99Ms = quantile(counts, total, 0.99)
+		reports = append(reports, report)
+	}
+
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Group < reports[j].Group })
+	return reports
+}
+
+// sum adds up the buckets of the last window, including the current minute
+func (s *series) sum(minute int64, window time.Duration) (good, total int64, counts [len(bounds) + 1]int64) {
+	minutes := int64(window / time.Minute)
+	for i := range s.buckets {
+		b := &s.buckets[i]
+		if b.total == 0 || b.minute <= minute-minutes || b.minute > minute {
+			continue
+		}
+		good += b.good
+		total += b.total
+		for j, n := range b.counts {
+			counts[j] += n
+		}
+	}
+	return good, total, counts
+}
+
+// quantile returns the upper bound of the bucket holding the q-th request, or nil without
+// requests; requests slower than the last bound report that bound
+func quantile(counts [len(bounds) + 1]int64, total int64, q float64) *float64 {
+	if total == 0 {
+		return nil
+	}
+	rank := int64(math.Ceil(q * float64(total)))
+	var seen int64
+	for i, n := range counts {
+		seen += n
+		if seen >= rank {
+			ms := float64(bounds[min(i, len(bounds)-1)]) / float64(time.Millisecond)
+			return &ms
+		}
+	}
+	return nil
+}
+
+func formatWindow(d time.Duration) string {
+	if d%time.Hour == 0 {
+		return strconv.Itoa(int(d/time.Hour)) + "h"
+	}
+	return strconv.Itoa(int(d/time.Minute)) + "m"
+}
+
+// Group returns the route group of a matched route: the first path segment after /api,
+// e.g. "sessions" for /api/sessions/:id/laps, or the first segment outside /api
+func Group(fullPath string) string {
+	path := strings.TrimPrefix(strings.TrimPrefix(fullPath, "/api"), "/")
+	group, _, _ := strings.Cut(path, "/")
+	return group
+}
+
+// Middleware records every matched request's latency under its route group
+// WebSocket and event stream connections are left out: their lifetime is not a response time.
+func Middleware(t *Tracker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			return // Unmatched routes would let anyone create groups
+		}
+		if strings.HasPrefix(c.Writer.Header().Get("Content-Type"), "text/event-stream") {
+			return
+		}
+		t.Observe(Group(route), c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,131 @@
+package slo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/juan-cantero/fitapi/internal/models"
+)
+
+func TestParseTargets(t *testing.T) {
+	targets, err := ParseTargets("sessions=300ms:99.5, default=1s:99")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(targets) != 2 || targets[0].Group != "sessions" || targets[0].Threshold != 300*time.Millisecond || targets[0].Objective != 0.995 {
+		t.Errorf("Unexpected targets %+v", targets)
+	}
+
+	for _, spec := range []string{"sessions", "sessions=fast:99", "sessions=300ms:100", "sessions=300ms", "a=1s:99,a=2s:95"} {
+		if _, err := ParseTargets(spec); err == nil {
+			t.Errorf("ParseTargets(%q): expected an error", spec)
+		}
+	}
+}
+
+func TestReport_ComplianceAndBurnRate(t *testing.T) {
+	now := time.Date(2026, 5, 12, 12, 0, 30, 0, time.UTC)
+	tracker := NewTracker([]Target{{Group: "sessions", Threshold: 300 * time.Millisecond, Objective: 0.99}})
+
+	// Two hours ago: all good. Now: 90 fast, 5 slow, 5 server errors.
+	tracker.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	for range 100 {
+		tracker.Observe("sessions", http.StatusOK, 20*time.Millisecond)
+	}
+	tracker.now = func() time.Time { return now }
+	for range 90 {
+		tracker.Observe("sessions", http.StatusOK, 20*time.Millisecond)
+	}
+	for range 5 {
+		tracker.Observe("sessions", http.StatusOK, time.Second)
+	}
+	for range 5 {
+		tracker.Observe("sessions", http.StatusInternalServerError, 5*time.Millisecond)
+	}
+	tracker.Observe("unknown", http.StatusOK, time.Millisecond)
+
+	reports := tracker.Report()
+	if len(reports) != 1 {
+		t.Fatalf("Expected only the targeted group, got %d reports", len(reports))
+	}
+
+	windows := map[string]models.SLOWindowReport{}
+	for _, w := range reports[0].Windows {
+		windows[w.Window] = w
+	}
+	if w := windows["5m"]; w.Requests != 100 || w.Good != 90 || w.Compliance != 0.9 {
+		t.Errorf("Unexpected 5m window %+v", w)
+	}
+	if w := windows["5m"]; w.BurnRate < 9.99 || w.BurnRate > 10.01 {
+		t.Errorf("Expected a burn rate of 10, got %v", w.BurnRate)
+	}
+	if w := windows["6h"]; w.Requests != 200 || w.Good != 190 {
+		t.Errorf("Unexpected 6h window %+v", w)
+	}
+	if p50 := reports[0].P50Ms; p50 == nil || *p50 != 25 {
+		t.Errorf("Expected p50 of 25ms, got %v", p50)
+	}
+	if p99 := reports[0].P99Ms; p99 == nil || *p99 != 1000 {
+		t.Errorf("Expected p99 of 1000ms, got %v", p99)
+	}
+}
+
+func TestObserve_DefaultTargetAndExpiry(t *testing.T) {
+	now := time.Date(2026, 5, 12, 12, 0, 0, 0, time.UTC)
+	tracker := NewTracker([]Target{{Group: DefaultGroup, Threshold: time.Second, Objective: 0.95}})
+	tracker.now = func() time.Time { return now }
+	tracker.Observe("analytics", http.StatusOK, 10*time.Millisecond)
+
+	// The same ring slot, one retention period later
+	tracker.now = func() time.Time { return now.Add(Retention) }
+	tracker.Observe("analytics", http.StatusOK, 10*time.Millisecond)
+
+	var analytics *models.SLOGroupReport
+	for _, report := range tracker.Report() {
+		if report.Group == "analytics" {
+			analytics = report
+		}
+	}
+	if analytics == nil {
+		t.Fatal("Expected the group to be tracked with the default target")
+	}
+	if last := analytics.Windows[len(analytics.Windows)-1]; last.Requests != 1 {
+		t.Errorf("Expected the expired minute to be dropped, got %d requests", last.Requests)
+	}
+}
+
+func TestMiddleware_GroupsByRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	tracker := NewTracker([]Target{{Group: DefaultGroup, Threshold: time.Second, Objective: 0.99}})
+
+	router := gin.New()
+	router.Use(Middleware(tracker))
+	router.GET("/api/sessions/:id/laps", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/events", func(c *gin.Context) { c.SSEvent("ping", "{}") })
+
+	for _, path := range []string{"/api/sessions/1/laps", "/api/sessions/2/laps", "/api/events", "/nope"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	reports := tracker.Report()
+	if len(reports) != 2 || reports[1].Group != "sessions" || reports[1].Windows[0].Requests != 2 {
+		t.Errorf("Expected two requests under sessions and no group for streams or unmatched routes, got %+v", reports)
+	}
+}
+
+func TestGroup(t *testing.T) {
+	tests := map[string]string{
+		"/api/sessions/:id/laps": "sessions",
+		"/api/me":                "me",
+		"/health":                "health",
+		"/webhooks/strava":       "webhooks",
+	}
+	for path, want := range tests {
+		if got := Group(path); got != want {
+			t.Errorf("Group(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
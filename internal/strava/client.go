@@ -0,0 +1,374 @@
+// Package strava is a minimal client for Strava's OAuth 2.0 and activity APIs
+package strava
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrInvalidGrant is returned when Strava rejects an authorization code, refresh token or
+	// access token, e.g. because the athlete revoked access; the user has to connect again
+	ErrInvalidGrant = errors.New("strava authorization is no longer valid")
+
+	// ErrRateLimited is returned when the application's request quota is used up
+	// Strava counts requests per 15 minutes and per day.
+	ErrRateLimited = errors.New("strava rate limit exceeded")
+
+	// ErrNotFound is returned for activities that were deleted or are not visible to the token
+	ErrNotFound = errors.New("strava activity not found")
+)
+
+// Scope requested when connecting: all activities (including private ones) and uploads
+const Scope = "read,activity:read_all,activity:write"
+
+const (
+	defaultAuthURL   = "https://www.strava.com/oauth/authorize"
+	defaultTokenURL  = "https://www.strava.com/oauth/token"
+	defaultRevokeURL = "https://www.strava.com/oauth/deauthorize"
+	defaultAPIURL    = "https://www.strava.com/api/v3"
+
+	// activitiesPerPage is the largest page Strava serves
+	activitiesPerPage = 200
+)
+
+// Config holds the API application registered at strava.com/settings/api
+type Config struct {
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+}
+
+// Token is an OAuth token pair with the athlete it belongs to
+type Token struct {
+	AccessToken  string
+	RefreshToken string // Strava may rotate it on refresh; always store the latest
+	ExpiresAt    time.Time
+	AthleteID    int64 // Only returned when exchanging an authorization code
+}
+
+// Activity is an activity recorded in Strava
+type Activity struct {
+	ID             int64
+	Name           string
+	Description    string
+	SportType      string // e.g. Run, Ride, WeightTraining
+	StartDate      time.Time
+	ElapsedSeconds int
+	DistanceM      float64
+}
+
+// NewActivity describes a manual activity to create
+type NewActivity struct {
+	Name           string
+	SportType      string
+	StartDate      time.Time
+	ElapsedSeconds int
+	Description    string
+	DistanceM      float64
+}
+
+// Client calls Strava's OAuth and API endpoints
+type Client struct {
+	config     Config
+	httpClient *http.Client
+
+	// Endpoints, overridden in tests
+	AuthURL   string
+	TokenURL  string
+	RevokeURL string
+	APIURL    string
+}
+
+// NewClient creates a Strava client; a nil httpClient uses one with a 30s timeout
+func NewClient(config Config, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: 30 * time.Second}
+	}
+	return &Client{
+		config:     config,
+		httpClient: httpClient,
+		AuthURL:    defaultAuthURL,
+		TokenURL:   defaultTokenURL,
+		RevokeURL:  defaultRevokeURL,
+		APIURL:     defaultAPIURL,
+	}
+}
+
+// Configured reports whether OAuth credentials were provided
+func (c *Client) Configured() bool {
+	return c.config.ClientID != "" && c.config.ClientSecret != "" && c.config.RedirectURL != ""
+}
+
+// AuthCodeURL returns the consent page URL; approval is forced so the athlete can review the
+// requested scope when reconnecting
+func (c *Client) AuthCodeURL(state string) string {
+	params := url.Values{
+		"client_id":       {c.config.ClientID},
+		"redirect_uri":    {c.config.RedirectURL},
+		"response_type":   {"code"},
+		"approval_prompt": {"force"},
+		"scope":           {Scope},
+		"state":           {state},
+	}
+	return c.AuthURL + "?" + params.Encode()
+}
+
+// Exchange trades an authorization code for tokens
+func (c *Client) Exchange(ctx context.Context, code string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type": {"authorization_code"},
+		"code":       {code},
+	})
+}
+
+// Refresh obtains a new access token with a refresh token
+func (c *Client) Refresh(ctx context.Context, refreshToken string) (*Token, error) {
+	return c.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refreshToken},
+	})
+}
+
+func (c *Client) requestToken(ctx context.Context, form url.Values) (*Token, error) {
+	form.Set("client_id", c.config.ClientID)
+	form.Set("client_secret", c.config.ClientSecret)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.TokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	var body struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresAt    int64  `json:"expires_at"`
+		Athlete      struct {
+			ID int64 `json:"id"`
+		} `json:"athlete"`
+		Message string `json:"message"`
+	}
+	status, err := c.do(req, &body)
+	if err != nil {
+		return nil, err
+	}
+	// Strava answers a bad code or refresh token with 400 or 401
+	if status == http.StatusBadRequest || status == http.StatusUnauthorized {
+		return nil, ErrInvalidGrant
+	}
+	if status == http.StatusTooManyRequests {
+		return nil, ErrRateLimited
+	}
+	if status != http.StatusOK || body.AccessToken == "" {
+		return nil, fmt.Errorf("token request failed with status %d: %s", status, body.Message)
+	}
+
+	return &Token{
+		AccessToken:  body.AccessToken,
+		RefreshToken: body.RefreshToken,
+		ExpiresAt:    time.Unix(body.ExpiresAt, 0).UTC(),
+		AthleteID:    body.Athlete.ID,
+	}, nil
+}
+
+// Deauthorize revokes the application's access to the athlete's account
+func (c *Client) Deauthorize(ctx context.Context, accessToken string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.RevokeURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	var body struct{}
+	status, err := c.do(req, &body)
+	if err != nil {
+		return err
+	}
+	if status == http.StatusUnauthorized {
+		return ErrInvalidGrant // Already revoked
+	}
+	if status != http.StatusOK {
+		return fmt.Errorf("deauthorize request failed with status %d", status)
+	}
+	return nil
+}
+
+// stravaActivity is the activity representation in API responses
+type stravaActivity struct {
+	ID          int64   `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	SportType   string  `json:"sport_type"`
+	Type        string  `json:"type"` // Deprecated in favor of sport_type, still set on older activities
+	StartDate   string  `json:"start_date"`
+	ElapsedTime int     `json:"elapsed_time"`
+	Distance    float64 `json:"distance"`
+}
+
+func (a *stravaActivity) parse() (*Activity, error) {
+	start, err := time.Parse(time.RFC3339, a.StartDate)
+	if err != nil {
+		return nil, fmt.Errorf("activity %d: invalid start date %q", a.ID, a.StartDate)
+	}
+	sport := a.SportType
+	if sport == "" {
+		sport = a.Type
+	}
+	return &Activity{
+		ID:             a.ID,
+		Name:           a.Name,
+		Description:    a.Description,
+		SportType:      sport,
+		StartDate:      start.UTC(),
+		ElapsedSeconds: a.ElapsedTime,
+		DistanceM:      a.Distance,
+	}, nil
+}
+
+// Activities lists the athlete's activities that started between after and before,
+// following pagination. Summaries do not include descriptions.
+func (c *Client) Activities(ctx context.Context, accessToken string, after, before time.Time) ([]*Activity, error) {
+	params := url.Values{
+		"after":    {strconv.FormatInt(after.Unix(), 10)},
+		"before":   {strconv.FormatInt(before.Unix(), 10)},
+		"per_page": {strconv.Itoa(activitiesPerPage)},
+	}
+
+	var activities []*Activity
+	for page := 1; ; page++ {
+		params.Set("page", strconv.Itoa(page))
+
+		var summaries []stravaActivity
+		if err := c.call(ctx, http.MethodGet, accessToken, "/athlete/activities?"+params.Encode(), nil, &summaries); err != nil {
+			return nil, err
+		}
+		for i := range summaries {
+			activity, err := summaries[i].parse()
+			if err != nil {
+				return nil, err
+			}
+			activities = append(activities, activity)
+		}
+
+		if len(summaries) < activitiesPerPage {
+			return activities, nil
+		}
+	}
+}
+
+// Activity retrieves one activity
+func (c *Client) Activity(ctx context.Context, accessToken string, id int64) (*Activity, error) {
+	var detail stravaActivity
+	if err := c.call(ctx, http.MethodGet, accessToken, "/activities/"+strconv.FormatInt(id, 10), nil, &detail); err != nil {
+		return nil, err
+	}
+	return detail.parse()
+}
+
+// CreateActivity creates a manual activity and returns its ID
+func (c *Client) CreateActivity(ctx context.Context, accessToken string, activity *NewActivity) (int64, error) {
+	form := url.Values{
+		"name":             {activity.Name},
+		"sport_type":       {activity.SportType},
+		"start_date_local": {activity.StartDate.UTC().Format(time.RFC3339)},
+		"elapsed_time":     {strconv.Itoa(activity.ElapsedSeconds)},
+	}
+	if activity.Description != "" {
+		form.Set("description", activity.Description)
+	}
+	if activity.DistanceM > 0 {
+		form.Set("distance", strconv.FormatFloat(activity.DistanceM, 'f', 1, 64))
+	}
+
+	var created stravaActivity
+	if err := c.call(ctx, http.MethodPost, accessToken, "/activities", form, &created); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, accessToken string, path string, form url.Values, out any) error {
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.APIURL+path, body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+
+	status, err := c.do(req, out)
+	if err != nil {
+		return err
+	}
+	switch status {
+	case http.StatusOK, http.StatusCreated:
+		return nil
+	case http.StatusUnauthorized:
+		return ErrInvalidGrant
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return fmt.Errorf("strava request %s %s failed with status %d", method, strings.SplitN(path, "?", 2)[0], status)
+}
+
+// do sends the request and decodes a JSON body into out, whatever the status
+func (c *Client) do(req *http.Request, out any) (int, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return 0, err
+	}
+	ok := resp.StatusCode == http.StatusOK || resp.StatusCode == http.StatusCreated
+	if len(data) > 0 && json.Unmarshal(data, out) != nil && ok {
+		return 0, fmt.Errorf("invalid response from %s", req.URL.Host)
+	}
+	return resp.StatusCode, nil
+}
+
+// sportTypes maps the names fitapi gives cardio sessions to Strava sport types
+var sportTypes = map[string]string{
+	"running":  "Run",
+	"run":      "Run",
+	"cycling":  "Ride",
+	"biking":   "Ride",
+	"ride":     "Ride",
+	"walking":  "Walk",
+	"walk":     "Walk",
+	"hiking":   "Hike",
+	"swimming": "Swim",
+	"swim":     "Swim",
+	"rowing":   "Rowing",
+}
+
+// SportType picks the Strava sport type for a fitapi session from its name and whether it
+// is cardio; strength sessions are WeightTraining and unrecognized cardio is Workout
+func SportType(name string, cardio bool) string {
+	if !cardio {
+		return "WeightTraining"
+	}
+	if sport, ok := sportTypes[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return sport
+	}
+	return "Workout"
+}
@@ -0,0 +1,163 @@
+package strava
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	client := NewClient(Config{ClientID: "id", ClientSecret: "secret", RedirectURL: "https://app.example.com/cb"}, server.Client())
+	client.TokenURL = server.URL + "/token"
+	client.RevokeURL = server.URL + "/deauthorize"
+	client.APIURL = server.URL + "/api"
+	return client
+}
+
+func TestExchange(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "code" || r.PostForm.Get("client_secret") != "secret" {
+			t.Errorf("Unexpected token request: %v", r.PostForm)
+		}
+		w.Write([]byte(`{"access_token":"access","refresh_token":"refresh","expires_at":1778688000,"athlete":{"id":134815}}`))
+	})
+
+	token, err := client.Exchange(context.Background(), "code")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token.AccessToken != "access" || token.RefreshToken != "refresh" || token.AthleteID != 134815 {
+		t.Errorf("Unexpected token %+v", token)
+	}
+	if want := time.Unix(1778688000, 0).UTC(); !token.ExpiresAt.Equal(want) {
+		t.Errorf("Expected expiry %v, got %v", want, token.ExpiresAt)
+	}
+}
+
+func TestRefresh_InvalidGrant(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"message":"Bad Request","errors":[{"resource":"RefreshToken","field":"refresh_token","code":"invalid"}]}`))
+	})
+
+	if _, err := client.Refresh(context.Background(), "refresh"); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Expected ErrInvalidGrant, got %v", err)
+	}
+}
+
+func TestActivities_FollowsPages(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("page") != "1" {
+			w.Write([]byte(`[{"id":2,"name":"Evening Ride","type":"Ride","start_date":"2026-05-12T17:00:00Z","elapsed_time":3600,"distance":30000}]`))
+			return
+		}
+		// A full first page, so the client asks for the next one
+		activities := make([]string, activitiesPerPage)
+		for i := range activities {
+			activities[i] = fmt.Sprintf(`{"id":%d,"name":"Run","sport_type":"Run","start_date":"2026-05-01T07:00:00Z","elapsed_time":1800}`, 100+i)
+		}
+		w.Write([]byte("[" + strings.Join(activities, ",") + "]"))
+	})
+
+	activities, err := client.Activities(context.Background(), "access", time.Now().Add(-time.Hour), time.Now())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(activities) != activitiesPerPage+1 {
+		t.Fatalf("Expected %d activities across pages, got %d", activitiesPerPage+1, len(activities))
+	}
+	last := activities[len(activities)-1]
+	if last.SportType != "Ride" || last.DistanceM != 30000 || last.ElapsedSeconds != 3600 {
+		t.Errorf("Expected the legacy type to fill in the sport type, got %+v", last)
+	}
+
+	if _, err := client.Activities(context.Background(), "expired", time.Now(), time.Now()); !errors.Is(err, ErrInvalidGrant) {
+		t.Errorf("Expected ErrInvalidGrant for a rejected access token, got %v", err)
+	}
+}
+
+func TestActivity_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr error
+	}{
+		{"deleted", http.StatusNotFound, ErrNotFound},
+		{"rate limited", http.StatusTooManyRequests, ErrRateLimited},
+		{"revoked", http.StatusUnauthorized, ErrInvalidGrant},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(`{"message":"error"}`))
+			})
+
+			if _, err := client.Activity(context.Background(), "access", 1); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestCreateActivity(t *testing.T) {
+	client := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/activities" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("Expected a form body, got %v", err)
+		}
+		if r.PostForm.Get("sport_type") != "Run" || r.PostForm.Get("elapsed_time") != "1800" || r.PostForm.Get("distance") != "5000.0" {
+			t.Errorf("Unexpected activity %v", r.PostForm)
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id":987}`))
+	})
+
+	id, err := client.CreateActivity(context.Background(), "access", &NewActivity{
+		Name:           "Tempo run",
+		SportType:      "Run",
+		StartDate:      time.Date(2026, 5, 12, 7, 0, 0, 0, time.UTC),
+		ElapsedSeconds: 1800,
+		DistanceM:      5000,
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if id != 987 {
+		t.Errorf("Expected activity 987, got %d", id)
+	}
+}
+
+func TestSportType(t *testing.T) {
+	tests := []struct {
+		name   string
+		cardio bool
+		want   string
+	}{
+		{"Push day", false, "WeightTraining"},
+		{"Running", true, "Run"},
+		{" cycling ", true, "Ride"},
+		{"Stairs", true, "Workout"},
+	}
+
+	for _, tt := range tests {
+		if got := SportType(tt.name, tt.cardio); got != tt.want {
+			t.Errorf("SportType(%q, %v) = %q, want %q", tt.name, tt.cardio, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+// Package webhooks signs and sends outgoing webhook deliveries
+//
+// Deliveries follow the Standard Webhooks format (https://www.standardwebhooks.com): the
+// webhook-id, webhook-timestamp and webhook-signature headers, with an HMAC-SHA256 of
+// "<id>.<timestamp>.<body>" keyed by the endpoint's whsec_ secret. Receivers can verify
+// them with any Standard Webhooks library, or webhookverify.StandardWebhooksScheme.
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var (
+	// ErrUnsafeURL is returned for endpoint URLs that are not https or point at a private,
+	// loopback or link-local address
+	ErrUnsafeURL = errors.New("webhook url must use https and a public host")
+
+	// ErrGone is returned when the endpoint answers 410 Gone, asking not to be called again
+	ErrGone = errors.New("webhook endpoint is gone")
+)
+
+// secretPrefix marks webhook signing secrets
+const secretPrefix = "whsec_"
+
+// Message is an event to deliver
+type Message struct {
+	ID        string // Same for every attempt, so receivers can drop duplicates
+	Type      string
+	Timestamp time.Time // When the event happened
+	Data      json.RawMessage
+}
+
+// NewSecret generates an endpoint signing secret
+func NewSecret() (string, error) {
+	key := make([]byte, 24)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.StdEncoding.EncodeToString(key), nil
+}
+
+// Sign computes the webhook-signature header value for a delivery
+func Sign(secret string, id string, timestamp time.Time, body []byte) (string, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(secret, secretPrefix))
+	if err != nil {
+		return "", fmt.Errorf("invalid webhook secret: %w", err)
+	}
+
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id + "." + strconv.FormatInt(timestamp.Unix(), 10) + "."))
+	mac.Write(body)
+	return "v1," + base64.StdEncoding.EncodeToString(mac.Sum(nil)), nil
+}
+
+// ValidateURL checks an endpoint URL before it is registered. Hosts are checked again when
+// connecting (see SafeClient), since DNS can change after registration.
+func ValidateURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme != "https" || u.Hostname() == "" || u.User != nil {
+		return ErrUnsafeURL
+	}
+
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") || strings.HasSuffix(host, ".internal") {
+		return ErrUnsafeURL
+	}
+	if ip := net.ParseIP(host); ip != nil && !isPublic(ip) {
+		return ErrUnsafeURL
+	}
+	return nil
+}
+
+// SafeClient returns an HTTP client for deliveries that refuses to connect to non-public
+// addresses and does not follow redirects
+func SafeClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 10 * time.Second,
+		Control: func(network, address string, conn syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !isPublic(ip) {
+				return ErrUnsafeURL
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+func isPublic(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() || ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// Sender posts signed messages to endpoints
+type Sender struct {
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewSender creates a sender; use SafeClient outside of tests
+func NewSender(httpClient *http.Client) *Sender {
+	return &Sender{httpClient: httpClient, now: time.Now}
+}
+
+// Send delivers a message and returns the response status. Any status other than 2xx is an
+// error; the status is 0 when no response was received.
+func (s *Sender) Send(ctx context.Context, endpointURL string, secret string, msg *Message) (int, error) {
+	body, err := json.Marshal(map[string]any{
+		"id":        msg.ID,
+		"type":      msg.Type,
+		"timestamp": msg.Timestamp.UTC(),
+		"data":      msg.Data,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	sentAt := s.now()
+	signature, err := Sign(secret, msg.ID, sentAt, body)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpointURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "fitapi-webhooks/1")
+	req.Header.Set("webhook-id", msg.ID)
+	req.Header.Set("webhook-timestamp", strconv.FormatInt(sentAt.Unix(), 10))
+	req.Header.Set("webhook-signature", signature)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return resp.StatusCode, nil
+	case resp.StatusCode == http.StatusGone:
+		return resp.StatusCode, ErrGone
+	}
+	return resp.StatusCode, fmt.Errorf("endpoint responded with status %d", resp.StatusCode)
+}
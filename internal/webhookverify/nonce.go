@@ -0,0 +1,73 @@
+package webhookverify
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// NonceStore remembers processed delivery IDs
+// A shared implementation (database, Redis) is needed when several API instances
+// receive the same provider's webhooks; MemoryNonceStore covers a single instance.
+type NonceStore interface {
+	// Remember stores id for ttl and reports whether it was not seen before
+	Remember(ctx context.Context, id string, ttl time.Duration) (bool, error)
+	// Forget removes id so a retried delivery is accepted
+	Forget(ctx context.Context, id string)
+}
+
+// MemoryNonceStore is an in-process NonceStore with lazy expiry
+type MemoryNonceStore struct {
+	mu        sync.Mutex
+	entries   map[string]time.Time // id -> expiry
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// sweepInterval bounds how often expired entries are scanned for
+const sweepInterval = time.Minute
+
+// NewMemoryNonceStore creates an empty in-memory nonce store
+func NewMemoryNonceStore() *MemoryNonceStore {
+	return &MemoryNonceStore{
+		entries: make(map[string]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Remember stores id for ttl and reports whether it was not seen before
+func (s *MemoryNonceStore) Remember(ctx context.Context, id string, ttl time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	if expiry, ok := s.entries[id]; ok && now.Before(expiry) {
+		return false, nil
+	}
+
+	s.entries[id] = now.Add(ttl)
+	return true, nil
+}
+
+// Forget removes id so a retried delivery is accepted
+func (s *MemoryNonceStore) Forget(ctx context.Context, id string) {
+	s.mu.Lock()
+	delete(s.entries, id)
+	s.mu.Unlock()
+}
+
+// sweep drops expired entries at most once per sweepInterval; callers must hold the lock
+func (s *MemoryNonceStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for id, expiry := range s.entries {
+		if !now.Before(expiry) {
+			delete(s.entries, id)
+		}
+	}
+}
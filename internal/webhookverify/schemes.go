@@ -0,0 +1,179 @@
+package webhookverify
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// StripeScheme verifies Stripe's "Stripe-Signature: t=<unix>,v1=<hex>" header
+// The signed payload is "<t>.<body>". Stripe re-signs every retry, so the
+// signature itself serves as the delivery ID.
+type StripeScheme struct {
+	Secret string
+}
+
+// Verify implements Scheme
+func (s StripeScheme) Verify(header http.Header, body []byte) (Delivery, error) {
+	value := header.Get("Stripe-Signature")
+	if value == "" {
+		return Delivery{}, ErrMissingSignature
+	}
+
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(value, ",") {
+		key, val, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = val
+		case "v1":
+			signatures = append(signatures, val)
+		}
+	}
+	if timestamp == "" || len(signatures) == 0 {
+		return Delivery{}, ErrMissingSignature
+	}
+
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	expected := computeHMAC([]byte(s.Secret), timestamp+"."+string(body))
+	for _, signature := range signatures {
+		actual, err := hex.DecodeString(signature)
+		if err == nil && equalMAC(expected, actual) {
+			return Delivery{ID: "stripe:" + signature, Timestamp: signedAt}, nil
+		}
+	}
+
+	return Delivery{}, ErrInvalidSignature
+}
+
+// StandardWebhooksScheme verifies the Standard Webhooks format used by Supabase auth hooks
+// Headers: webhook-id, webhook-timestamp, webhook-signature ("v1,<base64>" entries,
+// space separated). The signed payload is "<id>.<timestamp>.<body>" and the secret
+// is the base64 part of "v1,whsec_<base64>" (prefixes are optional).
+type StandardWebhooksScheme struct {
+	Secret string
+}
+
+// Verify implements Scheme
+func (s StandardWebhooksScheme) Verify(header http.Header, body []byte) (Delivery, error) {
+	id := header.Get("webhook-id")
+	timestamp := header.Get("webhook-timestamp")
+	value := header.Get("webhook-signature")
+	if id == "" || timestamp == "" || value == "" {
+		return Delivery{}, ErrMissingSignature
+	}
+
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	secret := strings.TrimPrefix(strings.TrimPrefix(s.Secret, "v1,"), "whsec_")
+	key, err := base64.StdEncoding.DecodeString(secret)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	expected := computeHMAC(key, id+"."+timestamp+"."+string(body))
+	for _, entry := range strings.Fields(value) {
+		version, signature, ok := strings.Cut(entry, ",")
+		if !ok || version != "v1" {
+			continue
+		}
+		actual, err := base64.StdEncoding.DecodeString(signature)
+		if err == nil && equalMAC(expected, actual) {
+			return Delivery{ID: "standard:" + id, Timestamp: signedAt}, nil
+		}
+	}
+
+	return Delivery{}, ErrInvalidSignature
+}
+
+// HMACHeaderScheme covers providers (e.g. wearable vendors) that send a hex
+// HMAC-SHA256 of "<timestamp>.<body>" plus a timestamp in headers. A delivery ID header,
+// if the provider sends one, is not signed, so the signature serves as the delivery ID.
+type HMACHeaderScheme struct {
+	Secret          string
+	SignatureHeader string
+	TimestampHeader string
+}
+
+// Verify implements Scheme
+func (s HMACHeaderScheme) Verify(header http.Header, body []byte) (Delivery, error) {
+	signature := strings.TrimPrefix(header.Get(s.SignatureHeader), "sha256=")
+	timestamp := header.Get(s.TimestampHeader)
+	if signature == "" || timestamp == "" {
+		return Delivery{}, ErrMissingSignature
+	}
+
+	signedAt, err := parseUnix(timestamp)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	actual, err := hex.DecodeString(signature)
+	if err != nil {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	expected := computeHMAC([]byte(s.Secret), timestamp+"."+string(body))
+	if !equalMAC(expected, actual) {
+		return Delivery{}, ErrInvalidSignature
+	}
+	// Re-encoded so the same signature in another letter case is the same delivery
+	return Delivery{ID: "hmac:" + hex.EncodeToString(actual), Timestamp: signedAt}, nil
+}
+
+// StravaScheme checks Strava push subscription events. Strava does not sign them, so an
+// event is only checked to name our subscription and to be recent, and the body itself
+// serves as the delivery ID. Receivers must still treat events as hints and confirm them
+// with the Strava API before acting.
+type StravaScheme struct {
+	// SubscriptionID is our push subscription's ID; 0 rejects every event
+	SubscriptionID int64
+}
+
+// Verify implements Scheme
+func (s StravaScheme) Verify(header http.Header, body []byte) (Delivery, error) {
+	var event struct {
+		SubscriptionID int64 `json:"subscription_id"`
+		EventTime      int64 `json:"event_time"`
+	}
+	if err := json.Unmarshal(body, &event); err != nil || event.SubscriptionID == 0 || event.EventTime == 0 {
+		return Delivery{}, ErrMissingSignature
+	}
+	if s.SubscriptionID == 0 || event.SubscriptionID != s.SubscriptionID {
+		return Delivery{}, ErrInvalidSignature
+	}
+
+	sum := sha256.Sum256(body)
+	return Delivery{ID: "strava:" + hex.EncodeToString(sum[:]), Timestamp: time.Unix(event.EventTime, 0)}, nil
+}
+
+func computeHMAC(key []byte, payload string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+func parseUnix(value string) (time.Time, error) {
+	seconds, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(seconds, 0), nil
+}
@@ -0,0 +1,127 @@
+// Package webhookverify centralizes security checks for inbound webhooks
+// (Supabase auth events, Stripe, Strava, wearable providers): per-provider signature
+// schemes, timestamp tolerance, and a nonce cache that rejects replayed deliveries.
+package webhookverify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook signature missing")
+	ErrInvalidSignature = errors.New("webhook signature invalid")
+	ErrStaleTimestamp   = errors.New("webhook timestamp outside tolerance")
+	ErrReplay           = errors.New("webhook delivery already processed")
+)
+
+// DefaultTolerance is how far a delivery timestamp may drift from the server clock
+const DefaultTolerance = 5 * time.Minute
+
+// maxBodyBytes bounds how much of a webhook body is read for verification
+const maxBodyBytes = 1 << 20 // 1 MiB
+
+// Delivery is what a scheme extracts from a verified request
+type Delivery struct {
+	ID        string    // Unique delivery identifier used for replay detection
+	Timestamp time.Time // When the provider signed the delivery
+}
+
+// Scheme verifies one provider's signature format
+type Scheme interface {
+	Verify(header http.Header, body []byte) (Delivery, error)
+}
+
+// Verifier applies a scheme plus timestamp and replay checks
+type Verifier struct {
+	scheme    Scheme
+	nonces    NonceStore
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+// NewVerifier creates a verifier; tolerance <= 0 uses DefaultTolerance
+func NewVerifier(scheme Scheme, nonces NonceStore, tolerance time.Duration) *Verifier {
+	if tolerance <= 0 {
+		tolerance = DefaultTolerance
+	}
+	return &Verifier{
+		scheme:    scheme,
+		nonces:    nonces,
+		tolerance: tolerance,
+		now:       time.Now,
+	}
+}
+
+// Verify checks the signature, timestamp window and replay cache for a delivery
+func (v *Verifier) Verify(ctx context.Context, header http.Header, body []byte) (Delivery, error) {
+	delivery, err := v.scheme.Verify(header, body)
+	if err != nil {
+		return Delivery{}, err
+	}
+
+	drift := v.now().Sub(delivery.Timestamp)
+	if drift > v.tolerance || drift < -v.tolerance {
+		return Delivery{}, ErrStaleTimestamp
+	}
+
+	// Remember the delivery at least as long as it could pass the timestamp check
+	fresh, err := v.nonces.Remember(ctx, delivery.ID, 2*v.tolerance)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("failed to check replay cache: %w", err)
+	}
+	if !fresh {
+		return Delivery{}, ErrReplay
+	}
+
+	return delivery, nil
+}
+
+// Middleware verifies inbound webhooks before the handler runs
+// The body is restored so handlers can bind it normally. If the handler fails
+// with a 5xx the delivery is forgotten again, so the provider's retry is accepted.
+func Middleware(v *Verifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBodyBytes))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "failed to read webhook body"})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		delivery, err := v.Verify(c.Request.Context(), c.Request.Header, body)
+		if err != nil {
+			switch {
+			case errors.Is(err, ErrReplay):
+				c.JSON(http.StatusConflict, gin.H{"error": "webhook delivery already processed"})
+			case errors.Is(err, ErrMissingSignature), errors.Is(err, ErrInvalidSignature), errors.Is(err, ErrStaleTimestamp):
+				c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+			default:
+				c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify webhook"})
+			}
+			c.Abort()
+			return
+		}
+
+		c.Set("webhook_delivery_id", delivery.ID)
+		c.Next()
+
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			v.nonces.Forget(c.Request.Context(), delivery.ID)
+		}
+	}
+}
+
+// equalMAC compares signatures in constant time
+func equalMAC(expected, actual []byte) bool {
+	return hmac.Equal(expected, actual)
+}
@@ -0,0 +1,182 @@
+package webhookverify
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func stripeHeader(secret string, ts time.Time, body string) http.Header {
+	t := strconv.FormatInt(ts.Unix(), 10)
+	sig := hex.EncodeToString(computeHMAC([]byte(secret), t+"."+body))
+	return http.Header{"Stripe-Signature": {"t=" + t + ",v1=" + sig}}
+}
+
+func TestStripeScheme(t *testing.T) {
+	body := `{"id":"evt_1"}`
+	now := time.Now()
+
+	tests := []struct {
+		name    string
+		header  http.Header
+		wantErr error
+	}{
+		{"valid", stripeHeader("whsec_test", now, body), nil},
+		{"wrong secret", stripeHeader("other", now, body), ErrInvalidSignature},
+		{"missing header", http.Header{}, ErrMissingSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := StripeScheme{Secret: "whsec_test"}.Verify(tt.header, []byte(body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestStandardWebhooksScheme(t *testing.T) {
+	key := []byte("supabase-hook-secret")
+	secret := "v1,whsec_" + base64.StdEncoding.EncodeToString(key)
+	body := `{"type":"user.created"}`
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	sig := base64.StdEncoding.EncodeToString(computeHMAC(key, "msg_1."+ts+"."+body))
+
+	header := http.Header{
+		"Webhook-Id":        {"msg_1"},
+		"Webhook-Timestamp": {ts},
+		"Webhook-Signature": {"v1,bogus v1," + sig},
+	}
+
+	delivery, err := StandardWebhooksScheme{Secret: secret}.Verify(header, []byte(body))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if delivery.ID != "standard:msg_1" {
+		t.Errorf("Expected delivery ID 'standard:msg_1', got '%s'", delivery.ID)
+	}
+
+	_, err = StandardWebhooksScheme{Secret: secret}.Verify(header, []byte(body+"tampered"))
+	if !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for tampered body, got %v", err)
+	}
+}
+
+func TestHMACHeaderScheme(t *testing.T) {
+	scheme := HMACHeaderScheme{Secret: "wearable-secret", SignatureHeader: "X-Signature", TimestampHeader: "X-Timestamp"}
+	body := `{"event":"sleep.created"}`
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	sig := hex.EncodeToString(computeHMAC([]byte("wearable-secret"), ts+"."+body))
+
+	delivery, err := scheme.Verify(http.Header{"X-Signature": {"sha256=" + sig}, "X-Timestamp": {ts}, "X-Delivery-Id": {"d1"}}, []byte(body))
+	if err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+
+	// The ID header is unsigned, so changing it must not make a replay a new delivery
+	replay, err := scheme.Verify(http.Header{"X-Signature": {strings.ToUpper(sig)}, "X-Timestamp": {ts}, "X-Delivery-Id": {"d2"}}, []byte(body))
+	if err != nil {
+		t.Fatalf("Expected valid signature, got %v", err)
+	}
+	if replay.ID != delivery.ID {
+		t.Errorf("Expected the replay to keep delivery ID %q, got %q", delivery.ID, replay.ID)
+	}
+
+	if _, err := scheme.Verify(http.Header{"X-Signature": {sig}, "X-Timestamp": {ts}}, []byte(`{"event":"other"}`)); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("Expected ErrInvalidSignature for a changed body, got %v", err)
+	}
+}
+
+func TestStravaScheme(t *testing.T) {
+	ts := time.Now().Unix()
+	event := func(subscriptionID int64) []byte {
+		return []byte(`{"object_type":"activity","object_id":1,"aspect_type":"create","owner_id":2,"subscription_id":` +
+			strconv.FormatInt(subscriptionID, 10) + `,"event_time":` + strconv.FormatInt(ts, 10) + `}`)
+	}
+
+	tests := []struct {
+		name    string
+		scheme  StravaScheme
+		body    []byte
+		wantErr error
+	}{
+		{"ours", StravaScheme{SubscriptionID: 42}, event(42), nil},
+		{"another subscription", StravaScheme{SubscriptionID: 42}, event(7), ErrInvalidSignature},
+		{"no subscription configured", StravaScheme{}, event(42), ErrInvalidSignature},
+		{"not an event", StravaScheme{SubscriptionID: 42}, []byte(`{}`), ErrMissingSignature},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delivery, err := tt.scheme.Verify(http.Header{}, tt.body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && (delivery.Timestamp.Unix() != ts || !strings.HasPrefix(delivery.ID, "strava:")) {
+				t.Errorf("Unexpected delivery: %+v", delivery)
+			}
+		})
+	}
+}
+
+func TestVerifier_RejectsStaleAndReplayed(t *testing.T) {
+	body := `{"id":"evt_1"}`
+	verifier := NewVerifier(StripeScheme{Secret: "s"}, NewMemoryNonceStore(), time.Minute)
+
+	stale := stripeHeader("s", time.Now().Add(-10*time.Minute), body)
+	if _, err := verifier.Verify(context.Background(), stale, []byte(body)); !errors.Is(err, ErrStaleTimestamp) {
+		t.Errorf("Expected ErrStaleTimestamp, got %v", err)
+	}
+
+	fresh := stripeHeader("s", time.Now(), body)
+	if _, err := verifier.Verify(context.Background(), fresh, []byte(body)); err != nil {
+		t.Fatalf("Expected first delivery to pass, got %v", err)
+	}
+	if _, err := verifier.Verify(context.Background(), fresh, []byte(body)); !errors.Is(err, ErrReplay) {
+		t.Errorf("Expected ErrReplay, got %v", err)
+	}
+}
+
+func TestMiddleware_ForgetsDeliveryOnServerError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	body := `{"id":"evt_1"}`
+	header := stripeHeader("s", time.Now(), body)
+	verifier := NewVerifier(StripeScheme{Secret: "s"}, NewMemoryNonceStore(), time.Minute)
+
+	status := http.StatusInternalServerError
+	router := gin.New()
+	router.POST("/webhooks/stripe", Middleware(verifier), func(c *gin.Context) {
+		c.Status(status)
+	})
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/stripe", strings.NewReader(body))
+		req.Header = header.Clone()
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := send(); code != http.StatusInternalServerError {
+		t.Fatalf("Expected 500 from handler, got %d", code)
+	}
+
+	status = http.StatusOK
+	if code := send(); code != http.StatusOK {
+		t.Errorf("Expected retry after failure to be accepted, got %d", code)
+	}
+	if code := send(); code != http.StatusConflict {
+		t.Errorf("Expected replay after success to be rejected, got %d", code)
+	}
+}
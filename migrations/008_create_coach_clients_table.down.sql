@@ -0,0 +1,3 @@
+-- Rollback: Drop coach_clients table
+DROP TRIGGER IF EXISTS update_coach_clients_updated_at ON coach_clients;
+DROP TABLE IF EXISTS coach_clients CASCADE;
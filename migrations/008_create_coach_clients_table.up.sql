@@ -0,0 +1,36 @@
+-- Create coach_clients table
+-- Links a coach to a client with delegated access to the client's data
+CREATE TABLE IF NOT EXISTS coach_clients (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    coach_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    client_id UUID REFERENCES auth.users(id) ON DELETE CASCADE,  -- Set when the invitation is accepted
+    client_email TEXT NOT NULL,  -- Invited address (lowercase)
+
+    -- Invitation lifecycle
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'active', 'declined', 'revoked')),
+
+    -- Delegated permissions (read access is implied by an active relationship)
+    can_write BOOLEAN NOT NULL DEFAULT FALSE,
+
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for a coach's client list
+CREATE INDEX idx_coach_clients_coach ON coach_clients(coach_id);
+
+-- Index for a client's coaches
+CREATE INDEX idx_coach_clients_client ON coach_clients(client_id) WHERE client_id IS NOT NULL;
+
+-- Index for pending invitations addressed to an email
+CREATE INDEX idx_coach_clients_email ON coach_clients(client_email) WHERE status = 'pending';
+
+-- Only one open invitation or relationship per coach and client email
+CREATE UNIQUE INDEX idx_coach_clients_open ON coach_clients(coach_id, client_email) WHERE status IN ('pending', 'active');
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_coach_clients_updated_at
+    BEFORE UPDATE ON coach_clients
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,11 @@
+-- Rollback: Drop organization tables and org-scoped columns
+DROP INDEX IF EXISTS idx_workouts_organization;
+DROP INDEX IF EXISTS idx_equipment_organization;
+ALTER TABLE workouts DROP COLUMN IF EXISTS organization_id;
+ALTER TABLE equipment DROP COLUMN IF EXISTS organization_id;
+
+DROP TRIGGER IF EXISTS update_organization_members_updated_at ON organization_members;
+DROP TABLE IF EXISTS organization_members CASCADE;
+
+DROP TRIGGER IF EXISTS update_organizations_updated_at ON organizations;
+DROP TABLE IF EXISTS organizations CASCADE;
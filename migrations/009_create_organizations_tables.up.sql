@@ -0,0 +1,40 @@
+-- Create organizations and organization_members tables
+-- Gyms/organizations share equipment and workout templates across their members
+CREATE TABLE IF NOT EXISTS organizations (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    description TEXT,
+    created_by UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    role TEXT NOT NULL DEFAULT 'member' CHECK (role IN ('owner', 'trainer', 'member')),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (organization_id, user_id)
+);
+
+-- Index for "which organizations does this user belong to?"
+CREATE INDEX idx_organization_members_user ON organization_members(user_id);
+
+-- Org-scoped shared resources (NULL means the resource is personal)
+ALTER TABLE equipment ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+ALTER TABLE workouts ADD COLUMN organization_id UUID REFERENCES organizations(id) ON DELETE CASCADE;
+
+CREATE INDEX idx_equipment_organization ON equipment(organization_id) WHERE organization_id IS NOT NULL;
+CREATE INDEX idx_workouts_organization ON workouts(organization_id) WHERE organization_id IS NOT NULL;
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_organizations_updated_at
+    BEFORE UPDATE ON organizations
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TRIGGER update_organization_members_updated_at
+    BEFORE UPDATE ON organization_members
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Rollback: Drop revoked_tokens table
+DROP TABLE IF EXISTS revoked_tokens CASCADE;
@@ -0,0 +1,11 @@
+-- Create revoked_tokens table
+-- Tokens rejected before their natural expiry (logout, compromise)
+CREATE TABLE IF NOT EXISTS revoked_tokens (
+    token_id TEXT PRIMARY KEY,  -- jti claim, or SHA-256 of the token when no jti is present
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    expires_at TIMESTAMPTZ NOT NULL,  -- Original token expiry; the row is useless afterwards
+    revoked_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for loading and purging unexpired revocations
+CREATE INDEX idx_revoked_tokens_expires ON revoked_tokens(expires_at);
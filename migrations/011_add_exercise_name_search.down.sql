@@ -0,0 +1,4 @@
+-- Rollback: Drop exercise name search index and helper
+-- The extensions are left installed since other schemas may rely on them
+DROP INDEX IF EXISTS idx_exercises_name_trgm;
+DROP FUNCTION IF EXISTS immutable_unaccent(TEXT);
@@ -0,0 +1,18 @@
+-- Add accent-insensitive fuzzy search on exercise names
+-- unaccent folds "presión" to "presion"; pg_trgm scores "pres de banca" against "press banca"
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is only STABLE, so index expressions need an IMMUTABLE wrapper
+-- that pins the dictionary explicitly
+CREATE OR REPLACE FUNCTION immutable_unaccent(TEXT)
+RETURNS TEXT
+LANGUAGE sql
+IMMUTABLE PARALLEL SAFE STRICT
+AS $$
+    SELECT unaccent('unaccent', $1)
+$$;
+
+-- Trigram index for substring (LIKE) and similarity lookups on normalized names
+CREATE INDEX idx_exercises_name_trgm
+    ON exercises USING GIN (immutable_unaccent(lower(name)) gin_trgm_ops);
@@ -0,0 +1,6 @@
+-- Rollback: Drop challenge tables
+DROP TABLE IF EXISTS challenge_completions CASCADE;
+DROP TABLE IF EXISTS challenge_participants CASCADE;
+
+DROP TRIGGER IF EXISTS update_challenges_updated_at ON challenges;
+DROP TABLE IF EXISTS challenges CASCADE;
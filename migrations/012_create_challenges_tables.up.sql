@@ -0,0 +1,47 @@
+-- Create challenges, challenge_participants and challenge_completions tables
+-- Time-boxed focus programs (e.g. 30-day squat challenge) that users join and check off daily
+CREATE TABLE IF NOT EXISTS challenges (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name TEXT NOT NULL,
+    description TEXT,
+    daily_target INTEGER CHECK (daily_target > 0),  -- e.g. 100 squats or 10000 steps per day
+    unit TEXT,                                        -- Unit of daily_target ("reps", "steps")
+    starts_on DATE NOT NULL,
+    ends_on DATE NOT NULL,
+    created_by UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_on >= starts_on)
+);
+
+CREATE TABLE IF NOT EXISTS challenge_participants (
+    challenge_id UUID NOT NULL REFERENCES challenges(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    joined_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,  -- Set once every day of the challenge has been completed
+    PRIMARY KEY (challenge_id, user_id)
+);
+
+-- One row per participant per completed day; leaving a challenge discards progress
+CREATE TABLE IF NOT EXISTS challenge_completions (
+    challenge_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    day DATE NOT NULL,
+    value INTEGER CHECK (value >= 0),  -- Amount achieved that day, in the challenge unit
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (challenge_id, user_id, day),
+    FOREIGN KEY (challenge_id, user_id)
+        REFERENCES challenge_participants(challenge_id, user_id) ON DELETE CASCADE
+);
+
+-- Index for listing active and upcoming challenges
+CREATE INDEX idx_challenges_ends_on ON challenges(ends_on);
+
+-- Index for "which challenges has this user joined?"
+CREATE INDEX idx_challenge_participants_user ON challenge_participants(user_id);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_challenges_updated_at
+    BEFORE UPDATE ON challenges
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
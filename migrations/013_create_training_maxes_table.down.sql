@@ -0,0 +1,5 @@
+-- Rollback: Drop training_maxes table and template reference
+ALTER TABLE workout_exercises DROP COLUMN IF EXISTS training_max_name;
+
+DROP TRIGGER IF EXISTS update_training_maxes_updated_at ON training_maxes;
+DROP TABLE IF EXISTS training_maxes CASCADE;
@@ -0,0 +1,21 @@
+-- Create training_maxes table
+-- Named reference loads (e.g. "squat" = 140kg) that templates prescribe percentages of
+CREATE TABLE IF NOT EXISTS training_maxes (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,  -- Lowercase key referenced by templates ("squat", "bench")
+    exercise_id UUID REFERENCES exercises(id) ON DELETE SET NULL,  -- Optional link for display
+    weight_kg REAL NOT NULL CHECK (weight_kg > 0),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+-- Templates: when set, intensity_percentage is a percentage of this named training max
+ALTER TABLE workout_exercises ADD COLUMN training_max_name TEXT;
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_training_maxes_updated_at
+    BEFORE UPDATE ON training_maxes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,2 @@
+-- Rollback: Drop account_exports table
+DROP TABLE IF EXISTS account_exports CASCADE;
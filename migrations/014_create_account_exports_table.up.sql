@@ -0,0 +1,16 @@
+-- Create account_exports table
+-- Asynchronously generated JSON archives of everything a user has stored
+CREATE TABLE IF NOT EXISTS account_exports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    error TEXT,          -- Failure reason when status = 'failed'
+    archive JSONB,       -- The export itself, set when status = 'ready'
+    size_bytes BIGINT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ  -- Archives are downloadable until this time
+);
+
+-- Index for a user's export history
+CREATE INDEX idx_account_exports_user ON account_exports(user_id, created_at DESC);
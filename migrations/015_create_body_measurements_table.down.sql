@@ -0,0 +1,3 @@
+-- Rollback: Drop body_measurements table
+DROP TRIGGER IF EXISTS update_body_measurements_updated_at ON body_measurements;
+DROP TABLE IF EXISTS body_measurements CASCADE;
@@ -0,0 +1,23 @@
+-- Create body_measurements table
+-- Weigh-ins entered manually or imported from smart scale exports
+CREATE TABLE IF NOT EXISTS body_measurements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    measured_at TIMESTAMPTZ NOT NULL,
+    weight_kg REAL CHECK (weight_kg > 0),
+    body_fat_percentage REAL CHECK (body_fat_percentage BETWEEN 0 AND 100),
+    muscle_mass_kg REAL CHECK (muscle_mass_kg > 0),
+    source TEXT NOT NULL DEFAULT 'manual',  -- 'manual', 'withings', 'renpho', ...
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, measured_at)  -- Re-importing the same export is a no-op
+);
+
+-- Index for chronological history
+CREATE INDEX idx_body_measurements_user_date ON body_measurements(user_id, measured_at DESC);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_body_measurements_updated_at
+    BEFORE UPDATE ON body_measurements
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
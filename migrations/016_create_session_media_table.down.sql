@@ -0,0 +1,3 @@
+-- Rollback: Drop session_media table
+DROP TRIGGER IF EXISTS update_session_media_updated_at ON session_media;
+DROP TABLE IF EXISTS session_media CASCADE;
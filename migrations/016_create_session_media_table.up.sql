@@ -0,0 +1,25 @@
+-- Create session_media table
+-- Photos and videos attached to a workout session, ordered for the highlights reel
+CREATE TABLE IF NOT EXISTS session_media (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workout_session_id UUID NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    media_type TEXT NOT NULL CHECK (media_type IN ('photo', 'video')),
+    url TEXT NOT NULL,  -- Stored object URL (uploads happen client-side)
+    thumbnail_url TEXT,
+    caption TEXT,
+    duration_seconds INTEGER CHECK (duration_seconds > 0),  -- Videos only
+    position INTEGER NOT NULL,  -- 0-based order within the session
+    highlight BOOLEAN NOT NULL DEFAULT TRUE,  -- Included in the highlights reel
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for listing a session's media in order
+CREATE INDEX idx_session_media_session ON session_media(workout_session_id, position);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_session_media_updated_at
+    BEFORE UPDATE ON session_media
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
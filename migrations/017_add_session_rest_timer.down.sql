@@ -0,0 +1,4 @@
+-- Rollback: Drop rest timer columns
+ALTER TABLE workout_sessions
+    DROP COLUMN IF EXISTS rest_timer_started_at,
+    DROP COLUMN IF EXISTS rest_timer_duration_seconds;
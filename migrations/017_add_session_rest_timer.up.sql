@@ -0,0 +1,5 @@
+-- Add the active rest timer to workout_sessions
+-- Devices derive the countdown from started_at + duration instead of running their own
+ALTER TABLE workout_sessions
+    ADD COLUMN rest_timer_started_at TIMESTAMPTZ,
+    ADD COLUMN rest_timer_duration_seconds INTEGER CHECK (rest_timer_duration_seconds > 0);
@@ -0,0 +1,4 @@
+-- Rollback: Drop exercise_swaps table and planned load
+DROP TABLE IF EXISTS exercise_swaps CASCADE;
+
+ALTER TABLE exercise_logs DROP COLUMN IF EXISTS weight_planned_kg;
@@ -0,0 +1,21 @@
+-- Planned load for a session's exercise, set when a swap rescales the prescription
+ALTER TABLE exercise_logs ADD COLUMN weight_planned_kg REAL;
+
+-- Create exercise_swaps table
+-- Exercises replaced during a session, kept so coaches can see what changed and why
+CREATE TABLE IF NOT EXISTS exercise_swaps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workout_session_id UUID NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    exercise_log_id UUID NOT NULL REFERENCES exercise_logs(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    from_exercise_id UUID REFERENCES exercises(id) ON DELETE SET NULL,
+    to_exercise_id UUID REFERENCES exercises(id) ON DELETE SET NULL,
+    reason TEXT,
+    strength_ratio REAL,  -- Substitute's estimated 1RM / original's (NULL without history)
+    from_weight_kg REAL,
+    to_weight_kg REAL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for listing a session's swaps
+CREATE INDEX idx_exercise_swaps_session ON exercise_swaps(workout_session_id, created_at);
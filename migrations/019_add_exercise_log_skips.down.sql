@@ -0,0 +1,6 @@
+-- Rollback: Drop skip tracking
+DROP INDEX IF EXISTS idx_exercise_logs_skipped;
+
+ALTER TABLE exercise_logs
+    DROP COLUMN IF EXISTS skipped_at,
+    DROP COLUMN IF EXISTS skip_reason;
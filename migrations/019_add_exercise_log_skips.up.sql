@@ -0,0 +1,7 @@
+-- Track planned exercises skipped during a session
+ALTER TABLE exercise_logs
+    ADD COLUMN skipped_at TIMESTAMPTZ,
+    ADD COLUMN skip_reason TEXT;
+
+-- Index for weekly skipped volume
+CREATE INDEX idx_exercise_logs_skipped ON exercise_logs(workout_session_id) WHERE skipped_at IS NOT NULL;
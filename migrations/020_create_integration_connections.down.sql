@@ -0,0 +1,9 @@
+-- Rollback: Drop integration connections and external session IDs
+DROP INDEX IF EXISTS idx_workout_sessions_external;
+
+ALTER TABLE workout_sessions
+    DROP COLUMN IF EXISTS external_source,
+    DROP COLUMN IF EXISTS external_id,
+    DROP COLUMN IF EXISTS external_synced_at;
+
+DROP TABLE IF EXISTS integration_connections;
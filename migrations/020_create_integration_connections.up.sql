@@ -0,0 +1,36 @@
+-- Create integration_connections table
+-- OAuth tokens for third-party fitness platforms, one connection per user and provider
+CREATE TABLE IF NOT EXISTS integration_connections (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    provider TEXT NOT NULL CHECK (provider IN ('googlefit')),
+    access_token TEXT NOT NULL,
+    refresh_token TEXT NOT NULL,
+    token_expires_at TIMESTAMPTZ NOT NULL,
+    scope TEXT,
+    last_synced_at TIMESTAMPTZ,
+    last_sync_error TEXT,
+    reauth_required BOOLEAN NOT NULL DEFAULT FALSE,  -- Provider rejected the refresh token
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, provider)
+);
+
+-- Index for the background sync job
+CREATE INDEX idx_integration_connections_sync ON integration_connections(provider, last_synced_at NULLS FIRST);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_integration_connections_updated_at
+    BEFORE UPDATE ON integration_connections
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Sessions pulled from a provider keep its ID so repeated syncs update rather than duplicate
+ALTER TABLE workout_sessions
+    ADD COLUMN external_source TEXT,
+    ADD COLUMN external_id TEXT,
+    ADD COLUMN external_synced_at TIMESTAMPTZ;
+
+CREATE UNIQUE INDEX idx_workout_sessions_external
+    ON workout_sessions(user_id, external_source, external_id)
+    WHERE external_id IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Rollback: Drop session laps and session types
+DROP TABLE IF EXISTS session_laps;
+
+ALTER TABLE workout_sessions
+    DROP COLUMN IF EXISTS session_type;
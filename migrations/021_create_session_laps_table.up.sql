@@ -0,0 +1,19 @@
+-- Distinguish cardio sessions (runs, rides) from strength sessions
+ALTER TABLE workout_sessions
+    ADD COLUMN session_type TEXT NOT NULL DEFAULT 'strength' CHECK (session_type IN ('strength', 'cardio'));
+
+-- Create session_laps table
+-- Laps of cardio sessions imported from FIT/TCX files
+CREATE TABLE IF NOT EXISTS session_laps (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    workout_session_id UUID NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    lap_index INTEGER NOT NULL CHECK (lap_index > 0),
+    started_at TIMESTAMPTZ NOT NULL,
+    duration_seconds INTEGER NOT NULL CHECK (duration_seconds >= 0),
+    distance_meters REAL CHECK (distance_meters >= 0),
+    calories_burned INTEGER,
+    heart_rate_avg INTEGER,
+    heart_rate_max INTEGER,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (workout_session_id, lap_index)
+);
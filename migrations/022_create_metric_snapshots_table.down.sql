@@ -0,0 +1,6 @@
+-- Rollback: Drop metric snapshots
+DROP INDEX IF EXISTS idx_body_measurements_updated;
+DROP INDEX IF EXISTS idx_exercise_logs_updated;
+DROP INDEX IF EXISTS idx_workout_sessions_updated;
+
+DROP TABLE IF EXISTS metric_snapshots;
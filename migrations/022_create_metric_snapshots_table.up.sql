@@ -0,0 +1,16 @@
+-- Create metric_snapshots table
+-- Monthly aggregates of key metrics, precomputed so multi-year trends stay cheap
+CREATE TABLE IF NOT EXISTS metric_snapshots (
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    metric TEXT NOT NULL,  -- 'sessions', 'volume_kg', 'cardio_distance_m', 'body_weight_kg'
+    month DATE NOT NULL,   -- First day of the month (UTC)
+    value DOUBLE PRECISION NOT NULL,
+    samples INTEGER NOT NULL,  -- Rows aggregated into value
+    computed_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, metric, month)
+);
+
+-- Index for finding history changed since the last snapshot refresh
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_updated ON workout_sessions(updated_at);
+CREATE INDEX IF NOT EXISTS idx_exercise_logs_updated ON exercise_logs(updated_at);
+CREATE INDEX IF NOT EXISTS idx_body_measurements_updated ON body_measurements(updated_at);
@@ -0,0 +1,14 @@
+-- Rollback: Drop integration jobs and Strava connection settings
+DROP TRIGGER IF EXISTS update_integration_jobs_updated_at ON integration_jobs;
+DROP TABLE IF EXISTS integration_jobs;
+
+DROP INDEX IF EXISTS idx_integration_connections_account;
+ALTER TABLE integration_connections
+    DROP COLUMN IF EXISTS push_sessions_since,
+    DROP COLUMN IF EXISTS pull_activities,
+    DROP COLUMN IF EXISTS external_account_id;
+
+DELETE FROM integration_connections WHERE provider = 'strava';
+ALTER TABLE integration_connections DROP CONSTRAINT integration_connections_provider_check;
+ALTER TABLE integration_connections
+    ADD CONSTRAINT integration_connections_provider_check CHECK (provider IN ('googlefit'));
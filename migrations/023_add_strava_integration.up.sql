@@ -0,0 +1,43 @@
+-- Allow Strava connections
+ALTER TABLE integration_connections DROP CONSTRAINT integration_connections_provider_check;
+ALTER TABLE integration_connections
+    ADD CONSTRAINT integration_connections_provider_check CHECK (provider IN ('googlefit', 'strava'));
+
+-- The provider's account ID routes webhook events to the connection; sync directions are per user
+ALTER TABLE integration_connections
+    ADD COLUMN external_account_id TEXT,
+    ADD COLUMN pull_activities BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN push_sessions_since TIMESTAMPTZ;  -- NULL when pushing is off; older sessions are never pushed
+
+CREATE UNIQUE INDEX idx_integration_connections_account
+    ON integration_connections(provider, external_account_id)
+    WHERE external_account_id IS NOT NULL;
+
+-- Create integration_jobs table
+-- Work for a connection (webhook events, session pushes) retried with backoff until done
+CREATE TABLE IF NOT EXISTS integration_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES integration_connections(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('import_activity', 'delete_activity', 'push_session')),
+    object_id TEXT NOT NULL,  -- Provider activity ID, or the fitapi session ID for pushes
+    result_id TEXT,           -- Provider activity created by a push
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (connection_id, kind, object_id)
+);
+
+-- Index for the job worker
+CREATE INDEX idx_integration_jobs_due ON integration_jobs(run_at) WHERE status = 'pending';
+
+-- Index for recognizing activities fitapi pushed itself
+CREATE INDEX idx_integration_jobs_result ON integration_jobs(connection_id, result_id) WHERE result_id IS NOT NULL;
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_integration_jobs_updated_at
+    BEFORE UPDATE ON integration_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,13 @@
+-- Rollback: Drop the session type registry and session payloads
+DROP INDEX IF EXISTS idx_workout_sessions_type;
+
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS type_payload;
+ALTER TABLE workout_sessions DROP CONSTRAINT IF EXISTS workout_sessions_session_type_fkey;
+
+-- Sessions of types that did not exist before fall back to strength
+UPDATE workout_sessions SET session_type = 'strength' WHERE session_type NOT IN ('strength', 'cardio');
+ALTER TABLE workout_sessions
+    ADD CONSTRAINT workout_sessions_session_type_check CHECK (session_type IN ('strength', 'cardio'));
+
+DROP TRIGGER IF EXISTS update_session_types_updated_at ON session_types;
+DROP TABLE IF EXISTS session_types;
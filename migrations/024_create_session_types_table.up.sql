@@ -0,0 +1,65 @@
+-- Create session_types table
+-- Registry of session types; each declares the JSONB payload its sessions carry, so a new
+-- sport is a row here instead of new columns on workout_sessions
+CREATE TABLE IF NOT EXISTS session_types (
+    name TEXT PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]{1,31}$'),
+    display_name TEXT NOT NULL,
+    description TEXT,
+    payload_schema JSONB NOT NULL DEFAULT '{"fields": {}}',  -- Validated by the API, see models.PayloadSchema
+    builtin BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_session_types_updated_at
+    BEFORE UPDATE ON session_types
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO session_types (name, display_name, description, payload_schema, builtin) VALUES
+    ('strength', 'Strength', 'Gym sessions logged set by set', '{
+        "fields": {
+            "split": {"type": "enum", "values": ["full_body", "upper", "lower", "push", "pull", "legs"]}
+        }
+    }', TRUE),
+    ('cardio', 'Cardio', 'Runs, rides, swims and other distance activities', '{
+        "fields": {
+            "distance_m": {"type": "number", "min": 0, "unit": "m", "summary": "sum"},
+            "elevation_gain_m": {"type": "number", "min": 0, "unit": "m", "summary": "sum"},
+            "heart_rate_avg": {"type": "integer", "min": 20, "max": 250, "unit": "bpm", "summary": "avg"},
+            "indoor": {"type": "boolean"}
+        }
+    }', TRUE),
+    ('mobility', 'Mobility', 'Stretching, yoga and mobility routines', '{
+        "fields": {
+            "focus": {"type": "enum", "values": ["hips", "shoulders", "spine", "ankles", "full_body"]},
+            "routine": {"type": "string", "max_length": 200},
+            "hold_seconds": {"type": "integer", "min": 0, "unit": "s", "summary": "sum"}
+        }
+    }', TRUE),
+    ('class', 'Class', 'Instructor-led group classes', '{
+        "fields": {
+            "class_name": {"type": "string", "required": true, "max_length": 200},
+            "instructor": {"type": "string", "max_length": 200},
+            "studio": {"type": "string", "max_length": 200},
+            "intensity": {"type": "enum", "values": ["low", "moderate", "high"]}
+        }
+    }', TRUE),
+    ('custom', 'Custom', 'Anything else; the payload is free-form', '{
+        "fields": {},
+        "additional_fields": true
+    }', TRUE)
+ON CONFLICT (name) DO NOTHING;
+
+-- Session types now come from the registry
+ALTER TABLE workout_sessions DROP CONSTRAINT workout_sessions_session_type_check;
+ALTER TABLE workout_sessions
+    ADD CONSTRAINT workout_sessions_session_type_fkey
+    FOREIGN KEY (session_type) REFERENCES session_types(name);
+
+ALTER TABLE workout_sessions
+    ADD COLUMN type_payload JSONB NOT NULL DEFAULT '{}' CHECK (jsonb_typeof(type_payload) = 'object');
+
+-- Index for per-type analytics
+CREATE INDEX idx_workout_sessions_type ON workout_sessions(user_id, session_type, started_at);
@@ -0,0 +1,12 @@
+-- Rollback: Drop webhook triggers and tables
+DROP TRIGGER IF EXISTS workouts_webhook_event ON workouts;
+DROP TRIGGER IF EXISTS exercise_logs_webhook_event ON exercise_logs;
+DROP TRIGGER IF EXISTS workout_sessions_webhook_event ON workout_sessions;
+DROP FUNCTION IF EXISTS workouts_webhook_event();
+DROP FUNCTION IF EXISTS exercise_logs_webhook_event();
+DROP FUNCTION IF EXISTS workout_sessions_webhook_event();
+DROP FUNCTION IF EXISTS record_webhook_event(UUID, TEXT, JSONB);
+DROP TRIGGER IF EXISTS update_webhook_endpoints_updated_at ON webhook_endpoints;
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS webhook_endpoints;
@@ -0,0 +1,135 @@
+-- Create webhook tables
+-- Users register endpoints for events; triggers record events for users with a matching
+-- endpoint, and the API's webhook worker fans them out into deliveries and sends them
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    url TEXT NOT NULL,
+    secret TEXT NOT NULL,  -- whsec_<base64>, signs deliveries (Standard Webhooks)
+    events TEXT[] NOT NULL CHECK (
+        cardinality(events) > 0 AND events <@ ARRAY['session.completed', 'pr.achieved', 'workout.created']
+    ),
+    description TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for user's endpoints
+CREATE INDEX idx_webhook_endpoints_user ON webhook_endpoints(user_id);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_webhook_endpoints_updated_at
+    BEFORE UPDATE ON webhook_endpoints
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+CREATE TABLE IF NOT EXISTS webhook_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    event TEXT NOT NULL,
+    data JSONB NOT NULL,
+    dispatched_at TIMESTAMPTZ,  -- Set once deliveries are created
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for events waiting to be dispatched
+CREATE INDEX idx_webhook_events_pending ON webhook_events(created_at) WHERE dispatched_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL REFERENCES webhook_events(id) ON DELETE CASCADE,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'delivered', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- Next attempt while pending
+    response_status INTEGER,  -- HTTP status of the last attempt
+    last_error TEXT,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (endpoint_id, event_id)
+);
+
+-- Index for the delivery worker
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(run_at) WHERE status = 'pending';
+
+-- Index for an endpoint's delivery log
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
+
+-- Records an event for a user with an endpoint subscribed to it. Bulk writers that rewrite
+-- history (imports, exercise merges) set fitapi.suppress_webhooks for their transaction.
+CREATE OR REPLACE FUNCTION record_webhook_event(p_user_id UUID, p_event TEXT, p_data JSONB)
+RETURNS VOID AS $$
+BEGIN
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN;
+    END IF;
+    IF EXISTS (SELECT 1 FROM webhook_endpoints WHERE user_id = p_user_id AND p_event = ANY(events)) THEN
+        INSERT INTO webhook_events (user_id, event, data) VALUES (p_user_id, p_event, p_data);
+    END IF;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION workout_sessions_webhook_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'completed' AND (TG_OP = 'INSERT' OR OLD.status <> 'completed') THEN
+        PERFORM record_webhook_event(NEW.user_id, 'session.completed', jsonb_build_object(
+            'session_id', NEW.id,
+            'workout_id', NEW.workout_id,
+            'name', NEW.name,
+            'session_type', NEW.session_type,
+            'started_at', NEW.started_at,
+            'completed_at', NEW.completed_at,
+            'duration_minutes', NEW.duration_minutes
+        ));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_webhook_event
+    AFTER INSERT OR UPDATE OF status ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_webhook_event();
+
+CREATE OR REPLACE FUNCTION exercise_logs_webhook_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+BEGIN
+    IF NEW.is_personal_record AND (TG_OP = 'INSERT' OR NOT COALESCE(OLD.is_personal_record, FALSE)) THEN
+        SELECT user_id INTO v_user_id FROM workout_sessions WHERE id = NEW.workout_session_id;
+        PERFORM record_webhook_event(v_user_id, 'pr.achieved', jsonb_build_object(
+            'exercise_log_id', NEW.id,
+            'session_id', NEW.workout_session_id,
+            'exercise_id', NEW.exercise_id,
+            'weight_kg', NEW.weight_kg,
+            'reps', NEW.reps_completed,
+            'previous_best_weight', NEW.previous_best_weight
+        ));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_webhook_event
+    AFTER INSERT OR UPDATE OF is_personal_record ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_webhook_event();
+
+CREATE OR REPLACE FUNCTION workouts_webhook_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM record_webhook_event(NEW.user_id, 'workout.created', jsonb_build_object(
+        'workout_id', NEW.id,
+        'name', NEW.name,
+        'description', NEW.description
+    ));
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workouts_webhook_event
+    AFTER INSERT ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION workouts_webhook_event();
@@ -0,0 +1,5 @@
+-- Rollback: Drop session activity notifications
+DROP TRIGGER IF EXISTS workout_sessions_session_activity ON workout_sessions;
+DROP TRIGGER IF EXISTS exercise_logs_session_activity ON exercise_logs;
+DROP FUNCTION IF EXISTS notify_session_state_activity();
+DROP FUNCTION IF EXISTS notify_session_set_activity();
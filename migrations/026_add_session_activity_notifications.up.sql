@@ -0,0 +1,81 @@
+-- Notify listeners of activity in sessions being performed
+-- The API LISTENs on session_activity and forwards events to clients watching a session
+-- over WebSocket. Payloads stay well below pg_notify's 8000 byte limit.
+CREATE OR REPLACE FUNCTION notify_session_set_activity()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_log exercise_logs;
+    v_type TEXT;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_log := OLD;
+        v_type := 'set.deleted';
+    ELSIF TG_OP = 'INSERT' THEN
+        v_log := NEW;
+        v_type := 'set.logged';
+    ELSE
+        v_log := NEW;
+        v_type := 'set.updated';
+    END IF;
+
+    -- Only sessions being performed can be watched; imports and later edits stay quiet
+    IF NOT EXISTS (
+        SELECT 1 FROM workout_sessions
+        WHERE id = v_log.workout_session_id AND status IN ('in_progress', 'paused')
+    ) THEN
+        RETURN NULL;
+    END IF;
+
+    PERFORM pg_notify('session_activity', json_build_object(
+        'type', v_type,
+        'session_id', v_log.workout_session_id,
+        'set', json_build_object(
+            'id', v_log.id,
+            'exercise_id', v_log.exercise_id,
+            'order_index', v_log.order_index,
+            'sets_completed', v_log.sets_completed,
+            'reps_completed', v_log.reps_completed,
+            'weight_kg', v_log.weight_kg,
+            'duration_seconds', v_log.duration_seconds,
+            'distance_meters', v_log.distance_meters,
+            'rpe', v_log.rpe,
+            'is_personal_record', v_log.is_personal_record
+        )
+    )::text);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_session_activity
+    AFTER INSERT OR UPDATE OR DELETE ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_session_set_activity();
+
+CREATE OR REPLACE FUNCTION notify_session_state_activity()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.status IS DISTINCT FROM NEW.status THEN
+        PERFORM pg_notify('session_activity', json_build_object(
+            'type', 'session.status',
+            'session_id', NEW.id,
+            'status', NEW.status
+        )::text);
+    END IF;
+
+    IF OLD.rest_timer_started_at IS DISTINCT FROM NEW.rest_timer_started_at
+        OR OLD.rest_timer_duration_seconds IS DISTINCT FROM NEW.rest_timer_duration_seconds THEN
+        PERFORM pg_notify('session_activity', json_build_object(
+            'type', 'rest_timer',
+            'session_id', NEW.id,
+            'rest_timer_started_at', NEW.rest_timer_started_at,
+            'rest_timer_duration_seconds', NEW.rest_timer_duration_seconds
+        )::text);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_session_activity
+    AFTER UPDATE OF status, rest_timer_started_at, rest_timer_duration_seconds ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION notify_session_state_activity();
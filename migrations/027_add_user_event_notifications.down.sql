@@ -0,0 +1,6 @@
+-- Rollback: Drop user event notifications
+DROP TRIGGER IF EXISTS workouts_user_event ON workouts;
+DROP TRIGGER IF EXISTS exercise_logs_user_event ON exercise_logs;
+DROP FUNCTION IF EXISTS workouts_user_event();
+DROP FUNCTION IF EXISTS exercise_logs_user_event();
+DROP FUNCTION IF EXISTS notify_user_event(UUID, TEXT, JSONB);
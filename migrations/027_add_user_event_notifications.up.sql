@@ -0,0 +1,69 @@
+-- Notify listeners of events for users' connected clients
+-- The API LISTENs on user_events and pushes each event to the user's event streams.
+-- Application code publishes on the same channel, so every instance sees every event.
+CREATE OR REPLACE FUNCTION notify_user_event(p_user_id UUID, p_type TEXT, p_data JSONB)
+RETURNS VOID AS $$
+BEGIN
+    -- Bulk writers that rewrite history are not news to clients either
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN;
+    END IF;
+    PERFORM pg_notify('user_events', json_build_object(
+        'id', gen_random_uuid(),
+        'type', p_type,
+        'user_id', p_user_id,
+        'created_at', NOW(),
+        'data', p_data
+    )::text);
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION exercise_logs_user_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+BEGIN
+    IF NEW.is_personal_record AND (TG_OP = 'INSERT' OR NOT COALESCE(OLD.is_personal_record, FALSE)) THEN
+        SELECT user_id INTO v_user_id FROM workout_sessions WHERE id = NEW.workout_session_id;
+        PERFORM notify_user_event(v_user_id, 'pr.achieved', jsonb_build_object(
+            'exercise_log_id', NEW.id,
+            'session_id', NEW.workout_session_id,
+            'exercise_id', NEW.exercise_id,
+            'weight_kg', NEW.weight_kg,
+            'reps', NEW.reps_completed,
+            'previous_best_weight', NEW.previous_best_weight
+        ));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_user_event
+    AFTER INSERT OR UPDATE OF is_personal_record ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_user_event();
+
+-- A workout created for a client by one of their active coaches is an assignment
+CREATE OR REPLACE FUNCTION workouts_user_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_actor_id UUID := auth.uid();
+BEGIN
+    IF v_actor_id IS NOT NULL AND v_actor_id <> NEW.user_id AND EXISTS (
+        SELECT 1 FROM coach_clients
+        WHERE coach_id = v_actor_id AND client_id = NEW.user_id AND status = 'active'
+    ) THEN
+        PERFORM notify_user_event(NEW.user_id, 'workout.assigned', jsonb_build_object(
+            'workout_id', NEW.id,
+            'name', NEW.name,
+            'coach_id', v_actor_id
+        ));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workouts_user_event
+    AFTER INSERT ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION workouts_user_event();
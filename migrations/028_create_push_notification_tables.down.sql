@@ -0,0 +1,5 @@
+-- Rollback: Drop push notification tables
+DROP TRIGGER IF EXISTS workouts_push_notification ON workouts;
+DROP FUNCTION IF EXISTS workouts_push_notification();
+DROP TABLE IF EXISTS push_notifications;
+DROP TABLE IF EXISTS device_tokens;
@@ -0,0 +1,72 @@
+-- Create device_tokens table
+-- Push notification tokens of users' devices: APNs for iOS, FCM for Android
+CREATE TABLE IF NOT EXISTS device_tokens (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    platform TEXT NOT NULL CHECK (platform IN ('ios', 'android')),
+    token TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- A token identifies one app install; registering it again (e.g. after signing in as
+-- someone else) moves it
+CREATE UNIQUE INDEX idx_device_tokens_token ON device_tokens(token);
+
+-- Index for a user's devices
+CREATE INDEX idx_device_tokens_user ON device_tokens(user_id);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_device_tokens_updated_at
+    BEFORE UPDATE ON device_tokens
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create push_notifications table
+-- Notifications waiting to be sent to a user's devices; the API formats them per kind
+CREATE TABLE IF NOT EXISTS push_notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- Next attempt, or lease expiry while sending
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for the sender's queue
+CREATE INDEX idx_push_notifications_due ON push_notifications(run_at) WHERE status = 'pending';
+
+-- Queue a push for a workout created for a client by one of their active coaches
+CREATE OR REPLACE FUNCTION workouts_push_notification()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_actor_id UUID := auth.uid();
+BEGIN
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN NULL;
+    END IF;
+    IF v_actor_id IS NOT NULL AND v_actor_id <> NEW.user_id
+        AND EXISTS (SELECT 1 FROM device_tokens WHERE user_id = NEW.user_id)
+        AND EXISTS (
+            SELECT 1 FROM coach_clients
+            WHERE coach_id = v_actor_id AND client_id = NEW.user_id AND status = 'active'
+        ) THEN
+        INSERT INTO push_notifications (user_id, kind, data)
+        VALUES (NEW.user_id, 'workout.assigned', jsonb_build_object(
+            'workout_id', NEW.id,
+            'name', NEW.name,
+            'coach_id', v_actor_id
+        ));
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workouts_push_notification
+    AFTER INSERT ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION workouts_push_notification();
@@ -0,0 +1,4 @@
+-- Rollback: Drop email tables
+DROP INDEX IF EXISTS idx_workout_sessions_planned;
+DROP TABLE IF EXISTS email_jobs;
+DROP TABLE IF EXISTS email_preferences;
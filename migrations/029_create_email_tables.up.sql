@@ -0,0 +1,40 @@
+-- Create email_preferences table
+-- Which recurring emails a user opted into; users without a row get none
+CREATE TABLE IF NOT EXISTS email_preferences (
+    user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+    workout_reminders BOOLEAN NOT NULL DEFAULT FALSE,
+    weekly_summary BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_email_preferences_updated_at
+    BEFORE UPDATE ON email_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create email_jobs table
+-- Emails waiting to be sent; the API renders them from the template and data
+CREATE TABLE IF NOT EXISTS email_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES auth.users(id) ON DELETE CASCADE,  -- Recipient, when they have an account
+    to_address TEXT NOT NULL,
+    template TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    dedupe_key TEXT,  -- Scheduled emails are queued once per key, e.g. per session reminded
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- Next attempt, or lease expiry while sending
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_email_jobs_dedupe_key ON email_jobs(dedupe_key);
+
+-- Index for the sender's queue
+CREATE INDEX idx_email_jobs_due ON email_jobs(run_at) WHERE status = 'pending';
+
+-- Index for finding upcoming planned sessions to remind
+CREATE INDEX idx_workout_sessions_planned ON workout_sessions(started_at) WHERE status = 'planned';
@@ -0,0 +1,7 @@
+-- Rollback: Drop notifications table
+DROP TRIGGER IF EXISTS workouts_notification ON workouts;
+DROP FUNCTION IF EXISTS workouts_notification();
+DROP TRIGGER IF EXISTS exercise_logs_notification ON exercise_logs;
+DROP FUNCTION IF EXISTS exercise_logs_notification();
+DROP FUNCTION IF EXISTS create_notification(UUID, TEXT, TEXT, TEXT, JSONB);
+DROP TABLE IF EXISTS notifications;
@@ -0,0 +1,95 @@
+-- Create notifications table
+-- Users' in-app inbox, filled by database triggers as things happen to their account
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL DEFAULT '',
+    data JSONB NOT NULL DEFAULT '{}',  -- IDs of what the notification is about, per type
+    read_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for a user's inbox, newest first
+CREATE INDEX idx_notifications_user ON notifications(user_id, created_at DESC);
+
+-- Index for unread counts
+CREATE INDEX idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- Add a notification to a user's inbox and tell their connected clients about it
+CREATE OR REPLACE FUNCTION create_notification(p_user_id UUID, p_type TEXT, p_title TEXT, p_body TEXT, p_data JSONB)
+RETURNS VOID AS $$
+DECLARE
+    v_notification notifications%ROWTYPE;
+BEGIN
+    -- Bulk writers that rewrite history are not news to the user either
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN;
+    END IF;
+    INSERT INTO notifications (user_id, type, title, body, data)
+    VALUES (p_user_id, p_type, p_title, COALESCE(p_body, ''), p_data)
+    RETURNING * INTO v_notification;
+
+    PERFORM notify_user_event(p_user_id, 'notification.created', to_jsonb(v_notification));
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION exercise_logs_notification()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+    v_exercise TEXT;
+BEGIN
+    IF NEW.is_personal_record AND (TG_OP = 'INSERT' OR NOT COALESCE(OLD.is_personal_record, FALSE)) THEN
+        SELECT user_id INTO v_user_id FROM workout_sessions WHERE id = NEW.workout_session_id;
+        SELECT name INTO v_exercise FROM exercises WHERE id = NEW.exercise_id;
+        PERFORM create_notification(
+            v_user_id,
+            'pr.achieved',
+            'New personal record',
+            COALESCE(v_exercise, 'Exercise')
+                || COALESCE(': ' || NEW.weight_kg || ' kg', '')
+                || COALESCE(' × ' || NEW.reps_completed, ''),
+            jsonb_build_object(
+                'exercise_log_id', NEW.id,
+                'session_id', NEW.workout_session_id,
+                'exercise_id', NEW.exercise_id
+            )
+        );
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_notification
+    AFTER INSERT OR UPDATE OF is_personal_record ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_notification();
+
+-- A workout created for a client by one of their active coaches is an assignment
+CREATE OR REPLACE FUNCTION workouts_notification()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_actor_id UUID := auth.uid();
+BEGIN
+    IF v_actor_id IS NOT NULL AND v_actor_id <> NEW.user_id AND EXISTS (
+        SELECT 1 FROM coach_clients
+        WHERE coach_id = v_actor_id AND client_id = NEW.user_id AND status = 'active'
+    ) THEN
+        PERFORM create_notification(
+            NEW.user_id,
+            'workout.assigned',
+            'New workout from your coach',
+            NEW.name,
+            jsonb_build_object('workout_id', NEW.id, 'coach_id', v_actor_id)
+        );
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workouts_notification
+    AFTER INSERT ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION workouts_notification();
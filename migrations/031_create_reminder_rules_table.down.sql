@@ -0,0 +1,2 @@
+-- Rollback: Drop reminder_rules table
+DROP TABLE IF EXISTS reminder_rules;
@@ -0,0 +1,28 @@
+-- Create reminder_rules table
+-- Recurring workout reminders, e.g. Mon/Wed/Fri at 18:00 in the user's timezone
+CREATE TABLE IF NOT EXISTS reminder_rules (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    weekdays SMALLINT[] NOT NULL,  -- 0 = Sunday ... 6 = Saturday
+    time_of_day TEXT NOT NULL,  -- HH:MM, local to timezone
+    timezone TEXT NOT NULL DEFAULT 'UTC',  -- IANA name
+    channels TEXT[] NOT NULL,  -- push and/or email
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    next_fire_at TIMESTAMPTZ,  -- Set by the API; NULL while disabled, lease expiry while firing
+    last_fired_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for a user's rules
+CREATE INDEX idx_reminder_rules_user ON reminder_rules(user_id);
+
+-- Index for the scheduler
+CREATE INDEX idx_reminder_rules_due ON reminder_rules(next_fire_at) WHERE enabled;
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_reminder_rules_updated_at
+    BEFORE UPDATE ON reminder_rules
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
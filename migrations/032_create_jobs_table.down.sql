@@ -0,0 +1,29 @@
+-- Rollback: Restore the email and webhook delivery queues and drop jobs
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(run_at) WHERE status = 'pending';
+
+CREATE TABLE IF NOT EXISTS email_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES auth.users(id) ON DELETE CASCADE,
+    to_address TEXT NOT NULL,
+    template TEXT NOT NULL,
+    data JSONB NOT NULL DEFAULT '{}',
+    dedupe_key TEXT,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_email_jobs_dedupe_key ON email_jobs(dedupe_key);
+CREATE INDEX IF NOT EXISTS idx_email_jobs_due ON email_jobs(run_at) WHERE status = 'pending';
+
+INSERT INTO email_jobs (user_id, to_address, template, data, dedupe_key, attempts, run_at, last_error, created_at)
+SELECT user_id, payload->>'to', payload->>'template', COALESCE(payload->'data', '{}'),
+       dedupe_key, attempts, run_at, last_error, created_at
+FROM jobs
+WHERE kind = 'email' AND status IN ('pending', 'running');
+
+DROP TRIGGER IF EXISTS update_jobs_updated_at ON jobs;
+DROP TABLE IF EXISTS jobs;
@@ -0,0 +1,54 @@
+-- Create jobs table
+-- Background work run by the API's workers: emails, account exports and webhook deliveries
+CREATE TABLE IF NOT EXISTS jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID REFERENCES auth.users(id) ON DELETE CASCADE,  -- The user the work is for, if any
+    kind TEXT NOT NULL,
+    payload JSONB NOT NULL DEFAULT '{}',
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'done', 'dead')),
+    attempts INTEGER NOT NULL DEFAULT 0,  -- Counted when a worker claims the job
+    max_attempts INTEGER NOT NULL DEFAULT 8,  -- Dead-lettered after this many failures
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),  -- Next attempt, or lease expiry while running
+    last_error TEXT,
+    dedupe_key TEXT,  -- Scheduled jobs are queued once per key, e.g. per session reminded
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX idx_jobs_dedupe_key ON jobs(dedupe_key);
+
+-- Index for the workers' queue
+CREATE INDEX idx_jobs_due ON jobs(run_at) WHERE status IN ('pending', 'running');
+
+-- Index for a user's jobs
+CREATE INDEX idx_jobs_user ON jobs(user_id, created_at DESC) WHERE user_id IS NOT NULL;
+
+-- Index for the dead letter list
+CREATE INDEX idx_jobs_dead ON jobs(finished_at DESC) WHERE status = 'dead';
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_jobs_updated_at
+    BEFORE UPDATE ON jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Move queued emails over; sent and failed ones are dropped with their table
+INSERT INTO jobs (user_id, kind, payload, attempts, run_at, last_error, dedupe_key, created_at)
+SELECT user_id, 'email',
+       jsonb_build_object('to', to_address, 'template', template, 'data', data),
+       attempts, run_at, last_error, dedupe_key, created_at
+FROM email_jobs
+WHERE status = 'pending';
+
+DROP TABLE IF EXISTS email_jobs;
+
+-- Pending webhook deliveries are now sent by delivery jobs
+INSERT INTO jobs (user_id, kind, payload, attempts, max_attempts, run_at, last_error)
+SELECT e.user_id, 'webhook_delivery', jsonb_build_object('delivery_id', d.id),
+       d.attempts, 10, d.run_at, d.last_error
+FROM webhook_deliveries d
+JOIN webhook_endpoints e ON e.id = d.endpoint_id
+WHERE d.status = 'pending';
+
+DROP INDEX IF EXISTS idx_webhook_deliveries_due;
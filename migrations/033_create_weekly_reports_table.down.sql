@@ -0,0 +1,2 @@
+-- Rollback: Drop weekly_reports table
+DROP TABLE IF EXISTS weekly_reports;
@@ -0,0 +1,14 @@
+-- Create weekly_reports table
+-- One row per user and week (Monday to Sunday, UTC), compiled on Mondays
+CREATE TABLE IF NOT EXISTS weekly_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    week_start DATE NOT NULL,  -- Monday (UTC)
+    sessions INTEGER NOT NULL,  -- Completed sessions
+    minutes INTEGER NOT NULL,
+    volume_kg DOUBLE PRECISION NOT NULL,  -- Sets x reps x weight lifted
+    personal_records INTEGER NOT NULL,
+    streak_weeks INTEGER NOT NULL,  -- Weeks in a row with a completed session, ending this week
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, week_start)
+);
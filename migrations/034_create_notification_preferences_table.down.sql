@@ -0,0 +1,22 @@
+-- Rollback: Restore create_notification without preferences
+CREATE OR REPLACE FUNCTION create_notification(p_user_id UUID, p_type TEXT, p_title TEXT, p_body TEXT, p_data JSONB)
+RETURNS VOID AS $$
+DECLARE
+    v_notification notifications%ROWTYPE;
+BEGIN
+    -- Bulk writers that rewrite history are not news to the user either
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN;
+    END IF;
+    INSERT INTO notifications (user_id, type, title, body, data)
+    VALUES (p_user_id, p_type, p_title, COALESCE(p_body, ''), p_data)
+    RETURNING * INTO v_notification;
+
+    PERFORM notify_user_event(p_user_id, 'notification.created', to_jsonb(v_notification));
+END;
+$$ LANGUAGE plpgsql;
+
+DROP FUNCTION IF EXISTS notification_allowed(UUID, TEXT, TEXT);
+
+-- Rollback: Drop notification_preferences table
+DROP TABLE IF EXISTS notification_preferences;
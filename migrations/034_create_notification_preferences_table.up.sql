@@ -0,0 +1,47 @@
+-- Create notification_preferences table
+-- Which channels each type of notification is sent on, and quiet hours for pushes
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+    channels JSONB NOT NULL DEFAULT '{}',  -- Type -> channels ('email', 'push', 'in_app'); missing types use every channel
+    quiet_start TEXT,  -- HH:MM in timezone; NULL for no quiet hours
+    quiet_end TEXT,    -- HH:MM in timezone; before quiet_start when spanning midnight
+    timezone TEXT NOT NULL DEFAULT 'UTC',  -- IANA name
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_notification_preferences_updated_at
+    BEFORE UPDATE ON notification_preferences
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Whether the user lets notifications of a type reach a channel
+CREATE OR REPLACE FUNCTION notification_allowed(p_user_id UUID, p_type TEXT, p_channel TEXT)
+RETURNS BOOLEAN AS $$
+    SELECT NOT EXISTS (
+        SELECT 1 FROM notification_preferences
+        WHERE user_id = p_user_id AND channels ? p_type AND NOT (channels -> p_type) ? p_channel
+    );
+$$ LANGUAGE sql STABLE;
+
+-- Add a notification to a user's inbox, unless they turned the type off in-app, and tell
+-- their connected clients about it
+CREATE OR REPLACE FUNCTION create_notification(p_user_id UUID, p_type TEXT, p_title TEXT, p_body TEXT, p_data JSONB)
+RETURNS VOID AS $$
+DECLARE
+    v_notification notifications%ROWTYPE;
+BEGIN
+    -- Bulk writers that rewrite history are not news to the user either
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN;
+    END IF;
+    IF NOT notification_allowed(p_user_id, p_type, 'in_app') THEN
+        RETURN;
+    END IF;
+    INSERT INTO notifications (user_id, type, title, body, data)
+    VALUES (p_user_id, p_type, p_title, COALESCE(p_body, ''), p_data)
+    RETURNING * INTO v_notification;
+
+    PERFORM notify_user_event(p_user_id, 'notification.created', to_jsonb(v_notification));
+END;
+$$ LANGUAGE plpgsql;
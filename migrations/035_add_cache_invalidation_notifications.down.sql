@@ -0,0 +1,10 @@
+-- Rollback: Drop cache invalidation notifications
+DROP TRIGGER IF EXISTS exercise_logs_cache_invalidation ON exercise_logs;
+DROP TRIGGER IF EXISTS metric_snapshots_cache_invalidation ON metric_snapshots;
+DROP TRIGGER IF EXISTS body_measurements_cache_invalidation ON body_measurements;
+DROP TRIGGER IF EXISTS workout_sessions_cache_invalidation ON workout_sessions;
+DROP TRIGGER IF EXISTS exercises_cache_invalidation ON exercises;
+DROP FUNCTION IF EXISTS exercise_logs_cache_invalidation();
+DROP FUNCTION IF EXISTS analytics_cache_invalidation();
+DROP FUNCTION IF EXISTS exercises_cache_invalidation();
+DROP FUNCTION IF EXISTS notify_cache_invalidation(TEXT);
@@ -0,0 +1,82 @@
+-- Notify listeners when data behind cached reads changes
+-- The API LISTENs on cache_invalidation and drops the cache scope named by each payload.
+-- pg_notify folds identical payloads within a transaction, so bulk writes such as imports
+-- notify once per scope.
+CREATE OR REPLACE FUNCTION notify_cache_invalidation(p_scope TEXT)
+RETURNS VOID AS $$
+BEGIN
+    PERFORM pg_notify('cache_invalidation', p_scope);
+END;
+$$ LANGUAGE plpgsql;
+
+-- The exercise library is cached as a whole
+CREATE OR REPLACE FUNCTION exercises_cache_invalidation()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM notify_cache_invalidation('exercises');
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercises_cache_invalidation
+    AFTER INSERT OR UPDATE OR DELETE OR TRUNCATE ON exercises
+    FOR EACH STATEMENT
+    EXECUTE FUNCTION exercises_cache_invalidation();
+
+-- Analytics are cached per user, for tables with a user_id column
+CREATE OR REPLACE FUNCTION analytics_cache_invalidation()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_user_id := OLD.user_id;
+    ELSE
+        v_user_id := NEW.user_id;
+    END IF;
+    PERFORM notify_cache_invalidation('analytics:' || v_user_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_cache_invalidation
+    AFTER INSERT OR UPDATE OR DELETE ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION analytics_cache_invalidation();
+
+CREATE TRIGGER body_measurements_cache_invalidation
+    AFTER INSERT OR UPDATE OR DELETE ON body_measurements
+    FOR EACH ROW
+    EXECUTE FUNCTION analytics_cache_invalidation();
+
+CREATE TRIGGER metric_snapshots_cache_invalidation
+    AFTER INSERT OR UPDATE OR DELETE ON metric_snapshots
+    FOR EACH ROW
+    EXECUTE FUNCTION analytics_cache_invalidation();
+
+-- Sets belong to users through their session
+CREATE OR REPLACE FUNCTION exercise_logs_cache_invalidation()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_session_id UUID;
+    v_user_id UUID;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        v_session_id := OLD.workout_session_id;
+    ELSE
+        v_session_id := NEW.workout_session_id;
+    END IF;
+
+    -- Sets deleted along with their session are covered by the session's own trigger
+    SELECT user_id INTO v_user_id FROM workout_sessions WHERE id = v_session_id;
+    IF v_user_id IS NOT NULL THEN
+        PERFORM notify_cache_invalidation('analytics:' || v_user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_cache_invalidation
+    AFTER INSERT OR UPDATE OR DELETE ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_cache_invalidation();
@@ -0,0 +1,5 @@
+-- Rollback: Drop analytics materialized views and exercise muscle groups
+DROP MATERIALIZED VIEW IF EXISTS exercise_e1rm_bests;
+DROP MATERIALIZED VIEW IF EXISTS weekly_muscle_volume;
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS muscle_group;
@@ -0,0 +1,40 @@
+-- Create analytics materialized views
+-- Dashboard aggregates over exercise_logs, refreshed on a schedule instead of computed per request
+
+-- Muscle group an exercise mainly trains; exercises without one count as 'other'
+ALTER TABLE exercises ADD COLUMN muscle_group TEXT CHECK (muscle_group IN (
+    'chest', 'back', 'shoulders', 'biceps', 'triceps', 'forearms', 'core',
+    'quads', 'hamstrings', 'glutes', 'calves', 'full_body', 'cardio'
+));
+
+-- Weekly volume (sets x reps x weight) per user and muscle group, from completed sessions
+CREATE MATERIALIZED VIEW IF NOT EXISTS weekly_muscle_volume AS
+SELECT s.user_id,
+       date_trunc('week', s.started_at AT TIME ZONE 'UTC')::date AS week,  -- Monday (UTC)
+       COALESCE(e.muscle_group, 'other') AS muscle_group,
+       SUM(COALESCE(l.sets_completed, 1) * l.reps_completed * l.weight_kg)::float8 AS volume_kg,
+       SUM(COALESCE(l.sets_completed, 1))::int AS sets
+FROM exercise_logs l
+JOIN workout_sessions s ON s.id = l.workout_session_id
+JOIN exercises e ON e.id = l.exercise_id
+WHERE s.status = 'completed' AND l.reps_completed IS NOT NULL AND l.weight_kg IS NOT NULL
+GROUP BY s.user_id, week, COALESCE(e.muscle_group, 'other');
+
+-- Unique index, required to refresh concurrently; also serves per-user range reads
+CREATE UNIQUE INDEX IF NOT EXISTS idx_weekly_muscle_volume ON weekly_muscle_volume(user_id, week, muscle_group);
+
+-- Best estimated one-rep max (Epley) per user and exercise, with the set it came from
+CREATE MATERIALIZED VIEW IF NOT EXISTS exercise_e1rm_bests AS
+SELECT DISTINCT ON (s.user_id, l.exercise_id)
+       s.user_id,
+       l.exercise_id,
+       (l.weight_kg * (1 + l.reps_completed / 30.0))::float8 AS e1rm_kg,
+       l.weight_kg::float8 AS weight_kg,
+       l.reps_completed AS reps,
+       s.started_at AS achieved_at
+FROM exercise_logs l
+JOIN workout_sessions s ON s.id = l.workout_session_id
+WHERE s.status = 'completed' AND l.weight_kg > 0 AND l.reps_completed > 0
+ORDER BY s.user_id, l.exercise_id, l.weight_kg * (1 + l.reps_completed / 30.0) DESC, s.started_at ASC;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_exercise_e1rm_bests ON exercise_e1rm_bests(user_id, exercise_id);
@@ -0,0 +1,2 @@
+-- Rollback: Drop the session history keyset index
+DROP INDEX IF EXISTS idx_workout_sessions_user_created;
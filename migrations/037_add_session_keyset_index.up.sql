@@ -0,0 +1,4 @@
+-- Add keyset pagination index for the session history
+-- Pages continue from (created_at, id) of the previous page's last session instead of an
+-- OFFSET, so every page is a range scan of this index
+CREATE INDEX IF NOT EXISTS idx_workout_sessions_user_created ON workout_sessions(user_id, created_at DESC, id DESC);
@@ -0,0 +1,9 @@
+-- Rollback: Drop row versions and their triggers
+DROP TRIGGER IF EXISTS bump_exercises_version ON exercises;
+DROP TRIGGER IF EXISTS bump_workouts_version ON workouts;
+DROP TRIGGER IF EXISTS bump_equipment_version ON equipment;
+DROP FUNCTION IF EXISTS bump_row_version();
+
+ALTER TABLE exercises DROP COLUMN IF EXISTS version;
+ALTER TABLE workouts DROP COLUMN IF EXISTS version;
+ALTER TABLE equipment DROP COLUMN IF EXISTS version;
@@ -0,0 +1,29 @@
+-- Add row versions for optimistic concurrency control
+-- Every update bumps the version, so a client that read version N can update only if nobody
+-- else has since; the API answers 409 Conflict otherwise instead of overwriting their edit.
+ALTER TABLE equipment ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE workouts ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+ALTER TABLE exercises ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_row_version()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.version = OLD.version + 1;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER bump_equipment_version
+    BEFORE UPDATE ON equipment
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_row_version();
+
+CREATE TRIGGER bump_workouts_version
+    BEFORE UPDATE ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_row_version();
+
+CREATE TRIGGER bump_exercises_version
+    BEFORE UPDATE ON exercises
+    FOR EACH ROW
+    EXECUTE FUNCTION bump_row_version();
@@ -0,0 +1,16 @@
+-- Create idempotency_keys table
+-- POST requests sent with an Idempotency-Key header and their responses, so a retry of a
+-- request that already ran gets the same response instead of running again
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    key TEXT NOT NULL,
+    request_hash TEXT NOT NULL,  -- SHA-256 of method, URL and body; a reused key must match
+    status_code INTEGER,  -- NULL while the first request is still running
+    content_type TEXT,
+    body BYTEA,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, key)
+);
+
+-- Index for pruning expired keys
+CREATE INDEX idx_idempotency_keys_created ON idempotency_keys(created_at);
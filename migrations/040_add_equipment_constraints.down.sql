@@ -0,0 +1,8 @@
+-- Rollback: Drop unique equipment names and cascade equipment deletes to exercises again
+ALTER TABLE exercise_equipment DROP CONSTRAINT exercise_equipment_equipment_id_fkey;
+ALTER TABLE exercise_equipment
+    ADD CONSTRAINT exercise_equipment_equipment_id_fkey
+    FOREIGN KEY (equipment_id) REFERENCES equipment(id) ON DELETE CASCADE;
+
+DROP INDEX IF EXISTS idx_equipment_organization_name;
+DROP INDEX IF EXISTS idx_equipment_user_name;
@@ -0,0 +1,26 @@
+-- Make equipment names unique per owner and keep equipment that exercises use
+-- Violations surface as 409 Conflict (duplicate name) and 422 Unprocessable Entity (in use)
+-- instead of silently creating look-alikes or dropping equipment from exercises.
+
+-- Rename existing duplicates, keeping the oldest name as is: "Barbell", "Barbell (1f0c2a9e)", ...
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY COALESCE(organization_id, user_id), organization_id IS NULL, LOWER(name)
+        ORDER BY created_at, id
+    ) AS n
+    FROM equipment
+)
+UPDATE equipment e
+SET name = e.name || ' (' || LEFT(e.id::text, 8) || ')'
+FROM ranked
+WHERE ranked.id = e.id AND ranked.n > 1;
+
+-- Personal equipment is unique per user, shared equipment per organization
+CREATE UNIQUE INDEX idx_equipment_user_name ON equipment(user_id, LOWER(name)) WHERE organization_id IS NULL;
+CREATE UNIQUE INDEX idx_equipment_organization_name ON equipment(organization_id, LOWER(name)) WHERE organization_id IS NOT NULL;
+
+-- Deleting equipment that exercises use is rejected; deleting the exercises still unlinks it
+ALTER TABLE exercise_equipment DROP CONSTRAINT exercise_equipment_equipment_id_fkey;
+ALTER TABLE exercise_equipment
+    ADD CONSTRAINT exercise_equipment_equipment_id_fkey
+    FOREIGN KEY (equipment_id) REFERENCES equipment(id);
@@ -0,0 +1,2 @@
+-- Rollback: Drop unique exercise names; normalized names are kept
+DROP INDEX IF EXISTS idx_exercises_user_name;
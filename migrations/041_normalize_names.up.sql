@@ -0,0 +1,41 @@
+-- Normalize stored names the way the API now normalizes input, and make exercise names
+-- unique per user regardless of case, like equipment names
+-- Unicode NFC, whitespace trimmed and collapsed; casing is left as users typed it.
+
+-- The equipment indexes are rebuilt after renaming the duplicates normalizing may create
+DROP INDEX IF EXISTS idx_equipment_user_name;
+DROP INDEX IF EXISTS idx_equipment_organization_name;
+
+UPDATE equipment
+SET name = regexp_replace(btrim(normalize(name, NFC)), '\s+', ' ', 'g')
+WHERE name <> regexp_replace(btrim(normalize(name, NFC)), '\s+', ' ', 'g');
+
+UPDATE exercises
+SET name = regexp_replace(btrim(normalize(name, NFC)), '\s+', ' ', 'g')
+WHERE name <> regexp_replace(btrim(normalize(name, NFC)), '\s+', ' ', 'g');
+
+-- Rename duplicates, keeping the oldest name as is: "Barbell", "Barbell (1f0c2a9e)", ...
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (
+        PARTITION BY COALESCE(organization_id, user_id), organization_id IS NULL, LOWER(name)
+        ORDER BY created_at, id
+    ) AS n
+    FROM equipment
+)
+UPDATE equipment e
+SET name = e.name || ' (' || LEFT(e.id::text, 8) || ')'
+FROM ranked
+WHERE ranked.id = e.id AND ranked.n > 1;
+
+WITH ranked AS (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY user_id, LOWER(name) ORDER BY created_at, id) AS n
+    FROM exercises
+)
+UPDATE exercises e
+SET name = e.name || ' (' || LEFT(e.id::text, 8) || ')'
+FROM ranked
+WHERE ranked.id = e.id AND ranked.n > 1;
+
+CREATE UNIQUE INDEX idx_equipment_user_name ON equipment(user_id, LOWER(name)) WHERE organization_id IS NULL;
+CREATE UNIQUE INDEX idx_equipment_organization_name ON equipment(organization_id, LOWER(name)) WHERE organization_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_exercises_user_name ON exercises(user_id, LOWER(name));
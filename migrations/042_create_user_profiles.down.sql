@@ -0,0 +1,23 @@
+-- Rollback: Bucket weekly muscle volume in UTC again and drop user_profiles table
+COMMENT ON COLUMN metric_snapshots.month IS NULL;
+COMMENT ON COLUMN weekly_reports.week_start IS NULL;
+
+DROP MATERIALIZED VIEW IF EXISTS weekly_muscle_volume;
+
+CREATE MATERIALIZED VIEW weekly_muscle_volume AS
+SELECT s.user_id,
+       date_trunc('week', s.started_at AT TIME ZONE 'UTC')::date AS week,  -- Monday (UTC)
+       COALESCE(e.muscle_group, 'other') AS muscle_group,
+       SUM(COALESCE(l.sets_completed, 1) * l.reps_completed * l.weight_kg)::float8 AS volume_kg,
+       SUM(COALESCE(l.sets_completed, 1))::int AS sets
+FROM exercise_logs l
+JOIN workout_sessions s ON s.id = l.workout_session_id
+JOIN exercises e ON e.id = l.exercise_id
+WHERE s.status = 'completed' AND l.reps_completed IS NOT NULL AND l.weight_kg IS NOT NULL
+GROUP BY s.user_id, week, COALESCE(e.muscle_group, 'other');
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_weekly_muscle_volume ON weekly_muscle_volume(user_id, week, muscle_group);
+
+DROP FUNCTION IF EXISTS user_timezone(UUID);
+
+DROP TABLE IF EXISTS user_profiles;
@@ -0,0 +1,47 @@
+-- Create user_profiles table
+-- Per-user settings that aren't tied to a feature, starting with the timezone history is
+-- bucketed in: a session at 23:00 local time counts on that day, not the next UTC one
+CREATE TABLE IF NOT EXISTS user_profiles (
+    user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+    timezone TEXT NOT NULL DEFAULT 'UTC',  -- IANA name
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_user_profiles_updated_at
+    BEFORE UPDATE ON user_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- The user's timezone, UTC for users without a profile
+CREATE OR REPLACE FUNCTION user_timezone(p_user_id UUID)
+RETURNS TEXT AS $$
+    SELECT COALESCE((SELECT timezone FROM user_profiles WHERE user_id = p_user_id), 'UTC');
+$$ LANGUAGE sql STABLE;
+
+-- Users who set a timezone for quiet hours already told us where they are
+INSERT INTO user_profiles (user_id, timezone)
+SELECT user_id, timezone FROM notification_preferences WHERE timezone <> 'UTC'
+ON CONFLICT (user_id) DO NOTHING;
+
+-- Weeks of weekly_muscle_volume start on the user's Monday
+DROP MATERIALIZED VIEW IF EXISTS weekly_muscle_volume;
+
+CREATE MATERIALIZED VIEW weekly_muscle_volume AS
+SELECT s.user_id,
+       date_trunc('week', s.started_at AT TIME ZONE COALESCE(p.timezone, 'UTC'))::date AS week,  -- Monday (user's timezone)
+       COALESCE(e.muscle_group, 'other') AS muscle_group,
+       SUM(COALESCE(l.sets_completed, 1) * l.reps_completed * l.weight_kg)::float8 AS volume_kg,
+       SUM(COALESCE(l.sets_completed, 1))::int AS sets
+FROM exercise_logs l
+JOIN workout_sessions s ON s.id = l.workout_session_id
+JOIN exercises e ON e.id = l.exercise_id
+LEFT JOIN user_profiles p ON p.user_id = s.user_id
+WHERE s.status = 'completed' AND l.reps_completed IS NOT NULL AND l.weight_kg IS NOT NULL
+GROUP BY s.user_id, week, COALESCE(e.muscle_group, 'other');
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_weekly_muscle_volume ON weekly_muscle_volume(user_id, week, muscle_group);
+
+COMMENT ON COLUMN metric_snapshots.month IS 'First day of the month in the user''s timezone';
+COMMENT ON COLUMN weekly_reports.week_start IS 'Monday in the user''s timezone';
@@ -0,0 +1,4 @@
+-- Rollback: Drop hydration_goals and water_logs tables
+DROP TRIGGER IF EXISTS update_hydration_goals_updated_at ON hydration_goals;
+DROP TABLE IF EXISTS hydration_goals;
+DROP TABLE IF EXISTS water_logs CASCADE;
@@ -0,0 +1,27 @@
+-- Create water_logs table
+-- Water the user drank, logged a glass or bottle at a time
+CREATE TABLE IF NOT EXISTS water_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    amount_ml INTEGER NOT NULL CHECK (amount_ml > 0 AND amount_ml <= 5000),
+    logged_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for a day's logs
+CREATE INDEX idx_water_logs_user_date ON water_logs(user_id, logged_at DESC);
+
+-- Create hydration_goals table
+-- The water a user aims to drink per day; users without a row get the default goal
+CREATE TABLE IF NOT EXISTS hydration_goals (
+    user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+    daily_ml INTEGER NOT NULL CHECK (daily_ml BETWEEN 250 AND 10000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_hydration_goals_updated_at
+    BEFORE UPDATE ON hydration_goals
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
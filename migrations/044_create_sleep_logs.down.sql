@@ -0,0 +1,3 @@
+-- Rollback: Drop sleep_logs table
+DROP TRIGGER IF EXISTS update_sleep_logs_updated_at ON sleep_logs;
+DROP TABLE IF EXISTS sleep_logs CASCADE;
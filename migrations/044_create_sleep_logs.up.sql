@@ -0,0 +1,24 @@
+-- Create sleep_logs table
+-- Nights of sleep entered manually or imported from sleep tracker exports
+CREATE TABLE IF NOT EXISTS sleep_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    started_at TIMESTAMPTZ NOT NULL,  -- In bed
+    ended_at TIMESTAMPTZ NOT NULL,    -- Woke up
+    duration_minutes INTEGER GENERATED ALWAYS AS ((EXTRACT(EPOCH FROM ended_at - started_at) / 60)::integer) STORED,
+    quality INTEGER CHECK (quality BETWEEN 1 AND 5),
+    source TEXT NOT NULL DEFAULT 'manual',  -- 'manual', 'sleepcycle', 'fitbit'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ended_at > started_at AND ended_at <= started_at + INTERVAL '24 hours'),
+    UNIQUE (user_id, started_at)  -- Re-importing the same export is a no-op
+);
+
+-- Index for the night before a session
+CREATE INDEX idx_sleep_logs_user_ended ON sleep_logs(user_id, ended_at DESC);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_sleep_logs_updated_at
+    BEFORE UPDATE ON sleep_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,12 @@
+-- Rollback: Drop goals table and its progress functions and triggers
+DROP TRIGGER IF EXISTS exercise_logs_goals ON exercise_logs;
+DROP TRIGGER IF EXISTS workout_sessions_goals ON workout_sessions;
+DROP TRIGGER IF EXISTS body_measurements_goals ON body_measurements;
+DROP FUNCTION IF EXISTS exercise_logs_goals();
+DROP FUNCTION IF EXISTS workout_sessions_goals();
+DROP FUNCTION IF EXISTS body_measurements_goals();
+DROP TABLE IF EXISTS goals CASCADE;
+DROP FUNCTION IF EXISTS goals_check();
+DROP FUNCTION IF EXISTS check_user_goals(UUID);
+DROP FUNCTION IF EXISTS goal_current_value(UUID, TEXT, UUID);
+DROP FUNCTION IF EXISTS user_week_start(UUID);
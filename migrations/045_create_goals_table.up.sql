@@ -0,0 +1,170 @@
+-- Create goals table
+-- Targets users set for their body weight, a lift's estimated 1RM or sessions per week;
+-- progress is computed from existing measurements and logs, and triggers mark goals
+-- achieved and notify the user
+CREATE TABLE IF NOT EXISTS goals (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('body_weight', 'e1rm', 'weekly_sessions')),
+    exercise_id UUID REFERENCES exercises(id) ON DELETE CASCADE,  -- The lift, for e1rm goals
+    target_value REAL NOT NULL CHECK (target_value > 0),
+    start_value REAL NOT NULL DEFAULT 0,  -- Value when the goal was set; a lower target means losing
+    achieved_at TIMESTAMPTZ,              -- Weekly goals: the last week they were met
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((kind = 'e1rm') = (exercise_id IS NOT NULL))
+);
+
+CREATE INDEX idx_goals_user ON goals(user_id, created_at);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_goals_updated_at
+    BEFORE UPDATE ON goals
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Start of the user's current week (Monday), in their timezone
+CREATE OR REPLACE FUNCTION user_week_start(p_user_id UUID)
+RETURNS TIMESTAMPTZ AS $$
+    SELECT date_trunc('week', NOW() AT TIME ZONE z.tz) AT TIME ZONE z.tz
+    FROM (SELECT user_timezone(p_user_id) AS tz) z;
+$$ LANGUAGE sql STABLE;
+
+-- Current value a goal is measured against: the latest weigh-in, the best Epley estimated
+-- 1RM over completed sessions, or the sessions completed this week; NULL without data
+CREATE OR REPLACE FUNCTION goal_current_value(p_user_id UUID, p_kind TEXT, p_exercise_id UUID)
+RETURNS FLOAT8 AS $$
+    SELECT CASE p_kind
+        WHEN 'body_weight' THEN (
+            SELECT weight_kg::float8 FROM body_measurements
+            WHERE user_id = p_user_id AND weight_kg IS NOT NULL
+            ORDER BY measured_at DESC
+            LIMIT 1
+        )
+        WHEN 'e1rm' THEN (
+            SELECT MAX(l.weight_kg * (1 + l.reps_completed / 30.0))::float8
+            FROM exercise_logs l
+            JOIN workout_sessions s ON s.id = l.workout_session_id
+            WHERE s.user_id = p_user_id AND s.status = 'completed'
+              AND l.exercise_id = p_exercise_id AND l.weight_kg > 0 AND l.reps_completed > 0
+        )
+        WHEN 'weekly_sessions' THEN (
+            SELECT COUNT(*)::float8 FROM workout_sessions
+            WHERE user_id = p_user_id AND status = 'completed'
+              AND started_at >= user_week_start(p_user_id)
+        )
+    END;
+$$ LANGUAGE sql STABLE;
+
+-- Mark the user's goals that are met as achieved and notify them; weekly goals can be
+-- achieved again each week
+CREATE OR REPLACE FUNCTION check_user_goals(p_user_id UUID)
+RETURNS VOID AS $$
+DECLARE
+    v_goal goals%ROWTYPE;
+    v_current FLOAT8;
+    v_week_start TIMESTAMPTZ := user_week_start(p_user_id);
+    v_exercise TEXT;
+BEGIN
+    FOR v_goal IN
+        SELECT * FROM goals
+        WHERE user_id = p_user_id
+          AND (achieved_at IS NULL OR (kind = 'weekly_sessions' AND achieved_at < v_week_start))
+    LOOP
+        v_current := goal_current_value(v_goal.user_id, v_goal.kind, v_goal.exercise_id);
+        IF v_current IS NULL OR NOT CASE
+            WHEN v_goal.target_value < v_goal.start_value THEN v_current <= v_goal.target_value
+            ELSE v_current >= v_goal.target_value
+        END THEN
+            CONTINUE;
+        END IF;
+
+        UPDATE goals SET achieved_at = NOW() WHERE id = v_goal.id;
+
+        SELECT name INTO v_exercise FROM exercises WHERE id = v_goal.exercise_id;
+        PERFORM create_notification(
+            v_goal.user_id,
+            'goal.achieved',
+            'Goal achieved',
+            CASE v_goal.kind
+                WHEN 'body_weight' THEN 'Body weight: ' || v_goal.target_value || ' kg'
+                WHEN 'e1rm' THEN COALESCE(v_exercise, 'Exercise') || ': estimated 1RM of ' || v_goal.target_value || ' kg'
+                ELSE v_goal.target_value::int || ' sessions this week'
+            END,
+            jsonb_build_object(
+                'goal_id', v_goal.id,
+                'kind', v_goal.kind,
+                'target_value', v_goal.target_value,
+                'current_value', v_current
+            )
+        );
+    END LOOP;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE FUNCTION body_measurements_goals()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.weight_kg IS NOT NULL THEN
+        PERFORM check_user_goals(NEW.user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER body_measurements_goals
+    AFTER INSERT OR UPDATE OF weight_kg, measured_at ON body_measurements
+    FOR EACH ROW
+    EXECUTE FUNCTION body_measurements_goals();
+
+CREATE OR REPLACE FUNCTION workout_sessions_goals()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'completed' AND (TG_OP = 'INSERT' OR OLD.status <> 'completed') THEN
+        PERFORM check_user_goals(NEW.user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_goals
+    AFTER INSERT OR UPDATE OF status ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_goals();
+
+-- Sets logged or corrected after a session was completed count too
+CREATE OR REPLACE FUNCTION exercise_logs_goals()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+BEGIN
+    SELECT user_id INTO v_user_id FROM workout_sessions
+    WHERE id = NEW.workout_session_id AND status = 'completed';
+    IF v_user_id IS NOT NULL AND EXISTS (
+        SELECT 1 FROM goals
+        WHERE user_id = v_user_id AND kind = 'e1rm' AND exercise_id = NEW.exercise_id AND achieved_at IS NULL
+    ) THEN
+        PERFORM check_user_goals(v_user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_goals
+    AFTER INSERT OR UPDATE OF weight_kg, reps_completed ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_goals();
+
+-- A goal that is already met when set, or whose new target is, is achieved right away
+CREATE OR REPLACE FUNCTION goals_check()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM check_user_goals(NEW.user_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER goals_check
+    AFTER INSERT OR UPDATE OF target_value ON goals
+    FOR EACH ROW
+    EXECUTE FUNCTION goals_check();
@@ -0,0 +1,7 @@
+-- Rollback: Drop injuries table, the muscle group mapping and the session warning
+DROP TRIGGER IF EXISTS workout_sessions_injury_warning ON workout_sessions;
+DROP FUNCTION IF EXISTS workout_sessions_injury_warning();
+DROP FUNCTION IF EXISTS active_injury_body_parts(UUID);
+DROP TABLE IF EXISTS muscle_group_body_parts;
+DROP TRIGGER IF EXISTS update_injuries_updated_at ON injuries;
+DROP TABLE IF EXISTS injuries CASCADE;
@@ -0,0 +1,114 @@
+-- Create injuries table
+-- Injuries users record with the body parts they affect; exercises training a muscle group
+-- that loads an injured body part are contraindicated while the injury is active
+CREATE TABLE IF NOT EXISTS injuries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    body_parts TEXT[] NOT NULL CHECK (
+        cardinality(body_parts) > 0 AND body_parts <@ ARRAY[
+            'neck', 'shoulder', 'elbow', 'wrist', 'chest', 'upper_back', 'lower_back',
+            'abdomen', 'hip', 'knee', 'ankle'
+        ]
+    ),
+    started_on DATE NOT NULL,
+    ended_on DATE,  -- Last day of the injury; NULL while ongoing
+    notes TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ended_on IS NULL OR ended_on >= started_on)
+);
+
+CREATE INDEX idx_injuries_user ON injuries(user_id, started_on DESC);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_injuries_updated_at
+    BEFORE UPDATE ON injuries
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Body parts each muscle group's exercises load
+CREATE TABLE IF NOT EXISTS muscle_group_body_parts (
+    muscle_group TEXT NOT NULL,
+    body_part TEXT NOT NULL,
+    PRIMARY KEY (muscle_group, body_part)
+);
+
+INSERT INTO muscle_group_body_parts (muscle_group, body_part) VALUES
+    ('chest', 'chest'), ('chest', 'shoulder'), ('chest', 'elbow'), ('chest', 'wrist'),
+    ('back', 'upper_back'), ('back', 'lower_back'), ('back', 'shoulder'), ('back', 'elbow'),
+    ('shoulders', 'shoulder'), ('shoulders', 'neck'), ('shoulders', 'elbow'),
+    ('biceps', 'elbow'), ('biceps', 'wrist'),
+    ('triceps', 'elbow'), ('triceps', 'shoulder'),
+    ('forearms', 'wrist'), ('forearms', 'elbow'),
+    ('core', 'abdomen'), ('core', 'lower_back'),
+    ('quads', 'knee'), ('quads', 'hip'),
+    ('hamstrings', 'knee'), ('hamstrings', 'hip'), ('hamstrings', 'lower_back'),
+    ('glutes', 'hip'), ('glutes', 'lower_back'),
+    ('calves', 'ankle'), ('calves', 'knee'),
+    ('full_body', 'shoulder'), ('full_body', 'lower_back'), ('full_body', 'hip'), ('full_body', 'knee'),
+    ('cardio', 'knee'), ('cardio', 'ankle')
+ON CONFLICT DO NOTHING;
+
+-- Body parts of the user's injuries active today, in their timezone
+CREATE OR REPLACE FUNCTION active_injury_body_parts(p_user_id UUID)
+RETURNS TEXT[] AS $$
+    SELECT COALESCE(array_agg(DISTINCT b.part), '{}')
+    FROM injuries i
+    CROSS JOIN (SELECT (NOW() AT TIME ZONE user_timezone(p_user_id))::date AS today) d
+    CROSS JOIN LATERAL unnest(i.body_parts) AS b(part)
+    WHERE i.user_id = p_user_id
+      AND i.started_on <= d.today AND (i.ended_on IS NULL OR i.ended_on >= d.today);
+$$ LANGUAGE sql STABLE;
+
+-- A session started from a workout with exercises that load an injured body part warns the user
+CREATE OR REPLACE FUNCTION workout_sessions_injury_warning()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_parts TEXT[];
+    v_conflicts JSONB;
+    v_names TEXT;
+BEGIN
+    -- Imported or backfilled sessions already happened
+    IF NEW.workout_id IS NULL OR NEW.status NOT IN ('planned', 'in_progress') THEN
+        RETURN NULL;
+    END IF;
+    v_parts := active_injury_body_parts(NEW.user_id);
+    IF cardinality(v_parts) = 0 THEN
+        RETURN NULL;
+    END IF;
+
+    SELECT jsonb_agg(jsonb_build_object('exercise_id', c.id, 'body_parts', c.parts) ORDER BY c.first_index),
+           string_agg(c.name || ' (' || array_to_string(c.parts, ', ') || ')', ', ' ORDER BY c.first_index)
+    INTO v_conflicts, v_names
+    FROM (
+        SELECT e.id, e.name, MIN(we.order_index) AS first_index,
+               array_agg(DISTINCT m.body_part ORDER BY m.body_part) AS parts
+        FROM workout_exercises we
+        JOIN exercises e ON e.id = we.exercise_id
+        JOIN muscle_group_body_parts m ON m.muscle_group = e.muscle_group
+        WHERE we.workout_id = NEW.workout_id AND m.body_part = ANY(v_parts)
+        GROUP BY e.id, e.name
+    ) c;
+
+    IF v_conflicts IS NOT NULL THEN
+        PERFORM create_notification(
+            NEW.user_id,
+            'injury.warning',
+            'Workout conflicts with an injury',
+            v_names,
+            jsonb_build_object(
+                'session_id', NEW.id,
+                'workout_id', NEW.workout_id,
+                'exercises', v_conflicts
+            )
+        );
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_injury_warning
+    AFTER INSERT ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_injury_warning();
@@ -0,0 +1,4 @@
+-- Rollback: Remove progression schemes from workout templates
+ALTER TABLE workout_exercises
+    DROP COLUMN IF EXISTS progression_scheme,
+    DROP COLUMN IF EXISTS min_reps;
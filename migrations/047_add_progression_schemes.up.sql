@@ -0,0 +1,5 @@
+-- Add progression schemes to workout templates
+-- How each template exercise's load and reps advance from session to session
+ALTER TABLE workout_exercises
+    ADD COLUMN progression_scheme TEXT CHECK (progression_scheme IN ('linear', '531', 'double')),
+    ADD COLUMN min_reps INTEGER CHECK (min_reps > 0);  -- Bottom of the rep range for double progression; reps is the top
@@ -0,0 +1,7 @@
+-- Rollback: Drop user_follows table and profile privacy
+DROP TRIGGER IF EXISTS user_profiles_accept_pending_follows ON user_profiles;
+DROP FUNCTION IF EXISTS accept_pending_follows();
+DROP FUNCTION IF EXISTS can_view_profile(UUID, UUID);
+DROP FUNCTION IF EXISTS user_privacy(UUID);
+DROP TABLE IF EXISTS user_follows;
+ALTER TABLE user_profiles DROP COLUMN IF EXISTS privacy;
@@ -0,0 +1,57 @@
+-- Create user_follows table
+-- Users following each other, the base of social features, gated by each profile's privacy:
+-- public profiles can be followed by anyone, followers-only profiles approve each follower,
+-- and private profiles can't be followed
+ALTER TABLE user_profiles ADD COLUMN privacy TEXT NOT NULL DEFAULT 'public'
+    CHECK (privacy IN ('public', 'followers', 'private'));
+
+CREATE TABLE IF NOT EXISTS user_follows (
+    follower_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    followee_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    status TEXT NOT NULL CHECK (status IN ('pending', 'accepted')),
+    accepted_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (follower_id, followee_id),
+    CHECK (follower_id <> followee_id),
+    CHECK ((status = 'accepted') = (accepted_at IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_follows_followee ON user_follows(followee_id, status);
+
+-- The user's profile privacy, public for users without a profile
+CREATE OR REPLACE FUNCTION user_privacy(p_user_id UUID)
+RETURNS TEXT AS $$
+    SELECT COALESCE((SELECT privacy FROM user_profiles WHERE user_id = p_user_id), 'public');
+$$ LANGUAGE sql STABLE;
+
+-- Whether p_viewer may see p_owner's profile, follows and anything social built on them
+CREATE OR REPLACE FUNCTION can_view_profile(p_viewer UUID, p_owner UUID)
+RETURNS BOOLEAN AS $$
+    SELECT p_viewer = p_owner
+        OR CASE user_privacy(p_owner)
+               WHEN 'public' THEN TRUE
+               WHEN 'followers' THEN EXISTS (
+                   SELECT 1 FROM user_follows
+                   WHERE follower_id = p_viewer AND followee_id = p_owner AND status = 'accepted'
+               )
+               ELSE FALSE
+           END;
+$$ LANGUAGE sql STABLE;
+
+-- Going public lets everyone waiting for approval in
+CREATE OR REPLACE FUNCTION accept_pending_follows()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.privacy = 'public' THEN
+        UPDATE user_follows SET status = 'accepted', accepted_at = NOW()
+        WHERE followee_id = NEW.user_id AND status = 'pending';
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER user_profiles_accept_pending_follows
+    AFTER UPDATE OF privacy ON user_profiles
+    FOR EACH ROW
+    WHEN (OLD.privacy IS DISTINCT FROM NEW.privacy)
+    EXECUTE FUNCTION accept_pending_follows();
@@ -0,0 +1,7 @@
+-- Rollback: Drop activity_events table and its triggers
+DROP TRIGGER IF EXISTS exercise_logs_activity_event ON exercise_logs;
+DROP FUNCTION IF EXISTS exercise_logs_activity_event();
+DROP TRIGGER IF EXISTS workout_sessions_activity_event ON workout_sessions;
+DROP FUNCTION IF EXISTS workout_sessions_activity_event();
+DROP FUNCTION IF EXISTS record_activity_event(UUID, TEXT, UUID, UUID);
+DROP TABLE IF EXISTS activity_events;
@@ -0,0 +1,79 @@
+-- Create activity_events table
+-- Completed sessions and personal records, recorded as they happen, that make up the feeds
+-- of the users' followers. Feeds are assembled on read from the events of followed users,
+-- so events only point at what they are about and show its current details.
+CREATE TABLE IF NOT EXISTS activity_events (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    type TEXT NOT NULL CHECK (type IN ('session.completed', 'pr.achieved')),
+    workout_session_id UUID NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    exercise_log_id UUID REFERENCES exercise_logs(id) ON DELETE CASCADE,  -- Personal records
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((type = 'pr.achieved') = (exercise_log_id IS NOT NULL))
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_events_user ON activity_events(user_id, created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_activity_events_session ON activity_events(workout_session_id);
+
+CREATE OR REPLACE FUNCTION record_activity_event(p_user_id UUID, p_type TEXT, p_session_id UUID, p_log_id UUID)
+RETURNS VOID AS $$
+BEGIN
+    -- Imported and restored history isn't news to followers
+    IF current_setting('fitapi.suppress_webhooks', TRUE) = 'on' THEN
+        RETURN;
+    END IF;
+    INSERT INTO activity_events (user_id, type, workout_session_id, exercise_log_id)
+    VALUES (p_user_id, p_type, p_session_id, p_log_id);
+END;
+$$ LANGUAGE plpgsql;
+
+-- Sessions reopened after completion leave the feed
+CREATE OR REPLACE FUNCTION workout_sessions_activity_event()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'completed' AND (TG_OP = 'INSERT' OR OLD.status <> 'completed') THEN
+        PERFORM record_activity_event(NEW.user_id, 'session.completed', NEW.id, NULL);
+    ELSIF TG_OP = 'UPDATE' AND OLD.status = 'completed' AND NEW.status <> 'completed' THEN
+        DELETE FROM activity_events WHERE workout_session_id = NEW.id AND type = 'session.completed';
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_activity_event
+    AFTER INSERT OR UPDATE OF status ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_activity_event();
+
+-- Records corrected away leave the feed
+CREATE OR REPLACE FUNCTION exercise_logs_activity_event()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+BEGIN
+    IF NEW.is_personal_record AND (TG_OP = 'INSERT' OR NOT COALESCE(OLD.is_personal_record, FALSE)) THEN
+        SELECT user_id INTO v_user_id FROM workout_sessions WHERE id = NEW.workout_session_id;
+        PERFORM record_activity_event(v_user_id, 'pr.achieved', NEW.workout_session_id, NEW.id);
+    ELSIF TG_OP = 'UPDATE' AND OLD.is_personal_record AND NOT COALESCE(NEW.is_personal_record, FALSE) THEN
+        DELETE FROM activity_events WHERE exercise_log_id = NEW.id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_activity_event
+    AFTER INSERT OR UPDATE OF is_personal_record ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_activity_event();
+
+-- Start feeds off with the last 30 days
+INSERT INTO activity_events (user_id, type, workout_session_id, created_at)
+SELECT user_id, 'session.completed', id, COALESCE(completed_at, started_at)
+FROM workout_sessions
+WHERE status = 'completed' AND COALESCE(completed_at, started_at) > NOW() - INTERVAL '30 days';
+
+INSERT INTO activity_events (user_id, type, workout_session_id, exercise_log_id, created_at)
+SELECT s.user_id, 'pr.achieved', s.id, l.id, l.created_at
+FROM exercise_logs l
+JOIN workout_sessions s ON s.id = l.workout_session_id
+WHERE l.is_personal_record AND l.created_at > NOW() - INTERVAL '30 days';
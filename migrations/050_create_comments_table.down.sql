@@ -0,0 +1,8 @@
+-- Rollback: Drop comments table and its triggers
+DROP TRIGGER IF EXISTS comments_notification ON comments;
+DROP FUNCTION IF EXISTS comments_notification();
+DROP TRIGGER IF EXISTS workout_sessions_delete_comments ON workout_sessions;
+DROP FUNCTION IF EXISTS workout_sessions_delete_comments();
+DROP TRIGGER IF EXISTS workouts_delete_comments ON workouts;
+DROP FUNCTION IF EXISTS workouts_delete_comments();
+DROP TABLE IF EXISTS comments;
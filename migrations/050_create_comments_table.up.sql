@@ -0,0 +1,76 @@
+-- Create comments table
+-- Comments on workouts and sessions others can see; resource_type says which table
+-- resource_id points into, so comments are removed with their resource by trigger
+CREATE TABLE IF NOT EXISTS comments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('workout', 'session')),
+    resource_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,  -- Author
+    body TEXT NOT NULL CHECK (char_length(body) BETWEEN 1 AND 2000),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_resource ON comments(resource_type, resource_id, created_at, id);
+CREATE INDEX IF NOT EXISTS idx_comments_user ON comments(user_id);
+
+CREATE OR REPLACE FUNCTION workouts_delete_comments()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM comments WHERE resource_type = 'workout' AND resource_id = OLD.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workouts_delete_comments
+    AFTER DELETE ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION workouts_delete_comments();
+
+CREATE OR REPLACE FUNCTION workout_sessions_delete_comments()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM comments WHERE resource_type = 'session' AND resource_id = OLD.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_delete_comments
+    AFTER DELETE ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_delete_comments();
+
+-- Owners hear about comments others leave on their workouts and sessions
+CREATE OR REPLACE FUNCTION comments_notification()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_owner_id UUID;
+    v_name TEXT;
+BEGIN
+    IF NEW.resource_type = 'workout' THEN
+        SELECT user_id, name INTO v_owner_id, v_name FROM workouts WHERE id = NEW.resource_id;
+    ELSE
+        SELECT user_id, COALESCE(name, 'your session') INTO v_owner_id, v_name FROM workout_sessions WHERE id = NEW.resource_id;
+    END IF;
+
+    IF v_owner_id IS NOT NULL AND v_owner_id <> NEW.user_id THEN
+        PERFORM create_notification(
+            v_owner_id,
+            'comment.created',
+            'New comment on ' || v_name,
+            left(NEW.body, 140),
+            jsonb_build_object(
+                'comment_id', NEW.id,
+                'resource_type', NEW.resource_type,
+                'resource_id', NEW.resource_id,
+                'author_id', NEW.user_id
+            )
+        );
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER comments_notification
+    AFTER INSERT ON comments
+    FOR EACH ROW
+    EXECUTE FUNCTION comments_notification();
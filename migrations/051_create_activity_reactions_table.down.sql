@@ -0,0 +1,2 @@
+-- Rollback: Drop activity_reactions table
+DROP TABLE IF EXISTS activity_reactions;
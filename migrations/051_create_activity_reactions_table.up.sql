@@ -0,0 +1,10 @@
+-- Create activity_reactions table
+-- Users liking feed items; the primary key keeps it to one like per user and item
+CREATE TABLE IF NOT EXISTS activity_reactions (
+    activity_event_id UUID NOT NULL REFERENCES activity_events(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (activity_event_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_activity_reactions_user ON activity_reactions(user_id);
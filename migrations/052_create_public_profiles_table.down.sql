@@ -0,0 +1,3 @@
+-- Rollback: Drop public_profiles table
+DROP TRIGGER IF EXISTS update_public_profiles_updated_at ON public_profiles;
+DROP TABLE IF EXISTS public_profiles;
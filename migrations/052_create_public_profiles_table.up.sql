@@ -0,0 +1,20 @@
+-- Create public_profiles table
+-- The username a user's profile is found by and which parts of it others see. Who may see
+-- the profile at all still follows the user's privacy setting.
+CREATE TABLE IF NOT EXISTS public_profiles (
+    user_id UUID PRIMARY KEY REFERENCES auth.users(id) ON DELETE CASCADE,
+    username TEXT NOT NULL UNIQUE CHECK (username ~ '^[a-z0-9_]{3,30}$'),
+    display_name TEXT CHECK (char_length(display_name) BETWEEN 1 AND 60),
+    show_display_name BOOLEAN NOT NULL DEFAULT TRUE,
+    show_records BOOLEAN NOT NULL DEFAULT TRUE,
+    show_recent_workouts BOOLEAN NOT NULL DEFAULT TRUE,
+    show_follow_counts BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_public_profiles_updated_at
+    BEFORE UPDATE ON public_profiles
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,16 @@
+-- Rollback: Drop challenge goals and completion notifications
+DROP TRIGGER IF EXISTS challenge_participants_completed_notification ON challenge_participants;
+DROP FUNCTION IF EXISTS challenge_participants_completed_notification();
+DROP TRIGGER IF EXISTS challenge_participants_check ON challenge_participants;
+DROP FUNCTION IF EXISTS challenge_participants_check();
+DROP TRIGGER IF EXISTS exercise_logs_challenges ON exercise_logs;
+DROP FUNCTION IF EXISTS exercise_logs_challenges();
+DROP TRIGGER IF EXISTS workout_sessions_challenges ON workout_sessions;
+DROP FUNCTION IF EXISTS workout_sessions_challenges();
+DROP FUNCTION IF EXISTS check_user_challenges(UUID);
+DROP FUNCTION IF EXISTS challenge_progress(UUID, UUID);
+ALTER TABLE challenges
+    DROP CONSTRAINT IF EXISTS challenges_goal_daily_target_check,
+    DROP CONSTRAINT IF EXISTS challenges_goal_check,
+    DROP COLUMN IF EXISTS goal_value,
+    DROP COLUMN IF EXISTS metric;
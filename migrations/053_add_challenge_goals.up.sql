@@ -0,0 +1,128 @@
+-- Add goals computed from workout logs to challenges
+-- Besides checking off days, a challenge can set a total to reach over its dates, e.g.
+-- 100000 kg of volume in March; progress is computed from the participants' completed
+-- sessions, in each participant's timezone, and triggers mark them as having completed it.
+-- Participants completing any challenge are notified.
+ALTER TABLE challenges
+    ADD COLUMN metric TEXT CHECK (metric IN ('volume_kg', 'reps', 'sessions')),
+    ADD COLUMN goal_value REAL CHECK (goal_value > 0),
+    ADD CONSTRAINT challenges_goal_check CHECK ((metric IS NULL) = (goal_value IS NULL)),
+    ADD CONSTRAINT challenges_goal_daily_target_check CHECK (metric IS NULL OR daily_target IS NULL);
+
+-- A participant's progress on a challenge with a goal: volume lifted, reps done or
+-- sessions completed over the challenge's dates; 0 without data
+CREATE OR REPLACE FUNCTION challenge_progress(p_challenge_id UUID, p_user_id UUID)
+RETURNS FLOAT8 AS $$
+    WITH sessions AS (
+        SELECT s.id
+        FROM workout_sessions s
+        JOIN challenges c ON c.id = p_challenge_id
+        WHERE s.user_id = p_user_id AND s.status = 'completed'
+          AND (s.started_at AT TIME ZONE user_timezone(p_user_id))::date BETWEEN c.starts_on AND c.ends_on
+    )
+    SELECT COALESCE(CASE (SELECT metric FROM challenges WHERE id = p_challenge_id)
+        WHEN 'volume_kg' THEN (
+            SELECT SUM(l.weight_kg * l.reps_completed)::float8
+            FROM exercise_logs l
+            WHERE l.workout_session_id IN (SELECT id FROM sessions) AND l.skipped_at IS NULL
+        )
+        WHEN 'reps' THEN (
+            SELECT SUM(l.reps_completed)::float8
+            FROM exercise_logs l
+            WHERE l.workout_session_id IN (SELECT id FROM sessions) AND l.skipped_at IS NULL
+        )
+        WHEN 'sessions' THEN (SELECT COUNT(*)::float8 FROM sessions)
+    END, 0);
+$$ LANGUAGE sql STABLE;
+
+-- Mark the user as having completed the challenges with a goal they reached
+CREATE OR REPLACE FUNCTION check_user_challenges(p_user_id UUID)
+RETURNS VOID AS $$
+    UPDATE challenge_participants p SET completed_at = NOW()
+    FROM challenges c
+    WHERE c.id = p.challenge_id AND p.user_id = p_user_id AND p.completed_at IS NULL
+      AND c.metric IS NOT NULL AND challenge_progress(c.id, p_user_id) >= c.goal_value;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION workout_sessions_challenges()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW.status = 'completed' AND (TG_OP = 'INSERT' OR OLD.status <> 'completed') THEN
+        PERFORM check_user_challenges(NEW.user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_challenges
+    AFTER INSERT OR UPDATE OF status ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_challenges();
+
+-- Sets logged or corrected after a session was completed count too
+CREATE OR REPLACE FUNCTION exercise_logs_challenges()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_user_id UUID;
+BEGIN
+    SELECT user_id INTO v_user_id FROM workout_sessions
+    WHERE id = NEW.workout_session_id AND status = 'completed';
+    IF v_user_id IS NOT NULL AND EXISTS (
+        SELECT 1 FROM challenge_participants p
+        JOIN challenges c ON c.id = p.challenge_id
+        WHERE p.user_id = v_user_id AND p.completed_at IS NULL AND c.metric IN ('volume_kg', 'reps')
+    ) THEN
+        PERFORM check_user_challenges(v_user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercise_logs_challenges
+    AFTER INSERT OR UPDATE OF weight_kg, reps_completed, skipped_at ON exercise_logs
+    FOR EACH ROW
+    EXECUTE FUNCTION exercise_logs_challenges();
+
+-- Progress made before joining counts, so joining can complete a challenge right away
+CREATE OR REPLACE FUNCTION challenge_participants_check()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM check_user_challenges(NEW.user_id);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER challenge_participants_check
+    AFTER INSERT ON challenge_participants
+    FOR EACH ROW
+    EXECUTE FUNCTION challenge_participants_check();
+
+-- Participants hear when they complete a challenge, whether by checking off its last day
+-- or by reaching its goal
+CREATE OR REPLACE FUNCTION challenge_participants_completed_notification()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_challenge challenges%ROWTYPE;
+BEGIN
+    IF OLD.completed_at IS NULL AND NEW.completed_at IS NOT NULL THEN
+        SELECT * INTO v_challenge FROM challenges WHERE id = NEW.challenge_id;
+        PERFORM create_notification(
+            NEW.user_id,
+            'challenge.completed',
+            'Challenge completed',
+            'You completed ' || v_challenge.name,
+            jsonb_build_object(
+                'challenge_id', v_challenge.id,
+                'metric', v_challenge.metric,
+                'goal_value', v_challenge.goal_value
+            )
+        );
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER challenge_participants_completed_notification
+    AFTER UPDATE OF completed_at ON challenge_participants
+    FOR EACH ROW
+    EXECUTE FUNCTION challenge_participants_completed_notification();
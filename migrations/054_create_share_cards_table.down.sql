@@ -0,0 +1,2 @@
+-- Rollback: Drop share_cards table
+DROP TABLE IF EXISTS share_cards CASCADE;
@@ -0,0 +1,19 @@
+-- Create share_cards table
+-- Asynchronously rendered summary images of sessions, downloadable through signed links
+CREATE TABLE IF NOT EXISTS share_cards (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    workout_session_id UUID NOT NULL REFERENCES workout_sessions(id) ON DELETE CASCADE,
+    format TEXT NOT NULL CHECK (format IN ('png', 'svg')),
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'ready', 'failed')),
+    error TEXT,          -- Failure reason when status = 'failed'
+    image BYTEA,         -- The rendered card, set when status = 'ready'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+-- Index for a session's cards
+CREATE INDEX idx_share_cards_session ON share_cards(workout_session_id, created_at DESC);
+
+-- Index for cleanup when a user is deleted
+CREATE INDEX idx_share_cards_user ON share_cards(user_id);
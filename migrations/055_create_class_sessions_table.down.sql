@@ -0,0 +1,4 @@
+-- Rollback: Drop class_sessions table and the link from workout_sessions
+DROP INDEX IF EXISTS idx_workout_sessions_class;
+ALTER TABLE workout_sessions DROP COLUMN IF EXISTS class_session_id;
+DROP TABLE IF EXISTS class_sessions CASCADE;
@@ -0,0 +1,29 @@
+-- Create class_sessions table
+-- Group workouts an organization's trainers schedule for members to join; each member who
+-- joins gets their own session of the class workout, linked back to the class
+CREATE TABLE IF NOT EXISTS class_sessions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    trainer_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    workout_id UUID NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,  -- Shared in the organization
+    name TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 100),
+    starts_at TIMESTAMPTZ NOT NULL,
+    capacity INTEGER CHECK (capacity > 0),  -- NULL means no limit
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for an organization's schedule
+CREATE INDEX idx_class_sessions_organization ON class_sessions(organization_id, starts_at);
+
+-- Members' sessions of a class; deleting the class keeps them as ordinary sessions
+ALTER TABLE workout_sessions ADD COLUMN class_session_id UUID REFERENCES class_sessions(id) ON DELETE SET NULL;
+
+-- A member joins a class once
+CREATE UNIQUE INDEX idx_workout_sessions_class ON workout_sessions(class_session_id, user_id) WHERE class_session_id IS NOT NULL;
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_class_sessions_updated_at
+    BEFORE UPDATE ON class_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,5 @@
+-- Rollback: Drop coach_programs and program_workouts tables
+DROP TRIGGER IF EXISTS workout_sessions_program ON workout_sessions;
+DROP FUNCTION IF EXISTS workout_sessions_program();
+DROP TABLE IF EXISTS program_workouts CASCADE;
+DROP TABLE IF EXISTS coach_programs CASCADE;
@@ -0,0 +1,70 @@
+-- Create coach_programs and program_workouts tables
+-- Programs are workouts a coach schedules for a client on given days. Completed sessions of
+-- a scheduled workout are linked to it by a trigger, so coaches can see which were done as
+-- prescribed, done with changes, skipped or missed.
+CREATE TABLE IF NOT EXISTS coach_programs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    coach_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    client_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    name TEXT NOT NULL CHECK (length(name) BETWEEN 1 AND 100),
+    notes TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Index for a client's programs, and a coach's programs for one client
+CREATE INDEX idx_coach_programs_client ON coach_programs(client_id, coach_id, created_at);
+
+CREATE TABLE IF NOT EXISTS program_workouts (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    program_id UUID NOT NULL REFERENCES coach_programs(id) ON DELETE CASCADE,
+    workout_id UUID NOT NULL REFERENCES workouts(id) ON DELETE CASCADE,
+    scheduled_on DATE NOT NULL,  -- In the client's timezone
+    session_id UUID REFERENCES workout_sessions(id) ON DELETE SET NULL,  -- The completed session done for it
+    skipped_at TIMESTAMPTZ,      -- Set when the client skips it
+    skip_reason TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_program_workouts_program ON program_workouts(program_id, scheduled_on);
+
+-- A session counts for one scheduled workout
+CREATE UNIQUE INDEX idx_program_workouts_session ON program_workouts(session_id) WHERE session_id IS NOT NULL;
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_coach_programs_updated_at
+    BEFORE UPDATE ON coach_programs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Link a completed session to the client's open scheduled workout of the same workout in
+-- the same week (Monday to Sunday, in their timezone), the closest to the session's day,
+-- so a workout made up later in the week still counts
+CREATE OR REPLACE FUNCTION workout_sessions_program()
+RETURNS TRIGGER AS $$
+DECLARE
+    v_day DATE;
+BEGIN
+    IF NEW.status = 'completed' AND NEW.workout_id IS NOT NULL AND (TG_OP = 'INSERT' OR OLD.status <> 'completed')
+       AND NOT EXISTS (SELECT 1 FROM program_workouts WHERE session_id = NEW.id) THEN
+        v_day := (NEW.started_at AT TIME ZONE user_timezone(NEW.user_id))::date;
+        UPDATE program_workouts SET session_id = NEW.id
+        WHERE id = (
+            SELECT pw.id
+            FROM program_workouts pw
+            JOIN coach_programs p ON p.id = pw.program_id
+            WHERE p.client_id = NEW.user_id AND pw.workout_id = NEW.workout_id
+              AND pw.session_id IS NULL AND pw.skipped_at IS NULL
+              AND date_trunc('week', pw.scheduled_on) = date_trunc('week', v_day)
+            ORDER BY abs(pw.scheduled_on - v_day), pw.scheduled_on
+            LIMIT 1
+        );
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workout_sessions_program
+    AFTER INSERT OR UPDATE OF status ON workout_sessions
+    FOR EACH ROW
+    EXECUTE FUNCTION workout_sessions_program();
@@ -0,0 +1,6 @@
+-- Rollback: Drop content_reports table and its triggers
+DROP TRIGGER IF EXISTS workouts_delete_reports ON workouts;
+DROP FUNCTION IF EXISTS workouts_delete_reports();
+DROP TRIGGER IF EXISTS exercises_delete_reports ON exercises;
+DROP FUNCTION IF EXISTS exercises_delete_reports();
+DROP TABLE IF EXISTS content_reports;
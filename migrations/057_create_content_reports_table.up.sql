@@ -0,0 +1,52 @@
+-- Create content_reports table
+-- Users report public library content (public exercises and workouts shared in an
+-- organization) for admins to review. resource_type says which table resource_id points
+-- into, so reports are removed with their resource by trigger; open reports have no
+-- resolution yet.
+CREATE TABLE IF NOT EXISTS content_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    resource_type TEXT NOT NULL CHECK (resource_type IN ('exercise', 'workout')),
+    resource_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,  -- Reporter
+    reason TEXT NOT NULL CHECK (reason IN ('spam', 'inappropriate', 'incorrect', 'duplicate', 'other')),
+    details TEXT CHECK (char_length(details) <= 1000),
+    resolution TEXT CHECK (resolution IN ('approved', 'edited', 'unpublished')),
+    resolved_by UUID REFERENCES auth.users(id) ON DELETE SET NULL,  -- NULL for service tokens
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK ((resolution IS NULL) = (resolved_at IS NULL))
+);
+
+-- One open report per user and resource; the queue reads open reports only
+CREATE UNIQUE INDEX IF NOT EXISTS idx_content_reports_open
+    ON content_reports(resource_type, resource_id, user_id)
+    WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_content_reports_queue ON content_reports(created_at) WHERE resolved_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_content_reports_user ON content_reports(user_id);
+CREATE INDEX IF NOT EXISTS idx_content_reports_resolved_by ON content_reports(resolved_by);
+
+CREATE OR REPLACE FUNCTION exercises_delete_reports()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM content_reports WHERE resource_type = 'exercise' AND resource_id = OLD.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER exercises_delete_reports
+    AFTER DELETE ON exercises
+    FOR EACH ROW
+    EXECUTE FUNCTION exercises_delete_reports();
+
+CREATE OR REPLACE FUNCTION workouts_delete_reports()
+RETURNS TRIGGER AS $$
+BEGIN
+    DELETE FROM content_reports WHERE resource_type = 'workout' AND resource_id = OLD.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER workouts_delete_reports
+    AFTER DELETE ON workouts
+    FOR EACH ROW
+    EXECUTE FUNCTION workouts_delete_reports();
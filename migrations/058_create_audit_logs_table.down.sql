@@ -0,0 +1,2 @@
+-- Rollback: Drop audit_logs table
+DROP TABLE IF EXISTS audit_logs;
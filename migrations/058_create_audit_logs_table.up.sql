@@ -0,0 +1,17 @@
+-- Create audit_logs table
+-- Who created, updated or deleted which resource, with the fields that changed. Written by
+-- the services after a write succeeds; resource_id is text as some resources have composite
+-- keys (organization members are "<organization_id>/<user_id>").
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    actor_id UUID REFERENCES auth.users(id) ON DELETE SET NULL,  -- NULL for service tokens
+    action TEXT NOT NULL CHECK (action IN ('create', 'update', 'delete')),
+    resource_type TEXT NOT NULL,
+    resource_id TEXT NOT NULL,
+    changes JSONB NOT NULL DEFAULT '{}',  -- Field name to {"before", "after"}
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created ON audit_logs(created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor ON audit_logs(actor_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_resource ON audit_logs(resource_type, resource_id, created_at DESC);
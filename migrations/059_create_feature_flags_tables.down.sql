@@ -0,0 +1,4 @@
+-- Rollback: Drop feature_flag_overrides and feature_flags tables
+DROP TABLE IF EXISTS feature_flag_overrides;
+DROP TRIGGER IF EXISTS update_feature_flags_updated_at ON feature_flags;
+DROP TABLE IF EXISTS feature_flags;
@@ -0,0 +1,27 @@
+-- Create feature_flags table
+-- Flags edited at runtime by admins; flags without a row use the defaults from
+-- configuration. Enabled flags are on for rollout_percent of users.
+CREATE TABLE IF NOT EXISTS feature_flags (
+    name TEXT PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]{0,62}$'),
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percent INTEGER NOT NULL DEFAULT 100 CHECK (rollout_percent BETWEEN 0 AND 100),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Auto-update updated_at timestamp
+CREATE TRIGGER update_feature_flags_updated_at
+    BEFORE UPDATE ON feature_flags
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+-- Create feature_flag_overrides table
+-- A flag turned on or off for one user, whatever its rollout
+CREATE TABLE IF NOT EXISTS feature_flag_overrides (
+    flag_name TEXT NOT NULL REFERENCES feature_flags(name) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (flag_name, user_id)
+);
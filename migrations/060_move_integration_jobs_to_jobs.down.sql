@@ -0,0 +1,40 @@
+-- Rollback: Restore integration_jobs from Strava jobs and pushes, and drop integration_pushes
+CREATE TABLE IF NOT EXISTS integration_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    connection_id UUID NOT NULL REFERENCES integration_connections(id) ON DELETE CASCADE,
+    kind TEXT NOT NULL CHECK (kind IN ('import_activity', 'delete_activity', 'push_session')),
+    object_id TEXT NOT NULL,
+    result_id TEXT,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'done', 'failed')),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    run_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (connection_id, kind, object_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_integration_jobs_due ON integration_jobs(run_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_integration_jobs_result ON integration_jobs(connection_id, result_id) WHERE result_id IS NOT NULL;
+
+CREATE TRIGGER update_integration_jobs_updated_at
+    BEFORE UPDATE ON integration_jobs
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
+
+INSERT INTO integration_jobs (connection_id, kind, object_id, result_id, status, created_at)
+SELECT connection_id, 'push_session', session_id::text, activity_id,
+       CASE WHEN activity_id IS NULL THEN 'pending' ELSE 'done' END, created_at
+FROM integration_pushes;
+
+INSERT INTO integration_jobs (connection_id, kind, object_id, attempts, run_at, last_error, created_at)
+SELECT (payload->>'connection_id')::uuid, substring(kind FROM 8), payload->>'object_id',
+       attempts, run_at, last_error, created_at
+FROM jobs
+WHERE kind IN ('strava_import_activity', 'strava_delete_activity') AND status IN ('pending', 'running')
+  AND EXISTS (SELECT 1 FROM integration_connections c WHERE c.id = (payload->>'connection_id')::uuid)
+ON CONFLICT (connection_id, kind, object_id) DO NOTHING;
+
+DELETE FROM jobs WHERE kind LIKE 'strava\_%';
+
+DROP TABLE IF EXISTS integration_pushes;
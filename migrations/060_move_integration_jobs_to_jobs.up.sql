@@ -0,0 +1,30 @@
+-- Create integration_pushes table
+-- Sessions queued for pushing to a provider, so each is pushed once, and the activity each
+-- push created, so it is not pulled back in as a new session
+CREATE TABLE IF NOT EXISTS integration_pushes (
+    connection_id UUID NOT NULL REFERENCES integration_connections(id) ON DELETE CASCADE,
+    session_id UUID NOT NULL,
+    activity_id TEXT,  -- Set once the push succeeded
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (connection_id, session_id)
+);
+
+-- Index for recognizing activities fitapi pushed itself
+CREATE INDEX idx_integration_pushes_activity ON integration_pushes(connection_id, activity_id) WHERE activity_id IS NOT NULL;
+
+INSERT INTO integration_pushes (connection_id, session_id, activity_id, created_at)
+SELECT connection_id, object_id::uuid, result_id, created_at
+FROM integration_jobs
+WHERE kind = 'push_session';
+
+-- Pending webhook events and pushes are now run by Strava jobs
+INSERT INTO jobs (user_id, kind, payload, attempts, run_at, last_error, created_at)
+SELECT c.user_id, 'strava_' || j.kind,
+       jsonb_build_object('connection_id', j.connection_id, 'object_id', j.object_id),
+       j.attempts, j.run_at, j.last_error, j.created_at
+FROM integration_jobs j
+JOIN integration_connections c ON c.id = j.connection_id
+WHERE j.status = 'pending';
+
+DROP TRIGGER IF EXISTS update_integration_jobs_updated_at ON integration_jobs;
+DROP TABLE IF EXISTS integration_jobs;
@@ -0,0 +1,2 @@
+-- Rollback: Drop imports table
+DROP TABLE IF EXISTS imports;
@@ -0,0 +1,17 @@
+-- Create imports table
+-- Workout history uploaded from other apps, parsed on upload and stored by a background job
+CREATE TABLE IF NOT EXISTS imports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES auth.users(id) ON DELETE CASCADE,
+    source TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'completed', 'failed')),
+    timezone TEXT NOT NULL DEFAULT 'UTC',  -- Days are compared in it to skip history logged twice
+    sessions JSONB,  -- The parsed export, cleared once the import finished
+    result JSONB,    -- What the import created, set when status = 'completed'
+    error TEXT,      -- Failure reason when status = 'failed'
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    completed_at TIMESTAMPTZ
+);
+
+-- Index for a user's import history
+CREATE INDEX idx_imports_user ON imports(user_id, created_at DESC);